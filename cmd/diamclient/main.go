@@ -0,0 +1,146 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Diamclient sends Diameter requests written as JSON templates to a peer
+// and prints their answers, for ad-hoc testing.
+//
+// Use: diamclient [flags] [template.json...]
+//
+// It dials the peer at -addr, performs the capabilities exchange, and
+// sends the requests of the templates in turn. Without template files,
+// templates are read from the standard input, so requests can be typed
+// interactively. A template file may hold several templates.
+//
+// Templates are the JSON form of diam.Message, in which AVPs can be given
+// by name and Enumerated values by item name. The Origin-Host and
+// Origin-Realm of the client are added to requests without them:
+//
+//	{
+//		"header": {"flags": 192, "code": 272, "application_id": 4},
+//		"avps": [
+//			{"name": "Session-Id", "value": "${session_id}"},
+//			{"name": "Destination-Realm", "value": "${realm}"},
+//			{"name": "Auth-Application-Id", "value": 4},
+//			{"name": "CC-Request-Type", "value": "INITIAL_REQUEST"},
+//			{"name": "CC-Request-Number", "value": 0}
+//		]
+//	}
+//
+// Variables of the form ${name} are substituted with the values given
+// with -var name=value, or else with those of environment variables.
+// The variables session_id, a new Session-Id for each template,
+// origin_host and origin_realm are predefined.
+//
+// YAML templates are not supported.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+	"github.com/omnicate/go-diameter/v4/diam/sm"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:3868", "address of the peer")
+	network := flag.String("network", "tcp", "network of the peer, tcp or sctp")
+	useTLS := flag.Bool("tls", false, "connect to the peer with TLS")
+	certFile := flag.String("cert", "", "TLS client certificate file (optional)")
+	keyFile := flag.String("key", "", "TLS client key file (optional)")
+	host := flag.String("host", "diamclient", "Origin-Host of the client")
+	realm := flag.String("realm", "go-diameter", "Origin-Realm of the client")
+	authApps := flag.String("auth", "4", "comma separated list of Auth-Application-Ids")
+	acctApps := flag.String("acct", "", "comma separated list of Acct-Application-Ids")
+	files := flag.String("dict", "", "comma separated list of dictionaries")
+	timeout := flag.Duration("timeout", 5*time.Second, "time to wait for each answer")
+	asJSON := flag.Bool("json", false, "print answers as JSON, one per line")
+	vars := make(variables)
+	flag.Var(vars, "var", "set a template variable as name=value (repeatable)")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [flags] [template.json...]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *files != "" {
+		for _, f := range strings.Split(*files, ",") {
+			if err := dict.Default.LoadFile(f); err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
+	settings := &sm.Settings{
+		OriginHost:  datatype.DiameterIdentity(*host),
+		OriginRealm: datatype.DiameterIdentity(*realm),
+		VendorID:    13,
+		ProductName: "go-diameter",
+	}
+	cli := &sm.Client{Handler: sm.New(settings)}
+	var err error
+	if cli.AuthApplicationID, err = appIDs(avp.AuthApplicationID, *authApps); err != nil {
+		log.Fatal(err)
+	}
+	if cli.AcctApplicationID, err = appIDs(avp.AcctApplicationID, *acctApps); err != nil {
+		log.Fatal(err)
+	}
+	var c diam.Conn
+	if *useTLS {
+		c, err = cli.DialNetworkTLS(*network, *addr, *certFile, *keyFile, nil)
+	} else {
+		c, err = cli.DialNetwork(*network, *addr)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer c.Close()
+
+	s := newSender(c, settings, vars)
+	s.timeout = *timeout
+	s.json = *asJSON
+	if flag.NArg() == 0 {
+		if err = s.run(os.Stdin, os.Stdout, os.Stderr); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	for _, name := range flag.Args() {
+		if err = runFile(s, name); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func runFile(s *sender, name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return s.run(f, os.Stdout, os.Stderr)
+}
+
+// appIDs returns the AVPs of code with the comma separated list of
+// application ids s.
+func appIDs(code uint32, s string) ([]*diam.AVP, error) {
+	var avps []*diam.AVP
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid application id %q", v)
+		}
+		avps = append(avps, diam.NewAVP(code, avp.Mbit, 0, datatype.Unsigned32(id)))
+	}
+	return avps, nil
+}
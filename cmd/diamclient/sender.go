@@ -0,0 +1,150 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/session"
+	"github.com/omnicate/go-diameter/v4/diam/sm"
+)
+
+// ErrNotRequest is returned for templates of answers.
+var ErrNotRequest = errors.New("template is not a request")
+
+// variables are template variables, set by the -var flag.
+type variables map[string]string
+
+func (v variables) String() string {
+	return ""
+}
+
+func (v variables) Set(s string) error {
+	i := strings.IndexByte(s, '=')
+	if i <= 0 {
+		return fmt.Errorf("invalid variable %q, want name=value", s)
+	}
+	v[s[:i]] = s[i+1:]
+	return nil
+}
+
+// sender sends the requests of templates on a connection.
+type sender struct {
+	conn     diam.Conn
+	settings *sm.Settings
+	vars     variables
+	ids      *session.IDGenerator
+	timeout  time.Duration
+	json     bool
+}
+
+func newSender(c diam.Conn, settings *sm.Settings, vars variables) *sender {
+	return &sender{
+		conn:     c,
+		settings: settings,
+		vars:     vars,
+		ids:      session.NewIDGenerator(settings.OriginHost),
+		timeout:  5 * time.Second,
+	}
+}
+
+// run sends the requests of the templates read from r, and prints their
+// answers to w. Requests that fail are reported to errw.
+func (s *sender) run(r io.Reader, w, errw io.Writer) error {
+	dec := json.NewDecoder(r)
+	for {
+		var tmpl json.RawMessage
+		err := dec.Decode(&tmpl)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		a, err := s.send(tmpl)
+		if err != nil {
+			fmt.Fprintln(errw, err)
+			continue
+		}
+		if s.json {
+			b, err := json.Marshal(a)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "%s\n", b)
+		} else {
+			fmt.Fprintln(w, a)
+		}
+	}
+}
+
+// send sends the request of the template tmpl and returns its answer.
+func (s *sender) send(tmpl []byte) (*diam.Message, error) {
+	b, err := s.expand(tmpl)
+	if err != nil {
+		return nil, err
+	}
+	var m diam.Message
+	if err = json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	if m.Header.CommandFlags&diam.RequestFlag == 0 {
+		return nil, ErrNotRequest
+	}
+	if _, err = m.FindAVP(avp.OriginHost, 0); err != nil {
+		m.NewAVP(avp.OriginHost, avp.Mbit, 0, s.settings.OriginHost)
+	}
+	if _, err = m.FindAVP(avp.OriginRealm, 0); err != nil {
+		m.NewAVP(avp.OriginRealm, avp.Mbit, 0, s.settings.OriginRealm)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	return diam.SendRequest(ctx, s.conn, &m)
+}
+
+// expand substitutes the variables of the template tmpl. Values are
+// escaped as JSON strings.
+func (s *sender) expand(tmpl []byte) ([]byte, error) {
+	var sid string
+	var err error
+	b := os.Expand(string(tmpl), func(name string) string {
+		if v, ok := s.vars[name]; ok {
+			return escape(v)
+		}
+		switch name {
+		case "session_id":
+			if sid == "" {
+				sid = string(s.ids.Next())
+			}
+			return escape(sid)
+		case "origin_host":
+			return escape(string(s.settings.OriginHost))
+		case "origin_realm":
+			return escape(string(s.settings.OriginRealm))
+		}
+		v, ok := os.LookupEnv(name)
+		if !ok && err == nil {
+			err = fmt.Errorf("undefined variable %q", name)
+		}
+		return escape(v)
+	})
+	return []byte(b), err
+}
+
+// escape returns s escaped as the contents of a JSON string.
+func escape(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b[1 : len(b)-1])
+}
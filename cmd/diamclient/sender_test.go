@@ -0,0 +1,105 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+	"github.com/omnicate/go-diameter/v4/diam/sm"
+)
+
+var (
+	serverSettings = &sm.Settings{
+		OriginHost:  "srv",
+		OriginRealm: "srv.test",
+		VendorID:    13,
+		ProductName: "go-diameter",
+	}
+
+	clientSettings = &sm.Settings{
+		OriginHost:  "cli",
+		OriginRealm: "cli.test",
+		VendorID:    13,
+		ProductName: "go-diameter",
+	}
+)
+
+const ccrTemplate = `{
+	"header": {"flags": 192, "code": 272, "application_id": 4},
+	"avps": [
+		{"name": "Session-Id", "value": "${session_id}"},
+		{"name": "Destination-Realm", "value": "${realm}"},
+		{"name": "Auth-Application-Id", "value": 4},
+		{"name": "CC-Request-Type", "value": "INITIAL_REQUEST"},
+		{"name": "CC-Request-Number", "value": 0}
+	]
+}`
+
+func TestSenderRun(t *testing.T) {
+	reqc := make(chan *diam.Message, 2)
+	srvSM := sm.New(serverSettings)
+	srvSM.HandleFunc("CCR", func(c diam.Conn, m *diam.Message) {
+		reqc <- m
+		a := m.Answer(diam.Success)
+		a.AddAVP(m.AVP[0])
+		a.NewAVP(avp.OriginHost, avp.Mbit, 0, serverSettings.OriginHost)
+		a.NewAVP(avp.OriginRealm, avp.Mbit, 0, serverSettings.OriginRealm)
+		a.WriteTo(c)
+	})
+	srv := diamtest.NewServer(srvSM, dict.Default)
+	defer srv.Close()
+	authApp := []*diam.AVP{diam.NewAVP(avp.AuthApplicationID, avp.Mbit, 0, datatype.Unsigned32(4))}
+	c, err := (&sm.Client{Handler: sm.New(clientSettings), AuthApplicationID: authApp}).Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	s := newSender(c, clientSettings, variables{"realm": "srv.test"})
+	s.json = true
+	var out, errs bytes.Buffer
+	in := ccrTemplate + ccrTemplate + `{"header": {"code": 272}, "avps": [{"name": "Session-Id", "value": "${undefined_var}"}]}`
+	if err = s.run(strings.NewReader(in), &out, &errs); err != nil {
+		t.Fatal(err)
+	}
+	if errs.String() != "undefined variable \"undefined_var\"\n" {
+		t.Fatalf("Unexpected errors: %q", errs.String())
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"name":"Result-Code","code":268,"flags":64,"type":"Unsigned32","value":2001`) {
+		t.Fatalf("Unexpected answers: %s", out.String())
+	}
+	var sids []string
+	for i := 0; i < 2; i++ {
+		m := <-reqc
+		if oh, err := m.FindAVP(avp.OriginHost, 0); err != nil || oh.Data != clientSettings.OriginHost {
+			t.Fatalf("Unexpected request: %s", m)
+		}
+		if dr, err := m.FindAVP(avp.DestinationRealm, 0); err != nil || dr.Data != datatype.DiameterIdentity("srv.test") {
+			t.Fatalf("Unexpected request: %s", m)
+		}
+		sids = append(sids, string(m.AVP[0].Data.(datatype.UTF8String)))
+	}
+	if !strings.HasPrefix(sids[0], "cli;") || sids[0] == sids[1] {
+		t.Fatalf("Unexpected Session-Ids: %v", sids)
+	}
+}
+
+func TestVariablesSet(t *testing.T) {
+	v := make(variables)
+	if err := v.Set("a=b=c"); err != nil || v["a"] != "b=c" {
+		t.Fatalf("Unexpected variables %v: %v", v, err)
+	}
+	if err := v.Set("=b"); err == nil {
+		t.Fatal("Unexpected success without name")
+	}
+}
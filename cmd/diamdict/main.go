@@ -0,0 +1,107 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Diamdict converts dictionary files between the XML and JSON formats.
+//
+// Use: diamdict [-format json|xml] [-o file] [-wireshark] dictionary...
+//
+// The dictionaries are read in the format of their extension, JSON for
+// .json and XML otherwise, checked by loading them together as a
+// dictionary.Parser would, and written as a single dictionary with the
+// applications of all of them. The output format is given by -format,
+// or else by the extension of -o, or else it is JSON.
+//
+// With -wireshark the dictionaries are those of Wireshark, such as its
+// diameter/dictionary.xml, imported by the dict/wireshark package.
+//
+// For example, to edit the default Gx dictionary in JSON:
+//
+//	diamdict -o gx.json diam/dict/testdata/gx_credit_control.xml
+//
+// Or to import the dictionaries of Wireshark:
+//
+//	diamdict -wireshark -o wireshark.xml /usr/share/wireshark/diameter/dictionary.xml
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+	"github.com/omnicate/go-diameter/v4/diam/dict/wireshark"
+)
+
+func main() {
+	format := flag.String("format", "", "output format: json or xml (default by the extension of -o, or json)")
+	out := flag.String("o", "", "output file (default stdout)")
+	ws := flag.Bool("wireshark", false, "read Wireshark dictionaries")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [flags] dictionary...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	f := dict.JSON
+	switch strings.ToLower(*format) {
+	case "json":
+	case "xml":
+		f = dict.XML
+	case "":
+		if *out != "" {
+			f = dict.FormatOf(*out)
+		}
+	default:
+		log.Fatalf("Unsupported format %q", *format)
+	}
+	var b bytes.Buffer
+	conv := convert
+	if *ws {
+		conv = convertWireshark
+	}
+	if err := conv(&b, f, flag.Args()...); err != nil {
+		log.Fatal(err)
+	}
+	if *out == "" {
+		os.Stdout.Write(b.Bytes())
+		return
+	}
+	if err := ioutil.WriteFile(*out, b.Bytes(), 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// convert writes the dictionaries files, merged, to w in format.
+func convert(w io.Writer, format dict.Format, files ...string) error {
+	if _, err := dict.NewParser(files...); err != nil {
+		return err
+	}
+	merged := new(dict.File)
+	for _, name := range files {
+		f, err := dict.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		merged.App = append(merged.App, f.App...)
+	}
+	return merged.Encode(w, format)
+}
+
+// convertWireshark writes the Wireshark dictionaries files, merged, to w
+// in format.
+func convertWireshark(w io.Writer, format dict.Format, files ...string) error {
+	f, err := wireshark.ReadFile(files...)
+	if err != nil {
+		return err
+	}
+	return f.Encode(w, format)
+}
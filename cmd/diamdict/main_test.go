@@ -0,0 +1,84 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+func TestConvert(t *testing.T) {
+	dir, err := ioutil.TempDir("", "diamdict")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	var b bytes.Buffer
+	err = convert(&b, dict.JSON,
+		"../../diam/dict/testdata/base.xml",
+		"../../diam/dict/testdata/credit_control.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	filename := filepath.Join(dir, "cc.json")
+	if err = ioutil.WriteFile(filename, b.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p, err := dict.NewParser(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = p.FindCommand(4, 272); err != nil {
+		t.Fatal(err)
+	}
+	a, err := p.FindAVP(4, "CC-Request-Type")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a.Data.Enum) != 3 {
+		t.Fatalf("Unexpected CC-Request-Type items: %d", len(a.Data.Enum))
+	}
+
+	b.Reset()
+	if err = convert(&b, dict.XML, filename); err != nil {
+		t.Fatal(err)
+	}
+	f, err := dict.Decode(&b, dict.XML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.App) != 3 || f.App[2].ID != 4 {
+		t.Fatalf("Unexpected applications: %d", len(f.App))
+	}
+}
+
+func TestConvert_Invalid(t *testing.T) {
+	if err := convert(ioutil.Discard, dict.JSON, "no-such-dictionary.xml"); err == nil {
+		t.Fatal("No error converting a missing dictionary")
+	}
+}
+
+func TestConvertWireshark(t *testing.T) {
+	var b bytes.Buffer
+	err := convertWireshark(&b, dict.XML, "../../diam/dict/wireshark/testdata/dictionary.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := dict.NewParser()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = p.Load(&b); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = p.FindCommand(4, 272); err != nil {
+		t.Fatal(err)
+	}
+}
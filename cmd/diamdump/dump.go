@@ -0,0 +1,176 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+	"github.com/omnicate/go-diameter/v4/diam/pcap"
+)
+
+// Kinds of messages matched by a command filter.
+const (
+	anyMessage = iota
+	requestsOnly
+	answersOnly
+)
+
+type command struct {
+	code uint32
+	kind int
+}
+
+// filter selects the messages to print.
+type filter struct {
+	cmds    []command
+	app     int64 // -1 for any
+	session string
+	port    uint16
+}
+
+// newFilter returns a filter of the messages of the commands cmds, a
+// comma separated list of codes, names or short names, the application
+// app, the Session-Id session and the port. Empty, negative or zero
+// values match any message.
+func newFilter(cmds string, app int64, session string, port int, d *dict.Parser) (*filter, error) {
+	if port < 0 || port > 65535 {
+		return nil, fmt.Errorf("invalid port %d", port)
+	}
+	f := &filter{app: app, session: session, port: uint16(port)}
+	for _, s := range strings.Split(cmds, ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		c, err := findCommand(s, d)
+		if err != nil {
+			return nil, err
+		}
+		f.cmds = append(f.cmds, c)
+	}
+	return f, nil
+}
+
+// findCommand returns the command of a code, name or short name.
+func findCommand(s string, d *dict.Parser) (command, error) {
+	if code, err := strconv.ParseUint(s, 10, 32); err == nil {
+		return command{code: uint32(code)}, nil
+	}
+	for _, app := range d.Apps() {
+		for _, cmd := range app.Command {
+			switch {
+			case strings.EqualFold(s, cmd.Name):
+				return command{cmd.Code, anyMessage}, nil
+			case strings.EqualFold(s, cmd.Short+"R"):
+				return command{cmd.Code, requestsOnly}, nil
+			case strings.EqualFold(s, cmd.Short+"A"):
+				return command{cmd.Code, answersOnly}, nil
+			}
+		}
+	}
+	return command{}, fmt.Errorf("unknown command %q", s)
+}
+
+func (f *filter) matchFlow(fl pcap.Flow) bool {
+	return f.port == 0 || fl.SrcPort == f.port || fl.DstPort == f.port
+}
+
+func (f *filter) match(m *pcap.Message) bool {
+	if !f.matchFlow(m.Flow) {
+		return false
+	}
+	if f.app >= 0 && int64(m.Header.ApplicationID) != f.app {
+		return false
+	}
+	if len(f.cmds) > 0 && !f.matchCommand(m.Header) {
+		return false
+	}
+	if f.session != "" {
+		sid, err := m.FindAVP(avp.SessionID, 0)
+		if err != nil {
+			return false
+		}
+		if s, ok := sid.Data.(datatype.UTF8String); !ok || string(s) != f.session {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *filter) matchCommand(h *diam.Header) bool {
+	req := h.CommandFlags&diam.RequestFlag != 0
+	for _, c := range f.cmds {
+		if c.code != h.CommandCode {
+			continue
+		}
+		switch c.kind {
+		case anyMessage:
+			return true
+		case requestsOnly:
+			if req {
+				return true
+			}
+		case answersOnly:
+			if !req {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// dumper prints the messages of a capture.
+type dumper struct {
+	filter  *filter
+	json    bool
+	verbose bool
+}
+
+// jsonMessage is the JSON form of a printed message.
+type jsonMessage struct {
+	Timestamp time.Time     `json:"timestamp"`
+	Flow      string        `json:"flow"`
+	Message   *diam.Message `json:"message"`
+}
+
+// dump prints the messages of r to w, and the messages that cannot be
+// decoded to errw if verbose.
+func (d *dumper) dump(w, errw io.Writer, r *pcap.Reader) error {
+	enc := json.NewEncoder(w)
+	for {
+		m, err := r.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if derr, ok := err.(*pcap.DecodeError); ok {
+			if d.verbose && d.filter.matchFlow(derr.Flow) {
+				fmt.Fprintln(errw, derr)
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !d.filter.match(m) {
+			continue
+		}
+		if d.json {
+			err = enc.Encode(&jsonMessage{m.Timestamp, m.Flow.String(), m.Message})
+		} else {
+			_, err = fmt.Fprintf(w, "%s %s\n%s\n", m.Timestamp.Format(time.RFC3339Nano), m.Flow, m.Message)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+	"github.com/omnicate/go-diameter/v4/diam/pcap"
+)
+
+func newCCR(session string) *diam.Message {
+	m := diam.NewRequest(diam.CreditControl, 4, dict.Default)
+	m.NewAVP(avp.SessionID, avp.Mbit, 0, datatype.UTF8String(session))
+	m.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("cli"))
+	m.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity("cli.test"))
+	m.NewAVP(avp.DestinationRealm, avp.Mbit, 0, datatype.DiameterIdentity("srv.test"))
+	m.NewAVP(avp.AuthApplicationID, avp.Mbit, 0, datatype.Unsigned32(4))
+	m.NewAVP(avp.CCRequestType, avp.Mbit, 0, datatype.Enumerated(1))
+	m.NewAVP(avp.CCRequestNumber, avp.Mbit, 0, datatype.Unsigned32(0))
+	return m
+}
+
+func newCCA(req *diam.Message) *diam.Message {
+	a := req.Answer(diam.Success)
+	a.AddAVP(req.AVP[0])
+	a.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("srv"))
+	a.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity("srv.test"))
+	return a
+}
+
+func dump(t *testing.T, d *dumper, msgs ...*diam.Message) string {
+	t.Helper()
+	var capture bytes.Buffer
+	if err := pcap.WriteMessages(&capture, msgs); err != nil {
+		t.Fatal(err)
+	}
+	r, err := pcap.NewReader(&capture, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out, errs bytes.Buffer
+	if err = d.dump(&out, &errs, r); err != nil {
+		t.Fatal(err)
+	}
+	return out.String()
+}
+
+func TestDumpFilter(t *testing.T) {
+	req1, req2 := newCCR("s1"), newCCR("s2")
+	msgs := []*diam.Message{req1, newCCA(req1), req2, newCCA(req2)}
+	for _, tc := range []struct {
+		cmds, session string
+		app           int64
+		port          int
+		want          []string
+	}{
+		{"", "", -1, 0, []string{"s1", "s1", "s2", "s2"}},
+		{"CCR", "", -1, 0, []string{"s1", "s2"}},
+		{"cca", "s2", -1, 0, []string{"s2"}},
+		{"Credit-Control", "s1", 4, 3868, []string{"s1", "s1"}},
+		{"272", "", 3, 0, nil},
+		{"", "", -1, 3869, nil},
+	} {
+		f, err := newFilter(tc.cmds, tc.app, tc.session, tc.port, dict.Default)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out := dump(t, &dumper{filter: f, json: true}, msgs...)
+		var have []string
+		for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+			if line == "" {
+				continue
+			}
+			var j struct {
+				Flow    string
+				Message struct {
+					AVPs []struct {
+						Name  string
+						Value interface{}
+					}
+				}
+			}
+			if err = json.Unmarshal([]byte(line), &j); err != nil {
+				t.Fatal(err)
+			}
+			for _, a := range j.Message.AVPs {
+				if a.Name == "Session-Id" {
+					have = append(have, a.Value.(string))
+				}
+			}
+		}
+		if strings.Join(have, ",") != strings.Join(tc.want, ",") {
+			t.Fatalf("Unexpected sessions with %+v: %v", tc, have)
+		}
+	}
+	if _, err := newFilter("XYZ", -1, "", 0, dict.Default); err == nil {
+		t.Fatal("Unexpected success with unknown command")
+	}
+}
+
+func TestDumpText(t *testing.T) {
+	f, _ := newFilter("", -1, "", 0, dict.Default)
+	out := dump(t, &dumper{filter: f}, newCCR("s1"))
+	for _, want := range []string{
+		"2020-01-01T00:00:00Z tcp 127.0.0.1:49152 > 127.0.0.1:3868\n",
+		"Credit-Control-Request (CCR)",
+		"Session-Id {Code:263",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("Output is missing %q:\n%s", want, out)
+		}
+	}
+}
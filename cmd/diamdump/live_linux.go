@@ -0,0 +1,89 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// +build linux
+
+package main
+
+import (
+	"net"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam/pcap"
+)
+
+// liveSource is a pcap.PacketSource of the IP packets of a packet socket.
+type liveSource struct {
+	fd       int
+	buf      []byte
+	loopback map[int]bool // indexes of loopback interfaces
+}
+
+func htons(v uint16) uint16 {
+	return v<<8 | v>>8
+}
+
+// openLive returns a pcap.PacketSource of the traffic of the interface
+// name, or of all interfaces if name is any.
+func openLive(name string) (pcap.PacketSource, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	src := &liveSource{buf: make([]byte, 1<<16), loopback: make(map[int]bool)}
+	index := -1
+	for _, ifi := range ifaces {
+		if ifi.Flags&net.FlagLoopback != 0 {
+			src.loopback[ifi.Index] = true
+		}
+		if ifi.Name == name {
+			index = ifi.Index
+		}
+	}
+	if name == "any" {
+		index = 0
+	}
+	if index < 0 {
+		return nil, &net.OpError{Op: "listen", Net: "ip", Err: &net.AddrError{Err: "no such interface", Addr: name}}
+	}
+	// Datagram packet sockets receive packets without link layer header.
+	fd, err := syscall.Socket(syscall.AF_PACKET, syscall.SOCK_DGRAM, int(htons(syscall.ETH_P_ALL)))
+	if err != nil {
+		return nil, os.NewSyscallError("socket", err)
+	}
+	sa := &syscall.SockaddrLinklayer{Protocol: htons(syscall.ETH_P_ALL), Ifindex: index}
+	if err = syscall.Bind(fd, sa); err != nil {
+		syscall.Close(fd)
+		return nil, os.NewSyscallError("bind", err)
+	}
+	src.fd = fd
+	return src, nil
+}
+
+// ReadPacket implements the pcap.PacketSource interface.
+func (s *liveSource) ReadPacket() (time.Time, int, []byte, error) {
+	for {
+		n, from, err := syscall.Recvfrom(s.fd, s.buf, 0)
+		if err == syscall.EINTR {
+			continue
+		}
+		if err != nil {
+			return time.Time{}, 0, nil, os.NewSyscallError("recvfrom", err)
+		}
+		ll, ok := from.(*syscall.SockaddrLinklayer)
+		if !ok {
+			continue
+		}
+		if p := htons(ll.Protocol); p != syscall.ETH_P_IP && p != syscall.ETH_P_IPV6 {
+			continue
+		}
+		// Packets on loopback interfaces are received twice.
+		if ll.Pkttype == syscall.PACKET_OUTGOING && s.loopback[ll.Ifindex] {
+			continue
+		}
+		return time.Now(), pcap.LinkTypeRaw, append([]byte(nil), s.buf[:n]...), nil
+	}
+}
@@ -0,0 +1,17 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// +build !linux
+
+package main
+
+import (
+	"errors"
+
+	"github.com/omnicate/go-diameter/v4/diam/pcap"
+)
+
+func openLive(name string) (pcap.PacketSource, error) {
+	return nil, errors.New("live capture is only supported on Linux")
+}
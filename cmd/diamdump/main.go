@@ -0,0 +1,92 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Diamdump prints the Diameter messages of pcap and pcapng captures, or
+// of live traffic, decoded with the dictionaries.
+//
+// Use: diamdump [flags] (-r file | -i interface)
+//
+// Captures are read with -r, - for the standard input, and live traffic
+// is sniffed on an interface, or on all of them with -i any, which
+// requires Linux and the CAP_NET_RAW capability.
+//
+// Messages can be filtered by command with -cmd, a comma separated list
+// of command codes, names such as Credit-Control, or short names such as
+// CCR for requests and CCA for answers; by application with -app; by
+// Session-Id with -session; and by TCP or SCTP port with -port.
+//
+// With -json, messages are printed one per line in the JSON form of
+// diam.Message, for piping into jq:
+//
+//	diamdump -r trace.pcapng -cmd CCR -json | jq .message.avps
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+	"github.com/omnicate/go-diameter/v4/diam/pcap"
+)
+
+func main() {
+	file := flag.String("r", "", "read the capture file (- for stdin)")
+	iface := flag.String("i", "", "capture live traffic on the interface (any for all)")
+	files := flag.String("dict", "", "comma separated list of dictionaries")
+	cmds := flag.String("cmd", "", "comma separated list of commands to print")
+	app := flag.Int64("app", -1, "print only messages of this Application-Id")
+	session := flag.String("session", "", "print only messages of this Session-Id")
+	port := flag.Int("port", 0, "print only messages to or from this port")
+	asJSON := flag.Bool("json", false, "print messages as JSON, one per line")
+	verbose := flag.Bool("v", false, "print messages that cannot be decoded")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [flags] (-r file | -i interface)\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if (*file == "") == (*iface == "") {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *files != "" {
+		for _, f := range strings.Split(*files, ",") {
+			if err := dict.Default.LoadFile(f); err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
+	f, err := newFilter(*cmds, *app, *session, *port, dict.Default)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var r *pcap.Reader
+	if *iface != "" {
+		src, err := openLive(*iface)
+		if err != nil {
+			log.Fatal(err)
+		}
+		r = pcap.NewPacketReader(src, dict.Default)
+	} else {
+		var in io.Reader = os.Stdin
+		if *file != "-" {
+			fd, err := os.Open(*file)
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer fd.Close()
+			in = fd
+		}
+		if r, err = pcap.NewReader(in, dict.Default); err != nil {
+			log.Fatal(err)
+		}
+	}
+	d := &dumper{filter: f, json: *asJSON, verbose: *verbose}
+	if err = d.dump(os.Stdout, os.Stderr, r); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,397 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+const (
+	diamPkg     = "github.com/omnicate/go-diameter/v4/diam"
+	datatypePkg = "github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// generator generates the Go source of the applications of a set of
+// dictionary files.
+type generator struct {
+	pkg        string
+	files      []string
+	parser     *dict.Parser
+	useDefault bool
+	warnings   []string
+
+	names   map[string]string // generated identifiers, to detect clashes
+	imports map[string]bool
+	groups  []*group          // queue of the Grouped AVP types to generate
+	grouped map[string]*group // by AVP name
+	warned  map[string]bool
+}
+
+// group is the struct type of a Grouped AVP.
+type group struct {
+	name  string
+	avp   *dict.AVP
+	appid uint32
+}
+
+func newGenerator(pkg string, useDefault bool, files ...string) (*generator, error) {
+	p, err := dict.NewParser(files...)
+	if err != nil {
+		return nil, err
+	}
+	return &generator{
+		pkg:        pkg,
+		files:      files,
+		parser:     p,
+		useDefault: useDefault,
+		names:      make(map[string]string),
+		imports:    make(map[string]bool),
+		grouped:    make(map[string]*group),
+		warned:     make(map[string]bool),
+	}, nil
+}
+
+// generate returns the formatted Go source.
+func (g *generator) generate() ([]byte, error) {
+	var body bytes.Buffer
+	apps := g.parser.Apps()
+	if err := g.constants(&body, apps); err != nil {
+		return nil, err
+	}
+	for _, app := range apps {
+		for _, cmd := range app.Command {
+			if err := g.message(&body, app, cmd, true); err != nil {
+				return nil, err
+			}
+			if err := g.message(&body, app, cmd, false); err != nil {
+				return nil, err
+			}
+		}
+	}
+	// Generating a group may queue the groups of its own AVPs.
+	for i := 0; i < len(g.groups); i++ {
+		g.group(&body, g.groups[i])
+	}
+
+	var src bytes.Buffer
+	names := make([]string, len(g.files))
+	for i, f := range g.files {
+		names[i] = filepath.Base(f)
+	}
+	fmt.Fprintf(&src, "// Code generated by diamgen from %s. DO NOT EDIT.\n\n", strings.Join(names, ", "))
+	fmt.Fprintf(&src, "package %s\n\n", g.pkg)
+	if len(g.imports) > 0 {
+		var imports []string
+		for imp := range g.imports {
+			imports = append(imports, imp)
+		}
+		sort.Strings(imports)
+		src.WriteString("import (\n")
+		std := true
+		for _, imp := range imports {
+			// Standard library packages come first.
+			if std && strings.Contains(imp, ".") {
+				std = false
+				src.WriteString("\n")
+			}
+			fmt.Fprintf(&src, "\t%q\n", imp)
+		}
+		src.WriteString(")\n\n")
+	}
+	src.Write(body.Bytes())
+	b, err := format.Source(src.Bytes())
+	if err != nil {
+		return src.Bytes(), fmt.Errorf("failed to format generated code: %v", err)
+	}
+	return b, nil
+}
+
+// define reserves the identifier name for the given definition. It
+// fails if name is already used by a different definition.
+func (g *generator) define(name, def string) error {
+	if prev, ok := g.names[name]; ok && prev != def {
+		return fmt.Errorf("%s is generated for both %s and %s", name, prev, def)
+	}
+	g.names[name] = def
+	return nil
+}
+
+// defined reports whether name is used by a definition other than def.
+func (g *generator) defined(name, def string) bool {
+	prev, ok := g.names[name]
+	return ok && prev != def
+}
+
+func (g *generator) warn(format string, args ...interface{}) {
+	w := fmt.Sprintf(format, args...)
+	if !g.warned[w] {
+		g.warned[w] = true
+		g.warnings = append(g.warnings, w)
+	}
+}
+
+// constants writes the Application-Id, command code, AVP code and
+// Enumerated value constants of the applications.
+func (g *generator) constants(w *bytes.Buffer, apps []*dict.App) error {
+	w.WriteString("// Application IDs.\nconst (\n")
+	for _, app := range apps {
+		name := ident(app.Name) + "ApplicationID"
+		if err := g.define(name, fmt.Sprintf("application %d", app.ID)); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "\t%s = %d // %s\n", name, app.ID, app.Name)
+	}
+	w.WriteString(")\n\n")
+
+	w.WriteString("// Command codes.\nconst (\n")
+	for _, app := range apps {
+		for _, cmd := range app.Command {
+			name := ident(cmd.Name) + "Command"
+			if err := g.define(name, fmt.Sprintf("command %d", cmd.Code)); err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "\t%s = %d\n", name, cmd.Code)
+		}
+	}
+	w.WriteString(")\n\n")
+
+	var avps []*dict.AVP
+	seen := make(map[string]bool)
+	for _, app := range apps {
+		for _, a := range app.AVP {
+			name := ident(a.Name) + "AVP"
+			if err := g.define(name, fmt.Sprintf("AVP %d", a.Code)); err != nil {
+				return err
+			}
+			if !seen[name] {
+				seen[name] = true
+				avps = append(avps, a)
+			}
+		}
+	}
+	sort.Slice(avps, func(i, j int) bool { return avps[i].Name < avps[j].Name })
+	w.WriteString("// AVP codes.\nconst (\n")
+	for _, a := range avps {
+		fmt.Fprintf(w, "\t%sAVP = %d\n", ident(a.Name), a.Code)
+	}
+	w.WriteString(")\n")
+
+	for _, a := range avps {
+		if a.Data.Type != datatype.EnumeratedType || len(a.Data.Enum) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n// Values of the %s AVP.\nconst (\n", a.Name)
+		prefix := ident(a.Name)
+		for _, item := range a.Data.Enum {
+			name := prefix + enumIdent(item.Name)
+			def := fmt.Sprintf("%s value %d", a.Name, item.Code)
+			if g.defined(name, def) {
+				name = fmt.Sprintf("%s%d", name, item.Code)
+			}
+			if err := g.define(name, def); err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "\t%s = %d\n", name, item.Code)
+		}
+		w.WriteString(")\n")
+	}
+	return nil
+}
+
+// message writes the struct of the request or answer of the command.
+func (g *generator) message(w *bytes.Buffer, app *dict.App, cmd *dict.Command, request bool) error {
+	suffix, kind, rules := "A", "Answer", cmd.Answer.Rule
+	if request {
+		suffix, kind, rules = "R", "Request", cmd.Request.Rule
+	}
+	short := cmd.Short + suffix
+	name := ident(short)
+	def := fmt.Sprintf("message %s of application %d", short, app.ID)
+	if g.defined(name, def) {
+		// Applications may reuse the short names of other applications.
+		name = ident(app.Name) + name
+	}
+	if err := g.define(name, def); err != nil {
+		return err
+	}
+	g.imports[diamPkg] = true
+	fmt.Fprintf(w, "\n// %s is the %s-%s of the %s application.\n", name, cmd.Name, kind, app.Name)
+	fmt.Fprintf(w, "type %s struct {\n", name)
+	fmt.Fprintf(w, "\tHeader diam.Header `diam:\"%s,app=%d\"`\n", short, app.ID)
+	g.fields(w, short, app.ID, rules, map[string]bool{"Header": true})
+	w.WriteString("}\n")
+	return nil
+}
+
+// group writes the struct of a Grouped AVP.
+func (g *generator) group(w *bytes.Buffer, gr *group) {
+	fmt.Fprintf(w, "\n// %s is the content of the %s AVP.\n", gr.name, gr.avp.Name)
+	fmt.Fprintf(w, "type %s struct {\n", gr.name)
+	g.fields(w, gr.avp.Name, gr.appid, gr.avp.Data.Rule, make(map[string]bool))
+	w.WriteString("}\n")
+}
+
+// fields writes the struct fields of the AVP rules of a message or
+// Grouped AVP.
+func (g *generator) fields(w *bytes.Buffer, parent string, appid uint32, rules []*dict.Rule, seen map[string]bool) {
+	for _, rule := range rules {
+		a := g.findAVP(appid, rule.AVP)
+		if a == nil {
+			g.warn("%s: AVP %s not found, skipped", parent, rule.AVP)
+			continue
+		}
+		name := ident(a.Name)
+		if seen[name] {
+			continue
+		}
+		typ := g.goType(a, appid, rule.Max != 1, !rule.Required)
+		if typ == "" {
+			g.warn("%s: AVP %s has unsupported type %s, skipped", parent, a.Name, a.Data.TypeName)
+			continue
+		}
+		seen[name] = true
+		tag := a.Name
+		if !rule.Required {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(w, "\t%s %s `avp:\"%s\"`\n", name, typ, tag)
+	}
+}
+
+// findAVP looks up an AVP in the dictionaries being generated, then in
+// the default dictionary.
+func (g *generator) findAVP(appid uint32, name string) *dict.AVP {
+	if a, err := g.parser.FindAVP(appid, name); err == nil {
+		return a
+	}
+	if g.useDefault {
+		if a, err := dict.Default.FindAVP(appid, name); err == nil {
+			return a
+		}
+	}
+	return nil
+}
+
+// goType returns the Go type of the field of the AVP a. Multiple AVPs
+// are slices, and Grouped AVPs pointers to their struct. Optional
+// Enumerated AVPs are pointers too, as their zero value is meaningful.
+func (g *generator) goType(a *dict.AVP, appid uint32, multiple, optional bool) string {
+	var typ string
+	switch a.Data.Type {
+	case datatype.AddressType, datatype.IPv4Type, datatype.IPv6Type:
+		g.imports["net"] = true
+		typ = "net.IP"
+	case datatype.DiameterIdentityType:
+		g.imports[datatypePkg] = true
+		typ = "datatype.DiameterIdentity"
+	case datatype.DiameterURIType:
+		g.imports[datatypePkg] = true
+		typ = "datatype.DiameterURI"
+	case datatype.IPFilterRuleType:
+		g.imports[datatypePkg] = true
+		typ = "datatype.IPFilterRule"
+	case datatype.QoSFilterRuleType:
+		g.imports[datatypePkg] = true
+		typ = "datatype.QoSFilterRule"
+	case datatype.EnumeratedType, datatype.Integer32Type:
+		typ = "int32"
+	case datatype.Integer64Type:
+		typ = "int64"
+	case datatype.Unsigned32Type:
+		typ = "uint32"
+	case datatype.Unsigned64Type:
+		typ = "uint64"
+	case datatype.Float32Type:
+		typ = "float32"
+	case datatype.Float64Type:
+		typ = "float64"
+	case datatype.OctetStringType:
+		typ = "[]byte"
+	case datatype.UTF8StringType:
+		typ = "string"
+	case datatype.TimeType:
+		g.imports["time"] = true
+		typ = "time.Time"
+	case datatype.GroupedType:
+		typ = "*" + g.groupType(a, appid)
+	default:
+		return ""
+	}
+	switch {
+	case multiple:
+		return "[]" + typ
+	case optional && a.Data.Type == datatype.EnumeratedType:
+		return "*" + typ
+	}
+	return typ
+}
+
+// groupType returns the name of the struct of the Grouped AVP a,
+// queueing it for generation.
+func (g *generator) groupType(a *dict.AVP, appid uint32) string {
+	if gr, ok := g.grouped[a.Name]; ok {
+		return gr.name
+	}
+	if a.App != nil {
+		appid = a.App.ID
+	}
+	name := ident(a.Name)
+	def := "Grouped AVP " + a.Name
+	if g.defined(name, def) {
+		name += "Group"
+	}
+	g.define(name, def)
+	gr := &group{name: name, avp: a, appid: appid}
+	g.grouped[a.Name] = gr
+	g.groups = append(g.groups, gr)
+	return name
+}
+
+// ident returns the Go identifier of a dictionary name, following the
+// conventions of the avp package: Session-Id becomes SessionID.
+func ident(s string) string {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, w := range words {
+		switch w {
+		case "Id":
+			words[i] = "ID"
+		case "Ids":
+			words[i] = "IDs"
+		default:
+			words[i] = strings.ToUpper(w[:1]) + w[1:]
+		}
+	}
+	id := strings.Join(words, "")
+	if id == "" || unicode.IsDigit(rune(id[0])) {
+		id = "X" + id
+	}
+	return id
+}
+
+// enumIdent returns the identifier suffix of an Enumerated item name.
+// Upper case names such as INITIAL_REQUEST become InitialRequest.
+func enumIdent(s string) string {
+	if strings.ToUpper(s) == s {
+		s = strings.ToLower(s)
+	}
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, "")
+}
@@ -0,0 +1,66 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	g, err := newGenerator("cc", true, "../../diam/dict/testdata/credit_control.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := g.generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = parser.ParseFile(token.NewFileSet(), "cc.go", b, 0); err != nil {
+		t.Fatal(err)
+	}
+	// Ignore the alignment of gofmt.
+	src := strings.Join(strings.Fields(string(b)), " ")
+	for _, want := range []string{
+		"package cc",
+		"ChargingControlApplicationID = 4",
+		"CreditControlCommand = 272",
+		"CCRequestTypeAVP = 416",
+		"CCRequestTypeInitialRequest = 1",
+		"type CCR struct {",
+		"`diam:\"CCR,app=4\"`",
+		"`avp:\"Session-Id\"`",
+		"*MultipleServicesCreditControl `avp:\"Multiple-Services-Credit-Control,omitempty\"`",
+		"type GrantedServiceUnit struct {",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("Generated code is missing %q", want)
+		}
+	}
+}
+
+func TestIdent(t *testing.T) {
+	for name, want := range map[string]string{
+		"Session-Id":          "SessionID",
+		"Supported-Vendor-Id": "SupportedVendorID",
+		"CC-Request-Type":     "CCRequestType",
+		"3GPP-IMSI":           "X3GPPIMSI",
+	} {
+		if id := ident(name); id != want {
+			t.Errorf("Unexpected identifier of %s. Want %s, have %s", name, want, id)
+		}
+	}
+	for name, want := range map[string]string{
+		"INITIAL_REQUEST": "InitialRequest",
+		"ISDN Sync":       "ISDNSync",
+		"ipv4-address":    "Ipv4Address",
+	} {
+		if id := enumIdent(name); id != want {
+			t.Errorf("Unexpected identifier of %s. Want %s, have %s", name, want, id)
+		}
+	}
+}
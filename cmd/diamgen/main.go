@@ -0,0 +1,66 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Diamgen generates Go constants and message types from dictionary XML
+// files.
+//
+// Use: diamgen [-pkg name] [-o file.go] dictionary.xml...
+//
+// For each application of the dictionaries it generates constants for
+// the Application-Id, command codes, AVP codes and values of Enumerated
+// AVPs, and a struct per request and answer that can be encoded with
+// diam.Marshal and decoded with diam.Message.Unmarshal. Grouped AVPs
+// used by the messages get their own struct.
+//
+// AVPs referenced but not defined by the dictionaries, such as those of
+// the base protocol, are looked up in the default dictionary unless
+// -default=false is given. Messages are encoded with the default
+// dictionary, so custom dictionaries must also be loaded in dict.Default
+// at run time.
+//
+// Diamgen can be run by go generate:
+//
+//	//go:generate diamgen -pkg myapp -o dict.go myapp.xml
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+)
+
+func main() {
+	pkg := flag.String("pkg", "main", "package name of the generated file")
+	out := flag.String("o", "", "output file (default stdout)")
+	useDefault := flag.Bool("default", true, "resolve AVPs with the default dictionary")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [flags] dictionary.xml...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	g, err := newGenerator(*pkg, *useDefault, flag.Args()...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	b, err := g.generate()
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, w := range g.warnings {
+		log.Print(w)
+	}
+	if *out == "" {
+		os.Stdout.Write(b)
+		return
+	}
+	if err = ioutil.WriteFile(*out, b, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Diamperf is a load generator for Diameter servers.
+//
+// Use: diamperf [flags]
+//
+// It dials -conns connections to the server at -addr, and runs sessions
+// on -c concurrent workers spread over the connections, at a total rate
+// of -rate requests per second, for -duration or until -n requests are
+// sent. It then reports the latency percentiles of each request type,
+// and the distribution of Result-Codes and errors.
+//
+// By default, sessions are credit control sessions of a CCR-I, -updates
+// CCR-U and a CCR-T, so the ratio of CCR-I/U/T is 1:updates:1. Other
+// scenarios are loaded from JSON files with -scenario:
+//
+//	{"steps": [
+//		{"name": "STR", "message": {
+//			"header": {"flags": 192, "code": 275, "application_id": 1},
+//			"avps": [
+//				{"name": "Session-Id", "value": "${session_id}"},
+//				{"name": "Destination-Realm", "value": "${destination_realm}"},
+//				{"name": "Auth-Application-Id", "value": 1},
+//				{"name": "Termination-Cause", "value": 1}
+//			]
+//		}}
+//	]}
+//
+// The messages of the steps are the JSON form of diam.Message. Their
+// variables session_id, request_number (the index of the step),
+// origin_host, origin_realm and destination_realm are substituted, and
+// their Origin-Host and Origin-Realm added if missing.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+	"github.com/omnicate/go-diameter/v4/diam/sm"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:3868", "address of the server")
+	network := flag.String("network", "tcp", "network of the server, tcp or sctp")
+	host := flag.String("host", "diamperf", "Origin-Host of the client")
+	realm := flag.String("realm", "go-diameter", "Origin-Realm of the client")
+	destRealm := flag.String("dest_realm", "go-diameter", "Destination-Realm of the requests")
+	authApps := flag.String("auth", "4", "comma separated list of Auth-Application-Ids")
+	files := flag.String("dict", "", "comma separated list of dictionaries")
+	conns := flag.Int("conns", 1, "number of connections")
+	concurrency := flag.Int("c", 10, "number of concurrent sessions")
+	rate := flag.Float64("rate", 100, "requests per second, 0 for unlimited")
+	duration := flag.Duration("duration", 10*time.Second, "duration of the run")
+	max := flag.Int64("n", 0, "number of requests to send, 0 for unlimited")
+	timeout := flag.Duration("timeout", 5*time.Second, "time to wait for each answer")
+	updates := flag.Int("updates", 1, "number of CCR-U per credit control session")
+	scenarioFile := flag.String("scenario", "", "JSON file of the scenario of sessions")
+	flag.Parse()
+	if *conns < 1 || *concurrency < 1 {
+		log.Fatal("-conns and -c must be positive")
+	}
+	if *files != "" {
+		for _, f := range strings.Split(*files, ",") {
+			if err := dict.Default.LoadFile(f); err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
+	s := creditControlScenario(*updates)
+	if *scenarioFile != "" {
+		var err error
+		if s, err = loadScenario(*scenarioFile); err != nil {
+			log.Fatal(err)
+		}
+	}
+	settings := &sm.Settings{
+		OriginHost:  datatype.DiameterIdentity(*host),
+		OriginRealm: datatype.DiameterIdentity(*realm),
+		VendorID:    13,
+		ProductName: "go-diameter",
+	}
+	cli := &sm.Client{Handler: sm.New(settings)}
+	for _, v := range strings.Split(*authApps, ",") {
+		id, err := strconv.ParseUint(strings.TrimSpace(v), 10, 32)
+		if err != nil {
+			log.Fatalf("invalid application id %q", v)
+		}
+		cli.AuthApplicationID = append(cli.AuthApplicationID,
+			diam.NewAVP(avp.AuthApplicationID, avp.Mbit, 0, datatype.Unsigned32(id)))
+	}
+	var cs []diam.Conn
+	for i := 0; i < *conns; i++ {
+		c, err := cli.DialNetwork(*network, *addr)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer c.Close()
+		cs = append(cs, c)
+	}
+
+	r := newRunner(cs, s, settings)
+	r.vars["destination_realm"] = *destRealm
+	r.timeout = *timeout
+	r.rate = *rate
+	r.max = *max
+	if err := r.validate(); err != nil {
+		log.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), *duration)
+	defer cancel()
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, os.Interrupt)
+	go func() {
+		<-sigc
+		cancel()
+	}()
+	go progress(ctx, r.stats)
+	r.run(ctx, *concurrency)
+	r.stats.report(os.Stdout)
+}
+
+// progress prints the number of requests sent each second until ctx is
+// done.
+func progress(ctx context.Context, s *stats) {
+	t := time.NewTicker(time.Second)
+	defer t.Stop()
+	last := 0
+	for {
+		select {
+		case <-t.C:
+			n := s.sent()
+			fmt.Fprintf(os.Stderr, "%d requests, %d/s\n", n, n-last)
+			last = n
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package main
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+	"github.com/omnicate/go-diameter/v4/diam/session"
+	"github.com/omnicate/go-diameter/v4/diam/sm"
+)
+
+// runner runs the sessions of a scenario on a set of connections.
+type runner struct {
+	conns    []diam.Conn
+	scenario *scenario
+	settings *sm.Settings
+	vars     map[string]string // template variables besides the predefined
+	dict     *dict.Parser
+	timeout  time.Duration // time to wait for each answer
+	rate     float64       // requests per second, unlimited if zero
+	max      int64         // number of requests to send, unlimited if zero
+	ids      *session.IDGenerator
+	stats    *stats
+	sent     int64 // atomic
+}
+
+func newRunner(conns []diam.Conn, s *scenario, settings *sm.Settings) *runner {
+	return &runner{
+		conns:    conns,
+		scenario: s,
+		settings: settings,
+		vars:     make(map[string]string),
+		dict:     dict.Default,
+		timeout:  5 * time.Second,
+		ids:      session.NewIDGenerator(settings.OriginHost),
+		stats:    newStats(),
+	}
+}
+
+// sessionVars returns the template variables of a new session.
+func (r *runner) sessionVars() map[string]string {
+	vars := map[string]string{
+		"session_id":   string(r.ids.Next()),
+		"origin_host":  string(r.settings.OriginHost),
+		"origin_realm": string(r.settings.OriginRealm),
+	}
+	for k, v := range r.vars {
+		vars[k] = v
+	}
+	return vars
+}
+
+// validate checks that the messages of the scenario can be built.
+func (r *runner) validate() error {
+	vars := r.sessionVars()
+	for i, st := range r.scenario.Steps {
+		vars["request_number"] = strconv.Itoa(i)
+		if _, err := st.message(vars, r.dict); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// run runs sessions on concurrency workers, spread over the connections,
+// until ctx is done or the maximum number of requests is sent.
+func (r *runner) run(ctx context.Context, concurrency int) {
+	var tick <-chan time.Time
+	if r.rate > 0 {
+		t := time.NewTicker(time.Duration(float64(time.Second) / r.rate))
+		defer t.Stop()
+		tick = t.C
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func(c diam.Conn) {
+			defer wg.Done()
+			for r.session(ctx, c, tick) {
+			}
+		}(r.conns[i%len(r.conns)])
+	}
+	wg.Wait()
+	r.stats.stop()
+}
+
+// session sends the requests of a session on c, one per tick, and
+// reports whether the run goes on.
+func (r *runner) session(ctx context.Context, c diam.Conn, tick <-chan time.Time) bool {
+	vars := r.sessionVars()
+	for i, st := range r.scenario.Steps {
+		if tick != nil {
+			select {
+			case <-tick:
+			case <-ctx.Done():
+				return false
+			}
+		} else if ctx.Err() != nil {
+			return false
+		}
+		if r.max > 0 && atomic.AddInt64(&r.sent, 1) > r.max {
+			return false
+		}
+		vars["request_number"] = strconv.Itoa(i)
+		m, err := st.message(vars, r.dict)
+		if err != nil {
+			r.stats.record(st.Name, 0, 0, err)
+			continue
+		}
+		if _, err = m.FindAVP(avp.OriginHost, 0); err != nil {
+			m.NewAVP(avp.OriginHost, avp.Mbit, 0, r.settings.OriginHost)
+		}
+		if _, err = m.FindAVP(avp.OriginRealm, 0); err != nil {
+			m.NewAVP(avp.OriginRealm, avp.Mbit, 0, r.settings.OriginRealm)
+		}
+		start := time.Now()
+		rctx, cancel := context.WithTimeout(ctx, r.timeout)
+		a, err := diam.SendRequest(rctx, c, m)
+		cancel()
+		if err != nil && ctx.Err() != nil {
+			return false // the run ended while waiting
+		}
+		if err != nil {
+			r.stats.record(st.Name, 0, 0, err)
+			continue
+		}
+		r.stats.record(st.Name, time.Since(start), resultCode(a), nil)
+	}
+	return true
+}
+
+// resultCode returns the Result-Code or Experimental-Result-Code of the
+// answer m, or 0 if it has none.
+func resultCode(m *diam.Message) uint32 {
+	if rc, err := m.FindAVP(avp.ResultCode, 0); err == nil {
+		if v, ok := rc.Data.(datatype.Unsigned32); ok {
+			return uint32(v)
+		}
+	}
+	path := []interface{}{avp.ExperimentalResult, avp.ExperimentalResultCode}
+	if erc, err := m.FindAVPsWithPath(path, 0); err == nil && len(erc) > 0 {
+		if v, ok := erc[0].Data.(datatype.Unsigned32); ok {
+			return uint32(v)
+		}
+	}
+	return 0
+}
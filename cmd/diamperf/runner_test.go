@@ -0,0 +1,163 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+	"github.com/omnicate/go-diameter/v4/diam/sm"
+)
+
+var (
+	serverSettings = &sm.Settings{
+		OriginHost:  "srv",
+		OriginRealm: "srv.test",
+		VendorID:    13,
+		ProductName: "go-diameter",
+	}
+
+	clientSettings = &sm.Settings{
+		OriginHost:  "cli",
+		OriginRealm: "cli.test",
+		VendorID:    13,
+		ProductName: "go-diameter",
+	}
+)
+
+// newServer starts a server that answers CCR-U with
+// DIAMETER_UNABLE_TO_COMPLY and other CCRs with DIAMETER_SUCCESS.
+func newServer() *diamtest.Server {
+	srvSM := sm.New(serverSettings)
+	srvSM.HandleFunc("CCR", func(c diam.Conn, m *diam.Message) {
+		code := uint32(diam.Success)
+		if typ, err := m.FindAVP(avp.CCRequestType, 0); err == nil && typ.Data == datatype.Enumerated(ccrUpdate) {
+			code = diam.UnableToComply
+		}
+		a := m.Answer(code)
+		a.NewAVP(avp.OriginHost, avp.Mbit, 0, serverSettings.OriginHost)
+		a.NewAVP(avp.OriginRealm, avp.Mbit, 0, serverSettings.OriginRealm)
+		a.WriteTo(c)
+	})
+	return diamtest.NewServer(srvSM, dict.Default)
+}
+
+func dial(t *testing.T, addr string) diam.Conn {
+	authApp := []*diam.AVP{diam.NewAVP(avp.AuthApplicationID, avp.Mbit, 0, datatype.Unsigned32(4))}
+	c, err := (&sm.Client{Handler: sm.New(clientSettings), AuthApplicationID: authApp}).Dial(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestRunnerCreditControl(t *testing.T) {
+	srv := newServer()
+	defer srv.Close()
+	c1, c2 := dial(t, srv.Addr), dial(t, srv.Addr)
+	defer c1.Close()
+	defer c2.Close()
+
+	r := newRunner([]diam.Conn{c1, c2}, creditControlScenario(2), clientSettings)
+	r.vars["destination_realm"] = "srv.test"
+	r.max = 40
+	if err := r.validate(); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	r.run(ctx, 4)
+	if n := r.stats.sent(); n != 40 {
+		t.Fatalf("Unexpected number of requests. Want 40, have %d", n)
+	}
+	var b bytes.Buffer
+	r.stats.report(&b)
+	out := b.String()
+	for _, want := range []string{
+		"CCR-I: Result-Code 2001: ",
+		"CCR-U: Result-Code 5012: ",
+		"CCR-T: Result-Code 2001: ",
+		"40 requests in ",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("Report is missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunnerRate(t *testing.T) {
+	srv := newServer()
+	defer srv.Close()
+	c := dial(t, srv.Addr)
+	defer c.Close()
+
+	r := newRunner([]diam.Conn{c}, creditControlScenario(0), clientSettings)
+	r.vars["destination_realm"] = "srv.test"
+	r.rate = 100
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	r.run(ctx, 2)
+	if n := r.stats.sent(); n < 10 || n > 21 {
+		t.Fatalf("Unexpected number of requests at 100/s in 200ms: %d", n)
+	}
+}
+
+func TestLoadScenario(t *testing.T) {
+	dir, err := ioutil.TempDir("", "diamperf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "scenario.json")
+	err = ioutil.WriteFile(name, []byte(`{"steps": [{"message": {
+		"header": {"flags": 192, "code": 275, "application_id": 1},
+		"avps": [{"name": "Session-Id", "value": "${session_id}"}, {"name": "User-Name", "value": "${user}"}]
+	}}]}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := loadScenario(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Steps[0].Name != "step-1" {
+		t.Fatalf("Unexpected step name %q", s.Steps[0].Name)
+	}
+	r := newRunner(nil, s, clientSettings)
+	if err = r.validate(); err == nil || !strings.Contains(err.Error(), `undefined variable "user"`) {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	r.vars["user"] = "alice"
+	if err = r.validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	var l []time.Duration
+	for i := 1; i <= 100; i++ {
+		l = append(l, time.Duration(i))
+	}
+	for p, want := range map[float64]time.Duration{50: 50, 90: 90, 99: 99, 100: 100} {
+		if have := percentile(l, p); have != want {
+			t.Fatalf("Unexpected percentile %v. Want %d, have %d", p, want, have)
+		}
+	}
+	if percentile(nil, 50) != 0 {
+		t.Fatal("Unexpected percentile of no latencies")
+	}
+}
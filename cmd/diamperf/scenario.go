@@ -0,0 +1,107 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+// ErrEmptyScenario is returned for scenarios without steps.
+var ErrEmptyScenario = errors.New("scenario has no steps")
+
+// step is a request of a scenario.
+type step struct {
+	Name    string          `json:"name"`
+	Message json.RawMessage `json:"message"`
+}
+
+// scenario is the sequence of requests of a session.
+type scenario struct {
+	Steps []*step `json:"steps"`
+}
+
+const (
+	ccrTemplate = `{
+	"header": {"flags": 192, "code": 272, "application_id": 4},
+	"avps": [
+		{"name": "Session-Id", "value": "${session_id}"},
+		{"name": "Destination-Realm", "value": "${destination_realm}"},
+		{"name": "Auth-Application-Id", "value": 4},
+		{"name": "Service-Context-Id", "value": "32251@3gpp.org"},
+		{"name": "CC-Request-Type", "value": %d},
+		{"name": "CC-Request-Number", "value": ${request_number}}
+	]
+}`
+	ccrInitial     = 1
+	ccrUpdate      = 2
+	ccrTermination = 3
+)
+
+// creditControlScenario returns the scenario of a credit control session
+// with a CCR-I, n CCR-U and a CCR-T.
+func creditControlScenario(n int) *scenario {
+	s := &scenario{}
+	add := func(name string, typ int) {
+		s.Steps = append(s.Steps, &step{name, json.RawMessage(fmt.Sprintf(ccrTemplate, typ))})
+	}
+	add("CCR-I", ccrInitial)
+	for i := 0; i < n; i++ {
+		add("CCR-U", ccrUpdate)
+	}
+	add("CCR-T", ccrTermination)
+	return s
+}
+
+// loadScenario loads a scenario from a JSON file, which holds the steps
+// with their name and message template:
+//
+//	{"steps": [{"name": "STR", "message": {"header": ..., "avps": ...}}]}
+func loadScenario(name string) (*scenario, error) {
+	b, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	s := &scenario{}
+	if err = json.Unmarshal(b, s); err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	if len(s.Steps) == 0 {
+		return nil, ErrEmptyScenario
+	}
+	for i, st := range s.Steps {
+		if st.Name == "" {
+			st.Name = "step-" + strconv.Itoa(i+1)
+		}
+	}
+	return s, nil
+}
+
+// message returns the request of the step with the variables vars.
+func (st *step) message(vars map[string]string, d *dict.Parser) (*diam.Message, error) {
+	var err error
+	b := os.Expand(string(st.Message), func(name string) string {
+		v, ok := vars[name]
+		if !ok && err == nil {
+			err = fmt.Errorf("%s: undefined variable %q", st.Name, name)
+		}
+		return v
+	})
+	if err != nil {
+		return nil, err
+	}
+	m := diam.NewMessage(0, 0, 0, 0, 0, d)
+	if err = json.Unmarshal([]byte(b), m); err != nil {
+		return nil, fmt.Errorf("%s: %v", st.Name, err)
+	}
+	return m, nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// stepStats are the statistics of the requests of a step.
+type stepStats struct {
+	sent      int
+	latencies []time.Duration // of answered requests
+	codes     map[uint32]int  // answers by Result-Code
+	errors    map[string]int  // failed requests by error
+}
+
+// stats are the statistics of a run.
+type stats struct {
+	mu    sync.Mutex
+	order []string
+	steps map[string]*stepStats
+	start time.Time
+	end   time.Time
+}
+
+func newStats() *stats {
+	return &stats{steps: make(map[string]*stepStats), start: time.Now()}
+}
+
+func (s *stats) step(name string) *stepStats {
+	st, ok := s.steps[name]
+	if !ok {
+		st = &stepStats{codes: make(map[uint32]int), errors: make(map[string]int)}
+		s.steps[name] = st
+		s.order = append(s.order, name)
+	}
+	return st
+}
+
+// record records a request of step that was answered after d with the
+// Result-Code code, or failed with err.
+func (s *stats) record(step string, d time.Duration, code uint32, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.step(step)
+	st.sent++
+	if err != nil {
+		st.errors[err.Error()]++
+		return
+	}
+	st.latencies = append(st.latencies, d)
+	st.codes[code]++
+}
+
+// sent returns the number of requests recorded.
+func (s *stats) sent() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, st := range s.steps {
+		n += st.sent
+	}
+	return n
+}
+
+// stop sets the end time of the run.
+func (s *stats) stop() {
+	s.mu.Lock()
+	s.end = time.Now()
+	s.mu.Unlock()
+}
+
+// percentile returns the p-th percentile of the sorted latencies l.
+func percentile(l []time.Duration, p float64) time.Duration {
+	if len(l) == 0 {
+		return 0
+	}
+	i := int(p/100*float64(len(l))+0.5) - 1
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(l) {
+		i = len(l) - 1
+	}
+	return l[i]
+}
+
+// report writes the latency percentiles, and the distribution of
+// Result-Codes and errors of each step to w.
+func (s *stats) report(w io.Writer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	end := s.end
+	if end.IsZero() {
+		end = time.Now()
+	}
+	elapsed := end.Sub(s.start)
+	total := 0
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "step\tsent\tanswered\tp50\tp90\tp99\tmax\t")
+	for _, name := range s.order {
+		st := s.steps[name]
+		total += st.sent
+		l := append([]time.Duration(nil), st.latencies...)
+		sort.Slice(l, func(i, j int) bool { return l[i] < l[j] })
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\t%s\t%s\t\n", name, st.sent, len(l),
+			percentile(l, 50), percentile(l, 90), percentile(l, 99), percentile(l, 100))
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "\n%d requests in %s, %.1f requests/s\n", total, elapsed.Round(time.Millisecond),
+		float64(total)/elapsed.Seconds())
+	for _, name := range s.order {
+		st := s.steps[name]
+		codes := make([]int, 0, len(st.codes))
+		for code := range st.codes {
+			codes = append(codes, int(code))
+		}
+		sort.Ints(codes)
+		for _, code := range codes {
+			fmt.Fprintf(w, "%s: Result-Code %d: %d\n", name, code, st.codes[uint32(code)])
+		}
+		errs := make([]string, 0, len(st.errors))
+		for err := range st.errors {
+			errs = append(errs, err)
+		}
+		sort.Strings(errs)
+		for _, err := range errs {
+			fmt.Fprintf(w, "%s: %s: %d\n", name, err, st.errors[err])
+		}
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package agent provides Diameter agents as specified by RFC 6733
+// section 2.8.
+//
+// A Relay forwards requests of any application to other peers based on
+// their Destination-Host and Destination-Realm AVPs, and routes the
+// answers back to the peers the requests were received from.
+//
+// A Proxy is a Relay that allows inspecting and modifying the forwarded
+// requests and answers with ProxyHandler functions. It adds Proxy-Info
+// to the requests it forwards, and validates it in their answers.
+//
+// The relay is registered as the catch-all handler of a state machine
+// that advertises the Relay application, which also provides the
+// connections to the peers the requests are forwarded to:
+//
+//	settings.Relay = true
+//	mux := sm.New(settings)
+//	relay := agent.NewRelay(settings.OriginHost, settings.OriginRealm, mux)
+//	relay.Routes.Add("example.com", sm.AnyApplication,
+//		sm.Route{Host: "server1.example.com"},
+//		sm.Route{Host: "server2.example.com", Priority: 1},
+//	)
+//	mux.Handle("ALL", relay)
+//
+// Connections to the servers are established by sm.Client, using the
+// same state machine.
+package agent
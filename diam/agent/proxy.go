@@ -0,0 +1,139 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package agent
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+var (
+	// ErrMissingProxyInfo is reported when the answer to a proxied
+	// request does not contain the Proxy-Info AVP added by the Proxy.
+	ErrMissingProxyInfo = errors.New("missing Proxy-Info in answer")
+)
+
+// ProxyHandler inspects and modifies a message forwarded by a Proxy.
+// The connection c is the one the message was received from.
+//
+// Returning an error stops forwarding the message, and an answer with
+// DIAMETER_UNABLE_TO_COMPLY is sent in its place.
+type ProxyHandler func(c diam.Conn, m *diam.Message) error
+
+// Proxy is a Relay that allows modifying the forwarded messages,
+// as specified by RFC 6733 section 2.8.2.
+//
+// A Proxy-Info AVP holding the Proxy's identity is added to forwarded
+// requests, after the request handlers are called. It's validated and
+// removed from answers before the answer handlers are called.
+type Proxy struct {
+	*Relay
+
+	mu          sync.RWMutex // guards handlers
+	reqHandlers []ProxyHandler
+	ansHandlers []ProxyHandler
+}
+
+// NewProxy creates and initializes a Proxy identified by host and realm,
+// which forwards requests to peers.
+func NewProxy(host, realm datatype.DiameterIdentity, peers Peers) *Proxy {
+	p := &Proxy{Relay: NewRelay(host, realm, peers)}
+	p.Relay.proxy = p
+	return p
+}
+
+// HandleRequest registers a handler called with every request before
+// it's forwarded. Handlers are called in the order they're registered.
+func (p *Proxy) HandleRequest(h ProxyHandler) {
+	p.mu.Lock()
+	p.reqHandlers = append(p.reqHandlers, h)
+	p.mu.Unlock()
+}
+
+// HandleAnswer registers a handler called with every answer before
+// it's sent back. Handlers are called in the order they're registered.
+func (p *Proxy) HandleAnswer(h ProxyHandler) {
+	p.mu.Lock()
+	p.ansHandlers = append(p.ansHandlers, h)
+	p.mu.Unlock()
+}
+
+// request prepares the request m received on c to be forwarded with the
+// Hop-by-Hop identifier id.
+func (p *Proxy) request(c diam.Conn, m *diam.Message, id uint32) error {
+	p.mu.RLock()
+	handlers := p.reqHandlers
+	p.mu.RUnlock()
+	for _, h := range handlers {
+		if err := h(c, m); err != nil {
+			return err
+		}
+	}
+	m.NewAVP(avp.ProxyInfo, avp.Mbit, 0, &diam.GroupedAVP{
+		AVP: []*diam.AVP{
+			diam.NewAVP(avp.ProxyHost, avp.Mbit, 0, p.OriginHost),
+			diam.NewAVP(avp.ProxyState, avp.Mbit, 0, proxyState(id)),
+		},
+	})
+	return nil
+}
+
+// answer prepares the answer m received on c, to the request forwarded
+// with the Hop-by-Hop identifier id, to be sent back.
+func (p *Proxy) answer(c diam.Conn, m *diam.Message, id uint32) error {
+	if !p.removeProxyInfo(m, proxyState(id)) {
+		return ErrMissingProxyInfo
+	}
+	p.mu.RLock()
+	handlers := p.ansHandlers
+	p.mu.RUnlock()
+	for _, h := range handlers {
+		if err := h(c, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeProxyInfo removes the Proxy-Info AVP added by the Proxy with the
+// given state from m, and reports whether it was found.
+func (p *Proxy) removeProxyInfo(m *diam.Message, state datatype.OctetString) bool {
+	for i, a := range m.AVP {
+		if a.Code != avp.ProxyInfo {
+			continue
+		}
+		g, ok := a.Data.(*diam.GroupedAVP)
+		if !ok {
+			continue
+		}
+		var host, st bool
+		for _, ga := range g.AVP {
+			switch ga.Code {
+			case avp.ProxyHost:
+				host = ga.Data == p.OriginHost
+			case avp.ProxyState:
+				st = ga.Data == state
+			}
+		}
+		if host && st {
+			m.AVP = append(m.AVP[:i], m.AVP[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// proxyState returns the Proxy-State of the request forwarded with the
+// Hop-by-Hop identifier id.
+func proxyState(id uint32) datatype.OctetString {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, id)
+	return datatype.OctetString(b)
+}
@@ -0,0 +1,104 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package agent
+
+import (
+	"testing"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+	"github.com/omnicate/go-diameter/v4/diam/sm"
+)
+
+func newProxy(t *testing.T, echo bool) (*diamtest.Server, *diamtest.Server, chan *diam.Message) {
+	srv, reqc := newServer(echo)
+	proxySrv := newAgent(t, srv.Addr, func(mux *sm.StateMachine) diam.Handler {
+		proxy := NewProxy(relaySettings.OriginHost, relaySettings.OriginRealm, mux)
+		proxy.Routes.Add(serverSettings.OriginRealm, 3, sm.Route{Host: serverSettings.OriginHost})
+		proxy.HandleRequest(func(c diam.Conn, m *diam.Message) error {
+			a, err := m.FindAVP(avp.OriginRealm, 0)
+			if err != nil {
+				return err
+			}
+			a.Data = relaySettings.OriginRealm
+			return nil
+		})
+		proxy.HandleAnswer(func(c diam.Conn, m *diam.Message) error {
+			m.NewAVP(avp.ErrorMessage, 0, 0, datatype.UTF8String("proxied"))
+			return nil
+		})
+		return proxy
+	})
+	return srv, proxySrv, reqc
+}
+
+func TestProxy_Rewrite(t *testing.T) {
+	srv, proxySrv, reqc := newProxy(t, true)
+	defer srv.Close()
+	defer proxySrv.Close()
+	c, ansc := dialRelay(t, proxySrv.Addr)
+	defer c.Close()
+
+	if _, err := newACR(serverSettings.OriginRealm).WriteTo(c); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case m := <-reqc:
+		or, err := m.FindAVP(avp.OriginRealm, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if or.Data != relaySettings.OriginRealm {
+			t.Fatalf("Unexpected Origin-Realm. Want %s, have %s", relaySettings.OriginRealm, or.Data)
+		}
+		ph, err := m.FindAVPsWithPath([]interface{}{avp.ProxyInfo, avp.ProxyHost}, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ph) != 1 || ph[0].Data != relaySettings.OriginHost {
+			t.Fatalf("Unexpected Proxy-Host: %v", ph)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for forwarded request")
+	}
+	select {
+	case a := <-ansc:
+		if _, err := a.FindAVP(avp.ProxyInfo, 0); err == nil {
+			t.Fatal("Proxy-Info was not removed from answer")
+		}
+		if _, err := a.FindAVP(avp.ErrorMessage, 0); err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for answer")
+	}
+}
+
+func TestProxy_MissingProxyInfo(t *testing.T) {
+	srv, proxySrv, _ := newProxy(t, false)
+	defer srv.Close()
+	defer proxySrv.Close()
+	c, ansc := dialRelay(t, proxySrv.Addr)
+	defer c.Close()
+
+	if _, err := newACR(serverSettings.OriginRealm).WriteTo(c); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case a := <-ansc:
+		rc, err := a.FindAVP(avp.ResultCode, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rc.Data != datatype.Unsigned32(diam.UnableToComply) {
+			t.Fatalf("Unexpected Result-Code. Want %d, have %v", diam.UnableToComply, rc.Data)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for answer")
+	}
+}
@@ -0,0 +1,260 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package agent
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/sm"
+	"github.com/omnicate/go-diameter/v4/diam/sm/smpeer"
+)
+
+// RelayApplicationID is the application identifier advertised in
+// capabilities exchange by relay agents.
+const RelayApplicationID = 0xffffffff
+
+// DefaultAnswerTimeout is the time the Relay waits for the answer of a
+// forwarded request when AnswerTimeout is not set.
+const DefaultAnswerTimeout = 30 * time.Second
+
+var (
+	// ErrUnknownAnswer is reported when an answer that does not belong
+	// to any forwarded request is received.
+	ErrUnknownAnswer = errors.New("answer to unknown request")
+)
+
+// Peers provides the connections to the peers known to the Relay.
+// It is implemented by sm.StateMachine.
+type Peers interface {
+	// PeerConn returns the connection to the given peer identity,
+	// or nil if the peer is not connected.
+	PeerConn(host datatype.DiameterIdentity) diam.Conn
+}
+
+// Relay is a diam.Handler that forwards requests to other peers,
+// as specified by RFC 6733 section 6.1.
+//
+// Requests are sent to the peer in their Destination-Host, if connected,
+// or else to the next hop selected by Routes from their Destination-Realm
+// and application. A Route-Record AVP with the identity of the peer
+// the request was received from is appended before forwarding it, and
+// its Hop-by-Hop identifier is replaced by a locally unique one, which is
+// used to route the answer back.
+//
+// Errors are reported to Peers, if it implements diam.ErrorReporter.
+type Relay struct {
+	OriginHost  datatype.DiameterIdentity
+	OriginRealm datatype.DiameterIdentity
+
+	// AnswerTimeout is the time a forwarded request waits for its answer,
+	// after which the answer is discarded. Defaults to DefaultAnswerTimeout.
+	AnswerTimeout time.Duration
+
+	// Routes selects the peer requests are forwarded to when their
+	// Destination-Host is not connected.
+	Routes *sm.RoutingTable
+
+	peers Peers
+	proxy *Proxy // set by NewProxy
+
+	mu      sync.Mutex // guards pending
+	pending map[uint32]*relayedRequest
+}
+
+// relayedRequest holds the state of a forwarded request.
+type relayedRequest struct {
+	conn  diam.Conn // where the request was received from
+	hbh   uint32    // original Hop-by-Hop identifier
+	timer *time.Timer
+}
+
+// NewRelay creates and initializes a Relay identified by host and realm,
+// which forwards requests to peers.
+func NewRelay(host, realm datatype.DiameterIdentity, peers Peers) *Relay {
+	return &Relay{
+		OriginHost:  host,
+		OriginRealm: realm,
+		Routes:      sm.NewRoutingTable(),
+		peers:       peers,
+		pending:     make(map[uint32]*relayedRequest),
+	}
+}
+
+// ServeDIAM implements the diam.Handler interface.
+func (r *Relay) ServeDIAM(c diam.Conn, m *diam.Message) {
+	if m.Header.ApplicationID == 0 {
+		switch m.Header.CommandCode {
+		case diam.CapabilitiesExchange, diam.DeviceWatchdog, diam.DisconnectPeer:
+			// Hop-by-hop messages are never relayed.
+			return
+		}
+	}
+	var err error
+	if m.Header.CommandFlags&diam.RequestFlag == diam.RequestFlag {
+		err = r.serveRequest(c, m)
+	} else {
+		err = r.serveAnswer(c, m)
+	}
+	if err != nil {
+		r.error(&diam.ErrorReport{Conn: c, Message: m, Error: err})
+	}
+}
+
+func (r *Relay) serveRequest(c diam.Conn, m *diam.Message) error {
+	if m.Header.CommandFlags&diam.ProxiableFlag == 0 {
+		return r.answerError(c, m, diam.CommandUnsupported)
+	}
+	for _, a := range m.AVP {
+		if a.Code == avp.RouteRecord && a.Data == r.OriginHost {
+			return r.answerError(c, m, diam.LoopDetected)
+		}
+	}
+	out, code := r.route(m)
+	if out == nil {
+		return r.answerError(c, m, code)
+	}
+	if meta, ok := smpeer.FromContext(c.Context()); ok {
+		m.NewAVP(avp.RouteRecord, avp.Mbit, 0, meta.OriginHost)
+	}
+	hbh := m.Header.HopByHopID
+	id := r.track(c, hbh)
+	if r.proxy != nil {
+		if err := r.proxy.request(c, m, id); err != nil {
+			r.untrack(id)
+			r.answerError(c, m, diam.UnableToComply)
+			return err
+		}
+	}
+	m.Header.HopByHopID = id
+	m.Header.MessageLength = uint32(m.Len())
+	if _, err := m.WriteTo(out); err != nil {
+		r.untrack(id)
+		m.Header.HopByHopID = hbh
+		return r.answerError(c, m, diam.UnableToDeliver)
+	}
+	return nil
+}
+
+func (r *Relay) serveAnswer(c diam.Conn, m *diam.Message) error {
+	id := m.Header.HopByHopID
+	req, ok := r.untrack(id)
+	if !ok {
+		return ErrUnknownAnswer
+	}
+	m.Header.HopByHopID = req.hbh
+	if r.proxy != nil {
+		if err := r.proxy.answer(c, m, id); err != nil {
+			r.answerError(req.conn, m, diam.UnableToComply)
+			return err
+		}
+	}
+	m.Header.MessageLength = uint32(m.Len())
+	_, err := m.WriteTo(req.conn)
+	return err
+}
+
+// route returns the connection the request m must be forwarded to, or
+// the Result-Code of the error answer if there is none.
+func (r *Relay) route(m *diam.Message) (diam.Conn, uint32) {
+	return Route(m, r.peers, r.Routes)
+}
+
+// Route returns the connection to the peer in the Destination-Host of
+// the request m, if connected, or else to the next hop selected by
+// routes from its Destination-Realm and application, among the peers
+// that support the application. If there is none, it returns the
+// Result-Code of the error answer to m.
+func Route(m *diam.Message, peers Peers, routes *sm.RoutingTable) (diam.Conn, uint32) {
+	host, realm := sm.Destination(m)
+	if len(host) > 0 {
+		if c := peers.PeerConn(host); c != nil {
+			return c, 0
+		}
+	}
+	if len(realm) == 0 {
+		return nil, diam.MissingAVP
+	}
+	host, err := routes.NextHop(realm, m.Header.ApplicationID, func(h datatype.DiameterIdentity) bool {
+		c := peers.PeerConn(h)
+		if c == nil {
+			return false
+		}
+		meta, ok := smpeer.FromContext(c.Context())
+		return !ok || meta.Supports(m.Header.ApplicationID)
+	})
+	switch err {
+	case nil:
+		if c := peers.PeerConn(host); c != nil {
+			return c, 0
+		}
+	case sm.ErrNoRoute:
+		return nil, diam.RealmNotServed
+	}
+	return nil, diam.UnableToDeliver
+}
+
+// track saves the state of a request received on c with the Hop-by-Hop
+// identifier hbh, and returns the identifier to forward it with.
+func (r *Relay) track(c diam.Conn, hbh uint32) uint32 {
+	timeout := r.AnswerTimeout
+	if timeout == 0 {
+		timeout = DefaultAnswerTimeout
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id := rand.Uint32()
+	for _, exists := r.pending[id]; exists; _, exists = r.pending[id] {
+		id = rand.Uint32()
+	}
+	r.pending[id] = &relayedRequest{
+		conn:  c,
+		hbh:   hbh,
+		timer: time.AfterFunc(timeout, func() { r.untrack(id) }),
+	}
+	return id
+}
+
+// untrack removes and returns the state of the request forwarded with
+// the Hop-by-Hop identifier id.
+func (r *Relay) untrack(id uint32) (*relayedRequest, bool) {
+	r.mu.Lock()
+	req, ok := r.pending[id]
+	delete(r.pending, id)
+	r.mu.Unlock()
+	if ok {
+		req.timer.Stop()
+	}
+	return req, ok
+}
+
+// answerError sends an answer with the E bit and the given Result-Code
+// in place of m, which is either the request or its invalid answer.
+func (r *Relay) answerError(c diam.Conn, m *diam.Message, code uint32) error {
+	a := m.Answer(0)
+	a.Header.CommandFlags |= diam.ErrorFlag
+	for _, sid := range m.AVP {
+		if sid.Code == avp.SessionID {
+			a.AddAVP(sid)
+			break
+		}
+	}
+	a.NewAVP(avp.ResultCode, avp.Mbit, 0, datatype.Unsigned32(code))
+	a.NewAVP(avp.OriginHost, avp.Mbit, 0, r.OriginHost)
+	a.NewAVP(avp.OriginRealm, avp.Mbit, 0, r.OriginRealm)
+	_, err := a.WriteTo(c)
+	return err
+}
+
+func (r *Relay) error(err *diam.ErrorReport) {
+	if er, ok := r.peers.(diam.ErrorReporter); ok {
+		er.Error(err)
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package agent
+
+import (
+	"testing"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+	"github.com/omnicate/go-diameter/v4/diam/sm"
+)
+
+var (
+	serverSettings = &sm.Settings{
+		OriginHost:  "srv",
+		OriginRealm: "srv.test",
+		VendorID:    13,
+		ProductName: "go-diameter",
+	}
+
+	relaySettings = &sm.Settings{
+		OriginHost:  "relay",
+		OriginRealm: "relay.test",
+		VendorID:    13,
+		ProductName: "go-diameter",
+		Relay:       true,
+	}
+
+	clientSettings = &sm.Settings{
+		OriginHost:  "cli",
+		OriginRealm: "cli.test",
+		VendorID:    13,
+		ProductName: "go-diameter",
+	}
+
+	acctApp = []*diam.AVP{
+		diam.NewAVP(avp.AcctApplicationID, avp.Mbit, 0, datatype.Unsigned32(3)),
+	}
+	relayApp = []*diam.AVP{
+		diam.NewAVP(avp.AuthApplicationID, avp.Mbit, 0, datatype.Unsigned32(RelayApplicationID)),
+	}
+)
+
+func newACR(realm datatype.DiameterIdentity) *diam.Message {
+	m := diam.NewMessage(diam.Accounting, diam.RequestFlag|diam.ProxiableFlag, 3, 0, 0, dict.Default)
+	m.NewAVP(avp.SessionID, avp.Mbit, 0, datatype.UTF8String("sess"))
+	m.NewAVP(avp.OriginHost, avp.Mbit, 0, clientSettings.OriginHost)
+	m.NewAVP(avp.OriginRealm, avp.Mbit, 0, clientSettings.OriginRealm)
+	m.NewAVP(avp.DestinationRealm, avp.Mbit, 0, realm)
+	m.NewAVP(avp.AccountingRecordType, avp.Mbit, 0, datatype.Enumerated(1))
+	m.NewAVP(avp.AccountingRecordNumber, avp.Mbit, 0, datatype.Unsigned32(0))
+	return m
+}
+
+// newServer starts a server that answers ACRs, including their Proxy-Info
+// AVPs if echo is set, and returns the requests it receives.
+func newServer(echo bool) (*diamtest.Server, chan *diam.Message) {
+	reqc := make(chan *diam.Message, 1)
+	srvSM := sm.New(serverSettings)
+	srvSM.HandleFunc("ACR", func(c diam.Conn, m *diam.Message) {
+		reqc <- m
+		a := m.Answer(diam.Success)
+		a.NewAVP(avp.OriginHost, avp.Mbit, 0, serverSettings.OriginHost)
+		a.NewAVP(avp.OriginRealm, avp.Mbit, 0, serverSettings.OriginRealm)
+		if echo {
+			pi, _ := m.FindAVPs(avp.ProxyInfo, 0)
+			for _, a2 := range pi {
+				a.AddAVP(a2)
+			}
+		}
+		a.WriteTo(c)
+	})
+	return diamtest.NewServer(srvSM, dict.Default), reqc
+}
+
+// newAgent starts an agent connected to the server at addr, with the
+// handler returned by h.
+func newAgent(t *testing.T, addr string, h func(*sm.StateMachine) diam.Handler) *diamtest.Server {
+	agentSM := sm.New(relaySettings)
+	agentSM.Handle("ALL", h(agentSM))
+	srv := diamtest.NewServer(agentSM, dict.Default)
+	cli := &sm.Client{Handler: agentSM, AuthApplicationID: relayApp}
+	if _, err := cli.Dial(addr); err != nil {
+		srv.Close()
+		t.Fatal(err)
+	}
+	return srv
+}
+
+// newRelay starts a relay connected to a server that answers ACRs,
+// and returns the requests received by the server.
+func newRelay(t *testing.T) (*diamtest.Server, *diamtest.Server, chan *diam.Message) {
+	srv, reqc := newServer(false)
+	relaySrv := newAgent(t, srv.Addr, func(mux *sm.StateMachine) diam.Handler {
+		relay := NewRelay(relaySettings.OriginHost, relaySettings.OriginRealm, mux)
+		relay.Routes.Add(serverSettings.OriginRealm, sm.AnyApplication,
+			sm.Route{Host: "unknown"},
+			sm.Route{Host: serverSettings.OriginHost, Priority: 1},
+		)
+		return relay
+	})
+	return srv, relaySrv, reqc
+}
+
+func dialRelay(t *testing.T, addr string) (diam.Conn, chan *diam.Message) {
+	ansc := make(chan *diam.Message, 1)
+	cliSM := sm.New(clientSettings)
+	cliSM.HandleFunc("ACA", func(c diam.Conn, m *diam.Message) {
+		ansc <- m
+	})
+	c, err := (&sm.Client{Handler: cliSM, AcctApplicationID: acctApp}).Dial(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, ansc
+}
+
+func TestRelay_Forward(t *testing.T) {
+	srv, relaySrv, reqc := newRelay(t)
+	defer srv.Close()
+	defer relaySrv.Close()
+	c, ansc := dialRelay(t, relaySrv.Addr)
+	defer c.Close()
+
+	req := newACR(serverSettings.OriginRealm)
+	if _, err := req.WriteTo(c); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case m := <-reqc:
+		rr, err := m.FindAVP(avp.RouteRecord, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rr.Data != clientSettings.OriginHost {
+			t.Fatalf("Unexpected Route-Record. Want %s, have %s", clientSettings.OriginHost, rr.Data)
+		}
+		if m.Header.EndToEndID != req.Header.EndToEndID {
+			t.Fatal("End-to-End identifier was not preserved")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for forwarded request")
+	}
+	select {
+	case a := <-ansc:
+		if a.Header.HopByHopID != req.Header.HopByHopID {
+			t.Fatalf("Unexpected Hop-by-Hop identifier. Want %d, have %d",
+				req.Header.HopByHopID, a.Header.HopByHopID)
+		}
+		oh, err := a.FindAVP(avp.OriginHost, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if oh.Data != serverSettings.OriginHost {
+			t.Fatalf("Unexpected Origin-Host. Want %s, have %s", serverSettings.OriginHost, oh.Data)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for answer")
+	}
+}
+
+func TestRelay_RealmNotServed(t *testing.T) {
+	srv, relaySrv, _ := newRelay(t)
+	defer srv.Close()
+	defer relaySrv.Close()
+	c, ansc := dialRelay(t, relaySrv.Addr)
+	defer c.Close()
+
+	if _, err := newACR("unknown.test").WriteTo(c); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case a := <-ansc:
+		if a.Header.CommandFlags&diam.ErrorFlag == 0 {
+			t.Fatal("Missing E bit in error answer")
+		}
+		rc, err := a.FindAVP(avp.ResultCode, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rc.Data != datatype.Unsigned32(diam.RealmNotServed) {
+			t.Fatalf("Unexpected Result-Code. Want %d, have %v", diam.RealmNotServed, rc.Data)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for answer")
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+import (
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// AnswerFrom creates the answer to the request m with the Result-Code
+// resultCode, sent by the node originHost of originRealm. The AVPs that
+// RFC 6733 requires are copied from the request: Session-Id as the first
+// AVP (section 8.8), the Proxy-Info AVPs in the same order (section 6.2),
+// and Route-Record when the answer of the command has it in the
+// dictionary.
+//
+// The E flag is set for protocol errors (3xxx) as required by section
+// 7.1.3, and the T flag is cleared. See Validate to check the answer
+// against the command of the dictionary before sending it.
+func (m *Message) AnswerFrom(resultCode uint32, originHost, originRealm datatype.DiameterIdentity) *Message {
+	nm := m.answer()
+	nm.Header.CommandFlags &^= RetransmittedFlag | ErrorFlag
+	if resultCode >= 3000 && resultCode < 4000 {
+		nm.Header.CommandFlags |= ErrorFlag
+	}
+	if sid, err := m.FindAVP(avp.SessionID, 0); err == nil && sid != nil {
+		nm.AddAVP(sid)
+	}
+	nm.NewAVP(avp.ResultCode, avp.Mbit, 0, datatype.Unsigned32(resultCode))
+	nm.NewAVP(avp.OriginHost, avp.Mbit, 0, originHost)
+	nm.NewAVP(avp.OriginRealm, avp.Mbit, 0, originRealm)
+	routeRecord := false
+	if cmd, err := m.Dictionary().FindCommand(m.Header.ApplicationID, m.Header.CommandCode); err == nil {
+		for _, r := range cmd.Answer.Rule {
+			if r.AVP == "Route-Record" {
+				routeRecord = true
+				break
+			}
+		}
+	}
+	for _, a := range m.AVP {
+		if a.Code == avp.ProxyInfo || (routeRecord && a.Code == avp.RouteRecord) {
+			nm.AddAVP(a)
+		}
+	}
+	return nm
+}
@@ -0,0 +1,75 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+import (
+	"testing"
+
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+func newTestRAR() *Message {
+	m := NewRequest(ReAuth, 0, dict.Default)
+	m.Header.CommandFlags |= RetransmittedFlag
+	m.NewAVP(avp.SessionID, avp.Mbit, 0, datatype.UTF8String("sess"))
+	m.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("cli"))
+	m.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity("test"))
+	m.NewAVP(avp.DestinationRealm, avp.Mbit, 0, datatype.DiameterIdentity("test"))
+	m.NewAVP(avp.DestinationHost, avp.Mbit, 0, datatype.DiameterIdentity("srv"))
+	m.NewAVP(avp.AuthApplicationID, avp.Mbit, 0, datatype.Unsigned32(4))
+	m.NewAVP(avp.ReAuthRequestType, avp.Mbit, 0, datatype.Enumerated(0))
+	for _, host := range []string{"proxy1", "proxy2"} {
+		m.NewAVP(avp.ProxyInfo, avp.Mbit, 0, &GroupedAVP{
+			AVP: []*AVP{
+				NewAVP(avp.ProxyHost, avp.Mbit, 0, datatype.DiameterIdentity(host)),
+				NewAVP(avp.ProxyState, avp.Mbit, 0, datatype.OctetString("state")),
+			},
+		})
+	}
+	m.NewAVP(avp.RouteRecord, avp.Mbit, 0, datatype.DiameterIdentity("proxy1"))
+	return m
+}
+
+func TestMessage_AnswerFrom(t *testing.T) {
+	req := newTestRAR()
+	if err := req.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	a := req.AnswerFrom(Success, "srv", "test")
+	if a.Header.CommandFlags&(RequestFlag|RetransmittedFlag|ErrorFlag) != 0 {
+		t.Fatalf("Unexpected flags: %#x", a.Header.CommandFlags)
+	}
+	if a.Header.HopByHopID != req.Header.HopByHopID || a.Header.EndToEndID != req.Header.EndToEndID {
+		t.Fatal("Unexpected identifiers")
+	}
+	if a.AVP[0].Code != avp.SessionID {
+		t.Fatalf("Session-Id is not the first AVP: %s", a.AVP[0])
+	}
+	var proxies []*AVP
+	for _, v := range a.AVP {
+		switch v.Code {
+		case avp.ProxyInfo:
+			proxies = append(proxies, v)
+		case avp.RouteRecord:
+			t.Fatal("Route-Record copied to RAA")
+		}
+	}
+	if len(proxies) != 2 || proxies[0] != req.AVP[7] || proxies[1] != req.AVP[8] {
+		t.Fatalf("Proxy-Info not copied in order: %v", proxies)
+	}
+	if err := a.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	e := req.AnswerFrom(UnableToDeliver, "srv", "test")
+	if e.Header.CommandFlags&ErrorFlag == 0 {
+		t.Fatal("E flag not set for protocol error")
+	}
+	if e = req.AnswerFrom(UnknownSessionID, "srv", "test"); e.Header.CommandFlags&ErrorFlag != 0 {
+		t.Fatal("E flag set for permanent failure")
+	}
+}
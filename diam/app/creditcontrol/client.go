@@ -0,0 +1,259 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package creditcontrol
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/session"
+)
+
+// Client is a Diameter Credit-Control client.
+type Client struct {
+	OriginHost       datatype.DiameterIdentity
+	OriginRealm      datatype.DiameterIdentity
+	DestinationRealm datatype.DiameterIdentity
+
+	// ServiceContextID is sent in the Service-Context-Id AVP of
+	// requests when set, e.g. "32251@3gpp.org" for PS charging.
+	ServiceContextID string
+
+	// Peers are the connections to the OCS peers, in order of
+	// preference. They must implement diam.RequestSender, which is
+	// the case of connections created by diam.Dial or sm.Client.
+	Peers []diam.Conn
+
+	// Tx is the time to wait for answers. Defaults to DefaultTx.
+	Tx time.Duration
+
+	// FailureHandling and SessionFailover are the CCFH and
+	// CC-Session-Failover of new sessions. Answers may override them
+	// for the rest of their session.
+	FailureHandling FailureHandling
+	SessionFailover bool
+
+	ids *session.IDGenerator
+}
+
+// NewClient creates and initializes a Client for the given origin,
+// sending requests to the given peers.
+func NewClient(host, realm, destRealm datatype.DiameterIdentity, peers ...diam.Conn) *Client {
+	return &Client{
+		OriginHost:       host,
+		OriginRealm:      realm,
+		DestinationRealm: destRealm,
+		Peers:            peers,
+		Tx:               DefaultTx,
+		ids:              session.NewIDGenerator(host),
+	}
+}
+
+// NewSession creates a new credit-control session with a generated
+// Session-Id.
+func (cli *Client) NewSession() *Session {
+	if cli.ids == nil {
+		cli.ids = session.NewIDGenerator(cli.OriginHost)
+	}
+	return &Session{
+		ID:              cli.ids.Next(),
+		cli:             cli,
+		failureHandling: cli.FailureHandling,
+		sessionFailover: cli.SessionFailover,
+	}
+}
+
+// Session is a credit-control session.
+type Session struct {
+	ID datatype.UTF8String
+
+	cli             *Client
+	mu              sync.Mutex // guards the following
+	number          uint32
+	peer            int
+	failureHandling FailureHandling
+	sessionFailover bool
+}
+
+// NewCCR returns a Credit-Control-Request of the given type with the
+// next CC-Request-Number of the session and the given MSCC AVPs. Other
+// AVPs, e.g. Subscription-Id, can be added before sending it.
+func (s *Session) NewCCR(typ RequestType, mscc ...*MSCC) *diam.Message {
+	s.mu.Lock()
+	var number uint32
+	if typ != InitialRequest && typ != EventRequest {
+		s.number++
+		number = s.number
+	}
+	s.mu.Unlock()
+	m := diam.NewRequest(diam.CreditControl, ApplicationID, nil)
+	m.NewAVP(avp.SessionID, avp.Mbit, 0, s.ID)
+	m.NewAVP(avp.OriginHost, avp.Mbit, 0, s.cli.OriginHost)
+	m.NewAVP(avp.OriginRealm, avp.Mbit, 0, s.cli.OriginRealm)
+	m.NewAVP(avp.DestinationRealm, avp.Mbit, 0, s.cli.DestinationRealm)
+	m.NewAVP(avp.AuthApplicationID, avp.Mbit, 0, datatype.Unsigned32(ApplicationID))
+	if s.cli.ServiceContextID != "" {
+		m.NewAVP(avp.ServiceContextID, avp.Mbit, 0, datatype.UTF8String(s.cli.ServiceContextID))
+	}
+	m.NewAVP(avp.CCRequestType, avp.Mbit, 0, datatype.Enumerated(typ))
+	m.NewAVP(avp.CCRequestNumber, avp.Mbit, 0, datatype.Unsigned32(number))
+	if len(mscc) > 0 {
+		m.NewAVP(avp.MultipleServicesIndicator, avp.Mbit, 0, datatype.Enumerated(1))
+	}
+	for _, v := range mscc {
+		m.AddAVP(v.AVP())
+	}
+	return m
+}
+
+// Init sends a CCR-Initial and returns its answer.
+func (s *Session) Init(ctx context.Context, mscc ...*MSCC) (*Answer, error) {
+	return s.Send(ctx, s.NewCCR(InitialRequest, mscc...))
+}
+
+// Update sends a CCR-Update and returns its answer.
+func (s *Session) Update(ctx context.Context, mscc ...*MSCC) (*Answer, error) {
+	return s.Send(ctx, s.NewCCR(UpdateRequest, mscc...))
+}
+
+// Terminate sends a CCR-Terminate with Termination-Cause
+// DIAMETER_LOGOUT and returns its answer.
+func (s *Session) Terminate(ctx context.Context, mscc ...*MSCC) (*Answer, error) {
+	m := s.NewCCR(TerminationRequest, mscc...)
+	m.NewAVP(avp.TerminationCause, avp.Mbit, 0, datatype.Enumerated(1))
+	return s.Send(ctx, m)
+}
+
+// Send sends the request m to the current peer of the session and
+// waits for its answer for up to Tx.
+//
+// When Tx expires, the connection fails, or the peer answers with
+// DIAMETER_UNABLE_TO_DELIVER or DIAMETER_TOO_BUSY, the request is
+// retransmitted to the next peer if CC-Session-Failover is supported.
+// The session then stays with that peer. As required by RFC 4006
+// section 5.7, the request is not retransmitted when Tx expires and
+// the CCFH of the session is TERMINATE. If no peer answers, Send
+// returns a *Failure with the CCFH of the session.
+func (s *Session) Send(ctx context.Context, m *diam.Message) (*Answer, error) {
+	peers := s.cli.Peers
+	if len(peers) == 0 {
+		return nil, ErrNoPeers
+	}
+	tx := s.cli.Tx
+	if tx <= 0 {
+		tx = DefaultTx
+	}
+	s.mu.Lock()
+	peer := s.peer
+	s.mu.Unlock()
+	for tries := 0; ; tries++ {
+		a, err := s.send(ctx, peers[peer%len(peers)], m, tx)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err == nil && a.ResultCode != diam.UnableToDeliver && a.ResultCode != diam.TooBusy {
+			s.update(peer, a)
+			return a, nil
+		}
+		s.mu.Lock()
+		handling, failover := s.failureHandling, s.sessionFailover
+		s.mu.Unlock()
+		if !failover || tries+1 >= len(peers) || err == ErrTxExpired && handling == Terminate {
+			if err == nil {
+				// The last peer answered with a failure.
+				s.update(peer, a)
+				return a, nil
+			}
+			return nil, &Failure{Handling: handling, Err: err}
+		}
+		peer++
+		m.Header.CommandFlags |= diam.RetransmittedFlag
+	}
+}
+
+func (s *Session) send(ctx context.Context, c diam.Conn, m *diam.Message, tx time.Duration) (*Answer, error) {
+	ctx, cancel := context.WithTimeout(ctx, tx)
+	defer cancel()
+	a, err := diam.SendRequest(ctx, c, m)
+	if err == context.DeadlineExceeded {
+		return nil, ErrTxExpired
+	} else if err != nil {
+		return nil, err
+	}
+	return ParseAnswer(a)
+}
+
+// update sticks the session to the peer that answered, and applies
+// the CCFH and CC-Session-Failover of the answer.
+func (s *Session) update(peer int, a *Answer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.peer = peer
+	if a.FailureHandling != nil {
+		s.failureHandling = *a.FailureHandling
+	}
+	if a.SessionFailover != nil {
+		s.sessionFailover = *a.SessionFailover
+	}
+}
+
+// Answer is a Credit-Control-Answer.
+type Answer struct {
+	*diam.Message
+
+	ResultCode    uint32
+	RequestType   RequestType
+	RequestNumber uint32
+	MSCC          []*MSCC
+	ValidityTime  time.Duration
+
+	// FailureHandling and SessionFailover are set when the answer
+	// carries the CCFH and CC-Session-Failover AVPs.
+	FailureHandling *FailureHandling
+	SessionFailover *bool
+}
+
+// answer is used to unmarshal Credit-Control-Answers.
+type answer struct {
+	ResultCode      uint32  `avp:"Result-Code"`
+	RequestType     int32   `avp:"CC-Request-Type"`
+	RequestNumber   uint32  `avp:"CC-Request-Number"`
+	MSCC            []*mscc `avp:"Multiple-Services-Credit-Control"`
+	ValidityTime    uint32  `avp:"Validity-Time"`
+	FailureHandling *int32  `avp:"Credit-Control-Failure-Handling"`
+	SessionFailover *int32  `avp:"CC-Session-Failover"`
+}
+
+// ParseAnswer parses the Credit-Control-Answer m.
+func ParseAnswer(m *diam.Message) (*Answer, error) {
+	var v answer
+	if err := m.Unmarshal(&v); err != nil {
+		return nil, err
+	}
+	a := &Answer{
+		Message:       m,
+		ResultCode:    v.ResultCode,
+		RequestType:   RequestType(v.RequestType),
+		RequestNumber: v.RequestNumber,
+		ValidityTime:  time.Duration(v.ValidityTime) * time.Second,
+	}
+	for _, mscc := range v.MSCC {
+		a.MSCC = append(a.MSCC, mscc.mscc())
+	}
+	if v.FailureHandling != nil {
+		h := FailureHandling(*v.FailureHandling)
+		a.FailureHandling = &h
+	}
+	if v.SessionFailover != nil {
+		failover := *v.SessionFailover == 1 // FAILOVER_SUPPORTED
+		a.SessionFailover = &failover
+	}
+	return a, nil
+}
@@ -0,0 +1,184 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package creditcontrol
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+)
+
+// newOCS returns a server answering CCRs with 60s granted for each
+// MSCC, or a server that never answers if silent is true.
+func newOCS(silent bool, retransmitted chan bool) *diamtest.Server {
+	mux := diam.NewServeMux()
+	mux.HandleFunc("CCR", func(c diam.Conn, m *diam.Message) {
+		if retransmitted != nil {
+			retransmitted <- m.Header.CommandFlags&diam.RetransmittedFlag != 0
+		}
+		if silent {
+			return
+		}
+		a := m.Answer(diam.Success)
+		for _, code := range []uint32{avp.SessionID, avp.CCRequestType, avp.CCRequestNumber} {
+			v, _ := m.FindAVP(code, 0)
+			a.AddAVP(v)
+		}
+		a.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("ocs"))
+		a.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity("localhost"))
+		a.NewAVP(avp.CreditControlFailureHandling, avp.Mbit, 0, datatype.Enumerated(Continue))
+		ms, _ := m.FindAVPs(avp.MultipleServicesCreditControl, 0)
+		for _, v := range ms {
+			rg, _ := v.Data.(*diam.GroupedAVP).AVP[1].Data.(datatype.Unsigned32)
+			a.NewAVP(avp.MultipleServicesCreditControl, avp.Mbit, 0, &diam.GroupedAVP{
+				AVP: []*diam.AVP{
+					(&ServiceUnit{Time: 60}).AVP(avp.GrantedServiceUnit),
+					diam.NewAVP(avp.RatingGroup, avp.Mbit, 0, rg),
+					diam.NewAVP(avp.ValidityTime, avp.Mbit, 0, datatype.Unsigned32(30)),
+				},
+			})
+		}
+		a.WriteTo(c)
+	})
+	return diamtest.NewServer(mux, nil)
+}
+
+func dial(t *testing.T, srv *diamtest.Server) diam.Conn {
+	c, err := diam.Dial(srv.Addr, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestSession_Init(t *testing.T) {
+	srv := newOCS(false, nil)
+	defer srv.Close()
+	c := dial(t, srv)
+	defer c.Close()
+
+	cli := NewClient("cli", "localhost", "localhost", c)
+	s := cli.NewSession()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	a, err := s.Init(ctx, NewMSCC(10).Request(&ServiceUnit{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.ResultCode != diam.Success || a.RequestType != InitialRequest {
+		t.Fatalf("Unexpected answer: %+v", a)
+	}
+	if len(a.MSCC) != 1 {
+		t.Fatalf("Unexpected number of MSCC. Want 1, have %d", len(a.MSCC))
+	}
+	mscc := a.MSCC[0]
+	if mscc.RatingGroup == nil || *mscc.RatingGroup != 10 {
+		t.Fatalf("Unexpected Rating-Group: %v", mscc.RatingGroup)
+	}
+	if mscc.Granted == nil || mscc.Granted.Time != 60 || mscc.ValidityTime != 30*time.Second {
+		t.Fatalf("Unexpected MSCC: %+v", mscc)
+	}
+	if a.FailureHandling == nil || *a.FailureHandling != Continue {
+		t.Fatalf("Unexpected CCFH: %v", a.FailureHandling)
+	}
+	a, err = s.Update(ctx, NewMSCC(10).Report(&ServiceUnit{Time: 60}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.RequestType != UpdateRequest || a.RequestNumber != 1 {
+		t.Fatalf("Unexpected answer: %+v", a)
+	}
+}
+
+func TestSession_Failover(t *testing.T) {
+	retransmitted := make(chan bool, 2)
+	srv1 := newOCS(true, retransmitted)
+	defer srv1.Close()
+	srv2 := newOCS(false, retransmitted)
+	defer srv2.Close()
+	c1, c2 := dial(t, srv1), dial(t, srv2)
+	defer c1.Close()
+	defer c2.Close()
+
+	cli := NewClient("cli", "localhost", "localhost", c1, c2)
+	cli.Tx = 50 * time.Millisecond
+	cli.FailureHandling = RetryAndTerminate
+	cli.SessionFailover = true
+	s := cli.NewSession()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := s.Init(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if <-retransmitted || !<-retransmitted {
+		t.Fatal("Failed over request was not marked as retransmitted")
+	}
+	// The session sticks to the peer that answered.
+	if _, err := s.Update(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if <-retransmitted {
+		t.Fatal("Update was sent to the failed peer")
+	}
+}
+
+func TestSession_FailoverTerminate(t *testing.T) {
+	retransmitted := make(chan bool, 2)
+	srv1 := newOCS(true, retransmitted)
+	defer srv1.Close()
+	srv2 := newOCS(false, retransmitted)
+	defer srv2.Close()
+	c1, c2 := dial(t, srv1), dial(t, srv2)
+	defer c1.Close()
+	defer c2.Close()
+
+	cli := NewClient("cli", "localhost", "localhost", c1, c2)
+	cli.Tx = 50 * time.Millisecond
+	cli.FailureHandling = Terminate
+	cli.SessionFailover = true
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err := cli.NewSession().Init(ctx)
+	f, ok := err.(*Failure)
+	if !ok {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if f.Err != ErrTxExpired || f.Handling != Terminate {
+		t.Fatalf("Unexpected failure: %v", f)
+	}
+	<-retransmitted
+	select {
+	case <-retransmitted:
+		t.Fatal("Request was failed over with CCFH TERMINATE")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestSession_Failure(t *testing.T) {
+	srv := newOCS(true, nil)
+	defer srv.Close()
+	c := dial(t, srv)
+	defer c.Close()
+
+	cli := NewClient("cli", "localhost", "localhost", c)
+	cli.Tx = 10 * time.Millisecond
+	cli.FailureHandling = RetryAndTerminate
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err := cli.NewSession().Init(ctx)
+	f, ok := err.(*Failure)
+	if !ok {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if f.Err != ErrTxExpired || f.Handling != RetryAndTerminate || f.Continue() {
+		t.Fatalf("Unexpected failure: %v", f)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package creditcontrol
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+// ApplicationID is the Diameter Credit-Control Application ID.
+const ApplicationID = 4
+
+// tgppVendorID is the Vendor-Id of the 3GPP AVPs of Gy and Ro.
+const tgppVendorID = 10415
+
+// DefaultTx is the default value of the Tx timer, as recommended by
+// RFC 4006 section 13.
+const DefaultTx = 10 * time.Second
+
+// Credit-Control codes for the Result-Code AVP, see RFC 4006 section 9.1.
+const (
+	EndUserServiceDenied       = 4010
+	CreditControlNotApplicable = 4011
+	CreditLimitReached         = 4012
+	UserUnknown                = 5030
+	RatingFailed               = 5031
+)
+
+// RequestType is the value of the CC-Request-Type AVP.
+type RequestType int32
+
+// Credit-Control request types.
+const (
+	InitialRequest     RequestType = 1
+	UpdateRequest      RequestType = 2
+	TerminationRequest RequestType = 3
+	EventRequest       RequestType = 4
+)
+
+var requestTypes = [...]string{"", "INITIAL", "UPDATE", "TERMINATION", "EVENT"}
+
+func (t RequestType) String() string {
+	if t > 0 && int(t) < len(requestTypes) {
+		return requestTypes[t]
+	}
+	return "RequestType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// FailureHandling is the value of the Credit-Control-Failure-Handling
+// (CCFH) AVP. It tells the client what to do when the OCS does not
+// answer, see RFC 4006 section 5.7.
+type FailureHandling int32
+
+// Credit-Control failure handling actions.
+const (
+	// Terminate the service.
+	Terminate FailureHandling = 0
+	// Continue grants the service without credit control.
+	Continue FailureHandling = 1
+	// RetryAndTerminate retries the request with an alternate peer,
+	// and terminates the service if that fails too.
+	RetryAndTerminate FailureHandling = 2
+)
+
+var failureHandlings = [...]string{"TERMINATE", "CONTINUE", "RETRY_AND_TERMINATE"}
+
+func (h FailureHandling) String() string {
+	if h >= 0 && int(h) < len(failureHandlings) {
+		return failureHandlings[h]
+	}
+	return "FailureHandling(" + strconv.Itoa(int(h)) + ")"
+}
+
+var (
+	// ErrTxExpired is the error of a Failure caused by the expiration
+	// of the Tx timer.
+	ErrTxExpired = errors.New("credit-control answer not received within Tx")
+
+	// ErrNoPeers is returned by Session.Send when the Client has no
+	// peers to send requests to.
+	ErrNoPeers = errors.New("no credit-control peers")
+)
+
+// Failure is returned by Session.Send when no peer answered the
+// request. Handling is the CCFH in effect for the session, and tells
+// whether the service should be granted or terminated.
+type Failure struct {
+	Handling FailureHandling
+	Err      error
+}
+
+func (f *Failure) Error() string {
+	return "credit-control failure (" + f.Handling.String() + "): " + f.Err.Error()
+}
+
+// Continue reports whether the service should be granted despite the
+// failure.
+func (f *Failure) Continue() bool {
+	return f.Handling == Continue
+}
@@ -0,0 +1,68 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package creditcontrol provides a Diameter Credit-Control client, as
+// specified by RFC 4006, suitable for the 3GPP Gy and Ro interfaces.
+//
+// A Client holds the connections to the Online Charging System (OCS)
+// peers, in order of preference. Each credit-control session is a
+// Session that builds and sends CCR-Initial, CCR-Update and
+// CCR-Terminate requests, keeping track of the CC-Request-Number.
+//
+// Every request is supervised by the Tx timer. When it expires, or the
+// connection fails, the Credit-Control-Failure-Handling (CCFH) and
+// CC-Session-Failover in effect for the session decide whether the
+// request is sent to an alternate peer and whether the service should
+// be granted or terminated.
+//
+// Example:
+//
+//	cli := creditcontrol.NewClient("gw.example.com", "example.com", "ocs.example.com", conn1, conn2)
+//	cli.SessionFailover = true
+//	cli.FailureHandling = creditcontrol.RetryAndTerminate
+//
+//	s := cli.NewSession()
+//	a, err := s.Init(ctx, creditcontrol.NewMSCC(1).Request(&creditcontrol.ServiceUnit{}))
+//	if f, ok := err.(*creditcontrol.Failure); ok && f.Continue() {
+//		// grant the service without credit control
+//	}
+//	for _, mscc := range a.MSCC {
+//		// use mscc.Granted
+//	}
+//
+// A QuotaManager tracks the units granted to a session per Rating-Group.
+// It sends CCR-Updates when the Volume-Quota-Threshold or
+// Time-Quota-Threshold of a grant is reached, or when its Validity-Time
+// expires, and calls OnExhausted when no more units are granted.
+//
+// Example:
+//
+//	qm := creditcontrol.NewQuotaManager(cli.NewSession())
+//	qm.OnExhausted = func(rg uint32, final *creditcontrol.FinalUnitAction) {
+//		// block the traffic of the rating group
+//	}
+//	if _, err := qm.Init(ctx, 1, 2); err != nil {
+//		return err
+//	}
+//	qm.Use(1, creditcontrol.ServiceUnit{TotalOctets: n})
+//
+// A Server parses CCRs and dispatches them to the Handler registered for
+// their CC-Request-Type. It keeps the units granted and used in each
+// session, and answers with CCAs that echo the CC-Request-Type and
+// CC-Request-Number of the request.
+//
+// Example:
+//
+//	srv := creditcontrol.NewServer("ocs.example.com", "example.com")
+//	srv.Handle(creditcontrol.InitialRequest, func(s *creditcontrol.ServerSession, req *creditcontrol.Request, res *creditcontrol.Response) {
+//		for _, m := range req.MSCC {
+//			res.MSCC = append(res.MSCC, &creditcontrol.MSCC{
+//				RatingGroup: m.RatingGroup,
+//				Granted:     &creditcontrol.ServiceUnit{Time: 3600},
+//			})
+//		}
+//	})
+//	mux := sm.New(settings)
+//	mux.Handle("CCR", srv)
+package creditcontrol
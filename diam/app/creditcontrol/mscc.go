@@ -0,0 +1,176 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package creditcontrol
+
+import (
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// ServiceUnit is the content of the Requested-Service-Unit,
+// Used-Service-Unit and Granted-Service-Unit AVPs. Zero values are
+// omitted, so an empty ServiceUnit requests units without specifying
+// the amount.
+type ServiceUnit struct {
+	Time         uint32 `avp:"CC-Time,omitempty"`
+	TotalOctets  uint64 `avp:"CC-Total-Octets,omitempty"`
+	InputOctets  uint64 `avp:"CC-Input-Octets,omitempty"`
+	OutputOctets uint64 `avp:"CC-Output-Octets,omitempty"`
+}
+
+// AVP returns the ServiceUnit as a grouped AVP with the given code.
+func (u *ServiceUnit) AVP(code uint32) *diam.AVP {
+	var avps []*diam.AVP
+	if u.Time != 0 {
+		avps = append(avps, diam.NewAVP(avp.CCTime, avp.Mbit, 0, datatype.Unsigned32(u.Time)))
+	}
+	if u.TotalOctets != 0 {
+		avps = append(avps, diam.NewAVP(avp.CCTotalOctets, avp.Mbit, 0, datatype.Unsigned64(u.TotalOctets)))
+	}
+	if u.InputOctets != 0 {
+		avps = append(avps, diam.NewAVP(avp.CCInputOctets, avp.Mbit, 0, datatype.Unsigned64(u.InputOctets)))
+	}
+	if u.OutputOctets != 0 {
+		avps = append(avps, diam.NewAVP(avp.CCOutputOctets, avp.Mbit, 0, datatype.Unsigned64(u.OutputOctets)))
+	}
+	return diam.NewAVP(code, avp.Mbit, 0, &diam.GroupedAVP{AVP: avps})
+}
+
+// FinalUnitAction is the value of the Final-Unit-Action AVP.
+type FinalUnitAction int32
+
+// Final unit actions.
+const (
+	FinalTerminate      FinalUnitAction = 0
+	FinalRedirect       FinalUnitAction = 1
+	FinalRestrictAccess FinalUnitAction = 2
+)
+
+// MSCC is the content of a Multiple-Services-Credit-Control AVP.
+//
+// Requested and Used are sent in requests; Granted, ValidityTime,
+// ResultCode, FinalUnit and the 3GPP quota thresholds are sent in
+// answers.
+type MSCC struct {
+	RatingGroup       *uint32
+	ServiceIdentifier *uint32
+	Requested         *ServiceUnit
+	Used              *ServiceUnit
+	Granted           *ServiceUnit
+	ValidityTime      time.Duration
+	ResultCode        uint32
+	FinalUnit         *FinalUnitAction
+
+	// VolumeQuotaThreshold and TimeQuotaThreshold are the octets and
+	// seconds of the granted units left when the client must ask for
+	// more, see 3GPP TS 32.299 sections 7.2.240 and 7.2.222.
+	VolumeQuotaThreshold uint32
+	TimeQuotaThreshold   uint32
+}
+
+// NewMSCC returns an MSCC for the given Rating-Group.
+func NewMSCC(ratingGroup uint32) *MSCC {
+	return &MSCC{RatingGroup: &ratingGroup}
+}
+
+// Service sets the Service-Identifier of the MSCC.
+func (m *MSCC) Service(id uint32) *MSCC {
+	m.ServiceIdentifier = &id
+	return m
+}
+
+// Request sets the Requested-Service-Unit of the MSCC.
+func (m *MSCC) Request(u *ServiceUnit) *MSCC {
+	m.Requested = u
+	return m
+}
+
+// Report sets the Used-Service-Unit of the MSCC.
+func (m *MSCC) Report(u *ServiceUnit) *MSCC {
+	m.Used = u
+	return m
+}
+
+// AVP returns the Multiple-Services-Credit-Control AVP with the fields
+// that are set.
+func (m *MSCC) AVP() *diam.AVP {
+	var avps []*diam.AVP
+	if m.Granted != nil {
+		avps = append(avps, m.Granted.AVP(avp.GrantedServiceUnit))
+	}
+	if m.Requested != nil {
+		avps = append(avps, m.Requested.AVP(avp.RequestedServiceUnit))
+	}
+	if m.Used != nil {
+		avps = append(avps, m.Used.AVP(avp.UsedServiceUnit))
+	}
+	if m.ServiceIdentifier != nil {
+		avps = append(avps, diam.NewAVP(avp.ServiceIdentifier, avp.Mbit, 0, datatype.Unsigned32(*m.ServiceIdentifier)))
+	}
+	if m.RatingGroup != nil {
+		avps = append(avps, diam.NewAVP(avp.RatingGroup, avp.Mbit, 0, datatype.Unsigned32(*m.RatingGroup)))
+	}
+	if m.ValidityTime > 0 {
+		avps = append(avps, diam.NewAVP(avp.ValidityTime, avp.Mbit, 0, datatype.Unsigned32(m.ValidityTime/time.Second)))
+	}
+	if m.ResultCode != 0 {
+		avps = append(avps, diam.NewAVP(avp.ResultCode, avp.Mbit, 0, datatype.Unsigned32(m.ResultCode)))
+	}
+	if m.TimeQuotaThreshold != 0 {
+		avps = append(avps, diam.NewAVP(avp.TimeQuotaThreshold, avp.Mbit|avp.Vbit, tgppVendorID, datatype.Unsigned32(m.TimeQuotaThreshold)))
+	}
+	if m.VolumeQuotaThreshold != 0 {
+		avps = append(avps, diam.NewAVP(avp.VolumeQuotaThreshold, avp.Mbit|avp.Vbit, tgppVendorID, datatype.Unsigned32(m.VolumeQuotaThreshold)))
+	}
+	if m.FinalUnit != nil {
+		avps = append(avps, diam.NewAVP(avp.FinalUnitIndication, avp.Mbit, 0, &diam.GroupedAVP{
+			AVP: []*diam.AVP{
+				diam.NewAVP(avp.FinalUnitAction, avp.Mbit, 0, datatype.Enumerated(*m.FinalUnit)),
+			},
+		}))
+	}
+	return diam.NewAVP(avp.MultipleServicesCreditControl, avp.Mbit, 0, &diam.GroupedAVP{AVP: avps})
+}
+
+// mscc is used to unmarshal the Multiple-Services-Credit-Control AVP.
+type mscc struct {
+	Granted           *ServiceUnit `avp:"Granted-Service-Unit"`
+	Requested         *ServiceUnit `avp:"Requested-Service-Unit"`
+	Used              *ServiceUnit `avp:"Used-Service-Unit"`
+	ServiceIdentifier *uint32      `avp:"Service-Identifier"`
+	RatingGroup       *uint32      `avp:"Rating-Group"`
+	ValidityTime      uint32       `avp:"Validity-Time"`
+	ResultCode        uint32       `avp:"Result-Code"`
+	FinalUnit         *finalUnit   `avp:"Final-Unit-Indication"`
+	TimeThreshold     uint32       `avp:"Time-Quota-Threshold"`
+	VolumeThreshold   uint32       `avp:"Volume-Quota-Threshold"`
+}
+
+type finalUnit struct {
+	Action *int32 `avp:"Final-Unit-Action"`
+}
+
+func (v *mscc) mscc() *MSCC {
+	m := &MSCC{
+		RatingGroup:       v.RatingGroup,
+		ServiceIdentifier: v.ServiceIdentifier,
+		Requested:         v.Requested,
+		Used:              v.Used,
+		Granted:           v.Granted,
+		ValidityTime:      time.Duration(v.ValidityTime) * time.Second,
+		ResultCode:        v.ResultCode,
+
+		VolumeQuotaThreshold: v.VolumeThreshold,
+		TimeQuotaThreshold:   v.TimeThreshold,
+	}
+	if v.FinalUnit != nil && v.FinalUnit.Action != nil {
+		action := FinalUnitAction(*v.FinalUnit.Action)
+		m.FinalUnit = &action
+	}
+	return m
+}
@@ -0,0 +1,314 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package creditcontrol
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+)
+
+// Quota is the state of the units granted for a Rating-Group.
+type Quota struct {
+	RatingGroup uint32
+
+	// Granted is the last Granted-Service-Unit, and Used the units
+	// used since it was received.
+	Granted ServiceUnit
+	Used    ServiceUnit
+
+	// VolumeThreshold and TimeThreshold are the Volume-Quota-Threshold
+	// and Time-Quota-Threshold of the grant.
+	VolumeThreshold uint64
+	TimeThreshold   uint32
+
+	// Expires is the end of the Validity-Time of the grant, if any.
+	Expires time.Time
+
+	// FinalUnit is set when the grant carries a Final-Unit-Indication.
+	FinalUnit *FinalUnitAction
+}
+
+// Exhausted reports whether all the granted units have been used.
+func (q *Quota) Exhausted() bool {
+	g, u := &q.Granted, &q.Used
+	return (g.Time != 0 && u.Time >= g.Time) ||
+		(g.TotalOctets != 0 && u.TotalOctets >= g.TotalOctets) ||
+		(g.InputOctets != 0 && u.InputOctets >= g.InputOctets) ||
+		(g.OutputOctets != 0 && u.OutputOctets >= g.OutputOctets)
+}
+
+// thresholdReached reports whether the units left are at or below the
+// thresholds of the grant.
+func (q *Quota) thresholdReached() bool {
+	g, u := &q.Granted, &q.Used
+	left := func(granted, used uint64) uint64 {
+		if used >= granted {
+			return 0
+		}
+		return granted - used
+	}
+	if q.TimeThreshold != 0 && g.Time != 0 &&
+		left(uint64(g.Time), uint64(u.Time)) <= uint64(q.TimeThreshold) {
+		return true
+	}
+	if q.VolumeThreshold != 0 {
+		for _, v := range [][2]uint64{
+			{g.TotalOctets, u.TotalOctets},
+			{g.InputOctets, u.InputOctets},
+			{g.OutputOctets, u.OutputOctets},
+		} {
+			if v[0] != 0 && left(v[0], v[1]) <= q.VolumeThreshold {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// QuotaManager tracks the units granted to a credit-control session
+// per Rating-Group, as done by Gy clients such as the PCEF.
+//
+// Units are reported with Use. A CCR-Update reporting the used units
+// and requesting more is sent when the units left for a Rating-Group
+// reach its quota thresholds, when all its units are used, or when its
+// Validity-Time expires. OnExhausted is called when a Rating-Group has
+// no units left and none can be obtained.
+type QuotaManager struct {
+	Session *Session
+
+	// Request is the Requested-Service-Unit sent for each Rating-Group.
+	// An empty ServiceUnit is sent when nil.
+	Request *ServiceUnit
+
+	// OnExhausted is called when the units of a Rating-Group are used
+	// up and no more are granted. final is the Final-Unit-Action of the
+	// last grant, or nil when the OCS denied or failed to grant units.
+	OnExhausted func(ratingGroup uint32, final *FinalUnitAction)
+
+	// OnAnswer, if set, is called with the result of each CCR-Update
+	// sent by the QuotaManager.
+	OnAnswer func(a *Answer, err error)
+
+	mu      sync.Mutex // guards the following
+	quotas  map[uint32]*Quota
+	timers  map[uint32]*time.Timer
+	pending map[uint32]bool
+	closed  bool
+}
+
+// NewQuotaManager returns a QuotaManager for the given session.
+func NewQuotaManager(s *Session) *QuotaManager {
+	return &QuotaManager{
+		Session: s,
+		quotas:  make(map[uint32]*Quota),
+		timers:  make(map[uint32]*time.Timer),
+		pending: make(map[uint32]bool),
+	}
+}
+
+func (qm *QuotaManager) request() *ServiceUnit {
+	if qm.Request != nil {
+		return qm.Request
+	}
+	return &ServiceUnit{}
+}
+
+// Init sends the CCR-Initial of the session, requesting units for the
+// given Rating-Groups, and applies the units granted in its answer.
+func (qm *QuotaManager) Init(ctx context.Context, ratingGroups ...uint32) (*Answer, error) {
+	mscc := make([]*MSCC, len(ratingGroups))
+	for i, rg := range ratingGroups {
+		mscc[i] = NewMSCC(rg).Request(qm.request())
+	}
+	a, err := qm.Session.Init(ctx, mscc...)
+	qm.apply(ratingGroups, a, err)
+	return a, err
+}
+
+// Quota returns a copy of the state of the given Rating-Group.
+func (qm *QuotaManager) Quota(ratingGroup uint32) (Quota, bool) {
+	qm.mu.Lock()
+	defer qm.mu.Unlock()
+	q, ok := qm.quotas[ratingGroup]
+	if !ok {
+		return Quota{}, false
+	}
+	return *q, true
+}
+
+// Use adds the given units to the units used for the Rating-Group.
+// It sends a CCR-Update in the background when the quota thresholds
+// are reached, and calls OnExhausted when the units are used up under
+// a final grant.
+func (qm *QuotaManager) Use(ratingGroup uint32, u ServiceUnit) {
+	qm.mu.Lock()
+	q, ok := qm.quotas[ratingGroup]
+	if !ok || qm.closed {
+		qm.mu.Unlock()
+		return
+	}
+	q.Used.Time += u.Time
+	q.Used.TotalOctets += u.TotalOctets
+	q.Used.InputOctets += u.InputOctets
+	q.Used.OutputOctets += u.OutputOctets
+	exhausted := q.Exhausted()
+	final := q.FinalUnit
+	update := !qm.pending[ratingGroup] && final == nil && (exhausted || q.thresholdReached())
+	if update {
+		qm.pending[ratingGroup] = true
+	}
+	qm.mu.Unlock()
+	if update {
+		go qm.update(ratingGroup)
+	} else if exhausted && final != nil {
+		qm.exhausted(ratingGroup, final)
+	}
+}
+
+// Terminate stops the Validity-Time timers and sends the CCR-Terminate
+// of the session, reporting the units used for each Rating-Group.
+func (qm *QuotaManager) Terminate(ctx context.Context) (*Answer, error) {
+	qm.mu.Lock()
+	qm.closed = true
+	var mscc []*MSCC
+	for rg, q := range qm.quotas {
+		used := q.Used
+		mscc = append(mscc, NewMSCC(rg).Report(&used))
+	}
+	for rg, t := range qm.timers {
+		t.Stop()
+		delete(qm.timers, rg)
+	}
+	qm.mu.Unlock()
+	return qm.Session.Terminate(ctx, mscc...)
+}
+
+// update sends a CCR-Update reporting the units used for the
+// Rating-Group and requesting more.
+func (qm *QuotaManager) update(ratingGroup uint32) {
+	qm.mu.Lock()
+	q, ok := qm.quotas[ratingGroup]
+	if !ok || qm.closed {
+		delete(qm.pending, ratingGroup)
+		qm.mu.Unlock()
+		return
+	}
+	used := q.Used
+	qm.mu.Unlock()
+	a, err := qm.Session.Update(context.Background(),
+		NewMSCC(ratingGroup).Report(&used).Request(qm.request()))
+	qm.mu.Lock()
+	delete(qm.pending, ratingGroup)
+	if q, ok := qm.quotas[ratingGroup]; ok {
+		// Units used while the update was in flight count against
+		// the new grant.
+		q.Used = ServiceUnit{
+			Time:         q.Used.Time - used.Time,
+			TotalOctets:  q.Used.TotalOctets - used.TotalOctets,
+			InputOctets:  q.Used.InputOctets - used.InputOctets,
+			OutputOctets: q.Used.OutputOctets - used.OutputOctets,
+		}
+	}
+	qm.mu.Unlock()
+	qm.apply([]uint32{ratingGroup}, a, err)
+	if qm.OnAnswer != nil {
+		qm.OnAnswer(a, err)
+	}
+}
+
+// apply stores the units granted in the answer to a request for the
+// given Rating-Groups.
+func (qm *QuotaManager) apply(ratingGroups []uint32, a *Answer, err error) {
+	var denied []uint32
+	granted := make(map[uint32]bool)
+	qm.mu.Lock()
+	if qm.closed {
+		qm.mu.Unlock()
+		return
+	}
+	if err != nil {
+		if f, ok := err.(*Failure); ok && f.Continue() {
+			// The service continues without credit control.
+			qm.mu.Unlock()
+			return
+		}
+	} else if a.ResultCode == diam.Success || a.ResultCode == diam.LimitedSuccess {
+		for _, m := range a.MSCC {
+			if m.RatingGroup == nil {
+				continue
+			}
+			rg := *m.RatingGroup
+			if m.Granted == nil || (m.ResultCode != 0 && m.ResultCode != diam.Success) {
+				continue
+			}
+			q, ok := qm.quotas[rg]
+			if !ok {
+				q = &Quota{RatingGroup: rg}
+				qm.quotas[rg] = q
+			}
+			q.Granted = *m.Granted
+			q.VolumeThreshold = uint64(m.VolumeQuotaThreshold)
+			q.TimeThreshold = m.TimeQuotaThreshold
+			q.FinalUnit = m.FinalUnit
+			validity := m.ValidityTime
+			if validity == 0 {
+				validity = a.ValidityTime
+			}
+			qm.setTimer(q, validity)
+			granted[rg] = true
+		}
+	}
+	for _, rg := range ratingGroups {
+		if granted[rg] {
+			continue
+		}
+		denied = append(denied, rg)
+		if t, ok := qm.timers[rg]; ok {
+			t.Stop()
+			delete(qm.timers, rg)
+		}
+		delete(qm.quotas, rg)
+	}
+	qm.mu.Unlock()
+	for _, rg := range denied {
+		qm.exhausted(rg, nil)
+	}
+}
+
+// setTimer arms the Validity-Time timer of the quota. It must be called
+// with qm.mu held.
+func (qm *QuotaManager) setTimer(q *Quota, validity time.Duration) {
+	rg := q.RatingGroup
+	if t, ok := qm.timers[rg]; ok {
+		t.Stop()
+		delete(qm.timers, rg)
+	}
+	q.Expires = time.Time{}
+	if validity <= 0 {
+		return
+	}
+	q.Expires = time.Now().Add(validity)
+	qm.timers[rg] = time.AfterFunc(validity, func() {
+		qm.mu.Lock()
+		update := !qm.pending[rg] && !qm.closed
+		if update {
+			qm.pending[rg] = true
+		}
+		qm.mu.Unlock()
+		if update {
+			qm.update(rg)
+		}
+	})
+}
+
+func (qm *QuotaManager) exhausted(ratingGroup uint32, final *FinalUnitAction) {
+	if qm.OnExhausted != nil {
+		qm.OnExhausted(ratingGroup, final)
+	}
+}
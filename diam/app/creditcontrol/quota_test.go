@@ -0,0 +1,166 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package creditcontrol
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+)
+
+// newQuotaOCS returns a connection to an OCS granting 1000 octets per
+// MSCC with a Volume-Quota-Threshold of 200 in CCA-Initials, and
+// answering CCR-Updates with update.
+func newQuotaOCS(t *testing.T, update Handler) (diam.Conn, func()) {
+	srv := NewServer("ocs", "localhost")
+	srv.Handle(InitialRequest, func(s *ServerSession, req *Request, res *Response) {
+		for _, m := range req.MSCC {
+			res.MSCC = append(res.MSCC, &MSCC{
+				RatingGroup:          m.RatingGroup,
+				Granted:              &ServiceUnit{TotalOctets: 1000},
+				VolumeQuotaThreshold: 200,
+				ValidityTime:         time.Hour,
+				ResultCode:           diam.Success,
+			})
+		}
+	})
+	srv.Handle(UpdateRequest, update)
+	ts := diamtest.NewServer(srv, nil)
+	c := dial(t, ts)
+	return c, func() {
+		c.Close()
+		ts.Close()
+	}
+}
+
+func TestQuotaManager_Threshold(t *testing.T) {
+	reported := make(chan uint64, 1)
+	c, done := newQuotaOCS(t, func(s *ServerSession, req *Request, res *Response) {
+		for _, m := range req.MSCC {
+			if m.Used != nil {
+				reported <- m.Used.TotalOctets
+			}
+			res.MSCC = append(res.MSCC, &MSCC{
+				RatingGroup: m.RatingGroup,
+				Granted:     &ServiceUnit{TotalOctets: 500},
+				ResultCode:  diam.Success,
+			})
+		}
+	})
+	defer done()
+
+	qm := NewQuotaManager(NewClient("cli", "localhost", "localhost", c).NewSession())
+	answers := make(chan error, 1)
+	qm.OnAnswer = func(a *Answer, err error) { answers <- err }
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := qm.Init(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	q, ok := qm.Quota(1)
+	if !ok || q.Granted.TotalOctets != 1000 || q.VolumeThreshold != 200 || q.Expires.IsZero() {
+		t.Fatalf("Unexpected quota: %+v", q)
+	}
+	qm.Use(1, ServiceUnit{TotalOctets: 700})
+	select {
+	case <-reported:
+		t.Fatal("Update sent before the threshold was reached")
+	case <-time.After(50 * time.Millisecond):
+	}
+	qm.Use(1, ServiceUnit{TotalOctets: 150})
+	select {
+	case n := <-reported:
+		if n != 850 {
+			t.Fatalf("Unexpected used octets. Want 850, have %d", n)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for CCR-Update")
+	}
+	select {
+	case err := <-answers:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for CCA-Update")
+	}
+	if q, _ = qm.Quota(1); q.Granted.TotalOctets != 500 || q.Used.TotalOctets != 0 {
+		t.Fatalf("Unexpected quota: %+v", q)
+	}
+	if a, err := qm.Terminate(ctx); err != nil || a.ResultCode != diam.Success {
+		t.Fatalf("Unexpected answer: %v, %v", a, err)
+	}
+}
+
+func TestQuotaManager_Exhausted(t *testing.T) {
+	c, done := newQuotaOCS(t, func(s *ServerSession, req *Request, res *Response) {
+		for _, m := range req.MSCC {
+			res.MSCC = append(res.MSCC, &MSCC{
+				RatingGroup: m.RatingGroup,
+				ResultCode:  CreditLimitReached,
+			})
+		}
+	})
+	defer done()
+
+	qm := NewQuotaManager(NewClient("cli", "localhost", "localhost", c).NewSession())
+	exhausted := make(chan *FinalUnitAction, 1)
+	qm.OnExhausted = func(rg uint32, final *FinalUnitAction) {
+		if rg == 2 {
+			exhausted <- final
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := qm.Init(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+	qm.Use(2, ServiceUnit{TotalOctets: 1000})
+	select {
+	case final := <-exhausted:
+		if final != nil {
+			t.Fatalf("Unexpected Final-Unit-Action: %v", *final)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for exhaustion")
+	}
+	if _, ok := qm.Quota(2); ok {
+		t.Fatal("Denied quota was not removed")
+	}
+}
+
+func TestQuotaManager_FinalUnit(t *testing.T) {
+	ocs := NewServer("ocs", "localhost")
+	ocs.Handle(InitialRequest, func(s *ServerSession, req *Request, res *Response) {
+		action := FinalTerminate
+		res.MSCC = []*MSCC{{
+			RatingGroup: req.MSCC[0].RatingGroup,
+			Granted:     &ServiceUnit{Time: 60},
+			ResultCode:  diam.Success,
+			FinalUnit:   &action,
+		}}
+	})
+	ts := diamtest.NewServer(ocs, nil)
+	defer ts.Close()
+	c := dial(t, ts)
+	defer c.Close()
+
+	qm := NewQuotaManager(NewClient("cli", "localhost", "localhost", c).NewSession())
+	var final *FinalUnitAction
+	qm.OnExhausted = func(rg uint32, f *FinalUnitAction) { final = f }
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := qm.Init(ctx, 3); err != nil {
+		t.Fatal(err)
+	}
+	qm.Use(3, ServiceUnit{Time: 60})
+	if final == nil || *final != FinalTerminate {
+		t.Fatalf("Unexpected Final-Unit-Action: %v", final)
+	}
+}
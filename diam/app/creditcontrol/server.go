@@ -0,0 +1,352 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package creditcontrol
+
+import (
+	"sync"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/session"
+)
+
+// Subscription-Id types.
+const (
+	EndUserE164    = 0
+	EndUserIMSI    = 1
+	EndUserSIPURI  = 2
+	EndUserNAI     = 3
+	EndUserPrivate = 4
+)
+
+// SubscriptionID is the content of a Subscription-Id AVP.
+type SubscriptionID struct {
+	Type int32  `avp:"Subscription-Id-Type"`
+	Data string `avp:"Subscription-Id-Data"`
+}
+
+// AVP returns the Subscription-Id AVP.
+func (id *SubscriptionID) AVP() *diam.AVP {
+	return diam.NewAVP(avp.SubscriptionID, avp.Mbit, 0, &diam.GroupedAVP{
+		AVP: []*diam.AVP{
+			diam.NewAVP(avp.SubscriptionIDType, avp.Mbit, 0, datatype.Enumerated(id.Type)),
+			diam.NewAVP(avp.SubscriptionIDData, avp.Mbit, 0, datatype.UTF8String(id.Data)),
+		},
+	})
+}
+
+// FindSubscriptionID returns the data of the first Subscription-Id of
+// type typ in ids, such as the IMSI for EndUserIMSI.
+func FindSubscriptionID(ids []*SubscriptionID, typ int32) (string, bool) {
+	for _, id := range ids {
+		if id != nil && id.Type == typ {
+			return id.Data, true
+		}
+	}
+	return "", false
+}
+
+// User-Equipment-Info types.
+const (
+	EquipmentIMEISV        = 0
+	EquipmentMAC           = 1
+	EquipmentEUI64         = 2
+	EquipmentModifiedEUI64 = 3
+)
+
+// UserEquipmentInfo is the content of a User-Equipment-Info AVP, see
+// RFC 4006 section 8.49.
+type UserEquipmentInfo struct {
+	Type  int32  `avp:"User-Equipment-Info-Type"`
+	Value []byte `avp:"User-Equipment-Info-Value"`
+}
+
+// IMEISVInfo returns the User-Equipment-Info of the IMEISV imeisv, the
+// string of its 16 digits.
+func IMEISVInfo(imeisv string) *UserEquipmentInfo {
+	return &UserEquipmentInfo{Type: EquipmentIMEISV, Value: []byte(imeisv)}
+}
+
+// IMEISV returns the IMEISV of the User-Equipment-Info, if it is of
+// type EquipmentIMEISV.
+func (ue *UserEquipmentInfo) IMEISV() (string, bool) {
+	if ue.Type != EquipmentIMEISV {
+		return "", false
+	}
+	return string(ue.Value), true
+}
+
+// AVP returns the User-Equipment-Info AVP.
+func (ue *UserEquipmentInfo) AVP() *diam.AVP {
+	return diam.NewAVP(avp.UserEquipmentInfo, 0, 0, &diam.GroupedAVP{
+		AVP: []*diam.AVP{
+			diam.NewAVP(avp.UserEquipmentInfoType, 0, 0, datatype.Enumerated(ue.Type)),
+			diam.NewAVP(avp.UserEquipmentInfoValue, 0, 0, datatype.OctetString(ue.Value)),
+		},
+	})
+}
+
+// Request is a Credit-Control-Request.
+type Request struct {
+	*diam.Message
+
+	SessionID        datatype.UTF8String
+	OriginHost       datatype.DiameterIdentity
+	OriginRealm      datatype.DiameterIdentity
+	ServiceContextID string
+	RequestType      RequestType
+	RequestNumber    uint32
+	SubscriptionID   []*SubscriptionID
+	MSCC             []*MSCC
+}
+
+// request is used to unmarshal Credit-Control-Requests.
+type request struct {
+	SessionID        datatype.UTF8String       `avp:"Session-Id"`
+	OriginHost       datatype.DiameterIdentity `avp:"Origin-Host"`
+	OriginRealm      datatype.DiameterIdentity `avp:"Origin-Realm"`
+	ServiceContextID string                    `avp:"Service-Context-Id"`
+	RequestType      int32                     `avp:"CC-Request-Type"`
+	RequestNumber    uint32                    `avp:"CC-Request-Number"`
+	SubscriptionID   []*SubscriptionID         `avp:"Subscription-Id"`
+	MSCC             []*mscc                   `avp:"Multiple-Services-Credit-Control"`
+}
+
+// ParseRequest parses the Credit-Control-Request m.
+func ParseRequest(m *diam.Message) (*Request, error) {
+	var v request
+	if err := m.Unmarshal(&v); err != nil {
+		return nil, err
+	}
+	r := &Request{
+		Message:          m,
+		SessionID:        v.SessionID,
+		OriginHost:       v.OriginHost,
+		OriginRealm:      v.OriginRealm,
+		ServiceContextID: v.ServiceContextID,
+		RequestType:      RequestType(v.RequestType),
+		RequestNumber:    v.RequestNumber,
+		SubscriptionID:   v.SubscriptionID,
+	}
+	for _, mscc := range v.MSCC {
+		r.MSCC = append(r.MSCC, mscc.mscc())
+	}
+	return r, nil
+}
+
+// Response is the content of a Credit-Control-Answer, filled by the
+// Server's handlers.
+type Response struct {
+	ResultCode      uint32
+	MSCC            []*MSCC
+	FailureHandling *FailureHandling
+	SessionFailover *bool
+
+	// AVP are additional AVPs added to the answer.
+	AVP []*diam.AVP
+}
+
+// ServerSession is the state of a credit-control session on the
+// Server.
+type ServerSession struct {
+	*session.Session
+
+	mu      sync.Mutex
+	granted map[uint32]ServiceUnit
+	used    map[uint32]ServiceUnit
+}
+
+// Granted returns the units last granted for the Rating-Group.
+func (s *ServerSession) Granted(ratingGroup uint32) (ServiceUnit, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.granted[ratingGroup]
+	return u, ok
+}
+
+// Used returns the units used so far for the Rating-Group, as reported
+// by the client.
+func (s *ServerSession) Used(ratingGroup uint32) ServiceUnit {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.used[ratingGroup]
+}
+
+// report accumulates the units reported in req.
+func (s *ServerSession) report(req *Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, m := range req.MSCC {
+		if m.RatingGroup == nil || m.Used == nil {
+			continue
+		}
+		u := s.used[*m.RatingGroup]
+		u.Time += m.Used.Time
+		u.TotalOctets += m.Used.TotalOctets
+		u.InputOctets += m.Used.InputOctets
+		u.OutputOctets += m.Used.OutputOctets
+		s.used[*m.RatingGroup] = u
+	}
+}
+
+// grant records the units granted in res.
+func (s *ServerSession) grant(res *Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, m := range res.MSCC {
+		if m.RatingGroup != nil && m.Granted != nil {
+			s.granted[*m.RatingGroup] = *m.Granted
+		}
+	}
+}
+
+// Handler handles a Credit-Control-Request of a session. The Response
+// is initialized with DIAMETER_SUCCESS.
+type Handler func(s *ServerSession, req *Request, res *Response)
+
+// Server is a Diameter Credit-Control server.
+//
+// It implements the diam.Handler interface and dispatches CCRs to the
+// Handler registered for their CC-Request-Type. Requests without a
+// Handler are answered with DIAMETER_SUCCESS.
+//
+// Sessions are created by CCR-Initial, and closed by CCR-Terminate or
+// failed CCR-Initial. CCR-Update and CCR-Terminate of unknown sessions
+// are answered with DIAMETER_UNKNOWN_SESSION_ID. CCR-Event are handled
+// with a session that is closed once answered.
+type Server struct {
+	OriginHost  datatype.DiameterIdentity
+	OriginRealm datatype.DiameterIdentity
+
+	sessions *session.Manager
+	mu       sync.RWMutex // guards handlers
+	handlers map[RequestType]Handler
+}
+
+// NewServer creates and initializes a Server.
+func NewServer(host, realm datatype.DiameterIdentity) *Server {
+	return &Server{
+		OriginHost:  host,
+		OriginRealm: realm,
+		sessions:    session.NewManager(host, realm),
+		handlers:    make(map[RequestType]Handler),
+	}
+}
+
+// Handle registers the handler for the given CC-Request-Type.
+func (srv *Server) Handle(typ RequestType, h Handler) {
+	srv.mu.Lock()
+	srv.handlers[typ] = h
+	srv.mu.Unlock()
+}
+
+// Sessions returns the session manager of the Server.
+func (srv *Server) Sessions() *session.Manager {
+	return srv.sessions
+}
+
+// Session returns the state of the session with the given Session-Id.
+func (srv *Server) Session(id datatype.UTF8String) (*ServerSession, bool) {
+	s, ok := srv.sessions.Get(id)
+	if !ok {
+		return nil, false
+	}
+	ss, ok := s.Value().(*ServerSession)
+	return ss, ok
+}
+
+// ServeDIAM implements the diam.Handler interface.
+func (srv *Server) ServeDIAM(c diam.Conn, m *diam.Message) {
+	req, err := ParseRequest(m)
+	if err != nil {
+		diam.LoggerOf(c).Log(diam.LevelWarn, "failed to parse CCR", "remote", c.RemoteAddr(), "err", err)
+		srv.answer(c, m, &Response{ResultCode: diam.UnableToComply})
+		return
+	}
+	res := &Response{ResultCode: diam.Success}
+	var s *ServerSession
+	switch req.RequestType {
+	case InitialRequest, EventRequest:
+		s = srv.newSession(srv.sessions.Add(req.SessionID))
+	case UpdateRequest, TerminationRequest:
+		var ok bool
+		if s, ok = srv.Session(req.SessionID); !ok {
+			res.ResultCode = diam.UnknownSessionID
+			srv.answer(c, m, res)
+			return
+		}
+	default:
+		res.ResultCode = diam.InvalidAVPValue
+		srv.answer(c, m, res)
+		return
+	}
+	s.report(req)
+	srv.mu.RLock()
+	h := srv.handlers[req.RequestType]
+	srv.mu.RUnlock()
+	if h != nil {
+		h(s, req, res)
+	}
+	s.grant(res)
+	switch {
+	case req.RequestType == InitialRequest && res.ResultCode == diam.Success:
+		s.SetState(session.Open)
+	case req.RequestType != UpdateRequest:
+		s.SetState(session.Closed)
+	}
+	srv.answer(c, m, res)
+}
+
+// newSession initializes the state of the session s.
+func (srv *Server) newSession(s *session.Session) *ServerSession {
+	if ss, ok := s.Value().(*ServerSession); ok {
+		return ss
+	}
+	ss := &ServerSession{
+		Session: s,
+		granted: make(map[uint32]ServiceUnit),
+		used:    make(map[uint32]ServiceUnit),
+	}
+	s.SetValue(ss)
+	return ss
+}
+
+// answer writes the Credit-Control-Answer to the request m, echoing its
+// Session-Id, CC-Request-Type and CC-Request-Number.
+func (srv *Server) answer(c diam.Conn, m *diam.Message, res *Response) {
+	a := m.Answer(0)
+	if v, err := m.FindAVP(avp.SessionID, 0); err == nil {
+		a.AddAVP(v)
+	}
+	a.NewAVP(avp.ResultCode, avp.Mbit, 0, datatype.Unsigned32(res.ResultCode))
+	a.NewAVP(avp.OriginHost, avp.Mbit, 0, srv.OriginHost)
+	a.NewAVP(avp.OriginRealm, avp.Mbit, 0, srv.OriginRealm)
+	a.NewAVP(avp.AuthApplicationID, avp.Mbit, 0, datatype.Unsigned32(ApplicationID))
+	for _, code := range []uint32{avp.CCRequestType, avp.CCRequestNumber} {
+		if v, err := m.FindAVP(code, 0); err == nil {
+			a.AddAVP(v)
+		}
+	}
+	for _, v := range res.MSCC {
+		a.AddAVP(v.AVP())
+	}
+	if res.FailureHandling != nil {
+		a.NewAVP(avp.CreditControlFailureHandling, avp.Mbit, 0, datatype.Enumerated(*res.FailureHandling))
+	}
+	if res.SessionFailover != nil {
+		var failover datatype.Enumerated
+		if *res.SessionFailover {
+			failover = 1 // FAILOVER_SUPPORTED
+		}
+		a.NewAVP(avp.CCSessionFailover, avp.Mbit, 0, failover)
+	}
+	for _, v := range res.AVP {
+		a.AddAVP(v)
+	}
+	if _, err := a.WriteTo(c); err != nil {
+		diam.LoggerOf(c).Log(diam.LevelError, "failed to send CCA", "remote", c.RemoteAddr(), "err", err)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package creditcontrol
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+)
+
+func TestServer(t *testing.T) {
+	srv := NewServer("ocs", "localhost")
+	grant := func(s *ServerSession, req *Request, res *Response) {
+		for _, m := range req.MSCC {
+			res.MSCC = append(res.MSCC, &MSCC{
+				RatingGroup:  m.RatingGroup,
+				Granted:      &ServiceUnit{Time: 60},
+				ValidityTime: time.Minute,
+				ResultCode:   diam.Success,
+			})
+		}
+	}
+	srv.Handle(InitialRequest, grant)
+	srv.Handle(UpdateRequest, grant)
+	ts := diamtest.NewServer(srv, nil)
+	defer ts.Close()
+	c := dial(t, ts)
+	defer c.Close()
+
+	s := NewClient("cli", "localhost", "localhost", c).NewSession()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	a, err := s.Init(ctx, NewMSCC(1).Request(&ServiceUnit{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.ResultCode != diam.Success || a.RequestType != InitialRequest || len(a.MSCC) != 1 {
+		t.Fatalf("Unexpected answer: %+v", a)
+	}
+	if g := a.MSCC[0].Granted; g == nil || g.Time != 60 {
+		t.Fatalf("Unexpected Granted-Service-Unit: %+v", g)
+	}
+	if _, err = s.Update(ctx, NewMSCC(1).Report(&ServiceUnit{Time: 40})); err != nil {
+		t.Fatal(err)
+	}
+	a, err = s.Update(ctx, NewMSCC(1).Report(&ServiceUnit{Time: 20}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.RequestNumber != 2 {
+		t.Fatalf("Unexpected CC-Request-Number. Want 2, have %d", a.RequestNumber)
+	}
+	ss, ok := srv.Session(s.ID)
+	if !ok {
+		t.Fatal("Session not found")
+	}
+	if u := ss.Used(1); u.Time != 60 {
+		t.Fatalf("Unexpected used time. Want 60, have %d", u.Time)
+	}
+	if u, ok := ss.Granted(1); !ok || u.Time != 60 {
+		t.Fatalf("Unexpected granted units: %+v", u)
+	}
+	if a, err = s.Terminate(ctx); err != nil || a.ResultCode != diam.Success {
+		t.Fatalf("Unexpected answer: %v, %v", a, err)
+	}
+	if _, ok = srv.Session(s.ID); ok {
+		t.Fatal("Terminated session was not removed")
+	}
+	a, err = s.Update(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.ResultCode != diam.UnknownSessionID {
+		t.Fatalf("Unexpected Result-Code. Want %d, have %d", diam.UnknownSessionID, a.ResultCode)
+	}
+}
+
+func TestFindSubscriptionID(t *testing.T) {
+	ids := []*SubscriptionID{
+		{Type: EndUserE164, Data: "15551234567"},
+		{Type: EndUserIMSI, Data: "001010123456789"},
+	}
+	if v, ok := FindSubscriptionID(ids, EndUserIMSI); !ok || v != "001010123456789" {
+		t.Fatalf("Unexpected IMSI %q", v)
+	}
+	if v, ok := FindSubscriptionID(ids, EndUserNAI); ok {
+		t.Fatalf("Unexpected NAI %q", v)
+	}
+}
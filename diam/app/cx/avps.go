@@ -0,0 +1,108 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package cx
+
+import (
+	"errors"
+
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// ErrInvalidItem is returned by SIPAuthDataItem.Validate when the item
+// does not hold a valid IMS AKA authentication vector.
+var ErrInvalidItem = errors.New("invalid SIP-Auth-Data-Item")
+
+// ServerCapabilities is the Server-Capabilities AVP, see 3GPP TS 29.229
+// section 6.3.4.
+type ServerCapabilities struct {
+	MandatoryCapability []uint32 `avp:"Mandatory-Capability,omitempty"`
+	OptionalCapability  []uint32 `avp:"Optional-Capability,omitempty"`
+	ServerName          []string `avp:"Server-Name,omitempty"`
+}
+
+// ChargingInformation is the Charging-Information AVP, see 3GPP TS
+// 29.229 section 6.3.19.
+type ChargingInformation struct {
+	PrimaryEventChargingFunctionName        datatype.DiameterURI `avp:"Primary-Event-Charging-Function-Name,omitempty"`
+	SecondaryEventChargingFunctionName      datatype.DiameterURI `avp:"Secondary-Event-Charging-Function-Name,omitempty"`
+	PrimaryChargingCollectionFunctionName   datatype.DiameterURI `avp:"Primary-Charging-Collection-Function-Name,omitempty"`
+	SecondaryChargingCollectionFunctionName datatype.DiameterURI `avp:"Secondary-Charging-Collection-Function-Name,omitempty"`
+}
+
+// SIPAuthDataItem is the SIP-Auth-Data-Item AVP, see 3GPP TS 29.229
+// section 6.3.13.
+//
+// For IMS AKA, SIP-Authenticate is the concatenation of RAND and AUTN,
+// and SIP-Authorization is XRES, or the concatenation of RAND and AUTS
+// in MARs requesting resynchronization.
+type SIPAuthDataItem struct {
+	SIPItemNumber            uint32 `avp:"SIP-Item-Number,omitempty"`
+	SIPAuthenticationScheme  string `avp:"SIP-Authentication-Scheme,omitempty"`
+	SIPAuthenticate          []byte `avp:"SIP-Authenticate,omitempty"`
+	SIPAuthorization         []byte `avp:"SIP-Authorization,omitempty"`
+	SIPAuthenticationContext []byte `avp:"SIP-Authentication-Context,omitempty"`
+	ConfidentialityKey       []byte `avp:"Confidentiality-Key,omitempty"`
+	IntegrityKey             []byte `avp:"Integrity-Key,omitempty"`
+}
+
+// NewAKAItem returns the item number n with the IMS AKA authentication
+// vector made of the given RAND, AUTN, XRES, CK and IK.
+func NewAKAItem(n uint32, rand, autn, xres, ck, ik []byte) *SIPAuthDataItem {
+	return &SIPAuthDataItem{
+		SIPItemNumber:           n,
+		SIPAuthenticationScheme: DigestAKAv1MD5,
+		SIPAuthenticate:         append(append([]byte{}, rand...), autn...),
+		SIPAuthorization:        xres,
+		ConfidentialityKey:      ck,
+		IntegrityKey:            ik,
+	}
+}
+
+// NewResyncItem returns the item of a MAR requesting the
+// resynchronization of the sequence numbers of the HSS, with the RAND
+// of the failed challenge and the AUTS returned by the UE.
+func NewResyncItem(rand, auts []byte) *SIPAuthDataItem {
+	return &SIPAuthDataItem{
+		SIPAuthenticationScheme: DigestAKAv1MD5,
+		SIPAuthorization:        append(append([]byte{}, rand...), auts...),
+	}
+}
+
+// RAND returns the RAND of the IMS AKA vector of the item.
+func (i *SIPAuthDataItem) RAND() []byte {
+	if len(i.SIPAuthenticate) < 16 {
+		return nil
+	}
+	return i.SIPAuthenticate[:16]
+}
+
+// AUTN returns the AUTN of the IMS AKA vector of the item.
+func (i *SIPAuthDataItem) AUTN() []byte {
+	if len(i.SIPAuthenticate) < 32 {
+		return nil
+	}
+	return i.SIPAuthenticate[16:32]
+}
+
+// Resync returns the RAND and AUTS of an item requesting
+// resynchronization.
+func (i *SIPAuthDataItem) Resync() (rand, auts []byte, ok bool) {
+	if len(i.SIPAuthenticate) != 0 || len(i.SIPAuthorization) != 30 {
+		return nil, nil, false
+	}
+	return i.SIPAuthorization[:16], i.SIPAuthorization[16:], true
+}
+
+// Validate checks the lengths of the fields of an IMS AKA vector, as
+// specified by 3GPP TS 33.102.
+func (i *SIPAuthDataItem) Validate() error {
+	if i.SIPAuthenticationScheme != DigestAKAv1MD5 ||
+		len(i.SIPAuthenticate) != 32 ||
+		len(i.SIPAuthorization) < 4 || len(i.SIPAuthorization) > 16 ||
+		len(i.ConfidentialityKey) != 16 || len(i.IntegrityKey) != 16 {
+		return ErrInvalidItem
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package cx
+
+import "github.com/omnicate/go-diameter/v4/diam"
+
+// ApplicationID is the Cx/Dx Application ID.
+const ApplicationID = diam.TGPP_CX_APP_ID
+
+// VendorID is the 3GPP Vendor-Id.
+const VendorID = 10415
+
+// Values of the Auth-Session-State AVP. Cx sessions are stateless.
+const (
+	StateMaintained   = 0
+	NoStateMaintained = 1
+)
+
+// Values of the SIP-Authentication-Scheme AVP.
+const (
+	DigestAKAv1MD5 = "Digest-AKAv1-MD5"
+	SIPDigest      = "SIP Digest"
+	NASSBundled    = "NASS-Bundled"
+	Unknown        = "Unknown"
+)
+
+// Values of the User-Authorization-Type AVP.
+const (
+	AuthorizationRegistration                = 0
+	AuthorizationDeRegistration              = 1
+	AuthorizationRegistrationAndCapabilities = 2
+)
+
+// Values of the Server-Assignment-Type AVP.
+const (
+	NoAssignment                         = 0
+	Registration                         = 1
+	ReRegistration                       = 2
+	UnregisteredUser                     = 3
+	TimeoutDeregistration                = 4
+	UserDeregistration                   = 5
+	TimeoutDeregistrationStoreServerName = 6
+	UserDeregistrationStoreServerName    = 7
+	AdministrativeDeregistration         = 8
+	AuthenticationFailure                = 9
+	AuthenticationTimeout                = 10
+	DeregistrationTooMuchData            = 11
+	AAAUserDataRequest                   = 12
+	PGWUpdate                            = 13
+	Restoration                          = 14
+)
+
+// Values of the User-Data-Already-Available AVP.
+const (
+	UserDataNotAvailable     = 0
+	UserDataAlreadyAvailable = 1
+)
+
+// Originating is the value of the Originating-Request AVP.
+const Originating = 0
+
+// Bits of the UAR-Flags AVP.
+const (
+	UARIMSEmergencyRegistration = 1 << 0
+)
+
+// Experimental-Result-Code values, see 3GPP TS 29.229 section 6.2.
+const (
+	FirstRegistration                  = 2001
+	SubsequentRegistration             = 2002
+	UnregisteredService                = 2003
+	SuccessServerNameNotStored         = 2004
+	ErrorUserUnknown                   = 5001
+	ErrorIdentitiesDontMatch           = 5002
+	ErrorIdentityNotRegistered         = 5003
+	ErrorRoamingNotAllowed             = 5004
+	ErrorIdentityAlreadyRegistered     = 5005
+	ErrorAuthSchemeNotSupported        = 5006
+	ErrorInAssignmentType              = 5007
+	ErrorTooMuchData                   = 5008
+	ErrorNotSupportedUserData          = 5009
+	ErrorFeatureUnsupported            = 5011
+	ErrorServingNodeFeatureUnsupported = 5012
+)
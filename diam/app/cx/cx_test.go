@@ -0,0 +1,222 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package cx
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/app/tgpp"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+)
+
+var cxApp = &tgpp.VendorSpecificApplicationID{
+	VendorID:          VendorID,
+	AuthApplicationID: ApplicationID,
+}
+
+func newMAR() *MAR {
+	return &MAR{
+		SessionID:                   "scscf;1;2",
+		VendorSpecificApplicationID: cxApp,
+		AuthSessionState:            NoStateMaintained,
+		OriginHost:                  "scscf",
+		OriginRealm:                 "ims",
+		DestinationRealm:            "ims",
+		UserName:                    "001010000000001@ims",
+		PublicIdentity:              "sip:001010000000001@ims",
+		SIPAuthDataItem:             &SIPAuthDataItem{SIPAuthenticationScheme: DigestAKAv1MD5},
+		SIPNumberAuthItems:          1,
+		ServerName:                  "sip:scscf.ims",
+	}
+}
+
+func TestSIPAuthDataItem(t *testing.T) {
+	rand := bytes.Repeat([]byte{1}, 16)
+	autn := bytes.Repeat([]byte{2}, 16)
+	key := bytes.Repeat([]byte{3}, 16)
+	item := NewAKAItem(1, rand, autn, []byte("xres0001"), key, key)
+	if err := item.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(item.RAND(), rand) || !bytes.Equal(item.AUTN(), autn) {
+		t.Fatalf("Unexpected vector: %+v", item)
+	}
+	if _, _, ok := item.Resync(); ok {
+		t.Fatal("Vector is a resynchronization request")
+	}
+	item.IntegrityKey = nil
+	if err := item.Validate(); err != ErrInvalidItem {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	auts := bytes.Repeat([]byte{4}, 14)
+	r, a, ok := NewResyncItem(rand, auts).Resync()
+	if !ok || !bytes.Equal(r, rand) || !bytes.Equal(a, auts) {
+		t.Fatalf("Unexpected resynchronization: %x %x %v", r, a, ok)
+	}
+}
+
+func TestMAR(t *testing.T) {
+	m, err := newMAR().Message()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Header.CommandCode != diam.MultimediaAuthentication || m.Header.ApplicationID != ApplicationID {
+		t.Fatalf("Unexpected header: %s", m.Header)
+	}
+	if _, err := m.FindAVP(avp.SIPAuthDataItem, VendorID); err != nil {
+		t.Fatal(err)
+	}
+	r, err := ParseMAR(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.PublicIdentity != "sip:001010000000001@ims" || r.SIPNumberAuthItems != 1 ||
+		r.SIPAuthDataItem == nil || r.SIPAuthDataItem.SIPAuthenticationScheme != DigestAKAv1MD5 {
+		t.Fatalf("Unexpected MAR: %+v", r)
+	}
+}
+
+func TestMAA(t *testing.T) {
+	req, err := newMAR().Message()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := bytes.Repeat([]byte{3}, 16)
+	maa := &MAA{
+		SessionID:                   "scscf;1;2",
+		VendorSpecificApplicationID: cxApp,
+		ResultCode:                  diam.Success,
+		AuthSessionState:            NoStateMaintained,
+		OriginHost:                  "hss",
+		OriginRealm:                 "ims",
+		SIPNumberAuthItems:          2,
+		SIPAuthDataItem: []*SIPAuthDataItem{
+			NewAKAItem(2, bytes.Repeat([]byte{2}, 16), key, []byte("xres0002"), key, key),
+			NewAKAItem(1, bytes.Repeat([]byte{1}, 16), key, []byte("xres0001"), key, key),
+		},
+	}
+	m, err := maa.Message(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := ParseMAA(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code, _ := diam.ResultCode(m); code != diam.Success {
+		t.Fatalf("Unexpected result: %d", code)
+	}
+	items := a.Items()
+	if len(items) != 2 || items[0].SIPItemNumber != 1 || items[1].SIPItemNumber != 2 {
+		t.Fatalf("Unexpected items: %+v", items)
+	}
+	for _, item := range items {
+		if err := item.Validate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestUAA_ExperimentalResult(t *testing.T) {
+	typ := int32(AuthorizationRegistration)
+	uar := &UAR{
+		SessionID:                   "icscf;1;2",
+		VendorSpecificApplicationID: cxApp,
+		AuthSessionState:            NoStateMaintained,
+		OriginHost:                  "icscf",
+		OriginRealm:                 "ims",
+		DestinationRealm:            "ims",
+		UserName:                    "001010000000001@ims",
+		PublicIdentity:              "sip:001010000000001@ims",
+		VisitedNetworkIdentifier:    []byte("ims"),
+		UserAuthorizationType:       &typ,
+	}
+	req, err := uar.Message()
+	if err != nil {
+		t.Fatal(err)
+	}
+	uaa := &UAA{
+		SessionID:                   uar.SessionID,
+		VendorSpecificApplicationID: cxApp,
+		ExperimentalResult: &tgpp.ExperimentalResult{
+			VendorID:               VendorID,
+			ExperimentalResultCode: FirstRegistration,
+		},
+		AuthSessionState: NoStateMaintained,
+		OriginHost:       "hss",
+		OriginRealm:      "ims",
+		ServerCapabilities: &ServerCapabilities{
+			MandatoryCapability: []uint32{1},
+			ServerName:          []string{"sip:scscf.ims"},
+		},
+	}
+	m, err := uaa.Message(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := ParseUAA(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code, _ := diam.ResultCode(m); code != FirstRegistration {
+		t.Fatalf("Unexpected result: %d", code)
+	}
+	if c := a.ServerCapabilities; c == nil || len(c.MandatoryCapability) != 1 ||
+		len(c.ServerName) != 1 || c.ServerName[0] != "sip:scscf.ims" {
+		t.Fatalf("Unexpected Server-Capabilities: %+v", c)
+	}
+}
+
+func TestSAA(t *testing.T) {
+	sar := &SAR{
+		SessionID:                   "scscf;1;3",
+		VendorSpecificApplicationID: cxApp,
+		AuthSessionState:            NoStateMaintained,
+		OriginHost:                  "scscf",
+		OriginRealm:                 "ims",
+		DestinationRealm:            "ims",
+		PublicIdentity:              []string{"sip:001010000000001@ims"},
+		ServerName:                  "sip:scscf.ims",
+		ServerAssignmentType:        Registration,
+		UserDataAlreadyAvailable:    UserDataNotAvailable,
+	}
+	req, err := sar.Message()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := ParseSAR(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.ServerAssignmentType != Registration || len(r.PublicIdentity) != 1 {
+		t.Fatalf("Unexpected SAR: %+v", r)
+	}
+	saa := &SAA{
+		SessionID:                   sar.SessionID,
+		VendorSpecificApplicationID: cxApp,
+		ResultCode:                  diam.Success,
+		AuthSessionState:            NoStateMaintained,
+		OriginHost:                  "hss",
+		OriginRealm:                 "ims",
+		UserData:                    []byte("<IMSSubscription/>"),
+		ChargingInformation: &ChargingInformation{
+			PrimaryChargingCollectionFunctionName: "aaa://ccf.ims",
+		},
+	}
+	m, err := saa.Message(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := ParseSAA(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(a.UserData) != "<IMSSubscription/>" || a.ChargingInformation == nil ||
+		a.ChargingInformation.PrimaryChargingCollectionFunctionName != "aaa://ccf.ims" {
+		t.Fatalf("Unexpected SAA: %+v", a)
+	}
+}
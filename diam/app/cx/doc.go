@@ -0,0 +1,44 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package cx provides typed messages of the 3GPP Cx and Dx interfaces,
+// between the I-CSCF or S-CSCF and the HSS or SLF, as specified by 3GPP
+// TS 29.228 and TS 29.229.
+//
+// The Cx dictionary is part of dict.Default. The UAR/UAA, MAR/MAA,
+// SAR/SAA and LIR/LIA types map the AVPs of their commands to Go
+// structs. Their Message methods build a diam.Message with
+// diam.Message.Marshal, and the Parse functions decode one with
+// diam.Message.Unmarshal. Dx uses the same application and commands.
+//
+// SIPAuthDataItem carries the authentication vectors of IMS AKA, and
+// NewAKAItem and NewResyncItem build the items of MAAs and of MARs
+// requesting resynchronization.
+//
+// Example of an HSS emulator answering MARs:
+//
+//	mux.HandleFunc("MAR", func(c diam.Conn, m *diam.Message) {
+//		mar, err := cx.ParseMAR(m)
+//		if err != nil {
+//			return
+//		}
+//		maa := &cx.MAA{
+//			SessionID:        mar.SessionID,
+//			ResultCode:       diam.Success,
+//			AuthSessionState: cx.NoStateMaintained,
+//			OriginHost:       "hss.example.com",
+//			OriginRealm:      "example.com",
+//			UserName:         mar.UserName,
+//			PublicIdentity:   mar.PublicIdentity,
+//			SIPAuthDataItem: []*cx.SIPAuthDataItem{
+//				cx.NewAKAItem(1, rand, autn, xres, ck, ik),
+//			},
+//		}
+//		a, err := maa.Message(m)
+//		if err != nil {
+//			return
+//		}
+//		a.WriteTo(c)
+//	})
+package cx
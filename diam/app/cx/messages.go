@@ -0,0 +1,254 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package cx
+
+import (
+	"sort"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/app/internal/appmsg"
+	"github.com/omnicate/go-diameter/v4/diam/app/tgpp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// UAR is the User-Authorization-Request, see 3GPP TS 29.229 section 6.1.1.
+type UAR struct {
+	SessionID                   datatype.UTF8String               `avp:"Session-Id"`
+	VendorSpecificApplicationID *tgpp.VendorSpecificApplicationID `avp:"Vendor-Specific-Application-Id"`
+	AuthSessionState            int32                             `avp:"Auth-Session-State"`
+	OriginHost                  datatype.DiameterIdentity         `avp:"Origin-Host"`
+	OriginRealm                 datatype.DiameterIdentity         `avp:"Origin-Realm"`
+	DestinationHost             datatype.DiameterIdentity         `avp:"Destination-Host,omitempty"`
+	DestinationRealm            datatype.DiameterIdentity         `avp:"Destination-Realm"`
+	UserName                    string                            `avp:"User-Name"`
+	PublicIdentity              string                            `avp:"Public-Identity"`
+	VisitedNetworkIdentifier    []byte                            `avp:"Visited-Network-Identifier"`
+	UserAuthorizationType       *int32                            `avp:"User-Authorization-Type,omitempty"`
+	UARFlags                    uint32                            `avp:"UAR-Flags,omitempty"`
+}
+
+// Message returns a new User-Authorization-Request with the AVPs of r.
+func (r *UAR) Message() (*diam.Message, error) {
+	return appmsg.NewRequest(diam.UserAuthorization, ApplicationID, r)
+}
+
+// ParseUAR parses the User-Authorization-Request m.
+func ParseUAR(m *diam.Message) (*UAR, error) {
+	r := &UAR{}
+	if err := m.Unmarshal(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// UAA is the User-Authorization-Answer, see 3GPP TS 29.229 section 6.1.2.
+type UAA struct {
+	SessionID                   datatype.UTF8String               `avp:"Session-Id"`
+	VendorSpecificApplicationID *tgpp.VendorSpecificApplicationID `avp:"Vendor-Specific-Application-Id"`
+	ResultCode                  uint32                            `avp:"Result-Code,omitempty"`
+	ExperimentalResult          *tgpp.ExperimentalResult          `avp:"Experimental-Result,omitempty"`
+	AuthSessionState            int32                             `avp:"Auth-Session-State"`
+	OriginHost                  datatype.DiameterIdentity         `avp:"Origin-Host"`
+	OriginRealm                 datatype.DiameterIdentity         `avp:"Origin-Realm"`
+	ServerName                  string                            `avp:"Server-Name,omitempty"`
+	ServerCapabilities          *ServerCapabilities               `avp:"Server-Capabilities,omitempty"`
+}
+
+// Message returns a new answer to the request m with the AVPs of a.
+func (a *UAA) Message(m *diam.Message) (*diam.Message, error) {
+	return appmsg.NewAnswer(m, a)
+}
+
+// ParseUAA parses the User-Authorization-Answer m.
+func ParseUAA(m *diam.Message) (*UAA, error) {
+	a := &UAA{}
+	if err := m.Unmarshal(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// SAR is the Server-Assignment-Request, see 3GPP TS 29.229 section 6.1.3.
+type SAR struct {
+	SessionID                   datatype.UTF8String               `avp:"Session-Id"`
+	VendorSpecificApplicationID *tgpp.VendorSpecificApplicationID `avp:"Vendor-Specific-Application-Id"`
+	AuthSessionState            int32                             `avp:"Auth-Session-State"`
+	OriginHost                  datatype.DiameterIdentity         `avp:"Origin-Host"`
+	OriginRealm                 datatype.DiameterIdentity         `avp:"Origin-Realm"`
+	DestinationHost             datatype.DiameterIdentity         `avp:"Destination-Host,omitempty"`
+	DestinationRealm            datatype.DiameterIdentity         `avp:"Destination-Realm"`
+	UserName                    string                            `avp:"User-Name,omitempty"`
+	PublicIdentity              []string                          `avp:"Public-Identity,omitempty"`
+	ServerName                  string                            `avp:"Server-Name"`
+	ServerAssignmentType        int32                             `avp:"Server-Assignment-Type"`
+	UserDataAlreadyAvailable    int32                             `avp:"User-Data-Already-Available"`
+}
+
+// Message returns a new Server-Assignment-Request with the AVPs of r.
+func (r *SAR) Message() (*diam.Message, error) {
+	return appmsg.NewRequest(diam.ServerAssignment, ApplicationID, r)
+}
+
+// ParseSAR parses the Server-Assignment-Request m.
+func ParseSAR(m *diam.Message) (*SAR, error) {
+	r := &SAR{}
+	if err := m.Unmarshal(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// SAA is the Server-Assignment-Answer, see 3GPP TS 29.229 section 6.1.4.
+type SAA struct {
+	SessionID                   datatype.UTF8String               `avp:"Session-Id"`
+	VendorSpecificApplicationID *tgpp.VendorSpecificApplicationID `avp:"Vendor-Specific-Application-Id"`
+	ResultCode                  uint32                            `avp:"Result-Code,omitempty"`
+	ExperimentalResult          *tgpp.ExperimentalResult          `avp:"Experimental-Result,omitempty"`
+	AuthSessionState            int32                             `avp:"Auth-Session-State"`
+	OriginHost                  datatype.DiameterIdentity         `avp:"Origin-Host"`
+	OriginRealm                 datatype.DiameterIdentity         `avp:"Origin-Realm"`
+	UserName                    string                            `avp:"User-Name,omitempty"`
+	UserData                    []byte                            `avp:"User-Data,omitempty"`
+	ChargingInformation         *ChargingInformation              `avp:"Charging-Information,omitempty"`
+	ServerName                  string                            `avp:"Server-Name,omitempty"`
+}
+
+// Message returns a new answer to the request m with the AVPs of a.
+func (a *SAA) Message(m *diam.Message) (*diam.Message, error) {
+	return appmsg.NewAnswer(m, a)
+}
+
+// ParseSAA parses the Server-Assignment-Answer m.
+func ParseSAA(m *diam.Message) (*SAA, error) {
+	a := &SAA{}
+	if err := m.Unmarshal(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// LIR is the Location-Info-Request, see 3GPP TS 29.229 section 6.1.5.
+type LIR struct {
+	SessionID                   datatype.UTF8String               `avp:"Session-Id"`
+	VendorSpecificApplicationID *tgpp.VendorSpecificApplicationID `avp:"Vendor-Specific-Application-Id"`
+	AuthSessionState            int32                             `avp:"Auth-Session-State"`
+	OriginHost                  datatype.DiameterIdentity         `avp:"Origin-Host"`
+	OriginRealm                 datatype.DiameterIdentity         `avp:"Origin-Realm"`
+	DestinationHost             datatype.DiameterIdentity         `avp:"Destination-Host,omitempty"`
+	DestinationRealm            datatype.DiameterIdentity         `avp:"Destination-Realm"`
+	OriginatingRequest          *int32                            `avp:"Originating-Request,omitempty"`
+	PublicIdentity              string                            `avp:"Public-Identity"`
+	UserAuthorizationType       *int32                            `avp:"User-Authorization-Type,omitempty"`
+}
+
+// Message returns a new Location-Info-Request with the AVPs of r.
+func (r *LIR) Message() (*diam.Message, error) {
+	return appmsg.NewRequest(diam.LocationInfo, ApplicationID, r)
+}
+
+// ParseLIR parses the Location-Info-Request m.
+func ParseLIR(m *diam.Message) (*LIR, error) {
+	r := &LIR{}
+	if err := m.Unmarshal(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// LIA is the Location-Info-Answer, see 3GPP TS 29.229 section 6.1.6.
+type LIA struct {
+	SessionID                   datatype.UTF8String               `avp:"Session-Id"`
+	VendorSpecificApplicationID *tgpp.VendorSpecificApplicationID `avp:"Vendor-Specific-Application-Id"`
+	ResultCode                  uint32                            `avp:"Result-Code,omitempty"`
+	ExperimentalResult          *tgpp.ExperimentalResult          `avp:"Experimental-Result,omitempty"`
+	AuthSessionState            int32                             `avp:"Auth-Session-State"`
+	OriginHost                  datatype.DiameterIdentity         `avp:"Origin-Host"`
+	OriginRealm                 datatype.DiameterIdentity         `avp:"Origin-Realm"`
+	ServerName                  string                            `avp:"Server-Name,omitempty"`
+	ServerCapabilities          *ServerCapabilities               `avp:"Server-Capabilities,omitempty"`
+}
+
+// Message returns a new answer to the request m with the AVPs of a.
+func (a *LIA) Message(m *diam.Message) (*diam.Message, error) {
+	return appmsg.NewAnswer(m, a)
+}
+
+// ParseLIA parses the Location-Info-Answer m.
+func ParseLIA(m *diam.Message) (*LIA, error) {
+	a := &LIA{}
+	if err := m.Unmarshal(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// MAR is the Multimedia-Auth-Request, see 3GPP TS 29.229 section 6.1.7.
+type MAR struct {
+	SessionID                   datatype.UTF8String               `avp:"Session-Id"`
+	VendorSpecificApplicationID *tgpp.VendorSpecificApplicationID `avp:"Vendor-Specific-Application-Id"`
+	AuthSessionState            int32                             `avp:"Auth-Session-State"`
+	OriginHost                  datatype.DiameterIdentity         `avp:"Origin-Host"`
+	OriginRealm                 datatype.DiameterIdentity         `avp:"Origin-Realm"`
+	DestinationHost             datatype.DiameterIdentity         `avp:"Destination-Host,omitempty"`
+	DestinationRealm            datatype.DiameterIdentity         `avp:"Destination-Realm"`
+	UserName                    string                            `avp:"User-Name"`
+	PublicIdentity              string                            `avp:"Public-Identity"`
+	SIPAuthDataItem             *SIPAuthDataItem                  `avp:"SIP-Auth-Data-Item"`
+	SIPNumberAuthItems          uint32                            `avp:"SIP-Number-Auth-Items"`
+	ServerName                  string                            `avp:"Server-Name"`
+}
+
+// Message returns a new Multimedia-Auth-Request with the AVPs of r.
+func (r *MAR) Message() (*diam.Message, error) {
+	return appmsg.NewRequest(diam.MultimediaAuthentication, ApplicationID, r)
+}
+
+// ParseMAR parses the Multimedia-Auth-Request m.
+func ParseMAR(m *diam.Message) (*MAR, error) {
+	r := &MAR{}
+	if err := m.Unmarshal(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// MAA is the Multimedia-Auth-Answer, see 3GPP TS 29.229 section 6.1.8.
+type MAA struct {
+	SessionID                   datatype.UTF8String               `avp:"Session-Id"`
+	VendorSpecificApplicationID *tgpp.VendorSpecificApplicationID `avp:"Vendor-Specific-Application-Id"`
+	ResultCode                  uint32                            `avp:"Result-Code,omitempty"`
+	ExperimentalResult          *tgpp.ExperimentalResult          `avp:"Experimental-Result,omitempty"`
+	AuthSessionState            int32                             `avp:"Auth-Session-State"`
+	OriginHost                  datatype.DiameterIdentity         `avp:"Origin-Host"`
+	OriginRealm                 datatype.DiameterIdentity         `avp:"Origin-Realm"`
+	UserName                    string                            `avp:"User-Name,omitempty"`
+	PublicIdentity              string                            `avp:"Public-Identity,omitempty"`
+	SIPNumberAuthItems          uint32                            `avp:"SIP-Number-Auth-Items,omitempty"`
+	SIPAuthDataItem             []*SIPAuthDataItem                `avp:"SIP-Auth-Data-Item,omitempty"`
+}
+
+// Message returns a new answer to the request m with the AVPs of a.
+func (a *MAA) Message(m *diam.Message) (*diam.Message, error) {
+	return appmsg.NewAnswer(m, a)
+}
+
+// ParseMAA parses the Multimedia-Auth-Answer m.
+func ParseMAA(m *diam.Message) (*MAA, error) {
+	a := &MAA{}
+	if err := m.Unmarshal(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Items returns the SIP-Auth-Data-Items of the answer, sorted by
+// SIP-Item-Number.
+func (a *MAA) Items() []*SIPAuthDataItem {
+	items := append([]*SIPAuthDataItem{}, a.SIPAuthDataItem...)
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].SIPItemNumber < items[j].SIPItemNumber
+	})
+	return items
+}
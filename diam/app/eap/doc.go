@@ -0,0 +1,27 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package eap provides the Diameter EAP application, as specified by
+// RFC 4072.
+//
+// The DER and DEA types map the AVPs of the Diameter-EAP command to Go
+// structs. ParseDER and ParseDEA reassemble EAP packets split over
+// several EAP-Payload AVPs, e.g. by gateways relaying RADIUS EAP-Message
+// attributes, and Split and Reassemble convert between EAP packets and
+// such chunks. ResultStatus maps the Result-Code of a DEA to the state
+// of the authentication.
+//
+// The Server handles DERs and runs the EAP conversation of each
+// session. EAP methods such as EAP-SIM or EAP-AKA are plugged in by
+// implementing the Method interface:
+//
+//	srv := eap.NewServer("aaa.example.com", "example.com")
+//	srv.Register(eap.TypeAKA, func() eap.Method {
+//		return newAKA(hss)
+//	})
+//	mux.Handle("DER", srv)
+//
+// The Diameter EAP dictionary is part of dict.Default. AVPs it does not
+// define are looked up in the NASREQ dictionary.
+package eap
@@ -0,0 +1,106 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package eap
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+)
+
+// ApplicationID is the Diameter EAP Application ID.
+const ApplicationID = diam.DIAMETER_EAP_APP_ID
+
+// Values of the Auth-Request-Type AVP.
+const (
+	AuthenticateOnly      = 1
+	AuthorizeOnly         = 2
+	AuthorizeAuthenticate = 3
+)
+
+// EAP packet codes, see RFC 3748 section 4.
+const (
+	CodeRequest  = 1
+	CodeResponse = 2
+	CodeSuccess  = 3
+	CodeFailure  = 4
+)
+
+// EAP method types, see RFC 3748 section 5, RFC 4186, RFC 4187 and
+// RFC 5448.
+const (
+	TypeIdentity     = 1
+	TypeNotification = 2
+	TypeNak          = 3
+	TypeSIM          = 18
+	TypeAKA          = 23
+	TypeAKAPrime     = 50
+)
+
+// Status is the state of an EAP authentication.
+type Status int
+
+// EAP authentication states.
+const (
+	Continue Status = iota // More EAP round trips are needed
+	Success                // Authentication succeeded
+	Failure                // Authentication failed
+)
+
+var statuses = [...]string{"Continue", "Success", "Failure"}
+
+func (s Status) String() string {
+	if s >= 0 && int(s) < len(statuses) {
+		return statuses[s]
+	}
+	return "Status(" + strconv.Itoa(int(s)) + ")"
+}
+
+// ResultCode returns the Result-Code of a Diameter-EAP-Answer carrying
+// an EAP packet of the given status, see RFC 4072 section 2.
+func (s Status) ResultCode() uint32 {
+	switch s {
+	case Continue:
+		return diam.MultiRoundAuth
+	case Success:
+		return diam.Success
+	default:
+		return diam.AuthenticationRejected
+	}
+}
+
+// ResultStatus returns the status of an EAP authentication given the
+// Result-Code of a Diameter-EAP-Answer. DIAMETER_MULTI_ROUND_AUTH means
+// the EAP-Payload is the next EAP-Request, DIAMETER_SUCCESS that it is
+// an EAP-Success, and any other Result-Code that the authentication
+// failed.
+func ResultStatus(resultCode uint32) Status {
+	switch resultCode {
+	case diam.MultiRoundAuth:
+		return Continue
+	case diam.Success:
+		return Success
+	default:
+		return Failure
+	}
+}
+
+var (
+	// ErrShortPacket is returned when parsing an EAP packet shorter than
+	// its header or its Length field.
+	ErrShortPacket = errors.New("eap: short packet")
+
+	// ErrInvalidLength is returned when the Length field of an EAP
+	// packet does not match the data being parsed.
+	ErrInvalidLength = errors.New("eap: invalid packet length")
+
+	// ErrPacketTooLong is returned when encoding an EAP packet longer
+	// than its 16-bit Length field allows.
+	ErrPacketTooLong = errors.New("eap: packet too long")
+
+	// ErrNoPayload is returned when a message has no EAP-Payload AVP.
+	ErrNoPayload = errors.New("eap: missing EAP-Payload")
+)
@@ -0,0 +1,262 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package eap
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+)
+
+// encode returns the encoded packet p.
+func encode(t *testing.T, p *Packet) []byte {
+	b, err := p.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestPacket(t *testing.T) {
+	p := NewResponse(7, TypeIdentity, []byte("user@example.com"))
+	b := encode(t, p)
+	if len(b) != 5+16 || b[0] != CodeResponse || b[1] != 7 || b[3] != byte(len(b)) {
+		t.Fatalf("Unexpected packet: %x", b)
+	}
+	q, err := ParsePacket(append(b, 0, 0)) // trailing padding
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Code != p.Code || q.Identifier != 7 || q.Type != TypeIdentity || string(q.Data) != "user@example.com" {
+		t.Fatalf("Unexpected packet: %s", q)
+	}
+	s := encode(t, &Packet{Code: CodeSuccess, Identifier: 7})
+	if !bytes.Equal(s, []byte{CodeSuccess, 7, 0, 4}) {
+		t.Fatalf("Unexpected EAP-Success: %x", s)
+	}
+	for _, b := range [][]byte{{1, 1, 0}, {1, 1, 0, 8, 1}, {1, 1, 0, 4}, {3, 1, 0, 5, 0}, {9, 1, 0, 4}} {
+		if _, err := ParsePacket(b); err == nil {
+			t.Fatalf("Unexpected success parsing %x", b)
+		}
+	}
+}
+
+func TestPacket_TooLong(t *testing.T) {
+	if _, err := NewRequest(1, TypeAKA, make([]byte, maxLen-5)).Bytes(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewRequest(1, TypeAKA, make([]byte, maxLen-4)).Bytes(); err != ErrPacketTooLong {
+		t.Fatalf("Unexpected error. Want %v, have %v", ErrPacketTooLong, err)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	b := encode(t, NewRequest(1, TypeAKA, make([]byte, 600)))
+	chunks := Split(b, 0)
+	if len(chunks) != 3 || len(chunks[0]) != MaxChunkSize || len(chunks[2]) != len(b)-2*MaxChunkSize {
+		t.Fatalf("Unexpected chunks: %d", len(chunks))
+	}
+	r, err := Reassemble(chunks...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(r, b) {
+		t.Fatal("Unexpected reassembled packet")
+	}
+	if r, err = Reassemble(append(chunks, []byte{0, 0})...); err != nil || !bytes.Equal(r, b) {
+		t.Fatalf("Unexpected packet with trailing padding: %v", err)
+	}
+	if _, err = Reassemble(chunks[:2]...); err != ErrShortPacket {
+		t.Fatalf("Unexpected error. Want %v, have %v", ErrShortPacket, err)
+	}
+}
+
+func TestResultStatus(t *testing.T) {
+	for code, want := range map[uint32]Status{
+		diam.MultiRoundAuth:         Continue,
+		diam.Success:                Success,
+		diam.AuthenticationRejected: Failure,
+		diam.UnableToComply:         Failure,
+	} {
+		if s := ResultStatus(code); s != want {
+			t.Fatalf("Unexpected status of %d. Want %s, have %s", code, want, s)
+		}
+	}
+	for _, s := range []Status{Continue, Success, Failure} {
+		if ResultStatus(s.ResultCode()) != s {
+			t.Fatalf("Unexpected Result-Code of %s: %d", s, s.ResultCode())
+		}
+	}
+}
+
+func TestParseDER(t *testing.T) {
+	der := &DER{
+		SessionID:         "nas;1;1",
+		AuthApplicationID: ApplicationID,
+		OriginHost:        "nas",
+		OriginRealm:       "localhost",
+		DestinationRealm:  "localhost",
+		AuthRequestType:   AuthorizeAuthenticate,
+		UserName:          "user",
+	}
+	m, err := der.Message()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Header.CommandCode != diam.DiameterEAP || m.Header.ApplicationID != ApplicationID {
+		t.Fatalf("Unexpected header: %s", m.Header)
+	}
+	b := encode(t, NewResponse(2, TypeAKA, make([]byte, 400)))
+	for _, c := range Split(b, 0) {
+		m.NewAVP(avp.EAPPayload, avp.Mbit, 0, datatype.OctetString(c))
+	}
+	r, err := ParseDER(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(r.EAPPayload, b) || r.UserName != "user" || r.AuthRequestType != AuthorizeAuthenticate {
+		t.Fatalf("Unexpected DER: %+v", r)
+	}
+	if p, err := r.Packet(); err != nil || p.Type != TypeAKA || p.Identifier != 2 {
+		t.Fatalf("Unexpected packet: %v, %v", p, err)
+	}
+}
+
+// method is a test Method that succeeds when the peer echoes its
+// challenge.
+type method struct {
+	typ       uint8
+	challenge []byte
+}
+
+func (t *method) Type() uint8 { return t.typ }
+
+func (t *method) Start(identity string) (*Packet, error) {
+	return &Packet{Type: t.typ, Data: t.challenge}, nil
+}
+
+func (t *method) Process(resp *Packet) (*Packet, Status, error) {
+	if bytes.Equal(resp.Data, t.challenge) {
+		return nil, Success, nil
+	}
+	return nil, Failure, nil
+}
+
+func (t *method) MSK() []byte { return []byte("msk") }
+
+func dial(t *testing.T, srv *diamtest.Server) diam.Conn {
+	c, err := diam.Dial(srv.Addr, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func sendDER(t *testing.T, c diam.Conn, id datatype.UTF8String, p *Packet) *DEA {
+	der := &DER{
+		SessionID:         id,
+		AuthApplicationID: ApplicationID,
+		OriginHost:        "nas",
+		OriginRealm:       "localhost",
+		DestinationRealm:  "localhost",
+		AuthRequestType:   AuthorizeAuthenticate,
+	}
+	if p != nil {
+		der.EAPPayload = encode(t, p)
+	}
+	m, err := der.Message()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	a, err := diam.SendRequest(ctx, c, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dea, err := ParseDEA(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dea
+}
+
+func TestServer(t *testing.T) {
+	srv := NewServer("aaa", "localhost")
+	srv.Register(TypeAKAPrime, func() Method { return &method{typ: TypeAKAPrime, challenge: []byte("aka'")} })
+	srv.Register(TypeAKA, func() Method { return &method{typ: TypeAKA, challenge: []byte("aka")} })
+	ts := diamtest.NewServer(srv, nil)
+	defer ts.Close()
+	c := dial(t, ts)
+	defer c.Close()
+
+	// Identity request.
+	dea := sendDER(t, c, "nas;1;1", nil)
+	p, err := dea.Packet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dea.Status() != Continue || p.Code != CodeRequest || p.Type != TypeIdentity {
+		t.Fatalf("Unexpected answer: %+v, %s", dea, p)
+	}
+	// First registered method.
+	dea = sendDER(t, c, "nas;1;1", NewResponse(p.Identifier, TypeIdentity, []byte("user")))
+	if p, err = dea.Packet(); err != nil {
+		t.Fatal(err)
+	}
+	if dea.Status() != Continue || p.Code != CodeRequest || p.Type != TypeAKAPrime {
+		t.Fatalf("Unexpected answer: %+v, %s", dea, p)
+	}
+	// Nak, the peer wants EAP-AKA.
+	dea = sendDER(t, c, "nas;1;1", NewResponse(p.Identifier, TypeNak, []byte{TypeAKA}))
+	if p, err = dea.Packet(); err != nil {
+		t.Fatal(err)
+	}
+	if dea.Status() != Continue || p.Type != TypeAKA || string(p.Data) != "aka" {
+		t.Fatalf("Unexpected answer: %+v, %s", dea, p)
+	}
+	if s, ok := srv.Session("nas;1;1"); !ok || s.Identity() != "user" || s.Method().Type() != TypeAKA {
+		t.Fatal("Unexpected session state")
+	}
+	dea = sendDER(t, c, "nas;1;1", NewResponse(p.Identifier, TypeAKA, []byte("aka")))
+	if p, err = dea.Packet(); err != nil {
+		t.Fatal(err)
+	}
+	if dea.Status() != Success || p.Code != CodeSuccess || string(dea.EAPMasterSessionKey) != "msk" {
+		t.Fatalf("Unexpected answer: %+v, %s", dea, p)
+	}
+	if len(dea.AccountingEAPAuthMethod) != 1 || dea.AccountingEAPAuthMethod[0] != TypeAKA {
+		t.Fatalf("Unexpected Accounting-EAP-Auth-Method: %v", dea.AccountingEAPAuthMethod)
+	}
+
+	// Failed authentication, starting with the identity.
+	dea = sendDER(t, c, "nas;1;2", NewResponse(1, TypeIdentity, []byte("user")))
+	if p, err = dea.Packet(); err != nil {
+		t.Fatal(err)
+	}
+	dea = sendDER(t, c, "nas;1;2", NewResponse(p.Identifier, TypeAKAPrime, []byte("bad")))
+	if p, err = dea.Packet(); err != nil {
+		t.Fatal(err)
+	}
+	if dea.Status() != Failure || dea.ResultCode != diam.AuthenticationRejected || p.Code != CodeFailure {
+		t.Fatalf("Unexpected answer: %+v, %s", dea, p)
+	}
+	if _, ok := srv.Session("nas;1;2"); ok {
+		t.Fatal("Unexpected session after failure")
+	}
+
+	// Unknown session.
+	dea = sendDER(t, c, "nas;1;3", NewResponse(1, TypeAKA, nil))
+	if dea.ResultCode != diam.UnknownSessionID {
+		t.Fatalf("Unexpected Result-Code: %d", dea.ResultCode)
+	}
+}
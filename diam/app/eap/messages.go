@@ -0,0 +1,135 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package eap
+
+import (
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/app/internal/appmsg"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// payload returns the EAP packet carried by the EAP-Payload AVPs of m.
+// Peers relaying RADIUS EAP-Message attributes may split a packet over
+// several AVPs, which are reassembled.
+func payload(m *diam.Message) ([]byte, error) {
+	avps, err := m.FindAVPs(avp.EAPPayload, 0)
+	if err != nil || len(avps) == 0 {
+		return nil, ErrNoPayload
+	}
+	if len(avps) == 1 {
+		b, _ := avps[0].Data.(datatype.OctetString)
+		return []byte(b), nil
+	}
+	chunks := make([][]byte, len(avps))
+	for i, a := range avps {
+		b, _ := a.Data.(datatype.OctetString)
+		chunks[i] = []byte(b)
+	}
+	return Reassemble(chunks...)
+}
+
+// DER is the Diameter-EAP-Request, see RFC 4072 section 3.1.
+type DER struct {
+	SessionID         datatype.UTF8String       `avp:"Session-Id"`
+	AuthApplicationID uint32                    `avp:"Auth-Application-Id"`
+	OriginHost        datatype.DiameterIdentity `avp:"Origin-Host"`
+	OriginRealm       datatype.DiameterIdentity `avp:"Origin-Realm"`
+	DestinationRealm  datatype.DiameterIdentity `avp:"Destination-Realm"`
+	AuthRequestType   int32                     `avp:"Auth-Request-Type"`
+	EAPPayload        []byte                    `avp:"EAP-Payload"`
+	DestinationHost   datatype.DiameterIdentity `avp:"Destination-Host,omitempty"`
+	UserName          string                    `avp:"User-Name,omitempty"`
+	EAPKeyName        []byte                    `avp:"EAP-Key-Name,omitempty"`
+	State             []byte                    `avp:"State,omitempty"`
+	CalledStationID   string                    `avp:"Called-Station-Id,omitempty"`
+	CallingStationID  string                    `avp:"Calling-Station-Id,omitempty"`
+	FramedMTU         uint32                    `avp:"Framed-MTU,omitempty"`
+}
+
+// Message returns a new Diameter-EAP-Request with the AVPs of r.
+func (r *DER) Message() (*diam.Message, error) {
+	return appmsg.NewRequest(diam.DiameterEAP, ApplicationID, r)
+}
+
+// Packet parses the EAP packet of the request. An empty EAP-Payload,
+// sent by the NAS to start the authentication, returns a nil Packet.
+func (r *DER) Packet() (*Packet, error) {
+	if len(r.EAPPayload) == 0 {
+		return nil, nil
+	}
+	return ParsePacket(r.EAPPayload)
+}
+
+// ParseDER parses the Diameter-EAP-Request m, reassembling its
+// EAP-Payload if needed.
+func ParseDER(m *diam.Message) (*DER, error) {
+	r := &DER{}
+	if err := m.Unmarshal(r); err != nil {
+		return nil, err
+	}
+	b, err := payload(m)
+	if err != nil {
+		return nil, err
+	}
+	r.EAPPayload = b
+	return r, nil
+}
+
+// DEA is the Diameter-EAP-Answer, see RFC 4072 section 3.2.
+type DEA struct {
+	SessionID               datatype.UTF8String       `avp:"Session-Id"`
+	AuthApplicationID       uint32                    `avp:"Auth-Application-Id"`
+	AuthRequestType         int32                     `avp:"Auth-Request-Type"`
+	ResultCode              uint32                    `avp:"Result-Code"`
+	OriginHost              datatype.DiameterIdentity `avp:"Origin-Host"`
+	OriginRealm             datatype.DiameterIdentity `avp:"Origin-Realm"`
+	UserName                string                    `avp:"User-Name,omitempty"`
+	EAPPayload              []byte                    `avp:"EAP-Payload,omitempty"`
+	EAPReissuedPayload      []byte                    `avp:"EAP-Reissued-Payload,omitempty"`
+	EAPMasterSessionKey     []byte                    `avp:"EAP-Master-Session-Key,omitempty"`
+	EAPKeyName              []byte                    `avp:"EAP-Key-Name,omitempty"`
+	MultiRoundTimeOut       uint32                    `avp:"Multi-Round-Time-Out,omitempty"`
+	AccountingEAPAuthMethod []uint64                  `avp:"Accounting-EAP-Auth-Method,omitempty"`
+	ErrorMessage            string                    `avp:"Error-Message,omitempty"`
+	AuthorizationLifetime   uint32                    `avp:"Authorization-Lifetime,omitempty"`
+	SessionTimeout          uint32                    `avp:"Session-Timeout,omitempty"`
+	State                   []byte                    `avp:"State,omitempty"`
+}
+
+// Message returns a new answer to the request m with the AVPs of a.
+func (a *DEA) Message(m *diam.Message) (*diam.Message, error) {
+	return appmsg.NewAnswer(m, a)
+}
+
+// Status returns the state of the authentication given the Result-Code
+// of the answer.
+func (a *DEA) Status() Status {
+	return ResultStatus(a.ResultCode)
+}
+
+// Packet parses the EAP packet of the answer, or returns nil if the
+// answer has no EAP-Payload.
+func (a *DEA) Packet() (*Packet, error) {
+	if len(a.EAPPayload) == 0 {
+		return nil, nil
+	}
+	return ParsePacket(a.EAPPayload)
+}
+
+// ParseDEA parses the Diameter-EAP-Answer m, reassembling its
+// EAP-Payload if needed.
+func ParseDEA(m *diam.Message) (*DEA, error) {
+	a := &DEA{}
+	if err := m.Unmarshal(a); err != nil {
+		return nil, err
+	}
+	if b, err := payload(m); err == nil {
+		a.EAPPayload = b
+	} else if err != ErrNoPayload {
+		return nil, err
+	}
+	return a, nil
+}
@@ -0,0 +1,31 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package eap
+
+// Method is the server side state machine of an EAP method, such as
+// EAP-SIM or EAP-AKA, for a single authentication.
+//
+// The Server assigns the Code and Identifier of the EAP-Requests
+// returned by the Method, which only need to set their Type and Data.
+type Method interface {
+	// Type returns the EAP method type, e.g. TypeAKA.
+	Type() uint8
+
+	// Start returns the first EAP-Request of the method for the peer
+	// with the given identity, as received in its EAP-Response/Identity.
+	Start(identity string) (*Packet, error)
+
+	// Process handles an EAP-Response of the method type. It returns
+	// the next EAP-Request and Continue, or a nil Packet with Success
+	// or Failure when the authentication is over.
+	Process(resp *Packet) (*Packet, Status, error)
+
+	// MSK returns the Master Session Key derived by the method, which
+	// is only valid after Process returned Success.
+	MSK() []byte
+}
+
+// MethodFactory returns a new Method for an authentication.
+type MethodFactory func() Method
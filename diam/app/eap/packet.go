@@ -0,0 +1,146 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package eap
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// MaxChunkSize is the maximum size of the chunks of an EAP packet
+// carried in RADIUS EAP-Message attributes, see RFC 3579 section 3.1.
+const MaxChunkSize = 253
+
+// headerLen is the length of the Code, Identifier and Length fields.
+const headerLen = 4
+
+// Packet is an EAP packet, see RFC 3748 section 4.
+type Packet struct {
+	Code       uint8
+	Identifier uint8
+
+	// Type and Data are only present in EAP-Request and EAP-Response
+	// packets.
+	Type uint8
+	Data []byte
+}
+
+// NewRequest returns an EAP-Request of the given method type.
+func NewRequest(id, typ uint8, data []byte) *Packet {
+	return &Packet{Code: CodeRequest, Identifier: id, Type: typ, Data: data}
+}
+
+// NewResponse returns an EAP-Response of the given method type.
+func NewResponse(id, typ uint8, data []byte) *Packet {
+	return &Packet{Code: CodeResponse, Identifier: id, Type: typ, Data: data}
+}
+
+// ParsePacket parses the EAP packet b. Bytes past its Length field are
+// ignored, as required by RFC 3748.
+func ParsePacket(b []byte) (*Packet, error) {
+	if len(b) < headerLen {
+		return nil, ErrShortPacket
+	}
+	n := int(binary.BigEndian.Uint16(b[2:4]))
+	if n < headerLen {
+		return nil, ErrInvalidLength
+	}
+	if len(b) < n {
+		return nil, ErrShortPacket
+	}
+	p := &Packet{Code: b[0], Identifier: b[1]}
+	switch p.Code {
+	case CodeRequest, CodeResponse:
+		if n < headerLen+1 {
+			return nil, ErrInvalidLength
+		}
+		p.Type = b[4]
+		p.Data = append([]byte(nil), b[5:n]...)
+	case CodeSuccess, CodeFailure:
+		if n != headerLen {
+			return nil, ErrInvalidLength
+		}
+	default:
+		return nil, fmt.Errorf("eap: unknown packet code %d", p.Code)
+	}
+	return p, nil
+}
+
+// Len returns the length of the encoded packet.
+func (p *Packet) Len() int {
+	if p.Code == CodeSuccess || p.Code == CodeFailure {
+		return headerLen
+	}
+	return headerLen + 1 + len(p.Data)
+}
+
+// maxLen is the maximum length of an EAP packet, limited by its 16-bit
+// Length field.
+const maxLen = 1<<16 - 1
+
+// Bytes returns the encoded packet, or ErrPacketTooLong if its length
+// does not fit in the Length field.
+func (p *Packet) Bytes() ([]byte, error) {
+	if p.Len() > maxLen {
+		return nil, ErrPacketTooLong
+	}
+	b := make([]byte, p.Len())
+	b[0] = p.Code
+	b[1] = p.Identifier
+	binary.BigEndian.PutUint16(b[2:4], uint16(len(b)))
+	if len(b) > headerLen {
+		b[4] = p.Type
+		copy(b[5:], p.Data)
+	}
+	return b, nil
+}
+
+func (p *Packet) String() string {
+	return fmt.Sprintf("{Code:%d,Identifier:%d,Type:%d,Length:%d}",
+		p.Code, p.Identifier, p.Type, p.Len())
+}
+
+// Split splits the EAP packet b in chunks of at most size bytes, such
+// as the EAP-Message attributes of RADIUS. The chunks share the memory
+// of b.
+func Split(b []byte, size int) [][]byte {
+	if size <= 0 {
+		size = MaxChunkSize
+	}
+	chunks := make([][]byte, 0, (len(b)+size-1)/size)
+	for len(b) > size {
+		chunks = append(chunks, b[:size])
+		b = b[size:]
+	}
+	if len(b) > 0 {
+		chunks = append(chunks, b)
+	}
+	return chunks
+}
+
+// Reassemble concatenates the chunks of an EAP packet and checks them
+// against the Length field of its header. As in ParsePacket, bytes past
+// the Length field are ignored and not returned.
+func Reassemble(chunks ...[]byte) ([]byte, error) {
+	var n int
+	for _, c := range chunks {
+		n += len(c)
+	}
+	b := make([]byte, 0, n)
+	for _, c := range chunks {
+		b = append(b, c...)
+	}
+	if len(b) < headerLen {
+		return nil, ErrShortPacket
+	}
+	n = int(binary.BigEndian.Uint16(b[2:4]))
+	if n < headerLen {
+		return nil, ErrInvalidLength
+	}
+	if len(b) < n {
+		return nil, ErrShortPacket
+	}
+	return b[:n], nil
+}
@@ -0,0 +1,272 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package eap
+
+import (
+	"sync"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/session"
+)
+
+// ServerSession is the state of an EAP authentication on the Server.
+type ServerSession struct {
+	*session.Session
+
+	mu       sync.Mutex // guards the following
+	identity string
+	method   Method
+	id       uint8 // Identifier of the last EAP-Request
+	tried    map[uint8]bool
+}
+
+// Identity returns the identity sent by the peer in its
+// EAP-Response/Identity.
+func (s *ServerSession) Identity() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.identity
+}
+
+// Method returns the EAP method of the session, or nil if none was
+// selected yet.
+func (s *ServerSession) Method() Method {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.method
+}
+
+// Server is a Diameter EAP server.
+//
+// It implements the diam.Handler interface for DERs, and runs the EAP
+// conversation of each session: it requests the identity of the peer
+// when the first DER has an empty EAP-Payload, starts the first method
+// registered that the peer did not refuse with a Nak, and passes the
+// following EAP-Responses to the method until it succeeds or fails.
+type Server struct {
+	OriginHost  datatype.DiameterIdentity
+	OriginRealm datatype.DiameterIdentity
+
+	// MultiRoundTimeOut, if not zero, is sent in DEAs carrying an
+	// EAP-Request, in seconds.
+	MultiRoundTimeOut uint32
+
+	sessions *session.Manager
+	mu       sync.RWMutex // guards the following
+	methods  map[uint8]MethodFactory
+	order    []uint8
+}
+
+// NewServer creates and initializes a Server.
+func NewServer(host, realm datatype.DiameterIdentity) *Server {
+	return &Server{
+		OriginHost:  host,
+		OriginRealm: realm,
+		sessions:    session.NewManager(host, realm),
+		methods:     make(map[uint8]MethodFactory),
+	}
+}
+
+// Register registers the factory of the EAP method of the given type.
+// Methods are proposed to peers in the order they were registered.
+func (srv *Server) Register(typ uint8, f MethodFactory) {
+	srv.mu.Lock()
+	if _, ok := srv.methods[typ]; !ok {
+		srv.order = append(srv.order, typ)
+	}
+	srv.methods[typ] = f
+	srv.mu.Unlock()
+}
+
+// Sessions returns the session manager of the Server.
+func (srv *Server) Sessions() *session.Manager {
+	return srv.sessions
+}
+
+// Session returns the state of the session with the given Session-Id.
+func (srv *Server) Session(id datatype.UTF8String) (*ServerSession, bool) {
+	s, ok := srv.sessions.Get(id)
+	if !ok {
+		return nil, false
+	}
+	ss, ok := s.Value().(*ServerSession)
+	return ss, ok
+}
+
+// ServeDIAM implements the diam.Handler interface.
+func (srv *Server) ServeDIAM(c diam.Conn, m *diam.Message) {
+	der, err := ParseDER(m)
+	if err != nil {
+		diam.LoggerOf(c).Log(diam.LevelWarn, "failed to parse DER", "remote", c.RemoteAddr(), "err", err)
+		srv.answer(c, m, &DEA{ResultCode: diam.UnableToComply}, nil)
+		return
+	}
+	dea := &DEA{AuthRequestType: der.AuthRequestType, UserName: der.UserName}
+	p, err := der.Packet()
+	if err != nil {
+		dea.ResultCode = diam.InvalidAVPValue
+		srv.answer(c, m, dea, nil)
+		return
+	}
+	s, ok := srv.Session(der.SessionID)
+	if !ok {
+		if p != nil && (p.Code != CodeResponse || p.Type != TypeIdentity) {
+			dea.ResultCode = diam.UnknownSessionID
+			srv.answer(c, m, dea, nil)
+			return
+		}
+		s = srv.newSession(srv.sessions.Add(der.SessionID))
+	}
+	next, status, msk := srv.process(diam.LoggerOf(c), s, p)
+	dea.ResultCode = status.ResultCode()
+	switch status {
+	case Continue:
+		if srv.MultiRoundTimeOut != 0 {
+			dea.MultiRoundTimeOut = srv.MultiRoundTimeOut
+		}
+	case Success:
+		dea.EAPMasterSessionKey = msk
+		if t := s.Method(); t != nil {
+			dea.AccountingEAPAuthMethod = []uint64{uint64(t.Type())}
+		}
+		s.SetState(session.Open)
+	default:
+		s.SetState(session.Closed)
+	}
+	srv.answer(c, m, dea, next)
+}
+
+// newSession initializes the state of the session s.
+func (srv *Server) newSession(s *session.Session) *ServerSession {
+	if ss, ok := s.Value().(*ServerSession); ok {
+		return ss
+	}
+	ss := &ServerSession{Session: s, tried: make(map[uint8]bool)}
+	s.SetValue(ss)
+	return ss
+}
+
+// process runs the EAP conversation of the session with the EAP packet
+// p received from the peer, or nil if none was received. It returns the
+// EAP packet of the answer with the state of the authentication, and
+// the MSK on success. Failures of methods are logged to l.
+func (srv *Server) process(l diam.Logger, s *ServerSession, p *Packet) (*Packet, Status, []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p == nil {
+		s.id++
+		return NewRequest(s.id, TypeIdentity, nil), Continue, nil
+	}
+	if p.Code != CodeResponse || (s.id != 0 && p.Identifier != s.id) {
+		return s.failure(), Failure, nil
+	}
+	s.id = p.Identifier
+	switch {
+	case p.Type == TypeIdentity && s.method == nil:
+		s.identity = string(p.Data)
+		return s.start(l, srv.choose(s, nil))
+	case p.Type == TypeNak && s.method != nil:
+		return s.start(l, srv.choose(s, p.Data))
+	case s.method != nil && p.Type == s.method.Type():
+		next, status, err := s.method.Process(p)
+		if err != nil {
+			l.Log(diam.LevelWarn, "EAP method failed", "method", p.Type, "session", s.ID(), "err", err)
+			return s.failure(), Failure, nil
+		}
+		switch status {
+		case Continue:
+			if next == nil {
+				return s.failure(), Failure, nil
+			}
+			return s.request(next), Continue, nil
+		case Success:
+			return &Packet{Code: CodeSuccess, Identifier: s.id}, Success, s.method.MSK()
+		}
+	}
+	return s.failure(), Failure, nil
+}
+
+// choose returns a new instance of the first registered method not yet
+// tried in the session. If desired is not nil, the method must be one
+// of the types it lists, as in the Type-Data of a Nak.
+func (srv *Server) choose(s *ServerSession, desired []byte) Method {
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	for _, typ := range srv.order {
+		if s.tried[typ] {
+			continue
+		}
+		if desired != nil && !containsType(desired, typ) {
+			continue
+		}
+		return srv.methods[typ]()
+	}
+	return nil
+}
+
+func containsType(types []byte, typ uint8) bool {
+	for _, t := range types {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// start starts the method t, or fails the authentication if it is nil.
+// It must be called with s.mu held. Failures are logged to l.
+func (s *ServerSession) start(l diam.Logger, t Method) (*Packet, Status, []byte) {
+	if t == nil {
+		return s.failure(), Failure, nil
+	}
+	s.method = t
+	s.tried[t.Type()] = true
+	p, err := t.Start(s.identity)
+	if err != nil || p == nil {
+		l.Log(diam.LevelWarn, "failed to start EAP method", "method", t.Type(), "session", s.ID(), "err", err)
+		return s.failure(), Failure, nil
+	}
+	return s.request(p), Continue, nil
+}
+
+// request sets the Code and next Identifier of the EAP-Request p. It
+// must be called with s.mu held.
+func (s *ServerSession) request(p *Packet) *Packet {
+	s.id++
+	p.Code = CodeRequest
+	p.Identifier = s.id
+	return p
+}
+
+// failure returns an EAP-Failure. It must be called with s.mu held.
+func (s *ServerSession) failure() *Packet {
+	return &Packet{Code: CodeFailure, Identifier: s.id}
+}
+
+// answer writes the Diameter-EAP-Answer a to the request m, with the
+// EAP packet p.
+func (srv *Server) answer(c diam.Conn, m *diam.Message, a *DEA, p *Packet) {
+	if v, ok := session.ID(m); ok {
+		a.SessionID = v
+	}
+	a.AuthApplicationID = ApplicationID
+	a.OriginHost = srv.OriginHost
+	a.OriginRealm = srv.OriginRealm
+	var err error
+	if p != nil {
+		a.EAPPayload, err = p.Bytes()
+	}
+	var ans *diam.Message
+	if err == nil {
+		ans, err = a.Message(m)
+	}
+	if err == nil {
+		_, err = ans.WriteTo(c)
+	}
+	if err != nil {
+		diam.LoggerOf(c).Log(diam.LevelError, "failed to send DEA", "remote", c.RemoteAddr(), "err", err)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package gx
+
+import (
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam/app/creditcontrol"
+)
+
+// ChargingRuleInstall is the Charging-Rule-Install AVP, see 3GPP TS
+// 29.212 section 5.3.2.
+type ChargingRuleInstall struct {
+	ChargingRuleDefinition []*ChargingRuleDefinition `avp:"Charging-Rule-Definition,omitempty"`
+	ChargingRuleName       []string                  `avp:"Charging-Rule-Name,omitempty"`
+	ChargingRuleBaseName   []string                  `avp:"Charging-Rule-Base-Name,omitempty"`
+	RuleActivationTime     *time.Time                `avp:"Rule-Activation-Time,omitempty"`
+	RuleDeactivationTime   *time.Time                `avp:"Rule-Deactivation-Time,omitempty"`
+}
+
+// ChargingRuleRemove is the Charging-Rule-Remove AVP, see 3GPP TS
+// 29.212 section 5.3.3.
+type ChargingRuleRemove struct {
+	ChargingRuleName     []string `avp:"Charging-Rule-Name,omitempty"`
+	ChargingRuleBaseName []string `avp:"Charging-Rule-Base-Name,omitempty"`
+}
+
+// ChargingRuleDefinition is the Charging-Rule-Definition AVP, see 3GPP
+// TS 29.212 section 5.3.4.
+type ChargingRuleDefinition struct {
+	ChargingRuleName  string             `avp:"Charging-Rule-Name"`
+	ServiceIdentifier *uint32            `avp:"Service-Identifier,omitempty"`
+	RatingGroup       *uint32            `avp:"Rating-Group,omitempty"`
+	FlowInformation   []*FlowInformation `avp:"Flow-Information,omitempty"`
+	QoSInformation    *QoSInformation    `avp:"QoS-Information,omitempty"`
+	Precedence        *uint32            `avp:"Precedence,omitempty"`
+	MonitoringKey     string             `avp:"Monitoring-Key,omitempty"`
+}
+
+// FlowInformation is the Flow-Information AVP, see 3GPP TS 29.212
+// section 5.3.53.
+type FlowInformation struct {
+	FlowDescription        string `avp:"Flow-Description,omitempty"`
+	PacketFilterIdentifier string `avp:"Packet-Filter-Identifier,omitempty"`
+	FlowDirection          *int32 `avp:"Flow-Direction,omitempty"`
+}
+
+// QoSInformation is the QoS-Information AVP, see 3GPP TS 29.212
+// section 5.3.16.
+type QoSInformation struct {
+	QoSClassIdentifier          *int32                       `avp:"QoS-Class-Identifier,omitempty"`
+	MaxRequestedBandwidthUL     uint32                       `avp:"Max-Requested-Bandwidth-UL,omitempty"`
+	MaxRequestedBandwidthDL     uint32                       `avp:"Max-Requested-Bandwidth-DL,omitempty"`
+	AllocationRetentionPriority *AllocationRetentionPriority `avp:"Allocation-Retention-Priority,omitempty"`
+	APNAggregateMaxBitrateUL    uint32                       `avp:"APN-Aggregate-Max-Bitrate-UL,omitempty"`
+	APNAggregateMaxBitrateDL    uint32                       `avp:"APN-Aggregate-Max-Bitrate-DL,omitempty"`
+}
+
+// AllocationRetentionPriority is the Allocation-Retention-Priority AVP,
+// see 3GPP TS 29.212 section 5.3.32.
+type AllocationRetentionPriority struct {
+	PriorityLevel           uint32 `avp:"Priority-Level"`
+	PreemptionCapability    *int32 `avp:"Pre-emption-Capability,omitempty"`
+	PreemptionVulnerability *int32 `avp:"Pre-emption-Vulnerability,omitempty"`
+}
+
+// DefaultEPSBearerQoS is the Default-EPS-Bearer-QoS AVP, see 3GPP TS
+// 29.212 section 5.3.48.
+type DefaultEPSBearerQoS struct {
+	QoSClassIdentifier          *int32                       `avp:"QoS-Class-Identifier,omitempty"`
+	AllocationRetentionPriority *AllocationRetentionPriority `avp:"Allocation-Retention-Priority,omitempty"`
+}
+
+// UsageMonitoringInformation is the Usage-Monitoring-Information AVP,
+// see 3GPP TS 29.212 section 5.3.60.
+type UsageMonitoringInformation struct {
+	MonitoringKey        string                       `avp:"Monitoring-Key,omitempty"`
+	GrantedServiceUnit   []*creditcontrol.ServiceUnit `avp:"Granted-Service-Unit,omitempty"`
+	UsedServiceUnit      []*creditcontrol.ServiceUnit `avp:"Used-Service-Unit,omitempty"`
+	UsageMonitoringLevel *int32                       `avp:"Usage-Monitoring-Level,omitempty"`
+}
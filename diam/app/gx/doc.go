@@ -0,0 +1,39 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package gx provides typed messages of the 3GPP Gx interface, between
+// the PCEF and the PCRF, as specified by 3GPP TS 29.212.
+//
+// The CCR, CCA, RAR and RAA types map the AVPs of their commands,
+// including grouped AVPs such as Charging-Rule-Install,
+// Charging-Rule-Remove and QoS-Information, to Go structs. Their
+// Message methods build a diam.Message with diam.Message.Marshal, and
+// the Parse functions decode one with diam.Message.Unmarshal.
+//
+// Example of a PCRF answering CCR-Initial with a charging rule:
+//
+//	mux.HandleFunc("CCR", func(c diam.Conn, m *diam.Message) {
+//		ccr, err := gx.ParseCCR(m)
+//		if err != nil {
+//			return
+//		}
+//		cca := &gx.CCA{
+//			SessionID:         ccr.SessionID,
+//			ResultCode:        diam.Success,
+//			OriginHost:        "pcrf.example.com",
+//			OriginRealm:       "example.com",
+//			AuthApplicationID: gx.ApplicationID,
+//			RequestType:       ccr.RequestType,
+//			RequestNumber:     ccr.RequestNumber,
+//			ChargingRuleInstall: []*gx.ChargingRuleInstall{
+//				{ChargingRuleName: []string{"default"}},
+//			},
+//		}
+//		a, err := cca.Message(m)
+//		if err != nil {
+//			return
+//		}
+//		a.WriteTo(c)
+//	})
+package gx
@@ -0,0 +1,69 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package gx
+
+import "github.com/omnicate/go-diameter/v4/diam"
+
+// ApplicationID is the Gx Application ID.
+const ApplicationID = diam.GX_CHARGING_CONTROL_APP_ID
+
+// Values of the Event-Trigger AVP.
+const (
+	SGSNChange                      = 0
+	QoSChange                       = 1
+	RATChange                       = 2
+	TFTChange                       = 3
+	PLMNChange                      = 4
+	LossOfBearer                    = 5
+	RecoveryOfBearer                = 6
+	IPCANChange                     = 7
+	QoSChangeExceedingAuthorization = 11
+	RAIChange                       = 12
+	UserLocationChange              = 13
+	NoEventTriggers                 = 14
+	OutOfCredit                     = 15
+	ReallocationOfCredit            = 16
+	RevalidationTimeout             = 17
+	UEIPAddressAllocate             = 18
+	UEIPAddressRelease              = 19
+	DefaultEPSBearerQoSChange       = 20
+	ANGWChange                      = 21
+	SuccessfulResourceAllocation    = 22
+	ResourceModificationRequest     = 23
+	PGWTraceControl                 = 24
+	UETimeZoneChange                = 25
+	TAIChange                       = 26
+	ECGIChange                      = 27
+	ChargingCorrelationExchange     = 28
+	APNAMBRModificationFailure      = 29
+	UserCSGInformationChange        = 30
+	UsageReport                     = 33
+)
+
+// Values of the IP-CAN-Type AVP.
+const (
+	IPCAN3GPPGPRS   = 0
+	IPCANDOCSIS     = 1
+	IPCANxDSL       = 2
+	IPCANWiMAX      = 3
+	IPCAN3GPP2      = 4
+	IPCAN3GPPEPS    = 5
+	IPCANNon3GPPEPS = 6
+)
+
+// Values of the Flow-Direction AVP.
+const (
+	FlowUnspecified   = 0
+	FlowDownlink      = 1
+	FlowUplink        = 2
+	FlowBidirectional = 3
+)
+
+// Values of the Pre-emption-Capability and Pre-emption-Vulnerability
+// AVPs.
+const (
+	PreemptionEnabled  = 0
+	PreemptionDisabled = 1
+)
@@ -0,0 +1,161 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package gx
+
+import (
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/app/creditcontrol"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+)
+
+func uint32p(v uint32) *uint32 { return &v }
+func int32p(v int32) *int32    { return &v }
+
+func TestCCR(t *testing.T) {
+	want := &CCR{
+		SessionID:         "pcef;1;1",
+		AuthApplicationID: ApplicationID,
+		OriginHost:        "pcef",
+		OriginRealm:       "localhost",
+		DestinationRealm:  "localhost",
+		RequestType:       creditcontrol.InitialRequest,
+		SubscriptionID: []*creditcontrol.SubscriptionID{
+			{Type: creditcontrol.EndUserIMSI, Data: "001010123456789"},
+		},
+		FramedIPAddress: net.IP{10, 0, 0, 1},
+		IPCANType:       int32p(IPCAN3GPPEPS),
+		QoSInformation: &QoSInformation{
+			QoSClassIdentifier:       int32p(9),
+			APNAggregateMaxBitrateUL: 1000000,
+			APNAggregateMaxBitrateDL: 5000000,
+		},
+		ANGWAddress:     []net.IP{net.ParseIP("192.168.0.1").To4()},
+		CalledStationID: "internet",
+		DefaultEPSBearerQoS: &DefaultEPSBearerQoS{
+			QoSClassIdentifier: int32p(9),
+			AllocationRetentionPriority: &AllocationRetentionPriority{
+				PriorityLevel:        8,
+				PreemptionCapability: int32p(PreemptionDisabled),
+			},
+		},
+		EventTrigger:      []int32{UEIPAddressAllocate},
+		UserEquipmentInfo: creditcontrol.IMEISVInfo("3534900698733201"),
+		UserLocationInfo:  []byte{0x82, 0x00, 0xf1, 0x10, 0x00, 0x01, 0x00, 0xf1, 0x10, 0x00, 0x00, 0x01, 0x01},
+	}
+	m, err := want.Message()
+	if err != nil {
+		t.Fatal(err)
+	}
+	have, err := ParseCCR(diamtest.RoundTrip(t, m))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, have) {
+		t.Fatalf("Unexpected CCR.\nWant %#v\nHave %#v", want, have)
+	}
+}
+
+func TestCCA(t *testing.T) {
+	req, err := (&CCR{SessionID: "pcef;1;1", RequestType: creditcontrol.InitialRequest}).Message()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &CCA{
+		SessionID:         "pcef;1;1",
+		AuthApplicationID: ApplicationID,
+		OriginHost:        "pcrf",
+		OriginRealm:       "localhost",
+		ResultCode:        diam.Success,
+		RequestType:       creditcontrol.InitialRequest,
+		EventTrigger:      []int32{RATChange, UsageReport},
+		ChargingRuleInstall: []*ChargingRuleInstall{
+			{
+				ChargingRuleDefinition: []*ChargingRuleDefinition{
+					{
+						ChargingRuleName: "video",
+						RatingGroup:      uint32p(10),
+						FlowInformation: []*FlowInformation{
+							{
+								FlowDescription: "permit out 17 from any to any",
+								FlowDirection:   int32p(FlowDownlink),
+							},
+						},
+						Precedence: uint32p(100),
+					},
+				},
+				ChargingRuleName: []string{"default"},
+			},
+		},
+		ChargingRuleRemove: []*ChargingRuleRemove{
+			{ChargingRuleBaseName: []string{"legacy"}},
+		},
+		UsageMonitoringInformation: []*UsageMonitoringInformation{
+			{
+				MonitoringKey:      "mk1",
+				GrantedServiceUnit: []*creditcontrol.ServiceUnit{{TotalOctets: 1 << 30}},
+			},
+		},
+	}
+	m, err := want.Message(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Header.HopByHopID != req.Header.HopByHopID {
+		t.Fatal("Answer does not match the request")
+	}
+	have, err := ParseCCA(diamtest.RoundTrip(t, m))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, have) {
+		t.Fatalf("Unexpected CCA.\nWant %#v\nHave %#v", want, have)
+	}
+}
+
+func TestRAR(t *testing.T) {
+	revalidation := time.Unix(1600000000, 0)
+	r := &RAR{
+		SessionID:         "pcef;1;1",
+		AuthApplicationID: ApplicationID,
+		OriginHost:        "pcrf",
+		OriginRealm:       "localhost",
+		DestinationRealm:  "localhost",
+		DestinationHost:   "pcef",
+		RevalidationTime:  &revalidation,
+		ChargingRuleRemove: []*ChargingRuleRemove{
+			{ChargingRuleName: []string{"video"}},
+		},
+	}
+	m, err := r.Message()
+	if err != nil {
+		t.Fatal(err)
+	}
+	have, err := ParseRAR(diamtest.RoundTrip(t, m))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have.RevalidationTime == nil || !have.RevalidationTime.Equal(revalidation) {
+		t.Fatalf("Unexpected Revalidation-Time: %v", have.RevalidationTime)
+	}
+	if len(have.ChargingRuleRemove) != 1 || !reflect.DeepEqual(have.ChargingRuleRemove[0].ChargingRuleName, []string{"video"}) {
+		t.Fatalf("Unexpected Charging-Rule-Remove: %#v", have.ChargingRuleRemove)
+	}
+	a, err := (&RAA{SessionID: r.SessionID, OriginHost: "pcef", OriginRealm: "localhost", ResultCode: diam.Success}).Message(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raa, err := ParseRAA(diamtest.RoundTrip(t, a))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raa.ResultCode != diam.Success || raa.SessionID != r.SessionID {
+		t.Fatalf("Unexpected RAA: %#v", raa)
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package gx
+
+import (
+	"net"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/app/creditcontrol"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// CCR is the Gx Credit-Control-Request, see 3GPP TS 29.212 section
+// 5.6.2.
+type CCR struct {
+	SessionID                  datatype.UTF8String              `avp:"Session-Id"`
+	AuthApplicationID          uint32                           `avp:"Auth-Application-Id"`
+	OriginHost                 datatype.DiameterIdentity        `avp:"Origin-Host"`
+	OriginRealm                datatype.DiameterIdentity        `avp:"Origin-Realm"`
+	DestinationRealm           datatype.DiameterIdentity        `avp:"Destination-Realm"`
+	RequestType                creditcontrol.RequestType        `avp:"CC-Request-Type"`
+	RequestNumber              uint32                           `avp:"CC-Request-Number"`
+	DestinationHost            datatype.DiameterIdentity        `avp:"Destination-Host,omitempty"`
+	OriginStateID              uint32                           `avp:"Origin-State-Id,omitempty"`
+	SubscriptionID             []*creditcontrol.SubscriptionID  `avp:"Subscription-Id,omitempty"`
+	NetworkRequestSupport      *int32                           `avp:"Network-Request-Support,omitempty"`
+	BearerUsage                *int32                           `avp:"Bearer-Usage,omitempty"`
+	FramedIPAddress            net.IP                           `avp:"Framed-IP-Address,omitempty"`
+	IPCANType                  *int32                           `avp:"IP-CAN-Type,omitempty"`
+	RATType                    *int32                           `avp:"RAT-Type,omitempty"`
+	TerminationCause           *int32                           `avp:"Termination-Cause,omitempty"`
+	UserEquipmentInfo          *creditcontrol.UserEquipmentInfo `avp:"User-Equipment-Info,omitempty"`
+	QoSInformation             *QoSInformation                  `avp:"QoS-Information,omitempty"`
+	ANGWAddress                []net.IP                         `avp:"AN-GW-Address,omitempty"`
+	CalledStationID            string                           `avp:"Called-Station-Id,omitempty"`
+	DefaultEPSBearerQoS        *DefaultEPSBearerQoS             `avp:"Default-EPS-Bearer-QoS,omitempty"`
+	EventTrigger               []int32                          `avp:"Event-Trigger,omitempty"`
+	UsageMonitoringInformation []*UsageMonitoringInformation    `avp:"Usage-Monitoring-Information,omitempty"`
+	Online                     *int32                           `avp:"Online,omitempty"`
+	Offline                    *int32                           `avp:"Offline,omitempty"`
+	UserLocationInfo           []byte                           `avp:"TGPP-User-Location-Info,omitempty"`
+}
+
+// Message returns a new Credit-Control-Request with the AVPs of the CCR.
+func (r *CCR) Message() (*diam.Message, error) {
+	m := diam.NewRequest(diam.CreditControl, ApplicationID, nil)
+	if err := m.Marshal(r); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ParseCCR parses the Gx Credit-Control-Request m.
+func ParseCCR(m *diam.Message) (*CCR, error) {
+	r := &CCR{}
+	if err := m.Unmarshal(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// CCA is the Gx Credit-Control-Answer, see 3GPP TS 29.212 section
+// 5.6.3.
+type CCA struct {
+	SessionID                  datatype.UTF8String           `avp:"Session-Id"`
+	AuthApplicationID          uint32                        `avp:"Auth-Application-Id"`
+	OriginHost                 datatype.DiameterIdentity     `avp:"Origin-Host"`
+	OriginRealm                datatype.DiameterIdentity     `avp:"Origin-Realm"`
+	ResultCode                 uint32                        `avp:"Result-Code,omitempty"`
+	RequestType                creditcontrol.RequestType     `avp:"CC-Request-Type"`
+	RequestNumber              uint32                        `avp:"CC-Request-Number"`
+	OriginStateID              uint32                        `avp:"Origin-State-Id,omitempty"`
+	BearerUsage                *int32                        `avp:"Bearer-Usage,omitempty"`
+	EventTrigger               []int32                       `avp:"Event-Trigger,omitempty"`
+	ChargingRuleRemove         []*ChargingRuleRemove         `avp:"Charging-Rule-Remove,omitempty"`
+	ChargingRuleInstall        []*ChargingRuleInstall        `avp:"Charging-Rule-Install,omitempty"`
+	Online                     *int32                        `avp:"Online,omitempty"`
+	Offline                    *int32                        `avp:"Offline,omitempty"`
+	QoSInformation             *QoSInformation               `avp:"QoS-Information,omitempty"`
+	RevalidationTime           *time.Time                    `avp:"Revalidation-Time,omitempty"`
+	DefaultEPSBearerQoS        *DefaultEPSBearerQoS          `avp:"Default-EPS-Bearer-QoS,omitempty"`
+	UsageMonitoringInformation []*UsageMonitoringInformation `avp:"Usage-Monitoring-Information,omitempty"`
+}
+
+// Message returns a new answer to the request m with the AVPs of the
+// CCA.
+func (a *CCA) Message(m *diam.Message) (*diam.Message, error) {
+	am := m.Answer(0)
+	if err := am.Marshal(a); err != nil {
+		return nil, err
+	}
+	return am, nil
+}
+
+// ParseCCA parses the Gx Credit-Control-Answer m.
+func ParseCCA(m *diam.Message) (*CCA, error) {
+	a := &CCA{}
+	if err := m.Unmarshal(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// RAR is the Gx Re-Auth-Request, see 3GPP TS 29.212 section 5.6.4.
+type RAR struct {
+	SessionID                  datatype.UTF8String           `avp:"Session-Id"`
+	AuthApplicationID          uint32                        `avp:"Auth-Application-Id"`
+	OriginHost                 datatype.DiameterIdentity     `avp:"Origin-Host"`
+	OriginRealm                datatype.DiameterIdentity     `avp:"Origin-Realm"`
+	DestinationRealm           datatype.DiameterIdentity     `avp:"Destination-Realm"`
+	DestinationHost            datatype.DiameterIdentity     `avp:"Destination-Host"`
+	ReAuthRequestType          int32                         `avp:"Re-Auth-Request-Type"`
+	OriginStateID              uint32                        `avp:"Origin-State-Id,omitempty"`
+	EventTrigger               []int32                       `avp:"Event-Trigger,omitempty"`
+	ChargingRuleRemove         []*ChargingRuleRemove         `avp:"Charging-Rule-Remove,omitempty"`
+	ChargingRuleInstall        []*ChargingRuleInstall        `avp:"Charging-Rule-Install,omitempty"`
+	DefaultEPSBearerQoS        *DefaultEPSBearerQoS          `avp:"Default-EPS-Bearer-QoS,omitempty"`
+	QoSInformation             *QoSInformation               `avp:"QoS-Information,omitempty"`
+	RevalidationTime           *time.Time                    `avp:"Revalidation-Time,omitempty"`
+	UsageMonitoringInformation []*UsageMonitoringInformation `avp:"Usage-Monitoring-Information,omitempty"`
+}
+
+// Message returns a new Re-Auth-Request with the AVPs of the RAR.
+func (r *RAR) Message() (*diam.Message, error) {
+	m := diam.NewRequest(diam.ReAuth, ApplicationID, nil)
+	if err := m.Marshal(r); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ParseRAR parses the Gx Re-Auth-Request m.
+func ParseRAR(m *diam.Message) (*RAR, error) {
+	r := &RAR{}
+	if err := m.Unmarshal(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// RAA is the Gx Re-Auth-Answer, see 3GPP TS 29.212 section 5.6.5.
+type RAA struct {
+	SessionID     datatype.UTF8String       `avp:"Session-Id"`
+	OriginHost    datatype.DiameterIdentity `avp:"Origin-Host"`
+	OriginRealm   datatype.DiameterIdentity `avp:"Origin-Realm"`
+	ResultCode    uint32                    `avp:"Result-Code,omitempty"`
+	OriginStateID uint32                    `avp:"Origin-State-Id,omitempty"`
+	IPCANType     *int32                    `avp:"IP-CAN-Type,omitempty"`
+	RATType       *int32                    `avp:"RAT-Type,omitempty"`
+	ANGWAddress   []net.IP                  `avp:"AN-GW-Address,omitempty"`
+	ErrorMessage  string                    `avp:"Error-Message,omitempty"`
+}
+
+// Message returns a new answer to the request m with the AVPs of the
+// RAA.
+func (a *RAA) Message(m *diam.Message) (*diam.Message, error) {
+	am := m.Answer(0)
+	if err := am.Marshal(a); err != nil {
+		return nil, err
+	}
+	return am, nil
+}
+
+// ParseRAA parses the Gx Re-Auth-Answer m.
+func ParseRAA(m *diam.Message) (*RAA, error) {
+	a := &RAA{}
+	if err := m.Unmarshal(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
@@ -0,0 +1,28 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package appmsg builds the messages of the typed requests and answers
+// of the application packages.
+package appmsg
+
+import "github.com/omnicate/go-diameter/v4/diam"
+
+// NewRequest returns a new request of the application appID with the
+// command code and the AVPs of v.
+func NewRequest(code, appID uint32, v interface{}) (*diam.Message, error) {
+	m := diam.NewRequest(code, appID, nil)
+	if err := m.Marshal(v); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// NewAnswer returns a new answer to the request m with the AVPs of v.
+func NewAnswer(m *diam.Message, v interface{}) (*diam.Message, error) {
+	a := m.Answer(0)
+	if err := a.Marshal(v); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package nasreq
+
+import (
+	"net"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// Special values of the Framed-IP-Address AVP, see RFC 2865 section 5.8.
+var (
+	// UserSelectedIP tells the NAS to let the user select an address.
+	UserSelectedIP = net.IPv4(255, 255, 255, 255)
+
+	// NASSelectedIP tells the NAS to select an address for the user,
+	// e.g. from a pool.
+	NASSelectedIP = net.IPv4(255, 255, 255, 254)
+)
+
+// Address returns the content of an address AVP such as NAS-IP-Address
+// or Framed-IP-Address, which carry IPv4 addresses as 4 octets, or
+// NAS-IPv6-Address, which carries 16. It returns nil if ip is nil.
+func Address(ip net.IP) []byte {
+	if v4 := ip.To4(); v4 != nil {
+		return []byte(v4)
+	}
+	return []byte(ip.To16())
+}
+
+// ParseAddress returns the address carried by the content of an address
+// AVP, or nil if it is not 4 or 16 octets long.
+func ParseAddress(b []byte) net.IP {
+	switch len(b) {
+	case net.IPv4len:
+		return net.IPv4(b[0], b[1], b[2], b[3])
+	case net.IPv6len:
+		return net.IP(append([]byte(nil), b...))
+	}
+	return nil
+}
+
+// Port returns a pointer to the NAS-Port n, to set the optional NASPort
+// fields of the messages.
+func Port(n uint32) *uint32 {
+	return &n
+}
+
+// NewNASPort returns a NAS-Port AVP.
+func NewNASPort(n uint32) *diam.AVP {
+	return diam.NewAVP(avp.NASPort, avp.Mbit, 0, datatype.Unsigned32(n))
+}
+
+// NewFramedIPAddress returns a Framed-IP-Address AVP with the IPv4
+// address ip.
+func NewFramedIPAddress(ip net.IP) *diam.AVP {
+	return diam.NewAVP(avp.FramedIPAddress, avp.Mbit, 0, datatype.OctetString(Address(ip)))
+}
+
+// NewFilterID returns a Filter-Id AVP naming a filter list configured
+// on the NAS.
+func NewFilterID(id string) *diam.AVP {
+	return diam.NewAVP(avp.FilterID, avp.Mbit, 0, datatype.UTF8String(id))
+}
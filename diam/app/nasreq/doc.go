@@ -0,0 +1,45 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package nasreq provides typed messages of the Diameter Network Access
+// Server application (NASREQ), as specified by RFC 7155.
+//
+// The AAR/AAA, RAR/RAA, STR/STA, ASR/ASA and ACR/ACA types map the AVPs
+// of their commands to Go structs. Their Message methods build a
+// diam.Message with diam.Message.Marshal, and the Parse functions decode
+// one with diam.Message.Unmarshal. Address converts the addresses of
+// AVPs such as NAS-IP-Address and Framed-IP-Address to their encoding,
+// and NewNASPort, NewFramedIPAddress and NewFilterID build single AVPs
+// for messages assembled by hand.
+//
+// Example of a NAS authorizing a PPP user:
+//
+//	aar := &nasreq.AAR{
+//		SessionID:         sid,
+//		AuthApplicationID: nasreq.ApplicationID,
+//		OriginHost:        "nas.example.com",
+//		OriginRealm:       "example.com",
+//		DestinationRealm:  "example.com",
+//		AuthRequestType:   nasreq.AuthorizeAuthenticate,
+//		NASIPAddress:      nasreq.Address(net.ParseIP("192.0.2.1")),
+//		NASPort:           nasreq.Port(7),
+//		UserName:          "user",
+//		UserPassword:      []byte("secret"),
+//		ServiceType:       nasreq.ServiceFramed,
+//		FramedProtocol:    nasreq.FramedPPP,
+//	}
+//	m, err := aar.Message()
+//	if err != nil {
+//		return err
+//	}
+//	a, err := diam.SendRequest(ctx, c, m)
+//	if err != nil {
+//		return err
+//	}
+//	aaa, err := nasreq.ParseAAA(a)
+//	if err != nil {
+//		return err
+//	}
+//	log.Println(aaa.FramedIP(), aaa.FilterID)
+package nasreq
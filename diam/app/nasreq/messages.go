@@ -0,0 +1,346 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package nasreq
+
+import (
+	"net"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/app/internal/appmsg"
+	"github.com/omnicate/go-diameter/v4/diam/app/rf"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// AAR is the AA-Request, see RFC 7155 section 3.1.
+type AAR struct {
+	SessionID         datatype.UTF8String       `avp:"Session-Id"`
+	AuthApplicationID uint32                    `avp:"Auth-Application-Id"`
+	OriginHost        datatype.DiameterIdentity `avp:"Origin-Host"`
+	OriginRealm       datatype.DiameterIdentity `avp:"Origin-Realm"`
+	DestinationRealm  datatype.DiameterIdentity `avp:"Destination-Realm"`
+	AuthRequestType   int32                     `avp:"Auth-Request-Type"`
+	DestinationHost   datatype.DiameterIdentity `avp:"Destination-Host,omitempty"`
+	NASIdentifier     string                    `avp:"NAS-Identifier,omitempty"`
+	NASIPAddress      []byte                    `avp:"NAS-IP-Address,omitempty"`
+	NASIPv6Address    []byte                    `avp:"NAS-IPv6-Address,omitempty"`
+	NASPort           *uint32                   `avp:"NAS-Port,omitempty"`
+	NASPortID         string                    `avp:"NAS-Port-Id,omitempty"`
+	NASPortType       *int32                    `avp:"NAS-Port-Type,omitempty"`
+	UserName          string                    `avp:"User-Name,omitempty"`
+	UserPassword      []byte                    `avp:"User-Password,omitempty"`
+	ServiceType       int32                     `avp:"Service-Type,omitempty"`
+	State             []byte                    `avp:"State,omitempty"`
+	CalledStationID   string                    `avp:"Called-Station-Id,omitempty"`
+	CallingStationID  string                    `avp:"Calling-Station-Id,omitempty"`
+	FramedIPAddress   []byte                    `avp:"Framed-IP-Address,omitempty"`
+	FramedMTU         uint32                    `avp:"Framed-MTU,omitempty"`
+	FramedProtocol    int32                     `avp:"Framed-Protocol,omitempty"`
+}
+
+// Message returns a new AA-Request with the AVPs of r.
+func (r *AAR) Message() (*diam.Message, error) {
+	return appmsg.NewRequest(diam.AA, ApplicationID, r)
+}
+
+// ParseAAR parses the AA-Request m.
+func ParseAAR(m *diam.Message) (*AAR, error) {
+	r := &AAR{}
+	if err := m.Unmarshal(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// AAA is the AA-Answer, see RFC 7155 section 3.2.
+type AAA struct {
+	SessionID             datatype.UTF8String       `avp:"Session-Id"`
+	AuthApplicationID     uint32                    `avp:"Auth-Application-Id"`
+	AuthRequestType       int32                     `avp:"Auth-Request-Type"`
+	ResultCode            uint32                    `avp:"Result-Code"`
+	OriginHost            datatype.DiameterIdentity `avp:"Origin-Host"`
+	OriginRealm           datatype.DiameterIdentity `avp:"Origin-Realm"`
+	UserName              string                    `avp:"User-Name,omitempty"`
+	ServiceType           int32                     `avp:"Service-Type,omitempty"`
+	Class                 [][]byte                  `avp:"Class,omitempty"`
+	ErrorMessage          string                    `avp:"Error-Message,omitempty"`
+	IdleTimeout           uint32                    `avp:"Idle-Timeout,omitempty"`
+	AuthorizationLifetime uint32                    `avp:"Authorization-Lifetime,omitempty"`
+	AuthGracePeriod       uint32                    `avp:"Auth-Grace-Period,omitempty"`
+	SessionTimeout        uint32                    `avp:"Session-Timeout,omitempty"`
+	State                 []byte                    `avp:"State,omitempty"`
+	ReplyMessage          []string                  `avp:"Reply-Message,omitempty"`
+	FilterID              []string                  `avp:"Filter-Id,omitempty"`
+	NASFilterRule         []datatype.IPFilterRule   `avp:"NAS-Filter-Rule,omitempty"`
+	FramedIPAddress       []byte                    `avp:"Framed-IP-Address,omitempty"`
+	FramedIPNetmask       []byte                    `avp:"Framed-IP-Netmask,omitempty"`
+	FramedMTU             uint32                    `avp:"Framed-MTU,omitempty"`
+	FramedProtocol        int32                     `avp:"Framed-Protocol,omitempty"`
+	FramedPool            []byte                    `avp:"Framed-Pool,omitempty"`
+	AcctInterimInterval   uint32                    `avp:"Acct-Interim-Interval,omitempty"`
+}
+
+// Message returns a new answer to the request m with the AVPs of a.
+func (a *AAA) Message(m *diam.Message) (*diam.Message, error) {
+	return appmsg.NewAnswer(m, a)
+}
+
+// ParseAAA parses the AA-Answer m.
+func ParseAAA(m *diam.Message) (*AAA, error) {
+	a := &AAA{}
+	if err := m.Unmarshal(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// FramedIP returns the Framed-IP-Address assigned to the user, or nil.
+func (a *AAA) FramedIP() net.IP {
+	return ParseAddress(a.FramedIPAddress)
+}
+
+// RAR is the Re-Auth-Request, see RFC 7155 section 3.3.
+type RAR struct {
+	SessionID         datatype.UTF8String       `avp:"Session-Id"`
+	OriginHost        datatype.DiameterIdentity `avp:"Origin-Host"`
+	OriginRealm       datatype.DiameterIdentity `avp:"Origin-Realm"`
+	DestinationRealm  datatype.DiameterIdentity `avp:"Destination-Realm"`
+	DestinationHost   datatype.DiameterIdentity `avp:"Destination-Host"`
+	AuthApplicationID uint32                    `avp:"Auth-Application-Id"`
+	ReAuthRequestType int32                     `avp:"Re-Auth-Request-Type"`
+	UserName          string                    `avp:"User-Name,omitempty"`
+	NASIdentifier     string                    `avp:"NAS-Identifier,omitempty"`
+	NASIPAddress      []byte                    `avp:"NAS-IP-Address,omitempty"`
+	NASIPv6Address    []byte                    `avp:"NAS-IPv6-Address,omitempty"`
+	NASPort           *uint32                   `avp:"NAS-Port,omitempty"`
+	ServiceType       int32                     `avp:"Service-Type,omitempty"`
+	FramedIPAddress   []byte                    `avp:"Framed-IP-Address,omitempty"`
+	State             []byte                    `avp:"State,omitempty"`
+}
+
+// Message returns a new Re-Auth-Request with the AVPs of r.
+func (r *RAR) Message() (*diam.Message, error) {
+	return appmsg.NewRequest(diam.ReAuth, ApplicationID, r)
+}
+
+// ParseRAR parses the Re-Auth-Request m.
+func ParseRAR(m *diam.Message) (*RAR, error) {
+	r := &RAR{}
+	if err := m.Unmarshal(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// RAA is the Re-Auth-Answer, see RFC 7155 section 3.4.
+type RAA struct {
+	SessionID             datatype.UTF8String       `avp:"Session-Id"`
+	ResultCode            uint32                    `avp:"Result-Code"`
+	OriginHost            datatype.DiameterIdentity `avp:"Origin-Host"`
+	OriginRealm           datatype.DiameterIdentity `avp:"Origin-Realm"`
+	UserName              string                    `avp:"User-Name,omitempty"`
+	ErrorMessage          string                    `avp:"Error-Message,omitempty"`
+	ServiceType           int32                     `avp:"Service-Type,omitempty"`
+	IdleTimeout           uint32                    `avp:"Idle-Timeout,omitempty"`
+	AuthorizationLifetime uint32                    `avp:"Authorization-Lifetime,omitempty"`
+	FilterID              []string                  `avp:"Filter-Id,omitempty"`
+	State                 []byte                    `avp:"State,omitempty"`
+}
+
+// Message returns a new answer to the request m with the AVPs of a.
+func (a *RAA) Message(m *diam.Message) (*diam.Message, error) {
+	return appmsg.NewAnswer(m, a)
+}
+
+// ParseRAA parses the Re-Auth-Answer m.
+func ParseRAA(m *diam.Message) (*RAA, error) {
+	a := &RAA{}
+	if err := m.Unmarshal(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// STR is the Session-Termination-Request, see RFC 7155 section 3.5.
+type STR struct {
+	SessionID         datatype.UTF8String       `avp:"Session-Id"`
+	OriginHost        datatype.DiameterIdentity `avp:"Origin-Host"`
+	OriginRealm       datatype.DiameterIdentity `avp:"Origin-Realm"`
+	DestinationRealm  datatype.DiameterIdentity `avp:"Destination-Realm"`
+	AuthApplicationID uint32                    `avp:"Auth-Application-Id"`
+	TerminationCause  int32                     `avp:"Termination-Cause"`
+	UserName          string                    `avp:"User-Name,omitempty"`
+	DestinationHost   datatype.DiameterIdentity `avp:"Destination-Host,omitempty"`
+	Class             [][]byte                  `avp:"Class,omitempty"`
+	OriginAAAProtocol int32                     `avp:"Origin-AAA-Protocol,omitempty"`
+}
+
+// Message returns a new Session-Termination-Request with the AVPs of r.
+func (r *STR) Message() (*diam.Message, error) {
+	return appmsg.NewRequest(diam.SessionTermination, ApplicationID, r)
+}
+
+// ParseSTR parses the Session-Termination-Request m.
+func ParseSTR(m *diam.Message) (*STR, error) {
+	r := &STR{}
+	if err := m.Unmarshal(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// STA is the Session-Termination-Answer, see RFC 7155 section 3.6.
+type STA struct {
+	SessionID    datatype.UTF8String       `avp:"Session-Id"`
+	ResultCode   uint32                    `avp:"Result-Code"`
+	OriginHost   datatype.DiameterIdentity `avp:"Origin-Host"`
+	OriginRealm  datatype.DiameterIdentity `avp:"Origin-Realm"`
+	UserName     string                    `avp:"User-Name,omitempty"`
+	Class        [][]byte                  `avp:"Class,omitempty"`
+	ErrorMessage string                    `avp:"Error-Message,omitempty"`
+}
+
+// Message returns a new answer to the request m with the AVPs of a.
+func (a *STA) Message(m *diam.Message) (*diam.Message, error) {
+	return appmsg.NewAnswer(m, a)
+}
+
+// ParseSTA parses the Session-Termination-Answer m.
+func ParseSTA(m *diam.Message) (*STA, error) {
+	a := &STA{}
+	if err := m.Unmarshal(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// ASR is the Abort-Session-Request, see RFC 7155 section 3.7.
+type ASR struct {
+	SessionID         datatype.UTF8String       `avp:"Session-Id"`
+	OriginHost        datatype.DiameterIdentity `avp:"Origin-Host"`
+	OriginRealm       datatype.DiameterIdentity `avp:"Origin-Realm"`
+	DestinationRealm  datatype.DiameterIdentity `avp:"Destination-Realm"`
+	DestinationHost   datatype.DiameterIdentity `avp:"Destination-Host"`
+	AuthApplicationID uint32                    `avp:"Auth-Application-Id"`
+	UserName          string                    `avp:"User-Name,omitempty"`
+	NASIdentifier     string                    `avp:"NAS-Identifier,omitempty"`
+	NASIPAddress      []byte                    `avp:"NAS-IP-Address,omitempty"`
+	NASIPv6Address    []byte                    `avp:"NAS-IPv6-Address,omitempty"`
+	NASPort           *uint32                   `avp:"NAS-Port,omitempty"`
+	ServiceType       int32                     `avp:"Service-Type,omitempty"`
+	State             []byte                    `avp:"State,omitempty"`
+}
+
+// Message returns a new Abort-Session-Request with the AVPs of r.
+func (r *ASR) Message() (*diam.Message, error) {
+	return appmsg.NewRequest(diam.AbortSession, ApplicationID, r)
+}
+
+// ParseASR parses the Abort-Session-Request m.
+func ParseASR(m *diam.Message) (*ASR, error) {
+	r := &ASR{}
+	if err := m.Unmarshal(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// ASA is the Abort-Session-Answer, see RFC 7155 section 3.8.
+type ASA struct {
+	SessionID    datatype.UTF8String       `avp:"Session-Id"`
+	ResultCode   uint32                    `avp:"Result-Code"`
+	OriginHost   datatype.DiameterIdentity `avp:"Origin-Host"`
+	OriginRealm  datatype.DiameterIdentity `avp:"Origin-Realm"`
+	UserName     string                    `avp:"User-Name,omitempty"`
+	ErrorMessage string                    `avp:"Error-Message,omitempty"`
+}
+
+// Message returns a new answer to the request m with the AVPs of a.
+func (a *ASA) Message(m *diam.Message) (*diam.Message, error) {
+	return appmsg.NewAnswer(m, a)
+}
+
+// ParseASA parses the Abort-Session-Answer m.
+func ParseASA(m *diam.Message) (*ASA, error) {
+	a := &ASA{}
+	if err := m.Unmarshal(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// ACR is the Accounting-Request, see RFC 7155 section 3.9.
+type ACR struct {
+	SessionID               datatype.UTF8String       `avp:"Session-Id"`
+	OriginHost              datatype.DiameterIdentity `avp:"Origin-Host"`
+	OriginRealm             datatype.DiameterIdentity `avp:"Origin-Realm"`
+	DestinationRealm        datatype.DiameterIdentity `avp:"Destination-Realm"`
+	AccountingRecordType    rf.RecordType             `avp:"Accounting-Record-Type"`
+	AccountingRecordNumber  uint32                    `avp:"Accounting-Record-Number"`
+	AcctApplicationID       uint32                    `avp:"Acct-Application-Id"`
+	UserName                string                    `avp:"User-Name,omitempty"`
+	AcctMultiSessionID      string                    `avp:"Acct-Multi-Session-Id,omitempty"`
+	DestinationHost         datatype.DiameterIdentity `avp:"Destination-Host,omitempty"`
+	NASIdentifier           string                    `avp:"NAS-Identifier,omitempty"`
+	NASIPAddress            []byte                    `avp:"NAS-IP-Address,omitempty"`
+	NASIPv6Address          []byte                    `avp:"NAS-IPv6-Address,omitempty"`
+	NASPort                 *uint32                   `avp:"NAS-Port,omitempty"`
+	NASPortID               string                    `avp:"NAS-Port-Id,omitempty"`
+	NASPortType             *int32                    `avp:"NAS-Port-Type,omitempty"`
+	Class                   [][]byte                  `avp:"Class,omitempty"`
+	ServiceType             int32                     `avp:"Service-Type,omitempty"`
+	TerminationCause        int32                     `avp:"Termination-Cause,omitempty"`
+	AccountingInputOctets   uint64                    `avp:"Accounting-Input-Octets,omitempty"`
+	AccountingInputPackets  uint64                    `avp:"Accounting-Input-Packets,omitempty"`
+	AccountingOutputOctets  uint64                    `avp:"Accounting-Output-Octets,omitempty"`
+	AccountingOutputPackets uint64                    `avp:"Accounting-Output-Packets,omitempty"`
+	AcctSessionTime         uint32                    `avp:"Acct-Session-Time,omitempty"`
+	CalledStationID         string                    `avp:"Called-Station-Id,omitempty"`
+	CallingStationID        string                    `avp:"Calling-Station-Id,omitempty"`
+	AcctInterimInterval     uint32                    `avp:"Acct-Interim-Interval,omitempty"`
+	FilterID                []string                  `avp:"Filter-Id,omitempty"`
+	FramedIPAddress         []byte                    `avp:"Framed-IP-Address,omitempty"`
+}
+
+// Message returns a new Accounting-Request with the AVPs of r.
+func (r *ACR) Message() (*diam.Message, error) {
+	return appmsg.NewRequest(diam.Accounting, ApplicationID, r)
+}
+
+// ParseACR parses the Accounting-Request m.
+func ParseACR(m *diam.Message) (*ACR, error) {
+	r := &ACR{}
+	if err := m.Unmarshal(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// ACA is the Accounting-Answer, see RFC 7155 section 3.10.
+type ACA struct {
+	SessionID              datatype.UTF8String       `avp:"Session-Id"`
+	ResultCode             uint32                    `avp:"Result-Code"`
+	OriginHost             datatype.DiameterIdentity `avp:"Origin-Host"`
+	OriginRealm            datatype.DiameterIdentity `avp:"Origin-Realm"`
+	AccountingRecordType   rf.RecordType             `avp:"Accounting-Record-Type"`
+	AccountingRecordNumber uint32                    `avp:"Accounting-Record-Number"`
+	AcctApplicationID      uint32                    `avp:"Acct-Application-Id"`
+	UserName               string                    `avp:"User-Name,omitempty"`
+	ErrorMessage           string                    `avp:"Error-Message,omitempty"`
+	AcctInterimInterval    uint32                    `avp:"Acct-Interim-Interval,omitempty"`
+}
+
+// Message returns a new answer to the request m with the AVPs of a.
+func (a *ACA) Message(m *diam.Message) (*diam.Message, error) {
+	return appmsg.NewAnswer(m, a)
+}
+
+// ParseACA parses the Accounting-Answer m.
+func ParseACA(m *diam.Message) (*ACA, error) {
+	a := &ACA{}
+	if err := m.Unmarshal(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package nasreq
+
+import "github.com/omnicate/go-diameter/v4/diam"
+
+// ApplicationID is the NASREQ Application ID, used as Auth-Application-Id
+// and Acct-Application-Id.
+const ApplicationID = diam.NETWORK_ACCESS_APP_ID
+
+// Values of the Auth-Request-Type AVP.
+const (
+	AuthenticateOnly      = 1
+	AuthorizeOnly         = 2
+	AuthorizeAuthenticate = 3
+)
+
+// Values of the Re-Auth-Request-Type AVP.
+const (
+	ReAuthAuthorizeOnly         = 0
+	ReAuthAuthorizeAuthenticate = 1
+)
+
+// Values of the Service-Type AVP, see RFC 2865 section 5.6.
+const (
+	ServiceLogin          = 1
+	ServiceFramed         = 2
+	ServiceCallbackLogin  = 3
+	ServiceCallbackFramed = 4
+	ServiceOutbound       = 5
+	ServiceAdministrative = 6
+	ServiceAuthorizeOnly  = 17
+)
+
+// Values of the Framed-Protocol AVP.
+const (
+	FramedPPP  = 1
+	FramedSLIP = 2
+	FramedGPRS = 7
+)
+
+// Values of the NAS-Port-Type AVP.
+const (
+	PortAsync         = 0
+	PortSync          = 1
+	PortISDNSync      = 2
+	PortVirtual       = 5
+	PortEthernet      = 15
+	PortXDSL          = 16
+	PortCable         = 17
+	PortWireless80211 = 19
+)
+
+// Values of the Termination-Cause AVP, see RFC 6733 section 8.15.
+const (
+	DiameterLogout             = 1
+	DiameterServiceNotProvided = 2
+	DiameterBadAnswer          = 3
+	DiameterAdministrative     = 4
+	DiameterLinkBroken         = 5
+	DiameterAuthExpired        = 6
+	DiameterUserMoved          = 7
+	DiameterSessionTimeout     = 8
+	UserRequest                = 11
+	LostCarrier                = 12
+	LostService                = 13
+	IdleTimeout                = 14
+	SessionTimeout             = 15
+	AdminReset                 = 16
+	AdminReboot                = 17
+	PortError                  = 18
+	NASError                   = 19
+	NASRequest                 = 20
+	NASReboot                  = 21
+	PortUnneeded               = 22
+	PortPreempted              = 23
+	PortSuspended              = 24
+	ServiceUnavailable         = 25
+	Callback                   = 26
+	UserError                  = 27
+	HostRequest                = 28
+)
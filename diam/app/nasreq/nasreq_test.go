@@ -0,0 +1,187 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package nasreq
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/app/rf"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+func TestAddress(t *testing.T) {
+	if b := Address(net.ParseIP("192.0.2.1")); !bytes.Equal(b, []byte{192, 0, 2, 1}) {
+		t.Fatalf("Unexpected IPv4 address: %x", b)
+	}
+	if b := Address(net.ParseIP("2001:db8::1")); len(b) != net.IPv6len {
+		t.Fatalf("Unexpected IPv6 address: %x", b)
+	}
+	if ip := ParseAddress(Address(NASSelectedIP)); !ip.Equal(NASSelectedIP) {
+		t.Fatalf("Unexpected address: %s", ip)
+	}
+	if ip := ParseAddress([]byte{1, 2, 3}); ip != nil {
+		t.Fatalf("Unexpected address: %s", ip)
+	}
+}
+
+func TestAVPs(t *testing.T) {
+	m := diam.NewRequest(diam.AA, ApplicationID, nil)
+	m.AddAVP(NewNASPort(7))
+	m.AddAVP(NewFramedIPAddress(net.ParseIP("10.0.0.1")))
+	m.AddAVP(NewFilterID("web-only"))
+	for code, want := range map[uint32]datatype.Type{
+		avp.NASPort:         datatype.Unsigned32(7),
+		avp.FramedIPAddress: datatype.OctetString([]byte{10, 0, 0, 1}),
+		avp.FilterID:        datatype.UTF8String("web-only"),
+	} {
+		a, err := m.FindAVP(code, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.Flags != avp.Mbit || a.Data.String() != want.String() {
+			t.Fatalf("Unexpected AVP: %s", a)
+		}
+	}
+}
+
+func TestAAA(t *testing.T) {
+	aar := &AAR{
+		SessionID:         "nas;1;2",
+		AuthApplicationID: ApplicationID,
+		OriginHost:        "nas",
+		OriginRealm:       "localhost",
+		DestinationRealm:  "localhost",
+		AuthRequestType:   AuthorizeAuthenticate,
+		NASIPAddress:      Address(net.ParseIP("192.0.2.1")),
+		NASPort:           Port(0),
+		UserName:          "user",
+		UserPassword:      []byte("secret"),
+		ServiceType:       ServiceFramed,
+		FramedIPAddress:   Address(NASSelectedIP),
+		FramedProtocol:    FramedPPP,
+	}
+	req, err := aar.Message()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Header.CommandCode != diam.AA || req.Header.ApplicationID != ApplicationID {
+		t.Fatalf("Unexpected header: %s", req.Header)
+	}
+	r, err := ParseAAR(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.NASPort == nil || *r.NASPort != 0 || r.ServiceType != ServiceFramed ||
+		!ParseAddress(r.NASIPAddress).Equal(net.ParseIP("192.0.2.1")) ||
+		!ParseAddress(r.FramedIPAddress).Equal(NASSelectedIP) {
+		t.Fatalf("Unexpected AAR: %+v", r)
+	}
+	aaa := &AAA{
+		SessionID:         r.SessionID,
+		AuthApplicationID: ApplicationID,
+		AuthRequestType:   r.AuthRequestType,
+		ResultCode:        diam.Success,
+		OriginHost:        "aaa",
+		OriginRealm:       "localhost",
+		FilterID:          []string{"web-only", "no-smtp"},
+		FramedIPAddress:   Address(net.ParseIP("10.0.0.1")),
+		SessionTimeout:    3600,
+	}
+	m, err := aaa.Message(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := ParseAAA(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.ResultCode != diam.Success || len(a.FilterID) != 2 || a.FilterID[1] != "no-smtp" ||
+		!a.FramedIP().Equal(net.ParseIP("10.0.0.1")) || a.SessionTimeout != 3600 {
+		t.Fatalf("Unexpected AAA: %+v", a)
+	}
+}
+
+func TestACA(t *testing.T) {
+	acr := &ACR{
+		SessionID:              "nas;1;2",
+		OriginHost:             "nas",
+		OriginRealm:            "localhost",
+		DestinationRealm:       "localhost",
+		AccountingRecordType:   rf.StopRecord,
+		AccountingRecordNumber: 2,
+		AcctApplicationID:      ApplicationID,
+		NASPort:                Port(7),
+		TerminationCause:       UserRequest,
+		AccountingInputOctets:  1 << 33,
+		AcctSessionTime:        600,
+	}
+	req, err := acr.Message()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := ParseACR(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.AccountingRecordType != rf.StopRecord || r.TerminationCause != UserRequest ||
+		r.AccountingInputOctets != 1<<33 || *r.NASPort != 7 {
+		t.Fatalf("Unexpected ACR: %+v", r)
+	}
+	aca := &ACA{
+		SessionID:              r.SessionID,
+		ResultCode:             diam.Success,
+		OriginHost:             "aaa",
+		OriginRealm:            "localhost",
+		AccountingRecordType:   r.AccountingRecordType,
+		AccountingRecordNumber: r.AccountingRecordNumber,
+		AcctApplicationID:      ApplicationID,
+	}
+	m, err := aca.Message(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := ParseACA(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.AccountingRecordType != rf.StopRecord || a.AccountingRecordNumber != 2 {
+		t.Fatalf("Unexpected ACA: %+v", a)
+	}
+}
+
+func TestASA(t *testing.T) {
+	asr := &ASR{
+		SessionID:         "nas;1;2",
+		OriginHost:        "aaa",
+		OriginRealm:       "localhost",
+		DestinationRealm:  "localhost",
+		DestinationHost:   "nas",
+		AuthApplicationID: ApplicationID,
+		NASPort:           Port(7),
+	}
+	req, err := asr.Message()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Header.CommandCode != diam.AbortSession {
+		t.Fatalf("Unexpected header: %s", req.Header)
+	}
+	asa := &ASA{SessionID: asr.SessionID, ResultCode: diam.Success, OriginHost: "nas", OriginRealm: "localhost"}
+	m, err := asa.Message(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := ParseASA(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.ResultCode != diam.Success || a.SessionID != "nas;1;2" {
+		t.Fatalf("Unexpected ASA: %+v", a)
+	}
+}
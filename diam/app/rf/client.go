@@ -0,0 +1,287 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package rf
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/session"
+)
+
+// Client is an Rf accounting client, sending ACRs to a Charging Data
+// Function (CDF).
+//
+// Records that cannot be delivered because the CDF is unreachable are
+// buffered, in order, and sent again with the T flag set once a new
+// connection is given to SetPeer, or when Flush is called. Sessions
+// whose RealtimeRequired is DeliverAndGrant or GrantAndLose are not
+// buffered, see RealtimeRequired.
+type Client struct {
+	OriginHost       datatype.DiameterIdentity
+	OriginRealm      datatype.DiameterIdentity
+	DestinationRealm datatype.DiameterIdentity
+
+	// RealtimeRequired is the initial RealtimeRequired of sessions.
+	// Defaults to GrantAndStore.
+	RealtimeRequired RealtimeRequired
+
+	// OnInterim, if set, is called when the interim interval of an open
+	// session elapses, and returns the AVPs of the Interim record sent
+	// then, such as the usage of the session. Interim records are not
+	// sent automatically when unset.
+	OnInterim func(s *Session) []*diam.AVP
+
+	// Tx is the time to wait for ACAs. Defaults to DefaultTx.
+	Tx time.Duration
+
+	// MaxBuffered is the maximum number of buffered records. Defaults
+	// to DefaultMaxBuffered.
+	MaxBuffered int
+
+	ids *session.IDGenerator
+
+	flushMu sync.Mutex // serializes flushes
+	mu      sync.Mutex // guards the following
+	peer    diam.Conn
+	buffer  []*diam.Message
+}
+
+// NewClient creates and initializes a Client for the given origin,
+// sending records to peer. The peer may be nil, in which case records
+// are buffered until SetPeer is called.
+func NewClient(host, realm, destRealm datatype.DiameterIdentity, peer diam.Conn) *Client {
+	cli := &Client{
+		OriginHost:       host,
+		OriginRealm:      realm,
+		DestinationRealm: destRealm,
+		Tx:               DefaultTx,
+		MaxBuffered:      DefaultMaxBuffered,
+		ids:              session.NewIDGenerator(host),
+	}
+	if peer != nil {
+		cli.setPeer(peer)
+	}
+	return cli
+}
+
+// SetPeer sets the connection to the CDF, e.g. after a reconnection,
+// and sends the buffered records to it.
+func (cli *Client) SetPeer(ctx context.Context, c diam.Conn) error {
+	cli.setPeer(c)
+	return cli.Flush(ctx)
+}
+
+func (cli *Client) setPeer(c diam.Conn) {
+	cli.mu.Lock()
+	cli.peer = c
+	cli.mu.Unlock()
+	if cn, ok := c.(diam.CloseNotifier); ok {
+		go func() {
+			<-cn.CloseNotify()
+			cli.mu.Lock()
+			if cli.peer == c {
+				cli.peer = nil
+			}
+			cli.mu.Unlock()
+		}()
+	}
+}
+
+// Buffered returns the number of buffered records.
+func (cli *Client) Buffered() int {
+	cli.mu.Lock()
+	defer cli.mu.Unlock()
+	return len(cli.buffer)
+}
+
+// Flush sends the buffered records to the CDF, in order, and returns
+// the error of the first one that could not be delivered. That record
+// and the following ones stay buffered.
+func (cli *Client) Flush(ctx context.Context) error {
+	cli.flushMu.Lock()
+	defer cli.flushMu.Unlock()
+	for {
+		cli.mu.Lock()
+		if len(cli.buffer) == 0 {
+			cli.mu.Unlock()
+			return nil
+		}
+		m, peer := cli.buffer[0], cli.peer
+		cli.mu.Unlock()
+		if peer == nil {
+			return ErrBuffered
+		}
+		if _, err := cli.send(ctx, peer, m); err != nil {
+			return err
+		}
+		cli.mu.Lock()
+		cli.buffer = cli.buffer[1:]
+		cli.mu.Unlock()
+	}
+}
+
+// NewSession creates a new accounting session with a generated
+// Session-Id.
+func (cli *Client) NewSession() *Session {
+	if cli.ids == nil {
+		cli.ids = session.NewIDGenerator(cli.OriginHost)
+	}
+	return &Session{ID: cli.ids.Next(), cli: cli, realtime: cli.RealtimeRequired}
+}
+
+// Send sends the ACR m to the CDF and waits for its answer for up to
+// Tx. When the CDF is unreachable, or records are already buffered,
+// the record is buffered and ErrBuffered is returned.
+func (cli *Client) Send(ctx context.Context, m *diam.Message) (*Answer, error) {
+	cli.mu.Lock()
+	peer := cli.peer
+	if peer == nil || len(cli.buffer) > 0 {
+		err := cli.bufferLocked(m)
+		cli.mu.Unlock()
+		return nil, err
+	}
+	cli.mu.Unlock()
+	a, err := cli.send(ctx, peer, m)
+	if err == nil || ctx.Err() != nil {
+		return a, err
+	}
+	cli.mu.Lock()
+	defer cli.mu.Unlock()
+	return nil, cli.bufferLocked(m)
+}
+
+// bufferLocked appends m to the buffer, marked as retransmitted. It
+// must be called with cli.mu held.
+func (cli *Client) bufferLocked(m *diam.Message) error {
+	max := cli.MaxBuffered
+	if max <= 0 {
+		max = DefaultMaxBuffered
+	}
+	if len(cli.buffer) >= max {
+		return ErrBufferFull
+	}
+	m.Header.CommandFlags |= diam.RetransmittedFlag
+	cli.buffer = append(cli.buffer, m)
+	return ErrBuffered
+}
+
+func (cli *Client) send(ctx context.Context, c diam.Conn, m *diam.Message) (*Answer, error) {
+	tx := cli.Tx
+	if tx <= 0 {
+		tx = DefaultTx
+	}
+	ctx, cancel := context.WithTimeout(ctx, tx)
+	defer cancel()
+	a, err := diam.SendRequest(ctx, c, m)
+	if err != nil {
+		return nil, err
+	}
+	return ParseAnswer(a)
+}
+
+// Session is an accounting session, following the client accounting
+// state machine of RFC 6733 section 8.2: Start records open the session,
+// and Stop records close it. Interim records are only sent while the
+// session is Open, and Start and Event records while it is Idle.
+type Session struct {
+	ID datatype.UTF8String
+
+	cli      *Client
+	mu       sync.Mutex // guards the following
+	number   uint32
+	sent     bool
+	state    State
+	realtime RealtimeRequired
+	interval time.Duration
+	timer    *time.Timer
+}
+
+// NewACR returns an Accounting-Request of the given type with the next
+// Accounting-Record-Number of the session and the given additional
+// AVPs, e.g. Service-Context-Id and Service-Information.
+func (s *Session) NewACR(typ RecordType, avps ...*diam.AVP) *diam.Message {
+	s.mu.Lock()
+	if s.sent {
+		s.number++
+	}
+	s.sent = true
+	number := s.number
+	s.mu.Unlock()
+	m := diam.NewRequest(diam.Accounting, ApplicationID, nil)
+	m.NewAVP(avp.SessionID, avp.Mbit, 0, s.ID)
+	m.NewAVP(avp.OriginHost, avp.Mbit, 0, s.cli.OriginHost)
+	m.NewAVP(avp.OriginRealm, avp.Mbit, 0, s.cli.OriginRealm)
+	m.NewAVP(avp.DestinationRealm, avp.Mbit, 0, s.cli.DestinationRealm)
+	m.NewAVP(avp.AccountingRecordType, avp.Mbit, 0, datatype.Enumerated(typ))
+	m.NewAVP(avp.AccountingRecordNumber, avp.Mbit, 0, datatype.Unsigned32(number))
+	m.NewAVP(avp.AcctApplicationID, avp.Mbit, 0, datatype.Unsigned32(ApplicationID))
+	m.NewAVP(avp.EventTimestamp, avp.Mbit, 0, datatype.Time(time.Now()))
+	for _, a := range avps {
+		m.AddAVP(a)
+	}
+	return m
+}
+
+// Start sends an ACR Start record, which opens the session.
+func (s *Session) Start(ctx context.Context, avps ...*diam.AVP) (*Answer, error) {
+	return s.record(ctx, StartRecord, avps)
+}
+
+// Interim sends an ACR Interim record of the open session.
+func (s *Session) Interim(ctx context.Context, avps ...*diam.AVP) (*Answer, error) {
+	return s.record(ctx, InterimRecord, avps)
+}
+
+// Stop sends an ACR Stop record, which closes the session.
+func (s *Session) Stop(ctx context.Context, avps ...*diam.AVP) (*Answer, error) {
+	return s.record(ctx, StopRecord, avps)
+}
+
+// Event sends an ACR Event record.
+func (s *Session) Event(ctx context.Context, avps ...*diam.AVP) (*Answer, error) {
+	return s.record(ctx, EventRecord, avps)
+}
+
+// Answer is an Accounting-Answer.
+type Answer struct {
+	*diam.Message
+
+	ResultCode       uint32
+	RecordType       RecordType
+	RecordNumber     uint32
+	InterimInterval  time.Duration
+	RealtimeRequired RealtimeRequired
+}
+
+// answer is used to unmarshal Accounting-Answers.
+type answer struct {
+	ResultCode       uint32 `avp:"Result-Code"`
+	RecordType       int32  `avp:"Accounting-Record-Type"`
+	RecordNumber     uint32 `avp:"Accounting-Record-Number"`
+	InterimInterval  uint32 `avp:"Acct-Interim-Interval"`
+	RealtimeRequired int32  `avp:"Accounting-Realtime-Required"`
+}
+
+// ParseAnswer parses the Accounting-Answer m.
+func ParseAnswer(m *diam.Message) (*Answer, error) {
+	var v answer
+	if err := m.Unmarshal(&v); err != nil {
+		return nil, err
+	}
+	return &Answer{
+		Message:          m,
+		ResultCode:       v.ResultCode,
+		RecordType:       RecordType(v.RecordType),
+		RecordNumber:     v.RecordNumber,
+		InterimInterval:  time.Duration(v.InterimInterval) * time.Second,
+		RealtimeRequired: RealtimeRequired(v.RealtimeRequired),
+	}, nil
+}
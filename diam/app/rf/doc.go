@@ -0,0 +1,33 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package rf provides an offline charging client and server for the
+// 3GPP Rf interface, as specified by 3GPP TS 32.299, on top of the
+// Diameter base accounting application.
+//
+// A Client sends Accounting-Requests (ACRs) to a Charging Data Function
+// (CDF). Each accounting session is a Session that builds Start,
+// Interim, Stop and Event records, keeping track of the
+// Accounting-Record-Number and of its state, following the accounting
+// state machines of RFC 6733 section 8.2. Interim records are sent at
+// the interval of the Acct-Interim-Interval AVP when OnInterim is set.
+//
+// Records that cannot be delivered are buffered with the T flag set,
+// and sent in order when a new connection is given to SetPeer, unless
+// the Accounting-Realtime-Required AVP requires otherwise.
+//
+// A Server is the handler of ACRs of a CDF, which tracks the open
+// sessions and times out the ones that stop sending records.
+//
+// Example:
+//
+//	cli := rf.NewClient("ctf.example.com", "example.com", "cdf.example.com", conn)
+//	s := cli.NewSession()
+//	a, err := s.Start(ctx, diam.NewAVP(avp.ServiceContextID, avp.Mbit, 0, datatype.UTF8String("32260@3gpp.org")))
+//	if err == rf.ErrBuffered {
+//		// the record will be sent after the next SetPeer
+//	}
+//	...
+//	s.Stop(ctx)
+package rf
@@ -0,0 +1,54 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package rf
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+)
+
+// ApplicationID is the Acct-Application-Id of Rf, the Diameter base
+// accounting application.
+const ApplicationID = diam.BASE_ACCOUNTING_APP_ID
+
+// DefaultTx is the default time to wait for ACAs.
+const DefaultTx = 10 * time.Second
+
+// DefaultMaxBuffered is the default number of records buffered while
+// the CDF is unreachable.
+const DefaultMaxBuffered = 1024
+
+// RecordType is the value of the Accounting-Record-Type AVP.
+type RecordType int32
+
+// Accounting record types.
+const (
+	EventRecord   RecordType = 1
+	StartRecord   RecordType = 2
+	InterimRecord RecordType = 3
+	StopRecord    RecordType = 4
+)
+
+var recordTypes = [...]string{"", "EVENT_RECORD", "START_RECORD", "INTERIM_RECORD", "STOP_RECORD"}
+
+func (t RecordType) String() string {
+	if t > 0 && int(t) < len(recordTypes) {
+		return recordTypes[t]
+	}
+	return "RecordType(" + strconv.Itoa(int(t)) + ")"
+}
+
+var (
+	// ErrBuffered is returned by Client.Send when the record could not
+	// be delivered and was buffered until the CDF is reachable again.
+	ErrBuffered = errors.New("accounting record buffered")
+
+	// ErrBufferFull is returned by Client.Send when the record could
+	// not be delivered and the buffer is full. The record is dropped.
+	ErrBufferFull = errors.New("accounting buffer full")
+)
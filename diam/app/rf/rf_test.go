@@ -0,0 +1,126 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package rf
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+)
+
+type record struct {
+	typ           RecordType
+	number        uint32
+	retransmitted bool
+}
+
+// newCDF returns a server answering ACRs, sending each request to
+// records.
+func newCDF(records chan record) *diamtest.Server {
+	mux := diam.NewServeMux()
+	mux.HandleFunc("ACR", func(c diam.Conn, m *diam.Message) {
+		typ, _ := m.FindAVP(avp.AccountingRecordType, 0)
+		number, _ := m.FindAVP(avp.AccountingRecordNumber, 0)
+		records <- record{
+			typ:           RecordType(typ.Data.(datatype.Enumerated)),
+			number:        uint32(number.Data.(datatype.Unsigned32)),
+			retransmitted: m.Header.CommandFlags&diam.RetransmittedFlag != 0,
+		}
+		a := m.Answer(diam.Success)
+		sid, _ := m.FindAVP(avp.SessionID, 0)
+		a.AddAVP(sid)
+		a.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("cdf"))
+		a.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity("localhost"))
+		a.AddAVP(typ)
+		a.AddAVP(number)
+		a.NewAVP(avp.AcctInterimInterval, avp.Mbit, 0, datatype.Unsigned32(300))
+		a.WriteTo(c)
+	})
+	return diamtest.NewServer(mux, nil)
+}
+
+func dial(t *testing.T, srv *diamtest.Server) diam.Conn {
+	c, err := diam.Dial(srv.Addr, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestSession(t *testing.T) {
+	records := make(chan record, 3)
+	srv := newCDF(records)
+	defer srv.Close()
+	c := dial(t, srv)
+	defer c.Close()
+
+	s := NewClient("ctf", "localhost", "localhost", c).NewSession()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for i, send := range []func(context.Context, ...*diam.AVP) (*Answer, error){s.Start, s.Interim, s.Stop} {
+		a, err := send(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := RecordType(i + 2)
+		if a.ResultCode != diam.Success || a.RecordType != want || a.RecordNumber != uint32(i) ||
+			a.InterimInterval != 5*time.Minute {
+			t.Fatalf("Unexpected answer: %+v", a)
+		}
+		if r := <-records; r.typ != want || r.number != uint32(i) || r.retransmitted {
+			t.Fatalf("Unexpected record: %+v", r)
+		}
+	}
+}
+
+func TestClient_Buffer(t *testing.T) {
+	records := make(chan record, 3)
+	srv := newCDF(records)
+	defer srv.Close()
+
+	cli := NewClient("ctf", "localhost", "localhost", nil)
+	cli.MaxBuffered = 2
+	s := cli.NewSession()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := s.Start(ctx); err != ErrBuffered {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := s.Interim(ctx); err != ErrBuffered {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := s.Stop(ctx); err != ErrBufferFull {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n := cli.Buffered(); n != 2 {
+		t.Fatalf("Unexpected number of buffered records. Want 2, have %d", n)
+	}
+	c := dial(t, srv)
+	defer c.Close()
+	if err := cli.SetPeer(ctx, c); err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []RecordType{StartRecord, InterimRecord} {
+		if r := <-records; r.typ != want || r.number != uint32(i) || !r.retransmitted {
+			t.Fatalf("Unexpected record: %+v", r)
+		}
+	}
+	if n := cli.Buffered(); n != 0 {
+		t.Fatalf("Unexpected number of buffered records. Want 0, have %d", n)
+	}
+	a, err := s.Event(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.RecordNumber != 3 {
+		t.Fatalf("Unexpected Accounting-Record-Number. Want 3, have %d", a.RecordNumber)
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package rf
+
+import (
+	"sync"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// Server is a stateful accounting server such as a CDF, following the
+// server accounting state machine of RFC 6733 section 8.2. It is the
+// diam.Handler of ACRs:
+//
+//	srv := rf.NewServer("cdf.example.com", "example.com")
+//	mux.Handle("ACR", srv)
+//
+// Sessions are Open from their Start record to their Stop record. Open
+// sessions that receive no record for twice the interim interval time
+// out, and are Idle again.
+type Server struct {
+	OriginHost  datatype.DiameterIdentity
+	OriginRealm datatype.DiameterIdentity
+
+	// InterimInterval is sent in the Acct-Interim-Interval AVP of the
+	// ACAs of Start and Interim records when set.
+	InterimInterval time.Duration
+
+	// RealtimeRequired is sent in the Accounting-Realtime-Required AVP
+	// of ACAs when set.
+	RealtimeRequired RealtimeRequired
+
+	// OnRecord, if set, is called with each ACR and returns the
+	// Result-Code of its ACA. Records not answered with DIAMETER_SUCCESS
+	// do not change the state of their session. Records are answered
+	// with DIAMETER_SUCCESS when unset.
+	OnRecord func(m *diam.Message, typ RecordType) uint32
+
+	// OnTimeout, if set, is called when an open session times out.
+	// It is called from its own goroutine.
+	OnTimeout func(id datatype.UTF8String)
+
+	mu       sync.Mutex // guards sessions
+	sessions map[datatype.UTF8String]*serverSession
+}
+
+// serverSession is an open session of a Server.
+type serverSession struct {
+	timer *time.Timer // session supervision timer, Ts
+}
+
+// NewServer creates and initializes a Server for the given origin.
+func NewServer(host, realm datatype.DiameterIdentity) *Server {
+	return &Server{
+		OriginHost:  host,
+		OriginRealm: realm,
+		sessions:    make(map[datatype.UTF8String]*serverSession),
+	}
+}
+
+// State returns the state of the session with the given Session-Id,
+// Open or Idle.
+func (srv *Server) State(id datatype.UTF8String) State {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if _, ok := srv.sessions[id]; ok {
+		return Open
+	}
+	return Idle
+}
+
+// Len returns the number of open sessions.
+func (srv *Server) Len() int {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	return len(srv.sessions)
+}
+
+// request is used to unmarshal Accounting-Requests.
+type request struct {
+	SessionID    datatype.UTF8String `avp:"Session-Id"`
+	RecordType   int32               `avp:"Accounting-Record-Type"`
+	RecordNumber uint32              `avp:"Accounting-Record-Number"`
+}
+
+// ServeDIAM implements the diam.Handler interface.
+func (srv *Server) ServeDIAM(c diam.Conn, m *diam.Message) {
+	var r request
+	code := uint32(diam.Success)
+	if err := m.Unmarshal(&r); err != nil || r.SessionID == "" {
+		code = diam.MissingAVP
+	}
+	typ := RecordType(r.RecordType)
+	if code == diam.Success && srv.OnRecord != nil {
+		code = srv.OnRecord(m, typ)
+	}
+	if code == diam.Success {
+		srv.transition(r.SessionID, typ)
+	}
+	a := m.Answer(code)
+	a.NewAVP(avp.SessionID, avp.Mbit, 0, r.SessionID)
+	a.NewAVP(avp.OriginHost, avp.Mbit, 0, srv.OriginHost)
+	a.NewAVP(avp.OriginRealm, avp.Mbit, 0, srv.OriginRealm)
+	a.NewAVP(avp.AccountingRecordType, avp.Mbit, 0, datatype.Enumerated(typ))
+	a.NewAVP(avp.AccountingRecordNumber, avp.Mbit, 0, datatype.Unsigned32(r.RecordNumber))
+	a.NewAVP(avp.AcctApplicationID, avp.Mbit, 0, datatype.Unsigned32(ApplicationID))
+	interval := datatype.Unsigned32(srv.InterimInterval / time.Second)
+	if interval > 0 && (typ == StartRecord || typ == InterimRecord) {
+		a.NewAVP(avp.AcctInterimInterval, avp.Mbit, 0, interval)
+	}
+	if srv.RealtimeRequired != 0 {
+		a.NewAVP(avp.AccountingRealtimeRequired, avp.Mbit, 0, datatype.Enumerated(srv.RealtimeRequired))
+	}
+	a.WriteTo(c)
+}
+
+// transition moves the session id to its state after the record of type
+// typ, and restarts its session supervision timer.
+func (srv *Server) transition(id datatype.UTF8String, typ RecordType) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if s, ok := srv.sessions[id]; ok {
+		if s.timer != nil {
+			s.timer.Stop()
+		}
+		delete(srv.sessions, id)
+	}
+	if typ != StartRecord && typ != InterimRecord {
+		return
+	}
+	s := &serverSession{}
+	if srv.InterimInterval > 0 {
+		s.timer = time.AfterFunc(2*srv.InterimInterval, func() { srv.timeout(id, s) })
+	}
+	srv.sessions[id] = s
+}
+
+// timeout closes the session id when its session supervision timer
+// expires, unless it was restarted.
+func (srv *Server) timeout(id datatype.UTF8String, s *serverSession) {
+	srv.mu.Lock()
+	ok := srv.sessions[id] == s
+	if ok {
+		delete(srv.sessions, id)
+	}
+	srv.mu.Unlock()
+	if ok && srv.OnTimeout != nil {
+		srv.OnTimeout(id)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package rf
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+)
+
+func TestServer(t *testing.T) {
+	cdf := NewServer("cdf", "localhost")
+	cdf.InterimInterval = 50 * time.Millisecond
+	timeouts := make(chan datatype.UTF8String, 1)
+	cdf.OnTimeout = func(id datatype.UTF8String) {
+		timeouts <- id
+	}
+	cdf.OnRecord = func(m *diam.Message, typ RecordType) uint32 {
+		if typ == EventRecord {
+			return diam.UnableToComply
+		}
+		return diam.Success
+	}
+	mux := diam.NewServeMux()
+	mux.Handle("ACR", cdf)
+	srv := diamtest.NewServer(mux, nil)
+	defer srv.Close()
+	c := dial(t, srv)
+	defer c.Close()
+
+	cli := NewClient("ctf", "localhost", "localhost", c)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	s := cli.NewSession()
+	if _, err := s.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if state := cdf.State(s.ID); state != Open {
+		t.Fatalf("Unexpected state. Want %s, have %s", Open, state)
+	}
+	if _, err := s.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if state := cdf.State(s.ID); state != Idle {
+		t.Fatalf("Unexpected state. Want %s, have %s", Idle, state)
+	}
+	a, err := cli.NewSession().Event(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.ResultCode != diam.UnableToComply {
+		t.Fatalf("Unexpected Result-Code: %d", a.ResultCode)
+	}
+
+	s = cli.NewSession()
+	if _, err := s.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case id := <-timeouts:
+		if id != s.ID {
+			t.Fatalf("Unexpected session timed out: %s", id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Session did not time out")
+	}
+	if cdf.Len() != 0 {
+		t.Fatal("Session timed out is open")
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package rf
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+)
+
+var (
+	// ErrInvalidState is returned when sending a record that is not
+	// allowed in the state of the session, such as an Interim record
+	// of a session that is not Open.
+	ErrInvalidState = errors.New("accounting record not allowed in session state")
+
+	// ErrDeliveryFailed is returned when a record of a DeliverAndGrant
+	// session could not be delivered. The session is then Idle, and the
+	// service must be terminated.
+	ErrDeliveryFailed = errors.New("accounting record not delivered")
+
+	// ErrRecordLost is returned when a record of a GrantAndLose session
+	// could not be delivered and was dropped.
+	ErrRecordLost = errors.New("accounting record lost")
+)
+
+// State is the state of an accounting session, see RFC 6733 section
+// 8.2.
+type State int
+
+// Accounting session states.
+const (
+	Idle     State = iota // No session, or session closed
+	PendingS              // Start record sent
+	PendingE              // Event record sent
+	Open                  // Service granted
+	PendingI              // Interim record sent
+	PendingL              // Stop record sent
+)
+
+var states = [...]string{"Idle", "PendingS", "PendingE", "Open", "PendingI", "PendingL"}
+
+func (s State) String() string {
+	if s >= 0 && int(s) < len(states) {
+		return states[s]
+	}
+	return "State(" + strconv.Itoa(int(s)) + ")"
+}
+
+// RealtimeRequired is the value of the Accounting-Realtime-Required AVP,
+// which tells clients what to do when records cannot be delivered, see
+// RFC 6733 section 9.8.7.
+type RealtimeRequired int32
+
+// Values of the Accounting-Realtime-Required AVP.
+const (
+	DeliverAndGrant RealtimeRequired = 1 // Service is granted only while records are delivered
+	GrantAndStore   RealtimeRequired = 2 // Records are buffered, the default
+	GrantAndLose    RealtimeRequired = 3 // Records are dropped
+)
+
+var realtimeRequired = [...]string{"", "DELIVER_AND_GRANT", "GRANT_AND_STORE", "GRANT_AND_LOSE"}
+
+func (r RealtimeRequired) String() string {
+	if r > 0 && int(r) < len(realtimeRequired) {
+		return realtimeRequired[r]
+	}
+	return "RealtimeRequired(" + strconv.Itoa(int(r)) + ")"
+}
+
+// State returns the state of the session. Sessions become Idle again
+// when a Start or Interim record fails in a way that requires the
+// service to be terminated, depending on their RealtimeRequired.
+func (s *Session) State() State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state
+}
+
+// RealtimeRequired returns the RealtimeRequired of the session, which
+// is updated by the Accounting-Realtime-Required AVP of ACAs.
+func (s *Session) RealtimeRequired() RealtimeRequired {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.realtime
+}
+
+// SetRealtimeRequired sets the RealtimeRequired of the session, e.g.
+// from the Accounting-Realtime-Required AVP of an authorization answer.
+func (s *Session) SetRealtimeRequired(r RealtimeRequired) {
+	s.mu.Lock()
+	s.realtime = r
+	s.mu.Unlock()
+}
+
+// InterimInterval returns the interval of the Interim records of the
+// session, which is updated by the Acct-Interim-Interval AVP of ACAs.
+func (s *Session) InterimInterval() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.interval
+}
+
+// SetInterimInterval sets the interval of the Interim records of the
+// session, e.g. from the Acct-Interim-Interval AVP of an authorization
+// answer. Zero disables Interim records.
+func (s *Session) SetInterimInterval(d time.Duration) {
+	s.mu.Lock()
+	s.interval = d
+	s.mu.Unlock()
+}
+
+// pendingStates are the states of sessions waiting for the answer to a
+// record, and the states records are allowed in.
+var pendingStates = map[RecordType]struct{ from, to State }{
+	StartRecord:   {Idle, PendingS},
+	EventRecord:   {Idle, PendingE},
+	InterimRecord: {Open, PendingI},
+	StopRecord:    {Open, PendingL},
+}
+
+// record sends a record of type typ and moves the session to its next
+// state.
+func (s *Session) record(ctx context.Context, typ RecordType, avps []*diam.AVP) (*Answer, error) {
+	p := pendingStates[typ]
+	s.mu.Lock()
+	if s.state != p.from {
+		s.mu.Unlock()
+		return nil, ErrInvalidState
+	}
+	s.state = p.to
+	s.stopTimerLocked()
+	realtime := s.realtime
+	s.mu.Unlock()
+	a, err := s.cli.deliver(ctx, s.NewACR(typ, avps...), realtime)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if a != nil {
+		if a.InterimInterval > 0 {
+			s.interval = a.InterimInterval
+		}
+		if a.RealtimeRequired != 0 {
+			s.realtime = a.RealtimeRequired
+		}
+	}
+	s.state = nextState(p.to, realtime, a, err)
+	if s.state == Open && s.interval > 0 && s.cli.OnInterim != nil {
+		s.timer = time.AfterFunc(s.interval, s.interim)
+	}
+	return a, err
+}
+
+// nextState returns the state of a session in the pending state p,
+// whose record was answered with a or failed with err, following the
+// client state machine of RFC 6733 section 8.2.
+func nextState(p State, realtime RealtimeRequired, a *Answer, err error) State {
+	switch p {
+	case PendingS, PendingI:
+	default:
+		return Idle
+	}
+	switch {
+	case err == nil && a.ResultCode == diam.Success:
+		return Open
+	case err == ErrBuffered, err == ErrRecordLost:
+		return Open
+	case err == ErrDeliveryFailed:
+		return Idle
+	case realtime == GrantAndLose:
+		return Open
+	case p == PendingI && realtime != DeliverAndGrant:
+		return Open
+	}
+	return Idle
+}
+
+// interim sends the Interim record of an open session whose interim
+// interval elapsed.
+func (s *Session) interim() {
+	if f := s.cli.OnInterim; f != nil {
+		s.Interim(context.Background(), f(s)...)
+	}
+}
+
+// stopTimerLocked stops the interim timer. It must be called with s.mu
+// held.
+func (s *Session) stopTimerLocked() {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+}
+
+// deliver sends the record m, which is buffered on failure only when
+// realtime is GrantAndStore.
+func (cli *Client) deliver(ctx context.Context, m *diam.Message, realtime RealtimeRequired) (*Answer, error) {
+	if realtime != DeliverAndGrant && realtime != GrantAndLose {
+		return cli.Send(ctx, m)
+	}
+	cli.mu.Lock()
+	peer := cli.peer
+	cli.mu.Unlock()
+	if peer != nil {
+		if a, err := cli.send(ctx, peer, m); err == nil {
+			return a, nil
+		}
+	}
+	if realtime == DeliverAndGrant {
+		return nil, ErrDeliveryFailed
+	}
+	return nil, ErrRecordLost
+}
@@ -0,0 +1,134 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package rf
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+)
+
+func TestSession_State(t *testing.T) {
+	records := make(chan record, 4)
+	srv := newCDF(records)
+	defer srv.Close()
+	c := dial(t, srv)
+	defer c.Close()
+
+	s := NewClient("ctf", "localhost", "localhost", c).NewSession()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := s.Interim(ctx); err != ErrInvalidState {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := s.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if s.State() != Open || s.InterimInterval() != 5*time.Minute {
+		t.Fatalf("Unexpected session: %s, interim %s", s.State(), s.InterimInterval())
+	}
+	if _, err := s.Start(ctx); err != ErrInvalidState {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := s.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if s.State() != Idle {
+		t.Fatalf("Unexpected state. Want %s, have %s", Idle, s.State())
+	}
+}
+
+func TestSession_RealtimeRequired(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for _, tc := range []struct {
+		realtime RealtimeRequired
+		err      error
+		state    State
+		buffered int
+	}{
+		{DeliverAndGrant, ErrDeliveryFailed, Idle, 0},
+		{GrantAndStore, ErrBuffered, Open, 1},
+		{GrantAndLose, ErrRecordLost, Open, 0},
+	} {
+		cli := NewClient("ctf", "localhost", "localhost", nil)
+		cli.RealtimeRequired = tc.realtime
+		s := cli.NewSession()
+		if _, err := s.Start(ctx); err != tc.err {
+			t.Fatalf("Unexpected error with %s: %v", tc.realtime, err)
+		}
+		if s.State() != tc.state {
+			t.Fatalf("Unexpected state with %s. Want %s, have %s", tc.realtime, tc.state, s.State())
+		}
+		if n := cli.Buffered(); n != tc.buffered {
+			t.Fatalf("Unexpected number of buffered records with %s. Want %d, have %d",
+				tc.realtime, tc.buffered, n)
+		}
+	}
+}
+
+func TestSession_InterimInterval(t *testing.T) {
+	cdf := NewServer("cdf", "localhost")
+	cdf.RealtimeRequired = GrantAndLose
+	records := make(chan RecordType, 4)
+	cdf.OnRecord = func(m *diam.Message, typ RecordType) uint32 {
+		records <- typ
+		return diam.Success
+	}
+	mux := diam.NewServeMux()
+	mux.Handle("ACR", cdf)
+	srv := diamtest.NewServer(mux, nil)
+	defer srv.Close()
+	c := dial(t, srv)
+	defer c.Close()
+
+	cli := NewClient("ctf", "localhost", "localhost", c)
+	interims := make(chan *Session, 2)
+	cli.OnInterim = func(s *Session) []*diam.AVP {
+		interims <- s
+		return nil
+	}
+	s := cli.NewSession()
+	s.SetInterimInterval(20 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := s.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if s.RealtimeRequired() != GrantAndLose {
+		t.Fatalf("Unexpected RealtimeRequired: %s", s.RealtimeRequired())
+	}
+	for _, want := range []RecordType{StartRecord, InterimRecord, InterimRecord} {
+		select {
+		case typ := <-records:
+			if typ != want {
+				t.Fatalf("Unexpected record. Want %s, have %s", want, typ)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("No %s", want)
+		}
+	}
+	if is := <-interims; is != s {
+		t.Fatalf("Unexpected session: %s", is.ID)
+	}
+	for i := 0; ; i++ {
+		_, err := s.Stop(ctx)
+		if err == nil {
+			break
+		}
+		// The session may be sending an Interim record.
+		if err != ErrInvalidState || i == 100 {
+			t.Fatal(err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if s.State() != Idle || cdf.Len() != 0 {
+		t.Fatalf("Unexpected state of stopped session: %s, %d open", s.State(), cdf.Len())
+	}
+}
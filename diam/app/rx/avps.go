@@ -0,0 +1,124 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package rx
+
+import (
+	"fmt"
+	"net"
+)
+
+// MediaComponentDescription is the Media-Component-Description AVP, see
+// 3GPP TS 29.214 section 5.3.7.
+type MediaComponentDescription struct {
+	MediaComponentNumber    uint32               `avp:"Media-Component-Number"`
+	MediaSubComponent       []*MediaSubComponent `avp:"Media-Sub-Component,omitempty"`
+	AFApplicationIdentifier []byte               `avp:"AF-Application-Identifier,omitempty"`
+	MediaType               *int32               `avp:"Media-Type,omitempty"`
+	MaxRequestedBandwidthUL uint32               `avp:"Max-Requested-Bandwidth-UL,omitempty"`
+	MaxRequestedBandwidthDL uint32               `avp:"Max-Requested-Bandwidth-DL,omitempty"`
+	FlowStatus              *int32               `avp:"Flow-Status,omitempty"`
+	RSBandwidth             uint32               `avp:"RS-Bandwidth,omitempty"`
+	RRBandwidth             uint32               `avp:"RR-Bandwidth,omitempty"`
+	CodecData               []string             `avp:"Codec-Data,omitempty"`
+}
+
+// NewMediaComponent returns a Media-Component-Description with the given
+// Media-Component-Number and Media-Type.
+func NewMediaComponent(number uint32, mediaType int32) *MediaComponentDescription {
+	return &MediaComponentDescription{
+		MediaComponentNumber: number,
+		MediaType:            &mediaType,
+	}
+}
+
+// SetFlowStatus sets the Flow-Status of the media component.
+func (c *MediaComponentDescription) SetFlowStatus(status int32) {
+	c.FlowStatus = &status
+}
+
+// AddFlow adds a Media-Sub-Component with the given flow descriptions to
+// the media component, and returns it. Its Flow-Number is the next one
+// of the component.
+func (c *MediaComponentDescription) AddFlow(desc ...string) *MediaSubComponent {
+	var n uint32
+	for _, s := range c.MediaSubComponent {
+		if s.FlowNumber > n {
+			n = s.FlowNumber
+		}
+	}
+	s := &MediaSubComponent{
+		FlowNumber:      n + 1,
+		FlowDescription: desc,
+	}
+	c.MediaSubComponent = append(c.MediaSubComponent, s)
+	return s
+}
+
+// Flow returns the Media-Sub-Component with the given Flow-Number.
+func (c *MediaComponentDescription) Flow(number uint32) (*MediaSubComponent, bool) {
+	for _, s := range c.MediaSubComponent {
+		if s.FlowNumber == number {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+// MediaSubComponent is the Media-Sub-Component AVP, see 3GPP TS 29.214
+// section 5.3.12.
+type MediaSubComponent struct {
+	FlowNumber              uint32   `avp:"Flow-Number"`
+	FlowDescription         []string `avp:"Flow-Description,omitempty"`
+	FlowStatus              *int32   `avp:"Flow-Status,omitempty"`
+	FlowUsage               *int32   `avp:"Flow-Usage,omitempty"`
+	MaxRequestedBandwidthUL uint32   `avp:"Max-Requested-Bandwidth-UL,omitempty"`
+	MaxRequestedBandwidthDL uint32   `avp:"Max-Requested-Bandwidth-DL,omitempty"`
+}
+
+// SetFlowUsage sets the Flow-Usage of the sub-component.
+func (s *MediaSubComponent) SetFlowUsage(usage int32) {
+	s.FlowUsage = &usage
+}
+
+// FlowDescriptions returns the uplink and downlink Flow-Description
+// IPFilterRules of the IP flows of the given protocol between the UE and
+// a remote endpoint. Uplink rules use the "in" direction and downlink
+// rules the "out" direction. A zero port matches any port.
+func FlowDescriptions(proto uint8, ue net.IP, uePort int, remote net.IP, remotePort int) (uplink, downlink string) {
+	uplink = fmt.Sprintf("permit in %d from %s to %s", proto,
+		endpoint(ue, uePort), endpoint(remote, remotePort))
+	downlink = fmt.Sprintf("permit out %d from %s to %s", proto,
+		endpoint(remote, remotePort), endpoint(ue, uePort))
+	return uplink, downlink
+}
+
+func endpoint(ip net.IP, port int) string {
+	if port == 0 {
+		return ip.String()
+	}
+	return fmt.Sprintf("%s %d", ip, port)
+}
+
+// Flows is the Flows AVP, see 3GPP TS 29.214 section 5.3.10.
+type Flows struct {
+	MediaComponentNumber uint32   `avp:"Media-Component-Number"`
+	FlowNumber           []uint32 `avp:"Flow-Number,omitempty"`
+}
+
+// AccessNetworkChargingIdentifier is the
+// Access-Network-Charging-Identifier AVP, see 3GPP TS 29.214 section
+// 5.3.2.
+type AccessNetworkChargingIdentifier struct {
+	Value []byte   `avp:"Access-Network-Charging-Identifier-Value"`
+	Flows []*Flows `avp:"Flows,omitempty"`
+}
+
+// AcceptableServiceInfo is the Acceptable-Service-Info AVP, see 3GPP TS
+// 29.214 section 5.3.24.
+type AcceptableServiceInfo struct {
+	MediaComponentDescription []*MediaComponentDescription `avp:"Media-Component-Description,omitempty"`
+	MaxRequestedBandwidthDL   uint32                       `avp:"Max-Requested-Bandwidth-DL,omitempty"`
+	MaxRequestedBandwidthUL   uint32                       `avp:"Max-Requested-Bandwidth-UL,omitempty"`
+}
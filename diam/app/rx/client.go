@@ -0,0 +1,302 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package rx
+
+import (
+	"errors"
+	"net"
+	"sync"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/app/creditcontrol"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/session"
+	"github.com/omnicate/go-diameter/v4/diam/sm"
+)
+
+// ErrSessionClosed is returned when sending requests of a session that
+// was terminated.
+var ErrSessionClosed = errors.New("rx session closed")
+
+// Client is an Rx client for AFs such as the P-CSCF, on top of an
+// sm.Client.
+//
+// Sessions are established with AARs carrying the media of the IMS
+// session, and terminated with STRs. The Client handles the ASRs and
+// RARs of the PCRF for its sessions: aborted sessions are terminated
+// with an STR after OnAbort is called.
+type Client struct {
+	*sm.Client
+
+	DestinationRealm datatype.DiameterIdentity
+	DestinationHost  datatype.DiameterIdentity
+
+	// Conn is the connection to the PCRF. Requests are routed by the
+	// sm.Client when nil.
+	Conn diam.Conn
+
+	// AFApplicationID is sent in the AF-Application-Identifier AVP of
+	// AARs when set.
+	AFApplicationID []byte
+
+	// SpecificActions are the Specific-Action AVPs sent in initial
+	// AARs, i.e. the events the PCRF notifies with RARs.
+	SpecificActions []int32
+
+	// OnAbort is called when the PCRF aborts a session.
+	OnAbort func(s *Session, cause int32)
+
+	// OnReAuth is called when the PCRF sends a RAR for a session, and
+	// returns the Result-Code of the RAA. RARs are answered with
+	// DIAMETER_SUCCESS when unset.
+	OnReAuth func(s *Session, r *RAR) uint32
+
+	ids      *session.IDGenerator
+	mu       sync.Mutex // guards sessions
+	sessions map[datatype.UTF8String]*Session
+}
+
+// NewClient creates a Client sending requests with the sm.Client cli to
+// the given realm, and registers its handlers of ASRs and RARs in the
+// state machine of cli.
+func NewClient(cli *sm.Client, destRealm datatype.DiameterIdentity) *Client {
+	c := &Client{
+		Client:           cli,
+		DestinationRealm: destRealm,
+		SpecificActions: []int32{
+			IndicationOfLossOfBearer,
+			IndicationOfReleaseOfBearer,
+		},
+		ids:      session.NewIDGenerator(cli.Handler.Settings().OriginHost),
+		sessions: make(map[datatype.UTF8String]*Session),
+	}
+	cli.Handler.HandleIdx(
+		diam.CommandIndex{AppID: ApplicationID, Code: diam.AbortSession, Request: true},
+		diam.HandlerFunc(c.handleASR))
+	cli.Handler.HandleIdx(
+		diam.CommandIndex{AppID: ApplicationID, Code: diam.ReAuth, Request: true},
+		diam.HandlerFunc(c.handleRAR))
+	return c
+}
+
+// NewSession creates a new Rx session for the UE with the given IP
+// address and subscription identifiers.
+func (c *Client) NewSession(ue net.IP, id ...*creditcontrol.SubscriptionID) *Session {
+	return &Session{
+		ID:              c.ids.Next(),
+		FramedIPAddress: ue,
+		SubscriptionID:  id,
+		cli:             c,
+	}
+}
+
+// Session returns the established session with the given Session-Id.
+func (c *Client) Session(id datatype.UTF8String) (*Session, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.sessions[id]
+	return s, ok
+}
+
+func (c *Client) add(s *Session) {
+	c.mu.Lock()
+	c.sessions[s.ID] = s
+	c.mu.Unlock()
+}
+
+func (c *Client) remove(id datatype.UTF8String) {
+	c.mu.Lock()
+	delete(c.sessions, id)
+	c.mu.Unlock()
+}
+
+func (c *Client) origin() (datatype.DiameterIdentity, datatype.DiameterIdentity) {
+	s := c.Handler.Settings()
+	return s.OriginHost, s.OriginRealm
+}
+
+func (c *Client) handleASR(conn diam.Conn, m *diam.Message) {
+	r, err := ParseASR(m)
+	if err != nil {
+		diam.LoggerOf(conn).Log(diam.LevelWarn, "failed to parse ASR", "remote", conn.RemoteAddr(), "err", err)
+		return
+	}
+	s, ok := c.Session(r.SessionID)
+	result := uint32(diam.Success)
+	if !ok {
+		result = diam.UnknownSessionID
+	}
+	host, realm := c.origin()
+	a := &ASA{
+		SessionID:   r.SessionID,
+		OriginHost:  host,
+		OriginRealm: realm,
+		ResultCode:  result,
+	}
+	c.answer(conn, m, a)
+	if !ok {
+		return
+	}
+	if c.OnAbort != nil {
+		c.OnAbort(s, r.AbortCause)
+	}
+	// The answer to the STR is received by the goroutine running this
+	// handler, the STR can't be sent from it.
+	l := diam.LoggerOf(conn)
+	go func() {
+		if _, err := s.Terminate(); err != nil {
+			l.Log(diam.LevelError, "failed to terminate Rx session", "session", s.ID, "err", err)
+		}
+	}()
+}
+
+func (c *Client) handleRAR(conn diam.Conn, m *diam.Message) {
+	r, err := ParseRAR(m)
+	if err != nil {
+		diam.LoggerOf(conn).Log(diam.LevelWarn, "failed to parse RAR", "remote", conn.RemoteAddr(), "err", err)
+		return
+	}
+	result := uint32(diam.Success)
+	if s, ok := c.Session(r.SessionID); !ok {
+		result = diam.UnknownSessionID
+	} else if c.OnReAuth != nil {
+		result = c.OnReAuth(s, r)
+	}
+	host, realm := c.origin()
+	a := &RAA{
+		SessionID:   r.SessionID,
+		OriginHost:  host,
+		OriginRealm: realm,
+		ResultCode:  result,
+	}
+	c.answer(conn, m, a)
+}
+
+// answer writes the answer a to the request m.
+func (c *Client) answer(conn diam.Conn, m *diam.Message, a interface {
+	Message(*diam.Message) (*diam.Message, error)
+}) {
+	am, err := a.Message(m)
+	if err == nil {
+		_, err = am.WriteTo(conn)
+	}
+	if err != nil {
+		diam.LoggerOf(conn).Log(diam.LevelError, "failed to answer", "remote", conn.RemoteAddr(), "err", err)
+	}
+}
+
+// Session is an Rx session, bound to an IMS session of a UE.
+type Session struct {
+	ID              datatype.UTF8String
+	FramedIPAddress net.IP
+	SubscriptionID  []*creditcontrol.SubscriptionID
+
+	cli    *Client
+	mu     sync.Mutex // guards the following
+	open   bool
+	closed bool
+	media  []*MediaComponentDescription
+}
+
+// Media returns the media components last authorized by the PCRF.
+func (s *Session) Media() []*MediaComponentDescription {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.media
+}
+
+// Authorize sends an AAR with the given media components. The first
+// AAR of the session is an initial request, which establishes the
+// session if answered with DIAMETER_SUCCESS, and the next ones are
+// updates of the media.
+//
+// AAAs with another result are returned with a *diam.ResultError of
+// their Result-Code or Experimental-Result-Code.
+func (s *Session) Authorize(media ...*MediaComponentDescription) (*AAA, error) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil, ErrSessionClosed
+	}
+	initial := !s.open
+	s.mu.Unlock()
+	m, err := s.newAAR(initial, media).Message()
+	if err != nil {
+		return nil, err
+	}
+	am, err := s.cli.Send(s.cli.Conn, m)
+	if err != nil {
+		return nil, err
+	}
+	a, err := ParseAAA(am)
+	if err != nil {
+		return nil, err
+	}
+	if code, _ := diam.ResultCode(am); code != diam.Success {
+		return a, &diam.ResultError{Code: code}
+	}
+	s.mu.Lock()
+	s.open = true
+	s.media = media
+	s.mu.Unlock()
+	if initial {
+		s.cli.add(s)
+	}
+	return a, nil
+}
+
+func (s *Session) newAAR(initial bool, media []*MediaComponentDescription) *AAR {
+	host, realm := s.cli.origin()
+	r := &AAR{
+		SessionID:                 s.ID,
+		AuthApplicationID:         ApplicationID,
+		OriginHost:                host,
+		OriginRealm:               realm,
+		DestinationRealm:          s.cli.DestinationRealm,
+		DestinationHost:           s.cli.DestinationHost,
+		AFApplicationIdentifier:   s.cli.AFApplicationID,
+		MediaComponentDescription: media,
+	}
+	typ := int32(UpdateRequest)
+	if initial {
+		typ = InitialRequest
+		r.SpecificAction = s.cli.SpecificActions
+		r.SubscriptionID = s.SubscriptionID
+		r.FramedIPAddress = s.FramedIPAddress
+	}
+	r.RxRequestType = &typ
+	return r
+}
+
+// Terminate sends an STR and closes the session.
+func (s *Session) Terminate() (*STA, error) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil, ErrSessionClosed
+	}
+	s.closed = true
+	s.mu.Unlock()
+	s.cli.remove(s.ID)
+	host, realm := s.cli.origin()
+	r := &STR{
+		SessionID:         s.ID,
+		OriginHost:        host,
+		OriginRealm:       realm,
+		DestinationRealm:  s.cli.DestinationRealm,
+		DestinationHost:   s.cli.DestinationHost,
+		AuthApplicationID: ApplicationID,
+		TerminationCause:  DiameterLogout,
+	}
+	m, err := r.Message()
+	if err != nil {
+		return nil, err
+	}
+	am, err := s.cli.Send(s.cli.Conn, m)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSTA(am)
+}
@@ -0,0 +1,30 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package rx provides typed messages of the 3GPP Rx interface, between
+// the AF and the PCRF, as specified by 3GPP TS 29.214.
+//
+// The Rx dictionary is part of dict.Default. The AAR/AAA, STR/STA,
+// ASR/ASA and RAR/RAA types map the AVPs of their commands to Go
+// structs, and MediaComponentDescription describes the media of an IMS
+// session with its IP flows.
+//
+// The Client is an Rx client for the P-CSCF on top of an sm.Client. It
+// handles the ASRs and RARs of the PCRF for its sessions.
+//
+// Example of a P-CSCF authorizing the audio of a call:
+//
+//	cli := rx.NewClient(smClient, "example.com")
+//	cli.Conn, err = cli.Dial("pcrf.example.com:3868")
+//	...
+//	audio := rx.NewMediaComponent(1, rx.Audio)
+//	audio.AddFlow(rx.FlowDescriptions(17, ue, 49000, remote, 5000))
+//	s := cli.NewSession(ue)
+//	aaa, err := s.Authorize(audio)
+//	if re, ok := err.(*diam.ResultError); ok {
+//		// the PCRF rejected the media with re.Code
+//	}
+//	...
+//	s.Terminate()
+package rx
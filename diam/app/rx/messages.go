@@ -0,0 +1,244 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package rx
+
+import (
+	"net"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/app/creditcontrol"
+	"github.com/omnicate/go-diameter/v4/diam/app/internal/appmsg"
+	"github.com/omnicate/go-diameter/v4/diam/app/tgpp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// AAR is the AA-Request, see 3GPP TS 29.214 section 5.6.1.
+type AAR struct {
+	SessionID                 datatype.UTF8String             `avp:"Session-Id"`
+	AuthApplicationID         uint32                          `avp:"Auth-Application-Id"`
+	OriginHost                datatype.DiameterIdentity       `avp:"Origin-Host"`
+	OriginRealm               datatype.DiameterIdentity       `avp:"Origin-Realm"`
+	DestinationRealm          datatype.DiameterIdentity       `avp:"Destination-Realm"`
+	DestinationHost           datatype.DiameterIdentity       `avp:"Destination-Host,omitempty"`
+	AFApplicationIdentifier   []byte                          `avp:"AF-Application-Identifier,omitempty"`
+	MediaComponentDescription []*MediaComponentDescription    `avp:"Media-Component-Description,omitempty"`
+	ServiceInfoStatus         *int32                          `avp:"Service-Info-Status,omitempty"`
+	AFChargingIdentifier      []byte                          `avp:"AF-Charging-Identifier,omitempty"`
+	SpecificAction            []int32                         `avp:"Specific-Action,omitempty"`
+	SubscriptionID            []*creditcontrol.SubscriptionID `avp:"Subscription-Id,omitempty"`
+	FramedIPAddress           net.IP                          `avp:"Framed-IP-Address,omitempty"`
+	FramedIPv6Prefix          []byte                          `avp:"Framed-IPv6-Prefix,omitempty"`
+	CalledStationID           string                          `avp:"Called-Station-Id,omitempty"`
+	ServiceURN                string                          `avp:"Service-URN,omitempty"`
+	RxRequestType             *int32                          `avp:"Rx-Request-Type,omitempty"`
+	OriginStateID             uint32                          `avp:"Origin-State-Id,omitempty"`
+}
+
+// Message returns a new AA-Request with the AVPs of r.
+func (r *AAR) Message() (*diam.Message, error) {
+	return appmsg.NewRequest(diam.AA, ApplicationID, r)
+}
+
+// ParseAAR parses the AA-Request m.
+func ParseAAR(m *diam.Message) (*AAR, error) {
+	r := &AAR{}
+	if err := m.Unmarshal(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// AAA is the AA-Answer, see 3GPP TS 29.214 section 5.6.2.
+type AAA struct {
+	SessionID                       datatype.UTF8String                `avp:"Session-Id"`
+	AuthApplicationID               uint32                             `avp:"Auth-Application-Id"`
+	OriginHost                      datatype.DiameterIdentity          `avp:"Origin-Host"`
+	OriginRealm                     datatype.DiameterIdentity          `avp:"Origin-Realm"`
+	ResultCode                      uint32                             `avp:"Result-Code,omitempty"`
+	ExperimentalResult              *tgpp.ExperimentalResult           `avp:"Experimental-Result,omitempty"`
+	AccessNetworkChargingIdentifier []*AccessNetworkChargingIdentifier `avp:"Access-Network-Charging-Identifier,omitempty"`
+	AccessNetworkChargingAddress    net.IP                             `avp:"Access-Network-Charging-Address,omitempty"`
+	AcceptableServiceInfo           *AcceptableServiceInfo             `avp:"Acceptable-Service-Info,omitempty"`
+	IPCANType                       *int32                             `avp:"IP-CAN-Type,omitempty"`
+	RATType                         *int32                             `avp:"RAT-Type,omitempty"`
+	ErrorMessage                    string                             `avp:"Error-Message,omitempty"`
+	OriginStateID                   uint32                             `avp:"Origin-State-Id,omitempty"`
+}
+
+// Message returns a new answer to the request m with the AVPs of a.
+func (a *AAA) Message(m *diam.Message) (*diam.Message, error) {
+	return appmsg.NewAnswer(m, a)
+}
+
+// ParseAAA parses the AA-Answer m.
+func ParseAAA(m *diam.Message) (*AAA, error) {
+	a := &AAA{}
+	if err := m.Unmarshal(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// STR is the Session-Termination-Request, see 3GPP TS 29.214 section
+// 5.6.5.
+type STR struct {
+	SessionID          datatype.UTF8String       `avp:"Session-Id"`
+	OriginHost         datatype.DiameterIdentity `avp:"Origin-Host"`
+	OriginRealm        datatype.DiameterIdentity `avp:"Origin-Realm"`
+	DestinationRealm   datatype.DiameterIdentity `avp:"Destination-Realm"`
+	AuthApplicationID  uint32                    `avp:"Auth-Application-Id"`
+	TerminationCause   int32                     `avp:"Termination-Cause"`
+	DestinationHost    datatype.DiameterIdentity `avp:"Destination-Host,omitempty"`
+	RequiredAccessInfo []int32                   `avp:"Required-Access-Info,omitempty"`
+	OriginStateID      uint32                    `avp:"Origin-State-Id,omitempty"`
+}
+
+// Message returns a new Session-Termination-Request with the AVPs of r.
+func (r *STR) Message() (*diam.Message, error) {
+	return appmsg.NewRequest(diam.SessionTermination, ApplicationID, r)
+}
+
+// ParseSTR parses the Session-Termination-Request m.
+func ParseSTR(m *diam.Message) (*STR, error) {
+	r := &STR{}
+	if err := m.Unmarshal(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// STA is the Session-Termination-Answer, see 3GPP TS 29.214 section
+// 5.6.6.
+type STA struct {
+	SessionID     datatype.UTF8String       `avp:"Session-Id"`
+	OriginHost    datatype.DiameterIdentity `avp:"Origin-Host"`
+	OriginRealm   datatype.DiameterIdentity `avp:"Origin-Realm"`
+	ResultCode    uint32                    `avp:"Result-Code,omitempty"`
+	ErrorMessage  string                    `avp:"Error-Message,omitempty"`
+	OriginStateID uint32                    `avp:"Origin-State-Id,omitempty"`
+}
+
+// Message returns a new answer to the request m with the AVPs of a.
+func (a *STA) Message(m *diam.Message) (*diam.Message, error) {
+	return appmsg.NewAnswer(m, a)
+}
+
+// ParseSTA parses the Session-Termination-Answer m.
+func ParseSTA(m *diam.Message) (*STA, error) {
+	a := &STA{}
+	if err := m.Unmarshal(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// ASR is the Abort-Session-Request, see 3GPP TS 29.214 section 5.6.7.
+type ASR struct {
+	SessionID         datatype.UTF8String       `avp:"Session-Id"`
+	OriginHost        datatype.DiameterIdentity `avp:"Origin-Host"`
+	OriginRealm       datatype.DiameterIdentity `avp:"Origin-Realm"`
+	DestinationRealm  datatype.DiameterIdentity `avp:"Destination-Realm"`
+	DestinationHost   datatype.DiameterIdentity `avp:"Destination-Host"`
+	AuthApplicationID uint32                    `avp:"Auth-Application-Id"`
+	AbortCause        int32                     `avp:"Abort-Cause"`
+	OriginStateID     uint32                    `avp:"Origin-State-Id,omitempty"`
+}
+
+// Message returns a new Abort-Session-Request with the AVPs of r.
+func (r *ASR) Message() (*diam.Message, error) {
+	return appmsg.NewRequest(diam.AbortSession, ApplicationID, r)
+}
+
+// ParseASR parses the Abort-Session-Request m.
+func ParseASR(m *diam.Message) (*ASR, error) {
+	r := &ASR{}
+	if err := m.Unmarshal(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// ASA is the Abort-Session-Answer, see 3GPP TS 29.214 section 5.6.8.
+type ASA struct {
+	SessionID     datatype.UTF8String       `avp:"Session-Id"`
+	OriginHost    datatype.DiameterIdentity `avp:"Origin-Host"`
+	OriginRealm   datatype.DiameterIdentity `avp:"Origin-Realm"`
+	ResultCode    uint32                    `avp:"Result-Code,omitempty"`
+	OriginStateID uint32                    `avp:"Origin-State-Id,omitempty"`
+	ErrorMessage  string                    `avp:"Error-Message,omitempty"`
+}
+
+// Message returns a new answer to the request m with the AVPs of a.
+func (a *ASA) Message(m *diam.Message) (*diam.Message, error) {
+	return appmsg.NewAnswer(m, a)
+}
+
+// ParseASA parses the Abort-Session-Answer m.
+func ParseASA(m *diam.Message) (*ASA, error) {
+	a := &ASA{}
+	if err := m.Unmarshal(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// RAR is the Re-Auth-Request, see 3GPP TS 29.214 section 5.6.3.
+type RAR struct {
+	SessionID                       datatype.UTF8String                `avp:"Session-Id"`
+	OriginHost                      datatype.DiameterIdentity          `avp:"Origin-Host"`
+	OriginRealm                     datatype.DiameterIdentity          `avp:"Origin-Realm"`
+	DestinationRealm                datatype.DiameterIdentity          `avp:"Destination-Realm"`
+	DestinationHost                 datatype.DiameterIdentity          `avp:"Destination-Host"`
+	AuthApplicationID               uint32                             `avp:"Auth-Application-Id"`
+	SpecificAction                  []int32                            `avp:"Specific-Action"`
+	AccessNetworkChargingIdentifier []*AccessNetworkChargingIdentifier `avp:"Access-Network-Charging-Identifier,omitempty"`
+	AccessNetworkChargingAddress    net.IP                             `avp:"Access-Network-Charging-Address,omitempty"`
+	Flows                           []*Flows                           `avp:"Flows,omitempty"`
+	AbortCause                      *int32                             `avp:"Abort-Cause,omitempty"`
+	IPCANType                       *int32                             `avp:"IP-CAN-Type,omitempty"`
+	RATType                         *int32                             `avp:"RAT-Type,omitempty"`
+	OriginStateID                   uint32                             `avp:"Origin-State-Id,omitempty"`
+}
+
+// Message returns a new Re-Auth-Request with the AVPs of r.
+func (r *RAR) Message() (*diam.Message, error) {
+	return appmsg.NewRequest(diam.ReAuth, ApplicationID, r)
+}
+
+// ParseRAR parses the Re-Auth-Request m.
+func ParseRAR(m *diam.Message) (*RAR, error) {
+	r := &RAR{}
+	if err := m.Unmarshal(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// RAA is the Re-Auth-Answer, see 3GPP TS 29.214 section 5.6.4.
+type RAA struct {
+	SessionID                 datatype.UTF8String          `avp:"Session-Id"`
+	OriginHost                datatype.DiameterIdentity    `avp:"Origin-Host"`
+	OriginRealm               datatype.DiameterIdentity    `avp:"Origin-Realm"`
+	ResultCode                uint32                       `avp:"Result-Code,omitempty"`
+	ExperimentalResult        *tgpp.ExperimentalResult     `avp:"Experimental-Result,omitempty"`
+	MediaComponentDescription []*MediaComponentDescription `avp:"Media-Component-Description,omitempty"`
+	ServiceURN                string                       `avp:"Service-URN,omitempty"`
+	OriginStateID             uint32                       `avp:"Origin-State-Id,omitempty"`
+	ErrorMessage              string                       `avp:"Error-Message,omitempty"`
+}
+
+// Message returns a new answer to the request m with the AVPs of a.
+func (a *RAA) Message(m *diam.Message) (*diam.Message, error) {
+	return appmsg.NewAnswer(m, a)
+}
+
+// ParseRAA parses the Re-Auth-Answer m.
+func ParseRAA(m *diam.Message) (*RAA, error) {
+	a := &RAA{}
+	if err := m.Unmarshal(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
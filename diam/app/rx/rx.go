@@ -0,0 +1,93 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package rx
+
+import "github.com/omnicate/go-diameter/v4/diam"
+
+// ApplicationID is the Rx Application ID.
+const ApplicationID = diam.TGPP_RX_APP_ID
+
+// VendorID is the 3GPP Vendor-Id.
+const VendorID = 10415
+
+// Values of the Abort-Cause AVP.
+const (
+	BearerReleased                      = 0
+	InsufficientServerResources         = 1
+	InsufficientBearerResources         = 2
+	PSToCSHandover                      = 3
+	SponsoredDataConnectivityDisallowed = 4
+)
+
+// Values of the Flow-Status AVP.
+const (
+	EnabledUplink   = 0
+	EnabledDownlink = 1
+	Enabled         = 2
+	Disabled        = 3
+	Removed         = 4
+)
+
+// Values of the Flow-Usage AVP.
+const (
+	NoInformation = 0
+	RTCP          = 1
+	AFSignalling  = 2
+)
+
+// Values of the Media-Type AVP.
+const (
+	Audio       = 0
+	Video       = 1
+	Data        = 2
+	Application = 3
+	Control     = 4
+	Text        = 5
+	Message     = 6
+)
+
+// Values of the Specific-Action AVP.
+const (
+	ChargingCorrelationExchange               = 1
+	IndicationOfLossOfBearer                  = 2
+	IndicationOfRecoveryOfBearer              = 3
+	IndicationOfReleaseOfBearer               = 4
+	IPCANChange                               = 6
+	IndicationOfOutOfCredit                   = 7
+	IndicationOfSuccessfulResourcesAllocation = 8
+	IndicationOfFailedResourcesAllocation     = 9
+	IndicationOfLimitedPCCDeployment          = 10
+	UsageReport                               = 11
+	AccessNetworkInfoReport                   = 12
+)
+
+// Values of the Rx-Request-Type AVP.
+const (
+	InitialRequest   = 0
+	UpdateRequest    = 1
+	PCSCFRestoration = 2
+)
+
+// Values of the Service-Info-Status AVP.
+const (
+	FinalServiceInformation       = 0
+	PreliminaryServiceInformation = 1
+)
+
+// DiameterLogout is the value of the Termination-Cause AVP sent in STRs
+// for sessions terminated by the AF.
+const DiameterLogout = 1
+
+// Experimental-Result-Code values, see 3GPP TS 29.214 section 5.5.
+const (
+	InvalidServiceInformation             = 5061
+	FilterRestrictions                    = 5062
+	RequestedServiceNotAuthorized         = 5063
+	DuplicatedAFSession                   = 5064
+	IPCANSessionNotAvailable              = 5065
+	UnauthorizedNonEmergencySession       = 5066
+	UnauthorizedSponsoredDataConnectivity = 5067
+	TemporaryNetworkFailure               = 5068
+)
@@ -0,0 +1,283 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package rx
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/app/tgpp"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+	"github.com/omnicate/go-diameter/v4/diam/sm"
+)
+
+var (
+	ue     = net.ParseIP("10.45.0.2").To4()
+	remote = net.ParseIP("192.0.2.10").To4()
+)
+
+func newAudio() *MediaComponentDescription {
+	c := NewMediaComponent(1, Audio)
+	c.MaxRequestedBandwidthUL = 64000
+	c.MaxRequestedBandwidthDL = 64000
+	c.CodecData = []string{"uplink\noffer\nm=audio 49000 RTP/AVP 0"}
+	c.AddFlow(FlowDescriptions(17, ue, 49000, remote, 5000))
+	c.AddFlow(FlowDescriptions(17, ue, 49001, remote, 5001)).SetFlowUsage(RTCP)
+	return c
+}
+
+func TestFlowDescriptions(t *testing.T) {
+	up, down := FlowDescriptions(17, ue, 49000, remote, 0)
+	if want := "permit in 17 from 10.45.0.2 49000 to 192.0.2.10"; up != want {
+		t.Fatalf("Unexpected uplink flow. Want %q, have %q", want, up)
+	}
+	if want := "permit out 17 from 192.0.2.10 to 10.45.0.2 49000"; down != want {
+		t.Fatalf("Unexpected downlink flow. Want %q, have %q", want, down)
+	}
+}
+
+func TestAAR(t *testing.T) {
+	typ := int32(InitialRequest)
+	r := &AAR{
+		SessionID:                 "pcscf;1;2",
+		AuthApplicationID:         ApplicationID,
+		OriginHost:                "pcscf",
+		OriginRealm:               "test",
+		DestinationRealm:          "test",
+		MediaComponentDescription: []*MediaComponentDescription{newAudio()},
+		SpecificAction:            []int32{IndicationOfLossOfBearer},
+		FramedIPAddress:           ue,
+		RxRequestType:             &typ,
+	}
+	m, err := r.Message()
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := m.FindAVP(avp.MediaComponentDescription, VendorID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Flags&avp.Vbit == 0 || v.VendorID != VendorID {
+		t.Fatalf("Unexpected Media-Component-Description header: %+v", v)
+	}
+	p, err := ParseAAR(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.MediaComponentDescription) != 1 {
+		t.Fatalf("Unexpected media: %+v", p.MediaComponentDescription)
+	}
+	c := p.MediaComponentDescription[0]
+	if c.MediaComponentNumber != 1 || c.MediaType == nil || *c.MediaType != Audio ||
+		c.MaxRequestedBandwidthUL != 64000 || len(c.CodecData) != 1 {
+		t.Fatalf("Unexpected media component: %+v", c)
+	}
+	s, ok := c.Flow(2)
+	if !ok || len(s.FlowDescription) != 2 || s.FlowUsage == nil || *s.FlowUsage != RTCP {
+		t.Fatalf("Unexpected media sub-component: %+v", s)
+	}
+	if !p.FramedIPAddress.Equal(ue) || p.RxRequestType == nil || *p.RxRequestType != InitialRequest {
+		t.Fatalf("Unexpected AAR: %+v", p)
+	}
+}
+
+var (
+	pcrfSettings = &sm.Settings{
+		OriginHost:       "pcrf",
+		OriginRealm:      "test",
+		VendorID:         VendorID,
+		ProductName:      "go-diameter",
+		FirmwareRevision: 1,
+	}
+	pcscfSettings = &sm.Settings{
+		OriginHost:       "pcscf",
+		OriginRealm:      "test",
+		VendorID:         VendorID,
+		ProductName:      "go-diameter",
+		FirmwareRevision: 1,
+	}
+)
+
+func TestClient(t *testing.T) {
+	conns := make(chan diam.Conn, 1)
+	strs := make(chan *STR, 1)
+	asas := make(chan *ASA, 1)
+	pcrf := sm.New(pcrfSettings)
+	pcrf.HandleFunc("AAR", func(c diam.Conn, m *diam.Message) {
+		r, err := ParseAAR(m)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		a := &AAA{
+			SessionID:         r.SessionID,
+			AuthApplicationID: ApplicationID,
+			OriginHost:        pcrfSettings.OriginHost,
+			OriginRealm:       pcrfSettings.OriginRealm,
+			ResultCode:        diam.Success,
+		}
+		if am, err := a.Message(m); err == nil {
+			am.WriteTo(c)
+		}
+		conns <- c
+	})
+	pcrf.HandleFunc("STR", func(c diam.Conn, m *diam.Message) {
+		r, err := ParseSTR(m)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		a := &STA{
+			SessionID:   r.SessionID,
+			OriginHost:  pcrfSettings.OriginHost,
+			OriginRealm: pcrfSettings.OriginRealm,
+			ResultCode:  diam.Success,
+		}
+		if am, err := a.Message(m); err == nil {
+			am.WriteTo(c)
+		}
+		strs <- r
+	})
+	pcrf.HandleFunc("ASA", func(c diam.Conn, m *diam.Message) {
+		a, err := ParseASA(m)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		asas <- a
+	})
+	srv := diamtest.NewServer(pcrf, dict.Default)
+	defer srv.Close()
+
+	cli := NewClient(&sm.Client{
+		Handler:        sm.New(pcscfSettings),
+		RequestTimeout: time.Second,
+		AuthApplicationID: []*diam.AVP{
+			diam.NewAVP(avp.AuthApplicationID, avp.Mbit, 0, datatype.Unsigned32(ApplicationID)),
+		},
+	}, "test")
+	aborted := make(chan int32, 1)
+	cli.OnAbort = func(s *Session, cause int32) {
+		aborted <- cause
+	}
+	c, err := cli.Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	cli.Conn = c
+
+	s := cli.NewSession(ue)
+	a, err := s.Authorize(newAudio())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.ResultCode != diam.Success {
+		t.Fatalf("Unexpected result: %d", a.ResultCode)
+	}
+	if _, ok := cli.Session(s.ID); !ok {
+		t.Fatal("Session was not established")
+	}
+
+	// Abort the session from the PCRF.
+	asr := &ASR{
+		SessionID:         s.ID,
+		OriginHost:        pcrfSettings.OriginHost,
+		OriginRealm:       pcrfSettings.OriginRealm,
+		DestinationRealm:  pcscfSettings.OriginRealm,
+		DestinationHost:   pcscfSettings.OriginHost,
+		AuthApplicationID: ApplicationID,
+		AbortCause:        BearerReleased,
+	}
+	m, err := asr.Message()
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case pc := <-conns:
+		if _, err := m.WriteTo(pc); err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for AAR")
+	}
+	select {
+	case a := <-asas:
+		if a.ResultCode != diam.Success {
+			t.Fatalf("Unexpected ASA result: %d", a.ResultCode)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for ASA")
+	}
+	select {
+	case cause := <-aborted:
+		if cause != BearerReleased {
+			t.Fatalf("Unexpected Abort-Cause: %d", cause)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for OnAbort")
+	}
+	select {
+	case r := <-strs:
+		if r.SessionID != s.ID || r.TerminationCause != DiameterLogout {
+			t.Fatalf("Unexpected STR: %+v", r)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for STR")
+	}
+	if _, err := s.Authorize(newAudio()); err != ErrSessionClosed {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestSession_Authorize_Rejected(t *testing.T) {
+	pcrf := sm.New(pcrfSettings)
+	pcrf.HandleFunc("AAR", func(c diam.Conn, m *diam.Message) {
+		r, err := ParseAAR(m)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		a := &AAA{
+			SessionID:          r.SessionID,
+			AuthApplicationID:  ApplicationID,
+			OriginHost:         pcrfSettings.OriginHost,
+			OriginRealm:        pcrfSettings.OriginRealm,
+			ExperimentalResult: &tgpp.ExperimentalResult{VendorID: VendorID, ExperimentalResultCode: 5065},
+		}
+		if am, err := a.Message(m); err == nil {
+			am.WriteTo(c)
+		}
+	})
+	srv := diamtest.NewServer(pcrf, dict.Default)
+	defer srv.Close()
+	cli := NewClient(&sm.Client{
+		Handler:        sm.New(pcscfSettings),
+		RequestTimeout: time.Second,
+		AuthApplicationID: []*diam.AVP{
+			diam.NewAVP(avp.AuthApplicationID, avp.Mbit, 0, datatype.Unsigned32(ApplicationID)),
+		},
+	}, "test")
+	c, err := cli.Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	cli.Conn = c
+
+	s := cli.NewSession(ue)
+	_, err = s.Authorize(newAudio())
+	if re, ok := err.(*diam.ResultError); !ok || re.Code != 5065 {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := cli.Session(s.ID); ok {
+		t.Fatal("Rejected session was established")
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package s6a
+
+import (
+	"errors"
+	"net"
+)
+
+// ErrInvalidVector is returned by EUTRANVector.Validate when a field of
+// the vector has an invalid length.
+var ErrInvalidVector = errors.New("invalid E-UTRAN vector")
+
+// RequestedEUTRANAuthInfo is the Requested-EUTRAN-Authentication-Info
+// AVP, see 3GPP TS 29.272 section 7.3.11.
+type RequestedEUTRANAuthInfo struct {
+	NumberOfRequestedVectors   uint32 `avp:"Number-Of-Requested-Vectors,omitempty"`
+	ImmediateResponsePreferred uint32 `avp:"Immediate-Response-Preferred,omitempty"`
+	ResynchronizationInfo      []byte `avp:"Re-synchronization-Info,omitempty"`
+}
+
+// AuthenticationInfo is the Authentication-Info AVP, see 3GPP TS 29.272
+// section 7.3.17.
+type AuthenticationInfo struct {
+	EUTRANVector []*EUTRANVector `avp:"E-UTRAN-Vector,omitempty"`
+}
+
+// EUTRANVector is the E-UTRAN-Vector AVP, see 3GPP TS 29.272 section
+// 7.3.18.
+type EUTRANVector struct {
+	ItemNumber uint32 `avp:"Item-Number,omitempty"`
+	RAND       []byte `avp:"RAND"`
+	XRES       []byte `avp:"XRES"`
+	AUTN       []byte `avp:"AUTN"`
+	KASME      []byte `avp:"KASME"`
+}
+
+// Validate checks the lengths of the fields of the vector, as specified
+// by 3GPP TS 33.401.
+func (v *EUTRANVector) Validate() error {
+	if len(v.RAND) != 16 || len(v.AUTN) != 16 || len(v.KASME) != 32 ||
+		len(v.XRES) < 4 || len(v.XRES) > 16 {
+		return ErrInvalidVector
+	}
+	return nil
+}
+
+// SubscriptionData is the Subscription-Data AVP, see 3GPP TS 29.272
+// section 7.3.2.
+type SubscriptionData struct {
+	SubscriberStatus              *int32                   `avp:"Subscriber-Status,omitempty"`
+	MSISDN                        []byte                   `avp:"MSISDN,omitempty"`
+	NetworkAccessMode             *int32                   `avp:"Network-Access-Mode,omitempty"`
+	AccessRestrictionData         uint32                   `avp:"Access-Restriction-Data,omitempty"`
+	AMBR                          *AMBR                    `avp:"AMBR,omitempty"`
+	APNConfigurationProfile       *APNConfigurationProfile `avp:"APN-Configuration-Profile,omitempty"`
+	SubscribedPeriodicRAUTAUTimer uint32                   `avp:"Subscribed-Periodic-RAU-TAU-Timer,omitempty"`
+}
+
+// AddAPN adds the APN configuration to the profile of the subscription,
+// creating the profile if needed. The first APN added is the default.
+func (s *SubscriptionData) AddAPN(c *APNConfiguration) {
+	if s.APNConfigurationProfile == nil {
+		s.APNConfigurationProfile = &APNConfigurationProfile{
+			ContextIdentifier: c.ContextIdentifier,
+		}
+	}
+	p := s.APNConfigurationProfile
+	p.APNConfiguration = append(p.APNConfiguration, c)
+}
+
+// APN returns the configuration of the APN with the given
+// Service-Selection, or of the default APN if name is empty.
+func (s *SubscriptionData) APN(name string) (*APNConfiguration, bool) {
+	p := s.APNConfigurationProfile
+	if p == nil {
+		return nil, false
+	}
+	for _, c := range p.APNConfiguration {
+		if name == "" && c.ContextIdentifier == p.ContextIdentifier ||
+			name != "" && c.ServiceSelection == name {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// AMBR is the AMBR AVP, see 3GPP TS 29.272 section 7.3.41.
+type AMBR struct {
+	MaxRequestedBandwidthUL uint32 `avp:"Max-Requested-Bandwidth-UL"`
+	MaxRequestedBandwidthDL uint32 `avp:"Max-Requested-Bandwidth-DL"`
+}
+
+// APNConfigurationProfile is the APN-Configuration-Profile AVP, see 3GPP
+// TS 29.272 section 7.3.34. ContextIdentifier identifies the default
+// APN configuration.
+type APNConfigurationProfile struct {
+	ContextIdentifier                     uint32              `avp:"Context-Identifier"`
+	AllAPNConfigurationsIncludedIndicator int32               `avp:"All-APN-Configurations-Included-Indicator"`
+	APNConfiguration                      []*APNConfiguration `avp:"APN-Configuration"`
+}
+
+// APNConfiguration is the APN-Configuration AVP, see 3GPP TS 29.272
+// section 7.3.35.
+type APNConfiguration struct {
+	ContextIdentifier       uint32                   `avp:"Context-Identifier"`
+	ServedPartyIPAddress    []net.IP                 `avp:"Served-Party-IP-Address,omitempty"`
+	PDNType                 int32                    `avp:"PDN-Type"`
+	ServiceSelection        string                   `avp:"Service-Selection"`
+	EPSSubscribedQoSProfile *EPSSubscribedQoSProfile `avp:"EPS-Subscribed-QoS-Profile,omitempty"`
+	AMBR                    *AMBR                    `avp:"AMBR,omitempty"`
+}
+
+// EPSSubscribedQoSProfile is the EPS-Subscribed-QoS-Profile AVP, see
+// 3GPP TS 29.272 section 7.3.37.
+type EPSSubscribedQoSProfile struct {
+	QoSClassIdentifier          int32                       `avp:"QoS-Class-Identifier"`
+	AllocationRetentionPriority AllocationRetentionPriority `avp:"Allocation-Retention-Priority"`
+}
+
+// AllocationRetentionPriority is the Allocation-Retention-Priority AVP,
+// see 3GPP TS 29.272 section 7.3.40.
+type AllocationRetentionPriority struct {
+	PriorityLevel           uint32 `avp:"Priority-Level"`
+	PreemptionCapability    *int32 `avp:"Pre-emption-Capability,omitempty"`
+	PreemptionVulnerability *int32 `avp:"Pre-emption-Vulnerability,omitempty"`
+}
@@ -0,0 +1,34 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package s6a provides typed messages of the 3GPP S6a interface,
+// between the MME and the HSS, as specified by 3GPP TS 29.272.
+//
+// The S6a dictionary is part of dict.Default. The ULR/ULA, AIR/AIA,
+// PUR/PUA, CLR/CLA and IDR/IDA types map the AVPs of their commands to
+// Go structs. Their Message methods build a diam.Message with
+// diam.Message.Marshal, and the Parse functions decode one with
+// diam.Message.Unmarshal.
+//
+// Example of an MME requesting authentication vectors:
+//
+//	air := &s6a.AIR{
+//		SessionID:        sid,
+//		AuthSessionState: s6a.NoStateMaintained,
+//		OriginHost:       "mme.example.com",
+//		OriginRealm:      "example.com",
+//		DestinationRealm: "example.com",
+//		UserName:         imsi,
+//		VisitedPLMNID:    plmn,
+//		RequestedEUTRANAuthInfo: &s6a.RequestedEUTRANAuthInfo{
+//			NumberOfRequestedVectors: 1,
+//		},
+//	}
+//	m, err := air.Message()
+//	...
+//	aia, err := s6a.ParseAIA(answer)
+//	for _, v := range aia.Vectors() {
+//		// use v.RAND, v.XRES, v.AUTN and v.KASME
+//	}
+package s6a
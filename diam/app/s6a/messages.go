@@ -0,0 +1,307 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package s6a
+
+import (
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/app/internal/appmsg"
+	"github.com/omnicate/go-diameter/v4/diam/app/tgpp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// ULR is the Update-Location-Request, see 3GPP TS 29.272 section 7.2.3.
+type ULR struct {
+	SessionID                   datatype.UTF8String               `avp:"Session-Id"`
+	VendorSpecificApplicationID *tgpp.VendorSpecificApplicationID `avp:"Vendor-Specific-Application-Id,omitempty"`
+	AuthSessionState            int32                             `avp:"Auth-Session-State"`
+	OriginHost                  datatype.DiameterIdentity         `avp:"Origin-Host"`
+	OriginRealm                 datatype.DiameterIdentity         `avp:"Origin-Realm"`
+	DestinationHost             datatype.DiameterIdentity         `avp:"Destination-Host,omitempty"`
+	DestinationRealm            datatype.DiameterIdentity         `avp:"Destination-Realm"`
+	UserName                    string                            `avp:"User-Name"`
+	RATType                     int32                             `avp:"RAT-Type"`
+	ULRFlags                    uint32                            `avp:"ULR-Flags"`
+	VisitedPLMNID               []byte                            `avp:"Visited-PLMN-Id"`
+}
+
+// Message returns a new Update-Location-Request with the AVPs of r.
+func (r *ULR) Message() (*diam.Message, error) {
+	return appmsg.NewRequest(diam.UpdateLocation, ApplicationID, r)
+}
+
+// ParseULR parses the Update-Location-Request m.
+func ParseULR(m *diam.Message) (*ULR, error) {
+	r := &ULR{}
+	if err := m.Unmarshal(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// ULA is the Update-Location-Answer, see 3GPP TS 29.272 section 7.2.4.
+type ULA struct {
+	SessionID                   datatype.UTF8String               `avp:"Session-Id"`
+	VendorSpecificApplicationID *tgpp.VendorSpecificApplicationID `avp:"Vendor-Specific-Application-Id,omitempty"`
+	ResultCode                  uint32                            `avp:"Result-Code,omitempty"`
+	ExperimentalResult          *tgpp.ExperimentalResult          `avp:"Experimental-Result,omitempty"`
+	AuthSessionState            int32                             `avp:"Auth-Session-State"`
+	OriginHost                  datatype.DiameterIdentity         `avp:"Origin-Host"`
+	OriginRealm                 datatype.DiameterIdentity         `avp:"Origin-Realm"`
+	ULAFlags                    uint32                            `avp:"ULA-Flags,omitempty"`
+	SubscriptionData            *SubscriptionData                 `avp:"Subscription-Data,omitempty"`
+}
+
+// Message returns a new answer to the request m with the AVPs of a.
+func (a *ULA) Message(m *diam.Message) (*diam.Message, error) {
+	return appmsg.NewAnswer(m, a)
+}
+
+// ParseULA parses the Update-Location-Answer m.
+func ParseULA(m *diam.Message) (*ULA, error) {
+	a := &ULA{}
+	if err := m.Unmarshal(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// AIR is the Authentication-Information-Request, see 3GPP TS 29.272
+// section 7.2.5.
+type AIR struct {
+	SessionID                   datatype.UTF8String               `avp:"Session-Id"`
+	VendorSpecificApplicationID *tgpp.VendorSpecificApplicationID `avp:"Vendor-Specific-Application-Id,omitempty"`
+	AuthSessionState            int32                             `avp:"Auth-Session-State"`
+	OriginHost                  datatype.DiameterIdentity         `avp:"Origin-Host"`
+	OriginRealm                 datatype.DiameterIdentity         `avp:"Origin-Realm"`
+	DestinationHost             datatype.DiameterIdentity         `avp:"Destination-Host,omitempty"`
+	DestinationRealm            datatype.DiameterIdentity         `avp:"Destination-Realm"`
+	UserName                    string                            `avp:"User-Name"`
+	RequestedEUTRANAuthInfo     *RequestedEUTRANAuthInfo          `avp:"Requested-EUTRAN-Authentication-Info,omitempty"`
+	VisitedPLMNID               []byte                            `avp:"Visited-PLMN-Id"`
+}
+
+// Message returns a new Authentication-Information-Request with the
+// AVPs of r.
+func (r *AIR) Message() (*diam.Message, error) {
+	return appmsg.NewRequest(diam.AuthenticationInformation, ApplicationID, r)
+}
+
+// ParseAIR parses the Authentication-Information-Request m.
+func ParseAIR(m *diam.Message) (*AIR, error) {
+	r := &AIR{}
+	if err := m.Unmarshal(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// AIA is the Authentication-Information-Answer, see 3GPP TS 29.272
+// section 7.2.6.
+type AIA struct {
+	SessionID                   datatype.UTF8String               `avp:"Session-Id"`
+	VendorSpecificApplicationID *tgpp.VendorSpecificApplicationID `avp:"Vendor-Specific-Application-Id,omitempty"`
+	ResultCode                  uint32                            `avp:"Result-Code,omitempty"`
+	ExperimentalResult          *tgpp.ExperimentalResult          `avp:"Experimental-Result,omitempty"`
+	AuthSessionState            int32                             `avp:"Auth-Session-State"`
+	OriginHost                  datatype.DiameterIdentity         `avp:"Origin-Host"`
+	OriginRealm                 datatype.DiameterIdentity         `avp:"Origin-Realm"`
+	AuthenticationInfo          *AuthenticationInfo               `avp:"Authentication-Info,omitempty"`
+}
+
+// Vectors returns the E-UTRAN vectors of the answer.
+func (a *AIA) Vectors() []*EUTRANVector {
+	if a.AuthenticationInfo == nil {
+		return nil
+	}
+	return a.AuthenticationInfo.EUTRANVector
+}
+
+// AddVector adds the E-UTRAN vector v to the answer, numbering it.
+func (a *AIA) AddVector(v *EUTRANVector) {
+	if a.AuthenticationInfo == nil {
+		a.AuthenticationInfo = &AuthenticationInfo{}
+	}
+	info := a.AuthenticationInfo
+	v.ItemNumber = uint32(len(info.EUTRANVector) + 1)
+	info.EUTRANVector = append(info.EUTRANVector, v)
+}
+
+// Message returns a new answer to the request m with the AVPs of a.
+func (a *AIA) Message(m *diam.Message) (*diam.Message, error) {
+	return appmsg.NewAnswer(m, a)
+}
+
+// ParseAIA parses the Authentication-Information-Answer m.
+func ParseAIA(m *diam.Message) (*AIA, error) {
+	a := &AIA{}
+	if err := m.Unmarshal(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// PUR is the Purge-UE-Request, see 3GPP TS 29.272 section 7.2.13.
+type PUR struct {
+	SessionID                   datatype.UTF8String               `avp:"Session-Id"`
+	VendorSpecificApplicationID *tgpp.VendorSpecificApplicationID `avp:"Vendor-Specific-Application-Id,omitempty"`
+	AuthSessionState            int32                             `avp:"Auth-Session-State"`
+	OriginHost                  datatype.DiameterIdentity         `avp:"Origin-Host"`
+	OriginRealm                 datatype.DiameterIdentity         `avp:"Origin-Realm"`
+	DestinationHost             datatype.DiameterIdentity         `avp:"Destination-Host,omitempty"`
+	DestinationRealm            datatype.DiameterIdentity         `avp:"Destination-Realm"`
+	UserName                    string                            `avp:"User-Name"`
+	PURFlags                    uint32                            `avp:"PUR-Flags,omitempty"`
+}
+
+// Message returns a new Purge-UE-Request with the AVPs of r.
+func (r *PUR) Message() (*diam.Message, error) {
+	return appmsg.NewRequest(diam.PurgeUE, ApplicationID, r)
+}
+
+// ParsePUR parses the Purge-UE-Request m.
+func ParsePUR(m *diam.Message) (*PUR, error) {
+	r := &PUR{}
+	if err := m.Unmarshal(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// PUA is the Purge-UE-Answer, see 3GPP TS 29.272 section 7.2.14.
+type PUA struct {
+	SessionID                   datatype.UTF8String               `avp:"Session-Id"`
+	VendorSpecificApplicationID *tgpp.VendorSpecificApplicationID `avp:"Vendor-Specific-Application-Id,omitempty"`
+	ResultCode                  uint32                            `avp:"Result-Code,omitempty"`
+	ExperimentalResult          *tgpp.ExperimentalResult          `avp:"Experimental-Result,omitempty"`
+	AuthSessionState            int32                             `avp:"Auth-Session-State"`
+	OriginHost                  datatype.DiameterIdentity         `avp:"Origin-Host"`
+	OriginRealm                 datatype.DiameterIdentity         `avp:"Origin-Realm"`
+	PUAFlags                    uint32                            `avp:"PUA-Flags,omitempty"`
+}
+
+// Message returns a new answer to the request m with the AVPs of a.
+func (a *PUA) Message(m *diam.Message) (*diam.Message, error) {
+	return appmsg.NewAnswer(m, a)
+}
+
+// ParsePUA parses the Purge-UE-Answer m.
+func ParsePUA(m *diam.Message) (*PUA, error) {
+	a := &PUA{}
+	if err := m.Unmarshal(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// CLR is the Cancel-Location-Request, see 3GPP TS 29.272 section 7.2.7.
+type CLR struct {
+	SessionID                   datatype.UTF8String               `avp:"Session-Id"`
+	VendorSpecificApplicationID *tgpp.VendorSpecificApplicationID `avp:"Vendor-Specific-Application-Id,omitempty"`
+	AuthSessionState            int32                             `avp:"Auth-Session-State"`
+	OriginHost                  datatype.DiameterIdentity         `avp:"Origin-Host"`
+	OriginRealm                 datatype.DiameterIdentity         `avp:"Origin-Realm"`
+	DestinationHost             datatype.DiameterIdentity         `avp:"Destination-Host"`
+	DestinationRealm            datatype.DiameterIdentity         `avp:"Destination-Realm"`
+	UserName                    string                            `avp:"User-Name"`
+	CancellationType            int32                             `avp:"Cancellation-Type"`
+	CLRFlags                    uint32                            `avp:"CLR-Flags,omitempty"`
+}
+
+// Message returns a new Cancel-Location-Request with the AVPs of r.
+func (r *CLR) Message() (*diam.Message, error) {
+	return appmsg.NewRequest(diam.CancelLocation, ApplicationID, r)
+}
+
+// ParseCLR parses the Cancel-Location-Request m.
+func ParseCLR(m *diam.Message) (*CLR, error) {
+	r := &CLR{}
+	if err := m.Unmarshal(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// CLA is the Cancel-Location-Answer, see 3GPP TS 29.272 section 7.2.8.
+type CLA struct {
+	SessionID                   datatype.UTF8String               `avp:"Session-Id"`
+	VendorSpecificApplicationID *tgpp.VendorSpecificApplicationID `avp:"Vendor-Specific-Application-Id,omitempty"`
+	ResultCode                  uint32                            `avp:"Result-Code,omitempty"`
+	ExperimentalResult          *tgpp.ExperimentalResult          `avp:"Experimental-Result,omitempty"`
+	AuthSessionState            int32                             `avp:"Auth-Session-State"`
+	OriginHost                  datatype.DiameterIdentity         `avp:"Origin-Host"`
+	OriginRealm                 datatype.DiameterIdentity         `avp:"Origin-Realm"`
+}
+
+// Message returns a new answer to the request m with the AVPs of a.
+func (a *CLA) Message(m *diam.Message) (*diam.Message, error) {
+	return appmsg.NewAnswer(m, a)
+}
+
+// ParseCLA parses the Cancel-Location-Answer m.
+func ParseCLA(m *diam.Message) (*CLA, error) {
+	a := &CLA{}
+	if err := m.Unmarshal(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// IDR is the Insert-Subscriber-Data-Request, see 3GPP TS 29.272 section
+// 7.2.9.
+type IDR struct {
+	SessionID                   datatype.UTF8String               `avp:"Session-Id"`
+	VendorSpecificApplicationID *tgpp.VendorSpecificApplicationID `avp:"Vendor-Specific-Application-Id,omitempty"`
+	AuthSessionState            int32                             `avp:"Auth-Session-State"`
+	OriginHost                  datatype.DiameterIdentity         `avp:"Origin-Host"`
+	OriginRealm                 datatype.DiameterIdentity         `avp:"Origin-Realm"`
+	DestinationHost             datatype.DiameterIdentity         `avp:"Destination-Host"`
+	DestinationRealm            datatype.DiameterIdentity         `avp:"Destination-Realm"`
+	UserName                    string                            `avp:"User-Name"`
+	SubscriptionData            *SubscriptionData                 `avp:"Subscription-Data"`
+	IDRFlags                    uint32                            `avp:"IDR-Flags,omitempty"`
+}
+
+// Message returns a new Insert-Subscriber-Data-Request with the AVPs
+// of r.
+func (r *IDR) Message() (*diam.Message, error) {
+	return appmsg.NewRequest(diam.InsertSubscriberData, ApplicationID, r)
+}
+
+// ParseIDR parses the Insert-Subscriber-Data-Request m.
+func ParseIDR(m *diam.Message) (*IDR, error) {
+	r := &IDR{}
+	if err := m.Unmarshal(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// IDA is the Insert-Subscriber-Data-Answer, see 3GPP TS 29.272 section
+// 7.2.10.
+type IDA struct {
+	SessionID                   datatype.UTF8String               `avp:"Session-Id"`
+	VendorSpecificApplicationID *tgpp.VendorSpecificApplicationID `avp:"Vendor-Specific-Application-Id,omitempty"`
+	ResultCode                  uint32                            `avp:"Result-Code,omitempty"`
+	ExperimentalResult          *tgpp.ExperimentalResult          `avp:"Experimental-Result,omitempty"`
+	AuthSessionState            int32                             `avp:"Auth-Session-State"`
+	OriginHost                  datatype.DiameterIdentity         `avp:"Origin-Host"`
+	OriginRealm                 datatype.DiameterIdentity         `avp:"Origin-Realm"`
+	RATType                     *int32                            `avp:"RAT-Type,omitempty"`
+	IDAFlags                    uint32                            `avp:"IDA-Flags,omitempty"`
+}
+
+// Message returns a new answer to the request m with the AVPs of a.
+func (a *IDA) Message(m *diam.Message) (*diam.Message, error) {
+	return appmsg.NewAnswer(m, a)
+}
+
+// ParseIDA parses the Insert-Subscriber-Data-Answer m.
+func ParseIDA(m *diam.Message) (*IDA, error) {
+	a := &IDA{}
+	if err := m.Unmarshal(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package s6a
+
+import "github.com/omnicate/go-diameter/v4/diam"
+
+// ApplicationID is the S6a Application ID.
+const ApplicationID = diam.TGPP_S6A_APP_ID
+
+// VendorID is the 3GPP Vendor-Id.
+const VendorID = 10415
+
+// Values of the Auth-Session-State AVP. S6a sessions are stateless.
+const (
+	StateMaintained   = 0
+	NoStateMaintained = 1
+)
+
+// RATTypeEUTRAN is the value of the RAT-Type AVP for E-UTRAN.
+const RATTypeEUTRAN = 1004
+
+// Experimental-Result-Code values, see 3GPP TS 29.272 section 7.4.
+const (
+	ErrorUserUnknown              = 5001
+	ErrorRoamingNotAllowed        = 5004
+	ErrorUnknownEPSSubscription   = 5420
+	ErrorRATNotAllowed            = 5421
+	ErrorEquipmentUnknown         = 5422
+	ErrorUnknownServingNode       = 5423
+	AuthenticationDataUnavailable = 4181
+	ErrorCamelSubscriptionPresent = 4182
+)
+
+// Bits of the ULR-Flags AVP.
+const (
+	ULRSingleRegistrationIndication  = 1 << 0
+	ULRS6aS6dIndicator               = 1 << 1
+	ULRSkipSubscriberData            = 1 << 2
+	ULRGPRSSubscriptionDataIndicator = 1 << 3
+	ULRNodeTypeIndicator             = 1 << 4
+	ULRInitialAttachIndicator        = 1 << 5
+	ULRPSLCSNotSupportedByUE         = 1 << 6
+)
+
+// Bits of the ULA-Flags AVP.
+const (
+	ULASeparationIndication = 1 << 0
+)
+
+// Bits of the CLR-Flags AVP.
+const (
+	CLRS6aS6dIndicator  = 1 << 0
+	CLRReattachRequired = 1 << 1
+)
+
+// Bits of the IDR-Flags AVP.
+const (
+	IDRUEReachabilityRequest  = 1 << 0
+	IDRTADSDataRequest        = 1 << 1
+	IDREPSUserStateRequest    = 1 << 2
+	IDREPSLocationInfoRequest = 1 << 3
+	IDRCurrentLocationRequest = 1 << 4
+	IDRLocalTimeZoneRequest   = 1 << 5
+	IDRRemoveSMSRegistration  = 1 << 6
+)
+
+// Bits of the PUR-Flags and PUA-Flags AVPs.
+const (
+	PURUESPurgedInMME  = 1 << 0
+	PURUESPurgedInSGSN = 1 << 1
+	PUAFreezeMTMSI     = 1 << 0
+	PUAFreezePTMSI     = 1 << 1
+)
+
+// Values of the Cancellation-Type AVP.
+const (
+	MMEUpdateProcedure     = 0
+	SGSNUpdateProcedure    = 1
+	SubscriptionWithdrawal = 2
+	UpdateProcedureIWF     = 3
+	InitialAttachProcedure = 4
+)
+
+// Values of the PDN-Type AVP.
+const (
+	PDNTypeIPv4       = 0
+	PDNTypeIPv6       = 1
+	PDNTypeIPv4v6     = 2
+	PDNTypeIPv4OrIPv6 = 3
+)
@@ -0,0 +1,193 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package s6a
+
+import (
+	"bytes"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+)
+
+var plmn = []byte{0x00, 0xf1, 0x10}
+
+func newSubscriptionData() *SubscriptionData {
+	s := &SubscriptionData{
+		MSISDN:                []byte{0x21, 0x43},
+		AccessRestrictionData: 47,
+		AMBR:                  &AMBR{MaxRequestedBandwidthUL: 50000000, MaxRequestedBandwidthDL: 100000000},
+	}
+	for i, apn := range []string{"internet", "ims"} {
+		s.AddAPN(&APNConfiguration{
+			ContextIdentifier:    uint32(i + 1),
+			ServedPartyIPAddress: []net.IP{net.IPv4(10, 0, 0, byte(i+1)).To4()},
+			PDNType:              PDNTypeIPv4,
+			ServiceSelection:     apn,
+			EPSSubscribedQoSProfile: &EPSSubscribedQoSProfile{
+				QoSClassIdentifier: 9,
+				AllocationRetentionPriority: AllocationRetentionPriority{
+					PriorityLevel: 15,
+				},
+			},
+		})
+	}
+	return s
+}
+
+func TestULR(t *testing.T) {
+	ulr := &ULR{
+		SessionID:        "mme;1;1",
+		AuthSessionState: NoStateMaintained,
+		OriginHost:       "mme",
+		OriginRealm:      "localhost",
+		DestinationRealm: "localhost",
+		UserName:         "001010123456789",
+		RATType:          RATTypeEUTRAN,
+		ULRFlags:         ULRS6aS6dIndicator | ULRInitialAttachIndicator,
+		VisitedPLMNID:    plmn,
+	}
+	req, err := ulr.Message()
+	if err != nil {
+		t.Fatal(err)
+	}
+	req = diamtest.RoundTrip(t, req)
+	have, err := ParseULR(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ulr, have) {
+		t.Fatalf("Unexpected ULR.\nWant %#v\nHave %#v", ulr, have)
+	}
+
+	ula := &ULA{
+		SessionID:        ulr.SessionID,
+		ResultCode:       diam.Success,
+		AuthSessionState: NoStateMaintained,
+		OriginHost:       "hss",
+		OriginRealm:      "localhost",
+		SubscriptionData: newSubscriptionData(),
+	}
+	a, err := ula.Message(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	haveA, err := ParseULA(diamtest.RoundTrip(t, a))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ula, haveA) {
+		t.Fatalf("Unexpected ULA.\nWant %#v\nHave %#v", ula, haveA)
+	}
+	if c, ok := haveA.SubscriptionData.APN(""); !ok || c.ServiceSelection != "internet" {
+		t.Fatalf("Unexpected default APN: %#v", c)
+	}
+	if c, ok := haveA.SubscriptionData.APN("ims"); !ok || c.ContextIdentifier != 2 {
+		t.Fatalf("Unexpected ims APN: %#v", c)
+	}
+}
+
+func TestAIA(t *testing.T) {
+	air := &AIR{
+		SessionID:               "mme;1;2",
+		AuthSessionState:        NoStateMaintained,
+		OriginHost:              "mme",
+		OriginRealm:             "localhost",
+		DestinationRealm:        "localhost",
+		UserName:                "001010123456789",
+		RequestedEUTRANAuthInfo: &RequestedEUTRANAuthInfo{NumberOfRequestedVectors: 2},
+		VisitedPLMNID:           plmn,
+	}
+	req, err := air.Message()
+	if err != nil {
+		t.Fatal(err)
+	}
+	aia := &AIA{
+		SessionID:        air.SessionID,
+		ResultCode:       diam.Success,
+		AuthSessionState: NoStateMaintained,
+		OriginHost:       "hss",
+		OriginRealm:      "localhost",
+	}
+	for i := 0; i < 2; i++ {
+		aia.AddVector(&EUTRANVector{
+			RAND:  bytes.Repeat([]byte{byte(i)}, 16),
+			XRES:  bytes.Repeat([]byte{1}, 8),
+			AUTN:  bytes.Repeat([]byte{2}, 16),
+			KASME: bytes.Repeat([]byte{3}, 32),
+		})
+	}
+	a, err := aia.Message(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	have, err := ParseAIA(diamtest.RoundTrip(t, a))
+	if err != nil {
+		t.Fatal(err)
+	}
+	vectors := have.Vectors()
+	if len(vectors) != 2 {
+		t.Fatalf("Unexpected number of vectors. Want 2, have %d", len(vectors))
+	}
+	for i, v := range vectors {
+		if err = v.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(v, aia.Vectors()[i]) || v.ItemNumber != uint32(i+1) {
+			t.Fatalf("Unexpected vector %d: %#v", i, v)
+		}
+	}
+	if err = (&EUTRANVector{RAND: make([]byte, 8)}).Validate(); err != ErrInvalidVector {
+		t.Fatalf("Unexpected error. Want %v, have %v", ErrInvalidVector, err)
+	}
+}
+
+func TestIDR(t *testing.T) {
+	idr := &IDR{
+		SessionID:        "hss;1;1",
+		AuthSessionState: NoStateMaintained,
+		OriginHost:       "hss",
+		OriginRealm:      "localhost",
+		DestinationHost:  "mme",
+		DestinationRealm: "localhost",
+		UserName:         "001010123456789",
+		SubscriptionData: newSubscriptionData(),
+		IDRFlags:         IDREPSLocationInfoRequest,
+	}
+	req, err := idr.Message()
+	if err != nil {
+		t.Fatal(err)
+	}
+	req = diamtest.RoundTrip(t, req)
+	if req.Header.CommandCode != diam.InsertSubscriberData {
+		t.Fatalf("Unexpected command code: %d", req.Header.CommandCode)
+	}
+	have, err := ParseIDR(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(idr, have) {
+		t.Fatalf("Unexpected IDR.\nWant %#v\nHave %#v", idr, have)
+	}
+	a, err := (&IDA{
+		SessionID:        idr.SessionID,
+		ResultCode:       diam.Success,
+		AuthSessionState: NoStateMaintained,
+		OriginHost:       "mme",
+		OriginRealm:      "localhost",
+	}).Message(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ida, err := ParseIDA(diamtest.RoundTrip(t, a))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ida.ResultCode != diam.Success || ida.SessionID != idr.SessionID {
+		t.Fatalf("Unexpected IDA: %#v", ida)
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package s6b
+
+import (
+	"net"
+
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// MIP6AgentInfo is the MIP6-Agent-Info AVP, see RFC 5447 section 4.2.1.
+// On S6b it holds the address or the identity of the PDN GW.
+type MIP6AgentInfo struct {
+	MIPHomeAgentAddress []net.IP          `avp:"MIP-Home-Agent-Address,omitempty"`
+	MIPHomeAgentHost    *MIPHomeAgentHost `avp:"MIP-Home-Agent-Host,omitempty"`
+	MIP6HomeLinkPrefix  []byte            `avp:"MIP6-Home-Link-Prefix,omitempty"`
+}
+
+// MIPHomeAgentHost is the MIP-Home-Agent-Host AVP, see RFC 4004 section
+// 7.11.
+type MIPHomeAgentHost struct {
+	DestinationRealm datatype.DiameterIdentity `avp:"Destination-Realm"`
+	DestinationHost  datatype.DiameterIdentity `avp:"Destination-Host"`
+}
+
+// NewMIP6AgentInfo returns the MIP6-Agent-Info of the PDN GW with the
+// given Diameter identity and realm, and optional addresses.
+func NewMIP6AgentInfo(host, realm datatype.DiameterIdentity, addrs ...net.IP) *MIP6AgentInfo {
+	return &MIP6AgentInfo{
+		MIPHomeAgentAddress: addrs,
+		MIPHomeAgentHost: &MIPHomeAgentHost{
+			DestinationRealm: realm,
+			DestinationHost:  host,
+		},
+	}
+}
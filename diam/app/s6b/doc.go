@@ -0,0 +1,37 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package s6b provides typed messages of the 3GPP S6b interface, between
+// the PDN GW and the 3GPP AAA server, as specified by 3GPP TS 29.273.
+//
+// The S6b dictionary is part of dict.Default. AVPs it does not define,
+// such as APN-Configuration, are looked up in the SWx dictionary. The
+// AAR/AAA, DER/DEA, STR/STA, ASR/ASA and RAR/RAA types map the AVPs of
+// their commands to Go structs. Their Message methods build a
+// diam.Message with diam.Message.Marshal, and the Parse functions decode
+// one with diam.Message.Unmarshal.
+//
+// Example of a 3GPP AAA server authorizing PDN GW sessions:
+//
+//	mux.HandleFunc("AAR", func(c diam.Conn, m *diam.Message) {
+//		aar, err := s6b.ParseAAR(m)
+//		if err != nil {
+//			return
+//		}
+//		aaa := &s6b.AAA{
+//			SessionID:         aar.SessionID,
+//			AuthApplicationID: s6b.ApplicationID,
+//			AuthRequestType:   aar.AuthRequestType,
+//			ResultCode:        diam.Success,
+//			OriginHost:        "aaa.example.com",
+//			OriginRealm:       "example.com",
+//			ServiceSelection:  aar.ServiceSelection,
+//		}
+//		a, err := aaa.Message(m)
+//		if err != nil {
+//			return
+//		}
+//		a.WriteTo(c)
+//	})
+package s6b
@@ -0,0 +1,287 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package s6b
+
+import (
+	"net"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/app/internal/appmsg"
+	"github.com/omnicate/go-diameter/v4/diam/app/swx"
+	"github.com/omnicate/go-diameter/v4/diam/app/tgpp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// AAR is the AA-Request, see 3GPP TS 29.273 section 9.2.2.2.1.
+type AAR struct {
+	SessionID                datatype.UTF8String       `avp:"Session-Id"`
+	AuthApplicationID        uint32                    `avp:"Auth-Application-Id"`
+	OriginHost               datatype.DiameterIdentity `avp:"Origin-Host"`
+	OriginRealm              datatype.DiameterIdentity `avp:"Origin-Realm"`
+	DestinationHost          datatype.DiameterIdentity `avp:"Destination-Host,omitempty"`
+	DestinationRealm         datatype.DiameterIdentity `avp:"Destination-Realm"`
+	AuthRequestType          int32                     `avp:"Auth-Request-Type"`
+	UserName                 string                    `avp:"User-Name"`
+	RATType                  *int32                    `avp:"RAT-Type,omitempty"`
+	MIP6AgentInfo            *MIP6AgentInfo            `avp:"MIP6-Agent-Info,omitempty"`
+	MIP6FeatureVector        uint64                    `avp:"MIP6-Feature-Vector,omitempty"`
+	VisitedNetworkIdentifier []byte                    `avp:"Visited-Network-Identifier,omitempty"`
+	ServiceSelection         string                    `avp:"Service-Selection,omitempty"`
+	UELocalIPAddress         net.IP                    `avp:"UE-Local-IP-Address,omitempty"`
+}
+
+// Message returns a new AA-Request with the AVPs of r.
+func (r *AAR) Message() (*diam.Message, error) {
+	return appmsg.NewRequest(diam.AA, ApplicationID, r)
+}
+
+// ParseAAR parses the AA-Request m.
+func ParseAAR(m *diam.Message) (*AAR, error) {
+	r := &AAR{}
+	if err := m.Unmarshal(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// AAA is the AA-Answer, see 3GPP TS 29.273 section 9.2.2.2.2.
+type AAA struct {
+	SessionID             datatype.UTF8String       `avp:"Session-Id"`
+	AuthApplicationID     uint32                    `avp:"Auth-Application-Id"`
+	AuthRequestType       int32                     `avp:"Auth-Request-Type"`
+	ResultCode            uint32                    `avp:"Result-Code,omitempty"`
+	ExperimentalResult    *tgpp.ExperimentalResult  `avp:"Experimental-Result,omitempty"`
+	OriginHost            datatype.DiameterIdentity `avp:"Origin-Host"`
+	OriginRealm           datatype.DiameterIdentity `avp:"Origin-Realm"`
+	SessionTimeout        uint32                    `avp:"Session-Timeout,omitempty"`
+	AuthorizationLifetime uint32                    `avp:"Authorization-Lifetime,omitempty"`
+	AuthGracePeriod       uint32                    `avp:"Auth-Grace-Period,omitempty"`
+	MIP6FeatureVector     uint64                    `avp:"MIP6-Feature-Vector,omitempty"`
+	MobileNodeIdentifier  string                    `avp:"Mobile-Node-Identifier,omitempty"`
+	ServiceSelection      string                    `avp:"Service-Selection,omitempty"`
+	APNConfiguration      []*swx.APNConfiguration   `avp:"APN-Configuration,omitempty"`
+}
+
+// Message returns a new answer to the request m with the AVPs of a.
+func (a *AAA) Message(m *diam.Message) (*diam.Message, error) {
+	return appmsg.NewAnswer(m, a)
+}
+
+// ParseAAA parses the AA-Answer m.
+func ParseAAA(m *diam.Message) (*AAA, error) {
+	a := &AAA{}
+	if err := m.Unmarshal(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// DER is the Diameter-EAP-Request, see 3GPP TS 29.273 section 9.2.2.1.1.
+type DER struct {
+	SessionID                datatype.UTF8String       `avp:"Session-Id"`
+	AuthApplicationID        uint32                    `avp:"Auth-Application-Id"`
+	OriginHost               datatype.DiameterIdentity `avp:"Origin-Host"`
+	OriginRealm              datatype.DiameterIdentity `avp:"Origin-Realm"`
+	DestinationHost          datatype.DiameterIdentity `avp:"Destination-Host,omitempty"`
+	DestinationRealm         datatype.DiameterIdentity `avp:"Destination-Realm"`
+	AuthRequestType          int32                     `avp:"Auth-Request-Type"`
+	EAPPayload               []byte                    `avp:"EAP-Payload"`
+	UserName                 string                    `avp:"User-Name,omitempty"`
+	RATType                  *int32                    `avp:"RAT-Type,omitempty"`
+	ServiceSelection         string                    `avp:"Service-Selection,omitempty"`
+	MIP6FeatureVector        uint64                    `avp:"MIP6-Feature-Vector,omitempty"`
+	VisitedNetworkIdentifier []byte                    `avp:"Visited-Network-Identifier,omitempty"`
+	DERS6bFlags              uint32                    `avp:"DER-S6b-Flags,omitempty"`
+}
+
+// Message returns a new Diameter-EAP-Request with the AVPs of r.
+func (r *DER) Message() (*diam.Message, error) {
+	return appmsg.NewRequest(diam.DiameterEAP, ApplicationID, r)
+}
+
+// ParseDER parses the Diameter-EAP-Request m.
+func ParseDER(m *diam.Message) (*DER, error) {
+	r := &DER{}
+	if err := m.Unmarshal(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// DEA is the Diameter-EAP-Answer, see 3GPP TS 29.273 section 9.2.2.1.2.
+type DEA struct {
+	SessionID            datatype.UTF8String       `avp:"Session-Id"`
+	AuthApplicationID    uint32                    `avp:"Auth-Application-Id"`
+	AuthRequestType      int32                     `avp:"Auth-Request-Type"`
+	ResultCode           uint32                    `avp:"Result-Code,omitempty"`
+	ExperimentalResult   *tgpp.ExperimentalResult  `avp:"Experimental-Result,omitempty"`
+	OriginHost           datatype.DiameterIdentity `avp:"Origin-Host"`
+	OriginRealm          datatype.DiameterIdentity `avp:"Origin-Realm"`
+	UserName             string                    `avp:"User-Name,omitempty"`
+	EAPPayload           []byte                    `avp:"EAP-Payload,omitempty"`
+	EAPMasterSessionKey  []byte                    `avp:"EAP-Master-Session-Key,omitempty"`
+	MobileNodeIdentifier string                    `avp:"Mobile-Node-Identifier,omitempty"`
+	APNConfiguration     []*swx.APNConfiguration   `avp:"APN-Configuration,omitempty"`
+	MIP6FeatureVector    uint64                    `avp:"MIP6-Feature-Vector,omitempty"`
+	SessionTimeout       uint32                    `avp:"Session-Timeout,omitempty"`
+}
+
+// Message returns a new answer to the request m with the AVPs of a.
+func (a *DEA) Message(m *diam.Message) (*diam.Message, error) {
+	return appmsg.NewAnswer(m, a)
+}
+
+// ParseDEA parses the Diameter-EAP-Answer m.
+func ParseDEA(m *diam.Message) (*DEA, error) {
+	a := &DEA{}
+	if err := m.Unmarshal(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// STR is the Session-Termination-Request, see 3GPP TS 29.273 section 9.2.2.3.1.
+type STR struct {
+	SessionID         datatype.UTF8String       `avp:"Session-Id"`
+	AuthApplicationID uint32                    `avp:"Auth-Application-Id"`
+	OriginHost        datatype.DiameterIdentity `avp:"Origin-Host"`
+	OriginRealm       datatype.DiameterIdentity `avp:"Origin-Realm"`
+	DestinationRealm  datatype.DiameterIdentity `avp:"Destination-Realm"`
+	TerminationCause  int32                     `avp:"Termination-Cause"`
+	UserName          string                    `avp:"User-Name,omitempty"`
+}
+
+// Message returns a new Session-Termination-Request with the AVPs of r.
+func (r *STR) Message() (*diam.Message, error) {
+	return appmsg.NewRequest(diam.SessionTermination, ApplicationID, r)
+}
+
+// ParseSTR parses the Session-Termination-Request m.
+func ParseSTR(m *diam.Message) (*STR, error) {
+	r := &STR{}
+	if err := m.Unmarshal(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// STA is the Session-Termination-Answer, see 3GPP TS 29.273 section 9.2.2.3.2.
+type STA struct {
+	SessionID   datatype.UTF8String       `avp:"Session-Id"`
+	ResultCode  uint32                    `avp:"Result-Code"`
+	OriginHost  datatype.DiameterIdentity `avp:"Origin-Host"`
+	OriginRealm datatype.DiameterIdentity `avp:"Origin-Realm"`
+}
+
+// Message returns a new answer to the request m with the AVPs of a.
+func (a *STA) Message(m *diam.Message) (*diam.Message, error) {
+	return appmsg.NewAnswer(m, a)
+}
+
+// ParseSTA parses the Session-Termination-Answer m.
+func ParseSTA(m *diam.Message) (*STA, error) {
+	a := &STA{}
+	if err := m.Unmarshal(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// ASR is the Abort-Session-Request, see 3GPP TS 29.273 section 9.2.2.4.1.
+type ASR struct {
+	SessionID         datatype.UTF8String       `avp:"Session-Id"`
+	AuthApplicationID uint32                    `avp:"Auth-Application-Id"`
+	OriginHost        datatype.DiameterIdentity `avp:"Origin-Host"`
+	OriginRealm       datatype.DiameterIdentity `avp:"Origin-Realm"`
+	DestinationRealm  datatype.DiameterIdentity `avp:"Destination-Realm"`
+	DestinationHost   datatype.DiameterIdentity `avp:"Destination-Host"`
+	UserName          string                    `avp:"User-Name,omitempty"`
+	AuthSessionState  *int32                    `avp:"Auth-Session-State,omitempty"`
+}
+
+// Message returns a new Abort-Session-Request with the AVPs of r.
+func (r *ASR) Message() (*diam.Message, error) {
+	return appmsg.NewRequest(diam.AbortSession, ApplicationID, r)
+}
+
+// ParseASR parses the Abort-Session-Request m.
+func ParseASR(m *diam.Message) (*ASR, error) {
+	r := &ASR{}
+	if err := m.Unmarshal(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// ASA is the Abort-Session-Answer, see 3GPP TS 29.273 section 9.2.2.4.2.
+type ASA struct {
+	SessionID   datatype.UTF8String       `avp:"Session-Id"`
+	ResultCode  uint32                    `avp:"Result-Code"`
+	OriginHost  datatype.DiameterIdentity `avp:"Origin-Host"`
+	OriginRealm datatype.DiameterIdentity `avp:"Origin-Realm"`
+}
+
+// Message returns a new answer to the request m with the AVPs of a.
+func (a *ASA) Message(m *diam.Message) (*diam.Message, error) {
+	return appmsg.NewAnswer(m, a)
+}
+
+// ParseASA parses the Abort-Session-Answer m.
+func ParseASA(m *diam.Message) (*ASA, error) {
+	a := &ASA{}
+	if err := m.Unmarshal(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// RAR is the Re-Auth-Request, see 3GPP TS 29.273 section 9.2.2.5.1.
+type RAR struct {
+	SessionID         datatype.UTF8String       `avp:"Session-Id"`
+	AuthApplicationID uint32                    `avp:"Auth-Application-Id"`
+	OriginHost        datatype.DiameterIdentity `avp:"Origin-Host"`
+	OriginRealm       datatype.DiameterIdentity `avp:"Origin-Realm"`
+	DestinationRealm  datatype.DiameterIdentity `avp:"Destination-Realm"`
+	DestinationHost   datatype.DiameterIdentity `avp:"Destination-Host"`
+	ReAuthRequestType int32                     `avp:"Re-Auth-Request-Type"`
+	UserName          string                    `avp:"User-Name,omitempty"`
+	RARFlags          uint32                    `avp:"RAR-Flags,omitempty"`
+}
+
+// Message returns a new Re-Auth-Request with the AVPs of r.
+func (r *RAR) Message() (*diam.Message, error) {
+	return appmsg.NewRequest(diam.ReAuth, ApplicationID, r)
+}
+
+// ParseRAR parses the Re-Auth-Request m.
+func ParseRAR(m *diam.Message) (*RAR, error) {
+	r := &RAR{}
+	if err := m.Unmarshal(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// RAA is the Re-Auth-Answer, see 3GPP TS 29.273 section 9.2.2.5.2.
+type RAA struct {
+	SessionID   datatype.UTF8String       `avp:"Session-Id"`
+	ResultCode  uint32                    `avp:"Result-Code"`
+	OriginHost  datatype.DiameterIdentity `avp:"Origin-Host"`
+	OriginRealm datatype.DiameterIdentity `avp:"Origin-Realm"`
+}
+
+// Message returns a new answer to the request m with the AVPs of a.
+func (a *RAA) Message(m *diam.Message) (*diam.Message, error) {
+	return appmsg.NewAnswer(m, a)
+}
+
+// ParseRAA parses the Re-Auth-Answer m.
+func ParseRAA(m *diam.Message) (*RAA, error) {
+	a := &RAA{}
+	if err := m.Unmarshal(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
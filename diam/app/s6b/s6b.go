@@ -0,0 +1,57 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package s6b
+
+import "github.com/omnicate/go-diameter/v4/diam"
+
+// ApplicationID is the S6b Application ID.
+const ApplicationID = diam.TGPP_S6B_APP_ID
+
+// VendorID is the 3GPP Vendor-Id.
+const VendorID = 10415
+
+// Values of the Auth-Request-Type AVP.
+const (
+	AuthenticateOnly      = 1
+	AuthorizeOnly         = 2
+	AuthorizeAuthenticate = 3
+)
+
+// Values of the Auth-Session-State AVP.
+const (
+	StateMaintained   = 0
+	NoStateMaintained = 1
+)
+
+// Values of the Re-Auth-Request-Type AVP.
+const (
+	ReAuthAuthorizeOnly         = 0
+	ReAuthAuthorizeAuthenticate = 1
+)
+
+// DiameterLogout is the value of the Termination-Cause AVP sent in STRs
+// when the PDN GW releases the session.
+const DiameterLogout = 1
+
+// Bits of the MIP6-Feature-Vector AVP, see RFC 5447, RFC 5779 and 3GPP
+// TS 29.273 section 5.2.3.3.
+const (
+	MIP6Integrated           = 1 << 0
+	LocalHomeAgentAssignment = 1 << 1
+	PMIP6Supported           = 1 << 48
+	IP4HoASupported          = 1 << 49
+	LocalMAGRoutingSupported = 1 << 50
+	GTPv2Supported           = 1 << 55
+)
+
+// InitialAttachIndicator is the bit of the DER-S6b-Flags AVP set for
+// initial attaches.
+const InitialAttachIndicator = 1 << 0
+
+// Experimental-Result-Code values, see 3GPP TS 29.273 section 9.1.
+const (
+	ErrorUserUnknown           = 5001
+	ErrorUserNoAPNSubscription = 5451
+)
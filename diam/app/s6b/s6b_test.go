@@ -0,0 +1,163 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package s6b
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/app/swx"
+	"github.com/omnicate/go-diameter/v4/diam/app/tgpp"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+)
+
+func TestAAA(t *testing.T) {
+	aar := &AAR{
+		SessionID:         "pgw;1;2",
+		AuthApplicationID: ApplicationID,
+		OriginHost:        "pgw",
+		OriginRealm:       "epc",
+		DestinationRealm:  "epc",
+		AuthRequestType:   AuthorizeOnly,
+		UserName:          "0001010000000001@wlan",
+		MIP6AgentInfo:     NewMIP6AgentInfo("pgw", "epc", net.ParseIP("10.0.0.1")),
+		MIP6FeatureVector: GTPv2Supported,
+		ServiceSelection:  "internet",
+	}
+	req, err := aar.Message()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Header.CommandCode != diam.AA || req.Header.ApplicationID != ApplicationID {
+		t.Fatalf("Unexpected header: %s", req.Header)
+	}
+	if a, err := req.FindAVP(avp.MIP6AgentInfo, 0); err != nil {
+		t.Fatal(err)
+	} else if a.Flags&avp.Vbit != 0 {
+		t.Fatalf("Unexpected flags of MIP6-Agent-Info: %#x", a.Flags)
+	}
+	r, err := ParseAAR(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info := r.MIP6AgentInfo; info == nil || info.MIPHomeAgentHost == nil ||
+		info.MIPHomeAgentHost.DestinationHost != "pgw" || len(info.MIPHomeAgentAddress) != 1 ||
+		!info.MIPHomeAgentAddress[0].Equal(net.ParseIP("10.0.0.1")) {
+		t.Fatalf("Unexpected MIP6-Agent-Info: %+v", info)
+	}
+	if r.MIP6FeatureVector != GTPv2Supported || r.ServiceSelection != "internet" {
+		t.Fatalf("Unexpected AAR: %+v", r)
+	}
+	aaa := &AAA{
+		SessionID:         aar.SessionID,
+		AuthApplicationID: ApplicationID,
+		AuthRequestType:   AuthorizeOnly,
+		ResultCode:        diam.Success,
+		OriginHost:        "aaa",
+		OriginRealm:       "epc",
+		SessionTimeout:    3600,
+		APNConfiguration: []*swx.APNConfiguration{{
+			ContextIdentifier: 1,
+			ServiceSelection:  "internet",
+		}},
+	}
+	m, err := aaa.Message(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := ParseAAA(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code, _ := diam.ResultCode(m); code != diam.Success || a.SessionTimeout != 3600 || len(a.APNConfiguration) != 1 ||
+		a.APNConfiguration[0].ServiceSelection != "internet" {
+		t.Fatalf("Unexpected AAA: %+v", a)
+	}
+}
+
+func TestDEA(t *testing.T) {
+	der := &DER{
+		SessionID:         "pgw;1;3",
+		AuthApplicationID: ApplicationID,
+		OriginHost:        "pgw",
+		OriginRealm:       "epc",
+		DestinationRealm:  "epc",
+		AuthRequestType:   AuthorizeAuthenticate,
+		EAPPayload:        []byte{2, 0, 0, 5, 1},
+		UserName:          "0001010000000001@wlan",
+		DERS6bFlags:       InitialAttachIndicator,
+	}
+	req, err := der.Message()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Header.CommandCode != diam.DiameterEAP {
+		t.Fatalf("Unexpected header: %s", req.Header)
+	}
+	r, err := ParseDER(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(r.EAPPayload, der.EAPPayload) || r.DERS6bFlags != InitialAttachIndicator {
+		t.Fatalf("Unexpected DER: %+v", r)
+	}
+	dea := &DEA{
+		SessionID:         der.SessionID,
+		AuthApplicationID: ApplicationID,
+		AuthRequestType:   AuthorizeAuthenticate,
+		ExperimentalResult: &tgpp.ExperimentalResult{
+			VendorID:               VendorID,
+			ExperimentalResultCode: ErrorUserNoAPNSubscription,
+		},
+		OriginHost:  "aaa",
+		OriginRealm: "epc",
+		EAPPayload:  []byte{4, 0, 0, 4},
+	}
+	m, err := dea.Message(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := ParseDEA(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code, _ := diam.ResultCode(m); code != ErrorUserNoAPNSubscription || !bytes.Equal(a.EAPPayload, dea.EAPPayload) {
+		t.Fatalf("Unexpected DEA: %+v", a)
+	}
+}
+
+func TestSTR(t *testing.T) {
+	str := &STR{
+		SessionID:         "pgw;1;2",
+		AuthApplicationID: ApplicationID,
+		OriginHost:        "pgw",
+		OriginRealm:       "epc",
+		DestinationRealm:  "epc",
+		TerminationCause:  DiameterLogout,
+	}
+	req, err := str.Message()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sta := &STA{
+		SessionID:   str.SessionID,
+		ResultCode:  diam.Success,
+		OriginHost:  "aaa",
+		OriginRealm: "epc",
+	}
+	m, err := sta.Message(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := ParseSTA(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.ResultCode != diam.Success || m.Header.CommandCode != diam.SessionTermination {
+		t.Fatalf("Unexpected STA: %+v", a)
+	}
+}
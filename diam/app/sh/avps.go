@@ -0,0 +1,19 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sh
+
+// UserIdentity is the User-Identity AVP, see 3GPP TS 29.329 section
+// 6.3.1. It holds either a Public-Identity or an MSISDN.
+type UserIdentity struct {
+	PublicIdentity string `avp:"Public-Identity,omitempty"`
+	MSISDN         []byte `avp:"MSISDN,omitempty"`
+}
+
+// RepositoryDataID is the Repository-Data-ID AVP, see 3GPP TS 29.329
+// section 6.3.24.
+type RepositoryDataID struct {
+	ServiceIndication []byte `avp:"Service-Indication"`
+	SequenceNumber    uint32 `avp:"Sequence-Number"`
+}
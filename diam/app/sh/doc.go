@@ -0,0 +1,52 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package sh provides typed messages of the 3GPP Sh interface, between
+// application servers and the HSS, as specified by 3GPP TS 29.328 and
+// TS 29.329.
+//
+// The Sh dictionary is part of dict.Default. The UDR/UDA, PUR/PUA,
+// SNR/SNA and PNR/PNA types map the AVPs of their commands to Go
+// structs. Their Message methods build a diam.Message with
+// diam.Message.Marshal, and the Parse functions decode one with
+// diam.Message.Unmarshal.
+//
+// User-Data AVPs carry an Sh-Data XML document, decoded by ParseShData.
+// RepositoryData holds the transparent data an application server stores
+// in the HSS, and its Update method returns the next version to send in
+// a PUR.
+//
+// Example of an application server updating its repository data:
+//
+//	uda, err := sh.ParseUDA(m)
+//	if err != nil {
+//		return err
+//	}
+//	data, err := uda.ShData()
+//	if err != nil {
+//		return err
+//	}
+//	r, ok := data.Repository("my-service")
+//	if !ok {
+//		r = sh.NewRepositoryData("my-service", newData)
+//	} else {
+//		r = r.Update(newData)
+//	}
+//	userData, err := (&sh.ShData{
+//		RepositoryData: []*sh.RepositoryData{r},
+//	}).Bytes()
+//	if err != nil {
+//		return err
+//	}
+//	pur := &sh.PUR{
+//		SessionID:        sessionID,
+//		AuthSessionState: sh.NoStateMaintained,
+//		OriginHost:       "as.example.com",
+//		OriginRealm:      "example.com",
+//		DestinationRealm: "example.com",
+//		UserIdentity:     &sh.UserIdentity{PublicIdentity: impu},
+//		DataReference:    []int32{sh.RepositoryDataReference},
+//		UserData:         userData,
+//	}
+package sh
@@ -0,0 +1,275 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sh
+
+import (
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/app/internal/appmsg"
+	"github.com/omnicate/go-diameter/v4/diam/app/tgpp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// UDR is the User-Data-Request, see 3GPP TS 29.329 section 6.1.1.
+type UDR struct {
+	SessionID                   datatype.UTF8String               `avp:"Session-Id"`
+	VendorSpecificApplicationID *tgpp.VendorSpecificApplicationID `avp:"Vendor-Specific-Application-Id"`
+	AuthSessionState            int32                             `avp:"Auth-Session-State"`
+	OriginHost                  datatype.DiameterIdentity         `avp:"Origin-Host"`
+	OriginRealm                 datatype.DiameterIdentity         `avp:"Origin-Realm"`
+	DestinationHost             datatype.DiameterIdentity         `avp:"Destination-Host,omitempty"`
+	DestinationRealm            datatype.DiameterIdentity         `avp:"Destination-Realm"`
+	UserIdentity                *UserIdentity                     `avp:"User-Identity"`
+	WildcardedPublicIdentity    string                            `avp:"Wildcarded-Public-Identity,omitempty"`
+	ServerName                  string                            `avp:"Server-Name,omitempty"`
+	ServiceIndication           [][]byte                          `avp:"Service-Indication,omitempty"`
+	DataReference               []int32                           `avp:"Data-Reference"`
+	IdentitySet                 []int32                           `avp:"Identity-Set,omitempty"`
+	RequestedDomain             *int32                            `avp:"Requested-Domain,omitempty"`
+	CurrentLocation             *int32                            `avp:"Current-Location,omitempty"`
+	DSAITag                     [][]byte                          `avp:"DSAI-Tag,omitempty"`
+	UserName                    string                            `avp:"User-Name,omitempty"`
+	RequestedNodes              uint32                            `avp:"Requested-Nodes,omitempty"`
+	UDRFlags                    uint32                            `avp:"UDR-Flags,omitempty"`
+}
+
+// Message returns a new User-Data-Request with the AVPs of r.
+func (r *UDR) Message() (*diam.Message, error) {
+	return appmsg.NewRequest(diam.UserData, ApplicationID, r)
+}
+
+// ParseUDR parses the User-Data-Request m.
+func ParseUDR(m *diam.Message) (*UDR, error) {
+	r := &UDR{}
+	if err := m.Unmarshal(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// UDA is the User-Data-Answer, see 3GPP TS 29.329 section 6.1.2.
+type UDA struct {
+	SessionID                   datatype.UTF8String               `avp:"Session-Id"`
+	VendorSpecificApplicationID *tgpp.VendorSpecificApplicationID `avp:"Vendor-Specific-Application-Id"`
+	ResultCode                  uint32                            `avp:"Result-Code,omitempty"`
+	ExperimentalResult          *tgpp.ExperimentalResult          `avp:"Experimental-Result,omitempty"`
+	AuthSessionState            int32                             `avp:"Auth-Session-State"`
+	OriginHost                  datatype.DiameterIdentity         `avp:"Origin-Host"`
+	OriginRealm                 datatype.DiameterIdentity         `avp:"Origin-Realm"`
+	WildcardedPublicIdentity    string                            `avp:"Wildcarded-Public-Identity,omitempty"`
+	UserData                    []byte                            `avp:"User-Data,omitempty"`
+}
+
+// Message returns a new answer to the request m with the AVPs of a.
+func (a *UDA) Message(m *diam.Message) (*diam.Message, error) {
+	return appmsg.NewAnswer(m, a)
+}
+
+// ShData parses the Sh-Data document of the User-Data AVP.
+func (a *UDA) ShData() (*ShData, error) {
+	return ParseShData(a.UserData)
+}
+
+// ParseUDA parses the User-Data-Answer m.
+func ParseUDA(m *diam.Message) (*UDA, error) {
+	a := &UDA{}
+	if err := m.Unmarshal(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// PUR is the Profile-Update-Request, see 3GPP TS 29.329 section 6.1.3.
+type PUR struct {
+	SessionID                   datatype.UTF8String               `avp:"Session-Id"`
+	VendorSpecificApplicationID *tgpp.VendorSpecificApplicationID `avp:"Vendor-Specific-Application-Id"`
+	AuthSessionState            int32                             `avp:"Auth-Session-State"`
+	OriginHost                  datatype.DiameterIdentity         `avp:"Origin-Host"`
+	OriginRealm                 datatype.DiameterIdentity         `avp:"Origin-Realm"`
+	DestinationHost             datatype.DiameterIdentity         `avp:"Destination-Host,omitempty"`
+	DestinationRealm            datatype.DiameterIdentity         `avp:"Destination-Realm"`
+	UserIdentity                *UserIdentity                     `avp:"User-Identity"`
+	WildcardedPublicIdentity    string                            `avp:"Wildcarded-Public-Identity,omitempty"`
+	UserName                    string                            `avp:"User-Name,omitempty"`
+	DataReference               []int32                           `avp:"Data-Reference"`
+	UserData                    []byte                            `avp:"User-Data"`
+}
+
+// Message returns a new Profile-Update-Request with the AVPs of r.
+func (r *PUR) Message() (*diam.Message, error) {
+	return appmsg.NewRequest(diam.ProfileUpdate, ApplicationID, r)
+}
+
+// ShData parses the Sh-Data document of the User-Data AVP.
+func (r *PUR) ShData() (*ShData, error) {
+	return ParseShData(r.UserData)
+}
+
+// ParsePUR parses the Profile-Update-Request m.
+func ParsePUR(m *diam.Message) (*PUR, error) {
+	r := &PUR{}
+	if err := m.Unmarshal(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// PUA is the Profile-Update-Answer, see 3GPP TS 29.329 section 6.1.4.
+type PUA struct {
+	SessionID                   datatype.UTF8String               `avp:"Session-Id"`
+	VendorSpecificApplicationID *tgpp.VendorSpecificApplicationID `avp:"Vendor-Specific-Application-Id"`
+	ResultCode                  uint32                            `avp:"Result-Code,omitempty"`
+	ExperimentalResult          *tgpp.ExperimentalResult          `avp:"Experimental-Result,omitempty"`
+	AuthSessionState            int32                             `avp:"Auth-Session-State"`
+	OriginHost                  datatype.DiameterIdentity         `avp:"Origin-Host"`
+	OriginRealm                 datatype.DiameterIdentity         `avp:"Origin-Realm"`
+	WildcardedPublicIdentity    string                            `avp:"Wildcarded-Public-Identity,omitempty"`
+	RepositoryDataID            *RepositoryDataID                 `avp:"Repository-Data-ID,omitempty"`
+	DataReference               *int32                            `avp:"Data-Reference,omitempty"`
+}
+
+// Message returns a new answer to the request m with the AVPs of a.
+func (a *PUA) Message(m *diam.Message) (*diam.Message, error) {
+	return appmsg.NewAnswer(m, a)
+}
+
+// ParsePUA parses the Profile-Update-Answer m.
+func ParsePUA(m *diam.Message) (*PUA, error) {
+	a := &PUA{}
+	if err := m.Unmarshal(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// SNR is the Subscribe-Notifications-Request, see 3GPP TS 29.329 section 6.1.5.
+type SNR struct {
+	SessionID                   datatype.UTF8String               `avp:"Session-Id"`
+	VendorSpecificApplicationID *tgpp.VendorSpecificApplicationID `avp:"Vendor-Specific-Application-Id"`
+	AuthSessionState            int32                             `avp:"Auth-Session-State"`
+	OriginHost                  datatype.DiameterIdentity         `avp:"Origin-Host"`
+	OriginRealm                 datatype.DiameterIdentity         `avp:"Origin-Realm"`
+	DestinationHost             datatype.DiameterIdentity         `avp:"Destination-Host,omitempty"`
+	DestinationRealm            datatype.DiameterIdentity         `avp:"Destination-Realm"`
+	UserIdentity                *UserIdentity                     `avp:"User-Identity"`
+	WildcardedPublicIdentity    string                            `avp:"Wildcarded-Public-Identity,omitempty"`
+	ServiceIndication           [][]byte                          `avp:"Service-Indication,omitempty"`
+	SendDataIndication          *int32                            `avp:"Send-Data-Indication,omitempty"`
+	ServerName                  string                            `avp:"Server-Name,omitempty"`
+	SubsReqType                 int32                             `avp:"Subs-Req-Type"`
+	DataReference               []int32                           `avp:"Data-Reference"`
+	IdentitySet                 []int32                           `avp:"Identity-Set,omitempty"`
+	ExpiryTime                  *time.Time                        `avp:"Expiry-Time,omitempty"`
+	DSAITag                     [][]byte                          `avp:"DSAI-Tag,omitempty"`
+	OneTimeNotification         *int32                            `avp:"One-Time-Notification,omitempty"`
+	UserName                    string                            `avp:"User-Name,omitempty"`
+}
+
+// Message returns a new Subscribe-Notifications-Request with the AVPs of r.
+func (r *SNR) Message() (*diam.Message, error) {
+	return appmsg.NewRequest(diam.SubscribeNotifications, ApplicationID, r)
+}
+
+// ParseSNR parses the Subscribe-Notifications-Request m.
+func ParseSNR(m *diam.Message) (*SNR, error) {
+	r := &SNR{}
+	if err := m.Unmarshal(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// SNA is the Subscribe-Notifications-Answer, see 3GPP TS 29.329 section 6.1.6.
+type SNA struct {
+	SessionID                   datatype.UTF8String               `avp:"Session-Id"`
+	VendorSpecificApplicationID *tgpp.VendorSpecificApplicationID `avp:"Vendor-Specific-Application-Id"`
+	ResultCode                  uint32                            `avp:"Result-Code,omitempty"`
+	ExperimentalResult          *tgpp.ExperimentalResult          `avp:"Experimental-Result,omitempty"`
+	AuthSessionState            int32                             `avp:"Auth-Session-State"`
+	OriginHost                  datatype.DiameterIdentity         `avp:"Origin-Host"`
+	OriginRealm                 datatype.DiameterIdentity         `avp:"Origin-Realm"`
+	WildcardedPublicIdentity    string                            `avp:"Wildcarded-Public-Identity,omitempty"`
+	UserData                    []byte                            `avp:"User-Data,omitempty"`
+	ExpiryTime                  *time.Time                        `avp:"Expiry-Time,omitempty"`
+}
+
+// Message returns a new answer to the request m with the AVPs of a.
+func (a *SNA) Message(m *diam.Message) (*diam.Message, error) {
+	return appmsg.NewAnswer(m, a)
+}
+
+// ShData parses the Sh-Data document of the User-Data AVP.
+func (a *SNA) ShData() (*ShData, error) {
+	return ParseShData(a.UserData)
+}
+
+// ParseSNA parses the Subscribe-Notifications-Answer m.
+func ParseSNA(m *diam.Message) (*SNA, error) {
+	a := &SNA{}
+	if err := m.Unmarshal(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// PNR is the Push-Notification-Request, see 3GPP TS 29.329 section 6.1.7.
+type PNR struct {
+	SessionID                   datatype.UTF8String               `avp:"Session-Id"`
+	VendorSpecificApplicationID *tgpp.VendorSpecificApplicationID `avp:"Vendor-Specific-Application-Id"`
+	AuthSessionState            int32                             `avp:"Auth-Session-State"`
+	OriginHost                  datatype.DiameterIdentity         `avp:"Origin-Host"`
+	OriginRealm                 datatype.DiameterIdentity         `avp:"Origin-Realm"`
+	DestinationHost             datatype.DiameterIdentity         `avp:"Destination-Host,omitempty"`
+	DestinationRealm            datatype.DiameterIdentity         `avp:"Destination-Realm"`
+	UserIdentity                *UserIdentity                     `avp:"User-Identity"`
+	WildcardedPublicIdentity    string                            `avp:"Wildcarded-Public-Identity,omitempty"`
+	UserName                    string                            `avp:"User-Name,omitempty"`
+	UserData                    []byte                            `avp:"User-Data"`
+}
+
+// Message returns a new Push-Notification-Request with the AVPs of r.
+func (r *PNR) Message() (*diam.Message, error) {
+	return appmsg.NewRequest(diam.PushNotification, ApplicationID, r)
+}
+
+// ShData parses the Sh-Data document of the User-Data AVP.
+func (r *PNR) ShData() (*ShData, error) {
+	return ParseShData(r.UserData)
+}
+
+// ParsePNR parses the Push-Notification-Request m.
+func ParsePNR(m *diam.Message) (*PNR, error) {
+	r := &PNR{}
+	if err := m.Unmarshal(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// PNA is the Push-Notification-Answer, see 3GPP TS 29.329 section 6.1.8.
+type PNA struct {
+	SessionID                   datatype.UTF8String               `avp:"Session-Id"`
+	VendorSpecificApplicationID *tgpp.VendorSpecificApplicationID `avp:"Vendor-Specific-Application-Id"`
+	ResultCode                  uint32                            `avp:"Result-Code,omitempty"`
+	ExperimentalResult          *tgpp.ExperimentalResult          `avp:"Experimental-Result,omitempty"`
+	AuthSessionState            int32                             `avp:"Auth-Session-State"`
+	OriginHost                  datatype.DiameterIdentity         `avp:"Origin-Host"`
+	OriginRealm                 datatype.DiameterIdentity         `avp:"Origin-Realm"`
+}
+
+// Message returns a new answer to the request m with the AVPs of a.
+func (a *PNA) Message(m *diam.Message) (*diam.Message, error) {
+	return appmsg.NewAnswer(m, a)
+}
+
+// ParsePNA parses the Push-Notification-Answer m.
+func ParsePNA(m *diam.Message) (*PNA, error) {
+	a := &PNA{}
+	if err := m.Unmarshal(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
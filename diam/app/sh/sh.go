@@ -0,0 +1,102 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sh
+
+import "github.com/omnicate/go-diameter/v4/diam"
+
+// ApplicationID is the Sh Application ID.
+const ApplicationID = diam.TGPP_SH_APP_ID
+
+// VendorID is the 3GPP Vendor-Id.
+const VendorID = 10415
+
+// Values of the Auth-Session-State AVP. Sh sessions are stateless.
+const (
+	StateMaintained   = 0
+	NoStateMaintained = 1
+)
+
+// Values of the Data-Reference AVP.
+const (
+	RepositoryDataReference           = 0
+	IMSPublicIdentity                 = 10
+	IMSUserState                      = 11
+	SCSCFName                         = 12
+	InitialFilterCriteria             = 13
+	LocationInformation               = 14
+	UserState                         = 15
+	ChargingInformation               = 16
+	MSISDN                            = 17
+	PSIActivation                     = 18
+	DSAI                              = 19
+	ServiceLevelTraceInfo             = 21
+	IPAddressSecureBindingInformation = 22
+	ServicePriorityLevel              = 23
+	SMSRegistrationInfo               = 24
+	UEReachabilityForIP               = 25
+	TADSInformation                   = 26
+	STNSR                             = 27
+	UESRVCCCapability                 = 28
+	ExtendedPriority                  = 29
+	CSRN                              = 30
+	ReferenceLocationInformation      = 31
+	IMSI                              = 32
+	IMSPrivateUserIdentity            = 33
+	IMEISV                            = 34
+	UE5GSRVCCCapability               = 35
+)
+
+// Values of the Subs-Req-Type AVP.
+const (
+	Subscribe   = 0
+	Unsubscribe = 1
+)
+
+// Values of the Requested-Domain AVP.
+const (
+	CSDomain = 0
+	PSDomain = 1
+)
+
+// Values of the Identity-Set AVP.
+const (
+	AllIdentities        = 0
+	RegisteredIdentities = 1
+	ImplicitIdentities   = 2
+	AliasIdentities      = 3
+)
+
+// Values of the Send-Data-Indication AVP.
+const (
+	UserDataNotRequested = 0
+	UserDataRequested    = 1
+)
+
+// Values of the IMSUserState element of Sh-Data.
+const (
+	NotRegistered           = 0
+	Registered              = 1
+	RegisteredUnregServices = 2
+	AuthenticationPending   = 3
+)
+
+// Experimental-Result-Code values, see 3GPP TS 29.329 section 6.2.
+const (
+	ErrorUserDataNotAvailable     = 4100
+	ErrorPriorUpdateInProgress    = 4101
+	ErrorUserUnknown              = 5001
+	ErrorIdentitiesDontMatch      = 5002
+	ErrorTooMuchData              = 5008
+	ErrorFeatureUnsupported       = 5011
+	ErrorUserDataNotRecognized    = 5100
+	ErrorOperationNotAllowed      = 5101
+	ErrorUserDataCannotBeRead     = 5102
+	ErrorUserDataCannotBeModified = 5103
+	ErrorUserDataCannotBeNotified = 5104
+	ErrorTransparentDataOutOfSync = 5105
+	ErrorSubsDataAbsent           = 5106
+	ErrorNoSubscriptionToData     = 5107
+	ErrorDSAINotAvailable         = 5108
+)
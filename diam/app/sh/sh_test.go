@@ -0,0 +1,199 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sh
+
+import (
+	"testing"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/app/tgpp"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+)
+
+var shApp = &tgpp.VendorSpecificApplicationID{
+	VendorID:          VendorID,
+	AuthApplicationID: ApplicationID,
+}
+
+func newUDR() *UDR {
+	return &UDR{
+		SessionID:                   "as;1;2",
+		VendorSpecificApplicationID: shApp,
+		AuthSessionState:            NoStateMaintained,
+		OriginHost:                  "as",
+		OriginRealm:                 "ims",
+		DestinationRealm:            "ims",
+		UserIdentity:                &UserIdentity{PublicIdentity: "sip:001010000000001@ims"},
+		ServiceIndication:           [][]byte{[]byte("svc")},
+		DataReference:               []int32{RepositoryDataReference, IMSPublicIdentity},
+	}
+}
+
+func TestShData(t *testing.T) {
+	state := Registered
+	d := &ShData{
+		RepositoryData: []*RepositoryData{NewRepositoryData("svc", []byte("<a>1</a>"))},
+		ShIMSData:      &ShIMSData{SCSCFName: "sip:scscf.ims", IMSUserState: &state},
+	}
+	b, err := d.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := ParseShData(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, ok := p.Repository("svc")
+	if !ok || r.SequenceNumber != 0 || r.ServiceData == nil || string(r.ServiceData.Data) != "<a>1</a>" {
+		t.Fatalf("Unexpected repository data: %+v", r)
+	}
+	if p.ShIMSData == nil || p.ShIMSData.IMSUserState == nil || *p.ShIMSData.IMSUserState != Registered {
+		t.Fatalf("Unexpected Sh-IMS-Data: %+v", p.ShIMSData)
+	}
+	u := r.Update([]byte("<a>2</a>"))
+	if id := u.ID(); string(id.ServiceIndication) != "svc" || id.SequenceNumber != 1 {
+		t.Fatalf("Unexpected Repository-Data-ID: %+v", id)
+	}
+}
+
+func TestNextSequenceNumber(t *testing.T) {
+	for n, want := range map[uint32]uint32{0: 1, 1: 2, MaxSequenceNumber - 1: MaxSequenceNumber, MaxSequenceNumber: 1} {
+		if got := NextSequenceNumber(n); got != want {
+			t.Fatalf("NextSequenceNumber(%d) = %d, want %d", n, got, want)
+		}
+	}
+}
+
+func TestUDA(t *testing.T) {
+	req, err := newUDR().Message()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Header.CommandCode != diam.UserData || req.Header.ApplicationID != ApplicationID {
+		t.Fatalf("Unexpected header: %s", req.Header)
+	}
+	r, err := ParseUDR(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.UserIdentity == nil || r.UserIdentity.PublicIdentity != "sip:001010000000001@ims" ||
+		len(r.DataReference) != 2 || len(r.ServiceIndication) != 1 {
+		t.Fatalf("Unexpected UDR: %+v", r)
+	}
+	data, err := (&ShData{
+		RepositoryData: []*RepositoryData{NewRepositoryData("svc", []byte("x"))},
+	}).Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	uda := &UDA{
+		SessionID:                   r.SessionID,
+		VendorSpecificApplicationID: shApp,
+		ResultCode:                  diam.Success,
+		AuthSessionState:            NoStateMaintained,
+		OriginHost:                  "hss",
+		OriginRealm:                 "ims",
+		UserData:                    data,
+	}
+	m, err := uda.Message(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.FindAVP(avp.UserData, VendorID); err == nil {
+		t.Fatal("Unexpected Cx User-Data AVP")
+	}
+	if _, err := m.FindAVP(702, VendorID); err != nil {
+		t.Fatal(err)
+	}
+	a, err := ParseUDA(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code, _ := diam.ResultCode(m); code != diam.Success {
+		t.Fatalf("Unexpected result: %d", code)
+	}
+	d, err := a.ShData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := d.Repository("svc"); !ok {
+		t.Fatalf("Missing repository data: %+v", d)
+	}
+}
+
+func TestPUA_ExperimentalResult(t *testing.T) {
+	pur := &PUR{
+		SessionID:                   "as;1;3",
+		VendorSpecificApplicationID: shApp,
+		AuthSessionState:            NoStateMaintained,
+		OriginHost:                  "as",
+		OriginRealm:                 "ims",
+		DestinationRealm:            "ims",
+		UserIdentity:                &UserIdentity{MSISDN: []byte{0x21, 0x43}},
+		DataReference:               []int32{RepositoryDataReference},
+		UserData:                    []byte("<Sh-Data/>"),
+	}
+	req, err := pur.Message()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Header.CommandCode != diam.ProfileUpdate {
+		t.Fatalf("Unexpected header: %s", req.Header)
+	}
+	pua := &PUA{
+		SessionID:                   pur.SessionID,
+		VendorSpecificApplicationID: shApp,
+		ExperimentalResult: &tgpp.ExperimentalResult{
+			VendorID:               VendorID,
+			ExperimentalResultCode: ErrorTransparentDataOutOfSync,
+		},
+		AuthSessionState: NoStateMaintained,
+		OriginHost:       "hss",
+		OriginRealm:      "ims",
+		RepositoryDataID: &RepositoryDataID{ServiceIndication: []byte("svc"), SequenceNumber: 7},
+	}
+	m, err := pua.Message(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := ParsePUA(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code, _ := diam.ResultCode(m); code != ErrorTransparentDataOutOfSync {
+		t.Fatalf("Unexpected result: %d", code)
+	}
+	if id := a.RepositoryDataID; id == nil || string(id.ServiceIndication) != "svc" || id.SequenceNumber != 7 {
+		t.Fatalf("Unexpected Repository-Data-ID: %+v", id)
+	}
+}
+
+func TestSNR(t *testing.T) {
+	expiry := time.Unix(1600000000, 0)
+	snr := &SNR{
+		SessionID:                   "as;1;4",
+		VendorSpecificApplicationID: shApp,
+		AuthSessionState:            NoStateMaintained,
+		OriginHost:                  "as",
+		OriginRealm:                 "ims",
+		DestinationRealm:            "ims",
+		UserIdentity:                &UserIdentity{PublicIdentity: "sip:001010000000001@ims"},
+		SubsReqType:                 Subscribe,
+		DataReference:               []int32{IMSPublicIdentity},
+		ExpiryTime:                  &expiry,
+	}
+	m, err := snr.Message()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := ParseSNR(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.SubsReqType != Subscribe || r.ExpiryTime == nil || !r.ExpiryTime.Equal(expiry) {
+		t.Fatalf("Unexpected SNR: %+v", r)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sh
+
+import (
+	"bytes"
+	"encoding/xml"
+)
+
+// MaxSequenceNumber is the highest Sequence-Number of repository data.
+// The sequence number following it is 1, 0 being reserved for the
+// creation of the data.
+const MaxSequenceNumber = 65535
+
+// ShData is the Sh-Data XML document carried in User-Data AVPs, see
+// 3GPP TS 29.328 annex C and D. Only the most common elements are
+// mapped.
+type ShData struct {
+	XMLName           xml.Name           `xml:"Sh-Data"`
+	PublicIdentifiers *PublicIdentifiers `xml:"PublicIdentifiers,omitempty"`
+	RepositoryData    []*RepositoryData  `xml:"RepositoryData,omitempty"`
+	ShIMSData         *ShIMSData         `xml:"Sh-IMS-Data,omitempty"`
+}
+
+// PublicIdentifiers is the PublicIdentifiers element of Sh-Data.
+type PublicIdentifiers struct {
+	IMSPublicIdentity []string `xml:"IMSPublicIdentity,omitempty"`
+	MSISDN            []string `xml:"MSISDN,omitempty"`
+}
+
+// ShIMSData is the Sh-IMS-Data element of Sh-Data.
+type ShIMSData struct {
+	SCSCFName    string `xml:"S-CSCFName,omitempty"`
+	IMSUserState *int   `xml:"IMSUserState,omitempty"`
+}
+
+// RepositoryData is the RepositoryData element of Sh-Data, holding the
+// transparent data of an application server for a Service-Indication.
+type RepositoryData struct {
+	ServiceIndication string       `xml:"ServiceIndication"`
+	SequenceNumber    uint32       `xml:"SequenceNumber"`
+	ServiceData       *ServiceData `xml:"ServiceData,omitempty"`
+}
+
+// ServiceData is the ServiceData element of RepositoryData. Its content
+// is opaque to the HSS.
+type ServiceData struct {
+	Data []byte `xml:",innerxml"`
+}
+
+// ParseShData parses the content of a User-Data AVP.
+func ParseShData(b []byte) (*ShData, error) {
+	d := &ShData{}
+	if err := xml.Unmarshal(b, d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Bytes returns the Sh-Data document, to be sent in a User-Data AVP.
+func (d *ShData) Bytes() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	if err := xml.NewEncoder(&b).Encode(d); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// Repository returns the repository data of the given Service-Indication.
+func (d *ShData) Repository(serviceIndication string) (*RepositoryData, bool) {
+	for _, r := range d.RepositoryData {
+		if r.ServiceIndication == serviceIndication {
+			return r, true
+		}
+	}
+	return nil, false
+}
+
+// NewRepositoryData returns the repository data created with the given
+// Service-Indication and transparent data.
+func NewRepositoryData(serviceIndication string, data []byte) *RepositoryData {
+	return &RepositoryData{
+		ServiceIndication: serviceIndication,
+		ServiceData:       &ServiceData{Data: data},
+	}
+}
+
+// Update returns the next version of the repository data, with the
+// given transparent data and the next Sequence-Number.
+func (r *RepositoryData) Update(data []byte) *RepositoryData {
+	return &RepositoryData{
+		ServiceIndication: r.ServiceIndication,
+		SequenceNumber:    NextSequenceNumber(r.SequenceNumber),
+		ServiceData:       &ServiceData{Data: data},
+	}
+}
+
+// ID returns the Repository-Data-ID identifying this version of the
+// repository data.
+func (r *RepositoryData) ID() *RepositoryDataID {
+	return &RepositoryDataID{
+		ServiceIndication: []byte(r.ServiceIndication),
+		SequenceNumber:    r.SequenceNumber,
+	}
+}
+
+// NextSequenceNumber returns the Sequence-Number following n.
+func NextSequenceNumber(n uint32) uint32 {
+	if n >= MaxSequenceNumber {
+		return 1
+	}
+	return n + 1
+}
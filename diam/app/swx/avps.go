@@ -0,0 +1,141 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package swx
+
+import "net"
+
+// SIPAuthDataItem is the SIP-Auth-Data-Item AVP, see 3GPP TS 29.273
+// section 8.2.3.9.
+//
+// For EAP-AKA and EAP-AKA', SIP-Authenticate is the concatenation of RAND
+// and AUTN, and SIP-Authorization is XRES, or the concatenation of RAND
+// and AUTS in MARs requesting resynchronization. The keys are CK and IK,
+// or CK' and IK' for EAP-AKA'.
+type SIPAuthDataItem struct {
+	SIPItemNumber           uint32 `avp:"SIP-Item-Number,omitempty"`
+	SIPAuthenticationScheme string `avp:"SIP-Authentication-Scheme,omitempty"`
+	SIPAuthenticate         []byte `avp:"SIP-Authenticate,omitempty"`
+	SIPAuthorization        []byte `avp:"SIP-Authorization,omitempty"`
+	ConfidentialityKey      []byte `avp:"Confidentiality-Key,omitempty"`
+	IntegrityKey            []byte `avp:"Integrity-Key,omitempty"`
+}
+
+// NewAKAItem returns the item number n with the authentication vector of
+// the given scheme made of RAND, AUTN, XRES, CK and IK.
+func NewAKAItem(n uint32, scheme string, rand, autn, xres, ck, ik []byte) *SIPAuthDataItem {
+	return &SIPAuthDataItem{
+		SIPItemNumber:           n,
+		SIPAuthenticationScheme: scheme,
+		SIPAuthenticate:         append(append([]byte{}, rand...), autn...),
+		SIPAuthorization:        xres,
+		ConfidentialityKey:      ck,
+		IntegrityKey:            ik,
+	}
+}
+
+// NewResyncItem returns the item of a MAR requesting the
+// resynchronization of the sequence numbers of the HSS, with the RAND
+// of the failed challenge and the AUTS returned by the UE.
+func NewResyncItem(scheme string, rand, auts []byte) *SIPAuthDataItem {
+	return &SIPAuthDataItem{
+		SIPAuthenticationScheme: scheme,
+		SIPAuthorization:        append(append([]byte{}, rand...), auts...),
+	}
+}
+
+// RAND returns the RAND of the vector of the item.
+func (i *SIPAuthDataItem) RAND() []byte {
+	if len(i.SIPAuthenticate) < 16 {
+		return nil
+	}
+	return i.SIPAuthenticate[:16]
+}
+
+// AUTN returns the AUTN of the vector of the item.
+func (i *SIPAuthDataItem) AUTN() []byte {
+	if len(i.SIPAuthenticate) < 32 {
+		return nil
+	}
+	return i.SIPAuthenticate[16:32]
+}
+
+// Resync returns the RAND and AUTS of an item requesting
+// resynchronization.
+func (i *SIPAuthDataItem) Resync() (rand, auts []byte, ok bool) {
+	if len(i.SIPAuthenticate) != 0 || len(i.SIPAuthorization) != 30 {
+		return nil, nil, false
+	}
+	return i.SIPAuthorization[:16], i.SIPAuthorization[16:], true
+}
+
+// DeregistrationReason is the Deregistration-Reason AVP, see 3GPP TS
+// 29.273 section 8.2.3.18.
+type DeregistrationReason struct {
+	ReasonCode int32  `avp:"Reason-Code"`
+	ReasonInfo string `avp:"Reason-Info,omitempty"`
+}
+
+// SubscriptionID is the Subscription-Id AVP.
+type SubscriptionID struct {
+	SubscriptionIDType int32  `avp:"Subscription-Id-Type"`
+	SubscriptionIDData string `avp:"Subscription-Id-Data"`
+}
+
+// Non3GPPUserData is the Non-3GPP-User-Data AVP, see 3GPP TS 29.273
+// section 8.2.3.1.
+type Non3GPPUserData struct {
+	SubscriptionID     *SubscriptionID     `avp:"Subscription-Id,omitempty"`
+	Non3GPPIPAccess    *int32              `avp:"Non-3GPP-IP-Access,omitempty"`
+	Non3GPPIPAccessAPN *int32              `avp:"Non-3GPP-IP-Access-APN,omitempty"`
+	RATType            []int32             `avp:"RAT-Type,omitempty"`
+	SessionTimeout     uint32              `avp:"Session-Timeout,omitempty"`
+	MIP6FeatureVector  uint64              `avp:"MIP6-Feature-Vector,omitempty"`
+	AMBR               *AMBR               `avp:"AMBR,omitempty"`
+	ContextIdentifier  uint32              `avp:"Context-Identifier,omitempty"`
+	APNOIReplacement   string              `avp:"APN-OI-Replacement,omitempty"`
+	APNConfiguration   []*APNConfiguration `avp:"APN-Configuration,omitempty"`
+}
+
+// APN returns the APN configuration of the given Service-Selection.
+func (d *Non3GPPUserData) APN(name string) (*APNConfiguration, bool) {
+	for _, c := range d.APNConfiguration {
+		if c.ServiceSelection == name {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// AMBR is the AMBR AVP, see 3GPP TS 29.272 section 7.3.41.
+type AMBR struct {
+	MaxRequestedBandwidthUL uint32 `avp:"Max-Requested-Bandwidth-UL"`
+	MaxRequestedBandwidthDL uint32 `avp:"Max-Requested-Bandwidth-DL"`
+}
+
+// APNConfiguration is the APN-Configuration AVP, see 3GPP TS 29.272
+// section 7.3.35.
+type APNConfiguration struct {
+	ContextIdentifier       uint32                   `avp:"Context-Identifier"`
+	ServedPartyIPAddress    []net.IP                 `avp:"Served-Party-IP-Address,omitempty"`
+	PDNType                 int32                    `avp:"PDN-Type"`
+	ServiceSelection        string                   `avp:"Service-Selection"`
+	EPSSubscribedQoSProfile *EPSSubscribedQoSProfile `avp:"EPS-Subscribed-QoS-Profile,omitempty"`
+	AMBR                    *AMBR                    `avp:"AMBR,omitempty"`
+}
+
+// EPSSubscribedQoSProfile is the EPS-Subscribed-QoS-Profile AVP, see
+// 3GPP TS 29.272 section 7.3.37.
+type EPSSubscribedQoSProfile struct {
+	QoSClassIdentifier          int32                       `avp:"QoS-Class-Identifier"`
+	AllocationRetentionPriority AllocationRetentionPriority `avp:"Allocation-Retention-Priority"`
+}
+
+// AllocationRetentionPriority is the Allocation-Retention-Priority AVP,
+// see 3GPP TS 29.272 section 7.3.40.
+type AllocationRetentionPriority struct {
+	PriorityLevel           uint32 `avp:"Priority-Level"`
+	PreemptionCapability    *int32 `avp:"Pre-emption-Capability,omitempty"`
+	PreemptionVulnerability *int32 `avp:"Pre-emption-Vulnerability,omitempty"`
+}
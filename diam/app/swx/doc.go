@@ -0,0 +1,37 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package swx provides typed messages of the 3GPP SWx interface, between
+// the 3GPP AAA server and the HSS, as specified by 3GPP TS 29.273.
+//
+// The SWx dictionary is part of dict.Default. The MAR/MAA, SAR/SAA,
+// RTR/RTA and PPR/PPA types map the AVPs of their commands to Go
+// structs. Their Message methods build a diam.Message with
+// diam.Message.Marshal, and the Parse functions decode one with
+// diam.Message.Unmarshal.
+//
+// SIPAuthDataItem carries the EAP-AKA and EAP-AKA' authentication
+// vectors, and Non3GPPUserData the subscription of the user for
+// non-3GPP access.
+//
+// Example of a 3GPP AAA server fetching vectors from the HSS:
+//
+//	mar := &swx.MAR{
+//		SessionID:        sessionID,
+//		AuthSessionState: swx.NoStateMaintained,
+//		OriginHost:       "aaa.example.com",
+//		OriginRealm:      "example.com",
+//		DestinationRealm: "example.com",
+//		UserName:         nai,
+//		SIPAuthDataItem: &swx.SIPAuthDataItem{
+//			SIPAuthenticationScheme: swx.EAPAKA,
+//		},
+//		SIPNumberAuthItems: 1,
+//	}
+//	m, err := mar.Message()
+//	if err != nil {
+//		return err
+//	}
+//	m.WriteTo(c)
+package swx
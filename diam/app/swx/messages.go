@@ -0,0 +1,247 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package swx
+
+import (
+	"sort"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/app/internal/appmsg"
+	"github.com/omnicate/go-diameter/v4/diam/app/tgpp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// MAR is the Multimedia-Authentication-Request, see 3GPP TS 29.273 section 8.2.2.1.
+type MAR struct {
+	SessionID                   datatype.UTF8String               `avp:"Session-Id"`
+	VendorSpecificApplicationID *tgpp.VendorSpecificApplicationID `avp:"Vendor-Specific-Application-Id"`
+	AuthSessionState            int32                             `avp:"Auth-Session-State"`
+	OriginHost                  datatype.DiameterIdentity         `avp:"Origin-Host"`
+	OriginRealm                 datatype.DiameterIdentity         `avp:"Origin-Realm"`
+	DestinationHost             datatype.DiameterIdentity         `avp:"Destination-Host,omitempty"`
+	DestinationRealm            datatype.DiameterIdentity         `avp:"Destination-Realm"`
+	UserName                    string                            `avp:"User-Name"`
+	RATType                     *int32                            `avp:"RAT-Type,omitempty"`
+	ANID                        string                            `avp:"ANID,omitempty"`
+	VisitedNetworkIdentifier    []byte                            `avp:"Visited-Network-Identifier,omitempty"`
+	SIPAuthDataItem             *SIPAuthDataItem                  `avp:"SIP-Auth-Data-Item"`
+	SIPNumberAuthItems          uint32                            `avp:"SIP-Number-Auth-Items"`
+}
+
+// Message returns a new Multimedia-Authentication-Request with the AVPs of r.
+func (r *MAR) Message() (*diam.Message, error) {
+	return appmsg.NewRequest(diam.MultimediaAuthentication, ApplicationID, r)
+}
+
+// ParseMAR parses the Multimedia-Authentication-Request m.
+func ParseMAR(m *diam.Message) (*MAR, error) {
+	r := &MAR{}
+	if err := m.Unmarshal(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// MAA is the Multimedia-Authentication-Answer, see 3GPP TS 29.273 section 8.2.2.1.
+type MAA struct {
+	SessionID                   datatype.UTF8String               `avp:"Session-Id"`
+	VendorSpecificApplicationID *tgpp.VendorSpecificApplicationID `avp:"Vendor-Specific-Application-Id"`
+	ResultCode                  uint32                            `avp:"Result-Code,omitempty"`
+	ExperimentalResult          *tgpp.ExperimentalResult          `avp:"Experimental-Result,omitempty"`
+	AuthSessionState            int32                             `avp:"Auth-Session-State"`
+	OriginHost                  datatype.DiameterIdentity         `avp:"Origin-Host"`
+	OriginRealm                 datatype.DiameterIdentity         `avp:"Origin-Realm"`
+	UserName                    string                            `avp:"User-Name,omitempty"`
+	SIPNumberAuthItems          uint32                            `avp:"SIP-Number-Auth-Items,omitempty"`
+	SIPAuthDataItem             []*SIPAuthDataItem                `avp:"SIP-Auth-Data-Item,omitempty"`
+	TGPPAAAServerName           datatype.DiameterIdentity         `avp:"TGPP-AAA-Server-Name,omitempty"`
+}
+
+// Message returns a new answer to the request m with the AVPs of a.
+func (a *MAA) Message(m *diam.Message) (*diam.Message, error) {
+	return appmsg.NewAnswer(m, a)
+}
+
+// Items returns the SIP-Auth-Data-Items of the answer sorted by
+// SIP-Item-Number.
+func (a *MAA) Items() []*SIPAuthDataItem {
+	items := append([]*SIPAuthDataItem{}, a.SIPAuthDataItem...)
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].SIPItemNumber < items[j].SIPItemNumber
+	})
+	return items
+}
+
+// ParseMAA parses the Multimedia-Authentication-Answer m.
+func ParseMAA(m *diam.Message) (*MAA, error) {
+	a := &MAA{}
+	if err := m.Unmarshal(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// SAR is the Server-Assignment-Request, see 3GPP TS 29.273 section 8.2.2.3.
+type SAR struct {
+	SessionID                   datatype.UTF8String               `avp:"Session-Id"`
+	VendorSpecificApplicationID *tgpp.VendorSpecificApplicationID `avp:"Vendor-Specific-Application-Id"`
+	AuthSessionState            int32                             `avp:"Auth-Session-State"`
+	OriginHost                  datatype.DiameterIdentity         `avp:"Origin-Host"`
+	OriginRealm                 datatype.DiameterIdentity         `avp:"Origin-Realm"`
+	DestinationHost             datatype.DiameterIdentity         `avp:"Destination-Host,omitempty"`
+	DestinationRealm            datatype.DiameterIdentity         `avp:"Destination-Realm"`
+	UserName                    string                            `avp:"User-Name"`
+	ServiceSelection            string                            `avp:"Service-Selection,omitempty"`
+	ContextIdentifier           uint32                            `avp:"Context-Identifier,omitempty"`
+	VisitedNetworkIdentifier    []byte                            `avp:"Visited-Network-Identifier,omitempty"`
+	ServerAssignmentType        int32                             `avp:"Server-Assignment-Type"`
+}
+
+// Message returns a new Server-Assignment-Request with the AVPs of r.
+func (r *SAR) Message() (*diam.Message, error) {
+	return appmsg.NewRequest(diam.ServerAssignment, ApplicationID, r)
+}
+
+// ParseSAR parses the Server-Assignment-Request m.
+func ParseSAR(m *diam.Message) (*SAR, error) {
+	r := &SAR{}
+	if err := m.Unmarshal(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// SAA is the Server-Assignment-Answer, see 3GPP TS 29.273 section 8.2.2.3.
+type SAA struct {
+	SessionID                   datatype.UTF8String               `avp:"Session-Id"`
+	VendorSpecificApplicationID *tgpp.VendorSpecificApplicationID `avp:"Vendor-Specific-Application-Id"`
+	ResultCode                  uint32                            `avp:"Result-Code,omitempty"`
+	ExperimentalResult          *tgpp.ExperimentalResult          `avp:"Experimental-Result,omitempty"`
+	AuthSessionState            int32                             `avp:"Auth-Session-State"`
+	OriginHost                  datatype.DiameterIdentity         `avp:"Origin-Host"`
+	OriginRealm                 datatype.DiameterIdentity         `avp:"Origin-Realm"`
+	UserName                    string                            `avp:"User-Name,omitempty"`
+	Non3GPPUserData             *Non3GPPUserData                  `avp:"Non-3GPP-User-Data,omitempty"`
+	TGPPAAAServerName           datatype.DiameterIdentity         `avp:"TGPP-AAA-Server-Name,omitempty"`
+}
+
+// Message returns a new answer to the request m with the AVPs of a.
+func (a *SAA) Message(m *diam.Message) (*diam.Message, error) {
+	return appmsg.NewAnswer(m, a)
+}
+
+// ParseSAA parses the Server-Assignment-Answer m.
+func ParseSAA(m *diam.Message) (*SAA, error) {
+	a := &SAA{}
+	if err := m.Unmarshal(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// RTR is the Registration-Termination-Request, see 3GPP TS 29.273 section 8.2.2.4.
+type RTR struct {
+	SessionID                   datatype.UTF8String               `avp:"Session-Id"`
+	VendorSpecificApplicationID *tgpp.VendorSpecificApplicationID `avp:"Vendor-Specific-Application-Id"`
+	AuthSessionState            int32                             `avp:"Auth-Session-State"`
+	OriginHost                  datatype.DiameterIdentity         `avp:"Origin-Host"`
+	OriginRealm                 datatype.DiameterIdentity         `avp:"Origin-Realm"`
+	DestinationHost             datatype.DiameterIdentity         `avp:"Destination-Host,omitempty"`
+	DestinationRealm            datatype.DiameterIdentity         `avp:"Destination-Realm"`
+	UserName                    string                            `avp:"User-Name"`
+	DeregistrationReason        *DeregistrationReason             `avp:"Deregistration-Reason"`
+}
+
+// Message returns a new Registration-Termination-Request with the AVPs of r.
+func (r *RTR) Message() (*diam.Message, error) {
+	return appmsg.NewRequest(diam.RegistrationTermination, ApplicationID, r)
+}
+
+// ParseRTR parses the Registration-Termination-Request m.
+func ParseRTR(m *diam.Message) (*RTR, error) {
+	r := &RTR{}
+	if err := m.Unmarshal(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// RTA is the Registration-Termination-Answer, see 3GPP TS 29.273 section 8.2.2.4.
+type RTA struct {
+	SessionID                   datatype.UTF8String               `avp:"Session-Id"`
+	VendorSpecificApplicationID *tgpp.VendorSpecificApplicationID `avp:"Vendor-Specific-Application-Id"`
+	ResultCode                  uint32                            `avp:"Result-Code,omitempty"`
+	ExperimentalResult          *tgpp.ExperimentalResult          `avp:"Experimental-Result,omitempty"`
+	AuthSessionState            int32                             `avp:"Auth-Session-State"`
+	OriginHost                  datatype.DiameterIdentity         `avp:"Origin-Host"`
+	OriginRealm                 datatype.DiameterIdentity         `avp:"Origin-Realm"`
+}
+
+// Message returns a new answer to the request m with the AVPs of a.
+func (a *RTA) Message(m *diam.Message) (*diam.Message, error) {
+	return appmsg.NewAnswer(m, a)
+}
+
+// ParseRTA parses the Registration-Termination-Answer m.
+func ParseRTA(m *diam.Message) (*RTA, error) {
+	a := &RTA{}
+	if err := m.Unmarshal(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// PPR is the Push-Profile-Request, see 3GPP TS 29.273 section 8.2.2.2.
+type PPR struct {
+	SessionID                   datatype.UTF8String               `avp:"Session-Id"`
+	VendorSpecificApplicationID *tgpp.VendorSpecificApplicationID `avp:"Vendor-Specific-Application-Id"`
+	AuthSessionState            int32                             `avp:"Auth-Session-State"`
+	OriginHost                  datatype.DiameterIdentity         `avp:"Origin-Host"`
+	OriginRealm                 datatype.DiameterIdentity         `avp:"Origin-Realm"`
+	DestinationHost             datatype.DiameterIdentity         `avp:"Destination-Host,omitempty"`
+	DestinationRealm            datatype.DiameterIdentity         `avp:"Destination-Realm"`
+	UserName                    string                            `avp:"User-Name"`
+	Non3GPPUserData             *Non3GPPUserData                  `avp:"Non-3GPP-User-Data,omitempty"`
+	PPRFlags                    uint32                            `avp:"PPR-Flags,omitempty"`
+}
+
+// Message returns a new Push-Profile-Request with the AVPs of r.
+func (r *PPR) Message() (*diam.Message, error) {
+	return appmsg.NewRequest(diam.PushProfile, ApplicationID, r)
+}
+
+// ParsePPR parses the Push-Profile-Request m.
+func ParsePPR(m *diam.Message) (*PPR, error) {
+	r := &PPR{}
+	if err := m.Unmarshal(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// PPA is the Push-Profile-Answer, see 3GPP TS 29.273 section 8.2.2.2.
+type PPA struct {
+	SessionID                   datatype.UTF8String               `avp:"Session-Id"`
+	VendorSpecificApplicationID *tgpp.VendorSpecificApplicationID `avp:"Vendor-Specific-Application-Id"`
+	ResultCode                  uint32                            `avp:"Result-Code,omitempty"`
+	ExperimentalResult          *tgpp.ExperimentalResult          `avp:"Experimental-Result,omitempty"`
+	AuthSessionState            int32                             `avp:"Auth-Session-State"`
+	OriginHost                  datatype.DiameterIdentity         `avp:"Origin-Host"`
+	OriginRealm                 datatype.DiameterIdentity         `avp:"Origin-Realm"`
+}
+
+// Message returns a new answer to the request m with the AVPs of a.
+func (a *PPA) Message(m *diam.Message) (*diam.Message, error) {
+	return appmsg.NewAnswer(m, a)
+}
+
+// ParsePPA parses the Push-Profile-Answer m.
+func ParsePPA(m *diam.Message) (*PPA, error) {
+	a := &PPA{}
+	if err := m.Unmarshal(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package swx
+
+import "github.com/omnicate/go-diameter/v4/diam"
+
+// ApplicationID is the SWx Application ID.
+const ApplicationID = diam.TGPP_SWX_APP_ID
+
+// VendorID is the 3GPP Vendor-Id.
+const VendorID = 10415
+
+// Values of the Auth-Session-State AVP. SWx sessions are stateless.
+const (
+	StateMaintained   = 0
+	NoStateMaintained = 1
+)
+
+// Values of the SIP-Authentication-Scheme AVP for non-3GPP access, see
+// 3GPP TS 29.273 section 8.2.3.9.
+const (
+	EAPAKA      = "EAP-AKA"
+	EAPAKAPrime = "EAP-AKA'"
+)
+
+// Values of the RAT-Type AVP for non-3GPP access.
+const (
+	RATTypeWLAN    = 0
+	RATTypeVirtual = 1
+	RATTypeEHRPD   = 2003
+)
+
+// Values of the Server-Assignment-Type AVP used on SWx, see 3GPP TS
+// 29.273 section 8.2.3.12.
+const (
+	Registration                 = 1
+	TimeoutDeregistration        = 4
+	UserDeregistration           = 5
+	AdministrativeDeregistration = 8
+	AAAUserDataRequest           = 12
+	PGWUpdate                    = 13
+)
+
+// Values of the Reason-Code AVP of Deregistration-Reason.
+const (
+	PermanentTermination = 0
+	NewServerAssignment  = 1
+	ServerChange         = 2
+	RemoveSCSCF          = 3
+)
+
+// Values of the Non-3GPP-IP-Access AVP.
+const (
+	Non3GPPSubscriptionAllowed = 0
+	Non3GPPSubscriptionBarred  = 1
+)
+
+// Values of the Non-3GPP-IP-Access-APN AVP.
+const (
+	Non3GPPAPNsEnable  = 0
+	Non3GPPAPNsDisable = 1
+)
+
+// Bits of the PPR-Flags AVP.
+const (
+	PPRResetIndication          = 1 << 0
+	PPRAccessNetworkInfoRequest = 1 << 1
+)
+
+// Experimental-Result-Code values, see 3GPP TS 29.273 section 8.1.
+const (
+	ErrorUserUnknown               = 5001
+	ErrorIdentityNotRegistered     = 5003
+	ErrorRoamingNotAllowed         = 5004
+	ErrorIdentityAlreadyRegistered = 5005
+	ErrorUserNoNon3GPPSubscription = 5450
+	ErrorUserNoAPNSubscription     = 5451
+	ErrorRATTypeNotAllowed         = 5452
+)
@@ -0,0 +1,199 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package swx
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/app/tgpp"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+)
+
+var swxApp = &tgpp.VendorSpecificApplicationID{
+	VendorID:          VendorID,
+	AuthApplicationID: ApplicationID,
+}
+
+func TestMAA(t *testing.T) {
+	rat := int32(RATTypeWLAN)
+	mar := &MAR{
+		SessionID:                   "aaa;1;2",
+		VendorSpecificApplicationID: swxApp,
+		AuthSessionState:            NoStateMaintained,
+		OriginHost:                  "aaa",
+		OriginRealm:                 "epc",
+		DestinationRealm:            "epc",
+		UserName:                    "0001010000000001@wlan",
+		RATType:                     &rat,
+		SIPAuthDataItem:             &SIPAuthDataItem{SIPAuthenticationScheme: EAPAKA},
+		SIPNumberAuthItems:          1,
+	}
+	req, err := mar.Message()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Header.CommandCode != diam.MultimediaAuthentication || req.Header.ApplicationID != ApplicationID {
+		t.Fatalf("Unexpected header: %s", req.Header)
+	}
+	r, err := ParseMAR(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.RATType == nil || *r.RATType != RATTypeWLAN || r.SIPAuthDataItem == nil ||
+		r.SIPAuthDataItem.SIPAuthenticationScheme != EAPAKA {
+		t.Fatalf("Unexpected MAR: %+v", r)
+	}
+	key := bytes.Repeat([]byte{3}, 16)
+	maa := &MAA{
+		SessionID:                   mar.SessionID,
+		VendorSpecificApplicationID: swxApp,
+		ResultCode:                  diam.Success,
+		AuthSessionState:            NoStateMaintained,
+		OriginHost:                  "hss",
+		OriginRealm:                 "epc",
+		UserName:                    mar.UserName,
+		SIPNumberAuthItems:          2,
+		SIPAuthDataItem: []*SIPAuthDataItem{
+			NewAKAItem(2, EAPAKA, bytes.Repeat([]byte{2}, 16), key, []byte("xres0002"), key, key),
+			NewAKAItem(1, EAPAKA, bytes.Repeat([]byte{1}, 16), key, []byte("xres0001"), key, key),
+		},
+	}
+	m, err := maa.Message(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := ParseMAA(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code, _ := diam.ResultCode(m); code != diam.Success {
+		t.Fatalf("Unexpected result: %d", code)
+	}
+	items := a.Items()
+	if len(items) != 2 || items[0].SIPItemNumber != 1 ||
+		!bytes.Equal(items[0].RAND(), bytes.Repeat([]byte{1}, 16)) || !bytes.Equal(items[0].AUTN(), key) {
+		t.Fatalf("Unexpected items: %+v", items)
+	}
+}
+
+func TestResyncItem(t *testing.T) {
+	rand := bytes.Repeat([]byte{1}, 16)
+	auts := bytes.Repeat([]byte{4}, 14)
+	r, a, ok := NewResyncItem(EAPAKAPrime, rand, auts).Resync()
+	if !ok || !bytes.Equal(r, rand) || !bytes.Equal(a, auts) {
+		t.Fatalf("Unexpected resynchronization: %x %x %v", r, a, ok)
+	}
+}
+
+func TestSAA(t *testing.T) {
+	sar := &SAR{
+		SessionID:                   "aaa;1;3",
+		VendorSpecificApplicationID: swxApp,
+		AuthSessionState:            NoStateMaintained,
+		OriginHost:                  "aaa",
+		OriginRealm:                 "epc",
+		DestinationRealm:            "epc",
+		UserName:                    "0001010000000001@wlan",
+		ServerAssignmentType:        Registration,
+	}
+	req, err := sar.Message()
+	if err != nil {
+		t.Fatal(err)
+	}
+	access := int32(Non3GPPSubscriptionAllowed)
+	saa := &SAA{
+		SessionID:                   sar.SessionID,
+		VendorSpecificApplicationID: swxApp,
+		ResultCode:                  diam.Success,
+		AuthSessionState:            NoStateMaintained,
+		OriginHost:                  "hss",
+		OriginRealm:                 "epc",
+		Non3GPPUserData: &Non3GPPUserData{
+			Non3GPPIPAccess:   &access,
+			MIP6FeatureVector: 1 << 55,
+			ContextIdentifier: 1,
+			APNConfiguration: []*APNConfiguration{{
+				ContextIdentifier: 1,
+				ServiceSelection:  "internet",
+				AMBR:              &AMBR{MaxRequestedBandwidthUL: 1000, MaxRequestedBandwidthDL: 2000},
+			}},
+		},
+	}
+	m, err := saa.Message(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := ParseSAA(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := a.Non3GPPUserData
+	if d == nil || d.Non3GPPIPAccess == nil || *d.Non3GPPIPAccess != Non3GPPSubscriptionAllowed ||
+		d.MIP6FeatureVector != 1<<55 {
+		t.Fatalf("Unexpected Non-3GPP-User-Data: %+v", d)
+	}
+	apn, ok := d.APN("internet")
+	if !ok || apn.AMBR == nil || apn.AMBR.MaxRequestedBandwidthDL != 2000 {
+		t.Fatalf("Unexpected APN-Configuration: %+v", apn)
+	}
+}
+
+func TestRTR(t *testing.T) {
+	rtr := &RTR{
+		SessionID:                   "hss;1;4",
+		VendorSpecificApplicationID: swxApp,
+		AuthSessionState:            NoStateMaintained,
+		OriginHost:                  "hss",
+		OriginRealm:                 "epc",
+		DestinationHost:             "aaa",
+		DestinationRealm:            "epc",
+		UserName:                    "0001010000000001@wlan",
+		DeregistrationReason:        &DeregistrationReason{ReasonCode: PermanentTermination},
+	}
+	m, err := rtr.Message()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := ParseRTR(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.DeregistrationReason == nil || r.DeregistrationReason.ReasonCode != PermanentTermination {
+		t.Fatalf("Unexpected RTR: %+v", r)
+	}
+}
+
+func TestPPR(t *testing.T) {
+	ppr := &PPR{
+		SessionID:                   "hss;1;5",
+		VendorSpecificApplicationID: swxApp,
+		AuthSessionState:            NoStateMaintained,
+		OriginHost:                  "hss",
+		OriginRealm:                 "epc",
+		DestinationHost:             "aaa",
+		DestinationRealm:            "epc",
+		UserName:                    "0001010000000001@wlan",
+		PPRFlags:                    PPRResetIndication,
+	}
+	m, err := ppr.Message()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Header.CommandCode != diam.PushProfile {
+		t.Fatalf("Unexpected header: %s", m.Header)
+	}
+	if _, err := m.FindAVP(avp.PPRFlags, VendorID); err != nil {
+		t.Fatal(err)
+	}
+	r, err := ParsePPR(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.PPRFlags != PPRResetIndication {
+		t.Fatalf("Unexpected PPR: %+v", r)
+	}
+}
@@ -0,0 +1,20 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package tgpp
+
+// VendorSpecificApplicationID is the Vendor-Specific-Application-Id AVP
+// of the requests and answers of the 3GPP applications.
+type VendorSpecificApplicationID struct {
+	VendorID          uint32 `avp:"Vendor-Id"`
+	AuthApplicationID uint32 `avp:"Auth-Application-Id"`
+}
+
+// ExperimentalResult is the Experimental-Result AVP, which carries the
+// 3GPP result codes of answers. See diam.ResultCode to read the result
+// of answer messages.
+type ExperimentalResult struct {
+	VendorID               uint32 `avp:"Vendor-Id"`
+	ExperimentalResultCode uint32 `avp:"Experimental-Result-Code"`
+}
@@ -0,0 +1,26 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package tgpp provides the values and encodings of the AVPs shared by
+// the 3GPP applications, such as Gx, S6a and SWx.
+//
+// RATType is the value of the RAT-Type AVP, and UserLocationInfo the
+// content of the 3GPP-User-Location-Info AVP, with its cell and tracking
+// area identities decoded:
+//
+//	uli, err := tgpp.ParseUserLocationInfo(ccr.UserLocationInfo)
+//	if err != nil {
+//		return
+//	}
+//	if uli.ECGI != nil {
+//		log.Printf("Cell %s-%s %d", uli.ECGI.MCC, uli.ECGI.MNC, uli.ECGI.ECI)
+//	}
+//
+// VendorSpecificApplicationID and ExperimentalResult are the grouped
+// AVPs of the typed messages of the 3GPP application packages, such as
+// s6a.ULA.
+//
+// The Subscription-Id and User-Equipment-Info AVPs of RFC 4006 are in
+// package creditcontrol.
+package tgpp
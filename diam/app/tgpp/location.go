@@ -0,0 +1,340 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package tgpp
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// LocationType is the Geographic Location Type of a UserLocationInfo,
+// see 3GPP TS 29.061 section 16.4.7.2.
+type LocationType uint8
+
+// Geographic location types.
+const (
+	LocationCGI           LocationType = 0
+	LocationSAI           LocationType = 1
+	LocationRAI           LocationType = 2
+	LocationTAI           LocationType = 128
+	LocationECGI          LocationType = 129
+	LocationTAIAndECGI    LocationType = 130
+	LocationNCGI          LocationType = 135
+	Location5GSTAI        LocationType = 136
+	Location5GSTAIAndNCGI LocationType = 137
+)
+
+// PLMN is a Public Land Mobile Network identity, encoded in 3 bytes as
+// specified by 3GPP TS 24.008 section 10.5.1.3.
+type PLMN struct {
+	MCC string // Mobile Country Code, 3 digits
+	MNC string // Mobile Network Code, 2 or 3 digits
+}
+
+// CGI is a Cell Global Identification.
+type CGI struct {
+	PLMN
+	LAC uint16 // Location Area Code
+	CI  uint16 // Cell Identity
+}
+
+// SAI is a Service Area Identity.
+type SAI struct {
+	PLMN
+	LAC uint16 // Location Area Code
+	SAC uint16 // Service Area Code
+}
+
+// RAI is a Routing Area Identity.
+type RAI struct {
+	PLMN
+	LAC uint16 // Location Area Code
+	RAC uint8  // Routing Area Code
+}
+
+// TAI is a Tracking Area Identity. The TAC has 16 bits in EPS and 24 in
+// 5GS.
+type TAI struct {
+	PLMN
+	TAC uint32 // Tracking Area Code
+}
+
+// ECGI is an E-UTRAN Cell Global Identifier.
+type ECGI struct {
+	PLMN
+	ECI uint32 // E-UTRAN Cell Identifier, 28 bits
+}
+
+// NCGI is an NR Cell Global Identifier.
+type NCGI struct {
+	PLMN
+	NCI uint64 // NR Cell Identity, 36 bits
+}
+
+// UserLocationInfo is the content of the 3GPP-User-Location-Info AVP,
+// see 3GPP TS 29.061 section 16.4.7.2. The identities of its Type are
+// set, such as TAI and ECGI for LocationTAIAndECGI.
+type UserLocationInfo struct {
+	Type LocationType
+	CGI  *CGI
+	SAI  *SAI
+	RAI  *RAI
+	TAI  *TAI
+	ECGI *ECGI
+	NCGI *NCGI
+}
+
+// ParseUserLocationInfo parses the content of a 3GPP-User-Location-Info
+// AVP.
+func ParseUserLocationInfo(b []byte) (*UserLocationInfo, error) {
+	if len(b) == 0 {
+		return nil, fmt.Errorf("Invalid length for User-Location-Info: 0")
+	}
+	u := &UserLocationInfo{Type: LocationType(b[0])}
+	want := u.Type.len()
+	if want == 0 {
+		return nil, fmt.Errorf("Unsupported User-Location-Info type %d", b[0])
+	}
+	if len(b) != want {
+		return nil, fmt.Errorf("Invalid length for User-Location-Info type %d: %d", b[0], len(b))
+	}
+	b = b[1:]
+	var err error
+	switch u.Type {
+	case LocationCGI:
+		u.CGI = &CGI{LAC: be16(b[3:]), CI: be16(b[5:])}
+		u.CGI.PLMN, err = parsePLMN(b)
+	case LocationSAI:
+		u.SAI = &SAI{LAC: be16(b[3:]), SAC: be16(b[5:])}
+		u.SAI.PLMN, err = parsePLMN(b)
+	case LocationRAI:
+		u.RAI = &RAI{LAC: be16(b[3:]), RAC: b[5]}
+		u.RAI.PLMN, err = parsePLMN(b)
+	case LocationTAI:
+		u.TAI, err = parseTAI(b, 2)
+	case LocationECGI:
+		u.ECGI, err = parseECGI(b)
+	case LocationTAIAndECGI:
+		if u.TAI, err = parseTAI(b, 2); err == nil {
+			u.ECGI, err = parseECGI(b[5:])
+		}
+	case LocationNCGI:
+		u.NCGI, err = parseNCGI(b)
+	case Location5GSTAI:
+		u.TAI, err = parseTAI(b, 3)
+	case Location5GSTAIAndNCGI:
+		if u.TAI, err = parseTAI(b, 3); err == nil {
+			u.NCGI, err = parseNCGI(b[6:])
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// Serialize returns the content of the 3GPP-User-Location-Info AVP, or
+// an error if the identities of its Type are not set or invalid.
+func (u *UserLocationInfo) Serialize() ([]byte, error) {
+	if u.Type.len() == 0 {
+		return nil, fmt.Errorf("Unsupported User-Location-Info type %d", u.Type)
+	}
+	b := make([]byte, 1, u.Type.len())
+	b[0] = byte(u.Type)
+	var err error
+	switch u.Type {
+	case LocationCGI:
+		if u.CGI == nil {
+			break
+		}
+		b, err = u.CGI.PLMN.append(b)
+		b = append(b, byte(u.CGI.LAC>>8), byte(u.CGI.LAC), byte(u.CGI.CI>>8), byte(u.CGI.CI))
+	case LocationSAI:
+		if u.SAI == nil {
+			break
+		}
+		b, err = u.SAI.PLMN.append(b)
+		b = append(b, byte(u.SAI.LAC>>8), byte(u.SAI.LAC), byte(u.SAI.SAC>>8), byte(u.SAI.SAC))
+	case LocationRAI:
+		if u.RAI == nil {
+			break
+		}
+		b, err = u.RAI.PLMN.append(b)
+		// The RAC is followed by a filler byte.
+		b = append(b, byte(u.RAI.LAC>>8), byte(u.RAI.LAC), u.RAI.RAC, 0xff)
+	case LocationTAI:
+		b, err = u.TAI.append(b, 2)
+	case LocationECGI:
+		b, err = u.ECGI.append(b)
+	case LocationTAIAndECGI:
+		if b, err = u.TAI.append(b, 2); err == nil {
+			b, err = u.ECGI.append(b)
+		}
+	case LocationNCGI:
+		b, err = u.NCGI.append(b)
+	case Location5GSTAI:
+		b, err = u.TAI.append(b, 3)
+	case Location5GSTAIAndNCGI:
+		if b, err = u.TAI.append(b, 3); err == nil {
+			b, err = u.NCGI.append(b)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != u.Type.len() {
+		return nil, fmt.Errorf("Missing identity of User-Location-Info type %d", u.Type)
+	}
+	return b, nil
+}
+
+// AVP returns the 3GPP-User-Location-Info AVP.
+func (u *UserLocationInfo) AVP() (*diam.AVP, error) {
+	b, err := u.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	return diam.NewAVP(avp.TGPPUserLocationInfo, avp.Vbit, VendorID, datatype.OctetString(b)), nil
+}
+
+// len returns the length of the User-Location-Info of type t, 0 for the
+// types not supported.
+func (t LocationType) len() int {
+	switch t {
+	case LocationCGI, LocationSAI, LocationRAI:
+		return 8
+	case LocationTAI:
+		return 6
+	case LocationECGI:
+		return 8
+	case LocationTAIAndECGI:
+		return 13
+	case LocationNCGI:
+		return 9
+	case Location5GSTAI:
+		return 7
+	case Location5GSTAIAndNCGI:
+		return 15
+	}
+	return 0
+}
+
+// String returns the PLMN as MCC-MNC, such as 001-01.
+func (p PLMN) String() string {
+	return p.MCC + "-" + p.MNC
+}
+
+// append appends the encoding of the PLMN to b.
+func (p PLMN) append(b []byte) ([]byte, error) {
+	if len(p.MCC) != 3 || len(p.MNC) < 2 || len(p.MNC) > 3 ||
+		!isDigits(p.MCC) || !isDigits(p.MNC) {
+		return b, fmt.Errorf("Invalid PLMN %s", p)
+	}
+	mnc3 := byte(0x0f)
+	if len(p.MNC) == 3 {
+		mnc3 = p.MNC[2] - '0'
+	}
+	return append(b,
+		(p.MCC[1]-'0')<<4|(p.MCC[0]-'0'),
+		mnc3<<4|(p.MCC[2]-'0'),
+		(p.MNC[1]-'0')<<4|(p.MNC[0]-'0'),
+	), nil
+}
+
+func (t *TAI) append(b []byte, tacLen int) ([]byte, error) {
+	if t == nil {
+		return b, nil
+	}
+	b, err := t.PLMN.append(b)
+	if tacLen == 3 {
+		b = append(b, byte(t.TAC>>16))
+	}
+	return append(b, byte(t.TAC>>8), byte(t.TAC)), err
+}
+
+func (e *ECGI) append(b []byte) ([]byte, error) {
+	if e == nil {
+		return b, nil
+	}
+	b, err := e.PLMN.append(b)
+	eci := e.ECI & 0x0fffffff
+	return append(b, byte(eci>>24), byte(eci>>16), byte(eci>>8), byte(eci)), err
+}
+
+func (n *NCGI) append(b []byte) ([]byte, error) {
+	if n == nil {
+		return b, nil
+	}
+	b, err := n.PLMN.append(b)
+	nci := n.NCI & 0xfffffffff
+	return append(b, byte(nci>>32), byte(nci>>24), byte(nci>>16), byte(nci>>8), byte(nci)), err
+}
+
+func parsePLMN(b []byte) (PLMN, error) {
+	d := []byte{
+		b[0] & 0x0f, b[0] >> 4, b[1] & 0x0f, // MCC
+		b[2] & 0x0f, b[2] >> 4, b[1] >> 4, // MNC
+	}
+	n := len(d)
+	if d[5] == 0x0f {
+		n-- // 2-digit MNC
+	}
+	for i := 0; i < n; i++ {
+		if d[i] > 9 {
+			return PLMN{}, fmt.Errorf("Invalid PLMN 0x%x", b[:3])
+		}
+		d[i] += '0'
+	}
+	return PLMN{MCC: string(d[:3]), MNC: string(d[3:n])}, nil
+}
+
+func parseTAI(b []byte, tacLen int) (*TAI, error) {
+	p, err := parsePLMN(b)
+	if err != nil {
+		return nil, err
+	}
+	t := &TAI{PLMN: p}
+	for _, c := range b[3 : 3+tacLen] {
+		t.TAC = t.TAC<<8 | uint32(c)
+	}
+	return t, nil
+}
+
+func parseECGI(b []byte) (*ECGI, error) {
+	p, err := parsePLMN(b)
+	if err != nil {
+		return nil, err
+	}
+	return &ECGI{PLMN: p, ECI: binary.BigEndian.Uint32(b[3:]) & 0x0fffffff}, nil
+}
+
+func parseNCGI(b []byte) (*NCGI, error) {
+	p, err := parsePLMN(b)
+	if err != nil {
+		return nil, err
+	}
+	var nci uint64
+	for _, c := range b[3:8] {
+		nci = nci<<8 | uint64(c)
+	}
+	return &NCGI{PLMN: p, NCI: nci & 0xfffffffff}, nil
+}
+
+func be16(b []byte) uint16 {
+	return binary.BigEndian.Uint16(b)
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,60 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package tgpp
+
+import "strconv"
+
+// VendorID is the Vendor-Id of the 3GPP AVPs.
+const VendorID = 10415
+
+// RATType is the value of the RAT-Type AVP, see 3GPP TS 29.212 section
+// 5.3.31.
+type RATType int32
+
+// Radio access technologies.
+const (
+	RATWLAN          RATType = 0
+	RATVirtual       RATType = 1
+	RATTrustedN3GA   RATType = 2
+	RATWireline      RATType = 3
+	RATUTRAN         RATType = 1000
+	RATGERAN         RATType = 1001
+	RATGAN           RATType = 1002
+	RATHSPAEvolution RATType = 1003
+	RATEUTRAN        RATType = 1004
+	RATEUTRANNBIoT   RATType = 1005
+	RATNR            RATType = 1006
+	RATLTEM          RATType = 1007
+	RATCDMA20001X    RATType = 2000
+	RATHRPD          RATType = 2001
+	RATUMB           RATType = 2002
+	RATEHRPD         RATType = 2003
+)
+
+var ratTypes = map[RATType]string{
+	RATWLAN:          "WLAN",
+	RATVirtual:       "VIRTUAL",
+	RATTrustedN3GA:   "TRUSTED-N3GA",
+	RATWireline:      "WIRELINE",
+	RATUTRAN:         "UTRAN",
+	RATGERAN:         "GERAN",
+	RATGAN:           "GAN",
+	RATHSPAEvolution: "HSPA_EVOLUTION",
+	RATEUTRAN:        "EUTRAN",
+	RATEUTRANNBIoT:   "EUTRAN-NB-IoT",
+	RATNR:            "NR",
+	RATLTEM:          "LTE-M",
+	RATCDMA20001X:    "CDMA2000_1X",
+	RATHRPD:          "HRPD",
+	RATUMB:           "UMB",
+	RATEHRPD:         "EHRPD",
+}
+
+func (t RATType) String() string {
+	if s, ok := ratTypes[t]; ok {
+		return s
+	}
+	return "RATType(" + strconv.Itoa(int(t)) + ")"
+}
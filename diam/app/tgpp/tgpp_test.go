@@ -0,0 +1,101 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package tgpp
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestUserLocationInfo(t *testing.T) {
+	for _, test := range []struct {
+		b    []byte
+		want *UserLocationInfo
+	}{
+		{
+			[]byte{0x82, 0x00, 0xf1, 0x10, 0x00, 0x01, 0x00, 0xf1, 0x10, 0x01, 0x23, 0x45, 0x67},
+			&UserLocationInfo{
+				Type: LocationTAIAndECGI,
+				TAI:  &TAI{PLMN{"001", "01"}, 1},
+				ECGI: &ECGI{PLMN{"001", "01"}, 0x1234567},
+			},
+		},
+		{
+			[]byte{0x80, 0x13, 0x00, 0x62, 0x30, 0x39},
+			&UserLocationInfo{
+				Type: LocationTAI,
+				TAI:  &TAI{PLMN{"310", "260"}, 12345},
+			},
+		},
+		{
+			[]byte{0x00, 0x62, 0xf2, 0x10, 0x12, 0x34, 0x56, 0x78},
+			&UserLocationInfo{
+				Type: LocationCGI,
+				CGI:  &CGI{PLMN{"262", "01"}, 0x1234, 0x5678},
+			},
+		},
+		{
+			[]byte{0x02, 0x62, 0xf2, 0x10, 0x12, 0x34, 0x56, 0xff},
+			&UserLocationInfo{
+				Type: LocationRAI,
+				RAI:  &RAI{PLMN{"262", "01"}, 0x1234, 0x56},
+			},
+		},
+		{
+			[]byte{0x89, 0x00, 0xf1, 0x10, 0x00, 0x00, 0x07, 0x00, 0xf1, 0x10, 0x0f, 0x12, 0x34, 0x56, 0x78},
+			&UserLocationInfo{
+				Type: Location5GSTAIAndNCGI,
+				TAI:  &TAI{PLMN{"001", "01"}, 7},
+				NCGI: &NCGI{PLMN{"001", "01"}, 0xf12345678},
+			},
+		},
+	} {
+		u, err := ParseUserLocationInfo(test.b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(u, test.want) {
+			t.Errorf("Unexpected location of 0x%x.\nWant %#v\nHave %#v", test.b, test.want, u)
+		}
+		b, err := u.Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(b, test.b) {
+			t.Errorf("Unexpected value. Want 0x%x, have 0x%x", test.b, b)
+		}
+	}
+}
+
+func TestUserLocationInfoErrors(t *testing.T) {
+	for _, b := range [][]byte{
+		{},
+		{0x81, 0x00, 0xf1},
+		{0x83, 0x00, 0xf1, 0x10, 0x00, 0x01},
+		{0x80, 0xa0, 0xf1, 0x10, 0x00, 0x01},
+	} {
+		if u, err := ParseUserLocationInfo(b); err == nil {
+			t.Errorf("Unexpected location %#v of 0x%x", u, b)
+		}
+	}
+	for _, u := range []*UserLocationInfo{
+		{Type: LocationTAIAndECGI, TAI: &TAI{PLMN{"001", "01"}, 1}},
+		{Type: LocationECGI, ECGI: &ECGI{PLMN{"1", "01"}, 1}},
+	} {
+		if b, err := u.Serialize(); err == nil {
+			t.Errorf("Unexpected value 0x%x of %#v", b, u)
+		}
+	}
+}
+
+func TestRATType(t *testing.T) {
+	if s := RATEUTRAN.String(); s != "EUTRAN" {
+		t.Fatalf("Unexpected RAT type %q", s)
+	}
+	if s := RATType(9).String(); s != "RATType(9)" {
+		t.Fatalf("Unexpected RAT type %q", s)
+	}
+}
@@ -13,8 +13,16 @@ const (
 	BASE_ACCOUNTING_APP_ID     = 3
 	CHARGING_CONTROL_APP_ID    = 4
 	TGPP_APP_ID                = 4
+	DIAMETER_EAP_APP_ID        = 5
+	CAPABILITIES_UPDATE_APP_ID = 10
+	TGPP_CX_APP_ID             = 16777216
+	TGPP_SH_APP_ID             = 16777217
+	TGPP_RX_APP_ID             = 16777236
 	GX_CHARGING_CONTROL_APP_ID = 16777238
 	TGPP_S6A_APP_ID            = 16777251
+	TGPP_SWM_APP_ID            = 16777264
 	TGPP_SWX_APP_ID            = 16777265
+	TGPP_GXX_APP_ID            = 16777266
+	TGPP_S6B_APP_ID            = 16777272
 	DIAMETER_SY_APP_ID         = 16777302
 )
@@ -51,47 +51,61 @@ func DecodeAVP(data []byte, application uint32, dictionary *dict.Parser) (*AVP,
 // DecodeFromBytes decodes the bytes of a Diameter AVP.
 // It uses the given application id and dictionary for decoding the bytes.
 func (a *AVP) DecodeFromBytes(data []byte, application uint32, dictionary *dict.Parser) error {
+	payload, err := a.decodeHeader(data)
+	if err != nil {
+		return err
+	}
+	return a.decodeData(payload, application, dictionary)
+}
+
+// decodeHeader decodes the header of the AVP in data, and returns its
+// payload.
+func (a *AVP) decodeHeader(data []byte) ([]byte, error) {
 	if len(data) < 8 {
-		return fmt.Errorf("Not enough data to decode AVP header: %d bytes", len(data))
+		return nil, decodeErrorf(ErrTruncated, 0, "not enough data to decode AVP header: %d bytes", len(data))
 	}
 	a.Code = binary.BigEndian.Uint32(data[0:4])
 	a.Flags = data[4]
 	a.Length = int(uint24to32(data[5:8]))
+	hdrLength := 8
+	if a.Flags&avp.Vbit == avp.Vbit {
+		hdrLength = 12
+	}
+	if a.Length < hdrLength {
+		return nil, decodeErrorf(ErrBadAVPLength, a.Code, "length %d is shorter than the header", a.Length)
+	}
 	if len(data) < a.Length {
-		return fmt.Errorf("Not enough data to decode AVP: %d != %d",
-			len(data), a.Length)
+		return nil, decodeErrorf(ErrBadAVPLength, a.Code, "length %d exceeds the %d bytes available", a.Length, len(data))
 	}
-	data = data[:a.Length] // this cuts padded bytes off
-	if len(data) < 8 {
-		return fmt.Errorf("Not enough data to decode AVP header: %d bytes", len(data))
+	if pad := a.Length % 4; pad != 0 && len(data) < a.Length+4-pad {
+		return nil, decodeErrorf(ErrBadPadding, a.Code, "%d bytes of padding missing", a.Length+4-pad-len(data))
 	}
-
-	var hdrLength int
-	var payload []byte
+	data = data[:a.Length] // this cuts padded bytes off
 	// Read VendorId when required.
-	if a.Flags&avp.Vbit == avp.Vbit {
+	if hdrLength == 12 {
 		a.VendorID = binary.BigEndian.Uint32(data[8:12])
-		payload = data[12:]
-		hdrLength = 12
-	} else {
-		payload = data[8:]
-		hdrLength = 8
 	}
+	return data[hdrLength:], nil
+}
+
+// decodeData decodes the payload of the AVP with the type of its
+// dictionary definition.
+func (a *AVP) decodeData(payload []byte, application uint32, dictionary *dict.Parser) error {
 	// Find this code in the dictionary.
 	dictAVP, err := dictionary.FindAVPWithVendor(application, a.Code, a.VendorID)
 	if err != nil && dictAVP == nil {
 		return err
 	}
-	bodyLen := a.Length - hdrLength
-	if n := len(payload); n < bodyLen {
-		return fmt.Errorf(
-			"Not enough data to decode AVP: %d != %d",
-			hdrLength, n,
-		)
-	}
+	return a.decodeDictData(dictAVP, payload, application, dictionary)
+}
+
+// decodeDictData decodes the payload of the AVP with the type of its
+// dictionary definition dictAVP.
+func (a *AVP) decodeDictData(dictAVP *dict.AVP, payload []byte, application uint32, dictionary *dict.Parser) error {
+	var err error
 	a.Data, err = datatype.Decode(dictAVP.Data.Type, payload)
 	if err != nil {
-		return err
+		return &DecodeError{Err: err, Code: a.Code, Reason: "invalid " + dictAVP.Data.TypeName + " value"}
 	}
 	// Handle grouped AVPs.
 	if a.Data.Type() == datatype.GroupedType {
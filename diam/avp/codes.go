@@ -12,6 +12,7 @@ const (
 	AFApplicationIdentifier                    = 504
 	AFChargingIdentifier                       = 505
 	AFCorrelationInformation                   = 1276
+	AFSignallingProtocol                       = 529
 	AMBR                                       = 1435
 	ANGWAddress                                = 1050
 	ANID                                       = 1504
@@ -27,7 +28,10 @@ const (
 	ARAPSecurityData                           = 74
 	ARAPZoneAccess                             = 72
 	AUTN                                       = 1449
+	AbortCause                                 = 500
+	AcceptableServiceInfo                      = 526
 	AccessNetworkChargingAddress               = 501
+	AccessNetworkChargingIdentifier            = 502
 	AccessNetworkChargingIdentifierGx          = 1022
 	AccessNetworkChargingIdentifierValue       = 503
 	AccessNetworkInformation                   = 1263
@@ -36,6 +40,7 @@ const (
 	AccessTransferType                         = 2710
 	AccountExpiration                          = 2309
 	AccountingAuthMethod                       = 406
+	AccountingEAPAuthMethod                    = 465
 	AccountingInputOctets                      = 363
 	AccountingInputPackets                     = 365
 	AccountingOutputOctets                     = 364
@@ -142,6 +147,7 @@ const (
 	ChargeReasonCode                           = 2118
 	ChargedParty                               = 857
 	ChargingCharacteristicsSelectionMode       = 2066
+	ChargingInformation                        = 618
 	ChargingRuleBaseName                       = 1004
 	ChargingRuleDefinition                     = 1003
 	ChargingRuleInstall                        = 1001
@@ -152,6 +158,7 @@ const (
 	ClassIdentifier                            = 1214
 	ClientAddress                              = 2018
 	ClientIdentity                             = 1480
+	CodecData                                  = 524
 	CompleteDataListIncludedIndicator          = 1468
 	ConfidentialityKey                         = 625
 	ConfigurationToken                         = 78
@@ -169,10 +176,14 @@ const (
 	CreditControl                              = 426
 	CreditControlFailureHandling               = 427
 	CurrencyCode                               = 425
+	CurrentLocation                            = 707
 	CurrentTariff                              = 2056
+	DERS6bFlags                                = 1523
 	DRMContent                                 = 1221
 	DRMP                                       = 301
+	DSAITag                                    = 711
 	DataCodingScheme                           = 2001
+	DataReference                              = 703
 	DefaultEPSBearerQoS                        = 1049
 	DeferredLocationEventType                  = 1230
 	DeliveryReportRequested                    = 1216
@@ -187,6 +198,10 @@ const (
 	DomainName                                 = 1200
 	DynamicAddressFlag                         = 2051
 	DynamicAddressFlagExtension                = 2068
+	EAPKeyName                                 = 102
+	EAPMasterSessionKey                        = 464
+	EAPPayload                                 = 462
+	EAPReissuedPayload                         = 463
 	EPSSubscribedQoSProfile                    = 1431
 	EUTRANVector                               = 1414
 	EarlyMediaDescription                      = 1272
@@ -206,6 +221,7 @@ const (
 	ExperimentalResultCode                     = 298
 	ExpirationDate                             = 1439
 	Expires                                    = 888
+	ExpiryTime                                 = 709
 	Exponent                                   = 429
 	ExtPDPAddress                              = 1621
 	ExtPDPType                                 = 1620
@@ -260,6 +276,8 @@ const (
 	HomogeneousSupportofIMSVoiceOverPSSessions = 1493
 	HostIPAddress                              = 257
 	ICSIndicator                               = 1491
+	IDAFlags                                   = 1441
+	IDRFlags                                   = 1490
 	IMEI                                       = 1402
 	IMSApplicationReferenceIdentifier          = 2601
 	IMSChargingIdentifier                      = 841
@@ -275,6 +293,7 @@ const (
 	ISUPCauseLocation                          = 3423
 	ISUPCauseValue                             = 3424
 	ISUPLocationNumber                         = 3414
+	IdentitySet                                = 708
 	IdleTimeout                                = 28
 	ImmediateResponsePreferred                 = 1412
 	InbandSecurityID                           = 299
@@ -306,6 +325,9 @@ const (
 	LCSRequestorID                             = 1239
 	LCSRequestorIDString                       = 1240
 	LIPAPermission                             = 1618
+	Load                                       = 650
+	LoadType                                   = 651
+	LoadValue                                  = 652
 	LocalGWInsertedIndication                  = 2604
 	LocalSequenceNumber                        = 2063
 	LocationEstimate                           = 1242
@@ -332,6 +354,7 @@ const (
 	MDTConfiguration                           = 1622
 	MDTUserConsent                             = 1634
 	MediaComponentDescription                  = 517
+	MediaComponentNumber                       = 518
 	MediaSubComponent                          = 519
 	MIP6AgentInfo                              = 486
 	MIP6FeatureVector                          = 124
@@ -356,16 +379,21 @@ const (
 	MaxRequestedBandwidthUL                    = 516
 	MediaInitiatorFlag                         = 882
 	MediaInitiatorParty                        = 1288
+	MediaType                                  = 520
 	MessageBody                                = 889
 	MessageClass                               = 1213
 	MessageID                                  = 1210
 	MessageSize                                = 1212
 	MessageType                                = 1211
+	MobileNodeIdentifier                       = 506
 	MonitoringKey                              = 1066
 	MultiRoundTimeOut                          = 272
 	MultipleServicesCreditControl              = 456
 	MultipleServicesIndicator                  = 455
 	NASFilterRule                              = 400
+	NASIPAddress                               = 4
+	NASIPv6Address                             = 95
+	NASIdentifier                              = 32
 	NASPort                                    = 5
 	NASPortID                                  = 87
 	NASPortType                                = 61
@@ -402,15 +430,18 @@ const (
 	OMCID                                      = 1466
 	Offline                                    = 1008
 	OfflineCharging                            = 1278
+	OneTimeNotification                        = 712
 	Online                                     = 1009
 	OnlineChargingFlag                         = 2303
 	OperatorDeterminedBarring                  = 1425
 	OptionalCapability                         = 605
+	OriginAAAProtocol                          = 408
 	OriginHost                                 = 264
 	OriginRealm                                = 296
 	OriginStateID                              = 278
 	OriginatingIOI                             = 839
 	OriginatingLineInfo                        = 94
+	OriginatingRequest                         = 633
 	Originator                                 = 864
 	OriginatorAddress                          = 886
 	OriginatorInterface                        = 2009
@@ -427,6 +458,7 @@ const (
 	PDPContextType                             = 1247
 	PDPType                                    = 1470
 	PLMNClient                                 = 1482
+	PPRFlags                                   = 1508
 	PSAppendFreeFormatData                     = 867
 	PSFreeFormatData                           = 866
 	PSFurnishChargingInformation               = 865
@@ -462,6 +494,8 @@ const (
 	PresenceReportingAreaIdentifier            = 2821
 	PresenceReportingAreaInformation           = 2822
 	PresenceReportingAreaStatus                = 2823
+	PrimaryChargingCollectionFunctionName      = 621
+	PrimaryEventChargingFunctionName           = 619
 	Priority                                   = 1209
 	PriorityIndication                         = 3006
 	PriorityLevel                              = 1046
@@ -470,6 +504,7 @@ const (
 	ProxyHost                                  = 280
 	ProxyInfo                                  = 284
 	ProxyState                                 = 33
+	PublicIdentity                             = 601
 	QoSClassIdentifier                         = 1028
 	QoSFilterRule                              = 407
 	QoSInformation                             = 1016
@@ -478,8 +513,11 @@ const (
 	QuotaHoldingTime                           = 871
 	RAI                                        = 909
 	RAND                                       = 1447
+	RARFlags                                   = 1522
 	RATFrequencySelectionPriorityID            = 1440
 	RATType                                    = 1032
+	RRBandwidth                                = 521
+	RSBandwidth                                = 522
 	RateElement                                = 2058
 	RatingGroup                                = 432
 	ReAuthRequestType                          = 285
@@ -514,11 +552,15 @@ const (
 	ReplyMessage                               = 18
 	ReplyPathRequested                         = 2011
 	ReportingReason                            = 872
+	RepositoryDataID                           = 715
 	RequestedAction                            = 436
+	RequestedDomain                            = 706
 	RequestedEUTRANAuthenticationInfo          = 1408
+	RequestedNodes                             = 713
 	RequestedPartyAddress                      = 1251
 	RequestedServiceUnit                       = 437
 	RequestedUTRANGERANAuthenticationInfo      = 1409
+	RequiredAccessInfo                         = 536
 	RequiredMBMSBearerCapabilities             = 901
 	RestrictionFilterRule                      = 438
 	ResultCode                                 = 268
@@ -531,6 +573,7 @@ const (
 	RouteRecord                                = 282
 	RuleActivationTime                         = 1043
 	RuleDeactivationTime                       = 1044
+	RxRequestType                              = 533
 	SDPAnswerTimestamp                         = 1275
 	SDPMediaComponent                          = 843
 	SDPMediaDescription                        = 845
@@ -539,6 +582,15 @@ const (
 	SDPSessionDescription                      = 842
 	SDPTimeStamps                              = 1273
 	SDPType                                    = 2036
+	SIPAuthenticationContext                   = 611
+	SIPForkingIndication                       = 523
+	SecondaryChargingCollectionFunctionName    = 622
+	SecondaryEventChargingFunctionName         = 620
+	SendDataIndication                         = 710
+	SequenceNumber                             = 716
+	ServiceIndication                          = 704
+	ServiceInfoStatus                          = 527
+	ServiceURN                                 = 525
 	SessionReleaseCause                        = 1045
 	SGSNAddress                                = 1228
 	SGSNNumber                                 = 1489
@@ -604,13 +656,17 @@ const (
 	SessionServerFailover                      = 271
 	SessionTimeout                             = 27
 	SoftwareVersion                            = 1403
+	SourceID                                   = 649
 	SpecificAPNInfo                            = 1472
+	SpecificAction                             = 513
 	SponsorIdentity                            = 531
 	StartTime                                  = 2041
 	StartofCharging                            = 3419
+	State                                      = 24
 	StatusASCode                               = 2702
 	StopTime                                   = 2042
 	SubmissionTime                             = 1202
+	SubsReqType                                = 705
 	SubscribedPeriodicRAUTAUTimer              = 1619
 	SubscribedVSRVCC                           = 1636
 	SubscriberRole                             = 2033
@@ -695,6 +751,9 @@ const (
 	TunnelType                                 = 64
 	Tunneling                                  = 401
 	TypeNumber                                 = 1204
+	UARFlags                                   = 637
+	UDRFlags                                   = 719
+	UELocalIPAddress                           = 2805
 	UESRVCCCapability                          = 1615
 	ULAFlags                                   = 1406
 	ULRFlags                                   = 1405
@@ -705,12 +764,15 @@ const (
 	UsageMonitoringInformation                 = 1067
 	UsageMonitoringLevel                       = 1068
 	UsedServiceUnit                            = 446
+	UserAuthorizationType                      = 623
 	UserCSGInformation                         = 2319
 	UserData                                   = 606
+	UserDataAlreadyAvailable                   = 624
 	UserEquipmentInfo                          = 458
 	UserEquipmentInfoType                      = 459
 	UserEquipmentInfoValue                     = 460
 	UserID                                     = 1444
+	UserIdentity                               = 700
 	UserLocationInfoTime                       = 2812
 	UserName                                   = 1
 	UserParticipatingType                      = 1279
@@ -729,6 +791,7 @@ const (
 	VisitedNetworkIdentifier                   = 600
 	VisitedPLMNID                              = 1407
 	VolumeQuotaThreshold                       = 869
+	WildcardedPublicIdentity                   = 634
 	XRES                                       = 1448
 	ePDGAddress                                = 3425
 )
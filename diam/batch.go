@@ -0,0 +1,172 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+import (
+	"bytes"
+	"net"
+	"sync"
+	"time"
+)
+
+// BatchFlushLength is the number of bytes of batched messages that are
+// written immediately, without waiting for the flush interval.
+var BatchFlushLength = 1 << 16
+
+// The Batcher interface is implemented by Conns that can batch the
+// messages written to them, such as the Conns of this package for
+// stream connections.
+//
+// Batching raises the throughput of peers that write many small
+// messages, such as charging clients, by writing the messages written
+// within the flush interval with a single vectored write (writev on TCP
+// connections), at the cost of delaying them by up to the interval.
+// Errors of the delayed writes are returned by the next Write, or Flush.
+type Batcher interface {
+	// SetFlushInterval sets the maximum delay of the messages written
+	// to the Conn. Zero disables batching, after writing the batched
+	// messages.
+	SetFlushInterval(d time.Duration) error
+
+	// Flush writes the batched messages.
+	Flush() error
+}
+
+// batchWriter copies the messages written to it into pooled buffers, and
+// writes them to the connection interval after the first, or when they
+// reach BatchFlushLength.
+type batchWriter struct {
+	mu       sync.Mutex // guards the following
+	w        net.Conn
+	interval time.Duration
+	timeout  time.Duration // write timeout, see Server.WriteTimeout
+	bufs     []*bytes.Buffer
+	pending  net.Buffers
+	length   int
+	timer    *time.Timer
+	err      error // of the last delayed write
+}
+
+func newBatchWriter(w net.Conn, interval, timeout time.Duration) *batchWriter {
+	return &batchWriter{w: w, interval: interval, timeout: timeout}
+}
+
+// Write batches a copy of b.
+func (bw *batchWriter) Write(b []byte) (int, error) {
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+	if bw.err != nil {
+		err := bw.err
+		bw.err = nil
+		return 0, err
+	}
+	buf := newWriterBuffer(len(b))
+	p := buf.Bytes()[:len(b)]
+	copy(p, b)
+	bw.bufs = append(bw.bufs, buf)
+	bw.pending = append(bw.pending, p)
+	bw.length += len(p)
+	if bw.length >= BatchFlushLength {
+		if err := bw.flush(); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+	}
+	if bw.timer == nil {
+		bw.timer = time.AfterFunc(bw.interval, bw.flushDelayed)
+	}
+	return len(b), nil
+}
+
+// Flush writes the batched messages.
+func (bw *batchWriter) Flush() error {
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+	if err := bw.flush(); err != nil {
+		return err
+	}
+	err := bw.err
+	bw.err = nil
+	return err
+}
+
+func (bw *batchWriter) flushDelayed() {
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+	if err := bw.flush(); err != nil {
+		bw.err = err
+	}
+}
+
+func (bw *batchWriter) flush() error {
+	if bw.timer != nil {
+		bw.timer.Stop()
+		bw.timer = nil
+	}
+	if len(bw.pending) == 0 {
+		return nil
+	}
+	if bw.timeout > 0 {
+		bw.w.SetWriteDeadline(time.Now().Add(bw.timeout))
+	}
+	// WriteTo consumes pending, so bufs keeps the buffers to return.
+	_, err := bw.pending.WriteTo(bw.w)
+	for i, buf := range bw.bufs {
+		putWriterBuffer(buf)
+		bw.bufs[i] = nil
+	}
+	bw.bufs, bw.pending, bw.length = bw.bufs[:0], bw.pending[:0], 0
+	return err
+}
+
+// setConn flushes the batched messages and writes the next ones to w.
+func (bw *batchWriter) setConn(w net.Conn) error {
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+	err := bw.flush()
+	bw.w = w
+	return err
+}
+
+// SetFlushInterval implements the Batcher interface.
+func (w *response) SetFlushInterval(d time.Duration) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	c := w.conn
+	if c.buf == nil {
+		// Messages of multistream connections are not batched, as
+		// they would mix up streams.
+		return nil
+	}
+	if d <= 0 {
+		if c.batch == nil {
+			return nil
+		}
+		err := c.batch.Flush()
+		c.batch = nil
+		return err
+	}
+	if c.batch != nil {
+		c.batch.mu.Lock()
+		c.batch.interval = d
+		c.batch.mu.Unlock()
+		return nil
+	}
+	if err := c.buf.Writer.Flush(); err != nil {
+		return err
+	}
+	c.batch = newBatchWriter(c.wc, d, c.server.WriteTimeout)
+	return nil
+}
+
+// Flush implements the Batcher interface.
+func (w *response) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn.batch == nil {
+		return nil
+	}
+	return w.conn.batch.Flush()
+}
@@ -0,0 +1,89 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+import (
+	"bytes"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordConn records the bytes written to it.
+type recordConn struct {
+	net.Conn
+	mu      sync.Mutex
+	b       bytes.Buffer
+	written chan struct{}
+}
+
+func (c *recordConn) Write(b []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.b.Write(b)
+	select {
+	case c.written <- struct{}{}:
+	default:
+	}
+	return len(b), nil
+}
+
+func (c *recordConn) SetWriteDeadline(time.Time) error { return nil }
+
+func (c *recordConn) bytes() []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]byte(nil), c.b.Bytes()...)
+}
+
+func TestBatchWriter(t *testing.T) {
+	rc := &recordConn{written: make(chan struct{}, 1)}
+	bw := newBatchWriter(rc, time.Hour, time.Second)
+	var want []byte
+	for _, s := range []string{"first", "second", "third"} {
+		if _, err := bw.Write([]byte(s)); err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, s...)
+	}
+	if b := rc.bytes(); len(b) > 0 {
+		t.Fatalf("Unexpected write before Flush: %q", b)
+	}
+	if err := bw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if b := rc.bytes(); !bytes.Equal(b, want) {
+		t.Fatalf("Unexpected bytes. Want %q, have %q", want, b)
+	}
+}
+
+func TestBatchWriter_Interval(t *testing.T) {
+	rc := &recordConn{written: make(chan struct{}, 1)}
+	bw := newBatchWriter(rc, 10*time.Millisecond, 0)
+	if _, err := bw.Write([]byte("delayed")); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-rc.written:
+	case <-time.After(time.Second):
+		t.Fatal("Timed out: batched message not written")
+	}
+	if b := rc.bytes(); string(b) != "delayed" {
+		t.Fatalf("Unexpected bytes. Want %q, have %q", "delayed", b)
+	}
+}
+
+func TestBatchWriter_FlushLength(t *testing.T) {
+	rc := &recordConn{written: make(chan struct{}, 1)}
+	bw := newBatchWriter(rc, time.Hour, 0)
+	b := make([]byte, BatchFlushLength)
+	if _, err := bw.Write(b); err != nil {
+		t.Fatal(err)
+	}
+	if have := rc.bytes(); len(have) != len(b) {
+		t.Fatalf("Unexpected length written. Want %d, have %d", len(b), len(have))
+	}
+}
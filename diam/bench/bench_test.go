@@ -0,0 +1,135 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package bench
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+var messages = []struct {
+	name string
+	new  func() *diam.Message
+}{
+	{"CER", NewCER},
+	{"CCR", NewCCR},
+	{"ULA", NewULA},
+}
+
+func serialize(t testing.TB, m *diam.Message) []byte {
+	b, err := m.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestMessages(t *testing.T) {
+	for _, tc := range messages {
+		b := serialize(t, tc.new())
+		d, err := diam.ReadMessage(bytes.NewReader(b), dict.Default)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if have := serialize(t, d); !bytes.Equal(have, b) {
+			t.Fatalf("%s: unexpected message.\nWant:\n%x\nHave:\n%x", tc.name, b, have)
+		}
+	}
+}
+
+// allocBudgets are the maximum allocations of writing the messages with
+// WriteTo, and of reading them with ReadMessage and ReadMessageLazy. Lower
+// them when the codec allocates less.
+var allocBudgets = map[string]struct {
+	encode, decode, decodeLazy float64
+}{
+	"CER": {encode: 12, decode: 35, decodeLazy: 34},
+	"CCR": {encode: 31, decode: 117, decodeLazy: 34},
+	"ULA": {encode: 41, decode: 153, decodeLazy: 31},
+}
+
+func TestAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocations are not representative with the race detector")
+	}
+	for _, tc := range messages {
+		m := tc.new()
+		b := serialize(t, m)
+		budget := allocBudgets[tc.name]
+		r := bytes.NewReader(b)
+		allocs := map[string]float64{
+			"encode": testing.AllocsPerRun(100, func() {
+				m.WriteTo(ioutil.Discard)
+			}),
+			"decode": testing.AllocsPerRun(100, func() {
+				r.Seek(0, 0)
+				diam.ReadMessage(r, dict.Default)
+			}),
+			"decodeLazy": testing.AllocsPerRun(100, func() {
+				r.Seek(0, 0)
+				m, _ := diam.ReadMessageLazy(r, dict.Default)
+				m.Release()
+			}),
+		}
+		max := map[string]float64{
+			"encode":     budget.encode,
+			"decode":     budget.decode,
+			"decodeLazy": budget.decodeLazy,
+		}
+		for op, n := range allocs {
+			if n > max[op] {
+				t.Errorf("%s %s: %.0f allocations, budget %.0f", tc.name, op, n, max[op])
+			}
+		}
+	}
+}
+
+func BenchmarkEncode(b *testing.B) {
+	for _, tc := range messages {
+		m := tc.new()
+		b.Run(tc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(m.Len()))
+			for n := 0; n < b.N; n++ {
+				m.WriteTo(ioutil.Discard)
+			}
+		})
+	}
+}
+
+func BenchmarkDecode(b *testing.B) {
+	for _, tc := range messages {
+		msg := serialize(b, tc.new())
+		b.Run(tc.name, func(b *testing.B) {
+			r := bytes.NewReader(msg)
+			b.ReportAllocs()
+			b.SetBytes(int64(len(msg)))
+			for n := 0; n < b.N; n++ {
+				r.Seek(0, 0)
+				diam.ReadMessage(r, dict.Default)
+			}
+		})
+	}
+}
+
+func BenchmarkDecodeLazy(b *testing.B) {
+	for _, tc := range messages {
+		msg := serialize(b, tc.new())
+		b.Run(tc.name, func(b *testing.B) {
+			r := bytes.NewReader(msg)
+			b.ReportAllocs()
+			b.SetBytes(int64(len(msg)))
+			for n := 0; n < b.N; n++ {
+				r.Seek(0, 0)
+				m, _ := diam.ReadMessageLazy(r, dict.Default)
+				m.Release()
+			}
+		})
+	}
+}
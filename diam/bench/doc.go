@@ -0,0 +1,17 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package bench provides representative Diameter messages to benchmark
+// the codec of the diam package, and holds its benchmarks and allocation
+// regression tests:
+//
+//	go test -bench . -benchmem github.com/omnicate/go-diameter/v4/diam/bench
+//
+// The messages are a Capabilities-Exchange-Request, a Credit-Control-Request
+// with Multiple-Services-Credit-Control AVPs and an S6a Update-Location-Answer
+// with Subscription-Data. TestAllocs fails when encoding or decoding them
+// allocates more than the recorded budgets, so that regressions are caught
+// by the tests rather than by the users; the budgets should be lowered
+// along with the improvements of the codec.
+package bench
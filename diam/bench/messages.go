@@ -0,0 +1,115 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package bench
+
+import (
+	"net"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+// NewCER returns a Capabilities-Exchange-Request advertising the Credit
+// Control and S6a applications.
+func NewCER() *diam.Message {
+	m := diam.NewRequest(diam.CapabilitiesExchange, 0, dict.Default)
+	m.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("client.example.com"))
+	m.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity("example.com"))
+	m.NewAVP(avp.HostIPAddress, avp.Mbit, 0, datatype.Address(net.ParseIP("10.0.0.1")))
+	m.NewAVP(avp.VendorID, avp.Mbit, 0, datatype.Unsigned32(10415))
+	m.NewAVP(avp.ProductName, 0, 0, datatype.UTF8String("go-diameter"))
+	m.NewAVP(avp.OriginStateID, avp.Mbit, 0, datatype.Unsigned32(1397760650))
+	m.NewAVP(avp.SupportedVendorID, avp.Mbit, 0, datatype.Unsigned32(10415))
+	m.NewAVP(avp.AuthApplicationID, avp.Mbit, 0, datatype.Unsigned32(diam.CHARGING_CONTROL_APP_ID))
+	check(m.NewGroup().
+		Add(avp.AuthApplicationID, avp.Mbit, 0, datatype.Unsigned32(diam.TGPP_S6A_APP_ID)).
+		Add(avp.VendorID, avp.Mbit, 0, datatype.Unsigned32(10415)).
+		AddTo(m, avp.VendorSpecificApplicationID, avp.Mbit, 0))
+	m.NewAVP(avp.FirmwareRevision, 0, 0, datatype.Unsigned32(1))
+	return m
+}
+
+// NewCCR returns an update Credit-Control-Request reporting the usage of
+// three rating groups.
+func NewCCR() *diam.Message {
+	m := diam.NewRequest(diam.CreditControl, diam.CHARGING_CONTROL_APP_ID, dict.Default)
+	m.NewAVP(avp.SessionID, avp.Mbit, 0, datatype.UTF8String("client.example.com;1397760650;42"))
+	m.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("client.example.com"))
+	m.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity("example.com"))
+	m.NewAVP(avp.DestinationRealm, avp.Mbit, 0, datatype.DiameterIdentity("ocs.example.com"))
+	m.NewAVP(avp.AuthApplicationID, avp.Mbit, 0, datatype.Unsigned32(diam.CHARGING_CONTROL_APP_ID))
+	m.NewAVP(avp.ServiceContextID, avp.Mbit, 0, datatype.UTF8String("32251@3gpp.org"))
+	m.NewAVP(avp.CCRequestType, avp.Mbit, 0, datatype.Enumerated(2))
+	m.NewAVP(avp.CCRequestNumber, avp.Mbit, 0, datatype.Unsigned32(1))
+	m.NewAVP(avp.EventTimestamp, avp.Mbit, 0, datatype.Time(time.Unix(1397760650, 0)))
+	check(m.NewGroup().
+		Add(avp.SubscriptionIDType, avp.Mbit, 0, datatype.Enumerated(0)).
+		Add(avp.SubscriptionIDData, avp.Mbit, 0, datatype.UTF8String("5511999998888")).
+		AddTo(m, avp.SubscriptionID, avp.Mbit, 0))
+	m.NewAVP(avp.MultipleServicesIndicator, avp.Mbit, 0, datatype.Enumerated(1))
+	for rg := uint32(1); rg <= 3; rg++ {
+		check(m.NewGroup().
+			AddGroup(avp.RequestedServiceUnit, avp.Mbit, 0, m.NewGroup()).
+			AddGroup(avp.UsedServiceUnit, avp.Mbit, 0, m.NewGroup().
+				Add(avp.CCTime, avp.Mbit, 0, datatype.Unsigned32(60)).
+				Add(avp.CCInputOctets, avp.Mbit, 0, datatype.Unsigned64(1<<20)).
+				Add(avp.CCOutputOctets, avp.Mbit, 0, datatype.Unsigned64(1<<22))).
+			Add(avp.RatingGroup, avp.Mbit, 0, datatype.Unsigned32(rg)).
+			AddTo(m, avp.MultipleServicesCreditControl, avp.Mbit, 0))
+	}
+	return m
+}
+
+// NewULA returns an S6a Update-Location-Answer with the Subscription-Data
+// of a subscriber with two APN configurations.
+func NewULA() *diam.Message {
+	m := diam.NewMessage(diam.UpdateLocation, 0, diam.TGPP_S6A_APP_ID, 0, 0, dict.Default)
+	m.NewAVP(avp.SessionID, avp.Mbit, 0, datatype.UTF8String("mme.example.com;1397760650;42"))
+	check(m.NewGroup().
+		Add(avp.VendorID, avp.Mbit, 0, datatype.Unsigned32(10415)).
+		Add(avp.AuthApplicationID, avp.Mbit, 0, datatype.Unsigned32(diam.TGPP_S6A_APP_ID)).
+		AddTo(m, avp.VendorSpecificApplicationID, avp.Mbit, 0))
+	m.NewAVP(avp.ResultCode, avp.Mbit, 0, datatype.Unsigned32(diam.Success))
+	m.NewAVP(avp.AuthSessionState, avp.Mbit, 0, datatype.Enumerated(1))
+	m.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("hss.example.com"))
+	m.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity("example.com"))
+	m.NewAVP(avp.ULAFlags, avp.Mbit|avp.Vbit, 10415, datatype.Unsigned32(1))
+	apns := m.NewGroup().
+		Add(avp.ContextIdentifier, avp.Mbit|avp.Vbit, 10415, datatype.Unsigned32(1)).
+		Add(avp.AllAPNConfigurationsIncludedIndicator, avp.Mbit|avp.Vbit, 10415, datatype.Enumerated(0))
+	for i, apn := range []string{"internet", "ims"} {
+		apns.AddGroup(avp.APNConfiguration, avp.Mbit|avp.Vbit, 10415, m.NewGroup().
+			Add(avp.ContextIdentifier, avp.Mbit|avp.Vbit, 10415, datatype.Unsigned32(i+1)).
+			Add(avp.PDNType, avp.Mbit|avp.Vbit, 10415, datatype.Enumerated(2)).
+			Add(avp.ServiceSelection, avp.Mbit|avp.Vbit, 10415, datatype.UTF8String(apn)).
+			AddGroup(avp.EPSSubscribedQoSProfile, avp.Mbit|avp.Vbit, 10415, m.NewGroup().
+				Add(avp.QoSClassIdentifier, avp.Mbit|avp.Vbit, 10415, datatype.Enumerated(9)).
+				AddGroup(avp.AllocationRetentionPriority, avp.Mbit|avp.Vbit, 10415, m.NewGroup().
+					Add(avp.PriorityLevel, avp.Mbit|avp.Vbit, 10415, datatype.Unsigned32(8)))).
+			AddGroup(avp.AMBR, avp.Mbit|avp.Vbit, 10415, m.NewGroup().
+				Add(avp.MaxRequestedBandwidthUL, avp.Mbit|avp.Vbit, 10415, datatype.Unsigned32(50000000)).
+				Add(avp.MaxRequestedBandwidthDL, avp.Mbit|avp.Vbit, 10415, datatype.Unsigned32(100000000))))
+	}
+	check(m.NewGroup().
+		Add(avp.MSISDN, avp.Mbit|avp.Vbit, 10415, datatype.OctetString("\x55\x11\x99\x99\x88\x88")).
+		Add(avp.SubscriberStatus, avp.Mbit|avp.Vbit, 10415, datatype.Enumerated(0)).
+		Add(avp.NetworkAccessMode, avp.Mbit|avp.Vbit, 10415, datatype.Enumerated(2)).
+		AddGroup(avp.AMBR, avp.Mbit|avp.Vbit, 10415, m.NewGroup().
+			Add(avp.MaxRequestedBandwidthUL, avp.Mbit|avp.Vbit, 10415, datatype.Unsigned32(50000000)).
+			Add(avp.MaxRequestedBandwidthDL, avp.Mbit|avp.Vbit, 10415, datatype.Unsigned32(100000000))).
+		AddGroup(avp.APNConfigurationProfile, avp.Mbit|avp.Vbit, 10415, apns).
+		AddTo(m, avp.SubscriptionData, avp.Mbit|avp.Vbit, 10415))
+	return m
+}
+
+// check panics on the errors of the fixed messages built above.
+func check(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,9 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// +build !race
+
+package bench
+
+const raceEnabled = false
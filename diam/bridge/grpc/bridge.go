@@ -0,0 +1,316 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package grpc
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/agent"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/bridge/grpc/protos"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+	"github.com/omnicate/go-diameter/v4/diam/sm"
+)
+
+// DefaultAnswerTimeout is the time the Bridge waits for the answer of a
+// request when AnswerTimeout is not set.
+const DefaultAnswerTimeout = 30 * time.Second
+
+var (
+	// ErrNoHeader is returned when decoding a protobuf message without
+	// header.
+	ErrNoHeader = errors.New("message has no header")
+
+	// ErrUnknownAnswer is reported when a gRPC client sends an answer
+	// that does not belong to any streamed request.
+	ErrUnknownAnswer = errors.New("answer to unknown request")
+)
+
+// Bridge implements the protos.DiameterServer gRPC service.
+//
+// Requests sent by gRPC clients with SendRequest are sent to the peer in
+// their Destination-Host, if connected, or else to the next hop selected
+// by Routes, and their answers returned to the clients.
+//
+// Bridge is also a diam.Handler that streams the requests it receives to
+// the gRPC clients of Serve, in turns, and sends the answers of the
+// clients back to the peers. Requests are answered with
+// DIAMETER_UNABLE_TO_DELIVER when no client is connected.
+//
+// Errors are reported to Peers, if it implements diam.ErrorReporter.
+type Bridge struct {
+	OriginHost  datatype.DiameterIdentity
+	OriginRealm datatype.DiameterIdentity
+
+	// AnswerTimeout is the time a request waits for its answer, from
+	// the peer or the gRPC client. Defaults to DefaultAnswerTimeout.
+	AnswerTimeout time.Duration
+
+	// Routes selects the peer requests of gRPC clients are sent to when
+	// their Destination-Host is not connected.
+	Routes *sm.RoutingTable
+
+	// Dict is the dictionary of the messages of gRPC clients. If nil,
+	// the default dictionary is used.
+	Dict *dict.Parser
+
+	peers agent.Peers
+
+	mu      sync.Mutex // guards streams, next and pending
+	streams []*stream
+	next    int
+	pending map[uint32]*streamedRequest
+}
+
+// stream is a Serve call of a gRPC client.
+type stream struct {
+	mu sync.Mutex // serializes Send
+	s  protos.Diameter_ServeServer
+}
+
+func (s *stream) send(m *protos.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.s.Send(m)
+}
+
+// streamedRequest is a request streamed to a gRPC client.
+type streamedRequest struct {
+	conn  diam.Conn
+	hbh   uint32
+	timer *time.Timer
+}
+
+// NewBridge creates and initializes a Bridge identified by host and
+// realm, which sends requests to peers.
+func NewBridge(host, realm datatype.DiameterIdentity, peers agent.Peers) *Bridge {
+	return &Bridge{
+		OriginHost:  host,
+		OriginRealm: realm,
+		Routes:      sm.NewRoutingTable(),
+		peers:       peers,
+		pending:     make(map[uint32]*streamedRequest),
+	}
+}
+
+func (b *Bridge) answerTimeout() time.Duration {
+	if b.AnswerTimeout == 0 {
+		return DefaultAnswerTimeout
+	}
+	return b.AnswerTimeout
+}
+
+// SendRequest implements the protos.DiameterServer interface. The
+// Origin-Host and Origin-Realm of the bridge are added to requests
+// without them.
+//
+// Errors are returned with gRPC status codes: InvalidArgument for
+// invalid requests, NotFound when there is no route to their
+// Destination-Realm, Unavailable when they cannot be sent, and
+// DeadlineExceeded when their answer does not arrive in time.
+func (b *Bridge) SendRequest(ctx context.Context, pm *protos.Message) (*protos.Message, error) {
+	m, err := DecodeMessage(pm, b.Dict)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if m.Header.CommandFlags&diam.RequestFlag == 0 {
+		return nil, status.Error(codes.InvalidArgument, "message is not a request")
+	}
+	if _, err = m.FindAVP(avp.OriginHost, 0); err != nil {
+		m.NewAVP(avp.OriginHost, avp.Mbit, 0, b.OriginHost)
+	}
+	if _, err = m.FindAVP(avp.OriginRealm, 0); err != nil {
+		m.NewAVP(avp.OriginRealm, avp.Mbit, 0, b.OriginRealm)
+	}
+	c, err := b.route(m)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.answerTimeout())
+		defer cancel()
+	}
+	a, err := diam.SendRequest(ctx, c, m)
+	switch err {
+	case nil:
+		return EncodeMessage(a), nil
+	case context.DeadlineExceeded:
+		return nil, status.Error(codes.DeadlineExceeded, err.Error())
+	case context.Canceled:
+		return nil, status.Error(codes.Canceled, err.Error())
+	}
+	return nil, status.Error(codes.Unavailable, err.Error())
+}
+
+// route returns the connection the request m must be sent on.
+func (b *Bridge) route(m *diam.Message) (diam.Conn, error) {
+	c, code := agent.Route(m, b.peers, b.Routes)
+	switch code {
+	case 0:
+		return c, nil
+	case diam.MissingAVP:
+		return nil, status.Error(codes.InvalidArgument, "request has no Destination-Realm")
+	case diam.RealmNotServed:
+		return nil, status.Error(codes.NotFound, sm.ErrNoRoute.Error())
+	}
+	return nil, status.Error(codes.Unavailable, "no connection to the destination")
+}
+
+// Serve implements the protos.DiameterServer interface. It streams
+// requests to the client until the client closes the stream.
+func (b *Bridge) Serve(s protos.Diameter_ServeServer) error {
+	st := &stream{s: s}
+	b.mu.Lock()
+	b.streams = append(b.streams, st)
+	b.mu.Unlock()
+	defer b.removeStream(st)
+	for {
+		pm, err := s.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err = b.serveAnswer(pm); err != nil {
+			b.error(&diam.ErrorReport{Error: err})
+		}
+	}
+}
+
+func (b *Bridge) removeStream(st *stream) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, s := range b.streams {
+		if s == st {
+			b.streams = append(b.streams[:i], b.streams[i+1:]...)
+			return
+		}
+	}
+}
+
+// nextStream returns the stream of the next client in turn, or nil if
+// no client is connected.
+func (b *Bridge) nextStream() *stream {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.streams) == 0 {
+		return nil
+	}
+	b.next = (b.next + 1) % len(b.streams)
+	return b.streams[b.next]
+}
+
+// serveAnswer sends the answer of a gRPC client to the peer its request
+// was received from.
+func (b *Bridge) serveAnswer(pm *protos.Message) error {
+	if pm.GetHeader() == nil {
+		return ErrNoHeader
+	}
+	req, ok := b.untrack(pm.Header.HopByHopId)
+	if !ok {
+		return ErrUnknownAnswer
+	}
+	a, err := DecodeMessage(pm, b.Dict)
+	if err != nil {
+		return err
+	}
+	a.Header.HopByHopID = req.hbh
+	a.Header.CommandFlags &^= diam.RequestFlag
+	_, err = a.WriteTo(req.conn)
+	return err
+}
+
+// ServeDIAM implements the diam.Handler interface.
+func (b *Bridge) ServeDIAM(c diam.Conn, m *diam.Message) {
+	if m.Header.CommandFlags&diam.RequestFlag == 0 {
+		return
+	}
+	if err := b.serveRequest(c, m); err != nil {
+		b.error(&diam.ErrorReport{Conn: c, Message: m, Error: err})
+	}
+}
+
+func (b *Bridge) serveRequest(c diam.Conn, m *diam.Message) error {
+	st := b.nextStream()
+	if st == nil {
+		return b.answerError(c, m, diam.UnableToDeliver)
+	}
+	pm := EncodeMessage(m)
+	id := b.track(c, m.Header.HopByHopID)
+	pm.Header.HopByHopId = id
+	if err := st.send(pm); err != nil {
+		b.untrack(id)
+		b.answerError(c, m, diam.UnableToDeliver)
+		return err
+	}
+	return nil
+}
+
+// track saves the state of a request received on c with the Hop-by-Hop
+// identifier hbh, and returns the identifier to stream it with.
+func (b *Bridge) track(c diam.Conn, hbh uint32) uint32 {
+	timeout := b.answerTimeout()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id := rand.Uint32()
+	for _, exists := b.pending[id]; exists; _, exists = b.pending[id] {
+		id = rand.Uint32()
+	}
+	b.pending[id] = &streamedRequest{
+		conn:  c,
+		hbh:   hbh,
+		timer: time.AfterFunc(timeout, func() { b.untrack(id) }),
+	}
+	return id
+}
+
+// untrack removes and returns the state of the request streamed with
+// the Hop-by-Hop identifier id.
+func (b *Bridge) untrack(id uint32) (*streamedRequest, bool) {
+	b.mu.Lock()
+	req, ok := b.pending[id]
+	delete(b.pending, id)
+	b.mu.Unlock()
+	if ok {
+		req.timer.Stop()
+	}
+	return req, ok
+}
+
+// answerError sends an answer to the request m with the E bit and the
+// given Result-Code.
+func (b *Bridge) answerError(c diam.Conn, m *diam.Message, code uint32) error {
+	a := m.Answer(0)
+	a.Header.CommandFlags |= diam.ErrorFlag
+	for _, sid := range m.AVP {
+		if sid.Code == avp.SessionID {
+			a.AddAVP(sid)
+			break
+		}
+	}
+	a.NewAVP(avp.ResultCode, avp.Mbit, 0, datatype.Unsigned32(code))
+	a.NewAVP(avp.OriginHost, avp.Mbit, 0, b.OriginHost)
+	a.NewAVP(avp.OriginRealm, avp.Mbit, 0, b.OriginRealm)
+	_, err := a.WriteTo(c)
+	return err
+}
+
+func (b *Bridge) error(err *diam.ErrorReport) {
+	if er, ok := b.peers.(diam.ErrorReporter); ok {
+		er.Error(err)
+	}
+}
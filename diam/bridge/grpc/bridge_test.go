@@ -0,0 +1,233 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package grpc
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/bridge/grpc/protos"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+	"github.com/omnicate/go-diameter/v4/diam/sm"
+)
+
+var (
+	serverSettings = &sm.Settings{
+		OriginHost:  "srv",
+		OriginRealm: "srv.test",
+		VendorID:    13,
+		ProductName: "go-diameter",
+	}
+
+	clientSettings = &sm.Settings{
+		OriginHost:  "cli",
+		OriginRealm: "cli.test",
+		VendorID:    13,
+		ProductName: "go-diameter",
+	}
+
+	acctApp = []*diam.AVP{
+		diam.NewAVP(avp.AcctApplicationID, avp.Mbit, 0, datatype.Unsigned32(3)),
+	}
+)
+
+func newACR() *diam.Message {
+	m := diam.NewMessage(diam.Accounting, diam.RequestFlag|diam.ProxiableFlag, 3, 0, 0, dict.Default)
+	m.NewAVP(avp.SessionID, avp.Mbit, 0, datatype.UTF8String("sess"))
+	m.NewAVP(avp.OriginHost, avp.Mbit, 0, clientSettings.OriginHost)
+	m.NewAVP(avp.OriginRealm, avp.Mbit, 0, clientSettings.OriginRealm)
+	m.NewAVP(avp.DestinationRealm, avp.Mbit, 0, serverSettings.OriginRealm)
+	m.NewAVP(avp.AccountingRecordType, avp.Mbit, 0, datatype.Enumerated(1))
+	m.NewAVP(avp.AccountingRecordNumber, avp.Mbit, 0, datatype.Unsigned32(0))
+	m.NewAVP(avp.EventTimestamp, avp.Mbit, 0, datatype.Time(time.Unix(1577836800, 0)))
+	m.NewAVP(avp.VendorSpecificApplicationID, avp.Mbit, 0, &diam.GroupedAVP{
+		AVP: []*diam.AVP{
+			diam.NewAVP(avp.VendorID, avp.Mbit, 0, datatype.Unsigned32(10415)),
+			diam.NewAVP(avp.AcctApplicationID, avp.Mbit, 0, datatype.Unsigned32(3)),
+		},
+	})
+	return m
+}
+
+func TestEncodeDecodeMessage(t *testing.T) {
+	m := newACR()
+	pm := EncodeMessage(m)
+	if pm.Avps[0].Name != "Session-Id" || pm.Avps[0].Text != "sess" || len(pm.Avps[7].Avps) != 2 {
+		t.Fatalf("Unexpected message: %v", pm)
+	}
+	d, err := DecodeMessage(pm, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := m.Serialize()
+	have, _ := d.Serialize()
+	if !bytes.Equal(want, have) {
+		t.Fatalf("Unexpected message.\nWant %x\nHave %x", want, have)
+	}
+
+	// AVPs by name.
+	pm.Avps = []*protos.AVP{{Name: "Result-Code", Unsigned: diam.Success}}
+	if d, err = DecodeMessage(pm, nil); err != nil {
+		t.Fatal(err)
+	}
+	if a := d.AVP[0]; a.Code != avp.ResultCode || a.Flags != avp.Mbit || a.Data != datatype.Unsigned32(diam.Success) {
+		t.Fatalf("Unexpected AVP: %s", a)
+	}
+	if _, err = DecodeMessage(&protos.Message{}, nil); err != ErrNoHeader {
+		t.Fatalf("Unexpected error. Want %v, have %v", ErrNoHeader, err)
+	}
+}
+
+// newServer starts a server that answers ACRs.
+func newServer() *diamtest.Server {
+	srvSM := sm.New(serverSettings)
+	srvSM.HandleFunc("ACR", func(c diam.Conn, m *diam.Message) {
+		a := m.Answer(diam.Success)
+		a.NewAVP(avp.OriginHost, avp.Mbit, 0, serverSettings.OriginHost)
+		a.NewAVP(avp.OriginRealm, avp.Mbit, 0, serverSettings.OriginRealm)
+		a.WriteTo(c)
+	})
+	return diamtest.NewServer(srvSM, dict.Default)
+}
+
+func TestBridgeSendRequest(t *testing.T) {
+	srv := newServer()
+	defer srv.Close()
+	cliSM := sm.New(clientSettings)
+	c, err := (&sm.Client{Handler: cliSM, AcctApplicationID: acctApp}).Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	b := NewBridge(clientSettings.OriginHost, clientSettings.OriginRealm, cliSM)
+	b.Routes.Add(serverSettings.OriginRealm, sm.AnyApplication, sm.Route{Host: serverSettings.OriginHost})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	pa, err := b.SendRequest(ctx, EncodeMessage(newACR()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := DecodeMessage(pa, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rc, err := a.FindAVP(avp.ResultCode, 0); err != nil || rc.Data != datatype.Unsigned32(diam.Success) {
+		t.Fatalf("Unexpected answer: %s", a)
+	}
+
+	m := newACR()
+	m.Header.CommandFlags = 0
+	if _, err = b.SendRequest(ctx, EncodeMessage(m)); err == nil {
+		t.Fatal("Unexpected success sending an answer")
+	}
+	b.Routes.Remove(serverSettings.OriginRealm, sm.AnyApplication)
+	if _, err = b.SendRequest(ctx, EncodeMessage(newACR())); err == nil {
+		t.Fatal("Unexpected success without route")
+	}
+}
+
+// serveStream is a protos.Diameter_ServeServer that passes the streamed
+// requests and answers through channels.
+type serveStream struct {
+	protos.Diameter_ServeServer
+	reqc chan *protos.Message
+	ansc chan *protos.Message
+}
+
+func (s *serveStream) Send(m *protos.Message) error {
+	s.reqc <- m
+	return nil
+}
+
+func (s *serveStream) Recv() (*protos.Message, error) {
+	m, ok := <-s.ansc
+	if !ok {
+		return nil, io.EOF
+	}
+	return m, nil
+}
+
+func TestBridgeServe(t *testing.T) {
+	srvSM := sm.New(serverSettings)
+	b := NewBridge(serverSettings.OriginHost, serverSettings.OriginRealm, srvSM)
+	srvSM.Handle("ACR", b)
+	srv := diamtest.NewServer(srvSM, dict.Default)
+	defer srv.Close()
+
+	ansc := make(chan *diam.Message, 1)
+	cliSM := sm.New(clientSettings)
+	cliSM.HandleFunc("ACA", func(c diam.Conn, m *diam.Message) {
+		ansc <- m
+	})
+	c, err := (&sm.Client{Handler: cliSM, AcctApplicationID: acctApp}).Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	// No gRPC client.
+	if _, err = newACR().WriteTo(c); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case a := <-ansc:
+		if rc, err := a.FindAVP(avp.ResultCode, 0); err != nil || rc.Data != datatype.Unsigned32(diam.UnableToDeliver) {
+			t.Fatalf("Unexpected answer: %s", a)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for ACA")
+	}
+
+	s := &serveStream{reqc: make(chan *protos.Message, 1), ansc: make(chan *protos.Message)}
+	done := make(chan error)
+	go func() { done <- b.Serve(s) }()
+	for ok := false; !ok; {
+		b.mu.Lock()
+		ok = len(b.streams) == 1
+		b.mu.Unlock()
+	}
+	m := newACR()
+	if _, err = m.WriteTo(c); err != nil {
+		t.Fatal(err)
+	}
+	var pm *protos.Message
+	select {
+	case pm = <-s.reqc:
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for streamed ACR")
+	}
+	pm.Header.Flags &^= diam.RequestFlag
+	pm.Avps = []*protos.AVP{
+		pm.Avps[0],
+		{Name: "Result-Code", Unsigned: diam.Success},
+		{Name: "Origin-Host", Text: string(serverSettings.OriginHost)},
+		{Name: "Origin-Realm", Text: string(serverSettings.OriginRealm)},
+	}
+	s.ansc <- pm
+	select {
+	case a := <-ansc:
+		if a.Header.HopByHopID != m.Header.HopByHopID {
+			t.Fatalf("Unexpected Hop-by-Hop ID. Want %#x, have %#x", m.Header.HopByHopID, a.Header.HopByHopID)
+		}
+		if rc, err := a.FindAVP(avp.ResultCode, 0); err != nil || rc.Data != datatype.Unsigned32(diam.Success) {
+			t.Fatalf("Unexpected answer: %s", a)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for ACA")
+	}
+	close(s.ansc)
+	if err = <-done; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package grpc
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/bridge/grpc/protos"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+// EncodeMessage returns the protobuf representation of m. AVPs are
+// named after the message dictionary.
+func EncodeMessage(m *diam.Message) *protos.Message {
+	return &protos.Message{
+		Header: &protos.Header{
+			Version:       uint32(m.Header.Version),
+			Flags:         uint32(m.Header.CommandFlags),
+			CommandCode:   m.Header.CommandCode,
+			ApplicationId: m.Header.ApplicationID,
+			HopByHopId:    m.Header.HopByHopID,
+			EndToEndId:    m.Header.EndToEndID,
+		},
+		Avps: encodeAVPs(m, m.AVP),
+	}
+}
+
+func encodeAVPs(m *diam.Message, avps []*diam.AVP) []*protos.AVP {
+	pa := make([]*protos.AVP, len(avps))
+	for i, a := range avps {
+		pa[i] = encodeAVP(m, a)
+	}
+	return pa
+}
+
+func encodeAVP(m *diam.Message, a *diam.AVP) *protos.AVP {
+	p := &protos.AVP{
+		Code:     a.Code,
+		Flags:    uint32(a.Flags),
+		VendorId: a.VendorID,
+	}
+	if dictAVP, err := m.Dictionary().FindAVPWithVendor(m.Header.ApplicationID, a.Code, a.VendorID); err == nil {
+		p.Name = dictAVP.Name
+	}
+	switch d := a.Data.(type) {
+	case *diam.GroupedAVP:
+		p.Avps = encodeAVPs(m, d.AVP)
+	case datatype.Address:
+		if ip := d.IP(); ip != nil {
+			p.Text = ip.String()
+		} else {
+			p.Data = d.Serialize()
+		}
+	case datatype.IPv4:
+		p.Text = net.IP(d).String()
+	case datatype.IPv6:
+		p.Text = net.IP(d).String()
+	case datatype.UTF8String:
+		p.Text = string(d)
+	case datatype.DiameterIdentity:
+		p.Text = string(d)
+	case datatype.DiameterURI:
+		p.Text = string(d)
+	case datatype.IPFilterRule:
+		p.Text = string(d)
+	case datatype.QoSFilterRule:
+		p.Text = string(d)
+	case datatype.Time:
+		p.Text = time.Time(d).Format(time.RFC3339)
+	case datatype.Enumerated:
+		p.Integer = int64(d)
+	case datatype.Integer32:
+		p.Integer = int64(d)
+	case datatype.Integer64:
+		p.Integer = int64(d)
+	case datatype.Unsigned32:
+		p.Unsigned = uint64(d)
+	case datatype.Unsigned64:
+		p.Unsigned = uint64(d)
+	case datatype.Float32:
+		p.Float = float64(d)
+	case datatype.Float64:
+		p.Float = float64(d)
+	default:
+		p.Data = a.Data.Serialize()
+	}
+	return p
+}
+
+// DecodeMessage returns the Diameter message represented by pm, using
+// the dictionary d to find the data types of its AVPs, or the default
+// dictionary if d is nil. AVPs may be given by name only, in which case
+// their code, vendor and M flag are taken from the dictionary. Zero
+// Hop-by-Hop and End-to-End identifiers are replaced by random ones.
+func DecodeMessage(pm *protos.Message, d *dict.Parser) (*diam.Message, error) {
+	h := pm.GetHeader()
+	if h == nil {
+		return nil, ErrNoHeader
+	}
+	m := diam.NewMessage(h.CommandCode, uint8(h.Flags), h.ApplicationId, h.HopByHopId, h.EndToEndId, d)
+	if h.Version != 0 {
+		m.Header.Version = uint8(h.Version)
+	}
+	for _, p := range pm.Avps {
+		a, err := decodeAVP(m, p)
+		if err != nil {
+			return nil, err
+		}
+		m.AddAVP(a)
+	}
+	return m, nil
+}
+
+func decodeAVP(m *diam.Message, p *protos.AVP) (*diam.AVP, error) {
+	code, vendorID := p.Code, p.VendorId
+	var dictAVP *dict.AVP
+	if code == 0 && p.Name != "" {
+		vid := vendorID
+		if vid == 0 {
+			vid = dict.UndefinedVendorID
+		}
+		a, err := m.Dictionary().FindAVPWithVendor(m.Header.ApplicationID, p.Name, vid)
+		if err != nil {
+			return nil, err
+		}
+		dictAVP, code, vendorID = a, a.Code, a.VendorID
+	} else if a, err := m.Dictionary().FindAVPWithVendor(m.Header.ApplicationID, code, vendorID); err == nil {
+		dictAVP = a
+	}
+	flags := uint8(p.Flags)
+	if flags == 0 && dictAVP != nil && strings.Contains(dictAVP.Must, "M") {
+		flags = avp.Mbit
+	}
+	typ := datatype.UnknownType
+	if dictAVP != nil {
+		typ = dictAVP.Data.Type
+	} else if len(p.Avps) > 0 {
+		typ = datatype.GroupedType
+	}
+	data, err := decodeData(m, p, typ)
+	if err != nil {
+		return nil, fmt.Errorf("AVP %d: %v", code, err)
+	}
+	return diam.NewAVP(code, flags, vendorID, data), nil
+}
+
+func decodeData(m *diam.Message, p *protos.AVP, typ datatype.TypeID) (datatype.Type, error) {
+	switch typ {
+	case datatype.GroupedType:
+		g := &diam.GroupedAVP{}
+		for _, pa := range p.Avps {
+			a, err := decodeAVP(m, pa)
+			if err != nil {
+				return nil, err
+			}
+			g.AddAVP(a)
+		}
+		return g, nil
+	case datatype.AddressType, datatype.IPv4Type, datatype.IPv6Type:
+		if p.Text == "" {
+			return datatype.Decode(typ, p.Data)
+		}
+		ip := net.ParseIP(p.Text)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid address %q", p.Text)
+		}
+		switch typ {
+		case datatype.IPv4Type:
+			return datatype.IPv4(ip), nil
+		case datatype.IPv6Type:
+			return datatype.IPv6(ip), nil
+		}
+		if v4 := ip.To4(); v4 != nil {
+			ip = v4
+		}
+		return datatype.Address(ip), nil
+	case datatype.UTF8StringType:
+		return datatype.UTF8String(p.Text), nil
+	case datatype.DiameterIdentityType:
+		return datatype.DiameterIdentity(p.Text), nil
+	case datatype.DiameterURIType:
+		return datatype.DiameterURI(p.Text), nil
+	case datatype.IPFilterRuleType:
+		return datatype.IPFilterRule(p.Text), nil
+	case datatype.QoSFilterRuleType:
+		return datatype.QoSFilterRule(p.Text), nil
+	case datatype.TimeType:
+		t, err := time.Parse(time.RFC3339, p.Text)
+		return datatype.Time(t), err
+	case datatype.EnumeratedType:
+		return datatype.Enumerated(p.Integer), nil
+	case datatype.Integer32Type:
+		return datatype.Integer32(p.Integer), nil
+	case datatype.Integer64Type:
+		return datatype.Integer64(p.Integer), nil
+	case datatype.Unsigned32Type:
+		return datatype.Unsigned32(p.Unsigned), nil
+	case datatype.Unsigned64Type:
+		return datatype.Unsigned64(p.Unsigned), nil
+	case datatype.Float32Type:
+		return datatype.Float32(p.Float), nil
+	case datatype.Float64Type:
+		return datatype.Float64(p.Float), nil
+	case datatype.OctetStringType:
+		return datatype.OctetString(p.Data), nil
+	}
+	return datatype.Unknown(p.Data), nil
+}
@@ -0,0 +1,28 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package grpc provides a gRPC bridge that lets services written in any
+// language send and serve Diameter requests through go-diameter.
+//
+// The protobuf representation of Diameter messages and the Diameter
+// gRPC service are defined in protos/diameter.proto. Messages are
+// converted with EncodeMessage and DecodeMessage.
+//
+// The Bridge implements the service. It sends the requests of gRPC
+// clients to the peers of a state machine, and, registered as the
+// handler of Diameter commands, streams the requests it receives to
+// gRPC clients and their answers back to the peers:
+//
+//	mux := sm.New(settings)
+//	b := bridge.NewBridge(settings.OriginHost, settings.OriginRealm, mux)
+//	b.Routes.Add("example.com", sm.AnyApplication, sm.Route{Host: "server.example.com"})
+//	mux.Handle("CCR", b)
+//
+//	srv := grpc.NewServer()
+//	protos.RegisterDiameterServer(srv, b)
+//	srv.Serve(lis)
+//
+// As the name of this package is that of the gRPC package, it is
+// usually imported with another name, bridge above.
+package grpc
@@ -0,0 +1,6 @@
+//go:generate protoc --go_out=plugins=grpc:. ./diameter.proto
+
+// Package protos contains the protoc generated Go files of the Diameter
+// gRPC bridge. Use `go generate github.com/omnicate/go-diameter/v4/diam/bridge/grpc/protos`
+// to re-generate them.
+package protos
@@ -0,0 +1,26 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package http provides an HTTP gateway for sending Diameter requests,
+// for testing and for scripting environments without a Diameter stack.
+//
+// The Gateway is an http.Handler. POSTing a request in the JSON form of
+// diam.Message sends it to the peers of a state machine, and responds
+// with the JSON form of its answer:
+//
+//	mux := sm.New(settings)
+//	gw := gateway.NewGateway(settings.OriginHost, settings.OriginRealm, mux)
+//	gw.Routes.Add("example.com", sm.AnyApplication, sm.Route{Host: "server.example.com"})
+//	http.Handle("/diameter", gw)
+//
+// With the dictionary, AVPs may be given by name only:
+//
+//	curl -d '{"header": {"flags": 192, "code": 272, "application_id": 4},
+//		"avps": [{"name": "Session-Id", "value": "gw;1"},
+//		{"name": "Destination-Realm", "value": "example.com"}, ...]}' \
+//		http://localhost:8080/diameter
+//
+// As the name of this package is that of net/http, it is usually
+// imported with another name, gateway above.
+package http
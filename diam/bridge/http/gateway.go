@@ -0,0 +1,129 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/agent"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+	"github.com/omnicate/go-diameter/v4/diam/sm"
+)
+
+// DefaultAnswerTimeout is the time the Gateway waits for the answer of
+// a request when AnswerTimeout is not set.
+const DefaultAnswerTimeout = 10 * time.Second
+
+// MaxBodySize is the maximum size of the body of HTTP requests.
+const MaxBodySize = 1 << 20
+
+// Gateway is an http.Handler that sends the Diameter requests POSTed in
+// the JSON form of diam.Message, and responds with their answers.
+//
+// Requests are sent to the peer in their Destination-Host, if connected,
+// or else to the next hop selected by Routes. The Origin-Host and
+// Origin-Realm of the gateway are added to requests without them.
+//
+// Errors are responded with HTTP status codes: 400 for invalid
+// requests, 404 when there is no route to their Destination-Realm, 503
+// when they cannot be sent, and 504 when their answer does not arrive in
+// time. Diameter answers are responded with 200, whatever their
+// Result-Code.
+type Gateway struct {
+	OriginHost  datatype.DiameterIdentity
+	OriginRealm datatype.DiameterIdentity
+
+	// AnswerTimeout is the time a request waits for its answer.
+	// Defaults to DefaultAnswerTimeout.
+	AnswerTimeout time.Duration
+
+	// Routes selects the peer requests are sent to when their
+	// Destination-Host is not connected.
+	Routes *sm.RoutingTable
+
+	// Dict is the dictionary used to decode the JSON requests. If nil,
+	// the default dictionary is used.
+	Dict *dict.Parser
+
+	peers agent.Peers
+}
+
+// NewGateway creates and initializes a Gateway identified by host and
+// realm, which sends requests to peers.
+func NewGateway(host, realm datatype.DiameterIdentity, peers agent.Peers) *Gateway {
+	return &Gateway{
+		OriginHost:  host,
+		OriginRealm: realm,
+		Routes:      sm.NewRoutingTable(),
+		peers:       peers,
+	}
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	m := diam.NewMessage(0, 0, 0, 0, 0, g.Dict)
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxBodySize)).Decode(m); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if m.Header.CommandFlags&diam.RequestFlag == 0 {
+		http.Error(w, "message is not a request", http.StatusBadRequest)
+		return
+	}
+	if _, err := m.FindAVP(avp.OriginHost, 0); err != nil {
+		m.NewAVP(avp.OriginHost, avp.Mbit, 0, g.OriginHost)
+	}
+	if _, err := m.FindAVP(avp.OriginRealm, 0); err != nil {
+		m.NewAVP(avp.OriginRealm, avp.Mbit, 0, g.OriginRealm)
+	}
+	c, code := agent.Route(m, g.peers, g.Routes)
+	switch code {
+	case 0:
+	case diam.MissingAVP:
+		http.Error(w, "request has no Destination-Realm", http.StatusBadRequest)
+		return
+	case diam.RealmNotServed:
+		http.Error(w, sm.ErrNoRoute.Error(), http.StatusNotFound)
+		return
+	default:
+		http.Error(w, "no connection to the destination", http.StatusServiceUnavailable)
+		return
+	}
+	timeout := g.AnswerTimeout
+	if timeout == 0 {
+		timeout = DefaultAnswerTimeout
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+	a, err := diam.SendRequest(ctx, c, m)
+	switch err {
+	case nil:
+	case context.DeadlineExceeded:
+		http.Error(w, err.Error(), http.StatusGatewayTimeout)
+		return
+	default:
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	b, err := json.Marshal(a)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+}
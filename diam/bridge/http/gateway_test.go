@@ -0,0 +1,106 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+	"github.com/omnicate/go-diameter/v4/diam/sm"
+)
+
+var (
+	serverSettings = &sm.Settings{
+		OriginHost:  "srv",
+		OriginRealm: "srv.test",
+		VendorID:    13,
+		ProductName: "go-diameter",
+	}
+
+	gatewaySettings = &sm.Settings{
+		OriginHost:  "gw",
+		OriginRealm: "gw.test",
+		VendorID:    13,
+		ProductName: "go-diameter",
+	}
+)
+
+const testACR = `{
+	"header": {"flags": 192, "code": 271, "application_id": 3},
+	"avps": [
+		{"name": "Session-Id", "value": "gw;1"},
+		{"name": "Destination-Realm", "value": "%s"},
+		{"name": "Accounting-Record-Type", "value": "EVENT_RECORD"},
+		{"name": "Accounting-Record-Number", "value": 0}
+	]
+}`
+
+func post(g *Gateway, method, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest(method, "/", strings.NewReader(body)))
+	return w
+}
+
+func TestGateway(t *testing.T) {
+	reqc := make(chan *diam.Message, 1)
+	srvSM := sm.New(serverSettings)
+	srvSM.HandleFunc("ACR", func(c diam.Conn, m *diam.Message) {
+		reqc <- m
+		a := m.Answer(diam.Success)
+		a.NewAVP(avp.OriginHost, avp.Mbit, 0, serverSettings.OriginHost)
+		a.NewAVP(avp.OriginRealm, avp.Mbit, 0, serverSettings.OriginRealm)
+		a.WriteTo(c)
+	})
+	srv := diamtest.NewServer(srvSM, dict.Default)
+	defer srv.Close()
+
+	gwSM := sm.New(gatewaySettings)
+	acctApp := []*diam.AVP{diam.NewAVP(avp.AcctApplicationID, avp.Mbit, 0, datatype.Unsigned32(3))}
+	c, err := (&sm.Client{Handler: gwSM, AcctApplicationID: acctApp}).Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	g := NewGateway(gatewaySettings.OriginHost, gatewaySettings.OriginRealm, gwSM)
+	g.Routes.Add(serverSettings.OriginRealm, sm.AnyApplication, sm.Route{Host: serverSettings.OriginHost})
+
+	w := post(g, http.MethodPost, strings.Replace(testACR, "%s", "srv.test", 1))
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("Unexpected response: %d %s", w.Code, w.Body)
+	}
+	var a diam.Message
+	if err = json.Unmarshal(w.Body.Bytes(), &a); err != nil {
+		t.Fatal(err)
+	}
+	if rc, err := a.FindAVP(avp.ResultCode, 0); err != nil || rc.Data != datatype.Unsigned32(diam.Success) {
+		t.Fatalf("Unexpected answer: %s", &a)
+	}
+	m := <-reqc
+	if oh, err := m.FindAVP(avp.OriginHost, 0); err != nil || oh.Data != gatewaySettings.OriginHost {
+		t.Fatalf("Unexpected request: %s", m)
+	}
+
+	for _, test := range []struct {
+		method, body string
+		code         int
+	}{
+		{http.MethodGet, "", http.StatusMethodNotAllowed},
+		{http.MethodPost, "{", http.StatusBadRequest},
+		{http.MethodPost, `{"header": {"code": 271}, "avps": []}`, http.StatusBadRequest},
+		{http.MethodPost, strings.Replace(testACR, "%s", "unknown.test", 1), http.StatusNotFound},
+	} {
+		if w = post(g, test.method, test.body); w.Code != test.code {
+			t.Fatalf("Unexpected status of %s %q. Want %d, have %d", test.method, test.body, test.code, w.Code)
+		}
+	}
+}
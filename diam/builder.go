@@ -0,0 +1,290 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+import (
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+// Group is a builder of grouped AVPs, which checks the AVPs added against
+// the dictionary as they are added, for example to add a Subscription-Id
+// to the Credit-Control-Request m:
+//
+//	err := m.NewGroup().
+//		Add(avp.SubscriptionIDType, avp.Mbit, 0, datatype.Enumerated(0)).
+//		Add(avp.SubscriptionIDData, avp.Mbit, 0, datatype.UTF8String("5551234")).
+//		AddTo(m, avp.SubscriptionID, avp.Mbit, 0)
+//
+// The AVP codes can be either numbers or names, as in Message.NewAVP, and
+// the AVPs of vendors are looked up with their Vendor-Id. The first error,
+// such as an unknown AVP or data of the wrong type, is kept and returned
+// when the group is built, and the AVPs added after it are ignored.
+type Group struct {
+	dict  *dict.Parser
+	appID uint32
+	avp   []*AVP
+	err   error
+}
+
+// NewGroup returns a Group that checks AVPs against the base protocol
+// of dict.Default.
+func NewGroup() *Group {
+	return NewGroupWithDict(dict.Default, 0)
+}
+
+// NewGroupWithDict returns a Group that checks AVPs against the
+// application appID of the dictionary d.
+func NewGroupWithDict(d *dict.Parser, appID uint32) *Group {
+	return &Group{dict: d, appID: appID}
+}
+
+// NewGroup returns a Group that checks AVPs against the dictionary and
+// the application of the message.
+func (m *Message) NewGroup() *Group {
+	return NewGroupWithDict(m.Dictionary(), m.Header.ApplicationID)
+}
+
+// Add adds the AVP with code, flags, vendor and data to the group.
+func (g *Group) Add(code interface{}, flags uint8, vendor uint32, data datatype.Type) *Group {
+	if g.err != nil {
+		return g
+	}
+	da, err := g.dict.FindAVPWithVendor(g.appID, code, vendor)
+	if err != nil {
+		g.err = err
+		return g
+	}
+	if err = g.checkData(da, data); err != nil {
+		g.err = err
+		return g
+	}
+	g.avp = append(g.avp, NewAVP(da.Code, flags, vendor, data))
+	return g
+}
+
+// AddGroup adds the grouped AVP with code, flags and vendor built from
+// the AVPs of sub to the group. Errors of sub are returned by g.
+func (g *Group) AddGroup(code interface{}, flags uint8, vendor uint32, sub *Group) *Group {
+	if g.err != nil {
+		return g
+	}
+	a, err := sub.AVP(code, flags, vendor)
+	if err != nil {
+		g.err = err
+		return g
+	}
+	g.avp = append(g.avp, a)
+	return g
+}
+
+// Grouped returns the AVPs of the group as a GroupedAVP, or the first
+// error found while building it.
+func (g *Group) Grouped() (*GroupedAVP, error) {
+	if g.err != nil {
+		return nil, g.err
+	}
+	return &GroupedAVP{AVP: g.avp}, nil
+}
+
+// AVP returns the grouped AVP with code, flags and vendor made of the
+// AVPs of the group. The AVP must be of the Grouped type, and the AVPs
+// of the group must be allowed by its rules in the dictionary.
+func (g *Group) AVP(code interface{}, flags uint8, vendor uint32) (*AVP, error) {
+	ga, err := g.Grouped()
+	if err != nil {
+		return nil, err
+	}
+	da, err := g.dict.FindAVPWithVendor(g.appID, code, vendor)
+	if err != nil {
+		return nil, err
+	}
+	if err = g.checkData(da, ga); err != nil {
+		return nil, err
+	}
+	return NewAVP(da.Code, flags, vendor, ga), nil
+}
+
+// AddTo adds the grouped AVP built as in AVP to the message m.
+func (g *Group) AddTo(m *Message, code interface{}, flags uint8, vendor uint32) error {
+	a, err := g.AVP(code, flags, vendor)
+	if err != nil {
+		return err
+	}
+	m.AddAVP(a)
+	return nil
+}
+
+// checkData returns an error if data is not of the type of the
+// dictionary AVP da, or if da is grouped and data has AVPs that are not
+// allowed by its rules.
+func (g *Group) checkData(da *dict.AVP, data datatype.Type) error {
+	if da.Data.Type == datatype.UnknownType {
+		return nil
+	}
+	ga, grouped := data.(*GroupedAVP)
+	if grouped != (da.Data.Type == datatype.GroupedType) ||
+		(!grouped && data.Type() != da.Data.Type) {
+		return fmt.Errorf("AVP %s is %s, not %T", da.Name, da.Data.TypeName, data)
+	}
+	if !grouped || len(da.Data.Rule) == 0 {
+		return nil
+	}
+	allowed := make(map[string]bool, len(da.Data.Rule))
+	for _, r := range da.Data.Rule {
+		allowed[r.AVP] = true
+	}
+	if allowed[dict.AnyAVP] {
+		return nil
+	}
+	for _, a := range ga.AVP {
+		sub, err := g.dict.FindAVPWithVendor(g.appID, a.Code, a.VendorID)
+		if err != nil {
+			return err
+		}
+		if !allowed[sub.Name] {
+			return fmt.Errorf("AVP %s is not allowed in %s", sub.Name, da.Name)
+		}
+	}
+	return nil
+}
+
+// RequestBuilder is a builder of requests, which checks the AVPs added
+// against the dictionary as Group does, and the request built against the
+// rules of its command, for example:
+//
+//	m, err := diam.NewRequestBuilder(diam.CreditControl, 4, dict.Default).
+//		Origin("client.example.com", "example.com").
+//		DestinationRealm("example.com").
+//		Add(avp.ServiceContextID, avp.Mbit, 0, datatype.UTF8String("32251@3gpp.org")).
+//		Add(avp.CCRequestType, avp.Mbit, 0, datatype.Enumerated(1)).
+//		Add(avp.CCRequestNumber, avp.Mbit, 0, datatype.Unsigned32(0)).
+//		Build()
+//
+// Build adds the Session-Id first, generated from the Origin-Host unless
+// set, and the Auth-Application-Id of the application, when the command
+// requires them, followed by the Origin-Host, Origin-Realm,
+// Destination-Realm and Destination-Host that are set, and the AVPs
+// added.
+type RequestBuilder struct {
+	cmd       uint32
+	g         *Group
+	sessionID datatype.UTF8String
+	origin    [2]datatype.DiameterIdentity // host, realm
+	dest      [2]datatype.DiameterIdentity // host, realm
+}
+
+// NewRequestBuilder returns a RequestBuilder of the command cmd of the
+// application appID of the dictionary d.
+func NewRequestBuilder(cmd, appID uint32, d *dict.Parser) *RequestBuilder {
+	return &RequestBuilder{cmd: cmd, g: NewGroupWithDict(d, appID)}
+}
+
+// SessionID sets the Session-Id of the request.
+func (b *RequestBuilder) SessionID(id datatype.UTF8String) *RequestBuilder {
+	b.sessionID = id
+	return b
+}
+
+// Origin sets the Origin-Host and Origin-Realm of the request.
+func (b *RequestBuilder) Origin(host, realm datatype.DiameterIdentity) *RequestBuilder {
+	b.origin = [2]datatype.DiameterIdentity{host, realm}
+	return b
+}
+
+// DestinationRealm sets the Destination-Realm of the request.
+func (b *RequestBuilder) DestinationRealm(realm datatype.DiameterIdentity) *RequestBuilder {
+	b.dest[1] = realm
+	return b
+}
+
+// DestinationHost sets the Destination-Host of the request.
+func (b *RequestBuilder) DestinationHost(host datatype.DiameterIdentity) *RequestBuilder {
+	b.dest[0] = host
+	return b
+}
+
+// Add adds the AVP with code, flags, vendor and data to the request, see
+// Group.Add.
+func (b *RequestBuilder) Add(code interface{}, flags uint8, vendor uint32, data datatype.Type) *RequestBuilder {
+	b.g.Add(code, flags, vendor, data)
+	return b
+}
+
+// AddGroup adds the grouped AVP with code, flags and vendor built from
+// the AVPs of sub to the request, see Group.AddGroup.
+func (b *RequestBuilder) AddGroup(code interface{}, flags uint8, vendor uint32, sub *Group) *RequestBuilder {
+	b.g.AddGroup(code, flags, vendor, sub)
+	return b
+}
+
+// Build returns the request, or the first error found while building it,
+// such as an unknown AVP, or a ValidationError if the request does not
+// satisfy the rules of its command, for example when a required AVP is
+// missing. See Message.ValidateWith.
+func (b *RequestBuilder) Build() (*Message, error) {
+	if b.g.err != nil {
+		return nil, b.g.err
+	}
+	d, appID := b.g.dict, b.g.appID
+	cmd, err := d.FindCommand(appID, b.cmd)
+	if err != nil {
+		return nil, err
+	}
+	added := make(map[uint32]bool, len(b.g.avp))
+	for _, a := range b.g.avp {
+		added[a.Code] = true
+	}
+	m := NewRequest(b.cmd, appID, d)
+	sid := b.sessionID
+	if sid == "" && !added[avp.SessionID] && ruleOf(cmd.Request.Rule, "Session-Id") != nil {
+		sid = nextSessionID(b.origin[0])
+	}
+	if sid != "" {
+		m.NewAVP(avp.SessionID, avp.Mbit, 0, sid)
+	}
+	if !added[avp.AuthApplicationID] && appID != 0 {
+		if r := ruleOf(cmd.Request.Rule, "Auth-Application-Id"); r != nil && r.Required {
+			m.NewAVP(avp.AuthApplicationID, avp.Mbit, 0, datatype.Unsigned32(appID))
+		}
+	}
+	for i, code := range []uint32{avp.OriginHost, avp.OriginRealm} {
+		if b.origin[i] != "" {
+			m.NewAVP(code, avp.Mbit, 0, b.origin[i])
+		}
+	}
+	if b.dest[1] != "" {
+		m.NewAVP(avp.DestinationRealm, avp.Mbit, 0, b.dest[1])
+	}
+	if b.dest[0] != "" {
+		m.NewAVP(avp.DestinationHost, avp.Mbit, 0, b.dest[0])
+	}
+	for _, a := range b.g.avp {
+		m.AddAVP(a)
+	}
+	if err = m.ValidateWith(d); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Session-Ids generated by RequestBuilder, see session.IDGenerator.
+var (
+	sessionIDHigh = strconv.FormatUint(uint64(time.Now().Unix()), 10)
+	sessionIDLow  uint32 // atomic
+)
+
+// nextSessionID returns a new Session-Id of host in the format of RFC
+// 6733 section 8.8.
+func nextSessionID(host datatype.DiameterIdentity) datatype.UTF8String {
+	low := atomic.AddUint32(&sessionIDLow, 1)
+	return datatype.UTF8String(string(host) + ";" + sessionIDHigh + ";" + strconv.FormatUint(uint64(low), 10))
+}
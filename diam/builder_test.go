@@ -0,0 +1,139 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+func TestGroup(t *testing.T) {
+	const gx = 16777238
+	m := NewRequest(CreditControl, gx, dict.Default)
+	err := m.NewGroup().
+		AddGroup(avp.ChargingRuleDefinition, avp.Mbit, 10415, m.NewGroup().
+			Add(avp.ChargingRuleName, avp.Mbit, 10415, datatype.OctetString("rule")).
+			Add("Flow-Description", avp.Mbit, 10415, datatype.IPFilterRule("permit out ip from any to any"))).
+		AddTo(m, avp.ChargingRuleInstall, avp.Mbit, 10415)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := m.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m, err = ReadMessage(bytes.NewReader(b), dict.Default); err != nil {
+		t.Fatal(err)
+	}
+	avps, err := m.FindAVPsWithPath([]interface{}{avp.ChargingRuleInstall, avp.ChargingRuleDefinition, avp.ChargingRuleName}, 10415)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(avps) != 1 || avps[0].Data != datatype.OctetString("rule") || avps[0].Flags&avp.Vbit == 0 {
+		t.Fatalf("Unexpected Charging-Rule-Name: %v", avps)
+	}
+}
+
+func TestGroup_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		group *Group
+		code  uint32
+	}{
+		{
+			name:  "unknown AVP",
+			group: NewGroup().Add(99999, avp.Mbit, 0, datatype.OctetString("x")),
+			code:  avp.ProxyInfo,
+		},
+		{
+			name:  "wrong type",
+			group: NewGroup().Add(avp.ProxyHost, avp.Mbit, 0, datatype.Unsigned32(1)),
+			code:  avp.ProxyInfo,
+		},
+		{
+			name:  "not allowed",
+			group: NewGroup().Add(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("host")),
+			code:  avp.ProxyInfo,
+		},
+		{
+			name:  "not grouped",
+			group: NewGroup().Add(avp.ProxyHost, avp.Mbit, 0, datatype.DiameterIdentity("host")),
+			code:  avp.OriginHost,
+		},
+		{
+			name: "nested",
+			group: NewGroup().AddGroup(avp.ProxyInfo, avp.Mbit, 0, NewGroup().
+				Add(avp.ProxyHost, avp.Mbit, 0, datatype.Unsigned32(1))),
+			code: avp.ProxyInfo,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if a, err := tc.group.AVP(tc.code, avp.Mbit, 0); err == nil {
+				t.Fatalf("Unexpected AVP: %v", a)
+			}
+		})
+	}
+}
+
+func TestRequestBuilder(t *testing.T) {
+	m, err := NewRequestBuilder(CreditControl, 4, dict.Default).
+		Origin("client", "localhost").
+		DestinationRealm("localhost").
+		Add(avp.ServiceContextID, avp.Mbit, 0, datatype.UTF8String("32251@3gpp.org")).
+		Add("CC-Request-Type", avp.Mbit, 0, datatype.Enumerated(1)).
+		Add(avp.CCRequestNumber, avp.Mbit, 0, datatype.Unsigned32(0)).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Header.CommandFlags&RequestFlag == 0 || m.Header.ApplicationID != 4 {
+		t.Fatalf("Unexpected header: %s", m.Header)
+	}
+	want := []uint32{avp.SessionID, avp.AuthApplicationID, avp.OriginHost, avp.OriginRealm,
+		avp.DestinationRealm, avp.ServiceContextID, avp.CCRequestType, avp.CCRequestNumber}
+	if len(m.AVP) != len(want) {
+		t.Fatalf("Unexpected AVPs:\n%s", m)
+	}
+	for i, code := range want {
+		if m.AVP[i].Code != code {
+			t.Fatalf("Unexpected AVP %d. Want %d, have %d", i, code, m.AVP[i].Code)
+		}
+	}
+	sid := string(m.AVP[0].Data.(datatype.UTF8String))
+	if !strings.HasPrefix(sid, "client;") || strings.Count(sid, ";") != 2 {
+		t.Fatalf("Unexpected Session-Id %q", sid)
+	}
+	if m.AVP[1].Data != datatype.Unsigned32(4) {
+		t.Fatalf("Unexpected Auth-Application-Id %s", m.AVP[1].Data)
+	}
+}
+
+func TestRequestBuilder_Errors(t *testing.T) {
+	// CC-Request-Number is missing.
+	_, err := NewRequestBuilder(CreditControl, 4, dict.Default).
+		SessionID("client;1;1").
+		Origin("client", "localhost").
+		DestinationRealm("localhost").
+		Add(avp.ServiceContextID, avp.Mbit, 0, datatype.UTF8String("32251@3gpp.org")).
+		Add(avp.CCRequestType, avp.Mbit, 0, datatype.Enumerated(1)).
+		Build()
+	if re, ok := err.(*RuleError); !ok || re.Rule.AVP != "CC-Request-Number" {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	_, err = NewRequestBuilder(CreditControl, 4, dict.Default).
+		Add(avp.CCRequestNumber, avp.Mbit, 0, datatype.UTF8String("0")).
+		Build()
+	if err == nil {
+		t.Fatal("Unexpected request with CC-Request-Number of the wrong type")
+	}
+	if _, err = NewRequestBuilder(999, 4, dict.Default).Build(); err == nil {
+		t.Fatal("Unexpected request of unknown command")
+	}
+}
@@ -61,7 +61,20 @@ func DialTimeout(addr string, handler Handler, dp *dict.Parser, timeout time.Dur
 func DialExt(
 	network, addr string, handler Handler, dp *dict.Parser, timeout time.Duration, laddr net.Addr) (Conn, error) {
 
-	srv := &Server{Network: network, Addr: addr, Handler: handler, Dict: dp, LocalAddr: laddr}
+	return DialConfig(network, addr, handler, dp, timeout, laddr, nil)
+}
+
+// DialConfig is the same as DialExt, but sets the socket options of cfg
+// on the connection. If cfg is nil, the defaults are used.
+func DialConfig(
+	network, addr string,
+	handler Handler,
+	dp *dict.Parser,
+	timeout time.Duration,
+	laddr net.Addr,
+	cfg *TransportConfig) (Conn, error) {
+
+	srv := &Server{Network: network, Addr: addr, Handler: handler, Dict: dp, LocalAddr: laddr, TransportConfig: cfg}
 	return dial(srv, timeout)
 }
 
@@ -75,13 +88,22 @@ func dial(srv *Server, timeout time.Duration) (Conn, error) {
 	if len(addr) == 0 {
 		addr = ":3868"
 	}
-	var rw net.Conn
-	var err error
-	dialer := getMultistreamDialer(network, timeout, srv.LocalAddr)
-	rw, err = dialer.Dial(network, addr)
+	if t, taddr := transportOf(addr); t != nil {
+		return dialWith(srv, network, taddr, transportDialer{t, timeout})
+	}
+	return dialWith(srv, network, addr, getMultistreamDialer(network, timeout, srv.LocalAddr))
+}
+
+// dialWith connects to network & addr using the given dialer
+func dialWith(srv *Server, network, addr string, dialer Dialer) (Conn, error) {
+	rw, err := dialer.Dial(network, addr)
 	if err != nil {
 		return nil, err
 	}
+	if err = srv.TransportConfig.Apply(rw); err != nil {
+		rw.Close()
+		return nil, err
+	}
 	c, err := srv.newConn(rw)
 	if err != nil {
 		return nil, err
@@ -116,7 +138,22 @@ func DialTLSExt(
 	timeout time.Duration,
 	laddr net.Addr) (Conn, error) {
 
-	srv := &Server{Network: network, Addr: addr, Handler: handler, Dict: dp, LocalAddr: laddr}
+	return DialTLSConfig(network, addr, certFile, keyFile, handler, dp, timeout, laddr, nil)
+}
+
+// DialTLSConfig is the same as DialConfig, but for TLS.
+func DialTLSConfig(
+	network,
+	addr,
+	certFile,
+	keyFile string,
+	handler Handler,
+	dp *dict.Parser,
+	timeout time.Duration,
+	laddr net.Addr,
+	cfg *TransportConfig) (Conn, error) {
+
+	srv := &Server{Network: network, Addr: addr, Handler: handler, Dict: dp, LocalAddr: laddr, TransportConfig: cfg}
 	return dialTLS(srv, certFile, keyFile, timeout)
 }
 
@@ -147,10 +184,17 @@ func dialTLS(srv *Server, certFile, keyFile string, timeout time.Duration) (Conn
 
 	var rw net.Conn
 	dialer := getDialer(network, timeout, srv.LocalAddr)
+	if t, taddr := transportOf(addr); t != nil {
+		dialer, addr = transportDialer{t, timeout}, taddr
+	}
 	rw, err = dialer.Dial(network, addr)
 	if err != nil {
 		return nil, err
 	}
+	if err = srv.TransportConfig.Apply(rw); err != nil {
+		rw.Close()
+		return nil, err
+	}
 	c, err := srv.newConn(tls.Client(rw, config))
 	if err != nil {
 		return nil, err
@@ -14,19 +14,30 @@ const (
 	AuthenticationInformation = 318
 	CancelLocation            = 317
 	CapabilitiesExchange      = 257
+	CapabilitiesUpdate        = 328
 	CreditControl             = 272
+	DeleteSubscriberData      = 320
 	DeviceWatchdog            = 280
+	DiameterEAP               = 268
 	DisconnectPeer            = 282
+	InsertSubscriberData      = 319
+	LocationInfo              = 302
 	MultimediaAuthentication  = 303
 	Notify                    = 323
+	ProfileUpdate             = 307
 	PurgeUE                   = 321
+	PushNotification          = 309
+	PushProfile               = 305
 	ReAuth                    = 258
 	RegistrationTermination   = 304
 	Reset                     = 322
 	ServerAssignment          = 301
 	SessionTermination        = 275
+	SubscribeNotifications    = 308
 	SpendingLimit             = 8388635
 	UpdateLocation            = 316
+	UserAuthorization         = 300
+	UserData                  = 306
 )
 
 // Short Command Names
@@ -45,14 +56,28 @@ const (
 	CER = "CER"
 	CLA = "CLA"
 	CLR = "CLR"
+	CUA = "CUA"
+	CUR = "CUR"
+	DEA = "DEA"
+	DER = "DER"
 	DPA = "DPA"
 	DPR = "DPR"
+	DSA = "DSA"
+	DSR = "DSR"
 	DWA = "DWA"
 	DWR = "DWR"
+	IDA = "IDA"
+	IDR = "IDR"
+	LIA = "LIA"
+	LIR = "LIR"
 	MAA = "MAA"
 	MAR = "MAR"
 	NOA = "NOA"
 	NOR = "NOR"
+	PNA = "PNA"
+	PNR = "PNR"
+	PPA = "PPA"
+	PPR = "PPR"
 	PUA = "PUA"
 	PUR = "PUR"
 	RAA = "RAA"
@@ -65,8 +90,14 @@ const (
 	SAR = "SAR"
 	SLA = "SLA"
 	SLR = "SLR"
+	SNA = "SNA"
+	SNR = "SNR"
 	STA = "STA"
 	STR = "STR"
+	UAA = "UAA"
+	UAR = "UAR"
+	UDA = "UDA"
+	UDR = "UDR"
 	ULA = "ULA"
 	ULR = "ULR"
 )
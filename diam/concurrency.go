@@ -0,0 +1,193 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+import (
+	"runtime"
+	"sync"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// ConcurrencyModel selects how a Server runs its Handler on the messages
+// it receives, other than the answers to its SendRequest calls.
+//
+// Whatever the model, the messages of the base protocol, such as CER,
+// DWR and DPR, are handled in the goroutine reading their connection,
+// so that the next messages of the connection are handled after the
+// capabilities exchange completes.
+type ConcurrencyModel int
+
+// Concurrency models.
+const (
+	// SerialPerConn runs the Handler on the messages of each
+	// connection one at a time, in the order they are received, in the
+	// goroutine reading the connection. Slow handlers delay the next
+	// messages of their connection. It is the default.
+	SerialPerConn ConcurrencyModel = iota
+
+	// WorkerPool runs the Handler on the messages of all the
+	// connections of the Server in Server.Workers goroutines, in no
+	// particular order.
+	WorkerPool
+
+	// SessionOrdered runs the Handler on the messages with the same
+	// Session-Id one at a time, in the order they are received, and on
+	// the messages of other sessions concurrently, in Server.Workers
+	// goroutines. The messages without Session-Id are ordered per
+	// connection.
+	SessionOrdered
+)
+
+// workerQueueLength is the number of messages queued per worker of the
+// SessionOrdered model, and per worker of the WorkerPool model. The
+// connections stop reading when the queue of their messages is full.
+const workerQueueLength = 16
+
+// work is a message received on a connection, queued for a worker.
+type work struct {
+	c *conn
+	m *Message
+}
+
+// workerPool runs the Handler of a Server in a fixed number of
+// goroutines, which read a single queue for WorkerPool, or one queue
+// each for SessionOrdered.
+type workerPool struct {
+	queues   []chan work
+	quit     chan struct{}
+	stopOnce sync.Once
+}
+
+// serveMessage runs the Handler on the message m received on c according
+// to the concurrency model of the server.
+func (srv *Server) serveMessage(c *conn, m *Message) {
+	if m.Header.ApplicationID == 0 {
+		srv.handle(c, m)
+		return
+	}
+	switch srv.Concurrency {
+	case WorkerPool:
+		srv.workerPool().queue(0, c, m)
+	case SessionOrdered:
+		p := srv.workerPool()
+		p.queue(sessionShard(c, m, len(p.queues)), c, m)
+	default:
+		srv.handle(c, m)
+	}
+}
+
+// handle runs the Handler on the message m received on c, with the
+// context of c and the deadline of HandlerTimeout.
+func (srv *Server) handle(c *conn, m *Message) {
+	ctx := c.writer.Context()
+	if srv.HandlerTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, srv.HandlerTimeout)
+		defer cancel()
+	}
+	m.ctx = ctx
+	serverHandler{srv}.ServeDIAM(c.writer, m)
+}
+
+// workerPool returns the workers of the server, starting them on first
+// use.
+func (srv *Server) workerPool() *workerPool {
+	srv.workersOnce.Do(func() {
+		n := srv.Workers
+		if n <= 0 {
+			n = runtime.GOMAXPROCS(0)
+		}
+		p := &workerPool{quit: make(chan struct{})}
+		if srv.Concurrency == WorkerPool {
+			q := make(chan work, n*workerQueueLength)
+			p.queues = []chan work{q}
+			for i := 0; i < n; i++ {
+				go srv.work(q, p.quit)
+			}
+		} else {
+			p.queues = make([]chan work, n)
+			for i := range p.queues {
+				p.queues[i] = make(chan work, workerQueueLength)
+				go srv.work(p.queues[i], p.quit)
+			}
+		}
+		srv.workers = p
+	})
+	return srv.workers
+}
+
+// stopWorkers stops the workers of the server, if any.
+func (srv *Server) stopWorkers() {
+	// Do makes srv.workers visible, or prevents starting the workers.
+	srv.workersOnce.Do(func() {})
+	if p := srv.workers; p != nil {
+		p.stopOnce.Do(func() { close(p.quit) })
+	}
+}
+
+// queue queues the message m received on c for the workers of queue i.
+// Messages are dropped after the workers stop.
+func (p *workerPool) queue(i int, c *conn, m *Message) {
+	select {
+	case p.queues[i] <- work{c, m}:
+	case <-p.quit:
+	}
+}
+
+// work runs the Handler on the messages of q until quit is closed.
+func (srv *Server) work(q chan work, quit chan struct{}) {
+	for {
+		select {
+		case w := <-q:
+			srv.serveWork(w)
+		case <-quit:
+			return
+		}
+	}
+}
+
+// serveWork runs the Handler on w, closing its connection if the Handler
+// panics, as conn.serve does.
+func (srv *Server) serveWork(w work) {
+	defer func() {
+		if err := recover(); err != nil {
+			buf := make([]byte, 4096)
+			buf = buf[:runtime.Stack(buf, false)]
+			srv.logger().Log(LevelError, "panic serving message",
+				"conn", w.c.id,
+				"remote", w.c.rwc.RemoteAddr(),
+				"err", err,
+				"stack", string(buf),
+			)
+			w.c.rwc.Close()
+		}
+	}()
+	srv.handle(w.c, w.m)
+}
+
+// sessionShard returns the queue of the n queues of the SessionOrdered
+// model the message m received on c is handled by: the same for the
+// messages with the same Session-Id, or the same for the messages of the
+// connection c without Session-Id.
+func sessionShard(c *conn, m *Message, n int) int {
+	h := uint32(2166136261) // FNV-1a
+	for _, a := range m.AVP {
+		if a.Code != avp.SessionID || a.VendorID != 0 {
+			continue
+		}
+		if s, ok := a.Data.(datatype.UTF8String); ok {
+			for i := 0; i < len(s); i++ {
+				h ^= uint32(s[i])
+				h *= 16777619
+			}
+			return int(h % uint32(n))
+		}
+	}
+	return int(c.id % uint64(n))
+}
@@ -0,0 +1,161 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+)
+
+func newConcurrencyServer(t *testing.T, model diam.ConcurrencyModel, workers int, h diam.HandlerFunc) (*diamtest.Server, diam.Conn) {
+	smux := diam.NewServeMux()
+	smux.Handle("ACR", h)
+	srv := diamtest.NewUnstartedServer(smux, nil)
+	srv.Config.Concurrency = model
+	srv.Config.Workers = workers
+	srv.Start()
+	cli, err := diam.Dial(srv.Addr, nil, nil)
+	if err != nil {
+		srv.Close()
+		t.Fatal(err)
+	}
+	return srv, cli
+}
+
+func sendACR(t *testing.T, c diam.Conn, session string, n uint32) {
+	m := diam.NewRequest(diam.Accounting, 3, nil)
+	m.NewAVP(avp.SessionID, avp.Mbit, 0, datatype.UTF8String(session))
+	m.NewAVP(avp.AccountingRecordNumber, avp.Mbit, 0, datatype.Unsigned32(n))
+	if _, err := m.WriteTo(c); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestServerConcurrency_WorkerPool(t *testing.T) {
+	entered := make(chan struct{}, 2)
+	release := make(chan struct{})
+	srv, cli := newConcurrencyServer(t, diam.WorkerPool, 2, func(c diam.Conn, m *diam.Message) {
+		entered <- struct{}{}
+		<-release
+	})
+	defer srv.Close()
+	defer cli.Close()
+	defer close(release)
+	sendACR(t, cli, "cli;1", 1)
+	sendACR(t, cli, "cli;1", 2)
+	for i := 0; i < 2; i++ {
+		select {
+		case <-entered:
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out: %d of 2 requests handled concurrently", i)
+		}
+	}
+}
+
+func TestServerConcurrency_SessionOrdered(t *testing.T) {
+	const sessions, n = 4, 20
+	var mu sync.Mutex
+	received := make(map[string][]uint32)
+	done := make(chan struct{}, sessions*n)
+	srv, cli := newConcurrencyServer(t, diam.SessionOrdered, 3, func(c diam.Conn, m *diam.Message) {
+		var acr struct {
+			SessionID string `avp:"Session-Id"`
+			Number    uint32 `avp:"Accounting-Record-Number"`
+		}
+		if err := m.Unmarshal(&acr); err != nil {
+			t.Error(err)
+		}
+		if acr.Number%3 == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		mu.Lock()
+		received[acr.SessionID] = append(received[acr.SessionID], acr.Number)
+		mu.Unlock()
+		done <- struct{}{}
+	})
+	defer srv.Close()
+	defer cli.Close()
+	for i := uint32(0); i < n; i++ {
+		for s := 0; s < sessions; s++ {
+			sendACR(t, cli, fmt.Sprintf("cli;%d", s), i)
+		}
+	}
+	for i := 0; i < sessions*n; i++ {
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out: %d of %d requests handled", i, sessions*n)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for session, numbers := range received {
+		for i, number := range numbers {
+			if number != uint32(i) {
+				t.Fatalf("Unexpected order of session %s: %v", session, numbers)
+			}
+		}
+	}
+}
+
+func TestHandlerFuncCtx_Timeout(t *testing.T) {
+	errc := make(chan error, 1)
+	smux := diam.NewServeMux()
+	smux.Handle("ACR", diam.HandlerFuncCtx(func(ctx context.Context, c diam.Conn, m *diam.Message) {
+		if _, ok := ctx.Deadline(); !ok {
+			errc <- errors.New("no deadline")
+			return
+		}
+		<-ctx.Done()
+		errc <- ctx.Err()
+	}))
+	srv := diamtest.NewUnstartedServer(smux, nil)
+	srv.Config.HandlerTimeout = 10 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+	cli, err := diam.Dial(srv.Addr, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cli.Close()
+	sendACR(t, cli, "cli;1", 1)
+	select {
+	case err := <-errc:
+		if err != context.DeadlineExceeded {
+			t.Fatalf("Unexpected error. Want %v, have %v", context.DeadlineExceeded, err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out: handler context not done")
+	}
+}
+
+func TestHandlerFuncCtx_Close(t *testing.T) {
+	errc := make(chan error, 1)
+	srv, cli := newConcurrencyServer(t, diam.WorkerPool, 1, func(c diam.Conn, m *diam.Message) {
+		<-m.Context().Done()
+		errc <- m.Context().Err()
+	})
+	defer srv.Close()
+	sendACR(t, cli, "cli;1", 1)
+	cli.Close()
+	select {
+	case err := <-errc:
+		if err != context.Canceled {
+			t.Fatalf("Unexpected error. Want %v, have %v", context.Canceled, err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out: handler context not canceled")
+	}
+}
@@ -12,8 +12,102 @@ import (
 )
 
 // Address data type.
+//
+// Addresses of the IPv4 and IPv6 families hold the IP address, such as a
+// net.IP, and those of the other families of the IANA Address Family
+// Numbers hold the 2-byte family followed by the address, see NewAddress.
 type Address []byte
 
+// AddressFamily is an IANA Address Family Number, the type of an Address.
+type AddressFamily uint16
+
+// Address families.
+const (
+	AddressFamilyIPv4              AddressFamily = 1
+	AddressFamilyIPv6              AddressFamily = 2
+	AddressFamilyNSAP              AddressFamily = 3
+	AddressFamilyHDLC              AddressFamily = 4
+	AddressFamilyBBN1822           AddressFamily = 5
+	AddressFamily802               AddressFamily = 6
+	AddressFamilyE163              AddressFamily = 7
+	AddressFamilyE164              AddressFamily = 8
+	AddressFamilyF69               AddressFamily = 9
+	AddressFamilyX121              AddressFamily = 10
+	AddressFamilyIPX               AddressFamily = 11
+	AddressFamilyAppletalk         AddressFamily = 12
+	AddressFamilyDecnetIV          AddressFamily = 13
+	AddressFamilyBanyanVines       AddressFamily = 14
+	AddressFamilyE164NSAP          AddressFamily = 15
+	AddressFamilyDNS               AddressFamily = 16
+	AddressFamilyDistinguishedName AddressFamily = 17
+	AddressFamilyASNumber          AddressFamily = 18
+)
+
+// NewAddress returns the Address of the family with the given value,
+// the IP address for IPv4 and IPv6.
+func NewAddress(family AddressFamily, value []byte) Address {
+	switch family {
+	case AddressFamilyIPv4, AddressFamilyIPv6:
+		return Address(append([]byte(nil), value...))
+	}
+	b := make([]byte, 2+len(value))
+	binary.BigEndian.PutUint16(b, uint16(family))
+	copy(b[2:], value)
+	return Address(b)
+}
+
+// E164Address returns the E.164 Address of number, a string of digits
+// such as an MSISDN.
+func E164Address(number string) Address {
+	return NewAddress(AddressFamilyE164, []byte(number))
+}
+
+// IP returns the IP address of addr, or nil if addr is of another family.
+//
+// Addresses of other families of 4 or 16 bytes are told from IPv4 and
+// IPv6 addresses by their family, 3 to 31, in the first two bytes. These
+// are IPv4 addresses of 0.0.0.0/8 and IPv6 addresses of the reserved
+// 0000::/8, which are not used as host addresses.
+func (addr Address) IP() net.IP {
+	switch len(addr) {
+	case net.IPv4len, net.IPv6len:
+		if addr[0] == 0 && addr[1] >= 3 && addr[1] < 32 {
+			return nil
+		}
+		return net.IP(addr)
+	}
+	return nil
+}
+
+// Family returns the family of addr, 0 if addr is invalid.
+func (addr Address) Family() AddressFamily {
+	if ip := addr.IP(); ip != nil {
+		if ip.To4() != nil {
+			return AddressFamilyIPv4
+		}
+		return AddressFamilyIPv6
+	}
+	if len(addr) < 2 {
+		return 0
+	}
+	return AddressFamily(binary.BigEndian.Uint16(addr))
+}
+
+// Value returns the address of addr without its family, the 4 or 16
+// bytes of the IP address for IPv4 and IPv6.
+func (addr Address) Value() []byte {
+	if ip := addr.IP(); ip != nil {
+		if ip4 := ip.To4(); ip4 != nil {
+			return ip4
+		}
+		return ip
+	}
+	if len(addr) < 2 {
+		return nil
+	}
+	return addr[2:]
+}
+
 // DecodeAddress decodes an Address data type from byte array.
 func DecodeAddress(b []byte) (Type, error) {
 	if len(b) < 3 {
@@ -24,12 +118,12 @@ func DecodeAddress(b []byte) (Type, error) {
 	}
 	switch binary.BigEndian.Uint16(b[:2]) {
 	case 0x01:
-		if len(b[2:]) != 4 {
-			return nil, errors.New("Invalid length for IPv4")
+		if err := checkLen(AddressType, b, 6); err != nil {
+			return nil, err
 		}
 	case 0x02:
-		if len(b[2:]) != 16 {
-			return nil, errors.New("Invalid length for IPv6")
+		if err := checkLen(AddressType, b, 18); err != nil {
+			return nil, err
 		}
 	default:
 		return Address(b), nil
@@ -39,43 +133,39 @@ func DecodeAddress(b []byte) (Type, error) {
 
 // Serialize implements the Type interface.
 func (addr Address) Serialize() []byte {
+	ip := addr.IP()
+	if ip == nil {
+		b := make([]byte, len(addr))
+		copy(b, addr)
+		return b
+	}
 	var b []byte
-	if ip4 := net.IP(addr).To4(); ip4 != nil {
+	if ip4 := ip.To4(); ip4 != nil {
 		b = make([]byte, 6)
 		b[1] = 0x01
 		copy(b[2:], ip4)
-	} else if ip6 := net.IP(addr).To16(); ip6 != nil {
+	} else {
 		b = make([]byte, 18)
 		b[1] = 0x02
-		copy(b[2:], addr)
-	} else {
-		b = make([]byte, len(addr))
-		copy(b, addr)
+		copy(b[2:], ip)
 	}
 	return b
 }
 
 // Len implements the Type interface.
 func (addr Address) Len() int {
-	if ip4 := net.IP(addr).To4(); ip4 != nil {
-		return len(ip4) + 2 // two bytes from the address family
-	} else if ip6 := net.IP(addr).To16(); ip6 != nil {
-		return len(ip6) + 2 // two bytes from the address family
-	} else {
-		return len(addr)
+	if ip := addr.IP(); ip != nil {
+		if ip4 := ip.To4(); ip4 != nil {
+			return len(ip4) + 2 // two bytes from the address family
+		}
+		return len(ip) + 2 // two bytes from the address family
 	}
+	return len(addr)
 }
 
 // Padding implements the Type interface.
 func (addr Address) Padding() int {
-	var l int
-	if ip4 := net.IP(addr).To4(); ip4 != nil {
-		l = len(ip4) + 2 // two bytes from the address family
-	} else if ip6 := net.IP(addr).To16(); ip6 != nil {
-		l = len(ip6) + 2 // two bytes from the address family
-	} else {
-		l = len(addr)
-	}
+	l := addr.Len()
 	return pad4(l) - l
 }
 
@@ -86,11 +176,14 @@ func (addr Address) Type() TypeID {
 
 // String implements the Type interface.
 func (addr Address) String() string {
-	if ip4 := net.IP(addr).To4(); ip4 != nil {
-		return fmt.Sprintf("Address{%s},Padding:%d", net.IP(addr), addr.Padding())
+	if ip := addr.IP(); ip != nil {
+		return fmt.Sprintf("Address{%s},Padding:%d", ip, addr.Padding())
 	}
-	if ip6 := net.IP(addr).To16(); ip6 != nil {
-		return fmt.Sprintf("Address{%s},Padding:%d", net.IP(addr), addr.Padding())
+	switch addr.Family() {
+	case 0:
+		return fmt.Sprintf("Address{%#v},Padding:%d", []byte(addr), addr.Padding())
+	case AddressFamilyE164:
+		return fmt.Sprintf("Address{E.164:%s},Padding:%d", addr.Value(), addr.Padding())
 	}
 	return fmt.Sprintf("Address{%#v}, Type{%#v} Padding:%d", addr[2:], addr[:2], addr.Padding())
 }
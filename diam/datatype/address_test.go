@@ -207,6 +207,51 @@ func TestDecodeAddressE164(t *testing.T) {
 	}
 }
 
+func TestAddressFamily(t *testing.T) {
+	for _, test := range []struct {
+		addr   Address
+		family AddressFamily
+		value  []byte
+	}{
+		{Address(net.ParseIP("10.0.0.1")), AddressFamilyIPv4, []byte{10, 0, 0, 1}},
+		{Address(net.ParseIP("2001:db8::1")), AddressFamilyIPv6, net.ParseIP("2001:db8::1")},
+		{E164Address("48602007060"), AddressFamilyE164, []byte("48602007060")},
+		{NewAddress(AddressFamilyNSAP, []byte{0x49, 0x00, 0x01}), AddressFamilyNSAP, []byte{0x49, 0x00, 0x01}},
+	} {
+		if f := test.addr.Family(); f != test.family {
+			t.Errorf("Unexpected family of %s. Want %d, have %d", test.addr, test.family, f)
+		}
+		if v := test.addr.Value(); !bytes.Equal(v, test.value) {
+			t.Errorf("Unexpected value of %s. Want 0x%x, have 0x%x", test.addr, test.value, v)
+		}
+	}
+}
+
+func TestAddressE164Ambiguous(t *testing.T) {
+	// 14 digits, the length of an IPv6 address with the family.
+	address := E164Address("49170123456789")
+	if len(address) != net.IPv6len {
+		t.Fatalf("Unexpected length %d", len(address))
+	}
+	if ip := address.IP(); ip != nil {
+		t.Fatalf("Unexpected IP address %s", ip)
+	}
+	b := address.Serialize()
+	if !bytes.Equal(b, address) {
+		t.Fatalf("Unexpected value. Want 0x%x, have 0x%x", []byte(address), b)
+	}
+	v, err := DecodeAddress(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := string(v.(Address).Value()); s != "49170123456789" {
+		t.Fatalf("Unexpected E.164 address %q", s)
+	}
+	if s := v.String(); s != "Address{E.164:49170123456789},Padding:0" {
+		t.Fatalf("Unexpected string %q", s)
+	}
+}
+
 func BenchmarkAddressIPv4(b *testing.B) {
 	address := Address(net.ParseIP("10.0.0.1"))
 	for n := 0; n < b.N; n++ {
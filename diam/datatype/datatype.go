@@ -4,6 +4,8 @@
 
 package datatype
 
+import "strconv"
+
 // Type is an interface to support Diameter AVP data types.
 type Type interface {
 	Serialize() []byte
@@ -60,3 +62,16 @@ var Available = map[string]TypeID{
 	"Unsigned32":       Unsigned32Type,
 	"Unsigned64":       Unsigned64Type,
 }
+
+// String returns the name of the type, such as Unsigned32.
+func (t TypeID) String() string {
+	for name, id := range Available {
+		if id == t {
+			return name
+		}
+	}
+	if t == UnknownType {
+		return "Unknown"
+	}
+	return "TypeID(" + strconv.Itoa(int(t)) + ")"
+}
@@ -4,18 +4,20 @@
 
 package datatype
 
-import "fmt"
+import (
+	"encoding/binary"
+	"fmt"
+)
 
 // Enumerated data type.
 type Enumerated Integer32
 
 // DecodeEnumerated decodes an Enumerated data type from byte array.
 func DecodeEnumerated(b []byte) (Type, error) {
-	v, err := DecodeInteger32(b)
-	if err != nil {
+	if err := checkLen(EnumeratedType, b, 4); err != nil {
 		return nil, err
 	}
-	return Enumerated(v.(Integer32)), nil
+	return Enumerated(binary.BigEndian.Uint32(b)), nil
 }
 
 // Serialize implements the Type interface.
@@ -0,0 +1,31 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package datatype
+
+import "fmt"
+
+// LengthError is returned by the decoders of the types of fixed length,
+// such as Unsigned32 or Float64, for data of another length.
+//
+// The padding of AVPs is not part of their data: AVPs whose padding is
+// missing are reported by the AVP decoder, as diam.ErrBadPadding.
+type LengthError struct {
+	Type TypeID
+	Len  int // Length of the data
+	Want int // Length of the type
+}
+
+// Error implements the error interface.
+func (e *LengthError) Error() string {
+	return fmt.Sprintf("Invalid length for %s: %d, want %d", e.Type, e.Len, e.Want)
+}
+
+// checkLen returns a *LengthError if b is not n bytes long.
+func checkLen(t TypeID, b []byte, n int) error {
+	if len(b) != n {
+		return &LengthError{Type: t, Len: len(b), Want: n}
+	}
+	return nil
+}
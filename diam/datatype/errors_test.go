@@ -0,0 +1,63 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package datatype
+
+import "testing"
+
+func TestDecodeLengthError(t *testing.T) {
+	for _, test := range []struct {
+		typ  TypeID
+		want int
+	}{
+		{EnumeratedType, 4},
+		{Float32Type, 4},
+		{Float64Type, 8},
+		{Integer32Type, 4},
+		{Integer64Type, 8},
+		{IPv4Type, 4},
+		{IPv6Type, 16},
+		{TimeType, 4},
+		{Unsigned32Type, 4},
+		{Unsigned64Type, 8},
+	} {
+		for _, n := range []int{0, test.want - 1, test.want + 1} {
+			v, err := Decode(test.typ, make([]byte, n))
+			le, ok := err.(*LengthError)
+			if !ok || le.Type != test.typ || le.Len != n || le.Want != test.want {
+				t.Errorf("Unexpected %s of %d bytes: %v, %v", test.typ, n, v, err)
+			}
+		}
+		if _, err := Decode(test.typ, make([]byte, test.want)); err != nil {
+			t.Errorf("Unexpected error decoding %s: %v", test.typ, err)
+		}
+	}
+}
+
+func TestDecodeAddressLengthError(t *testing.T) {
+	_, err := DecodeAddress([]byte{0x00, 0x01, 0x0a, 0x00, 0x00})
+	if le, ok := err.(*LengthError); !ok || le.Want != 6 {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if s := err.Error(); s != "Invalid length for Address: 5, want 6" {
+		t.Fatalf("Unexpected error message %q", s)
+	}
+}
+
+func TestTypeIDString(t *testing.T) {
+	if s := Unsigned64Type.String(); s != "Unsigned64" {
+		t.Fatalf("Unexpected name %q", s)
+	}
+	if s := TypeID(100).String(); s != "TypeID(100)" {
+		t.Fatalf("Unexpected name %q", s)
+	}
+}
+
+func TestDecoderTypes(t *testing.T) {
+	for name, typ := range Available {
+		if _, ok := Decoder[typ]; !ok {
+			t.Errorf("Missing decoder of %s", name)
+		}
+	}
+}
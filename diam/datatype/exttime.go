@@ -0,0 +1,80 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package datatype
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// ExtendedTime data type. ExtendedTime is a Time with sub-second
+// precision carried by OctetString AVPs as the 64-bit NTP timestamp of
+// RFC 5905: the seconds of Time followed by the fraction of the second
+// in units of 2^-32 seconds.
+//
+// ExtendedTime is converted to and from time.Time, and its AVPs are
+// decoded as OctetString, see ExtendedTimeOf.
+type ExtendedTime time.Time
+
+// DecodeExtendedTime decodes an ExtendedTime data type from byte array.
+func DecodeExtendedTime(b []byte) (Type, error) {
+	if len(b) != 8 {
+		return nil, fmt.Errorf("Invalid length for ExtendedTime: %d", len(b))
+	}
+	s := unixSeconds(binary.BigEndian.Uint32(b))
+	frac := uint64(binary.BigEndian.Uint32(b[4:]))
+	// Round to the nearest nanosecond, so encoded times decode as is.
+	ns := (frac*1e9 + 1<<31) >> 32
+	return ExtendedTime(time.Unix(s, int64(ns))), nil
+}
+
+// ExtendedTimeOf returns the ExtendedTime of the value of an AVP, which
+// is either an ExtendedTime, a Time, or the OctetString of an
+// ExtendedTime.
+func ExtendedTimeOf(v Type) (ExtendedTime, error) {
+	switch v := v.(type) {
+	case ExtendedTime:
+		return v, nil
+	case Time:
+		return ExtendedTime(v), nil
+	case OctetString:
+		t, err := DecodeExtendedTime([]byte(v))
+		if err != nil {
+			return ExtendedTime{}, err
+		}
+		return t.(ExtendedTime), nil
+	}
+	return ExtendedTime{}, fmt.Errorf("Cannot convert %s to ExtendedTime", v)
+}
+
+// Serialize implements the Type interface.
+func (t ExtendedTime) Serialize() []byte {
+	b := make([]byte, 8)
+	tt := time.Time(t)
+	binary.BigEndian.PutUint32(b, ntpSeconds(tt))
+	binary.BigEndian.PutUint32(b[4:], uint32(uint64(tt.Nanosecond())<<32/1e9))
+	return b
+}
+
+// Len implements the Type interface.
+func (t ExtendedTime) Len() int {
+	return 8
+}
+
+// Padding implements the Type interface.
+func (t ExtendedTime) Padding() int {
+	return 0
+}
+
+// Type implements the Type interface. ExtendedTime is an OctetString.
+func (t ExtendedTime) Type() TypeID {
+	return OctetStringType
+}
+
+// String implements the Type interface.
+func (t ExtendedTime) String() string {
+	return fmt.Sprintf("ExtendedTime{%s}", time.Time(t))
+}
@@ -0,0 +1,72 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package datatype
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestExtendedTime(t *testing.T) {
+	n := ExtendedTime(time.Unix(1377093974, 500000000))
+	b := []byte{0xd5, 0xbf, 0x47, 0xd6, 0x80, 0x00, 0x00, 0x00}
+	if v := n.Serialize(); !bytes.Equal(v, b) {
+		t.Fatalf("Unexpected value. Want 0x%x, have 0x%x", b, v)
+	}
+	if n.Len() != 8 {
+		t.Fatalf("Unexpected len. Want 8, have %d", n.Len())
+	}
+	if n.Padding() != 0 {
+		t.Fatalf("Unexpected padding. Want 0, have %d", n.Padding())
+	}
+	if n.Type() != OctetStringType {
+		t.Fatalf("Unexpected type. Want %d, have %d",
+			OctetStringType, n.Type())
+	}
+	if len(n.String()) == 0 {
+		t.Fatalf("Unexpected empty string")
+	}
+}
+
+func TestDecodeExtendedTime(t *testing.T) {
+	for _, want := range []time.Time{
+		time.Unix(1377093974, 0),
+		time.Unix(1377093974, 1),
+		time.Unix(1377093974, 123456789),
+		time.Unix(1377093974, 999999999),
+		time.Unix(2147483648, 250000000), // NTP era 1
+	} {
+		v, err := DecodeExtendedTime(ExtendedTime(want).Serialize())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have := time.Time(v.(ExtendedTime)); !have.Equal(want) {
+			t.Errorf("Unexpected value. Want %s, have %s", want, have)
+		}
+	}
+	if _, err := DecodeExtendedTime([]byte{0xd5, 0xbf, 0x47, 0xd6}); err == nil {
+		t.Fatal("Unexpected decoding of 4 bytes")
+	}
+}
+
+func TestExtendedTimeOf(t *testing.T) {
+	want := time.Unix(1377093974, 500000000)
+	for _, v := range []Type{
+		ExtendedTime(want),
+		OctetString([]byte{0xd5, 0xbf, 0x47, 0xd6, 0x80, 0x00, 0x00, 0x00}),
+	} {
+		et, err := ExtendedTimeOf(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have := time.Time(et); !have.Equal(want) {
+			t.Errorf("Unexpected value of %s. Want %s, have %s", v, want, have)
+		}
+	}
+	if _, err := ExtendedTimeOf(Unsigned32(1)); err == nil {
+		t.Fatal("Unexpected conversion of Unsigned32")
+	}
+}
@@ -0,0 +1,306 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package datatype
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// FilterRule is the parsed form of the IPFilterRule of RFC 6733 section
+// 4.3.1 and of the QoSFilterRule of RFC 7155 section 4.4.4, which share
+// the grammar:
+//
+//	action dir proto from src to dst [options]
+//
+// for example "permit out 17 from 10.0.0.1 to 10.0.0.0/8 5060-5070".
+type FilterRule struct {
+	// Action is permit or deny for IPFilterRule, and tag or meter for
+	// QoSFilterRule.
+	Action string
+
+	// Dir is in, from the terminal, or out, to the terminal.
+	Dir string
+
+	// Proto is the IP protocol number, 0 for any protocol ("ip").
+	Proto uint8
+
+	Src FilterAddr
+	Dst FilterAddr
+
+	// Options are the options following the destination, such as
+	// "established" or "DSCP 46", as is.
+	Options []string
+}
+
+// FilterAddr is the source or destination of a FilterRule.
+type FilterAddr struct {
+	// Not negates the address, the "!" prefix.
+	Not bool
+
+	// Any is set for "any", and Assigned for "assigned", the address
+	// assigned to the terminal. Net is the address otherwise.
+	Any      bool
+	Assigned bool
+	Net      *net.IPNet
+
+	// Ports are the ports of TCP and UDP rules, any when empty.
+	Ports []PortRange
+}
+
+// PortRange is a range of ports, of a single port when Min equals Max.
+type PortRange struct {
+	Min, Max uint16
+}
+
+// Filter rule actions.
+var (
+	ipFilterActions  = []string{"permit", "deny"}
+	qosFilterActions = []string{"tag", "meter"}
+)
+
+// ParseIPFilterRule parses an IPFilterRule, see FilterRule.
+func ParseIPFilterRule(s string) (*FilterRule, error) {
+	return parseFilterRule(s, ipFilterActions)
+}
+
+// ParseQoSFilterRule parses a QoSFilterRule, see FilterRule.
+func ParseQoSFilterRule(s string) (*FilterRule, error) {
+	return parseFilterRule(s, qosFilterActions)
+}
+
+// Parse parses the rule, see FilterRule.
+func (s IPFilterRule) Parse() (*FilterRule, error) {
+	return ParseIPFilterRule(string(s))
+}
+
+// Parse parses the rule, see FilterRule.
+func (s QoSFilterRule) Parse() (*FilterRule, error) {
+	return ParseQoSFilterRule(string(s))
+}
+
+// IPFilterRule returns the IPFilterRule of r, or an error if r is not a
+// valid IPFilterRule.
+func (r *FilterRule) IPFilterRule() (IPFilterRule, error) {
+	if err := r.check(ipFilterActions); err != nil {
+		return "", err
+	}
+	return IPFilterRule(r.String()), nil
+}
+
+// QoSFilterRule returns the QoSFilterRule of r, or an error if r is not
+// a valid QoSFilterRule.
+func (r *FilterRule) QoSFilterRule() (QoSFilterRule, error) {
+	if err := r.check(qosFilterActions); err != nil {
+		return "", err
+	}
+	return QoSFilterRule(r.String()), nil
+}
+
+// String returns the rule in the grammar of FilterRule.
+func (r *FilterRule) String() string {
+	var b strings.Builder
+	b.WriteString(r.Action)
+	b.WriteByte(' ')
+	b.WriteString(r.Dir)
+	if r.Proto == 0 {
+		b.WriteString(" ip")
+	} else {
+		b.WriteByte(' ')
+		b.WriteString(strconv.Itoa(int(r.Proto)))
+	}
+	b.WriteString(" from ")
+	b.WriteString(r.Src.String())
+	b.WriteString(" to ")
+	b.WriteString(r.Dst.String())
+	for _, o := range r.Options {
+		b.WriteByte(' ')
+		b.WriteString(o)
+	}
+	return b.String()
+}
+
+// String returns the address in the grammar of FilterRule.
+func (a FilterAddr) String() string {
+	var b strings.Builder
+	if a.Not {
+		b.WriteByte('!')
+	}
+	switch {
+	case a.Any || a.Net == nil && !a.Assigned:
+		b.WriteString("any")
+	case a.Assigned:
+		b.WriteString("assigned")
+	default:
+		ones, bits := a.Net.Mask.Size()
+		b.WriteString(a.Net.IP.String())
+		if ones != bits {
+			fmt.Fprintf(&b, "/%d", ones)
+		}
+	}
+	for i, p := range a.Ports {
+		if i == 0 {
+			b.WriteByte(' ')
+		} else {
+			b.WriteByte(',')
+		}
+		b.WriteString(p.String())
+	}
+	return b.String()
+}
+
+// String returns the range as port or min-max.
+func (p PortRange) String() string {
+	if p.Min == p.Max {
+		return strconv.Itoa(int(p.Min))
+	}
+	return fmt.Sprintf("%d-%d", p.Min, p.Max)
+}
+
+func (r *FilterRule) check(actions []string) error {
+	if !isOneOf(r.Action, actions) {
+		return fmt.Errorf("Invalid filter rule action %q", r.Action)
+	}
+	if r.Dir != "in" && r.Dir != "out" {
+		return fmt.Errorf("Invalid filter rule direction %q", r.Dir)
+	}
+	return nil
+}
+
+func parseFilterRule(s string, actions []string) (*FilterRule, error) {
+	f := strings.Fields(s)
+	if len(f) < 6 {
+		return nil, fmt.Errorf("Invalid filter rule %q: too short", s)
+	}
+	r := &FilterRule{Action: f[0], Dir: f[1]}
+	if err := r.check(actions); err != nil {
+		return nil, err
+	}
+	proto, err := parseProto(f[2])
+	if err != nil {
+		return nil, err
+	}
+	r.Proto = proto
+	if f[3] != "from" {
+		return nil, fmt.Errorf("Invalid filter rule %q: want from, have %q", s, f[3])
+	}
+	f = f[4:]
+	if r.Src, f, err = parseFilterAddr(f); err != nil {
+		return nil, err
+	}
+	if len(f) == 0 || f[0] != "to" {
+		return nil, fmt.Errorf("Invalid filter rule %q: missing to", s)
+	}
+	if r.Dst, f, err = parseFilterAddr(f[1:]); err != nil {
+		return nil, err
+	}
+	if len(f) > 0 {
+		r.Options = f
+	}
+	return r, nil
+}
+
+// parseProto parses the protocol of a rule, which is a number, or ip
+// for any protocol. The names tcp, udp and icmp are also accepted.
+func parseProto(s string) (uint8, error) {
+	switch s {
+	case "ip":
+		return 0, nil
+	case "icmp":
+		return 1, nil
+	case "tcp":
+		return 6, nil
+	case "udp":
+		return 17, nil
+	}
+	n, err := strconv.ParseUint(s, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid filter rule protocol %q", s)
+	}
+	return uint8(n), nil
+}
+
+// parseFilterAddr parses the address and ports at the start of f, and
+// returns the fields that follow them.
+func parseFilterAddr(f []string) (FilterAddr, []string, error) {
+	var a FilterAddr
+	if len(f) == 0 {
+		return a, nil, fmt.Errorf("Invalid filter rule: missing address")
+	}
+	s := f[0]
+	if strings.HasPrefix(s, "!") {
+		a.Not = true
+		s = s[1:]
+	}
+	switch s {
+	case "any":
+		a.Any = true
+	case "assigned":
+		a.Assigned = true
+	default:
+		n, err := parseFilterNet(s)
+		if err != nil {
+			return a, nil, err
+		}
+		a.Net = n
+	}
+	f = f[1:]
+	if len(f) > 0 && len(f[0]) > 0 && f[0][0] >= '0' && f[0][0] <= '9' {
+		for _, p := range strings.Split(f[0], ",") {
+			pr, err := parsePortRange(p)
+			if err != nil {
+				return a, nil, err
+			}
+			a.Ports = append(a.Ports, pr)
+		}
+		f = f[1:]
+	}
+	return a, f, nil
+}
+
+func parseFilterNet(s string) (*net.IPNet, error) {
+	if strings.Contains(s, "/") {
+		_, n, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid filter rule address %q", s)
+		}
+		return n, nil
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, fmt.Errorf("Invalid filter rule address %q", s)
+	}
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)}, nil
+}
+
+func parsePortRange(s string) (PortRange, error) {
+	min, max := s, s
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		min, max = s[:i], s[i+1:]
+	}
+	lo, err := strconv.ParseUint(min, 10, 16)
+	if err != nil {
+		return PortRange{}, fmt.Errorf("Invalid filter rule port %q", s)
+	}
+	hi, err := strconv.ParseUint(max, 10, 16)
+	if err != nil || hi < lo {
+		return PortRange{}, fmt.Errorf("Invalid filter rule port %q", s)
+	}
+	return PortRange{uint16(lo), uint16(hi)}, nil
+}
+
+func isOneOf(s string, list []string) bool {
+	for _, v := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
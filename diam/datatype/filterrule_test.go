@@ -0,0 +1,108 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package datatype
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestParseIPFilterRule(t *testing.T) {
+	r, err := IPFilterRule("permit out 17 from !10.0.0.0/8 5060,6000-6010 to assigned established").Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, n, _ := net.ParseCIDR("10.0.0.0/8")
+	want := &FilterRule{
+		Action: "permit",
+		Dir:    "out",
+		Proto:  17,
+		Src: FilterAddr{
+			Not:   true,
+			Net:   n,
+			Ports: []PortRange{{5060, 5060}, {6000, 6010}},
+		},
+		Dst:     FilterAddr{Assigned: true},
+		Options: []string{"established"},
+	}
+	if !reflect.DeepEqual(r, want) {
+		t.Fatalf("Unexpected rule.\nWant: %#v\nHave: %#v", want, r)
+	}
+}
+
+func TestFilterRuleString(t *testing.T) {
+	for _, s := range []string{
+		"permit out ip from any to assigned",
+		"permit in 6 from 192.168.1.1 80 to 10.0.0.0/24 1024-65535",
+		"deny out 17 from !2001:db8::/32 to 2001:db8::1 53",
+		"permit out 1 from any to any icmptypes 0,8",
+	} {
+		r, err := ParseIPFilterRule(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v := r.String(); v != s {
+			t.Errorf("Unexpected rule. Want %q, have %q", s, v)
+		}
+	}
+}
+
+func TestParseQoSFilterRule(t *testing.T) {
+	s := "tag in udp from any to 10.1.1.1 5004 DSCP ef"
+	r, err := QoSFilterRule(s).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Action != "tag" || r.Proto != 17 || len(r.Options) != 2 {
+		t.Fatalf("Unexpected rule: %#v", r)
+	}
+	q, err := r.QoSFilterRule()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "tag in 17 from any to 10.1.1.1 5004 DSCP ef"; string(q) != want {
+		t.Fatalf("Unexpected rule. Want %q, have %q", want, q)
+	}
+	if _, err = r.IPFilterRule(); err == nil {
+		t.Fatal("Unexpected IPFilterRule with tag action")
+	}
+}
+
+func TestParseFilterRuleErrors(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"permit out ip from any",
+		"tag out ip from any to any",
+		"permit up ip from any to any",
+		"permit out 256 from any to any",
+		"permit out ip to any from any",
+		"permit out ip from 10.0.0.300 to any",
+		"permit out ip from any 80-20 to any",
+		"permit out ip from any 80 any",
+	} {
+		if r, err := ParseIPFilterRule(s); err == nil {
+			t.Errorf("Unexpected rule for %q: %s", s, r)
+		}
+	}
+}
+
+func TestNewFilterRule(t *testing.T) {
+	_, n, _ := net.ParseCIDR("10.0.0.0/16")
+	r := &FilterRule{
+		Action: "permit",
+		Dir:    "in",
+		Proto:  6,
+		Src:    FilterAddr{Any: true},
+		Dst:    FilterAddr{Net: n, Ports: []PortRange{{443, 443}}},
+	}
+	v, err := r.IPFilterRule()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "permit in 6 from any to 10.0.0.0/16 443"; string(v) != want {
+		t.Fatalf("Unexpected rule. Want %q, have %q", want, v)
+	}
+}
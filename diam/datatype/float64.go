@@ -15,8 +15,8 @@ type Float64 float64
 
 // DecodeFloat64 decodes a Float64 data type from byte array.
 func DecodeFloat64(b []byte) (Type, error) {
-	if len(b) != 8 {
-		return Float64(0), nil
+	if err := checkLen(Float64Type, b, 8); err != nil {
+		return nil, err
 	}
 	return Float64(math.Float64frombits(binary.BigEndian.Uint64(b))), nil
 }
@@ -14,8 +14,8 @@ type Integer32 int32
 
 // DecodeInteger32 decodes an Integer32 data type from byte array.
 func DecodeInteger32(b []byte) (Type, error) {
-	if len(b) != 4 {
-		return Integer32(0), nil
+	if err := checkLen(Integer32Type, b, 4); err != nil {
+		return nil, err
 	}
 	return Integer32(binary.BigEndian.Uint32(b)), nil
 }
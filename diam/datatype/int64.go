@@ -14,8 +14,8 @@ type Integer64 int64
 
 // DecodeInteger64 decodes an Integer64 data type from byte array.
 func DecodeInteger64(b []byte) (Type, error) {
-	if len(b) != 8 {
-		return Integer64(0), nil
+	if err := checkLen(Integer64Type, b, 8); err != nil {
+		return nil, err
 	}
 	return Integer64(binary.BigEndian.Uint64(b)), nil
 }
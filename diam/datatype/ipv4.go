@@ -14,8 +14,8 @@ type IPv4 net.IP
 
 // DecodeIPv4 decodes an IPv4 data type from byte array.
 func DecodeIPv4(b []byte) (Type, error) {
-	if len(b) != 4 {
-		return IPv4{0, 0, 0, 0}, nil
+	if err := checkLen(IPv4Type, b, net.IPv4len); err != nil {
+		return nil, err
 	}
 	return IPv4(b), nil
 }
@@ -14,8 +14,8 @@ type IPv6 net.IP
 
 // DecodeIPv6 decodes an IPv4 data type from byte array.
 func DecodeIPv6(b []byte) (Type, error) {
-	if len(b) != net.IPv6len {
-		return IPv6(make(net.IP, net.IPv6len)), nil
+	if err := checkLen(IPv6Type, b, net.IPv6len); err != nil {
+		return nil, err
 	}
 	return IPv6(b), nil
 }
@@ -0,0 +1,111 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package datatype
+
+import (
+	"fmt"
+	"net"
+)
+
+// IPv6Prefix data type. IPv6Prefix is the IPv6 prefix carried by
+// OctetString AVPs such as Framed-IPv6-Prefix and Delegated-IPv6-Prefix,
+// in the format of RFC 3162 section 2.3: a reserved byte, the prefix
+// length in bits, and the bytes of the prefix, of which only those
+// covered by the prefix length are sent.
+//
+// IPv6Prefix is converted to and from net.IPNet, and its AVPs are decoded
+// as OctetString, see IPv6PrefixOf.
+type IPv6Prefix net.IPNet
+
+// NewIPv6Prefix returns the IPv6Prefix of s in CIDR notation, such as
+// "2001:db8::/32".
+func NewIPv6Prefix(s string) (IPv6Prefix, error) {
+	ip, n, err := net.ParseCIDR(s)
+	if err != nil {
+		return IPv6Prefix{}, err
+	}
+	if ip.To4() != nil {
+		return IPv6Prefix{}, fmt.Errorf("Invalid IPv6 prefix %q", s)
+	}
+	return IPv6Prefix(*n), nil
+}
+
+// DecodeIPv6Prefix decodes an IPv6Prefix data type from byte array.
+func DecodeIPv6Prefix(b []byte) (Type, error) {
+	if len(b) < 2 || len(b) > 2+net.IPv6len {
+		return nil, fmt.Errorf("Invalid length for IPv6Prefix: %d", len(b))
+	}
+	bits := int(b[1])
+	if bits > 8*net.IPv6len || len(b)-2 < (bits+7)/8 {
+		return nil, fmt.Errorf("Invalid IPv6Prefix length: %d", bits)
+	}
+	mask := net.CIDRMask(bits, 8*net.IPv6len)
+	ip := make(net.IP, net.IPv6len)
+	copy(ip, b[2:])
+	return IPv6Prefix{IP: ip.Mask(mask), Mask: mask}, nil
+}
+
+// IPv6PrefixOf returns the IPv6Prefix of the value of an AVP, which is
+// either an IPv6Prefix or the OctetString of an IPv6Prefix.
+func IPv6PrefixOf(v Type) (IPv6Prefix, error) {
+	switch v := v.(type) {
+	case IPv6Prefix:
+		return v, nil
+	case OctetString:
+		p, err := DecodeIPv6Prefix([]byte(v))
+		if err != nil {
+			return IPv6Prefix{}, err
+		}
+		return p.(IPv6Prefix), nil
+	}
+	return IPv6Prefix{}, fmt.Errorf("Cannot convert %s to IPv6Prefix", v)
+}
+
+// Serialize implements the Type interface.
+func (p IPv6Prefix) Serialize() []byte {
+	bits := p.bits()
+	b := make([]byte, p.Len())
+	b[1] = byte(bits)
+	if ip := p.IP.To16(); ip != nil {
+		copy(b[2:], ip.Mask(net.CIDRMask(bits, 8*net.IPv6len)))
+	}
+	return b
+}
+
+// Len implements the Type interface.
+func (p IPv6Prefix) Len() int {
+	return 2 + (p.bits()+7)/8
+}
+
+// Padding implements the Type interface.
+func (p IPv6Prefix) Padding() int {
+	l := p.Len()
+	return pad4(l) - l
+}
+
+// Type implements the Type interface. IPv6Prefix is an OctetString.
+func (p IPv6Prefix) Type() TypeID {
+	return OctetStringType
+}
+
+// String implements the Type interface.
+func (p IPv6Prefix) String() string {
+	return fmt.Sprintf("IPv6Prefix{%s},Padding:%d", p.CIDR(), p.Padding())
+}
+
+// CIDR returns the prefix in CIDR notation, such as "2001:db8::/32".
+func (p IPv6Prefix) CIDR() string {
+	n := net.IPNet(p)
+	return n.String()
+}
+
+// bits returns the prefix length, 0 to 128.
+func (p IPv6Prefix) bits() int {
+	ones, bits := p.Mask.Size()
+	if bits != 8*net.IPv6len {
+		return 0
+	}
+	return ones
+}
@@ -0,0 +1,65 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package datatype
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestIPv6Prefix(t *testing.T) {
+	p, err := NewIPv6Prefix("2001:db8:1234::/48")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := []byte{0x00, 0x30, 0x20, 0x01, 0x0d, 0xb8, 0x12, 0x34}
+	if v := p.Serialize(); !bytes.Equal(v, b) {
+		t.Fatalf("Unexpected value. Want 0x%x, have 0x%x", b, v)
+	}
+	if p.Len() != 8 {
+		t.Fatalf("Unexpected len. Want 8, have %d", p.Len())
+	}
+	if p.Padding() != 0 {
+		t.Fatalf("Unexpected padding. Want 0, have %d", p.Padding())
+	}
+	if p.Type() != OctetStringType {
+		t.Fatalf("Unexpected type. Want %d, have %d", OctetStringType, p.Type())
+	}
+}
+
+func TestDecodeIPv6Prefix(t *testing.T) {
+	// The prefix sent in full, with bits beyond the prefix length.
+	b := []byte{0x00, 0x40,
+		0x20, 0x01, 0x0d, 0xb8, 0x00, 0x00, 0x00, 0x01,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01,
+	}
+	p, err := IPv6PrefixOf(OctetString(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := p.CIDR(); s != "2001:db8:0:1::/64" {
+		t.Fatalf("Unexpected prefix %s", s)
+	}
+	if p.Len() != 10 || p.Padding() != 2 {
+		t.Fatalf("Unexpected len %d and padding %d", p.Len(), p.Padding())
+	}
+}
+
+func TestDecodeIPv6PrefixInvalid(t *testing.T) {
+	for _, b := range [][]byte{
+		{},
+		{0x00},
+		{0x00, 0x81},
+		{0x00, 0x40, 0x20, 0x01},
+		make([]byte, 19),
+	} {
+		if p, err := DecodeIPv6Prefix(b); err == nil {
+			t.Errorf("Unexpected prefix %s from 0x%x", p, b)
+		}
+	}
+	if _, err := NewIPv6Prefix("10.0.0.0/8"); err == nil {
+		t.Error("Unexpected IPv6 prefix from IPv4 network")
+	}
+}
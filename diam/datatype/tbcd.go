@@ -0,0 +1,110 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package datatype
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TBCDString data type. TBCDString is the string of digits, such as an
+// IMSI or an MSISDN, carried by 3GPP OctetString AVPs such as MSISDN in
+// the TBCD encoding of 3GPP TS 29.002: two digits per byte, the first in
+// the low nibble, and a filler of 0xF in the high nibble of the last byte
+// of strings of odd length.
+//
+// Besides the digits, TBCD encodes the characters *, #, a, b and c.
+// TBCDString AVPs are decoded as OctetString, see TBCDStringOf.
+type TBCDString string
+
+// tbcdDigits are the characters of the nibbles 0 to 14, 15 is the filler.
+const tbcdDigits = "0123456789*#abc"
+
+// NewTBCDString returns the TBCDString of s, or an error if s has
+// characters that TBCD does not encode.
+func NewTBCDString(s string) (TBCDString, error) {
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(tbcdDigits, s[i]) < 0 {
+			return "", fmt.Errorf("Invalid TBCD digit %q in %q", s[i], s)
+		}
+	}
+	return TBCDString(s), nil
+}
+
+// DecodeTBCDString decodes a TBCDString data type from byte array. Only
+// the high nibble of the last byte may be a filler.
+func DecodeTBCDString(b []byte) (Type, error) {
+	s := make([]byte, 0, 2*len(b))
+	for i, c := range b {
+		lo, hi := c&0x0f, c>>4
+		if lo == 0x0f {
+			return nil, fmt.Errorf("Invalid TBCD filler in byte %d of 0x%x", i, b)
+		}
+		s = append(s, tbcdDigits[lo])
+		if hi == 0x0f {
+			if i != len(b)-1 {
+				return nil, fmt.Errorf("Invalid TBCD filler in byte %d of 0x%x", i, b)
+			}
+			break
+		}
+		s = append(s, tbcdDigits[hi])
+	}
+	return TBCDString(s), nil
+}
+
+// TBCDStringOf returns the TBCDString of the value of an AVP, which is
+// either a TBCDString or the OctetString of a TBCDString.
+func TBCDStringOf(v Type) (TBCDString, error) {
+	switch v := v.(type) {
+	case TBCDString:
+		return v, nil
+	case OctetString:
+		s, err := DecodeTBCDString([]byte(v))
+		if err != nil {
+			return "", err
+		}
+		return s.(TBCDString), nil
+	}
+	return "", fmt.Errorf("Cannot convert %s to TBCDString", v)
+}
+
+// Serialize implements the Type interface. Characters that TBCD does not
+// encode are encoded as fillers, see NewTBCDString.
+func (s TBCDString) Serialize() []byte {
+	b := make([]byte, s.Len())
+	for i := 0; i < len(s); i++ {
+		n := byte(0x0f)
+		if d := strings.IndexByte(tbcdDigits, s[i]); d >= 0 {
+			n = byte(d)
+		}
+		if i%2 == 0 {
+			b[i/2] = 0xf0 | n
+		} else {
+			b[i/2] = b[i/2]&0x0f | n<<4
+		}
+	}
+	return b
+}
+
+// Len implements the Type interface.
+func (s TBCDString) Len() int {
+	return (len(s) + 1) / 2
+}
+
+// Padding implements the Type interface.
+func (s TBCDString) Padding() int {
+	l := s.Len()
+	return pad4(l) - l
+}
+
+// Type implements the Type interface. TBCDString is an OctetString.
+func (s TBCDString) Type() TypeID {
+	return OctetStringType
+}
+
+// String implements the Type interface.
+func (s TBCDString) String() string {
+	return fmt.Sprintf("TBCDString{%s},Padding:%d", string(s), s.Padding())
+}
@@ -0,0 +1,66 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package datatype
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTBCDString(t *testing.T) {
+	for _, test := range []struct {
+		s string
+		b []byte
+	}{
+		{"", []byte{}},
+		{"1234", []byte{0x21, 0x43}},
+		{"33638060010", []byte{0x33, 0x36, 0x08, 0x06, 0x10, 0xf0}},
+		{"001010123456789", []byte{0x00, 0x01, 0x01, 0x21, 0x43, 0x65, 0x87, 0xf9}},
+		{"*#a", []byte{0xba, 0xfc}},
+	} {
+		s, err := NewTBCDString(test.s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v := s.Serialize(); !bytes.Equal(v, test.b) {
+			t.Errorf("Unexpected value of %q. Want 0x%x, have 0x%x", test.s, test.b, v)
+		}
+		if s.Len() != len(test.b) {
+			t.Errorf("Unexpected len of %q. Want %d, have %d", test.s, len(test.b), s.Len())
+		}
+		v, err := TBCDStringOf(OctetString(test.b))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(v) != test.s {
+			t.Errorf("Unexpected string. Want %q, have %q", test.s, v)
+		}
+	}
+}
+
+func TestTBCDStringPadding(t *testing.T) {
+	s := TBCDString("33638060010")
+	if s.Padding() != 2 {
+		t.Fatalf("Unexpected padding. Want 2, have %d", s.Padding())
+	}
+	if s.Type() != OctetStringType {
+		t.Fatalf("Unexpected type. Want %d, have %d", OctetStringType, s.Type())
+	}
+}
+
+func TestDecodeTBCDStringInvalid(t *testing.T) {
+	for _, b := range [][]byte{
+		{0x2f},
+		{0xf1, 0x43},
+		{0x21, 0xff},
+	} {
+		if s, err := DecodeTBCDString(b); err == nil {
+			t.Errorf("Unexpected string %s from 0x%x", s, b)
+		}
+	}
+	if _, err := NewTBCDString("+4912"); err == nil {
+		t.Error("Unexpected TBCD string with +")
+	}
+}
@@ -10,23 +10,41 @@ import (
 	"time"
 )
 
-// Time data type.
+// Time data type. Time is encoded as the 32-bit seconds of the NTP
+// timestamps of RFC 5905, see RFC 6733 section 4.3.1, which cover the
+// times from 1968 to 2104.
 type Time time.Time
 
 const rfc868offset = 2208988800 // Diff. between 1970 and 1900 in seconds.
 
 // DecodeTime decodes a Time data type from byte array.
 func DecodeTime(b []byte) (Type, error) {
-	if len(b) != 4 {
-		return &Time{}, nil
+	if err := checkLen(TimeType, b, 4); err != nil {
+		return nil, err
 	}
-	return Time(time.Unix(int64(binary.BigEndian.Uint32(b))-rfc868offset, 0)), nil
+	return Time(time.Unix(unixSeconds(binary.BigEndian.Uint32(b)), 0)), nil
+}
+
+// ntpSeconds returns the NTP seconds of t, which wrap in 2036.
+func ntpSeconds(t time.Time) uint32 {
+	return uint32(t.Unix() + rfc868offset)
+}
+
+// unixSeconds returns the Unix time of the NTP seconds s. As in RFC 4330
+// section 3, the seconds with the most significant bit set are in the
+// era starting in 1900, and the others are in the era starting on
+// February 7, 2036 at 06:28:16 UTC.
+func unixSeconds(s uint32) int64 {
+	if s&0x80000000 == 0 {
+		return int64(s) + 1<<32 - rfc868offset
+	}
+	return int64(s) - rfc868offset
 }
 
 // Serialize implements the Type interface.
 func (t Time) Serialize() []byte {
 	b := make([]byte, 4)
-	binary.BigEndian.PutUint32(b, uint32(time.Time(t).Unix())+rfc868offset)
+	binary.BigEndian.PutUint32(b, ntpSeconds(time.Time(t)))
 	return b
 }
 
@@ -42,6 +42,31 @@ func TestDecodeTime(t *testing.T) {
 	}
 }
 
+func TestTimeEra(t *testing.T) {
+	for _, test := range []struct {
+		unix int64
+		b    []byte
+	}{
+		{-61505152, []byte{0x80, 0x00, 0x00, 0x00}},  // 1968-01-20
+		{2085978495, []byte{0xff, 0xff, 0xff, 0xff}}, // Era 0 end, 2036-02-07
+		{2085978496, []byte{0x00, 0x00, 0x00, 0x00}}, // Era 1 start
+		{2147483648, []byte{0x03, 0xaa, 0x7e, 0x80}}, // 2038-01-19
+		{4233462143, []byte{0x7f, 0xff, 0xff, 0xff}}, // 2104-02-26
+	} {
+		n := Time(time.Unix(test.unix, 0))
+		if v := n.Serialize(); !bytes.Equal(v, test.b) {
+			t.Errorf("Unexpected value for %d. Want 0x%x, have 0x%x", test.unix, test.b, v)
+		}
+		v, err := DecodeTime(test.b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u := time.Time(v.(Time)).Unix(); u != test.unix {
+			t.Errorf("Unexpected value for 0x%x. Want %d, have %d", test.b, test.unix, u)
+		}
+	}
+}
+
 func BenchmarkTime(b *testing.B) {
 	v := Time(time.Unix(1377093974, 0))
 	for n := 0; n < b.N; n++ {
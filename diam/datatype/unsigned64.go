@@ -14,8 +14,8 @@ type Unsigned64 uint64
 
 // DecodeUnsigned64 decodes an Unsigned64 data type from byte array.
 func DecodeUnsigned64(b []byte) (Type, error) {
-	if len(b) != 8 {
-		return Unsigned64(0), nil
+	if err := checkLen(Unsigned64Type, b, 8); err != nil {
+		return nil, err
 	}
 	return Unsigned64(binary.BigEndian.Uint64(b)), nil
 }
@@ -0,0 +1,151 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diamtest
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+// MessageDiff compares the messages a and b, and returns their
+// differences in human readable form, one per line, or an empty string
+// if they are equal. It is meant for golden-file tests, for example:
+//
+//	if diff := diamtest.MessageDiff(want, have, avp.SessionID, avp.OriginStateID); diff != "" {
+//		t.Fatalf("Unexpected answer:\n%s", diff)
+//	}
+//
+// The command code, application and flags of the headers are compared,
+// but not their Hop-by-Hop and End-to-End identifiers. The AVPs are
+// compared by their flags and values, descending into grouped AVPs, in
+// the order they appear in the messages among the AVPs with the same code
+// and vendor. The order of AVPs with different codes is not compared.
+//
+// The AVPs in ignore, either codes or names resolved through the
+// dictionary of a, are not compared wherever they appear, such as volatile
+// AVPs like Session-Id or Origin-State-Id.
+func MessageDiff(a, b *diam.Message, ignore ...interface{}) string {
+	d := &differ{
+		dict:   a.Dictionary(),
+		appID:  a.Header.ApplicationID,
+		ignore: make(map[avpKey]bool, len(ignore)),
+	}
+	for _, code := range ignore {
+		if da, err := d.dict.FindAVP(d.appID, code); err == nil {
+			d.ignore[avpKey{da.Code, da.VendorID}] = true
+			continue
+		}
+		switch c := code.(type) {
+		case int:
+			d.ignore[avpKey{code: uint32(c)}] = true
+		case uint32:
+			d.ignore[avpKey{code: c}] = true
+		}
+	}
+	ha, hb := a.Header, b.Header
+	if ha.CommandCode != hb.CommandCode {
+		d.addf("Command-Code: %d != %d", ha.CommandCode, hb.CommandCode)
+	}
+	if ha.ApplicationID != hb.ApplicationID {
+		d.addf("Application-Id: %d != %d", ha.ApplicationID, hb.ApplicationID)
+	}
+	if ha.CommandFlags != hb.CommandFlags {
+		d.addf("Command-Flags: %#x != %#x", ha.CommandFlags, hb.CommandFlags)
+	}
+	d.diffAVPs("", a.AVP, b.AVP)
+	return strings.Join(d.diffs, "\n")
+}
+
+// avpKey identifies AVPs by code and vendor.
+type avpKey struct {
+	code, vendorID uint32
+}
+
+type differ struct {
+	dict   *dict.Parser
+	appID  uint32
+	ignore map[avpKey]bool
+	diffs  []string
+}
+
+func (d *differ) addf(format string, args ...interface{}) {
+	d.diffs = append(d.diffs, fmt.Sprintf(format, args...))
+}
+
+// diffAVPs compares the AVPs a and b found under the path prefix.
+func (d *differ) diffAVPs(prefix string, a, b []*diam.AVP) {
+	var keys []avpKey
+	byKey := func(avps []*diam.AVP) map[avpKey][]*diam.AVP {
+		m := make(map[avpKey][]*diam.AVP)
+		for _, x := range avps {
+			k := avpKey{x.Code, x.VendorID}
+			if d.ignore[k] || d.ignore[avpKey{code: x.Code}] {
+				continue
+			}
+			if !containsKey(keys, k) {
+				keys = append(keys, k)
+			}
+			m[k] = append(m[k], x)
+		}
+		return m
+	}
+	ma, mb := byKey(a), byKey(b)
+	for _, k := range keys {
+		xa, xb := ma[k], mb[k]
+		for i := 0; i < len(xa) || i < len(xb); i++ {
+			path := prefix + d.name(k)
+			if len(xa) > 1 || len(xb) > 1 {
+				path = fmt.Sprintf("%s[%d]", path, i)
+			}
+			switch {
+			case i >= len(xb):
+				d.addf("%s: only in a: %s", path, xa[i].Data)
+			case i >= len(xa):
+				d.addf("%s: only in b: %s", path, xb[i].Data)
+			default:
+				d.diffAVP(path, xa[i], xb[i])
+			}
+		}
+	}
+}
+
+func (d *differ) diffAVP(path string, a, b *diam.AVP) {
+	if a.Flags != b.Flags {
+		d.addf("%s: flags %#x != %#x", path, a.Flags, b.Flags)
+	}
+	ga, aGrouped := a.Data.(*diam.GroupedAVP)
+	gb, bGrouped := b.Data.(*diam.GroupedAVP)
+	if aGrouped && bGrouped {
+		d.diffAVPs(path+"/", ga.AVP, gb.AVP)
+		return
+	}
+	if a.Data.Type() != b.Data.Type() || !bytes.Equal(a.Data.Serialize(), b.Data.Serialize()) {
+		d.addf("%s: %s != %s", path, a.Data, b.Data)
+	}
+}
+
+// name returns the name of the AVP k in the dictionary, or its code.
+func (d *differ) name(k avpKey) string {
+	if da, err := d.dict.FindAVPWithVendor(d.appID, k.code, k.vendorID); err == nil {
+		return da.Name
+	}
+	if k.vendorID != 0 {
+		return fmt.Sprintf("%d:%d", k.vendorID, k.code)
+	}
+	return fmt.Sprint(k.code)
+}
+
+func containsKey(keys []avpKey, k avpKey) bool {
+	for _, x := range keys {
+		if x == k {
+			return true
+		}
+	}
+	return false
+}
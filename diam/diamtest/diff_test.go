@@ -0,0 +1,59 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diamtest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+func newCCA(session string, state, total uint32) *diam.Message {
+	m := diam.NewMessage(diam.CreditControl, 0, 4, 0, 0, dict.Default)
+	m.NewAVP(avp.SessionID, avp.Mbit, 0, datatype.UTF8String(session))
+	m.NewAVP(avp.ResultCode, avp.Mbit, 0, datatype.Unsigned32(diam.Success))
+	m.NewAVP(avp.OriginStateID, avp.Mbit, 0, datatype.Unsigned32(state))
+	m.NewAVP(avp.MultipleServicesCreditControl, avp.Mbit, 0, &diam.GroupedAVP{
+		AVP: []*diam.AVP{
+			diam.NewAVP(avp.GrantedServiceUnit, avp.Mbit, 0, &diam.GroupedAVP{
+				AVP: []*diam.AVP{
+					diam.NewAVP(avp.CCTotalOctets, avp.Mbit, 0, datatype.Unsigned64(total)),
+				},
+			}),
+		},
+	})
+	return m
+}
+
+func TestMessageDiff(t *testing.T) {
+	if diff := MessageDiff(newCCA("a", 1, 100), newCCA("b", 2, 100), avp.SessionID, "Origin-State-Id"); diff != "" {
+		t.Fatalf("Unexpected diff of equal messages:\n%s", diff)
+	}
+
+	a, b := newCCA("a", 1, 100), newCCA("a", 1, 200)
+	b.Header.CommandFlags |= diam.ErrorFlag
+	b.NewAVP(avp.FailedAVP, avp.Mbit, 0, &diam.GroupedAVP{
+		AVP: []*diam.AVP{diam.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("x"))},
+	})
+	want := []string{
+		"Command-Flags: 0x0 != 0x20",
+		"Multiple-Services-Credit-Control/Granted-Service-Unit/CC-Total-Octets: Unsigned64{100} != Unsigned64{200}",
+		"Failed-AVP: only in b: ",
+	}
+	diff := MessageDiff(a, b)
+	lines := strings.Split(diff, "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("Unexpected diff:\n%s", diff)
+	}
+	for i, line := range lines {
+		if !strings.HasPrefix(line, want[i]) {
+			t.Fatalf("Unexpected diff line %d. Want %q, have %q", i, want[i], line)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diamtest
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// FakeServer is a diam.Handler that answers the requests that match its
+// expectations with canned answers, to test Diameter clients, for
+// example:
+//
+//	fs := diamtest.NewFakeServer("srv", "test")
+//	fs.Expect(diam.CreditControl).WithAVP(avp.CCRequestType, datatype.Enumerated(1)).
+//		Reply(diam.Success, diam.NewAVP(avp.CCRequestType, avp.Mbit, 0, datatype.Enumerated(1)))
+//	c, _, err := diamtest.NewPipePeers(cli, fs, dict.Default)
+//	...
+//	if err := fs.Verify(); err != nil {
+//		t.Fatal(err)
+//	}
+//
+// Capabilities-Exchange, Device-Watchdog and Disconnect-Peer requests are
+// answered with DIAMETER_SUCCESS, unless an expectation matches them; the
+// CEAs advertise the applications of the CERs. Other requests that match
+// no expectation are answered with DIAMETER_UNABLE_TO_COMPLY, and are
+// reported by Verify.
+type FakeServer struct {
+	OriginHost  datatype.DiameterIdentity
+	OriginRealm datatype.DiameterIdentity
+
+	mu           sync.Mutex // guards the fields below
+	expectations []*Expectation
+	received     []*diam.Message
+	unexpected   []*diam.Message
+}
+
+// NewFakeServer returns a FakeServer that answers with the Origin-Host
+// originHost and the Origin-Realm originRealm.
+func NewFakeServer(originHost, originRealm datatype.DiameterIdentity) *FakeServer {
+	return &FakeServer{OriginHost: originHost, OriginRealm: originRealm}
+}
+
+// Expectation is a request expected by a FakeServer, and its answer.
+type Expectation struct {
+	s        *FakeServer
+	cmd      uint32
+	match    []func(*diam.Message) bool
+	answer   func(*diam.Message) *diam.Message
+	times    int // unlimited when zero
+	received int
+}
+
+// Expect adds the expectation of a request with the command code cmd,
+// which is answered with DIAMETER_SUCCESS unless Reply or ReplyFunc are
+// called. Requests are matched against the expectations in the order
+// they were added.
+func (s *FakeServer) Expect(cmd uint32) *Expectation {
+	e := &Expectation{s: s, cmd: cmd}
+	e.Reply(diam.Success)
+	s.mu.Lock()
+	s.expectations = append(s.expectations, e)
+	s.mu.Unlock()
+	return e
+}
+
+// Match restricts the expectation to the requests for which f returns
+// true.
+func (e *Expectation) Match(f func(m *diam.Message) bool) *Expectation {
+	e.s.mu.Lock()
+	e.match = append(e.match, f)
+	e.s.mu.Unlock()
+	return e
+}
+
+// WithAVP restricts the expectation to the requests with the AVP code of
+// the base protocol or of the application of the request, with the value
+// data. The code can be either the AVP code or its name.
+func (e *Expectation) WithAVP(code interface{}, data datatype.Type) *Expectation {
+	return e.Match(func(m *diam.Message) bool {
+		avps, err := m.FindAVPs(code, 0)
+		if err != nil {
+			return false
+		}
+		for _, a := range avps {
+			if a.Data.Type() == data.Type() && bytes.Equal(a.Data.Serialize(), data.Serialize()) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Times limits the expectation to n requests. Verify fails if fewer
+// requests were received.
+func (e *Expectation) Times(n int) *Expectation {
+	e.s.mu.Lock()
+	e.times = n
+	e.s.mu.Unlock()
+	return e
+}
+
+// Reply answers the requests with resultCode and the AVPs avps, after
+// the Session-Id, Result-Code, Origin-Host and Origin-Realm AVPs. See
+// diam.Message.AnswerFrom.
+func (e *Expectation) Reply(resultCode uint32, avps ...*diam.AVP) *Expectation {
+	return e.ReplyFunc(func(m *diam.Message) *diam.Message {
+		a := m.AnswerFrom(resultCode, e.s.OriginHost, e.s.OriginRealm)
+		for _, x := range avps {
+			a.AddAVP(x)
+		}
+		return a
+	})
+}
+
+// ReplyFunc answers the requests with the answers returned by f, or
+// does not answer when f returns nil.
+func (e *Expectation) ReplyFunc(f func(m *diam.Message) *diam.Message) *Expectation {
+	e.s.mu.Lock()
+	e.answer = f
+	e.s.mu.Unlock()
+	return e
+}
+
+func (e *Expectation) matches(m *diam.Message) bool {
+	if m.Header.CommandCode != e.cmd || (e.times > 0 && e.received >= e.times) {
+		return false
+	}
+	for _, f := range e.match {
+		if !f(m) {
+			return false
+		}
+	}
+	return true
+}
+
+// ServeDIAM implements the diam.Handler interface.
+func (s *FakeServer) ServeDIAM(c diam.Conn, m *diam.Message) {
+	if m.Header.CommandFlags&diam.RequestFlag == 0 {
+		return
+	}
+	var answer func(*diam.Message) *diam.Message
+	s.mu.Lock()
+	s.received = append(s.received, m)
+	for _, e := range s.expectations {
+		if e.matches(m) {
+			e.received++
+			answer = e.answer
+			break
+		}
+	}
+	if answer == nil {
+		switch m.Header.CommandCode {
+		case diam.CapabilitiesExchange:
+			answer = s.cea(c)
+		case diam.DeviceWatchdog, diam.DisconnectPeer:
+			answer = func(m *diam.Message) *diam.Message {
+				return m.AnswerFrom(diam.Success, s.OriginHost, s.OriginRealm)
+			}
+		default:
+			s.unexpected = append(s.unexpected, m)
+			answer = func(m *diam.Message) *diam.Message {
+				return m.AnswerFrom(diam.UnableToComply, s.OriginHost, s.OriginRealm)
+			}
+		}
+	}
+	s.mu.Unlock()
+	if a := answer(m); a != nil {
+		a.WriteTo(c)
+	}
+}
+
+// cea returns the function that answers CERs received on c.
+func (s *FakeServer) cea(c diam.Conn) func(*diam.Message) *diam.Message {
+	return func(m *diam.Message) *diam.Message {
+		a := m.AnswerFrom(diam.Success, s.OriginHost, s.OriginRealm)
+		ip := net.IPv4(127, 0, 0, 1)
+		if addr, ok := c.LocalAddr().(*net.TCPAddr); ok {
+			ip = addr.IP
+		}
+		a.NewAVP(avp.HostIPAddress, avp.Mbit, 0, datatype.Address(ip))
+		a.NewAVP(avp.VendorID, avp.Mbit, 0, datatype.Unsigned32(0))
+		a.NewAVP(avp.ProductName, 0, 0, datatype.UTF8String("diamtest"))
+		for _, x := range m.AVP {
+			switch x.Code {
+			case avp.AuthApplicationID, avp.AcctApplicationID, avp.VendorSpecificApplicationID:
+				a.AddAVP(x)
+			}
+		}
+		return a
+	}
+}
+
+// Received returns the requests received by the server.
+func (s *FakeServer) Received() []*diam.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*diam.Message(nil), s.received...)
+}
+
+// Verify returns an error if the server received requests that matched
+// no expectation, or fewer requests than the Times of an expectation.
+func (s *FakeServer) Verify() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.unexpected) > 0 {
+		m := s.unexpected[0]
+		return fmt.Errorf("unexpected request: command %d, application %d (%d unexpected requests)",
+			m.Header.CommandCode, m.Header.ApplicationID, len(s.unexpected))
+	}
+	for _, e := range s.expectations {
+		if e.received < e.times {
+			return fmt.Errorf("expected %d requests with command %d, received %d",
+				e.times, e.cmd, e.received)
+		}
+	}
+	return nil
+}
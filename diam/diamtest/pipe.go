@@ -0,0 +1,44 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diamtest
+
+import (
+	"net"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+// PipeAddr is the address of the connections created by NewPipePeers.
+const PipeAddr = "pipe"
+
+// The Dialer interface is implemented by clients that perform the
+// capabilities exchange on an open connection, such as sm.Client.
+type Dialer interface {
+	NewConn(rw net.Conn, addr string) (diam.Conn, error)
+}
+
+// NewPipePeers connects the client cli to a peer served by handler, such
+// as a sm.StateMachine or a FakeServer, over an in-memory net.Pipe, and
+// returns the connections of both peers after the capabilities exchange
+// of cli. No sockets are used.
+//
+// The pipe has no IP addresses, so the settings of state machines must
+// set HostIPAddresses. The caller should close the client connection
+// when finished.
+func NewPipePeers(cli Dialer, handler diam.Handler, dp *dict.Parser) (client, server diam.Conn, err error) {
+	cp, sp := net.Pipe()
+	if server, err = diam.NewConn(sp, PipeAddr, handler, dp); err != nil {
+		cp.Close()
+		sp.Close()
+		return nil, nil, err
+	}
+	if client, err = cli.NewConn(cp, PipeAddr); err != nil {
+		cp.Close()
+		server.Close()
+		return nil, nil, err
+	}
+	return client, server, nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diamtest_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+	"github.com/omnicate/go-diameter/v4/diam/sm"
+)
+
+func newClient() *sm.Client {
+	return &sm.Client{
+		Handler: sm.New(&sm.Settings{
+			OriginHost:      "cli",
+			OriginRealm:     "test",
+			VendorID:        13,
+			ProductName:     "go-diameter",
+			HostIPAddresses: []datatype.Address{datatype.Address(net.ParseIP("127.0.0.1"))},
+		}),
+		AcctApplicationID: []*diam.AVP{
+			diam.NewAVP(avp.AcctApplicationID, avp.Mbit, 0, datatype.Unsigned32(3)),
+		},
+	}
+}
+
+func newACR(n uint32) *diam.Message {
+	m := diam.NewRequest(diam.Accounting, 3, dict.Default)
+	m.NewAVP(avp.SessionID, avp.Mbit, 0, datatype.UTF8String("sess"))
+	m.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("cli"))
+	m.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity("test"))
+	m.NewAVP(avp.DestinationRealm, avp.Mbit, 0, datatype.DiameterIdentity("test"))
+	m.NewAVP(avp.AccountingRecordType, avp.Mbit, 0, datatype.Enumerated(1))
+	m.NewAVP(avp.AccountingRecordNumber, avp.Mbit, 0, datatype.Unsigned32(n))
+	return m
+}
+
+func TestNewPipePeers(t *testing.T) {
+	srv := sm.New(&sm.Settings{
+		OriginHost:      "srv",
+		OriginRealm:     "test",
+		VendorID:        13,
+		ProductName:     "go-diameter",
+		HostIPAddresses: []datatype.Address{datatype.Address(net.ParseIP("127.0.0.1"))},
+	})
+	srv.HandleAnswer("ACR", func(c diam.Conn, m *diam.Message) (*diam.Message, error) {
+		return srv.Answer(m, diam.Success), nil
+	})
+	cli := newClient()
+	c, sc, err := diamtest.NewPipePeers(cli, srv, dict.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if host := srv.PeerConn("cli"); host != sc {
+		t.Fatalf("Unexpected server connection of the client: %v", host)
+	}
+	a, err := cli.Send(c, newACR(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code, _ := diam.ResultCode(a); code != diam.Success {
+		t.Fatalf("Unexpected Result-Code: %d", code)
+	}
+}
+
+func TestFakeServer(t *testing.T) {
+	fs := diamtest.NewFakeServer("srv", "test")
+	fs.Expect(diam.Accounting).
+		WithAVP(avp.AccountingRecordNumber, datatype.Unsigned32(1)).
+		Reply(diam.Success, diam.NewAVP(avp.AccountingRecordNumber, avp.Mbit, 0, datatype.Unsigned32(1))).
+		Times(1)
+	fs.Expect(diam.Accounting).Reply(diam.InvalidAVPValue)
+
+	cli := newClient()
+	c, _, err := diamtest.NewPipePeers(cli, fs, dict.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	for _, want := range []uint32{diam.Success, diam.InvalidAVPValue} {
+		a, err := cli.Send(c, newACR(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code, _ := diam.ResultCode(a); code != want {
+			t.Fatalf("Unexpected Result-Code. Want %d, have %d", want, code)
+		}
+	}
+	if err = fs.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(fs.Received()); n != 3 {
+		t.Fatalf("Unexpected number of requests received. Want 3, have %d", n)
+	}
+
+	a, err := cli.Send(c, diam.NewRequest(diam.ReAuth, 3, dict.Default))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code, _ := diam.ResultCode(a); code != diam.UnableToComply {
+		t.Fatalf("Unexpected Result-Code for unexpected request: %d", code)
+	}
+	if err = fs.Verify(); err == nil {
+		t.Fatal("Unexpected request was not reported")
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diamtest
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+)
+
+// RoundTrip serializes the message m and decodes it with its dictionary,
+// as if it was received from the network. It fails the test t when the
+// message cannot be serialized or decoded.
+func RoundTrip(t testing.TB, m *diam.Message) *diam.Message {
+	t.Helper()
+	var b bytes.Buffer
+	if _, err := m.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	rm, err := diam.ReadMessage(&b, m.Dictionary())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rm
+}
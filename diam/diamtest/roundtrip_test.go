@@ -0,0 +1,18 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diamtest
+
+import "testing"
+
+func TestRoundTrip(t *testing.T) {
+	m := newCCA("cli;1", 1, 100)
+	rm := RoundTrip(t, m)
+	if rm == m {
+		t.Fatal("Message was not decoded")
+	}
+	if d := MessageDiff(m, rm); d != "" {
+		t.Fatalf("Unexpected difference: %s", d)
+	}
+}
@@ -18,11 +18,19 @@ var Default *Parser
 func init() {
 	var dictionaries = []struct{ name, xml string }{
 		{"Base", baseXML},
+		{"Capabilities Update", capabilitiesupdateXML},
 		{"Credit Control", creditcontrolXML},
+		{"Diameter EAP", diametereapXML},
 		{"Gx Charging Control", gxcreditcontrolXML},
 		{"Network Access Server", networkaccessserverXML},
 		{"TGPP", tgpprorfXML},
+		{"TGPP_Cx", tgppcxXML},
+		{"TGPP_Gxx", tgppgxxXML},
+		{"TGPP_Rx", tgpprxXML},
 		{"TGPP_S6a", tgpps6aXML},
+		{"TGPP_S6b", tgpps6bXML},
+		{"TGPP_Sh", tgppshXML},
+		{"TGPP_SWm", tgppswmXML},
 		{"TGPP_Swx", tgppswxXML},
 	}
 	var err error
@@ -121,7 +129,7 @@ var baseXML = `<?xml version="1.0" encoding="UTF-8"?>
 				<rule avp="User-Name" required="false" max="1"/>
 				<rule avp="Destination-Host" required="false" max="1"/>
 				<rule avp="Accounting-Sub-Session-Id" required="false" max="1"/>
-				<rule avp="Acct-Session-Id" required="false" max="1"/>
+				<rule avp="Accounting-Session-Id" required="false" max="1"/>
 				<rule avp="Acct-Multi-Session-Id" required="false" max="1"/>
 				<rule avp="Acct-Interim-Interval" required="false" max="1"/>
 				<rule avp="Accounting-Realtime-Required" required="false" max="1"/>
@@ -141,7 +149,7 @@ var baseXML = `<?xml version="1.0" encoding="UTF-8"?>
 				<rule avp="Vendor-Specific-Application-Id" required="false" max="1"/>
 				<rule avp="User-Name" required="false" max="1"/>
 				<rule avp="Accounting-Sub-Session-Id" required="false" max="1"/>
-				<rule avp="Acct-Session-Id" required="false" max="1"/>
+				<rule avp="Accounting-Session-Id" required="false" max="1"/>
 				<rule avp="Acct-Multi-Session-Id" required="false" max="1"/>
 				<rule avp="Error-Message" required="false" max="1"/>
 				<rule avp="Error-Reporting-Host" required="false" max="1"/>
@@ -540,6 +548,32 @@ var baseXML = `<?xml version="1.0" encoding="UTF-8"?>
 			<data type="Unsigned32"/>
 		</avp>
 
+		<!-- IETF RFC 8581 - https://tools.ietf.org/html/rfc8581 -->
+		<avp name="SourceID" code="649" must-not="V">
+			<data type="DiameterIdentity"/>
+		</avp>
+
+		<!-- IETF RFC 8583 - https://tools.ietf.org/html/rfc8583 -->
+		<avp name="Load" code="650" must-not="V">
+			<data type="Grouped">
+				<rule avp="Load-Type" required="false" max="1"/>
+				<rule avp="Load-Value" required="false" max="1"/>
+				<rule avp="SourceID" required="false" max="1"/>
+				<rule avp="AVP" required="false"/>
+			</data>
+		</avp>
+
+		<avp name="Load-Type" code="651" must-not="V">
+			<data type="Enumerated">
+				<item code="0" name="HOST"/>
+				<item code="1" name="PEER"/>
+			</data>
+		</avp>
+
+		<avp name="Load-Value" code="652" must-not="V">
+			<data type="Unsigned64"/>
+		</avp>
+
 		<!-- IETF RFC 7944 - https://tools.ietf.org/html/rfc7944 -->
 		<avp name="DRMP" code="301" must-not="V">
 			<data type="Enumerated">
@@ -568,6 +602,40 @@ var baseXML = `<?xml version="1.0" encoding="UTF-8"?>
 	</application>
 </diameter>`
 
+var capabilitiesupdateXML = `<?xml version="1.0" encoding="UTF-8"?>
+<diameter>
+
+	<application id="10" type="auth" name="Capabilities Update">
+		<!-- Diameter Capabilities Update Application -->
+		<!-- http://tools.ietf.org/html/rfc6737 -->
+
+		<command code="328" short="CU" name="Capabilities-Update">
+			<request>
+				<!-- http://tools.ietf.org/html/rfc6737#section-3.1 -->
+				<rule avp="Origin-Host" required="true" max="1"/>
+				<rule avp="Origin-Realm" required="true" max="1"/>
+				<rule avp="Host-IP-Address" required="false"/>
+				<rule avp="Vendor-Id" required="false" max="1"/>
+				<rule avp="Product-Name" required="false" max="1"/>
+				<rule avp="Origin-State-Id" required="false" max="1"/>
+				<rule avp="Supported-Vendor-Id" required="false"/>
+				<rule avp="Auth-Application-Id" required="false"/>
+				<rule avp="Acct-Application-Id" required="false"/>
+				<rule avp="Vendor-Specific-Application-Id" required="false"/>
+				<rule avp="Firmware-Revision" required="false" max="1"/>
+			</request>
+			<answer>
+				<!-- http://tools.ietf.org/html/rfc6737#section-3.2 -->
+				<rule avp="Result-Code" required="true" max="1"/>
+				<rule avp="Origin-Host" required="true" max="1"/>
+				<rule avp="Origin-Realm" required="true" max="1"/>
+				<rule avp="Error-Message" required="false" max="1"/>
+				<rule avp="Failed-AVP" required="false" max="1"/>
+			</answer>
+		</command>
+	</application>
+</diameter>`
+
 var creditcontrolXML = `<?xml version="1.0" encoding="UTF-8"?>
 <diameter>
 
@@ -605,6 +673,7 @@ var creditcontrolXML = `<?xml version="1.0" encoding="UTF-8"?>
 				<rule avp="User-Equipment-Info" required="false" max="1"/>
 				<rule avp="Proxy-Info" required="false" max="1"/>
 				<rule avp="Route-Record" required="false" max="1"/>
+				<rule avp="AoC-Request-Type" required="false" max="1"/>
 				<rule avp="Service-Information" required="false" max="1"/>
 			</request>
 			<answer>
@@ -635,6 +704,9 @@ var creditcontrolXML = `<?xml version="1.0" encoding="UTF-8"?>
 				<rule avp="Proxy-Info" required="false" max="1"/>
 				<rule avp="Route-Record" required="false" max="1"/>
 				<rule avp="Failed-AVP" required="false" max="1"/>
+				<rule avp="Low-Balance-Indication" required="false" max="1"/>
+				<rule avp="Remaining-Balance" required="false" max="1"/>
+				<rule avp="Service-Information" required="false" max="1"/>
 			</answer>
 		</command>
 
@@ -800,6 +872,9 @@ var creditcontrolXML = `<?xml version="1.0" encoding="UTF-8"?>
 				<rule avp="CC-Input-Octets" required="false" max="1"/>
 				<rule avp="CC-Output-Octets" required="false" max="1"/>
 				<rule avp="CC-Service-Specific-Units" required="false" max="1"/>
+				<!-- 3GPP TS 32.299 Gy extensions -->
+				<rule avp="Reporting-Reason" required="false" max="1"/>
+				<rule avp="Event-Charging-TimeStamp" required="false"/>
 				<!-- *[ AVP ]-->
 			</data>
 		</avp>
@@ -831,6 +906,25 @@ var creditcontrolXML = `<?xml version="1.0" encoding="UTF-8"?>
 				<rule avp="Validity-Time" required="false" max="1"/>
 				<rule avp="Result-Code" required="false" max="1"/>
 				<rule avp="Final-Unit-Indication" required="false" max="1"/>
+				<!-- 3GPP TS 32.299 Gy extensions -->
+				<rule avp="Time-Quota-Threshold" required="false" max="1"/>
+				<rule avp="Volume-Quota-Threshold" required="false" max="1"/>
+				<rule avp="Unit-Quota-Threshold" required="false" max="1"/>
+				<rule avp="Quota-Holding-Time" required="false" max="1"/>
+				<rule avp="Quota-Consumption-Time" required="false" max="1"/>
+				<rule avp="Reporting-Reason" required="false"/>
+				<rule avp="Trigger" required="false" max="1"/>
+				<rule avp="PS-Furnish-Charging-Information" required="false" max="1"/>
+				<rule avp="Refund-Information" required="false" max="1"/>
+				<rule avp="AF-Correlation-Information" required="false"/>
+				<rule avp="Envelope" required="false"/>
+				<rule avp="Envelope-Reporting" required="false" max="1"/>
+				<rule avp="Time-Quota-Mechanism" required="false" max="1"/>
+				<rule avp="Service-Specific-Info" required="false"/>
+				<rule avp="QoS-Information" required="false" max="1"/>
+				<rule avp="Announcement-Information" required="false"/>
+				<rule avp="TGPP-RAT-Type" required="false" max="1"/>
+				<rule avp="Related-Trigger" required="false" max="1"/>
 				<!-- *[ AVP ]-->
 			</data>
 		</avp>
@@ -890,6 +984,9 @@ var creditcontrolXML = `<?xml version="1.0" encoding="UTF-8"?>
 				<rule avp="CC-Input-Octets" required="false" max="1"/>
 				<rule avp="CC-Output-Octets" required="false" max="1"/>
 				<rule avp="CC-Service-Specific-Units" required="false" max="1"/>
+				<!-- 3GPP TS 32.299 Gy extensions -->
+				<rule avp="Reporting-Reason" required="false" max="1"/>
+				<rule avp="Event-Charging-TimeStamp" required="false"/>
 				<!-- *[ AVP ]-->
 			</data>
 		</avp>
@@ -982,6 +1079,9 @@ var creditcontrolXML = `<?xml version="1.0" encoding="UTF-8"?>
 				<rule avp="CC-Input-Octets" required="false" max="1"/>
 				<rule avp="CC-Output-Octets" required="false" max="1"/>
 				<rule avp="CC-Service-Specific-Units" required="false" max="1"/>
+				<!-- 3GPP TS 32.299 Gy extensions -->
+				<rule avp="Reporting-Reason" required="false" max="1"/>
+				<rule avp="Event-Charging-TimeStamp" required="false"/>
 				<!-- *[ AVP ]-->
 			</data>
 		</avp>
@@ -1021,6 +1121,105 @@ var creditcontrolXML = `<?xml version="1.0" encoding="UTF-8"?>
 	</application>
 </diameter>`
 
+var diametereapXML = `<?xml version="1.0" encoding="UTF-8"?>
+<diameter>
+
+	<application id="5" type="auth" name="Diameter EAP">
+		<!-- Diameter Extensible Authentication Protocol (EAP) Application -->
+		<!-- http://tools.ietf.org/html/rfc4072 -->
+		<!-- AVPs not defined here are looked up in the NASREQ dictionary. -->
+
+		<command code="268" short="DE" name="Diameter-EAP">
+			<request>
+				<!-- https://tools.ietf.org/html/rfc4072#section-3.1 -->
+				<rule avp="Session-Id" required="true" max="1"/>
+				<rule avp="Auth-Application-Id" required="true" max="1"/>
+				<rule avp="Origin-Host" required="true" max="1"/>
+				<rule avp="Origin-Realm" required="true" max="1"/>
+				<rule avp="Destination-Realm" required="true" max="1"/>
+				<rule avp="Auth-Request-Type" required="true" max="1"/>
+				<rule avp="EAP-Payload" required="true" max="1"/>
+				<rule avp="Destination-Host" required="false" max="1"/>
+				<rule avp="NAS-Identifier" required="false" max="1"/>
+				<rule avp="NAS-IP-Address" required="false" max="1"/>
+				<rule avp="NAS-IPv6-Address" required="false" max="1"/>
+				<rule avp="NAS-Port" required="false" max="1"/>
+				<rule avp="NAS-Port-Id" required="false" max="1"/>
+				<rule avp="NAS-Port-Type" required="false" max="1"/>
+				<rule avp="Origin-State-Id" required="false" max="1"/>
+				<rule avp="User-Name" required="false" max="1"/>
+				<rule avp="EAP-Key-Name" required="false" max="1"/>
+				<rule avp="Service-Type" required="false" max="1"/>
+				<rule avp="State" required="false" max="1"/>
+				<rule avp="Authorization-Lifetime" required="false" max="1"/>
+				<rule avp="Auth-Grace-Period" required="false" max="1"/>
+				<rule avp="Auth-Session-State" required="false" max="1"/>
+				<rule avp="Called-Station-Id" required="false" max="1"/>
+				<rule avp="Calling-Station-Id" required="false" max="1"/>
+				<rule avp="Framed-MTU" required="false" max="1"/>
+				<rule avp="Proxy-Info" required="false"/>
+				<rule avp="Route-Record" required="false"/>
+			</request>
+			<answer>
+				<!-- https://tools.ietf.org/html/rfc4072#section-3.2 -->
+				<rule avp="Session-Id" required="true" max="1"/>
+				<rule avp="Auth-Application-Id" required="true" max="1"/>
+				<rule avp="Auth-Request-Type" required="true" max="1"/>
+				<rule avp="Result-Code" required="true" max="1"/>
+				<rule avp="Origin-Host" required="true" max="1"/>
+				<rule avp="Origin-Realm" required="true" max="1"/>
+				<rule avp="User-Name" required="false" max="1"/>
+				<rule avp="EAP-Payload" required="false" max="1"/>
+				<rule avp="EAP-Reissued-Payload" required="false" max="1"/>
+				<rule avp="EAP-Master-Session-Key" required="false" max="1"/>
+				<rule avp="EAP-Key-Name" required="false" max="1"/>
+				<rule avp="Multi-Round-Time-Out" required="false" max="1"/>
+				<rule avp="Accounting-EAP-Auth-Method" required="false"/>
+				<rule avp="Service-Type" required="false" max="1"/>
+				<rule avp="Class" required="false"/>
+				<rule avp="Acct-Interim-Interval" required="false" max="1"/>
+				<rule avp="Error-Message" required="false" max="1"/>
+				<rule avp="Error-Reporting-Host" required="false" max="1"/>
+				<rule avp="Failed-AVP" required="false"/>
+				<rule avp="Idle-Timeout" required="false" max="1"/>
+				<rule avp="Authorization-Lifetime" required="false" max="1"/>
+				<rule avp="Auth-Grace-Period" required="false" max="1"/>
+				<rule avp="Auth-Session-State" required="false" max="1"/>
+				<rule avp="Re-Auth-Request-Type" required="false" max="1"/>
+				<rule avp="Session-Timeout" required="false" max="1"/>
+				<rule avp="State" required="false" max="1"/>
+				<rule avp="Origin-State-Id" required="false" max="1"/>
+				<rule avp="Proxy-Info" required="false"/>
+			</answer>
+		</command>
+
+		<avp name="EAP-Payload" code="462" must="M" may="P" must-not="V" may-encrypt="Y">
+			<!-- https://tools.ietf.org/html/rfc4072#section-4.1.1 -->
+			<data type="OctetString"/>
+		</avp>
+
+		<avp name="EAP-Reissued-Payload" code="463" must="M" may="P" must-not="V" may-encrypt="Y">
+			<!-- https://tools.ietf.org/html/rfc4072#section-4.1.2 -->
+			<data type="OctetString"/>
+		</avp>
+
+		<avp name="EAP-Master-Session-Key" code="464" must="-" may="M" must-not="V" may-encrypt="Y">
+			<!-- https://tools.ietf.org/html/rfc4072#section-4.1.3 -->
+			<data type="OctetString"/>
+		</avp>
+
+		<avp name="EAP-Key-Name" code="102" must="-" may="M" must-not="V" may-encrypt="Y">
+			<!-- https://tools.ietf.org/html/rfc4072#section-4.1.4 -->
+			<data type="OctetString"/>
+		</avp>
+
+		<avp name="Accounting-EAP-Auth-Method" code="465" must="M" may="P" must-not="V" may-encrypt="Y">
+			<!-- https://tools.ietf.org/html/rfc4072#section-4.1.5 -->
+			<data type="Unsigned64"/>
+		</avp>
+	</application>
+</diameter>`
+
 var diametersyXML = `<?xml version="1.0" encoding="UTF-8"?>
 <diameter>
 
@@ -1117,6 +1316,17 @@ var gxcreditcontrolXML = `<?xml version="1.0" encoding="UTF-8"?>
                 <rule avp="QoS-Information" required="false" max="1"/>
                 <rule avp="TGPP-SGSN-MCC-MNC" required="false" max="1"/>
                 <rule avp="TGPP-User-Location-Info" required="false" max="1"/>
+                <rule avp="Bearer-Identifier" required="false" max="1"/>
+                <rule avp="Bearer-Operation" required="false" max="1"/>
+                <rule avp="Packet-Filter-Information" required="false"/>
+                <rule avp="Packet-Filter-Operation" required="false" max="1"/>
+                <rule avp="Charging-Rule-Report" required="false"/>
+                <rule avp="Event-Trigger" required="false"/>
+                <rule avp="Usage-Monitoring-Information" required="false"/>
+                <rule avp="CoA-Information" required="false"/>
+                <rule avp="PDN-Connection-ID" required="false" max="1"/>
+                <rule avp="Application-Detection-Information" required="false"/>
+                <rule avp="PS-to-CS-Session-Continuity" required="false" max="1"/>
             </request>
             <answer>
                 <!-- 3GPP 29.212 Section 5.6.3 -->
@@ -1136,6 +1346,14 @@ var gxcreditcontrolXML = `<?xml version="1.0" encoding="UTF-8"?>
                 <rule avp="Event-Trigger" required="false"/>
                 <rule avp="Revalidation-Time" required="false"/>
                 <rule avp="Session-Release-Cause" required="false" max="1"/>
+                <rule avp="Bearer-Control-Mode" required="false" max="1"/>
+                <rule avp="QoS-Information" required="false" max="1"/>
+                <rule avp="Event-Report-Indication" required="false" max="1"/>
+                <rule avp="CSG-Information-Reporting" required="false" max="2"/>
+                <rule avp="ADC-Rule-Install" required="false"/>
+                <rule avp="ADC-Rule-Remove" required="false"/>
+                <rule avp="Charging-Correlation-Indicator" required="false" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
             </answer>
         </command>
 
@@ -1155,6 +1373,14 @@ var gxcreditcontrolXML = `<?xml version="1.0" encoding="UTF-8"?>
                 <rule avp="Route-Record" required="false"/>
                 <rule avp="Event-Trigger" required="false"/>
                 <rule avp="Revalidation-Time" required="false"/>
+                <rule avp="Charging-Rule-Install" required="false"/>
+                <rule avp="Charging-Rule-Remove" required="false"/>
+                <rule avp="Default-EPS-Bearer-QoS" required="false" max="1"/>
+                <rule avp="Event-Report-Indication" required="false" max="1"/>
+                <rule avp="Usage-Monitoring-Information" required="false"/>
+                <rule avp="ADC-Rule-Install" required="false"/>
+                <rule avp="ADC-Rule-Remove" required="false"/>
+                <rule avp="CSG-Information-Reporting" required="false" max="2"/>
             </request>
             <answer>
                 <rule avp="Session-Id" required="true" max="1"/>
@@ -1166,6 +1392,13 @@ var gxcreditcontrolXML = `<?xml version="1.0" encoding="UTF-8"?>
                 <rule avp="Error-Reporting-Host" required="false" max="1"/>
                 <rule avp="Failed-AVP" required="false" max="1"/>
                 <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Experimental-Result" required="false" max="1"/>
+                <rule avp="Charging-Rule-Report" required="false"/>
+                <rule avp="Bearer-Identifier" required="false" max="1"/>
+                <rule avp="Event-Trigger" required="false"/>
+                <rule avp="Usage-Monitoring-Information" required="false"/>
+                <rule avp="ADC-Rule-Report" required="false"/>
+                <rule avp="Application-Detection-Information" required="false"/>
             </answer>
         </command>
 
@@ -1253,6 +1486,19 @@ var gxcreditcontrolXML = `<?xml version="1.0" encoding="UTF-8"?>
                 <item code="29" name="APN-AMBR_MODIFICATION_FAILURE"/>
                 <item code="30" name="USER_CSG_INFORMATION_CHANGE"/>
                 <item code="33" name="USAGE_REPORT"/>
+                <item code="34" name="DEFAULT_EPS_BEARER_QOS_MODIFICATION_FAILURE"/>
+                <item code="35" name="USER_CSG_HYBRID_SUBSCRIBED_INFORMATION_CHANGE"/>
+                <item code="36" name="USER_CSG_HYBRID_UNSUBSCRIBED_INFORMATION_CHANGE"/>
+                <item code="37" name="ROUTING_RULE_CHANGE"/>
+                <item code="39" name="APPLICATION_START"/>
+                <item code="40" name="APPLICATION_STOP"/>
+                <item code="42" name="CS_TO_PS_HANDOVER"/>
+                <item code="43" name="UE_LOCAL_IP_ADDRESS_CHANGE"/>
+                <item code="44" name="HENB_LOCAL_IP_ADDRESS_CHANGE"/>
+                <item code="45" name="ACCESS_NETWORK_INFO_REPORT"/>
+                <item code="46" name="CREDIT_MANAGEMENT_SESSION_FAILURE"/>
+                <item code="47" name="DEFAULT_QOS_CHANGE"/>
+                <item code="48" name="CHANGE_OF_UE_PRESENCE_IN_PRESENCE_REPORTING_AREA_REPORT"/>
             </data>
         </avp>
 
@@ -1488,177 +1734,494 @@ var gxcreditcontrolXML = `<?xml version="1.0" encoding="UTF-8"?>
             </data>
         </avp>
 
-    </application>
-</diameter>`
+        <avp name="Metering-Method" code="1007" must="M,V" may="P" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="Enumerated">
+                <item code="0" name="DURATION"/>
+                <item code="1" name="VOLUME"/>
+                <item code="2" name="DURATION_VOLUME"/>
+                <item code="3" name="EVENT"/>
+            </data>
+        </avp>
 
-var networkaccessserverXML = `<?xml version="1.0" encoding="UTF-8"?>
-<diameter>
+        <avp name="Reporting-Level" code="1011" must="M,V" may="P" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="Enumerated">
+                <item code="0" name="SERVICE_IDENTIFIER_LEVEL"/>
+                <item code="1" name="RATING_GROUP_LEVEL"/>
+                <item code="2" name="SPONSORED_CONNECTIVITY_LEVEL"/>
+            </data>
+        </avp>
 
-	<application id="1" type="auth" name="Network Access">
-		<!-- Diameter Network Access Server Application -->
-		<!-- http://tools.ietf.org/html/rfc7155 -->
+        <avp name="QoS-Negotiation" code="1017" must="M,V" may="P" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="Enumerated">
+                <item code="0" name="NO_QoS_NEGOTIATION"/>
+                <item code="1" name="QoS_NEGOTIATION_SUPPORTED"/>
+            </data>
+        </avp>
 
-		<command code="265" short="AA" name="AA">
-			<request>
-				<!-- https://tools.ietf.org/html/rfc7155#section-3.1 -->
-				<rule avp="Session-Id" required="true" max="1"/>
-				<rule avp="Auth-Application-Id" required="true" max="1"/>
-				<rule avp="Origin-Host" required="true" max="1"/>
-				<rule avp="Origin-Realm" required="true" max="1"/>
-				<rule avp="Destination-Realm" required="true" max="1"/>
-				<rule avp="Auth-Request-Type" required="true" max="1"/>
-				<rule avp="Destination-Host" required="false" max="1"/>
-				<rule avp="NAS-Identifier" required="false" max="1"/>
-				<rule avp="NAS-IP-Address" required="true" max="1"/>
-				<rule avp="NAS-IPv6-Address" required="false" max="1"/>
-				<rule avp="NAS-Port" required="false" max="1"/>
-				<rule avp="NAS-Port-Id" required="false" max="1"/>
-				<rule avp="NAS-Port-Type" required="false" max="1"/>
-				<rule avp="Origin-AAA-Protocol" required="false" max="1"/>
-				<rule avp="Origin-State-Id" required="false" max="1"/>
-				<rule avp="Port-Limit" required="false" max="1"/>
-				<rule avp="User-Name" required="false" max="1"/>
-				<rule avp="User-Password" required="false" max="1"/>
-				<rule avp="Service-Type" required="false" max="1"/>
-				<rule avp="State" required="false" max="1"/>
-				<rule avp="Authorization-Lifetime" required="false" max="1"/>
-				<rule avp="Auth-Grace-Period" required="false" max="1"/>
-				<rule avp="Auth-Session-State" required="false" max="1"/>
-				<rule avp="Callback-Number" required="false" max="1"/>
-				<rule avp="Called-Station-Id" required="false" max="1"/>
-				<rule avp="Calling-Station-Id" required="false" max="1"/>
-				<rule avp="Originating-Line-Info" required="false" max="1"/>
-				<rule avp="Connect-Info" required="false" max="1"/>
-				<rule avp="CHAP-Auth" required="false" max="1"/>
-				<rule avp="CHAP-Challenge" required="false" max="1"/>
-				<rule avp="Framed-Compression" required="false"/>
-				<rule avp="Framed-Interface-Id" required="false" max="1"/>
-				<rule avp="Framed-IP-Address" required="false" max="1"/>
-				<rule avp="Framed-IPv6-Prefix" required="false"/>
-				<rule avp="Framed-IP-Netmask" required="false" max="1"/>
-				<rule avp="Framed-MTU" required="false" max="1"/>
-				<rule avp="Framed-Protocol" required="false" max="1"/>
-				<rule avp="ARAP-Password" required="false" max="1"/>
-				<rule avp="ARAP-Security" required="false" max="1"/>
-				<rule avp="ARAP-Security-Data" required="false"/>
-				<rule avp="Login-IP-Host" required="false"/>
-				<rule avp="Login-IPv6-Host" required="false"/>
-				<rule avp="Login-LAT-Group" required="false" max="1"/>
-				<rule avp="Login-LAT-Node" required="false" max="1"/>
-				<rule avp="Login-LAT-Port" required="false" max="1"/>
-				<rule avp="Login-LAT-Service" required="false" max="1"/>
-				<rule avp="Tunneling" required="false"/>
-				<rule avp="Proxy-Info" required="false"/>
-				<rule avp="Route-Record" required="false"/>
-			</request>
-			<answer>
-				<!-- http://tools.ietf.org/html/rfc7155#section-3.2 -->
-				<rule avp="Session-Id" required="true" max="1"/>
-				<rule avp="Auth-Application-Id" required="true" max="1"/>
-				<rule avp="Auth-Request-Type" required="true" max="1"/>
-				<rule avp="Result-Code" required="true" max="1"/>
-				<rule avp="Origin-Host" required="true" max="1"/>
-				<rule avp="Origin-Realm" required="true" max="1"/>
-				<rule avp="User-Name" required="false" max="1"/>
-				<rule avp="Service-Type" required="false" max="1"/>
-				<rule avp="Class" required="false"/>
-				<rule avp="Configuration-Token" required="false"/>
-				<rule avp="Acct-Interim-Interval" required="false" max="1"/>
-				<rule avp="Error-Message" required="false" max="1"/>
-				<rule avp="Error-Reporting-Host" required="false" max="1"/>
-				<rule avp="Failed-AVP" required="false"/>
-				<rule avp="Idle-Timeout" required="false" max="1"/>
-				<rule avp="Authorization-Lifetime" required="false" max="1"/>
-				<rule avp="Auth-Grace-Period" required="false" max="1"/>
-				<rule avp="Auth-Session-State" required="false" max="1"/>
-				<rule avp="Re-Auth-Request-Type" required="false" max="1"/>
-				<rule avp="Multi-Round-Time-Out" required="false" max="1"/>
-				<rule avp="Session-Timeout" required="false" max="1"/>
-				<rule avp="State" required="false" max="1"/>
-				<rule avp="Reply-Message" required="false"/>
-				<rule avp="Origin-AAA-Protocol" required="false" max="1"/>
-				<rule avp="Origin-State-Id" required="false" max="1"/>
-				<rule avp="Filter-Id" required="false"/>
-				<rule avp="Password-Retry" required="false" max="1"/>
-				<rule avp="Port-Limit" required="false" max="1"/>
-				<rule avp="Prompt" required="false" max="1"/>
-				<rule avp="ARAP-Challenge-Response" required="false" max="1"/>
-				<rule avp="ARAP-Features" required="false" max="1"/>
-				<rule avp="ARAP-Security" required="false" max="1"/>
-				<rule avp="ARAP-Security-Data" required="false"/>
-				<rule avp="ARAP-Zone-Access" required="false" max="1"/>
-				<rule avp="Callback-Id" required="false" max="1"/>
-				<rule avp="Callback-Number" required="false" max="1"/>
-				<rule avp="Framed-Appletalk-Link" required="false" max="1"/>
-				<rule avp="Framed-Appletalk-Network" required="false"/>
-				<rule avp="Framed-Appletalk-Zone" required="false" max="1"/>
-				<rule avp="Framed-Compression" required="false"/>
-				<rule avp="Framed-Interface-Id" required="false" max="1"/>
-				<rule avp="Framed-IP-Address" required="false" max="1"/>
-				<rule avp="Framed-IPv6-Prefix" required="false"/>
-				<rule avp="Framed-IPv6-Pool" required="false" max="1"/>
-				<rule avp="Framed-IPv6-Route" required="false"/>
-				<rule avp="Framed-IP-Netmask" required="false" max="1"/>
-				<rule avp="Framed-Route" required="false"/>
-				<rule avp="Framed-Pool" required="false" max="1"/>
-				<rule avp="Framed-IPX-Network" required="false" max="1"/>
-				<rule avp="Framed-MTU" required="false" max="1"/>
-				<rule avp="Framed-Protocol" required="false" max="1"/>
-				<rule avp="Framed-Routing" required="false" max="1"/>
-				<rule avp="Login-IP-Host" required="false"/>
-				<rule avp="Login-IPv6-Host" required="false"/>
-				<rule avp="Login-LAT-Group" required="false" max="1"/>
-				<rule avp="Login-LAT-Node" required="false" max="1"/>
-				<rule avp="Login-LAT-Port" required="false" max="1"/>
-				<rule avp="Login-LAT-Service" required="false" max="1"/>
-				<rule avp="Login-Service" required="false" max="1"/>
-				<rule avp="Login-TCP-Port" required="false" max="1"/>
-				<rule avp="NAS-Filter-Rule" required="false"/>
-				<rule avp="QoS-Filter-Rule" required="false"/>
-				<rule avp="Tunneling" required="false"/>
-				<rule avp="Redirect-Host" required="false"/>
-				<rule avp="Redirect-Host-Usage" required="false" max="1"/>
-				<rule avp="Redirect-Max-Cache-Time" required="false" max="1"/>
-				<rule avp="Proxy-Info" required="false"/>
-			</answer>
-		</command>
+        <avp name="Charging-Rule-Report" code="1018" must="M,V" may="P" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="Grouped">
+                <rule avp="Charging-Rule-Name" required="false"/>
+                <rule avp="Charging-Rule-Base-Name" required="false"/>
+                <rule avp="Bearer-Identifier" required="false" max="1"/>
+                <rule avp="PCC-Rule-Status" required="false" max="1"/>
+                <rule avp="Rule-Failure-Code" required="false" max="1"/>
+                <rule avp="Final-Unit-Indication" required="false" max="1"/>
+            </data>
+        </avp>
 
-		<command code="258" short="RA" name="Re-Auth">
+        <avp name="PCC-Rule-Status" code="1019" must="M,V" may="P" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="Enumerated">
+                <item code="0" name="ACTIVE"/>
+                <item code="1" name="INACTIVE"/>
+                <item code="2" name="TEMPORARILY_INACTIVE"/>
+            </data>
+        </avp>
+
+        <avp name="Bearer-Operation" code="1021" must="M,V" may="P" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="Enumerated">
+                <item code="0" name="TERMINATION"/>
+                <item code="1" name="ESTABLISHMENT"/>
+                <item code="2" name="MODIFICATION"/>
+            </data>
+        </avp>
+
+        <avp name="QoS-Upgrade" code="1029" must="M,V" may="P" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="Enumerated">
+                <item code="0" name="QoS_UPGRADE_NOT_SUPPORTED"/>
+                <item code="1" name="QoS_UPGRADE_SUPPORTED"/>
+            </data>
+        </avp>
+
+        <avp name="Rule-Failure-Code" code="1031" must="M,V" may="P" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="Enumerated">
+                <item code="1" name="UNKNOWN_RULE_NAME"/>
+                <item code="2" name="RATING_GROUP_ERROR"/>
+                <item code="3" name="SERVICE_IDENTIFIER_ERROR"/>
+                <item code="4" name="GW/PCEF_MALFUNCTION"/>
+                <item code="5" name="RESOURCES_LIMITATION"/>
+                <item code="6" name="MAX_NR_BEARERS_REACHED"/>
+                <item code="7" name="UNKNOWN_BEARER_ID"/>
+                <item code="8" name="MISSING_BEARER_ID"/>
+                <item code="9" name="MISSING_FLOW_INFORMATION"/>
+                <item code="10" name="RESOURCE_ALLOCATION_FAILURE"/>
+                <item code="11" name="UNSUCCESSFUL_QOS_VALIDATION"/>
+                <item code="12" name="INCORRECT_FLOW_INFORMATION"/>
+                <item code="13" name="PS_TO_CS_HANDOVER"/>
+                <item code="14" name="TDF_APPLICATION_IDENTIFIER_ERROR"/>
+                <item code="15" name="NO_BEARER_BOUND"/>
+                <item code="16" name="FILTER_RESTRICTIONS"/>
+                <item code="17" name="AN_GW_FAILED"/>
+                <item code="18" name="MISSING_REDIRECT_SERVER_ADDRESS"/>
+                <item code="19" name="CM_END_USER_SERVICE_DENIED"/>
+                <item code="20" name="CM_CREDIT_CONTROL_NOT_APPLICABLE"/>
+                <item code="21" name="CM_AUTHORIZATION_REJECTED"/>
+                <item code="22" name="CM_USER_UNKNOWN"/>
+                <item code="23" name="CM_RATING_FAILED"/>
+            </data>
+        </avp>
+
+        <avp name="Event-Report-Indication" code="1033" must="V" may="P" must-not="M" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="Grouped">
+                <rule avp="Event-Trigger" required="false"/>
+                <rule avp="Event-Report-Indication" required="false" max="0"/>
+                <rule avp="QoS-Information" required="false" max="1"/>
+                <rule avp="RAT-Type" required="false" max="1"/>
+                <rule avp="AN-GW-Address" required="false" max="2"/>
+                <rule avp="TGPP-SGSN-Address" required="false" max="1"/>
+                <rule avp="TGPP-User-Location-Info" required="false" max="1"/>
+                <rule avp="TGPP-MS-TimeZone" required="false" max="1"/>
+                <rule avp="TGPP-SGSN-MCC-MNC" required="false" max="1"/>
+                <rule avp="Framed-IP-Address" required="false" max="1"/>
+            </data>
+        </avp>
+
+        <avp name="CoA-IP-Address" code="1035" must="V" may="P" must-not="M" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="Address"/>
+        </avp>
+
+        <avp name="Tunnel-Header-Filter" code="1036" must="V" may="P" must-not="M" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="IPFilterRule"/>
+        </avp>
+
+        <avp name="Tunnel-Header-Length" code="1037" must="V" may="P" must-not="M" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="Tunnel-Information" code="1038" must="V" may="P" must-not="M" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="Grouped">
+                <rule avp="Tunnel-Header-Length" required="false" max="1"/>
+                <rule avp="Tunnel-Header-Filter" required="false" max="2"/>
+            </data>
+        </avp>
+
+        <avp name="CoA-Information" code="1039" must="V" may="P" must-not="M" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="Grouped">
+                <rule avp="Tunnel-Information" required="true" max="1"/>
+                <rule avp="CoA-IP-Address" required="true" max="1"/>
+            </data>
+        </avp>
+
+        <avp name="Packet-Filter-Content" code="1059" must="M,V" may="P" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="IPFilterRule"/>
+        </avp>
+
+        <avp name="Packet-Filter-Information" code="1061" must="M,V" may="P" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="Grouped">
+                <rule avp="Packet-Filter-Identifier" required="false" max="1"/>
+                <rule avp="Precedence" required="false" max="1"/>
+                <rule avp="Packet-Filter-Content" required="false" max="1"/>
+                <rule avp="ToS-Traffic-Class" required="false" max="1"/>
+                <rule avp="Security-Parameter-Index" required="false" max="1"/>
+                <rule avp="Flow-Label" required="false" max="1"/>
+                <rule avp="Flow-Direction" required="false" max="1"/>
+            </data>
+        </avp>
+
+        <avp name="Packet-Filter-Operation" code="1062" must="M,V" may="P" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="Enumerated">
+                <item code="0" name="DELETION"/>
+                <item code="1" name="ADDITION"/>
+                <item code="2" name="MODIFICATION"/>
+            </data>
+        </avp>
+
+        <avp name="Resource-Allocation-Notification" code="1063" must="V" may="P" must-not="M" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="Enumerated">
+                <item code="0" name="ENABLE_NOTIFICATION"/>
+            </data>
+        </avp>
+
+        <avp name="PDN-Connection-ID" code="1065" must="V" may="P" must-not="M" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="Usage-Monitoring-Report" code="1069" must="M,V" may="P" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="Enumerated">
+                <item code="0" name="USAGE_MONITORING_REPORT_REQUIRED"/>
+            </data>
+        </avp>
+
+        <avp name="Usage-Monitoring-Support" code="1070" must="M,V" may="P" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="Enumerated">
+                <item code="0" name="USAGE_MONITORING_DISABLED"/>
+            </data>
+        </avp>
+
+        <avp name="CSG-Information-Reporting" code="1071" must="V" may="P" must-not="M" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="Enumerated">
+                <item code="0" name="CHANGE_CSG_CELL"/>
+                <item code="1" name="CHANGE_CSG_SUBSCRIBED_HYBRID_CELL"/>
+                <item code="2" name="CHANGE_CSG_UNSUBSCRIBED_HYBRID_CELL"/>
+            </data>
+        </avp>
+
+        <avp name="Charging-Correlation-Indicator" code="1073" must="M,V" may="P" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="Enumerated">
+                <item code="0" name="CHARGING_IDENTIFIER_REQUIRED"/>
+            </data>
+        </avp>
+
+        <avp name="TDF-Information" code="1087" must="V" may="P" must-not="M" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="Grouped">
+                <rule avp="TDF-Destination-Realm" required="false" max="1"/>
+                <rule avp="TDF-Destination-Host" required="false" max="1"/>
+                <rule avp="TDF-IP-Address" required="false" max="1"/>
+            </data>
+        </avp>
+
+        <avp name="TDF-Application-Identifier" code="1088" must="V" may="P" must-not="M" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="TDF-Destination-Host" code="1089" must="V" may="P" must-not="M" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="DiameterIdentity"/>
+        </avp>
+
+        <avp name="TDF-Destination-Realm" code="1090" must="V" may="P" must-not="M" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="DiameterIdentity"/>
+        </avp>
+
+        <avp name="ADC-Rule-Install" code="1092" must="V" may="P" must-not="M" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="Grouped">
+                <rule avp="ADC-Rule-Definition" required="false"/>
+                <rule avp="ADC-Rule-Name" required="false"/>
+                <rule avp="ADC-Rule-Base-Name" required="false"/>
+                <rule avp="Monitoring-Flags" required="false" max="1"/>
+                <rule avp="Rule-Activation-Time" required="false" max="1"/>
+                <rule avp="Rule-Deactivation-Time" required="false" max="1"/>
+            </data>
+        </avp>
+
+        <avp name="ADC-Rule-Remove" code="1093" must="V" may="P" must-not="M" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="Grouped">
+                <rule avp="ADC-Rule-Name" required="false"/>
+                <rule avp="ADC-Rule-Base-Name" required="false"/>
+            </data>
+        </avp>
+
+        <avp name="ADC-Rule-Definition" code="1094" must="V" may="P" must-not="M" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="Grouped">
+                <rule avp="ADC-Rule-Name" required="true" max="1"/>
+                <rule avp="TDF-Application-Identifier" required="false" max="1"/>
+                <rule avp="Flow-Information" required="false"/>
+                <rule avp="Service-Identifier" required="false" max="1"/>
+                <rule avp="Rating-Group" required="false" max="1"/>
+                <rule avp="Reporting-Level" required="false" max="1"/>
+                <rule avp="Online" required="false" max="1"/>
+                <rule avp="Offline" required="false" max="1"/>
+                <rule avp="Metering-Method" required="false" max="1"/>
+                <rule avp="Precedence" required="false" max="1"/>
+                <rule avp="QoS-Information" required="false" max="1"/>
+                <rule avp="Monitoring-Key" required="false" max="1"/>
+                <rule avp="Sponsor-Identity" required="false" max="1"/>
+                <rule avp="Application-Service-Provider-Identity" required="false" max="1"/>
+                <rule avp="Redirect-Information" required="false" max="1"/>
+            </data>
+        </avp>
+
+        <avp name="ADC-Rule-Name" code="1096" must="V" may="P" must-not="M" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="ADC-Rule-Report" code="1097" must="V" may="P" must-not="M" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="Grouped">
+                <rule avp="ADC-Rule-Name" required="false"/>
+                <rule avp="ADC-Rule-Base-Name" required="false"/>
+                <rule avp="PCC-Rule-Status" required="false" max="1"/>
+                <rule avp="Rule-Failure-Code" required="false" max="1"/>
+                <rule avp="Final-Unit-Indication" required="false" max="1"/>
+            </data>
+        </avp>
+
+        <avp name="Application-Detection-Information" code="1098" must="V" may="P" must-not="M" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="Grouped">
+                <rule avp="TDF-Application-Identifier" required="true" max="1"/>
+                <rule avp="TDF-Application-Instance-Identifier" required="false" max="1"/>
+                <rule avp="Flow-Information" required="false"/>
+            </data>
+        </avp>
+
+        <avp name="PS-to-CS-Session-Continuity" code="1099" must="V" may="P" must-not="M" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="Enumerated">
+                <item code="0" name="VIDEO_PS2CS_CONT_CANDIDATE"/>
+            </data>
+        </avp>
+
+        <avp name="TDF-Application-Instance-Identifier" code="2802" must="V" may="P" must-not="M" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="Monitoring-Flags" code="2828" must="V" may="P" must-not="M" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="Unsigned32"/>
+        </avp>
+
+    </application>
+</diameter>`
+
+var networkaccessserverXML = `<?xml version="1.0" encoding="UTF-8"?>
+<diameter>
+
+	<application id="1" type="auth" name="Network Access">
+		<!-- Diameter Network Access Server Application -->
+		<!-- http://tools.ietf.org/html/rfc7155 -->
+
+		<command code="265" short="AA" name="AA">
 			<request>
-				<!-- http://tools.ietf.org/html/rfc7155#section-3.3 -->
+				<!-- https://tools.ietf.org/html/rfc7155#section-3.1 -->
 				<rule avp="Session-Id" required="true" max="1"/>
+				<rule avp="Auth-Application-Id" required="true" max="1"/>
 				<rule avp="Origin-Host" required="true" max="1"/>
 				<rule avp="Origin-Realm" required="true" max="1"/>
 				<rule avp="Destination-Realm" required="true" max="1"/>
-				<rule avp="Destination-Host" required="true" max="1"/>
-				<rule avp="Auth-Application-Id" required="true" max="1"/>
-				<rule avp="Re-Auth-Request-Type" required="true" max="1"/>
-				<rule avp="User-Name" required="false" max="1"/>
-				<rule avp="Origin-AAA-Protocol" required="false" max="1"/>
-				<rule avp="Origin-State-Id" required="false" max="1"/>
+				<rule avp="Auth-Request-Type" required="true" max="1"/>
+				<rule avp="Destination-Host" required="false" max="1"/>
 				<rule avp="NAS-Identifier" required="false" max="1"/>
 				<rule avp="NAS-IP-Address" required="true" max="1"/>
 				<rule avp="NAS-IPv6-Address" required="false" max="1"/>
 				<rule avp="NAS-Port" required="false" max="1"/>
 				<rule avp="NAS-Port-Id" required="false" max="1"/>
 				<rule avp="NAS-Port-Type" required="false" max="1"/>
+				<rule avp="Origin-AAA-Protocol" required="false" max="1"/>
+				<rule avp="Origin-State-Id" required="false" max="1"/>
+				<rule avp="Port-Limit" required="false" max="1"/>
+				<rule avp="User-Name" required="false" max="1"/>
+				<rule avp="User-Password" required="false" max="1"/>
 				<rule avp="Service-Type" required="false" max="1"/>
-				<rule avp="Framed-IP-Address" required="false" max="1"/>
-				<rule avp="Framed-IPv6-Prefix" required="false" max="1"/>
-				<rule avp="Framed-Interface-Id" required="false" max="1"/>
+				<rule avp="State" required="false" max="1"/>
+				<rule avp="Authorization-Lifetime" required="false" max="1"/>
+				<rule avp="Auth-Grace-Period" required="false" max="1"/>
+				<rule avp="Auth-Session-State" required="false" max="1"/>
+				<rule avp="Callback-Number" required="false" max="1"/>
 				<rule avp="Called-Station-Id" required="false" max="1"/>
 				<rule avp="Calling-Station-Id" required="false" max="1"/>
 				<rule avp="Originating-Line-Info" required="false" max="1"/>
-				<rule avp="Acct-Session-Id" required="false" max="1"/>
-				<rule avp="Acct-Multi-Session-Id" required="false" max="1"/>
-				<rule avp="State" required="false" max="1"/>
-				<rule avp="Class" required="false"/>
-				<rule avp="Reply-Message" required="false" max="1"/>
+				<rule avp="Connect-Info" required="false" max="1"/>
+				<rule avp="CHAP-Auth" required="false" max="1"/>
+				<rule avp="CHAP-Challenge" required="false" max="1"/>
+				<rule avp="Framed-Compression" required="false"/>
+				<rule avp="Framed-Interface-Id" required="false" max="1"/>
+				<rule avp="Framed-IP-Address" required="false" max="1"/>
+				<rule avp="Framed-IPv6-Prefix" required="false"/>
+				<rule avp="Framed-IP-Netmask" required="false" max="1"/>
+				<rule avp="Framed-MTU" required="false" max="1"/>
+				<rule avp="Framed-Protocol" required="false" max="1"/>
+				<rule avp="ARAP-Password" required="false" max="1"/>
+				<rule avp="ARAP-Security" required="false" max="1"/>
+				<rule avp="ARAP-Security-Data" required="false"/>
+				<rule avp="Login-IP-Host" required="false"/>
+				<rule avp="Login-IPv6-Host" required="false"/>
+				<rule avp="Login-LAT-Group" required="false" max="1"/>
+				<rule avp="Login-LAT-Node" required="false" max="1"/>
+				<rule avp="Login-LAT-Port" required="false" max="1"/>
+				<rule avp="Login-LAT-Service" required="false" max="1"/>
+				<rule avp="Tunneling" required="false"/>
 				<rule avp="Proxy-Info" required="false"/>
 				<rule avp="Route-Record" required="false"/>
 			</request>
 			<answer>
-				<!-- http://tools.ietf.org/html/rfc7155#section-3.4 -->
+				<!-- http://tools.ietf.org/html/rfc7155#section-3.2 -->
+				<rule avp="Session-Id" required="true" max="1"/>
+				<rule avp="Auth-Application-Id" required="true" max="1"/>
+				<rule avp="Auth-Request-Type" required="true" max="1"/>
+				<rule avp="Result-Code" required="true" max="1"/>
+				<rule avp="Origin-Host" required="true" max="1"/>
+				<rule avp="Origin-Realm" required="true" max="1"/>
+				<rule avp="User-Name" required="false" max="1"/>
+				<rule avp="Service-Type" required="false" max="1"/>
+				<rule avp="Class" required="false"/>
+				<rule avp="Configuration-Token" required="false"/>
+				<rule avp="Acct-Interim-Interval" required="false" max="1"/>
+				<rule avp="Error-Message" required="false" max="1"/>
+				<rule avp="Error-Reporting-Host" required="false" max="1"/>
+				<rule avp="Failed-AVP" required="false"/>
+				<rule avp="Idle-Timeout" required="false" max="1"/>
+				<rule avp="Authorization-Lifetime" required="false" max="1"/>
+				<rule avp="Auth-Grace-Period" required="false" max="1"/>
+				<rule avp="Auth-Session-State" required="false" max="1"/>
+				<rule avp="Re-Auth-Request-Type" required="false" max="1"/>
+				<rule avp="Multi-Round-Time-Out" required="false" max="1"/>
+				<rule avp="Session-Timeout" required="false" max="1"/>
+				<rule avp="State" required="false" max="1"/>
+				<rule avp="Reply-Message" required="false"/>
+				<rule avp="Origin-AAA-Protocol" required="false" max="1"/>
+				<rule avp="Origin-State-Id" required="false" max="1"/>
+				<rule avp="Filter-Id" required="false"/>
+				<rule avp="Password-Retry" required="false" max="1"/>
+				<rule avp="Port-Limit" required="false" max="1"/>
+				<rule avp="Prompt" required="false" max="1"/>
+				<rule avp="ARAP-Challenge-Response" required="false" max="1"/>
+				<rule avp="ARAP-Features" required="false" max="1"/>
+				<rule avp="ARAP-Security" required="false" max="1"/>
+				<rule avp="ARAP-Security-Data" required="false"/>
+				<rule avp="ARAP-Zone-Access" required="false" max="1"/>
+				<rule avp="Callback-Id" required="false" max="1"/>
+				<rule avp="Callback-Number" required="false" max="1"/>
+				<rule avp="Framed-Appletalk-Link" required="false" max="1"/>
+				<rule avp="Framed-Appletalk-Network" required="false"/>
+				<rule avp="Framed-Appletalk-Zone" required="false" max="1"/>
+				<rule avp="Framed-Compression" required="false"/>
+				<rule avp="Framed-Interface-Id" required="false" max="1"/>
+				<rule avp="Framed-IP-Address" required="false" max="1"/>
+				<rule avp="Framed-IPv6-Prefix" required="false"/>
+				<rule avp="Framed-IPv6-Pool" required="false" max="1"/>
+				<rule avp="Framed-IPv6-Route" required="false"/>
+				<rule avp="Framed-IP-Netmask" required="false" max="1"/>
+				<rule avp="Framed-Route" required="false"/>
+				<rule avp="Framed-Pool" required="false" max="1"/>
+				<rule avp="Framed-IPX-Network" required="false" max="1"/>
+				<rule avp="Framed-MTU" required="false" max="1"/>
+				<rule avp="Framed-Protocol" required="false" max="1"/>
+				<rule avp="Framed-Routing" required="false" max="1"/>
+				<rule avp="Login-IP-Host" required="false"/>
+				<rule avp="Login-IPv6-Host" required="false"/>
+				<rule avp="Login-LAT-Group" required="false" max="1"/>
+				<rule avp="Login-LAT-Node" required="false" max="1"/>
+				<rule avp="Login-LAT-Port" required="false" max="1"/>
+				<rule avp="Login-LAT-Service" required="false" max="1"/>
+				<rule avp="Login-Service" required="false" max="1"/>
+				<rule avp="Login-TCP-Port" required="false" max="1"/>
+				<rule avp="NAS-Filter-Rule" required="false"/>
+				<rule avp="QoS-Filter-Rule" required="false"/>
+				<rule avp="Tunneling" required="false"/>
+				<rule avp="Redirect-Host" required="false"/>
+				<rule avp="Redirect-Host-Usage" required="false" max="1"/>
+				<rule avp="Redirect-Max-Cache-Time" required="false" max="1"/>
+				<rule avp="Proxy-Info" required="false"/>
+			</answer>
+		</command>
+
+		<command code="258" short="RA" name="Re-Auth">
+			<request>
+				<!-- http://tools.ietf.org/html/rfc7155#section-3.3 -->
+				<rule avp="Session-Id" required="true" max="1"/>
+				<rule avp="Origin-Host" required="true" max="1"/>
+				<rule avp="Origin-Realm" required="true" max="1"/>
+				<rule avp="Destination-Realm" required="true" max="1"/>
+				<rule avp="Destination-Host" required="true" max="1"/>
+				<rule avp="Auth-Application-Id" required="true" max="1"/>
+				<rule avp="Re-Auth-Request-Type" required="true" max="1"/>
+				<rule avp="User-Name" required="false" max="1"/>
+				<rule avp="Origin-AAA-Protocol" required="false" max="1"/>
+				<rule avp="Origin-State-Id" required="false" max="1"/>
+				<rule avp="NAS-Identifier" required="false" max="1"/>
+				<rule avp="NAS-IP-Address" required="true" max="1"/>
+				<rule avp="NAS-IPv6-Address" required="false" max="1"/>
+				<rule avp="NAS-Port" required="false" max="1"/>
+				<rule avp="NAS-Port-Id" required="false" max="1"/>
+				<rule avp="NAS-Port-Type" required="false" max="1"/>
+				<rule avp="Service-Type" required="false" max="1"/>
+				<rule avp="Framed-IP-Address" required="false" max="1"/>
+				<rule avp="Framed-IPv6-Prefix" required="false" max="1"/>
+				<rule avp="Framed-Interface-Id" required="false" max="1"/>
+				<rule avp="Called-Station-Id" required="false" max="1"/>
+				<rule avp="Calling-Station-Id" required="false" max="1"/>
+				<rule avp="Originating-Line-Info" required="false" max="1"/>
+				<rule avp="Accounting-Session-Id" required="false" max="1"/>
+				<rule avp="Acct-Multi-Session-Id" required="false" max="1"/>
+				<rule avp="State" required="false" max="1"/>
+				<rule avp="Class" required="false"/>
+				<rule avp="Reply-Message" required="false" max="1"/>
+				<rule avp="Proxy-Info" required="false"/>
+				<rule avp="Route-Record" required="false"/>
+			</request>
+			<answer>
+				<!-- http://tools.ietf.org/html/rfc7155#section-3.4 -->
 				<rule avp="Session-Id" required="true" max="1"/>
 				<rule avp="Result-Code" required="true" max="1"/>
 				<rule avp="Origin-Host" required="true" max="1"/>
@@ -1748,7 +2311,7 @@ var networkaccessserverXML = `<?xml version="1.0" encoding="UTF-8"?>
 				<rule avp="Called-Station-Id" required="false" max="1"/>
 				<rule avp="Calling-Station-Id" required="false" max="1"/>
 				<rule avp="Originating-Line-Info" required="false" max="1"/>
-				<rule avp="Acct-Session-Id" required="false" max="1"/>
+				<rule avp="Accounting-Session-Id" required="false" max="1"/>
 				<rule avp="Acct-Multi-Session-Id" required="false" max="1"/>
 				<rule avp="State" required="false" max="1"/>
 				<rule avp="Class" required="false"/>
@@ -1788,7 +2351,7 @@ var networkaccessserverXML = `<?xml version="1.0" encoding="UTF-8"?>
 				<rule avp="Acct-Application-Id" required="true" max="1"/>
 				<rule avp="User-Name" required="false" max="1"/>
 				<rule avp="Accounting-Sub-Session-Id" required="false" max="1"/>
-				<rule avp="Acct-Session-Id" required="false" max="1"/>
+				<rule avp="Accounting-Session-Id" required="false" max="1"/>
 				<rule avp="Acct-Multi-Session-Id" required="false" max="1"/>
 				<rule avp="Origin-AAA-Protocol" required="false" max="1"/>
 				<rule avp="Origin-State-Id" required="false" max="1"/>
@@ -1818,7 +2381,7 @@ var networkaccessserverXML = `<?xml version="1.0" encoding="UTF-8"?>
 				<rule avp="Callback-Number" required="false" max="1"/>
 				<rule avp="Called-Station-Id" required="false" max="1"/>
 				<rule avp="Calling-Station-Id" required="false" max="1"/>
-				<rule avp="Connection-Info" required="false"/>
+				<rule avp="Connect-Info" required="false"/>
 				<rule avp="Originating-Line-Info" required="false" max="1"/>
 				<rule avp="Authorization-Lifetime" required="false" max="1"/>
 				<rule avp="Session-Timeout" required="false" max="1"/>
@@ -1868,7 +2431,7 @@ var networkaccessserverXML = `<?xml version="1.0" encoding="UTF-8"?>
 				<rule avp="Acct-Application-Id" required="true" max="1"/>
 				<rule avp="User-Name" required="false" max="1"/>
 				<rule avp="Accounting-Sub-Session-Id" required="false" max="1"/>
-				<rule avp="Acct-Session-Id" required="false" max="1"/>
+				<rule avp="Accounting-Session-Id" required="false" max="1"/>
 				<rule avp="Acct-Multi-Session-Id" required="false" max="1"/>
 				<rule avp="Event-Timestamp" required="false" max="1"/>
 				<rule avp="Error-Message" required="false" max="1"/>
@@ -1893,6 +2456,33 @@ var networkaccessserverXML = `<?xml version="1.0" encoding="UTF-8"?>
 
 
 
+		<avp name="NAS-Identifier" code="32" must="M" may="-" must-not="V" may-encrypt="Y">
+			<!-- http://tools.ietf.org/html/rfc7155#section-4 -->
+			<data type="UTF8String"/>
+		</avp>
+
+		<avp name="NAS-IP-Address" code="4" must="M" may="-" must-not="V" may-encrypt="Y">
+			<!-- http://tools.ietf.org/html/rfc7155#section-4 -->
+			<data type="OctetString"/>
+		</avp>
+
+		<avp name="NAS-IPv6-Address" code="95" must="M" may="-" must-not="V" may-encrypt="Y">
+			<!-- http://tools.ietf.org/html/rfc7155#section-4 -->
+			<data type="OctetString"/>
+		</avp>
+
+		<avp name="State" code="24" must="M" may="-" must-not="V" may-encrypt="Y">
+			<!-- http://tools.ietf.org/html/rfc7155#section-4 -->
+			<data type="OctetString"/>
+		</avp>
+
+		<avp name="Origin-AAA-Protocol" code="408" must="M" may="-" must-not="V" may-encrypt="Y">
+			<!-- http://tools.ietf.org/html/rfc7155#section-4 -->
+			<data type="Enumerated">
+				<item code="1" name="RADIUS"/>
+			</data>
+		</avp>
+
 		<avp name="NAS-Port" code="5" must="M" may="-" must-not="V" may-encrypt="Y">
 			<!-- http://tools.ietf.org/html/rfc7155#section-4.2.2 -->
 			<data type="Unsigned32"/>
@@ -2111,10 +2701,10 @@ var networkaccessserverXML = `<?xml version="1.0" encoding="UTF-8"?>
 			<data type="OctetString"/>
 		</avp>
 
-		<!--avp name="QoS-Filter-Rule" code="407" must="-" may="" must-not="-" may-encrypt="Y"-->
+		<avp name="QoS-Filter-Rule" code="407" must="M" may="P" must-not="V" may-encrypt="Y">
 			<!-- http://tools.ietf.org/html/rfc7155#section-4.4.9 -->
-			<!--data type="QoSFilterRule"/-->
-		<!--/avp-->
+			<data type="QoSFilterRule"/>
+		</avp>
 
 
 		<avp name="Framed-Protocol" code="7" must="M" may="-" must-not="V" may-encrypt="Y">
@@ -2692,7 +3282,7 @@ var tgpprorfXML = `<?xml version="1.0" encoding="UTF-8"?>
 			</data>
 		</avp>
 
-		<avp name="Application-Port-Identifer" code="3010" must="V,M" may="P" must-not="-" may-encrypt="N" vendor-id="10415">
+		<avp name="Application-Port-Identifier" code="3010" must="V,M" may="P" must-not="-" may-encrypt="N" vendor-id="10415">
 			<data type="Unsigned32"/>
 		</avp>
 
@@ -2716,7 +3306,7 @@ var tgpprorfXML = `<?xml version="1.0" encoding="UTF-8"?>
 			<data type="Grouped">
 				<rule avp="Application-Server" required="false" max="1"/>
 				<rule avp="Application-Provided-Called-Party-Address" required="false"/>
-				<rule avp="Status- AS-Code" required="false" max="1"/>
+				<rule avp="Status-AS-Code" required="false" max="1"/>
 			</data>
 		</avp>
 
@@ -3243,7 +3833,7 @@ var tgpprorfXML = `<?xml version="1.0" encoding="UTF-8"?>
 			<data type="Grouped">
 				<rule avp="ISUP-Cause-Location" required="false" max="1"/>
 				<rule avp="ISUP-Cause-Value" required="false" max="1"/>
-				<rule avp="ISUP-Cause-Diagnostic" required="false" max="1"/>
+				<rule avp="ISUP-Cause-Diagnostics" required="false" max="1"/>
 			</data>
 		</avp>
 
@@ -4099,11 +4689,16 @@ var tgpprorfXML = `<?xml version="1.0" encoding="UTF-8"?>
 			<data type="Enumerated">
 				<item code="0" name="WLAN"/>
 				<item code="1" name="VIRTUAL"/>
+				<item code="2" name="TRUSTED-N3GA"/>
+				<item code="3" name="WIRELINE"/>
 				<item code="1000" name="UTRAN"/>
 				<item code="1001" name="GERAN"/>
 				<item code="1002" name="GAN"/>
 				<item code="1003" name="HSPA_EVOLUTION"/>
 				<item code="1004" name="EUTRAN"/>
+				<item code="1005" name="EUTRAN-NB-IoT"/>
+				<item code="1006" name="NR"/>
+				<item code="1007" name="LTE-M"/>
 				<item code="2000" name="CDMA2000_1X"/>
 				<item code="2001" name="HRPD"/>
 				<item code="2002" name="UMB"/>
@@ -4756,7 +5351,7 @@ var tgpprorfXML = `<?xml version="1.0" encoding="UTF-8"?>
 				<rule avp="QoS-Information" required="false" max="1"/>
 				<rule avp="Accounting-Input-Octets" required="false" max="1"/>
 				<rule avp="Accounting-Output-Octets" required="false" max="1"/>
-				<rule avp="Change-condition" required="false" max="1"/>
+				<rule avp="Change-Condition" required="false" max="1"/>
 				<rule avp="Change-Time" required="false" max="1"/>
 				<rule avp="TGPP-User-Location-Info" required="false" max="1"/>
 				<rule avp="TGPP-Charging-Id" required="false" max="1"/>
@@ -4898,7 +5493,7 @@ var tgpprorfXML = `<?xml version="1.0" encoding="UTF-8"?>
 				<rule avp="ISUP-Location-Number" required="false" max="1"/>
 				<rule avp="VLR-Number" required="false" max="1"/>
 				<rule avp="Forwarding-Pending" required="false" max="1"/>
-				<rule avp="ISUP-Release-Cause" required="false" max="1"/>
+				<rule avp="ISUP-Cause" required="false" max="1"/>
 				<rule avp="Start-Time" required="false" max="1"/>
 				<rule avp="Start-of-Charging" required="false" max="1"/>
 				<rule avp="Stop-Time" required="false" max="1"/>
@@ -4946,1052 +5541,3065 @@ var tgpprorfXML = `<?xml version="1.0" encoding="UTF-8"?>
       <data type="Unsigned32"/>
     </avp>
 
-	</application>
-</diameter>`
+		<!-- 3GPP TS 29.061 Gi/SGi and Gy AVPs -->
 
-var tgpps6aXML = `<?xml version="1.0" encoding="UTF-8"?>
-<diameter>
-    <!--
-        3GPP TS 29.272
-        See: http://www.etsi.org/deliver/etsi_ts/129200_129299/129272/12.06.00_60/ts_129272v120600p.pdf
-    -->
-    <application id="16777251" type="auth" name="TGPP S6A">
-        <vendor id="10415" name="TGPP"/>
-        <command code="316" short="UL" name="Update-Location">
-            <request>
-                <rule avp="Session-Id" required="true" max="1"/>
-                <rule avp="Vendor-Specific-Application-Id" required="false" max="1"/>
-                <rule avp="Auth-Session-State" required="true" max="1"/>
-                <rule avp="Origin-Host" required="true" max="1"/>
-                <rule avp="Origin-Realm" required="true" max="1"/>
-                <rule avp="Destination-Host" required="false" max="1"/>
-                <rule avp="Destination-Realm" required="true" max="1"/>
-                <rule avp="User-Name" required="true" max="1"/>
-                <rule avp="Supported-Features" required="false"/>
-                <rule avp="Terminal-Information" required="false" max="1"/>
-                <rule avp="RAT-Type" required="true" max="1"/>
-                <rule avp="ULR-Flags" required="true" max="1"/>
-                <rule avp="UE-SRVCC-Capability" required="false" max="1"/>
-                <rule avp="Visited-PLMN-Id" required="true" max="1"/>
-                <rule avp="SGSN-Number" required="false" max="1"/>
-                <rule avp="Homogeneous-Support-of-IMS-Voice-Over-PS-Sessions" required="false" max="1"/>
-                <rule avp="GMLC-Address" required="false" max="1"/>
-                <rule avp="Active-APN" required="false"/>
-                <rule avp="AVP" required="false"/>
-                <rule avp="Proxy-Info" required="false"/>
-                <rule avp="Route-Record" required="false"/>
-            </request>
-            <answer>
-                <rule avp="Session-Id" required="true" max="1"/>
-                <rule avp="Vendor-Specific-Application-Id" required="false" max="1"/>
-                <rule avp="Result-Code" required="false" max="1"/>
-                <rule avp="Experimental-Result" required="false" max="1"/>
-                <rule avp="Error-Diagnostic" required="false" max="1"/>
-                <rule avp="Auth-Session-State" required="true" max="1"/>
-                <rule avp="Origin-Host" required="true" max="1"/>
-                <rule avp="Origin-Realm" required="true" max="1"/>
-                <rule avp="Supported-Features" required="false"/>
-                <rule avp="ULA-Flags" required="false" max="1"/>
-                <rule avp="Subscription-Data" required="false" max="1"/>
-                <rule avp="AVP" required="false"/>
-                <rule avp="Failed-AVP" required="false"/>
-                <rule avp="Proxy-Info" required="false"/>
-                <rule avp="Route-Record" required="false"/>
-            </answer>
-        </command>
+		<avp name="TGPP-CG-Address" code="4" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+			<data type="OctetString"/>
+		</avp>
 
-        <command code="317" short="CL" name="Cancel-Location">
-            <request>
-                <rule avp="Session-Id" required="true" max="1"/>
-                <rule avp="Vendor-Specific-Application-Id" required="false" max="1"/>
-                <rule avp="Auth-Session-State" required="true" max="1"/>
-                <rule avp="Origin-Host" required="true" max="1"/>
-                <rule avp="Origin-Realm" required="true" max="1"/>
-                <rule avp="Destination-Host" required="true" max="1"/>
-                <rule avp="Destination-Realm" required="true" max="1"/>
-                <rule avp="User-Name" required="true" max="1"/>
-                <rule avp="Supported-Features" required="false"/>
-                <rule avp="Cancellation-Type" required="true" max="1"/>
-                <rule avp="CLR-Flags" required="false" max="1"/>
-                <rule avp="AVP" required="false"/>
-                <rule avp="Proxy-Info" required="false"/>
-                <rule avp="Route-Record" required="false"/>
-            </request>
-            <answer>
-                <rule avp="Session-Id" required="true" max="1"/>
-                <rule avp="Vendor-Specific-Application-Id" required="false" max="1"/>
-                <rule avp="Supported-Features" required="false"/>
-                <rule avp="Result-Code" required="false" max="1"/>
-                <rule avp="Experimental-Result" required="false" max="1"/>
-                <rule avp="Auth-Session-State" required="true" max="1"/>
-                <rule avp="Origin-Host" required="true" max="1"/>
-                <rule avp="Origin-Realm" required="true" max="1"/>
-                <rule avp="AVP" required="false"/>
-                <rule avp="Failed-AVP" required="false"/>
-                <rule avp="Proxy-Info" required="false"/>
-                <rule avp="Route-Record" required="false"/>
-            </answer>
-        </command>
+		<avp name="TGPP-GPRS-Negotiated-QoS-Profile" code="5" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+			<data type="UTF8String"/>
+		</avp>
 
-        <command code="318" short="AI" name="Authentication-Information">
-            <request>
-                <rule avp="Session-Id" required="true" max="1"/>
-                <rule avp="Vendor-Specific-Application-Id" required="false" max="1"/>
-                <rule avp="Auth-Session-State" required="true" max="1"/>
-                <rule avp="Origin-Host" required="true" max="1"/>
-                <rule avp="Origin-Realm" required="true" max="1"/>
-                <rule avp="Destination-Host" required="false" max="1"/>
-                <rule avp="Destination-Realm" required="true" max="1"/>
-                <rule avp="User-Name" required="true" max="1"/>
-                <rule avp="Supported-Features" required="false"/>
-                <rule avp="Requested-EUTRAN-Authentication-Info" required="false" max="1"/>
-                <rule avp="Requested-UTRAN-GERAN-Authentication-Info" required="false" max="1"/>
-                <rule avp="Visited-PLMN-Id" required="true" max="1"/>
-                <rule avp="AVP" required="false"/>
-                <rule avp="Proxy-Info" required="false"/>
-                <rule avp="Route-Record" required="false"/>
-            </request>
-            <answer>
-                <rule avp="Session-Id" required="true" max="1"/>
-                <rule avp="Vendor-Specific-Application-Id" required="false" max="1"/>
-                <rule avp="Result-Code" required="false" max="1"/>
-                <rule avp="Experimental-Result" required="false" max="1"/>
-                <rule avp="Error-Diagnostic" required="false" max="1"/>
-                <rule avp="Auth-Session-State" required="true" max="1"/>
-                <rule avp="Origin-Host" required="true" max="1"/>
-                <rule avp="Origin-Realm" required="true" max="1"/>
-                <rule avp="Supported-Features" required="false"/>
-                <rule avp="Authentication-Info" required="true" max="1"/>
-                <rule avp="AVP" required="false"/>
-                <rule avp="Failed-AVP" required="false"/>
-                <rule avp="Proxy-Info" required="false"/>
-                <rule avp="Route-Record" required="false"/>
-            </answer>
-        </command>
+		<avp name="TGPP-SGSN-Address" code="6" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+			<data type="OctetString"/>
+		</avp>
 
-        <command code="321" short="PU" name="Purge-UE">
-            <!--
-                < Purge-UE-Request> ::=	< Diameter Header: 321, REQ, PXY, 16777251 >
-                < Session-Id >
-                [ DRMP ]
-                [ Vendor-Specific-Application-Id ]
-                { Auth-Session-State }
-                { Origin-Host }
-                { Origin-Realm }
-                [ Destination-Host ]
-                { Destination-Realm }
-                { User-Name }
-                [ OC-Supported-Features ]
-                [ PUR-Flags ]
-                *[ Supported-Features ]
-                [ EPS-Location-Information ]
-                *[ AVP ]
-                *[ Proxy-Info ]
-                *[ Route-Record ]
-            -->
+		<avp name="TGPP-GGSN-Address" code="7" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+			<data type="OctetString"/>
+		</avp>
+
+		<avp name="TGPP-CG-IPv6-Address" code="14" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+			<data type="OctetString"/>
+		</avp>
+
+		<avp name="TGPP-SGSN-IPv6-Address" code="15" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+			<data type="OctetString"/>
+		</avp>
+
+		<avp name="TGPP-GGSN-IPv6-Address" code="16" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+			<data type="OctetString"/>
+		</avp>
+
+		<avp name="TGPP-IPv6-DNS-Servers" code="17" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+			<data type="OctetString"/>
+		</avp>
+
+		<avp name="TGPP-IMEISV" code="20" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+			<data type="OctetString"/>
+		</avp>
+
+		<avp name="TGPP-CAMEL-Charging-Info" code="24" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+			<data type="OctetString"/>
+		</avp>
+
+		<avp name="TGPP-Packet-Filter" code="25" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+			<data type="OctetString"/>
+		</avp>
+
+		<avp name="TGPP-Negotiated-DSCP" code="26" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+			<data type="OctetString"/>
+		</avp>
+
+		<avp name="TGPP-Allocate-IP-Type" code="27" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+			<data type="OctetString"/>
+		</avp>
+
+		<avp name="TWAN-Identifier" code="29" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+			<data type="OctetString"/>
+		</avp>
+
+		<!-- 3GPP TS 32.299 Multiple-Services-Credit-Control extensions -->
+
+		<avp name="Announcement-Information" code="3904" must="V" may="P" must-not="M" may-encrypt="N" vendor-id="10415">
+			<data type="Grouped">
+				<rule avp="Announcement-Identifier" required="true" max="1"/>
+				<rule avp="Variable-Part" required="false"/>
+				<rule avp="Time-Indicator" required="false" max="1"/>
+				<rule avp="Quota-Indicator" required="false" max="1"/>
+				<rule avp="Announcement-Order" required="false" max="1"/>
+				<rule avp="Play-Alternative" required="false" max="1"/>
+				<rule avp="Privacy-Indicator" required="false" max="1"/>
+				<rule avp="Language" required="false" max="1"/>
+			</data>
+		</avp>
+
+		<avp name="Announcement-Identifier" code="3905" must="V" may="P" must-not="M" may-encrypt="N" vendor-id="10415">
+			<data type="Unsigned32"/>
+		</avp>
+
+		<avp name="Announcement-Order" code="3906" must="V" may="P" must-not="M" may-encrypt="N" vendor-id="10415">
+			<data type="Unsigned32"/>
+		</avp>
+
+		<avp name="Variable-Part" code="3907" must="V" may="P" must-not="M" may-encrypt="N" vendor-id="10415">
+			<data type="Grouped">
+				<rule avp="Variable-Part-Type" required="true" max="1"/>
+				<rule avp="Variable-Part-Value" required="true" max="1"/>
+				<rule avp="Variable-Part-Order" required="false" max="1"/>
+			</data>
+		</avp>
+
+		<avp name="Variable-Part-Order" code="3908" must="V" may="P" must-not="M" may-encrypt="N" vendor-id="10415">
+			<data type="Unsigned32"/>
+		</avp>
+
+		<avp name="Variable-Part-Type" code="3909" must="V" may="P" must-not="M" may-encrypt="N" vendor-id="10415">
+			<data type="Unsigned32"/>
+		</avp>
+
+		<avp name="Variable-Part-Value" code="3910" must="V" may="P" must-not="M" may-encrypt="N" vendor-id="10415">
+			<data type="UTF8String"/>
+		</avp>
+
+		<avp name="Time-Indicator" code="3911" must="V" may="P" must-not="M" may-encrypt="N" vendor-id="10415">
+			<data type="Unsigned32"/>
+		</avp>
+
+		<avp name="Quota-Indicator" code="3912" must="V" may="P" must-not="M" may-encrypt="N" vendor-id="10415">
+			<data type="Enumerated">
+				<item code="0" name="QUOTA_IS_NOT_USED_DURING_PLAYBACK"/>
+				<item code="1" name="QUOTA_IS_USED_DURING_PLAYBACK"/>
+			</data>
+		</avp>
+
+		<avp name="Play-Alternative" code="3913" must="V" may="P" must-not="M" may-encrypt="N" vendor-id="10415">
+			<data type="Enumerated">
+				<item code="0" name="SERVED_PARTY"/>
+				<item code="1" name="REMOTE_PARTY"/>
+			</data>
+		</avp>
+
+		<avp name="Language" code="3914" must="V" may="P" must-not="M" may-encrypt="N" vendor-id="10415">
+			<data type="UTF8String"/>
+		</avp>
+
+		<avp name="Privacy-Indicator" code="3915" must="V" may="P" must-not="M" may-encrypt="N" vendor-id="10415">
+			<data type="Enumerated">
+				<item code="0" name="NOT_PRIVATE"/>
+				<item code="1" name="PRIVATE"/>
+			</data>
+		</avp>
+
+		<avp name="Related-Trigger" code="3926" must="V" may="P" must-not="M" may-encrypt="N" vendor-id="10415">
+			<data type="Grouped">
+				<rule avp="Trigger-Type" required="false"/>
+			</data>
+		</avp>
+
+		<!-- Service-Information AVPs -->
+
+		<avp name="Service-Generic-Information" code="1256" must="V,M" may="P" must-not="-" may-encrypt="N" vendor-id="10415">
+			<data type="Grouped">
+				<rule avp="Application-Server-Id" required="false" max="1"/>
+				<rule avp="Application-Service-Type" required="false" max="1"/>
+				<rule avp="Application-Session-Id" required="false" max="1"/>
+				<rule avp="Delivery-Status" required="false" max="1"/>
+			</data>
+		</avp>
+
+		<avp name="Application-Service-Type" code="2102" must="V,M" may="P" must-not="-" may-encrypt="N" vendor-id="10415">
+			<data type="Enumerated">
+				<item code="100" name="SENDING"/>
+				<item code="101" name="RECEIVING"/>
+				<item code="102" name="RETRIEVAL"/>
+				<item code="103" name="INVITING"/>
+				<item code="104" name="LEAVING"/>
+				<item code="105" name="JOINING"/>
+			</data>
+		</avp>
+
+		<avp name="IM-Information" code="2110" must="V,M" may="P" must-not="-" may-encrypt="N" vendor-id="10415">
+			<data type="Grouped">
+				<rule avp="Total-Number-Of-Messages-Sent" required="false" max="1"/>
+				<rule avp="Total-Number-Of-Messages-Exploded" required="false" max="1"/>
+				<rule avp="Number-Of-Messages-Successfully-Sent" required="false" max="1"/>
+				<rule avp="Number-Of-Messages-Successfully-Exploded" required="false" max="1"/>
+			</data>
+		</avp>
+
+		<avp name="DCD-Information" code="2115" must="V,M" may="P" must-not="-" may-encrypt="N" vendor-id="10415">
+			<data type="Grouped">
+				<rule avp="Content-Id" required="false" max="1"/>
+				<rule avp="Content-Provider-Id" required="false" max="1"/>
+			</data>
+		</avp>
+
+		<avp name="Flow-Number" code="509" must="V,M" may="P" must-not="-" may-encrypt="Y" vendor-id="10415">
+			<data type="Unsigned32"/>
+		</avp>
+
+		<avp name="Media-Component-Number" code="518" must="V,M" may="P" must-not="-" may-encrypt="Y" vendor-id="10415">
+			<data type="Unsigned32"/>
+		</avp>
+
+		<avp name="TGPP-AAA-Server-Name" code="318" must="V,M" may="-" must-not="-" may-encrypt="N" vendor-id="10415">
+			<data type="DiameterIdentity"/>
+		</avp>
+
+		<avp name="MSC-Number" code="2403" must="V,M" may="-" must-not="-" may-encrypt="N" vendor-id="10415">
+			<data type="OctetString"/>
+		</avp>
+
+		<avp name="LCS-Capabilities-Sets" code="2404" must="V" may="P" must-not="M" may-encrypt="N" vendor-id="10415">
+			<data type="Unsigned32"/>
+		</avp>
+
+		<avp name="SGSN-Name" code="2409" must="V" may="P" must-not="M" may-encrypt="N" vendor-id="10415">
+			<data type="DiameterIdentity"/>
+		</avp>
+
+		<avp name="SGSN-Realm" code="2410" must="V" may="P" must-not="M" may-encrypt="N" vendor-id="10415">
+			<data type="DiameterIdentity"/>
+		</avp>
+
+		<avp name="TGPP2-MEID" code="1471" must="V,M" may="-" must-not="-" may-encrypt="N" vendor-id="10415">
+			<data type="OctetString"/>
+		</avp>
+
+		<avp name="TGPP2-BSID" code="9010" must="V,M" may="P" must-not="-" may-encrypt="Y" vendor-id="5535">
+			<data type="OctetString"/>
+		</avp>
+
+		<avp name="Logical-Access-Id" code="302" must="V" may="M" must-not="-" may-encrypt="N" vendor-id="13019">
+			<!-- ETSI TS 283 034 -->
+			<data type="OctetString"/>
+		</avp>
+
+		<avp name="Physical-Access-Id" code="313" must="V" may="M" must-not="-" may-encrypt="N" vendor-id="13019">
+			<!-- ETSI TS 283 034 -->
+			<data type="UTF8String"/>
+		</avp>
+
+		<avp name="Conditional-APN-Aggregate-Max-Bitrate" code="2818" must="V" may="P" must-not="M" may-encrypt="N" vendor-id="10415">
+			<data type="Grouped">
+				<rule avp="APN-Aggregate-Max-Bitrate-UL" required="false" max="1"/>
+				<rule avp="APN-Aggregate-Max-Bitrate-DL" required="false" max="1"/>
+				<rule avp="Extended-APN-AMBR-UL" required="false" max="1"/>
+				<rule avp="Extended-APN-AMBR-DL" required="false" max="1"/>
+				<rule avp="IP-CAN-Type" required="false"/>
+				<rule avp="RAT-Type" required="false"/>
+			</data>
+		</avp>
+
+		<avp name="Extended-APN-AMBR-DL" code="2848" must="V" may="P" must-not="M" may-encrypt="N" vendor-id="10415">
+			<data type="Unsigned32"/>
+		</avp>
+
+		<avp name="Extended-APN-AMBR-UL" code="2849" must="V" may="P" must-not="M" may-encrypt="N" vendor-id="10415">
+			<data type="Unsigned32"/>
+		</avp>
+
+		<avp name="IP-CAN-Type" code="1027" must="V,M" may="P" must-not="-" may-encrypt="Y" vendor-id="10415">
+			<data type="Enumerated">
+				<item code="0" name="3GPP-GPRS"/>
+				<item code="1" name="DOCSIS"/>
+				<item code="2" name="xDSL"/>
+				<item code="3" name="WiMAX"/>
+				<item code="4" name="3GPP2"/>
+				<item code="5" name="3GPP-EPS"/>
+				<item code="6" name="Non-3GPP-EPS"/>
+				<item code="7" name="FBA"/>
+				<item code="8" name="3GPP-5GS"/>
+				<item code="9" name="Non-3GPP-5GS"/>
+			</data>
+		</avp>
+
+		<avp name="Presence-Reporting-Area-Elements-List" code="2820" must="V" may="P" must-not="M" may-encrypt="N" vendor-id="10415">
+			<data type="OctetString"/>
+		</avp>
+
+	</application>
+</diameter>`
+
+var tgppcxXML = `<?xml version="1.0" encoding="UTF-8"?>
+<diameter>
+    <!--
+        3GPP TS 29.228 and 3GPP TS 29.229
+        See: http://www.etsi.org/deliver/etsi_ts/129200_129299/129229/12.06.00_60/ts_129229v120600p.pdf
+    -->
+    <application id="16777216" type="auth" name="TGPP Cx">
+        <vendor id="10415" name="TGPP"/>
+        <command code="300" short="UA" name="User-Authorization">
             <request>
-                <rule avp="Session-Id" required="true" max="1" />
-                <rule avp="DRMP" required="false" max="1" />
-                <rule avp="Vendor-Specific-Application-Id" required="false" max="1" />
-                <rule avp="Auth-Session-State" required="true" max="1" />
-                <rule avp="Origin-Host" required="true" max="1" />
-                <rule avp="Origin-Realm" required="true" max="1" />
-                <rule avp="Destination-Host" required="false" max="1" />
-                <rule avp="Destination-Realm" required="true" max="1" />
-                <rule avp="User-Name" required="true" max="1" />
-                <rule avp="OC-Supported-Features" required="false" max="1" />
-                <rule avp="PUR-Flags" required="false" max="1" />
-                <rule avp="Supported-Features" required="false" />
-                <rule avp="EPS-Location-Information" required="false" max="1" />
-                <rule avp="Proxy-Info" required="false" />
-                <rule avp="Route-Record" required="false" />
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Vendor-Specific-Application-Id" required="true" max="1"/>
+                <rule avp="Auth-Session-State" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Destination-Host" required="false" max="1"/>
+                <rule avp="Destination-Realm" required="true" max="1"/>
+                <rule avp="User-Name" required="true" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="Public-Identity" required="true" max="1"/>
+                <rule avp="Visited-Network-Identifier" required="true" max="1"/>
+                <rule avp="User-Authorization-Type" required="false" max="1"/>
+                <rule avp="UAR-Flags" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
             </request>
-
-            <!--
-                < Purge-UE-Answer> ::=	< Diameter Header: 321, PXY, 16777251 >
-                < Session-Id >
-                [ DRMP ]
-                [ Vendor-Specific-Application-Id ]
-                *[ Supported-Features ]
-                [ Result-Code ]
-                [ Experimental-Result ]
-                { Auth-Session-State }
-                { Origin-Host }
-                { Origin-Realm }
-                [ OC-Supported-Features ]
-                [ OC-OLR ]
-                *[ Load ]
-                [ PUA-Flags ]
-                *[ AVP ]
-                [ Failed-AVP ]
-                *[ Proxy-Info ]
-                *[ Route-Record ]
-            -->
             <answer>
-                <rule avp="Session-Id" required="true" max="1" />
-                <rule avp="DRMP" required="false" max="1" />
-                <rule avp="Vendor-Specific-Application-Id" required="false" max="1" />
-                <rule avp="Supported-Features" required="false" />
-                <rule avp="Result-Code" required="false" max="1" />
-                <rule avp="Experimental-Result" required="false" max="1" />
-                <rule avp="Auth-Session-State" required="true" max="1" />
-                <rule avp="Origin-Host" required="true" max="1" />
-                <rule avp="Origin-Realm" required="true" max="1" />
-                <rule avp="OC-Supported-Features" required="false" max="1" />
-                <rule avp="OC-OLR" required="false" max="1" />
-                <!-- rule avp="Load" required="false" /-->
-                <rule avp="PUA-Flags" required="false" max="1" />
-                <rule avp="Failed-AVP" required="false" max="1" />
-                <rule avp="Proxy-Info" required="false" />
-                <rule avp="Route-Record" required="false" />
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Vendor-Specific-Application-Id" required="true" max="1"/>
+                <rule avp="Result-Code" required="false" max="1"/>
+                <rule avp="Experimental-Result" required="false" max="1"/>
+                <rule avp="Auth-Session-State" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="Server-Name" required="false" max="1"/>
+                <rule avp="Server-Capabilities" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+                <rule avp="Failed-AVP" required="false"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
             </answer>
         </command>
-
-        <command code="323" short="NO" name="Notify">
-            <!--
-                < Notify-Request> ::=	< Diameter Header: 323, REQ, PXY, 16777251 >
-                < Session-Id >
-                [ Vendor-Specific-Application-Id ]
-                [ DRMP ]
-                { Auth-Session-State }
-                { Origin-Host }
-                { Origin-Realm }
-                [ Destination-Host ]
-                { Destination-Realm }
-                { User-Name }
-                [ OC-Supported-Features ]
-                * [ Supported-Features ]
-                [ Terminal-Information ]
-                [ MIP6-Agent-Info ]
-                [ Visited-Network-Identifier ]
-                [ Context-Identifier ]
-                [ Service-Selection ]
-                [ Alert-Reason ]
-                [ UE-SRVCC-Capability ]
-                [ NOR-Flags ]
-                [ Homogeneous-Support-of-IMS-Voice-Over-PS-Sessions ]
-                [ Maximum-UE-Availability-Time ]
-                *[ Monitoring-Event-Config-Status ]
-                [ Emergency-Services ]
-                *[ AVP ]
-                *[ Proxy-Info ]
-                *[ Route-Record ]
-            -->
+        <command code="301" short="SA" name="Server-Assignment">
             <request>
-                <rule avp="Session-Id" required="true" max="1" />
-                <rule avp="Vendor-Specific-Application-Id" required="false" max="1" />
-                <rule avp="DRMP" required="false" max="1" />
-                <rule avp="Auth-Session-State" required="true" max="1" />
-                <rule avp="Origin-Host" required="true" max="1" />
-                <rule avp="Origin-Realm" required="true" max="1" />
-                <rule avp="Destination-Host" required="false" max="1" />
-                <rule avp="Destination-Realm" required="true" max="1" />
-                <rule avp="User-Name" required="true" max="1" />
-                <rule avp="OC-Supported-Features" required="false" max="1" />
-                <rule avp="Supported-Features" required="false" />
-                <rule avp="Terminal-Information" required="false" max="1" />
-                <rule avp="MIP6-Agent-Info" required="false" max="1" />
-                <rule avp="Visited-Network-Identifier" required="false" max="1" />
-                <rule avp="Context-Identifier" required="false" max="1" />
-                <rule avp="Service-Selection" required="false" max="1" />
-                <rule avp="Alert-Reason" required="false" max="1" />
-                <rule avp="UE-SRVCC-Capability" required="false" max="1" />
-                <rule avp="NOR-Flags" required="false" max="1" />
-                <rule avp="Homogeneous-Support-of-IMS-Voice-Over-PS-Sessions" required="false" max="1" />
-                <rule avp="Maximum-UE-Availability-Type" required="false" max="1" />
-                <rule avp="Monitoring-Event-Config-Status" required="false" />
-                <rule avp="Emergency-Services" required="false" max="1" />
-                <rule avp="Proxy-Info" required="false" />
-                <rule avp="Route-Record" required="false" />
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Vendor-Specific-Application-Id" required="true" max="1"/>
+                <rule avp="Auth-Session-State" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Destination-Host" required="false" max="1"/>
+                <rule avp="Destination-Realm" required="true" max="1"/>
+                <rule avp="User-Name" required="false" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="Public-Identity" required="false"/>
+                <rule avp="Server-Name" required="true" max="1"/>
+                <rule avp="Server-Assignment-Type" required="true" max="1"/>
+                <rule avp="User-Data-Already-Available" required="true" max="1"/>
+                <rule avp="AVP" required="false"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
             </request>
-            <!--
-                < Notify-Answer> ::=	< Diameter Header: 323, PXY, 16777251 >
-                < Session-Id >
-                [ DRMP ]
-                [ Vendor-Specific-Application-Id ]
-                [ Result-Code ]
-                [ Experimental-Result ]
-                { Auth-Session-State }
-                { Origin-Host }
-                { Origin-Realm }
-                [ OC-Supported-Features ]
-                [ OC-OLR ]
-                *[ Load ]
-                *[ Supported-Features ]
-                *[ AVP ]
-                [ Failed-AVP ]
-                *[ Proxy-Info ]
-                *[ Route-Record ]
-            -->
             <answer>
-                <rule avp="Session-Id" required="true" max="1" />
-                <rule avp="DRMP" required="false" max="1" />
-                <rule avp="Vendor-Specific-Application-Id" required="false" max="1" />
-                <rule avp="Result-Code" required="false" max="1" />
-                <rule avp="Experimental-Result" required="false" max="1" />
-                <rule avp="Auth-Session-State" required="true" max="1" />
-                <rule avp="Origin-Host" required="true" max="1" />
-                <rule avp="Origin-Realm" required="true" max="1" />
-                <rule avp="OC-Supported-Features" required="false" max="1" />
-                <rule avp="OC-OLR" required="false" max="1" />
-                <!-- rule avp="Load" required="false" /-->
-                <rule avp="Supported-Features" required="false" />
-                <rule avp="Failed-AVP" required="false" max="1" />
-                <rule avp="Proxy-Info" required="false" />
-                <rule avp="Route-Record" required="false" />
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Vendor-Specific-Application-Id" required="true" max="1"/>
+                <rule avp="Result-Code" required="false" max="1"/>
+                <rule avp="Experimental-Result" required="false" max="1"/>
+                <rule avp="Auth-Session-State" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="User-Name" required="false" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="User-Data" required="false" max="1"/>
+                <rule avp="Charging-Information" required="false" max="1"/>
+                <rule avp="Server-Name" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+                <rule avp="Failed-AVP" required="false"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
+            </answer>
+        </command>
+        <command code="302" short="LI" name="Location-Info">
+            <request>
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Vendor-Specific-Application-Id" required="true" max="1"/>
+                <rule avp="Auth-Session-State" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Destination-Host" required="false" max="1"/>
+                <rule avp="Destination-Realm" required="true" max="1"/>
+                <rule avp="Originating-Request" required="false" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="Public-Identity" required="true" max="1"/>
+                <rule avp="User-Authorization-Type" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
+            </request>
+            <answer>
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Vendor-Specific-Application-Id" required="true" max="1"/>
+                <rule avp="Result-Code" required="false" max="1"/>
+                <rule avp="Experimental-Result" required="false" max="1"/>
+                <rule avp="Auth-Session-State" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="Server-Name" required="false" max="1"/>
+                <rule avp="Server-Capabilities" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+                <rule avp="Failed-AVP" required="false"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
+            </answer>
+        </command>
+        <command code="303" short="MA" name="Multimedia-Authentication">
+            <request>
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Vendor-Specific-Application-Id" required="true" max="1"/>
+                <rule avp="Auth-Session-State" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Destination-Realm" required="true" max="1"/>
+                <rule avp="Destination-Host" required="false" max="1"/>
+                <rule avp="User-Name" required="true" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="Public-Identity" required="true" max="1"/>
+                <rule avp="SIP-Auth-Data-Item" required="true" max="1"/>
+                <rule avp="SIP-Number-Auth-Items" required="true" max="1"/>
+                <rule avp="Server-Name" required="true" max="1"/>
+                <rule avp="AVP" required="false"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
+            </request>
+            <answer>
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Vendor-Specific-Application-Id" required="true" max="1"/>
+                <rule avp="Result-Code" required="false" max="1"/>
+                <rule avp="Experimental-Result" required="false" max="1"/>
+                <rule avp="Auth-Session-State" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="User-Name" required="false" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="Public-Identity" required="false" max="1"/>
+                <rule avp="SIP-Number-Auth-Items" required="false" max="1"/>
+                <rule avp="SIP-Auth-Data-Item" required="false"/>
+                <rule avp="AVP" required="false"/>
+                <rule avp="Failed-AVP" required="false"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
+            </answer>
+        </command>
+        <command code="304" short="RT" name="Registration-Termination">
+            <request>
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Vendor-Specific-Application-Id" required="true" max="1"/>
+                <rule avp="Auth-Session-State" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Destination-Host" required="true" max="1"/>
+                <rule avp="Destination-Realm" required="true" max="1"/>
+                <rule avp="User-Name" required="true" max="1"/>
+                <rule avp="Associated-Identities" required="false" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="Public-Identity" required="false"/>
+                <rule avp="Deregistration-Reason" required="true" max="1"/>
+                <rule avp="AVP" required="false"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
+            </request>
+            <answer>
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Vendor-Specific-Application-Id" required="true" max="1"/>
+                <rule avp="Result-Code" required="false" max="1"/>
+                <rule avp="Experimental-Result" required="false" max="1"/>
+                <rule avp="Auth-Session-State" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Associated-Identities" required="false" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="Identity-with-Emergency-Registration" required="false"/>
+                <rule avp="AVP" required="false"/>
+                <rule avp="Failed-AVP" required="false"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
+            </answer>
+        </command>
+        <command code="305" short="PP" name="Push-Profile">
+            <request>
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Vendor-Specific-Application-Id" required="true" max="1"/>
+                <rule avp="Auth-Session-State" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Destination-Host" required="true" max="1"/>
+                <rule avp="Destination-Realm" required="true" max="1"/>
+                <rule avp="User-Name" required="true" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="User-Data" required="false" max="1"/>
+                <rule avp="Charging-Information" required="false" max="1"/>
+                <rule avp="SIP-Auth-Data-Item" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
+            </request>
+            <answer>
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Vendor-Specific-Application-Id" required="true" max="1"/>
+                <rule avp="Result-Code" required="false" max="1"/>
+                <rule avp="Experimental-Result" required="false" max="1"/>
+                <rule avp="Auth-Session-State" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="AVP" required="false"/>
+                <rule avp="Failed-AVP" required="false"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
             </answer>
         </command>
 
-        <command code="322" short="RS" name="Reset">
-            <!--
-              < Reset-Request> ::= < Diameter Header: 322, REQ, PXY, 16777251 >
-                < Session-Id >
-                [ Vendor-Specific-Application-Id ]
-                { Auth-Session-State }
-                { Origin-Host }
-                { Origin-Realm }
-                { Destination-Host }
-                { Destination-Realm }
-                *[ Supported-Features ]
-                *[ User-Id ]
-                *[ AVP ]
-                *[ Proxy-Info ]
-                *[ Route-Record ]
-            -->
-            <request>
-                <rule avp="Session-Id" required="true" max="1" />
-                <rule avp="Vendor-Specific-Application-Id" required="false" max="1" />
-                <rule avp="Auth-Session-State" required="true" max="1" />
-                <rule avp="Origin-Host" required="true" max="1" />
-                <rule avp="Origin-Realm" required="true" max="1" />
-                <rule avp="Destination-Host" required="false" max="1" />
-                <rule avp="Destination-Realm" required="true" max="1" />
-                <rule avp="Supported-Features" required="false" />
-                <rule avp="User-Id" required="false" />
-                <rule avp="Proxy-Info" required="false" />
-                <rule avp="Route-Record" required="false" />
-            </request>
-            <!--
-              < Reset-Answer> ::= < Diameter Header: 322, PXY, 16777251 >
-                < Session-Id >
-                [ Vendor-Specific-Application-Id ]
-                *[ Supported-Features ]
-                [ Result-Code ]
-                [ Experimental-Result ]
-                { Auth-Session-State }
-                { Origin-Host }
-                { Origin-Realm }
-                *[ AVP ]
-                *[ Failed-AVP ]
-                *[ Proxy-Info ]
-                *[ Route-Record ]
-            -->
-            <answer>
-                <rule avp="Session-Id" required="true" max="1" />
-                <rule avp="Vendor-Specific-Application-Id" required="false" max="1" />
-                <rule avp="Supported-Features" required="false" />
-                <rule avp="Result-Code" required="false" max="1" />
-                <rule avp="Experimental-Result" required="false" max="1" />
-                <rule avp="Auth-Session-State" required="true" max="1" />
-                <rule avp="Origin-Host" required="true" max="1" />
-                <rule avp="Origin-Realm" required="true" max="1" />
-                <rule avp="Failed-AVP" required="false" max="1" />
-                <rule avp="Proxy-Info" required="false" />
-                <rule avp="Route-Record" required="false" />
-            </answer>
-        </command>
+        <avp name="Visited-Network-Identifier" code="600" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="Public-Identity" code="601" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="UTF8String"/>
+        </avp>
+
+        <avp name="SIP-Number-Auth-Items" code="607" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="SIP-Authentication-Scheme" code="608" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="UTF8String"/>
+        </avp>
+
+        <avp name="SIP-Authenticate" code="609" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="SIP-Authorization" code="610" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="SIP-Authentication-Context" code="611" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="SIP-Auth-Data-Item" code="612" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="SIP-Item-Number" required="false" max="1"/>
+                <rule avp="SIP-Authentication-Scheme" required="false" max="1"/>
+                <rule avp="SIP-Authenticate" required="false" max="1"/>
+                <rule avp="SIP-Authorization" required="false" max="1"/>
+                <rule avp="SIP-Authentication-Context" required="false" max="1"/>
+                <rule avp="Confidentiality-Key" required="false" max="1"/>
+                <rule avp="Integrity-Key" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+            </data>
+        </avp>
+
+        <avp name="SIP-Item-Number" code="613" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="Server-Assignment-Type" code="614" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="NO_ASSIGNMENT"/>
+                <item code="1" name="REGISTRATION"/>
+                <item code="2" name="RE_REGISTRATION"/>
+                <item code="3" name="UNREGISTERED_USER"/>
+                <item code="4" name="TIMEOUT_DEREGISTRATION"/>
+                <item code="5" name="USER_DEREGISTRATION"/>
+                <item code="6" name="TIMEOUT_DEREGISTRATION_STORE_SERVER_NAME"/>
+                <item code="7" name="USER_DEREGISTRATION_STORE_SERVER_NAME"/>
+                <item code="8" name="ADMINISTRATIVE_DEREGISTRATION"/>
+                <item code="9" name="AUTHENTICATION_FAILURE"/>
+                <item code="10" name="AUTHENTICATION_TIMEOUT"/>
+                <item code="11" name="DEREGISTRATION_TOO_MUCH_DATA"/>
+                <item code="12" name="AAA_USER_DATA_REQUEST"/>
+                <item code="13" name="PGW_UPDATE"/>
+                <item code="14" name="RESTORATION"/>
+            </data>
+        </avp>
+
+        <avp name="Charging-Information" code="618" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="Primary-Event-Charging-Function-Name" required="false" max="1"/>
+                <rule avp="Secondary-Event-Charging-Function-Name" required="false" max="1"/>
+                <rule avp="Primary-Charging-Collection-Function-Name" required="false" max="1"/>
+                <rule avp="Secondary-Charging-Collection-Function-Name" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+            </data>
+        </avp>
+
+        <avp name="Primary-Event-Charging-Function-Name" code="619" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="DiameterURI"/>
+        </avp>
+
+        <avp name="Secondary-Event-Charging-Function-Name" code="620" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="DiameterURI"/>
+        </avp>
+
+        <avp name="Primary-Charging-Collection-Function-Name" code="621" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="DiameterURI"/>
+        </avp>
+
+        <avp name="Secondary-Charging-Collection-Function-Name" code="622" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="DiameterURI"/>
+        </avp>
+
+        <avp name="User-Authorization-Type" code="623" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="REGISTRATION"/>
+                <item code="1" name="DE_REGISTRATION"/>
+                <item code="2" name="REGISTRATION_AND_CAPABILITIES"/>
+            </data>
+        </avp>
+
+        <avp name="User-Data-Already-Available" code="624" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="USER_DATA_NOT_AVAILABLE"/>
+                <item code="1" name="USER_DATA_ALREADY_AVAILABLE"/>
+            </data>
+        </avp>
+
+        <avp name="Confidentiality-Key" code="625" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="Integrity-Key" code="626" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="Originating-Request" code="633" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="ORIGINATING"/>
+            </data>
+        </avp>
+
+        <avp name="UAR-Flags" code="637" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="Deregistration-Reason" code="615" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="Reason-Code" required="true" max="1"/>
+                <rule avp="Reason-Info" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+            </data>
+        </avp>
+
+        <avp name="Reason-Code" code="616" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="PERMANENT_TERMINATION"/>
+                <item code="1" name="NEW_SERVER_ASSIGNMENT"/>
+                <item code="2" name="SERVER_CHANGE"/>
+                <item code="3" name="REMOVE_S_CSCF"/>
+            </data>
+        </avp>
+
+        <avp name="Reason-Info" code="617" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="UTF8String"/>
+        </avp>
+
+        <avp name="Supported-Applications" code="631" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="Auth-Application-Id" required="false"/>
+                <rule avp="Acct-Application-Id" required="false"/>
+                <rule avp="Vendor-Specific-Application-Id" required="false"/>
+                <rule avp="AVP" required="false"/>
+            </data>
+        </avp>
+
+        <avp name="Associated-Identities" code="632" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="User-Name" required="false"/>
+                <rule avp="AVP" required="false"/>
+            </data>
+        </avp>
+
+        <avp name="Loose-Route-Indication" code="638" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="LOOSE_ROUTE_NOT_REQUIRED"/>
+                <item code="1" name="LOOSE_ROUTE_REQUIRED"/>
+            </data>
+        </avp>
+
+        <avp name="SCSCF-Restoration-Info" code="639" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="User-Name" required="true" max="1"/>
+                <rule avp="Restoration-Info" required="true"/>
+                <rule avp="SIP-Authentication-Scheme" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+            </data>
+        </avp>
+
+        <avp name="Path" code="640" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="Contact" code="641" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="Subscription-Info" code="642" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="Call-ID-SIP-Header" required="true" max="1"/>
+                <rule avp="From-SIP-Header" required="true" max="1"/>
+                <rule avp="To-SIP-Header" required="true" max="1"/>
+                <rule avp="Record-Route" required="true" max="1"/>
+                <rule avp="Contact" required="true" max="1"/>
+                <rule avp="AVP" required="false"/>
+            </data>
+        </avp>
+
+        <avp name="Call-ID-SIP-Header" code="643" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="From-SIP-Header" code="644" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="To-SIP-Header" code="645" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="Record-Route" code="646" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="Associated-Registered-Identities" code="647" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="User-Name" required="false"/>
+                <rule avp="AVP" required="false"/>
+            </data>
+        </avp>
+
+        <avp name="Multiple-Registration-Indication" code="648" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="NOT_MULTIPLE_REGISTRATION"/>
+                <item code="1" name="MULTIPLE_REGISTRATION"/>
+            </data>
+        </avp>
+
+        <avp name="Restoration-Info" code="649" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="Path" required="true" max="1"/>
+                <rule avp="Contact" required="true" max="1"/>
+                <rule avp="Subscription-Info" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+            </data>
+        </avp>
+
+        <avp name="Identity-with-Emergency-Registration" code="651" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="User-Name" required="true" max="1"/>
+                <rule avp="Public-Identity" required="true" max="1"/>
+                <rule avp="AVP" required="false"/>
+            </data>
+        </avp>
+
+        <avp name="Priviledged-Sender-Indication" code="652" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="NOT_PRIVILEDGED_SENDER"/>
+                <item code="1" name="PRIVILEDGED_SENDER"/>
+            </data>
+        </avp>
+
+        <avp name="LIA-Flags" code="653" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="Initial-CSeq-Sequence-Number" code="654" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="SAR-Flags" code="655" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+    </application>
+</diameter>`
+
+var tgpprxXML = `<?xml version="1.0" encoding="UTF-8"?>
+<diameter>
+    <!--
+        3GPP TS 29.214
+        See: http://www.etsi.org/deliver/etsi_ts/129200_129299/129214/12.06.00_60/ts_129214v120600p.pdf
+    -->
+    <application id="16777236" type="auth" name="TGPP Rx">
+        <vendor id="10415" name="TGPP"/>
+        <command code="265" short="AA" name="AA">
+            <request>
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Auth-Application-Id" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Destination-Realm" required="true" max="1"/>
+                <rule avp="Destination-Host" required="false" max="1"/>
+                <rule avp="AF-Application-Identifier" required="false" max="1"/>
+                <rule avp="Media-Component-Description" required="false"/>
+                <rule avp="Service-Info-Status" required="false" max="1"/>
+                <rule avp="AF-Charging-Identifier" required="false" max="1"/>
+                <rule avp="SIP-Forking-Indication" required="false" max="1"/>
+                <rule avp="Specific-Action" required="false"/>
+                <rule avp="Subscription-Id" required="false"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="Framed-IP-Address" required="false" max="1"/>
+                <rule avp="Framed-IPv6-Prefix" required="false" max="1"/>
+                <rule avp="Called-Station-Id" required="false" max="1"/>
+                <rule avp="Service-URN" required="false" max="1"/>
+                <rule avp="Rx-Request-Type" required="false" max="1"/>
+                <rule avp="Sponsored-Connectivity-Data" required="false" max="1"/>
+                <rule avp="MPS-Identifier" required="false" max="1"/>
+                <rule avp="GCS-Identifier" required="false" max="1"/>
+                <rule avp="IP-Domain-Id" required="false" max="1"/>
+                <rule avp="Authorization-Token" required="false" max="1"/>
+                <rule avp="Origin-State-Id" required="false" max="1"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
+            </request>
+            <answer>
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Auth-Application-Id" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Result-Code" required="false" max="1"/>
+                <rule avp="Experimental-Result" required="false" max="1"/>
+                <rule avp="Auth-Session-State" required="false" max="1"/>
+                <rule avp="Access-Network-Charging-Identifier" required="false"/>
+                <rule avp="Access-Network-Charging-Address" required="false" max="1"/>
+                <rule avp="Acceptable-Service-Info" required="false" max="1"/>
+                <rule avp="IP-CAN-Type" required="false" max="1"/>
+                <rule avp="RAT-Type" required="false" max="1"/>
+                <rule avp="Retry-Interval" required="false" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="Error-Message" required="false" max="1"/>
+                <rule avp="Error-Reporting-Host" required="false" max="1"/>
+                <rule avp="Failed-AVP" required="false" max="1"/>
+                <rule avp="Origin-State-Id" required="false" max="1"/>
+                <rule avp="Proxy-Info" required="false"/>
+            </answer>
+        </command>
+        <command code="258" short="RA" name="Re-Auth">
+            <request>
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Destination-Realm" required="true" max="1"/>
+                <rule avp="Destination-Host" required="true" max="1"/>
+                <rule avp="Auth-Application-Id" required="true" max="1"/>
+                <rule avp="Specific-Action" required="true"/>
+                <rule avp="Access-Network-Charging-Identifier" required="false"/>
+                <rule avp="Access-Network-Charging-Address" required="false" max="1"/>
+                <rule avp="Flows" required="false"/>
+                <rule avp="Abort-Cause" required="false" max="1"/>
+                <rule avp="IP-CAN-Type" required="false" max="1"/>
+                <rule avp="RAT-Type" required="false" max="1"/>
+                <rule avp="Origin-State-Id" required="false" max="1"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
+            </request>
+            <answer>
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Result-Code" required="false" max="1"/>
+                <rule avp="Experimental-Result" required="false" max="1"/>
+                <rule avp="Media-Component-Description" required="false"/>
+                <rule avp="Service-URN" required="false" max="1"/>
+                <rule avp="Origin-State-Id" required="false" max="1"/>
+                <rule avp="Error-Message" required="false" max="1"/>
+                <rule avp="Error-Reporting-Host" required="false" max="1"/>
+                <rule avp="Failed-AVP" required="false" max="1"/>
+                <rule avp="Proxy-Info" required="false"/>
+            </answer>
+        </command>
+        <command code="275" short="ST" name="Session-Termination">
+            <request>
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Destination-Realm" required="true" max="1"/>
+                <rule avp="Auth-Application-Id" required="true" max="1"/>
+                <rule avp="Termination-Cause" required="true" max="1"/>
+                <rule avp="Destination-Host" required="false" max="1"/>
+                <rule avp="Required-Access-Info" required="false"/>
+                <rule avp="Class" required="false"/>
+                <rule avp="Origin-State-Id" required="false" max="1"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
+            </request>
+            <answer>
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Result-Code" required="false" max="1"/>
+                <rule avp="Error-Message" required="false" max="1"/>
+                <rule avp="Error-Reporting-Host" required="false" max="1"/>
+                <rule avp="Failed-AVP" required="false" max="1"/>
+                <rule avp="Origin-State-Id" required="false" max="1"/>
+                <rule avp="Proxy-Info" required="false"/>
+            </answer>
+        </command>
+        <command code="274" short="AS" name="Abort-Session">
+            <request>
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Destination-Realm" required="true" max="1"/>
+                <rule avp="Destination-Host" required="true" max="1"/>
+                <rule avp="Auth-Application-Id" required="true" max="1"/>
+                <rule avp="Abort-Cause" required="true" max="1"/>
+                <rule avp="Origin-State-Id" required="false" max="1"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
+            </request>
+            <answer>
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Result-Code" required="false" max="1"/>
+                <rule avp="Origin-State-Id" required="false" max="1"/>
+                <rule avp="Error-Message" required="false" max="1"/>
+                <rule avp="Error-Reporting-Host" required="false" max="1"/>
+                <rule avp="Failed-AVP" required="false" max="1"/>
+                <rule avp="Proxy-Info" required="false"/>
+            </answer>
+        </command>
+
+        <avp name="Abort-Cause" code="500" must="M,V" may="P" must-not="-" may-encrypt="Y" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="BEARER_RELEASED"/>
+                <item code="1" name="INSUFFICIENT_SERVER_RESOURCES"/>
+                <item code="2" name="INSUFFICIENT_BEARER_RESOURCES"/>
+                <item code="3" name="PS_TO_CS_HANDOVER"/>
+                <item code="4" name="SPONSORED_DATA_CONNECTIVITY_DISALLOWED"/>
+            </data>
+        </avp>
+
+        <avp name="Access-Network-Charging-Identifier" code="502" must="M,V" may="P" must-not="-" may-encrypt="Y" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="Access-Network-Charging-Identifier-Value" required="true" max="1"/>
+                <rule avp="Flows" required="false"/>
+            </data>
+        </avp>
+
+        <avp name="AF-Application-Identifier" code="504" must="M,V" may="P" must-not="-" may-encrypt="Y" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="Flow-Number" code="509" must="M,V" may="P" must-not="-" may-encrypt="Y" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="Flow-Status" code="511" must="M,V" may="P" must-not="-" may-encrypt="Y" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="ENABLED-UPLINK"/>
+                <item code="1" name="ENABLED-DOWNLINK"/>
+                <item code="2" name="ENABLED"/>
+                <item code="3" name="DISABLED"/>
+                <item code="4" name="REMOVED"/>
+            </data>
+        </avp>
+
+        <avp name="Flow-Usage" code="512" must="M,V" may="P" must-not="-" may-encrypt="Y" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="NO_INFORMATION"/>
+                <item code="1" name="RTCP"/>
+                <item code="2" name="AF_SIGNALLING"/>
+            </data>
+        </avp>
+
+        <avp name="Specific-Action" code="513" must="M,V" may="P" must-not="-" may-encrypt="Y" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="1" name="CHARGING_CORRELATION_EXCHANGE"/>
+                <item code="2" name="INDICATION_OF_LOSS_OF_BEARER"/>
+                <item code="3" name="INDICATION_OF_RECOVERY_OF_BEARER"/>
+                <item code="4" name="INDICATION_OF_RELEASE_OF_BEARER"/>
+                <item code="6" name="IP-CAN_CHANGE"/>
+                <item code="7" name="INDICATION_OF_OUT_OF_CREDIT"/>
+                <item code="8" name="INDICATION_OF_SUCCESSFUL_RESOURCES_ALLOCATION"/>
+                <item code="9" name="INDICATION_OF_FAILED_RESOURCES_ALLOCATION"/>
+                <item code="10" name="INDICATION_OF_LIMITED_PCC_DEPLOYMENT"/>
+                <item code="11" name="USAGE_REPORT"/>
+                <item code="12" name="ACCESS_NETWORK_INFO_REPORT"/>
+            </data>
+        </avp>
+
+        <avp name="Media-Component-Description" code="517" must="M,V" may="P" must-not="-" may-encrypt="Y" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="Media-Component-Number" required="true" max="1"/>
+                <rule avp="Media-Sub-Component" required="false"/>
+                <rule avp="AF-Application-Identifier" required="false" max="1"/>
+                <rule avp="Media-Type" required="false" max="1"/>
+                <rule avp="Max-Requested-Bandwidth-UL" required="false" max="1"/>
+                <rule avp="Max-Requested-Bandwidth-DL" required="false" max="1"/>
+                <rule avp="Flow-Status" required="false" max="1"/>
+                <rule avp="RS-Bandwidth" required="false" max="1"/>
+                <rule avp="RR-Bandwidth" required="false" max="1"/>
+                <rule avp="Codec-Data" required="false"/>
+                <rule avp="Sharing-Key-DL" required="false" max="1"/>
+                <rule avp="Sharing-Key-UL" required="false" max="1"/>
+                <rule avp="Content-Version" required="false" max="1"/>
+                <rule avp="Max-Supported-Bandwidth-DL" required="false" max="1"/>
+                <rule avp="Max-Supported-Bandwidth-UL" required="false" max="1"/>
+                <rule avp="Min-Desired-Bandwidth-DL" required="false" max="1"/>
+                <rule avp="Min-Desired-Bandwidth-UL" required="false" max="1"/>
+                <rule avp="Min-Requested-Bandwidth-DL" required="false" max="1"/>
+                <rule avp="Min-Requested-Bandwidth-UL" required="false" max="1"/>
+                <rule avp="Priority-Sharing-Indicator" required="false" max="1"/>
+                <rule avp="Extended-Max-Requested-BW-DL" required="false" max="1"/>
+                <rule avp="Extended-Max-Requested-BW-UL" required="false" max="1"/>
+                <rule avp="Extended-Max-Supported-BW-DL" required="false" max="1"/>
+                <rule avp="Extended-Max-Supported-BW-UL" required="false" max="1"/>
+                <rule avp="Extended-Min-Desired-BW-DL" required="false" max="1"/>
+                <rule avp="Extended-Min-Desired-BW-UL" required="false" max="1"/>
+                <rule avp="Extended-Min-Requested-BW-DL" required="false" max="1"/>
+                <rule avp="Extended-Min-Requested-BW-UL" required="false" max="1"/>
+            </data>
+        </avp>
+
+        <avp name="Media-Component-Number" code="518" must="M,V" may="P" must-not="-" may-encrypt="Y" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="Media-Sub-Component" code="519" must="M,V" may="P" must-not="-" may-encrypt="Y" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="Flow-Number" required="true" max="1"/>
+                <rule avp="Flow-Description" required="false" max="2"/>
+                <rule avp="Flow-Status" required="false" max="1"/>
+                <rule avp="Flow-Usage" required="false" max="1"/>
+                <rule avp="Max-Requested-Bandwidth-UL" required="false" max="1"/>
+                <rule avp="Max-Requested-Bandwidth-DL" required="false" max="1"/>
+                <rule avp="AF-Signalling-Protocol" required="false" max="1"/>
+            </data>
+        </avp>
+
+        <avp name="Media-Type" code="520" must="M,V" may="P" must-not="-" may-encrypt="Y" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="AUDIO"/>
+                <item code="1" name="VIDEO"/>
+                <item code="2" name="DATA"/>
+                <item code="3" name="APPLICATION"/>
+                <item code="4" name="CONTROL"/>
+                <item code="5" name="TEXT"/>
+                <item code="6" name="MESSAGE"/>
+            </data>
+        </avp>
+
+        <avp name="RR-Bandwidth" code="521" must="M,V" may="P" must-not="-" may-encrypt="Y" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="RS-Bandwidth" code="522" must="M,V" may="P" must-not="-" may-encrypt="Y" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="SIP-Forking-Indication" code="523" must="M,V" may="P" must-not="-" may-encrypt="Y" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="SINGLE_DIALOGUE"/>
+                <item code="1" name="SEVERAL_DIALOGUES"/>
+            </data>
+        </avp>
+
+        <avp name="Codec-Data" code="524" must="M,V" may="P" must-not="-" may-encrypt="Y" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="Service-URN" code="525" must="M,V" may="P" must-not="-" may-encrypt="Y" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="Acceptable-Service-Info" code="526" must="M,V" may="P" must-not="-" may-encrypt="Y" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="Media-Component-Description" required="false"/>
+                <rule avp="Max-Requested-Bandwidth-DL" required="false" max="1"/>
+                <rule avp="Max-Requested-Bandwidth-UL" required="false" max="1"/>
+            </data>
+        </avp>
+
+        <avp name="Service-Info-Status" code="527" must="M,V" may="P" must-not="-" may-encrypt="Y" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="FINAL_SERVICE_INFORMATION"/>
+                <item code="1" name="PRELIMINARY_SERVICE_INFORMATION"/>
+            </data>
+        </avp>
+
+        <avp name="AF-Signalling-Protocol" code="529" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="NO_INFORMATION"/>
+                <item code="1" name="SIP"/>
+            </data>
+        </avp>
+
+        <avp name="Rx-Request-Type" code="533" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="INITIAL_REQUEST"/>
+                <item code="1" name="UPDATE_REQUEST"/>
+                <item code="2" name="PCSCF_RESTORATION"/>
+            </data>
+        </avp>
+
+        <avp name="Required-Access-Info" code="536" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="USER_LOCATION"/>
+                <item code="1" name="MS_TIME_ZONE"/>
+            </data>
+        </avp>
+
+        <avp name="Authorization-Token" code="506" must="M,V" may="P" must-not="-" may-encrypt="Y" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="MPS-Identifier" code="528" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="Sponsored-Connectivity-Data" code="530" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="Sponsor-Identity" required="false" max="1"/>
+                <rule avp="Application-Service-Provider-Identity" required="false" max="1"/>
+                <rule avp="Granted-Service-Unit" required="false" max="1"/>
+                <rule avp="Used-Service-Unit" required="false" max="1"/>
+                <rule avp="Sponsoring-Action" required="false" max="1"/>
+            </data>
+        </avp>
+
+        <avp name="Min-Requested-Bandwidth-DL" code="534" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="Min-Requested-Bandwidth-UL" code="535" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="IP-Domain-Id" code="537" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="GCS-Identifier" code="538" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="Sharing-Key-DL" code="539" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="Sharing-Key-UL" code="540" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="Retry-Interval" code="541" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="Sponsoring-Action" code="542" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="DISABLE_SPONSORING"/>
+                <item code="1" name="ENABLE_SPONSORING"/>
+            </data>
+        </avp>
+
+        <avp name="Max-Supported-Bandwidth-DL" code="543" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="Max-Supported-Bandwidth-UL" code="544" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="Min-Desired-Bandwidth-DL" code="545" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="Min-Desired-Bandwidth-UL" code="546" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="Priority-Sharing-Indicator" code="550" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="PRIORITY_SHARING_ENABLED"/>
+                <item code="1" name="PRIORITY_SHARING_DISABLED"/>
+            </data>
+        </avp>
+
+        <avp name="Content-Version" code="552" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+            <data type="Unsigned64"/>
+        </avp>
+
+        <avp name="Extended-Max-Requested-BW-DL" code="554" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="Extended-Max-Requested-BW-UL" code="555" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="Extended-Max-Supported-BW-DL" code="556" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="Extended-Max-Supported-BW-UL" code="557" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="Extended-Min-Desired-BW-DL" code="558" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="Extended-Min-Desired-BW-UL" code="559" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="Extended-Min-Requested-BW-DL" code="560" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="Extended-Min-Requested-BW-UL" code="561" must="V" may="P" must-not="M" may-encrypt="Y" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+    </application>
+</diameter>`
+
+var tgpps6aXML = `<?xml version="1.0" encoding="UTF-8"?>
+<diameter>
+    <!--
+        3GPP TS 29.272
+        See: http://www.etsi.org/deliver/etsi_ts/129200_129299/129272/12.06.00_60/ts_129272v120600p.pdf
+    -->
+    <application id="16777251" type="auth" name="TGPP S6A">
+        <vendor id="10415" name="TGPP"/>
+        <command code="316" short="UL" name="Update-Location">
+            <request>
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Vendor-Specific-Application-Id" required="false" max="1"/>
+                <rule avp="Auth-Session-State" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Destination-Host" required="false" max="1"/>
+                <rule avp="Destination-Realm" required="true" max="1"/>
+                <rule avp="User-Name" required="true" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="Terminal-Information" required="false" max="1"/>
+                <rule avp="RAT-Type" required="true" max="1"/>
+                <rule avp="ULR-Flags" required="true" max="1"/>
+                <rule avp="UE-SRVCC-Capability" required="false" max="1"/>
+                <rule avp="Visited-PLMN-Id" required="true" max="1"/>
+                <rule avp="SGSN-Number" required="false" max="1"/>
+                <rule avp="Homogeneous-Support-of-IMS-Voice-Over-PS-Sessions" required="false" max="1"/>
+                <rule avp="GMLC-Address" required="false" max="1"/>
+                <rule avp="Active-APN" required="false"/>
+                <rule avp="AVP" required="false"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
+            </request>
+            <answer>
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Vendor-Specific-Application-Id" required="false" max="1"/>
+                <rule avp="Result-Code" required="false" max="1"/>
+                <rule avp="Experimental-Result" required="false" max="1"/>
+                <rule avp="Error-Diagnostic" required="false" max="1"/>
+                <rule avp="Auth-Session-State" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="ULA-Flags" required="false" max="1"/>
+                <rule avp="Subscription-Data" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+                <rule avp="Failed-AVP" required="false"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
+            </answer>
+        </command>
+
+        <command code="317" short="CL" name="Cancel-Location">
+            <request>
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Vendor-Specific-Application-Id" required="false" max="1"/>
+                <rule avp="Auth-Session-State" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Destination-Host" required="true" max="1"/>
+                <rule avp="Destination-Realm" required="true" max="1"/>
+                <rule avp="User-Name" required="true" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="Cancellation-Type" required="true" max="1"/>
+                <rule avp="CLR-Flags" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
+            </request>
+            <answer>
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Vendor-Specific-Application-Id" required="false" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="Result-Code" required="false" max="1"/>
+                <rule avp="Experimental-Result" required="false" max="1"/>
+                <rule avp="Auth-Session-State" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="AVP" required="false"/>
+                <rule avp="Failed-AVP" required="false"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
+            </answer>
+        </command>
+
+        <command code="318" short="AI" name="Authentication-Information">
+            <request>
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Vendor-Specific-Application-Id" required="false" max="1"/>
+                <rule avp="Auth-Session-State" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Destination-Host" required="false" max="1"/>
+                <rule avp="Destination-Realm" required="true" max="1"/>
+                <rule avp="User-Name" required="true" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="Requested-EUTRAN-Authentication-Info" required="false" max="1"/>
+                <rule avp="Requested-UTRAN-GERAN-Authentication-Info" required="false" max="1"/>
+                <rule avp="Visited-PLMN-Id" required="true" max="1"/>
+                <rule avp="AVP" required="false"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
+            </request>
+            <answer>
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Vendor-Specific-Application-Id" required="false" max="1"/>
+                <rule avp="Result-Code" required="false" max="1"/>
+                <rule avp="Experimental-Result" required="false" max="1"/>
+                <rule avp="Error-Diagnostic" required="false" max="1"/>
+                <rule avp="Auth-Session-State" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="Authentication-Info" required="true" max="1"/>
+                <rule avp="AVP" required="false"/>
+                <rule avp="Failed-AVP" required="false"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
+            </answer>
+        </command>
+
+        <command code="319" short="ID" name="Insert-Subscriber-Data">
+            <request>
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Vendor-Specific-Application-Id" required="false" max="1"/>
+                <rule avp="Auth-Session-State" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Destination-Host" required="true" max="1"/>
+                <rule avp="Destination-Realm" required="true" max="1"/>
+                <rule avp="User-Name" required="true" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="Subscription-Data" required="true" max="1"/>
+                <rule avp="IDR-Flags" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
+            </request>
+            <answer>
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Vendor-Specific-Application-Id" required="false" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="Result-Code" required="false" max="1"/>
+                <rule avp="Experimental-Result" required="false" max="1"/>
+                <rule avp="Auth-Session-State" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="RAT-Type" required="false" max="1"/>
+                <rule avp="IDA-Flags" required="false" max="1"/>
+                <rule avp="IMS-Voice-Over-PS-Sessions-Supported" required="false" max="1"/>
+                <rule avp="Last-UE-Activity-Time" required="false" max="1"/>
+                <rule avp="EPS-User-State" required="false" max="1"/>
+                <rule avp="EPS-Location-Information" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+                <rule avp="Failed-AVP" required="false"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
+            </answer>
+        </command>
+
+        <command code="320" short="DS" name="Delete-Subscriber-Data">
+            <request>
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Vendor-Specific-Application-Id" required="false" max="1"/>
+                <rule avp="Auth-Session-State" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Destination-Host" required="true" max="1"/>
+                <rule avp="Destination-Realm" required="true" max="1"/>
+                <rule avp="User-Name" required="true" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="DSR-Flags" required="true" max="1"/>
+                <rule avp="Context-Identifier" required="false"/>
+                <rule avp="Trace-Reference" required="false" max="1"/>
+                <rule avp="TS-Code" required="false"/>
+                <rule avp="SS-Code" required="false"/>
+                <rule avp="AVP" required="false"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
+            </request>
+            <answer>
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Vendor-Specific-Application-Id" required="false" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="Result-Code" required="false" max="1"/>
+                <rule avp="Experimental-Result" required="false" max="1"/>
+                <rule avp="Auth-Session-State" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="DSA-Flags" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+                <rule avp="Failed-AVP" required="false"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
+            </answer>
+        </command>
+
+        <avp name="DSR-Flags" code="1421" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="DSA-Flags" code="1422" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <command code="321" short="PU" name="Purge-UE">
+            <!--
+                < Purge-UE-Request> ::=	< Diameter Header: 321, REQ, PXY, 16777251 >
+                < Session-Id >
+                [ DRMP ]
+                [ Vendor-Specific-Application-Id ]
+                { Auth-Session-State }
+                { Origin-Host }
+                { Origin-Realm }
+                [ Destination-Host ]
+                { Destination-Realm }
+                { User-Name }
+                [ OC-Supported-Features ]
+                [ PUR-Flags ]
+                *[ Supported-Features ]
+                [ EPS-Location-Information ]
+                *[ AVP ]
+                *[ Proxy-Info ]
+                *[ Route-Record ]
+            -->
+            <request>
+                <rule avp="Session-Id" required="true" max="1" />
+                <rule avp="DRMP" required="false" max="1" />
+                <rule avp="Vendor-Specific-Application-Id" required="false" max="1" />
+                <rule avp="Auth-Session-State" required="true" max="1" />
+                <rule avp="Origin-Host" required="true" max="1" />
+                <rule avp="Origin-Realm" required="true" max="1" />
+                <rule avp="Destination-Host" required="false" max="1" />
+                <rule avp="Destination-Realm" required="true" max="1" />
+                <rule avp="User-Name" required="true" max="1" />
+                <rule avp="OC-Supported-Features" required="false" max="1" />
+                <rule avp="PUR-Flags" required="false" max="1" />
+                <rule avp="Supported-Features" required="false" />
+                <rule avp="EPS-Location-Information" required="false" max="1" />
+                <rule avp="Proxy-Info" required="false" />
+                <rule avp="Route-Record" required="false" />
+            </request>
+
+            <!--
+                < Purge-UE-Answer> ::=	< Diameter Header: 321, PXY, 16777251 >
+                < Session-Id >
+                [ DRMP ]
+                [ Vendor-Specific-Application-Id ]
+                *[ Supported-Features ]
+                [ Result-Code ]
+                [ Experimental-Result ]
+                { Auth-Session-State }
+                { Origin-Host }
+                { Origin-Realm }
+                [ OC-Supported-Features ]
+                [ OC-OLR ]
+                *[ Load ]
+                [ PUA-Flags ]
+                *[ AVP ]
+                [ Failed-AVP ]
+                *[ Proxy-Info ]
+                *[ Route-Record ]
+            -->
+            <answer>
+                <rule avp="Session-Id" required="true" max="1" />
+                <rule avp="DRMP" required="false" max="1" />
+                <rule avp="Vendor-Specific-Application-Id" required="false" max="1" />
+                <rule avp="Supported-Features" required="false" />
+                <rule avp="Result-Code" required="false" max="1" />
+                <rule avp="Experimental-Result" required="false" max="1" />
+                <rule avp="Auth-Session-State" required="true" max="1" />
+                <rule avp="Origin-Host" required="true" max="1" />
+                <rule avp="Origin-Realm" required="true" max="1" />
+                <rule avp="OC-Supported-Features" required="false" max="1" />
+                <rule avp="OC-OLR" required="false" max="1" />
+                <!-- rule avp="Load" required="false" /-->
+                <rule avp="PUA-Flags" required="false" max="1" />
+                <rule avp="Failed-AVP" required="false" max="1" />
+                <rule avp="Proxy-Info" required="false" />
+                <rule avp="Route-Record" required="false" />
+            </answer>
+        </command>
+
+        <command code="323" short="NO" name="Notify">
+            <!--
+                < Notify-Request> ::=	< Diameter Header: 323, REQ, PXY, 16777251 >
+                < Session-Id >
+                [ Vendor-Specific-Application-Id ]
+                [ DRMP ]
+                { Auth-Session-State }
+                { Origin-Host }
+                { Origin-Realm }
+                [ Destination-Host ]
+                { Destination-Realm }
+                { User-Name }
+                [ OC-Supported-Features ]
+                * [ Supported-Features ]
+                [ Terminal-Information ]
+                [ MIP6-Agent-Info ]
+                [ Visited-Network-Identifier ]
+                [ Context-Identifier ]
+                [ Service-Selection ]
+                [ Alert-Reason ]
+                [ UE-SRVCC-Capability ]
+                [ NOR-Flags ]
+                [ Homogeneous-Support-of-IMS-Voice-Over-PS-Sessions ]
+                [ Maximum-UE-Availability-Time ]
+                *[ Monitoring-Event-Config-Status ]
+                [ Emergency-Services ]
+                *[ AVP ]
+                *[ Proxy-Info ]
+                *[ Route-Record ]
+            -->
+            <request>
+                <rule avp="Session-Id" required="true" max="1" />
+                <rule avp="Vendor-Specific-Application-Id" required="false" max="1" />
+                <rule avp="DRMP" required="false" max="1" />
+                <rule avp="Auth-Session-State" required="true" max="1" />
+                <rule avp="Origin-Host" required="true" max="1" />
+                <rule avp="Origin-Realm" required="true" max="1" />
+                <rule avp="Destination-Host" required="false" max="1" />
+                <rule avp="Destination-Realm" required="true" max="1" />
+                <rule avp="User-Name" required="true" max="1" />
+                <rule avp="OC-Supported-Features" required="false" max="1" />
+                <rule avp="Supported-Features" required="false" />
+                <rule avp="Terminal-Information" required="false" max="1" />
+                <rule avp="MIP6-Agent-Info" required="false" max="1" />
+                <rule avp="Visited-Network-Identifier" required="false" max="1" />
+                <rule avp="Context-Identifier" required="false" max="1" />
+                <rule avp="Service-Selection" required="false" max="1" />
+                <rule avp="Alert-Reason" required="false" max="1" />
+                <rule avp="UE-SRVCC-Capability" required="false" max="1" />
+                <rule avp="NOR-Flags" required="false" max="1" />
+                <rule avp="Homogeneous-Support-of-IMS-Voice-Over-PS-Sessions" required="false" max="1" />
+                <rule avp="Maximum-UE-Availability-Time" required="false" max="1" />
+                <rule avp="Monitoring-Event-Config-Status" required="false" />
+                <rule avp="Emergency-Services" required="false" max="1" />
+                <rule avp="Proxy-Info" required="false" />
+                <rule avp="Route-Record" required="false" />
+            </request>
+            <!--
+                < Notify-Answer> ::=	< Diameter Header: 323, PXY, 16777251 >
+                < Session-Id >
+                [ DRMP ]
+                [ Vendor-Specific-Application-Id ]
+                [ Result-Code ]
+                [ Experimental-Result ]
+                { Auth-Session-State }
+                { Origin-Host }
+                { Origin-Realm }
+                [ OC-Supported-Features ]
+                [ OC-OLR ]
+                *[ Load ]
+                *[ Supported-Features ]
+                *[ AVP ]
+                [ Failed-AVP ]
+                *[ Proxy-Info ]
+                *[ Route-Record ]
+            -->
+            <answer>
+                <rule avp="Session-Id" required="true" max="1" />
+                <rule avp="DRMP" required="false" max="1" />
+                <rule avp="Vendor-Specific-Application-Id" required="false" max="1" />
+                <rule avp="Result-Code" required="false" max="1" />
+                <rule avp="Experimental-Result" required="false" max="1" />
+                <rule avp="Auth-Session-State" required="true" max="1" />
+                <rule avp="Origin-Host" required="true" max="1" />
+                <rule avp="Origin-Realm" required="true" max="1" />
+                <rule avp="OC-Supported-Features" required="false" max="1" />
+                <rule avp="OC-OLR" required="false" max="1" />
+                <!-- rule avp="Load" required="false" /-->
+                <rule avp="Supported-Features" required="false" />
+                <rule avp="Failed-AVP" required="false" max="1" />
+                <rule avp="Proxy-Info" required="false" />
+                <rule avp="Route-Record" required="false" />
+            </answer>
+        </command>
+
+        <command code="322" short="RS" name="Reset">
+            <!--
+              < Reset-Request> ::= < Diameter Header: 322, REQ, PXY, 16777251 >
+                < Session-Id >
+                [ Vendor-Specific-Application-Id ]
+                { Auth-Session-State }
+                { Origin-Host }
+                { Origin-Realm }
+                { Destination-Host }
+                { Destination-Realm }
+                *[ Supported-Features ]
+                *[ User-Id ]
+                *[ AVP ]
+                *[ Proxy-Info ]
+                *[ Route-Record ]
+            -->
+            <request>
+                <rule avp="Session-Id" required="true" max="1" />
+                <rule avp="Vendor-Specific-Application-Id" required="false" max="1" />
+                <rule avp="Auth-Session-State" required="true" max="1" />
+                <rule avp="Origin-Host" required="true" max="1" />
+                <rule avp="Origin-Realm" required="true" max="1" />
+                <rule avp="Destination-Host" required="false" max="1" />
+                <rule avp="Destination-Realm" required="true" max="1" />
+                <rule avp="Supported-Features" required="false" />
+                <rule avp="User-Id" required="false" />
+                <rule avp="Proxy-Info" required="false" />
+                <rule avp="Route-Record" required="false" />
+            </request>
+            <!--
+              < Reset-Answer> ::= < Diameter Header: 322, PXY, 16777251 >
+                < Session-Id >
+                [ Vendor-Specific-Application-Id ]
+                *[ Supported-Features ]
+                [ Result-Code ]
+                [ Experimental-Result ]
+                { Auth-Session-State }
+                { Origin-Host }
+                { Origin-Realm }
+                *[ AVP ]
+                *[ Failed-AVP ]
+                *[ Proxy-Info ]
+                *[ Route-Record ]
+            -->
+            <answer>
+                <rule avp="Session-Id" required="true" max="1" />
+                <rule avp="Vendor-Specific-Application-Id" required="false" max="1" />
+                <rule avp="Supported-Features" required="false" />
+                <rule avp="Result-Code" required="false" max="1" />
+                <rule avp="Experimental-Result" required="false" max="1" />
+                <rule avp="Auth-Session-State" required="true" max="1" />
+                <rule avp="Origin-Host" required="true" max="1" />
+                <rule avp="Origin-Realm" required="true" max="1" />
+                <rule avp="Failed-AVP" required="false" max="1" />
+                <rule avp="Proxy-Info" required="false" />
+                <rule avp="Route-Record" required="false" />
+            </answer>
+        </command>
+
+        <avp name="Subscription-Data" code="1400" vendor-id="10415" must="M,V" may-encrypt="N">
+            <data type="Grouped">
+                <rule avp="Subscriber-Status" required="false" max="1"/>
+                <rule avp="MSISDN" required="false" max="1"/>
+                <rule avp="STN-SR" required="false" max="1"/>
+                <rule avp="ICS-Indicator" required="false" max="1"/>
+                <rule avp="Network-Access-Mode" required="false" max="1"/>
+                <rule avp="Operator-Determined-Barring" required="false" max="1"/>
+                <rule avp="HPLMN-ODB" required="false" max="1"/>
+                <rule avp="Regional-Subscription-Zone-Code" required="false" max="10"/>
+                <rule avp="Access-Restriction-Data" required="false" max="1"/>
+                <rule avp="APN-OI-Replacement" required="false" max="1"/>
+                <rule avp="LCS-Info" required="false" max="1"/>
+                <rule avp="Teleservice-List" required="false" max="1"/>
+                <rule avp="Call-Barring-Info" required="false"/>
+                <rule avp="TGPP-Charging-Characteristics" required="false" max="1"/>
+                <rule avp="AMBR" required="false" max="1"/>
+                <rule avp="APN-Configuration-Profile" required="false" max="1"/>
+                <rule avp="RAT-Frequency-Selection-Priority-ID" required="false" max="1"/>
+                <rule avp="Trace-Data" required="false" max="1"/>
+                <rule avp="GPRS-Subscription-Data" required="false" max="1"/>
+                <rule avp="CSG-Subscription-Data" required="false"/>
+                <rule avp="Roaming-Restricted-Due-To-Unsupported-Feature" required="false" max="1"/>
+                <rule avp="Subscribed-Periodic-RAU-TAU-Timer" required="false" max="1"/>
+                <rule avp="MPS-Priority" required="false" max="1"/>
+                <rule avp="VPLMN-LIPA-Allowed" required="false" max="1"/>
+                <rule avp="Relay-Node-Indicator" required="false" max="1"/>
+                <rule avp="MDT-User-Consent" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+            </data>
+        </avp>
+
+        <avp name="Subscriber-Status" code="1424" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="SERVICE_GRANTED"/>
+                <item code="1" name="OPERATOR_DETERMINED_BARRING"/>
+            </data>
+        </avp>
+
+        <avp name="STN-SR" code="1433" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="ICS-Indicator" code="1491" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="FALSE"/>
+                <item code="1" name="TRUE"/>
+            </data>
+        </avp>
+
+        <avp name="Network-Access-Mode" code="1417" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="PACKET_AND_CIRCUIT"/>
+                <item code="1" name="RESERVED"/>
+                <item code="2" name="ONLY_PACKET"/>
+            </data>
+        </avp>
+
+        <avp name="Operator-Determined-Barring" code="1425" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="HPLMN-ODB" code="1418" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="Regional-Subscription-Zone-Code" code="1446" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="Access-Restriction-Data" code="1426" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="APN-OI-Replacement" code="1427" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="UTF8String"/>
+        </avp>
+
+        <avp name="LCS-Info" code="1473" vendor-id="10415" must="M,V" may-encrypt="N">
+            <data type="Grouped">
+                <rule avp="GMLC-Number" required="false"/>
+                <rule avp="LCS-PrivacyException" required="false"/>
+                <rule avp="MO-LR" required="false"/>
+                <rule avp="AVP" required="false"/>
+            </data>
+        </avp>avp>
+
+        <avp name="GMLC-Number" code="1474" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="LCS-PrivacyException" code="1475" vendor-id="10415" must="M,V" may-encrypt="N">
+            <data type="Grouped">
+                <rule avp="SS-Code" required="true" max="1"/>
+                <rule avp="SS-Status" required="true" max="1"/>
+                <rule avp="Notification-To-UE-User" required="false" max="1"/>
+                <rule avp="External-Client" required="false"/>
+                <rule avp="PLMN-Client" required="false"/>
+                <rule avp="TGPP-Service-Type" required="false"/>
+                <rule avp="AVP" required="false"/>
+            </data>
+        </avp>avp>
+
+        <avp name="SS-Code" code="1476" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="SS-Status" code="1477" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="Notification-To-UE-User" code="1478" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="NOTIFY_LOCATION_ALLOWED"/>
+                <item code="1" name="NOTIFYANDVERIFY_LOCATION_ALLOWED_IF_NO_RESPONSE"/>
+                <item code="2" name="NOTIFYANDVERIFY_LOCATION_NOT_ALLOWED_IF_NO_RESPONSE"/>
+                <item code="3" name="LOCATION_NOT_ALLOWED"/>
+            </data>
+        </avp>
+
+        <avp name="External-Client" code="1479" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="Client-Identity" required="true" max="1"/>
+                <rule avp="GMLC-Restriction" required="false" max="1"/>
+                <rule avp="Notification-To-UE-User" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+            </data>
+        </avp>avp>
+
+        <avp name="Client-Identity" code="1480" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="GMLC-Restriction" code="1481" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="GMLC_LIST"/>
+                <item code="1" name="HOME_COUNTRY"/>
+            </data>
+        </avp>
+
+        <avp name="PLMN-Client" code="1482" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="BROADCAST_SERVICE"/>
+                <item code="1" name="O_AND_M_HPLMN"/>
+                <item code="2" name="O_AND_M_VPLMN"/>
+                <item code="3" name="ANONYMOUS_LOCATION"/>
+                <item code="3" name="TARGET_UE_SUBSCRIBED_SERVICE"/>
+            </data>
+        </avp>
+
+        <avp name="TGPP-Service-Type" code="1483" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="ServiceTypeIdentity" required="true" max="1"/>
+                <rule avp="GMLC-Restriction" required="false" max="1"/>
+                <rule avp="Notification-To-UE-User" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+            </data>
+        </avp>
+
+        <avp name="ServiceTypeIdentity" code="1484" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="MO-LR" code="1485" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="SS-Code" required="true" max="1"/>
+                <rule avp="SS-Status" required="true" max="1"/>
+                <rule avp="AVP" required="false"/>
+            </data>
+        </avp>
+
+        <avp name="Teleservice-List" code="1486" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="TS-Code" required="true" max="1"/>
+                <rule avp="AVP" required="false"/>
+            </data>
+        </avp>
+
+        <avp name="TS-Code" code="1487" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="Call-Barring-Info" code="1488" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="SS-Code" required="true" max="1"/>
+                <rule avp="SS-Status" required="true" max="1"/>
+                <rule avp="AVP" required="false"/>
+            </data>
+        </avp>
+
+        <avp name="AMBR" code="1435" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="Max-Requested-Bandwidth-UL" required="true" max="1"/>
+                <rule avp="Max-Requested-Bandwidth-DL" required="true" max="1"/>
+                <rule avp="AVP" required="false"/>
+            </data>
+        </avp>
+
+        <avp name="APN-Configuration-Profile" code="1429" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="Context-Identifier" required="true" max="1"/>
+                <rule avp="All-APN-Configurations-Included-Indicator" required="true" max="1"/>
+                <rule avp="APN-Configuration" required="true"/>
+                <rule avp="AVP" required="false"/>
+            </data>
+        </avp>
+
+        <avp name="Context-Identifier" code="1423" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="All-APN-Configurations-Included-Indicator" code="1428" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="All_APN_CONFIGURATIONS_INCLUDED"/>
+                <item code="1" name="MODIFIED|ADDED_APN_CONFIGURATIONS_INCLUDED"/>
+            </data>
+        </avp>
+
+        <avp name="APN-Configuration" code="1430" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="Context-Identifier" required="true" max="1"/>
+                <rule avp="Served-Party-IP-Address" required="false" max="2"/>
+                <rule avp="PDN-Type" required="true" max="1"/>
+                <rule avp="Service-Selection" required="true" max="1"/>
+                <rule avp="EPS-Subscribed-QoS-Profile" required="false" max="1"/>
+                <rule avp="VPLMN-Dynamic-Address-Allowed" required="false" max="1"/>
+                <rule avp="MIP6-Agent-Info" required="false" max="1"/>
+                <rule avp="Visited-Network-Identifier" required="false" max="1"/>
+                <rule avp="PDN-GW-Allocation-Type" required="false" max="1"/>
+                <rule avp="TGPP-Charging-Characteristics" required="false" max="1"/>
+                <rule avp="AMBR" required="false" max="1"/>
+                <rule avp="Specific-APN-Info" required="false"/>
+                <rule avp="APN-OI-Replacement" required="false" max="1"/>
+                <rule avp="SIPTO-Permission" required="false" max="1"/>
+                <rule avp="LIPA-Permission" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+            </data>
+        </avp>
+
+        <avp name="Served-Party-IP-Address" code="848" must="M,V" may="P" may-encrypt="N" vendor-id="10415">
+            <data type="Address"/>
+        </avp>
+
+        <avp name="PDN-Type" code="1456" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="IPv4"/>
+                <item code="1" name="IPv6"/>
+                <item code="2" name="IPv4v6"/>
+                <item code="3" name="IPv4_OR_IPv6"/>
+            </data>
+        </avp>
+
+        <avp name="EPS-Subscribed-QoS-Profile" code="1431" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="QoS-Class-Identifier" required="true" max="1"/>
+                <rule avp="Allocation-Retention-Priority" required="true" max="1"/>
+                <rule avp="AVP" required="false"/>
+            </data>
+        </avp>
+
+        <avp name="VPLMN-Dynamic-Address-Allowed" code="1432" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="NOTALLOWED"/>
+                <item code="1" name="ALLOWED"/>
+            </data>
+        </avp>
+
+        <avp name="PDN-GW-Allocation-Type" code="1438" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="STATIC"/>
+                <item code="1" name="DYNAMIC"/>
+            </data>
+        </avp>
+
+        <avp name="SIPTO-Permission" code="1613" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="SIPTO_ALLOWED"/>
+                <item code="1" name="SIPTO_NOTALLOWED"/>
+            </data>
+        </avp>
+
+        <avp name="LIPA-Permission" code="1618" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="LIPA-PROHIBITED"/>
+                <item code="1" name="LIPA-ONLY"/>
+                <item code="2" name="LIPA-CONDITIONAL"/>
+            </data>
+        </avp>
+
+        <avp name="RAT-Frequency-Selection-Priority-ID" code="1440" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="Trace-Data" code="1458" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="Trace-Reference" required="true" max="1"/>
+                <rule avp="Trace-Depth" required="true" max="1"/>
+                <rule avp="Trace-NE-Type-List" required="true" max="1"/>
+                <rule avp="Trace-Interface-List" required="false" max="1"/>
+                <rule avp="Trace-Event-List" required="true" max="1"/>
+                <rule avp="OMC-Id" required="false" max="1"/>
+                <rule avp="Trace-Collection-Entity" required="true" max="1"/>
+                <rule avp="MDT-Configuration" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+            </data>
+        </avp>avp>
+
+        <avp name="Trace-Reference" code="1459" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="Trace-Depth" code="1462" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="LIPA-PROHIBITED"/>
+                <item code="1" name="LIPA-ONLY"/>
+                <item code="2" name="LIPA-CONDITIONAL"/>
+            </data>
+        </avp>
+
+        <avp name="Trace-NE-Type-List" code="1463" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="Trace-Interface-List" code="1464" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="Trace-Event-List" code="1465" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="OMC-Id" code="1466" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="Trace-Collection-Entity" code="1452" must="M,V" may="P" may-encrypt="N" vendor-id="10415">
+            <data type="Address"/>
+        </avp>
+
+        <avp name="MDT-Configuration" code="1622" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="QoS-Class-Identifier" required="true" max="1"/>
+                <rule avp="Allocation-Retention-Priority" required="true" max="1"/>
+                <rule avp="AVP" required="false"/>
+            </data>
+        </avp>
+
+        <avp name="GPRS-Subscription-Data" code="1467" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="Complete-Data-List-Included-Indicator" required="true" max="1"/>
+                <rule avp="PDP-Context" required="true" max="50"/>
+                <rule avp="AVP" required="false"/>
+            </data>
+        </avp>
+
+        <avp name="Complete-Data-List-Included-Indicator" code="1468" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="All_PDP_CONTEXTS_INCLUDED"/>
+                <item code="1" name="MODIFIED/ADDED_PDP CONTEXTS_INCLUDED"/>
+            </data>
+        </avp>
+
+        <avp name="PDP-Context" code="1469" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="Context-Identifier" required="true" max="1"/>
+                <rule avp="PDP-Type" required="true" max="1"/>
+                <rule avp="PDP-Address" required="false" max="1"/>
+                <rule avp="QoS-Subscribed" required="true" max="1"/>
+                <rule avp="VPLMN-Dynamic-Address-Allowed" required="false" max="1"/>
+                <rule avp="Service-Selection" required="true" max="1"/>
+                <rule avp="TGPP-Charging-Characteristics" required="false" max="1"/>
+                <rule avp="Ext-PDP-Type" required="false" max="1"/>
+                <rule avp="Ext-PDP-Address" required="false" max="1"/>
+                <rule avp="AMBR" required="false" max="1"/>
+                <rule avp="SIPTO-Permission" required="false" max="1"/>
+                <rule avp="LIPA-Permission" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+            </data>
+        </avp>
+
+        <avp name="PDP-Type" code="1470" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="QoS-Subscribed" code="1404" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="Ext-PDP-Type" code="1620" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="Ext-PDP-Address" code="1621" must="V,M" may="P" must-not="-" may-encrypt="Y" vendor-id="10415">
+            <data type="Address"/>
+        </avp>
+
+        <avp name="CSG-Subscription-Data" code="1436" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="CSG-Id" required="true" max="1"/>
+                <rule avp="Expiration-Date" required="false" max="1"/>
+                <rule avp="Service-Selection" required="false"/>
+                <rule avp="AVP" required="false"/>
+            </data>
+        </avp>
+
+        <avp name="Expiration-Date" code="1439" must="V,M" may-encrypt="N" vendor-id="10415">
+            <data type="Time"/>
+        </avp>
+
+        <avp name="Roaming-Restricted-Due-To-Unsupported-Feature" code="1457" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="Roaming-Restricted-Due-To-Unsupported-Feature"/>
+            </data>
+        </avp>
+
+        <avp name="Subscribed-Periodic-RAU-TAU-Timer" code="1619" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="MPS-Priority" code="1616" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="VPLMN-LIPA-Allowed" code="1617" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="LIPA-NOTALLOWED"/>
+                <item code="1" name="LIPA-ALLOWED"/>
+            </data>
+        </avp>
+
+        <avp name="Relay-Node-Indicator" code="1633" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="NOT_RELAY_NODE"/>
+                <item code="1" name="RELAY_NODE"/>
+            </data>
+        </avp>
+
+        <avp name="MDT-User-Consent" code="1634" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="CONSENT_NOT_GIVEN"/>
+                <item code="1" name="CONSENT_GIVEN"/>
+            </data>
+        </avp>
+
+        <avp name="Requested-EUTRAN-Authentication-Info" code="1408" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="Number-Of-Requested-Vectors" required="false" max="1"/>
+                <rule avp="Immediate-Response-Preferred" required="false" max="1"/>
+                <rule avp="Re-synchronization-Info" required="false" max="1"/>
+            </data>
+        </avp>
+
+        <avp name="Number-Of-Requested-Vectors" code="1410" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="Re-synchronization-Info" code="1411" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="Immediate-Response-Preferred" code="1412" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="Requested-UTRAN-GERAN-Authentication-Info" code="1409" vendor-id="10415" must="M,V" may-encrypt="N">
+            <data type="Grouped">
+                <rule avp="Number-Of-Requested-Vectors" required="false" max="1"/>
+                <rule avp="Immediate-Response-Preferred" required="false" max="1"/>
+                <rule avp="Re-synchronization-Info" required="false" max="1"/>
+            </data>
+        </avp>
+
+        <avp name="Visited-PLMN-Id" code="1407" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="Error-Diagnostic" code="1614" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="GPRS_DATA_SUBSCRIBED"/>
+                <item code="1" name="NO_GPRS_DATA_SUBSCRIBED"/>
+                <item code="2" name="ODB-ALL-APN"/>
+                <item code="3" name="ODB-HPLMN-APN"/>
+                <item code="4" name="ODB-VPLMN-APN"/>
+            </data>
+        </avp>
+
+        <avp name="Authentication-Info" code="1413" vendor-id="10415" must="M,V" may-encrypt="N">
+            <data type="Grouped">
+                <rule avp="E-UTRAN-Vector" required="false"/>
+                <rule avp="UTRAN-Vector" required="false"/>
+                <rule avp="GERAN-Vector" required="false"/>
+            </data>
+        </avp>
+
+        <avp name="E-UTRAN-Vector" code="1414" vendor-id="10415" must="M,V" may-encrypt="N">
+            <data type="Grouped">
+                <rule avp="Item-Number" required="false" max="1"/>
+                <rule avp="RAND" required="true" max="1"/>
+                <rule avp="XRES" required="true" max="1"/>
+                <rule avp="AUTN" required="true" max="1"/>
+                <rule avp="KASME" required="true" max="1"/>
+            </data>
+        </avp>
+
+        <avp name="UTRAN-Vector" code="1415" vendor-id="10415" must="M,V" may-encrypt="N">
+            <data type="Grouped">
+                <rule avp="Item-Number" required="false"/>
+                <rule avp="RAND" required="true"/>
+                <rule avp="XRES" required="true"/>
+                <rule avp="AUTN" required="true"/>
+                <rule avp="Confidentiality-Key" required="true"/>
+                <rule avp="Integrity-Key" required="true"/>
+            </data>
+        </avp>
 
-        <avp name="Subscription-Data" code="1400" vendor-id="10415" must="M,V" may-encrypt="N">
+        <avp name="GERAN-Vector" code="1416" vendor-id="10415" must="M,V" may-encrypt="N">
             <data type="Grouped">
-                <rule avp="Subscriber-Status" required="false" max="1"/>
-                <rule avp="MSISDN" required="false" max="1"/>
-                <rule avp="STN-SR" required="false" max="1"/>
-                <rule avp="ICS-Indicator" required="false" max="1"/>
-                <rule avp="Network-Access-Mode" required="false" max="1"/>
-                <rule avp="Operator-Determined-Barring" required="false" max="1"/>
-                <rule avp="HPLMN-ODB" required="false" max="1"/>
-                <rule avp="Regional-Subscription-Zone-Code" required="false" max="10"/>
-                <rule avp="Access-Restriction-Data" required="false" max="1"/>
-                <rule avp="APN-OI-Replacement" required="false" max="1"/>
-                <rule avp="LCS-Info" required="false" max="1"/>
-                <rule avp="Teleservice-List" required="false" max="1"/>
-                <rule avp="Call-Barring-Info" required="false"/>
-                <rule avp="TGPP-Charging-Characteristics" required="false" max="1"/>
-                <rule avp="AMBR" required="false" max="1"/>
-                <rule avp="APN-Configuration-Profile" required="false" max="1"/>
-                <rule avp="RAT-Frequency-Selection-Priority-ID" required="false" max="1"/>
-                <rule avp="Trace-Data" required="false" max="1"/>
-                <rule avp="GPRS-Subscription-Data" required="false" max="1"/>
-                <rule avp="CSG-Subscription-Data" required="false"/>
-                <rule avp="Roaming-Restricted-Due-To-Unsupported-Feature" required="false" max="1"/>
-                <rule avp="Subscribed-Periodic-RAU-TAU-Timer" required="false" max="1"/>
-                <rule avp="MPS-Priority" required="false" max="1"/>
-                <rule avp="VPLMN-LIPA-Allowed" required="false" max="1"/>
-                <rule avp="Relay-Node-Indicator" required="false" max="1"/>
-                <rule avp="MDT-User-Consent" required="false" max="1"/>
+                <rule avp="Item-Number" required="false" max="1"/>
+                <rule avp="RAND" required="true" max="1"/>
+                <rule avp="XRES" required="true" max="1"/>
+                <rule avp="Kc" required="true" max="1"/>
+            </data>
+        </avp>
+
+        <avp name="Item-Number" code="1419" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="Cancellation-Type" code="1420" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="MME_UPDATE_PROCEDURE"/>
+                <item code="1" name="SGSN_UDPATE_PROCEDURE"/>
+                <item code="2" name="SUBSCRIPTION_WITHDRAWAL"/>
+                <item code="3" name="UPDATE_PROCEDURE_IWF"/>
+                <item code="4" name="INITIAL_ATTACH_PROCEDURE"/>
+            </data>
+        </avp>
+
+        <avp name="RAND" code="1447" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="XRES" code="1448" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="AUTN" code="1449" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="KASME" code="1450" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="Kc" code="1453" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="Confidentiality-Key" code="625" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="Integrity-Key" code="626" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="ULR-Flags" code="1405" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="ULA-Flags" code="1406" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="IDR-Flags" code="1490" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="IDA-Flags" code="1441" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="CLR-Flags" code="1638" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="UE-SRVCC-Capability" code="1615" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="UE-SRVCC-NOT-SUPPORTED"/>
+                <item code="1" name="UE-SRVCC-SUPPORTED"/>
+            </data>
+        </avp>
+
+        <avp name="Homogeneous-Support-of-IMS-Voice-Over-PS-Sessions" code="1493" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="NOT-SUPPORTED"/>
+                <item code="1" name="SUPPORTED"/>
+            </data>
+        </avp>
+
+        <avp name="Active-APN" code="1612" vendor-id="10415" must="M,V" may-encrypt="N">
+            <data type="Grouped">
+                <rule avp="Context-Identifier" required="true" max="1"/>
+                <rule avp="Service-Selection" required="false" max="1"/>
+                <rule avp="MIP6-Agent-Info" required="false" max="1"/>
+                <rule avp="Visited-Network-Identifier" required="false" max="1"/>
+                <rule avp="Specific-APN-Info" required="false" max="1"/>
                 <rule avp="AVP" required="false"/>
             </data>
         </avp>
 
-        <avp name="Subscriber-Status" code="1424" must="M,V" may-encrypt="N" vendor-id="10415">
+        <avp name="Specific-APN-Info" code="1472" vendor-id="10415" must="M,V" may-encrypt="N">
+            <data type="Grouped">
+                <rule avp="Service-Selection" required="true" max="1"/>
+                <rule avp="MIP6-Agent-Info" required="true" max="1"/>
+                <rule avp="Visited-Network-Identifier" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+            </data>
+        </avp>
+
+        <avp name="PUR-Flags" code="1635" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Unsigned32" />
+        </avp>
+
+        <avp name="PUA-Flags" code="1442" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Unsigned32" />
+        </avp>
+
+        <avp name="NOR-Flags" code="1443" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="Unsigned32" />
+        </avp>
+
+        <avp name="Subscribed-VSRVCC" code="1636" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
             <data type="Enumerated">
-                <item code="0" name="SERVICE_GRANTED"/>
-                <item code="1" name="OPERATOR_DETERMINED_BARRING"/>
+                <item code="0" name="VSRVCC_SUBSCRIBED" />
             </data>
         </avp>
 
-        <avp name="STN-SR" code="1433" must="M,V" may-encrypt="N" vendor-id="10415">
+        <avp name="MIP-Home-Agent-Address" code="334" must="M" must-not="V" vendor-id="0">
+            <data type="Address"/>
+        </avp>
+
+        <!-- RFC 4004 -->
+        <avp name="MIP-Home-Agent-Host" code="348" must="M" may="P" must-not="V" may-encrypt="Y" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="Destination-Realm" required="true" max="1"/>
+                <rule avp="Destination-Host" required="true" max="1"/>
+                <rule avp="AVP" required="false"/>
+            </data>
+        </avp>
+
+        <!-- RFC 5447 Diameter Mobile IPv6: Support for Network Access Server to Diameter Server Interaction -->
+        <avp name="MIP6-Agent-Info" code="486" must="M" may="P" must-not="V" may-encrypt="Y" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="MIP-Home-Agent-Address" required="false" max="2"/>
+                <rule avp="MIP-Home-Agent-Host" required="false" max="1"/>
+                <rule avp="MIP6-Home-Link-Prefix" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+            </data>
+        </avp>
+
+        <avp name="Service-Selection" code="493" must="M" may="P" must-not="V" may-encrypt="Y" vendor-id="10415">
+            <data type="UTF8String"/>
+        </avp>
+
+        <avp name="Visited-Network-Identifier" code="600" must="M,V" may-encrypt="N" vendor-id="10415">
             <data type="OctetString"/>
         </avp>
 
-        <avp name="ICS-Indicator" code="1491" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+        <avp name="MIP6-Home-Link-Prefix" code="125" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="User-Id" code="1444" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="UTF8String"/>
+        </avp>
+
+        <avp name="Equipment-Status" code="1445" must="M,V" may-encrypt="N" vendor-id="10415">
             <data type="Enumerated">
-                <item code="0" name="FALSE"/>
-                <item code="1" name="TRUE"/>
+                <item code="0" name="WHITELISTED"/>
+                <item code="1" name="BLACKLISTED"/>
+                <item code="2" name="GREYLISTED"/>
             </data>
         </avp>
 
-        <avp name="Network-Access-Mode" code="1417" must="M,V" may-encrypt="N" vendor-id="10415">
+        <avp name="SRES" code="1454" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="IMS-Voice-Over-PS-Sessions-Supported" code="1492" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
             <data type="Enumerated">
-                <item code="0" name="PACKET_AND_CIRCUIT"/>
-                <item code="1" name="RESERVED"/>
-                <item code="2" name="ONLY_PACKET"/>
+                <item code="0" name="NOT_SUPPORTED"/>
+                <item code="1" name="SUPPORTED"/>
             </data>
         </avp>
 
-        <avp name="Operator-Determined-Barring" code="1425" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="Unsigned32"/>
+        <avp name="Last-UE-Activity-Time" code="1494" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Time"/>
         </avp>
 
-        <avp name="HPLMN-ODB" code="1418" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="Unsigned32"/>
+        <avp name="EPS-User-State" code="1495" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="MME-User-State" required="false" max="1"/>
+                <rule avp="SGSN-User-State" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+            </data>
         </avp>
 
-        <avp name="Regional-Subscription-Zone-Code" code="1446" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="OctetString"/>
+        <avp name="EPS-Location-Information" code="1496" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="MME-Location-Information" required="false" max="1"/>
+                <rule avp="SGSN-Location-Information" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+            </data>
         </avp>
 
-        <avp name="Access-Restriction-Data" code="1426" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="Unsigned32"/>
+        <avp name="MME-User-State" code="1497" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="User-State" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+            </data>
         </avp>
 
-        <avp name="APN-OI-Replacement" code="1427" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="UTF8String"/>
+        <avp name="SGSN-User-State" code="1498" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="User-State" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+            </data>
         </avp>
 
-        <avp name="LCS-Info" code="1473" vendor-id="10415" must="M,V" may-encrypt="N">
+        <avp name="User-State" code="1499" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="DETACHED"/>
+                <item code="1" name="ATTACHED_NOT_REACHABLE_FOR_PAGING"/>
+                <item code="2" name="ATTACHED_REACHABLE_FOR_PAGING"/>
+                <item code="3" name="CONNECTED_NOT_REACHABLE_FOR_PAGING"/>
+                <item code="4" name="CONNECTED_REACHABLE_FOR_PAGING"/>
+                <item code="5" name="NETWORK_DETERMINED_NOT_REACHABLE"/>
+            </data>
+        </avp>
+
+        <avp name="MME-Location-Information" code="1600" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
             <data type="Grouped">
-                <rule avp="GMLC-Number" required="false"/>
-                <rule avp="LCS-PrivacyException" required="false"/>
-                <rule avp="MO-LR" required="false"/>
+                <rule avp="E-UTRAN-Cell-Global-Identity" required="false" max="1"/>
+                <rule avp="Tracking-Area-Identity" required="false" max="1"/>
+                <rule avp="Geographical-Information" required="false" max="1"/>
+                <rule avp="Geodetic-Information" required="false" max="1"/>
+                <rule avp="Current-Location-Retrieved" required="false" max="1"/>
+                <rule avp="Age-Of-Location-Information" required="false" max="1"/>
                 <rule avp="AVP" required="false"/>
             </data>
-        </avp>avp>
-
-        <avp name="GMLC-Number" code="1474" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="OctetString"/>
         </avp>
 
-        <avp name="LCS-PrivacyException" code="1475" vendor-id="10415" must="M,V" may-encrypt="N">
+        <avp name="SGSN-Location-Information" code="1601" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
             <data type="Grouped">
-                <rule avp="SS-Code" required="true" max="1"/>
-                <rule avp="SS-Status" required="true" max="1"/>
-                <rule avp="Notification-To-UE-User" required="false" max="1"/>
-                <rule avp="External-Client" required="false"/>
-                <rule avp="PLMN-Client" required="false"/>
-                <rule avp="TGPP-Service-Type" required="false"/>
+                <rule avp="Cell-Global-Identity" required="false" max="1"/>
+                <rule avp="Location-Area-Identity" required="false" max="1"/>
+                <rule avp="Service-Area-Identity" required="false" max="1"/>
+                <rule avp="Routing-Area-Identity" required="false" max="1"/>
+                <rule avp="Geographical-Information" required="false" max="1"/>
+                <rule avp="Geodetic-Information" required="false" max="1"/>
+                <rule avp="Current-Location-Retrieved" required="false" max="1"/>
+                <rule avp="Age-Of-Location-Information" required="false" max="1"/>
                 <rule avp="AVP" required="false"/>
             </data>
-        </avp>avp>
+        </avp>
 
-        <avp name="SS-Code" code="1476" must="M,V" may-encrypt="N" vendor-id="10415">
+        <avp name="E-UTRAN-Cell-Global-Identity" code="1602" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
             <data type="OctetString"/>
         </avp>
 
-        <avp name="SS-Status" code="1477" must="M,V" may-encrypt="N" vendor-id="10415">
+        <avp name="Tracking-Area-Identity" code="1603" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
             <data type="OctetString"/>
         </avp>
 
-        <avp name="Notification-To-UE-User" code="1478" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="Enumerated">
-                <item code="0" name="NOTIFY_LOCATION_ALLOWED"/>
-                <item code="1" name="NOTIFYANDVERIFY_LOCATION_ALLOWED_IF_NO_RESPONSE"/>
-                <item code="2" name="NOTIFYANDVERIFY_LOCATION_NOT_ALLOWED_IF_NO_RESPONSE"/>
-                <item code="3" name="LOCATION_NOT_ALLOWED"/>
-            </data>
+        <avp name="Cell-Global-Identity" code="1604" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
         </avp>
 
-        <avp name="External-Client" code="1479" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="Grouped">
-                <rule avp="Client-Identity" required="true" max="1"/>
-                <rule avp="GMLC-Restriction" required="false" max="1"/>
-                <rule avp="Notification-To-UE-User" required="false" max="1"/>
-                <rule avp="AVP" required="false"/>
-            </data>
-        </avp>avp>
+        <avp name="Routing-Area-Identity" code="1605" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
 
-        <avp name="Client-Identity" code="1480" must="M,V" may-encrypt="N" vendor-id="10415">
+        <avp name="Location-Area-Identity" code="1606" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
             <data type="OctetString"/>
         </avp>
 
-        <avp name="GMLC-Restriction" code="1481" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="Enumerated">
-                <item code="0" name="GMLC_LIST"/>
-                <item code="1" name="HOME_COUNTRY"/>
-            </data>
+        <avp name="Service-Area-Identity" code="1607" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
         </avp>
 
-        <avp name="PLMN-Client" code="1482" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="Enumerated">
-                <item code="0" name="BROADCAST_SERVICE"/>
-                <item code="1" name="O_AND_M_HPLMN"/>
-                <item code="2" name="O_AND_M_VPLMN"/>
-                <item code="3" name="ANONYMOUS_LOCATION"/>
-                <item code="3" name="TARGET_UE_SUBSCRIBED_SERVICE"/>
-            </data>
+        <avp name="Geographical-Information" code="1608" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
         </avp>
 
-        <avp name="TGPP-Service-Type" code="1483" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="Grouped">
-                <rule avp="ServiceTypeIdentity" required="true" max="1"/>
-                <rule avp="GMLC-Restriction" required="false" max="1"/>
-                <rule avp="Notification-To-UE-User" required="false" max="1"/>
-                <rule avp="AVP" required="false"/>
+        <avp name="Geodetic-Information" code="1609" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="Current-Location-Retrieved" code="1610" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="ACTIVE_LOCATION_RETRIEVAL"/>
             </data>
         </avp>
 
-        <avp name="ServiceTypeIdentity" code="1484" must="M,V" may-encrypt="N" vendor-id="10415">
+        <avp name="Age-Of-Location-Information" code="1611" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
             <data type="Unsigned32"/>
         </avp>
 
-        <avp name="MO-LR" code="1485" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="Grouped">
-                <rule avp="SS-Code" required="true" max="1"/>
-                <rule avp="SS-Status" required="true" max="1"/>
-                <rule avp="AVP" required="false"/>
+        <!-- 3GPP TS 29.272 Notify and 3GPP TS 29.336 monitoring AVPs -->
+
+        <avp name="Alert-Reason" code="1434" must="M,V" must-not="-" may-encrypt="N" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="UE_PRESENT"/>
+                <item code="1" name="UE_MEMORY_AVAILABLE"/>
             </data>
         </avp>
 
-        <avp name="Teleservice-List" code="1486" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="Grouped">
-                <rule avp="TS-Code" required="true" max="1"/>
-                <rule avp="AVP" required="false"/>
-            </data>
+        <avp name="Emergency-Services" code="1538" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Unsigned32"/>
         </avp>
 
-        <avp name="TS-Code" code="1487" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="OctetString"/>
+        <avp name="Maximum-UE-Availability-Time" code="3329" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Time"/>
         </avp>
 
-        <avp name="Call-Barring-Info" code="1488" must="M,V" may-encrypt="N" vendor-id="10415">
+        <avp name="Monitoring-Event-Config-Status" code="3142" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
             <data type="Grouped">
-                <rule avp="SS-Code" required="true" max="1"/>
-                <rule avp="SS-Status" required="true" max="1"/>
+                <rule avp="Service-Report" required="false"/>
+                <rule avp="SCEF-Reference-ID" required="false" max="1"/>
+                <rule avp="SCEF-ID" required="false" max="1"/>
+                <rule avp="SCEF-Reference-ID-for-Deletion" required="false"/>
                 <rule avp="AVP" required="false"/>
             </data>
         </avp>
 
-        <avp name="AMBR" code="1435" must="M,V" may-encrypt="N" vendor-id="10415">
+        <avp name="Service-Report" code="3152" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
             <data type="Grouped">
-                <rule avp="Max-Requested-Bandwidth-UL" required="true" max="1"/>
-                <rule avp="Max-Requested-Bandwidth-DL" required="true" max="1"/>
+                <rule avp="Service-Result" required="false" max="1"/>
+                <rule avp="Node-Type" required="false" max="1"/>
                 <rule avp="AVP" required="false"/>
             </data>
         </avp>
 
-        <avp name="APN-Configuration-Profile" code="1429" must="M,V" may-encrypt="N" vendor-id="10415">
+        <avp name="Service-Result" code="3146" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
             <data type="Grouped">
-                <rule avp="Context-Identifier" required="true" max="1"/>
-                <rule avp="All-APN-Configurations-Included-Indicator" required="true" max="1"/>
-                <rule avp="APN-Configuration" required="true"/>
+                <rule avp="Vendor-Id" required="false" max="1"/>
+                <rule avp="Service-Result-Code" required="false" max="1"/>
                 <rule avp="AVP" required="false"/>
             </data>
         </avp>
 
-        <avp name="Context-Identifier" code="1423" must="M,V" may-encrypt="N" vendor-id="10415">
+        <avp name="Service-Result-Code" code="3147" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
             <data type="Unsigned32"/>
         </avp>
 
-        <avp name="All-APN-Configurations-Included-Indicator" code="1428" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="Enumerated">
-                <item code="0" name="All_APN_CONFIGURATIONS_INCLUDED"/>
-                <item code="1" name="MODIFIED|ADDED_APN_CONFIGURATIONS_INCLUDED"/>
-            </data>
+        <avp name="Node-Type" code="3153" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Unsigned32"/>
         </avp>
 
-        <avp name="APN-Configuration" code="1430" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="Grouped">
-                <rule avp="Context-Identifier" required="true" max="1"/>
-                <rule avp="Served-Party-IP-Address" required="false" max="2"/>
-                <rule avp="PDN-Type" required="true" max="1"/>
-                <rule avp="Service-Selection" required="true" max="1"/>
-                <rule avp="EPS-Subscribed-QoS-Profile" required="false" max="1"/>
-                <rule avp="VPLMN-Dynamic-Address-Allowed" required="false" max="1"/>
-                <rule avp="MIP6-Agent-Info" required="false" max="1"/>
-                <rule avp="Visited-Network-Identifier" required="false" max="1"/>
-                <rule avp="PDN-GW-Allocation-Type" required="false" max="1"/>
-                <rule avp="TGPP-Charging-Characteristics" required="false" max="1"/>
-                <rule avp="AMBR" required="false" max="1"/>
-                <rule avp="Specific-APN-Info" required="false"/>
-                <rule avp="APN-OI-Replacement" required="false" max="1"/>
-                <rule avp="SIPTO-Permission" required="false" max="1"/>
-                <rule avp="LIPA-Permission" required="false" max="1"/>
-                <rule avp="AVP" required="false"/>
-            </data>
+        <avp name="SCEF-Reference-ID" code="3124" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Unsigned32"/>
         </avp>
 
-        <avp name="Served-Party-IP-Address" code="848" must="M,V" may="P" may-encrypt="N" vendor-id="10415">
-            <data type="Address"/>
+        <avp name="SCEF-ID" code="3125" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="DiameterIdentity"/>
         </avp>
 
-        <avp name="PDN-Type" code="1456" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="Enumerated">
-                <item code="0" name="IPv4"/>
-                <item code="1" name="IPv6"/>
-                <item code="2" name="IPv4v6"/>
-                <item code="3" name="IPv4_OR_IPv6"/>
-            </data>
+        <avp name="SCEF-Reference-ID-for-Deletion" code="3126" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Unsigned32"/>
         </avp>
 
-        <avp name="EPS-Subscribed-QoS-Profile" code="1431" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="Grouped">
-                <rule avp="QoS-Class-Identifier" required="true" max="1"/>
-                <rule avp="Allocation-Retention-Priority" required="true" max="1"/>
+    </application>
+</diameter>`
+
+var tgpps6bXML = `<?xml version="1.0" encoding="UTF-8"?>
+<diameter>
+    <!--
+        3GPP TS 29.273 Section 9
+        AVPs not defined here are looked up in the SWx dictionary.
+    -->
+    <application id="16777272" type="auth" name="TGPP S6b">
+        <vendor id="10415" name="TGPP"/>
+        <command code="265" short="AA" name="AA">
+            <request>
+                <!-- 3GPP TS 29.273 Section 9.2.2.2.1 -->
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Auth-Application-Id" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Destination-Host" required="false" max="1"/>
+                <rule avp="Destination-Realm" required="true" max="1"/>
+                <rule avp="Auth-Request-Type" required="true" max="1"/>
+                <rule avp="User-Name" required="true" max="1"/>
+                <rule avp="RAT-Type" required="false" max="1"/>
+                <rule avp="MIP6-Agent-Info" required="false" max="1"/>
+                <rule avp="MIP6-Feature-Vector" required="false" max="1"/>
+                <rule avp="Visited-Network-Identifier" required="false" max="1"/>
+                <rule avp="Service-Selection" required="false" max="1"/>
+                <rule avp="UE-Local-IP-Address" required="false" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="AVP" required="false"/>
+            </request>
+            <answer>
+                <!-- 3GPP TS 29.273 Section 9.2.2.2.2 -->
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Auth-Application-Id" required="true" max="1"/>
+                <rule avp="Auth-Request-Type" required="true" max="1"/>
+                <rule avp="Result-Code" required="false" max="1"/>
+                <rule avp="Experimental-Result" required="false" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Session-Timeout" required="false" max="1"/>
+                <rule avp="Authorization-Lifetime" required="false" max="1"/>
+                <rule avp="Auth-Grace-Period" required="false" max="1"/>
+                <rule avp="MIP6-Feature-Vector" required="false" max="1"/>
+                <rule avp="Mobile-Node-Identifier" required="false" max="1"/>
+                <rule avp="Service-Selection" required="false" max="1"/>
+                <rule avp="APN-Configuration" required="false"/>
+                <rule avp="Trace-Info" required="false" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="AVP" required="false"/>
+            </answer>
+        </command>
+        <command code="268" short="DE" name="Diameter-EAP">
+            <request>
+                <!-- 3GPP TS 29.273 Section 9.2.2.1.1 -->
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Auth-Application-Id" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Destination-Realm" required="true" max="1"/>
+                <rule avp="Auth-Request-Type" required="true" max="1"/>
+                <rule avp="EAP-Payload" required="true" max="1"/>
+                <rule avp="User-Name" required="false" max="1"/>
+                <rule avp="RAT-Type" required="false" max="1"/>
+                <rule avp="Service-Selection" required="false" max="1"/>
+                <rule avp="MIP6-Feature-Vector" required="false" max="1"/>
+                <rule avp="Visited-Network-Identifier" required="false" max="1"/>
+                <rule avp="DER-S6b-Flags" required="false" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="AVP" required="false"/>
+            </request>
+            <answer>
+                <!-- 3GPP TS 29.273 Section 9.2.2.1.2 -->
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Auth-Application-Id" required="true" max="1"/>
+                <rule avp="Auth-Request-Type" required="true" max="1"/>
+                <rule avp="Result-Code" required="false" max="1"/>
+                <rule avp="Experimental-Result" required="false" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="User-Name" required="false" max="1"/>
+                <rule avp="EAP-Payload" required="false" max="1"/>
+                <rule avp="EAP-Master-Session-Key" required="false" max="1"/>
+                <rule avp="Mobile-Node-Identifier" required="false" max="1"/>
+                <rule avp="APN-Configuration" required="false"/>
+                <rule avp="MIP6-Feature-Vector" required="false" max="1"/>
+                <rule avp="Session-Timeout" required="false" max="1"/>
+                <rule avp="Trace-Info" required="false" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="AVP" required="false"/>
+            </answer>
+        </command>
+        <command code="275" short="ST" name="Session-Termination">
+            <request>
+                <!-- 3GPP TS 29.273 Section 9.2.2.3.1 -->
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Auth-Application-Id" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Destination-Realm" required="true" max="1"/>
+                <rule avp="Termination-Cause" required="true" max="1"/>
+                <rule avp="User-Name" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+            </request>
+            <answer>
+                <!-- 3GPP TS 29.273 Section 9.2.2.3.2 -->
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Result-Code" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="AVP" required="false"/>
+            </answer>
+        </command>
+        <command code="274" short="AS" name="Abort-Session">
+            <request>
+                <!-- 3GPP TS 29.273 Section 9.2.2.4.1 -->
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Auth-Application-Id" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Destination-Realm" required="true" max="1"/>
+                <rule avp="Destination-Host" required="true" max="1"/>
+                <rule avp="User-Name" required="false" max="1"/>
+                <rule avp="Auth-Session-State" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+            </request>
+            <answer>
+                <!-- 3GPP TS 29.273 Section 9.2.2.4.2 -->
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Result-Code" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
                 <rule avp="AVP" required="false"/>
-            </data>
-        </avp>
-
-        <avp name="VPLMN-Dynamic-Address-Allowed" code="1432" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="Enumerated">
-                <item code="0" name="NOTALLOWED"/>
-                <item code="1" name="ALLOWED"/>
-            </data>
-        </avp>
-
-        <avp name="PDN-GW-Allocation-Type" code="1438" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="Enumerated">
-                <item code="0" name="STATIC"/>
-                <item code="1" name="DYNAMIC"/>
-            </data>
-        </avp>
-
-        <avp name="SIPTO-Permission" code="1613" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
-            <data type="Enumerated">
-                <item code="0" name="SIPTO_ALLOWED"/>
-                <item code="1" name="SIPTO_NOTALLOWED"/>
-            </data>
-        </avp>
-
-        <avp name="LIPA-Permission" code="1618" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
-            <data type="Enumerated">
-                <item code="0" name="LIPA-PROHIBITED"/>
-                <item code="1" name="LIPA-ONLY"/>
-                <item code="2" name="LIPA-CONDITIONAL"/>
-            </data>
-        </avp>
-
-        <avp name="RAT-Frequency-Selection-Priority-ID" code="1440" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="Unsigned32"/>
-        </avp>
-
-        <avp name="Trace-Data" code="1458" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="Grouped">
-                <rule avp="Trace-Reference" required="true" max="1"/>
-                <rule avp="Trace-Depth" required="true" max="1"/>
-                <rule avp="Trace-NE-Type-List" required="true" max="1"/>
-                <rule avp="Trace-Interface-List" required="false" max="1"/>
-                <rule avp="Trace-Event-List" required="true" max="1"/>
-                <rule avp="OMC-Id" required="false" max="1"/>
-                <rule avp="Trace-Collection-Entity" required="true" max="1"/>
-                <rule avp="MDT-Configuration" required="false" max="1"/>
+            </answer>
+        </command>
+        <command code="258" short="RA" name="Re-Auth">
+            <request>
+                <!-- 3GPP TS 29.273 Section 9.2.2.5.1 -->
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Auth-Application-Id" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Destination-Realm" required="true" max="1"/>
+                <rule avp="Destination-Host" required="true" max="1"/>
+                <rule avp="Re-Auth-Request-Type" required="true" max="1"/>
+                <rule avp="User-Name" required="false" max="1"/>
+                <rule avp="RAR-Flags" required="false" max="1"/>
                 <rule avp="AVP" required="false"/>
-            </data>
-        </avp>avp>
-
-        <avp name="Trace-Reference" code="1459" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="OctetString"/>
-        </avp>
-
-        <avp name="Trace-Depth" code="1462" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="Enumerated">
-                <item code="0" name="LIPA-PROHIBITED"/>
-                <item code="1" name="LIPA-ONLY"/>
-                <item code="2" name="LIPA-CONDITIONAL"/>
-            </data>
-        </avp>
-
-        <avp name="Trace-NE-Type-List" code="1463" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="OctetString"/>
-        </avp>
+            </request>
+            <answer>
+                <!-- 3GPP TS 29.273 Section 9.2.2.5.2 -->
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Result-Code" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="AVP" required="false"/>
+            </answer>
+        </command>
 
-        <avp name="Trace-Interface-List" code="1464" must="M,V" may-encrypt="N" vendor-id="10415">
+        <avp name="EAP-Key-Name" code="102" must="-" may="M" must-not="V" may-encrypt="Y" vendor-id="0">
+            <!-- RFC 4072 Section 4.1.4 -->
             <data type="OctetString"/>
         </avp>
 
-        <avp name="Trace-Event-List" code="1465" must="M,V" may-encrypt="N" vendor-id="10415">
+        <avp name="EAP-Payload" code="462" must="M" may="P" must-not="V" may-encrypt="Y" vendor-id="0">
+            <!-- RFC 4072 Section 4.1.1 -->
             <data type="OctetString"/>
         </avp>
 
-        <avp name="OMC-Id" code="1466" must="M,V" may-encrypt="N" vendor-id="10415">
+        <avp name="EAP-Reissued-Payload" code="463" must="M" may="P" must-not="V" may-encrypt="Y" vendor-id="0">
+            <!-- RFC 4072 Section 4.1.2 -->
             <data type="OctetString"/>
         </avp>
 
-        <avp name="Trace-Event-List" code="1465" must="M,V" may-encrypt="N" vendor-id="10415">
+        <avp name="EAP-Master-Session-Key" code="464" must="-" may="M" must-not="V" may-encrypt="Y" vendor-id="0">
+            <!-- RFC 4072 Section 4.1.3 -->
             <data type="OctetString"/>
         </avp>
 
-        <avp name="Trace-Collection-Entity" code="1452" must="M,V" may="P" may-encrypt="N" vendor-id="10415">
-            <data type="Address"/>
-        </avp>
-
-        <avp name="MDT-Configuration" code="1622" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="Grouped">
-                <rule avp="QoS-Class-Identifier" required="true" max="1"/>
-                <rule avp="Allocation-Retention-Priority" required="true" max="1"/>
-                <rule avp="AVP" required="false"/>
-            </data>
-        </avp>
-
-        <avp name="GPRS-Subscription-Data" code="1467" must="M,V" may-encrypt="N" vendor-id="10415">
+        <avp name="MIP6-Agent-Info" code="486" must="M" may="P" must-not="V" may-encrypt="Y" vendor-id="0">
+            <!-- RFC 5447 Section 4.2.1 -->
             <data type="Grouped">
-                <rule avp="Complete-Data-List-Included-Indicator" required="true" max="1"/>
-                <rule avp="PDP-Context" required="true" max="50"/>
+                <rule avp="MIP-Home-Agent-Address" required="false" max="2"/>
+                <rule avp="MIP-Home-Agent-Host" required="false" max="1"/>
+                <rule avp="MIP6-Home-Link-Prefix" required="false" max="1"/>
                 <rule avp="AVP" required="false"/>
             </data>
         </avp>
 
-        <avp name="Complete-Data-List-Included-Indicator" code="1468" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="Enumerated">
-                <item code="0" name="All_PDP_CONTEXTS_INCLUDED"/>
-                <item code="1" name="MODIFIED/ADDED_PDP CONTEXTS_INCLUDED"/>
-            </data>
+        <avp name="MIP-Home-Agent-Address" code="334" must="M" may="P" must-not="V" may-encrypt="Y" vendor-id="0">
+            <!-- RFC 4004 Section 7.4 -->
+            <data type="Address"/>
         </avp>
 
-        <avp name="PDP-Context" code="1469" must="M,V" may-encrypt="N" vendor-id="10415">
+        <avp name="MIP-Home-Agent-Host" code="348" must="M" may="P" must-not="V" may-encrypt="Y" vendor-id="0">
+            <!-- RFC 4004 Section 7.11 -->
             <data type="Grouped">
-                <rule avp="Context-Identifier" required="true" max="1"/>
-                <rule avp="PDP-Type" required="true" max="1"/>
-                <rule avp="PDP-Address" required="false" max="1"/>
-                <rule avp="QoS-Subscribed" required="true" max="1"/>
-                <rule avp="VPLMN-Dynamic-Address-Allowed" required="false" max="1"/>
-                <rule avp="Service-Selection" required="true" max="1"/>
-                <rule avp="TGPP-Charging-Characteristics" required="false" max="1"/>
-                <rule avp="Ext-PDP-Type" required="false" max="1"/>
-                <rule avp="Ext-PDP-Address" required="false" max="1"/>
-                <rule avp="AMBR" required="false" max="1"/>
-                <rule avp="SIPTO-Permission" required="false" max="1"/>
-                <rule avp="LIPA-Permission" required="false" max="1"/>
+                <rule avp="Destination-Realm" required="true" max="1"/>
+                <rule avp="Destination-Host" required="true" max="1"/>
                 <rule avp="AVP" required="false"/>
             </data>
         </avp>
 
-        <avp name="PDP-Type" code="1470" must="M,V" may-encrypt="N" vendor-id="10415">
+        <avp name="MIP6-Home-Link-Prefix" code="125" must="M" may="P" must-not="V" may-encrypt="Y" vendor-id="0">
+            <!-- RFC 5447 Section 4.2.4 -->
             <data type="OctetString"/>
         </avp>
 
-        <avp name="QoS-Subscribed" code="1404" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="OctetString"/>
+        <avp name="Mobile-Node-Identifier" code="506" must="M" may="P" must-not="V" may-encrypt="Y" vendor-id="0">
+            <!-- RFC 5779 Section 5.6 -->
+            <data type="UTF8String"/>
         </avp>
 
-        <avp name="VPLMN-Dynamic-Address-Allowed" code="1432" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="Enumerated">
-                <item code="0" name="NOTALLOWED"/>
-                <item code="1" name="ALLOWED"/>
-            </data>
+
+        <avp name="RAR-Flags" code="1522" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <!-- 3GPP TS 29.273 Section 9.2.3.1.3 -->
+            <data type="Unsigned32"/>
         </avp>
 
-        <avp name="Ext-PDP-Type" code="1620" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="OctetString"/>
+        <avp name="DER-S6b-Flags" code="1523" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <!-- 3GPP TS 29.273 Section 9.2.3.3.1 -->
+            <data type="Unsigned32"/>
         </avp>
 
-        <avp name="Ext-PDP-Address" code="1621" must="V,M" may="P" must-not="-" may-encrypt="Y" vendor-id="10415">
+        <avp name="UE-Local-IP-Address" code="2805" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <!-- 3GPP TS 29.212 Section 5.3.96 -->
             <data type="Address"/>
         </avp>
+    </application>
+</diameter>`
+
+var tgppshXML = `<?xml version="1.0" encoding="UTF-8"?>
+<diameter>
+    <!--
+        3GPP TS 29.328 and 3GPP TS 29.329
+        See: http://www.etsi.org/deliver/etsi_ts/129300_129399/129329/12.05.00_60/ts_129329v120500p.pdf
+    -->
+    <application id="16777217" type="auth" name="TGPP Sh">
+        <vendor id="10415" name="TGPP"/>
+        <command code="306" short="UD" name="User-Data">
+            <request>
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Vendor-Specific-Application-Id" required="true" max="1"/>
+                <rule avp="Auth-Session-State" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Destination-Host" required="false" max="1"/>
+                <rule avp="Destination-Realm" required="true" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="User-Identity" required="true" max="1"/>
+                <rule avp="Wildcarded-Public-Identity" required="false" max="1"/>
+                <rule avp="Server-Name" required="false" max="1"/>
+                <rule avp="Service-Indication" required="false"/>
+                <rule avp="Data-Reference" required="true"/>
+                <rule avp="Identity-Set" required="false"/>
+                <rule avp="Requested-Domain" required="false" max="1"/>
+                <rule avp="Current-Location" required="false" max="1"/>
+                <rule avp="DSAI-Tag" required="false"/>
+                <rule avp="Session-Priority" required="false" max="1"/>
+                <rule avp="User-Name" required="false" max="1"/>
+                <rule avp="Requested-Nodes" required="false" max="1"/>
+                <rule avp="UDR-Flags" required="false" max="1"/>
+                <rule avp="Serving-Node-Indication" required="false" max="1"/>
+                <rule avp="Pre-paging-Supported" required="false" max="1"/>
+                <rule avp="Local-Time-Zone-Indication" required="false" max="1"/>
+                <rule avp="Call-Reference-Info" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
+            </request>
+            <answer>
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Vendor-Specific-Application-Id" required="true" max="1"/>
+                <rule avp="Result-Code" required="false" max="1"/>
+                <rule avp="Experimental-Result" required="false" max="1"/>
+                <rule avp="Auth-Session-State" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="Wildcarded-Public-Identity" required="false" max="1"/>
+                <rule avp="User-Data" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+                <rule avp="Failed-AVP" required="false"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
+            </answer>
+        </command>
+        <command code="307" short="PU" name="Profile-Update">
+            <request>
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Vendor-Specific-Application-Id" required="true" max="1"/>
+                <rule avp="Auth-Session-State" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Destination-Host" required="false" max="1"/>
+                <rule avp="Destination-Realm" required="true" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="User-Identity" required="true" max="1"/>
+                <rule avp="Wildcarded-Public-Identity" required="false" max="1"/>
+                <rule avp="User-Name" required="false" max="1"/>
+                <rule avp="Data-Reference" required="true"/>
+                <rule avp="User-Data" required="true" max="1"/>
+                <rule avp="AVP" required="false"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
+            </request>
+            <answer>
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Vendor-Specific-Application-Id" required="true" max="1"/>
+                <rule avp="Result-Code" required="false" max="1"/>
+                <rule avp="Experimental-Result" required="false" max="1"/>
+                <rule avp="Auth-Session-State" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Wildcarded-Public-Identity" required="false" max="1"/>
+                <rule avp="Repository-Data-ID" required="false" max="1"/>
+                <rule avp="Data-Reference" required="false" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="AVP" required="false"/>
+                <rule avp="Failed-AVP" required="false"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
+            </answer>
+        </command>
+        <command code="308" short="SN" name="Subscribe-Notifications">
+            <request>
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Vendor-Specific-Application-Id" required="true" max="1"/>
+                <rule avp="Auth-Session-State" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Destination-Host" required="false" max="1"/>
+                <rule avp="Destination-Realm" required="true" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="User-Identity" required="true" max="1"/>
+                <rule avp="Wildcarded-Public-Identity" required="false" max="1"/>
+                <rule avp="Service-Indication" required="false"/>
+                <rule avp="Send-Data-Indication" required="false" max="1"/>
+                <rule avp="Server-Name" required="false" max="1"/>
+                <rule avp="Subs-Req-Type" required="true" max="1"/>
+                <rule avp="Data-Reference" required="true"/>
+                <rule avp="Identity-Set" required="false"/>
+                <rule avp="Expiry-Time" required="false" max="1"/>
+                <rule avp="DSAI-Tag" required="false"/>
+                <rule avp="One-Time-Notification" required="false" max="1"/>
+                <rule avp="User-Name" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
+            </request>
+            <answer>
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Vendor-Specific-Application-Id" required="true" max="1"/>
+                <rule avp="Auth-Session-State" required="true" max="1"/>
+                <rule avp="Result-Code" required="false" max="1"/>
+                <rule avp="Experimental-Result" required="false" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Wildcarded-Public-Identity" required="false" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="User-Data" required="false" max="1"/>
+                <rule avp="Expiry-Time" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+                <rule avp="Failed-AVP" required="false"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
+            </answer>
+        </command>
+        <command code="309" short="PN" name="Push-Notification">
+            <request>
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Vendor-Specific-Application-Id" required="true" max="1"/>
+                <rule avp="Auth-Session-State" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Destination-Host" required="true" max="1"/>
+                <rule avp="Destination-Realm" required="true" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="User-Identity" required="true" max="1"/>
+                <rule avp="Wildcarded-Public-Identity" required="false" max="1"/>
+                <rule avp="User-Name" required="false" max="1"/>
+                <rule avp="User-Data" required="true" max="1"/>
+                <rule avp="AVP" required="false"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
+            </request>
+            <answer>
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Vendor-Specific-Application-Id" required="true" max="1"/>
+                <rule avp="Result-Code" required="false" max="1"/>
+                <rule avp="Experimental-Result" required="false" max="1"/>
+                <rule avp="Auth-Session-State" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="AVP" required="false"/>
+                <rule avp="Failed-AVP" required="false"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
+            </answer>
+        </command>
 
-        <avp name="SIPTO-Permission" code="1613" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
-            <data type="Enumerated">
-                <item code="0" name="SIPTO_ALLOWED"/>
-                <item code="1" name="SIPTO_NOTALLOWED"/>
-            </data>
+        <avp name="Wildcarded-Public-Identity" code="634" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="UTF8String"/>
         </avp>
 
-        <avp name="CSG-Subscription-Data" code="1436" must="M,V" may-encrypt="N" vendor-id="10415">
+        <avp name="User-Identity" code="700" must="M,V" may-encrypt="N" vendor-id="10415">
             <data type="Grouped">
-                <rule avp="CSG-Id" required="true" max="1"/>
-                <rule avp="Expiration-Date" required="false" max="1"/>
-                <rule avp="Service-Selection" required="false"/>
+                <rule avp="Public-Identity" required="false" max="1"/>
+                <rule avp="MSISDN" required="false" max="1"/>
                 <rule avp="AVP" required="false"/>
             </data>
         </avp>
 
-        <avp name="Expiration-Date" code="1439" must="V,M" may-encrypt="N" vendor-id="10415">
-            <data type="Time"/>
+        <avp name="User-Data" code="702" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
         </avp>
 
-        <avp name="Roaming-Restricted-Due-To-Unsupported-Feature" code="1457" must="M,V" may-encrypt="N" vendor-id="10415">
+        <avp name="Data-Reference" code="703" must="M,V" may-encrypt="N" vendor-id="10415">
             <data type="Enumerated">
-                <item code="0" name="Roaming-Restricted-Due-To-Unsupported-Feature"/>
+                <item code="0" name="RepositoryData"/>
+                <item code="10" name="IMSPublicIdentity"/>
+                <item code="11" name="IMSUserState"/>
+                <item code="12" name="S-CSCFName"/>
+                <item code="13" name="InitialFilterCriteria"/>
+                <item code="14" name="LocationInformation"/>
+                <item code="15" name="UserState"/>
+                <item code="16" name="ChargingInformation"/>
+                <item code="17" name="MSISDN"/>
+                <item code="18" name="PSIActivation"/>
+                <item code="19" name="DSAI"/>
+                <item code="21" name="ServiceLevelTraceInfo"/>
+                <item code="22" name="IPAddressSecureBindingInformation"/>
+                <item code="23" name="ServicePriorityLevel"/>
+                <item code="24" name="SMSRegistrationInfo"/>
+                <item code="25" name="UEReachabilityForIP"/>
+                <item code="26" name="TADSinformation"/>
+                <item code="27" name="STN-SR"/>
+                <item code="28" name="UE-SRVCC-Capability"/>
+                <item code="29" name="ExtendedPriority"/>
+                <item code="30" name="CSRN"/>
+                <item code="31" name="ReferenceLocationInformation"/>
+                <item code="32" name="IMSI"/>
+                <item code="33" name="IMSPrivateUserIdentity"/>
+                <item code="34" name="IMEISV"/>
+                <item code="35" name="UE-5G-SRVCC-Capability"/>
             </data>
         </avp>
 
-        <avp name="Subscribed-Periodic-RAU-TAU-Timer" code="1619" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
-            <data type="Unsigned32"/>
-        </avp>
-
-        <avp name="MPS-Priority" code="1616" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
-            <data type="Unsigned32"/>
+        <avp name="Service-Indication" code="704" must="M,V" may-encrypt="N" vendor-id="10415">
+            <data type="OctetString"/>
         </avp>
 
-        <avp name="VPLMN-LIPA-Allowed" code="1617" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+        <avp name="Subs-Req-Type" code="705" must="M,V" may-encrypt="N" vendor-id="10415">
             <data type="Enumerated">
-                <item code="0" name="LIPA-NOTALLOWED"/>
-                <item code="1" name="LIPA-ALLOWED"/>
+                <item code="0" name="Subscribe"/>
+                <item code="1" name="Unsubscribe"/>
             </data>
         </avp>
 
-        <avp name="Relay-Node-Indicator" code="1633" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+        <avp name="Requested-Domain" code="706" must="M,V" may-encrypt="N" vendor-id="10415">
             <data type="Enumerated">
-                <item code="0" name="NOT_RELAY_NODE"/>
-                <item code="1" name="RELAY_NODE"/>
+                <item code="0" name="CS-Domain"/>
+                <item code="1" name="PS-Domain"/>
             </data>
         </avp>
 
-        <avp name="MDT-User-Consent" code="1634" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+        <avp name="Current-Location" code="707" must="M,V" may-encrypt="N" vendor-id="10415">
             <data type="Enumerated">
-                <item code="0" name="CONSENT_NOT_GIVEN"/>
-                <item code="1" name="CONSENT_GIVEN"/>
+                <item code="0" name="DoNotNeedInitiateActiveLocationRetrieval"/>
+                <item code="1" name="InitiateActiveLocationRetrieval"/>
             </data>
         </avp>
 
-        <avp name="Requested-EUTRAN-Authentication-Info" code="1408" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="Grouped">
-                <rule avp="Number-Of-Requested-Vectors" required="false" max="1"/>
-                <rule avp="Immediate-Response-Preferred" required="false" max="1"/>
-                <rule avp="Re-synchronization-Info" required="false" max="1"/>
+        <avp name="Identity-Set" code="708" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="ALL_IDENTITIES"/>
+                <item code="1" name="REGISTERED_IDENTITIES"/>
+                <item code="2" name="IMPLICIT_IDENTITIES"/>
+                <item code="3" name="ALIAS_IDENTITIES"/>
             </data>
         </avp>
 
-        <avp name="Number-Of-Requested-Vectors" code="1410" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="Unsigned32"/>
-        </avp>
-
-        <avp name="Re-synchronization-Info" code="1411" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="OctetString"/>
-        </avp>
-
-        <avp name="Immediate-Response-Preferred" code="1412" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="Unsigned32"/>
+        <avp name="Expiry-Time" code="709" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Time"/>
         </avp>
 
-        <avp name="Requested-UTRAN-GERAN-Authentication-Info" code="1409" vendor-id="10415" must="M,V" may-encrypt="N">
-            <data type="Grouped">
-                <rule avp="Number-Of-Requested-Vectors" required="false" max="1"/>
-                <rule avp="Immediate-Response-Preferred" required="false" max="1"/>
-                <rule avp="Re-synchronization-Info" required="false" max="1"/>
+        <avp name="Send-Data-Indication" code="710" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Enumerated">
+                <item code="0" name="USER_DATA_NOT_REQUESTED"/>
+                <item code="1" name="USER_DATA_REQUESTED"/>
             </data>
         </avp>
 
-        <avp name="Visited-PLMN-Id" code="1407" must="M,V" may-encrypt="N" vendor-id="10415">
+        <avp name="DSAI-Tag" code="711" must="M,V" may-encrypt="N" vendor-id="10415">
             <data type="OctetString"/>
         </avp>
 
-        <avp name="Error-Diagnostic" code="1614" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+        <avp name="One-Time-Notification" code="712" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
             <data type="Enumerated">
-                <item code="0" name="GPRS_DATA_SUBSCRIBED"/>
-                <item code="1" name="NO_GPRS_DATA_SUBSCRIBED"/>
-                <item code="2" name="ODB-ALL-APN"/>
-                <item code="3" name="ODB-HPLMN-APN"/>
-                <item code="4" name="ODB-VPLMN-APN"/>
-            </data>
-        </avp>
-
-        <avp name="Authentication-Info" code="1413" vendor-id="10415" must="M,V" may-encrypt="N">
-            <data type="Grouped">
-                <rule avp="E-UTRAN-Vector" required="false"/>
-                <rule avp="UTRAN-Vector" required="false"/>
-                <rule avp="GERAN-Vector" required="false"/>
-            </data>
-        </avp>
-
-        <avp name="E-UTRAN-Vector" code="1414" vendor-id="10415" must="M,V" may-encrypt="N">
-            <data type="Grouped">
-                <rule avp="Item-Number" required="false" max="1"/>
-                <rule avp="RAND" required="true" max="1"/>
-                <rule avp="XRES" required="true" max="1"/>
-                <rule avp="AUTN" required="true" max="1"/>
-                <rule avp="KASME" required="true" max="1"/>
-            </data>
-        </avp>
-
-        <avp name="UTRAN-Vector" code="1415" vendor-id="10415" must="M,V" may-encrypt="N">
-            <data type="Grouped">
-                <rule avp="Item-Number" required="false"/>
-                <rule avp="RAND" required="true"/>
-                <rule avp="XRES" required="true"/>
-                <rule avp="AUTN" required="true"/>
-                <rule avp="Confidentiality-Key" required="true"/>
-                <rule avp="Integrity-Key" required="true"/>
-            </data>
-        </avp>
-
-        <avp name="GERAN-Vector" code="1416" vendor-id="10415" must="M,V" may-encrypt="N">
-            <data type="Grouped">
-                <rule avp="Item-Number" required="false" max="1"/>
-                <rule avp="RAND" required="true" max="1"/>
-                <rule avp="XRES" required="true" max="1"/>
-                <rule avp="Kc" required="true" max="1"/>
+                <item code="0" name="ONE_TIME_NOTIFICATION_REQUESTED"/>
             </data>
         </avp>
 
-        <avp name="Item-Number" code="1419" must="M,V" may-encrypt="N" vendor-id="10415">
+        <avp name="Requested-Nodes" code="713" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
             <data type="Unsigned32"/>
         </avp>
 
-        <avp name="Cancellation-Type" code="1420" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="Enumerated">
-                <item code="0" name="MME_UPDATE_PROCEDURE"/>
-                <item code="1" name="SGSN_UDPATE_PROCEDURE"/>
-                <item code="2" name="SUBSCRIPTION_WITHDRAWAL"/>
-                <item code="3" name="UPDATE_PROCEDURE_IWF"/>
-                <item code="4" name="INITIAL_ATTACH_PROCEDURE"/>
+        <avp name="Repository-Data-ID" code="715" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <data type="Grouped">
+                <rule avp="Service-Indication" required="true" max="1"/>
+                <rule avp="Sequence-Number" required="true" max="1"/>
+                <rule avp="AVP" required="false"/>
             </data>
         </avp>
 
-        <avp name="RAND" code="1447" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="OctetString"/>
-        </avp>
-
-        <avp name="XRES" code="1448" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="OctetString"/>
-        </avp>
-
-        <avp name="AUTN" code="1449" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="OctetString"/>
-        </avp>
-
-        <avp name="KASME" code="1450" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="OctetString"/>
-        </avp>
-
-        <avp name="Kc" code="1453" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="OctetString"/>
-        </avp>
-
-        <avp name="Confidentiality-Key" code="625" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="OctetString"/>
-        </avp>
-
-        <avp name="Integrity-Key" code="626" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="OctetString"/>
-        </avp>
-
-        <avp name="ULR-Flags" code="1405" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="Unsigned32"/>
-        </avp>
-
-        <avp name="ULA-Flags" code="1406" must="M,V" may-encrypt="N" vendor-id="10415">
+        <avp name="Sequence-Number" code="716" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
             <data type="Unsigned32"/>
         </avp>
 
-        <avp name="CLR-Flags" code="1638" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+        <avp name="UDR-Flags" code="719" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
             <data type="Unsigned32"/>
         </avp>
 
-        <avp name="UE-SRVCC-Capability" code="1615" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+        <avp name="Serving-Node-Indication" code="714" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
             <data type="Enumerated">
-                <item code="0" name="UE-SRVCC-NOT-SUPPORTED"/>
-                <item code="1" name="UE-SRVCC-SUPPORTED"/>
+                <item code="0" name="ONLY_SERVING_NODES_REQUIRED"/>
             </data>
         </avp>
 
-        <avp name="Homogeneous-Support-of-IMS-Voice-Over-PS-Sessions" code="1493" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+        <avp name="Pre-paging-Supported" code="717" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
             <data type="Enumerated">
-                <item code="0" name="NOT-SUPPORTED"/>
-                <item code="1" name="SUPPORTED"/>
-            </data>
-        </avp>
-
-        <avp name="Active-APN" code="1612" vendor-id="10415" must="M,V" may-encrypt="N">
-            <data type="Grouped">
-                <rule avp="Context-Identifier" required="true" max="1"/>
-                <rule avp="Service-Selection" required="false" max="1"/>
-                <rule avp="MIP6-Agent-Info" required="false" max="1"/>
-                <rule avp="Visited-Network-Identifier" required="false" max="1"/>
-                <rule avp="Specific-APN-Info" required="false" max="1"/>
-                <rule avp="AVP" required="false"/>
-            </data>
-        </avp>
-
-        <avp name="Specific-APN-Info" code="1472" vendor-id="10415" must="M,V" may-encrypt="N">
-            <data type="Grouped">
-                <rule avp="Service-Selection" required="true" max="1"/>
-                <rule avp="MIP6-Agent-Info" required="true" max="1"/>
-                <rule avp="Visited-Network-Identifier" required="false" max="1"/>
-                <rule avp="AVP" required="false"/>
+                <item code="0" name="PREPAGING_NOT_SUPPORTED"/>
+                <item code="1" name="PREPAGING_SUPPORTED"/>
             </data>
         </avp>
 
-        <avp name="Context-Identifier" code="1423" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="Unsigned32"/>
-        </avp>
-
-        <avp name="PUR-Flags" code="1635" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
-            <data type="Unsigned32" />
-        </avp>
-
-        <avp name="PUA-Flags" code="1442" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="Unsigned32" />
-        </avp>
-
-        <avp name="NOR-Flags" code="1443" must="M,V" may-encrypt="N" vendor-id="10415">
-            <data type="Unsigned32" />
-        </avp>
-
-        <avp name="Subscribed-VSRVCC" code="1636" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+        <avp name="Local-Time-Zone-Indication" code="718" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
             <data type="Enumerated">
-                <item code="0" name="VSRVCC_SUBSCRIBED" />
-            </data>
-        </avp>
-
-        <avp name="MIP-Home-Agent-Address" code="334" must="M" must-not="V" vendor-id="0">
-            <data type="Address"/>
-        </avp>
-
-        <!-- RFC 4004 -->
-        <avp name="MIP-Home-Agent-Host" code="348" must="M" may="P" must-not="V" may-encrypt="Y" vendor-id="10415">
-            <data type="Grouped">
-                <rule avp="Destination-Realm" required="true" max="1"/>
-                <rule avp="Destination-Host" required="true" max="1"/>
-                <rule avp="AVP" required="false"/>
+                <item code="0" name="ONLY_LOCAL_TIME_ZONE_REQUESTED"/>
+                <item code="1" name="LOCAL_TIME_ZONE_WITH_LOCATION_INFO_REQUESTED"/>
             </data>
         </avp>
 
-        <!-- RFC 5447 Diameter Mobile IPv6: Support for Network Access Server to Diameter Server Interaction -->
-        <avp name="MIP6-Agent-Info" code="486" must="M" may="P" must-not="V" may-encrypt="Y" vendor-id="10415">
+        <avp name="Call-Reference-Info" code="720" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
             <data type="Grouped">
-                <rule avp="MIP-Home-Agent-Address" required="false" max="2"/>
-                <rule avp="MIP-Home-Agent-Host" required="false" max="1"/>
-                <rule avp="MIP6-Home-Link-Prefix" required="false" max="1"/>
+                <rule avp="Call-Reference-Number" required="true" max="1"/>
+                <rule avp="AS-Number" required="true" max="1"/>
                 <rule avp="AVP" required="false"/>
             </data>
         </avp>
 
-        <avp name="Service-Selection" code="493" must="M" may="P" must-not="V" may-encrypt="Y" vendor-id="10415">
-            <data type="UTF8String"/>
-        </avp>
-
-        <avp name="Visited-Network-Identifier" code="600" must="M,V" may-encrypt="N" vendor-id="10415">
+        <avp name="Call-Reference-Number" code="721" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
             <data type="OctetString"/>
         </avp>
 
-        <avp name="MIP6-Home-Link-Prefix" code="125" vendor-id="10415">
+        <avp name="AS-Number" code="722" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
             <data type="OctetString"/>
         </avp>
 
-        <avp name="User-Id" code="1444" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
-            <data type="UTF8String"/>
-        </avp>
-
     </application>
 </diameter>`
 
@@ -6075,26 +8683,55 @@ var tgppswxXML = `<?xml version="1.0" encoding="UTF-8"?>
                 <rule avp="AVP" required="false"/>
             </answer>
         </command>
-        <command code="304" short="RT" name="Registration-Termination">
+        <command code="304" short="RT" name="Registration-Termination">
+            <request>
+                <!-- http://www.qtc.jp/3GPP/Specs/29273-920.pdf Section 8.2.2.4 -->
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="DRMP" required="false" max="1" />
+                <rule avp="Vendor-Specific-Application-Id" required="true" max="1"/>
+                <rule avp="Auth-Session-State" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Destination-Host" required="false" max="1"/>
+                <rule avp="Destination-Realm" required="true" max="1"/>
+                <rule avp="User-Name" required="true" max="1"/>
+                <rule avp="Deregistration-Reason" required="true" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="AVP" required="false"/>
+            </request>
+            <answer>
+                <!-- http://www.qtc.jp/3GPP/Specs/29273-920.pdf Section 8.2.2.4 -->
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="DRMP" required="false" max="1" />
+                <rule avp="Vendor-Specific-Application-Id" required="true" max="1"/>
+                <rule avp="Result-Code" required="false" max="1"/>
+                <rule avp="Experimental-Result" required="false" max="1"/>
+                <rule avp="Auth-Session-State" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="AVP" required="false"/>
+            </answer>
+        </command>
+        <command code="305" short="PP" name="Push-Profile">
             <request>
-                <!-- http://www.qtc.jp/3GPP/Specs/29273-920.pdf Section 8.2.2.4 -->
+                <!-- 3GPP TS 29.273 Section 8.2.2.2 -->
                 <rule avp="Session-Id" required="true" max="1"/>
-                <rule avp="DRMP" required="false" max="1" />
                 <rule avp="Vendor-Specific-Application-Id" required="true" max="1"/>
                 <rule avp="Auth-Session-State" required="true" max="1"/>
                 <rule avp="Origin-Host" required="true" max="1"/>
                 <rule avp="Origin-Realm" required="true" max="1"/>
-                <rule avp="Destination-Host" required="false" max="1"/>
+                <rule avp="Destination-Host" required="true" max="1"/>
                 <rule avp="Destination-Realm" required="true" max="1"/>
                 <rule avp="User-Name" required="true" max="1"/>
-                <rule avp="Deregistration-Reason" required="true" max="1"/>
+                <rule avp="Non-3GPP-User-Data" required="false" max="1"/>
+                <rule avp="PPR-Flags" required="false" max="1"/>
                 <rule avp="Supported-Features" required="false"/>
                 <rule avp="AVP" required="false"/>
             </request>
             <answer>
-                <!-- http://www.qtc.jp/3GPP/Specs/29273-920.pdf Section 8.2.2.4 -->
+                <!-- 3GPP TS 29.273 Section 8.2.2.2 -->
                 <rule avp="Session-Id" required="true" max="1"/>
-                <rule avp="DRMP" required="false" max="1" />
                 <rule avp="Vendor-Specific-Application-Id" required="true" max="1"/>
                 <rule avp="Result-Code" required="false" max="1"/>
                 <rule avp="Experimental-Result" required="false" max="1"/>
@@ -6111,11 +8748,16 @@ var tgppswxXML = `<?xml version="1.0" encoding="UTF-8"?>
             <data type="Enumerated">
                 <item code="0" name="WLAN"/>
                 <item code="1" name="VIRTUAL"/>
+                <item code="2" name="TRUSTED-N3GA"/>
+                <item code="3" name="WIRELINE"/>
                 <item code="1000" name="UTRAN"/>
                 <item code="1001" name="GERAN"/>
                 <item code="1002" name="GAN"/>
                 <item code="1003" name="HSPA_EVOLUTION"/>
                 <item code="1004" name="EUTRAN"/>
+                <item code="1005" name="EUTRAN-NB-IoT"/>
+                <item code="1006" name="NR"/>
+                <item code="1007" name="LTE-M"/>
                 <item code="2000" name="CDMA2000_1X"/>
                 <item code="2001" name="HRPD"/>
                 <item code="2002" name="UMB"/>
@@ -6280,7 +8922,7 @@ var tgppswxXML = `<?xml version="1.0" encoding="UTF-8"?>
                 <rule avp="Session-Timeout" required="false" max="1"/>
                 <rule avp="MIP6-Feature-Vector" required="false" max="1"/>
                 <rule avp="AMBR" required="false" max="1"/>
-                <rule avp="3GPP-Charging-Characteristics" required="false" max="1"/>
+                <rule avp="TGPP-Charging-Characteristics" required="false" max="1"/>
                 <rule avp="Context-Identifier" required="false" max="1"/>
                 <rule avp="APN-OI-Replacement" required="false" max="1"/>
                 <rule avp="APN-Configuration" required="false"/>
@@ -6574,5 +9216,336 @@ var tgppswxXML = `<?xml version="1.0" encoding="UTF-8"?>
             </data>
         </avp>
 
+        <avp name="PPR-Flags" code="1508" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <!-- 3GPP TS 29.273 Section 8.2.3.20 -->
+            <data type="Unsigned32"/>
+        </avp>
+    </application>
+</diameter>`
+
+var tgppswmXML = `<?xml version="1.0" encoding="UTF-8"?>
+<diameter>
+    <!--
+        3GPP TS 29.273 Section 7
+        AVPs not defined here are looked up in the S6b dictionary.
+    -->
+    <application id="16777264" type="auth" name="TGPP SWm">
+        <vendor id="10415" name="TGPP"/>
+        <command code="268" short="DE" name="Diameter-EAP">
+            <request>
+                <!-- 3GPP TS 29.273 Section 7.2.2.1.1 -->
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Auth-Application-Id" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Destination-Realm" required="true" max="1"/>
+                <rule avp="Auth-Request-Type" required="true" max="1"/>
+                <rule avp="EAP-Payload" required="true" max="1"/>
+                <rule avp="User-Name" required="false" max="1"/>
+                <rule avp="RAT-Type" required="false" max="1"/>
+                <rule avp="Service-Selection" required="false" max="1"/>
+                <rule avp="MIP6-Feature-Vector" required="false" max="1"/>
+                <rule avp="Visited-Network-Identifier" required="false" max="1"/>
+                <rule avp="AAA-Failure-Indication" required="false" max="1"/>
+                <rule avp="UE-Local-IP-Address" required="false" max="1"/>
+                <rule avp="Emergency-Services" required="false" max="1"/>
+                <rule avp="DER-Flags" required="false" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="AVP" required="false"/>
+            </request>
+            <answer>
+                <!-- 3GPP TS 29.273 Section 7.2.2.1.2 -->
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Auth-Application-Id" required="true" max="1"/>
+                <rule avp="Auth-Request-Type" required="true" max="1"/>
+                <rule avp="Result-Code" required="false" max="1"/>
+                <rule avp="Experimental-Result" required="false" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="User-Name" required="false" max="1"/>
+                <rule avp="EAP-Payload" required="false" max="1"/>
+                <rule avp="EAP-Master-Session-Key" required="false" max="1"/>
+                <rule avp="APN-Configuration" required="false"/>
+                <rule avp="MIP6-Feature-Vector" required="false" max="1"/>
+                <rule avp="Mobile-Node-Identifier" required="false" max="1"/>
+                <rule avp="Trace-Info" required="false" max="1"/>
+                <rule avp="Session-Timeout" required="false" max="1"/>
+                <rule avp="MIP6-Agent-Info" required="false" max="1"/>
+                <rule avp="DEA-Flags" required="false" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="AVP" required="false"/>
+            </answer>
+        </command>
+        <command code="265" short="AA" name="AA">
+            <request>
+                <!-- 3GPP TS 29.273 Section 7.2.2.1.3 -->
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Auth-Application-Id" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Destination-Realm" required="true" max="1"/>
+                <rule avp="Destination-Host" required="false" max="1"/>
+                <rule avp="Auth-Request-Type" required="true" max="1"/>
+                <rule avp="User-Name" required="true" max="1"/>
+                <rule avp="AAA-Failure-Indication" required="false" max="1"/>
+                <rule avp="UE-Local-IP-Address" required="false" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="AVP" required="false"/>
+            </request>
+            <answer>
+                <!-- 3GPP TS 29.273 Section 7.2.2.1.4 -->
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Auth-Application-Id" required="true" max="1"/>
+                <rule avp="Auth-Request-Type" required="true" max="1"/>
+                <rule avp="Result-Code" required="false" max="1"/>
+                <rule avp="Experimental-Result" required="false" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Session-Timeout" required="false" max="1"/>
+                <rule avp="APN-Configuration" required="false"/>
+                <rule avp="Trace-Info" required="false" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="AVP" required="false"/>
+            </answer>
+        </command>
+        <command code="275" short="ST" name="Session-Termination">
+            <request>
+                <!-- 3GPP TS 29.273 Section 7.2.2.2.1 -->
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Auth-Application-Id" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Destination-Realm" required="true" max="1"/>
+                <rule avp="Termination-Cause" required="true" max="1"/>
+                <rule avp="User-Name" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+            </request>
+            <answer>
+                <!-- 3GPP TS 29.273 Section 7.2.2.2.2 -->
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Result-Code" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="AVP" required="false"/>
+            </answer>
+        </command>
+        <command code="274" short="AS" name="Abort-Session">
+            <request>
+                <!-- 3GPP TS 29.273 Section 7.2.2.3.1 -->
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Auth-Application-Id" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Destination-Realm" required="true" max="1"/>
+                <rule avp="Destination-Host" required="true" max="1"/>
+                <rule avp="User-Name" required="false" max="1"/>
+                <rule avp="Auth-Session-State" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+            </request>
+            <answer>
+                <!-- 3GPP TS 29.273 Section 7.2.2.3.2 -->
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Result-Code" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="AVP" required="false"/>
+            </answer>
+        </command>
+        <command code="258" short="RA" name="Re-Auth">
+            <request>
+                <!-- 3GPP TS 29.273 Section 7.2.2.4.1 -->
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Auth-Application-Id" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Destination-Realm" required="true" max="1"/>
+                <rule avp="Destination-Host" required="true" max="1"/>
+                <rule avp="Re-Auth-Request-Type" required="true" max="1"/>
+                <rule avp="User-Name" required="false" max="1"/>
+                <rule avp="RAR-Flags" required="false" max="1"/>
+                <rule avp="AVP" required="false"/>
+            </request>
+            <answer>
+                <!-- 3GPP TS 29.273 Section 7.2.2.4.2 -->
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Result-Code" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="AVP" required="false"/>
+            </answer>
+        </command>
+
+        <avp name="AAA-Failure-Indication" code="1518" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <!-- 3GPP TS 29.273 -->
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="DER-Flags" code="1520" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <!-- 3GPP TS 29.273 -->
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="DEA-Flags" code="1521" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <!-- 3GPP TS 29.273 -->
+            <data type="Unsigned32"/>
+        </avp>
+
+        <avp name="Emergency-Services" code="1538" must="V" must-not="M" may-encrypt="N" vendor-id="10415">
+            <!-- 3GPP TS 29.273 -->
+            <data type="Unsigned32"/>
+        </avp>
+    </application>
+</diameter>`
+
+var tgppgxxXML = `<?xml version="1.0" encoding="UTF-8"?>
+<diameter>
+
+    <application id="16777266" type="auth" name="TGPP Gxx">
+        <!-- Diameter Gxx Application, AVPs not defined here are looked up in the Gx dictionary -->
+        <!-- 3GPP 29.212 Section 5a -->
+
+        <vendor id="10415" name="TGPP"/>
+        <command code="272" short="CC" name="Credit-Control">
+            <request>
+                <!-- 3GPP 29.212 Section 5a.6.2 -->
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Auth-Application-Id" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Destination-Realm" required="true" max="1"/>
+                <rule avp="CC-Request-Type" required="true" max="1"/>
+                <rule avp="CC-Request-Number" required="true" max="1"/>
+                <rule avp="Destination-Host" required="false" max="1"/>
+                <rule avp="Origin-State-Id" required="false" max="1"/>
+                <rule avp="Subscription-Id" required="false"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="Network-Request-Support" required="false" max="1"/>
+                <rule avp="Termination-Cause" required="false" max="1"/>
+                <rule avp="Framed-IP-Address" required="false" max="1"/>
+                <rule avp="Framed-IPv6-Prefix" required="false" max="1"/>
+                <rule avp="IP-CAN-Type" required="false" max="1"/>
+                <rule avp="RAT-Type" required="false" max="1"/>
+                <rule avp="Called-Station-Id" required="false" max="1"/>
+                <rule avp="PDN-Connection-ID" required="false" max="1"/>
+                <rule avp="QoS-Information" required="false" max="1"/>
+                <rule avp="QoS-Rule-Report" required="false"/>
+                <rule avp="Event-Trigger" required="false"/>
+                <rule avp="Default-EPS-Bearer-QoS" required="false" max="1"/>
+                <rule avp="TGPP-SGSN-MCC-MNC" required="false" max="1"/>
+                <rule avp="TGPP-User-Location-Info" required="false" max="1"/>
+                <rule avp="TGPP-MS-TimeZone" required="false" max="1"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
+            </request>
+            <answer>
+                <!-- 3GPP 29.212 Section 5a.6.3 -->
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Auth-Application-Id" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Result-Code" required="false" max="1"/>
+                <rule avp="Experimental-Result" required="false" max="1"/>
+                <rule avp="CC-Request-Type" required="true" max="1"/>
+                <rule avp="CC-Request-Number" required="true" max="1"/>
+                <rule avp="Supported-Features" required="false"/>
+                <rule avp="Bearer-Control-Mode" required="false" max="1"/>
+                <rule avp="Event-Trigger" required="false"/>
+                <rule avp="Event-Report-Indication" required="false" max="1"/>
+                <rule avp="Origin-State-Id" required="false" max="1"/>
+                <rule avp="QoS-Rule-Remove" required="false"/>
+                <rule avp="QoS-Rule-Install" required="false"/>
+                <rule avp="QoS-Information" required="false" max="1"/>
+                <rule avp="Default-EPS-Bearer-QoS" required="false" max="1"/>
+                <rule avp="Error-Message" required="false" max="1"/>
+                <rule avp="Error-Reporting-Host" required="false" max="1"/>
+                <rule avp="Failed-AVP" required="false" max="1"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
+            </answer>
+        </command>
+
+        <command code="258" short="RA" name="Re-Auth">
+            <request>
+                <!-- 3GPP 29.212 Section 5a.6.4 -->
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Auth-Application-Id" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Destination-Realm" required="true" max="1"/>
+                <rule avp="Destination-Host" required="true" max="1"/>
+                <rule avp="Re-Auth-Request-Type" required="true" max="1"/>
+                <rule avp="Origin-State-Id" required="false" max="1"/>
+                <rule avp="Event-Trigger" required="false"/>
+                <rule avp="Event-Report-Indication" required="false" max="1"/>
+                <rule avp="QoS-Rule-Remove" required="false"/>
+                <rule avp="QoS-Rule-Install" required="false"/>
+                <rule avp="QoS-Information" required="false" max="1"/>
+                <rule avp="Default-EPS-Bearer-QoS" required="false" max="1"/>
+                <rule avp="Session-Release-Cause" required="false" max="1"/>
+                <rule avp="Proxy-Info" required="false"/>
+                <rule avp="Route-Record" required="false"/>
+            </request>
+            <answer>
+                <!-- 3GPP 29.212 Section 5a.6.5 -->
+                <rule avp="Session-Id" required="true" max="1"/>
+                <rule avp="Origin-Host" required="true" max="1"/>
+                <rule avp="Origin-Realm" required="true" max="1"/>
+                <rule avp="Result-Code" required="false" max="1"/>
+                <rule avp="Experimental-Result" required="false" max="1"/>
+                <rule avp="Origin-State-Id" required="false" max="1"/>
+                <rule avp="QoS-Rule-Report" required="false"/>
+                <rule avp="Error-Message" required="false" max="1"/>
+                <rule avp="Error-Reporting-Host" required="false" max="1"/>
+                <rule avp="Failed-AVP" required="false" max="1"/>
+                <rule avp="Proxy-Info" required="false"/>
+            </answer>
+        </command>
+
+        <avp name="QoS-Rule-Install" code="1051" must="M,V" may="P" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="Grouped">
+                <rule avp="QoS-Rule-Definition" required="false"/>
+                <rule avp="Tunnel-Information" required="false" max="1"/>
+            </data>
+        </avp>
+
+        <avp name="QoS-Rule-Remove" code="1052" must="M,V" may="P" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="Grouped">
+                <rule avp="QoS-Rule-Name" required="false"/>
+                <rule avp="QoS-Rule-Base-Name" required="false"/>
+            </data>
+        </avp>
+
+        <avp name="QoS-Rule-Definition" code="1053" must="M,V" may="P" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="Grouped">
+                <rule avp="QoS-Rule-Name" required="true" max="1"/>
+                <rule avp="Flow-Information" required="false"/>
+                <rule avp="QoS-Information" required="false" max="1"/>
+                <rule avp="Precedence" required="false" max="1"/>
+            </data>
+        </avp>
+
+        <avp name="QoS-Rule-Name" code="1054" must="M,V" may="P" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="OctetString"/>
+        </avp>
+
+        <avp name="QoS-Rule-Report" code="1055" must="M,V" may="P" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="Grouped">
+                <rule avp="QoS-Rule-Name" required="false"/>
+                <rule avp="QoS-Rule-Base-Name" required="false"/>
+                <rule avp="PCC-Rule-Status" required="false" max="1"/>
+                <rule avp="Rule-Failure-Code" required="false" max="1"/>
+            </data>
+        </avp>
+
+        <avp name="QoS-Rule-Base-Name" code="1074" must="V" may="P" must-not="M" may-encrypt="y" vendor-id="10415">
+            <!-- 3GPP 29.212 -->
+            <data type="UTF8String"/>
+        </avp>
+
     </application>
 </diameter>`
@@ -0,0 +1,107 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package dict
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Format is the format of dictionary files.
+type Format int
+
+// Dictionary formats.
+const (
+	// XML is the format of the dictionaries of this package, see File.
+	XML Format = iota
+
+	// JSON is the format of the dictionaries defined as JSON objects
+	// with the schema of the XML files, for example:
+	//
+	//	{"application": [{"id": 0, "avp": [{
+	//		"name": "Origin-Host", "code": 264, "must": "M",
+	//		"data": {"type": "DiameterIdentity"}
+	//	}]}]}
+	JSON
+)
+
+// FormatOf returns the format of the dictionary file filename by its
+// extension: JSON for .json, and XML otherwise.
+func FormatOf(filename string) Format {
+	if strings.EqualFold(filepath.Ext(filename), ".json") {
+		return JSON
+	}
+	return XML
+}
+
+func (f Format) String() string {
+	switch f {
+	case XML:
+		return "XML"
+	case JSON:
+		return "JSON"
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+// Decode decodes a dictionary of the given format from r.
+func Decode(r io.Reader, format Format) (*File, error) {
+	f := new(File)
+	var err error
+	switch format {
+	case XML:
+		err = xml.NewDecoder(r).Decode(f)
+	case JSON:
+		d := json.NewDecoder(r)
+		d.DisallowUnknownFields()
+		err = d.Decode(f)
+	default:
+		err = fmt.Errorf("Unsupported dictionary format: %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// ReadFile decodes the dictionary file filename, in the format of its
+// extension, see FormatOf.
+func ReadFile(filename string) (*File, error) {
+	fd, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+	return Decode(fd, FormatOf(filename))
+}
+
+// Encode writes the dictionary f to w in the given format, indented for
+// editing. Dictionaries are converted between formats by decoding them
+// in one and encoding them in the other.
+func (f *File) Encode(w io.Writer, format Format) error {
+	switch format {
+	case XML:
+		if _, err := io.WriteString(w, xml.Header); err != nil {
+			return err
+		}
+		e := xml.NewEncoder(w)
+		e.Indent("", "  ")
+		if err := e.Encode(f); err != nil {
+			return err
+		}
+		_, err := io.WriteString(w, "\n")
+		return err
+	case JSON:
+		e := json.NewEncoder(w)
+		e.SetIndent("", "  ")
+		return e.Encode(f)
+	}
+	return fmt.Errorf("Unsupported dictionary format: %s", format)
+}
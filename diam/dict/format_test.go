@@ -0,0 +1,71 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package dict
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFile_Encode(t *testing.T) {
+	for _, name := range testDicts {
+		f, err := ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, format := range []Format{JSON, XML} {
+			var b bytes.Buffer
+			if err = f.Encode(&b, format); err != nil {
+				t.Fatalf("Error encoding %s in %s: %s", name, format, err)
+			}
+			have, err := Decode(&b, format)
+			if err != nil {
+				t.Fatalf("Error decoding %s in %s: %s", name, format, err)
+			}
+			if !reflect.DeepEqual(have.App, f.App) {
+				t.Fatalf("Unexpected %s after %s round trip", name, format)
+			}
+		}
+	}
+}
+
+func TestParser_LoadJSON(t *testing.T) {
+	p, err := NewParser("./testdata/base.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = p.LoadJSON(strings.NewReader(`{"application": [{"id": 0, "avp": [{
+		"name": "Vendor-AVP", "code": 99001, "must": "V", "vendor-id": 99,
+		"data": {"type": "Enumerated", "item": [{"code": 1, "name": "ONE"}]}
+	}]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := p.Enum(0, 99001, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Name != "ONE" {
+		t.Fatalf("Unexpected Enum. Want ONE, have %s", e.Name)
+	}
+	err = p.LoadJSON(strings.NewReader(`{"application": [{"id": 0, "avps": []}]}`))
+	if err == nil {
+		t.Fatal("Unexpected load of unknown field")
+	}
+}
+
+func TestFormatOf(t *testing.T) {
+	for name, want := range map[string]Format{
+		"base.xml":   XML,
+		"gx.JSON":    JSON,
+		"vendor.dic": XML,
+	} {
+		if have := FormatOf(name); have != want {
+			t.Errorf("Unexpected format of %s. Want %s, have %s", name, want, have)
+		}
+	}
+}
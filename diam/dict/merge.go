@@ -0,0 +1,46 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package dict
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Conflict is the definition of an AVP that replaced a different one of
+// the same application: one with the same code and vendor, but another
+// name or type, or one with the same name and vendor, but another code
+// or type. The AVP is found by the new definition.
+type Conflict struct {
+	App uint32 // Application Id
+	Old *AVP   // Replaced definition
+	New *AVP
+}
+
+func (c *Conflict) Error() string {
+	return fmt.Sprintf("AVP %s (code %d, vendor %d, %s) of application %d is redefined as %s (code %d, vendor %d, %s)",
+		c.Old.Name, c.Old.Code, c.Old.VendorID, c.Old.Data.TypeName, c.App,
+		c.New.Name, c.New.Code, c.New.VendorID, c.New.Data.TypeName)
+}
+
+// MergeReport reports the conflicts between the dictionaries loaded in a
+// Parser, in the order they were loaded.
+type MergeReport struct {
+	Conflicts []*Conflict
+}
+
+// MergeReport returns the conflicts between the dictionaries loaded.
+func (p *Parser) MergeReport() *MergeReport {
+	return &MergeReport{Conflicts: p.index().conflicts}
+}
+
+// String returns the conflicts of the report, one per line.
+func (r *MergeReport) String() string {
+	var b bytes.Buffer
+	for _, c := range r.Conflicts {
+		fmt.Fprintln(&b, c.Error())
+	}
+	return b.String()
+}
@@ -0,0 +1,75 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package dict
+
+import (
+	"strings"
+	"testing"
+)
+
+const conflictDict = `<?xml version="1.0" encoding="UTF-8"?>
+<diameter>
+  <application id="0">
+    <avp name="Vendor-AVP" code="264" must="V" vendor-id="99">
+      <data type="UTF8String"/>
+    </avp>
+    <avp name="Origin-Host" code="264">
+      <data type="UTF8String"/>
+    </avp>
+    <avp name="Session-Id" code="263" must="M">
+      <data type="UTF8String"/>
+    </avp>
+  </application>
+</diameter>`
+
+func TestParser_MergeReport(t *testing.T) {
+	p, err := NewParser("./testdata/base.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := p.MergeReport(); len(r.Conflicts) != 0 {
+		t.Fatalf("Unexpected conflicts:\n%s", r)
+	}
+	if err = p.Load(strings.NewReader(conflictDict)); err != nil {
+		t.Fatal(err)
+	}
+	// The vendor AVP and the identical Session-Id do not conflict.
+	r := p.MergeReport()
+	if len(r.Conflicts) != 1 {
+		t.Fatalf("Unexpected conflicts:\n%s", r)
+	}
+	c := r.Conflicts[0]
+	if c.App != 0 || c.Old.Data.TypeName != "DiameterIdentity" || c.New.Data.TypeName != "UTF8String" {
+		t.Fatalf("Unexpected conflict: %s", c)
+	}
+	if a, _ := p.FindAVP(0, "Origin-Host"); a != c.New {
+		t.Fatalf("Unexpected Origin-Host: %s", a.Data.TypeName)
+	}
+}
+
+func TestParser_Strict(t *testing.T) {
+	p, err := NewParser()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Strict = true
+	if err = p.LoadFile("./testdata/base.xml"); err != nil {
+		t.Fatal(err)
+	}
+	err = p.Load(strings.NewReader(conflictDict))
+	if _, ok := err.(*Conflict); !ok {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	a, err := p.FindAVP(0, "Origin-Host")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Data.TypeName != "DiameterIdentity" {
+		t.Fatalf("Conflicting load changed Origin-Host to %s", a.Data.TypeName)
+	}
+	if _, err = p.FindAVP(0, "Vendor-AVP"); err == nil {
+		t.Fatal("Conflicting load added Vendor-AVP")
+	}
+}
@@ -8,11 +8,11 @@ package dict
 
 import (
 	"bytes"
-	"encoding/xml"
 	"fmt"
 	"io"
 	"os"
 	"sync"
+	"sync/atomic"
 
 	"github.com/omnicate/go-diameter/v4/diam/datatype"
 )
@@ -31,14 +31,34 @@ const (
 // multiple applications that are composed by multiple AVPs.
 //
 // The Parser element has an index to make pre-loaded AVPs searcheable per App.
+//
+// Dictionaries may be loaded and reloaded while the Parser is in use.
+// Loading is copy-on-write: the lookups in progress, such as those of
+// messages being decoded, use the index they started with, and the next
+// ones use the index with the new dictionary.
+//
+// Dictionaries may define an AVP already defined for the same application,
+// such as a vendor AVP with a code in use, in which case the last loaded
+// is used. These conflicts are reported by MergeReport, or fail the load
+// in Strict mode.
 type Parser struct {
-	file    []*File              // Dict supports multiple XML dictionaries
-	appcode map[uint32]*App      // Application index by code
-	avpname map[nameIdx]*AVP     // AVP index by name
-	avpcode map[codeIdx]*AVP     // AVP index by code
-	command map[codeIdx]*Command // Command index
-	mu      sync.Mutex           // Protects all maps
-	once    sync.Once
+	// Strict fails the loads of dictionaries that conflict with those
+	// loaded, with the first *Conflict. It must be set before loading.
+	Strict bool
+
+	idx atomic.Value // *index
+	mu  sync.Mutex   // Serializes loads
+}
+
+// index is the immutable index of the dictionaries of a Parser.
+type index struct {
+	file      []*File              // Dict supports multiple XML dictionaries
+	name      []string             // File names of the dictionaries, if any
+	appcode   map[uint32]*App      // Application index by code
+	avpname   map[nameIdx]*AVP     // AVP index by name
+	avpcode   map[codeIdx]*AVP     // AVP index by code
+	command   map[codeIdx]*Command // Command index
+	conflicts []*Conflict          // AVPs redefined
 }
 
 type codeIdx struct {
@@ -53,7 +73,8 @@ type nameIdx struct {
 	vendorID uint32
 }
 
-// NewParser allocates a new Parser optionally loading dictionary XML files.
+// NewParser allocates a new Parser optionally loading dictionary XML or
+// JSON files, see LoadFile.
 func NewParser(filename ...string) (*Parser, error) {
 	p := new(Parser)
 	var err error
@@ -65,57 +86,183 @@ func NewParser(filename ...string) (*Parser, error) {
 	return p, nil
 }
 
-// LoadFile loads a dictionary XML file. May be used multiple times.
+// LoadFile loads a dictionary file, in JSON if its extension is .json
+// and XML otherwise, see FormatOf. May be used multiple times.
 func (p *Parser) LoadFile(filename string) error {
 	fd, err := os.Open(filename)
 	if err != nil {
 		return err
 	}
 	defer fd.Close()
-	return p.Load(fd)
+	return p.load(filename, fd, FormatOf(filename), false)
 }
 
 // Load loads a dictionary from byte array. May be used multiple times.
+//
+// Loading is atomic: the Parser is unchanged when Load fails.
 func (p *Parser) Load(r io.Reader) error {
+	return p.load("", r, XML, false)
+}
+
+// LoadJSON loads a JSON dictionary, see Format. May be used multiple
+// times, and together with Load.
+func (p *Parser) LoadJSON(r io.Reader) error {
+	return p.load("", r, JSON, false)
+}
+
+// ReloadFile loads a dictionary file, replacing the applications,
+// commands and AVPs previously loaded from the same file name with
+// LoadFile or ReloadFile, if any. The dictionaries of the other files
+// are kept, so the AVPs that the file no longer defines are found in
+// them again, or not found.
+//
+// ReloadFile may be called while the Parser is in use, for example to
+// add the vendor AVPs of a peer without restarting. Reloading is atomic:
+// the Parser is unchanged when ReloadFile fails.
+func (p *Parser) ReloadFile(filename string) error {
+	fd, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+	return p.load(filename, fd, FormatOf(filename), true)
+}
+
+// load loads the dictionary named name from r, replacing the
+// dictionaries of the same name if replace is set.
+func (p *Parser) load(name string, r io.Reader, format Format, replace bool) error {
+	f, err := Decode(r, format)
+	if err != nil {
+		return err
+	}
+	for _, app := range f.App {
+		for _, avp := range app.AVP {
+			// Link AVP to its Application
+			avp.App = app
+			// Check the AVP type.
+			if err := updateType(avp); err != nil {
+				return err
+			}
+		}
+	}
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	p.once.Do(func() {
-		p.appcode = make(map[uint32]*App)
-		p.avpname = make(map[nameIdx]*AVP)
-		p.avpcode = make(map[codeIdx]*AVP)
-		p.command = make(map[codeIdx]*Command)
-	})
-	f := new(File)
-	d := xml.NewDecoder(r)
-	if err := d.Decode(f); err != nil {
+	old := p.index()
+	var idx *index
+	if replace {
+		// Rebuild the index without the replaced dictionaries.
+		idx = newIndex()
+		for i, of := range old.file {
+			if old.name[i] == name {
+				continue
+			}
+			if err := idx.add(old.name[i], of, p.Strict); err != nil {
+				return err
+			}
+		}
+	} else {
+		idx = old.clone()
+	}
+	if err := idx.add(name, f, p.Strict); err != nil {
 		return err
 	}
-	p.file = append(p.file, f)
+	p.idx.Store(idx)
+	return nil
+}
+
+// conflict returns the conflict of the definition avp of the application
+// appID with those indexed, if any.
+func (idx *index) conflict(appID uint32, avp *AVP) *Conflict {
+	old, ok := idx.avpcode[codeIdx{appID, avp.Code, avp.VendorID}]
+	if !ok || sameAVP(old, avp) {
+		old, ok = idx.avpname[nameIdx{appID, avp.Name, avp.VendorID}]
+		if !ok || sameAVP(old, avp) {
+			return nil
+		}
+	}
+	return &Conflict{App: appID, Old: old, New: avp}
+}
+
+func sameAVP(a, b *AVP) bool {
+	return a.Name == b.Name && a.Code == b.Code && a.VendorID == b.VendorID &&
+		a.Data.TypeName == b.Data.TypeName
+}
+
+// index returns the current index of the Parser.
+func (p *Parser) index() *index {
+	if idx, ok := p.idx.Load().(*index); ok {
+		return idx
+	}
+	return emptyIndex
+}
+
+var emptyIndex = newIndex()
+
+func newIndex() *index {
+	return &index{
+		appcode: make(map[uint32]*App),
+		avpname: make(map[nameIdx]*AVP),
+		avpcode: make(map[codeIdx]*AVP),
+		command: make(map[codeIdx]*Command),
+	}
+}
+
+// clone returns a copy of idx, for adding dictionaries to it.
+func (idx *index) clone() *index {
+	c := &index{
+		file:      append([]*File(nil), idx.file...),
+		name:      append([]string(nil), idx.name...),
+		conflicts: append([]*Conflict(nil), idx.conflicts...),
+		appcode:   make(map[uint32]*App, len(idx.appcode)),
+		avpname:   make(map[nameIdx]*AVP, len(idx.avpname)),
+		avpcode:   make(map[codeIdx]*AVP, len(idx.avpcode)),
+		command:   make(map[codeIdx]*Command, len(idx.command)),
+	}
+	for k, v := range idx.appcode {
+		c.appcode[k] = v
+	}
+	for k, v := range idx.avpname {
+		c.avpname[k] = v
+	}
+	for k, v := range idx.avpcode {
+		c.avpcode[k] = v
+	}
+	for k, v := range idx.command {
+		c.command[k] = v
+	}
+	return c
+}
+
+// add indexes the dictionary f named name. It fails on the first
+// conflict if strict is set.
+func (idx *index) add(name string, f *File, strict bool) error {
+	idx.file = append(idx.file, f)
+	idx.name = append(idx.name, name)
 	for _, app := range f.App {
 		// Cache supported applications by ID.
-		p.appcode[app.ID] = app
+		idx.appcode[app.ID] = app
 		// Cache commands.
 		for _, cmd := range app.Command {
-			idx := codeIdx{app.ID, cmd.Code, UndefinedVendorID}
-			_, exist := p.command[idx]
+			ci := codeIdx{app.ID, cmd.Code, UndefinedVendorID}
+			_, exist := idx.command[ci]
 			if exist {
 				return fmt.Errorf("Command: %s cannot be added: index exists", cmd)
 			}
-			p.command[idx] = cmd
+			idx.command[ci] = cmd
 		}
 		// Cache AVPs.
 		for _, avp := range app.AVP {
-			// Link AVP to its Application
-			avp.App = app
-			p.avpname[nameIdx{app.ID, avp.Name, avp.VendorID}] = avp
-			p.avpcode[codeIdx{app.ID, avp.Code, avp.VendorID}] = avp
-			// Index without vendorId
-			p.avpname[nameIdx{app.ID, avp.Name, UndefinedVendorID}] = avp
-			p.avpcode[codeIdx{app.ID, avp.Code, UndefinedVendorID}] = avp
-			// Check the AVP type.
-			if err := updateType(avp); err != nil {
-				return err
+			if c := idx.conflict(app.ID, avp); c != nil {
+				if strict {
+					return c
+				}
+				idx.conflicts = append(idx.conflicts, c)
 			}
+			idx.avpname[nameIdx{app.ID, avp.Name, avp.VendorID}] = avp
+			idx.avpcode[codeIdx{app.ID, avp.Code, avp.VendorID}] = avp
+			// Index without vendorId
+			idx.avpname[nameIdx{app.ID, avp.Name, UndefinedVendorID}] = avp
+			idx.avpcode[codeIdx{app.ID, avp.Code, UndefinedVendorID}] = avp
 		}
 	}
 	return nil
@@ -133,7 +280,7 @@ func updateType(a *AVP) error {
 // String returns the Parser represented in a human readable form.
 func (p *Parser) String() string {
 	var b bytes.Buffer
-	for _, f := range p.file {
+	for _, f := range p.index().file {
 		for _, app := range f.App {
 			fmt.Fprintf(&b, "Application Id: %d\n", app.ID)
 			fmt.Fprintf(&b, "\tVendors:\n")
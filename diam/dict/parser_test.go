@@ -5,7 +5,11 @@
 package dict
 
 import (
+	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 )
 
@@ -49,3 +53,108 @@ func TestLoad(t *testing.T) {
 		}
 	}
 }
+
+const vendorDict = `<?xml version="1.0" encoding="UTF-8"?>
+<diameter>
+  <application id="0">
+    <avp name="%s" code="99001" must="V" vendor-id="99">
+      <data type="%s"/>
+    </avp>
+  </application>
+</diameter>`
+
+func writeVendorDict(t *testing.T, filename, name, typ string) {
+	t.Helper()
+	xml := []byte(fmt.Sprintf(vendorDict, name, typ))
+	if err := ioutil.WriteFile(filename, xml, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReloadFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dict")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "vendor.xml")
+	p, err := NewParser("./testdata/base.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeVendorDict(t, filename, "Vendor-Old", "UTF8String")
+	if err = p.ReloadFile(filename); err != nil {
+		t.Fatal(err)
+	}
+	writeVendorDict(t, filename, "Vendor-New", "Unsigned32")
+	if err = p.ReloadFile(filename); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = p.FindAVP(0, "Vendor-Old"); err == nil {
+		t.Fatal("Vendor-Old was not replaced")
+	}
+	a, err := p.FindAVPWithVendor(0, uint32(99001), 99)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Name != "Vendor-New" {
+		t.Fatalf("Unexpected AVP. Want Vendor-New, have %s", a.Name)
+	}
+	if _, err = p.FindAVP(0, "Origin-Host"); err != nil {
+		t.Fatal(err)
+	}
+
+	writeVendorDict(t, filename, "Vendor-Bad", "NoSuchType")
+	if err = p.ReloadFile(filename); err == nil {
+		t.Fatal("Unexpected reload of invalid dictionary")
+	}
+	if _, err = p.FindAVP(0, "Vendor-New"); err != nil {
+		t.Fatalf("Failed reload changed the dictionary: %v", err)
+	}
+}
+
+func TestReloadFile_Concurrent(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dict")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "vendor.xml")
+	writeVendorDict(t, filename, "Vendor-AVP", "UTF8String")
+	p, err := NewParser("./testdata/base.xml", filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	quit := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-quit:
+					return
+				default:
+				}
+				if _, err := p.FindAVP(0, "Vendor-AVP"); err != nil {
+					t.Error(err)
+					return
+				}
+				if _, err := p.FindCommand(0, 257); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	for i := 0; i < 20; i++ {
+		if err = p.ReloadFile(filename); err != nil {
+			t.Error(err)
+			break
+		}
+	}
+	close(quit)
+	wg.Wait()
+}
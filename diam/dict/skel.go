@@ -14,35 +14,39 @@ import (
 )
 
 // File is the dictionary root element of a XML file.  See diam_base.xml.
+//
+// Dictionaries may also be JSON files with the same schema: the elements
+// and attributes of the XML files are the fields of the JSON objects,
+// and repeated elements are arrays. See Format.
 type File struct {
-	XMLName xml.Name `xml:"diameter"`
-	App     []*App   `xml:"application"` // Support for multiple applications
+	XMLName xml.Name `xml:"diameter" json:"-"`
+	App     []*App   `xml:"application" json:"application"` // Support for multiple applications
 }
 
 // App defines a diameter application in XML and its multiple AVPs.
 type App struct {
-	ID      uint32     `xml:"id,attr"`   // Application Id
-	Type    string     `xml:"type,attr"` // Application type
-	Name    string     `xml:"name,attr"` // Application name
-	Vendor  []*Vendor  `xml:"vendor"`    // Support for multiple vendors
-	Command []*Command `xml:"command"`   // Diameter commands
-	AVP     []*AVP     `xml:"avp"`       // Each application support multiple AVPs
+	ID      uint32     `xml:"id,attr" json:"id"`                         // Application Id
+	Type    string     `xml:"type,attr,omitempty" json:"type,omitempty"` // Application type
+	Name    string     `xml:"name,attr,omitempty" json:"name,omitempty"` // Application name
+	Vendor  []*Vendor  `xml:"vendor" json:"vendor,omitempty"`            // Support for multiple vendors
+	Command []*Command `xml:"command" json:"command,omitempty"`          // Diameter commands
+	AVP     []*AVP     `xml:"avp" json:"avp,omitempty"`                  // Each application support multiple AVPs
 }
 
 // Vendor defines diameter vendors in XML, that can be used to translate
 // the VendorId AVP of incoming messages.
 type Vendor struct {
-	ID   uint32 `xml:"id,attr"`
-	Name string `xml:"name,attr"`
+	ID   uint32 `xml:"id,attr" json:"id"`
+	Name string `xml:"name,attr" json:"name"`
 }
 
 // Command defines a diameter command (CE, CC, etc)
 type Command struct {
-	Code    uint32      `xml:"code,attr"`
-	Name    string      `xml:"name,attr"`
-	Short   string      `xml:"short,attr"`
-	Request CommandRule `xml:"request"`
-	Answer  CommandRule `xml:"answer"`
+	Code    uint32      `xml:"code,attr" json:"code"`
+	Name    string      `xml:"name,attr" json:"name"`
+	Short   string      `xml:"short,attr" json:"short"`
+	Request CommandRule `xml:"request" json:"request"`
+	Answer  CommandRule `xml:"answer" json:"answer"`
 }
 
 func (cmd *Command) String() string {
@@ -54,42 +58,61 @@ func (cmd *Command) String() string {
 
 // CommandRule contains rules for a given command.
 type CommandRule struct {
-	Rule []*Rule `xml:"rule"`
+	Rule []*Rule `xml:"rule" json:"rule"`
 }
 
 // AVP represents a dictionary AVP that is loaded from XML.
 type AVP struct {
-	Name       string `xml:"name,attr"`
-	Code       uint32 `xml:"code,attr"`
-	Must       string `xml:"must,attr"`
-	May        string `xml:"may,attr"`
-	MustNot    string `xml:"must-not,attr"`
-	MayEncrypt string `xml:"may-encrypt,attr"`
-	VendorID   uint32 `xml:"vendor-id,attr"`
-	Data       Data   `xml:"data"`
-	App        *App   `xml:"none"` // Link back to diameter application
+	Name       string `xml:"name,attr" json:"name"`
+	Code       uint32 `xml:"code,attr" json:"code"`
+	Must       string `xml:"must,attr,omitempty" json:"must,omitempty"`
+	May        string `xml:"may,attr,omitempty" json:"may,omitempty"`
+	MustNot    string `xml:"must-not,attr,omitempty" json:"must-not,omitempty"`
+	MayEncrypt string `xml:"may-encrypt,attr,omitempty" json:"may-encrypt,omitempty"`
+	VendorID   uint32 `xml:"vendor-id,attr,omitempty" json:"vendor-id,omitempty"`
+	Data       Data   `xml:"data" json:"data"`
+	App        *App   `xml:"-" json:"-"` // Link back to diameter application
+}
+
+// EnumName returns the name of the item n of the Enumerated AVP, or an
+// empty string if the AVP has no such item.
+func (a *AVP) EnumName(n int32) string {
+	for _, item := range a.Data.Enum {
+		if item.Code == n {
+			return item.Name
+		}
+	}
+	return ""
 }
 
 // Data of an AVP can be EnumItem or a Parser of multiple AVPs.
 type Data struct {
-	Type     datatype.TypeID `xml:"-"`
-	TypeName string          `xml:"type,attr"`
-	Enum     []*Enum         `xml:"item"` // In case of Enumerated AVP data
-	Rule     []*Rule         `xml:"rule"` // In case of Grouped AVPs
+	Type     datatype.TypeID `xml:"-" json:"-"`
+	TypeName string          `xml:"type,attr" json:"type"`
+	Enum     []*Enum         `xml:"item" json:"item,omitempty"` // In case of Enumerated AVP data
+	Rule     []*Rule         `xml:"rule" json:"rule,omitempty"` // In case of Grouped AVPs
 }
 
 // Enum contains the code and name of Enumerated items.
 type Enum struct {
 	// rfc6733 (section 4.3.1):
 	// The Enumerated format is derived from the Integer32 Basic AVP Format.
-	Code int32  `xml:"code,attr"`
-	Name string `xml:"name,attr"`
+	Code int32  `xml:"code,attr" json:"code"`
+	Name string `xml:"name,attr" json:"name"`
 }
 
 // Rule defines the usage rules of an AVP.
+//
+// Fixed AVPs must be the first AVPs of the message, in the order of
+// their rules, such as the Session-Id of RFC 6733 section 8.8. The AVP
+// named "AVP" allows any other AVP. Max is unlimited when zero.
 type Rule struct {
-	AVP      string `xml:"avp,attr"` // AVP Name
-	Required bool   `xml:"required,attr"`
-	Min      int    `xml:"min,attr"`
-	Max      int    `xml:"max,attr"`
+	AVP      string `xml:"avp,attr" json:"avp"` // AVP Name
+	Required bool   `xml:"required,attr" json:"required"`
+	Fixed    bool   `xml:"fixed,attr,omitempty" json:"fixed,omitempty"`
+	Min      int    `xml:"min,attr,omitempty" json:"min,omitempty"`
+	Max      int    `xml:"max,attr,omitempty" json:"max,omitempty"`
 }
+
+// AnyAVP is the name of the rule that allows any AVP.
+const AnyAVP = "AVP"
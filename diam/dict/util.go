@@ -19,20 +19,24 @@ import (
 // Note: care must be taken to avoid creating parent-child loops
 var parentAppIds map[uint32]uint32 = map[uint32]uint32{
 	4:        1,
+	5:        1,         // EAP -> NASREQ
+	16777216: 4,         // Cx  -> Cc
+	16777217: 16777216,  // Sh  -> Cx
 	16777251: 4,         // S6  -> Cc
+	16777272: 16777265,  // S6b -> SWx
+	16777265: 16777251,  // SWx -> S6a
+	16777264: 16777272,  // SWm -> S6b
 	16777236: 16777238,  // Rx  -> Gx
+	16777266: 16777238,  // Gxx -> Gx
 	16777238: 16777223,  // Gx  -> Gmb
 	16777223: 16777222,  // Gmb -> Gq
 	16777222: 4,         // Gq  -> Cc
 }
 
 // Apps return a list of all applications loaded in the Parser object.
-// Apps must never be called concurrently with LoadFile or Load.
 func (p *Parser) Apps() []*App {
-	//p.mu.Lock()
-	//defer p.mu.Unlock()
 	var apps []*App
-	for _, f := range p.file {
+	for _, f := range p.index().file {
 		for _, app := range f.App {
 			apps = append(apps, app)
 		}
@@ -41,9 +45,9 @@ func (p *Parser) Apps() []*App {
 }
 
 // App returns a dictionary application for the given application code
-// if exists. App must never be called concurrently with LoadFile or Load.
+// if exists.
 func (p *Parser) App(code uint32) (*App, error) {
-	app := p.appcode[code]
+	app := p.index().appcode[code]
 	if app == nil {
 		return nil, ErrApplicationUnsupported
 	}
@@ -75,11 +79,8 @@ func MakeUnknownAVP(appid, code, vendorID uint32) *AVP {
 // If the AVP code is not found for the given appid it tries with appid=0
 // before returning an error.
 // Code can be either the AVP code (int, uint32) or name (string).
-//
-// FindAVPWithVendor must never be called concurrently with LoadFile or Load.
 func (p *Parser) FindAVPWithVendor(appid uint32, code interface{}, vendorID uint32) (*AVP, error) {
-	//p.mu.Lock()
-	//defer p.mu.Unlock()
+	idx := p.index()
 	var (
 		avp *AVP
 		ok  bool
@@ -89,17 +90,17 @@ func (p *Parser) FindAVPWithVendor(appid uint32, code interface{}, vendorID uint
 retry:
 	switch codeVal := code.(type) {
 	case string:
-		avp, ok = p.avpname[nameIdx{appid, codeVal, vendorID}]
+		avp, ok = idx.avpname[nameIdx{appid, codeVal, vendorID}]
 		if !ok && appid == 0 {
 			err = fmt.Errorf("Could not find AVP %T(%q) for Vendor: %d", codeVal, codeVal, vendorID)
 		}
 	case uint32:
-		avp, ok = p.avpcode[codeIdx{appid, codeVal, vendorID}]
+		avp, ok = idx.avpcode[codeIdx{appid, codeVal, vendorID}]
 		if !ok && appid == 0 {
 			err = fmt.Errorf("Could not find AVP %T(%d) for Vendor: %d", codeVal, codeVal, vendorID)
 		}
 	case int:
-		avp, ok = p.avpcode[codeIdx{appid, uint32(codeVal), vendorID}]
+		avp, ok = idx.avpcode[codeIdx{appid, uint32(codeVal), vendorID}]
 		if !ok && appid == 0 {
 			err = fmt.Errorf("Could not find AVP %T(%d) for Vendor: %d", codeVal, codeVal, vendorID)
 		}
@@ -131,8 +132,6 @@ retry:
 // If the AVP code is not found for the given appid it tries with appid=0
 // before returning an error.
 // Code can be either the AVP code (int, uint32) or name (string).
-//
-// FindAVP must never be called concurrently with LoadFile or Load.
 func (p *Parser) FindAVP(appid uint32, code interface{}) (*AVP, error) {
 	return p.FindAVPWithVendor(appid, code, UndefinedVendorID)
 }
@@ -143,28 +142,25 @@ func (p *Parser) FindAVP(appid uint32, code interface{}) (*AVP, error) {
 //
 // ScanAVP is 20x or more slower than FindAVP. Use with care.
 // Code can be either the AVP code (uint32) or name (string).
-//
-// ScanAVP must never be called concurrently with LoadFile or Load.
 func (p *Parser) ScanAVP(code interface{}) (*AVP, error) {
-	//p.mu.Lock()
-	//defer p.mu.Unlock()
+	idx := p.index()
 	switch code.(type) {
 	case string:
-		for idx, avp := range p.avpname {
+		for idx, avp := range idx.avpname {
 			if idx.name == code.(string) {
 				return avp, nil
 			}
 		}
 		return nil, fmt.Errorf("Could not find AVP %s", code.(string))
 	case uint32:
-		for idx, avp := range p.avpcode {
+		for idx, avp := range idx.avpcode {
 			if idx.code == code.(uint32) {
 				return avp, nil
 			}
 		}
 		return nil, fmt.Errorf("Could not find AVP code %d", code.(uint32))
 	case int:
-		for idx, avp := range p.avpcode {
+		for idx, avp := range idx.avpcode {
 			if idx.code == uint32(code.(int)) {
 				return avp, nil
 			}
@@ -175,24 +171,32 @@ func (p *Parser) ScanAVP(code interface{}) (*AVP, error) {
 }
 
 // FindCommand returns a pre-loaded Command from the Parser.
-//
-// FindCommand must never be called concurrently with LoadFile or Load.
 func (p *Parser) FindCommand(appid, code uint32) (*Command, error) {
-	//p.mu.Lock()
-	//defer p.mu.Unlock()
-	if cmd, ok := p.command[codeIdx{appid, code, UndefinedVendorID}]; ok {
+	idx := p.index()
+	if cmd, ok := idx.command[codeIdx{appid, code, UndefinedVendorID}]; ok {
 		return cmd, nil
-	} else if cmd, ok = p.command[codeIdx{0, code, UndefinedVendorID}]; ok {
+	} else if cmd, ok = idx.command[codeIdx{0, code, UndefinedVendorID}]; ok {
 		// Always fall back to base dict.
 		return cmd, nil
 	}
 	return nil, fmt.Errorf("Could not find preloaded Command with code %d", code)
 }
 
+// CommandRules returns the rules of the AVPs of the requests, or of the
+// answers, of the command with the given appid and code.
+func (p *Parser) CommandRules(appid, code uint32, request bool) ([]*Rule, error) {
+	cmd, err := p.FindCommand(appid, code)
+	if err != nil {
+		return nil, err
+	}
+	if request {
+		return cmd.Request.Rule, nil
+	}
+	return cmd.Answer.Rule, nil
+}
+
 // Enum is a helper function that returns a pre-loaded Enum item for the
 // given AVP appid, code and n. (n is the enum code in the dictionary)
-//
-// Enum must never be called concurrently with LoadFile or Load.
 func (p *Parser) Enum(appid, code uint32, n int32) (*Enum, error) {
 	avp, err := p.FindAVP(appid, code)
 	if err != nil {
@@ -213,10 +217,19 @@ func (p *Parser) Enum(appid, code uint32, n int32) (*Enum, error) {
 		n, avp.Name, avp.Code)
 }
 
+// EnumName returns the name of the item n of the Enumerated AVP with the
+// given appid and code, for example "UPDATE_REQUEST" for the value 2 of
+// CC-Request-Type, or an empty string if the AVP or the item is not found.
+func (p *Parser) EnumName(appid, code uint32, n int32) string {
+	avp, err := p.FindAVP(appid, code)
+	if err != nil || avp.Data.Type != datatype.EnumeratedType {
+		return ""
+	}
+	return avp.EnumName(n)
+}
+
 // Rule is a helper function that returns a pre-loaded Rule item for the
 // given AVP code and name.
-//
-// Rule must never be called concurrently with LoadFile or Load.
 func (p *Parser) Rule(appid, code uint32, n string) (*Rule, error) {
 	avp, err := p.FindAVP(appid, code)
 	if err != nil {
@@ -11,8 +11,8 @@ import (
 
 func TestApps(t *testing.T) {
 	apps := Default.Apps()
-	if len(apps) != 8 {
-		t.Fatalf("Unexpected # of apps. Want 8, have %d", len(apps))
+	if len(apps) != 16 {
+		t.Fatalf("Unexpected # of apps. Want 16, have %d", len(apps))
 	}
 	// Base protocol.
 	if apps[0].ID != 0 {
@@ -22,24 +22,56 @@ func TestApps(t *testing.T) {
 	if apps[1].ID != 3 {
 		t.Fatalf("Unexpected app.ID. Want 3, have %d", apps[1].ID)
 	}
+	// Capabilities Update application
+	if apps[2].ID != 10 {
+		t.Fatalf("Unexpected app.ID. Want 10, have %d", apps[2].ID)
+	}
 	// Credit-Control applications.
-	if apps[2].ID != 4 {
-		t.Fatalf("Unexpected app.ID. Want 4, have %d", apps[2].ID)
+	if apps[3].ID != 4 {
+		t.Fatalf("Unexpected app.ID. Want 4, have %d", apps[3].ID)
+	}
+	// Diameter EAP applications
+	if apps[4].ID != 5 {
+		t.Fatalf("Unexpected app.ID. Want 5, have %d", apps[4].ID)
 	}
 	// 3GPP Gx Charging Control applications
-	if apps[3].ID != 16777238 {
-		t.Fatalf("Unexpected app.ID. Want 16777238, have %d", apps[3].ID)
+	if apps[5].ID != 16777238 {
+		t.Fatalf("Unexpected app.ID. Want 16777238, have %d", apps[5].ID)
 	}
 	// NASREQ applications
-	if apps[4].ID != 1 {
-		t.Fatalf("Unexpected app.ID. Want 1, have %d", apps[4].ID)
+	if apps[6].ID != 1 {
+		t.Fatalf("Unexpected app.ID. Want 1, have %d", apps[6].ID)
+	}
+	// 3GPP Cx applications
+	if apps[8].ID != 16777216 {
+		t.Fatalf("Unexpected app.ID. Want 16777216, have %d", apps[8].ID)
+	}
+	// 3GPP Gxx applications
+	if apps[9].ID != 16777266 {
+		t.Fatalf("Unexpected app.ID. Want 16777266, have %d", apps[9].ID)
+	}
+	// 3GPP Rx applications
+	if apps[10].ID != 16777236 {
+		t.Fatalf("Unexpected app.ID. Want 16777236, have %d", apps[10].ID)
 	}
 	// 3GPP S6a applications
-	if apps[6].ID != 16777251 {
-		t.Fatalf("Unexpected app.ID. Want 16777251, have %d", apps[6].ID)
+	if apps[11].ID != 16777251 {
+		t.Fatalf("Unexpected app.ID. Want 16777251, have %d", apps[11].ID)
+	}
+	// 3GPP S6b applications
+	if apps[12].ID != 16777272 {
+		t.Fatalf("Unexpected app.ID. Want 16777272, have %d", apps[12].ID)
+	}
+	// 3GPP Sh applications
+	if apps[13].ID != 16777217 {
+		t.Fatalf("Unexpected app.ID. Want 16777217, have %d", apps[13].ID)
 	}
-	if apps[7].ID != 16777265 {
-		t.Fatalf("Unexpected app.ID. Want 16777265, have %d", apps[7].ID)
+	// 3GPP SWm applications
+	if apps[14].ID != 16777264 {
+		t.Fatalf("Unexpected app.ID. Want 16777264, have %d", apps[14].ID)
+	}
+	if apps[15].ID != 16777265 {
+		t.Fatalf("Unexpected app.ID. Want 16777265, have %d", apps[15].ID)
 	}
 }
 
@@ -89,6 +121,16 @@ func TestFindAVPWithVendor(t *testing.T) {
 
 	// Test 'parent' AVP find - S6a app ID, tgpp_ro_rf dictionary
 	findAVPCodeTest(t, 16777251, "GMLC-Address", UndefinedVendorID, 2405)
+	// SWm app ID, S6b and SWx dictionaries
+	findAVPCodeTest(t, 16777264, "RAR-Flags", UndefinedVendorID, 1522)
+	findAVPCodeTest(t, 16777264, "APN-Configuration", UndefinedVendorID, 1430)
+	// Gxx app ID, Gx dictionary
+	findAVPCodeTest(t, 16777266, "Flow-Information", UndefinedVendorID, 1058)
+	// Gy, Credit-Control app ID with the TS 32.299 and TS 29.061 AVPs
+	findAVPCodeTest(t, 4, "Announcement-Information", UndefinedVendorID, 3904)
+	findAVPCodeTest(t, 4, "TGPP-IMEISV", UndefinedVendorID, 20)
+	// SWx app ID, S6a dictionary
+	findAVPCodeTest(t, 16777265, "Feature-List", UndefinedVendorID, 630)
 
 	if _, err := Default.FindAVPWithVendor(43, "User-Password", UndefinedVendorID); err == nil {
 		t.Error("User-Password Should not be found for app 43")
@@ -130,6 +172,45 @@ func TestFindCommand(t *testing.T) {
 	} else if cmd.Short != "AI" {
 		t.Fatalf("Unexpected command: %#v", cmd)
 	}
+
+	if cmd, err := Default.FindCommand(16777251, 320); err != nil {
+		t.Error(err)
+	} else if cmd.Short != "DS" {
+		t.Fatalf("Unexpected command: %#v", cmd)
+	}
+
+	if cmd, err := Default.FindCommand(16777216, 304); err != nil {
+		t.Error(err)
+	} else if cmd.Short != "RT" {
+		t.Fatalf("Unexpected command: %#v", cmd)
+	}
+}
+
+// TestRules checks that the AVPs of the command and grouped AVP rules of
+// all applications are found, in their own or their parent applications.
+func TestRules(t *testing.T) {
+	check := func(app uint32, name, where string) {
+		if name == "AVP" {
+			return
+		}
+		if _, err := Default.FindAVP(app, name); err != nil {
+			t.Errorf("App %d %s: %v", app, where, err)
+		}
+	}
+	for _, app := range Default.Apps() {
+		for _, cmd := range app.Command {
+			for _, rules := range [][]*Rule{cmd.Request.Rule, cmd.Answer.Rule} {
+				for _, rule := range rules {
+					check(app.ID, rule.AVP, "command "+cmd.Name)
+				}
+			}
+		}
+		for _, avp := range app.AVP {
+			for _, rule := range avp.Data.Rule {
+				check(app.ID, rule.AVP, "AVP "+avp.Name)
+			}
+		}
+	}
 }
 
 func TestEnum(t *testing.T) {
@@ -143,6 +224,18 @@ func TestEnum(t *testing.T) {
 	}
 }
 
+func TestEnumName(t *testing.T) {
+	if name := Default.EnumName(4, 416, 2); name != "UPDATE_REQUEST" {
+		t.Errorf("Unexpected name %q, expected UPDATE_REQUEST", name)
+	}
+	if name := Default.EnumName(4, 416, 99); name != "" {
+		t.Errorf("Unexpected name %q for unknown item", name)
+	}
+	if name := Default.EnumName(0, 263, 1); name != "" {
+		t.Errorf("Unexpected name %q for Session-Id", name)
+	}
+}
+
 func TestRule(t *testing.T) {
 	if rule, err := Default.Rule(0, 284, "Proxy-Host"); err != nil {
 		t.Fatal(err)
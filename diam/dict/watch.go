@@ -0,0 +1,124 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package dict
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Watcher reloads the dictionaries of a directory into a Parser when they
+// are added or changed. See Parser.Watch.
+type Watcher struct {
+	p        *Parser
+	dir      string
+	interval time.Duration
+	loaded   map[string]fileVersion // by file name
+	errc     chan error
+	quit     chan struct{}
+	done     chan struct{}
+	once     sync.Once
+}
+
+// fileVersion identifies the contents of a dictionary file.
+type fileVersion struct {
+	modTime time.Time
+	size    int64
+}
+
+// Watch loads the dictionary files of the directory dir, those with the
+// .xml or .json extension, into the Parser with ReloadFile, and checks
+// them every interval to reload those that are added or changed. The
+// dictionaries of the files removed from dir stay loaded.
+//
+// Watch returns the error of the first file that fails to load. The
+// errors of the next loads are sent to the Errors channel of the Watcher,
+// and the Parser keeps the last dictionary of the files that fail.
+// Each application should be defined by a single file of dir, as
+// commands defined twice fail to load.
+func (p *Parser) Watch(dir string, interval time.Duration) (*Watcher, error) {
+	w := &Watcher{
+		p:        p,
+		dir:      dir,
+		interval: interval,
+		loaded:   make(map[string]fileVersion),
+		errc:     make(chan error, 16),
+		quit:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if err := w.scan(); err != nil {
+		return nil, err
+	}
+	go w.watch()
+	return w, nil
+}
+
+// Errors returns the channel of the errors of the files that fail to
+// reload. Errors are dropped when the channel is full.
+func (w *Watcher) Errors() <-chan error {
+	return w.errc
+}
+
+// Close stops watching the directory.
+func (w *Watcher) Close() error {
+	w.once.Do(func() { close(w.quit) })
+	<-w.done
+	return nil
+}
+
+func (w *Watcher) watch() {
+	defer close(w.done)
+	t := time.NewTicker(w.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			if err := w.scan(); err != nil {
+				select {
+				case w.errc <- err:
+				default:
+				}
+			}
+		case <-w.quit:
+			return
+		}
+	}
+}
+
+// scan reloads the dictionary files of the directory that changed since
+// the last scan, and returns the first error.
+func (w *Watcher) scan() error {
+	files, err := ioutil.ReadDir(w.dir)
+	if err != nil {
+		return err
+	}
+	var first error
+	for _, fi := range files {
+		if fi.IsDir() || !isDictFile(fi.Name()) {
+			continue
+		}
+		name := filepath.Join(w.dir, fi.Name())
+		v := fileVersion{fi.ModTime(), fi.Size()}
+		if old, ok := w.loaded[name]; ok && old.modTime.Equal(v.modTime) && old.size == v.size {
+			continue
+		}
+		// Failed files are retried when they change again.
+		w.loaded[name] = v
+		if err = w.p.ReloadFile(name); err != nil && first == nil {
+			first = fmt.Errorf("%s: %v", name, err)
+		}
+	}
+	return first
+}
+
+// isDictFile reports whether filename has the extension of a dictionary.
+func isDictFile(filename string) bool {
+	ext := filepath.Ext(filename)
+	return strings.EqualFold(ext, ".xml") || strings.EqualFold(ext, ".json")
+}
@@ -0,0 +1,61 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package dict
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dict")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "vendor.xml")
+	writeVendorDict(t, filename, "Vendor-Old", "UTF8String")
+	p, err := NewParser("./testdata/base.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := p.Watch(dir, 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if _, err = p.FindAVP(0, "Vendor-Old"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The size of the file changes with the name.
+	writeVendorDict(t, filename, "Vendor-Newer", "UTF8String")
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err = p.FindAVP(0, "Vendor-Newer"); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Vendor-Newer was not loaded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, err = p.FindAVP(0, "Vendor-Old"); err == nil {
+		t.Fatal("Vendor-Old was not replaced")
+	}
+
+	writeVendorDict(t, filename, "Vendor-Bad", "NoSuchType")
+	select {
+	case err = <-w.Errors():
+	case <-time.After(5 * time.Second):
+		t.Fatal("No error for invalid dictionary")
+	}
+	if _, err = p.FindAVP(0, "Vendor-Newer"); err != nil {
+		t.Fatalf("Failed reload changed the dictionary: %v", err)
+	}
+}
@@ -0,0 +1,441 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package wireshark imports the Diameter dictionaries of Wireshark, the
+// diameter/dictionary.xml file and the vendor and application files it
+// includes, as dictionaries of the dict package.
+//
+// The AVPs of Wireshark dictionaries are global, so they are imported in
+// the base application, where the lookups of every application find
+// them. The commands are imported in their application.
+package wireshark
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+// ReadFile imports the Wireshark dictionary files, such as
+// dictionary.xml, as a single dictionary. The external entities declared
+// by the files, with which dictionary.xml includes the other files, are
+// read from the directory of the file that declares them.
+func ReadFile(filenames ...string) (*dict.File, error) {
+	var c converter
+	for _, name := range filenames {
+		b, err := readFile(name)
+		if err != nil {
+			return nil, err
+		}
+		if err = c.decode(bytes.NewReader(b)); err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+	}
+	return c.convert()
+}
+
+// Decode imports the Wireshark dictionaries of r as a single dictionary.
+// External entities are not supported, see ReadFile.
+func Decode(r ...io.Reader) (*dict.File, error) {
+	var c converter
+	for _, rr := range r {
+		if err := c.decode(rr); err != nil {
+			return nil, err
+		}
+	}
+	return c.convert()
+}
+
+// Load imports the Wireshark dictionary files into the Parser p, see
+// ReadFile, adding only the commands and AVPs that p does not define.
+// The definitions of p, such as those of dict.Default, are kept.
+func Load(p *dict.Parser, filenames ...string) error {
+	f, err := ReadFile(filenames...)
+	if err != nil {
+		return err
+	}
+	for _, app := range f.App {
+		cmds := app.Command[:0]
+		for _, cmd := range app.Command {
+			if _, err := p.FindCommand(app.ID, cmd.Code); err != nil {
+				cmds = append(cmds, cmd)
+			}
+		}
+		app.Command = cmds
+		avps := app.AVP[:0]
+		for _, a := range app.AVP {
+			if _, err := p.FindAVPWithVendor(app.ID, a.Code, a.VendorID); err == nil {
+				continue
+			}
+			if _, err := p.FindAVP(app.ID, a.Name); err == nil {
+				continue
+			}
+			avps = append(avps, a)
+		}
+		app.AVP = avps
+	}
+	var b bytes.Buffer
+	if err = f.Encode(&b, dict.XML); err != nil {
+		return err
+	}
+	return p.Load(&b)
+}
+
+var (
+	entityDecl = regexp.MustCompile(`<!ENTITY\s+([\w.-]+)\s+SYSTEM\s+"([^"]+)"\s*>`)
+	xmlDecl    = regexp.MustCompile(`<\?xml[^>]*\?>`)
+)
+
+// readFile reads the file filename, replacing the references to the
+// external entities it declares with the contents of their files.
+func readFile(filename string) ([]byte, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range entityDecl.FindAllSubmatch(b, -1) {
+		ref := []byte("&" + string(m[1]) + ";")
+		if !bytes.Contains(b, ref) {
+			continue
+		}
+		inc, err := ioutil.ReadFile(filepath.Join(filepath.Dir(filename), string(m[2])))
+		if err != nil {
+			return nil, err
+		}
+		b = bytes.Replace(b, ref, xmlDecl.ReplaceAll(inc, nil), -1)
+	}
+	return entityDecl.ReplaceAll(b, nil), nil
+}
+
+// The elements of Wireshark dictionaries.
+type (
+	wsApp struct {
+		ID      uint32       `xml:"id,attr"`
+		Name    string       `xml:"name,attr"`
+		Typedef []*wsTypedef `xml:"typedefn"`
+		Command []*wsCommand `xml:"command"`
+		AVP     []*wsAVP     `xml:"avp"`
+	}
+
+	wsVendor struct {
+		ID   string   `xml:"vendor-id,attr"`
+		Code uint32   `xml:"code,attr"`
+		Name string   `xml:"name,attr"`
+		AVP  []*wsAVP `xml:"avp"`
+	}
+
+	wsTypedef struct {
+		Name   string `xml:"type-name,attr"`
+		Parent string `xml:"type-parent,attr"`
+	}
+
+	wsCommand struct {
+		Name    string  `xml:"name,attr"`
+		Code    uint32  `xml:"code,attr"`
+		Request wsRules `xml:"requestrules"`
+		Answer  wsRules `xml:"answerrules"`
+	}
+
+	wsRules struct {
+		Fixed    []*wsRule `xml:"fixed>avp"`
+		Required []*wsRule `xml:"required>avp"`
+		Optional []*wsRule `xml:"optional>avp"`
+	}
+
+	wsRule struct {
+		Name string `xml:"name,attr"`
+		Min  string `xml:"min,attr"`
+		Max  string `xml:"max,attr"`
+	}
+
+	wsAVP struct {
+		Name        string    `xml:"name,attr"`
+		Code        uint32    `xml:"code,attr"`
+		Mandatory   string    `xml:"mandatory,attr"`
+		Protected   string    `xml:"protected,attr"`
+		MayEncrypt  string    `xml:"may-encrypt,attr"`
+		VendorBit   string    `xml:"vendor-bit,attr"`
+		VendorID    string    `xml:"vendor-id,attr"`
+		Type        *wsType   `xml:"type"`
+		Enum        []*wsEnum `xml:"enum"`
+		Grouped     *wsGroup  `xml:"grouped"`
+		vendorscope string    // vendor-id of the enclosing vendor element
+	}
+
+	wsType struct {
+		Name string `xml:"type-name,attr"`
+	}
+
+	wsEnum struct {
+		Name string `xml:"name,attr"`
+		Code int32  `xml:"code,attr"`
+	}
+
+	wsGroup struct {
+		AVP []*wsRule `xml:"gavp"`
+	}
+)
+
+// converter accumulates the elements of Wireshark dictionaries, and
+// converts them once the vendors and types of all are known.
+type converter struct {
+	apps    []*wsApp // base is the application 0
+	vendors []*wsVendor
+	types   map[string]string // parent by type name
+	avps    []*wsAVP
+}
+
+// decode decodes the elements of the Wireshark dictionary of r, which may
+// be a dictionary element or the elements of an included file.
+func (c *converter) decode(r io.Reader) error {
+	d := xml.NewDecoder(r)
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		se, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch se.Name.Local {
+		case "dictionary":
+			// Decode its children.
+		case "base", "application":
+			app := new(wsApp)
+			if err = d.DecodeElement(app, &se); err != nil {
+				return err
+			}
+			if se.Name.Local == "base" {
+				app.ID = 0
+			}
+			c.apps = append(c.apps, app)
+			c.addTypes(app.Typedef)
+			c.avps = append(c.avps, app.AVP...)
+		case "vendor":
+			v := new(wsVendor)
+			if err = d.DecodeElement(v, &se); err != nil {
+				return err
+			}
+			c.vendors = append(c.vendors, v)
+			for _, a := range v.AVP {
+				a.vendorscope = v.ID
+			}
+			c.avps = append(c.avps, v.AVP...)
+		case "typedefn":
+			t := new(wsTypedef)
+			if err = d.DecodeElement(t, &se); err != nil {
+				return err
+			}
+			c.addTypes([]*wsTypedef{t})
+		case "avp":
+			a := new(wsAVP)
+			if err = d.DecodeElement(a, &se); err != nil {
+				return err
+			}
+			c.avps = append(c.avps, a)
+		default:
+			if err = d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (c *converter) addTypes(types []*wsTypedef) {
+	if c.types == nil {
+		c.types = make(map[string]string)
+	}
+	for _, t := range types {
+		c.types[t.Name] = t.Parent
+	}
+}
+
+// convert returns the dictionary of the elements decoded.
+func (c *converter) convert() (*dict.File, error) {
+	vendors := make(map[string]uint32)
+	for _, v := range c.vendors {
+		vendors[v.ID] = v.Code
+	}
+	base := &dict.App{ID: 0, Name: "Base"}
+	f := &dict.File{App: []*dict.App{base}}
+	apps := map[uint32]*dict.App{0: base}
+	seen := make(map[uint32]bool) // vendors of base
+	for _, a := range c.avps {
+		da, err := c.convertAVP(a, vendors)
+		if err != nil {
+			return nil, err
+		}
+		base.AVP = append(base.AVP, da)
+		if da.VendorID != 0 && !seen[da.VendorID] {
+			seen[da.VendorID] = true
+			base.Vendor = append(base.Vendor, &dict.Vendor{ID: da.VendorID, Name: vendorName(c.vendors, da.VendorID)})
+		}
+	}
+	for _, wa := range c.apps {
+		app, ok := apps[wa.ID]
+		if !ok {
+			app = &dict.App{ID: wa.ID, Name: wa.Name}
+			apps[wa.ID] = app
+			f.App = append(f.App, app)
+		}
+		for _, wc := range wa.Command {
+			if hasCommand(app, wc.Code) {
+				// Commands are defined once per application.
+				continue
+			}
+			app.Command = append(app.Command, &dict.Command{
+				Code:    wc.Code,
+				Name:    wc.Name,
+				Short:   shortName(wc.Name),
+				Request: dict.CommandRule{Rule: convertRules(wc.Request)},
+				Answer:  dict.CommandRule{Rule: convertRules(wc.Answer)},
+			})
+		}
+	}
+	return f, nil
+}
+
+func (c *converter) convertAVP(a *wsAVP, vendors map[string]uint32) (*dict.AVP, error) {
+	da := &dict.AVP{
+		Name: a.Name,
+		Code: a.Code,
+	}
+	vendor := a.VendorID
+	if vendor == "" {
+		vendor = a.vendorscope
+	}
+	if vendor != "" && vendor != "None" {
+		id, ok := vendors[vendor]
+		if !ok {
+			return nil, fmt.Errorf("AVP %s: unknown vendor %s", a.Name, vendor)
+		}
+		da.VendorID = id
+	}
+	for _, bit := range []struct {
+		flag string
+		rule string
+	}{{"M", a.Mandatory}, {"P", a.Protected}, {"V", a.VendorBit}} {
+		switch strings.ToLower(bit.rule) {
+		case "must":
+			da.Must += bit.flag
+		case "may":
+			da.May += bit.flag
+		case "mustnot", "shouldnot":
+			da.MustNot += bit.flag
+		}
+	}
+	if da.VendorID != 0 && !strings.Contains(da.Must, "V") {
+		// The V bit is set whenever there is a Vendor-Id.
+		da.Must += "V"
+		da.MustNot = strings.Replace(da.MustNot, "V", "", -1)
+	}
+	if strings.EqualFold(a.MayEncrypt, "yes") {
+		da.MayEncrypt = "Y"
+	} else {
+		da.MayEncrypt = "N"
+	}
+	switch {
+	case a.Grouped != nil:
+		da.Data.TypeName = "Grouped"
+		for _, r := range a.Grouped.AVP {
+			da.Data.Rule = append(da.Data.Rule, convertRule(r, false))
+		}
+	case a.Type != nil:
+		da.Data.TypeName = c.typeName(a.Type.Name)
+	default:
+		da.Data.TypeName = "OctetString"
+	}
+	for _, e := range a.Enum {
+		da.Data.Enum = append(da.Data.Enum, &dict.Enum{Code: e.Code, Name: e.Name})
+	}
+	return da, nil
+}
+
+// wiresharkTypes are the Wireshark types that have another name in the
+// datatype package.
+var wiresharkTypes = map[string]string{
+	"AppId":     "Unsigned32",
+	"VendorId":  "Unsigned32",
+	"IPAddress": "Address",
+}
+
+// typeName returns the datatype of the Wireshark type name, or of its
+// closest parent that is a datatype. Unknown types are OctetString.
+func (c *converter) typeName(name string) string {
+	for i := 0; name != "" && i < 16; i++ {
+		if t, ok := wiresharkTypes[name]; ok {
+			return t
+		}
+		if _, ok := datatype.Available[name]; ok {
+			return name
+		}
+		name = c.types[name]
+	}
+	return "OctetString"
+}
+
+func convertRules(r wsRules) []*dict.Rule {
+	var rules []*dict.Rule
+	for _, wr := range r.Fixed {
+		rule := convertRule(wr, true)
+		rule.Fixed = true
+		rules = append(rules, rule)
+	}
+	for _, wr := range r.Required {
+		rules = append(rules, convertRule(wr, true))
+	}
+	for _, wr := range r.Optional {
+		rules = append(rules, convertRule(wr, false))
+	}
+	return rules
+}
+
+func convertRule(r *wsRule, required bool) *dict.Rule {
+	rule := &dict.Rule{AVP: r.Name, Required: required}
+	// Non numeric bounds, such as "unbounded", are no bounds.
+	rule.Min, _ = strconv.Atoi(r.Min)
+	rule.Max, _ = strconv.Atoi(r.Max)
+	return rule
+}
+
+func hasCommand(app *dict.App, code uint32) bool {
+	for _, cmd := range app.Command {
+		if cmd.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+func vendorName(vendors []*wsVendor, code uint32) string {
+	for _, v := range vendors {
+		if v.Code == code {
+			return v.Name
+		}
+	}
+	return ""
+}
+
+// shortName returns the initials of the words of the command name, such
+// as CC for Credit-Control, the short name of the dict package.
+func shortName(name string) string {
+	var b strings.Builder
+	for _, w := range strings.FieldsFunc(name, func(r rune) bool { return r == '-' || r == ' ' }) {
+		b.WriteString(strings.ToUpper(w[:1]))
+	}
+	return b.String()
+}
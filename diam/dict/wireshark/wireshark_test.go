@@ -0,0 +1,104 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package wireshark
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+func TestReadFile(t *testing.T) {
+	f, err := ReadFile("./testdata/dictionary.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err = f.Encode(&b, dict.XML); err != nil {
+		t.Fatal(err)
+	}
+	p, err := dict.NewParser()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = p.Load(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd, err := p.FindCommand(4, 272)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmd.Short != "CC" || len(cmd.Request.Rule) != 3 {
+		t.Fatalf("Unexpected command: %s, %d request rules", cmd, len(cmd.Request.Rule))
+	}
+	if r := cmd.Request.Rule[0]; r.AVP != "Session-Id" || !r.Fixed || !r.Required || r.Max != 1 {
+		t.Fatalf("Unexpected Session-Id rule: %+v", r)
+	}
+	if r := cmd.Request.Rule[2]; r.Required || r.Max != 0 {
+		t.Fatalf("Unexpected Multiple-Services-Credit-Control rule: %+v", r)
+	}
+	if _, err = p.FindCommand(0, 280); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []struct {
+		name     string
+		vendorID uint32
+		typ      string
+		must     string
+	}{
+		{"Origin-Host", 0, "DiameterIdentity", "M"},
+		{"Host-IP-Address", 0, "Address", "M"},
+		{"CC-Request-Type", 0, "Enumerated", "M"},
+		{"Multiple-Services-Credit-Control", 0, "Grouped", "M"},
+		{"3GPP-IMSI-MCC-MNC", 10415, "UTF8String", "V"},
+		{"MSISDN", 10415, "UTF8String", "MV"},
+		{"MIP6-Agent-Info", 0, "OctetString", "M"},
+	} {
+		a, err := p.FindAVP(4, want.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.VendorID != want.vendorID || a.Data.TypeName != want.typ || a.Must != want.must {
+			t.Errorf("Unexpected %s: vendor %d, type %s, must %q", want.name, a.VendorID, a.Data.TypeName, a.Must)
+		}
+	}
+	if e, err := p.Enum(4, 416, 4); err != nil || e.Name != "EVENT_REQUEST" {
+		t.Fatalf("Unexpected CC-Request-Type item: %v, %v", e, err)
+	}
+}
+
+func TestDecode_UnknownVendor(t *testing.T) {
+	_, err := Decode(strings.NewReader(`<dictionary><base>
+		<avp name="X" code="1" vendor-id="Nobody"><type type-name="Unsigned32"/></avp>
+	</base></dictionary>`))
+	if err == nil {
+		t.Fatal("Unexpected decode of AVP of unknown vendor")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	p, err := dict.NewParser("../testdata/base.xml", "../testdata/credit_control.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = Load(p, "./testdata/dictionary.xml"); err != nil {
+		t.Fatal(err)
+	}
+	// Credit_control.xml defines three items.
+	if _, err = p.Enum(4, 416, 4); err == nil {
+		t.Fatal("Load replaced CC-Request-Type")
+	}
+	a, err := p.FindAVPWithVendor(4, uint32(701), 10415)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Name != "MSISDN" {
+		t.Fatalf("Unexpected AVP. Want MSISDN, have %s", a.Name)
+	}
+}
@@ -20,6 +20,34 @@ organized in sub-packages with specific functionality:
 
  * diam/dict: a dictionary parser that supports collections of dictionaries.
 
+ * diam/doic: Diameter overload control (DOIC, RFC 7683).
+
+ * diam/agent: Diameter relay and proxy agents.
+
+ * diam/session: Session-Id generation and session state tracking.
+
+ * diam/app/creditcontrol: Credit-Control client and server (RFC 4006, Gy/Ro).
+
+ * diam/app/gx: typed Gx messages (3GPP TS 29.212).
+
+ * diam/app/s6a: typed S6a messages (3GPP TS 29.272).
+
+ * diam/app/rx: typed Rx messages and P-CSCF client (3GPP TS 29.214).
+
+ * diam/app/cx: typed Cx/Dx messages (3GPP TS 29.229).
+
+ * diam/app/sh: typed Sh messages and User-Data helpers (3GPP TS 29.329).
+
+ * diam/app/swx: typed SWx messages (3GPP TS 29.273).
+
+ * diam/app/s6b: typed S6b messages (3GPP TS 29.273).
+
+ * diam/app/rf: Rf offline charging client (3GPP TS 32.299).
+
+ * diam/app/eap: Diameter EAP application and EAP method framework (RFC 4072).
+
+ * diam/app/nasreq: typed NASREQ messages (RFC 7155).
+
 If you're looking to go right into code, see the examples subdirectory for
 applications like clients and servers.
 
@@ -49,5 +77,8 @@ are encoded and decoded using the dictionary automatically.
 The API of clients and servers require that you assign handlers for
 certain messages, similar to how you route HTTP endpoints. In the
 handlers, you'll receive messages already decoded.
+
+Requests may also be sent with SendRequest, which waits for the answer
+and returns it to the caller instead of passing it to a handler.
 */
 package diam
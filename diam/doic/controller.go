@@ -0,0 +1,162 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package doic
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// Controller implements the reacting node side of DOIC.
+//
+// It adds the OC-Supported-Features AVP to outgoing requests, keeps
+// track of overload reports received in answers, and throttles
+// requests sent to overloaded hosts or realms using its Algorithm.
+//
+// Host reports apply to requests that carry a matching Destination-Host
+// AVP. Realm reports apply to requests that only carry a matching
+// Destination-Realm AVP.
+//
+// It is safe for concurrent use.
+type Controller struct {
+	alg Algorithm
+
+	mu    sync.RWMutex // guards host and realm
+	host  map[datatype.DiameterIdentity]*Report
+	realm map[datatype.DiameterIdentity]*Report
+}
+
+// NewController creates and initializes a new Controller that uses the
+// given abatement algorithm. If alg is nil, Loss is used.
+func NewController(alg Algorithm) *Controller {
+	if alg == nil {
+		alg = NewLoss()
+	}
+	return &Controller{
+		alg:   alg,
+		host:  make(map[datatype.DiameterIdentity]*Report),
+		realm: make(map[datatype.DiameterIdentity]*Report),
+	}
+}
+
+// Algorithm returns the abatement algorithm used by the Controller.
+func (c *Controller) Algorithm() Algorithm {
+	return c.alg
+}
+
+// Prepare must be called before sending the request m. It returns
+// ErrThrottled if the request must not be sent, otherwise it adds the
+// OC-Supported-Features AVP to m.
+//
+// Requests that already carry OC-Supported-Features have been prepared,
+// and are not throttled again when retransmitted or written by several
+// layers. Prepare ignores answers.
+//
+// The state machines of the sm package call Prepare for every request
+// written to their connections when they have a Controller.
+func (c *Controller) Prepare(m *diam.Message) error {
+	if m.Header.CommandFlags&diam.RequestFlag == 0 {
+		return nil
+	}
+	var req request
+	if err := m.Unmarshal(&req); err != nil {
+		return err
+	}
+	if req.SupportedFeatures != nil {
+		return nil
+	}
+	if r := c.reportFor(&req); r != nil && !c.alg.Admit(r) {
+		return ErrThrottled
+	}
+	m.AddAVP(supportedFeatures(c.alg.Feature()))
+	return nil
+}
+
+// WriteTo calls Prepare and writes the message m to w when it
+// is not throttled.
+func (c *Controller) WriteTo(w io.Writer, m *diam.Message) (int64, error) {
+	if err := c.Prepare(m); err != nil {
+		return 0, err
+	}
+	return m.WriteTo(w)
+}
+
+// reportFor returns the active report that applies to the request,
+// or nil.
+func (c *Controller) reportFor(req *request) *Report {
+	now := time.Now()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if len(req.DestinationHost) > 0 {
+		if r := c.host[req.DestinationHost]; r.Active(now) {
+			return r
+		}
+		return nil
+	}
+	if r := c.realm[req.DestinationRealm]; r.Active(now) {
+		return r
+	}
+	return nil
+}
+
+// Process must be called for every answer received. It updates the
+// overload state of the answering host or realm when the answer
+// carries an OC-OLR AVP. Requests are ignored.
+func (c *Controller) Process(m *diam.Message) error {
+	if m.Header.CommandFlags&diam.RequestFlag != 0 {
+		return nil
+	}
+	var a answer
+	if err := m.Unmarshal(&a); err != nil {
+		return err
+	}
+	if a.SupportedFeatures == nil || a.SupportedFeatures.FeatureVector&c.alg.Feature() == 0 {
+		// The reporting node did not select our algorithm.
+		return nil
+	}
+	r, err := a.report()
+	if err != nil || r == nil {
+		return err
+	}
+	var (
+		idx      map[datatype.DiameterIdentity]*Report
+		reporter datatype.DiameterIdentity
+	)
+	switch r.Type {
+	case HostReport:
+		idx, reporter = c.host, a.OriginHost
+	case RealmReport:
+		idx, reporter = c.realm, a.OriginRealm
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if old, ok := idx[reporter]; ok && old.SequenceNumber >= r.SequenceNumber {
+		// Stale or duplicate report.
+		return nil
+	}
+	r.Expires = time.Now().Add(r.ValidityDuration)
+	idx[reporter] = r
+	return nil
+}
+
+// HostReport returns the last report received for the given host.
+func (c *Controller) HostReport(host datatype.DiameterIdentity) (*Report, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	r, ok := c.host[host]
+	return r, ok
+}
+
+// RealmReport returns the last report received for the given realm.
+func (c *Controller) RealmReport(realm datatype.DiameterIdentity) (*Report, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	r, ok := c.realm[realm]
+	return r, ok
+}
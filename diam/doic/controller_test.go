@@ -0,0 +1,155 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package doic
+
+import (
+	"testing"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+func newRequest(destHost, destRealm string) *diam.Message {
+	m := diam.NewRequest(diam.CreditControl, 4, dict.Default)
+	m.NewAVP(avp.SessionID, avp.Mbit, 0, datatype.UTF8String("sess"))
+	m.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("cli"))
+	m.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity("test"))
+	if destHost != "" {
+		m.NewAVP(avp.DestinationHost, avp.Mbit, 0, datatype.DiameterIdentity(destHost))
+	}
+	m.NewAVP(avp.DestinationRealm, avp.Mbit, 0, datatype.DiameterIdentity(destRealm))
+	return m
+}
+
+func newAnswer(req *diam.Message, rep *Reporter) *diam.Message {
+	a := req.Answer(diam.Success)
+	a.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("srv"))
+	a.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity("test"))
+	rep.Answer(req, a)
+	return a
+}
+
+func TestController_Prepare(t *testing.T) {
+	ctl := NewController(nil)
+	req := newRequest("srv", "test")
+	if err := ctl.Prepare(req); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctl.Prepare(req); err != nil {
+		t.Fatal(err)
+	}
+	avps, err := req.FindAVPs(avp.OCSupportedFeatures, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(avps) != 1 {
+		t.Fatalf("Unexpected number of OC-Supported-Features. Want 1, have %d", len(avps))
+	}
+}
+
+func TestController_HostReport(t *testing.T) {
+	ctl := NewController(nil)
+	rep := NewReporter()
+	rep.SetReport(HostReport, 100, time.Minute)
+
+	req := newRequest("srv", "test")
+	if err := ctl.Prepare(req); err != nil {
+		t.Fatal(err)
+	}
+	req = diamtest.RoundTrip(t, req)
+	if err := ctl.Process(diamtest.RoundTrip(t, newAnswer(req, rep))); err != nil {
+		t.Fatal(err)
+	}
+	r, ok := ctl.HostReport("srv")
+	if !ok {
+		t.Fatal("Host report was not stored")
+	}
+	if r.ReductionPercentage != 100 || r.ValidityDuration != time.Minute {
+		t.Fatalf("Unexpected report: %s", r)
+	}
+	if err := ctl.Prepare(newRequest("srv", "test")); err != ErrThrottled {
+		t.Fatalf("Unexpected error. Want %v, have %v", ErrThrottled, err)
+	}
+	// Prepared requests, such as retransmissions, are not throttled again.
+	if err := ctl.Prepare(req); err != nil {
+		t.Fatal(err)
+	}
+	// Realm routed requests are not affected by host reports.
+	if err := ctl.Prepare(newRequest("", "test")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Ending the overload must stop throttling.
+	rep.Clear()
+	if err := ctl.Process(diamtest.RoundTrip(t, newAnswer(req, rep))); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctl.Prepare(newRequest("srv", "test")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestController_RealmReport(t *testing.T) {
+	ctl := NewController(nil)
+	rep := NewReporter()
+	rep.SetReport(RealmReport, 100, 0)
+
+	req := newRequest("", "test")
+	if err := ctl.Prepare(req); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctl.Process(diamtest.RoundTrip(t, newAnswer(diamtest.RoundTrip(t, req), rep))); err != nil {
+		t.Fatal(err)
+	}
+	if r, ok := ctl.RealmReport("test"); !ok || r.ValidityDuration != DefaultValidityDuration {
+		t.Fatalf("Unexpected realm report: %v", r)
+	}
+	if err := ctl.Prepare(newRequest("", "test")); err != ErrThrottled {
+		t.Fatalf("Unexpected error. Want %v, have %v", ErrThrottled, err)
+	}
+	if err := ctl.Prepare(newRequest("", "other")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestController_StaleReport(t *testing.T) {
+	ctl := NewController(nil)
+	rep := NewReporter()
+	rep.SetReport(HostReport, 100, time.Minute)
+	req := newRequest("srv", "test")
+	if err := ctl.Prepare(req); err != nil {
+		t.Fatal(err)
+	}
+	req = diamtest.RoundTrip(t, req)
+	first := diamtest.RoundTrip(t, newAnswer(req, rep))
+	rep.SetReport(HostReport, 10, time.Minute)
+	second := diamtest.RoundTrip(t, newAnswer(req, rep))
+	if err := ctl.Process(second); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctl.Process(first); err != nil {
+		t.Fatal(err)
+	}
+	r, ok := ctl.HostReport("srv")
+	if !ok {
+		t.Fatal("Host report was not stored")
+	}
+	if r.ReductionPercentage != 10 {
+		t.Fatalf("Stale report was accepted: %s", r)
+	}
+}
+
+func TestReporter_NoSupportedFeatures(t *testing.T) {
+	rep := NewReporter()
+	rep.SetReport(HostReport, 50, time.Minute)
+	a := newAnswer(newRequest("srv", "test"), rep)
+	if _, err := a.FindAVP(avp.OCOLR, 0); err == nil {
+		t.Fatal("OC-OLR added to answer of request without DOIC support")
+	}
+}
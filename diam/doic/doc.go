@@ -0,0 +1,42 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package doic provides Diameter Overload Indication Conveyance (DOIC)
+// as specified by RFC 7683.
+//
+// A reacting node (typically a client) uses a Controller to advertise
+// DOIC support in outgoing requests with the OC-Supported-Features AVP,
+// to process OC-OLR overload reports received in answers, and to
+// throttle requests towards overloaded peers using an abatement
+// Algorithm. The only algorithm defined by RFC 7683 is Loss.
+//
+// A reporting node (typically a server) uses a Reporter to publish its
+// overload state by adding OC-Supported-Features and OC-OLR to answers
+// of requests that advertised DOIC support.
+//
+// Example of a reacting node:
+//
+//	ctl := doic.NewController(nil) // uses the Loss algorithm
+//	cli := &sm.Client{Handler: sm.New(settings), OverloadControl: ctl}
+//	conn, _ := cli.Dial(addr)
+//	...
+//	if _, err := req.WriteTo(conn); err == doic.ErrThrottled {
+//		// the request was dropped by the abatement algorithm
+//	}
+//
+// The state machine of the client adds OC-Supported-Features to the
+// requests written to its connections, and throttles them. Requests
+// written to other connections are prepared with Controller.WriteTo.
+//
+// Example of a reporting node:
+//
+//	mux := sm.New(settings)
+//	mux.ReportOverload(doic.HostReport, 50, time.Minute)
+//	mux.HandleFunc("CCR", func(c diam.Conn, m *diam.Message) {
+//		a := m.Answer(diam.Success)
+//		...
+//		mux.OverloadReporter().Answer(m, a)
+//		a.WriteTo(c)
+//	})
+package doic
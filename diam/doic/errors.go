@@ -0,0 +1,17 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package doic
+
+import "errors"
+
+var (
+	// ErrThrottled is returned by Controller.Prepare and Controller.WriteTo
+	// when the abatement algorithm decides not to send the request.
+	ErrThrottled = errors.New("request throttled by overload control")
+
+	// ErrMissingReportType is returned when the OC-OLR AVP does not
+	// contain the mandatory OC-Report-Type AVP.
+	ErrMissingReportType = errors.New("missing OC-Report-Type")
+)
@@ -0,0 +1,55 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package doic
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Algorithm is an overload abatement algorithm used by reacting nodes
+// to decide whether a request may be sent towards an overloaded node.
+type Algorithm interface {
+	// Feature returns the OC-Feature-Vector bit that identifies
+	// the algorithm.
+	Feature() uint64
+
+	// Admit reports whether a request may be sent while the given
+	// report is active.
+	Admit(r *Report) bool
+}
+
+// Loss implements the Loss abatement algorithm, which drops the
+// percentage of requests given by OC-Reduction-Percentage.
+// See RFC 7683 section 7 for details.
+type Loss struct {
+	mu  sync.Mutex // guards rnd
+	rnd *rand.Rand
+}
+
+// NewLoss creates and initializes a new Loss algorithm.
+func NewLoss() *Loss {
+	return &Loss{rnd: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+// Feature implements the Algorithm interface.
+func (l *Loss) Feature() uint64 {
+	return LossFeature
+}
+
+// Admit implements the Algorithm interface.
+func (l *Loss) Admit(r *Report) bool {
+	switch {
+	case r == nil || r.ReductionPercentage == 0:
+		return true
+	case r.ReductionPercentage >= 100:
+		return false
+	}
+	l.mu.Lock()
+	n := l.rnd.Intn(100)
+	l.mu.Unlock()
+	return uint32(n) >= r.ReductionPercentage
+}
@@ -0,0 +1,29 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package doic
+
+import "testing"
+
+func TestLoss_Admit(t *testing.T) {
+	l := NewLoss()
+	if !l.Admit(nil) {
+		t.Fatal("Request not admitted without a report")
+	}
+	if !l.Admit(&Report{ReductionPercentage: 0}) {
+		t.Fatal("Request not admitted with zero reduction")
+	}
+	if l.Admit(&Report{ReductionPercentage: 100}) {
+		t.Fatal("Request admitted with full reduction")
+	}
+	var admitted int
+	for i := 0; i < 10000; i++ {
+		if l.Admit(&Report{ReductionPercentage: 50}) {
+			admitted++
+		}
+	}
+	if admitted < 4000 || admitted > 6000 {
+		t.Fatalf("Unexpected number of admitted requests for 50%% reduction: %d", admitted)
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package doic
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// LossFeature is the OC-Feature-Vector bit of the Loss abatement
+// algorithm (OLR_DEFAULT_ALGO). See RFC 7683 section 7.2.
+const LossFeature uint64 = 1
+
+const (
+	// DefaultValidityDuration is used when the OC-OLR does not
+	// contain an OC-Validity-Duration AVP.
+	DefaultValidityDuration = 30 * time.Second
+
+	// MaxValidityDuration is the maximum allowed value of the
+	// OC-Validity-Duration AVP.
+	MaxValidityDuration = 86400 * time.Second
+)
+
+// ReportType is the value of the OC-Report-Type AVP.
+type ReportType int32
+
+// Report types. See RFC 7683 section 7.6.
+const (
+	HostReport  ReportType = 0
+	RealmReport ReportType = 1
+)
+
+// String implements the fmt.Stringer interface.
+func (t ReportType) String() string {
+	switch t {
+	case HostReport:
+		return "HOST_REPORT"
+	case RealmReport:
+		return "REALM_REPORT"
+	}
+	return fmt.Sprintf("ReportType(%d)", int32(t))
+}
+
+// Report is an overload report, carried in the OC-OLR AVP.
+type Report struct {
+	SequenceNumber      uint64
+	Type                ReportType
+	ReductionPercentage uint32
+	ValidityDuration    time.Duration
+
+	// Expires is set by the reacting node when the report is received.
+	Expires time.Time
+}
+
+// Active reports whether the report is asking for traffic reduction
+// at the given time.
+func (r *Report) Active(now time.Time) bool {
+	return r != nil && r.ReductionPercentage > 0 && now.Before(r.Expires)
+}
+
+// AVP returns the OC-OLR grouped AVP that carries the report.
+func (r *Report) AVP() *diam.AVP {
+	return diam.NewAVP(avp.OCOLR, 0, 0, &diam.GroupedAVP{
+		AVP: []*diam.AVP{
+			diam.NewAVP(avp.OCSequenceNumber, 0, 0, datatype.Unsigned64(r.SequenceNumber)),
+			diam.NewAVP(avp.OCReportType, 0, 0, datatype.Enumerated(r.Type)),
+			diam.NewAVP(avp.OCReductionPercentage, 0, 0, datatype.Unsigned32(r.ReductionPercentage)),
+			diam.NewAVP(avp.OCValidityDuration, 0, 0, datatype.Unsigned32(r.ValidityDuration/time.Second)),
+		},
+	})
+}
+
+func (r *Report) String() string {
+	return fmt.Sprintf("{SequenceNumber:%d,Type:%s,ReductionPercentage:%d,ValidityDuration:%s}",
+		r.SequenceNumber, r.Type, r.ReductionPercentage, r.ValidityDuration)
+}
+
+// olr is used to unmarshal the OC-OLR AVP.
+type olr struct {
+	SequenceNumber      uint64  `avp:"OC-Sequence-Number"`
+	ReportType          *int32  `avp:"OC-Report-Type"`
+	ReductionPercentage uint32  `avp:"OC-Reduction-Percentage"`
+	ValidityDuration    *uint32 `avp:"OC-Validity-Duration"`
+}
+
+// answer is used to unmarshal DOIC AVPs from answers.
+type answer struct {
+	OriginHost        datatype.DiameterIdentity `avp:"Origin-Host"`
+	OriginRealm       datatype.DiameterIdentity `avp:"Origin-Realm"`
+	SupportedFeatures *features                 `avp:"OC-Supported-Features"`
+	OLR               *olr                      `avp:"OC-OLR"`
+}
+
+// request is used to unmarshal DOIC AVPs from requests.
+type request struct {
+	DestinationHost   datatype.DiameterIdentity `avp:"Destination-Host"`
+	DestinationRealm  datatype.DiameterIdentity `avp:"Destination-Realm"`
+	SupportedFeatures *features                 `avp:"OC-Supported-Features"`
+}
+
+type features struct {
+	FeatureVector uint64 `avp:"OC-Feature-Vector"`
+}
+
+// ParseReport returns the overload report carried in the OC-OLR AVP
+// of the given message, or nil if the message has none.
+func ParseReport(m *diam.Message) (*Report, error) {
+	var a answer
+	if err := m.Unmarshal(&a); err != nil {
+		return nil, err
+	}
+	return a.report()
+}
+
+func (a *answer) report() (*Report, error) {
+	if a.OLR == nil {
+		return nil, nil
+	}
+	if a.OLR.ReportType == nil {
+		return nil, ErrMissingReportType
+	}
+	r := &Report{
+		SequenceNumber:      a.OLR.SequenceNumber,
+		Type:                ReportType(*a.OLR.ReportType),
+		ReductionPercentage: a.OLR.ReductionPercentage,
+		ValidityDuration:    DefaultValidityDuration,
+	}
+	if r.Type != HostReport && r.Type != RealmReport {
+		return nil, fmt.Errorf("unsupported OC-Report-Type: %d", r.Type)
+	}
+	if r.ReductionPercentage > 100 {
+		return nil, fmt.Errorf("invalid OC-Reduction-Percentage: %d", r.ReductionPercentage)
+	}
+	if a.OLR.ValidityDuration != nil {
+		r.ValidityDuration = time.Duration(*a.OLR.ValidityDuration) * time.Second
+		if r.ValidityDuration > MaxValidityDuration {
+			r.ValidityDuration = MaxValidityDuration
+		}
+	}
+	return r, nil
+}
+
+// supportedFeatures returns the OC-Supported-Features AVP advertising
+// the given feature vector.
+func supportedFeatures(vector uint64) *diam.AVP {
+	return diam.NewAVP(avp.OCSupportedFeatures, 0, 0, &diam.GroupedAVP{
+		AVP: []*diam.AVP{
+			diam.NewAVP(avp.OCFeatureVector, 0, 0, datatype.Unsigned64(vector)),
+		},
+	})
+}
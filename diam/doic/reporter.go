@@ -0,0 +1,101 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package doic
+
+import (
+	"sync"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+)
+
+// Reporter implements the reporting node side of DOIC.
+//
+// It holds the current overload state of the node and adds it to
+// answers of requests that advertise support for the Loss algorithm.
+//
+// It is safe for concurrent use.
+type Reporter struct {
+	mu      sync.Mutex // guards seq and report
+	seq     uint64
+	report  *Report
+	expires time.Time
+}
+
+// NewReporter creates and initializes a new Reporter.
+//
+// Sequence numbers are seeded from the current time so they keep
+// increasing across restarts of the node.
+func NewReporter() *Reporter {
+	return &Reporter{seq: uint64(time.Now().Unix())}
+}
+
+// SetReport publishes a new overload report that asks reacting nodes
+// to reduce their traffic by reduction percent during validity. If
+// validity is zero, DefaultValidityDuration is used.
+func (r *Reporter) SetReport(typ ReportType, reduction uint32, validity time.Duration) {
+	if reduction > 100 {
+		reduction = 100
+	}
+	if validity == 0 {
+		validity = DefaultValidityDuration
+	} else if validity > MaxValidityDuration {
+		validity = MaxValidityDuration
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seq++
+	r.report = &Report{
+		SequenceNumber:      r.seq,
+		Type:                typ,
+		ReductionPercentage: reduction,
+		ValidityDuration:    validity,
+	}
+	r.expires = time.Now().Add(validity)
+}
+
+// Clear ends the current overload condition. Until the previous report
+// would have expired, answers carry a report with zero reduction so
+// that reacting nodes stop throttling immediately.
+func (r *Reporter) Clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.report == nil {
+		return
+	}
+	r.seq++
+	r.report = &Report{
+		SequenceNumber: r.seq,
+		Type:           r.report.Type,
+	}
+}
+
+// Report returns the report currently being published, if any.
+func (r *Reporter) Report() (*Report, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.report == nil || !time.Now().Before(r.expires) {
+		return nil, false
+	}
+	return r.report, true
+}
+
+// Answer adds the DOIC AVPs to the answer a, according to the request
+// req. Nothing is added if req does not advertise the Loss algorithm
+// in its OC-Supported-Features AVP.
+func (r *Reporter) Answer(req, a *diam.Message) error {
+	var dr request
+	if err := req.Unmarshal(&dr); err != nil {
+		return err
+	}
+	if dr.SupportedFeatures == nil || dr.SupportedFeatures.FeatureVector&LossFeature == 0 {
+		return nil
+	}
+	a.AddAVP(supportedFeatures(LossFeature))
+	if report, ok := r.Report(); ok {
+		a.AddAVP(report.AVP())
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+import (
+	"crypto/tls"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// RedactedValue is printed by Dumper in place of the values of redacted
+// AVPs.
+const RedactedValue = "<redacted>"
+
+// Dumper logs messages in the text form of Message.String, with the
+// values of sensitive AVPs redacted, for example to log production
+// traffic without personal data.
+type Dumper struct {
+	// Logger logs the messages. Defaults to the Logger of their
+	// connection, see LoggerOf.
+	Logger Logger
+
+	// Level is the level of the entries, LevelDebug by default.
+	Level Level
+
+	// Redact are the names of the AVPs whose values are redacted, such
+	// as User-Name, MSISDN or SIP-Auth-Data-Item. Grouped AVPs are
+	// redacted with all the AVPs they contain.
+	Redact []string
+}
+
+// DumpHandler returns a HandlerFunc that logs the messages passed to h,
+// and the messages h writes to their connection, with the values of the
+// AVPs in redact redacted.
+func DumpHandler(h Handler, redact ...string) HandlerFunc {
+	d := &Dumper{Redact: redact}
+	return d.Handler(h)
+}
+
+// Handler returns a HandlerFunc that logs the messages passed to h, and
+// the messages h writes to their connection, and calls h.
+//
+// The connection passed to h wraps the connection of the message, which
+// must be used for comparisons with other connections.
+func (d *Dumper) Handler(h Handler) HandlerFunc {
+	return func(c Conn, m *Message) {
+		d.dump(c, "message received", m)
+		h.ServeDIAM(&dumpConn{Conn: c, d: d}, m)
+	}
+}
+
+func (d *Dumper) dump(c Conn, msg string, m *Message) {
+	l := d.Logger
+	if l == nil {
+		l = LoggerOf(c)
+	}
+	if !l.Enabled(d.Level) {
+		return
+	}
+	l.Log(d.Level, msg, "remote", c.RemoteAddr(), "message", d.String(m))
+}
+
+// String returns the text form of m, with the values of the AVPs in
+// Redact replaced with RedactedValue.
+func (d *Dumper) String(m *Message) string {
+	if len(d.Redact) == 0 {
+		return m.String()
+	}
+	redact := make(map[string]bool, len(d.Redact))
+	for _, name := range d.Redact {
+		redact[name] = true
+	}
+	rm := *m
+	rm.AVP = d.redact(m, m.AVP, redact)
+	return rm.String()
+}
+
+// redact returns a copy of avps with the values of the AVPs in redact
+// replaced, and the AVPs of their grouped AVPs redacted.
+func (d *Dumper) redact(m *Message, avps []*AVP, redact map[string]bool) []*AVP {
+	ra := make([]*AVP, len(avps))
+	for i, a := range avps {
+		ra[i] = a
+		dictAVP, err := m.Dictionary().FindAVPWithVendor(m.Header.ApplicationID, a.Code, a.VendorID)
+		switch {
+		case err == nil && redact[dictAVP.Name]:
+			c := *a
+			c.Data = redactedValue{a.Data}
+			ra[i] = &c
+		case a.Data != nil && a.Data.Type() == GroupedAVPType:
+			if g, ok := a.Data.(*GroupedAVP); ok {
+				c := *a
+				c.Data = &GroupedAVP{AVP: d.redact(m, g.AVP, redact)}
+				ra[i] = &c
+			}
+		}
+	}
+	return ra
+}
+
+// redactedValue replaces the value of redacted AVPs, and keeps their
+// length.
+type redactedValue struct {
+	v datatype.Type
+}
+
+func (v redactedValue) Serialize() []byte {
+	return make([]byte, v.v.Len())
+}
+
+func (v redactedValue) Len() int {
+	return v.v.Len()
+}
+
+func (v redactedValue) Padding() int {
+	return v.v.Padding()
+}
+
+func (v redactedValue) Type() datatype.TypeID {
+	return datatype.UnknownType
+}
+
+func (v redactedValue) String() string {
+	return RedactedValue
+}
+
+// dumpConn is the Conn passed to the handlers of a Dumper, which logs
+// the messages written to it.
+type dumpConn struct {
+	Conn
+	d *Dumper
+}
+
+// messageSent implements the messageSender interface.
+func (c *dumpConn) messageSent(m *Message) {
+	c.d.dump(c.Conn, "message sent", m)
+	if s, ok := c.Conn.(messageSender); ok {
+		s.messageSent(m)
+	}
+}
+
+// prepareMessage implements the messagePreparer interface.
+func (c *dumpConn) prepareMessage(m *Message) error {
+	if p, ok := c.Conn.(messagePreparer); ok {
+		return p.prepareMessage(m)
+	}
+	return nil
+}
+
+// CloseNotify implements the CloseNotifier interface.
+func (c *dumpConn) CloseNotify() <-chan struct{} {
+	if cn, ok := c.Conn.(CloseNotifier); ok {
+		return cn.CloseNotify()
+	}
+	return nil
+}
+
+// Drain implements the Drainer interface.
+func (c *dumpConn) Drain(ctx context.Context) error {
+	return Drain(ctx, c.Conn)
+}
+
+// SendRequest implements the RequestSender interface.
+func (c *dumpConn) SendRequest(ctx context.Context, m *Message) (*Message, error) {
+	rs, ok := c.Conn.(RequestSender)
+	if !ok {
+		return nil, ErrSendRequestUnsupported
+	}
+	a, err := rs.SendRequest(ctx, m)
+	c.d.dump(c.Conn, "message sent", m)
+	if a != nil {
+		c.d.dump(c.Conn, "message received", a)
+	}
+	return a, err
+}
+
+// WriteStream implements the MultistreamWriter interface.
+func (c *dumpConn) WriteStream(b []byte, stream uint) (int, error) {
+	if w, ok := c.Conn.(MultistreamWriter); ok {
+		return w.WriteStream(b, stream)
+	}
+	return c.Write(b)
+}
+
+// CurrentWriterStream implements the MultistreamWriter interface.
+func (c *dumpConn) CurrentWriterStream() uint {
+	if w, ok := c.Conn.(MultistreamWriter); ok {
+		return w.CurrentWriterStream()
+	}
+	return 0
+}
+
+// ResetWriterStream implements the MultistreamWriter interface.
+func (c *dumpConn) ResetWriterStream() {
+	if w, ok := c.Conn.(MultistreamWriter); ok {
+		w.ResetWriterStream()
+	}
+}
+
+// SetWriterStream implements the MultistreamWriter interface.
+func (c *dumpConn) SetWriterStream(stream uint) uint {
+	if w, ok := c.Conn.(MultistreamWriter); ok {
+		return w.SetWriterStream(stream)
+	}
+	return 0
+}
+
+// UpgradeTLS implements the TLSUpgrader interface.
+func (c *dumpConn) UpgradeTLS(config *tls.Config, client bool, before func() error) error {
+	if u, ok := c.Conn.(TLSUpgrader); ok {
+		return u.UpgradeTLS(config, client, before)
+	}
+	return ErrTLSUnsupported
+}
@@ -0,0 +1,99 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+func newDumpedRequest() *diam.Message {
+	m := diam.NewRequest(diam.CapabilitiesExchange, 0, dict.Default)
+	m.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("cli"))
+	m.NewAVP(avp.UserName, avp.Mbit, 0, datatype.UTF8String("alice@example.com"))
+	m.NewAVP(avp.VendorSpecificApplicationID, avp.Mbit, 0, &diam.GroupedAVP{
+		AVP: []*diam.AVP{
+			diam.NewAVP(avp.VendorID, avp.Mbit, 0, datatype.Unsigned32(10415)),
+			diam.NewAVP(avp.AuthApplicationID, avp.Mbit, 0, datatype.Unsigned32(16777251)),
+		},
+	})
+	return m
+}
+
+func TestDumper_String(t *testing.T) {
+	m := newDumpedRequest()
+	d := &diam.Dumper{Redact: []string{"User-Name", "Auth-Application-Id"}}
+	s := d.String(m)
+	for _, secret := range []string{"alice@example.com", "16777251"} {
+		if strings.Contains(s, secret) {
+			t.Fatalf("%s is not redacted:\n%s", secret, s)
+		}
+	}
+	for _, v := range []string{"cli", "10415", "Length:28,VendorId:0,Value:" + diam.RedactedValue} {
+		if !strings.Contains(s, v) {
+			t.Fatalf("%s is missing:\n%s", v, s)
+		}
+	}
+	if !strings.Contains(m.String(), "alice@example.com") {
+		t.Fatal("The message was modified")
+	}
+}
+
+func TestDumpHandler(t *testing.T) {
+	smux := diam.NewServeMux()
+	smux.Handle("CER", diam.DumpHandler(diam.HandlerFunc(func(c diam.Conn, m *diam.Message) {
+		a := m.Answer(diam.Success)
+		a.NewAVP(avp.UserName, avp.Mbit, 0, datatype.UTF8String("bob@example.com"))
+		a.WriteTo(c)
+	}), "User-Name"))
+	srv := diamtest.NewUnstartedServer(smux, nil)
+	logger := &testLogger{}
+	srv.Config.Logger = logger
+	srv.Start()
+	defer srv.Close()
+
+	answerc := make(chan *diam.Message, 1)
+	cmux := diam.NewServeMux()
+	cmux.HandleIdx(diam.CommandIndex{AppID: 0, Code: diam.CapabilitiesExchange, Request: false},
+		diam.HandlerFunc(func(c diam.Conn, m *diam.Message) { answerc <- m }))
+	cli, err := diam.Dial(srv.Addr, cmux, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cli.Close()
+	newDumpedRequest().WriteTo(cli)
+	select {
+	case a := <-answerc:
+		if !strings.Contains(a.String(), "bob@example.com") {
+			t.Fatalf("The answer was redacted:\n%s", a)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out: no CEA received")
+	}
+
+	var dumped int
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	for _, e := range logger.entries {
+		s, ok := e.keyvals["message"].(string)
+		if !ok {
+			continue // logged by the connection, not the Dumper
+		}
+		dumped++
+		if !strings.Contains(s, diam.RedactedValue) || strings.Contains(s, "@example.com") {
+			t.Fatalf("Unexpected %s:\n%s", e.msg, s)
+		}
+	}
+	if dumped != 2 {
+		t.Fatalf("Unexpected number of dumped messages. Want 2, have %d", dumped)
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Causes of the DecodeErrors returned for malformed messages.
+var (
+	// ErrTruncated is the cause of decode errors of messages, headers
+	// and AVPs that are shorter than their length.
+	ErrTruncated = errors.New("truncated data")
+
+	// ErrBadMessageLength is the cause of decode errors of messages
+	// whose length is shorter than the header or longer than
+	// MaxMessageLength.
+	ErrBadMessageLength = errors.New("bad message length")
+
+	// ErrBadAVPLength is the cause of decode errors of AVPs whose
+	// length is shorter than their header, or longer than the message
+	// or grouped AVP that contains them.
+	ErrBadAVPLength = errors.New("bad AVP length")
+
+	// ErrBadPadding is the cause of decode errors of AVPs that are not
+	// followed by the padding to a multiple of 4 bytes.
+	ErrBadPadding = errors.New("bad AVP padding")
+)
+
+// DecodeError is returned when decoding malformed messages, headers or
+// AVPs, as opposed to the errors of the underlying reader. Its Err is
+// one of ErrTruncated, ErrBadMessageLength, ErrBadAVPLength or
+// ErrBadPadding, or the error of the decoding of an AVP value, such as a
+// *datatype.LengthError for values of the wrong length.
+type DecodeError struct {
+	Err    error
+	Code   uint32 // the code of the AVP, if any
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *DecodeError) Error() string {
+	if e.Code != 0 {
+		return fmt.Sprintf("AVP %d: %s: %s", e.Code, e.Err, e.Reason)
+	}
+	return fmt.Sprintf("%s: %s", e.Err, e.Reason)
+}
+
+// Unwrap returns the cause of the error.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+func decodeErrorf(err error, code uint32, format string, args ...interface{}) *DecodeError {
+	return &DecodeError{Err: err, Code: code, Reason: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,47 @@
+// +build go1.18
+
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+// The seed corpus of the fuzz targets is in testdata/fuzz, in addition to
+// the messages of the tests. Run them with, for example:
+//
+//	go test -run '^$' -fuzz FuzzReadMessage
+
+func FuzzReadMessage(f *testing.F) {
+	f.Add(testMessage)
+	f.Add(testMessage[:HeaderLength])
+	f.Fuzz(func(t *testing.T, data []byte) {
+		m, err := ReadMessage(bytes.NewReader(data), dict.Default)
+		if err != nil {
+			return
+		}
+		if _, err = m.Serialize(); err != nil {
+			t.Fatalf("Decoded message cannot be serialized: %v", err)
+		}
+		_ = m.String()
+	})
+}
+
+func FuzzDecodeAVP(f *testing.F) {
+	f.Add(testMessage[HeaderLength:])
+	f.Fuzz(func(t *testing.T, data []byte) {
+		a, err := DecodeAVP(data, 0, dict.Default)
+		if err != nil {
+			return
+		}
+		if _, err = a.Serialize(); err != nil {
+			t.Fatalf("Decoded AVP cannot be serialized: %v", err)
+		}
+	})
+}
@@ -7,6 +7,7 @@ package diam
 import (
 	"bytes"
 	"fmt"
+	"reflect"
 
 	"github.com/omnicate/go-diameter/v4/diam/datatype"
 	"github.com/omnicate/go-diameter/v4/diam/dict"
@@ -17,28 +18,107 @@ import (
 const GroupedAVPType = 50
 
 // GroupedAVP that is different from the dummy datatype.Grouped.
+//
+// The GroupedAVPs decoded from messages keep the payload they were
+// decoded from, and are serialized as read while their AVPs are not
+// modified, preserving the padding of the AVPs that they contain. This
+// lets relays forward the grouped AVPs they inspect byte-identically.
+// The AVPs are modified by adding, removing or replacing AVPs, or by
+// changing their code, flags, vendor or data. The values of the AVPs must
+// be replaced rather than changed in place, such as the bytes of an
+// OctetString, which is not detected.
 type GroupedAVP struct {
 	AVP []*AVP
+
+	raw  []byte // Payload decoded, if any
+	orig []AVP  // AVPs decoded from raw
 }
 
 // DecodeGrouped decodes a Grouped AVP from a datatype.Grouped (byte array).
 func DecodeGrouped(data datatype.Grouped, application uint32, dictionary *dict.Parser) (*GroupedAVP, error) {
-	g := &GroupedAVP{}
 	b := []byte(data)
+	// Allocate the AVPs and their copies at once.
+	k := countAVPs(b)
+	avps := make([]AVP, 2*k)
+	g := &GroupedAVP{AVP: make([]*AVP, 0, k), raw: b}
 	for n := 0; n < len(b); {
-		avp, err := DecodeAVP(b[n:], application, dictionary)
-		if err != nil {
+		var a *AVP
+		if i := len(g.AVP); i < k {
+			a = &avps[i]
+		} else {
+			a = new(AVP)
+		}
+		if err := a.DecodeFromBytes(b[n:], application, dictionary); err != nil {
 			return nil, err
 		}
-		g.AVP = append(g.AVP, avp)
-		n += avp.Len()
+		g.AVP = append(g.AVP, a)
+		n += a.Len()
+	}
+	g.orig = avps[k:]
+	if len(g.AVP) != k {
+		g.orig = make([]AVP, len(g.AVP))
+	}
+	for i, a := range g.AVP {
+		g.orig[i] = *a
 	}
 	// TODO: handle nested groups?
 	return g, nil
 }
 
+// countAVPs returns the number of AVPs in b, as given by the lengths of
+// their headers.
+func countAVPs(b []byte) int {
+	var k int
+	for n := 0; n+8 <= len(b); k++ {
+		l := int(uint24to32(b[n+5 : n+8]))
+		if l < 8 {
+			return k + 1
+		}
+		n += l + (4-l%4)%4
+	}
+	return k
+}
+
+// modified reports whether the AVPs of g changed since they were
+// decoded, if they were.
+func (g *GroupedAVP) modified() bool {
+	if g.raw == nil || len(g.AVP) != len(g.orig) {
+		return true
+	}
+	for i, a := range g.AVP {
+		o := &g.orig[i]
+		if a == nil || a.Code != o.Code || a.Flags != o.Flags || a.VendorID != o.VendorID ||
+			!sameData(a.Data, o.Data) {
+			return true
+		}
+		if ga, ok := a.Data.(*GroupedAVP); ok && ga.modified() {
+			return true
+		}
+	}
+	return false
+}
+
+// sameData reports whether x and y are the same value. Values that are
+// slices are the same if they share their bytes.
+func sameData(x, y datatype.Type) bool {
+	if x == nil || y == nil {
+		return x == y
+	}
+	vx, vy := reflect.ValueOf(x), reflect.ValueOf(y)
+	if vx.Type() != vy.Type() {
+		return false
+	}
+	if vx.Kind() == reflect.Slice {
+		return vx.Pointer() == vy.Pointer() && vx.Len() == vy.Len()
+	}
+	return vx.Type().Comparable() && x == y
+}
+
 // Serialize implements the datatype.Type interface.
 func (g *GroupedAVP) Serialize() []byte {
+	if !g.modified() {
+		return g.raw
+	}
 	b := make([]byte, g.Len())
 	var n int
 	for _, a := range g.AVP {
@@ -50,6 +130,9 @@ func (g *GroupedAVP) Serialize() []byte {
 
 // Len implements the datatype.Type interface.
 func (g *GroupedAVP) Len() int {
+	if !g.modified() {
+		return len(g.raw)
+	}
 	var l int
 	for _, a := range g.AVP {
 		l += a.Len()
@@ -71,3 +71,75 @@ func TestMakeGroupedAVP(t *testing.T) {
 	}
 	t.Logf("Message:\n%s", a)
 }
+
+func TestGroupedAVPPreserved(t *testing.T) {
+	// Vendor-Specific-Application-Id with an unknown AVP and padding
+	// that is not zero.
+	raw := []byte{
+		0x00, 0x00, 0x01, 0x04,
+		0x40, 0x00, 0x00, 0x2c,
+		0x00, 0x00, 0x01, 0x0a, // Vendor-Id
+		0x40, 0x00, 0x00, 0x0c,
+		0x00, 0x00, 0x28, 0xaf,
+		0x00, 0x00, 0x27, 0x0f, // Unknown AVP 9999
+		0x00, 0x00, 0x00, 0x0b,
+		0x61, 0x62, 0x63, 0xff,
+		0x00, 0x00, 0x01, 0x02, // Auth-Application-Id
+		0x40, 0x00, 0x00, 0x0c,
+		0x00, 0x00, 0x00, 0x04,
+	}
+	a, err := DecodeAVP(raw, 0, dict.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := a.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, raw) {
+		t.Fatalf("Unexpected value.\nWant:\n%s\nHave:\n%s", hex.Dump(raw), hex.Dump(b))
+	}
+	// Modified groups are serialized from their AVPs.
+	g := a.Data.(*GroupedAVP)
+	g.AVP[2].Data = datatype.Unsigned32(16777251)
+	if b, err = a.Serialize(); err != nil {
+		t.Fatal(err)
+	}
+	want := append([]byte(nil), raw...)
+	copy(want[40:], []byte{0x01, 0x00, 0x00, 0x23})
+	want[31] = 0 // padding
+	if !bytes.Equal(b, want) {
+		t.Fatalf("Unexpected value.\nWant:\n%s\nHave:\n%s", hex.Dump(want), hex.Dump(b))
+	}
+	g.AddAVP(NewAVP(avp.VendorID, avp.Mbit, 0, datatype.Unsigned32(13)))
+	if n := a.Len(); n != len(raw)+12 {
+		t.Fatalf("Unexpected length. Want %d, have %d", len(raw)+12, n)
+	}
+}
+
+func TestNestedGroupedAVPModified(t *testing.T) {
+	outer := NewAVP(avp.ProxyInfo, avp.Mbit, 0, &GroupedAVP{
+		AVP: []*AVP{
+			NewAVP(avp.ProxyHost, avp.Mbit, 0, datatype.DiameterIdentity("proxy")),
+			NewAVP(avp.VendorSpecificApplicationID, avp.Mbit, 0, &GroupedAVP{
+				AVP: []*AVP{NewAVP(avp.VendorID, avp.Mbit, 0, datatype.Unsigned32(10415))},
+			}),
+		},
+	})
+	b, err := outer.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := DecodeAVP(b, 0, dict.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inner := a.Data.(*GroupedAVP).AVP[1].Data.(*GroupedAVP)
+	inner.AVP[0].Data = datatype.Unsigned32(13)
+	if b, err = a.Serialize(); err != nil {
+		t.Fatal(err)
+	}
+	if b[len(b)-1] != 13 {
+		t.Fatalf("Nested change not serialized:\n%s", hex.Dump(b))
+	}
+}
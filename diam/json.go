@@ -0,0 +1,385 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+// jsonMessage is the JSON form of a Message.
+type jsonMessage struct {
+	Command string     `json:"command,omitempty"`
+	Header  jsonHeader `json:"header"`
+	AVP     []*jsonAVP `json:"avps"`
+}
+
+// jsonHeader is the JSON form of a Header. The message length is left
+// out as it is computed from the AVPs.
+type jsonHeader struct {
+	Version       uint8  `json:"version"`
+	CommandFlags  uint8  `json:"flags"`
+	CommandCode   uint32 `json:"code"`
+	ApplicationID uint32 `json:"application_id"`
+	HopByHopID    uint32 `json:"hop_by_hop_id"`
+	EndToEndID    uint32 `json:"end_to_end_id"`
+}
+
+// jsonAVP is the JSON form of an AVP. Values are in Value, except
+// those of binary OctetString and unknown AVPs, which are hex encoded
+// in Hex, and those of Grouped AVPs, which are in AVP.
+type jsonAVP struct {
+	Name     string          `json:"name,omitempty"`
+	Code     uint32          `json:"code,omitempty"`
+	Flags    *uint8          `json:"flags,omitempty"`
+	VendorID uint32          `json:"vendor_id,omitempty"`
+	Type     string          `json:"type,omitempty"`
+	Value    json.RawMessage `json:"value,omitempty"`
+	Enum     string          `json:"enum,omitempty"`
+	Hex      string          `json:"hex,omitempty"`
+	AVP      []*jsonAVP      `json:"avps,omitempty"`
+}
+
+// MarshalJSON implements the json.Marshaler interface. AVPs are
+// annotated with their name and data type from the message dictionary,
+// Enumerated values with the name of the item, and Grouped AVPs contain
+// their AVPs:
+//
+//	{
+//		"command": "Credit-Control-Request",
+//		"header": {"version": 1, "flags": 192, "code": 272, ...},
+//		"avps": [
+//			{"name": "CC-Request-Type", "code": 416, "flags": 64,
+//			 "type": "Enumerated", "value": 1, "enum": "INITIAL_REQUEST"},
+//			...
+//		]
+//	}
+func (m *Message) MarshalJSON() ([]byte, error) {
+	if err := m.Decode(); err != nil {
+		return nil, err
+	}
+	jm := &jsonMessage{
+		Header: jsonHeader{
+			Version:       m.Header.Version,
+			CommandFlags:  m.Header.CommandFlags,
+			CommandCode:   m.Header.CommandCode,
+			ApplicationID: m.Header.ApplicationID,
+			HopByHopID:    m.Header.HopByHopID,
+			EndToEndID:    m.Header.EndToEndID,
+		},
+	}
+	if cmd, err := m.Dictionary().FindCommand(m.Header.ApplicationID, m.Header.CommandCode); err == nil {
+		if m.Header.CommandFlags&RequestFlag == RequestFlag {
+			jm.Command = cmd.Name + "-Request"
+		} else {
+			jm.Command = cmd.Name + "-Answer"
+		}
+	}
+	var err error
+	if jm.AVP, err = m.jsonAVPs(m.AVP); err != nil {
+		return nil, err
+	}
+	return json.Marshal(jm)
+}
+
+func (m *Message) jsonAVPs(avps []*AVP) ([]*jsonAVP, error) {
+	ja := make([]*jsonAVP, 0, len(avps))
+	for _, a := range avps {
+		j, err := m.jsonAVP(a)
+		if err != nil {
+			return nil, err
+		}
+		ja = append(ja, j)
+	}
+	return ja, nil
+}
+
+func (m *Message) jsonAVP(a *AVP) (*jsonAVP, error) {
+	flags := a.Flags
+	j := &jsonAVP{
+		Code:     a.Code,
+		Flags:    &flags,
+		VendorID: a.VendorID,
+	}
+	dictAVP, err := m.Dictionary().FindAVPWithVendor(m.Header.ApplicationID, a.Code, a.VendorID)
+	if err == nil {
+		j.Name = dictAVP.Name
+		j.Type = dictAVP.Data.TypeName
+	}
+	var v interface{}
+	switch d := a.Data.(type) {
+	case *GroupedAVP:
+		j.AVP, err = m.jsonAVPs(d.AVP)
+		return j, err
+	case datatype.Grouped:
+		g, err := DecodeGrouped(d, m.Header.ApplicationID, m.Dictionary())
+		if err != nil {
+			j.Hex = hex.EncodeToString(d)
+			return j, nil
+		}
+		j.AVP, err = m.jsonAVPs(g.AVP)
+		return j, err
+	case datatype.Address:
+		ip := d.IP()
+		if ip == nil {
+			j.Hex = hex.EncodeToString(d)
+			return j, nil
+		}
+		v = ip.String()
+	case datatype.IPv4:
+		v = net.IP(d).String()
+	case datatype.IPv6:
+		v = net.IP(d).String()
+	case datatype.OctetString:
+		if !printable(string(d)) {
+			j.Hex = hex.EncodeToString([]byte(d))
+			return j, nil
+		}
+		v = string(d)
+	case datatype.UTF8String:
+		v = string(d)
+	case datatype.DiameterIdentity:
+		v = string(d)
+	case datatype.DiameterURI:
+		v = string(d)
+	case datatype.IPFilterRule:
+		v = string(d)
+	case datatype.QoSFilterRule:
+		v = string(d)
+	case datatype.Enumerated:
+		v = int32(d)
+		if dictAVP != nil {
+			j.Enum = dictAVP.EnumName(int32(d))
+		}
+	case datatype.Integer32:
+		v = int32(d)
+	case datatype.Integer64:
+		v = int64(d)
+	case datatype.Unsigned32:
+		v = uint32(d)
+	case datatype.Unsigned64:
+		v = uint64(d)
+	case datatype.Float32:
+		v = float32(d)
+	case datatype.Float64:
+		v = float64(d)
+	case datatype.Time:
+		v = time.Time(d)
+	default:
+		j.Hex = hex.EncodeToString(a.Data.Serialize())
+		return j, nil
+	}
+	j.Value, err = json.Marshal(v)
+	return j, err
+}
+
+// printable reports whether s is UTF-8 text that can be shown as is.
+func printable(s string) bool {
+	if !utf8.ValidString(s) {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, decoding the
+// JSON form produced by MarshalJSON with the message dictionary, or the
+// default dictionary if the message has none.
+//
+// The command name and the AVP names, types and Enumerated item names
+// are informative and may be left out, but they allow hand written
+// messages to omit the codes and flags of the AVPs, which are then
+// taken from the dictionary. Enumerated values may be given by item
+// name. As for NewMessage, zero Hop-by-Hop and End-to-End IDs are
+// replaced by random ones.
+func (m *Message) UnmarshalJSON(b []byte) error {
+	var jm jsonMessage
+	if err := json.Unmarshal(b, &jm); err != nil {
+		return err
+	}
+	h := jm.Header
+	nm := NewMessage(h.CommandCode, h.CommandFlags, h.ApplicationID, h.HopByHopID, h.EndToEndID, m.dictionary)
+	if h.Version != 0 {
+		nm.Header.Version = h.Version
+	}
+	for _, j := range jm.AVP {
+		a, err := nm.avpFromJSON(j)
+		if err != nil {
+			return err
+		}
+		nm.AddAVP(a)
+	}
+	m.Header = nm.Header
+	m.AVP = nm.AVP
+	m.stream = nm.stream
+	return nil
+}
+
+func (m *Message) avpFromJSON(j *jsonAVP) (*AVP, error) {
+	var dictAVP *dict.AVP
+	if j.Code == 0 && j.Name != "" {
+		vendorID := j.VendorID
+		if vendorID == 0 {
+			vendorID = dict.UndefinedVendorID
+		}
+		a, err := m.Dictionary().FindAVPWithVendor(m.Header.ApplicationID, j.Name, vendorID)
+		if err != nil {
+			return nil, err
+		}
+		dictAVP = a
+		j.Code, j.VendorID = a.Code, a.VendorID
+	} else if a, err := m.Dictionary().FindAVPWithVendor(m.Header.ApplicationID, j.Code, j.VendorID); err == nil {
+		dictAVP = a
+	}
+
+	var flags uint8
+	switch {
+	case j.Flags != nil:
+		flags = *j.Flags
+	case dictAVP != nil && strings.Contains(dictAVP.Must, "M"):
+		flags = avp.Mbit
+	}
+
+	typ := datatype.UnknownType
+	switch {
+	case j.Type != "":
+		t, ok := datatype.Available[j.Type]
+		if !ok && j.Type != "Unknown" {
+			return nil, fmt.Errorf("AVP %d has unknown type %s", j.Code, j.Type)
+		}
+		typ = t
+	case dictAVP != nil:
+		typ = dictAVP.Data.Type
+	case j.AVP != nil:
+		typ = datatype.GroupedType
+	}
+
+	data, err := m.jsonData(j, typ, dictAVP)
+	if err != nil {
+		return nil, fmt.Errorf("AVP %d: %v", j.Code, err)
+	}
+	return NewAVP(j.Code, flags, j.VendorID, data), nil
+}
+
+func (m *Message) jsonData(j *jsonAVP, typ datatype.TypeID, dictAVP *dict.AVP) (datatype.Type, error) {
+	if j.Hex != "" {
+		b, err := hex.DecodeString(j.Hex)
+		if err != nil {
+			return nil, err
+		}
+		if typ == datatype.GroupedType {
+			return DecodeGrouped(datatype.Grouped(b), m.Header.ApplicationID, m.Dictionary())
+		}
+		return datatype.Decode(typ, b)
+	}
+	if typ == datatype.GroupedType {
+		g := &GroupedAVP{}
+		for _, ja := range j.AVP {
+			a, err := m.avpFromJSON(ja)
+			if err != nil {
+				return nil, err
+			}
+			g.AddAVP(a)
+		}
+		return g, nil
+	}
+	if j.Value == nil {
+		return nil, fmt.Errorf("no value")
+	}
+	switch typ {
+	case datatype.AddressType, datatype.IPv4Type, datatype.IPv6Type:
+		var s string
+		if err := json.Unmarshal(j.Value, &s); err != nil {
+			return nil, err
+		}
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid address %q", s)
+		}
+		switch typ {
+		case datatype.IPv4Type:
+			return datatype.IPv4(ip), nil
+		case datatype.IPv6Type:
+			return datatype.IPv6(ip), nil
+		}
+		if v4 := ip.To4(); v4 != nil {
+			ip = v4
+		}
+		return datatype.Address(ip), nil
+	case datatype.OctetStringType, datatype.UTF8StringType, datatype.DiameterIdentityType,
+		datatype.DiameterURIType, datatype.IPFilterRuleType, datatype.QoSFilterRuleType:
+		var s string
+		if err := json.Unmarshal(j.Value, &s); err != nil {
+			return nil, err
+		}
+		switch typ {
+		case datatype.UTF8StringType:
+			return datatype.UTF8String(s), nil
+		case datatype.DiameterIdentityType:
+			return datatype.DiameterIdentity(s), nil
+		case datatype.DiameterURIType:
+			return datatype.DiameterURI(s), nil
+		case datatype.IPFilterRuleType:
+			return datatype.IPFilterRule(s), nil
+		case datatype.QoSFilterRuleType:
+			return datatype.QoSFilterRule(s), nil
+		}
+		return datatype.OctetString(s), nil
+	case datatype.EnumeratedType:
+		var name string
+		if json.Unmarshal(j.Value, &name) == nil {
+			if dictAVP == nil {
+				return nil, fmt.Errorf("cannot resolve item %q without dictionary", name)
+			}
+			return enumByName(dictAVP, name)
+		}
+		var v int32
+		err := json.Unmarshal(j.Value, &v)
+		return datatype.Enumerated(v), err
+	case datatype.Integer32Type:
+		var v int32
+		err := json.Unmarshal(j.Value, &v)
+		return datatype.Integer32(v), err
+	case datatype.Integer64Type:
+		var v int64
+		err := json.Unmarshal(j.Value, &v)
+		return datatype.Integer64(v), err
+	case datatype.Unsigned32Type:
+		var v uint32
+		err := json.Unmarshal(j.Value, &v)
+		return datatype.Unsigned32(v), err
+	case datatype.Unsigned64Type:
+		var v uint64
+		err := json.Unmarshal(j.Value, &v)
+		return datatype.Unsigned64(v), err
+	case datatype.Float32Type:
+		var v float32
+		err := json.Unmarshal(j.Value, &v)
+		return datatype.Float32(v), err
+	case datatype.Float64Type:
+		var v float64
+		err := json.Unmarshal(j.Value, &v)
+		return datatype.Float64(v), err
+	case datatype.TimeType:
+		var v time.Time
+		err := json.Unmarshal(j.Value, &v)
+		return datatype.Time(v), err
+	}
+	return nil, fmt.Errorf("unsupported value of type %d", typ)
+}
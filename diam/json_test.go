@@ -0,0 +1,87 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+func TestMessageJSON(t *testing.T) {
+	m, err := ReadMessage(bytes.NewReader(testMessage), dict.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.NewAVP(avp.Class, 0, 0, datatype.OctetString([]byte{0, 1, 2}))
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`"command":"Capabilities-Exchange-Request"`,
+		`"name":"Host-IP-Address","code":257,"flags":64,"type":"Address","value":"10.1.0.1"`,
+		`"name":"Vendor-Specific-Application-Id","code":260,"flags":64,"type":"Grouped","avps":[`,
+		`"hex":"000102"`,
+	} {
+		if !strings.Contains(string(b), want) {
+			t.Fatalf("JSON is missing %s: %s", want, b)
+		}
+	}
+	var r Message
+	if err = json.Unmarshal(b, &r); err != nil {
+		t.Fatal(err)
+	}
+	m1, _ := m.Serialize()
+	m2, _ := r.Serialize()
+	if !bytes.Equal(m1, m2) {
+		t.Fatalf("Unexpected message.\nWant %x\nHave %x", m1, m2)
+	}
+}
+
+func TestMessageUnmarshalJSONByName(t *testing.T) {
+	var m Message
+	err := json.Unmarshal([]byte(`{
+		"header": {"flags": 192, "code": 272, "application_id": 4},
+		"avps": [
+			{"name": "Session-Id", "value": "client;1;1"},
+			{"name": "CC-Request-Type", "value": "TERMINATION_REQUEST"},
+			{"name": "Multiple-Services-Credit-Control", "avps": [
+				{"name": "Rating-Group", "value": 10},
+				{"name": "Used-Service-Unit", "avps": [
+					{"name": "CC-Total-Octets", "value": 1024}
+				]}
+			]}
+		]
+	}`), &m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Header.HopByHopID == 0 || m.Header.MessageLength != uint32(m.Len()) {
+		t.Fatalf("Unexpected header: %s", m.Header)
+	}
+	a, err := m.FindAVP(avp.CCRequestType, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Data != datatype.Enumerated(3) || a.Flags != avp.Mbit {
+		t.Fatalf("Unexpected CC-Request-Type: %s", a)
+	}
+	a, err = m.FindAVP(avp.CCTotalOctets, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Data != datatype.Unsigned64(1024) {
+		t.Fatalf("Unexpected CC-Total-Octets: %s", a)
+	}
+	if err = json.Unmarshal([]byte(`{"avps": [{"name": "No-Such-AVP", "value": 1}]}`), &m); err == nil {
+		t.Fatal("Unexpected success with unknown AVP")
+	}
+}
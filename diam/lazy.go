@@ -0,0 +1,140 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+// ReadMessageLazy is like ReadMessage, but only decodes the AVPs of the
+// base protocol, such as the Session-Id, Origin-Host or Route-Record
+// AVPs used to route and relay messages. The AVPs of the application of
+// the message keep their payload, which is serialized as read and is
+// decoded on access by FindAVP, FindAVPs, FindAVPsWithPath, FindPath,
+// FindAllPath, Unmarshal, CheckUnsupportedAVPs, MarshalJSON and String,
+// or by Decode. The grouped AVPs decoded are still serialized as read
+// unless modified, see GroupedAVP.
+//
+// The body of the message is read into a pooled buffer, which Release
+// returns to the pool. This reduces the allocations per message of
+// relays and proxies, which seldom inspect the AVPs of the applications.
+func ReadMessageLazy(reader io.Reader, dictionary *dict.Parser) (*Message, error) {
+	return readMessage(reader, dictionary, true)
+}
+
+// lazyData is the payload of the AVPs of messages read by ReadMessageLazy
+// that were not decoded yet.
+type lazyData []byte
+
+// Serialize implements the datatype.Type interface.
+func (l lazyData) Serialize() []byte {
+	return []byte(l)
+}
+
+// Len implements the datatype.Type interface.
+func (l lazyData) Len() int {
+	return len(l)
+}
+
+// Padding implements the datatype.Type interface.
+func (l lazyData) Padding() int {
+	return (4 - len(l)%4) % 4
+}
+
+// Type implements the datatype.Type interface.
+func (l lazyData) Type() datatype.TypeID {
+	return datatype.UnknownType
+}
+
+// String implements the datatype.Type interface.
+func (l lazyData) String() string {
+	return fmt.Sprintf("Lazy{%#x},Padding:%d", string(l), l.Padding())
+}
+
+// decodeLazily decodes the payload of the AVP a of the message read by
+// ReadMessageLazy if it belongs to the base protocol, or keeps it until
+// accessed otherwise.
+func (m *Message) decodeLazily(a *AVP, payload []byte) error {
+	da, err := m.Dictionary().FindAVPWithVendor(m.Header.ApplicationID, a.Code, a.VendorID)
+	if err == nil && da.App != nil && da.App.ID != 0 {
+		a.Data = lazyData(payload)
+		return nil
+	}
+	if err != nil && da == nil {
+		return err
+	}
+	return a.decodeDictData(da, payload, m.Header.ApplicationID, m.Dictionary())
+}
+
+// Decode decodes the AVPs of the message read by ReadMessageLazy that
+// were not decoded yet, for example before accessing its AVP field
+// directly. It does nothing for other messages.
+func (m *Message) Decode() error {
+	return m.decodeLazyAVPs(func(*AVP) bool { return true })
+}
+
+// decodeLazyAVPs decodes the AVPs of the message that were not decoded
+// yet for which f returns true.
+func (m *Message) decodeLazyAVPs(f func(a *AVP) bool) error {
+	if !m.lazy {
+		return nil
+	}
+	for _, a := range m.AVP {
+		raw, ok := a.Data.(lazyData)
+		if !ok || !f(a) {
+			continue
+		}
+		da := *a
+		if err := da.decodeData(raw, m.Header.ApplicationID, m.Dictionary()); err != nil {
+			return err
+		}
+		a.Data = da.Data
+	}
+	return nil
+}
+
+// decodeLazyCode decodes the AVPs of the message that were not decoded
+// yet with the given code, or that may contain it.
+func (m *Message) decodeLazyCode(code uint32) error {
+	return m.decodeLazyAVPs(func(a *AVP) bool {
+		if a.Code == code {
+			return true
+		}
+		da, err := m.Dictionary().FindAVPWithVendor(m.Header.ApplicationID, a.Code, a.VendorID)
+		return err == nil && da.Data.Type == datatype.GroupedType
+	})
+}
+
+// Release returns the buffer of the message read by ReadMessageLazy to
+// the pool. The message and its AVPs must not be used afterwards,
+// including the messages created from it, such as its answers. It does
+// nothing for other messages.
+func (m *Message) Release() {
+	if m.body == nil {
+		return
+	}
+	m.body.Reset()
+	bodyBufferPool.Put(m.body)
+	m.body = nil
+	m.AVP = nil
+}
+
+var bodyBufferPool sync.Pool
+
+// newBodyBuffer returns a pooled buffer with a capacity of at least n.
+func newBodyBuffer(n int) *bytes.Buffer {
+	b, _ := bodyBufferPool.Get().(*bytes.Buffer)
+	if b == nil {
+		b = new(bytes.Buffer)
+	}
+	b.Grow(n)
+	return b
+}
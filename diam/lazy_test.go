@@ -0,0 +1,105 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+func newLazyTestCCR(t testing.TB) []byte {
+	m := NewRequest(CreditControl, 4, dict.Default)
+	m.NewAVP(avp.SessionID, avp.Mbit, 0, datatype.UTF8String("cli;1;2"))
+	m.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("cli"))
+	m.NewAVP(avp.CCRequestType, avp.Mbit, 0, datatype.Enumerated(1))
+	m.NewAVP(avp.MultipleServicesCreditControl, avp.Mbit, 0, &GroupedAVP{
+		AVP: []*AVP{
+			NewAVP(avp.RatingGroup, avp.Mbit, 0, datatype.Unsigned32(7)),
+		},
+	})
+	b, err := m.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestReadMessageLazy(t *testing.T) {
+	b := newLazyTestCCR(t)
+	m, err := ReadMessageLazy(bytes.NewReader(b), dict.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := m.AVP[0].Data.(datatype.UTF8String); !ok {
+		t.Fatalf("Unexpected Session-Id. Want UTF8String, have %T", m.AVP[0].Data)
+	}
+	if _, ok := m.AVP[2].Data.(lazyData); !ok {
+		t.Fatalf("Unexpected CC-Request-Type. Want lazy data, have %T", m.AVP[2].Data)
+	}
+	have, err := m.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(have, b) {
+		t.Fatalf("Unexpected message.\nWant:\n%x\nHave:\n%x", b, have)
+	}
+
+	a, err := m.FindAVP(avp.CCRequestType, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Data != datatype.Enumerated(1) {
+		t.Fatalf("Unexpected CC-Request-Type. Want Enumerated{1}, have %s", a.Data)
+	}
+	a, err = m.FindPath("Multiple-Services-Credit-Control/Rating-Group")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Data != datatype.Unsigned32(7) {
+		t.Fatalf("Unexpected Rating-Group. Want Unsigned32{7}, have %s", a.Data)
+	}
+	if have, _ = m.Serialize(); !bytes.Equal(have, b) {
+		t.Fatalf("Unexpected decoded message.\nWant:\n%x\nHave:\n%x", b, have)
+	}
+
+	m.Release()
+	if m.AVP != nil {
+		t.Fatalf("Unexpected AVPs after Release: %d", len(m.AVP))
+	}
+}
+
+func TestMessage_Decode(t *testing.T) {
+	m, err := ReadMessageLazy(bytes.NewReader(newLazyTestCCR(t)), dict.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Release()
+	if err = m.Decode(); err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range m.AVP {
+		if _, ok := a.Data.(lazyData); ok {
+			t.Fatalf("Unexpected lazy AVP %d after Decode", a.Code)
+		}
+	}
+	if _, ok := m.AVP[3].Data.(*GroupedAVP); !ok {
+		t.Fatalf("Unexpected Multiple-Services-Credit-Control. Want *GroupedAVP, have %T", m.AVP[3].Data)
+	}
+}
+
+func BenchmarkReadMessageLazy(b *testing.B) {
+	msg := newLazyTestCCR(b)
+	reader := bytes.NewReader(msg)
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		m, _ := ReadMessageLazy(reader, dict.Default)
+		m.Release()
+		reader.Seek(0, 0)
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package load provides the Diameter Load Information Conveyance (DLIC)
+// of RFC 8583.
+//
+// A node reports its load by adding Load AVPs to the answers it sends,
+// using a Reporter. Load values range from 0 (idle) to MaxValue
+// (fully loaded).
+//
+// Nodes that receive answers keep the latest load of each peer in a
+// Table, which routing tables use to weight the selection of peers: the
+// less loaded peers receive a larger share of requests.
+//
+// Example of a reporting node:
+//
+//	mux := sm.New(settings)
+//	mux.ReportLoad(30000)
+//	mux.HandleFunc("CCR", func(c diam.Conn, m *diam.Message) {
+//		a := m.Answer(diam.Success)
+//		...
+//		mux.LoadReporter().Answer(a)
+//		a.WriteTo(c)
+//	})
+//
+// Example of a node that selects peers by their load:
+//
+//	loads := load.NewTable()
+//	mux := sm.New(settings)
+//	mux.SetLoadTable(loads) // updated with the answers received
+//	routes := sm.NewRoutingTable()
+//	routes.SetLoadTable(loads)
+//	cli := &sm.Client{Handler: mux, Routes: routes}
+package load
@@ -0,0 +1,100 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package load
+
+import (
+	"fmt"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// MaxValue is the Load-Value of fully loaded nodes. Load-Values are
+// relative, from 0 to MaxValue.
+const MaxValue uint64 = 65535
+
+// Type is the value of the Load-Type AVP.
+type Type int32
+
+// Load types. See RFC 8583 for details.
+const (
+	HostLoad Type = 0 // Load of the host that sent the answer
+	PeerLoad Type = 1 // Load of the peer the answer is received from
+)
+
+// String implements the fmt.Stringer interface.
+func (t Type) String() string {
+	switch t {
+	case HostLoad:
+		return "HOST"
+	case PeerLoad:
+		return "PEER"
+	}
+	return fmt.Sprintf("Type(%d)", int32(t))
+}
+
+// Load is the load of a node, carried in the Load AVP.
+type Load struct {
+	Type     Type
+	Value    uint64
+	SourceID datatype.DiameterIdentity // Identity of the node
+}
+
+// AVP returns the Load grouped AVP that carries the load.
+func (l *Load) AVP() *diam.AVP {
+	return diam.NewAVP(avp.Load, 0, 0, &diam.GroupedAVP{
+		AVP: []*diam.AVP{
+			diam.NewAVP(avp.LoadType, 0, 0, datatype.Enumerated(l.Type)),
+			diam.NewAVP(avp.LoadValue, 0, 0, datatype.Unsigned64(l.Value)),
+			diam.NewAVP(avp.SourceID, 0, 0, l.SourceID),
+		},
+	})
+}
+
+func (l *Load) String() string {
+	return fmt.Sprintf("{Type:%s,Value:%d,SourceID:%s}", l.Type, l.Value, l.SourceID)
+}
+
+// loadAVP is used to unmarshal the Load AVP.
+type loadAVP struct {
+	Type     *int32                    `avp:"Load-Type"`
+	Value    *uint64                   `avp:"Load-Value"`
+	SourceID datatype.DiameterIdentity `avp:"SourceID"`
+}
+
+// message is used to unmarshal the Load AVPs of messages.
+type message struct {
+	Load []*loadAVP `avp:"Load"`
+}
+
+// Parse returns the loads carried in the Load AVPs of the given message.
+// Load AVPs without Load-Type, Load-Value or SourceID, or of unknown
+// types, are ignored, and values above MaxValue are reduced to MaxValue.
+func Parse(m *diam.Message) ([]*Load, error) {
+	var msg message
+	if err := m.Unmarshal(&msg); err != nil {
+		return nil, err
+	}
+	var loads []*Load
+	for _, a := range msg.Load {
+		if a.Type == nil || a.Value == nil || len(a.SourceID) == 0 {
+			continue
+		}
+		l := &Load{
+			Type:     Type(*a.Type),
+			Value:    *a.Value,
+			SourceID: a.SourceID,
+		}
+		if l.Type != HostLoad && l.Type != PeerLoad {
+			continue
+		}
+		if l.Value > MaxValue {
+			l.Value = MaxValue
+		}
+		loads = append(loads, l)
+	}
+	return loads, nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package load
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+func newAnswer() *diam.Message {
+	m := diam.NewRequest(diam.CreditControl, 4, dict.Default)
+	m.NewAVP(avp.SessionID, avp.Mbit, 0, datatype.UTF8String("sess"))
+	a := m.Answer(diam.Success)
+	a.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("srv"))
+	a.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity("test"))
+	return a
+}
+
+func TestReporter_Answer(t *testing.T) {
+	r := NewReporter("srv")
+	a := newAnswer()
+	n := len(a.AVP)
+	r.Answer(a)
+	if len(a.AVP) != n {
+		t.Fatalf("Load added without value: %s", a)
+	}
+	r.SetValue(MaxValue + 1)
+	r.Answer(a)
+	loads, err := Parse(diamtest.RoundTrip(t, a))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*Load{
+		{Type: HostLoad, Value: MaxValue, SourceID: "srv"},
+		{Type: PeerLoad, Value: MaxValue, SourceID: "srv"},
+	}
+	if !reflect.DeepEqual(loads, want) {
+		t.Fatalf("Unexpected loads.\nWant %v\nHave %v", want, loads)
+	}
+}
+
+func TestReporter_Forward(t *testing.T) {
+	srv := NewReporter("srv")
+	srv.SetValue(100)
+	a := newAnswer()
+	srv.Answer(a)
+	agent := NewReporter("agent")
+	agent.SetValue(200)
+	agent.Forward(a)
+	loads, err := Parse(diamtest.RoundTrip(t, a))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*Load{
+		{Type: HostLoad, Value: 100, SourceID: "srv"},
+		{Type: PeerLoad, Value: 200, SourceID: "agent"},
+	}
+	if !reflect.DeepEqual(loads, want) {
+		t.Fatalf("Unexpected loads.\nWant %v\nHave %v", want, loads)
+	}
+	agent.Clear()
+	agent.Forward(a)
+	if loads, _ = Parse(diamtest.RoundTrip(t, a)); len(loads) != 1 || loads[0].Type != HostLoad {
+		t.Fatalf("Unexpected loads: %v", loads)
+	}
+}
+
+func TestTable_Update(t *testing.T) {
+	r := NewReporter("srv")
+	r.SetValue(MaxValue)
+	a := newAnswer()
+	r.Answer(a)
+	a.AddAVP(diam.NewAVP(avp.Load, 0, 0, &diam.GroupedAVP{
+		AVP: []*diam.AVP{
+			diam.NewAVP(avp.LoadType, 0, 0, datatype.Enumerated(PeerLoad)),
+			diam.NewAVP(avp.LoadValue, 0, 0, datatype.Unsigned64(0)),
+		},
+	}))
+	tbl := NewTable()
+	if err := tbl.Update(diamtest.RoundTrip(t, a)); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := tbl.Value("srv"); !ok || v != MaxValue {
+		t.Fatalf("Unexpected load. Want %d, have %d", MaxValue, v)
+	}
+	if w := tbl.Weight("srv", 2); w != 2 {
+		t.Fatalf("Unexpected weight of loaded peer. Want 2, have %d", w)
+	}
+	if w := tbl.Weight("idle", 2); w != 2*int(MaxValue+1) {
+		t.Fatalf("Unexpected weight of idle peer. Want %d, have %d", 2*int(MaxValue+1), w)
+	}
+	tbl.Remove("srv")
+	if _, ok := tbl.Value("srv"); ok {
+		t.Fatal("Load was not removed")
+	}
+}
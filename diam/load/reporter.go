@@ -0,0 +1,103 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package load
+
+import (
+	"sync"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// Reporter holds the current load of a node and adds it to the answers
+// the node sends.
+//
+// It is safe for concurrent use.
+type Reporter struct {
+	sourceID datatype.DiameterIdentity
+
+	mu    sync.Mutex // guards value and set
+	value uint64
+	set   bool
+}
+
+// NewReporter creates and initializes a new Reporter of the load of the
+// node identified by sourceID, usually its Origin-Host.
+func NewReporter(sourceID datatype.DiameterIdentity) *Reporter {
+	return &Reporter{sourceID: sourceID}
+}
+
+// SetValue sets the load of the node, from 0 to MaxValue. Greater
+// values are reduced to MaxValue.
+func (r *Reporter) SetValue(value uint64) {
+	if value > MaxValue {
+		value = MaxValue
+	}
+	r.mu.Lock()
+	r.value, r.set = value, true
+	r.mu.Unlock()
+}
+
+// Clear stops reporting the load of the node.
+func (r *Reporter) Clear() {
+	r.mu.Lock()
+	r.value, r.set = 0, false
+	r.mu.Unlock()
+}
+
+// Value returns the load of the node, if set.
+func (r *Reporter) Value() (uint64, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.value, r.set
+}
+
+// Answer adds the load of the node to the answer a it originates, as
+// Load AVPs of both HostLoad and PeerLoad types. Nothing is added if the
+// load is not set.
+func (r *Reporter) Answer(a *diam.Message) {
+	value, ok := r.Value()
+	if !ok {
+		return
+	}
+	a.AddAVP((&Load{Type: HostLoad, Value: value, SourceID: r.sourceID}).AVP())
+	a.AddAVP((&Load{Type: PeerLoad, Value: value, SourceID: r.sourceID}).AVP())
+}
+
+// Forward replaces the PeerLoad Load AVPs of the answer a, forwarded by
+// an agent, with the load of the agent. The PeerLoad AVPs are removed
+// if the load is not set. See RFC 8583.
+func (r *Reporter) Forward(a *diam.Message) {
+	avps := a.AVP[:0]
+	for _, v := range a.AVP {
+		if isPeerLoad(v) {
+			a.Header.MessageLength -= uint32(v.Len())
+			continue
+		}
+		avps = append(avps, v)
+	}
+	a.AVP = avps
+	if value, ok := r.Value(); ok {
+		a.AddAVP((&Load{Type: PeerLoad, Value: value, SourceID: r.sourceID}).AVP())
+	}
+}
+
+// isPeerLoad returns whether a is a Load AVP of type PeerLoad.
+func isPeerLoad(a *diam.AVP) bool {
+	if a.Code != avp.Load || a.VendorID != 0 {
+		return false
+	}
+	group, ok := a.Data.(*diam.GroupedAVP)
+	if !ok {
+		return false
+	}
+	for _, ga := range group.AVP {
+		if ga.Code == avp.LoadType {
+			return ga.Data == datatype.Enumerated(PeerLoad)
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package load
+
+import (
+	"sync"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// Table holds the latest load reported by each peer, in the PeerLoad
+// Load AVPs of the answers they send.
+//
+// It is safe for concurrent use.
+type Table struct {
+	mu    sync.RWMutex // guards loads
+	loads map[datatype.DiameterIdentity]uint64
+}
+
+// NewTable creates and initializes an empty Table.
+func NewTable() *Table {
+	return &Table{loads: make(map[datatype.DiameterIdentity]uint64)}
+}
+
+// Update must be called for every answer received. It records the
+// PeerLoad loads carried in the answer m. HostLoad loads and requests
+// are ignored.
+func (t *Table) Update(m *diam.Message) error {
+	if m.Header.CommandFlags&diam.RequestFlag != 0 {
+		return nil
+	}
+	loads, err := Parse(m)
+	if err != nil {
+		return err
+	}
+	for _, l := range loads {
+		if l.Type == PeerLoad {
+			t.Set(l.SourceID, l.Value)
+		}
+	}
+	return nil
+}
+
+// Set records the load of the peer host.
+func (t *Table) Set(host datatype.DiameterIdentity, value uint64) {
+	if value > MaxValue {
+		value = MaxValue
+	}
+	t.mu.Lock()
+	t.loads[host] = value
+	t.mu.Unlock()
+}
+
+// Remove forgets the load of the peer host.
+func (t *Table) Remove(host datatype.DiameterIdentity) {
+	t.mu.Lock()
+	delete(t.loads, host)
+	t.mu.Unlock()
+}
+
+// Value returns the load of the peer host, if known.
+func (t *Table) Value(host datatype.DiameterIdentity) (uint64, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	v, ok := t.loads[host]
+	return v, ok
+}
+
+// Weight returns the weight of the peer host, scaled by its spare
+// capacity: weight * (MaxValue + 1 - load). Peers of unknown load are
+// scaled as idle ones, and fully loaded peers keep a minimal share.
+func (t *Table) Weight(host datatype.DiameterIdentity, weight int) int {
+	v, _ := t.Value(host)
+	return weight * int(MaxValue+1-v)
+}
@@ -0,0 +1,159 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// Level is the severity of a log entry.
+type Level int
+
+// Log levels.
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// String returns the name of the level.
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	}
+	return "level-" + strconv.Itoa(int(l))
+}
+
+// Logger logs structured entries: a message and a list of alternating
+// keys and values, such as
+//
+//	l.Log(diam.LevelError, "accept error", "addr", addr, "err", err)
+//
+// Loggers must be safe for concurrent use.
+type Logger interface {
+	// Enabled reports whether entries of the level are logged, which
+	// allows skipping the preparation of expensive values.
+	Enabled(level Level) bool
+
+	// Log logs an entry of the level with the message msg and keyvals.
+	Log(level Level, msg string, keyvals ...interface{})
+}
+
+// The LoggerProvider interface is implemented by Handlers that provide
+// the Logger of the connections they serve, when the Server has none.
+type LoggerProvider interface {
+	// Logger returns the Logger of the handler, or nil to use the
+	// DefaultLogger.
+	Logger() Logger
+}
+
+// StdLogger is a Logger that writes entries of Level or above to a
+// standard library log.Logger, in logfmt: level=info msg="..." key=value.
+type StdLogger struct {
+	Logger *log.Logger // the standard logger of package log if nil
+	Level  Level       // the minimum level logged
+}
+
+// Enabled implements the Logger interface.
+func (l *StdLogger) Enabled(level Level) bool {
+	return level >= l.Level
+}
+
+// Log implements the Logger interface.
+func (l *StdLogger) Log(level Level, msg string, keyvals ...interface{}) {
+	if !l.Enabled(level) {
+		return
+	}
+	var b bytes.Buffer
+	b.WriteString("level=")
+	b.WriteString(level.String())
+	b.WriteString(" msg=")
+	writeLogValue(&b, msg)
+	for i := 0; i < len(keyvals); i += 2 {
+		b.WriteByte(' ')
+		b.WriteString(fmt.Sprint(keyvals[i]))
+		b.WriteByte('=')
+		if i+1 < len(keyvals) {
+			writeLogValue(&b, fmt.Sprint(keyvals[i+1]))
+		} else {
+			b.WriteString(`""`)
+		}
+	}
+	if l.Logger == nil {
+		log.Print(b.String())
+	} else {
+		l.Logger.Print(b.String())
+	}
+}
+
+// writeLogValue writes s, quoted if it is empty or contains spaces,
+// quotes, equal signs or control characters.
+func writeLogValue(b *bytes.Buffer, s string) {
+	if s == "" || strings.IndexFunc(s, func(r rune) bool {
+		return r <= ' ' || r == '"' || r == '=' || r == 0x7f
+	}) >= 0 {
+		b.WriteString(strconv.Quote(s))
+		return
+	}
+	b.WriteString(s)
+}
+
+// DefaultLogger is the Logger of connections whose Server has none and
+// whose Handler does not provide one. It logs entries of LevelInfo and
+// above with the standard logger of package log.
+var DefaultLogger Logger = &StdLogger{Level: LevelInfo}
+
+// logger returns the Logger of the server's connections.
+func (srv *Server) logger() Logger {
+	if srv.Logger != nil {
+		return srv.Logger
+	}
+	h := srv.Handler
+	if h == nil {
+		h = DefaultServeMux
+	}
+	if p, ok := h.(LoggerProvider); ok {
+		if l := p.Logger(); l != nil {
+			return l
+		}
+	}
+	return DefaultLogger
+}
+
+// LoggerOf returns the Logger of the connection c, for handlers logging
+// about the messages they serve. It is the DefaultLogger for connections
+// that are not served by this package.
+func LoggerOf(c Conn) Logger {
+	if w, ok := c.(*response); ok {
+		return w.conn.server.logger()
+	}
+	return DefaultLogger
+}
+
+// logMessage logs the message m received or sent on c at LevelDebug.
+func (c *conn) logMessage(msg string, m *Message) {
+	l := c.server.logger()
+	if !l.Enabled(LevelDebug) {
+		return
+	}
+	l.Log(LevelDebug, msg,
+		"conn", c.id,
+		"peer", c.peerIdentity(),
+		"remote", c.rwc.RemoteAddr(),
+		"message", m,
+	)
+}
@@ -0,0 +1,111 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam_test
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+)
+
+func TestStdLogger(t *testing.T) {
+	var b bytes.Buffer
+	l := &diam.StdLogger{Logger: log.New(&b, "", 0), Level: diam.LevelInfo}
+	l.Log(diam.LevelDebug, "message received")
+	l.Log(diam.LevelWarn, "accept error", "addr", "127.0.0.1:3868", "err", errors.New("too many files"), "odd")
+	want := `level=warn msg="accept error" addr=127.0.0.1:3868 err="too many files" odd=""` + "\n"
+	if b.String() != want {
+		t.Fatalf("Unexpected log.\nWant %q\nHave %q", want, b.String())
+	}
+}
+
+type logEntry struct {
+	level   diam.Level
+	msg     string
+	keyvals map[interface{}]interface{}
+}
+
+// testLogger records the entries of all levels.
+type testLogger struct {
+	mu      sync.Mutex
+	entries []logEntry
+}
+
+func (l *testLogger) Enabled(level diam.Level) bool { return true }
+
+func (l *testLogger) Log(level diam.Level, msg string, keyvals ...interface{}) {
+	e := logEntry{level: level, msg: msg, keyvals: make(map[interface{}]interface{})}
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		e.keyvals[keyvals[i]] = keyvals[i+1]
+	}
+	l.mu.Lock()
+	l.entries = append(l.entries, e)
+	l.mu.Unlock()
+}
+
+func (l *testLogger) find(msg string) (logEntry, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, e := range l.entries {
+		if e.msg == msg {
+			return e, true
+		}
+	}
+	return logEntry{}, false
+}
+
+func TestServerLogger(t *testing.T) {
+	errc := make(chan error, 1)
+	smux := diam.NewServeMux()
+	smux.Handle("CER", handleCER(errc, false))
+	srv := diamtest.NewUnstartedServer(smux, nil)
+	logger := &testLogger{}
+	srv.Config.Logger = logger
+	srv.Start()
+	defer srv.Close()
+
+	wait := make(chan struct{})
+	cmux := diam.NewServeMux()
+	cmux.HandleIdx(diam.CommandIndex{AppID: 0, Code: diam.CapabilitiesExchange, Request: false}, handleCEA(errc, wait))
+	cli, err := diam.Dial(srv.Addr, cmux, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cli.Close()
+	sendCER(cli)
+	select {
+	case <-wait:
+	case err := <-errc:
+		t.Fatal(err)
+	case <-time.After(time.Second):
+		t.Fatal("Timed out: no CER or CEA received")
+	}
+
+	for _, msg := range []string{"message received", "message sent"} {
+		e, ok := logger.find(msg)
+		if !ok {
+			t.Fatalf("No %q entry logged", msg)
+		}
+		if e.level != diam.LevelDebug {
+			t.Fatalf("Unexpected level of %q. Want debug, have %s", msg, e.level)
+		}
+		if peer, _ := e.keyvals["peer"].(datatype.DiameterIdentity); peer != "cli" {
+			t.Fatalf("Unexpected peer of %q: %v", msg, e.keyvals["peer"])
+		}
+		if id, _ := e.keyvals["conn"].(uint64); id == 0 {
+			t.Fatalf("Unexpected conn of %q: %v", msg, e.keyvals["conn"])
+		}
+		if m, _ := e.keyvals["message"].(*diam.Message); m == nil || m.Header.CommandCode != diam.CapabilitiesExchange {
+			t.Fatalf("Unexpected message of %q: %v", msg, e.keyvals["message"])
+		}
+	}
+}
@@ -11,9 +11,12 @@ import (
 	"io"
 	"math/rand"
 	"net"
+	"strings"
 	"sync"
 	"time"
 
+	"golang.org/x/net/context"
+
 	"github.com/omnicate/go-diameter/v4/diam/avp"
 	"github.com/omnicate/go-diameter/v4/diam/datatype"
 	"github.com/omnicate/go-diameter/v4/diam/dict"
@@ -22,6 +25,16 @@ import (
 // MessageBufferLength is the default buffer length for Diameter messages.
 var MessageBufferLength = 1 << 10
 
+// MaxMessageLength is the maximum length of the messages read by
+// ReadMessage, which returns a DecodeError with ErrBadMessageLength for
+// longer messages. Defaults to the maximum of the 24-bit length field.
+var MaxMessageLength uint32 = 1<<24 - 1
+
+// maxPreallocLength is the maximum length of the message body allocated
+// before it is read. Longer bodies grow as they are read, so that forged
+// message lengths do not allocate memory that is never received.
+const maxPreallocLength = 1 << 16
+
 func init() {
 	rand.Seed(time.Now().UnixNano())
 }
@@ -34,6 +47,40 @@ type Message struct {
 	// dictionary parser object used to encode and decode AVPs.
 	dictionary *dict.Parser
 	stream     uint // the stream this message was received on (if any)
+
+	lazy bool          // read by ReadMessageLazy
+	body *bytes.Buffer // pooled body of lazy messages, see Release
+
+	ctx context.Context // see Context
+}
+
+// Context returns the context of the message. The context of the
+// messages passed to the Handler of a Server is the context of their
+// connection, which is canceled when the connection is closed, with the
+// deadline of Server.HandlerTimeout if set, in which case it is also
+// canceled when the Handler returns. It is context.Background for other
+// messages.
+//
+// Connections closed by their peer are noticed when they are read, which
+// happens while the Handler runs with the WorkerPool and SessionOrdered
+// concurrency models of the Server, but only after it returns with
+// SerialPerConn.
+func (m *Message) Context() context.Context {
+	if m.ctx == nil {
+		return context.Background()
+	}
+	return m.ctx
+}
+
+// WithContext returns a shallow copy of the message with its context
+// changed to ctx, which must not be nil.
+func (m *Message) WithContext(ctx context.Context) *Message {
+	if ctx == nil {
+		panic("nil context")
+	}
+	nm := *m
+	nm.ctx = ctx
+	return &nm
 }
 
 var readerBufferPool sync.Pool
@@ -52,20 +99,16 @@ func putReaderBuffer(b *bytes.Buffer) {
 	}
 }
 
-func readerBufferSlice(buf *bytes.Buffer, l int) []byte {
-	b := buf.Bytes()
-	if l <= MessageBufferLength && cap(b) >= MessageBufferLength {
-		return b[:l]
-	}
-	return make([]byte, l)
-}
-
 // ReadMessage reads a binary stream from the reader and uses the given
 // dictionary to parse it.
 func ReadMessage(reader io.Reader, dictionary *dict.Parser) (*Message, error) {
+	return readMessage(reader, dictionary, false)
+}
+
+func readMessage(reader io.Reader, dictionary *dict.Parser, lazy bool) (*Message, error) {
 	buf := newReaderBuffer()
 	defer putReaderBuffer(buf)
-	m := &Message{dictionary: dictionary}
+	m := &Message{dictionary: dictionary, lazy: lazy}
 	cmd, stream, err := m.readHeader(reader, buf)
 	if err != nil {
 		return nil, err
@@ -113,12 +156,38 @@ func (m *Message) readHeader(r io.Reader, buf *bytes.Buffer) (cmd *dict.Command,
 func (m *Message) readBody(r io.Reader, buf *bytes.Buffer, cmd *dict.Command, stream uint) error {
 	var err error
 	var n int
-	b := readerBufferSlice(buf, int(m.Header.MessageLength-HeaderLength))
+	if m.Header.MessageLength < HeaderLength || m.Header.MessageLength > MaxMessageLength {
+		return decodeErrorf(ErrBadMessageLength, 0, "message length %d", m.Header.MessageLength)
+	}
+	// The body is only pooled for lazy messages, see Release: decoded
+	// AVPs keep references to it.
+	var b []byte
+	length := int(m.Header.MessageLength - HeaderLength)
 	msr, isMulti := r.(MultistreamReader)
-	if isMulti {
+	switch {
+	case isMulti:
+		b = make([]byte, length)
 		n, _, err = msr.ReadAtLeast(b, len(b), stream)
-	} else {
+	case length <= maxPreallocLength:
+		if m.lazy {
+			m.body = newBodyBuffer(length)
+			b = m.body.Bytes()[:length]
+		} else {
+			b = make([]byte, length)
+		}
 		n, err = io.ReadFull(r, b)
+	default:
+		var body bytes.Buffer
+		body.Grow(maxPreallocLength)
+		var nn int64
+		nn, err = io.CopyN(&body, r, int64(length))
+		b, n = body.Bytes(), int(nn)
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}
+	if err == io.ErrUnexpectedEOF {
+		return decodeErrorf(ErrTruncated, 0, "message body: %d of %d bytes read", n, length)
 	}
 	if err != nil {
 		return fmt.Errorf("readBody Error: %v, %d bytes read", err, n)
@@ -146,11 +215,26 @@ func (m *Message) maxAVPsFor(cmd *dict.Command) int {
 }
 
 func (m *Message) decodeAVPs(b []byte) error {
-	var a *AVP
-	var err error
+	// The AVPs are allocated in blocks, rather than one by one.
+	block := make([]AVP, cap(m.AVP))
 	for n := 0; n < len(b); {
-		a, err = DecodeAVP(b[n:], m.Header.ApplicationID, m.Dictionary())
+		if len(block) == 0 {
+			block = make([]AVP, cap(m.AVP))
+		}
+		a := &block[0]
+		block = block[1:]
+		payload, err := a.decodeHeader(b[n:])
+		if err == nil {
+			if m.lazy {
+				err = m.decodeLazily(a, payload)
+			} else {
+				err = a.decodeData(payload, m.Header.ApplicationID, m.Dictionary())
+			}
+		}
 		if err != nil {
+			if _, ok := err.(*DecodeError); ok {
+				return err
+			}
 			return fmt.Errorf("Failed to decode AVP: %s", err)
 		}
 		m.AVP = append(m.AVP, a)
@@ -276,6 +360,11 @@ func (m *Message) WriteToStream(writer io.Writer, stream uint) (n int, err error
 // if needed
 // If writer implements MultistreamWriter, writes the message into specified stream
 func (m *Message) WriteToStreamWithRetry(writer io.Writer, stream, retries uint) (n int, err error) {
+	if w, ok := writer.(messagePreparer); ok {
+		if err := w.prepareMessage(m); err != nil {
+			return 0, err
+		}
+	}
 	l := m.Len()
 	buf := newWriterBuffer(l)
 	defer putWriterBuffer(buf)
@@ -283,6 +372,9 @@ func (m *Message) WriteToStreamWithRetry(writer io.Writer, stream, retries uint)
 	if err := m.SerializeTo(b); err != nil {
 		return 0, err
 	}
+	if w, ok := writer.(messageSender); ok {
+		w.messageSent(m)
+	}
 	switch w := writer.(type) {
 	case MultistreamWriter:
 		return writeStreamRetry(w, b, stream, retries)
@@ -429,6 +521,9 @@ func (m *Message) FindAVPs(code interface{}, vendorID uint32) ([]*AVP, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err = m.decodeLazyCode(dictAVP.Code); err != nil {
+		return nil, err
+	}
 
 	return findFromAVP(m.AVP, dictAVP.Code, true)
 }
@@ -448,6 +543,9 @@ func (m *Message) FindAVP(code interface{}, vendorID uint32) (*AVP, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err = m.decodeLazyCode(dictAVP.Code); err != nil {
+		return nil, err
+	}
 
 	result, err := findFromAVP(m.AVP, dictAVP.Code, false)
 
@@ -476,9 +574,75 @@ func (m *Message) FindAVPsWithPath(path []interface{}, vendorID uint32) ([]*AVP,
 		}
 		pathCodes[i] = dictAVP.Code
 	}
+	err := m.decodeLazyAVPs(func(a *AVP) bool { return a.Code == pathCodes[0] })
+	if err != nil {
+		return nil, err
+	}
 	return avpsWithPath(m.AVP, pathCodes), nil
 }
 
+// FindPath returns the first AVP of the Message on the path of AVP names
+// separated by slashes, such as
+// "Multiple-Services-Credit-Control/Granted-Service-Unit/CC-Total-Octets".
+// See FindAllPath.
+func (m *Message) FindPath(path string) (*AVP, error) {
+	avps, err := m.FindAllPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(avps) == 0 {
+		return nil, errors.New("AVP not found")
+	}
+	return avps[0], nil
+}
+
+// FindAllPath returns all the AVPs of the Message on the path of AVP
+// names separated by slashes, descending into grouped AVPs.
+//
+// The names are resolved through the dictionary of the Message, in its
+// application, and the AVPs on the path must have the Vendor-Id of their
+// dictionary definition, so vendor AVPs may be nested in base AVPs.
+//
+// Example:
+//
+//	avps, err := m.FindAllPath("Multiple-Services-Credit-Control/Rating-Group")
+//
+func (m *Message) FindAllPath(path string) ([]*AVP, error) {
+	names := strings.Split(path, "/")
+	dictAVPs := make([]*dict.AVP, len(names))
+	for i, name := range names {
+		dictAVP, err := m.Dictionary().FindAVP(m.Header.ApplicationID, name)
+		if err != nil {
+			return nil, err
+		}
+		dictAVPs[i] = dictAVP
+	}
+	err := m.decodeLazyAVPs(func(a *AVP) bool {
+		return a.Code == dictAVPs[0].Code && a.VendorID == dictAVPs[0].VendorID
+	})
+	if err != nil {
+		return nil, err
+	}
+	return avpsWithDictPath(m.AVP, dictAVPs), nil
+}
+
+func avpsWithDictPath(avps []*AVP, path []*dict.AVP) []*AVP {
+	var found []*AVP
+	for _, a := range avps {
+		if a.Code != path[0].Code || a.VendorID != path[0].VendorID {
+			continue
+		}
+		if len(path) == 1 {
+			found = append(found, a)
+			continue
+		}
+		if g, ok := a.Data.(*GroupedAVP); ok {
+			found = append(found, avpsWithDictPath(g.AVP, path[1:])...)
+		}
+	}
+	return found
+}
+
 // Answer creates an answer for the current Message
 // with optinal ResultCode AVP
 func (m *Message) Answer(resultCode uint32) *Message {
@@ -493,12 +657,7 @@ func (m *Message) Answer(resultCode uint32) *Message {
 // the Experimental-Result grouped AVP instead of the Result-Code AVP.
 func (m *Message) ExperimentalAnswer(resultCode, vendorID uint32, avps ...*AVP) *Message {
 	nm := m.answer()
-	nm.NewAVP(avp.ExperimentalResult, avp.Mbit, 0, &GroupedAVP{
-		AVP: []*AVP{
-			NewAVP(avp.VendorID, avp.Mbit, 0, datatype.Unsigned32(vendorID)),
-			NewAVP(avp.ExperimentalResultCode, avp.Mbit, 0, datatype.Unsigned32(resultCode)),
-		},
-	})
+	nm.AddAVP(NewExperimentalResult(vendorID, resultCode))
 	for _, avp := range avps {
 		nm.AddAVP(avp)
 	}
@@ -519,6 +678,7 @@ func (m *Message) answer() *Message {
 }
 
 func (m *Message) String() string {
+	m.Decode()
 	var b bytes.Buffer
 	var typ string
 	if m.Header.CommandFlags&RequestFlag == RequestFlag {
@@ -550,7 +710,7 @@ func (m *Message) String() string {
 		} else if a.Data.Type() == GroupedAVPType {
 			fmt.Fprintf(&b, "\t%s %s\n", dictAVP.Name, printGrouped("\t", m, a, 1))
 		} else {
-			fmt.Fprintf(&b, "\t%s %s\n", dictAVP.Name, a)
+			fmt.Fprintf(&b, "\t%s %s\n", dictAVP.Name, avpString(dictAVP, a))
 		}
 	}
 	return b.String()
@@ -581,7 +741,7 @@ func printGrouped(prefix string, m *Message, a *AVP, indent int) string {
 				tabs := indentTabs(indent)
 				fmt.Fprintf(&b, "%s%s %s\n", tabs, dictAVP.Name, printGrouped(tabs, m, ga, indent))
 			} else {
-				fmt.Fprintf(&b, "%s\t%s %s,\n", prefix, dictAVP.Name, ga)
+				fmt.Fprintf(&b, "%s\t%s %s,\n", prefix, dictAVP.Name, avpString(dictAVP, ga))
 			}
 		}
 	}
@@ -589,6 +749,28 @@ func printGrouped(prefix string, m *Message, a *AVP, indent int) string {
 	return b.String()
 }
 
+// avpString returns the text form of a, with the values of Enumerated
+// AVPs followed by the name of their item in the dictionary, such as
+// UPDATE_REQUEST (2).
+func avpString(dictAVP *dict.AVP, a *AVP) string {
+	n, ok := a.Data.(datatype.Enumerated)
+	if !ok {
+		return a.String()
+	}
+	name := dictAVP.EnumName(int32(n))
+	if name == "" {
+		return a.String()
+	}
+	return fmt.Sprintf("{Code:%d,Flags:0x%x,Length:%d,VendorId:%d,Value:%s (%d)}",
+		a.Code,
+		a.Flags,
+		a.Len(),
+		a.VendorID,
+		name,
+		n,
+	)
+}
+
 func indentTabs(n int) string {
 	var s string
 	for i := 0; i < n; i++ {
@@ -9,6 +9,7 @@ import (
 	"encoding/hex"
 	"io/ioutil"
 	"net"
+	"strings"
 	"testing"
 
 	"github.com/omnicate/go-diameter/v4/diam/avp"
@@ -187,6 +188,28 @@ func TestNewMessage(t *testing.T) {
 	t.Logf("Message:\n%s", hex.Dump(a))
 }
 
+func TestMessageStringEnum(t *testing.T) {
+	m := NewRequest(CreditControl, 4, dict.Default)
+	m.NewAVP(avp.CCRequestType, avp.Mbit, 0, datatype.Enumerated(2))
+	m.NewAVP(avp.SubscriptionID, avp.Mbit, 0, &GroupedAVP{
+		AVP: []*AVP{
+			NewAVP(avp.SubscriptionIDType, avp.Mbit, 0, datatype.Enumerated(0)),
+			NewAVP(avp.SubscriptionIDData, avp.Mbit, 0, datatype.UTF8String("5511")),
+		},
+	})
+	m.NewAVP(avp.CCRequestType, avp.Mbit, 0, datatype.Enumerated(99))
+	s := m.String()
+	for _, want := range []string{
+		"CC-Request-Type {Code:416,Flags:0x40,Length:12,VendorId:0,Value:UPDATE_REQUEST (2)}",
+		"Subscription-Id-Type {Code:450,Flags:0x40,Length:12,VendorId:0,Value:END_USER_E164 (0)}",
+		"CC-Request-Type {Code:416,Flags:0x40,Length:12,VendorId:0,Value:Enumerated{99}}",
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("%s is missing:\n%s", want, s)
+		}
+	}
+}
+
 func TestMessageFindAVP(t *testing.T) {
 	m, _ := ReadMessage(bytes.NewReader(testMessage), dict.Default)
 	a, err := m.FindAVP(avp.OriginStateID, 0)
@@ -234,6 +257,46 @@ func TestMessageFindAVPsWithPath(t *testing.T) {
 	}
 }
 
+func TestMessageFindPath(t *testing.T) {
+	m := NewRequest(CreditControl, 4, dict.Default)
+	for i := 1; i <= 2; i++ {
+		m.NewAVP(avp.MultipleServicesCreditControl, avp.Mbit, 0, &GroupedAVP{
+			AVP: []*AVP{
+				NewAVP(avp.RatingGroup, avp.Mbit, 0, datatype.Unsigned32(i)),
+				NewAVP(avp.GrantedServiceUnit, avp.Mbit, 0, &GroupedAVP{
+					AVP: []*AVP{
+						NewAVP(avp.CCTotalOctets, avp.Mbit, 0, datatype.Unsigned64(i*1000)),
+					},
+				}),
+			},
+		})
+	}
+	a, err := m.FindPath("Multiple-Services-Credit-Control/Granted-Service-Unit/CC-Total-Octets")
+	if err != nil || a.Data != datatype.Unsigned64(1000) {
+		t.Fatalf("Unexpected AVP: %v, error: %v", a, err)
+	}
+	avps, err := m.FindAllPath("Multiple-Services-Credit-Control/Rating-Group")
+	if err != nil || len(avps) != 2 || avps[1].Data != datatype.Unsigned32(2) {
+		t.Fatalf("Unexpected AVPs: %v, error: %v", avps, err)
+	}
+	if a, err = m.FindPath("Multiple-Services-Credit-Control/Used-Service-Unit"); err == nil {
+		t.Fatalf("Unexpected AVP: %v", a)
+	}
+	if _, err = m.FindAllPath("Multiple-Services-Credit-Control/No-Such-AVP"); err == nil {
+		t.Fatal("Unknown AVP name was resolved")
+	}
+
+	m = NewRequest(CreditControl, 16777238, dict.Default)
+	m.NewAVP(avp.ChargingRuleInstall, avp.Mbit, 10415, &GroupedAVP{
+		AVP: []*AVP{
+			NewAVP(avp.ChargingRuleName, avp.Mbit, 10415, datatype.OctetString("rule")),
+		},
+	})
+	if a, err = m.FindPath("Charging-Rule-Install/Charging-Rule-Name"); err != nil || a.Data != datatype.OctetString("rule") {
+		t.Fatalf("Unexpected vendor AVP: %v, error: %v", a, err)
+	}
+}
+
 func TestMessageWriteTo(t *testing.T) {
 	var mydictXML = `<?xml version="1.0" encoding="UTF-8"?>
 <diameter>
@@ -288,3 +351,50 @@ func BenchmarkWriteMessage(b *testing.B) {
 		m.WriteTo(ioutil.Discard)
 	}
 }
+
+func TestReadMessageDecodeErrors(t *testing.T) {
+	header := func(length uint32) []byte {
+		h := &Header{Version: 1, MessageLength: length, CommandFlags: RequestFlag, CommandCode: CapabilitiesExchange}
+		return h.Serialize()
+	}
+	if _, err := DecodeHeader(header(100)[:10]); err.(*DecodeError).Err != ErrTruncated {
+		t.Fatalf("Unexpected error decoding short header: %v", err)
+	}
+	avpHeader := func(flags uint8, length uint32) []byte {
+		return append([]byte{0, 0, 1, 8, flags}, uint32to24(length)...)
+	}
+	for _, tc := range []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"short message length", header(12), ErrBadMessageLength},
+		{"truncated body", append(header(64), avpHeader(avp.Mbit, 12)...), ErrTruncated},
+		{"short vendor AVP", append(header(28), avpHeader(avp.Vbit|avp.Mbit, 8)...), ErrBadAVPLength},
+		{"long AVP", append(append(header(32), avpHeader(avp.Mbit, 200)...), "host"...), ErrBadAVPLength},
+		{"missing padding", append(append(header(31), avpHeader(avp.Mbit, 11)...), "srv"...), ErrBadPadding},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ReadMessage(bytes.NewReader(tc.data), dict.Default)
+			de, ok := err.(*DecodeError)
+			if !ok || de.Err != tc.want {
+				t.Fatalf("Unexpected error. Want %v, have %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestReadMessageLengthError(t *testing.T) {
+	h := &Header{Version: 1, MessageLength: 32, CommandFlags: RequestFlag, CommandCode: CapabilitiesExchange}
+	// Origin-State-Id, an Unsigned32, of 3 bytes.
+	data := append(h.Serialize(), 0, 0, 1, 22, avp.Mbit, 0, 0, 11, 1, 2, 3, 0)
+	_, err := ReadMessage(bytes.NewReader(data), dict.Default)
+	de, ok := err.(*DecodeError)
+	if !ok || de.Code != avp.OriginStateID {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	le, ok := de.Err.(*datatype.LengthError)
+	if !ok || le.Type != datatype.Unsigned32Type || le.Len != 3 || le.Want != 4 {
+		t.Fatalf("Unexpected error: %#v", de.Err)
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package metrics collects metrics of Diameter connections and messages
+// for Prometheus.
+//
+// A Collector set in sm.Settings is reported the connections and
+// messages of the state machine, its watchdog failures and reconnecting
+// peers, and serves them in the Prometheus text exposition format
+// without depending on the Prometheus client library:
+//
+//	mc := metrics.NewCollector()
+//	settings := &sm.Settings{
+//		OriginHost:  "client",
+//		OriginRealm: "go-diameter",
+//		Metrics:     mc,
+//	}
+//	http.Handle("/metrics", mc)
+//
+// The metrics are:
+//
+//	diameter_messages_received_total{application,command,result_code}
+//	diameter_messages_sent_total{application,command,result_code}
+//	diameter_requests_dropped_total{application,command}
+//	diameter_requests_deferred_total{application,command}
+//	diameter_request_duration_seconds{application,command,direction}
+//	diameter_connections_active
+//	diameter_watchdog_failures_total
+//	diameter_reconnects_total
+//
+// Commands are labeled with their short name and R or A, such as CCR,
+// and answers with their Result-Code or Experimental-Result-Code. The
+// direction of request durations is outgoing for requests sent until
+// their answer is received, and incoming for requests received until
+// their answer is sent. Requests are dropped or deferred by the
+// admission control of the state machine, see sm.Settings.RateLimiter.
+package metrics
@@ -0,0 +1,386 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// DefaultBuckets are the default upper bounds of the buckets of request
+// duration histograms, in seconds.
+var DefaultBuckets = []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Limits of the requests tracked for measuring their duration.
+const (
+	maxPending = 1 << 16
+	maxAge     = time.Minute
+)
+
+// Directions of requests in duration histograms.
+const (
+	outgoing = "outgoing" // sent, until their answer is received
+	incoming = "incoming" // received, until their answer is sent
+)
+
+// messageKey are the labels of message counters.
+type messageKey struct {
+	app, cmd, result string
+}
+
+// durationKey are the labels of request duration histograms.
+type durationKey struct {
+	app, cmd, direction string
+}
+
+// pendingKey identifies a request waiting for its answer.
+type pendingKey struct {
+	conn     diam.Conn
+	hbh      uint32
+	incoming bool
+}
+
+type pendingRequest struct {
+	start time.Time
+	key   durationKey
+}
+
+type histogram struct {
+	counts []uint64 // per bucket, not cumulative
+	sum    float64
+	count  uint64
+}
+
+// Collector collects metrics of Diameter connections and messages, and
+// serves them in the Prometheus text exposition format.
+//
+// It implements the diam.Observer interface, and is usually set in
+// sm.Settings so the state machine reports to it. Collectors may be
+// shared by several state machines.
+type Collector struct {
+	// Buckets are the upper bounds of the buckets of the request
+	// duration histograms, in seconds. Defaults to DefaultBuckets. It
+	// must not be changed after the Collector is used.
+	Buckets []float64
+
+	mu               sync.Mutex
+	received         map[messageKey]uint64
+	sent             map[messageKey]uint64
+	dropped          map[messageKey]uint64
+	deferred         map[messageKey]uint64
+	durations        map[durationKey]*histogram
+	pending          map[pendingKey]pendingRequest
+	conns            int64
+	watchdogFailures uint64
+	reconnects       uint64
+	peers            map[string]bool
+}
+
+// NewCollector creates and initializes a Collector.
+func NewCollector() *Collector {
+	return &Collector{
+		received:  make(map[messageKey]uint64),
+		sent:      make(map[messageKey]uint64),
+		dropped:   make(map[messageKey]uint64),
+		deferred:  make(map[messageKey]uint64),
+		durations: make(map[durationKey]*histogram),
+		pending:   make(map[pendingKey]pendingRequest),
+		peers:     make(map[string]bool),
+	}
+}
+
+func (c *Collector) buckets() []float64 {
+	if c.Buckets == nil {
+		return DefaultBuckets
+	}
+	return c.Buckets
+}
+
+// ConnOpened implements the diam.Observer interface.
+func (c *Collector) ConnOpened(conn diam.Conn) {
+	c.mu.Lock()
+	c.conns++
+	c.mu.Unlock()
+}
+
+// ConnClosed implements the diam.Observer interface.
+func (c *Collector) ConnClosed(conn diam.Conn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conns--
+	for k := range c.pending {
+		if k.conn == conn {
+			delete(c.pending, k)
+		}
+	}
+}
+
+// MessageReceived implements the diam.Observer interface.
+func (c *Collector) MessageReceived(conn diam.Conn, m *diam.Message) {
+	k := newMessageKey(m)
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.received[k]++
+	if m.Header.CommandFlags&diam.RequestFlag != 0 {
+		c.track(pendingKey{conn, m.Header.HopByHopID, true}, durationKey{k.app, k.cmd, incoming}, now)
+	} else {
+		c.observe(pendingKey{conn, m.Header.HopByHopID, false}, now)
+	}
+}
+
+// MessageSent implements the diam.Observer interface.
+func (c *Collector) MessageSent(conn diam.Conn, m *diam.Message) {
+	k := newMessageKey(m)
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sent[k]++
+	if m.Header.CommandFlags&diam.RequestFlag != 0 {
+		c.track(pendingKey{conn, m.Header.HopByHopID, false}, durationKey{k.app, k.cmd, outgoing}, now)
+	} else {
+		c.observe(pendingKey{conn, m.Header.HopByHopID, true}, now)
+	}
+}
+
+// RequestDropped records a request rejected with DIAMETER_TOO_BUSY by
+// admission control instead of being handled.
+func (c *Collector) RequestDropped(conn diam.Conn, m *diam.Message) {
+	k := newMessageKey(m)
+	c.mu.Lock()
+	c.dropped[k]++
+	c.mu.Unlock()
+}
+
+// RequestDeferred records a request whose handling was delayed by
+// admission control.
+func (c *Collector) RequestDeferred(conn diam.Conn, m *diam.Message) {
+	k := newMessageKey(m)
+	c.mu.Lock()
+	c.deferred[k]++
+	c.mu.Unlock()
+}
+
+// WatchdogFailure records a connection closed because its peer did not
+// answer watchdog requests.
+func (c *Collector) WatchdogFailure(conn diam.Conn) {
+	c.mu.Lock()
+	c.watchdogFailures++
+	c.mu.Unlock()
+}
+
+// PeerConnected records a peer passing the capabilities exchange. It is
+// counted as a reconnect if the peer had connected before.
+func (c *Collector) PeerConnected(host datatype.DiameterIdentity) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.peers[string(host)] {
+		c.reconnects++
+	}
+	c.peers[string(host)] = true
+}
+
+// track starts measuring the duration of a request. Requests older than
+// maxAge are dropped when too many are pending.
+func (c *Collector) track(k pendingKey, dk durationKey, now time.Time) {
+	if len(c.pending) >= maxPending {
+		for pk, req := range c.pending {
+			if now.Sub(req.start) > maxAge {
+				delete(c.pending, pk)
+			}
+		}
+		if len(c.pending) >= maxPending {
+			return
+		}
+	}
+	c.pending[k] = pendingRequest{start: now, key: dk}
+}
+
+// observe records the duration of the request of an answer.
+func (c *Collector) observe(k pendingKey, now time.Time) {
+	req, ok := c.pending[k]
+	if !ok {
+		return
+	}
+	delete(c.pending, k)
+	h := c.durations[req.key]
+	if h == nil {
+		h = &histogram{counts: make([]uint64, len(c.buckets()))}
+		c.durations[req.key] = h
+	}
+	d := now.Sub(req.start).Seconds()
+	for i, le := range c.buckets() {
+		if d <= le {
+			h.counts[i]++
+			break
+		}
+	}
+	h.sum += d
+	h.count++
+}
+
+func newMessageKey(m *diam.Message) messageKey {
+	k := messageKey{app: strconv.FormatUint(uint64(m.Header.ApplicationID), 10)}
+	req := m.Header.CommandFlags&diam.RequestFlag != 0
+	suffix := "A"
+	if req {
+		suffix = "R"
+	}
+	if cmd, err := m.Dictionary().FindCommand(m.Header.ApplicationID, m.Header.CommandCode); err == nil {
+		k.cmd = cmd.Short + suffix
+	} else {
+		k.cmd = strconv.FormatUint(uint64(m.Header.CommandCode), 10) + suffix
+	}
+	if !req {
+		code, _ := diam.ResultCode(m)
+		k.result = strconv.FormatUint(uint64(code), 10)
+	}
+	return k
+}
+
+// ServeHTTP serves the metrics in the Prometheus text exposition format.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.WriteTo(w)
+}
+
+// WriteTo writes the metrics to w in the Prometheus text exposition
+// format.
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	cw := &countWriter{w: bufio.NewWriter(w)}
+	c.mu.Lock()
+	c.writeMessages(cw, "diameter_messages_received_total", "Diameter messages received.", c.received)
+	c.writeMessages(cw, "diameter_messages_sent_total", "Diameter messages sent.", c.sent)
+	c.writeMessages(cw, "diameter_requests_dropped_total", "Diameter requests rejected by admission control.", c.dropped)
+	c.writeMessages(cw, "diameter_requests_deferred_total", "Diameter requests delayed by admission control.", c.deferred)
+	c.writeDurations(cw)
+	cw.metric("diameter_connections_active", "gauge", "Open Diameter connections.")
+	cw.sample("diameter_connections_active", nil, strconv.FormatInt(c.conns, 10))
+	cw.metric("diameter_watchdog_failures_total", "counter", "Connections closed by watchdog failures.")
+	cw.sample("diameter_watchdog_failures_total", nil, strconv.FormatUint(c.watchdogFailures, 10))
+	cw.metric("diameter_reconnects_total", "counter", "Capabilities exchanges with peers that connected before.")
+	cw.sample("diameter_reconnects_total", nil, strconv.FormatUint(c.reconnects, 10))
+	c.mu.Unlock()
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+	return cw.n, cw.err
+}
+
+func (c *Collector) writeMessages(w *countWriter, name, help string, counts map[messageKey]uint64) {
+	keys := make([]messageKey, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.app != b.app {
+			return a.app < b.app
+		}
+		if a.cmd != b.cmd {
+			return a.cmd < b.cmd
+		}
+		return a.result < b.result
+	})
+	w.metric(name, "counter", help)
+	for _, k := range keys {
+		labels := []string{"application", k.app, "command", k.cmd}
+		if k.result != "" {
+			labels = append(labels, "result_code", k.result)
+		}
+		w.sample(name, labels, strconv.FormatUint(counts[k], 10))
+	}
+}
+
+func (c *Collector) writeDurations(w *countWriter) {
+	const name = "diameter_request_duration_seconds"
+	keys := make([]durationKey, 0, len(c.durations))
+	for k := range c.durations {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.app != b.app {
+			return a.app < b.app
+		}
+		if a.cmd != b.cmd {
+			return a.cmd < b.cmd
+		}
+		return a.direction < b.direction
+	})
+	w.metric(name, "histogram", "Time between Diameter requests and their answers.")
+	for _, k := range keys {
+		h := c.durations[k]
+		labels := []string{"application", k.app, "command", k.cmd, "direction", k.direction}
+		var n uint64
+		for i, le := range c.buckets() {
+			n += h.counts[i]
+			w.sample(name+"_bucket", append(labels, "le", formatFloat(le)), strconv.FormatUint(n, 10))
+		}
+		w.sample(name+"_bucket", append(labels, "le", "+Inf"), strconv.FormatUint(h.count, 10))
+		w.sample(name+"_sum", labels, formatFloat(h.sum))
+		w.sample(name+"_count", labels, strconv.FormatUint(h.count, 10))
+	}
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// countWriter writes the lines of the exposition format, keeping the
+// first error and the number of bytes written.
+type countWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (w *countWriter) write(s string) {
+	if w.err != nil {
+		return
+	}
+	n, err := w.w.WriteString(s)
+	w.n += int64(n)
+	w.err = err
+}
+
+func (w *countWriter) metric(name, typ, help string) {
+	w.write("# HELP " + name + " " + help + "\n# TYPE " + name + " " + typ + "\n")
+}
+
+// sample writes a sample with labels given as name and value pairs.
+func (w *countWriter) sample(name string, labels []string, value string) {
+	var b strings.Builder
+	b.WriteString(name)
+	for i := 0; i+1 < len(labels); i += 2 {
+		if i == 0 {
+			b.WriteByte('{')
+		} else {
+			b.WriteByte(',')
+		}
+		b.WriteString(labels[i])
+		b.WriteString(`="`)
+		b.WriteString(labelEscaper.Replace(labels[i+1]))
+		b.WriteByte('"')
+	}
+	if len(labels) > 0 {
+		b.WriteByte('}')
+	}
+	b.WriteByte(' ')
+	b.WriteString(value)
+	b.WriteByte('\n')
+	w.write(b.String())
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
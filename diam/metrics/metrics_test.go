@@ -0,0 +1,139 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package metrics_test
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+	"github.com/omnicate/go-diameter/v4/diam/metrics"
+	"github.com/omnicate/go-diameter/v4/diam/sm"
+)
+
+func newACR() *diam.Message {
+	m := diam.NewRequest(diam.Accounting, 3, dict.Default)
+	m.NewAVP(avp.SessionID, avp.Mbit, 0, datatype.UTF8String("sess"))
+	m.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("cli"))
+	m.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity("cli.test"))
+	m.NewAVP(avp.DestinationRealm, avp.Mbit, 0, datatype.DiameterIdentity("srv.test"))
+	m.NewAVP(avp.AccountingRecordType, avp.Mbit, 0, datatype.Enumerated(1))
+	m.NewAVP(avp.AccountingRecordNumber, avp.Mbit, 0, datatype.Unsigned32(0))
+	return m
+}
+
+func TestCollector(t *testing.T) {
+	srvMetrics := metrics.NewCollector()
+	srvSM := sm.New(&sm.Settings{
+		OriginHost:  "srv",
+		OriginRealm: "srv.test",
+		VendorID:    13,
+		ProductName: "go-diameter",
+		Metrics:     srvMetrics,
+	})
+	srvSM.HandleFunc("ACR", func(c diam.Conn, m *diam.Message) {
+		a := m.Answer(diam.Success)
+		a.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("srv"))
+		a.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity("srv.test"))
+		a.WriteTo(c)
+	})
+	srv := diamtest.NewServer(srvSM, dict.Default)
+	defer srv.Close()
+
+	cliMetrics := metrics.NewCollector()
+	cliSM := sm.New(&sm.Settings{
+		OriginHost:  "cli",
+		OriginRealm: "cli.test",
+		VendorID:    13,
+		ProductName: "go-diameter",
+		Metrics:     cliMetrics,
+	})
+	acctApp := []*diam.AVP{diam.NewAVP(avp.AcctApplicationID, avp.Mbit, 0, datatype.Unsigned32(3))}
+	cli := &sm.Client{Handler: cliSM, AcctApplicationID: acctApp}
+	for i := 0; i < 2; i++ {
+		c, err := cli.Dial(srv.Addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		_, err = diam.SendRequest(ctx, c, newACR())
+		cancel()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			c.Close()
+		} else {
+			defer c.Close()
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	cliMetrics.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Fatalf("Unexpected Content-Type %q", ct)
+	}
+	checkMetrics(t, rec.Body.String(),
+		`diameter_messages_sent_total{application="0",command="CER"} 2`,
+		`diameter_messages_sent_total{application="3",command="ACR"} 2`,
+		`diameter_messages_received_total{application="3",command="ACA",result_code="2001"} 2`,
+		`diameter_request_duration_seconds_count{application="3",command="ACR",direction="outgoing"} 2`,
+		`diameter_request_duration_seconds_bucket{application="3",command="ACR",direction="outgoing",le="+Inf"} 2`,
+		`diameter_reconnects_total 1`,
+		`diameter_watchdog_failures_total 0`,
+	)
+
+	// Wait for the server to see the first connection closed.
+	var b bytes.Buffer
+	for i := 0; i < 100; i++ {
+		b.Reset()
+		srvMetrics.WriteTo(&b)
+		if strings.Contains(b.String(), "diameter_connections_active 1\n") {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	checkMetrics(t, b.String(),
+		`diameter_messages_received_total{application="3",command="ACR"} 2`,
+		`diameter_messages_sent_total{application="0",command="CEA",result_code="2001"} 2`,
+		`diameter_request_duration_seconds_count{application="3",command="ACR",direction="incoming"} 2`,
+		`diameter_connections_active 1`,
+		`# TYPE diameter_request_duration_seconds histogram`,
+	)
+}
+
+func checkMetrics(t *testing.T, out string, want ...string) {
+	t.Helper()
+	for _, w := range want {
+		if !strings.Contains(out, w+"\n") {
+			t.Fatalf("Metrics are missing %s:\n%s", w, out)
+		}
+	}
+}
+
+func TestCollectorBuckets(t *testing.T) {
+	mc := metrics.NewCollector()
+	mc.Buckets = []float64{0.5}
+	m := newACR()
+	mc.MessageSent(nil, m)
+	mc.MessageReceived(nil, m.Answer(diam.UnableToComply))
+	var b bytes.Buffer
+	if _, err := mc.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	checkMetrics(t, b.String(),
+		`diameter_messages_received_total{application="3",command="ACA",result_code="5012"} 1`,
+		`diameter_request_duration_seconds_bucket{application="3",command="ACR",direction="outgoing",le="0.5"} 1`,
+	)
+}
@@ -0,0 +1,90 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+import (
+	"runtime"
+
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// Middleware wraps a Handler with cross-cutting behavior, such as
+// logging, metrics, authorization or validation, calling next to
+// continue, or not calling it to stop the message, for example:
+//
+//	mux.Use(func(next diam.Handler) diam.Handler {
+//		return diam.HandlerFunc(func(c diam.Conn, m *diam.Message) {
+//			start := time.Now()
+//			next.ServeDIAM(c, m)
+//			log.Printf("%d handled in %s", m.Header.CommandCode, time.Since(start))
+//		})
+//	})
+type Middleware func(next Handler) Handler
+
+// Chain returns h wrapped by the middlewares mws, the first being the
+// outermost, which runs first.
+func Chain(h Handler, mws ...Middleware) Handler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
+
+// Use appends the middlewares mws to the chain that wraps the handlers of
+// the mux, registered before or after the call, in the order they are
+// added: the first runs first. Unhandled messages are reported to the
+// ErrorReports channel without running the middlewares.
+func (mux *ServeMux) Use(mws ...Middleware) {
+	mux.mu.Lock()
+	defer mux.mu.Unlock()
+	mux.mws = append(mux.mws, mws...)
+	for k, e := range mux.m {
+		e.h = Chain(e.raw, mux.mws...)
+		mux.m[k] = e
+	}
+	for k, e := range mux.idxMap {
+		e.h = Chain(e.raw, mux.mws...)
+		mux.idxMap[k] = e
+	}
+	for k, e := range mux.apps {
+		e.h = Chain(e.raw, mux.mws...)
+		mux.apps[k] = e
+	}
+}
+
+// Recover returns a Middleware that recovers the panics of the handlers
+// it wraps, instead of crashing the process: it logs them to logger, or
+// DefaultLogger if nil, with the stack and the message, and answers the
+// requests with DIAMETER_UNABLE_TO_COMPLY from originHost and
+// originRealm, keeping the connection open.
+func Recover(originHost, originRealm datatype.DiameterIdentity, logger Logger) Middleware {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(c Conn, m *Message) {
+			defer func() {
+				err := recover()
+				if err == nil {
+					return
+				}
+				l := logger
+				if l == nil {
+					l = DefaultLogger
+				}
+				if l.Enabled(LevelError) {
+					buf := make([]byte, 4096)
+					buf = buf[:runtime.Stack(buf, false)]
+					l.Log(LevelError, "panic handling message",
+						"err", err,
+						"stack", string(buf),
+						"message", m.String(),
+					)
+				}
+				if m.Header.CommandFlags&RequestFlag != 0 && c != nil {
+					m.AnswerFrom(UnableToComply, originHost, originRealm).WriteTo(c)
+				}
+			}()
+			next.ServeDIAM(c, m)
+		})
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+import (
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+func TestServeMux_Use(t *testing.T) {
+	var calls []string
+	record := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return HandlerFunc(func(c Conn, m *Message) {
+				calls = append(calls, name)
+				next.ServeDIAM(c, m)
+			})
+		}
+	}
+	mux := NewServeMux()
+	mux.Use(record("first"))
+	mux.HandleFunc("CER", func(c Conn, m *Message) { calls = append(calls, "CER") })
+	mux.Use(record("second"))
+	mux.HandleIdx(CommandIndex{Code: DeviceWatchdog, Request: true}, HandlerFunc(func(c Conn, m *Message) {
+		calls = append(calls, "DWR")
+	}))
+
+	mux.ServeDIAM(nil, NewRequest(CapabilitiesExchange, 0, dict.Default))
+	mux.ServeDIAM(nil, NewRequest(DeviceWatchdog, 0, dict.Default))
+	want := []string{"first", "second", "CER", "first", "second", "DWR"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Fatalf("Unexpected calls. Want %v, have %v", want, calls)
+	}
+}
+
+func TestServeMux_UseStop(t *testing.T) {
+	mux := NewServeMux()
+	mux.Use(func(next Handler) Handler {
+		return HandlerFunc(func(c Conn, m *Message) {
+			if m.Header.ApplicationID != 0 {
+				return
+			}
+			next.ServeDIAM(c, m)
+		})
+	})
+	var handled int
+	mux.HandleFunc("ALL", func(c Conn, m *Message) { handled++ })
+	mux.ServeDIAM(nil, NewRequest(CapabilitiesExchange, 0, dict.Default))
+	mux.ServeDIAM(nil, NewRequest(CreditControl, 4, dict.Default))
+	if handled != 1 {
+		t.Fatalf("Unexpected handled messages. Want 1, have %d", handled)
+	}
+}
+
+// recordLogger records the messages logged.
+type recordLogger struct {
+	mu   sync.Mutex
+	msgs []string
+}
+
+func (l *recordLogger) Enabled(level Level) bool { return true }
+
+func (l *recordLogger) Log(level Level, msg string, keyvals ...interface{}) {
+	l.mu.Lock()
+	l.msgs = append(l.msgs, msg)
+	l.mu.Unlock()
+}
+
+func TestRecover(t *testing.T) {
+	logger := &recordLogger{}
+	mux := NewServeMux()
+	mux.Use(Recover("srv", "test", logger))
+	mux.HandleFunc("ACR", func(c Conn, m *Message) {
+		if n, _ := m.FindAVP(avp.AccountingRecordNumber, 0); n.Data == datatype.Unsigned32(1) {
+			panic("boom")
+		}
+		m.AnswerFrom(Success, "srv", "test").WriteTo(c)
+	})
+	cp, sp := net.Pipe()
+	defer cp.Close()
+	if _, err := NewConn(sp, "pipe", mux, dict.Default); err != nil {
+		t.Fatal(err)
+	}
+	for n, want := range []uint32{UnableToComply, Success} {
+		m := NewRequest(Accounting, 3, dict.Default)
+		m.NewAVP(avp.SessionID, avp.Mbit, 0, datatype.UTF8String("cli;1"))
+		m.NewAVP(avp.AccountingRecordNumber, avp.Mbit, 0, datatype.Unsigned32(n+1))
+		if _, err := m.WriteTo(cp); err != nil {
+			t.Fatal(err)
+		}
+		cp.SetReadDeadline(time.Now().Add(time.Second))
+		a, err := ReadMessage(cp, dict.Default)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code, _ := ResultCode(a); code != want {
+			t.Fatalf("Unexpected Result-Code. Want %d, have %d", want, code)
+		}
+	}
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	if len(logger.msgs) != 1 || logger.msgs[0] != "panic handling message" {
+		t.Fatalf("Unexpected log: %v", logger.msgs)
+	}
+}
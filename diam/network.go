@@ -151,8 +151,12 @@ func listenSCTP(network, address string) (*sctp.SCTPListener, error) {
 			MaxInstreams: MaxInboundSCTPStreams})
 }
 
-// Listen announces on the local network address
+// Listen announces on the local network address, or on the address of
+// the Transport registered for its scheme, see RegisterTransport.
 func Listen(network, address string) (net.Listener, error) {
+	if t, addr := transportOf(address); t != nil {
+		return t.Listen(addr)
+	}
 	switch network {
 	case "sctp", "sctp4", "sctp6":
 		return listenSCTP(network, address)
@@ -163,6 +167,9 @@ func Listen(network, address string) (net.Listener, error) {
 
 // MultistreamListen returns Listener with multistreaming support when appropriate for the network/protocol
 func MultistreamListen(network, address string) (net.Listener, error) {
+	if t, addr := transportOf(address); t != nil {
+		return t.Listen(addr)
+	}
 	switch network {
 	case "sctp", "sctp4", "sctp6":
 		lis, err := listenSCTP(network, address)
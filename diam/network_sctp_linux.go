@@ -0,0 +1,142 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// +build linux,!386
+
+package diam
+
+import (
+	"encoding/binary"
+	"net"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/ishidawataru/sctp"
+)
+
+// sctpPrim mirrors struct sctp_prim (linux/sctp.h)
+type sctpPrim struct {
+	AssocID int32
+	Addr    [128]byte // struct sockaddr_storage
+}
+
+func setSCTPPrimaryAddr(fd int, addr *sctp.SCTPAddr) error {
+	var param sctpPrim
+	copy(param.Addr[:], addr.ToRawSockAddrBuf())
+	_, _, errno := syscall.Syscall6(
+		syscall.SYS_SETSOCKOPT,
+		uintptr(fd),
+		sctp.SOL_SCTP,
+		sctp.SCTP_PRIMARY_ADDR,
+		uintptr(unsafe.Pointer(&param)),
+		unsafe.Sizeof(param),
+		0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// sctpRTOInfo mirrors struct sctp_rtoinfo (linux/sctp.h)
+type sctpRTOInfo struct {
+	AssocID int32
+	Initial uint32
+	Max     uint32
+	Min     uint32
+}
+
+func setSCTPRTOInfo(fd int, initial, min, max time.Duration) error {
+	param := sctpRTOInfo{
+		Initial: uint32(initial / time.Millisecond),
+		Max:     uint32(max / time.Millisecond),
+		Min:     uint32(min / time.Millisecond),
+	}
+	return setsockoptSCTP(fd, sctp.SCTP_RTOINFO, unsafe.Pointer(&param), unsafe.Sizeof(param))
+}
+
+// struct sctp_paddrparams (linux/sctp.h) offsets, the struct is packed
+const (
+	sctpPaddrParamsHBInterval = 4 + 128
+	sctpPaddrParamsFlags      = sctpPaddrParamsHBInterval + 4 + 2 + 4 + 4
+	sctpPaddrParamsLen        = sctpPaddrParamsFlags + 4 + 4 + 1 + 1 // aligned to 4
+)
+
+// spp_flags of struct sctp_paddrparams
+const (
+	sppHBEnable  = 1
+	sppHBDisable = 2
+)
+
+// setSCTPHeartbeat sets the heartbeat interval of all of the paths of
+// the association, or disables heartbeats if interval is negative.
+func setSCTPHeartbeat(fd int, interval time.Duration) error {
+	var param [sctpPaddrParamsLen]byte
+	if interval < 0 {
+		putNativeUint32(param[sctpPaddrParamsFlags:], sppHBDisable)
+	} else {
+		putNativeUint32(param[sctpPaddrParamsHBInterval:], uint32(interval/time.Millisecond))
+		putNativeUint32(param[sctpPaddrParamsFlags:], sppHBEnable)
+	}
+	return setsockoptSCTP(fd, sctp.SCTP_PEER_ADDR_PARAMS, unsafe.Pointer(&param), uintptr(len(param)))
+}
+
+// putNativeUint32 copies v to b in the byte order of the host. The fields
+// of packed structs are not aligned, and must not be written through
+// *uint32 pointers.
+func putNativeUint32(b []byte, v uint32) {
+	copy(b, (*[4]byte)(unsafe.Pointer(&v))[:])
+}
+
+// nativeUint32 is the reverse of putNativeUint32.
+func nativeUint32(b []byte) uint32 {
+	var v uint32
+	copy((*[4]byte)(unsafe.Pointer(&v))[:], b)
+	return v
+}
+
+func setsockoptSCTP(fd int, opt int, val unsafe.Pointer, size uintptr) error {
+	_, _, errno := syscall.Syscall6(
+		syscall.SYS_SETSOCKOPT,
+		uintptr(fd),
+		sctp.SOL_SCTP,
+		uintptr(opt),
+		uintptr(val),
+		size,
+		0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// struct sctp_paddr_change (linux/sctp.h) offsets
+const (
+	sctpPaddrChangeAddr  = 8
+	sctpPaddrChangeState = sctpPaddrChangeAddr + 128
+	sctpPaddrChangeError = sctpPaddrChangeState + 4
+	sctpPaddrChangeLen   = sctpPaddrChangeError + 8
+)
+
+// parseSCTPPeerAddrChange parses b as a SCTP_PEER_ADDR_CHANGE notification,
+// it returns nil for any other notification.
+func parseSCTPPeerAddrChange(b []byte) *SCTPPeerAddrChange {
+	if len(b) < sctpPaddrChangeLen ||
+		sctp.SCTPNotificationType(*(*uint16)(unsafe.Pointer(&b[0]))) != sctp.SCTP_PEER_ADDR_CHANGE {
+		return nil
+	}
+	ev := &SCTPPeerAddrChange{
+		State: SCTPPeerAddrState(*(*int32)(unsafe.Pointer(&b[sctpPaddrChangeState]))),
+		Error: *(*int32)(unsafe.Pointer(&b[sctpPaddrChangeError])),
+	}
+	sa := b[sctpPaddrChangeAddr:sctpPaddrChangeState]
+	ev.Port = int(binary.BigEndian.Uint16(sa[2:4]))
+	switch *(*uint16)(unsafe.Pointer(&sa[0])) {
+	case syscall.AF_INET:
+		ev.Addr = net.IP(append([]byte(nil), sa[4:8]...))
+	case syscall.AF_INET6:
+		ev.Addr = net.IP(append([]byte(nil), sa[8:24]...))
+	}
+	return ev
+}
@@ -0,0 +1,129 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// +build linux,!386
+
+package diam
+
+import (
+	"net"
+	"syscall"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/ishidawataru/sctp"
+)
+
+func TestParseSCTPPeerAddrChange(t *testing.T) {
+	b := make([]byte, sctpPaddrChangeLen)
+	*(*uint16)(unsafe.Pointer(&b[0])) = uint16(sctp.SCTP_PEER_ADDR_CHANGE)
+	sa := b[sctpPaddrChangeAddr:]
+	*(*uint16)(unsafe.Pointer(&sa[0])) = 2 // AF_INET
+	sa[2], sa[3] = 0x0f, 0x1c              // 3868
+	copy(sa[4:], net.IPv4(10, 0, 0, 2).To4())
+	*(*int32)(unsafe.Pointer(&b[sctpPaddrChangeState])) = int32(SCTPAddrUnreachable)
+
+	ev := parseSCTPPeerAddrChange(b)
+	if ev == nil {
+		t.Fatal("Notification was not parsed")
+	}
+	if !ev.Addr.Equal(net.IPv4(10, 0, 0, 2)) || ev.Port != 3868 || ev.State != SCTPAddrUnreachable {
+		t.Fatalf("Unexpected event: %s", ev)
+	}
+
+	*(*uint16)(unsafe.Pointer(&b[0])) = uint16(sctp.SCTP_ASSOC_CHANGE)
+	if ev = parseSCTPPeerAddrChange(b); ev != nil {
+		t.Fatalf("Unexpected event: %s", ev)
+	}
+}
+
+func TestSCTPAddrString(t *testing.T) {
+	s := SCTPAddrString([]net.IP{net.IPv4(10, 0, 0, 1), net.IPv4(10, 0, 1, 1)}, 3868)
+	if s != "10.0.0.1/10.0.1.1:3868" {
+		t.Fatalf("Unexpected address: %s", s)
+	}
+}
+
+func TestNativeUint32(t *testing.T) {
+	b := make([]byte, 7)
+	putNativeUint32(b[1:], 0x01020304)
+	if v := nativeUint32(b[1:]); v != 0x01020304 {
+		t.Fatalf("Unexpected value. Want 0x01020304, have %#x", v)
+	}
+	var v uint32 = 0x01020304
+	if string(b[1:5]) != string((*[4]byte)(unsafe.Pointer(&v))[:]) {
+		t.Fatalf("Unexpected byte order: %x", b[1:5])
+	}
+}
+
+// getsockoptSCTP reads the SCTP socket option opt of fd into val.
+func getsockoptSCTP(t *testing.T, fd int, opt int, val unsafe.Pointer, size uintptr) {
+	n := uint32(size) // socklen_t
+	_, _, errno := syscall.Syscall6(
+		syscall.SYS_GETSOCKOPT,
+		uintptr(fd),
+		sctp.SOL_SCTP,
+		uintptr(opt),
+		uintptr(val),
+		uintptr(unsafe.Pointer(&n)),
+		0)
+	if errno != 0 {
+		t.Fatal(errno)
+	}
+}
+
+func TestTransportConfig_SCTP(t *testing.T) {
+	laddr := &sctp.SCTPAddr{IPAddrs: []net.IPAddr{{IP: net.IPv4(127, 0, 0, 1)}}}
+	l, err := sctp.ListenSCTP("sctp4", laddr)
+	if err != nil {
+		t.Skip("sctp4 not available:", err)
+	}
+	defer l.Close()
+	go func() {
+		if c, err := l.Accept(); err == nil {
+			defer c.Close()
+			time.Sleep(time.Second)
+		}
+	}()
+	c, err := sctp.DialSCTP("sctp4", nil, l.Addr().(*sctp.SCTPAddr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	fd := sctpConnFd(c)
+
+	cfg := &TransportConfig{
+		SCTPRTOInitial:        2 * time.Second,
+		SCTPRTOMin:            500 * time.Millisecond,
+		SCTPRTOMax:            10 * time.Second,
+		SCTPHeartbeatInterval: 5 * time.Second,
+	}
+	if err = cfg.Apply(c); err != nil {
+		t.Fatal(err)
+	}
+	var rto sctpRTOInfo
+	getsockoptSCTP(t, fd, sctp.SCTP_RTOINFO, unsafe.Pointer(&rto), unsafe.Sizeof(rto))
+	if rto.Initial != 2000 || rto.Min != 500 || rto.Max != 10000 {
+		t.Fatalf("Unexpected RTO info. Want 2000/500/10000, have %d/%d/%d", rto.Initial, rto.Min, rto.Max)
+	}
+	var param [sctpPaddrParamsLen]byte
+	getsockoptSCTP(t, fd, sctp.SCTP_PEER_ADDR_PARAMS, unsafe.Pointer(&param), uintptr(len(param)))
+	if v := nativeUint32(param[sctpPaddrParamsHBInterval:]); v != 5000 {
+		t.Fatalf("Unexpected heartbeat interval. Want 5000, have %d", v)
+	}
+	if flags := nativeUint32(param[sctpPaddrParamsFlags:]); flags&sppHBEnable == 0 {
+		t.Fatalf("Heartbeats are not enabled, flags %#x", flags)
+	}
+
+	cfg = &TransportConfig{SCTPHeartbeatInterval: -1}
+	if err = cfg.Apply(c); err != nil {
+		t.Fatal(err)
+	}
+	param = [sctpPaddrParamsLen]byte{}
+	getsockoptSCTP(t, fd, sctp.SCTP_PEER_ADDR_PARAMS, unsafe.Pointer(&param), uintptr(len(param)))
+	if flags := nativeUint32(param[sctpPaddrParamsFlags:]); flags&sppHBDisable == 0 {
+		t.Fatalf("Heartbeats are not disabled, flags %#x", flags)
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+import (
+	"net"
+	"strconv"
+	"sync/atomic"
+	"unsafe"
+
+	"github.com/ishidawataru/sctp"
+
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+// SCTPPeerAddrState is the state of a peer transport address as reported
+// by SCTP_PEER_ADDR_CHANGE notifications, see RFC 6458 section 6.1.2.
+type SCTPPeerAddrState int32
+
+// Peer transport address states.
+const (
+	SCTPAddrAvailable SCTPPeerAddrState = iota
+	SCTPAddrUnreachable
+	SCTPAddrRemoved
+	SCTPAddrAdded
+	SCTPAddrMadePrimary
+	SCTPAddrConfirmed
+)
+
+var sctpPeerAddrStates = [...]string{
+	"AVAILABLE", "UNREACHABLE", "REMOVED", "ADDED", "MADE_PRIM", "CONFIRMED",
+}
+
+func (s SCTPPeerAddrState) String() string {
+	if s >= 0 && int(s) < len(sctpPeerAddrStates) {
+		return sctpPeerAddrStates[s]
+	}
+	return "SCTPPeerAddrState(" + strconv.Itoa(int(s)) + ")"
+}
+
+// SCTPPeerAddrChange describes a change of state of one of the
+// transport addresses of a multi-homed SCTP peer.
+type SCTPPeerAddrChange struct {
+	Addr  net.IP
+	Port  int
+	State SCTPPeerAddrState
+	Error int32
+}
+
+func (ev *SCTPPeerAddrChange) String() string {
+	return net.JoinHostPort(ev.Addr.String(), strconv.Itoa(ev.Port)) + " " + ev.State.String()
+}
+
+// SCTPPeerAddrChangeHandler is called by the reader of the association
+// when a peer address change notification is received, it must not block.
+type SCTPPeerAddrChangeHandler func(c *SCTPConn, ev *SCTPPeerAddrChange)
+
+// SCTPMultihomeDialer is a Dialer for multi-homed SCTP associations.
+//
+// The address passed to Dial may list several peer addresses separated
+// by '/', as in "10.0.0.1/10.0.1.1:3868" (see SCTPAddrString).
+type SCTPMultihomeDialer struct {
+	// LocalAddrs are the local addresses to bind the association to.
+	// If empty, the kernel selects all available local addresses.
+	LocalAddrs []net.IP
+	// LocalPort is the local port to bind to, zero picks any port.
+	LocalPort int
+	// PrimaryAddr optionally selects the peer address to be used as the
+	// primary path once the association is established.
+	PrimaryAddr net.IP
+	// PeerAddrChangeHandler, if set, is notified of peer address changes.
+	PeerAddrChangeHandler SCTPPeerAddrChangeHandler
+}
+
+// Dial connects to the address on the named SCTP network.
+func (d *SCTPMultihomeDialer) Dial(network, address string) (net.Conn, error) {
+	raddr, err := sctp.ResolveSCTPAddr(network, address)
+	if err != nil {
+		return nil, err
+	}
+	var laddr *sctp.SCTPAddr
+	if len(d.LocalAddrs) > 0 || d.LocalPort > 0 {
+		laddr = newSCTPAddr(d.LocalAddrs, d.LocalPort)
+	}
+	conn, err := sctp.DialSCTPExt(
+		network,
+		laddr,
+		raddr,
+		sctp.InitMsg{
+			NumOstreams:  MaxOutboundSCTPStreams,
+			MaxInstreams: MaxInboundSCTPStreams})
+	if err != nil {
+		return nil, err
+	}
+	msc := newSCTPMultihomeConn(conn, d.PeerAddrChangeHandler)
+	if d.PrimaryAddr != nil {
+		if err = msc.SetPrimaryAddr(d.PrimaryAddr); err != nil {
+			msc.Close()
+			return nil, err
+		}
+	}
+	return msc, nil
+}
+
+// DialSCTPMultihome connects to the multi-homed SCTP peer reachable on
+// any of raddrs at port and returns the Conn that can be used to send
+// diameter messages. If d is nil, the association is bound to all local
+// addresses. If dict is nil, dict.Default is used.
+func DialSCTPMultihome(
+	network string, raddrs []net.IP, port int, handler Handler, dp *dict.Parser, d *SCTPMultihomeDialer) (Conn, error) {
+
+	if len(network) == 0 {
+		network = "sctp"
+	}
+	if d == nil {
+		d = &SCTPMultihomeDialer{}
+	}
+	addr := SCTPAddrString(raddrs, port)
+	srv := &Server{Network: network, Addr: addr, Handler: handler, Dict: dp}
+	return dialWith(srv, network, addr, d)
+}
+
+type sctpMultihomeListener struct {
+	*sctp.SCTPListener
+	handler SCTPPeerAddrChangeHandler
+}
+
+// Accept implements the Accept method in the listener interface for sctpMultihomeListener.
+func (l sctpMultihomeListener) Accept() (net.Conn, error) {
+	conn, err := l.AcceptSCTP()
+	if err != nil {
+		return nil, err
+	}
+	return newSCTPMultihomeConn(conn, l.handler), nil
+}
+
+// ListenSCTPMultihome announces on all of the local addresses laddrs at
+// port and returns a multi-streaming Listener that may be passed to
+// Server.Serve. If laddrs is empty, all local addresses are used.
+// Accepted associations notify handler, when set, of peer address changes.
+func ListenSCTPMultihome(network string, laddrs []net.IP, port int, handler SCTPPeerAddrChangeHandler) (net.Listener, error) {
+	if len(network) == 0 {
+		network = "sctp"
+	}
+	lis, err := sctp.ListenSCTPExt(
+		network,
+		newSCTPAddr(laddrs, port),
+		sctp.InitMsg{
+			NumOstreams:  MaxOutboundSCTPStreams,
+			MaxInstreams: MaxInboundSCTPStreams})
+	if err != nil {
+		return nil, err
+	}
+	return sctpMultihomeListener{SCTPListener: lis, handler: handler}, nil
+}
+
+// SCTPAddrString returns the multi-homed address string for the given
+// IP addresses and port, as accepted by the SCTP dialers & listeners:
+// "ip1/ip2/...:port".
+func SCTPAddrString(ips []net.IP, port int) string {
+	return newSCTPAddr(ips, port).String()
+}
+
+func newSCTPAddr(ips []net.IP, port int) *sctp.SCTPAddr {
+	addr := &sctp.SCTPAddr{Port: port}
+	for _, ip := range ips {
+		addr.IPAddrs = append(addr.IPAddrs, net.IPAddr{IP: ip})
+	}
+	return addr
+}
+
+// newSCTPMultihomeConn wraps conn into SCTPConn and, when h is not nil,
+// subscribes to peer address change notifications delivered to h.
+func newSCTPMultihomeConn(conn *sctp.SCTPConn, h SCTPPeerAddrChangeHandler) *SCTPConn {
+	if h == nil {
+		return NewSCTPConn(conn).(*SCTPConn)
+	}
+	var msc *SCTPConn
+	// sctp.SCTPConn only accepts notification handlers on creation,
+	// re-wrap the socket of conn in a new one.
+	conn = sctp.NewSCTPConn(sctpConnFd(conn), func(b []byte) error {
+		if ev := parseSCTPPeerAddrChange(b); ev != nil {
+			h(msc, ev)
+		}
+		return nil
+	})
+	msc = NewSCTPConn(conn).(*SCTPConn)
+	conn.SubscribeEvents(sctp.SCTP_EVENT_DATA_IO | sctp.SCTP_EVENT_ADDRESS)
+	return msc
+}
+
+// sctpConnFd returns the socket of conn, which sctp.SCTPConn keeps
+// unexported as its first field.
+func sctpConnFd(conn *sctp.SCTPConn) int {
+	return int(atomic.LoadInt32((*int32)(unsafe.Pointer(conn))))
+}
+
+// PrimaryAddr returns the peer address currently used as the primary path.
+func (msc *SCTPConn) PrimaryAddr() (net.IP, error) {
+	addr, err := msc.SCTPGetPrimaryPeerAddr()
+	if err != nil {
+		return nil, err
+	}
+	if len(addr.IPAddrs) == 0 {
+		return nil, nil
+	}
+	return addr.IPAddrs[0].IP, nil
+}
+
+// SetPrimaryAddr requests the peer address ip to be used as the primary
+// path of the association (SCTP_PRIMARY_ADDR). ip must be one of the
+// addresses of the peer.
+func (msc *SCTPConn) SetPrimaryAddr(ip net.IP) error {
+	var port int
+	if ra, ok := msc.RemoteAddr().(*sctp.SCTPAddr); ok && ra != nil {
+		port = ra.Port
+	}
+	return setSCTPPrimaryAddr(sctpConnFd(msc.SCTPConn), newSCTPAddr([]net.IP{ip}, port))
+}
@@ -0,0 +1,29 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// +build !linux linux,386
+
+package diam
+
+import (
+	"time"
+
+	"github.com/ishidawataru/sctp"
+)
+
+func setSCTPPrimaryAddr(fd int, addr *sctp.SCTPAddr) error {
+	return sctp.ErrUnsupported
+}
+
+func setSCTPRTOInfo(fd int, initial, min, max time.Duration) error {
+	return sctp.ErrUnsupported
+}
+
+func setSCTPHeartbeat(fd int, interval time.Duration) error {
+	return sctp.ErrUnsupported
+}
+
+func parseSCTPPeerAddrChange(b []byte) *SCTPPeerAddrChange {
+	return nil
+}
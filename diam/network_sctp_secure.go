@@ -0,0 +1,199 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"sync"
+
+	"github.com/ishidawataru/sctp"
+
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+// DiameterDTLSPPID - SCTP Payload Protocol Identifier for Diameter over DTLS
+// see: https://tools.ietf.org/html/rfc6733#section-2.1
+const DiameterDTLSPPID uint32 = 47
+
+// SCTPSecurity is the security protocol of secured SCTP associations,
+// see RFC 6733 section 13.
+type SCTPSecurity int
+
+const (
+	// SCTPTLS is TLS over SCTP, see RFC 3436. The TLS connection is
+	// carried on a single stream.
+	SCTPTLS SCTPSecurity = iota
+
+	// SCTPDTLS is DTLS over SCTP, see RFC 6083. DTLS is provided by a
+	// DTLSFunc.
+	SCTPDTLS
+)
+
+// ErrNoDTLS is returned when DTLS over SCTP is selected without a
+// DTLSFunc.
+var ErrNoDTLS = errors.New("no DTLS implementation")
+
+// DTLSFunc returns the DTLS connection over the SCTP association conn,
+// as the client if client is true or else as the server, configured
+// with config. Writes to conn are sent as single SCTP messages, and
+// reads return single SCTP messages, as required by DTLS records.
+//
+// The standard library has no DTLS implementation: DTLSFuncs adapt
+// third party ones, such as github.com/pion/dtls.
+type DTLSFunc func(conn net.Conn, config *tls.Config, client bool) (net.Conn, error)
+
+// SecureSCTPDialer is a Dialer of SCTP associations secured by TLS or
+// DTLS, which are not multi-streamed.
+type SecureSCTPDialer struct {
+	// Security selects TLS or DTLS over SCTP.
+	Security SCTPSecurity
+	// Config is the TLS configuration of the client. If nil, the
+	// certificate of the server is not verified.
+	Config *tls.Config
+	// DTLS performs the DTLS handshake when Security is SCTPDTLS.
+	DTLS DTLSFunc
+	// LocalAddr is the local address to bind to, if not nil.
+	LocalAddr *sctp.SCTPAddr
+}
+
+// Dial connects to the address on the named SCTP network.
+func (d *SecureSCTPDialer) Dial(network, address string) (net.Conn, error) {
+	if d.Security == SCTPDTLS && d.DTLS == nil {
+		return nil, ErrNoDTLS
+	}
+	config := d.Config
+	if config == nil {
+		config = &tls.Config{InsecureSkipVerify: true}
+	}
+	rw, err := sctpSingleStreamDialer{LocalAddr: d.LocalAddr}.Dial(network, address)
+	if err != nil {
+		return nil, err
+	}
+	sc := rw.(*sctp.SCTPConn)
+	if d.Security == SCTPDTLS {
+		c, err := d.DTLS(&sctpPPIDConn{sc, DiameterDTLSPPID}, config, true)
+		if err != nil {
+			sc.Close()
+			return nil, err
+		}
+		return c, nil
+	}
+	return tls.Client(&sctpPPIDConn{sc, DiameterPPID}, config), nil
+}
+
+// DialSCTPSecure connects to the SCTP peer at addr with the dialer d,
+// and returns the Conn that can be used to send diameter messages.
+// If dict is nil, dict.Default is used.
+func DialSCTPSecure(network, addr string, handler Handler, dp *dict.Parser, d *SecureSCTPDialer) (Conn, error) {
+	if len(network) == 0 {
+		network = "sctp"
+	}
+	srv := &Server{Network: network, Addr: addr, Handler: handler, Dict: dp}
+	return dialWith(srv, network, addr, d)
+}
+
+// ListenSCTPSecure announces on the local SCTP address, and returns a
+// Listener of associations secured by TLS or DTLS with config, which
+// must contain at least one certificate.
+func ListenSCTPSecure(network, address string, security SCTPSecurity, config *tls.Config, dtls DTLSFunc) (net.Listener, error) {
+	if security == SCTPDTLS && dtls == nil {
+		return nil, ErrNoDTLS
+	}
+	l, err := listenSCTP(network, address)
+	if err != nil {
+		return nil, err
+	}
+	return &secureSCTPListener{l, security, config, dtls}, nil
+}
+
+// ListenAndServeSCTPSecure listens on the SCTP network address addr
+// and then calls Serve with handler to handle requests on incoming
+// associations secured by TLS or DTLS.
+//
+// If handler is nil, DefaultServeMux is used.
+//
+// If dict is nil, dict.Default is used.
+func ListenAndServeSCTPSecure(network, addr string, security SCTPSecurity, config *tls.Config, dtls DTLSFunc, handler Handler, dp *dict.Parser) error {
+	if len(network) == 0 {
+		network = "sctp"
+	}
+	l, err := ListenSCTPSecure(network, addr, security, config, dtls)
+	if err != nil {
+		return err
+	}
+	server := &Server{Network: network, Addr: addr, Handler: handler, Dict: dp}
+	return server.Serve(l)
+}
+
+type secureSCTPListener struct {
+	*sctp.SCTPListener
+	security SCTPSecurity
+	config   *tls.Config
+	dtls     DTLSFunc
+}
+
+// Accept implements the Accept method in the listener interface for
+// secureSCTPListener. Handshakes are performed by the first read or
+// write of the connections.
+func (l *secureSCTPListener) Accept() (net.Conn, error) {
+	sc, err := l.AcceptSCTP()
+	if err != nil {
+		return nil, err
+	}
+	if l.security == SCTPDTLS {
+		pc := &sctpPPIDConn{sc, DiameterDTLSPPID}
+		return &dtlsServerConn{Conn: pc, config: l.config, dtls: l.dtls}, nil
+	}
+	return tls.Server(&sctpPPIDConn{sc, DiameterPPID}, l.config), nil
+}
+
+// sctpPPIDConn is an SCTP association writing messages with a Payload
+// Protocol Identifier on stream 0.
+type sctpPPIDConn struct {
+	*sctp.SCTPConn
+	ppid uint32
+}
+
+func (c *sctpPPIDConn) Write(b []byte) (int, error) {
+	return c.SCTPWrite(b, &sctp.SndRcvInfo{PPID: c.ppid})
+}
+
+// dtlsServerConn is the server side of a DTLS association, which
+// performs the handshake on first use, rather than in Accept.
+type dtlsServerConn struct {
+	net.Conn // SCTP association
+	config   *tls.Config
+	dtls     DTLSFunc
+
+	once sync.Once
+	dc   net.Conn
+	err  error
+}
+
+func (c *dtlsServerConn) handshake() error {
+	c.once.Do(func() {
+		c.dc, c.err = c.dtls(c.Conn, c.config, false)
+		if c.err != nil {
+			c.Conn.Close()
+		}
+	})
+	return c.err
+}
+
+func (c *dtlsServerConn) Read(b []byte) (int, error) {
+	if err := c.handshake(); err != nil {
+		return 0, err
+	}
+	return c.dc.Read(b)
+}
+
+func (c *dtlsServerConn) Write(b []byte) (int, error) {
+	if err := c.handshake(); err != nil {
+		return 0, err
+	}
+	return c.dc.Write(b)
+}
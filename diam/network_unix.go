@@ -7,7 +7,6 @@
 package diam
 
 import (
-	"log"
 	"strings"
 	"syscall"
 )
@@ -19,7 +18,8 @@ func setReuseTcpAddr(network, address string, c syscall.RawConn) error {
 		c.Control(func(fd uintptr) {
 			err := syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_REUSEADDR, 1)
 			if err != nil {
-				log.Printf("Setting SO_REUSEADDR for %s:%s error: %v", network, address, err)
+				DefaultLogger.Log(LevelWarn, "setting SO_REUSEADDR failed",
+					"network", network, "addr", address, "err", err)
 			}
 		})
 	}
@@ -0,0 +1,85 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+// The Observer interface is implemented by Handlers that observe the
+// connections and messages they serve, such as for collecting metrics.
+//
+// Observers are called from the goroutines of the connections and must
+// not block.
+type Observer interface {
+	// ConnOpened is called when a connection is accepted or dialed.
+	ConnOpened(c Conn)
+
+	// ConnClosed is called when a connection is closed.
+	ConnClosed(c Conn)
+
+	// MessageReceived is called with each message read from c.
+	MessageReceived(c Conn, m *Message)
+
+	// MessageSent is called with each message written to c, just
+	// before it is written.
+	MessageSent(c Conn, m *Message)
+}
+
+// observer returns the Observer of the connection handler, or nil.
+func (c *conn) observer() Observer {
+	h := c.server.Handler
+	if h == nil {
+		h = DefaultServeMux
+	}
+	o, _ := h.(Observer)
+	return o
+}
+
+// The Preparer interface is implemented by Handlers that update the
+// messages written to the connections they serve before they are
+// serialized, such as to add the AVPs of extensions to requests.
+type Preparer interface {
+	// PrepareMessage is called with each message written to c. The
+	// message is not written when it returns an error, which is
+	// returned by the write.
+	PrepareMessage(c Conn, m *Message) error
+}
+
+// preparer returns the Preparer of the connection handler, or nil.
+func (c *conn) preparer() Preparer {
+	h := c.server.Handler
+	if h == nil {
+		h = DefaultServeMux
+	}
+	p, _ := h.(Preparer)
+	return p
+}
+
+// messagePreparer is implemented by connections that prepare the
+// messages written to them.
+type messagePreparer interface {
+	prepareMessage(m *Message) error
+}
+
+// prepareMessage implements the messagePreparer interface.
+func (w *response) prepareMessage(m *Message) error {
+	if p := w.conn.preparer(); p != nil {
+		return p.PrepareMessage(w, m)
+	}
+	return nil
+}
+
+// messageSender is implemented by connections that are notified of the
+// messages written to them.
+type messageSender interface {
+	messageSent(m *Message)
+}
+
+// messageSent logs the message m being written to the connection, and
+// notifies its observer.
+func (w *response) messageSent(m *Message) {
+	w.conn.logMessage("message sent", m)
+	w.conn.trackMessage(m, false)
+	if o := w.conn.observer(); o != nil {
+		o.MessageSent(w, m)
+	}
+}
@@ -0,0 +1,310 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package pcap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+)
+
+// Ethernet types.
+const (
+	etherTypeIPv4 = 0x0800
+	etherTypeIPv6 = 0x86dd
+	etherTypeVLAN = 0x8100
+	etherTypeQinQ = 0x88a8
+)
+
+// TCP flags.
+const (
+	tcpFIN = 0x01
+	tcpSYN = 0x02
+	tcpRST = 0x04
+)
+
+// SCTP DATA chunk flags.
+const (
+	sctpEnd   = 0x01
+	sctpBegin = 0x02
+)
+
+// maxStreamBuffer is the maximum number of bytes buffered per TCP stream
+// or SCTP message. Streams that exceed it are dropped.
+const maxStreamBuffer = 1 << 24
+
+// maxOutOfOrder is the maximum number of out of order segments kept per
+// TCP stream.
+const maxOutOfOrder = 1024
+
+// decodePacket decodes the Diameter messages of a captured packet.
+func (r *Reader) decodePacket(ts time.Time, linkType int, data []byte) {
+	ip, ok := linkPayload(linkType, data)
+	if !ok {
+		return
+	}
+	flow, payload, ok := decodeIP(ip)
+	if !ok {
+		return
+	}
+	switch flow.Protocol {
+	case TCP:
+		r.decodeTCP(ts, flow, payload)
+	case SCTP:
+		r.decodeSCTP(ts, flow, payload)
+	}
+}
+
+// linkPayload returns the IP packet of a link layer frame.
+func linkPayload(linkType int, b []byte) ([]byte, bool) {
+	switch linkType {
+	case LinkTypeEthernet:
+		if len(b) < 14 {
+			return nil, false
+		}
+		et := binary.BigEndian.Uint16(b[12:])
+		b = b[14:]
+		for (et == etherTypeVLAN || et == etherTypeQinQ) && len(b) >= 4 {
+			et = binary.BigEndian.Uint16(b[2:])
+			b = b[4:]
+		}
+		return b, et == etherTypeIPv4 || et == etherTypeIPv6
+	case LinkTypeLinuxSLL:
+		if len(b) < 16 {
+			return nil, false
+		}
+		et := binary.BigEndian.Uint16(b[14:])
+		return b[16:], et == etherTypeIPv4 || et == etherTypeIPv6
+	case LinkTypeNull:
+		// The address family is in the byte order of the capturing host,
+		// so the IP version is checked instead.
+		if len(b) < 4 {
+			return nil, false
+		}
+		return b[4:], true
+	case LinkTypeRaw, LinkTypeIPv4, LinkTypeIPv6:
+		return b, true
+	}
+	return nil, false
+}
+
+// decodeIP returns the flow and transport payload of an IP packet. It
+// fails for fragments.
+func decodeIP(b []byte) (Flow, []byte, bool) {
+	var f Flow
+	if len(b) == 0 {
+		return f, nil, false
+	}
+	switch b[0] >> 4 {
+	case 4:
+		ihl := int(b[0]&0x0f) * 4
+		if len(b) < 20 || ihl < 20 || len(b) < ihl {
+			return f, nil, false
+		}
+		if binary.BigEndian.Uint16(b[6:])&0x3fff != 0 {
+			return f, nil, false // MF flag or fragment offset
+		}
+		end := int(binary.BigEndian.Uint16(b[2:]))
+		if end < ihl || end > len(b) {
+			end = len(b)
+		}
+		f.Protocol = Protocol(b[9])
+		f.Src = net.IP(b[12:16])
+		f.Dst = net.IP(b[16:20])
+		return f, b[ihl:end], true
+	case 6:
+		if len(b) < 40 {
+			return f, nil, false
+		}
+		end := 40 + int(binary.BigEndian.Uint16(b[4:]))
+		if end > len(b) {
+			end = len(b)
+		}
+		next := b[6]
+		f.Src = net.IP(b[8:24])
+		f.Dst = net.IP(b[24:40])
+		p := b[40:end]
+		// Skip the Hop-by-Hop, Routing and Destination Options headers.
+		for next == 0 || next == 43 || next == 60 {
+			if len(p) < 8 {
+				return f, nil, false
+			}
+			n := (int(p[1]) + 1) * 8
+			if n > len(p) {
+				return f, nil, false
+			}
+			next, p = p[0], p[n:]
+		}
+		f.Protocol = Protocol(next)
+		return f, p, true
+	}
+	return f, nil, false
+}
+
+// tcpStream is the state of the reassembly of a TCP stream.
+type tcpStream struct {
+	next uint32            // sequence number of the next byte
+	buf  []byte            // bytes not yet decoded
+	ooo  map[uint32][]byte // out of order segments by sequence number
+}
+
+func (r *Reader) decodeTCP(ts time.Time, f Flow, b []byte) {
+	if len(b) < 20 {
+		return
+	}
+	off := int(b[12]>>4) * 4
+	if off < 20 || off > len(b) {
+		return
+	}
+	f.SrcPort = binary.BigEndian.Uint16(b[0:])
+	f.DstPort = binary.BigEndian.Uint16(b[2:])
+	seq := binary.BigEndian.Uint32(b[4:])
+	flags := b[13]
+	payload := b[off:]
+	k := f.key()
+	s := r.tcp[k]
+	switch {
+	case flags&tcpSYN != 0:
+		r.tcp[k] = &tcpStream{next: seq + 1, ooo: make(map[uint32][]byte)}
+		return
+	case s == nil:
+		// The start of the stream was not captured.
+		if len(payload) < diam.HeaderLength || payload[0] != 1 {
+			return
+		}
+		s = &tcpStream{next: seq, ooo: make(map[uint32][]byte)}
+		r.tcp[k] = s
+	}
+	if len(payload) > 0 {
+		s.add(seq, payload)
+		r.decodeStream(ts, f, s)
+	}
+	if flags&(tcpFIN|tcpRST) != 0 {
+		delete(r.tcp, k)
+	}
+}
+
+// add adds the segment p with sequence number seq to the stream.
+func (s *tcpStream) add(seq uint32, p []byte) {
+	if d := int32(seq - s.next); d > 0 {
+		if len(s.ooo) < maxOutOfOrder {
+			s.ooo[seq] = append([]byte(nil), p...)
+		}
+		return
+	}
+	s.append(seq, p)
+	for again := true; again; {
+		again = false
+		for seq, p := range s.ooo {
+			if int32(seq-s.next) <= 0 {
+				delete(s.ooo, seq)
+				s.append(seq, p)
+				again = true
+			}
+		}
+	}
+}
+
+// append appends the bytes of the segment p with sequence number seq,
+// which must not be after the next, that were not seen yet.
+func (s *tcpStream) append(seq uint32, p []byte) {
+	seen := int(s.next - seq)
+	if seen >= len(p) {
+		return // retransmission
+	}
+	s.buf = append(s.buf, p[seen:]...)
+	s.next = seq + uint32(len(p))
+}
+
+// decodeStream decodes the complete Diameter messages buffered in s.
+func (r *Reader) decodeStream(ts time.Time, f Flow, s *tcpStream) {
+	for len(s.buf) >= diam.HeaderLength {
+		n := int(s.buf[1])<<16 | int(s.buf[2])<<8 | int(s.buf[3])
+		if s.buf[0] != 1 || n < diam.HeaderLength || len(s.buf) > maxStreamBuffer {
+			// Out of sync, wait for the next message boundary.
+			delete(r.tcp, f.key())
+			return
+		}
+		if len(s.buf) < n {
+			return
+		}
+		r.decodeMessage(ts, f, s.buf[:n])
+		s.buf = s.buf[n:]
+	}
+	if len(s.buf) == 0 {
+		s.buf = nil
+	}
+}
+
+// sctpKey identifies an SCTP stream.
+type sctpKey struct {
+	flow   flowKey
+	stream uint16
+}
+
+// sctpMessage is a fragmented SCTP user message being reassembled.
+type sctpMessage struct {
+	next uint32 // TSN of the next fragment
+	data []byte
+}
+
+func (r *Reader) decodeSCTP(ts time.Time, f Flow, b []byte) {
+	if len(b) < 12 {
+		return
+	}
+	f.SrcPort = binary.BigEndian.Uint16(b[0:])
+	f.DstPort = binary.BigEndian.Uint16(b[2:])
+	for chunks := b[12:]; len(chunks) >= 4; {
+		n := int(binary.BigEndian.Uint16(chunks[2:]))
+		if n < 4 || n > len(chunks) {
+			return
+		}
+		if chunks[0] == 0 && n >= 16 { // DATA
+			tsn := binary.BigEndian.Uint32(chunks[4:])
+			sid := binary.BigEndian.Uint16(chunks[8:])
+			r.decodeData(ts, f, sid, tsn, chunks[1], chunks[16:n])
+		}
+		if n = (n + 3) &^ 3; n > len(chunks) {
+			return
+		}
+		chunks = chunks[n:]
+	}
+}
+
+// decodeData decodes the user data of a DATA chunk, reassembling
+// fragmented messages.
+func (r *Reader) decodeData(ts time.Time, f Flow, sid uint16, tsn uint32, flags byte, data []byte) {
+	k := sctpKey{f.key(), sid}
+	switch {
+	case flags&sctpBegin != 0 && flags&sctpEnd != 0:
+		r.decodeMessage(ts, f, data)
+	case flags&sctpBegin != 0:
+		r.sctp[k] = &sctpMessage{next: tsn + 1, data: append([]byte(nil), data...)}
+	default:
+		m := r.sctp[k]
+		if m == nil || m.next != tsn || len(m.data) > maxStreamBuffer {
+			delete(r.sctp, k) // missing fragment
+			return
+		}
+		m.data = append(m.data, data...)
+		m.next++
+		if flags&sctpEnd != 0 {
+			delete(r.sctp, k)
+			r.decodeMessage(ts, f, m.data)
+		}
+	}
+}
+
+func (r *Reader) decodeMessage(ts time.Time, f Flow, b []byte) {
+	m, err := diam.ReadMessage(bytes.NewReader(b), r.dict)
+	if err != nil {
+		r.pending = append(r.pending, result{err: &DecodeError{Timestamp: ts, Flow: f, Err: err}})
+		return
+	}
+	r.pending = append(r.pending, result{m: &Message{Timestamp: ts, Flow: f, Message: m}})
+}
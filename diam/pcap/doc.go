@@ -0,0 +1,29 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package pcap reads Diameter messages from pcap and pcapng captures,
+// such as those of tcpdump and Wireshark, and writes synthetic captures
+// of messages for offline analysis and regression testing.
+//
+// Reading the messages of a capture:
+//
+//	f, err := os.Open("trace.pcapng")
+//	...
+//	r, err := pcap.NewReader(f, dict.Default)
+//	for {
+//		m, err := r.Next()
+//		if err == io.EOF {
+//			break
+//		}
+//		...
+//		fmt.Println(m.Timestamp, m.Flow, m.Message)
+//	}
+//
+// Supported link types are Ethernet (with VLAN tags), Linux cooked
+// capture, BSD loopback and raw IP, over IPv4 and IPv6.
+//
+// Writing a capture that Wireshark decodes as Diameter:
+//
+//	err := pcap.WriteMessages(f, []*diam.Message{req, ans})
+package pcap
@@ -0,0 +1,118 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package pcap
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+)
+
+// Link types of captures, see http://www.tcpdump.org/linktypes.html.
+const (
+	LinkTypeNull     = 0
+	LinkTypeEthernet = 1
+	LinkTypeRaw      = 101
+	LinkTypeLinuxSLL = 113
+	LinkTypeIPv4     = 228
+	LinkTypeIPv6     = 229
+)
+
+// Protocol is the transport protocol of a Flow.
+type Protocol uint8
+
+// Transport protocols, as IP protocol numbers.
+const (
+	TCP  Protocol = 6
+	SCTP Protocol = 132
+)
+
+// String returns the name of the protocol.
+func (p Protocol) String() string {
+	switch p {
+	case TCP:
+		return "tcp"
+	case SCTP:
+		return "sctp"
+	}
+	return "proto-" + strconv.Itoa(int(p))
+}
+
+// Flow is the direction of a transport connection messages are sent on.
+type Flow struct {
+	Protocol Protocol
+	Src      net.IP
+	SrcPort  uint16
+	Dst      net.IP
+	DstPort  uint16
+}
+
+// Reverse returns the opposite direction of the flow.
+func (f Flow) Reverse() Flow {
+	return Flow{f.Protocol, f.Dst, f.DstPort, f.Src, f.SrcPort}
+}
+
+// String returns the flow as "tcp 10.0.0.1:49152 > 10.0.0.2:3868".
+func (f Flow) String() string {
+	return fmt.Sprintf("%s %s > %s", f.Protocol,
+		net.JoinHostPort(f.Src.String(), strconv.Itoa(int(f.SrcPort))),
+		net.JoinHostPort(f.Dst.String(), strconv.Itoa(int(f.DstPort))),
+	)
+}
+
+// flowKey is the comparable form of a Flow.
+type flowKey struct {
+	proto    Protocol
+	src, dst [net.IPv6len]byte
+	sp, dp   uint16
+}
+
+func (f Flow) key() flowKey {
+	k := flowKey{proto: f.Protocol, sp: f.SrcPort, dp: f.DstPort}
+	copy(k.src[:], f.Src.To16())
+	copy(k.dst[:], f.Dst.To16())
+	return k
+}
+
+// Message is a Diameter message of a capture.
+type Message struct {
+	Timestamp time.Time
+	Flow      Flow
+	*diam.Message
+}
+
+// DecodeError is returned by Reader.Next for the Diameter messages of a
+// capture that cannot be decoded. Reading may continue after it.
+type DecodeError struct {
+	Timestamp time.Time
+	Flow      Flow
+	Err       error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("%s at %s: %v", e.Flow, e.Timestamp.Format(time.RFC3339Nano), e.Err)
+}
+
+var (
+	// ErrUnknownFormat is returned by NewReader when the capture is
+	// neither pcap nor pcapng.
+	ErrUnknownFormat = errors.New("unknown capture format")
+
+	// ErrInvalidBlock is returned when a capture record or block is
+	// truncated or malformed.
+	ErrInvalidBlock = errors.New("invalid capture block")
+
+	// ErrUnsupportedProtocol is returned by Writer for flows that are
+	// neither TCP nor SCTP.
+	ErrUnsupportedProtocol = errors.New("unsupported transport protocol")
+
+	// ErrMessageTooLarge is returned by Writer when a message does not
+	// fit in an IP packet.
+	ErrMessageTooLarge = errors.New("message too large for an IP packet")
+)
@@ -0,0 +1,270 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package pcap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+func newCER() *diam.Message {
+	m := diam.NewRequest(diam.CapabilitiesExchange, 0, dict.Default)
+	m.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("cli"))
+	m.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity("cli.test"))
+	m.NewAVP(avp.HostIPAddress, avp.Mbit, 0, datatype.Address(net.ParseIP("10.0.0.1")))
+	m.NewAVP(avp.VendorID, avp.Mbit, 0, datatype.Unsigned32(13))
+	m.NewAVP(avp.ProductName, 0, 0, datatype.UTF8String("go-diameter"))
+	return m
+}
+
+func newCEA(req *diam.Message) *diam.Message {
+	a := req.Answer(diam.Success)
+	a.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("srv"))
+	a.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity("srv.test"))
+	return a
+}
+
+func checkMessages(t *testing.T, have []*Message, want ...*diam.Message) {
+	t.Helper()
+	if len(have) != len(want) {
+		t.Fatalf("Unexpected number of messages. Want %d, have %d", len(want), len(have))
+	}
+	for i, m := range want {
+		b1, _ := m.Serialize()
+		b2, _ := have[i].Serialize()
+		if !bytes.Equal(b1, b2) {
+			t.Fatalf("Unexpected message %d.\nWant %x\nHave %x", i, b1, b2)
+		}
+	}
+}
+
+func TestWriteReadMessages(t *testing.T) {
+	req := newCER()
+	ans := newCEA(req)
+	var buf bytes.Buffer
+	if err := WriteMessages(&buf, []*diam.Message{req, ans}); err != nil {
+		t.Fatal(err)
+	}
+	msgs, err := ReadAll(&buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkMessages(t, msgs, req, ans)
+	if f := msgs[0].Flow.String(); f != "tcp 127.0.0.1:49152 > 127.0.0.1:3868" {
+		t.Fatalf("Unexpected flow: %s", f)
+	}
+	if f := msgs[1].Flow.String(); f != "tcp 127.0.0.1:3868 > 127.0.0.1:49152" {
+		t.Fatalf("Unexpected flow: %s", f)
+	}
+	if d := msgs[1].Timestamp.Sub(msgs[0].Timestamp); d != time.Millisecond {
+		t.Fatalf("Unexpected time between messages: %s", d)
+	}
+}
+
+func TestWriteReadSCTPv6(t *testing.T) {
+	f := Flow{
+		Protocol: SCTP,
+		Src:      net.ParseIP("2001:db8::1"),
+		SrcPort:  3868,
+		Dst:      net.ParseIP("2001:db8::2"),
+		DstPort:  3868,
+	}
+	req := newCER()
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err = w.WriteMessage(time.Now(), f, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.WriteMessage(time.Now(), Flow{Protocol: 17, Src: f.Src, Dst: f.Dst}, req); err != ErrUnsupportedProtocol {
+		t.Fatalf("Unexpected error. Want %v, have %v", ErrUnsupportedProtocol, err)
+	}
+	msgs, err := ReadAll(&buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkMessages(t, msgs, req, req)
+	if !msgs[0].Flow.Dst.Equal(f.Dst) || msgs[0].Flow.Protocol != SCTP {
+		t.Fatalf("Unexpected flow: %s", msgs[0].Flow)
+	}
+}
+
+// pcapngWriter writes pcapng captures of raw IP packets.
+type pcapngWriter struct {
+	bytes.Buffer
+}
+
+func (w *pcapngWriter) block(typ uint32, body []byte) {
+	for len(body)%4 != 0 {
+		body = append(body, 0)
+	}
+	var n [4]byte
+	binary.LittleEndian.PutUint32(n[:], uint32(12+len(body)))
+	binary.Write(w, binary.LittleEndian, typ)
+	w.Write(n[:])
+	w.Write(body)
+	w.Write(n[:])
+}
+
+func newPcapngWriter() *pcapngWriter {
+	w := &pcapngWriter{}
+	shb := make([]byte, 16)
+	binary.LittleEndian.PutUint32(shb, pcapngByteOrder)
+	binary.LittleEndian.PutUint16(shb[4:], 1)
+	binary.LittleEndian.PutUint64(shb[8:], ^uint64(0))
+	w.block(pcapngSHB, shb)
+	// if_tsresol of nanoseconds.
+	idb := []byte{LinkTypeRaw, 0, 0, 0, 0, 0, 0, 0, 9, 0, 1, 0, 9, 0, 0, 0, 0, 0, 0, 0}
+	w.block(pcapngIDB, idb)
+	return w
+}
+
+func (w *pcapngWriter) packet(ts time.Time, pkt []byte) {
+	epb := make([]byte, 20, 20+len(pkt))
+	n := uint64(ts.UnixNano())
+	binary.LittleEndian.PutUint32(epb[4:], uint32(n>>32))
+	binary.LittleEndian.PutUint32(epb[8:], uint32(n))
+	binary.LittleEndian.PutUint32(epb[12:], uint32(len(pkt)))
+	binary.LittleEndian.PutUint32(epb[16:], uint32(len(pkt)))
+	w.block(pcapngEPB, append(epb, pkt...))
+}
+
+func TestReadPcapngTCPReassembly(t *testing.T) {
+	f := Flow{
+		Protocol: TCP,
+		Src:      net.ParseIP("10.0.0.1"),
+		SrcPort:  49152,
+		Dst:      net.ParseIP("10.0.0.2"),
+		DstPort:  3868,
+	}
+	req := newCER()
+	ans := newCEA(req)
+	b1, _ := req.Serialize()
+	b2, _ := ans.Serialize()
+	stream := append(append(append([]byte(nil), b1...), b2...), b1...)
+
+	pw := &Writer{next: make(map[flowKey]uint32)}
+	w := newPcapngWriter()
+	ts := time.Unix(1577836800, 123456789)
+	segment := func(seq uint32, b []byte) {
+		pkt, err := pw.ipPacket(f, pw.tcpSegment(f, 1000+seq, b))
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.packet(ts, pkt)
+	}
+	// The first message, then the end of the stream out of order and
+	// a retransmission overlapping the first message.
+	split := len(b1) + len(b2) + 10
+	segment(0, stream[:len(b1)])
+	segment(uint32(split), stream[split:])
+	segment(0, stream[:split])
+
+	rd, err := NewReader(&w.Buffer, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msgs []*Message
+	for {
+		m, err := rd.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgs = append(msgs, m)
+	}
+	checkMessages(t, msgs, req, ans, req)
+	if !msgs[0].Timestamp.Equal(ts) {
+		t.Fatalf("Unexpected timestamp. Want %s, have %s", ts, msgs[0].Timestamp)
+	}
+}
+
+func TestReadSCTPFragments(t *testing.T) {
+	f := Flow{
+		Protocol: SCTP,
+		Src:      net.ParseIP("10.0.0.1"),
+		SrcPort:  3868,
+		Dst:      net.ParseIP("10.0.0.2"),
+		DstPort:  3868,
+	}
+	req := newCER()
+	b, _ := req.Serialize()
+	chunk := func(tsn uint32, flags byte, data []byte) []byte {
+		c := make([]byte, 16, 16+len(data)+3)
+		c[1] = flags
+		binary.BigEndian.PutUint16(c[2:], uint16(16+len(data)))
+		binary.BigEndian.PutUint32(c[4:], tsn)
+		binary.BigEndian.PutUint32(c[12:], sctpPPID)
+		c = append(c, data...)
+		for len(c)%4 != 0 {
+			c = append(c, 0)
+		}
+		return c
+	}
+	// Two fragments in one packet, a lone fragment that is dropped, and
+	// the last fragment with a bad message.
+	pkt := make([]byte, 12)
+	pkt = append(pkt, chunk(1, sctpBegin, b[:7])...)
+	pkt = append(pkt, chunk(2, 0, b[7:30])...)
+	pkt2 := append(make([]byte, 12), chunk(3, sctpEnd, b[30:])...)
+	pkt3 := append(make([]byte, 12), chunk(9, sctpEnd, b[30:])...)
+	pkt4 := append(make([]byte, 12), chunk(10, sctpBegin|sctpEnd, b[:30])...)
+
+	pw := &Writer{}
+	var buf bytes.Buffer
+	hdr := make([]byte, 24)
+	binary.BigEndian.PutUint32(hdr, pcapMagicNano)
+	binary.BigEndian.PutUint32(hdr[20:], LinkTypeIPv4)
+	buf.Write(hdr)
+	for _, p := range [][]byte{pkt, pkt2, pkt3, pkt4} {
+		ip, err := pw.ipPacket(f, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rec := make([]byte, 16)
+		binary.BigEndian.PutUint32(rec[8:], uint32(len(ip)))
+		buf.Write(rec)
+		buf.Write(ip)
+	}
+
+	rd, err := NewReader(&buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := rd.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkMessages(t, []*Message{m}, req)
+	if _, err = rd.Next(); err == nil {
+		t.Fatal("Unexpected success decoding a truncated message")
+	} else if _, ok := err.(*DecodeError); !ok {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err = rd.Next(); err != io.EOF {
+		t.Fatalf("Unexpected error. Want %v, have %v", io.EOF, err)
+	}
+}
+
+func TestNewReaderUnknownFormat(t *testing.T) {
+	if _, err := NewReader(bytes.NewReader(make([]byte, 24)), nil); err != ErrUnknownFormat {
+		t.Fatalf("Unexpected error. Want %v, have %v", ErrUnknownFormat, err)
+	}
+}
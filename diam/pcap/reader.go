@@ -0,0 +1,312 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package pcap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"math"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+// Magic numbers of the capture formats.
+const (
+	pcapMagic       = 0xa1b2c3d4 // pcap, microsecond timestamps
+	pcapMagicNano   = 0xa1b23c4d // pcap, nanosecond timestamps
+	pcapngSHB       = 0x0a0d0d0a // pcapng Section Header Block type
+	pcapngByteOrder = 0x1a2b3c4d
+)
+
+// pcapng block types.
+const (
+	pcapngIDB = 1 // Interface Description Block
+	pcapngSPB = 3 // Simple Packet Block
+	pcapngEPB = 6 // Enhanced Packet Block
+)
+
+// PacketSource is a source of captured packets, such as a capture file or
+// a live capture.
+type PacketSource interface {
+	// ReadPacket returns the next packet with its capture time and link
+	// type, or io.EOF when there are no more packets.
+	ReadPacket() (ts time.Time, linkType int, data []byte, err error)
+}
+
+// pcapFile reads pcap files.
+type pcapFile struct {
+	r        io.Reader
+	order    binary.ByteOrder
+	nano     bool
+	linkType int
+}
+
+func newPcapFile(r io.Reader, magic []byte) (*pcapFile, error) {
+	f := &pcapFile{r: r}
+	switch {
+	case binary.LittleEndian.Uint32(magic) == pcapMagic:
+		f.order = binary.LittleEndian
+	case binary.BigEndian.Uint32(magic) == pcapMagic:
+		f.order = binary.BigEndian
+	case binary.LittleEndian.Uint32(magic) == pcapMagicNano:
+		f.order, f.nano = binary.LittleEndian, true
+	case binary.BigEndian.Uint32(magic) == pcapMagicNano:
+		f.order, f.nano = binary.BigEndian, true
+	default:
+		return nil, ErrUnknownFormat
+	}
+	var hdr [20]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return nil, ErrInvalidBlock
+	}
+	f.linkType = int(f.order.Uint32(hdr[16:]) & 0xffff)
+	return f, nil
+}
+
+func (f *pcapFile) ReadPacket() (time.Time, int, []byte, error) {
+	var hdr [16]byte
+	if _, err := io.ReadFull(f.r, hdr[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			err = ErrInvalidBlock
+		}
+		return time.Time{}, 0, nil, err
+	}
+	sec := int64(f.order.Uint32(hdr[0:]))
+	frac := int64(f.order.Uint32(hdr[4:]))
+	if !f.nano {
+		frac *= 1000
+	}
+	data := make([]byte, f.order.Uint32(hdr[8:]))
+	if _, err := io.ReadFull(f.r, data); err != nil {
+		return time.Time{}, 0, nil, ErrInvalidBlock
+	}
+	return time.Unix(sec, frac), f.linkType, data, nil
+}
+
+// pcapngFile reads pcapng files.
+type pcapngFile struct {
+	r      io.Reader
+	order  binary.ByteOrder
+	ifaces []pcapngInterface
+}
+
+type pcapngInterface struct {
+	linkType int
+	tsUnits  uint64 // timestamp units per second
+}
+
+func (f *pcapngFile) ReadPacket() (time.Time, int, []byte, error) {
+	for {
+		typ, body, err := f.readBlock()
+		if err != nil {
+			return time.Time{}, 0, nil, err
+		}
+		switch typ {
+		case pcapngIDB:
+			if len(body) < 8 {
+				return time.Time{}, 0, nil, ErrInvalidBlock
+			}
+			f.ifaces = append(f.ifaces, pcapngInterface{
+				linkType: int(f.order.Uint16(body)),
+				tsUnits:  f.tsUnits(body[8:]),
+			})
+		case pcapngEPB:
+			if len(body) < 20 {
+				return time.Time{}, 0, nil, ErrInvalidBlock
+			}
+			id := int(f.order.Uint32(body))
+			n := int(f.order.Uint32(body[12:]))
+			if id >= len(f.ifaces) || 20+n > len(body) {
+				return time.Time{}, 0, nil, ErrInvalidBlock
+			}
+			ts := uint64(f.order.Uint32(body[4:]))<<32 | uint64(f.order.Uint32(body[8:]))
+			iface := f.ifaces[id]
+			return iface.time(ts), iface.linkType, body[20 : 20+n], nil
+		case pcapngSPB:
+			if len(body) < 4 || len(f.ifaces) == 0 {
+				return time.Time{}, 0, nil, ErrInvalidBlock
+			}
+			n := int(f.order.Uint32(body))
+			if n > len(body)-4 {
+				n = len(body) - 4
+			}
+			return time.Time{}, f.ifaces[0].linkType, body[4 : 4+n], nil
+		}
+	}
+}
+
+// time returns the time of the timestamp ts of a packet of the interface.
+func (i pcapngInterface) time(ts uint64) time.Time {
+	sec, frac := ts/i.tsUnits, ts%i.tsUnits
+	if i.tsUnits <= 1e9 {
+		return time.Unix(int64(sec), int64(frac*1e9/i.tsUnits))
+	}
+	return time.Unix(int64(sec), int64(float64(frac)/float64(i.tsUnits)*1e9))
+}
+
+// tsUnits returns the timestamp resolution of an interface from the
+// if_tsresol option, defaulting to microseconds.
+func (f *pcapngFile) tsUnits(opts []byte) uint64 {
+	for len(opts) >= 4 {
+		code := f.order.Uint16(opts)
+		n := int(f.order.Uint16(opts[2:]))
+		if code == 0 || 4+n > len(opts) {
+			break
+		}
+		if code == 9 && n >= 1 {
+			v := opts[4]
+			if v&0x80 != 0 && v&0x7f < 64 {
+				return 1 << (v & 0x7f)
+			}
+			if v < 20 {
+				return uint64(math.Pow10(int(v)))
+			}
+		}
+		opts = opts[4+(n+3)&^3:]
+	}
+	return 1e6
+}
+
+// readBlock reads the next block, handling Section Header Blocks, and
+// returns its type and body.
+func (f *pcapngFile) readBlock() (uint32, []byte, error) {
+	var hdr [8]byte
+	if _, err := io.ReadFull(f.r, hdr[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			err = ErrInvalidBlock
+		}
+		return 0, nil, err
+	}
+	if binary.BigEndian.Uint32(hdr[:]) == pcapngSHB {
+		// The byte order of a section is set by its header.
+		var bom [4]byte
+		if _, err := io.ReadFull(f.r, bom[:]); err != nil {
+			return 0, nil, ErrInvalidBlock
+		}
+		switch {
+		case binary.LittleEndian.Uint32(bom[:]) == pcapngByteOrder:
+			f.order = binary.LittleEndian
+		case binary.BigEndian.Uint32(bom[:]) == pcapngByteOrder:
+			f.order = binary.BigEndian
+		default:
+			return 0, nil, ErrUnknownFormat
+		}
+		f.ifaces = nil
+		n := int(f.order.Uint32(hdr[4:]))
+		if n < 16 || n%4 != 0 {
+			return 0, nil, ErrInvalidBlock
+		}
+		if _, err := io.CopyN(ioutil.Discard, f.r, int64(n-12)); err != nil {
+			return 0, nil, ErrInvalidBlock
+		}
+		return pcapngSHB, nil, nil
+	}
+	if f.order == nil {
+		return 0, nil, ErrUnknownFormat
+	}
+	n := int(f.order.Uint32(hdr[4:]))
+	if n < 12 || n%4 != 0 {
+		return 0, nil, ErrInvalidBlock
+	}
+	b := make([]byte, n-8)
+	if _, err := io.ReadFull(f.r, b); err != nil {
+		return 0, nil, ErrInvalidBlock
+	}
+	return f.order.Uint32(hdr[:]), b[:len(b)-4], nil
+}
+
+// Reader reads the Diameter messages of a pcap or pcapng capture, or of
+// any other PacketSource.
+//
+// TCP streams are reassembled from their segments in sequence order,
+// and SCTP user messages from the fragments of their DATA chunks.
+// Messages of streams whose start was not captured are decoded from the
+// first segment that starts with a Diameter header. IP fragments are not
+// reassembled.
+type Reader struct {
+	src     PacketSource
+	dict    *dict.Parser
+	tcp     map[flowKey]*tcpStream
+	sctp    map[sctpKey]*sctpMessage
+	pending []result
+}
+
+type result struct {
+	m   *Message
+	err error
+}
+
+// NewReader returns a Reader of the capture in r, which decodes the
+// Diameter messages with the dictionary d. If d is nil, the default
+// dictionary is used.
+func NewReader(r io.Reader, d *dict.Parser) (*Reader, error) {
+	var magic [4]byte
+	if _, err := io.ReadFull(r, magic[:]); err != nil {
+		return nil, ErrUnknownFormat
+	}
+	if binary.BigEndian.Uint32(magic[:]) == pcapngSHB {
+		src := &pcapngFile{r: io.MultiReader(bytes.NewReader(magic[:]), r)}
+		return NewPacketReader(src, d), nil
+	}
+	f, err := newPcapFile(r, magic[:])
+	if err != nil {
+		return nil, err
+	}
+	return NewPacketReader(f, d), nil
+}
+
+// NewPacketReader returns a Reader of the packets of src, which decodes
+// the Diameter messages with the dictionary d. If d is nil, the default
+// dictionary is used.
+func NewPacketReader(src PacketSource, d *dict.Parser) *Reader {
+	if d == nil {
+		d = dict.Default
+	}
+	return &Reader{
+		src:  src,
+		dict: d,
+		tcp:  make(map[flowKey]*tcpStream),
+		sctp: make(map[sctpKey]*sctpMessage),
+	}
+}
+
+// Next returns the next Diameter message of the capture, or io.EOF at
+// its end. Messages that cannot be decoded are returned as *DecodeError
+// errors, after which reading may continue.
+func (r *Reader) Next() (*Message, error) {
+	for len(r.pending) == 0 {
+		ts, linkType, data, err := r.src.ReadPacket()
+		if err != nil {
+			return nil, err
+		}
+		r.decodePacket(ts, linkType, data)
+	}
+	res := r.pending[0]
+	r.pending = r.pending[1:]
+	return res.m, res.err
+}
+
+// ReadAll reads the Diameter messages of the capture in r with the
+// dictionary d. It stops at the first error.
+func ReadAll(r io.Reader, d *dict.Parser) ([]*Message, error) {
+	rd, err := NewReader(r, d)
+	if err != nil {
+		return nil, err
+	}
+	var msgs []*Message
+	for {
+		m, err := rd.Next()
+		if err == io.EOF {
+			return msgs, nil
+		}
+		if err != nil {
+			return msgs, err
+		}
+		msgs = append(msgs, m)
+	}
+}
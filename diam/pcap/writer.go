@@ -0,0 +1,238 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package pcap
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"net"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+)
+
+// snapLen is the snapshot length of the captures written by Writer.
+const snapLen = 262144
+
+// sctpPPID is the SCTP payload protocol identifier of Diameter.
+const sctpPPID = 46
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// Writer writes synthetic pcap captures of Diameter messages, in
+// Ethernet frames. Messages are sent on TCP connections without handshake,
+// or as unfragmented SCTP DATA chunks of stream 0, depending on the
+// protocol of their flow.
+type Writer struct {
+	w    io.Writer
+	id   uint16             // IPv4 identification
+	next map[flowKey]uint32 // next TCP sequence number or SCTP TSN
+	ssn  map[flowKey]uint16 // next SCTP stream sequence number
+}
+
+// NewWriter writes the header of a pcap capture to w, and returns a
+// Writer that writes packets to it.
+func NewWriter(w io.Writer) (*Writer, error) {
+	var hdr [24]byte
+	binary.LittleEndian.PutUint32(hdr[0:], pcapMagic)
+	binary.LittleEndian.PutUint16(hdr[4:], 2)
+	binary.LittleEndian.PutUint16(hdr[6:], 4)
+	binary.LittleEndian.PutUint32(hdr[16:], snapLen)
+	binary.LittleEndian.PutUint32(hdr[20:], LinkTypeEthernet)
+	if _, err := w.Write(hdr[:]); err != nil {
+		return nil, err
+	}
+	return &Writer{
+		w:    w,
+		next: make(map[flowKey]uint32),
+		ssn:  make(map[flowKey]uint16),
+	}, nil
+}
+
+// WriteMessage writes a packet with the message m sent on the flow f at
+// the time ts.
+func (w *Writer) WriteMessage(ts time.Time, f Flow, m *diam.Message) error {
+	b, err := m.Serialize()
+	if err != nil {
+		return err
+	}
+	k := f.key()
+	seq, ok := w.next[k]
+	if !ok {
+		seq = 1
+	}
+	var payload []byte
+	switch f.Protocol {
+	case TCP:
+		payload = w.tcpSegment(f, seq, b)
+		w.next[k] = seq + uint32(len(b))
+	case SCTP:
+		payload = w.sctpPacket(f, seq, w.ssn[k], b)
+		w.next[k] = seq + 1
+		w.ssn[k]++
+	default:
+		return ErrUnsupportedProtocol
+	}
+	pkt, err := w.ipPacket(f, payload)
+	if err != nil {
+		return err
+	}
+	frame := make([]byte, 14, 14+len(pkt))
+	copy(frame, []byte{2, 0, 0, 0, 0, 2, 2, 0, 0, 0, 0, 1})
+	if f.Src.To4() != nil {
+		binary.BigEndian.PutUint16(frame[12:], etherTypeIPv4)
+	} else {
+		binary.BigEndian.PutUint16(frame[12:], etherTypeIPv6)
+	}
+	frame = append(frame, pkt...)
+	var hdr [16]byte
+	binary.LittleEndian.PutUint32(hdr[0:], uint32(ts.Unix()))
+	binary.LittleEndian.PutUint32(hdr[4:], uint32(ts.Nanosecond()/1000))
+	binary.LittleEndian.PutUint32(hdr[8:], uint32(len(frame)))
+	binary.LittleEndian.PutUint32(hdr[12:], uint32(len(frame)))
+	if _, err = w.w.Write(hdr[:]); err != nil {
+		return err
+	}
+	_, err = w.w.Write(frame)
+	return err
+}
+
+// tcpSegment returns a TCP segment with the data b, acknowledging the
+// data sent on the reverse flow. Its checksum is set by ipPacket.
+func (w *Writer) tcpSegment(f Flow, seq uint32, b []byte) []byte {
+	ack, ok := w.next[f.Reverse().key()]
+	if !ok {
+		ack = 1
+	}
+	s := make([]byte, 20, 20+len(b))
+	binary.BigEndian.PutUint16(s[0:], f.SrcPort)
+	binary.BigEndian.PutUint16(s[2:], f.DstPort)
+	binary.BigEndian.PutUint32(s[4:], seq)
+	binary.BigEndian.PutUint32(s[8:], ack)
+	s[12] = 5 << 4
+	s[13] = 0x18 // PSH, ACK
+	binary.BigEndian.PutUint16(s[14:], 65535)
+	return append(s, b...)
+}
+
+// sctpPacket returns an SCTP packet with a DATA chunk of the message b.
+func (w *Writer) sctpPacket(f Flow, tsn uint32, ssn uint16, b []byte) []byte {
+	p := make([]byte, 28, 28+len(b)+3)
+	binary.BigEndian.PutUint16(p[0:], f.SrcPort)
+	binary.BigEndian.PutUint16(p[2:], f.DstPort)
+	binary.BigEndian.PutUint32(p[4:], 1) // verification tag
+	p[13] = sctpBegin | sctpEnd
+	binary.BigEndian.PutUint16(p[14:], uint16(16+len(b)))
+	binary.BigEndian.PutUint32(p[16:], tsn)
+	binary.BigEndian.PutUint16(p[22:], ssn)
+	binary.BigEndian.PutUint32(p[24:], sctpPPID)
+	p = append(p, b...)
+	for len(p)%4 != 0 {
+		p = append(p, 0)
+	}
+	binary.LittleEndian.PutUint32(p[8:], crc32.Checksum(p, castagnoli))
+	return p
+}
+
+// ipPacket returns an IPv4 or IPv6 packet with the transport payload p,
+// and sets the TCP checksum of p.
+func (w *Writer) ipPacket(f Flow, p []byte) ([]byte, error) {
+	var pseudo []byte
+	var pkt []byte
+	if src, dst := f.Src.To4(), f.Dst.To4(); src != nil && dst != nil {
+		if 20+len(p) > 65535 {
+			return nil, ErrMessageTooLarge
+		}
+		pkt = make([]byte, 20, 20+len(p))
+		pkt[0] = 0x45
+		binary.BigEndian.PutUint16(pkt[2:], uint16(20+len(p)))
+		binary.BigEndian.PutUint16(pkt[4:], w.id)
+		w.id++
+		binary.BigEndian.PutUint16(pkt[6:], 0x4000) // DF
+		pkt[8] = 64
+		pkt[9] = byte(f.Protocol)
+		copy(pkt[12:], src)
+		copy(pkt[16:], dst)
+		binary.BigEndian.PutUint16(pkt[10:], checksum(pkt, 0))
+		pseudo = make([]byte, 12)
+		copy(pseudo, src)
+		copy(pseudo[4:], dst)
+		pseudo[9] = byte(f.Protocol)
+		binary.BigEndian.PutUint16(pseudo[10:], uint16(len(p)))
+	} else {
+		src, dst = f.Src.To16(), f.Dst.To16()
+		if src == nil || dst == nil {
+			return nil, &net.AddrError{Err: "invalid flow address", Addr: f.String()}
+		}
+		if len(p) > 65535 {
+			return nil, ErrMessageTooLarge
+		}
+		pkt = make([]byte, 40, 40+len(p))
+		pkt[0] = 0x60
+		binary.BigEndian.PutUint16(pkt[4:], uint16(len(p)))
+		pkt[6] = byte(f.Protocol)
+		pkt[7] = 64
+		copy(pkt[8:], src)
+		copy(pkt[24:], dst)
+		pseudo = make([]byte, 40)
+		copy(pseudo, src)
+		copy(pseudo[16:], dst)
+		binary.BigEndian.PutUint32(pseudo[32:], uint32(len(p)))
+		pseudo[39] = byte(f.Protocol)
+	}
+	if f.Protocol == TCP {
+		binary.BigEndian.PutUint16(p[16:], checksum(p, sum(pseudo, 0)))
+	}
+	return append(pkt, p...), nil
+}
+
+// sum adds the 16-bit words of b to the one's complement sum s.
+func sum(b []byte, s uint32) uint32 {
+	for ; len(b) >= 2; b = b[2:] {
+		s += uint32(b[0])<<8 | uint32(b[1])
+	}
+	if len(b) == 1 {
+		s += uint32(b[0]) << 8
+	}
+	return s
+}
+
+// checksum returns the Internet checksum of b, starting from the sum s.
+func checksum(b []byte, s uint32) uint16 {
+	s = sum(b, s)
+	for s > 0xffff {
+		s = s>>16 + s&0xffff
+	}
+	return ^uint16(s)
+}
+
+// WriteMessages writes a pcap capture of msgs to w. Requests are sent
+// from 127.0.0.1:49152 to 127.0.0.1:3868 over TCP, and answers the other
+// way, one millisecond apart.
+func WriteMessages(w io.Writer, msgs []*diam.Message) error {
+	pw, err := NewWriter(w)
+	if err != nil {
+		return err
+	}
+	f := Flow{
+		Protocol: TCP,
+		Src:      net.IPv4(127, 0, 0, 1),
+		SrcPort:  49152,
+		Dst:      net.IPv4(127, 0, 0, 1),
+		DstPort:  3868,
+	}
+	ts := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, m := range msgs {
+		mf := f
+		if m.Header.CommandFlags&diam.RequestFlag == 0 {
+			mf = f.Reverse()
+		}
+		if err = pw.WriteMessage(ts.Add(time.Duration(i)*time.Millisecond), mf, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrProxyHeader is returned by the reads of the connections of a
+// ProxyListener that do not start with a valid PROXY protocol header.
+var ErrProxyHeader = errors.New("diam: invalid PROXY protocol header")
+
+// defaultProxyHeaderTimeout is the time to receive the PROXY protocol
+// header when ProxyListener.HeaderTimeout is not set.
+const defaultProxyHeaderTimeout = 5 * time.Second
+
+// proxyV2Signature starts the headers of version 2 of the PROXY protocol.
+var proxyV2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+
+// ProxyListener is a listener of connections that start with the header
+// of the PROXY protocol, version 1 or 2, sent by L4 load balancers such
+// as HAProxy to forward the address of the client. The RemoteAddr and
+// LocalAddr of its connections are the addresses of the header, so that
+// servers behind load balancers log, validate and rate limit the actual
+// addresses of their peers:
+//
+//	l, _ := diam.Listen("tcp", ":3868")
+//	srv.Serve(&diam.ProxyListener{Listener: l})
+//
+// The header is read by the first Read, RemoteAddr or LocalAddr of the
+// connections, not by Accept. Connections whose header is invalid fail
+// with ErrProxyHeader. Headers of health checks, with the LOCAL command
+// or the UNKNOWN protocol, keep the addresses of the connection. See
+// https://www.haproxy.org/download/2.0/doc/proxy-protocol.txt.
+type ProxyListener struct {
+	net.Listener
+
+	// HeaderTimeout is the time to receive the header after the first
+	// read. Will default to 5 seconds.
+	HeaderTimeout time.Duration
+
+	// Optional accepts connections without header too, such as those
+	// of peers that bypass the load balancer.
+	Optional bool
+}
+
+// Accept implements the net.Listener interface.
+func (l *ProxyListener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	timeout := l.HeaderTimeout
+	if timeout == 0 {
+		timeout = defaultProxyHeaderTimeout
+	}
+	return &proxyConn{Conn: c, r: bufio.NewReader(c), timeout: timeout, optional: l.Optional}, nil
+}
+
+// proxyConn is a connection of a ProxyListener.
+type proxyConn struct {
+	net.Conn
+	r        *bufio.Reader
+	timeout  time.Duration
+	optional bool
+
+	once          sync.Once
+	err           error
+	remote, local net.Addr // addresses of the header, nil if none
+
+	mu           sync.Mutex // guards readDeadline
+	readDeadline time.Time
+}
+
+func (c *proxyConn) Read(b []byte) (int, error) {
+	c.once.Do(c.readHeader)
+	if c.err != nil {
+		return 0, c.err
+	}
+	return c.r.Read(b)
+}
+
+func (c *proxyConn) RemoteAddr() net.Addr {
+	c.once.Do(c.readHeader)
+	if c.remote != nil {
+		return c.remote
+	}
+	return c.Conn.RemoteAddr()
+}
+
+func (c *proxyConn) LocalAddr() net.Addr {
+	c.once.Do(c.readHeader)
+	if c.local != nil {
+		return c.local
+	}
+	return c.Conn.LocalAddr()
+}
+
+func (c *proxyConn) SetDeadline(t time.Time) error {
+	c.mu.Lock()
+	c.readDeadline = t
+	c.mu.Unlock()
+	return c.Conn.SetDeadline(t)
+}
+
+func (c *proxyConn) SetReadDeadline(t time.Time) error {
+	c.mu.Lock()
+	c.readDeadline = t
+	c.mu.Unlock()
+	return c.Conn.SetReadDeadline(t)
+}
+
+// readHeader reads the PROXY protocol header within the header timeout,
+// and then restores the read deadline of the connection.
+func (c *proxyConn) readHeader() {
+	c.Conn.SetReadDeadline(time.Now().Add(c.timeout))
+	c.remote, c.local, c.err = readProxyHeader(c.r, c.optional)
+	c.mu.Lock()
+	c.Conn.SetReadDeadline(c.readDeadline)
+	c.mu.Unlock()
+}
+
+// readProxyHeader reads the PROXY protocol header from r, and returns the
+// source and destination addresses it carries, if any. Streams without
+// header are accepted when optional is set.
+func readProxyHeader(r *bufio.Reader, optional bool) (src, dst net.Addr, err error) {
+	b, err := r.Peek(1)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch b[0] {
+	case 'P':
+		return readProxyV1(r)
+	case proxyV2Signature[0]:
+		return readProxyV2(r)
+	}
+	if optional {
+		return nil, nil, nil
+	}
+	return nil, nil, ErrProxyHeader
+}
+
+// readProxyV1 reads the human-readable header of version 1, such as
+// "PROXY TCP4 192.0.2.1 192.0.2.2 56324 3868\r\n".
+func readProxyV1(r *bufio.Reader) (src, dst net.Addr, err error) {
+	var line []byte
+	for len(line) < 107 {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, nil, err
+		}
+		line = append(line, b)
+		if b == '\n' {
+			break
+		}
+	}
+	if !bytes.HasSuffix(line, []byte("\r\n")) {
+		return nil, nil, ErrProxyHeader
+	}
+	f := strings.Split(string(line[:len(line)-2]), " ")
+	if len(f) < 2 || f[0] != "PROXY" {
+		return nil, nil, ErrProxyHeader
+	}
+	if f[1] == "UNKNOWN" {
+		return nil, nil, nil
+	}
+	if len(f) != 6 || f[1] != "TCP4" && f[1] != "TCP6" {
+		return nil, nil, ErrProxyHeader
+	}
+	srcIP, dstIP := net.ParseIP(f[2]), net.ParseIP(f[3])
+	srcPort, err1 := strconv.ParseUint(f[4], 10, 16)
+	dstPort, err2 := strconv.ParseUint(f[5], 10, 16)
+	if srcIP == nil || dstIP == nil || err1 != nil || err2 != nil {
+		return nil, nil, ErrProxyHeader
+	}
+	return &net.TCPAddr{IP: srcIP, Port: int(srcPort)}, &net.TCPAddr{IP: dstIP, Port: int(dstPort)}, nil
+}
+
+// readProxyV2 reads the binary header of version 2.
+func readProxyV2(r *bufio.Reader) (src, dst net.Addr, err error) {
+	hdr := make([]byte, 16)
+	if _, err = io.ReadFull(r, hdr); err != nil {
+		return nil, nil, err
+	}
+	if !bytes.Equal(hdr[:12], proxyV2Signature) || hdr[12]>>4 != 2 {
+		return nil, nil, ErrProxyHeader
+	}
+	body := make([]byte, binary.BigEndian.Uint16(hdr[14:]))
+	if _, err = io.ReadFull(r, body); err != nil {
+		return nil, nil, err
+	}
+	switch hdr[12] & 0xf {
+	case 0: // LOCAL
+		return nil, nil, nil
+	case 1: // PROXY
+	default:
+		return nil, nil, ErrProxyHeader
+	}
+	var n int
+	switch hdr[13] >> 4 {
+	case 1: // AF_INET
+		n = net.IPv4len
+	case 2: // AF_INET6
+		n = net.IPv6len
+	default: // AF_UNSPEC or AF_UNIX
+		return nil, nil, nil
+	}
+	if len(body) < 2*n+4 {
+		return nil, nil, ErrProxyHeader
+	}
+	src = &net.TCPAddr{
+		IP:   net.IP(append([]byte(nil), body[:n]...)),
+		Port: int(binary.BigEndian.Uint16(body[2*n:])),
+	}
+	dst = &net.TCPAddr{
+		IP:   net.IP(append([]byte(nil), body[n:2*n]...)),
+		Port: int(binary.BigEndian.Uint16(body[2*n+2:])),
+	}
+	return src, dst, nil
+}
+
+// proxied returns the listeners ls wrapped in ProxyListeners if
+// srv.ProxyProtocol is set, or ls otherwise.
+func (srv *Server) proxied(ls []net.Listener) []net.Listener {
+	if !srv.ProxyProtocol {
+		return ls
+	}
+	pls := make([]net.Listener, len(ls))
+	for i, l := range ls {
+		pls[i] = &ProxyListener{Listener: l}
+	}
+	return pls
+}
@@ -0,0 +1,153 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam_test
+
+import (
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+// proxyV2 returns a version 2 PROXY header with the command cmd, the
+// family fam and the address block addrs.
+func proxyV2(cmd, fam byte, addrs []byte) []byte {
+	b := []byte("\r\n\r\n\x00\r\nQUIT\n")
+	b = append(b, 0x20|cmd, fam, byte(len(addrs)>>8), byte(len(addrs)))
+	return append(b, addrs...)
+}
+
+func TestProxyListener(t *testing.T) {
+	v4 := []byte{192, 0, 2, 1, 192, 0, 2, 2, 0xdc, 0x04, 0x0f, 0x1c}
+	v6 := append(append(net.ParseIP("2001:db8::1"), net.ParseIP("2001:db8::2")...), 0xdc, 0x04, 0x0f, 0x1c)
+	testCases := []struct {
+		name     string
+		header   string
+		optional bool
+		remote   string // empty for the address of the connection
+		err      error
+	}{
+		{"v1 TCP4", "PROXY TCP4 192.0.2.1 192.0.2.2 56324 3868\r\n", false, "192.0.2.1:56324", nil},
+		{"v1 TCP6", "PROXY TCP6 2001:db8::1 2001:db8::2 56324 3868\r\n", false, "[2001:db8::1]:56324", nil},
+		{"v1 UNKNOWN", "PROXY UNKNOWN\r\n", false, "", nil},
+		{"v1 invalid", "PROXY TCP4 192.0.2.1\r\n", false, "", diam.ErrProxyHeader},
+		{"v2 TCP4", string(proxyV2(1, 0x11, v4)), false, "192.0.2.1:56324", nil},
+		{"v2 TCP6", string(proxyV2(1, 0x21, v6)), false, "[2001:db8::1]:56324", nil},
+		{"v2 TLVs", string(proxyV2(1, 0x11, append(v4, 0x04, 0x00, 0x01, 0x00))), false, "192.0.2.1:56324", nil},
+		{"v2 LOCAL", string(proxyV2(0, 0x00, nil)), false, "", nil},
+		{"missing", "", false, "", diam.ErrProxyHeader},
+		{"optional", "", true, "", nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			l, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			pl := &diam.ProxyListener{Listener: l, Optional: tc.optional}
+			defer pl.Close()
+			c, err := net.Dial("tcp", l.Addr().String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer c.Close()
+			go func() {
+				c.Write([]byte(tc.header + "\x01diameter"))
+				c.(*net.TCPConn).CloseWrite()
+			}()
+			sc, err := pl.Accept()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer sc.Close()
+			want := tc.remote
+			if want == "" {
+				want = c.LocalAddr().String()
+			}
+			if tc.err == nil && sc.RemoteAddr().String() != want {
+				t.Fatalf("Unexpected remote address. Want %s, have %s", want, sc.RemoteAddr())
+			}
+			b, err := ioutil.ReadAll(sc)
+			if err != tc.err {
+				t.Fatalf("Unexpected error. Want %v, have %v", tc.err, err)
+			}
+			if err == nil && string(b) != "\x01diameter" {
+				t.Fatalf("Unexpected data. Want %q, have %q", "\x01diameter", b)
+			}
+		})
+	}
+}
+
+func TestProxyListener_HeaderTimeout(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pl := &diam.ProxyListener{Listener: l, HeaderTimeout: 20 * time.Millisecond}
+	defer pl.Close()
+	c, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	sc, err := pl.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sc.Close()
+	_, err = sc.Read(make([]byte, 1))
+	if ne, ok := err.(net.Error); !ok || !ne.Timeout() {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestServer_ProxyProtocol(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	remote := make(chan net.Addr, 1)
+	smux := diam.NewServeMux()
+	smux.HandleFunc("ACR", func(c diam.Conn, m *diam.Message) {
+		remote <- c.RemoteAddr()
+		a := m.Answer(diam.Success)
+		a.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("srv"))
+		a.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity("test"))
+		a.WriteTo(c)
+	})
+	srv := &diam.Server{Handler: smux, Dict: dict.Default}
+	go srv.Serve(&diam.ProxyListener{Listener: l})
+	defer srv.Shutdown(context.Background())
+
+	c, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.Write([]byte("PROXY TCP4 192.0.2.1 192.0.2.2 56324 3868\r\n")); err != nil {
+		t.Fatal(err)
+	}
+	cli, err := diam.NewConn(c, c.RemoteAddr().String(), nil, dict.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cli.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	m := diam.NewRequest(diam.Accounting, 3, dict.Default)
+	m.NewAVP(avp.SessionID, avp.Mbit, 0, datatype.UTF8String("sess"))
+	if _, err = diam.SendRequest(ctx, cli, m); err != nil {
+		t.Fatal(err)
+	}
+	if addr := (<-remote).String(); addr != "192.0.2.1:56324" {
+		t.Fatalf("Unexpected remote address. Want 192.0.2.1:56324, have %s", addr)
+	}
+}
@@ -6,7 +6,9 @@ package diam
 
 import (
 	"errors"
+	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/omnicate/go-diameter/v4/diam/avp"
@@ -57,7 +59,8 @@ func isEmptyValue(v reflect.Value) bool {
 	return false
 }
 
-// Marshal encodes struct into AVPs
+// Marshal encodes struct into AVPs, replacing the AVPs of the message.
+// See Unmarshal for the supported field types.
 func (m *Message) Marshal(src interface{}) error {
 	v := reflect.ValueOf(src)
 	if v.Kind() != reflect.Ptr {
@@ -72,6 +75,140 @@ func (m *Message) Marshal(src interface{}) error {
 	return nil
 }
 
+// Marshal returns a new message with the AVPs of the struct pointed to
+// by src, encoded as with Message.Marshal.
+//
+// The command of the message is set by the diam tag of a field of the
+// struct, usually its Header:
+//
+//	type CCR struct {
+//		Header    diam.Header `diam:"CCR,app=4,proxiable"`
+//		SessionID string      `avp:"Session-Id"`
+//		...
+//	}
+//
+// The tag holds the short name of the command, followed by R for
+// requests or A for answers as in ServeMux.Handle, then the optional
+// Application-Id and proxiable flag. The command is looked up in the
+// default dictionary. If the tagged field is a Header or *Header with
+// a Hop-by-Hop or End-to-End Identifier set, they are used as the
+// identifiers of the message. Message.Unmarshal fills such a field with
+// the header of the message being decoded.
+func Marshal(src interface{}) (*Message, error) {
+	v := reflect.ValueOf(src)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil, errors.New("src is not a pointer to struct")
+	}
+	f, tag, ok := headerField(v.Elem())
+	if !ok {
+		return nil, errors.New("src has no field with a diam tag")
+	}
+	code, flags, appid, err := parseDiamTag(tag, dict.Default)
+	if err != nil {
+		return nil, err
+	}
+	var hopbyhop, endtoend uint32
+	if h, ok := headerValue(f); ok {
+		hopbyhop, endtoend = h.HopByHopID, h.EndToEndID
+	}
+	m := NewMessage(code, flags, appid, hopbyhop, endtoend, nil)
+	if err = m.Marshal(src); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// headerField returns the first field of the struct v with a diam tag.
+func headerField(v reflect.Value) (reflect.Value, string, bool) {
+	for n := 0; n < v.NumField(); n++ {
+		if tag, ok := v.Type().Field(n).Tag.Lookup("diam"); ok {
+			return v.Field(n), tag, true
+		}
+	}
+	return reflect.Value{}, "", false
+}
+
+// headerValue returns the value of a Header or *Header field.
+func headerValue(f reflect.Value) (*Header, bool) {
+	if !f.CanInterface() {
+		return nil, false
+	}
+	switch h := f.Interface().(type) {
+	case Header:
+		return &h, true
+	case *Header:
+		return h, h != nil
+	}
+	return nil, false
+}
+
+// setHeader copies the header of m to the field of dst with a diam tag,
+// if it is a Header or *Header.
+func setHeader(dst reflect.Value, m *Message) {
+	base := reflect.Indirect(dst)
+	if base.Kind() != reflect.Struct || m.Header == nil {
+		return
+	}
+	f, _, ok := headerField(base)
+	if !ok || !f.CanSet() {
+		return
+	}
+	h := *m.Header
+	switch f.Type() {
+	case reflect.TypeOf(h):
+		f.Set(reflect.ValueOf(h))
+	case reflect.TypeOf(&h):
+		f.Set(reflect.ValueOf(&h))
+	}
+}
+
+// parseDiamTag returns the command code, flags and Application-Id of
+// the diam tag of a struct, e.g. "CCR,app=4".
+func parseDiamTag(tag string, d *dict.Parser) (code uint32, flags uint8, appid uint32, err error) {
+	opts := strings.Split(tag, ",")
+	name := opts[0]
+	for _, opt := range opts[1:] {
+		switch {
+		case strings.HasPrefix(opt, "app="):
+			n, err := strconv.ParseUint(opt[4:], 10, 32)
+			if err != nil {
+				return 0, 0, 0, fmt.Errorf("invalid Application-Id in diam tag %q", tag)
+			}
+			appid = uint32(n)
+		case opt == "proxiable":
+			flags |= ProxiableFlag
+		default:
+			return 0, 0, 0, fmt.Errorf("unknown option %q in diam tag %q", opt, tag)
+		}
+	}
+	if len(name) < 2 {
+		return 0, 0, 0, fmt.Errorf("invalid command in diam tag %q", tag)
+	}
+	switch name[len(name)-1] {
+	case 'R':
+		flags |= RequestFlag
+	case 'A':
+	default:
+		return 0, 0, 0, fmt.Errorf("invalid command in diam tag %q, want R or A suffix", tag)
+	}
+	short := name[:len(name)-1]
+	// Several dictionaries may define the same application.
+	apps := d.Apps()
+	for _, id := range []uint32{appid, 0} {
+		for _, app := range apps {
+			if app.ID != id {
+				continue
+			}
+			for _, cmd := range app.Command {
+				if cmd.Short == short {
+					return cmd.Code, flags, appid, nil
+				}
+			}
+		}
+	}
+	return 0, 0, 0, fmt.Errorf("command %s not found in application %d", name, appid)
+}
+
 func marshalStruct(m *Message, field reflect.Value) (error, []*AVP) {
 	var err error
 	var dictAVP *dict.AVP
@@ -164,6 +301,15 @@ BASIC_TYPE:
 	case datatype.DiameterURIType:
 		t = reflect.TypeOf((*datatype.DiameterURI)(nil)).Elem()
 	case datatype.EnumeratedType:
+		if field.Kind() == reflect.String {
+			// Enumerated values may be given by name.
+			e, err := enumByName(fieldAVP, field.String())
+			if err != nil {
+				return err, nil
+			}
+			data = e
+			break
+		}
 		t = reflect.TypeOf((*datatype.Enumerated)(nil)).Elem()
 	case datatype.Float32Type:
 		t = reflect.TypeOf((*datatype.Float32)(nil)).Elem()
@@ -339,6 +485,11 @@ BASIC_TYPE:
 // usually added to responses, such as Origin-State-Id are better decoded to
 // just AVP or *AVP, making it easier to re-use them in the answer.
 //
+// Enumerated AVPs decoded to a string are set to the name of their item
+// in the dictionary, and Message.Marshal and Marshal accept such names.
+// A Header or *Header field with a diam tag, as used by Marshal, is set
+// to a copy of the header of the message.
+//
 // Note that decoding values to *AVP is much faster and more efficient than
 // decoding to AVP or the native Go types.
 func (m *Message) Unmarshal(dst interface{}) error {
@@ -346,7 +497,25 @@ func (m *Message) Unmarshal(dst interface{}) error {
 	if v.Kind() != reflect.Ptr {
 		return errors.New("dst is not a pointer to struct")
 	}
-	return scanStruct(m, v, m.AVP)
+	if err := m.Decode(); err != nil {
+		return err
+	}
+	if err := scanStruct(m, v, m.AVP); err != nil {
+		return err
+	}
+	setHeader(v, m)
+	return nil
+}
+
+// enumByName returns the value of the item of the Enumerated AVP with
+// the given name.
+func enumByName(a *dict.AVP, name string) (datatype.Enumerated, error) {
+	for _, item := range a.Data.Enum {
+		if item.Name == name {
+			return datatype.Enumerated(item.Code), nil
+		}
+	}
+	return 0, fmt.Errorf("%s AVP has no item %q", a.Name, name)
 }
 
 // newIndex returns a map of AVPs indexed by their code.
@@ -446,6 +615,15 @@ func unmarshal(m *Message, f reflect.Value, avps []*AVP) {
 		}
 
 	default:
+		// Enumerated values are decoded to the name of their item.
+		if e, ok := avps[0].Data.(datatype.Enumerated); ok && f.Kind() == reflect.String {
+			if item, err := m.Dictionary().Enum(m.Header.ApplicationID, avps[0].Code, int32(e)); err == nil {
+				f.SetString(item.Name)
+			} else {
+				f.SetString(strconv.Itoa(int(e)))
+			}
+			break
+		}
 		// Test for AVP.Data (e.g. format.UTF8String, string)
 		dv := reflect.ValueOf(avps[0].Data)
 		if dv.Type().ConvertibleTo(fieldType) {
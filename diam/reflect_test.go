@@ -323,6 +323,118 @@ func TestUnmarshalCER(t *testing.T) {
 	}
 }
 
+type testMSCC struct {
+	RatingGroup *uint32 `avp:"Rating-Group,omitempty"`
+	Granted     *struct {
+		Time uint32 `avp:"CC-Time"`
+	} `avp:"Granted-Service-Unit,omitempty"`
+}
+
+type testCCR struct {
+	Header        Header              `diam:"CCR,app=4,proxiable"`
+	SessionID     datatype.UTF8String `avp:"Session-Id"`
+	OriginHost    string              `avp:"Origin-Host"`
+	RequestType   string              `avp:"CC-Request-Type"`
+	RequestNumber uint32              `avp:"CC-Request-Number"`
+	UserName      string              `avp:"User-Name,omitempty"`
+	EventTime     time.Time           `avp:"Event-Timestamp"`
+	MSCC          []*testMSCC         `avp:"Multiple-Services-Credit-Control"`
+}
+
+func TestMarshal(t *testing.T) {
+	rg := uint32(10)
+	ts := time.Unix(1577836800, 0).UTC()
+	src := &testCCR{
+		SessionID:     "cli;1;2",
+		OriginHost:    "cli",
+		RequestType:   "UPDATE_REQUEST",
+		RequestNumber: 1,
+		EventTime:     ts,
+		MSCC: []*testMSCC{
+			{RatingGroup: &rg},
+			{Granted: &struct {
+				Time uint32 `avp:"CC-Time"`
+			}{60}},
+		},
+	}
+	m, err := Marshal(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := m.Header
+	if h.CommandCode != CreditControl || h.ApplicationID != 4 || h.CommandFlags != RequestFlag|ProxiableFlag {
+		t.Fatalf("Unexpected header: %s", h)
+	}
+	if len(m.AVP) != 7 { // User-Name is omitted
+		t.Fatalf("Unexpected # of AVPs. Want 7, have %d", len(m.AVP))
+	}
+	if a, err := m.FindAVP("CC-Request-Type", 0); err != nil {
+		t.Fatal(err)
+	} else if a.Data != datatype.Enumerated(2) {
+		t.Fatalf("Unexpected CC-Request-Type: %s", a.Data)
+	}
+	b, err := m.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err = ReadMessage(bytes.NewReader(b), dict.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dst testCCR
+	if err = m.Unmarshal(&dst); err != nil {
+		t.Fatal(err)
+	}
+	switch {
+	case dst.Header.HopByHopID != h.HopByHopID || dst.Header.CommandCode != CreditControl:
+		t.Fatalf("Unexpected header: %s", &dst.Header)
+	case dst.SessionID != src.SessionID || dst.RequestType != "UPDATE_REQUEST" || dst.RequestNumber != 1:
+		t.Fatalf("Unexpected CCR: %+v", dst)
+	case !dst.EventTime.Equal(ts):
+		t.Fatalf("Unexpected Event-Timestamp: %s", dst.EventTime)
+	case len(dst.MSCC) != 2 || dst.MSCC[0].RatingGroup == nil || *dst.MSCC[0].RatingGroup != 10 ||
+		dst.MSCC[1].Granted == nil || dst.MSCC[1].Granted.Time != 60:
+		t.Fatalf("Unexpected Multiple-Services-Credit-Control: %+v", dst.MSCC)
+	}
+
+	// Answers keep the identifiers of the request.
+	reqHeader := dst.Header
+	cca := &struct {
+		Header     *Header `diam:"CCA,app=4"`
+		ResultCode uint32  `avp:"Result-Code"`
+	}{Header: &reqHeader, ResultCode: Success}
+	a, err := Marshal(cca)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Header.CommandFlags != 0 || a.Header.HopByHopID != h.HopByHopID || a.Header.EndToEndID != h.EndToEndID {
+		t.Fatalf("Unexpected header: %s", a.Header)
+	}
+}
+
+func TestMarshalErrors(t *testing.T) {
+	for _, v := range []interface{}{
+		testCCR{},
+		&struct {
+			SessionID string `avp:"Session-Id"`
+		}{},
+		&struct {
+			Header Header `diam:"CC,app=4"`
+		}{},
+		&struct {
+			Header Header `diam:"XXR,app=4"`
+		}{},
+		&struct {
+			Header Header `diam:"CCR,app=4"`
+			Type   string `avp:"CC-Request-Type"`
+		}{Type: "UNKNOWN"},
+	} {
+		if _, err := Marshal(v); err == nil {
+			t.Fatalf("Unexpected success marshaling %#v", v)
+		}
+	}
+}
+
 func BenchmarkUnmarshal(b *testing.B) {
 	msg, err := ReadMessage(bytes.NewReader(testMessage), dict.Default)
 	if err != nil {
@@ -0,0 +1,120 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+import (
+	"errors"
+	"math/rand"
+
+	"golang.org/x/net/context"
+)
+
+// ErrConnClosed is returned by SendRequest when the connection is closed
+// before the answer is received.
+var ErrConnClosed = errors.New("connection closed before the answer was received")
+
+// ErrSendRequestUnsupported is returned by SendRequest when the Conn
+// does not implement the RequestSender interface.
+var ErrSendRequestUnsupported = errors.New("connection does not support SendRequest")
+
+// The RequestSender interface is implemented by Conns which allow
+// sending requests and waiting for their answers.
+//
+// Answers to requests sent by SendRequest are returned to the caller
+// and are not passed to the connection's Handler.
+type RequestSender interface {
+	// SendRequest writes the request m with a new Hop-by-Hop identifier,
+	// and an End-to-End identifier if it has none, and waits for its
+	// answer until ctx is done.
+	SendRequest(ctx context.Context, m *Message) (*Message, error)
+}
+
+// SendRequest sends the request m on c and waits for its answer until
+// ctx is done. The connection must implement RequestSender.
+func SendRequest(ctx context.Context, c Conn, m *Message) (*Message, error) {
+	rs, ok := c.(RequestSender)
+	if !ok {
+		return nil, ErrSendRequestUnsupported
+	}
+	return rs.SendRequest(ctx, m)
+}
+
+// SendRequest implements the RequestSender interface.
+func (w *response) SendRequest(ctx context.Context, m *Message) (*Message, error) {
+	if m.Header.EndToEndID == 0 {
+		m.Header.EndToEndID = rand.Uint32()
+	}
+	id, answerc, err := w.conn.addPending()
+	if err != nil {
+		return nil, err
+	}
+	defer w.conn.removePending(id)
+	m.Header.HopByHopID = id
+	if _, err = m.WriteTo(w); err != nil {
+		return nil, err
+	}
+	select {
+	case a, ok := <-answerc:
+		if !ok {
+			return nil, ErrConnClosed
+		}
+		return a, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// addPending registers a waiter for the answer to a request, and returns
+// the Hop-by-Hop identifier to send it with.
+func (c *conn) addPending() (uint32, chan *Message, error) {
+	c.pmu.Lock()
+	defer c.pmu.Unlock()
+	if c.pendingClosed {
+		return 0, nil, ErrConnClosed
+	}
+	if c.pending == nil {
+		c.pending = make(map[uint32]chan *Message)
+	}
+	id := rand.Uint32()
+	for _, exists := c.pending[id]; exists; _, exists = c.pending[id] {
+		id = rand.Uint32()
+	}
+	answerc := make(chan *Message, 1)
+	c.pending[id] = answerc
+	return id, answerc, nil
+}
+
+func (c *conn) removePending(id uint32) {
+	c.pmu.Lock()
+	delete(c.pending, id)
+	c.pmu.Unlock()
+}
+
+// deliverAnswer hands the answer m to the request it belongs to, and
+// reports whether there was one.
+func (c *conn) deliverAnswer(m *Message) bool {
+	if m.Header.CommandFlags&RequestFlag == RequestFlag {
+		return false
+	}
+	c.pmu.Lock()
+	answerc, ok := c.pending[m.Header.HopByHopID]
+	delete(c.pending, m.Header.HopByHopID)
+	c.pmu.Unlock()
+	if ok {
+		answerc <- m
+	}
+	return ok
+}
+
+// closePending releases the waiters of all pending requests.
+func (c *conn) closePending() {
+	c.pmu.Lock()
+	c.pendingClosed = true
+	for id, answerc := range c.pending {
+		close(answerc)
+		delete(c.pending, id)
+	}
+	c.pmu.Unlock()
+}
@@ -0,0 +1,95 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam_test
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+)
+
+func newDWR() *diam.Message {
+	m := diam.NewRequest(diam.DeviceWatchdog, 0, nil)
+	m.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("cli"))
+	m.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity("localhost"))
+	return m
+}
+
+func TestSendRequest(t *testing.T) {
+	smux := diam.NewServeMux()
+	smux.HandleFunc("DWR", func(c diam.Conn, m *diam.Message) {
+		a := m.Answer(diam.Success)
+		a.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("srv"))
+		a.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity("localhost"))
+		a.WriteTo(c)
+	})
+	srv := diamtest.NewServer(smux, nil)
+	defer srv.Close()
+
+	cmux := diam.NewServeMux()
+	cmux.HandleFunc("DWA", func(c diam.Conn, m *diam.Message) {
+		t.Error("Answer was passed to the handler")
+	})
+	cli, err := diam.Dial(srv.Addr, cmux, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	req := newDWR()
+	a, err := diam.SendRequest(ctx, cli, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Header.HopByHopID != req.Header.HopByHopID || a.Header.EndToEndID != req.Header.EndToEndID {
+		t.Fatalf("Unexpected answer: %s", a)
+	}
+}
+
+func TestSendRequest_Timeout(t *testing.T) {
+	smux := diam.NewServeMux()
+	smux.HandleFunc("DWR", func(c diam.Conn, m *diam.Message) {})
+	srv := diamtest.NewServer(smux, nil)
+	defer srv.Close()
+	cli, err := diam.Dial(srv.Addr, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err = diam.SendRequest(ctx, cli, newDWR()); err != context.DeadlineExceeded {
+		t.Fatalf("Unexpected error. Want %v, have %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestSendRequest_ConnClosed(t *testing.T) {
+	smux := diam.NewServeMux()
+	smux.HandleFunc("DWR", func(c diam.Conn, m *diam.Message) {
+		c.Close()
+	})
+	srv := diamtest.NewServer(smux, nil)
+	defer srv.Close()
+	cli, err := diam.Dial(srv.Addr, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err = diam.SendRequest(ctx, cli, newDWR()); err != diam.ErrConnClosed {
+		t.Fatalf("Unexpected error. Want %v, have %v", diam.ErrConnClosed, err)
+	}
+}
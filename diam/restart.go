@@ -0,0 +1,237 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrReusePortUnsupported is returned by ListenReusePort, and by
+	// ListenAndServe when Server.ReusePort is set, for networks or
+	// platforms that do not support SO_REUSEPORT.
+	ErrReusePortUnsupported = errors.New("diam: SO_REUSEPORT not supported")
+
+	// ErrNoListeners is returned by Server.StartProcess when the server
+	// has no listeners to hand off.
+	ErrNoListeners = errors.New("diam: no listeners to hand off")
+)
+
+// listenFDsEnv is the environment variable that describes the listeners
+// inherited from the parent process, see Server.StartProcess.
+const listenFDsEnv = "DIAM_LISTEN_FDS"
+
+// A handoff is a listener opened by ListenAndServe, which is handed off
+// to the new process of a graceful restart.
+type handoff struct {
+	network, addr string
+	l             net.Listener
+}
+
+// filer is implemented by the listeners that can be handed off, such as
+// *net.TCPListener.
+type filer interface {
+	File() (*os.File, error)
+}
+
+// inherited holds the listeners inherited from the parent process, by
+// network and address, until ListenAndServe takes them.
+var inherited struct {
+	once sync.Once
+	mu   sync.Mutex
+	ls   map[string][]net.Listener
+	err  error
+}
+
+// inheritedListener returns a listener on the network address inherited
+// from the parent process, if any.
+func inheritedListener(network, addr string) (net.Listener, error) {
+	inherited.once.Do(func() {
+		spec := os.Getenv(listenFDsEnv)
+		if spec == "" {
+			return
+		}
+		os.Unsetenv(listenFDsEnv)
+		n := len(strings.Split(spec, ","))
+		files := make([]*os.File, n)
+		for i := range files {
+			fd := uintptr(3 + i)
+			files[i] = os.NewFile(fd, fmt.Sprintf("listener-%d", fd))
+		}
+		inherited.ls, inherited.err = inheritListeners(spec, files)
+	})
+	inherited.mu.Lock()
+	defer inherited.mu.Unlock()
+	if inherited.err != nil {
+		return nil, inherited.err
+	}
+	key := network + " " + addr
+	ls := inherited.ls[key]
+	if len(ls) == 0 {
+		return nil, nil
+	}
+	inherited.ls[key] = ls[1:]
+	return ls[0], nil
+}
+
+// inheritListeners returns the listeners of the files described by spec,
+// a comma separated list of network and address pairs, by network and
+// address. The files are closed.
+func inheritListeners(spec string, files []*os.File) (map[string][]net.Listener, error) {
+	ls := make(map[string][]net.Listener)
+	for i, key := range strings.Split(spec, ",") {
+		if i >= len(files) {
+			return nil, fmt.Errorf("Invalid %s: %q", listenFDsEnv, spec)
+		}
+		l, err := net.FileListener(files[i])
+		files[i].Close()
+		if err != nil {
+			return nil, err
+		}
+		ls[key] = append(ls[key], l)
+	}
+	return ls, nil
+}
+
+// listen returns the listeners ListenAndServe and ListenAndServeTLS
+// serve on: those inherited from the parent process for the network
+// address, or else new ones created by listen, or by ListenReusePort if
+// srv.ReusePort is set.
+func (srv *Server) listen(network, addr string, listen func(network, addr string) (net.Listener, error)) ([]net.Listener, error) {
+	n := 1
+	if srv.ReusePort > 1 {
+		n = srv.ReusePort
+	}
+	ls := make([]net.Listener, 0, n)
+	closeAll := func() {
+		for _, l := range ls {
+			l.Close()
+		}
+	}
+	for len(ls) < n {
+		l, err := inheritedListener(network, addr)
+		if err == nil && l == nil {
+			if t, _ := transportOf(addr); t == nil && srv.ReusePort > 0 {
+				l, err = ListenReusePort(network, addr)
+			} else {
+				l, err = listen(network, addr)
+			}
+		}
+		if err != nil {
+			closeAll()
+			return nil, err
+		}
+		ls = append(ls, l)
+	}
+	srv.mu.Lock()
+	for _, l := range ls {
+		if _, ok := l.(filer); ok {
+			srv.handoffs = append(srv.handoffs, handoff{network, addr, l})
+		}
+	}
+	srv.mu.Unlock()
+	return ls, nil
+}
+
+// dropHandoffs removes the listeners ls, which are closed, from the
+// listeners handed off by StartProcess.
+func (srv *Server) dropHandoffs(ls []net.Listener) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	handoffs := srv.handoffs[:0]
+	for _, h := range srv.handoffs {
+		closed := false
+		for _, l := range ls {
+			closed = closed || h.l == l
+		}
+		if !closed {
+			handoffs = append(handoffs, h)
+		}
+	}
+	srv.handoffs = handoffs
+}
+
+// serveAll serves on the listeners ls, and returns the error of the
+// first Serve that returns, after closing the other listeners.
+func (srv *Server) serveAll(ls []net.Listener) error {
+	if len(ls) == 1 {
+		return srv.Serve(ls[0])
+	}
+	errc := make(chan error, len(ls))
+	for _, l := range ls {
+		go func(l net.Listener) { errc <- srv.Serve(l) }(l)
+	}
+	err := <-errc
+	for _, l := range ls {
+		l.Close()
+	}
+	for i := 1; i < len(ls); i++ {
+		<-errc
+	}
+	return err
+}
+
+// StartProcess starts a new instance of the running program, with the
+// same arguments and environment, that inherits the listeners opened by
+// ListenAndServe and ListenAndServeTLS: its servers listening on the same
+// network addresses accept the new connections, on the same sockets, so
+// that no connection attempt is refused during the restart.
+//
+// It is typically called on SIGUSR2 to upgrade the program, followed by
+// Shutdown, which disconnects the peers of the connections of the server
+// gracefully after their pending requests are answered. Peers then
+// reconnect to the new process:
+//
+//	sig := make(chan os.Signal, 1)
+//	signal.Notify(sig, syscall.SIGUSR2)
+//	<-sig
+//	if _, err := srv.StartProcess(); err == nil {
+//		srv.Shutdown(ctx)
+//	}
+//
+// Only TCP listeners are handed off.
+func (srv *Server) StartProcess() (*os.Process, error) {
+	srv.mu.Lock()
+	handoffs := append([]handoff(nil), srv.handoffs...)
+	srv.mu.Unlock()
+	if len(handoffs) == 0 {
+		return nil, ErrNoListeners
+	}
+	keys := make([]string, 0, len(handoffs))
+	files := make([]*os.File, 0, len(handoffs))
+	defer func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+	for _, h := range handoffs {
+		f, err := h.l.(filer).File()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, h.network+" "+h.addr)
+		files = append(files, f)
+	}
+	path, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.Command(path, os.Args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), listenFDsEnv+"="+strings.Join(keys, ","))
+	cmd.ExtraFiles = files
+	if err = cmd.Start(); err != nil {
+		return nil, err
+	}
+	return cmd.Process, nil
+}
@@ -0,0 +1,112 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+import (
+	"net"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+func TestListenReusePort(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "solaris" || runtime.GOOS == "aix" {
+		t.Skip("SO_REUSEPORT not supported")
+	}
+	l1, err := ListenReusePort("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l1.Close()
+	l2, err := ListenReusePort("tcp", l1.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l2.Close()
+	if _, err = ListenReusePort("sctp", "127.0.0.1:0"); err != ErrReusePortUnsupported {
+		t.Fatalf("Unexpected error. Want %v, have %v", ErrReusePortUnsupported, err)
+	}
+}
+
+func TestServer_ReusePort(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "solaris" || runtime.GOOS == "aix" {
+		t.Skip("SO_REUSEPORT not supported")
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+	srv := &Server{Addr: addr, Dict: dict.Default, ReusePort: 2}
+	errc := make(chan error, 1)
+	go func() { errc <- srv.ListenAndServe() }()
+	var c Conn
+	for i := 0; i < 100; i++ {
+		if c, err = Dial(addr, nil, dict.Default); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Close()
+	srv.mu.Lock()
+	n := len(srv.handoffs)
+	srv.mu.Unlock()
+	if n != 2 {
+		t.Fatalf("Unexpected number of listeners. Want 2, have %d", n)
+	}
+	srv.Shutdown(context.Background())
+	select {
+	case err := <-errc:
+		if err != ErrServerClosed {
+			t.Fatalf("Unexpected error. Want %v, have %v", ErrServerClosed, err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("ListenAndServe did not return")
+	}
+}
+
+func TestInheritListeners(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	f, err := l.(*net.TCPListener).File()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := "tcp " + l.Addr().String()
+	ls, err := inheritListeners(key, []*os.File{f})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ls[key]) != 1 {
+		t.Fatalf("Unexpected listeners: %v", ls)
+	}
+	inherited := ls[key][0]
+	defer inherited.Close()
+	if inherited.Addr().String() != l.Addr().String() {
+		t.Fatalf("Unexpected address. Want %s, have %s", l.Addr(), inherited.Addr())
+	}
+	if _, err = inheritListeners(key+","+key, []*os.File{}); err == nil {
+		t.Fatal("Missing files were not detected")
+	}
+}
+
+func TestServer_StartProcess_NoListeners(t *testing.T) {
+	srv := &Server{}
+	if _, err := srv.StartProcess(); err != ErrNoListeners {
+		t.Fatalf("Unexpected error. Want %v, have %v", ErrNoListeners, err)
+	}
+}
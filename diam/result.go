@@ -0,0 +1,67 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+import (
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// NewExperimentalResult creates an Experimental-Result grouped AVP with
+// the Vendor-Id vendorID and the Experimental-Result-Code resultCode.
+func NewExperimentalResult(vendorID, resultCode uint32) *AVP {
+	return NewAVP(avp.ExperimentalResult, avp.Mbit, 0, &GroupedAVP{
+		AVP: []*AVP{
+			NewAVP(avp.VendorID, avp.Mbit, 0, datatype.Unsigned32(vendorID)),
+			NewAVP(avp.ExperimentalResultCode, avp.Mbit, 0, datatype.Unsigned32(resultCode)),
+		},
+	})
+}
+
+// ExperimentalResult returns the Vendor-Id and Experimental-Result-Code
+// of the Experimental-Result AVP of the answer m, and whether it has one.
+func (m *Message) ExperimentalResult() (vendorID, resultCode uint32, ok bool) {
+	for _, a := range m.AVP {
+		if a.Code != avp.ExperimentalResult || a.VendorID != 0 {
+			continue
+		}
+		g, isGroup := a.Data.(*GroupedAVP)
+		if !isGroup {
+			continue
+		}
+		for _, ga := range g.AVP {
+			v, isUint := ga.Data.(datatype.Unsigned32)
+			if !isUint {
+				continue
+			}
+			switch ga.Code {
+			case avp.VendorID:
+				vendorID = uint32(v)
+			case avp.ExperimentalResultCode:
+				resultCode, ok = uint32(v), true
+			}
+		}
+		if ok {
+			return vendorID, resultCode, true
+		}
+	}
+	return 0, 0, false
+}
+
+// ResultCode returns the Result-Code of the answer m, or else its
+// Experimental-Result-Code, and whether it has either. The vendor of
+// experimental results is returned by Message.ExperimentalResult.
+func ResultCode(m *Message) (uint32, bool) {
+	for _, a := range m.AVP {
+		if a.Code != avp.ResultCode || a.VendorID != 0 {
+			continue
+		}
+		if v, ok := a.Data.(datatype.Unsigned32); ok {
+			return uint32(v), true
+		}
+	}
+	_, code, ok := m.ExperimentalResult()
+	return code, ok
+}
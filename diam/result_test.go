@@ -0,0 +1,45 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+func TestResultCode(t *testing.T) {
+	req := NewRequest(CreditControl, 4, dict.Default)
+	req.NewAVP(avp.SessionID, avp.Mbit, 0, datatype.UTF8String("sess"))
+
+	if _, ok := ResultCode(req.answer()); ok {
+		t.Fatal("Unexpected result in answer without result")
+	}
+	if code, ok := ResultCode(req.Answer(Success)); !ok || code != Success {
+		t.Fatalf("Unexpected result. Want %d, have %d", Success, code)
+	}
+
+	a := req.ExperimentalAnswer(5420, 10415)
+	b, err := a.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a, err = ReadMessage(bytes.NewReader(b), dict.Default); err != nil {
+		t.Fatal(err)
+	}
+	if code, ok := ResultCode(a); !ok || code != 5420 {
+		t.Fatalf("Unexpected experimental result. Want 5420, have %d", code)
+	}
+	vendorID, code, ok := a.ExperimentalResult()
+	if !ok || vendorID != 10415 || code != 5420 {
+		t.Fatalf("Unexpected Experimental-Result: vendor %d, code %d", vendorID, code)
+	}
+	if _, _, ok = req.Answer(Success).ExperimentalResult(); ok {
+		t.Fatal("Unexpected Experimental-Result in answer with Result-Code")
+	}
+}
@@ -0,0 +1,15 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package diam
+
+import "net"
+
+// ListenReusePort returns ErrReusePortUnsupported: SO_REUSEPORT is not
+// supported on this platform.
+func ListenReusePort(network, address string) (net.Listener, error) {
+	return nil, ErrReusePortUnsupported
+}
@@ -0,0 +1,42 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package diam
+
+import (
+	"net"
+	"strings"
+	"syscall"
+
+	"golang.org/x/net/context"
+	"golang.org/x/sys/unix"
+)
+
+// ListenReusePort listens on the TCP network address like Listen, with
+// the SO_REUSEPORT socket option set so that several listeners, of the
+// same process or of several processes, share the address. The kernel
+// balances the connections between them.
+//
+// It returns ErrReusePortUnsupported for other networks than TCP.
+func ListenReusePort(network, address string) (net.Listener, error) {
+	if !strings.HasPrefix(network, "tcp") {
+		return nil, ErrReusePortUnsupported
+	}
+	lc := net.ListenConfig{Control: setReusePort}
+	return lc.Listen(context.Background(), network, address)
+}
+
+// setReusePort sets the SO_REUSEPORT socket option.
+func setReusePort(network, address string, c syscall.RawConn) error {
+	var err error
+	cerr := c.Control(func(fd uintptr) {
+		err = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_REUSEPORT, 1)
+	})
+	if cerr != nil {
+		return cerr
+	}
+	return err
+}
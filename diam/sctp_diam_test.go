@@ -8,10 +8,26 @@
 package diam_test
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/ishidawataru/sctp"
+	"golang.org/x/net/context"
+
 	"github.com/omnicate/go-diameter/v4/diam"
 	"github.com/omnicate/go-diameter/v4/diam/diamtest"
 )
@@ -84,3 +100,218 @@ func TestCapabilitiesExchangeSCTP_TLS(t *testing.T) {
 		t.Fatal("Timed out: no CER or CEA received")
 	}
 }
+
+func TestCapabilitiesExchangeSCTPMultihome(t *testing.T) {
+	errc := make(chan error, 1)
+
+	smux := diam.NewServeMux()
+	smux.Handle("CER", handleCER(errc, false))
+
+	addrs := []net.IP{net.IPv4(127, 0, 0, 1), net.IPv4(127, 0, 0, 2)}
+	lis, err := diam.ListenSCTPMultihome("sctp4", addrs, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+	srv := &diam.Server{Handler: smux}
+	go srv.Serve(lis)
+
+	wait := make(chan struct{})
+	cmux := diam.NewServeMux()
+	cmux.Handle("CEA", handleCEA(errc, wait))
+
+	port := lis.Addr().(*sctp.SCTPAddr).Port
+	cli, err := diam.DialSCTPMultihome("sctp4", addrs, port, cmux, nil,
+		&diam.SCTPMultihomeDialer{
+			PrimaryAddr:           addrs[1],
+			PeerAddrChangeHandler: func(*diam.SCTPConn, *diam.SCTPPeerAddrChange) {},
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cli.Close()
+
+	prim, err := cli.Connection().(*diam.SCTPConn).PrimaryAddr()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !prim.Equal(addrs[1]) {
+		t.Fatalf("Unexpected primary address. Want %s, have %s", addrs[1], prim)
+	}
+
+	sendCER(cli)
+
+	select {
+	case <-wait:
+	case err := <-errc:
+		t.Fatal(err)
+	case <-time.After(time.Second):
+		t.Fatal("Timed out: no CER or CEA received")
+	}
+}
+
+// newTestCertificate returns a self-signed certificate for 127.0.0.1.
+func newTestCertificate(t *testing.T) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "go-diameter"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func TestCapabilitiesExchangeSCTPSecure_TLS(t *testing.T) {
+	testCapabilitiesExchangeSCTPSecure(t, diam.SCTPTLS, nil, true)
+}
+
+func TestCapabilitiesExchangeSCTPSecure_DTLS(t *testing.T) {
+	// DTLS is not implemented by the standard library: check that the
+	// DTLSFunc is called on both sides.
+	var calls int32
+	dtls := func(c net.Conn, config *tls.Config, client bool) (net.Conn, error) {
+		atomic.AddInt32(&calls, 1)
+		return c, nil
+	}
+	testCapabilitiesExchangeSCTPSecure(t, diam.SCTPDTLS, dtls, false)
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Fatalf("Unexpected DTLS handshakes. Want 2, have %d", n)
+	}
+}
+
+func testCapabilitiesExchangeSCTPSecure(t *testing.T, security diam.SCTPSecurity, dtls diam.DTLSFunc, useTLS bool) {
+	errc := make(chan error, 1)
+
+	smux := diam.NewServeMux()
+	smux.Handle("CER", handleCER(errc, useTLS))
+
+	config := &tls.Config{Certificates: []tls.Certificate{newTestCertificate(t)}}
+	lis, err := diam.ListenSCTPSecure("sctp4", "127.0.0.1:0", security, config, dtls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+	srv := &diam.Server{Handler: smux}
+	go srv.Serve(lis)
+
+	wait := make(chan struct{})
+	cmux := diam.NewServeMux()
+	cmux.Handle("CEA", handleCEA(errc, wait))
+
+	addr := net.JoinHostPort("127.0.0.1", strconv.Itoa(lis.Addr().(*sctp.SCTPAddr).Port))
+	cli, err := diam.DialSCTPSecure("sctp4", addr, cmux, nil,
+		&diam.SecureSCTPDialer{Security: security, DTLS: dtls})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cli.Close()
+
+	sendCER(cli)
+
+	select {
+	case <-wait:
+	case err := <-errc:
+		t.Fatal(err)
+	case err := <-smux.ErrorReports():
+		t.Fatal(err)
+	case <-time.After(time.Second):
+		t.Fatal("Timed out: no CER or CEA received")
+	}
+}
+
+func TestSecureSCTPDialer_NoDTLS(t *testing.T) {
+	d := &diam.SecureSCTPDialer{Security: diam.SCTPDTLS}
+	if _, err := d.Dial("sctp", "127.0.0.1:3868"); err != diam.ErrNoDTLS {
+		t.Fatal("Unexpected error:", err)
+	}
+}
+
+func TestServer_ListenAndServeTLS(t *testing.T) {
+	testListenAndServeTLS(t, "tcp4")
+}
+
+// TestServer_ListenAndServeTLS_SCTP checks that ListenAndServeTLS runs
+// TLS on plain SCTP connections, not on multistream ones.
+func TestServer_ListenAndServeTLS_SCTP(t *testing.T) {
+	testListenAndServeTLS(t, "sctp4")
+}
+
+func testListenAndServeTLS(t *testing.T, network string) {
+	l, err := diam.Listen(network, "127.0.0.1:0")
+	if err != nil {
+		t.Skip(network, "not available:", err)
+	}
+	var port int
+	switch a := l.Addr().(type) {
+	case *sctp.SCTPAddr:
+		port = a.Port
+	case *net.TCPAddr:
+		port = a.Port
+	}
+	addr := net.JoinHostPort("127.0.0.1", strconv.Itoa(port))
+	l.Close()
+
+	dir, err := ioutil.TempDir("", "diam")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	cert := newTestCertificate(t)
+	key, err := x509.MarshalECPrivateKey(cert.PrivateKey.(*ecdsa.PrivateKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	err = ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]}), 0600)
+	if err == nil {
+		err = ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: key}), 0600)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errc := make(chan error, 1)
+	smux := diam.NewServeMux()
+	smux.Handle("CER", handleCER(errc, true))
+	srv := &diam.Server{Network: network, Addr: addr, Handler: smux}
+	go srv.ListenAndServeTLS(certFile, keyFile)
+	defer srv.Shutdown(context.Background())
+
+	wait := make(chan struct{})
+	cmux := diam.NewServeMux()
+	cmux.Handle("CEA", handleCEA(errc, wait))
+	var cli diam.Conn
+	for i := 0; i < 100; i++ {
+		if cli, err = diam.DialNetworkTLS(network, addr, "", "", cmux, nil); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cli.Close()
+
+	sendCER(cli)
+
+	select {
+	case <-wait:
+	case err := <-errc:
+		t.Fatal(err)
+	case err := <-smux.ErrorReports():
+		t.Fatal(err)
+	case <-time.After(time.Second):
+		t.Fatal("Timed out: no CER or CEA received")
+	}
+}
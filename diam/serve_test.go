@@ -229,3 +229,79 @@ func handleCEA(errc chan error, wait chan struct{}) diam.HandlerFunc {
 		c.Close()
 	}
 }
+
+func TestServerFlushInterval(t *testing.T) {
+	const n = 20
+	smux := diam.NewServeMux()
+	smux.Handle("DWR", diam.HandlerFunc(func(c diam.Conn, m *diam.Message) {
+		a := m.Answer(diam.Success)
+		a.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("srv"))
+		a.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity("localhost"))
+		a.WriteTo(c)
+	}))
+	srv := diamtest.NewUnstartedServer(smux, nil)
+	srv.Config.FlushInterval = 5 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+
+	dwa := make(chan struct{}, n)
+	cmux := diam.NewServeMux()
+	cmux.HandleIdx(diam.CommandIndex{AppID: 0, Code: diam.DeviceWatchdog, Request: false},
+		diam.HandlerFunc(func(c diam.Conn, m *diam.Message) { dwa <- struct{}{} }))
+	cli, err := diam.Dial(srv.Addr, cmux, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cli.Close()
+	b, ok := cli.(diam.Batcher)
+	if !ok {
+		t.Fatalf("Unexpected Conn. Want diam.Batcher, have %T", cli)
+	}
+	if err = b.SetFlushInterval(time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < n; i++ {
+		m := diam.NewRequest(diam.DeviceWatchdog, 0, nil)
+		m.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("cli"))
+		m.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity("localhost"))
+		if _, err = m.WriteTo(cli); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < n; i++ {
+		select {
+		case <-dwa:
+		case err := <-smux.ErrorReports():
+			t.Fatal(err)
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out: %d of %d DWAs received", i, n)
+		}
+	}
+}
+
+func TestServeMux_Dispatch(t *testing.T) {
+	var handled []string
+	record := func(name string) diam.HandlerFunc {
+		return func(c diam.Conn, m *diam.Message) { handled = append(handled, name) }
+	}
+	mux := diam.NewServeMux()
+	mux.HandleIdx(diam.CommandIndex{AppID: diam.GX_CHARGING_CONTROL_APP_ID, Code: diam.CreditControl, Request: true}, record("Gx"))
+	mux.Handle("CCR", record("CCR"))
+	mux.HandleApp(diam.TGPP_RX_APP_ID, record("Rx"))
+	mux.HandleFuncIdx(diam.CommandIndex{AppID: 5, Code: 9999, Request: true}, record("unknown"))
+	mux.Handle("ALL", record("ALL"))
+
+	for _, m := range []*diam.Message{
+		diam.NewRequest(diam.CreditControl, diam.GX_CHARGING_CONTROL_APP_ID, nil),
+		diam.NewRequest(diam.CreditControl, diam.CHARGING_CONTROL_APP_ID, nil),
+		diam.NewRequest(diam.AA, diam.TGPP_RX_APP_ID, nil),
+		diam.NewRequest(9999, 5, nil),
+		diam.NewRequest(diam.DeviceWatchdog, 0, nil),
+	} {
+		mux.ServeDIAM(nil, m)
+	}
+	want := []string{"Gx", "CCR", "Rx", "unknown", "ALL"}
+	if fmt.Sprint(handled) != fmt.Sprint(want) {
+		t.Fatalf("Unexpected handlers. Want %v, have %v", want, handled)
+	}
+}
@@ -11,14 +11,16 @@ import (
 	"crypto/tls"
 	"fmt"
 	"io"
-	"log"
 	"net"
 	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/net/context"
 
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
 	"github.com/omnicate/go-diameter/v4/diam/dict"
 )
 
@@ -40,7 +42,7 @@ type Conn interface {
 	RemoteAddr() net.Addr                           // Returns the remote IP
 	TLS() *tls.ConnectionState                      // TLS or nil when not using TLS
 	Dictionary() *dict.Parser                       // Dictionary parser of the connection
-	Context() context.Context                       // Returns the internal context
+	Context() context.Context                       // Returns the internal context, canceled when closed
 	SetContext(ctx context.Context)                 // Stores a new context
 	Connection() net.Conn                           // Returns network connection
 }
@@ -80,16 +82,62 @@ func (sr *liveSwitchReader) Read(p []byte) (n int, err error) {
 
 // conn represents the server side of a diameter connection.
 type conn struct {
+	id       uint64               // unique identifier, for logging
 	server   *Server              // the Server on which the connection arrived
 	rwc      net.Conn             // i/o connection
 	sr       liveSwitchReader     // reads from rwc
 	buf      *bufio.ReadWriter    // buffered(sr, rwc)
 	tlsState *tls.ConnectionState // or nil when not using TLS
 	writer   *response            // the diam.Conn exposed to handlers
+	cancel   context.CancelFunc   // cancels the initial context of writer when closed
+	wc       net.Conn             // written by buf, rwc or the upgraded tlsConn; guarded by writer.mu
+	batch    *batchWriter         // batches written messages, or nil; guarded by writer.mu
+
+	umu       sync.Mutex // guards the following, and tlsState after the first read
+	ucond     *sync.Cond // signals changes of upgrading and readers
+	upgrading bool       // UpgradeTLS in progress
+	upgrades  int        // number of UpgradeTLS calls completed
+	readers   int        // goroutines reading rwc or tlsConn
+	tlsConn   *tls.Conn  // upgraded by UpgradeTLS, or nil
 
 	mu           sync.Mutex // guards the following
 	closeNotifyc chan struct{}
 	clientGone   bool
+
+	pmu           sync.Mutex               // guards the following
+	pending       map[uint32]chan *Message // requests sent by SendRequest
+	pendingClosed bool
+
+	peer atomic.Value // datatype.DiameterIdentity from the CER or CEA of the peer
+
+	imu      sync.Mutex                // guards inflight
+	inflight map[inflightKey]time.Time // requests sent and received, not answered yet
+	done     chan struct{}             // closed when the connection is closed
+}
+
+// lastConnID is the identifier of the last connection created.
+var lastConnID uint64
+
+// peerIdentity returns the Origin-Host of the CER or CEA received on the
+// connection, or an empty identity before the capabilities exchange.
+func (c *conn) peerIdentity() datatype.DiameterIdentity {
+	id, _ := c.peer.Load().(datatype.DiameterIdentity)
+	return id
+}
+
+// setPeerIdentity saves the identity of the peer from its CER or CEA m.
+func (c *conn) setPeerIdentity(m *Message) {
+	if m.Header.CommandCode != CapabilitiesExchange || m.Header.ApplicationID != 0 {
+		return
+	}
+	for _, a := range m.AVP {
+		if a.Code == avp.OriginHost && a.VendorID == 0 {
+			if id, ok := a.Data.(datatype.DiameterIdentity); ok {
+				c.peer.Store(id)
+			}
+			return
+		}
+	}
 }
 
 func (c *conn) closeNotify() <-chan struct{} {
@@ -140,20 +188,30 @@ func (c *conn) notifyClientGone() {
 // Create new connection from rwc.
 func (srv *Server) newConn(rwc net.Conn) (c *conn, err error) {
 	msc, isMulti := rwc.(MultistreamConn)
+	id := atomic.AddUint64(&lastConnID, 1)
 	if isMulti {
 		c = &conn{
+			id:     id,
 			server: srv,
 			rwc:    msc,
 		}
 	} else {
 		c = &conn{
+			id:     id,
 			server: srv,
 			rwc:    rwc,
-			sr:     liveSwitchReader{r: rwc},
+			wc:     rwc,
 		}
+		c.ucond = sync.NewCond(&c.umu)
+		c.sr.r = readerFunc(c.readRaw)
 		c.buf = bufio.NewReadWriter(bufio.NewReader(&c.sr), bufio.NewWriter(rwc))
+		if srv.FlushInterval > 0 {
+			c.batch = newBatchWriter(rwc, srv.FlushInterval, srv.WriteTimeout)
+		}
 	}
 	c.writer = &response{conn: c}
+	c.writer.ctx, c.cancel = context.WithCancel(context.Background())
+	c.done = make(chan struct{})
 	return c, nil
 }
 
@@ -165,9 +223,9 @@ func (c *conn) readMessage() (m *Message, err error) {
 	if msc, isMulti := c.rwc.(MultistreamConn); isMulti {
 		// If it's a multi-stream association - reset the stream to "undefined" prior to reading next message
 		msc.ResetCurrentStream()
-		m, err = ReadMessage(msc, c.dictionary()) // MultistreamConn has it's own buffering
+		m, err = readMessage(msc, c.dictionary(), c.server.LazyDecoding) // MultistreamConn has it's own buffering
 	} else {
-		m, err = ReadMessage(c.buf.Reader, c.dictionary())
+		m, err = readMessage(c.buf.Reader, c.dictionary(), c.server.LazyDecoding)
 	}
 	if err != nil {
 		return nil, err
@@ -177,21 +235,44 @@ func (c *conn) readMessage() (m *Message, err error) {
 
 // Serve a new connection.
 func (c *conn) serve() {
+	obs := c.observer()
+	if obs != nil {
+		obs.ConnOpened(c.writer)
+	}
+	tracked := c.server.trackConn(c, true)
 	defer func() {
 		if err := recover(); err != nil {
 			buf := make([]byte, 4096)
 			buf = buf[:runtime.Stack(buf, false)]
-			log.Printf("diam: panic serving %v: %v\n%s",
-				c.rwc.RemoteAddr().String(), err, buf)
+			c.server.logger().Log(LevelError, "panic serving connection",
+				"conn", c.id,
+				"remote", c.rwc.RemoteAddr(),
+				"err", err,
+				"stack", string(buf),
+			)
 		}
 		c.rwc.Close()
+		c.closePending()
+		c.server.trackConn(c, false)
+		c.cancel()
+		close(c.done)
+		if obs != nil {
+			obs.ConnClosed(c.writer)
+		}
 	}()
+	if !tracked {
+		// Accepted while the server shuts down, after Shutdown took
+		// its connections.
+		return
+	}
 	if tlsConn, ok := c.rwc.(*tls.Conn); ok {
 		if err := tlsConn.Handshake(); err != nil {
 			return
 		}
-		c.tlsState = &tls.ConnectionState{}
-		*c.tlsState = tlsConn.ConnectionState()
+		state := tlsConn.ConnectionState()
+		c.umu.Lock()
+		c.tlsState = &state
+		c.umu.Unlock()
 	}
 	for {
 		m, err := c.readMessage()
@@ -209,8 +290,16 @@ func (c *conn) serve() {
 			}
 			break
 		}
-		// Handle messages in this goroutine.
-		serverHandler{c.server}.ServeDIAM(c.writer, m)
+		c.setPeerIdentity(m)
+		c.logMessage("message received", m)
+		if obs != nil {
+			obs.MessageReceived(c.writer, m)
+		}
+		c.trackMessage(m, true)
+		if c.deliverAnswer(m) {
+			continue
+		}
+		c.server.serveMessage(c, m)
 	}
 }
 
@@ -224,7 +313,8 @@ func (c *conn) dictionary() *dict.Parser {
 }
 
 // A response represents the server side of a diameter response.
-// It implements the Conn and CloseNotifier interfaces.
+// It implements the Conn, CloseNotifier, RequestSender, TLSUpgrader and
+// Batcher interfaces.
 type response struct {
 	mu   sync.Mutex      // guards conn and Write
 	conn *conn           // socket, reader and writer
@@ -243,6 +333,9 @@ func (w *response) Write(b []byte) (int, error) {
 	if isMulti {                                 // don't use buffered writer for muti-streamming writes it'll mix up streams
 		return msc.Write(b)
 	}
+	if w.conn.batch != nil {
+		return w.conn.batch.Write(b)
+	}
 	n, err := w.conn.buf.Writer.Write(b)
 	if err != nil {
 		return 0, err
@@ -286,9 +379,16 @@ func (w *response) SetWriterStream(stream uint) uint {
 	return 0
 }
 
-// Close closes the connection.
+// Close closes the connection, after writing the batched messages.
 func (w *response) Close() {
+	w.mu.Lock()
+	batch := w.conn.batch
+	w.mu.Unlock()
+	if batch != nil {
+		batch.Flush()
+	}
 	w.conn.rwc.Close()
+	w.conn.cancel()
 }
 
 // LocalAddr returns the local address of the connection.
@@ -303,6 +403,8 @@ func (w *response) RemoteAddr() net.Addr {
 
 // TLS returns the TLS connection state, or nil.
 func (w *response) TLS() *tls.ConnectionState {
+	w.conn.umu.Lock()
+	defer w.conn.umu.Unlock()
 	return w.conn.tlsState
 }
 
@@ -317,7 +419,9 @@ func (w *response) CloseNotify() <-chan struct{} {
 	return w.conn.closeNotify()
 }
 
-// Context returns the internal context or a new context.Background.
+// Context returns the internal context, which is canceled when the
+// connection is closed unless replaced by SetContext, or a new
+// context.Background.
 func (w *response) Context() context.Context {
 	w.xmu.Lock()
 	defer w.xmu.Unlock()
@@ -349,6 +453,24 @@ func (f HandlerFunc) ServeDIAM(c Conn, m *Message) {
 	f(c, m)
 }
 
+// The HandlerFuncCtx type is an adapter to allow the use of ordinary
+// functions that take the context of the message as diameter handlers,
+// for example to cancel the downstream calls of requests whose peer
+// disconnects:
+//
+//	mux.Handle("CCR", diam.HandlerFuncCtx(func(ctx context.Context, c diam.Conn, m *diam.Message) {
+//		rows, err := db.QueryContext(ctx, query)
+//		...
+//	}))
+//
+// See Message.Context.
+type HandlerFuncCtx func(context.Context, Conn, *Message)
+
+// ServeDIAM calls f(m.Context(), c, m).
+func (f HandlerFuncCtx) ServeDIAM(c Conn, m *Message) {
+	f(m.Context(), c, m)
+}
+
 // The ErrorReporter interface is implemented by Handlers that
 // allow reading errors from the underlying connection, like
 // parsing diameter messages or connection errors.
@@ -385,10 +507,13 @@ type ServeMux struct {
 	mu     sync.RWMutex // Guards m.
 	m      map[string]muxEntry
 	idxMap map[CommandIndex]muxEntry
+	apps   map[uint32]muxEntry
+	mws    []Middleware
 }
 
 type muxEntry struct {
-	h      Handler
+	h      Handler // handler wrapped by the middlewares
+	raw    Handler // handler as registered
 	cmd    string
 	cmdIdx CommandIndex
 }
@@ -407,6 +532,7 @@ func NewServeMux() *ServeMux {
 		e:      make(chan *ErrorReport, 1),
 		m:      make(map[string]muxEntry),
 		idxMap: make(map[CommandIndex]muxEntry),
+		apps:   make(map[uint32]muxEntry),
 	}
 }
 
@@ -426,76 +552,52 @@ func (mux *ServeMux) ErrorReports() <-chan *ErrorReport {
 	return mux.e
 }
 
-// ServeDIAM dispatches the request to the handler that match the code
-// in the incoming message. If the special "ALL" handler is registered
-// it is used as a catch-all. Otherwise an ErrorReport is sent out.
+// ServeDIAM dispatches the message to the handler registered for its
+// application, command code and request flag by HandleIdx, or else to
+// the handler registered for its command short name, such as "CCR", by
+// Handle, or else to the fallback handler of its application registered
+// by HandleApp. If the special "ALL" handler is registered it is used as
+// a catch-all. Otherwise an ErrorReport is sent out.
 func (mux *ServeMux) ServeDIAM(c Conn, m *Message) {
 	mux.mu.RLock()
 	defer mux.mu.RUnlock()
-	dcmd, err := m.Dictionary().FindCommand(
-		m.Header.ApplicationID,
-		m.Header.CommandCode)
-
-	if err != nil {
-		// Try the catch-all.
-		mux.serveIdx(ALL_CMD_INDEX, c, m)
-		return
-	}
-
 	idx := CommandIndex{
 		m.Header.ApplicationID,
 		m.Header.CommandCode,
 		m.Header.CommandFlags&RequestFlag == RequestFlag}
-	_, ok := mux.idxMap[idx]
-	if ok {
-		mux.serveIdx(idx, c, m)
+	if entry, ok := mux.idxMap[idx]; ok {
+		entry.h.ServeDIAM(c, m)
 		return
 	}
-
 	var cmd string
-	if m.Header.CommandFlags&RequestFlag == RequestFlag {
-		cmd = dcmd.Short + "R"
-	} else {
-		cmd = dcmd.Short + "A"
+	if dcmd, err := m.Dictionary().FindCommand(idx.AppID, idx.Code); err == nil {
+		if idx.Request {
+			cmd = dcmd.Short + "R"
+		} else {
+			cmd = dcmd.Short + "A"
+		}
+		if entry, ok := mux.m[cmd]; ok {
+			entry.h.ServeDIAM(c, m)
+			return
+		}
 	}
-	mux.serve(cmd, c, m)
-}
-
-func (mux *ServeMux) serveIdx(cmd CommandIndex, c Conn, m *Message) {
-	entry, ok := mux.idxMap[cmd]
-	if ok {
+	if entry, ok := mux.apps[idx.AppID]; ok {
 		entry.h.ServeDIAM(c, m)
 		return
 	}
 	// Try catch-all.
-	entry, ok = mux.idxMap[ALL_CMD_INDEX]
-	if ok {
+	if entry, ok := mux.idxMap[ALL_CMD_INDEX]; ok {
 		entry.h.ServeDIAM(c, m)
 		return
 	}
-	mux.Error(&ErrorReport{
-		Conn:    c,
-		Message: m,
-		Error:   fmt.Errorf("unhandled message for index: %+v", cmd),
-	})
-}
-
-func (mux *ServeMux) serve(cmd string, c Conn, m *Message) {
-	entry, ok := mux.m[cmd]
-	if ok {
-		entry.h.ServeDIAM(c, m)
-		return
-	}
-	// Try catch-all.
-	entry, ok = mux.idxMap[ALL_CMD_INDEX]
-	if ok {
-		entry.h.ServeDIAM(c, m)
-		return
+	err := fmt.Errorf("unhandled message for index: %+v", idx)
+	if cmd != "" {
+		err = fmt.Errorf("unhandled message for '%s'", cmd)
 	}
 	mux.Error(&ErrorReport{
 		Conn:    c,
 		Message: m,
-		Error:   fmt.Errorf("unhandled message for '%s'", cmd),
+		Error:   err,
 	})
 }
 
@@ -508,10 +610,10 @@ func (mux *ServeMux) Handle(shortCmd string, handler Handler) {
 		panic("DIAM: nil handler")
 	}
 	if shortCmd == "ALL" {
-		mux.idxMap[ALL_CMD_INDEX] = muxEntry{h: handler, cmd: shortCmd}
+		mux.idxMap[ALL_CMD_INDEX] = muxEntry{h: Chain(handler, mux.mws...), raw: handler, cmd: shortCmd}
 		return
 	}
-	mux.m[shortCmd] = muxEntry{h: handler, cmd: shortCmd}
+	mux.m[shortCmd] = muxEntry{h: Chain(handler, mux.mws...), raw: handler, cmd: shortCmd}
 }
 
 // Handle registers the handler for the given code.
@@ -522,7 +624,26 @@ func (mux *ServeMux) HandleIdx(cmd CommandIndex, handler Handler) {
 	if handler == nil {
 		panic("DIAM: nil handler")
 	}
-	mux.idxMap[cmd] = muxEntry{h: handler, cmdIdx: cmd}
+	mux.idxMap[cmd] = muxEntry{h: Chain(handler, mux.mws...), raw: handler, cmdIdx: cmd}
+}
+
+// HandleFuncIdx registers the handler function for the given
+// application, command code and request flag.
+func (mux *ServeMux) HandleFuncIdx(cmd CommandIndex, handler func(Conn, *Message)) {
+	mux.HandleIdx(cmd, HandlerFunc(handler))
+}
+
+// HandleApp registers the fallback handler of the messages of the
+// application appID that have no handler registered for their command,
+// so that servers of several applications that share command codes,
+// such as Gx, Gy and Rx, dispatch them unambiguously.
+func (mux *ServeMux) HandleApp(appID uint32, handler Handler) {
+	mux.mu.Lock()
+	defer mux.mu.Unlock()
+	if handler == nil {
+		panic("DIAM: nil handler")
+	}
+	mux.apps[appID] = muxEntry{h: Chain(handler, mux.mws...), raw: handler}
 }
 
 // HandleFunc registers the handler function for the given command.
@@ -559,14 +680,35 @@ func Serve(l net.Listener, handler Handler) error {
 
 // A Server defines parameters for running a diameter server.
 type Server struct {
-	Network      string        // network of the address - empty string defaults to tcp
-	Addr         string        // address to listen on, ":3868" if empty
-	Handler      Handler       // handler to invoke, DefaultServeMux if nil
-	Dict         *dict.Parser  // diameter dictionaries for this server
-	ReadTimeout  time.Duration // maximum duration before timing out read of the request
-	WriteTimeout time.Duration // maximum duration before timing out write of the response
-	TLSConfig    *tls.Config   // optional TLS config, used by ListenAndServeTLS
-	LocalAddr    net.Addr      // optional Local Address to bind dailer's (Dail...) socket to
+	Network        string           // network of the address - empty string defaults to tcp
+	Addr           string           // address to listen on, ":3868" if empty
+	Handler        Handler          // handler to invoke, DefaultServeMux if nil
+	Dict           *dict.Parser     // diameter dictionaries for this server
+	ReadTimeout    time.Duration    // maximum duration before timing out read of the request
+	WriteTimeout   time.Duration    // maximum duration before timing out write of the response
+	TLSConfig      *tls.Config      // optional TLS config, used by ListenAndServeTLS
+	LocalAddr      net.Addr         // optional Local Address to bind dailer's (Dail...) socket to
+	Logger         Logger           // optional, the Handler's if it is a LoggerProvider, or DefaultLogger
+	LazyDecoding   bool             // optional, read messages with ReadMessageLazy; handlers may Release them
+	FlushInterval  time.Duration    // optional, batch the messages written within the interval, see Batcher
+	Concurrency    ConcurrencyModel // optional, how the Handler runs on received messages, SerialPerConn if unset
+	Workers        int              // optional, goroutines of the WorkerPool and SessionOrdered models, GOMAXPROCS if unset
+	HandlerTimeout time.Duration    // optional, deadline of the contexts of received messages, see Message.Context
+	ReusePort      int              // optional, number of listeners of ListenAndServe sharing Addr with SO_REUSEPORT, see ListenReusePort
+	ProxyProtocol  bool             // optional, accept the PROXY protocol header on the connections of ListenAndServe and ListenAndServeTLS, see ProxyListener
+
+	// TransportConfig is optional, the socket options of the connections
+	// of ListenAndServe and ListenAndServeTLS. See ConfigureListener.
+	TransportConfig *TransportConfig
+
+	mu         sync.Mutex // guards listeners, conns and handoffs
+	listeners  map[net.Listener]struct{}
+	conns      map[*conn]struct{}
+	handoffs   []handoff // listeners of ListenAndServe, see StartProcess
+	inShutdown int32     // accessed atomically
+
+	workersOnce sync.Once
+	workers     *workerPool // started by the first message, see serveMessage
 }
 
 // serverHandler delegates to either the server's Handler or DefaultServeMux.
@@ -575,11 +717,14 @@ type serverHandler struct {
 }
 
 func (sh serverHandler) ServeDIAM(w Conn, m *Message) {
-	handler := sh.srv.Handler
-	if handler == nil {
-		handler = DefaultServeMux
+	sh.handler().ServeDIAM(w, m)
+}
+
+func (sh serverHandler) handler() Handler {
+	if sh.srv.Handler == nil {
+		return DefaultServeMux
 	}
-	handler.ServeDIAM(w, m)
+	return sh.srv.Handler
 }
 
 // ListenAndServe listens on the network address srv.Addr and then
@@ -587,6 +732,13 @@ func (sh serverHandler) ServeDIAM(w Conn, m *Message) {
 //
 // If srv.Network is blank, "tcp" is used
 // If srv.Addr is blank, ":3868" is used.
+//
+// If srv.ReusePort is set, it listens with SO_REUSEPORT and serves on
+// that many listeners. Listeners inherited from the parent process, see
+// StartProcess, are used instead of new ones.
+//
+// If srv.ProxyProtocol is set, connections start with the PROXY protocol
+// header of a load balancer, see ProxyListener.
 func (srv *Server) ListenAndServe() error {
 	network := srv.Network
 	if len(network) == 0 {
@@ -596,11 +748,12 @@ func (srv *Server) ListenAndServe() error {
 	if len(addr) == 0 {
 		addr = ":3868"
 	}
-	l, e := MultistreamListen(network, addr)
+	ls, e := srv.listen(network, addr, MultistreamListen)
 	if e != nil {
 		return e
 	}
-	return srv.Serve(l)
+	defer srv.dropHandoffs(ls)
+	return srv.serveAll(srv.proxied(srv.configured(ls)))
 }
 
 // Serve accepts incoming connections on the Listener l, creating a
@@ -608,10 +761,18 @@ func (srv *Server) ListenAndServe() error {
 // then call srv.Handler to reply to them.
 func (srv *Server) Serve(l net.Listener) error {
 	defer l.Close()
+	if srv.shuttingDown() {
+		return ErrServerClosed
+	}
+	srv.trackListener(l, true)
+	defer srv.trackListener(l, false)
 	var tempDelay time.Duration // how long to sleep on accept failure
 	for {
 		rw, e := l.Accept()
 		if e != nil {
+			if srv.shuttingDown() {
+				return ErrServerClosed
+			}
 			if ne, ok := e.(net.Error); ok && ne.Temporary() {
 				if tempDelay == 0 {
 					tempDelay = 5 * time.Millisecond
@@ -621,7 +782,7 @@ func (srv *Server) Serve(l net.Listener) error {
 				if max := 1 * time.Second; tempDelay > max {
 					tempDelay = max
 				}
-				log.Printf("diam: accept error: %v; retrying in %v", e, tempDelay)
+				srv.logger().Log(LevelWarn, "accept error", "err", e, "retry", tempDelay)
 				time.Sleep(tempDelay)
 				continue
 			}
@@ -632,12 +793,12 @@ func (srv *Server) Serve(l net.Listener) error {
 				network = addr.Network()
 				address = addr.String()
 			}
-			log.Printf("diam: accept error: %v for %s %s", e, network, address)
+			srv.logger().Log(LevelError, "accept error", "err", e, "network", network, "addr", address)
 			return e
 		}
 		tempDelay = 0
 		if c, err := srv.newConn(rw); err != nil {
-			log.Printf("srv.newConn error: %v", err)
+			srv.logger().Log(LevelError, "new connection error", "err", err)
 			continue
 		} else {
 			go c.serve()
@@ -699,12 +860,17 @@ func (srv *Server) ListenAndServeTLS(certFile, keyFile string) error {
 	if err != nil {
 		return err
 	}
-	conn, err := Listen(network, addr)
+	// TLS does not run on the streams of multistream SCTP connections.
+	ls, err := srv.listen(network, addr, Listen)
 	if err != nil {
 		return err
 	}
-	tlsListener := tls.NewListener(conn, config)
-	return srv.Serve(tlsListener)
+	defer srv.dropHandoffs(ls)
+	tlsListeners := make([]net.Listener, len(ls))
+	for i, l := range srv.proxied(srv.configured(ls)) {
+		tlsListeners[i] = tls.NewListener(l, config)
+	}
+	return srv.serveAll(tlsListeners)
 }
 
 // ListenAndServeNetworkTLS acts identically to ListenAndServeNetwork, except that it
@@ -0,0 +1,67 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package session
+
+import (
+	"strconv"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// AuthSessionState is the value of the Auth-Session-State AVP, which
+// tells whether the server maintains the state of a session, see RFC
+// 6733 section 8.11.
+type AuthSessionState int32
+
+// Values of the Auth-Session-State AVP.
+const (
+	StateMaintained   AuthSessionState = 0
+	NoStateMaintained AuthSessionState = 1
+)
+
+func (s AuthSessionState) String() string {
+	switch s {
+	case StateMaintained:
+		return "STATE_MAINTAINED"
+	case NoStateMaintained:
+		return "NO_STATE_MAINTAINED"
+	}
+	return "AuthSessionState(" + strconv.Itoa(int(s)) + ")"
+}
+
+// AuthSessionStateOf returns the value of the Auth-Session-State AVP of
+// the message m, StateMaintained when absent.
+func AuthSessionStateOf(m *diam.Message) AuthSessionState {
+	for _, a := range m.AVP {
+		if a.Code == avp.AuthSessionState {
+			if v, ok := a.Data.(datatype.Enumerated); ok {
+				return AuthSessionState(v)
+			}
+		}
+	}
+	return StateMaintained
+}
+
+// AuthSessionState returns whether the server maintains the state of
+// the session, from the last message passed to Refresh.
+//
+// Sessions without state maintained are not tracked by their Manager,
+// and Terminate closes them without sending an STR.
+func (s *Session) AuthSessionState() AuthSessionState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.authState
+}
+
+// untrack stops tracking the session with the given Session-Id without
+// closing it.
+func (mgr *Manager) untrack(id datatype.UTF8String) {
+	mgr.mu.Lock()
+	delete(mgr.sessions, id)
+	mgr.mu.Unlock()
+	mgr.unpersist(id)
+}
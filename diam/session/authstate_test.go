@@ -0,0 +1,46 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package session
+
+import (
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+func TestSession_NoStateMaintained(t *testing.T) {
+	mgr := NewManager("cli", "localhost")
+	s := mgr.New()
+	m := diam.NewRequest(diam.AA, 1, nil)
+	m.NewAVP(avp.AuthorizationLifetime, avp.Mbit, 0, datatype.Unsigned32(60))
+	if v := AuthSessionStateOf(m); v != StateMaintained {
+		t.Fatalf("Unexpected Auth-Session-State: %s", v)
+	}
+	m.NewAVP(avp.AuthSessionState, avp.Mbit, 0, datatype.Enumerated(NoStateMaintained))
+	s.Refresh(m)
+	if v := s.AuthSessionState(); v != NoStateMaintained {
+		t.Fatalf("Unexpected Auth-Session-State. Want %s, have %s", NoStateMaintained, v)
+	}
+	if s.State() != Open || !s.Expires().IsZero() {
+		t.Fatalf("Unexpected session: %s, expires %s", s.State(), s.Expires())
+	}
+	if mgr.Len() != 0 {
+		t.Fatal("Session without state maintained is tracked")
+	}
+	a, err := mgr.Terminate(context.Background(), nil, s, 1, Logout)
+	if a != nil || err != nil {
+		t.Fatalf("Unexpected STR of session without state maintained: %v, %v", a, err)
+	}
+	if s.State() != Closed {
+		t.Fatalf("Unexpected state. Want %s, have %s", Closed, s.State())
+	}
+	if _, err = mgr.Terminate(context.Background(), nil, s, 1, Logout); err != ErrClosed {
+		t.Fatalf("Unexpected error. Want %v, have %v", ErrClosed, err)
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package session provides Diameter session management as specified by
+// RFC 6733 section 8.
+//
+// A Manager generates Session-Ids, keeps track of the state of each
+// session, and expires sessions when their authorization lifetime and
+// grace period elapse. It is also a diam.Handler that dispatches
+// messages to the handler of the session they belong to, which allows
+// routing requests such as STR, ASR and RAR to the right session:
+//
+//	mgr := session.NewManager(settings.OriginHost, settings.OriginRealm)
+//	mux.Handle("ASR", mgr)
+//	mux.Handle("RAR", mgr)
+//	...
+//	s := mgr.New()
+//	s.Handle(diam.HandlerFunc(func(c diam.Conn, m *diam.Message) {
+//		// m belongs to s
+//	}))
+//
+// Sessions are terminated with STRs by Manager.Terminate. The Manager
+// answers the STRs of sessions without a handler, and the ASRs of
+// sessions with an AbortFunc, which are then terminated with an STR:
+//
+//	mux.Handle("STR", mgr)
+//	s.OnAbort(func(s *session.Session, m *diam.Message) bool {
+//		// release the resources of s
+//		return true
+//	})
+//	...
+//	sta, err := mgr.Terminate(ctx, c, s, appID, session.Logout)
+//
+// Sessions whose server answers with the NO_STATE_MAINTAINED
+// Auth-Session-State, passed to Refresh, are not tracked and not
+// terminated with STRs.
+//
+// Sessions are persisted by the Store of the Manager when set, such as
+// a MemoryStore or the Redis store of the redisstore package, which
+// allows sessions to survive restarts and to be shared by the nodes of
+// a cluster.
+//
+// Servers track the sessions created by their peers with Track, and
+// re-authorize them with ReAuth. Clients register a ReAuthFunc to
+// answer the RARs of their sessions:
+//
+//	s, err := mgr.Track(c, ccr)
+//	...
+//	raa, err := mgr.ReAuth(ctx, s, appID, session.ReAuthAuthorizeOnly)
+package session
@@ -0,0 +1,50 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package session
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// IDGenerator generates Session-Ids in the format recommended by
+// RFC 6733 section 8.8:
+//
+//	<DiameterIdentity>;<high 32 bits>;<low 32 bits>[;<optional value>]
+//
+// The high 32 bits are initialized with the time the generator is
+// created, and the low 32 bits are incremented for each Session-Id.
+type IDGenerator struct {
+	host datatype.DiameterIdentity
+	high uint32
+	low  uint32 // atomic
+}
+
+// NewIDGenerator creates and initializes an IDGenerator for the given
+// DiameterIdentity, typically the Origin-Host of the node.
+func NewIDGenerator(host datatype.DiameterIdentity) *IDGenerator {
+	return &IDGenerator{host: host, high: uint32(time.Now().Unix())}
+}
+
+// Next returns a new Session-Id. The optional values are appended to
+// it, separated by semicolons.
+func (g *IDGenerator) Next(optional ...string) datatype.UTF8String {
+	low := atomic.AddUint32(&g.low, 1)
+	var b strings.Builder
+	b.WriteString(string(g.host))
+	b.WriteByte(';')
+	b.WriteString(strconv.FormatUint(uint64(g.high), 10))
+	b.WriteByte(';')
+	b.WriteString(strconv.FormatUint(uint64(low), 10))
+	for _, v := range optional {
+		b.WriteByte(';')
+		b.WriteString(v)
+	}
+	return datatype.UTF8String(b.String())
+}
@@ -0,0 +1,25 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package session
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIDGenerator_Next(t *testing.T) {
+	g := NewIDGenerator("host.example.com")
+	id1, id2 := g.Next(), g.Next("opt")
+	if id1 == id2 {
+		t.Fatalf("Duplicate Session-Id: %s", id1)
+	}
+	parts := strings.Split(string(id1), ";")
+	if len(parts) != 3 || parts[0] != "host.example.com" || parts[2] != "1" {
+		t.Fatalf("Unexpected Session-Id: %s", id1)
+	}
+	if !strings.HasSuffix(string(id2), ";2;opt") {
+		t.Fatalf("Unexpected Session-Id: %s", id2)
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package session
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// Manager keeps track of the sessions of a Diameter node.
+//
+// It implements the diam.Handler interface by dispatching messages to
+// the handler of their session. ASRs of sessions with an AbortFunc,
+// RARs of sessions with a ReAuthFunc, and STRs of sessions without a
+// handler, are answered by the Manager.
+// Other requests of unknown sessions, or of sessions without a handler,
+// are answered with DIAMETER_UNKNOWN_SESSION_ID, and their answers are
+// dropped.
+type Manager struct {
+	OriginHost  datatype.DiameterIdentity
+	OriginRealm datatype.DiameterIdentity
+
+	// OnExpire, if set, is called when a session expires.
+	// It is called from its own goroutine.
+	OnExpire func(s *Session)
+
+	// OnTerminate, if set, is called when the peer terminates a
+	// session with an STR, after the STA is sent.
+	OnTerminate func(s *Session, cause TerminationCause)
+
+	// Store, if set, persists the sessions of the Manager. Sessions
+	// not tracked by the Manager are looked up in the Store by Get,
+	// and Load tracks all the sessions of the Store.
+	Store Store
+
+	// OnStoreError, if set, is called with the errors of the Store
+	// when saving or deleting sessions.
+	OnStoreError func(err error)
+
+	// StoreTimeout is the time to wait for the Store when looking up,
+	// saving or deleting a session. Will default to 1 second.
+	StoreTimeout time.Duration
+
+	// NoStoreLookup disables the lookups of untracked sessions in the
+	// Store by Get, which block the dispatch of their messages. It
+	// suits Stores not shared by other nodes, whose sessions are all
+	// tracked by Load.
+	NoStoreLookup bool
+
+	ids      *IDGenerator
+	mu       sync.RWMutex // guards sessions
+	sessions map[datatype.UTF8String]*Session
+}
+
+// NewManager creates and initializes a Manager for the node identified
+// by host and realm.
+func NewManager(host, realm datatype.DiameterIdentity) *Manager {
+	return &Manager{
+		OriginHost:  host,
+		OriginRealm: realm,
+		ids:         NewIDGenerator(host),
+		sessions:    make(map[datatype.UTF8String]*Session),
+	}
+}
+
+// New creates a new Idle session with a generated Session-Id.
+func (mgr *Manager) New(optional ...string) *Session {
+	return mgr.Add(mgr.ids.Next(optional...))
+}
+
+// Add tracks the session with the given Session-Id, typically one
+// created by the peer. It returns the existing session, if any.
+func (mgr *Manager) Add(id datatype.UTF8String) *Session {
+	mgr.mu.Lock()
+	if s, ok := mgr.sessions[id]; ok {
+		mgr.mu.Unlock()
+		return s
+	}
+	s := &Session{id: id, mgr: mgr}
+	mgr.sessions[id] = s
+	mgr.mu.Unlock()
+	mgr.persist(s)
+	return s
+}
+
+// Get returns the session with the given Session-Id, which is loaded
+// from the Store when not tracked, unless NoStoreLookup is set.
+func (mgr *Manager) Get(id datatype.UTF8String) (*Session, bool) {
+	mgr.mu.RLock()
+	s, ok := mgr.sessions[id]
+	mgr.mu.RUnlock()
+	if ok || mgr.Store == nil || mgr.NoStoreLookup {
+		return s, ok
+	}
+	ctx, cancel := mgr.storeContext()
+	defer cancel()
+	r, err := mgr.Store.Get(ctx, id)
+	if err != nil {
+		if err != ErrNotFound && mgr.OnStoreError != nil {
+			mgr.OnStoreError(err)
+		}
+		return nil, false
+	}
+	s, _ = mgr.restore(r)
+	return s, s != nil
+}
+
+// Find returns the session the message m belongs to.
+func (mgr *Manager) Find(m *diam.Message) (*Session, bool) {
+	id, ok := ID(m)
+	if !ok {
+		return nil, false
+	}
+	return mgr.Get(id)
+}
+
+// Remove closes the session with the given Session-Id and stops
+// tracking it.
+func (mgr *Manager) Remove(id datatype.UTF8String) {
+	mgr.remove(id)
+}
+
+// remove reports whether the session was tracked.
+func (mgr *Manager) remove(id datatype.UTF8String) bool {
+	mgr.mu.Lock()
+	s, ok := mgr.sessions[id]
+	delete(mgr.sessions, id)
+	mgr.mu.Unlock()
+	if ok {
+		s.close()
+	}
+	mgr.unpersist(id)
+	return ok
+}
+
+// unpersist deletes the session with the given Session-Id from the
+// Store.
+func (mgr *Manager) unpersist(id datatype.UTF8String) {
+	if mgr.Store == nil {
+		return
+	}
+	ctx, cancel := mgr.storeContext()
+	defer cancel()
+	err := mgr.Store.Delete(ctx, id)
+	if err != nil && mgr.OnStoreError != nil {
+		mgr.OnStoreError(err)
+	}
+}
+
+// defaultStoreTimeout is the time to wait for the Store when
+// Manager.StoreTimeout is not set.
+const defaultStoreTimeout = time.Second
+
+// storeContext returns the context of the operations of the Store.
+func (mgr *Manager) storeContext() (context.Context, context.CancelFunc) {
+	timeout := mgr.StoreTimeout
+	if timeout <= 0 {
+		timeout = defaultStoreTimeout
+	}
+	return context.WithTimeout(context.Background(), timeout)
+}
+
+// Len returns the number of sessions tracked by the Manager.
+func (mgr *Manager) Len() int {
+	mgr.mu.RLock()
+	defer mgr.mu.RUnlock()
+	return len(mgr.sessions)
+}
+
+// ServeDIAM implements the diam.Handler interface.
+func (mgr *Manager) ServeDIAM(c diam.Conn, m *diam.Message) {
+	s, ok := mgr.Find(m)
+	request := m.Header.CommandFlags&diam.RequestFlag != 0
+	if ok && request {
+		s.mu.Lock()
+		onAbort, onReAuth, h := s.onAbort, s.onReAuth, s.handler
+		s.mu.Unlock()
+		switch {
+		case m.Header.CommandCode == diam.AbortSession && onAbort != nil:
+			mgr.handleASR(c, m, s, onAbort)
+			return
+		case m.Header.CommandCode == diam.ReAuth && onReAuth != nil:
+			mgr.handleRAR(c, m, s, onReAuth)
+			return
+		case m.Header.CommandCode == diam.SessionTermination && h == nil:
+			mgr.handleSTR(c, m, s)
+			return
+		}
+	}
+	if ok && s.serveDIAM(c, m) {
+		return
+	}
+	if !request {
+		return
+	}
+	a := m.Answer(0)
+	if id, ok := ID(m); ok {
+		a.NewAVP(avp.SessionID, avp.Mbit, 0, id)
+	}
+	a.NewAVP(avp.ResultCode, avp.Mbit, 0, datatype.Unsigned32(diam.UnknownSessionID))
+	a.NewAVP(avp.OriginHost, avp.Mbit, 0, mgr.OriginHost)
+	a.NewAVP(avp.OriginRealm, avp.Mbit, 0, mgr.OriginRealm)
+	a.WriteTo(c)
+}
+
+// ID returns the Session-Id of the message m.
+func ID(m *diam.Message) (datatype.UTF8String, bool) {
+	for _, a := range m.AVP {
+		if a.Code == avp.SessionID {
+			id, ok := a.Data.(datatype.UTF8String)
+			return id, ok
+		}
+	}
+	return "", false
+}
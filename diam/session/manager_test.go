@@ -0,0 +1,124 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package session
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+)
+
+func newASR(id datatype.UTF8String) *diam.Message {
+	m := diam.NewRequest(diam.AbortSession, 0, nil)
+	m.NewAVP(avp.SessionID, avp.Mbit, 0, id)
+	m.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("cli"))
+	m.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity("localhost"))
+	m.NewAVP(avp.DestinationRealm, avp.Mbit, 0, datatype.DiameterIdentity("localhost"))
+	m.NewAVP(avp.DestinationHost, avp.Mbit, 0, datatype.DiameterIdentity("srv"))
+	m.NewAVP(avp.AuthApplicationID, avp.Mbit, 0, datatype.Unsigned32(4))
+	return m
+}
+
+func resultCode(t *testing.T, m *diam.Message) datatype.Unsigned32 {
+	rc, err := m.FindAVP(avp.ResultCode, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rc.Data.(datatype.Unsigned32)
+}
+
+func TestManager_ServeDIAM(t *testing.T) {
+	mgr := NewManager("srv", "localhost")
+	s := mgr.New()
+	s.Handle(diam.HandlerFunc(func(c diam.Conn, m *diam.Message) {
+		s.SetState(Terminating)
+		a := m.Answer(diam.Success)
+		a.NewAVP(avp.SessionID, avp.Mbit, 0, s.ID())
+		a.NewAVP(avp.OriginHost, avp.Mbit, 0, mgr.OriginHost)
+		a.NewAVP(avp.OriginRealm, avp.Mbit, 0, mgr.OriginRealm)
+		a.WriteTo(c)
+	}))
+	mux := diam.NewServeMux()
+	mux.Handle("ASR", mgr)
+	srv := diamtest.NewServer(mux, nil)
+	defer srv.Close()
+	c, err := diam.Dial(srv.Addr, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	a, err := diam.SendRequest(ctx, c, newASR(s.ID()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rc := resultCode(t, a); rc != diam.Success {
+		t.Fatalf("Unexpected Result-Code. Want %d, have %d", diam.Success, rc)
+	}
+	if s.State() != Terminating {
+		t.Fatalf("Unexpected state. Want %s, have %s", Terminating, s.State())
+	}
+	a, err = diam.SendRequest(ctx, c, newASR("unknown"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rc := resultCode(t, a); rc != diam.UnknownSessionID {
+		t.Fatalf("Unexpected Result-Code. Want %d, have %d", diam.UnknownSessionID, rc)
+	}
+}
+
+func TestSession_Expire(t *testing.T) {
+	expired := make(chan *Session, 1)
+	mgr := NewManager("srv", "localhost")
+	mgr.OnExpire = func(s *Session) {
+		expired <- s
+	}
+	s := mgr.New()
+	s.SetLifetime(10*time.Millisecond, 10*time.Millisecond)
+	if s.Expires().IsZero() {
+		t.Fatal("Session has no expiration time")
+	}
+	select {
+	case es := <-expired:
+		if es != s {
+			t.Fatalf("Unexpected session expired: %s", es.ID())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Session did not expire")
+	}
+	if s.State() != Closed {
+		t.Fatalf("Unexpected state. Want %s, have %s", Closed, s.State())
+	}
+	if _, ok := mgr.Get(s.ID()); ok {
+		t.Fatal("Expired session was not removed")
+	}
+}
+
+func TestSession_Refresh(t *testing.T) {
+	mgr := NewManager("srv", "localhost")
+	s := mgr.New()
+	m := diam.NewRequest(diam.ReAuth, 4, nil)
+	m.NewAVP(avp.AuthorizationLifetime, avp.Mbit, 0, datatype.Unsigned32(60))
+	m.NewAVP(avp.AuthGracePeriod, avp.Mbit, 0, datatype.Unsigned32(30))
+	s.Refresh(m)
+	if s.State() != Open {
+		t.Fatalf("Unexpected state. Want %s, have %s", Open, s.State())
+	}
+	if d := time.Until(s.Expires()); d < 89*time.Second || d > 90*time.Second {
+		t.Fatalf("Unexpected expiration: %s", d)
+	}
+	s.SetState(Closed)
+	if mgr.Len() != 0 {
+		t.Fatal("Closed session was not removed")
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package session
+
+import (
+	"errors"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// ErrNoSessionID is returned by Track for messages without a
+// Session-Id.
+var ErrNoSessionID = errors.New("session: message has no Session-Id")
+
+// ReAuthRequestType is the value of the Re-Auth-Request-Type AVP sent
+// in RARs, see RFC 6733 section 8.12.
+type ReAuthRequestType int32
+
+// Values of the Re-Auth-Request-Type AVP.
+const (
+	ReAuthAuthorizeOnly         ReAuthRequestType = 0
+	ReAuthAuthorizeAuthenticate ReAuthRequestType = 1
+)
+
+// ReAuthFunc is called when the peer sends the RAR m for a session, and
+// returns the Result-Code of the RAA, such as diam.Success. The session
+// is then usually re-authorized with a new request.
+type ReAuthFunc func(s *Session, m *diam.Message) uint32
+
+// OnReAuth registers the function called when the peer sends an RAR
+// for the session. The Manager answers the RAR with an RAA. RARs of
+// sessions without a ReAuthFunc are passed to their handler.
+func (s *Session) OnReAuth(f ReAuthFunc) {
+	s.mu.Lock()
+	s.onReAuth = f
+	s.mu.Unlock()
+}
+
+// Peer returns the Origin-Host and Origin-Realm of the peer of a
+// session added by Track.
+func (s *Session) Peer() (host, realm datatype.DiameterIdentity) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.peerHost, s.peerRealm
+}
+
+// Track tracks the session of the request m received on the connection
+// c, typically the initial request of a session created by the peer,
+// and remembers its origin peer for ReAuth. It returns the existing
+// session, if any.
+func (mgr *Manager) Track(c diam.Conn, m *diam.Message) (*Session, error) {
+	id, ok := ID(m)
+	if !ok {
+		return nil, ErrNoSessionID
+	}
+	s := mgr.Add(id)
+	s.mu.Lock()
+	s.conn = c
+	if a, err := m.FindAVP(avp.OriginHost, 0); err == nil {
+		s.peerHost, _ = a.Data.(datatype.DiameterIdentity)
+	}
+	if a, err := m.FindAVP(avp.OriginRealm, 0); err == nil {
+		s.peerRealm, _ = a.Data.(datatype.DiameterIdentity)
+	}
+	s.mu.Unlock()
+	mgr.persist(s)
+	return s, nil
+}
+
+// ReAuth sends an RAR of the application appID for the session s to its
+// origin peer, on the connection the session was tracked from, and
+// waits for its RAA until ctx is done.
+//
+// ReAuth must not be called from the handler of the connection of the
+// session, which receives the RAA.
+func (mgr *Manager) ReAuth(ctx context.Context, s *Session, appID uint32, typ ReAuthRequestType) (*diam.Message, error) {
+	s.mu.Lock()
+	state, c, host, realm := s.state, s.conn, s.peerHost, s.peerRealm
+	s.mu.Unlock()
+	if state == Closed {
+		return nil, ErrClosed
+	}
+	if c == nil || host == "" {
+		return nil, ErrUnknownPeer
+	}
+	m := diam.NewRequest(diam.ReAuth, appID, nil)
+	m.NewAVP(avp.SessionID, avp.Mbit, 0, s.id)
+	m.NewAVP(avp.OriginHost, avp.Mbit, 0, mgr.OriginHost)
+	m.NewAVP(avp.OriginRealm, avp.Mbit, 0, mgr.OriginRealm)
+	m.NewAVP(avp.DestinationRealm, avp.Mbit, 0, realm)
+	m.NewAVP(avp.DestinationHost, avp.Mbit, 0, host)
+	m.NewAVP(avp.AuthApplicationID, avp.Mbit, 0, datatype.Unsigned32(appID))
+	m.NewAVP(avp.ReAuthRequestType, avp.Mbit, 0, datatype.Enumerated(typ))
+	return diam.SendRequest(ctx, c, m)
+}
+
+// handleRAR answers the RAR m of the session s with the Result-Code
+// returned by its ReAuthFunc.
+func (mgr *Manager) handleRAR(c diam.Conn, m *diam.Message, s *Session, f ReAuthFunc) {
+	mgr.answer(c, m, s.id, f(s, m))
+}
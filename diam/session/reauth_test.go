@@ -0,0 +1,85 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package session
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+)
+
+func TestManager_ReAuth(t *testing.T) {
+	srvMgr := NewManager("srv", "localhost")
+	tracked := make(chan *Session, 1)
+	mux := diam.NewServeMux()
+	mux.HandleFunc("ASR", func(c diam.Conn, m *diam.Message) {
+		s, err := srvMgr.Track(c, m)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		srvMgr.answer(c, m, s.ID(), diam.Success)
+		tracked <- s
+	})
+	srv := diamtest.NewServer(mux, nil)
+	defer srv.Close()
+
+	cliMgr := NewManager("cli", "localhost")
+	cliMux := diam.NewServeMux()
+	cliMux.Handle("RAR", cliMgr)
+	c, err := diam.Dial(srv.Addr, cliMux, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	rars := make(chan *diam.Message, 1)
+	cs := cliMgr.New()
+	cs.OnReAuth(func(s *Session, m *diam.Message) uint32 {
+		rars <- m
+		return diam.Success
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err = diam.SendRequest(ctx, c, newASR(cs.ID())); err != nil {
+		t.Fatal(err)
+	}
+	s := <-tracked
+	if host, realm := s.Peer(); host != "cli" || realm != "localhost" {
+		t.Fatalf("Unexpected peer: %s, %s", host, realm)
+	}
+	a, err := srvMgr.ReAuth(ctx, s, 4, ReAuthAuthorizeOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rc := resultCode(t, a); rc != diam.Success {
+		t.Fatalf("Unexpected Result-Code. Want %d, have %d", diam.Success, rc)
+	}
+	m := <-rars
+	typ, err := m.FindAVP(avp.ReAuthRequestType, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := typ.Data.(datatype.Enumerated); v != datatype.Enumerated(ReAuthAuthorizeOnly) {
+		t.Fatalf("Unexpected Re-Auth-Request-Type: %d", v)
+	}
+
+	cliMgr.Remove(cs.ID())
+	if a, err = srvMgr.ReAuth(ctx, s, 4, ReAuthAuthorizeOnly); err != nil {
+		t.Fatal(err)
+	}
+	if rc := resultCode(t, a); rc != diam.UnknownSessionID {
+		t.Fatalf("Unexpected Result-Code. Want %d, have %d", diam.UnknownSessionID, rc)
+	}
+	if _, err = srvMgr.ReAuth(ctx, srvMgr.New(), 4, ReAuthAuthorizeOnly); err != ErrUnknownPeer {
+		t.Fatalf("Unexpected error. Want %v, have %v", ErrUnknownPeer, err)
+	}
+}
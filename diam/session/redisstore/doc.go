@@ -0,0 +1,20 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package redisstore provides a session.Store backed by Redis, which
+// allows the sessions of a cluster of Diameter nodes to be shared, and
+// to survive restarts of the nodes.
+//
+// Records are saved as JSON, under the Session-Id prefixed with
+// Store.Prefix, and expire with the authorization lifetime of their
+// session.
+//
+// Example:
+//
+//	mgr := session.NewManager(settings.OriginHost, settings.OriginRealm)
+//	mgr.Store = redisstore.New("localhost:6379")
+//	if _, err := mgr.Load(ctx); err != nil {
+//		log.Fatal(err)
+//	}
+package redisstore
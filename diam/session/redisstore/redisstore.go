@@ -0,0 +1,331 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package redisstore
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/session"
+)
+
+// DefaultPrefix is the default prefix of the keys of records.
+const DefaultPrefix = "diameter:session:"
+
+// DefaultTimeout is the default timeout of connections and commands.
+const DefaultTimeout = 5 * time.Second
+
+// ErrUnexpectedReply is returned for replies of the Redis server that
+// are not those of the commands sent, or are not session records.
+var ErrUnexpectedReply = errors.New("redisstore: unexpected reply")
+
+// scanCount is the number of keys requested by each SCAN.
+const scanCount = 100
+
+// Store is a session.Store backed by a Redis server, speaking the Redis
+// protocol (RESP) over a single connection, which is dialed again after
+// errors.
+type Store struct {
+	Addr     string
+	Password string // Sent with AUTH when set
+	DB       int    // Selected with SELECT when not zero
+
+	// Prefix is the prefix of the keys of records. Defaults to
+	// DefaultPrefix.
+	Prefix string
+
+	// Timeout is the timeout of connections and commands, unless the
+	// context of commands is done before. Defaults to DefaultTimeout.
+	Timeout time.Duration
+
+	mu   sync.Mutex // guards conn and serializes commands
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// New creates and initializes a Store for the Redis server at addr.
+func New(addr string) *Store {
+	return &Store{Addr: addr, Prefix: DefaultPrefix, Timeout: DefaultTimeout}
+}
+
+// Get implements the session.Store interface.
+func (st *Store) Get(ctx context.Context, id datatype.UTF8String) (*session.Record, error) {
+	v, err := st.do(ctx, "GET", st.key(id))
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, session.ErrNotFound
+	}
+	return decode(v)
+}
+
+// Put implements the session.Store interface.
+func (st *Store) Put(ctx context.Context, r *session.Record, ttl time.Duration) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	args := []string{"SET", st.key(r.ID), string(b)}
+	if ttl > 0 {
+		ms := int64(ttl / time.Millisecond)
+		if ms == 0 {
+			ms = 1
+		}
+		args = append(args, "PX", strconv.FormatInt(ms, 10))
+	}
+	_, err = st.do(ctx, args...)
+	return err
+}
+
+// Delete implements the session.Store interface.
+func (st *Store) Delete(ctx context.Context, id datatype.UTF8String) error {
+	_, err := st.do(ctx, "DEL", st.key(id))
+	return err
+}
+
+// Scan implements the session.Store interface, with the SCAN and MGET
+// commands. Records saved during the scan may not be seen.
+func (st *Store) Scan(ctx context.Context, f func(r *session.Record) bool) error {
+	match := globEscaper.Replace(st.prefix()) + "*"
+	cursor := "0"
+	for {
+		v, err := st.do(ctx, "SCAN", cursor, "MATCH", match, "COUNT", strconv.Itoa(scanCount))
+		if err != nil {
+			return err
+		}
+		reply, ok := v.([]interface{})
+		if !ok || len(reply) != 2 {
+			return ErrUnexpectedReply
+		}
+		cursor, _ = reply[0].(string)
+		keys, _ := reply[1].([]interface{})
+		if len(keys) > 0 {
+			args := []string{"MGET"}
+			for _, k := range keys {
+				s, _ := k.(string)
+				args = append(args, s)
+			}
+			if v, err = st.do(ctx, args...); err != nil {
+				return err
+			}
+			values, _ := v.([]interface{})
+			for _, value := range values {
+				if value == nil {
+					continue // Deleted or expired since SCAN
+				}
+				r, err := decode(value)
+				if err != nil {
+					return err
+				}
+				if !f(r) {
+					return nil
+				}
+			}
+		}
+		if cursor == "0" || cursor == "" {
+			return nil
+		}
+	}
+}
+
+// Close closes the connection to the Redis server, if any.
+func (st *Store) Close() error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.conn == nil {
+		return nil
+	}
+	err := st.conn.Close()
+	st.conn, st.r = nil, nil
+	return err
+}
+
+func (st *Store) prefix() string {
+	if st.Prefix == "" {
+		return DefaultPrefix
+	}
+	return st.Prefix
+}
+
+func (st *Store) key(id datatype.UTF8String) string {
+	return st.prefix() + string(id)
+}
+
+func (st *Store) timeout() time.Duration {
+	if st.Timeout <= 0 {
+		return DefaultTimeout
+	}
+	return st.Timeout
+}
+
+// globEscaper escapes the special characters of SCAN patterns.
+var globEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
+
+func decode(v interface{}) (*session.Record, error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, ErrUnexpectedReply
+	}
+	r := new(session.Record)
+	if err := json.Unmarshal([]byte(s), r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// redisError is an error reply of the Redis server.
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// do sends the command args and returns its reply: nil, a string, an
+// int64 or a []interface{} of them.
+func (st *Store) do(ctx context.Context, args ...string) (interface{}, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.conn == nil {
+		if err := st.dial(ctx); err != nil {
+			return nil, err
+		}
+	}
+	v, err := st.roundTrip(ctx, args)
+	if err != nil {
+		if _, ok := err.(redisError); !ok {
+			st.conn.Close()
+			st.conn, st.r = nil, nil
+		}
+	}
+	return v, err
+}
+
+// dial connects to the server, and authenticates and selects the
+// database when required. It must be called with st.mu held.
+func (st *Store) dial(ctx context.Context) error {
+	d := net.Dialer{Timeout: st.timeout()}
+	if deadline, ok := ctx.Deadline(); ok {
+		d.Deadline = deadline
+	}
+	c, err := d.Dial("tcp", st.Addr)
+	if err != nil {
+		return err
+	}
+	st.conn, st.r = c, bufio.NewReader(c)
+	var setup [][]string
+	if st.Password != "" {
+		setup = append(setup, []string{"AUTH", st.Password})
+	}
+	if st.DB != 0 {
+		setup = append(setup, []string{"SELECT", strconv.Itoa(st.DB)})
+	}
+	for _, args := range setup {
+		if _, err = st.roundTrip(ctx, args); err != nil {
+			c.Close()
+			st.conn, st.r = nil, nil
+			return err
+		}
+	}
+	return nil
+}
+
+// roundTrip writes the command args and reads its reply. It must be
+// called with st.mu held.
+func (st *Store) roundTrip(ctx context.Context, args []string) (interface{}, error) {
+	deadline := time.Now().Add(st.timeout())
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	st.conn.SetDeadline(deadline)
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, a := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(a), a)
+	}
+	if _, err := io.WriteString(st.conn, b.String()); err != nil {
+		return nil, err
+	}
+	return readReply(st.r)
+}
+
+// errProtocol is returned for malformed replies.
+var errProtocol = errors.New("redis: protocol error")
+
+// readReply reads a RESP reply.
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, errProtocol
+	}
+	typ, line := line[0], line[1:len(line)-2]
+	switch typ {
+	case '+':
+		return line, nil
+	case '-':
+		return nil, redisError(line)
+	case ':':
+		n, err := strconv.ParseInt(line, 10, 64)
+		if err != nil {
+			return nil, errProtocol
+		}
+		return n, nil
+	case '$':
+		n, err := strconv.Atoi(line)
+		if err != nil {
+			return nil, errProtocol
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		b := make([]byte, n+2)
+		if _, err = io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		return string(b[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(line)
+		if err != nil {
+			return nil, errProtocol
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		a := make([]interface{}, n)
+		var rerr error
+		for i := range a {
+			a[i], err = readReply(r)
+			if _, ok := err.(redisError); ok {
+				// Read the rest of the reply to stay in sync.
+				if rerr == nil {
+					rerr = err
+				}
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		if rerr != nil {
+			return nil, rerr
+		}
+		return a, nil
+	}
+	return nil, errProtocol
+}
@@ -0,0 +1,213 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package redisstore
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/session"
+)
+
+// fakeRedis is a Redis server implementing the commands used by Store.
+type fakeRedis struct {
+	ln   net.Listener
+	mu   sync.Mutex
+	keys map[string]string
+	ttls map[string]string
+	cmds []string
+	scan string // Reply of SCAN, if set
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &fakeRedis{ln: ln, keys: make(map[string]string), ttls: make(map[string]string)}
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go srv.serve(c)
+		}
+	}()
+	return srv
+}
+
+func (srv *fakeRedis) serve(c net.Conn) {
+	defer c.Close()
+	r := bufio.NewReader(c)
+	for {
+		v, err := readReply(r)
+		if err != nil {
+			return
+		}
+		var args []string
+		for _, a := range v.([]interface{}) {
+			args = append(args, a.(string))
+		}
+		srv.mu.Lock()
+		srv.cmds = append(srv.cmds, args[0])
+		fmt.Fprint(c, srv.exec(args))
+		srv.mu.Unlock()
+	}
+}
+
+func bulk(s string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(s), s)
+}
+
+func (srv *fakeRedis) exec(args []string) string {
+	switch args[0] {
+	case "AUTH", "SELECT":
+		return "+OK\r\n"
+	case "GET":
+		if v, ok := srv.keys[args[1]]; ok {
+			return bulk(v)
+		}
+		return "$-1\r\n"
+	case "SET":
+		srv.keys[args[1]] = args[2]
+		if len(args) == 5 && args[3] == "PX" {
+			srv.ttls[args[1]] = args[4]
+		}
+		return "+OK\r\n"
+	case "DEL":
+		delete(srv.keys, args[1])
+		return ":1\r\n"
+	case "MGET":
+		s := fmt.Sprintf("*%d\r\n", len(args)-1)
+		for _, k := range args[1:] {
+			if v, ok := srv.keys[k]; ok {
+				s += bulk(v)
+			} else {
+				s += "$-1\r\n"
+			}
+		}
+		return s
+	case "SCAN":
+		if srv.scan != "" {
+			return srv.scan
+		}
+		// Returns one key per call, the cursor is the index of the next.
+		var keys []string
+		pattern := strings.Replace(args[3], `\`, "", -1)
+		for k := range srv.keys {
+			if ok, _ := path.Match(pattern, k); ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		var i int
+		fmt.Sscan(args[1], &i)
+		next, s := "0", "*0\r\n"
+		if i < len(keys) {
+			s = "*1\r\n" + bulk(keys[i])
+			if i+1 < len(keys) {
+				next = fmt.Sprint(i + 1)
+			}
+		}
+		return "*2\r\n" + bulk(next) + s
+	}
+	return "-ERR unknown command '" + args[0] + "'\r\n"
+}
+
+func TestStore(t *testing.T) {
+	srv := newFakeRedis(t)
+	defer srv.ln.Close()
+	st := New(srv.ln.Addr().String())
+	st.Password = "secret"
+	st.DB = 2
+	defer st.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := st.Get(ctx, "a"); err != session.ErrNotFound {
+		t.Fatalf("Unexpected error. Want %v, have %v", session.ErrNotFound, err)
+	}
+	expires := time.Now().Add(time.Minute).Truncate(time.Second)
+	for _, id := range []datatype.UTF8String{"a", "b", "c"} {
+		r := &session.Record{ID: id, State: session.Open, PeerHost: "cli", Expires: expires}
+		if err := st.Put(ctx, r, time.Minute); err != nil {
+			t.Fatal(err)
+		}
+	}
+	srv.mu.Lock()
+	ttl, cmds := srv.ttls[DefaultPrefix+"a"], strings.Join(srv.cmds[:2], " ")
+	srv.mu.Unlock()
+	if ttl != "60000" {
+		t.Fatalf("Unexpected TTL: %q", ttl)
+	}
+	r, err := st.Get(ctx, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.ID != "a" || r.State != session.Open || r.PeerHost != "cli" || !r.Expires.Equal(expires) {
+		t.Fatalf("Unexpected record: %+v", r)
+	}
+	if err = st.Delete(ctx, "b"); err != nil {
+		t.Fatal(err)
+	}
+	var ids []datatype.UTF8String
+	err = st.Scan(ctx, func(r *session.Record) bool {
+		ids = append(ids, r.ID)
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 || ids[0] != "a" || ids[1] != "c" {
+		t.Fatalf("Unexpected records: %v", ids)
+	}
+	if cmds != "AUTH SELECT" {
+		t.Fatalf("Unexpected setup commands: %s", cmds)
+	}
+	srv.mu.Lock()
+	srv.scan = ":1\r\n"
+	srv.mu.Unlock()
+	err = st.Scan(ctx, func(r *session.Record) bool { return true })
+	if err != ErrUnexpectedReply {
+		t.Fatalf("Unexpected error. Want %v, have %v", ErrUnexpectedReply, err)
+	}
+}
+
+func TestStore_Manager(t *testing.T) {
+	srv := newFakeRedis(t)
+	defer srv.ln.Close()
+	st := New(srv.ln.Addr().String())
+	defer st.Close()
+
+	mgr := session.NewManager("srv", "localhost")
+	mgr.Store = st
+	mgr.OnStoreError = func(err error) {
+		t.Error(err)
+	}
+	s := mgr.New()
+	s.SetState(session.Open)
+
+	other := session.NewManager("srv", "localhost")
+	other.Store = st
+	shared, ok := other.Get(s.ID())
+	if !ok || shared.State() != session.Open {
+		t.Fatal("Session not shared")
+	}
+	mgr.Remove(s.ID())
+	if _, err := st.Get(context.Background(), s.ID()); err != session.ErrNotFound {
+		t.Fatalf("Removed session is stored: %v", err)
+	}
+}
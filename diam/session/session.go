@@ -0,0 +1,202 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package session
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// State is the state of a session.
+type State int
+
+// Session states.
+const (
+	Idle        State = iota // Created, no request exchanged yet
+	Open                     // Authorized
+	Terminating              // Termination requested, e.g. STR sent
+	Closed                   // Ended or expired, removed from its Manager
+)
+
+var states = [...]string{"Idle", "Open", "Terminating", "Closed"}
+
+func (s State) String() string {
+	if s >= 0 && int(s) < len(states) {
+		return states[s]
+	}
+	return "State(" + strconv.Itoa(int(s)) + ")"
+}
+
+// Session is a Diameter session tracked by a Manager.
+type Session struct {
+	id  datatype.UTF8String
+	mgr *Manager
+
+	mu        sync.Mutex // guards the following
+	state     State
+	handler   diam.Handler
+	onAbort   AbortFunc
+	onReAuth  ReAuthFunc
+	conn      diam.Conn // connection of the peer, set by Track
+	peerHost  datatype.DiameterIdentity
+	peerRealm datatype.DiameterIdentity
+	authState AuthSessionState
+	timer     *time.Timer
+	expires   time.Time
+	value     interface{}
+}
+
+// ID returns the Session-Id of the session.
+func (s *Session) ID() datatype.UTF8String {
+	return s.id
+}
+
+// State returns the current state of the session.
+func (s *Session) State() State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state
+}
+
+// SetState sets the state of the session. Setting it to Closed removes
+// the session from its Manager.
+func (s *Session) SetState(state State) {
+	if state == Closed {
+		s.mgr.Remove(s.id)
+		return
+	}
+	s.mu.Lock()
+	if s.state != Closed {
+		s.state = state
+	}
+	s.mu.Unlock()
+	s.mgr.persist(s)
+}
+
+// Handle sets the handler of messages that belong to the session.
+func (s *Session) Handle(h diam.Handler) {
+	s.mu.Lock()
+	s.handler = h
+	s.mu.Unlock()
+}
+
+// Value returns the application data associated with the session.
+func (s *Session) Value() interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.value
+}
+
+// SetValue associates application data with the session.
+func (s *Session) SetValue(v interface{}) {
+	s.mu.Lock()
+	s.value = v
+	s.mu.Unlock()
+}
+
+// Expires returns the time the session expires, or the zero time if
+// it has no authorization lifetime.
+func (s *Session) Expires() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.expires
+}
+
+// SetLifetime sets the authorization lifetime and grace period of the
+// session. The session expires when both elapse, unless it's refreshed
+// before. A zero lifetime disables expiration.
+//
+// See RFC 6733 section 8.9 for details.
+func (s *Session) SetLifetime(lifetime, grace time.Duration) {
+	s.mu.Lock()
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	if s.state == Closed || lifetime <= 0 {
+		s.expires = time.Time{}
+	} else {
+		s.expires = time.Now().Add(lifetime + grace)
+		s.timer = time.AfterFunc(lifetime+grace, s.expire)
+	}
+	s.mu.Unlock()
+	s.mgr.persist(s)
+}
+
+// Refresh updates the authorization lifetime of the session with the
+// Authorization-Lifetime and Auth-Grace-Period AVPs of the message m,
+// if present, and opens the session.
+//
+// When the Auth-Session-State AVP of m is NO_STATE_MAINTAINED, the
+// session stays open but is no longer tracked by its Manager, and has
+// no authorization lifetime. See AuthSessionState.
+func (s *Session) Refresh(m *diam.Message) {
+	if AuthSessionStateOf(m) == NoStateMaintained {
+		s.mu.Lock()
+		s.authState = NoStateMaintained
+		if s.state != Closed {
+			s.state = Open
+		}
+		s.mu.Unlock()
+		s.SetLifetime(0, 0)
+		s.mgr.untrack(s.id)
+		return
+	}
+	var lifetime, grace time.Duration
+	var found bool
+	for _, a := range m.AVP {
+		switch a.Code {
+		case avp.AuthorizationLifetime:
+			if v, ok := a.Data.(datatype.Unsigned32); ok {
+				lifetime, found = time.Duration(v)*time.Second, true
+			}
+		case avp.AuthGracePeriod:
+			if v, ok := a.Data.(datatype.Unsigned32); ok {
+				grace = time.Duration(v) * time.Second
+			}
+		}
+	}
+	s.SetState(Open)
+	if found {
+		s.SetLifetime(lifetime, grace)
+	}
+}
+
+func (s *Session) expire() {
+	s.mu.Lock()
+	// The lifetime may have been refreshed while the timer fired.
+	expired := !s.expires.IsZero() && !time.Now().Before(s.expires)
+	s.mu.Unlock()
+	if expired && s.mgr.remove(s.id) && s.mgr.OnExpire != nil {
+		s.mgr.OnExpire(s)
+	}
+}
+
+// close marks the session as closed and stops its timer.
+func (s *Session) close() {
+	s.mu.Lock()
+	s.state = Closed
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	s.mu.Unlock()
+}
+
+func (s *Session) serveDIAM(c diam.Conn, m *diam.Message) bool {
+	s.mu.Lock()
+	h := s.handler
+	s.mu.Unlock()
+	if h == nil {
+		return false
+	}
+	h.ServeDIAM(c, m)
+	return true
+}
@@ -0,0 +1,205 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package session
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// ErrNotFound is returned by Store.Get for unknown sessions.
+var ErrNotFound = errors.New("session not found")
+
+// Record is the persistent state of a session, saved in a Store.
+// Handlers and values of sessions are not persistent.
+type Record struct {
+	ID               datatype.UTF8String
+	State            State
+	AuthSessionState AuthSessionState
+	PeerHost         datatype.DiameterIdentity
+	PeerRealm        datatype.DiameterIdentity
+	Expires          time.Time // Zero if the session does not expire
+}
+
+// Store is a persistent store of sessions, which allows sessions to
+// survive restarts of a node, and to be shared by the nodes of a
+// cluster. See Manager.Store.
+type Store interface {
+	// Get returns the record of the session with the given Session-Id,
+	// or ErrNotFound.
+	Get(ctx context.Context, id datatype.UTF8String) (*Record, error)
+
+	// Put saves the record r, which expires after ttl if not zero.
+	Put(ctx context.Context, r *Record, ttl time.Duration) error
+
+	// Delete deletes the record of the session with the given
+	// Session-Id, if any.
+	Delete(ctx context.Context, id datatype.UTF8String) error
+
+	// Scan calls f with each record until f returns false.
+	Scan(ctx context.Context, f func(r *Record) bool) error
+}
+
+// MemoryStore is a Store that keeps records in memory, for tests and
+// single nodes.
+type MemoryStore struct {
+	mu      sync.Mutex // guards records
+	records map[datatype.UTF8String]memoryRecord
+}
+
+type memoryRecord struct {
+	Record
+	deadline time.Time // Zero if the record does not expire
+}
+
+// NewMemoryStore creates and initializes a MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[datatype.UTF8String]memoryRecord)}
+}
+
+// Get implements the Store interface.
+func (st *MemoryStore) Get(ctx context.Context, id datatype.UTF8String) (*Record, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	r, ok := st.records[id]
+	if !ok || r.expired(time.Now()) {
+		return nil, ErrNotFound
+	}
+	return &r.Record, nil
+}
+
+// Put implements the Store interface.
+func (st *MemoryStore) Put(ctx context.Context, r *Record, ttl time.Duration) error {
+	mr := memoryRecord{Record: *r}
+	if ttl > 0 {
+		mr.deadline = time.Now().Add(ttl)
+	}
+	st.mu.Lock()
+	st.records[r.ID] = mr
+	st.mu.Unlock()
+	return nil
+}
+
+// Delete implements the Store interface.
+func (st *MemoryStore) Delete(ctx context.Context, id datatype.UTF8String) error {
+	st.mu.Lock()
+	delete(st.records, id)
+	st.mu.Unlock()
+	return nil
+}
+
+// Scan implements the Store interface. Expired records are deleted.
+func (st *MemoryStore) Scan(ctx context.Context, f func(r *Record) bool) error {
+	now := time.Now()
+	st.mu.Lock()
+	records := make([]Record, 0, len(st.records))
+	for id, r := range st.records {
+		if r.expired(now) {
+			delete(st.records, id)
+			continue
+		}
+		records = append(records, r.Record)
+	}
+	st.mu.Unlock()
+	for i := range records {
+		if !f(&records[i]) {
+			break
+		}
+	}
+	return nil
+}
+
+func (r *memoryRecord) expired(now time.Time) bool {
+	return !r.deadline.IsZero() && !now.Before(r.deadline)
+}
+
+// record returns the persistent state of the session.
+func (s *Session) record() *Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &Record{
+		ID:               s.id,
+		State:            s.state,
+		AuthSessionState: s.authState,
+		PeerHost:         s.peerHost,
+		PeerRealm:        s.peerRealm,
+		Expires:          s.expires,
+	}
+}
+
+// persist saves the session s in the Store of the Manager, or deletes
+// it when it is closed or its state is not maintained.
+func (mgr *Manager) persist(s *Session) {
+	if mgr.Store == nil {
+		return
+	}
+	r := s.record()
+	ctx, cancel := mgr.storeContext()
+	defer cancel()
+	var err error
+	if r.State == Closed || r.AuthSessionState == NoStateMaintained {
+		err = mgr.Store.Delete(ctx, r.ID)
+	} else {
+		var ttl time.Duration
+		if !r.Expires.IsZero() {
+			if ttl = time.Until(r.Expires); ttl <= 0 {
+				return
+			}
+		}
+		err = mgr.Store.Put(ctx, r, ttl)
+	}
+	if err != nil && mgr.OnStoreError != nil {
+		mgr.OnStoreError(err)
+	}
+}
+
+// Load tracks the sessions of the Store of the Manager, e.g. after a
+// restart, and returns the number of sessions loaded. Sessions already
+// tracked are not replaced.
+func (mgr *Manager) Load(ctx context.Context) (int, error) {
+	if mgr.Store == nil {
+		return 0, nil
+	}
+	n := 0
+	err := mgr.Store.Scan(ctx, func(r *Record) bool {
+		if _, ok := mgr.restore(r); ok {
+			n++
+		}
+		return true
+	})
+	return n, err
+}
+
+// restore tracks the session of the record r, and reports whether it
+// was not tracked yet. Expired sessions are not restored.
+func (mgr *Manager) restore(r *Record) (*Session, bool) {
+	if r.State == Closed || !r.Expires.IsZero() && !time.Now().Before(r.Expires) {
+		return nil, false
+	}
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+	if s, ok := mgr.sessions[r.ID]; ok {
+		return s, false
+	}
+	s := &Session{
+		id:        r.ID,
+		mgr:       mgr,
+		state:     r.State,
+		authState: r.AuthSessionState,
+		peerHost:  r.PeerHost,
+		peerRealm: r.PeerRealm,
+		expires:   r.Expires,
+	}
+	if !r.Expires.IsZero() {
+		s.timer = time.AfterFunc(time.Until(r.Expires), s.expire)
+	}
+	mgr.sessions[r.ID] = s
+	return s, true
+}
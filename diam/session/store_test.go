@@ -0,0 +1,119 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package session
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStore()
+	if _, err := st.Get(ctx, "a"); err != ErrNotFound {
+		t.Fatalf("Unexpected error. Want %v, have %v", ErrNotFound, err)
+	}
+	st.Put(ctx, &Record{ID: "a", State: Open}, 0)
+	st.Put(ctx, &Record{ID: "b", State: Open}, time.Millisecond)
+	st.Put(ctx, &Record{ID: "c", State: Open}, time.Minute)
+	time.Sleep(2 * time.Millisecond)
+	if r, err := st.Get(ctx, "a"); err != nil || r.State != Open {
+		t.Fatalf("Unexpected record: %+v, %v", r, err)
+	}
+	if _, err := st.Get(ctx, "b"); err != ErrNotFound {
+		t.Fatalf("Unexpected error for expired record: %v", err)
+	}
+	st.Delete(ctx, "c")
+	var ids []datatype.UTF8String
+	st.Scan(ctx, func(r *Record) bool {
+		ids = append(ids, r.ID)
+		return true
+	})
+	if len(ids) != 1 || ids[0] != "a" {
+		t.Fatalf("Unexpected records: %v", ids)
+	}
+}
+
+func TestManager_Store(t *testing.T) {
+	ctx := context.Background()
+	st := NewMemoryStore()
+	mgr := NewManager("srv", "localhost")
+	mgr.Store = st
+	s := mgr.New()
+	s.SetState(Open)
+	s.SetLifetime(time.Minute, 0)
+	closed := mgr.New()
+	closed.SetState(Closed)
+
+	// Restart.
+	mgr = NewManager("srv", "localhost")
+	mgr.Store = st
+	n, err := mgr.Load(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("Unexpected number of sessions loaded. Want 1, have %d", n)
+	}
+	ls, ok := mgr.Get(s.ID())
+	if !ok || ls.State() != Open || !ls.Expires().Equal(s.Expires()) {
+		t.Fatalf("Unexpected session loaded: %+v", ls)
+	}
+
+	// Another node of the cluster.
+	other := NewManager("srv", "localhost")
+	other.Store = st
+	shared, ok := other.Get(s.ID())
+	if !ok || shared.State() != Open {
+		t.Fatal("Session not shared")
+	}
+	shared.SetState(Closed)
+	if _, err = st.Get(ctx, s.ID()); err != ErrNotFound {
+		t.Fatalf("Closed session is stored: %v", err)
+	}
+}
+
+// slowStore is a Store whose lookups block until their context is done.
+type slowStore struct {
+	*MemoryStore
+	gets int
+}
+
+func (st *slowStore) Get(ctx context.Context, id datatype.UTF8String) (*Record, error) {
+	st.gets++
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestManager_StoreTimeout(t *testing.T) {
+	st := &slowStore{MemoryStore: NewMemoryStore()}
+	mgr := NewManager("srv", "localhost")
+	mgr.Store = st
+	mgr.StoreTimeout = 10 * time.Millisecond
+	errc := make(chan error, 1)
+	mgr.OnStoreError = func(err error) { errc <- err }
+	start := time.Now()
+	if _, ok := mgr.Get("a"); ok {
+		t.Fatal("Unexpected session found")
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("Unexpected lookup time: %s", d)
+	}
+	if err := <-errc; err != context.DeadlineExceeded {
+		t.Fatalf("Unexpected error. Want %v, have %v", context.DeadlineExceeded, err)
+	}
+
+	mgr.NoStoreLookup = true
+	if _, ok := mgr.Get("a"); ok {
+		t.Fatal("Unexpected session found")
+	}
+	if st.gets != 1 {
+		t.Fatalf("Unexpected number of lookups. Want 1, have %d", st.gets)
+	}
+}
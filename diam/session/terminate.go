@@ -0,0 +1,184 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package session
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/sm/smpeer"
+)
+
+// ErrClosed is returned when terminating a session that is closed.
+var ErrClosed = errors.New("session closed")
+
+// ErrUnknownPeer is returned by Terminate when the peer of the
+// connection has not passed the CER/CEA handshake, and by ReAuth for
+// sessions not added by Track.
+var ErrUnknownPeer = errors.New("session: unknown peer")
+
+// abortTimeout is the time to wait for the STA of sessions terminated
+// after an ASR.
+const abortTimeout = 10 * time.Second
+
+// TerminationCause is the value of the Termination-Cause AVP sent in
+// STRs, see RFC 6733 section 8.15.
+type TerminationCause int32
+
+// Termination causes.
+const (
+	Logout                 TerminationCause = 1 + iota // DIAMETER_LOGOUT
+	ServiceNotProvided                                 // DIAMETER_SERVICE_NOT_PROVIDED
+	BadAnswer                                          // DIAMETER_BAD_ANSWER
+	Administrative                                     // DIAMETER_ADMINISTRATIVE, e.g. after an ASR
+	LinkBroken                                         // DIAMETER_LINK_BROKEN
+	AuthExpired                                        // DIAMETER_AUTH_EXPIRED
+	UserMovedToOtherSystem                             // DIAMETER_USER_MOVED
+	SessionTimeout                                     // DIAMETER_SESSION_TIMEOUT
+)
+
+var terminationCauses = [...]string{
+	"Logout", "ServiceNotProvided", "BadAnswer", "Administrative",
+	"LinkBroken", "AuthExpired", "UserMovedToOtherSystem", "SessionTimeout",
+}
+
+func (c TerminationCause) String() string {
+	if c >= Logout && int(c) <= len(terminationCauses) {
+		return terminationCauses[c-1]
+	}
+	return "TerminationCause(" + strconv.Itoa(int(c)) + ")"
+}
+
+// AbortFunc is called when the peer aborts a session with the ASR m.
+// It returns false to refuse the abort, which is then answered with
+// DIAMETER_UNABLE_TO_COMPLY.
+type AbortFunc func(s *Session, m *diam.Message) bool
+
+// OnAbort registers the function called when the peer aborts the
+// session with an ASR. The Manager answers the ASR, and terminates
+// the session with an STR with the Administrative cause when f
+// accepts the abort. ASRs of sessions without an AbortFunc are passed
+// to their handler.
+func (s *Session) OnAbort(f AbortFunc) {
+	s.mu.Lock()
+	s.onAbort = f
+	s.mu.Unlock()
+}
+
+// Terminate sends an STR of the application appID for the session s on
+// the connection c, to the peer of c, and waits for its STA until ctx
+// is done. The session is Terminating until the STA is received, and
+// then closed.
+//
+// Sessions whose AuthSessionState is NoStateMaintained are closed
+// without sending an STR, and Terminate returns a nil answer for them.
+//
+// Terminate must not be called from the handler of the connection c,
+// which receives the STA.
+func (mgr *Manager) Terminate(ctx context.Context, c diam.Conn, s *Session, appID uint32, cause TerminationCause) (*diam.Message, error) {
+	if s.AuthSessionState() == NoStateMaintained {
+		if s.State() == Closed {
+			return nil, ErrClosed
+		}
+		s.close()
+		return nil, nil
+	}
+	meta, ok := smpeer.FromContext(c.Context())
+	if !ok {
+		return nil, ErrUnknownPeer
+	}
+	return mgr.terminate(ctx, c, s, appID, cause, meta.OriginHost, meta.OriginRealm)
+}
+
+// terminate sends an STR to the given host and realm.
+func (mgr *Manager) terminate(ctx context.Context, c diam.Conn, s *Session, appID uint32, cause TerminationCause, host, realm datatype.DiameterIdentity) (*diam.Message, error) {
+	s.mu.Lock()
+	if s.state == Closed {
+		s.mu.Unlock()
+		return nil, ErrClosed
+	}
+	s.state = Terminating
+	s.mu.Unlock()
+	mgr.persist(s)
+	m := diam.NewRequest(diam.SessionTermination, appID, nil)
+	m.NewAVP(avp.SessionID, avp.Mbit, 0, s.id)
+	m.NewAVP(avp.OriginHost, avp.Mbit, 0, mgr.OriginHost)
+	m.NewAVP(avp.OriginRealm, avp.Mbit, 0, mgr.OriginRealm)
+	m.NewAVP(avp.DestinationRealm, avp.Mbit, 0, realm)
+	if host != "" {
+		m.NewAVP(avp.DestinationHost, avp.Mbit, 0, host)
+	}
+	m.NewAVP(avp.AuthApplicationID, avp.Mbit, 0, datatype.Unsigned32(appID))
+	m.NewAVP(avp.TerminationCause, avp.Mbit, 0, datatype.Enumerated(cause))
+	a, err := diam.SendRequest(ctx, c, m)
+	if err != nil {
+		return nil, err
+	}
+	mgr.remove(s.id)
+	return a, nil
+}
+
+// handleASR answers the ASR m of the session s, and terminates the
+// session if its AbortFunc accepts the abort.
+func (mgr *Manager) handleASR(c diam.Conn, m *diam.Message, s *Session, f AbortFunc) {
+	ok := f(s, m)
+	code := uint32(diam.Success)
+	if !ok {
+		code = diam.UnableToComply
+	}
+	mgr.answer(c, m, s.id, code)
+	if !ok {
+		return
+	}
+	var host, realm datatype.DiameterIdentity
+	if a, err := m.FindAVP(avp.OriginHost, 0); err == nil {
+		host, _ = a.Data.(datatype.DiameterIdentity)
+	}
+	if a, err := m.FindAVP(avp.OriginRealm, 0); err == nil {
+		realm, _ = a.Data.(datatype.DiameterIdentity)
+	}
+	// The STA is received by the goroutine running this handler, the
+	// STR can't be sent from it.
+	l := diam.LoggerOf(c)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), abortTimeout)
+		defer cancel()
+		_, err := mgr.terminate(ctx, c, s, m.Header.ApplicationID, Administrative, host, realm)
+		if err != nil && err != ErrClosed {
+			l.Log(diam.LevelError, "failed to terminate aborted session", "session", s.id, "err", err)
+		}
+	}()
+}
+
+// handleSTR answers the STR m of the session s and closes the session.
+func (mgr *Manager) handleSTR(c diam.Conn, m *diam.Message, s *Session) {
+	var cause TerminationCause
+	if a, err := m.FindAVP(avp.TerminationCause, 0); err == nil {
+		if v, ok := a.Data.(datatype.Enumerated); ok {
+			cause = TerminationCause(v)
+		}
+	}
+	mgr.answer(c, m, s.id, diam.Success)
+	if mgr.remove(s.id) && mgr.OnTerminate != nil {
+		mgr.OnTerminate(s, cause)
+	}
+}
+
+// answer writes the answer with the given Result-Code to the request m
+// of the session id.
+func (mgr *Manager) answer(c diam.Conn, m *diam.Message, id datatype.UTF8String, code uint32) {
+	a := m.Answer(0)
+	a.NewAVP(avp.SessionID, avp.Mbit, 0, id)
+	a.NewAVP(avp.ResultCode, avp.Mbit, 0, datatype.Unsigned32(code))
+	a.NewAVP(avp.OriginHost, avp.Mbit, 0, mgr.OriginHost)
+	a.NewAVP(avp.OriginRealm, avp.Mbit, 0, mgr.OriginRealm)
+	a.WriteTo(c)
+}
@@ -0,0 +1,145 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package session
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+	"github.com/omnicate/go-diameter/v4/diam/sm/smpeer"
+)
+
+type terminated struct {
+	s     *Session
+	cause TerminationCause
+}
+
+// newTerminatingManager returns a Manager notifying the sessions
+// terminated by the peer on the returned channel.
+func newTerminatingManager(host string) (*Manager, chan terminated) {
+	tc := make(chan terminated, 1)
+	mgr := NewManager(datatype.DiameterIdentity(host), "localhost")
+	mgr.OnTerminate = func(s *Session, cause TerminationCause) {
+		tc <- terminated{s, cause}
+	}
+	return mgr, tc
+}
+
+func waitTerminated(t *testing.T, tc chan terminated) terminated {
+	t.Helper()
+	select {
+	case v := <-tc:
+		return v
+	case <-time.After(time.Second):
+		t.Fatal("Session not terminated")
+	}
+	return terminated{}
+}
+
+func TestManager_Terminate(t *testing.T) {
+	srvMgr, tc := newTerminatingManager("srv")
+	mux := diam.NewServeMux()
+	mux.Handle("STR", srvMgr)
+	srv := diamtest.NewServer(mux, nil)
+	defer srv.Close()
+	c, err := diam.Dial(srv.Addr, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	mgr := NewManager("cli", "localhost")
+	s := mgr.New()
+	srvMgr.Add(s.ID())
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err = mgr.Terminate(ctx, c, s, 4, Logout); err != ErrUnknownPeer {
+		t.Fatalf("Unexpected error. Want %v, have %v", ErrUnknownPeer, err)
+	}
+	c.SetContext(smpeer.NewContext(c.Context(), &smpeer.Metadata{
+		OriginHost:  "srv",
+		OriginRealm: "localhost",
+	}))
+	a, err := mgr.Terminate(ctx, c, s, 4, Logout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rc := resultCode(t, a); rc != diam.Success {
+		t.Fatalf("Unexpected Result-Code. Want %d, have %d", diam.Success, rc)
+	}
+	if s.State() != Closed || mgr.Len() != 0 {
+		t.Fatal("Terminated session was not closed")
+	}
+	v := waitTerminated(t, tc)
+	if v.s.ID() != s.ID() || v.cause != Logout {
+		t.Fatalf("Unexpected termination of %s: %s", v.s.ID(), v.cause)
+	}
+	if srvMgr.Len() != 0 {
+		t.Fatal("Session terminated by the peer was not removed")
+	}
+	if _, err = mgr.Terminate(ctx, c, s, 4, Logout); err != ErrClosed {
+		t.Fatalf("Unexpected error. Want %v, have %v", ErrClosed, err)
+	}
+}
+
+func TestSession_OnAbort(t *testing.T) {
+	srvMgr := NewManager("srv", "localhost")
+	mux := diam.NewServeMux()
+	mux.Handle("ASR", srvMgr)
+	srv := diamtest.NewServer(mux, nil)
+	defer srv.Close()
+	cliMgr, tc := newTerminatingManager("cli")
+	cliMux := diam.NewServeMux()
+	cliMux.Handle("STR", cliMgr)
+	c, err := diam.Dial(srv.Addr, cliMux, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	accept := make(chan bool, 2)
+	accept <- false
+	accept <- true
+	s := srvMgr.New()
+	s.OnAbort(func(as *Session, m *diam.Message) bool {
+		return <-accept
+	})
+	cliMgr.Add(s.ID())
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	a, err := diam.SendRequest(ctx, c, newASR(s.ID()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rc := resultCode(t, a); rc != diam.UnableToComply {
+		t.Fatalf("Unexpected Result-Code. Want %d, have %d", diam.UnableToComply, rc)
+	}
+	if s.State() != Idle {
+		t.Fatalf("Unexpected state. Want %s, have %s", Idle, s.State())
+	}
+
+	a, err = diam.SendRequest(ctx, c, newASR(s.ID()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rc := resultCode(t, a); rc != diam.Success {
+		t.Fatalf("Unexpected Result-Code. Want %d, have %d", diam.Success, rc)
+	}
+	v := waitTerminated(t, tc)
+	if v.s.ID() != s.ID() || v.cause != Administrative {
+		t.Fatalf("Unexpected termination of %s: %s", v.s.ID(), v.cause)
+	}
+	for i := 0; s.State() != Closed; i++ {
+		if i == 100 {
+			t.Fatalf("Unexpected state. Want %s, have %s", Closed, s.State())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
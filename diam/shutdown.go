@@ -0,0 +1,184 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// ErrServerClosed is returned by Serve and ListenAndServe after a call
+// to Shutdown.
+var ErrServerClosed = errors.New("diam: Server closed")
+
+// Limits of the requests tracked for draining connections.
+const (
+	maxInflight       = 1 << 12
+	maxInflightAge    = time.Minute
+	drainPollInterval = 10 * time.Millisecond
+)
+
+// The Disconnecter interface is implemented by Handlers that disconnect
+// peers gracefully, such as with the Disconnect-Peer-Request of the base
+// protocol, before Server.Shutdown closes their connections.
+type Disconnecter interface {
+	// Disconnect tells the peer of c that the connection is about to
+	// be closed, until ctx is done. Requests received on c after that
+	// should be rejected.
+	Disconnect(ctx context.Context, c Conn) error
+}
+
+// The Drainer interface is implemented by Conns that track the requests
+// sent and received on them that are not answered yet.
+type Drainer interface {
+	// Drain waits until all the requests sent and received on the
+	// connection are answered, or ctx is done.
+	Drain(ctx context.Context) error
+}
+
+// Drain waits until all the requests sent and received on c are
+// answered, or ctx is done. It returns right away if c does not
+// implement Drainer.
+func Drain(ctx context.Context, c Conn) error {
+	if d, ok := c.(Drainer); ok {
+		return d.Drain(ctx)
+	}
+	return nil
+}
+
+// Shutdown gracefully shuts down the server: it closes its listeners,
+// disconnects the peers of its connections if the Handler implements
+// Disconnecter, waits for their requests to be answered, and then closes
+// the connections.
+//
+// Connections are closed when ctx is done even if requests are still
+// pending, and Shutdown returns the error of ctx.
+func (srv *Server) Shutdown(ctx context.Context) error {
+	atomic.StoreInt32(&srv.inShutdown, 1) // before taking the connections, see trackConn
+	srv.mu.Lock()
+	for l := range srv.listeners {
+		l.Close()
+	}
+	conns := make([]*conn, 0, len(srv.conns))
+	for c := range srv.conns {
+		conns = append(conns, c)
+	}
+	srv.mu.Unlock()
+	d, _ := serverHandler{srv}.handler().(Disconnecter)
+	var wg sync.WaitGroup
+	for _, c := range conns {
+		wg.Add(1)
+		go func(c *conn) {
+			defer wg.Done()
+			if d != nil {
+				if err := d.Disconnect(ctx, c.writer); err != nil {
+					srv.logger().Log(LevelWarn, "disconnect error", "conn", c.id, "err", err)
+				}
+			}
+			c.writer.Drain(ctx)
+			c.writer.Close()
+		}(c)
+	}
+	wg.Wait()
+	srv.stopWorkers()
+	return ctx.Err()
+}
+
+func (srv *Server) shuttingDown() bool {
+	return atomic.LoadInt32(&srv.inShutdown) != 0
+}
+
+// trackListener adds or removes the listener l of Serve.
+func (srv *Server) trackListener(l net.Listener, add bool) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if srv.listeners == nil {
+		srv.listeners = make(map[net.Listener]struct{})
+	}
+	if add {
+		srv.listeners[l] = struct{}{}
+	} else {
+		delete(srv.listeners, l)
+	}
+}
+
+// trackConn adds or removes the connection c being served. It does not
+// add c, and returns false, once Shutdown is called, so that Shutdown
+// does not miss the connections accepted while it closes the listeners.
+func (srv *Server) trackConn(c *conn, add bool) bool {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if srv.conns == nil {
+		srv.conns = make(map[*conn]struct{})
+	}
+	if !add {
+		delete(srv.conns, c)
+		return true
+	}
+	if srv.shuttingDown() {
+		return false
+	}
+	srv.conns[c] = struct{}{}
+	return true
+}
+
+// inflightKey identifies a request waiting for its answer.
+type inflightKey struct {
+	hbh      uint32
+	incoming bool
+}
+
+// trackMessage records the message m sent or received on the
+// connection, which starts or ends a transaction.
+func (c *conn) trackMessage(m *Message, incoming bool) {
+	now := time.Now()
+	c.imu.Lock()
+	defer c.imu.Unlock()
+	if m.Header.CommandFlags&RequestFlag == 0 {
+		delete(c.inflight, inflightKey{m.Header.HopByHopID, !incoming})
+		return
+	}
+	if c.inflight == nil {
+		c.inflight = make(map[inflightKey]time.Time)
+	}
+	if len(c.inflight) >= maxInflight {
+		// Drop requests that were never answered.
+		for k, t := range c.inflight {
+			if now.Sub(t) > maxInflightAge {
+				delete(c.inflight, k)
+			}
+		}
+		if len(c.inflight) >= maxInflight {
+			return
+		}
+	}
+	c.inflight[inflightKey{m.Header.HopByHopID, incoming}] = now
+}
+
+// Drain implements the Drainer interface.
+func (w *response) Drain(ctx context.Context) error {
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for {
+		w.conn.imu.Lock()
+		n := len(w.conn.inflight)
+		w.conn.imu.Unlock()
+		if n == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-w.conn.done:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+// Connections accepted while the server shuts down are closed, instead
+// of outliving Shutdown.
+func TestServer_Shutdown_LateConn(t *testing.T) {
+	srv := &Server{Dict: dict.Default}
+	srv.Shutdown(context.Background())
+	cli, rw := net.Pipe()
+	defer cli.Close()
+	c, err := srv.newConn(rw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	go func() {
+		c.serve()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Connection was served after Shutdown")
+	}
+	srv.mu.Lock()
+	n := len(srv.conns)
+	srv.mu.Unlock()
+	if n != 0 {
+		t.Fatalf("Unexpected number of connections. Want 0, have %d", n)
+	}
+	if _, err = cli.Read(make([]byte, 1)); err == nil {
+		t.Fatal("Connection was not closed")
+	}
+}
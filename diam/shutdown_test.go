@@ -0,0 +1,71 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam_test
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+func TestServer_Shutdown(t *testing.T) {
+	received := make(chan struct{})
+	smux := diam.NewServeMux()
+	smux.HandleFunc("ACR", func(c diam.Conn, m *diam.Message) {
+		close(received)
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			a := m.Answer(diam.Success)
+			a.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("srv"))
+			a.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity("test"))
+			a.WriteTo(c)
+		}()
+	})
+	srv := diamtest.NewServer(smux, dict.Default)
+	defer srv.Close()
+
+	cli, err := diam.Dial(srv.Addr, diam.NewServeMux(), dict.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cli.Close()
+	disconnect := cli.(diam.CloseNotifier).CloseNotify()
+	answerc := make(chan error, 1)
+	go func() {
+		m := diam.NewRequest(diam.Accounting, 3, dict.Default)
+		m.NewAVP(avp.SessionID, avp.Mbit, 0, datatype.UTF8String("sess"))
+		_, err := diam.SendRequest(context.Background(), cli, m)
+		answerc <- err
+	}()
+	<-received
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := srv.Config.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-answerc:
+		if err != nil {
+			t.Fatalf("Pending request failed: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Pending request was not answered")
+	}
+	select {
+	case <-disconnect:
+	case <-time.After(time.Second):
+		t.Fatal("Connection was not closed")
+	}
+	if err := srv.Config.Serve(srv.Listener); err != diam.ErrServerClosed {
+		t.Fatalf("Unexpected error. Want %v, have %v", diam.ErrServerClosed, err)
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import "github.com/omnicate/go-diameter/v4/diam"
+
+// Answer creates the answer to the request m with resultCode, sent with
+// the Origin-Host and Origin-Realm of the settings. The AVPs required by
+// RFC 6733 are copied from m, see diam.Message.AnswerFrom.
+func (sm *StateMachine) Answer(m *diam.Message, resultCode uint32) *diam.Message {
+	return m.AnswerFrom(resultCode, sm.cfg.OriginHost, sm.cfg.OriginRealm)
+}
+
+// WriteAnswer validates the answer a against the rules of its command in
+// the dictionary, and writes it to c. Invalid answers are not sent, and
+// the error of diam.Message.Validate is returned.
+func (sm *StateMachine) WriteAnswer(c diam.Conn, a *diam.Message) error {
+	if err := a.Validate(); err != nil {
+		return err
+	}
+	_, err := a.WriteTo(c)
+	return err
+}
+
+// AnswerFunc is the type of handlers that return the answer to the
+// request m, or an error that is answered with a protocol error answer.
+// Errors that implement diam.ValidationError, such as diam.ResultError,
+// are answered with their Result-Code and Failed-AVP, and others with
+// DIAMETER_UNABLE_TO_COMPLY. See diam.Message.ErrorAnswer.
+//
+// No answer is sent when both the answer and the error are nil.
+type AnswerFunc func(c diam.Conn, m *diam.Message) (*diam.Message, error)
+
+// HandleAnswer registers the AnswerFunc f for the command cmd, such as
+// "ACR". The requests are validated against the dictionary first when
+// Settings.ValidateRequests is set, and those that fail are answered
+// with the error of diam.Message.Validate without calling f.
+func (sm *StateMachine) HandleAnswer(cmd string, f AnswerFunc) {
+	sm.HandleFunc(cmd, sm.answerHandler(f))
+}
+
+// HandleTyped registers the typed function f for the command cmd, such
+// as "CCR". f takes and returns pointers to structs with avp tags, see
+// diam.TypedAnswer:
+//
+//	sm.HandleTyped("CCR", func(c diam.Conn, ccr *gx.CCR) (*gx.CCA, error) {
+//		...
+//	})
+//
+// The requests are handled as by HandleAnswer, and the errors of their
+// decoding, and those of f, are answered with a protocol error answer.
+// HandleTyped panics if f does not have the signature of
+// diam.TypedAnswer.
+func (sm *StateMachine) HandleTyped(cmd string, f interface{}) {
+	sm.HandleAnswer(cmd, diam.TypedAnswer(f))
+}
+
+func (sm *StateMachine) answerHandler(f AnswerFunc) diam.HandlerFunc {
+	return func(c diam.Conn, m *diam.Message) {
+		var (
+			a   *diam.Message
+			err error
+		)
+		if sm.cfg.ValidateRequests {
+			err = m.Validate()
+		}
+		if err == nil {
+			a, err = f(c, m)
+		}
+		if err != nil {
+			sm.Error(&diam.ErrorReport{Conn: c, Message: m, Error: err})
+			a = m.ErrorAnswer(err, sm.cfg.OriginHost, sm.cfg.OriginRealm)
+		}
+		if a == nil {
+			return
+		}
+		if _, err = a.WriteTo(c); err != nil {
+			sm.Error(&diam.ErrorReport{Conn: c, Message: m, Error: err})
+		}
+	}
+}
+
+// rejectUnsupported answers the request m with DIAMETER_AVP_UNSUPPORTED
+// when Settings.RejectUnsupportedAVPs is set and m has an unknown AVP
+// with the M bit set, and reports whether it did.
+func (sm *StateMachine) rejectUnsupported(c diam.Conn, m *diam.Message) bool {
+	if !sm.cfg.RejectUnsupportedAVPs {
+		return false
+	}
+	err := m.CheckUnsupportedAVPs()
+	if err == nil {
+		return false
+	}
+	sm.Error(&diam.ErrorReport{Conn: c, Message: m, Error: err})
+	a := m.ErrorAnswer(err, sm.cfg.OriginHost, sm.cfg.OriginRealm)
+	if _, err = a.WriteTo(c); err != nil {
+		sm.Error(&diam.ErrorReport{Conn: c, Message: m, Error: err})
+	}
+	return true
+}
@@ -0,0 +1,238 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"errors"
+	"io/ioutil"
+	"log"
+	"testing"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+func TestStateMachine_WriteAnswer(t *testing.T) {
+	srvSM := New(serverSettings)
+	errc := make(chan error, 2)
+	srvSM.HandleFunc("ACR", func(c diam.Conn, m *diam.Message) {
+		// The first answer lacks the required Accounting-Record-Type.
+		errc <- srvSM.WriteAnswer(c, srvSM.Answer(m, diam.Success))
+		a := srvSM.Answer(m, diam.Success)
+		a.NewAVP(avp.AccountingRecordType, avp.Mbit, 0, datatype.Enumerated(1))
+		a.NewAVP(avp.AccountingRecordNumber, avp.Mbit, 0, datatype.Unsigned32(0))
+		errc <- srvSM.WriteAnswer(c, a)
+	})
+	srv := diamtest.NewServer(srvSM, dict.Default)
+	defer srv.Close()
+
+	cli := newPeerClient(New(clientSettings), "")
+	c, err := cli.Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	a, err := cli.Send(c, newACR(cli))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e, ok := (<-errc).(*diam.RuleError); !ok || e.Rule.AVP != "Accounting-Record-Type" {
+		t.Fatalf("Unexpected error: %v", e)
+	}
+	if err = <-errc; err != nil {
+		t.Fatal(err)
+	}
+	if a.AVP[0].Code != avp.SessionID {
+		t.Fatalf("Session-Id is not the first AVP: %s", a.AVP[0])
+	}
+	if oh, err := a.FindAVP(avp.OriginHost, 0); err != nil || oh.Data != serverSettings.OriginHost {
+		t.Fatalf("Unexpected Origin-Host: %v", oh)
+	}
+}
+
+func TestStateMachine_HandleAnswer(t *testing.T) {
+	cfg := *serverSettings
+	cfg.ValidateRequests = true
+	srvSM := New(&cfg)
+	srvSM.HandleAnswer("ACR", func(c diam.Conn, m *diam.Message) (*diam.Message, error) {
+		n, err := m.FindAVP(avp.AccountingRecordNumber, 0)
+		if err != nil {
+			return nil, err
+		}
+		switch n.Data {
+		case datatype.Unsigned32(1):
+			return nil, &diam.ResultError{Code: diam.InvalidAVPValue, AVP: n}
+		case datatype.Unsigned32(2):
+			return nil, errors.New("failure")
+		}
+		a := srvSM.Answer(m, diam.Success)
+		return a, nil
+	})
+	srv := diamtest.NewServer(srvSM, dict.Default)
+	defer srv.Close()
+
+	cli := newPeerClient(New(clientSettings), "")
+	c, err := cli.Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	send := func(m *diam.Message) (uint32, *diam.AVP) {
+		t.Helper()
+		a, err := cli.Send(c, m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rc, err := a.FindAVP(avp.ResultCode, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		failed, _ := a.FindAVP(avp.FailedAVP, 0)
+		return uint32(rc.Data.(datatype.Unsigned32)), failed
+	}
+
+	if rc, _ := send(newACR(cli)); rc != diam.Success {
+		t.Fatalf("Unexpected Result-Code. Want %d, have %d", diam.Success, rc)
+	}
+	// The request lacks the required Accounting-Record-Type.
+	invalid := diam.NewRequest(diam.Accounting, 3, dict.Default)
+	for _, a := range newACR(cli).AVP[:4] {
+		invalid.AddAVP(a)
+	}
+	if rc, failed := send(invalid); rc != diam.MissingAVP || failed == nil {
+		t.Fatalf("Unexpected answer to invalid request: %d, Failed-AVP %v", rc, failed)
+	}
+	for i, want := range []uint32{diam.InvalidAVPValue, diam.UnableToComply} {
+		m := newACR(cli)
+		m.AVP[len(m.AVP)-1].Data = datatype.Unsigned32(i + 1)
+		if rc, _ := send(m); rc != want {
+			t.Fatalf("Unexpected Result-Code. Want %d, have %d", want, rc)
+		}
+	}
+}
+
+func TestStateMachine_RejectUnsupportedAVPs(t *testing.T) {
+	cfg := *serverSettings
+	cfg.RejectUnsupportedAVPs = true
+	srv := diamtest.NewServer(newACRServer(&cfg), dict.Default)
+	defer srv.Close()
+
+	cli := newPeerClient(New(clientSettings), "")
+	c, err := cli.Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	m := newACR(cli)
+	m.NewAVP(99999, avp.Mbit, 0, datatype.OctetString("mandatory"))
+	a, err := cli.Send(c, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rc, err := a.FindAVP(avp.ResultCode, 0); err != nil || rc.Data != datatype.Unsigned32(diam.AVPUnsupported) {
+		t.Fatalf("Unexpected Result-Code: %v", rc)
+	}
+	failed, err := a.FindAVP(avp.FailedAVP, 0)
+	if err != nil {
+		t.Fatal("No Failed-AVP in answer")
+	}
+	if g := failed.Data.(*diam.GroupedAVP); len(g.AVP) != 1 || g.AVP[0].Code != 99999 {
+		t.Fatalf("Unexpected Failed-AVP: %v", failed)
+	}
+}
+
+func TestStateMachine_RecoverPanics(t *testing.T) {
+	cfg := *serverSettings
+	cfg.RecoverPanics = true
+	cfg.Logger = &diam.StdLogger{Logger: log.New(ioutil.Discard, "", 0)}
+	srvSM := New(&cfg)
+	srvSM.HandleFunc("ACR", func(c diam.Conn, m *diam.Message) {
+		if n, _ := m.FindAVP(avp.AccountingRecordNumber, 0); n.Data == datatype.Unsigned32(1) {
+			panic("boom")
+		}
+		a := srvSM.Answer(m, diam.Success)
+		a.WriteTo(c)
+	})
+	srv := diamtest.NewServer(srvSM, dict.Default)
+	defer srv.Close()
+
+	cli := newPeerClient(New(clientSettings), "")
+	c, err := cli.Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	for n, want := range []uint32{diam.UnableToComply, diam.Success} {
+		m := diam.NewRequest(diam.Accounting, 3, dict.Default)
+		m.NewAVP(avp.SessionID, avp.Mbit, 0, datatype.UTF8String("sess"))
+		m.NewAVP(avp.OriginHost, avp.Mbit, 0, clientSettings.OriginHost)
+		m.NewAVP(avp.OriginRealm, avp.Mbit, 0, clientSettings.OriginRealm)
+		m.NewAVP(avp.DestinationRealm, avp.Mbit, 0, serverSettings.OriginRealm)
+		m.NewAVP(avp.AccountingRecordType, avp.Mbit, 0, datatype.Enumerated(1))
+		m.NewAVP(avp.AccountingRecordNumber, avp.Mbit, 0, datatype.Unsigned32(n+1))
+		a, err := cli.Send(c, m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code, _ := diam.ResultCode(a); code != want {
+			t.Fatalf("Unexpected Result-Code. Want %d, have %d", want, code)
+		}
+	}
+}
+
+type typedACR struct {
+	SessionID    datatype.UTF8String `avp:"Session-Id"`
+	RecordType   datatype.Enumerated `avp:"Accounting-Record-Type"`
+	RecordNumber uint32              `avp:"Accounting-Record-Number"`
+}
+
+type typedACA struct {
+	SessionID    datatype.UTF8String       `avp:"Session-Id"`
+	ResultCode   uint32                    `avp:"Result-Code"`
+	OriginHost   datatype.DiameterIdentity `avp:"Origin-Host"`
+	OriginRealm  datatype.DiameterIdentity `avp:"Origin-Realm"`
+	RecordType   datatype.Enumerated       `avp:"Accounting-Record-Type"`
+	RecordNumber uint32                    `avp:"Accounting-Record-Number"`
+}
+
+func TestStateMachine_HandleTyped(t *testing.T) {
+	srvSM := New(serverSettings)
+	srvSM.HandleTyped("ACR", func(c diam.Conn, acr *typedACR) (*typedACA, error) {
+		if acr.RecordNumber == 1 {
+			return nil, &diam.ResultError{Code: diam.InvalidAVPValue}
+		}
+		return &typedACA{
+			SessionID:    acr.SessionID,
+			ResultCode:   diam.Success,
+			OriginHost:   serverSettings.OriginHost,
+			OriginRealm:  serverSettings.OriginRealm,
+			RecordType:   acr.RecordType,
+			RecordNumber: acr.RecordNumber,
+		}, nil
+	})
+	srv := diamtest.NewServer(srvSM, dict.Default)
+	defer srv.Close()
+
+	cli := newPeerClient(New(clientSettings), "")
+	c, err := cli.Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	for i, want := range []uint32{diam.Success, diam.InvalidAVPValue} {
+		m := newACR(cli)
+		m.AVP[len(m.AVP)-1].Data = datatype.Unsigned32(i)
+		a, err := cli.Send(c, m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code, _ := diam.ResultCode(a); code != want {
+			t.Fatalf("Unexpected Result-Code. Want %d, have %d", want, code)
+		}
+	}
+}
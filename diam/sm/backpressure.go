@@ -0,0 +1,192 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/sm/smpeer"
+)
+
+// DefaultBreakerTimeout is the time the circuit of a peer stays open
+// when the BackpressurePolicy has no BreakerTimeout.
+const DefaultBreakerTimeout = 30 * time.Second
+
+// ErrCircuitOpen is returned by Send when the circuit of the peer of the
+// connection is open, and there is no alternate peer.
+var ErrCircuitOpen = errors.New("peer circuit is open")
+
+// BackpressurePolicy configures how Send handles DIAMETER_TOO_BUSY and
+// DIAMETER_UNABLE_TO_DELIVER answers: requests may be sent again, after
+// a backoff delay, on alternate peers, and peers that keep answering
+// them are avoided for a while by opening their circuit.
+//
+// The policy holds the circuit state of the peers, and must not be
+// copied after first use. It is safe for concurrent use.
+type BackpressurePolicy struct {
+	// Retries is the number of times a request answered with
+	// DIAMETER_TOO_BUSY or DIAMETER_UNABLE_TO_DELIVER is sent again.
+	// The last answer is returned when they are exhausted.
+	Retries int
+
+	// Alternate sends the retries to an alternate peer selected by the
+	// Client's Routes, other than the peers that answered, when there
+	// is one. Retries are sent on the same connection otherwise, as
+	// required for DIAMETER_TOO_BUSY by RFC 6733 section 7.1.3 when
+	// there is no alternate peer.
+	Alternate bool
+
+	// Backoff is the delay before the first retry, doubled after each
+	// one up to MaxBackoff. Retries are not delayed when zero.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+
+	// BreakerThreshold is the number of consecutive DIAMETER_TOO_BUSY
+	// or DIAMETER_UNABLE_TO_DELIVER answers of a peer that open its
+	// circuit. Requests are not routed to peers with an open circuit,
+	// and Send returns ErrCircuitOpen for their connections when there
+	// is no alternate peer. Circuits are never opened when zero.
+	BreakerThreshold int
+
+	// BreakerTimeout is the time a circuit stays open, after which
+	// requests are sent to the peer again: the circuit is closed by the
+	// first answer that is not busy, and opened again by the first one
+	// that is. Defaults to DefaultBreakerTimeout.
+	BreakerTimeout time.Duration
+
+	mu    sync.Mutex // guards peers
+	peers map[datatype.DiameterIdentity]*circuit
+}
+
+type circuit struct {
+	failures  int       // consecutive busy answers
+	openUntil time.Time // circuit is open until then
+}
+
+// CircuitOpen returns whether the circuit of the peer host is open.
+func (p *BackpressurePolicy) CircuitOpen(host datatype.DiameterIdentity) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	cb, ok := p.peers[host]
+	return ok && time.Now().Before(cb.openUntil)
+}
+
+// failure counts a busy answer of the peer host, and opens its circuit
+// when the threshold is reached.
+func (p *BackpressurePolicy) failure(host datatype.DiameterIdentity) {
+	if p.BreakerThreshold <= 0 || len(host) == 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.peers == nil {
+		p.peers = make(map[datatype.DiameterIdentity]*circuit)
+	}
+	cb, ok := p.peers[host]
+	if !ok {
+		cb = &circuit{}
+		p.peers[host] = cb
+	}
+	if cb.failures++; cb.failures >= p.BreakerThreshold {
+		timeout := p.BreakerTimeout
+		if timeout <= 0 {
+			timeout = DefaultBreakerTimeout
+		}
+		cb.openUntil = time.Now().Add(timeout)
+	}
+}
+
+// success closes the circuit of the peer host.
+func (p *BackpressurePolicy) success(host datatype.DiameterIdentity) {
+	p.mu.Lock()
+	delete(p.peers, host)
+	p.mu.Unlock()
+}
+
+// backoff returns the delay before the retry n, from 0.
+func (p *BackpressurePolicy) backoff(n int) time.Duration {
+	d := p.Backoff
+	for i := 0; i < n && d > 0; i++ {
+		if d *= 2; p.MaxBackoff > 0 && d >= p.MaxBackoff {
+			return p.MaxBackoff
+		}
+	}
+	return d
+}
+
+// sendBackpressure sends the request m on c like transmit, and handles
+// busy answers according to the Client's Backpressure policy.
+func (cli *Client) sendBackpressure(ctx context.Context, c diam.Conn, m *diam.Message) (*diam.Message, error) {
+	p := cli.Backpressure
+	tried := []diam.Conn{c}
+	if p.CircuitOpen(peerHost(c)) {
+		alt, err := cli.nextHop(m, tried...)
+		if err != nil {
+			return nil, ErrCircuitOpen
+		}
+		c = alt
+	}
+	for i := 0; ; i++ {
+		a, err := cli.transmit(ctx, c, m)
+		if err != nil {
+			return nil, err
+		}
+		if !busyAnswer(a) {
+			p.success(peerHost(c))
+			return a, nil
+		}
+		p.failure(peerHost(c))
+		if i >= p.Retries {
+			return a, nil
+		}
+		tried = append(tried, c)
+		if p.Alternate {
+			if alt, err := cli.nextHop(m, tried...); err == nil {
+				c = alt
+			}
+		}
+		if p.CircuitOpen(peerHost(c)) {
+			return a, nil
+		}
+		if d := p.backoff(i); d > 0 {
+			select {
+			case <-time.After(d):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		m.Header.HopByHopID = rand.Uint32()
+		m.Header.CommandFlags &^= diam.RetransmittedFlag
+	}
+}
+
+// busyAnswer returns whether a is a DIAMETER_TOO_BUSY or
+// DIAMETER_UNABLE_TO_DELIVER answer.
+func busyAnswer(a *diam.Message) bool {
+	for _, v := range a.AVP {
+		if v.Code == avp.ResultCode {
+			return v.Data == datatype.Unsigned32(diam.TooBusy) ||
+				v.Data == datatype.Unsigned32(diam.UnableToDeliver)
+		}
+	}
+	return false
+}
+
+// peerHost returns the identity of the peer of the connection c, or an
+// empty identity before the capabilities exchange.
+func peerHost(c diam.Conn) datatype.DiameterIdentity {
+	if meta, ok := smpeer.FromContext(c.Context()); ok {
+		return meta.OriginHost
+	}
+	return ""
+}
@@ -0,0 +1,124 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+// newBusyServer returns a server that answers every ACR with
+// DIAMETER_TOO_BUSY, and counts them in n.
+func newBusyServer(n *int32) *diamtest.Server {
+	sm := New(serverSettings)
+	sm.HandleFunc("ACR", func(c diam.Conn, m *diam.Message) {
+		atomic.AddInt32(n, 1)
+		a := m.Answer(diam.TooBusy)
+		a.Header.CommandFlags |= diam.ErrorFlag
+		a.NewAVP(avp.OriginHost, avp.Mbit, 0, serverSettings.OriginHost)
+		a.NewAVP(avp.OriginRealm, avp.Mbit, 0, serverSettings.OriginRealm)
+		a.WriteTo(c)
+	})
+	return diamtest.NewServer(sm, dict.Default)
+}
+
+func TestClient_Send_BackpressureAlternate(t *testing.T) {
+	var busy int32
+	srv1 := newBusyServer(&busy)
+	defer srv1.Close()
+	srv2 := diamtest.NewServer(newACRServer(serverSettings2), dict.Default)
+	defer srv2.Close()
+
+	cli := newPeerClient(New(clientSettings), "")
+	cli.Routes = NewRoutingTable()
+	cli.Routes.Add(serverSettings.OriginRealm, 3,
+		Route{Host: serverSettings.OriginHost, Priority: 1},
+		Route{Host: serverSettings2.OriginHost, Priority: 2},
+	)
+	cli.Backpressure = &BackpressurePolicy{
+		Retries:          1,
+		Alternate:        true,
+		BreakerThreshold: 1,
+		BreakerTimeout:   time.Minute,
+	}
+	c1, err := cli.Dial(srv1.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c1.Close()
+	c2, err := cli.Dial(srv2.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c2.Close()
+	waitPeerState(cli.Handler, serverSettings2.OriginHost, ROpen)
+
+	a, err := cli.Send(c1, newACR(cli))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if oh, _ := a.FindAVP(avp.OriginHost, 0); oh == nil || oh.Data != serverSettings2.OriginHost {
+		t.Fatalf("Request was not sent to the alternate peer: %s", a)
+	}
+	if !cli.Backpressure.CircuitOpen(serverSettings.OriginHost) {
+		t.Fatal("Circuit of the busy peer was not opened")
+	}
+	// The busy peer is skipped while its circuit is open.
+	if _, err = cli.Send(nil, newACR(cli)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = cli.Send(c1, newACR(cli)); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&busy); n != 1 {
+		t.Fatalf("Unexpected number of requests to the busy peer. Want 1, have %d", n)
+	}
+}
+
+func TestClient_Send_BackpressureRetries(t *testing.T) {
+	var busy int32
+	srv := newBusyServer(&busy)
+	defer srv.Close()
+
+	cli := newPeerClient(New(clientSettings), "")
+	cli.Backpressure = &BackpressurePolicy{
+		Retries:          2,
+		Backoff:          10 * time.Millisecond,
+		BreakerThreshold: 3,
+	}
+	c, err := cli.Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	a, err := cli.Send(c, newACR(cli))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !busyAnswer(a) {
+		t.Fatalf("Unexpected answer: %s", a)
+	}
+	if n := atomic.LoadInt32(&busy); n != 3 {
+		t.Fatalf("Unexpected number of requests. Want 3, have %d", n)
+	}
+	if _, err = cli.Send(c, newACR(cli)); err != ErrCircuitOpen {
+		t.Fatalf("Unexpected error. Want %v, have %v", ErrCircuitOpen, err)
+	}
+}
+
+func TestBackpressurePolicy_Backoff(t *testing.T) {
+	p := &BackpressurePolicy{Backoff: time.Second, MaxBackoff: 3 * time.Second}
+	for i, want := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second} {
+		if d := p.backoff(i); d != want {
+			t.Fatalf("Unexpected backoff of retry %d. Want %s, have %s", i, want, d)
+		}
+	}
+}
@@ -18,13 +18,29 @@ func handleCEA(sm *StateMachine, errc chan error) diam.HandlerFunc {
 			errc <- err
 			return
 		}
+		if err := sm.checkSecurity(c, cea.InbandSecurityID); err != nil {
+			errc <- err
+			return
+		}
+		if cea.InbandSecurityID != smparser.InbandSecurityTLS {
+			if err := sm.verifyPeer(c, cea.OriginHost); err != nil {
+				errc <- err
+				return
+			}
+		}
+		err := sm.upgradeTLS(c, cea.OriginHost, cea.InbandSecurityID, true, nil)
+		if err != nil {
+			errc <- err
+			return
+		}
+		if err := sm.peers.iRcvCEA(c, cea.OriginHost); err != nil {
+			errc <- err
+			return
+		}
 		meta := smpeer.FromCEA(cea)
 		c.SetContext(smpeer.NewContext(c.Context(), meta))
-		// Notify about peer passing the handshake.
-		select {
-		case sm.hsNotifyc <- c:
-		default:
-		}
+		sm.checkOriginState(c, cea.OriginHost, cea.OriginStateID)
+		sm.notifyHandshake(c, cea.OriginHost)
 		// Done receiving and validating this CEA.
 		close(errc)
 	}
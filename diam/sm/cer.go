@@ -17,9 +17,10 @@ import (
 // handleCER handles Capabilities-Exchange-Request messages.
 //
 // If mandatory AVPs such as Origin-Host or Origin-Realm
-// are missing, we close the connection.
+// are missing, we close the connection. Valid CERs are processed by
+// the peer state machine, which may delay or reject the answer.
 //
-// See RFC 6733 section 5.3 for details.
+// See RFC 6733 sections 5.3 and 5.6 for details.
 func handleCER(sm *StateMachine) diam.HandlerFunc {
 	return func(c diam.Conn, m *diam.Message) {
 		ctx := c.Context()
@@ -28,7 +29,7 @@ func handleCER(sm *StateMachine) diam.HandlerFunc {
 			return
 		}
 		cer := new(smparser.CER)
-		_, err := cer.Parse(m, smparser.Server)
+		_, err := cer.ParseSecurity(m, smparser.Server, sm.inbandSecurity(c)...)
 		if err != nil {
 			err = errorCEA(sm, c, m, cer, err)
 			if err != nil {
@@ -41,51 +42,80 @@ func handleCER(sm *StateMachine) diam.HandlerFunc {
 			c.Close()
 			return
 		}
-		err = successCEA(sm, c, m, cer)
-
-		if err != nil {
-			sm.Error(&diam.ErrorReport{
-				Conn:    c,
-				Message: m,
-				Error:   err,
-			})
+		if err = sm.admitCER(c, cer); err != nil {
+			sm.Error(&diam.ErrorReport{Conn: c, Message: m, Error: err})
+			if err = errorCEA(sm, c, m, cer, err); err != nil {
+				sm.Error(&diam.ErrorReport{Conn: c, Message: m, Error: err})
+			}
+			c.Close()
 			return
 		}
-		meta := smpeer.FromCER(cer)
-		c.SetContext(smpeer.NewContext(ctx, meta))
-		// Notify about peer passing the handshake.
-		select {
-		case sm.hsNotifyc <- c:
-		default:
+		if sm.peers.rConnCER(c, m, cer) {
+			sm.acceptCER(c, m, cer)
+		}
+	}
+}
+
+// acceptCER sends a success CEA in response to the CER m and associates
+// the peer metadata with the connection c. The peer is verified before
+// the CEA, and answered with an error CEA when it fails, unless the
+// connection is upgraded to TLS after the CEA: it is then verified after
+// the upgrade, and the connection closed when the upgrade or the
+// verification fail.
+func (sm *StateMachine) acceptCER(c diam.Conn, m *diam.Message, cer *smparser.CER) {
+	if cer.Security() != smparser.InbandSecurityTLS {
+		if err := sm.verifyPeer(c, cer.OriginHost); err != nil {
+			sm.Error(&diam.ErrorReport{Conn: c, Message: m, Error: err})
+			if err = errorCEA(sm, c, m, cer, err); err != nil {
+				sm.Error(&diam.ErrorReport{Conn: c, Message: m, Error: err})
+			}
+			c.Close()
+			return
 		}
 	}
+	err := sm.upgradeTLS(c, cer.OriginHost, cer.Security(), false, func() error {
+		return successCEA(sm, c, m, cer)
+	})
+	if err != nil {
+		sm.Error(&diam.ErrorReport{
+			Conn:    c,
+			Message: m,
+			Error:   err,
+		})
+		c.Close()
+		return
+	}
+	meta := smpeer.FromCER(cer)
+	c.SetContext(smpeer.NewContext(c.Context(), meta))
+	sm.checkOriginState(c, cer.OriginHost, meta.OriginStateID)
+	sm.notifyHandshake(c, cer.OriginHost)
 }
 
 // errorCEA sends an error answer indicating that the CER failed due to
 // an unsupported (acct/auth) application, and includes the AVP that
 // caused the failure in the message.
 func errorCEA(sm *StateMachine, c diam.Conn, m *diam.Message, cer *smparser.CER, errMessage error) error {
-	var (
-		hostAddresses []datatype.Address
-		err           error
-	)
-	if len(sm.cfg.HostIPAddresses) > 0 {
-		hostAddresses = sm.cfg.HostIPAddresses
-	} else {
-		hostAddresses, err = getLocalAddresses(c)
-		if err != nil {
-			return fmt.Errorf("Error CEA '%s' create failure: %v", errMessage, err)
-		}
+	hostAddresses, err := sm.hostAddresses(c, cer.OriginHost)
+	if err != nil {
+		return fmt.Errorf("Error CEA '%s' create failure: %v", errMessage, err)
 	}
 
 	var a *diam.Message
+	var failedAVP *diam.AVP
 	switch errMessage {
 	case smparser.ErrNoCommonSecurity:
 		a = m.Answer(diam.NoCommonSecurity)
 	case smparser.ErrNoCommonApplication:
 		a = m.Answer(diam.NoCommonApplication)
+	case ErrUnknownPeer:
+		a = m.Answer(diam.UnknownPeer)
 	default:
-		a = m.Answer(diam.UnableToComply)
+		if re, ok := errMessage.(*diam.ResultError); ok {
+			a = m.Answer(re.Code)
+			failedAVP = re.AVP
+		} else {
+			a = m.Answer(diam.UnableToComply)
+		}
 	}
 	a.Header.CommandFlags |= diam.ErrorFlag
 	a.NewAVP(avp.OriginHost, avp.Mbit, 0, sm.cfg.OriginHost)
@@ -94,13 +124,14 @@ func errorCEA(sm *StateMachine, c diam.Conn, m *diam.Message, cer *smparser.CER,
 		a.NewAVP(avp.HostIPAddress, avp.Mbit, 0, hostAddress)
 	}
 	a.NewAVP(avp.VendorID, avp.Mbit, 0, sm.cfg.VendorID)
-	a.NewAVP(avp.ProductName, 0, 0, sm.cfg.ProductName)
-	if cer.OriginStateID != nil {
-		a.AddAVP(cer.OriginStateID)
-	}
+	a.NewAVP(avp.ProductName, 0, 0, sm.productName(cer.OriginHost))
+	a.NewAVP(avp.OriginStateID, avp.Mbit, 0, sm.cfg.OriginStateID)
 	if sm.cfg.FirmwareRevision != 0 {
 		a.NewAVP(avp.FirmwareRevision, 0, 0, sm.cfg.FirmwareRevision)
 	}
+	if failedAVP != nil {
+		a.NewAVP(avp.FailedAVP, avp.Mbit, 0, &diam.GroupedAVP{AVP: []*diam.AVP{failedAVP}})
+	}
 	_, err = a.WriteTo(c)
 	if err != nil {
 		err = fmt.Errorf("Error CEA '%s' send failure: %v", errMessage, err)
@@ -111,17 +142,9 @@ func errorCEA(sm *StateMachine, c diam.Conn, m *diam.Message, cer *smparser.CER,
 // successCEA sends a success answer indicating that the CER was successfully
 // parsed and accepted by the server.
 func successCEA(sm *StateMachine, c diam.Conn, m *diam.Message, cer *smparser.CER) error {
-	var (
-		hostAddresses []datatype.Address
-		err           error
-	)
-	if len(sm.cfg.HostIPAddresses) > 0 {
-		hostAddresses = sm.cfg.HostIPAddresses
-	} else {
-		hostAddresses, err = getLocalAddresses(c)
-		if err != nil {
-			return err
-		}
+	hostAddresses, err := sm.hostAddresses(c, cer.OriginHost)
+	if err != nil {
+		return err
 	}
 
 	a := m.Answer(diam.Success)
@@ -131,11 +154,16 @@ func successCEA(sm *StateMachine, c diam.Conn, m *diam.Message, cer *smparser.CE
 		a.NewAVP(avp.HostIPAddress, avp.Mbit, 0, hostAddress)
 	}
 	a.NewAVP(avp.VendorID, avp.Mbit, 0, sm.cfg.VendorID)
-	a.NewAVP(avp.ProductName, 0, 0, sm.cfg.ProductName)
-	if cer.OriginStateID != nil {
-		a.AddAVP(cer.OriginStateID)
+	a.NewAVP(avp.ProductName, 0, 0, sm.productName(cer.OriginHost))
+	a.NewAVP(avp.OriginStateID, avp.Mbit, 0, sm.cfg.OriginStateID)
+	peer := sm.peerConfig(cer.OriginHost)
+	for _, id := range sm.supportedVendors(peer) {
+		a.NewAVP(avp.SupportedVendorID, avp.Mbit, 0, datatype.Unsigned32(id))
 	}
 	for _, app := range sm.supportedApps {
+		if !peer.advertises(app.ID) {
+			continue
+		}
 		var typ uint32
 		switch app.AppType {
 		case "auth":
@@ -144,7 +172,6 @@ func successCEA(sm *StateMachine, c diam.Conn, m *diam.Message, cer *smparser.CE
 			typ = avp.AcctApplicationID
 		}
 		if app.Vendor != 0 {
-			a.NewAVP(avp.SupportedVendorID, avp.Mbit, 0, datatype.Unsigned32(app.Vendor))
 			a.NewAVP(avp.VendorSpecificApplicationID, avp.Mbit, 0, &diam.GroupedAVP{
 				AVP: []*diam.AVP{
 					diam.NewAVP(avp.VendorID, avp.Mbit, 0, datatype.Unsigned32(app.Vendor)),
@@ -155,9 +182,38 @@ func successCEA(sm *StateMachine, c diam.Conn, m *diam.Message, cer *smparser.CE
 			a.NewAVP(typ, avp.Mbit, 0, datatype.Unsigned32(app.ID))
 		}
 	}
+	if len(cer.InbandSecurityID) > 0 {
+		a.NewAVP(avp.InbandSecurityID, avp.Mbit, 0, datatype.Unsigned32(cer.Security()))
+	}
 	if sm.cfg.FirmwareRevision != 0 {
 		a.NewAVP(avp.FirmwareRevision, 0, 0, sm.cfg.FirmwareRevision)
 	}
+	for _, ca := range sm.cfg.CapabilitiesAVPs {
+		a.AddAVP(ca)
+	}
 	_, err = a.WriteTo(c)
 	return err
 }
+
+// supportedVendors returns the Supported-Vendor-Id values to advertise
+// in CEAs to the peer: the vendors of the applications advertised to
+// it, followed by Settings.SupportedVendorIDs, without duplicates.
+func (sm *StateMachine) supportedVendors(peer *PeerConfig) []uint32 {
+	var ids []uint32
+	seen := make(map[uint32]bool)
+	add := func(id uint32) {
+		if id != 0 && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, app := range sm.supportedApps {
+		if peer.advertises(app.ID) {
+			add(app.Vendor)
+		}
+	}
+	for _, id := range sm.cfg.SupportedVendorIDs {
+		add(id)
+	}
+	return ids
+}
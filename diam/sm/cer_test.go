@@ -5,6 +5,8 @@
 package sm
 
 import (
+	"crypto/tls"
+	"net"
 	"testing"
 	"time"
 
@@ -534,3 +536,115 @@ func TestHandleCER_InbandSecurity(t *testing.T) {
 		t.Fatal("No message received")
 	}
 }
+
+// countAVPs returns the number of AVPs of m with the given code, and the
+// data of the first one.
+func countAVPs(m *diam.Message, code uint32) (int, datatype.Type) {
+	avps, _ := m.FindAVPs(code, 0)
+	if len(avps) == 0 {
+		return 0, nil
+	}
+	return len(avps), avps[0].Data
+}
+
+func TestHandleCER_Capabilities(t *testing.T) {
+	settings := *serverSettings
+	settings.HostIPAddresses = []datatype.Address{localhostAddress, datatype.Address(net.ParseIP("10.0.0.1"))}
+	settings.SupportedVendorIDs = []uint32{10415, 10415, 13}
+	settings.CapabilitiesAVPs = []*diam.AVP{diam.NewAVP(avp.ErrorMessage, 0, 0, datatype.UTF8String("extra"))}
+	sm := New(&settings)
+	srv := diamtest.NewServer(sm, dict.Default)
+	defer srv.Close()
+	mc := make(chan *diam.Message, 1)
+	mux := diam.NewServeMux()
+	mux.HandleFunc("CEA", func(c diam.Conn, m *diam.Message) {
+		mc <- m
+	})
+	cli, err := diam.Dial(srv.Addr, mux, dict.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cli.Close()
+	m := diam.NewRequest(diam.CapabilitiesExchange, 0, dict.Default)
+	m.NewAVP(avp.OriginHost, avp.Mbit, 0, clientSettings.OriginHost)
+	m.NewAVP(avp.OriginRealm, avp.Mbit, 0, clientSettings.OriginRealm)
+	m.NewAVP(avp.HostIPAddress, avp.Mbit, 0, localhostAddress)
+	m.NewAVP(avp.VendorID, avp.Mbit, 0, clientSettings.VendorID)
+	m.NewAVP(avp.ProductName, 0, 0, clientSettings.ProductName)
+	m.NewAVP(avp.AcctApplicationID, avp.Mbit, 0, datatype.Unsigned32(3))
+	if _, err = m.WriteTo(cli); err != nil {
+		t.Fatal(err)
+	}
+	var cea *diam.Message
+	select {
+	case cea = <-mc:
+	case err := <-mux.ErrorReports():
+		t.Fatal(err)
+	case <-time.After(time.Second):
+		t.Fatal("No message received")
+	}
+	if !testResultCode(cea, diam.Success) {
+		t.Fatalf("Unexpected result code.\n%s", cea)
+	}
+	if n, _ := countAVPs(cea, avp.HostIPAddress); n != 2 {
+		t.Fatalf("Unexpected number of Host-IP-Address. Want 2, have %d", n)
+	}
+	if n, _ := countAVPs(cea, avp.SupportedVendorID); n != 2 {
+		t.Fatalf("Unexpected number of Supported-Vendor-Id. Want 2, have %d", n)
+	}
+	if _, v := countAVPs(cea, avp.FirmwareRevision); v != settings.FirmwareRevision {
+		t.Fatalf("Unexpected Firmware-Revision. Want %v, have %v", settings.FirmwareRevision, v)
+	}
+	if _, v := countAVPs(cea, avp.ErrorMessage); v != datatype.UTF8String("extra") {
+		t.Fatalf("Unexpected custom AVP: %v", v)
+	}
+}
+
+func TestHandleCER_WorkerPool(t *testing.T) {
+	testHandleCER_Concurrency(t, diam.WorkerPool)
+}
+
+func TestHandleCER_SessionOrdered(t *testing.T) {
+	testHandleCER_Concurrency(t, diam.SessionOrdered)
+}
+
+// testHandleCER_Concurrency checks that the requests sent right after
+// the handshake are handled when the server runs its handlers in
+// workers, while the CER is still being processed.
+func testHandleCER_Concurrency(t *testing.T, model diam.ConcurrencyModel) {
+	const n = 20
+	settings := *serverSettings
+	settings.VerifyPeer = func(host datatype.DiameterIdentity, state *tls.ConnectionState) error {
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	}
+	received := make(chan struct{}, n)
+	sm := New(&settings)
+	sm.HandleFunc("ACR", func(c diam.Conn, m *diam.Message) {
+		received <- struct{}{}
+	})
+	srv := diamtest.NewUnstartedServer(sm, dict.Default)
+	srv.Config.Concurrency = model
+	srv.Config.Workers = 4
+	srv.Start()
+	defer srv.Close()
+
+	cli := newPeerClient(New(clientSettings), "")
+	c, err := cli.Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	for i := 0; i < n; i++ {
+		if _, err = newACR(cli).WriteTo(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < n; i++ {
+		select {
+		case <-received:
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out: %d of %d requests handled", i, n)
+		}
+	}
+}
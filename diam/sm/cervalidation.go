@@ -0,0 +1,148 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/sm/smparser"
+)
+
+var (
+	// ErrUnknownPeer is returned by the CER validation when the peer is
+	// not allowed to connect. It is answered with DIAMETER_UNKNOWN_PEER.
+	ErrUnknownPeer = errors.New("unknown peer")
+
+	// ErrHostIPAddressMismatch is returned by the CER validation when
+	// none of the Host-IP-Address AVPs of the CER is the address the
+	// connection comes from.
+	ErrHostIPAddressMismatch = errors.New("Host-IP-Address does not match the transport address")
+
+	// ErrUnexpectedVendorID is returned by the CER validation when the
+	// Vendor-Id of the CER is not allowed.
+	ErrUnexpectedVendorID = errors.New("unexpected Vendor-Id")
+)
+
+// CERValidation configures the checks of the CERs received by the state
+// machine, in addition to those of RFC 6733. Each check is enabled when
+// its field is set, and CERs that fail are answered with an error CEA,
+// after which the connection is closed.
+type CERValidation struct {
+	// OriginHosts are the Origin-Host of the peers allowed to connect,
+	// others are rejected with DIAMETER_UNKNOWN_PEER.
+	OriginHosts []datatype.DiameterIdentity
+
+	// OriginRealms are the Origin-Realm of the peers allowed to
+	// connect, others are rejected with DIAMETER_UNKNOWN_PEER.
+	OriginRealms []datatype.DiameterIdentity
+
+	// RejectUnknownPeers rejects the peers that are not configured in
+	// Settings.Peers with DIAMETER_UNKNOWN_PEER.
+	RejectUnknownPeers bool
+
+	// CheckHostIPAddress rejects the CERs with no Host-IP-Address
+	// matching the remote address of the connection, such as those of
+	// peers behind a NAT, with DIAMETER_UNABLE_TO_COMPLY.
+	CheckHostIPAddress bool
+
+	// VendorIDs are the Vendor-Id allowed in CERs, others are rejected
+	// with DIAMETER_UNABLE_TO_COMPLY.
+	VendorIDs []uint32
+}
+
+// CERAdmissionFunc decides whether the peer of the CER cer, received on
+// the connection c, is admitted, for example from an external inventory
+// of peers. It is called after the checks of RFC 6733 and CERValidation,
+// before the CEA is sent.
+//
+// It returns nil to admit the peer. Other errors are answered with an
+// error CEA, after which the connection is closed: *diam.ResultError
+// with its Result-Code and Failed-AVP, ErrUnknownPeer with
+// DIAMETER_UNKNOWN_PEER, and others with DIAMETER_UNABLE_TO_COMPLY.
+type CERAdmissionFunc func(c diam.Conn, cer *smparser.CER) error
+
+// HandleCERAdmission registers the function that decides whether the
+// peers of the CERs received are admitted.
+func (sm *StateMachine) HandleCERAdmission(f CERAdmissionFunc) {
+	sm.admissionFn.Store(f)
+}
+
+// admitCER checks the CER cer received on the connection c with the
+// CERValidation and the CERAdmissionFunc of the state machine.
+func (sm *StateMachine) admitCER(c diam.Conn, cer *smparser.CER) error {
+	if v := sm.cfg.CERValidation; v != nil {
+		if err := v.validate(sm, c, cer); err != nil {
+			return err
+		}
+	}
+	if f, _ := sm.admissionFn.Load().(CERAdmissionFunc); f != nil {
+		return f(c, cer)
+	}
+	return nil
+}
+
+// validate checks the CER cer received on the connection c.
+func (v *CERValidation) validate(sm *StateMachine, c diam.Conn, cer *smparser.CER) error {
+	if len(v.OriginHosts) > 0 && !containsIdentity(v.OriginHosts, cer.OriginHost) {
+		return ErrUnknownPeer
+	}
+	if len(v.OriginRealms) > 0 && !containsIdentity(v.OriginRealms, cer.OriginRealm) {
+		return ErrUnknownPeer
+	}
+	if v.RejectUnknownPeers {
+		if _, ok := sm.cfg.Peers[cer.OriginHost]; !ok {
+			return ErrUnknownPeer
+		}
+	}
+	if v.CheckHostIPAddress && !matchHostIPAddress(c.RemoteAddr(), cer.HostIPAddress) {
+		return ErrHostIPAddressMismatch
+	}
+	if len(v.VendorIDs) > 0 {
+		for _, id := range v.VendorIDs {
+			if id == cer.VendorID {
+				return nil
+			}
+		}
+		return ErrUnexpectedVendorID
+	}
+	return nil
+}
+
+func containsIdentity(ids []datatype.DiameterIdentity, id datatype.DiameterIdentity) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+// matchHostIPAddress returns whether one of the Host-IP-Address AVPs
+// is one of the addresses of addr, which has several for SCTP.
+func matchHostIPAddress(addr net.Addr, avps []*diam.AVP) bool {
+	if addr == nil {
+		return false
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return false
+	}
+	for _, s := range strings.Split(host, "/") {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			continue
+		}
+		for _, a := range avps {
+			if hostIP, ok := a.Data.(datatype.Address); ok && ip.Equal(net.IP(hostIP)) {
+				return true
+			}
+		}
+	}
+	return false
+}
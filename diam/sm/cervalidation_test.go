@@ -0,0 +1,106 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+	"github.com/omnicate/go-diameter/v4/diam/sm/smparser"
+)
+
+func TestSettings_CERValidation(t *testing.T) {
+	nat := []datatype.Address{datatype.Address(net.ParseIP("10.0.0.1"))}
+	tests := []struct {
+		name       string
+		validation CERValidation
+		hostIPs    []datatype.Address
+		resultCode uint32
+	}{
+		{"AllowedHost", CERValidation{OriginHosts: []datatype.DiameterIdentity{"cli"}}, nil, diam.Success},
+		{"UnknownHost", CERValidation{OriginHosts: []datatype.DiameterIdentity{"other"}}, nil, diam.UnknownPeer},
+		{"UnknownRealm", CERValidation{OriginRealms: []datatype.DiameterIdentity{"other"}}, nil, diam.UnknownPeer},
+		{"UnknownPeer", CERValidation{RejectUnknownPeers: true}, nil, diam.UnknownPeer},
+		{"HostIPAddress", CERValidation{CheckHostIPAddress: true}, nil, diam.Success},
+		{"HostIPAddressMismatch", CERValidation{CheckHostIPAddress: true}, nat, diam.UnableToComply},
+		{"VendorID", CERValidation{VendorIDs: []uint32{13}}, nil, diam.Success},
+		{"UnexpectedVendorID", CERValidation{VendorIDs: []uint32{10415}}, nil, diam.UnableToComply},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srvCfg := *serverSettings
+			srvCfg.CERValidation = &tc.validation
+			srv := diamtest.NewServer(New(&srvCfg), dict.Default)
+			defer srv.Close()
+
+			cliCfg := *clientSettings
+			cliCfg.HostIPAddresses = tc.hostIPs
+			c, err := newPeerClient(New(&cliCfg), "").Dial(srv.Addr)
+			if tc.resultCode == diam.Success {
+				if err != nil {
+					t.Fatal(err)
+				}
+				c.Close()
+				return
+			}
+			e, ok := err.(*smparser.ErrFailedResultCode)
+			if !ok {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if e.ResultCode != tc.resultCode {
+				t.Fatalf("Unexpected Result-Code. Want %d, have %d", tc.resultCode, e.ResultCode)
+			}
+		})
+	}
+}
+
+func TestStateMachine_HandleCERAdmission(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		resultCode uint32
+	}{
+		{"Admitted", nil, diam.Success},
+		{"ResultError", &diam.ResultError{Code: diam.AuthorizationRejected}, diam.AuthorizationRejected},
+		{"UnknownPeer", ErrUnknownPeer, diam.UnknownPeer},
+		{"Error", errors.New("inventory unavailable"), diam.UnableToComply},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			received := make(chan *smparser.CER, 1)
+			srvSM := New(serverSettings)
+			srvSM.HandleCERAdmission(func(c diam.Conn, cer *smparser.CER) error {
+				received <- cer
+				return tc.err
+			})
+			srv := diamtest.NewServer(srvSM, dict.Default)
+			defer srv.Close()
+
+			c, err := newPeerClient(New(clientSettings), "").Dial(srv.Addr)
+			if cer := <-received; cer.OriginHost != clientSettings.OriginHost || len(cer.Applications()) == 0 {
+				t.Fatalf("Unexpected CER: %s, applications %v", cer.OriginHost, cer.Applications())
+			}
+			if tc.resultCode == diam.Success {
+				if err != nil {
+					t.Fatal(err)
+				}
+				c.Close()
+				return
+			}
+			e, ok := err.(*smparser.ErrFailedResultCode)
+			if !ok {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if e.ResultCode != tc.resultCode {
+				t.Fatalf("Unexpected Result-Code. Want %d, have %d", tc.resultCode, e.ResultCode)
+			}
+		})
+	}
+}
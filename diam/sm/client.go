@@ -7,6 +7,7 @@ package sm
 import (
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"strings"
 	"time"
@@ -15,6 +16,7 @@ import (
 	"github.com/omnicate/go-diameter/v4/diam/avp"
 	"github.com/omnicate/go-diameter/v4/diam/datatype"
 	"github.com/omnicate/go-diameter/v4/diam/dict"
+	"github.com/omnicate/go-diameter/v4/diam/doic"
 )
 
 var (
@@ -29,6 +31,31 @@ var (
 	// handshake timeout only occurs after all retransmits are
 	// attempted and none has an aswer.
 	ErrHandshakeTimeout = errors.New("handshake timeout (no response)")
+
+	// ErrPeerConnected is returned by Dial or DialTLS when the peer is
+	// already connected and the state machine enforces a single
+	// connection per peer, or when the connection was closed because
+	// the peer connected to us simultaneously and we won the election.
+	//
+	// In both cases the open connection is returned by the state
+	// machine's PeerConn method.
+	ErrPeerConnected = errors.New("peer is already connected")
+
+	// ErrPeerDisconnected is returned by Send when the connection is
+	// closed before the answer is received, and the request could not
+	// be failed over to another connection.
+	ErrPeerDisconnected = errors.New("peer disconnected (no answer)")
+
+	// ErrRequestTimeout is returned by Send when no answer is received
+	// after all retransmissions of the request.
+	ErrRequestTimeout = errors.New("request timeout (no answer)")
+
+	// ErrApplicationUnsupportedByPeer is returned by Send when the peer
+	// of the connection did not advertise the application of the
+	// request in its CER or CEA, which it would answer with
+	// DIAMETER_APPLICATION_UNSUPPORTED, unless unsupported applications
+	// are allowed. See AllowUnsupportedApps.
+	ErrApplicationUnsupportedByPeer = errors.New("application not supported by peer")
 )
 
 // A Client is a diameter client that automatically performs a handshake
@@ -41,7 +68,17 @@ var (
 //
 // By default, retransmission and watchdog are disabled. Retransmission is
 // enabled by setting MaxRetransmits to a number greater than zero, and
-// watchdog is enabled by setting EnableWatchdog to true.
+// watchdog is enabled by setting EnableWatchdog to true. Requests sent
+// with Send are retransmitted with the T flag set when RequestRetransmits
+// is greater than zero.
+//
+// The watchdog follows the algorithm of RFC 3539 section 3.4.1: a DWR is
+// sent when no message is received from the peer for Tw, the connection
+// becomes suspect and requests sent by Send are routed to other peers
+// when it is not answered for another Tw, and the connection is closed
+// after a third Tw. Connections to peers whose connection was closed by
+// the watchdog are not used until the peer answers 3 DWRs. See
+// WatchdogState.
 //
 // A custom message handler for Device-Watchdog-Answer (DWA) can be registered.
 // However, that will be overwritten if watchdog is enabled.
@@ -51,12 +88,59 @@ type Client struct {
 	MaxRetransmits              uint          // Max number of retransmissions before aborting
 	RetransmitInterval          time.Duration // Interval between retransmissions (default 1s)
 	EnableWatchdog              bool          // Enable automatic DWR
-	WatchdogInterval            time.Duration // Interval between DWRs (default 5s)
+	WatchdogInterval            time.Duration // Watchdog timer Tw, Twinit of RFC 3539 (default 5s)
+	WatchdogJitter              time.Duration // Max random jitter of Tw, RFC 3539 uses 2s
 	WatchdogStream              uint          // Stream to send DWR on (for multistreaming protocols), default is 0
 	SupportedVendorID           []*diam.AVP   // Supported vendor ID
 	AcctApplicationID           []*diam.AVP   // Acct applications
 	AuthApplicationID           []*diam.AVP   // Auth applications
 	VendorSpecificApplicationID []*diam.AVP   // Vendor specific applications
+	RequestRetransmits          uint          // Max number of retransmissions of requests sent by Send
+	RequestTimeout              time.Duration // Time to wait for an answer, Tw (default WatchdogInterval)
+	RetransmitBackoff           float64       // Factor applied to RequestTimeout after each retransmission
+	FollowRedirects             bool          // Follow redirect indications in answers to Send
+	FlushInterval               time.Duration // Batch the messages written within the interval after the CER, see diam.Batcher
+	AllowUnsupportedApps        bool          // Send requests of applications the peer did not advertise
+
+	// TransportConfig is the socket options of the connections of
+	// DialExt, DialTLSExt and the methods built on them, such as TCP
+	// keep-alives or SCTP heartbeats. The defaults are used when unset.
+	TransportConfig *diam.TransportConfig
+
+	// PeerIdentity is the expected Origin-Host of the peer. When set,
+	// the peer state machine tracks the connection from the moment it
+	// is dialed, which allows resolving simultaneous connections with
+	// the peer. See RFC 6733 section 5.6.4 for details.
+	PeerIdentity datatype.DiameterIdentity
+
+	// Failover selects the connection requests sent by Send are sent
+	// again on when their connection fails. Requests are not failed over
+	// when unset.
+	Failover FailoverPolicy
+
+	// Routes selects the peer that requests sent by Send without
+	// a connection are routed to, by realm and application.
+	Routes *RoutingTable
+
+	// Discovery finds the peers of the realms dialed by DialRealm in
+	// DNS.
+	Discovery *Discovery
+
+	// OverloadControl enables DOIC (RFC 7683) when set. Answers received
+	// by Handler are used to update the controller, and the requests
+	// written to the connections of Handler carry OC-Supported-Features
+	// and are throttled. Writes of throttled requests, and Send, return
+	// doic.ErrThrottled.
+	OverloadControl *doic.Controller
+
+	// Backpressure handles DIAMETER_TOO_BUSY and DIAMETER_UNABLE_TO_DELIVER
+	// answers to requests sent by Send when set. The policy is shared by
+	// the copies of the Client.
+	Backpressure *BackpressurePolicy
+
+	// Reconnect dials again the connections that drop when set. The
+	// policy is shared by the copies of the Client.
+	Reconnect *ReconnectPolicy
 }
 
 // Dial calls the address set as ip:port, performs a handshake and optionally
@@ -104,7 +188,7 @@ func (cli *Client) DialNetworkTLS(network, addr, certFile, keyFile string, laddr
 // performs a handshake and optionally start a watchdog goroutine in background.
 func (cli *Client) DialExt(network, addr string, timeout time.Duration, laddr net.Addr) (diam.Conn, error) {
 	return cli.dial(func() (diam.Conn, error) {
-		return diam.DialExt(network, addr, cli.Handler, cli.Dict, timeout, laddr)
+		return diam.DialConfig(network, addr, cli.Handler, cli.Dict, timeout, laddr, cli.TransportConfig)
 	})
 }
 
@@ -114,7 +198,8 @@ func (cli *Client) DialTLSExt(
 	network, addr, certFile, keyFile string, timeout time.Duration, laddr net.Addr) (diam.Conn, error) {
 
 	return cli.dial(func() (diam.Conn, error) {
-		return diam.DialTLSExt(network, addr, certFile, keyFile, cli.Handler, cli.Dict, timeout, laddr)
+		return diam.DialTLSConfig(
+			network, addr, certFile, keyFile, cli.Handler, cli.Dict, timeout, laddr, cli.TransportConfig)
 	})
 }
 
@@ -131,12 +216,42 @@ func (cli *Client) dial(f dialFunc) (diam.Conn, error) {
 	if err := cli.validate(); err != nil {
 		return nil, err
 	}
+	var tracked bool
+	if len(cli.PeerIdentity) > 0 {
+		if cli.Handler.ReconnectDelay(cli.PeerIdentity) > 0 {
+			return nil, ErrReconnectDelayed
+		}
+		var err error
+		if tracked, err = cli.Handler.peers.start(cli.PeerIdentity); err != nil {
+			return nil, err
+		}
+	}
 	c, err := f()
+	if tracked {
+		if err != nil {
+			cli.Handler.peers.connNack(cli.PeerIdentity)
+			return c, err
+		}
+		if err = cli.Handler.peers.connAck(cli.PeerIdentity, c); err != nil {
+			return nil, err
+		}
+	}
 	if err != nil {
 		return c, err
 	}
-	c, err = cli.handshake(c)
-	return c, err
+	if c, err = cli.handshake(c); err != nil {
+		return nil, err
+	}
+	if b, ok := c.(diam.Batcher); ok && cli.FlushInterval > 0 {
+		if err = b.SetFlushInterval(cli.FlushInterval); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	if cli.Reconnect != nil && !cli.Reconnect.isStopped() {
+		cli.watchReconnect(f, c)
+	}
+	return c, nil
 }
 
 func (cli *Client) validate() error {
@@ -154,6 +269,9 @@ func (cli *Client) validate() error {
 		// Set default WatchdogInterval
 		cli.WatchdogInterval = 5 * time.Second
 	}
+	if cli.OverloadControl != nil {
+		cli.Handler.SetOverloadController(cli.OverloadControl)
+	}
 	// Make sure the applications supplied to Client are supported locally
 	for _, submittedAcctApp := range cli.AcctApplicationID {
 		acctAppID := uint32(submittedAcctApp.Data.(datatype.Unsigned32))
@@ -189,35 +307,24 @@ func (cli *Client) validate() error {
 }
 
 func (cli *Client) handshake(c diam.Conn) (diam.Conn, error) {
-	var (
-		hostAddresses []datatype.Address
-		err           error
-	)
-	if len(cli.Handler.cfg.HostIPAddresses) > 0 {
-		hostAddresses = cli.Handler.cfg.HostIPAddresses
-	} else {
-		hostAddresses, err = getLocalAddresses(c)
-		if err != nil {
-			c.Close()
-			return nil, fmt.Errorf("diameter handshake failure: %v", err)
-		}
+	hostAddresses, err := cli.Handler.hostAddresses(c, cli.PeerIdentity)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("diameter handshake failure: %v", err)
 	}
 
-	m := cli.makeCER(hostAddresses)
-	// Ignore CER, but not DWR.
-	cerClientHandler := func(c diam.Conn, m *diam.Message) {}
-	// See sm.go for Base Diam Idx declarations
-	cli.Handler.mux.HandleIdx(baseCERIdx, diam.HandlerFunc(cerClientHandler))
-	cli.Handler.mux.HandleFunc("CER", cerClientHandler)
-	// Handle CEA and DWA.
+	m := cli.makeCER(hostAddresses, cli.Handler.inbandSecurity(c))
+	// CERs are left to the state machine, so that the same handler can
+	// accept connections from peers it dials. Handle CEA and DWA.
 	errc := make(chan error)
 	cli.Handler.mux.Handle("CEA", handleCEA(cli.Handler, errc))
 
-	var dwac chan struct{}
+	var w *watchdog
 	if cli.EnableWatchdog {
-		dwac = make(chan struct{})
-		cli.Handler.mux.Handle("DWA", handshakeOK(handleDWA(cli.Handler, dwac)))
+		w = newConnWatchdog(c)
+		cli.Handler.mux.Handle("DWA", handshakeOK(handleDWA(cli.Handler, nil)))
 	}
+	disconnect := closeNotify(c)
 	for i := 0; i < (int(cli.MaxRetransmits) + 1); i++ {
 		_, err := m.WriteTo(c)
 		if err != nil {
@@ -225,14 +332,20 @@ func (cli *Client) handshake(c diam.Conn) (diam.Conn, error) {
 			return nil, err
 		}
 		select {
+		case <-disconnect:
+			if len(cli.PeerIdentity) > 0 && cli.Handler.PeerState(cli.PeerIdentity).Open() {
+				// Closed by the election, see peerTable.rConnCER.
+				return nil, ErrPeerConnected
+			}
+			return nil, io.ErrUnexpectedEOF
 		case err, ok := <-errc: // Wait for CEA.
 			if ok && err != nil {
 				close(errc)
 				c.Close()
 				return nil, err
 			}
-			if cli.EnableWatchdog {
-				go cli.watchdog(c, dwac)
+			if w != nil {
+				cli.startWatchdog(c, w)
 			}
 			return c, nil
 		case <-time.After(cli.RetransmitInterval):
@@ -242,76 +355,64 @@ func (cli *Client) handshake(c diam.Conn) (diam.Conn, error) {
 	return nil, ErrHandshakeTimeout
 }
 
-func (cli *Client) makeCER(hostIPAddresses []datatype.Address) *diam.Message {
+func (cli *Client) makeCER(hostIPAddresses []datatype.Address, security []uint32) *diam.Message {
 	m := diam.NewRequest(diam.CapabilitiesExchange, 0, cli.Dict)
+	cli.addCapabilities(m, cli.PeerIdentity, hostIPAddresses, security)
+	return m
+}
+
+// addCapabilities adds the AVPs that advertise the capabilities of the
+// client to the peer host in the CER or CUR m.
+func (cli *Client) addCapabilities(m *diam.Message, host datatype.DiameterIdentity, hostIPAddresses []datatype.Address, security []uint32) {
+	peer := cli.Handler.peerConfig(host)
+	addApps := func(apps []*diam.AVP) {
+		for _, a := range apps {
+			if id, ok := appID(a); !ok || peer.advertises(id) {
+				m.AddAVP(a)
+			}
+		}
+	}
 	m.NewAVP(avp.OriginHost, avp.Mbit, 0, cli.Handler.cfg.OriginHost)
 	m.NewAVP(avp.OriginRealm, avp.Mbit, 0, cli.Handler.cfg.OriginRealm)
 	for _, hostIPAddress := range hostIPAddresses {
 		m.NewAVP(avp.HostIPAddress, avp.Mbit, 0, hostIPAddress)
 	}
 	m.NewAVP(avp.VendorID, avp.Mbit, 0, cli.Handler.cfg.VendorID)
-	m.NewAVP(avp.ProductName, 0, 0, cli.Handler.cfg.ProductName)
+	m.NewAVP(avp.ProductName, 0, 0, cli.Handler.productName(host))
 	if cli.Handler.cfg.OriginStateID != 0 {
 		stateid := datatype.Unsigned32(cli.Handler.cfg.OriginStateID)
 		m.NewAVP(avp.OriginStateID, avp.Mbit, 0, stateid)
 	}
-	if cli.SupportedVendorID != nil {
-		for _, a := range cli.SupportedVendorID {
-			m.AddAVP(a)
+	seen := make(map[uint32]bool)
+	for _, a := range cli.SupportedVendorID {
+		if id, ok := a.Data.(datatype.Unsigned32); ok {
+			seen[uint32(id)] = true
 		}
+		m.AddAVP(a)
 	}
-	if cli.AuthApplicationID != nil {
-		for _, a := range cli.AuthApplicationID {
-			m.AddAVP(a)
+	for _, id := range cli.Handler.cfg.SupportedVendorIDs {
+		if !seen[id] {
+			seen[id] = true
+			m.NewAVP(avp.SupportedVendorID, avp.Mbit, 0, datatype.Unsigned32(id))
 		}
 	}
-	m.NewAVP(avp.InbandSecurityID, avp.Mbit, 0, datatype.Unsigned32(0))
-	if cli.AcctApplicationID != nil {
-		for _, a := range cli.AcctApplicationID {
-			m.AddAVP(a)
-		}
+	addApps(cli.AuthApplicationID)
+	if cli.Handler.cfg.CapabilitiesUpdate {
+		m.NewAVP(avp.AuthApplicationID, avp.Mbit, 0, datatype.Unsigned32(diam.CAPABILITIES_UPDATE_APP_ID))
 	}
-	if cli.VendorSpecificApplicationID != nil {
-		for _, a := range cli.VendorSpecificApplicationID {
-			m.AddAVP(a)
-		}
+	for _, id := range security {
+		m.NewAVP(avp.InbandSecurityID, avp.Mbit, 0, datatype.Unsigned32(id))
 	}
+	addApps(cli.AcctApplicationID)
+	addApps(cli.VendorSpecificApplicationID)
 	if cli.Handler.cfg.FirmwareRevision != 0 {
 		m.NewAVP(avp.FirmwareRevision, 0, 0, cli.Handler.cfg.FirmwareRevision)
 	}
-	return m
-}
-
-func (cli *Client) watchdog(c diam.Conn, dwac chan struct{}) {
-	disconnect := c.(diam.CloseNotifier).CloseNotify()
-	var osid = uint32(cli.Handler.cfg.OriginStateID)
-	for {
-		select {
-		case <-disconnect:
-			return
-		case <-time.After(cli.WatchdogInterval):
-			cli.dwr(c, osid, dwac)
-		}
+	for _, a := range cli.Handler.cfg.CapabilitiesAVPs {
+		m.AddAVP(a)
 	}
 }
 
-func (cli *Client) dwr(c diam.Conn, osid uint32, dwac chan struct{}) {
-	m := cli.makeDWR(osid)
-	for i := 0; i < (int(cli.MaxRetransmits) + 1); i++ {
-		_, err := m.WriteToStream(c, cli.WatchdogStream)
-		if err != nil {
-			return
-		}
-		select {
-		case <-dwac:
-			return
-		case <-time.After(cli.RetransmitInterval):
-		}
-	}
-	// Watchdog failed, disconnect.
-	c.Close()
-}
-
 func (cli *Client) makeDWR(osid uint32) *diam.Message {
 	m := diam.NewRequest(diam.DeviceWatchdog, 0, cli.Dict)
 	m.NewAVP(avp.OriginHost, avp.Mbit, 0, cli.Handler.cfg.OriginHost)
@@ -320,9 +421,17 @@ func (cli *Client) makeDWR(osid uint32) *diam.Message {
 	return m
 }
 
+// getLocalAddresses returns the local addresses of the connection c to
+// advertise in Host-IP-Address AVPs: all the local addresses of SCTP
+// associations, and the loopback address of the transports that connect
+// local peers without IP, such as Unix domain sockets.
 func getLocalAddresses(c diam.Conn) ([]datatype.Address, error) {
 	var addrStr string
 	if c.LocalAddr() != nil {
+		switch c.LocalAddr().Network() {
+		case "unix", "unixpacket", "mem":
+			return []datatype.Address{datatype.Address(net.IPv4(127, 0, 0, 1).To4())}, nil
+		}
 		addrStr = c.LocalAddr().String()
 	}
 	addr, _, err := net.SplitHostPort(addrStr)
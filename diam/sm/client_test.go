@@ -11,12 +11,16 @@ import (
 	"testing"
 	"time"
 
+	"golang.org/x/net/context"
+
 	"github.com/omnicate/go-diameter/v4/diam"
 	"github.com/omnicate/go-diameter/v4/diam/avp"
 	"github.com/omnicate/go-diameter/v4/diam/datatype"
 	"github.com/omnicate/go-diameter/v4/diam/diamtest"
 	"github.com/omnicate/go-diameter/v4/diam/dict"
+	"github.com/omnicate/go-diameter/v4/diam/doic"
 	"github.com/omnicate/go-diameter/v4/diam/sm/smparser"
+	"github.com/omnicate/go-diameter/v4/diam/sm/smpeer"
 )
 
 func TestClient_Dial_MissingStateMachine(t *testing.T) {
@@ -245,6 +249,56 @@ func TestClient_Handshake_RetransmitTimeout(t *testing.T) {
 	}
 }
 
+// plainConn hides the optional interfaces of the diam.Conn, such as
+// diam.CloseNotifier, of custom transports and wrapped connections.
+type plainConn struct{ diam.Conn }
+
+func TestClient_Handshake_NoCloseNotifier(t *testing.T) {
+	srv := diamtest.NewServer(New(serverSettings), dict.Default)
+	defer srv.Close()
+	cli := &Client{
+		Handler:            New(clientSettings),
+		MaxRetransmits:     1,
+		RetransmitInterval: time.Second,
+		AcctApplicationID: []*diam.AVP{
+			diam.NewAVP(avp.AcctApplicationID, avp.Mbit, 0, datatype.Unsigned32(3)),
+		},
+	}
+	c, err := diam.Dial(srv.Addr, cli.Handler, dict.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if _, err = cli.handshake(plainConn{c}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClient_Watchdog_NoCloseNotifier(t *testing.T) {
+	srv := diamtest.NewServer(New(serverSettings), dict.Default)
+	defer srv.Close()
+	cli := &Client{
+		EnableWatchdog:     true,
+		WatchdogInterval:   50 * time.Millisecond,
+		MaxRetransmits:     1,
+		RetransmitInterval: time.Second,
+		Handler:            New(clientSettings),
+		AcctApplicationID: []*diam.AVP{
+			diam.NewAVP(avp.AcctApplicationID, avp.Mbit, 0, datatype.Unsigned32(3)),
+		},
+	}
+	c, err := diam.Dial(srv.Addr, cli.Handler, dict.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if _, err = cli.handshake(plainConn{c}); err != nil {
+		t.Fatal(err)
+	}
+	// Let the watchdog send DWRs on the connection.
+	time.Sleep(150 * time.Millisecond)
+}
+
 func TestClient_Watchdog(t *testing.T) {
 	srv := diamtest.NewServer(New(serverSettings), dict.Default)
 	defer srv.Close()
@@ -302,3 +356,161 @@ func TestClient_Watchdog_Timeout(t *testing.T) {
 		t.Fatal("Timeout waiting for watchdog to disconnect client")
 	}
 }
+
+func TestClient_Handshake_Capabilities(t *testing.T) {
+	mc := make(chan *diam.Message, 1)
+	mux := diam.NewServeMux()
+	mux.HandleFunc("CER", func(c diam.Conn, m *diam.Message) {
+		mc <- m
+	})
+	srv := diamtest.NewServer(mux, dict.Default)
+	defer srv.Close()
+
+	settings := *clientSettings
+	settings.SupportedVendorIDs = []uint32{13, 10415}
+	settings.CapabilitiesAVPs = []*diam.AVP{diam.NewAVP(avp.ErrorMessage, 0, 0, datatype.UTF8String("extra"))}
+	cli := newPeerClient(New(&settings), "")
+	cli.SupportedVendorID = []*diam.AVP{
+		diam.NewAVP(avp.SupportedVendorID, avp.Mbit, 0, datatype.Unsigned32(13)),
+	}
+	cli.RetransmitInterval = 50 * time.Millisecond
+	if _, err := cli.Dial(srv.Addr); err != ErrHandshakeTimeout {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	cer := <-mc
+	if n, _ := countAVPs(cer, avp.SupportedVendorID); n != 2 {
+		t.Fatalf("Unexpected number of Supported-Vendor-Id. Want 2, have %d", n)
+	}
+	if _, v := countAVPs(cer, avp.FirmwareRevision); v != settings.FirmwareRevision {
+		t.Fatalf("Unexpected Firmware-Revision. Want %v, have %v", settings.FirmwareRevision, v)
+	}
+	if _, v := countAVPs(cer, avp.ErrorMessage); v != datatype.UTF8String("extra") {
+		t.Fatalf("Unexpected custom AVP: %v", v)
+	}
+}
+
+func TestClient_Send_UnsupportedApplication(t *testing.T) {
+	srv := diamtest.NewServer(newACRServer(serverSettings), dict.Default)
+	defer srv.Close()
+
+	cli := newPeerClient(New(clientSettings), "")
+	cli.RequestTimeout = 50 * time.Millisecond
+	c, err := cli.Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	meta, ok := smpeer.FromConn(c)
+	if !ok {
+		t.Fatal("No peer metadata after handshake")
+	}
+	if meta.ProductName != serverSettings.ProductName || meta.VendorID != uint32(serverSettings.VendorID) {
+		t.Fatalf("Unexpected peer product %q, vendor %d", meta.ProductName, meta.VendorID)
+	}
+	if meta.FirmwareRevision != uint32(serverSettings.FirmwareRevision) {
+		t.Fatalf("Unexpected peer firmware. Want %d, have %d", serverSettings.FirmwareRevision, meta.FirmwareRevision)
+	}
+
+	req := newACR(cli)
+	req.Header.ApplicationID = 999
+	if _, err = cli.Send(c, req); err != ErrApplicationUnsupportedByPeer {
+		t.Fatalf("Unexpected error. Want %v, have %v", ErrApplicationUnsupportedByPeer, err)
+	}
+	cli.AllowUnsupportedApps = true
+	if _, err = cli.Send(c, req); err == ErrApplicationUnsupportedByPeer {
+		t.Fatal("Request was not sent with AllowUnsupportedApps")
+	}
+}
+
+func TestClient_Send_AllowUnsupportedAppsConn(t *testing.T) {
+	srv := diamtest.NewServer(newACRServer(serverSettings), dict.Default)
+	defer srv.Close()
+
+	cli := newPeerClient(New(clientSettings), "")
+	cli.RequestTimeout = 50 * time.Millisecond
+	c, err := cli.Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	AllowUnsupportedApps(c)
+	req := newACR(cli)
+	req.Header.ApplicationID = 999
+	if _, err = cli.Send(c, req); err == ErrApplicationUnsupportedByPeer {
+		t.Fatal("Request was not sent on connection allowing unsupported applications")
+	}
+}
+
+func TestClient_Handshake_MemTransport(t *testing.T) {
+	l, err := diam.Listen("tcp", "mem://sm-handshake")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &diam.Server{Handler: newACRServer(serverSettings), Dict: dict.Default}
+	go srv.Serve(l)
+	defer srv.Shutdown(context.Background())
+
+	cli := newPeerClient(New(clientSettings), "")
+	c, err := cli.Dial("mem://sm-handshake")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if _, err = cli.Send(c, newACR(cli)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClient_OverloadControl(t *testing.T) {
+	supported := make(chan bool, 1)
+	srvSM := New(serverSettings)
+	srvSM.ReportOverload(doic.RealmReport, 100, time.Minute)
+	srvSM.HandleFunc("ACR", func(c diam.Conn, m *diam.Message) {
+		_, err := m.FindAVP(avp.OCSupportedFeatures, 0)
+		supported <- err == nil
+		a := m.Answer(diam.Success)
+		a.NewAVP(avp.OriginHost, avp.Mbit, 0, serverSettings.OriginHost)
+		a.NewAVP(avp.OriginRealm, avp.Mbit, 0, serverSettings.OriginRealm)
+		srvSM.OverloadReporter().Answer(m, a)
+		a.WriteTo(c)
+	})
+	srv := diamtest.NewServer(srvSM, dict.Default)
+	defer srv.Close()
+
+	ctl := doic.NewController(nil)
+	cli := newPeerClient(New(clientSettings), "")
+	cli.OverloadControl = ctl
+	c, err := cli.Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	// Requests written without Send are prepared too.
+	if _, err = newACR(cli).WriteTo(c); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case ok := <-supported:
+		if !ok {
+			t.Fatal("OC-Supported-Features was not added to the request")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for the request")
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, ok := ctl.RealmReport(serverSettings.OriginRealm); ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Overload report was not processed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if _, err = newACR(cli).WriteTo(c); err != doic.ErrThrottled {
+		t.Fatalf("Unexpected error. Want %v, have %v", doic.ErrThrottled, err)
+	}
+	if _, err = cli.Send(c, newACR(cli)); err != doic.ErrThrottled {
+		t.Fatalf("Unexpected error. Want %v, have %v", doic.ErrThrottled, err)
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/sm/smpeer"
+)
+
+// defaultClusterTTL is the lifetime of the entries of a PendingTable
+// when Cluster.TTL is not set.
+const defaultClusterTTL = time.Minute
+
+// defaultClusterLookupTimeout is the time to look up the instance of an
+// answer in the PendingTable when Cluster.LookupTimeout is not set.
+const defaultClusterLookupTimeout = time.Second
+
+// PendingKey identifies a request sent by an instance of a cluster, and
+// its answer. Hop-by-Hop identifiers are only unique per connection, so
+// that instances connected to the same peer may send requests with the
+// same one: the key combines it with the End-to-End identifier and the
+// identity of the peer, known to all of the instances connected to it.
+type PendingKey struct {
+	Peer       datatype.DiameterIdentity // Origin-Host of the peer of the connection
+	EndToEndID uint32
+	HopByHopID uint32
+}
+
+// pendingKey returns the PendingKey of the message m, sent or received
+// on the connection c.
+func pendingKey(c diam.Conn, m *diam.Message) PendingKey {
+	k := PendingKey{EndToEndID: m.Header.EndToEndID, HopByHopID: m.Header.HopByHopID}
+	if meta, ok := smpeer.FromConn(c); ok {
+		k.Peer = meta.OriginHost
+	}
+	return k
+}
+
+// PendingTable is a table shared by the instances of a cluster, which
+// maps the requests they send to the name of the instance that sent
+// them. See Cluster.
+type PendingTable interface {
+	// Put maps the key to instance, until it is deleted or ttl elapses.
+	Put(ctx context.Context, key PendingKey, instance string, ttl time.Duration) error
+
+	// Get returns the instance key is mapped to, or "" if none.
+	Get(ctx context.Context, key PendingKey) (string, error)
+
+	// Delete deletes the mapping of key, if any.
+	Delete(ctx context.Context, key PendingKey) error
+}
+
+// AnswerForwarder sends the answers received by an instance of a
+// cluster to the instance that sent their request, which passes them to
+// StateMachine.DeliverForwarded.
+type AnswerForwarder interface {
+	Forward(ctx context.Context, instance string, m *diam.Message) error
+}
+
+// The ForwarderFunc type is an adapter to allow the use of ordinary
+// functions as AnswerForwarders.
+type ForwarderFunc func(ctx context.Context, instance string, m *diam.Message) error
+
+// Forward calls f(ctx, instance, m).
+func (f ForwarderFunc) Forward(ctx context.Context, instance string, m *diam.Message) error {
+	return f(ctx, instance, m)
+}
+
+// Cluster configures the instances of a state machine that share their
+// peers, for example behind a load balancer or over multihomed SCTP
+// associations, where the answer to a request sent by an instance may be
+// received by another one.
+//
+// The requests sent by Client.Send are registered in the Table with the
+// name of their Instance until answered. Answers received for requests
+// that are not pending locally are looked up in the Table, and forwarded
+// to their instance by the Forwarder. Lookups run in the background, so
+// that a slow Table does not delay the messages of the connection:
+// answers that no instance is waiting for are handled after the lookup.
+type Cluster struct {
+	// Instance is the name of this instance, unique in the cluster.
+	Instance string
+
+	// Table is the pending table shared by the cluster.
+	Table PendingTable
+
+	// Forwarder forwards answers to the other instances.
+	Forwarder AnswerForwarder
+
+	// TTL is the lifetime of the entries of the Table, which should
+	// exceed the time requests wait for their answer. Will default to
+	// one minute.
+	TTL time.Duration
+
+	// LookupTimeout is the time to look up the instance of an answer in
+	// the Table, after which the answer is handled locally. Will default
+	// to one second.
+	LookupTimeout time.Duration
+}
+
+func (cl *Cluster) ttl() time.Duration {
+	if cl.TTL > 0 {
+		return cl.TTL
+	}
+	return defaultClusterTTL
+}
+
+func (cl *Cluster) lookupTimeout() time.Duration {
+	if cl.LookupTimeout > 0 {
+		return cl.LookupTimeout
+	}
+	return defaultClusterLookupTimeout
+}
+
+// register maps the request m, sent on c, to this instance, and returns
+// the function that deletes the mapping.
+func (sm *StateMachine) register(ctx context.Context, c diam.Conn, m *diam.Message) func() {
+	cl := sm.cfg.Cluster
+	key := pendingKey(c, m)
+	if err := cl.Table.Put(ctx, key, cl.Instance, cl.ttl()); err != nil {
+		sm.Error(&diam.ErrorReport{Conn: c, Message: m, Error: err})
+		return func() {}
+	}
+	return func() {
+		if err := cl.Table.Delete(context.Background(), key); err != nil {
+			sm.Error(&diam.ErrorReport{Conn: c, Message: m, Error: err})
+		}
+	}
+}
+
+// forward forwards the answer m received on c to the instance of the
+// cluster that sent its request, in the background, and reports whether
+// it takes over the answer. Answers that no other instance sent are
+// handed to the handlers of the state machine once looked up. Answers of
+// the base protocol, such as CEAs and DWAs, are specific to their
+// connection and never forwarded.
+func (sm *StateMachine) forward(c diam.Conn, m *diam.Message) bool {
+	if m.Header.ApplicationID == 0 {
+		return false
+	}
+	cl := sm.cfg.Cluster
+	key := pendingKey(c, m)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), cl.lookupTimeout())
+		instance, err := cl.Table.Get(ctx, key)
+		cancel()
+		if err != nil {
+			sm.Error(&diam.ErrorReport{Conn: c, Message: m, Error: err})
+		}
+		if err != nil || instance == "" || instance == cl.Instance {
+			sm.mux.ServeDIAM(c, m)
+			return
+		}
+		ctx, cancel = context.WithTimeout(context.Background(), cl.ttl())
+		defer cancel()
+		if err = cl.Forwarder.Forward(ctx, instance, m); err != nil {
+			sm.Error(&diam.ErrorReport{Conn: c, Message: m, Error: err})
+		}
+	}()
+	return true
+}
+
+// DeliverForwarded hands the answer m, forwarded by another instance of
+// the cluster, to the request pending in this instance it belongs to,
+// and reports whether there was one.
+func (sm *StateMachine) DeliverForwarded(m *diam.Message) bool {
+	return sm.pending.deliver(m)
+}
+
+// MemoryPendingTable is a PendingTable that keeps its entries in memory,
+// for tests and instances that run in the same process.
+type MemoryPendingTable struct {
+	mu sync.Mutex // guards m
+	m  map[PendingKey]memoryPending
+}
+
+type memoryPending struct {
+	instance string
+	deadline time.Time
+}
+
+// NewMemoryPendingTable creates and initializes a MemoryPendingTable.
+func NewMemoryPendingTable() *MemoryPendingTable {
+	return &MemoryPendingTable{m: make(map[PendingKey]memoryPending)}
+}
+
+// Put implements the PendingTable interface.
+func (t *MemoryPendingTable) Put(ctx context.Context, key PendingKey, instance string, ttl time.Duration) error {
+	t.mu.Lock()
+	t.m[key] = memoryPending{instance: instance, deadline: time.Now().Add(ttl)}
+	t.mu.Unlock()
+	return nil
+}
+
+// Get implements the PendingTable interface.
+func (t *MemoryPendingTable) Get(ctx context.Context, key PendingKey) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p, ok := t.m[key]
+	if !ok {
+		return "", nil
+	}
+	if !time.Now().Before(p.deadline) {
+		delete(t.m, key)
+		return "", nil
+	}
+	return p.instance, nil
+}
+
+// Delete implements the PendingTable interface.
+func (t *MemoryPendingTable) Delete(ctx context.Context, key PendingKey) error {
+	t.mu.Lock()
+	delete(t.m, key)
+	t.mu.Unlock()
+	return nil
+}
+
+// Len returns the number of entries of the table, including expired
+// ones not deleted yet.
+func (t *MemoryPendingTable) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.m)
+}
@@ -0,0 +1,157 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+func TestCluster_ForwardAnswer(t *testing.T) {
+	// The server does not answer on the connection of the request: its
+	// answers are received by the other instance, B.
+	requests := make(chan *diam.Message, 1)
+	srvSM := New(serverSettings)
+	srvSM.HandleFunc("ACR", func(c diam.Conn, m *diam.Message) {
+		requests <- m
+	})
+	srv := diamtest.NewServer(srvSM, dict.Default)
+	defer srv.Close()
+
+	table := NewMemoryPendingTable()
+	var smA *StateMachine
+	forwarded := make(chan string, 1)
+	forwarder := ForwarderFunc(func(ctx context.Context, instance string, m *diam.Message) error {
+		forwarded <- instance
+		smA.DeliverForwarded(m)
+		return nil
+	})
+	smA = New(&Settings{
+		OriginHost:  "cli",
+		OriginRealm: "test",
+		Cluster:     &Cluster{Instance: "a", Table: table, Forwarder: forwarder},
+	})
+	smB := New(&Settings{
+		OriginHost:  "cli",
+		OriginRealm: "test",
+		Cluster:     &Cluster{Instance: "b", Table: table, Forwarder: forwarder},
+	})
+
+	cli := newPeerClient(smA, "")
+	cli.RequestTimeout = time.Second
+	c, err := cli.Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	cB, err := newPeerClient(smB, "").Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cB.Close()
+
+	go func() {
+		m := <-requests
+		a := m.Answer(diam.Success)
+		a.NewAVP(avp.OriginHost, avp.Mbit, 0, serverSettings.OriginHost)
+		a.NewAVP(avp.OriginRealm, avp.Mbit, 0, serverSettings.OriginRealm)
+		smB.ServeDIAM(cB, a)
+	}()
+	req := newACR(cli)
+	a, err := cli.Send(c, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Header.EndToEndID != req.Header.EndToEndID {
+		t.Fatalf("Unexpected End-to-End ID. Want %d, have %d", req.Header.EndToEndID, a.Header.EndToEndID)
+	}
+	select {
+	case instance := <-forwarded:
+		if instance != "a" {
+			t.Fatalf("Unexpected instance. Want a, have %s", instance)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Answer was not forwarded")
+	}
+	if n := table.Len(); n != 0 {
+		t.Fatalf("Unexpected pending table length. Want 0, have %d", n)
+	}
+}
+
+func TestMemoryPendingTable(t *testing.T) {
+	table := NewMemoryPendingTable()
+	ctx := context.Background()
+	// Requests of two instances with the same Hop-by-Hop identifier.
+	a := PendingKey{Peer: "srv", EndToEndID: 1, HopByHopID: 7}
+	b := PendingKey{Peer: "srv", EndToEndID: 2, HopByHopID: 7}
+	table.Put(ctx, a, "a", time.Minute)
+	table.Put(ctx, b, "b", time.Nanosecond)
+	if instance, _ := table.Get(ctx, a); instance != "a" {
+		t.Fatalf("Unexpected instance. Want a, have %q", instance)
+	}
+	time.Sleep(time.Millisecond)
+	if instance, _ := table.Get(ctx, b); instance != "" {
+		t.Fatalf("Unexpected instance of expired entry: %q", instance)
+	}
+	table.Delete(ctx, a)
+	if n := table.Len(); n != 0 {
+		t.Fatalf("Unexpected length. Want 0, have %d", n)
+	}
+}
+
+// slowPendingTable is a PendingTable whose lookups block until their
+// context is done.
+type slowPendingTable struct{ *MemoryPendingTable }
+
+func (t slowPendingTable) Get(ctx context.Context, key PendingKey) (string, error) {
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func TestCluster_LookupTimeout(t *testing.T) {
+	handled := make(chan *diam.Message, 1)
+	sm := New(&Settings{
+		OriginHost:  "cli",
+		OriginRealm: "test",
+		Cluster: &Cluster{
+			Instance:      "a",
+			Table:         slowPendingTable{NewMemoryPendingTable()},
+			LookupTimeout: 50 * time.Millisecond,
+		},
+	})
+	sm.HandleFunc("ACA", func(c diam.Conn, m *diam.Message) {
+		handled <- m
+	})
+	srv := diamtest.NewServer(New(serverSettings), dict.Default)
+	defer srv.Close()
+	cli := newPeerClient(sm, "")
+	c, err := cli.Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	a := newACR(cli).Answer(diam.Success)
+	start := time.Now()
+	sm.ServeDIAM(c, a)
+	if d := time.Since(start); d >= 50*time.Millisecond {
+		t.Fatalf("Answer lookup blocked the connection for %s", d)
+	}
+	select {
+	case m := <-handled:
+		if m != a {
+			t.Fatal("Unexpected answer handled")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Answer was not handled after the lookup timeout")
+	}
+}
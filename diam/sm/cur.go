@@ -0,0 +1,124 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/sm/smparser"
+	"github.com/omnicate/go-diameter/v4/diam/sm/smpeer"
+)
+
+// ErrUpdateUnsupported is returned by UpdateCapabilities when the state
+// machine or the peer of the connection did not advertise the
+// Capabilities Update application in the capabilities exchange.
+var ErrUpdateUnsupported = errors.New("capabilities update not supported")
+
+// handleCUR handles Capabilities-Update-Request messages.
+//
+// The applications of the CUR replace those of the peer's metadata.
+// CURs without applications in common are answered with
+// DIAMETER_NO_COMMON_APPLICATION, and the connection is closed.
+//
+// See RFC 6737 section 4 for details.
+func handleCUR(sm *StateMachine) diam.HandlerFunc {
+	return func(c diam.Conn, m *diam.Message) {
+		cur := new(smparser.CUR)
+		failedAVP, err := cur.Parse(m, smparser.Server)
+		if err != nil {
+			if werr := errorCUA(sm, c, m, failedAVP, err); werr != nil {
+				err = werr
+			}
+			sm.Error(&diam.ErrorReport{
+				Conn:    c,
+				Message: m,
+				Error:   err,
+			})
+			c.Close()
+			return
+		}
+		meta, _ := smpeer.FromContext(c.Context())
+		updated := *meta
+		updated.Applications = cur.Applications()
+		c.SetContext(smpeer.NewContext(c.Context(), &updated))
+		a := m.Answer(diam.Success)
+		a.NewAVP(avp.OriginHost, avp.Mbit, 0, sm.cfg.OriginHost)
+		a.NewAVP(avp.OriginRealm, avp.Mbit, 0, sm.cfg.OriginRealm)
+		if _, err = a.WriteTo(c); err != nil {
+			sm.Error(&diam.ErrorReport{
+				Conn:    c,
+				Message: m,
+				Error:   err,
+			})
+		}
+	}
+}
+
+// errorCUA sends an error answer indicating that the CUR failed, and
+// includes the AVP that caused the failure, if any.
+func errorCUA(sm *StateMachine, c diam.Conn, m *diam.Message, failedAVP *diam.AVP, errMessage error) error {
+	var a *diam.Message
+	switch errMessage {
+	case smparser.ErrNoCommonApplication:
+		a = m.Answer(diam.NoCommonApplication)
+	case smparser.ErrMissingOriginHost, smparser.ErrMissingOriginRealm:
+		a = m.Answer(diam.MissingAVP)
+	default:
+		a = m.Answer(diam.UnableToComply)
+	}
+	a.Header.CommandFlags |= diam.ErrorFlag
+	a.NewAVP(avp.OriginHost, avp.Mbit, 0, sm.cfg.OriginHost)
+	a.NewAVP(avp.OriginRealm, avp.Mbit, 0, sm.cfg.OriginRealm)
+	if failedAVP != nil {
+		a.NewAVP(avp.FailedAVP, avp.Mbit, 0, &diam.GroupedAVP{
+			AVP: []*diam.AVP{failedAVP},
+		})
+	}
+	if _, err := a.WriteTo(c); err != nil {
+		return fmt.Errorf("Error CUA '%s' send failure: %v", errMessage, err)
+	}
+	return nil
+}
+
+// UpdateCapabilities sends a Capabilities-Update-Request to the peer of
+// the connection c with the current applications of the client, after
+// they were added or removed, and waits for its answer. The connection
+// is not affected. See RFC 6737 for details.
+//
+// Both the state machine and the peer must advertise the Capabilities
+// Update application, see Settings.CapabilitiesUpdate, or else it
+// returns ErrUpdateUnsupported. Answers with a Result-Code other than
+// success are returned as *smparser.ErrFailedUpdate.
+func (cli *Client) UpdateCapabilities(c diam.Conn) error {
+	if cli.Handler == nil {
+		return ErrMissingStateMachine
+	}
+	meta, ok := smpeer.FromContext(c.Context())
+	if !ok || !cli.Handler.cfg.CapabilitiesUpdate ||
+		!meta.Supports(diam.CAPABILITIES_UPDATE_APP_ID) {
+		return ErrUpdateUnsupported
+	}
+	hostAddresses, err := cli.Handler.hostAddresses(c, meta.OriginHost)
+	if err != nil {
+		return err
+	}
+	m := diam.NewRequest(diam.CapabilitiesUpdate, diam.CAPABILITIES_UPDATE_APP_ID, cli.Dict)
+	cli.addCapabilities(m, meta.OriginHost, hostAddresses, nil)
+	a, err := cli.Send(c, m)
+	if err != nil {
+		return err
+	}
+	return new(smparser.CUA).Parse(a)
+}
+
+// supports returns whether the peer of the connection c supports the
+// application appID, as advertised in its capabilities.
+func supports(c diam.Conn, appID uint32) bool {
+	meta, ok := smpeer.FromContext(c.Context())
+	return !ok || meta.Supports(appID)
+}
@@ -0,0 +1,107 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+	"github.com/omnicate/go-diameter/v4/diam/sm/smparser"
+	"github.com/omnicate/go-diameter/v4/diam/sm/smpeer"
+)
+
+func TestClient_UpdateCapabilities(t *testing.T) {
+	srvCfg := *serverSettings
+	srvCfg.CapabilitiesUpdate = true
+	srvSM := New(&srvCfg)
+	srv := diamtest.NewServer(srvSM, dict.Default)
+	defer srv.Close()
+
+	cliCfg := *clientSettings
+	cliCfg.CapabilitiesUpdate = true
+	cli := newPeerClient(New(&cliCfg), "")
+	c, err := cli.Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if err = waitPeerState(srvSM, cliCfg.OriginHost, ROpen); err != nil {
+		t.Fatal(err)
+	}
+	sc := srvSM.PeerConn(cliCfg.OriginHost)
+	apps := func() []uint32 {
+		meta, _ := smpeer.FromContext(sc.Context())
+		return meta.Applications
+	}
+	want := []uint32{3, diam.CAPABILITIES_UPDATE_APP_ID}
+	if !reflect.DeepEqual(apps(), want) {
+		t.Fatalf("Unexpected applications. Want %v, have %v", want, apps())
+	}
+
+	// Replace accounting with credit control.
+	cli.AcctApplicationID = nil
+	cli.AuthApplicationID = []*diam.AVP{
+		diam.NewAVP(avp.AuthApplicationID, avp.Mbit, 0, datatype.Unsigned32(4)),
+	}
+	if err = cli.UpdateCapabilities(c); err != nil {
+		t.Fatal(err)
+	}
+	want = []uint32{4, diam.CAPABILITIES_UPDATE_APP_ID}
+	if !reflect.DeepEqual(apps(), want) {
+		t.Fatalf("Unexpected applications. Want %v, have %v", want, apps())
+	}
+
+	// Accounting requests are no longer routed to the client.
+	srvCli := &Client{Handler: srvSM, Routes: NewRoutingTable()}
+	srvCli.Routes.Add(cliCfg.OriginRealm, AnyApplication, Route{Host: cliCfg.OriginHost})
+	m := diam.NewRequest(diam.Accounting, 3, dict.Default)
+	m.NewAVP(avp.DestinationRealm, avp.Mbit, 0, cliCfg.OriginRealm)
+	if _, err = srvCli.nextHop(m); err != ErrNoAvailablePeer {
+		t.Fatal("Unexpected error:", err)
+	}
+	m = diam.NewRequest(diam.CreditControl, 4, dict.Default)
+	m.NewAVP(avp.DestinationRealm, avp.Mbit, 0, cliCfg.OriginRealm)
+	if _, err = srvCli.nextHop(m); err != nil {
+		t.Fatal(err)
+	}
+
+	// No common application.
+	cli.AuthApplicationID = nil
+	cli.AcctApplicationID = []*diam.AVP{
+		diam.NewAVP(avp.AcctApplicationID, avp.Mbit, 0, datatype.Unsigned32(2)),
+	}
+	err = cli.UpdateCapabilities(c)
+	if fe, ok := err.(*smparser.ErrFailedUpdate); !ok || fe.ResultCode != diam.NoCommonApplication {
+		t.Fatal("Unexpected error:", err)
+	}
+	select {
+	case <-c.(diam.CloseNotifier).CloseNotify():
+	case <-time.After(time.Second):
+		t.Fatal("Connection was not closed")
+	}
+}
+
+func TestClient_UpdateCapabilities_Unsupported(t *testing.T) {
+	srv := diamtest.NewServer(New(serverSettings), dict.Default)
+	defer srv.Close()
+
+	cliCfg := *clientSettings
+	cliCfg.CapabilitiesUpdate = true
+	cli := newPeerClient(New(&cliCfg), "")
+	c, err := cli.Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if err = cli.UpdateCapabilities(c); err != ErrUpdateUnsupported {
+		t.Fatal("Unexpected error:", err)
+	}
+}
@@ -0,0 +1,373 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"errors"
+	"math/rand"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// DefaultDiscoveryTTL is the time peers found by Discovery are cached,
+// when its TTL is not set.
+const DefaultDiscoveryTTL = 5 * time.Minute
+
+// Transports of the peers found by Discovery, as in the S-NAPTR services
+// of RFC 6408.
+const (
+	TransportTCP  = "tcp"
+	TransportSCTP = "sctp"
+	TransportTLS  = "tls.tcp" // TLS over TCP
+)
+
+// Default ports of the transports, see RFC 6733 section 11.5.
+const (
+	defaultPort    = "3868"
+	defaultTLSPort = "5658"
+)
+
+var (
+	// ErrMissingDiscovery is returned by DialRealm when the client
+	// has no Discovery.
+	ErrMissingDiscovery = errors.New("client discovery is nil")
+
+	// ErrNoPeerFound is returned by Discovery when no peer of a realm
+	// is found in DNS.
+	ErrNoPeerFound = errors.New("no peer found for realm")
+)
+
+// Candidate is a peer found by Discovery.
+type Candidate struct {
+	Transport string // TransportTCP, TransportSCTP or TransportTLS
+	Host      string // Host name or address of the peer
+	Addr      string // Address to dial, as host:port
+}
+
+// Discovery finds the peers of realms in DNS, as described in RFC 6733
+// section 5.2: the realm is resolved with NAPTR records of the S-NAPTR
+// services of RFC 6408, such as "aaa+ap4:diameter.tcp", whose SRV or
+// address records give the peers. Realms without NAPTR records are
+// resolved with the SRV records of _diameter._tcp, _diameters._tcp and
+// _diameter._sctp, and then with their own addresses.
+//
+// Peers are returned in order of preference: NAPTR order and preference,
+// then SRV priority, and SRV weights among peers of the same priority.
+// Results are cached for TTL.
+type Discovery struct {
+	// Resolver looks up the DNS records. DefaultResolver is used when
+	// unset.
+	Resolver Resolver
+
+	// Applications restricts the peers to those of NAPTR services
+	// of these applications, or of the Relay application. All peers
+	// are returned when empty.
+	Applications []uint32
+
+	// Transports are the transports of the peers to return, in order
+	// of preference for realms without NAPTR records. Defaults to
+	// TransportTCP.
+	Transports []string
+
+	// TTL is the time results are cached. Defaults to
+	// DefaultDiscoveryTTL.
+	TTL time.Duration
+
+	mu    sync.Mutex // guards cache
+	cache map[datatype.DiameterIdentity]*discovered
+}
+
+type discovered struct {
+	peers   []Candidate
+	expires time.Time
+}
+
+// Lookup returns the peers of realm, from the cache when it has not
+// expired.
+func (d *Discovery) Lookup(ctx context.Context, realm datatype.DiameterIdentity) ([]Candidate, error) {
+	peers, _, err := d.lookup(ctx, realm)
+	return peers, err
+}
+
+// lookup is like Lookup, and returns whether the peers were cached.
+func (d *Discovery) lookup(ctx context.Context, realm datatype.DiameterIdentity) ([]Candidate, bool, error) {
+	d.mu.Lock()
+	if e, ok := d.cache[realm]; ok && time.Now().Before(e.expires) {
+		d.mu.Unlock()
+		return e.peers, true, nil
+	}
+	d.mu.Unlock()
+	peers, err := d.resolve(ctx, string(realm))
+	if err != nil {
+		return nil, false, err
+	}
+	ttl := d.TTL
+	if ttl == 0 {
+		ttl = DefaultDiscoveryTTL
+	}
+	d.mu.Lock()
+	if d.cache == nil {
+		d.cache = make(map[datatype.DiameterIdentity]*discovered)
+	}
+	d.cache[realm] = &discovered{peers: peers, expires: time.Now().Add(ttl)}
+	d.mu.Unlock()
+	return peers, false, nil
+}
+
+// Invalidate removes the peers of realm from the cache, so that the
+// next Lookup resolves it again.
+func (d *Discovery) Invalidate(realm datatype.DiameterIdentity) {
+	d.mu.Lock()
+	delete(d.cache, realm)
+	d.mu.Unlock()
+}
+
+func (d *Discovery) resolver() Resolver {
+	if d.Resolver == nil {
+		return DefaultResolver
+	}
+	return d.Resolver
+}
+
+func (d *Discovery) transports() []string {
+	if len(d.Transports) == 0 {
+		return []string{TransportTCP}
+	}
+	return d.Transports
+}
+
+// resolve looks up the peers of realm in DNS.
+func (d *Discovery) resolve(ctx context.Context, realm string) ([]Candidate, error) {
+	r := d.resolver()
+	var peers []Candidate
+	// NAPTR lookup errors are handled as the absence of records.
+	rrs, _ := r.LookupNAPTR(ctx, realm)
+	sort.SliceStable(rrs, func(i, j int) bool {
+		if rrs[i].Order != rrs[j].Order {
+			return rrs[i].Order < rrs[j].Order
+		}
+		return rrs[i].Preference < rrs[j].Preference
+	})
+	for _, rr := range rrs {
+		transport, ok := d.service(rr.Service)
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(rr.Flags) {
+		case "s":
+			srvs, err := r.LookupSRV(ctx, rr.Replacement)
+			if err == nil {
+				peers = append(peers, srvCandidates(transport, srvs)...)
+			}
+		case "a":
+			addrs, err := r.LookupHost(ctx, rr.Replacement)
+			if err == nil {
+				peers = append(peers, hostCandidates(transport, rr.Replacement, addrs)...)
+			}
+		}
+	}
+	if len(peers) > 0 {
+		return peers, nil
+	}
+	for _, transport := range d.transports() {
+		srvs, err := r.LookupSRV(ctx, srvName(transport)+"."+realm)
+		if err == nil {
+			peers = append(peers, srvCandidates(transport, srvs)...)
+		}
+	}
+	if len(peers) > 0 {
+		return peers, nil
+	}
+	addrs, err := r.LookupHost(ctx, realm)
+	if err != nil {
+		return nil, err
+	}
+	for _, transport := range d.transports() {
+		peers = append(peers, hostCandidates(transport, realm, addrs)...)
+	}
+	if len(peers) == 0 {
+		return nil, ErrNoPeerFound
+	}
+	return peers, nil
+}
+
+// service returns the transport of the S-NAPTR service s, or of the
+// services "AAA+D2T" and "AAA+D2S" of RFC 3588, and whether it is one of
+// the transports and applications of d.
+func (d *Discovery) service(s string) (string, bool) {
+	var transport string
+	s = strings.ToLower(s)
+	switch s {
+	case "aaa+d2t":
+		transport = TransportTCP
+	case "aaa+d2s":
+		transport = TransportSCTP
+	default:
+		i := strings.Index(s, ":diameter.")
+		if i < 0 {
+			return "", false
+		}
+		tag := s[:i]
+		transport = s[i+len(":diameter."):]
+		if tag != "aaa" {
+			if !strings.HasPrefix(tag, "aaa+ap") {
+				return "", false
+			}
+			id, err := strconv.ParseUint(tag[len("aaa+ap"):], 10, 32)
+			if err != nil || !d.hasApplication(uint32(id)) {
+				return "", false
+			}
+		}
+	}
+	for _, t := range d.transports() {
+		if t == transport {
+			return transport, true
+		}
+	}
+	return "", false
+}
+
+func (d *Discovery) hasApplication(id uint32) bool {
+	if len(d.Applications) == 0 || id == AnyApplication { // Relay
+		return true
+	}
+	for _, app := range d.Applications {
+		if app == id {
+			return true
+		}
+	}
+	return false
+}
+
+// srvName returns the SRV service and protocol labels of transport.
+func srvName(transport string) string {
+	switch transport {
+	case TransportTLS:
+		return "_diameters._tcp"
+	case TransportSCTP:
+		return "_diameter._sctp"
+	}
+	return "_diameter._tcp"
+}
+
+func srvCandidates(transport string, srvs []*net.SRV) []Candidate {
+	var peers []Candidate
+	for _, srv := range orderSRV(srvs) {
+		host := strings.TrimSuffix(srv.Target, ".")
+		if host == "" {
+			continue // "." means the service is not available
+		}
+		peers = append(peers, Candidate{
+			Transport: transport,
+			Host:      host,
+			Addr:      net.JoinHostPort(host, strconv.Itoa(int(srv.Port))),
+		})
+	}
+	return peers
+}
+
+func hostCandidates(transport, host string, addrs []string) []Candidate {
+	port := defaultPort
+	if transport == TransportTLS {
+		port = defaultTLSPort
+	}
+	peers := make([]Candidate, 0, len(addrs))
+	for _, addr := range addrs {
+		peers = append(peers, Candidate{
+			Transport: transport,
+			Host:      strings.TrimSuffix(host, "."),
+			Addr:      net.JoinHostPort(addr, port),
+		})
+	}
+	return peers
+}
+
+// orderSRV returns the SRV records in the order they must be tried,
+// as described in RFC 2782: by priority, and in a random order weighted
+// by their weight among records of the same priority.
+func orderSRV(srvs []*net.SRV) []*net.SRV {
+	s := append([]*net.SRV(nil), srvs...)
+	sort.SliceStable(s, func(i, j int) bool {
+		return s[i].Priority < s[j].Priority
+	})
+	for i := 0; i < len(s); {
+		j := i + 1
+		for j < len(s) && s[j].Priority == s[i].Priority {
+			j++
+		}
+		shuffleByWeight(s[i:j])
+		i = j
+	}
+	return s
+}
+
+func shuffleByWeight(s []*net.SRV) {
+	sum := 0
+	for _, srv := range s {
+		sum += int(srv.Weight)
+	}
+	for sum > 0 && len(s) > 1 {
+		n, acc := rand.Intn(sum), 0
+		for i := range s {
+			acc += int(s[i].Weight)
+			if acc > n {
+				s[0], s[i] = s[i], s[0]
+				break
+			}
+		}
+		sum -= int(s[0].Weight)
+		s = s[1:]
+	}
+}
+
+// DialRealm dials the peers of realm found by the client's Discovery in
+// order of preference, and returns the first connection that completes
+// the handshake. When all the peers fail, the realm is resolved again
+// and its new peers dialed, unless they were not cached.
+//
+// Peers of TransportTLS are dialed with DialNetworkTLS, without client
+// certificate.
+func (cli *Client) DialRealm(realm datatype.DiameterIdentity) (diam.Conn, error) {
+	d := cli.Discovery
+	if d == nil {
+		return nil, ErrMissingDiscovery
+	}
+	var err error
+	for {
+		peers, cached, lerr := d.lookup(context.Background(), realm)
+		if lerr != nil {
+			return nil, lerr
+		}
+		for _, p := range peers {
+			var c diam.Conn
+			if c, err = cli.dialCandidate(p); err == nil {
+				return c, nil
+			}
+		}
+		d.Invalidate(realm)
+		if !cached {
+			return nil, err
+		}
+	}
+}
+
+func (cli *Client) dialCandidate(p Candidate) (diam.Conn, error) {
+	switch p.Transport {
+	case TransportTLS:
+		return cli.DialNetworkTLS("tcp", p.Addr, "", "", nil)
+	case TransportSCTP:
+		return cli.DialNetwork("sctp", p.Addr)
+	}
+	return cli.DialNetwork("tcp", p.Addr)
+}
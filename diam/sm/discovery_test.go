@@ -0,0 +1,233 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+
+	"golang.org/x/net/context"
+	"golang.org/x/net/dns/dnsmessage"
+
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+var errNotFound = errors.New("not found")
+
+// fakeResolver is a Resolver of static records.
+type fakeResolver struct {
+	mu      sync.Mutex
+	naptr   map[string][]*NAPTR
+	srv     map[string][]*net.SRV
+	host    map[string][]string
+	lookups int
+}
+
+func (r *fakeResolver) LookupNAPTR(ctx context.Context, name string) ([]*NAPTR, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lookups++
+	if rrs, ok := r.naptr[name]; ok {
+		return rrs, nil
+	}
+	return nil, errNotFound
+}
+
+func (r *fakeResolver) LookupSRV(ctx context.Context, name string) ([]*net.SRV, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if srvs, ok := r.srv[name]; ok {
+		return srvs, nil
+	}
+	return nil, errNotFound
+}
+
+func (r *fakeResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if addrs, ok := r.host[host]; ok {
+		return addrs, nil
+	}
+	return nil, errNotFound
+}
+
+func TestDiscovery_NAPTR(t *testing.T) {
+	d := &Discovery{
+		Applications: []uint32{4},
+		Transports:   []string{TransportTCP, TransportSCTP},
+		Resolver: &fakeResolver{
+			naptr: map[string][]*NAPTR{
+				"example.com": {
+					{Order: 20, Flags: "s", Service: "aaa+ap4:diameter.tcp", Replacement: "_diameter._tcp.example.com"},
+					{Order: 10, Flags: "s", Service: "aaa+ap1:diameter.tcp", Replacement: "_acct._tcp.example.com"},
+					{Order: 10, Preference: 2, Flags: "a", Service: "AAA+D2S", Replacement: "sctp.example.com"},
+					{Order: 10, Preference: 1, Flags: "s", Service: "aaa+ap4:diameter.tls.tcp", Replacement: "_diameters._tcp.example.com"},
+				},
+			},
+			srv: map[string][]*net.SRV{
+				"_diameter._tcp.example.com": {
+					{Target: "b.example.com.", Port: 3868, Priority: 2, Weight: 1},
+					{Target: "a.example.com.", Port: 3869, Priority: 1, Weight: 1},
+				},
+				"_acct._tcp.example.com": {
+					{Target: "acct.example.com.", Port: 3868},
+				},
+			},
+			host: map[string][]string{
+				"sctp.example.com": {"10.0.0.1"},
+			},
+		},
+	}
+	peers, err := d.Lookup(context.Background(), "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Candidate{
+		{TransportSCTP, "sctp.example.com", "10.0.0.1:3868"},
+		{TransportTCP, "a.example.com", "a.example.com:3869"},
+		{TransportTCP, "b.example.com", "b.example.com:3868"},
+	}
+	if !reflect.DeepEqual(peers, want) {
+		t.Fatalf("Unexpected peers.\nWant %v\nHave %v", want, peers)
+	}
+}
+
+func TestDiscovery_Fallback(t *testing.T) {
+	r := &fakeResolver{
+		srv: map[string][]*net.SRV{
+			"_diameters._tcp.example.com": {{Target: "tls.example.com.", Port: 5658}},
+		},
+		host: map[string][]string{"example.com": {"10.0.0.1"}},
+	}
+	d := &Discovery{Resolver: r}
+	peers, err := d.Lookup(context.Background(), "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Candidate{{TransportTCP, "example.com", "10.0.0.1:3868"}}
+	if !reflect.DeepEqual(peers, want) {
+		t.Fatalf("Unexpected peers.\nWant %v\nHave %v", want, peers)
+	}
+
+	d = &Discovery{Resolver: r, Transports: []string{TransportTLS, TransportTCP}}
+	peers, err = d.Lookup(context.Background(), "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []Candidate{{TransportTLS, "tls.example.com", "tls.example.com:5658"}}
+	if !reflect.DeepEqual(peers, want) {
+		t.Fatalf("Unexpected peers.\nWant %v\nHave %v", want, peers)
+	}
+
+	if _, err = d.Lookup(context.Background(), "example.org"); err != errNotFound {
+		t.Fatal("Unexpected error:", err)
+	}
+}
+
+func TestOrderSRV_Weight(t *testing.T) {
+	srvs := []*net.SRV{
+		{Target: "zero", Priority: 1, Weight: 0},
+		{Target: "heavy", Priority: 1, Weight: 1000},
+		{Target: "backup", Priority: 2, Weight: 1000},
+	}
+	for i := 0; i < 10; i++ {
+		s := orderSRV(srvs)
+		if s[0].Target != "heavy" || s[1].Target != "zero" || s[2].Target != "backup" {
+			t.Fatalf("Unexpected order: %s, %s, %s", s[0].Target, s[1].Target, s[2].Target)
+		}
+	}
+}
+
+func TestClient_DialRealm(t *testing.T) {
+	srv := diamtest.NewServer(New(serverSettings), dict.Default)
+	defer srv.Close()
+	host, port, err := net.SplitHostPort(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, _ := strconv.Atoi(port)
+
+	// The first peer is down, and the second one is only found after
+	// the realm is resolved again.
+	r := &fakeResolver{
+		srv: map[string][]*net.SRV{
+			"_diameter._tcp.example.com": {{Target: "127.0.0.1.", Port: 1}},
+		},
+	}
+	cli := newPeerClient(New(clientSettings), "")
+	cli.Discovery = &Discovery{Resolver: r}
+	if _, err = cli.DialRealm("example.com"); err == nil {
+		t.Fatal("Dial to unavailable peer succeeded")
+	}
+	if _, err = cli.Discovery.Lookup(context.Background(), "example.com"); err != nil {
+		t.Fatal(err)
+	}
+	r.mu.Lock()
+	r.srv["_diameter._tcp.example.com"] = []*net.SRV{{Target: host, Port: uint16(p)}}
+	r.mu.Unlock()
+	c, err := cli.DialRealm("example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if r.lookups != 3 {
+		t.Fatalf("Unexpected number of lookups. Want 3, have %d", r.lookups)
+	}
+}
+
+func TestParseNAPTRAnswer(t *testing.T) {
+	name := dnsmessage.MustNewName("example.com.")
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: 1, Response: true})
+	b.EnableCompression()
+	b.StartQuestions()
+	b.Question(dnsmessage.Question{Name: name, Type: typeNAPTR, Class: dnsmessage.ClassINET})
+	a, err := b.Finish()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The answer: a pointer to the question name, and the record.
+	a[7] = 1 // ancount
+	a = append(a, 0xc0, 12, 0, byte(typeNAPTR), 0, 1, 0, 0, 0, 60)
+	rdata := []byte{0, 10, 0, 20, 1, 's'}
+	rdata = append(rdata, 20)
+	rdata = append(rdata, "aaa+ap4:diameter.tcp"...)
+	rdata = append(rdata, 0)
+	rdata = append(rdata, 9)
+	rdata = append(rdata, "_diameter"...)
+	rdata = append(rdata, 4)
+	rdata = append(rdata, "_tcp"...)
+	rdata = append(rdata, 0xc0, 12)
+	a = append(a, 0, byte(len(rdata)))
+	a = append(a, rdata...)
+
+	rrs, truncated, err := parseNAPTRAnswer(1, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if truncated {
+		t.Fatal("Unexpected truncated answer")
+	}
+	want := []*NAPTR{{
+		Order:       10,
+		Preference:  20,
+		Flags:       "s",
+		Service:     "aaa+ap4:diameter.tcp",
+		Replacement: "_diameter._tcp.example.com",
+	}}
+	if !reflect.DeepEqual(rrs, want) {
+		t.Fatalf("Unexpected records.\nWant %+v\nHave %+v", want[0], rrs)
+	}
+	if _, _, err = parseNAPTRAnswer(2, a); err != ErrInvalidDNSMessage {
+		t.Fatal("Unexpected error:", err)
+	}
+	if _, _, err = parseNAPTRAnswer(1, a[:len(a)-3]); err != ErrInvalidDNSMessage {
+		t.Fatal("Unexpected error:", err)
+	}
+}
@@ -0,0 +1,283 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// typeNAPTR is the DNS type of NAPTR records, see RFC 3403.
+const typeNAPTR dnsmessage.Type = 35
+
+// dnsTimeout is the time to wait for the answer of a name server when
+// the context has no deadline.
+const dnsTimeout = 5 * time.Second
+
+// ErrInvalidDNSMessage is returned when the answer of a name server
+// cannot be parsed.
+var ErrInvalidDNSMessage = errors.New("invalid DNS message")
+
+// NAPTR is a DNS Naming Authority Pointer record, see RFC 3403.
+type NAPTR struct {
+	Order       uint16
+	Preference  uint16
+	Flags       string
+	Service     string
+	Regexp      string
+	Replacement string
+}
+
+// Resolver looks up the DNS records used by peer discovery.
+type Resolver interface {
+	// LookupNAPTR returns the NAPTR records of name.
+	LookupNAPTR(ctx context.Context, name string) ([]*NAPTR, error)
+	// LookupSRV returns the SRV records of name, such as
+	// "_diameter._tcp.example.com".
+	LookupSRV(ctx context.Context, name string) ([]*net.SRV, error)
+	// LookupHost returns the addresses of host.
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// DefaultResolver is the Resolver used by Discovery when none is set.
+// SRV and address records are looked up with net.DefaultResolver, and
+// NAPTR records with the name servers of /etc/resolv.conf.
+var DefaultResolver Resolver = dnsResolver{conf: "/etc/resolv.conf"}
+
+type dnsResolver struct {
+	conf string // path of resolv.conf
+}
+
+func (r dnsResolver) LookupSRV(ctx context.Context, name string) ([]*net.SRV, error) {
+	_, srvs, err := net.DefaultResolver.LookupSRV(ctx, "", "", name)
+	return srvs, err
+}
+
+func (r dnsResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	return net.DefaultResolver.LookupHost(ctx, host)
+}
+
+func (r dnsResolver) LookupNAPTR(ctx context.Context, name string) ([]*NAPTR, error) {
+	var err error
+	for _, server := range nameServers(r.conf) {
+		var rrs []*NAPTR
+		if rrs, err = queryNAPTR(ctx, server, name); err == nil {
+			return rrs, nil
+		}
+	}
+	return nil, err
+}
+
+// nameServers returns the addresses of the name servers in the
+// resolv.conf file at path, or the local name server.
+func nameServers(path string) []string {
+	var servers []string
+	if f, err := os.Open(path); err == nil {
+		defer f.Close()
+		s := bufio.NewScanner(f)
+		for s.Scan() {
+			fields := strings.Fields(s.Text())
+			if len(fields) > 1 && fields[0] == "nameserver" {
+				servers = append(servers, net.JoinHostPort(fields[1], "53"))
+			}
+		}
+	}
+	if len(servers) == 0 {
+		servers = []string{"127.0.0.1:53"}
+	}
+	return servers
+}
+
+// queryNAPTR queries the name server at addr for the NAPTR records of
+// name, over UDP and then over TCP when the answer is truncated.
+func queryNAPTR(ctx context.Context, addr, name string) ([]*NAPTR, error) {
+	if !strings.HasSuffix(name, ".") {
+		name += "."
+	}
+	qname, err := dnsmessage.NewName(name)
+	if err != nil {
+		return nil, err
+	}
+	id := uint16(rand.Uint32())
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: id, RecursionDesired: true})
+	b.StartQuestions()
+	b.Question(dnsmessage.Question{Name: qname, Type: typeNAPTR, Class: dnsmessage.ClassINET})
+	q, err := b.Finish()
+	if err != nil {
+		return nil, err
+	}
+	for _, network := range []string{"udp", "tcp"} {
+		a, err := exchangeDNS(ctx, network, addr, q)
+		if err != nil {
+			return nil, err
+		}
+		rrs, truncated, err := parseNAPTRAnswer(id, a)
+		if err != nil || !truncated {
+			return rrs, err
+		}
+	}
+	return nil, ErrInvalidDNSMessage
+}
+
+// exchangeDNS sends the query q to the name server at addr and returns
+// its answer.
+func exchangeDNS(ctx context.Context, network, addr string, q []byte) ([]byte, error) {
+	var d net.Dialer
+	c, err := d.DialContext(ctx, network, addr)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(dnsTimeout)
+	}
+	c.SetDeadline(deadline)
+	if network == "udp" {
+		if _, err = c.Write(q); err != nil {
+			return nil, err
+		}
+		a := make([]byte, 4096)
+		n, err := c.Read(a)
+		if err != nil {
+			return nil, err
+		}
+		return a[:n], nil
+	}
+	// Messages over TCP are prefixed with their length.
+	b := make([]byte, 2+len(q))
+	binary.BigEndian.PutUint16(b, uint16(len(q)))
+	copy(b[2:], q)
+	if _, err = c.Write(b); err != nil {
+		return nil, err
+	}
+	if _, err = io.ReadFull(c, b[:2]); err != nil {
+		return nil, err
+	}
+	a := make([]byte, binary.BigEndian.Uint16(b))
+	if _, err = io.ReadFull(c, a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// parseNAPTRAnswer returns the NAPTR records of the answer b to the
+// query id, or whether it was truncated. Names that do not exist have
+// no records.
+func parseNAPTRAnswer(id uint16, b []byte) (rrs []*NAPTR, truncated bool, err error) {
+	if len(b) < 12 || binary.BigEndian.Uint16(b) != id {
+		return nil, false, ErrInvalidDNSMessage
+	}
+	flags := binary.BigEndian.Uint16(b[2:])
+	if flags&0x0200 != 0 {
+		return nil, true, nil
+	}
+	switch dnsmessage.RCode(flags & 0xf) {
+	case dnsmessage.RCodeSuccess:
+	case dnsmessage.RCodeNameError:
+		return nil, false, nil
+	default:
+		return nil, false, ErrInvalidDNSMessage
+	}
+	qdcount := int(binary.BigEndian.Uint16(b[4:]))
+	ancount := int(binary.BigEndian.Uint16(b[6:]))
+	off := 12
+	for i := 0; i < qdcount; i++ {
+		if _, off, err = readDNSName(b, off); err != nil {
+			return nil, false, err
+		}
+		off += 4 // type and class
+	}
+	for i := 0; i < ancount; i++ {
+		if _, off, err = readDNSName(b, off); err != nil {
+			return nil, false, err
+		}
+		if off+10 > len(b) {
+			return nil, false, ErrInvalidDNSMessage
+		}
+		typ := dnsmessage.Type(binary.BigEndian.Uint16(b[off:]))
+		end := off + 10 + int(binary.BigEndian.Uint16(b[off+8:]))
+		if end > len(b) {
+			return nil, false, ErrInvalidDNSMessage
+		}
+		if typ == typeNAPTR {
+			rr, err := parseNAPTR(b, off+10, end)
+			if err != nil {
+				return nil, false, err
+			}
+			rrs = append(rrs, rr)
+		}
+		off = end
+	}
+	return rrs, false, nil
+}
+
+// parseNAPTR parses the data of a NAPTR record in b[off:end].
+func parseNAPTR(b []byte, off, end int) (*NAPTR, error) {
+	if off+4 > end {
+		return nil, ErrInvalidDNSMessage
+	}
+	rr := &NAPTR{
+		Order:      binary.BigEndian.Uint16(b[off:]),
+		Preference: binary.BigEndian.Uint16(b[off+2:]),
+	}
+	off += 4
+	var err error
+	for _, s := range []*string{&rr.Flags, &rr.Service, &rr.Regexp} {
+		if off >= end || off+1+int(b[off]) > end {
+			return nil, ErrInvalidDNSMessage
+		}
+		*s = string(b[off+1 : off+1+int(b[off])])
+		off += 1 + int(b[off])
+	}
+	if rr.Replacement, _, err = readDNSName(b[:end], off); err != nil {
+		return nil, err
+	}
+	return rr, nil
+}
+
+// readDNSName reads the possibly compressed domain name at b[off:], and
+// returns it without the trailing dot, and the offset that follows it.
+func readDNSName(b []byte, off int) (string, int, error) {
+	var labels []string
+	next := -1 // offset after the name, once a pointer is followed
+	for ptrs := 0; ; {
+		if off >= len(b) {
+			return "", 0, ErrInvalidDNSMessage
+		}
+		n := int(b[off])
+		switch {
+		case n == 0:
+			if next < 0 {
+				next = off + 1
+			}
+			return strings.Join(labels, "."), next, nil
+		case n&0xc0 == 0xc0:
+			if off+1 >= len(b) || ptrs > 10 {
+				return "", 0, ErrInvalidDNSMessage
+			}
+			if next < 0 {
+				next = off + 2
+			}
+			off = int(binary.BigEndian.Uint16(b[off:]) & 0x3fff)
+			ptrs++
+		case n&0xc0 != 0 || off+1+n > len(b):
+			return "", 0, ErrInvalidDNSMessage
+		default:
+			labels = append(labels, string(b[off+1:off+1+n]))
+			off += 1 + n
+		}
+	}
+}
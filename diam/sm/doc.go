@@ -7,4 +7,13 @@
 // It currently handles CER/CEA handshakes, and automatic DWR/DWA. Peers
 // that pass the handshake get metadata associated to their connection.
 // See the peer sub-package for details on the metadata.
+//
+// The state of each peer follows the peer state machine of RFC 6733
+// section 5.6, including the election of simultaneous connections when
+// Settings.SingleConnection is set. See StateMachine.PeerState.
+//
+// Redirect agents are implemented with StateMachine.RedirectHandler, and
+// clients follow redirects in Client.Send when FollowRedirects is set.
+// Requests may also be routed to the next hop by realm and application
+// with a RoutingTable, or balanced across connections with a Pool.
 package sm
@@ -0,0 +1,258 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/sm/smparser"
+	"github.com/omnicate/go-diameter/v4/diam/sm/smpeer"
+)
+
+// DisconnectCause is the value of the Disconnect-Cause AVP of DPRs.
+type DisconnectCause int32
+
+// Disconnect causes. See RFC 6733 section 5.4.3 for details.
+const (
+	DisconnectRebooting            DisconnectCause = 0 // Scheduled reboot
+	DisconnectBusy                 DisconnectCause = 1 // Too busy to handle the peer's traffic
+	DisconnectDoNotWantToTalkToYou DisconnectCause = 2 // Connection is not needed
+)
+
+func (c DisconnectCause) String() string {
+	switch c {
+	case DisconnectRebooting:
+		return "REBOOTING"
+	case DisconnectBusy:
+		return "BUSY"
+	case DisconnectDoNotWantToTalkToYou:
+		return "DO_NOT_WANT_TO_TALK_TO_YOU"
+	}
+	return fmt.Sprintf("DisconnectCause(%d)", int32(c))
+}
+
+// DefaultTc is the default Tc timer of RFC 6733 section 2.1, the time
+// to wait before reconnecting to a peer.
+const DefaultTc = 30 * time.Second
+
+// ErrReconnectDelayed is returned by the Client's Dial functions when
+// PeerIdentity disconnected with a DPR whose reconnect delay has not
+// expired yet. See DisconnectPolicy.
+var ErrReconnectDelayed = errors.New("reconnect to peer delayed after DPR")
+
+// DisconnectPolicy configures the Disconnect-Peer-Requests sent and
+// received by the state machine.
+//
+// Peers that disconnect with a DPR are not dialed again by the Client,
+// when set as its PeerIdentity, until the delay of their
+// Disconnect-Cause expires. Negative delays disable reconnect delays.
+type DisconnectPolicy struct {
+	// Cause is the Disconnect-Cause of the DPRs sent by Disconnect,
+	// when diam.Server.Shutdown is called. Defaults to
+	// DisconnectRebooting.
+	Cause DisconnectCause
+
+	// Rebooting is the reconnect delay of peers that disconnect with
+	// DisconnectRebooting. Defaults to DefaultTc.
+	Rebooting time.Duration
+
+	// Busy is the reconnect delay of peers that disconnect with
+	// DisconnectBusy. Defaults to DefaultTc.
+	Busy time.Duration
+
+	// DoNotWantToTalkToYou is the reconnect delay of peers that
+	// disconnect with DisconnectDoNotWantToTalkToYou. Peers are dialed
+	// again right away by default, as the Client only dials when it
+	// has requests to send, which RFC 6733 section 5.4 allows.
+	DoNotWantToTalkToYou time.Duration
+}
+
+// delay returns the reconnect delay of peers that disconnect with cause.
+func (p *DisconnectPolicy) delay(cause DisconnectCause) time.Duration {
+	var d time.Duration
+	switch cause {
+	case DisconnectRebooting:
+		if d = p.Rebooting; d == 0 {
+			d = DefaultTc
+		}
+	case DisconnectBusy:
+		if d = p.Busy; d == 0 {
+			d = DefaultTc
+		}
+	case DisconnectDoNotWantToTalkToYou:
+		d = p.DoNotWantToTalkToYou
+	}
+	return d
+}
+
+// DisconnectFunc is the type of functions called for the DPRs received
+// on the connection c from the peer host.
+type DisconnectFunc func(c diam.Conn, host datatype.DiameterIdentity, cause DisconnectCause)
+
+// disconnects holds the reconnect delays of the peers that disconnected
+// with a DPR, and the functions notified of DPRs.
+type disconnects struct {
+	mu    sync.Mutex // guards until and funcs
+	until map[datatype.DiameterIdentity]time.Time
+	funcs []DisconnectFunc
+}
+
+// add records the DPR of host, and returns the functions to notify.
+func (d *disconnects) add(host datatype.DiameterIdentity, delay time.Duration) []DisconnectFunc {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if delay > 0 {
+		if d.until == nil {
+			d.until = make(map[datatype.DiameterIdentity]time.Time)
+		}
+		d.until[host] = time.Now().Add(delay)
+	} else {
+		delete(d.until, host)
+	}
+	return d.funcs
+}
+
+// delay returns the time left before reconnecting to host.
+func (d *disconnects) delay(host datatype.DiameterIdentity) time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	until, ok := d.until[host]
+	if !ok {
+		return 0
+	}
+	left := time.Until(until)
+	if left <= 0 {
+		delete(d.until, host)
+		return 0
+	}
+	return left
+}
+
+// NotifyDisconnect registers the function f, called after answering
+// each DPR received, in the order of registration. It may be called
+// several times to register several functions, which must not block.
+func (sm *StateMachine) NotifyDisconnect(f DisconnectFunc) {
+	sm.disconnects.mu.Lock()
+	sm.disconnects.funcs = append(sm.disconnects.funcs, f)
+	sm.disconnects.mu.Unlock()
+}
+
+// ReconnectDelay returns the time left before reconnecting to the peer
+// host, which disconnected with a DPR. See DisconnectPolicy.
+func (sm *StateMachine) ReconnectDelay(host datatype.DiameterIdentity) time.Duration {
+	return sm.disconnects.delay(host)
+}
+
+// handleDPR handles Disconnect-Peer-Request messages. The connection
+// is no longer selected for its peer, and is closed by the peer after
+// receiving the DPA. The peer is not dialed again until the reconnect
+// delay of the Disconnect-Cause expires.
+//
+// See RFC 6733 section 5.4 for details.
+func handleDPR(sm *StateMachine) diam.HandlerFunc {
+	return func(c diam.Conn, m *diam.Message) {
+		dpr := new(smparser.DPR)
+		if err := dpr.Parse(m); err != nil {
+			sm.Error(&diam.ErrorReport{Conn: c, Message: m, Error: err})
+			a := m.Answer(diam.MissingAVP)
+			a.Header.CommandFlags |= diam.ErrorFlag
+			a.NewAVP(avp.OriginHost, avp.Mbit, 0, sm.cfg.OriginHost)
+			a.NewAVP(avp.OriginRealm, avp.Mbit, 0, sm.cfg.OriginRealm)
+			a.WriteTo(c)
+			return
+		}
+		cause := DisconnectCause(*dpr.DisconnectCause)
+		setCloseReason(c, &DisconnectError{Cause: cause})
+		sm.peers.disconnect(c)
+		a := m.Answer(diam.Success)
+		a.NewAVP(avp.OriginHost, avp.Mbit, 0, sm.cfg.OriginHost)
+		a.NewAVP(avp.OriginRealm, avp.Mbit, 0, sm.cfg.OriginRealm)
+		if _, err := a.WriteTo(c); err != nil {
+			sm.Error(&diam.ErrorReport{
+				Conn:    c,
+				Message: m,
+				Error:   fmt.Errorf("Failed to write DPA: %v", err),
+			})
+		}
+		for _, f := range sm.disconnects.add(dpr.OriginHost, sm.cfg.DisconnectPolicy.delay(cause)) {
+			f(c, dpr.OriginHost, cause)
+		}
+	}
+}
+
+// DisconnectPeer sends a Disconnect-Peer-Request with the given cause
+// on c, and waits for its DPA until ctx is done. The connection is no
+// longer selected for its peer, and the requests received on it are
+// answered with DIAMETER_TOO_BUSY.
+//
+// The connection is not closed: it should be closed after its pending
+// requests are answered, see diam.Drain.
+func (sm *StateMachine) DisconnectPeer(ctx context.Context, c diam.Conn, cause DisconnectCause) error {
+	if _, ok := smpeer.FromContext(c.Context()); !ok {
+		// The capabilities exchange did not complete.
+		return nil
+	}
+	sm.peers.disconnect(c)
+	m := diam.NewRequest(diam.DisconnectPeer, 0, c.Dictionary())
+	m.NewAVP(avp.OriginHost, avp.Mbit, 0, sm.cfg.OriginHost)
+	m.NewAVP(avp.OriginRealm, avp.Mbit, 0, sm.cfg.OriginRealm)
+	m.NewAVP(avp.DisconnectCause, avp.Mbit, 0, datatype.Enumerated(cause))
+	_, err := diam.SendRequest(ctx, c, m)
+	return err
+}
+
+// Disconnect implements the diam.Disconnecter interface, used by
+// diam.Server.Shutdown. It disconnects the peer of c with the Cause of
+// the DisconnectPolicy of the settings.
+func (sm *StateMachine) Disconnect(ctx context.Context, c diam.Conn) error {
+	return sm.DisconnectPeer(ctx, c, sm.cfg.DisconnectPolicy.Cause)
+}
+
+// rejectDisconnecting answers the request m received on a connection
+// being disconnected with DIAMETER_TOO_BUSY, and reports whether it did.
+func (sm *StateMachine) rejectDisconnecting(c diam.Conn, m *diam.Message) bool {
+	if baseCommand(m.Header.CommandCode) || !sm.peers.disconnecting(c) {
+		return false
+	}
+	if err := sm.tooBusy(c, m); err != nil {
+		sm.Error(&diam.ErrorReport{Conn: c, Message: m, Error: err})
+	}
+	return true
+}
+
+// Close disconnects all the peers of the Client's Handler gracefully:
+// it stops the Reconnect policy, sends the peers a Disconnect-Peer-Request
+// with DisconnectDoNotWantToTalkToYou, waits for the requests pending on
+// their connections to be answered, and closes the connections.
+//
+// Connections are closed when ctx is done even if requests are still
+// pending, and Close returns the error of ctx.
+func (cli *Client) Close(ctx context.Context) error {
+	if cli.Reconnect != nil {
+		cli.Reconnect.Stop()
+	}
+	var wg sync.WaitGroup
+	for _, c := range cli.Handler.peers.conns() {
+		wg.Add(1)
+		go func(c diam.Conn) {
+			defer wg.Done()
+			if err := cli.Handler.DisconnectPeer(ctx, c, DisconnectDoNotWantToTalkToYou); err != nil {
+				cli.Handler.Error(&diam.ErrorReport{Conn: c, Error: err})
+			}
+			diam.Drain(ctx, c)
+			c.Close()
+		}(c)
+	}
+	wg.Wait()
+	return ctx.Err()
+}
@@ -0,0 +1,148 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+func TestClient_Close(t *testing.T) {
+	srvSM := newACRServer(serverSettings)
+	srv := diamtest.NewServer(srvSM, dict.Default)
+	defer srv.Close()
+
+	cli := newPeerClient(New(clientSettings), "")
+	c, err := cli.Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if err = waitPeerState(srvSM, clientSettings.OriginHost, ROpen); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err = cli.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if state := cli.Handler.PeerState(serverSettings.OriginHost); state != Closed && state != Closing {
+		t.Fatalf("Unexpected state of the server. Want %s, have %s", Closed, state)
+	}
+	if err = waitPeerState(srvSM, clientSettings.OriginHost, Closed); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestServer_Shutdown(t *testing.T) {
+	srvSM := New(serverSettings)
+	srvSM.HandleFunc("ACR", func(c diam.Conn, m *diam.Message) {
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			a := m.Answer(diam.Success)
+			a.NewAVP(avp.OriginHost, avp.Mbit, 0, serverSettings.OriginHost)
+			a.NewAVP(avp.OriginRealm, avp.Mbit, 0, serverSettings.OriginRealm)
+			a.WriteTo(c)
+		}()
+	})
+	srv := diamtest.NewServer(srvSM, dict.Default)
+	defer srv.Close()
+
+	causec := make(chan *diam.AVP, 1)
+	cli := newPeerClient(New(clientSettings), "")
+	cli.Handler.HandleFunc("DPR", func(c diam.Conn, m *diam.Message) {
+		cause, _ := m.FindAVP(avp.DisconnectCause, 0)
+		causec <- cause
+		handleDPR(cli.Handler)(c, m)
+	})
+	c, err := cli.Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	answerc := make(chan error, 1)
+	go func() {
+		_, err := cli.Send(c, newACR(cli))
+		answerc <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err = srv.Config.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case cause := <-causec:
+		if cause == nil || cause.Data != datatype.Enumerated(DisconnectRebooting) {
+			t.Fatalf("Unexpected Disconnect-Cause: %v", cause)
+		}
+	default:
+		t.Fatal("No DPR received")
+	}
+	select {
+	case err := <-answerc:
+		if err != nil {
+			t.Fatalf("Pending request failed: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Pending request was not answered")
+	}
+	if err = waitPeerState(cli.Handler, serverSettings.OriginHost, Closed); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStateMachine_DisconnectPolicy(t *testing.T) {
+	cfg := *serverSettings
+	cfg.DisconnectPolicy = DisconnectPolicy{Cause: DisconnectBusy}
+	srv := diamtest.NewServer(newACRServer(&cfg), dict.Default)
+	defer srv.Close()
+
+	cliCfg := *clientSettings
+	cliCfg.DisconnectPolicy = DisconnectPolicy{Busy: time.Minute}
+	cli := newPeerClient(New(&cliCfg), "")
+	cli.PeerIdentity = serverSettings.OriginHost
+	causec := make(chan DisconnectCause, 1)
+	cli.Handler.NotifyDisconnect(func(c diam.Conn, host datatype.DiameterIdentity, cause DisconnectCause) {
+		if host == serverSettings.OriginHost {
+			causec <- cause
+		}
+	})
+	c, err := cli.Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err = srv.Config.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case cause := <-causec:
+		if cause != DisconnectBusy {
+			t.Fatalf("Unexpected cause. Want %d, have %d", DisconnectBusy, cause)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("DPR was not notified")
+	}
+	if d := cli.Handler.ReconnectDelay(serverSettings.OriginHost); d <= 0 || d > time.Minute {
+		t.Fatalf("Unexpected reconnect delay: %s", d)
+	}
+	if err = waitPeerState(cli.Handler, serverSettings.OriginHost, Closed); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = cli.Dial(srv.Addr); err != ErrReconnectDelayed {
+		t.Fatalf("Unexpected error. Want %v, have %v", ErrReconnectDelayed, err)
+	}
+}
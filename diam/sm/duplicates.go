@@ -0,0 +1,191 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// Defaults of DuplicateCache.
+const (
+	DefaultDuplicateTTL  = time.Minute
+	DefaultDuplicateSize = 4096
+)
+
+// DuplicateCache detects the retransmissions of requests that were
+// already answered, which are identified by their Origin-Host and
+// End-to-End identifier, and answers them with the cached answer instead
+// of dispatching them to their handler again. See RFC 6733 section 9.
+//
+// Requests received with the T flag that are not in the cache, or whose
+// original is still being handled on another connection, are handled
+// normally.
+//
+// It must not be copied after first use. It is safe for concurrent use.
+type DuplicateCache struct {
+	// TTL is the time answers are cached for. Defaults to
+	// DefaultDuplicateTTL.
+	TTL time.Duration
+
+	// MaxSize is the maximum number of requests tracked, after which
+	// the oldest are evicted. Defaults to DefaultDuplicateSize.
+	MaxSize int
+
+	mu       sync.Mutex // guards entries, order and inflight
+	entries  map[duplicateKey]*list.Element
+	order    list.List // of *duplicateEntry, oldest first
+	inflight map[spanKey]duplicateKey
+}
+
+// duplicateKey identifies a request for duplicate detection.
+type duplicateKey struct {
+	host datatype.DiameterIdentity
+	e2e  uint32
+}
+
+type duplicateEntry struct {
+	key     duplicateKey
+	conn    diam.Conn     // connection the request was received on
+	hbh     uint32        // Hop-by-Hop identifier of the request
+	answer  *diam.Message // nil while the request is being handled
+	expires time.Time
+}
+
+func (dc *DuplicateCache) ttl() time.Duration {
+	if dc.TTL > 0 {
+		return dc.TTL
+	}
+	return DefaultDuplicateTTL
+}
+
+func (dc *DuplicateCache) maxSize() int {
+	if dc.MaxSize > 0 {
+		return dc.MaxSize
+	}
+	return DefaultDuplicateSize
+}
+
+// requestKey returns the key of the request m, or false if it has no
+// Origin-Host.
+func requestKey(m *diam.Message) (duplicateKey, bool) {
+	a, err := m.FindAVP(avp.OriginHost, 0)
+	if err != nil || a == nil {
+		return duplicateKey{}, false
+	}
+	host, ok := a.Data.(datatype.DiameterIdentity)
+	return duplicateKey{host, m.Header.EndToEndID}, ok
+}
+
+// received records the request m received on c, and returns the cached
+// answer when it is a duplicate, with the Hop-by-Hop identifier of m.
+// It returns true with a nil answer for duplicates of requests still
+// being handled on c, which must be dropped.
+func (dc *DuplicateCache) received(c diam.Conn, m *diam.Message) (*diam.Message, bool) {
+	k, ok := requestKey(m)
+	if !ok {
+		return nil, false
+	}
+	now := time.Now()
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	dc.expire(now)
+	if el, ok := dc.entries[k]; ok && m.Header.CommandFlags&diam.RetransmittedFlag != 0 {
+		e := el.Value.(*duplicateEntry)
+		if e.answer != nil {
+			a := *e.answer
+			h := *e.answer.Header
+			h.HopByHopID = m.Header.HopByHopID
+			a.Header = &h
+			return &a, true
+		}
+		if e.conn == c {
+			return nil, true
+		}
+	}
+	if dc.entries == nil {
+		dc.entries = make(map[duplicateKey]*list.Element)
+		dc.inflight = make(map[spanKey]duplicateKey)
+	}
+	if el, ok := dc.entries[k]; ok {
+		dc.remove(el)
+	}
+	for dc.order.Len() >= dc.maxSize() {
+		dc.remove(dc.order.Front())
+	}
+	dc.entries[k] = dc.order.PushBack(&duplicateEntry{
+		key:     k,
+		conn:    c,
+		hbh:     m.Header.HopByHopID,
+		expires: now.Add(dc.ttl()),
+	})
+	dc.inflight[spanKey{c, m.Header.HopByHopID}] = k
+	return nil, false
+}
+
+// answered caches the answer a sent on c.
+func (dc *DuplicateCache) answered(c diam.Conn, a *diam.Message) {
+	sk := spanKey{c, a.Header.HopByHopID}
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	k, ok := dc.inflight[sk]
+	if !ok {
+		return
+	}
+	delete(dc.inflight, sk)
+	if el, ok := dc.entries[k]; ok {
+		e := el.Value.(*duplicateEntry)
+		e.answer = a
+		e.expires = time.Now().Add(dc.ttl())
+		dc.order.MoveToBack(el)
+	}
+}
+
+// expire removes the entries that expired at now.
+func (dc *DuplicateCache) expire(now time.Time) {
+	for el := dc.order.Front(); el != nil; el = dc.order.Front() {
+		if now.Before(el.Value.(*duplicateEntry).expires) {
+			return
+		}
+		dc.remove(el)
+	}
+}
+
+func (dc *DuplicateCache) remove(el *list.Element) {
+	e := dc.order.Remove(el).(*duplicateEntry)
+	delete(dc.entries, e.key)
+	if e.answer == nil {
+		delete(dc.inflight, spanKey{e.conn, e.hbh})
+	}
+}
+
+// Len returns the number of requests in the cache.
+func (dc *DuplicateCache) Len() int {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	return dc.order.Len()
+}
+
+// answerDuplicate answers the request m received on c from the
+// DuplicateCache of the settings, and returns whether it did or whether
+// m must be dropped.
+func (sm *StateMachine) answerDuplicate(c diam.Conn, m *diam.Message) bool {
+	dc := sm.cfg.DuplicateCache
+	if dc == nil || baseCommand(m.Header.CommandCode) {
+		return false
+	}
+	a, dup := dc.received(c, m)
+	if a != nil {
+		if _, err := a.WriteTo(c); err != nil {
+			sm.Error(&diam.ErrorReport{Conn: c, Message: m, Error: err})
+		}
+	}
+	return dup
+}
@@ -0,0 +1,97 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+func TestStateMachine_DuplicateCache(t *testing.T) {
+	cfg := *serverSettings
+	cfg.DuplicateCache = &DuplicateCache{}
+	srvSM := New(&cfg)
+	var handled int32
+	srvSM.HandleFunc("ACR", func(c diam.Conn, m *diam.Message) {
+		atomic.AddInt32(&handled, 1)
+		a := m.Answer(diam.Success)
+		a.NewAVP(avp.OriginHost, avp.Mbit, 0, serverSettings.OriginHost)
+		a.NewAVP(avp.OriginRealm, avp.Mbit, 0, serverSettings.OriginRealm)
+		a.WriteTo(c)
+	})
+	srv := diamtest.NewServer(srvSM, dict.Default)
+	defer srv.Close()
+
+	cli := newPeerClient(New(clientSettings), "")
+	c, err := cli.Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	req := newACR(cli)
+	if _, err = cli.Send(c, req); err != nil {
+		t.Fatal(err)
+	}
+
+	dup := newACR(cli)
+	dup.Header.EndToEndID = req.Header.EndToEndID
+	dup.Header.CommandFlags |= diam.RetransmittedFlag
+	a, err := cli.Send(c, dup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Header.HopByHopID != dup.Header.HopByHopID {
+		t.Fatalf("Unexpected Hop-by-Hop ID. Want %d, have %d", dup.Header.HopByHopID, a.Header.HopByHopID)
+	}
+	if n := atomic.LoadInt32(&handled); n != 1 {
+		t.Fatalf("Duplicate was handled. Want 1 request handled, have %d", n)
+	}
+
+	// Requests without the T flag are never duplicates.
+	again := newACR(cli)
+	again.Header.EndToEndID = req.Header.EndToEndID
+	if _, err = cli.Send(c, again); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&handled); n != 2 {
+		t.Fatalf("Request was not handled. Want 2 requests handled, have %d", n)
+	}
+}
+
+func TestDuplicateCache_Limits(t *testing.T) {
+	cli := newPeerClient(New(clientSettings), "")
+	dc := &DuplicateCache{TTL: 50 * time.Millisecond, MaxSize: 2}
+	var reqs []*diam.Message
+	for i := 0; i < 3; i++ {
+		m := newACR(cli)
+		reqs = append(reqs, m)
+		dc.received(nil, m)
+		dc.answered(nil, m.Answer(diam.Success))
+	}
+	if n := dc.Len(); n != 2 {
+		t.Fatalf("Unexpected size. Want 2, have %d", n)
+	}
+	retransmit := func(m *diam.Message) *diam.Message {
+		m.Header.CommandFlags |= diam.RetransmittedFlag
+		a, _ := dc.received(nil, m)
+		return a
+	}
+	if a := retransmit(reqs[0]); a != nil {
+		t.Fatal("Evicted request was answered")
+	}
+	if a := retransmit(reqs[2]); a == nil {
+		t.Fatal("Duplicate was not answered")
+	}
+	time.Sleep(60 * time.Millisecond)
+	if a := retransmit(reqs[2]); a != nil {
+		t.Fatal("Expired request was answered")
+	}
+}
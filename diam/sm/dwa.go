@@ -7,11 +7,13 @@ package sm
 import (
 	"github.com/omnicate/go-diameter/v4/diam"
 	"github.com/omnicate/go-diameter/v4/diam/sm/smparser"
+	"github.com/omnicate/go-diameter/v4/diam/sm/smpeer"
 )
 
 var dwaACK = struct{}{}
 
-// handleDWA handles Device-Watchdog-Answer messages.
+// handleDWA handles Device-Watchdog-Answer messages, which are passed to
+// the watchdog of the connection and notified on dwac.
 func handleDWA(sm *StateMachine, dwac chan struct{}) diam.HandlerFunc {
 	return func(c diam.Conn, m *diam.Message) {
 		dwa := new(smparser.DWA)
@@ -26,6 +28,13 @@ func handleDWA(sm *StateMachine, dwac chan struct{}) diam.HandlerFunc {
 		if dwa.ResultCode != diam.Success {
 			return
 		}
+		meta, ok := smpeer.FromContext(c.Context())
+		if ok {
+			sm.checkOriginState(c, meta.OriginHost, dwa.OriginStateID)
+		}
+		if w := connWatchdog(c); w != nil && w.dwa() && ok {
+			sm.watchdogs.failback(meta.OriginHost)
+		}
 		select {
 		case dwac <- dwaACK:
 		default:
@@ -7,7 +7,6 @@ package sm
 import (
 	"github.com/omnicate/go-diameter/v4/diam"
 	"github.com/omnicate/go-diameter/v4/diam/avp"
-	"github.com/omnicate/go-diameter/v4/diam/datatype"
 	"github.com/omnicate/go-diameter/v4/diam/sm/smparser"
 )
 
@@ -29,13 +28,11 @@ func handleDWR(sm *StateMachine) diam.HandlerFunc {
 			})
 			return
 		}
+		sm.checkOriginState(c, dwr.OriginHost, dwr.StateID())
 		a := m.Answer(diam.Success)
 		a.NewAVP(avp.OriginHost, avp.Mbit, 0, sm.cfg.OriginHost)
 		a.NewAVP(avp.OriginRealm, avp.Mbit, 0, sm.cfg.OriginRealm)
-		if sm.cfg.OriginStateID != 0 {
-			stateid := datatype.Unsigned32(sm.cfg.OriginStateID)
-			m.NewAVP(avp.OriginStateID, avp.Mbit, 0, stateid)
-		}
+		a.NewAVP(avp.OriginStateID, avp.Mbit, 0, sm.cfg.OriginStateID)
 		_, err = a.WriteTo(c)
 		if err != nil {
 			sm.Error(&diam.ErrorReport{
@@ -73,6 +73,14 @@ func TestHandleDWR(t *testing.T) {
 		if !testResultCode(resp, diam.Success) {
 			t.Fatalf("Unexpected result code for DWA.\n%s", resp)
 		}
+		dwa := new(smparser.DWA)
+		if err := dwa.Parse(resp); err != nil {
+			t.Fatal(err)
+		}
+		if dwa.OriginStateID != uint32(serverSettings.OriginStateID) {
+			t.Fatalf("Unexpected Origin-State-Id. Want %d, have %d",
+				serverSettings.OriginStateID, dwa.OriginStateID)
+		}
 	case err := <-mux.ErrorReports():
 		t.Fatal(err)
 	case <-time.After(time.Second):
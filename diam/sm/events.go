@@ -0,0 +1,160 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/sm/smpeer"
+)
+
+// EventType is the type of the connection events of a state machine.
+// Types are bit flags that may be combined to subscribe to several.
+type EventType uint
+
+// Event types.
+const (
+	PeerUp          EventType = 1 << iota // Peer passed the CER/CEA handshake
+	PeerDown                              // Connection of an open peer closed
+	WatchdogTimeout                       // Peer did not answer DWRs, the connection is closed
+	Reconnecting                          // Client is dialing a lost connection again
+	PeerRestarted                         // Origin-State-Id of the peer increased
+
+	// ConnectionEvents are all the event types.
+	ConnectionEvents = PeerUp | PeerDown | WatchdogTimeout | Reconnecting | PeerRestarted
+)
+
+func (t EventType) String() string {
+	switch t {
+	case PeerUp:
+		return "PeerUp"
+	case PeerDown:
+		return "PeerDown"
+	case WatchdogTimeout:
+		return "WatchdogTimeout"
+	case Reconnecting:
+		return "Reconnecting"
+	case PeerRestarted:
+		return "PeerRestarted"
+	}
+	return fmt.Sprintf("EventType(%d)", uint(t))
+}
+
+// eventBuffer is the size of the channels of subscriptions.
+const eventBuffer = 64
+
+// ErrWatchdogTimeout is the Reason of the PeerDown events of connections
+// closed because their peer did not answer DWRs.
+var ErrWatchdogTimeout = errors.New("watchdog timeout")
+
+// DisconnectError is the Reason of the PeerDown events of connections
+// closed after a DPR from the peer.
+type DisconnectError struct {
+	Cause DisconnectCause
+}
+
+// Error implements the error interface.
+func (e *DisconnectError) Error() string {
+	return fmt.Sprintf("peer disconnected with DPR: %s", e.Cause)
+}
+
+// Event is a connection event of a state machine.
+type Event struct {
+	Type          EventType
+	Conn          diam.Conn
+	Peer          *smpeer.Metadata // Capabilities of the peer from its CER or CEA, if known
+	Reason        error            // Reason of PeerDown: ErrWatchdogTimeout, *DisconnectError or ErrPeerDisconnected
+	Attempt       int              // Reconnect attempt of Reconnecting, from 1
+	OriginStateID uint32           // New Origin-State-Id of PeerRestarted
+}
+
+type subscription struct {
+	types EventType
+	c     chan Event
+}
+
+// eventHub sends the events of a state machine to its subscriptions.
+type eventHub struct {
+	mu   sync.Mutex // guards subs
+	subs []*subscription
+}
+
+func (h *eventHub) subscribe(types EventType) (<-chan Event, func()) {
+	s := &subscription{types: types, c: make(chan Event, eventBuffer)}
+	h.mu.Lock()
+	h.subs = append(h.subs, s)
+	h.mu.Unlock()
+	var once sync.Once
+	return s.c, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			for i, v := range h.subs {
+				if v == s {
+					h.subs = append(h.subs[:i], h.subs[i+1:]...)
+					break
+				}
+			}
+			close(s.c)
+		})
+	}
+}
+
+func (h *eventHub) emit(ev Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, s := range h.subs {
+		if s.types&ev.Type == 0 {
+			continue
+		}
+		select {
+		case s.c <- ev:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel that receives the connection events of
+// the given types, such as ConnectionEvents, and a function that ends
+// the subscription and closes the channel. Events are dropped when the
+// channel is full, which allows applications to drive their own HA
+// logic without blocking the state machine.
+func (sm *StateMachine) Subscribe(types EventType) (<-chan Event, func()) {
+	return sm.events.subscribe(types)
+}
+
+// Subscribe returns a channel that receives the connection events of
+// the Client's Handler. See StateMachine.Subscribe.
+func (cli *Client) Subscribe(types EventType) (<-chan Event, func()) {
+	return cli.Handler.Subscribe(types)
+}
+
+// emitConn emits the event of type t for the connection c.
+func (sm *StateMachine) emitConn(t EventType, c diam.Conn, reason error, attempt int) {
+	meta, _ := smpeer.FromContext(c.Context())
+	sm.events.emit(Event{Type: t, Conn: c, Peer: meta, Reason: reason, Attempt: attempt})
+}
+
+// closeReasonKey is the key of the reason a connection is closed for, in
+// its context.
+type closeReasonKey struct{}
+
+// setCloseReason saves the reason the connection c is closed for.
+func setCloseReason(c diam.Conn, reason error) {
+	c.SetContext(context.WithValue(c.Context(), closeReasonKey{}, reason))
+}
+
+// closeReason returns the reason the connection c was closed for.
+func closeReason(c diam.Conn) error {
+	if err, ok := c.Context().Value(closeReasonKey{}).(error); ok {
+		return err
+	}
+	return ErrPeerDisconnected
+}
@@ -0,0 +1,122 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+// waitEvent returns the next event of events.
+func waitEvent(t *testing.T, events <-chan Event) Event {
+	t.Helper()
+	select {
+	case ev := <-events:
+		return ev
+	case <-time.After(2 * time.Second):
+		t.Fatal("No event")
+	}
+	return Event{}
+}
+
+func TestClient_Subscribe(t *testing.T) {
+	srv := diamtest.NewServer(newACRServer(serverSettings), dict.Default)
+	defer srv.Close()
+
+	cli := newPeerClient(New(clientSettings), "")
+	events, cancel := cli.Subscribe(ConnectionEvents)
+	defer cancel()
+	c, err := cli.Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	ev := waitEvent(t, events)
+	if ev.Type != PeerUp || ev.Conn != c {
+		t.Fatalf("Unexpected event: %+v", ev)
+	}
+	if ev.Peer == nil || ev.Peer.OriginHost != serverSettings.OriginHost {
+		t.Fatalf("Unexpected peer: %+v", ev.Peer)
+	}
+	ctx, cancelCtx := context.WithTimeout(context.Background(), time.Second)
+	defer cancelCtx()
+	if err = srv.Config.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	ev = waitEvent(t, events)
+	if ev.Type != PeerDown || ev.Conn != c {
+		t.Fatalf("Unexpected event: %+v", ev)
+	}
+	dpr, ok := ev.Reason.(*DisconnectError)
+	if !ok || dpr.Cause != DisconnectRebooting {
+		t.Fatalf("Unexpected reason: %v", ev.Reason)
+	}
+}
+
+func TestStateMachine_Subscribe_Filter(t *testing.T) {
+	srvSM := newACRServer(serverSettings)
+	srv := diamtest.NewServer(srvSM, dict.Default)
+	defer srv.Close()
+
+	down, cancel := srvSM.Subscribe(PeerDown)
+	defer cancel()
+	cli := newPeerClient(New(clientSettings), "")
+	c, err := cli.Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Close()
+	ev := waitEvent(t, down)
+	if ev.Type != PeerDown || ev.Reason != ErrPeerDisconnected {
+		t.Fatalf("Unexpected event: %+v", ev)
+	}
+	if ev.Peer == nil || ev.Peer.OriginHost != clientSettings.OriginHost {
+		t.Fatalf("Unexpected peer: %+v", ev.Peer)
+	}
+	cancel()
+	if _, ok := <-down; ok {
+		t.Fatal("Channel not closed after cancel")
+	}
+}
+
+func TestStateMachine_PeerRestarted(t *testing.T) {
+	srvSM := newACRServer(serverSettings)
+	srv := diamtest.NewServer(srvSM, dict.Default)
+	defer srv.Close()
+
+	restarted, cancel := srvSM.Subscribe(PeerRestarted)
+	defer cancel()
+	for _, osid := range []datatype.Unsigned32{100, 100, 101} {
+		settings := *clientSettings
+		settings.OriginStateID = osid
+		cli := newPeerClient(New(&settings), "")
+		c, err := cli.Dial(srv.Addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.Close()
+	}
+	ev := waitEvent(t, restarted)
+	if ev.OriginStateID != 101 {
+		t.Fatalf("Unexpected event: %+v", ev)
+	}
+	if ev.Peer == nil || ev.Peer.OriginHost != clientSettings.OriginHost || ev.Peer.OriginStateID != 101 {
+		t.Fatalf("Unexpected peer: %+v", ev.Peer)
+	}
+	if osid, ok := srvSM.PeerOriginStateID(clientSettings.OriginHost); !ok || osid != 101 {
+		t.Fatalf("Unexpected Origin-State-Id: %d", osid)
+	}
+	select {
+	case ev = <-restarted:
+		t.Fatalf("Unexpected event: %+v", ev)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
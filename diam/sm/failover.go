@@ -0,0 +1,67 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"github.com/omnicate/go-diameter/v4/diam"
+)
+
+// FailoverPolicy returns the connection the request m must be sent again
+// on when the connection c it was sent on fails before the answer is
+// received, or nil to give up. See RFC 6733 section 5.5.4 for details.
+//
+// Client.FailoverRoutes and Pool.Failover are failover policies.
+type FailoverPolicy func(c diam.Conn, m *diam.Message) diam.Conn
+
+// FailoverRoutes is a FailoverPolicy that routes the request m to an
+// alternate peer, as Send does for requests sent without a connection.
+func (cli *Client) FailoverRoutes(c diam.Conn, m *diam.Message) diam.Conn {
+	alt, err := cli.nextHop(m, c)
+	if err != nil {
+		return nil
+	}
+	return alt
+}
+
+// Failover is a FailoverPolicy that sends the request m on another
+// connection of the pool. The failed connection c is removed from it.
+func (p *Pool) Failover(c diam.Conn, m *diam.Message) diam.Conn {
+	p.mu.Lock()
+	for i, pc := range p.conns {
+		if pc.Conn == c {
+			p.conns = append(p.conns[:i], p.conns[i+1:]...)
+			break
+		}
+	}
+	p.mu.Unlock()
+	if pc := p.pick(); pc != nil {
+		return pc.Conn
+	}
+	return nil
+}
+
+// failover returns the connection the request m is sent again on when c
+// fails, or nil. Connections that already failed are never returned.
+func (cli *Client) failover(c diam.Conn, m *diam.Message, failed []diam.Conn) diam.Conn {
+	if cli.Failover == nil {
+		return nil
+	}
+	alt := cli.Failover(c, m)
+	for _, fc := range failed {
+		if alt == fc {
+			return nil
+		}
+	}
+	return alt
+}
+
+// closeNotify returns the channel closed when c is closed, or nil if c
+// does not implement diam.CloseNotifier.
+func closeNotify(c diam.Conn) <-chan struct{} {
+	if cn, ok := c.(diam.CloseNotifier); ok {
+		return cn.CloseNotify()
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+// newFailingServer returns a server that disconnects on every ACR.
+func newFailingServer() *diamtest.Server {
+	sm := New(serverSettings)
+	sm.HandleFunc("ACR", func(c diam.Conn, m *diam.Message) {
+		c.Close()
+	})
+	return diamtest.NewServer(sm, dict.Default)
+}
+
+func TestClient_Send_Failover(t *testing.T) {
+	srv1 := newFailingServer()
+	defer srv1.Close()
+	received := make(chan *diam.Message, 1)
+	srvSM := newACRServer(serverSettings2)
+	srvSM.HandleRetransmission(func(c diam.Conn, m *diam.Message) bool {
+		received <- m
+		return true
+	})
+	srv2 := diamtest.NewServer(srvSM, dict.Default)
+	defer srv2.Close()
+
+	pool := NewPool(newPeerClient(New(clientSettings), ""), RoundRobin)
+	defer pool.Close()
+	pool.Client.Failover = pool.Failover
+	pool.Client.RequestTimeout = time.Second
+	if err := pool.Dial("tcp", srv1.Addr, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.Dial("tcp", srv2.Addr, 1); err != nil {
+		t.Fatal(err)
+	}
+	req := newACR(pool.Client)
+	a, err := pool.Send(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oh, err := a.FindAVP(avp.OriginHost, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if oh.Data != serverSettings2.OriginHost {
+		t.Fatalf("Unexpected Origin-Host. Want %s, have %s", serverSettings2.OriginHost, oh.Data)
+	}
+	select {
+	case m := <-received:
+		if m.Header.EndToEndID != req.Header.EndToEndID {
+			t.Fatalf("Unexpected End-to-End ID. Want %d, have %d", req.Header.EndToEndID, m.Header.EndToEndID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Request was not failed over with the T flag")
+	}
+	if pool.Len() != 1 {
+		t.Fatalf("Failed connection was not removed. Want 1, have %d", pool.Len())
+	}
+}
+
+func TestClient_Send_Disconnect(t *testing.T) {
+	srv := newFailingServer()
+	defer srv.Close()
+
+	cli := newPeerClient(New(clientSettings), "")
+	cli.RequestTimeout = time.Second
+	c, err := cli.Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if _, err = cli.Send(c, newACR(cli)); err != ErrPeerDisconnected {
+		t.Fatalf("Unexpected error. Want %v, have %v", ErrPeerDisconnected, err)
+	}
+}
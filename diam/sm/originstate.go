@@ -0,0 +1,61 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"sync"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/sm/smpeer"
+)
+
+// bootStateID is the Origin-State-Id of the state machines whose Settings
+// do not set one: the time the process started.
+var bootStateID = datatype.Unsigned32(time.Now().Unix())
+
+// originStates holds the last Origin-State-Id of the peers, which
+// increases when they restart. See RFC 6733 section 8.16.
+type originStates struct {
+	mu sync.Mutex // guards m
+	m  map[datatype.DiameterIdentity]uint32
+}
+
+// update saves the Origin-State-Id osid of the peer host, and reports
+// whether it increased since the last one.
+func (s *originStates) update(host datatype.DiameterIdentity, osid uint32) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.m == nil {
+		s.m = make(map[datatype.DiameterIdentity]uint32)
+	}
+	last, ok := s.m[host]
+	if ok && osid <= last {
+		return false
+	}
+	s.m[host] = osid
+	return ok
+}
+
+// PeerOriginStateID returns the last Origin-State-Id received from the
+// peer host in a CER, CEA, DWR or DWA.
+func (sm *StateMachine) PeerOriginStateID(host datatype.DiameterIdentity) (uint32, bool) {
+	sm.originStates.mu.Lock()
+	defer sm.originStates.mu.Unlock()
+	osid, ok := sm.originStates.m[host]
+	return osid, ok
+}
+
+// checkOriginState saves the Origin-State-Id osid received from the peer
+// host on the connection c, and emits a PeerRestarted event when it
+// increased. Zero means the peer did not send one.
+func (sm *StateMachine) checkOriginState(c diam.Conn, host datatype.DiameterIdentity, osid uint32) {
+	if osid == 0 || !sm.originStates.update(host, osid) {
+		return
+	}
+	meta, _ := smpeer.FromContext(c.Context())
+	sm.events.emit(Event{Type: PeerRestarted, Conn: c, Peer: meta, OriginStateID: osid})
+}
@@ -0,0 +1,118 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/sm/smpeer"
+)
+
+// PeerConfig overrides the Settings of the state machine, and the
+// capabilities of the Client, for the peer it is keyed by in
+// Settings.Peers. Fields are not overridden when zero.
+//
+// Clients only know the peer before the capabilities exchange when
+// PeerIdentity is set, and only apply the overrides of the CER then.
+type PeerConfig struct {
+	// HostIPAddresses are advertised in the CER, CEA or CUR sent to the
+	// peer instead of Settings.HostIPAddresses.
+	HostIPAddresses []datatype.Address
+
+	// ProductName is advertised in the CER, CEA or CUR sent to the peer
+	// instead of Settings.ProductName.
+	ProductName datatype.UTF8String
+
+	// WatchdogInterval is the watchdog timer Tw of the connections of
+	// the Client to the peer, instead of Client.WatchdogInterval.
+	WatchdogInterval time.Duration
+
+	// Applications are the IDs of the applications advertised to the
+	// peer, among those of the dictionary for servers and those of the
+	// Client for clients. The Relay and Capabilities Update applications
+	// are always advertised when enabled.
+	Applications []uint32
+}
+
+// noPeerConfig is the PeerConfig of the peers without overrides.
+var noPeerConfig = &PeerConfig{}
+
+// advertises returns whether the application id is advertised to the
+// peer.
+func (p *PeerConfig) advertises(id uint32) bool {
+	if len(p.Applications) == 0 || id == diam.CAPABILITIES_UPDATE_APP_ID || id == AnyApplication {
+		return true
+	}
+	for _, app := range p.Applications {
+		if app == id {
+			return true
+		}
+	}
+	return false
+}
+
+// peerConfig returns the PeerConfig of host from the settings.
+func (sm *StateMachine) peerConfig(host datatype.DiameterIdentity) *PeerConfig {
+	if p := sm.cfg.Peers[host]; p != nil {
+		return p
+	}
+	return noPeerConfig
+}
+
+// hostAddresses returns the Host-IP-Address AVPs to advertise to the
+// peer host on the connection c.
+func (sm *StateMachine) hostAddresses(c diam.Conn, host datatype.DiameterIdentity) ([]datatype.Address, error) {
+	if p := sm.peerConfig(host); len(p.HostIPAddresses) > 0 {
+		return p.HostIPAddresses, nil
+	}
+	if len(sm.cfg.HostIPAddresses) > 0 {
+		return sm.cfg.HostIPAddresses, nil
+	}
+	return getLocalAddresses(c)
+}
+
+// productName returns the Product-Name to advertise to the peer host.
+func (sm *StateMachine) productName(host datatype.DiameterIdentity) datatype.UTF8String {
+	if p := sm.peerConfig(host); len(p.ProductName) > 0 {
+		return p.ProductName
+	}
+	return sm.cfg.ProductName
+}
+
+// watchdogInterval returns the watchdog timer Tw of the connection c.
+func (cli *Client) watchdogInterval(c diam.Conn) time.Duration {
+	host := cli.PeerIdentity
+	if meta, ok := smpeer.FromContext(c.Context()); ok {
+		host = meta.OriginHost
+	}
+	if p := cli.Handler.peerConfig(host); p.WatchdogInterval > 0 {
+		return p.WatchdogInterval
+	}
+	return cli.WatchdogInterval
+}
+
+// appID returns the application ID of the Auth-Application-Id,
+// Acct-Application-Id or Vendor-Specific-Application-Id AVP a.
+func appID(a *diam.AVP) (uint32, bool) {
+	switch a.Code {
+	case avp.AuthApplicationID, avp.AcctApplicationID:
+		id, ok := a.Data.(datatype.Unsigned32)
+		return uint32(id), ok
+	case avp.VendorSpecificApplicationID:
+		g, ok := a.Data.(*diam.GroupedAVP)
+		if !ok {
+			return 0, false
+		}
+		for _, ga := range g.AVP {
+			if id, ok := appID(ga); ok {
+				return id, true
+			}
+		}
+	}
+	return 0, false
+}
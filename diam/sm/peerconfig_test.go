@@ -0,0 +1,74 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+	"github.com/omnicate/go-diameter/v4/diam/sm/smpeer"
+)
+
+func TestSettings_Peers(t *testing.T) {
+	srvCfg := *serverSettings
+	srvCfg.Peers = map[datatype.DiameterIdentity]*PeerConfig{
+		clientSettings.OriginHost: {Applications: []uint32{4}},
+	}
+	srvSM := New(&srvCfg)
+	cerc := make(chan *diam.Message, 1)
+	srv := diamtest.NewServer(diam.HandlerFunc(func(c diam.Conn, m *diam.Message) {
+		if m.Header.CommandCode == diam.CapabilitiesExchange {
+			cerc <- m
+		}
+		srvSM.ServeDIAM(c, m)
+	}), dict.Default)
+	defer srv.Close()
+
+	cliCfg := *clientSettings
+	cliCfg.Peers = map[datatype.DiameterIdentity]*PeerConfig{
+		serverSettings.OriginHost: {
+			HostIPAddresses:  []datatype.Address{datatype.Address(net.ParseIP("10.0.0.1"))},
+			ProductName:      "per-peer",
+			WatchdogInterval: time.Minute,
+			Applications:     []uint32{4},
+		},
+	}
+	cli := newPeerClient(New(&cliCfg), serverSettings.OriginHost)
+	cli.AuthApplicationID = []*diam.AVP{
+		diam.NewAVP(avp.AuthApplicationID, avp.Mbit, 0, datatype.Unsigned32(4)),
+	}
+	c, err := cli.Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	cer := <-cerc
+	if a, err := cer.FindAVP(avp.ProductName, 0); err != nil || a.Data.(datatype.UTF8String) != "per-peer" {
+		t.Fatalf("Unexpected Product-Name: %v", a)
+	}
+	if a, err := cer.FindAVP(avp.HostIPAddress, 0); err != nil || !net.IP(a.Data.(datatype.Address)).Equal(net.ParseIP("10.0.0.1")) {
+		t.Fatalf("Unexpected Host-IP-Address: %v", a)
+	}
+	if a, err := cer.FindAVP(avp.AcctApplicationID, 0); err == nil {
+		t.Fatalf("Unexpected Acct-Application-Id: %v", a)
+	}
+	meta, ok := smpeer.FromContext(c.Context())
+	if !ok {
+		t.Fatal("No peer metadata")
+	}
+	if meta.Supports(3) || !meta.Supports(4) {
+		t.Fatalf("Unexpected applications advertised in CEA: %v", meta.Applications)
+	}
+	if d := cli.watchdogInterval(c); d != time.Minute {
+		t.Fatalf("Unexpected watchdog interval. Want 1m, have %s", d)
+	}
+}
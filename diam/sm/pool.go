@@ -0,0 +1,152 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+)
+
+// BalancePolicy is the policy used by a Pool to select the connection
+// each request is sent on.
+type BalancePolicy int
+
+// Balance policies.
+const (
+	RoundRobin       BalancePolicy = iota // Each connection in turn
+	LeastOutstanding                      // Connection with fewest requests waiting for an answer
+)
+
+var (
+	// ErrPoolEmpty is returned by Pool.Send when the pool has no open
+	// connections.
+	ErrPoolEmpty = errors.New("no connections in pool")
+)
+
+// poolConn is a connection of a Pool.
+type poolConn struct {
+	diam.Conn
+	outstanding int32 // requests waiting for an answer, atomic
+}
+
+// A Pool maintains connections to one or more servers, established by
+// its Client, and load balances the requests sent by Send across them.
+//
+// Connections are removed from the pool when closed, which includes
+// those closed by the Client's watchdog when the peer stops answering.
+type Pool struct {
+	Client *Client
+	Policy BalancePolicy
+
+	mu    sync.Mutex // guards conns and next
+	conns []*poolConn
+	next  int
+}
+
+// NewPool creates and initializes a Pool that uses cli to dial and to
+// send requests, and balances them according to policy.
+func NewPool(cli *Client, policy BalancePolicy) *Pool {
+	return &Pool{Client: cli, Policy: policy}
+}
+
+// Dial opens n connections to the network address addr and adds them
+// to the pool. On error, the connections opened so far are kept.
+func (p *Pool) Dial(network, addr string, n int) error {
+	for i := 0; i < n; i++ {
+		c, err := p.Client.DialNetwork(network, addr)
+		if err != nil {
+			return err
+		}
+		p.Add(c)
+	}
+	return nil
+}
+
+// Add adds the open connection c to the pool. It's removed when closed.
+func (p *Pool) Add(c diam.Conn) {
+	pc := &poolConn{Conn: c}
+	p.mu.Lock()
+	p.conns = append(p.conns, pc)
+	p.mu.Unlock()
+	cn, ok := c.(diam.CloseNotifier)
+	if !ok {
+		return
+	}
+	disconnect := cn.CloseNotify()
+	go func() {
+		<-disconnect
+		p.remove(pc)
+	}()
+}
+
+func (p *Pool) remove(pc *poolConn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, v := range p.conns {
+		if v == pc {
+			p.conns = append(p.conns[:i], p.conns[i+1:]...)
+			return
+		}
+	}
+}
+
+// Len returns the number of open connections in the pool.
+func (p *Pool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.conns)
+}
+
+// Send sends the request m on the connection selected by the pool's
+// policy and waits for its answer. See Client.Send for details.
+func (p *Pool) Send(m *diam.Message) (*diam.Message, error) {
+	pc := p.pick()
+	if pc == nil {
+		return nil, ErrPoolEmpty
+	}
+	atomic.AddInt32(&pc.outstanding, 1)
+	defer atomic.AddInt32(&pc.outstanding, -1)
+	return p.Client.Send(pc.Conn, m)
+}
+
+// pick returns the connection the next request is sent on.
+func (p *Pool) pick() *poolConn {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.conns) == 0 {
+		return nil
+	}
+	switch p.Policy {
+	case LeastOutstanding:
+		// Start from the round robin position to spread ties.
+		best := p.conns[p.next%len(p.conns)]
+		for i := 1; i < len(p.conns); i++ {
+			pc := p.conns[(p.next+i)%len(p.conns)]
+			if atomic.LoadInt32(&pc.outstanding) < atomic.LoadInt32(&best.outstanding) {
+				best = pc
+			}
+		}
+		p.next++
+		return best
+	default:
+		pc := p.conns[p.next%len(p.conns)]
+		p.next++
+		return pc
+	}
+}
+
+// Close closes all connections of the pool.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	conns := p.conns
+	p.conns = nil
+	p.mu.Unlock()
+	for _, pc := range conns {
+		pc.Close()
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+func TestPool_RoundRobin(t *testing.T) {
+	srv1 := diamtest.NewServer(newACRServer(serverSettings), dict.Default)
+	defer srv1.Close()
+	srv2 := diamtest.NewServer(newACRServer(serverSettings2), dict.Default)
+	defer srv2.Close()
+
+	pool := NewPool(newPeerClient(New(clientSettings), ""), RoundRobin)
+	defer pool.Close()
+	if err := pool.Dial("tcp", srv1.Addr, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.Dial("tcp", srv2.Addr, 1); err != nil {
+		t.Fatal(err)
+	}
+	count := make(map[datatype.DiameterIdentity]int)
+	for i := 0; i < 4; i++ {
+		a, err := pool.Send(newACR(pool.Client))
+		if err != nil {
+			t.Fatal(err)
+		}
+		oh, err := a.FindAVP(avp.OriginHost, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		count[oh.Data.(datatype.DiameterIdentity)]++
+	}
+	if count[serverSettings.OriginHost] != 2 || count[serverSettings2.OriginHost] != 2 {
+		t.Fatalf("Requests were not balanced: %v", count)
+	}
+}
+
+func TestPool_LeastOutstanding(t *testing.T) {
+	pool := NewPool(nil, LeastOutstanding)
+	pool.conns = []*poolConn{{outstanding: 2}, {outstanding: 1}, {outstanding: 3}}
+	for i := 0; i < 3; i++ {
+		if pc := pool.pick(); pc != pool.conns[1] {
+			t.Fatalf("Unexpected connection picked with %d outstanding requests", pc.outstanding)
+		}
+	}
+}
+
+func TestPool_RemoveClosed(t *testing.T) {
+	srv := diamtest.NewServer(newACRServer(serverSettings), dict.Default)
+	defer srv.Close()
+
+	pool := NewPool(newPeerClient(New(clientSettings), ""), RoundRobin)
+	defer pool.Close()
+	if err := pool.Dial("tcp", srv.Addr, 2); err != nil {
+		t.Fatal(err)
+	}
+	if pool.Len() != 2 {
+		t.Fatalf("Unexpected number of connections. Want 2, have %d", pool.Len())
+	}
+	pool.pick().Close()
+	deadline := time.Now().Add(time.Second)
+	for pool.Len() != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("Closed connection was not removed from the pool")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if _, err := pool.Send(newACR(pool.Client)); err != nil {
+		t.Fatal(err)
+	}
+}
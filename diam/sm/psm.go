@@ -0,0 +1,419 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"bytes"
+	"strconv"
+	"sync"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/sm/smparser"
+)
+
+// PeerState is a state of the peer state machine.
+// See RFC 6733 section 5.6 for details.
+type PeerState int
+
+// Peer states.
+const (
+	Closed           PeerState = iota // No connection to the peer
+	WaitConnAck                       // Connecting to the peer
+	WaitICEA                          // CER sent, waiting for CEA
+	WaitConnAckElect                  // Peer's CER received while connecting to it
+	WaitReturns                       // Election lost, waiting for CEA
+	ROpen                             // Open, peer is the initiator
+	IOpen                             // Open, we are the initiator
+	Closing                           // Disconnecting from the peer
+)
+
+var peerStates = [...]string{
+	"Closed",
+	"Wait-Conn-Ack",
+	"Wait-I-CEA",
+	"Wait-Conn-Ack/Elect",
+	"Wait-Returns",
+	"R-Open",
+	"I-Open",
+	"Closing",
+}
+
+func (s PeerState) String() string {
+	if s >= 0 && int(s) < len(peerStates) {
+		return peerStates[s]
+	}
+	return "PeerState(" + strconv.Itoa(int(s)) + ")"
+}
+
+// Open reports whether the peer passed the CER/CEA handshake.
+func (s PeerState) Open() bool {
+	return s == ROpen || s == IOpen
+}
+
+// peer holds the state of a single peer identity.
+type peer struct {
+	state PeerState
+	iconn diam.Conn // initiator connection
+	rconn diam.Conn // responder connection
+
+	// CER received on rconn, answered when the election is resolved.
+	rcer  *diam.Message
+	rmeta *smparser.CER
+
+	// Additional open connections, unless SingleConnection is set.
+	extra []peerConn
+}
+
+type peerConn struct {
+	diam.Conn
+	initiator bool
+}
+
+// promote makes the next additional connection the open one.
+func (p *peer) promote() {
+	next := p.extra[0]
+	p.extra = p.extra[1:]
+	if next.initiator {
+		p.state, p.iconn, p.rconn = IOpen, next.Conn, nil
+	} else {
+		p.state, p.iconn, p.rconn = ROpen, nil, next.Conn
+	}
+}
+
+// peerTable implements the peer state machine of RFC 6733 section 5.6
+// for each peer identity known to the StateMachine.
+//
+// The transport events and actions are those of the RFC, with Client
+// dials being the initiator (I) connections and accepted connections
+// being the responder (R) ones. Actions that do I/O are performed after
+// the table's lock is released.
+type peerTable struct {
+	sm    *StateMachine
+	mu    sync.Mutex // guards peers and closing
+	peers map[datatype.DiameterIdentity]*peer
+
+	// Connections being disconnected with DPR/DPA.
+	closing map[diam.Conn]struct{}
+}
+
+func newPeerTable(sm *StateMachine) *peerTable {
+	return &peerTable{
+		sm:      sm,
+		peers:   make(map[datatype.DiameterIdentity]*peer),
+		closing: make(map[diam.Conn]struct{}),
+	}
+}
+
+// single reports whether only one connection per peer is allowed.
+func (t *peerTable) single() bool {
+	return t.sm.cfg.SingleConnection
+}
+
+// state returns the state of the peer and its connection, if open.
+func (t *peerTable) state(host datatype.DiameterIdentity) (PeerState, diam.Conn) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p, ok := t.peers[host]
+	switch {
+	case !ok:
+		return Closed, nil
+	case p.state == ROpen:
+		return p.state, p.rconn
+	case p.state == IOpen:
+		return p.state, p.iconn
+	}
+	return p.state, nil
+}
+
+// start handles the Start event, issued before connecting to host.
+// It reports whether the connection is tracked by the state machine.
+func (t *peerTable) start(host datatype.DiameterIdentity) (bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.peers[host]; ok {
+		if t.single() {
+			return false, ErrPeerConnected
+		}
+		return false, nil
+	}
+	t.peers[host] = &peer{state: WaitConnAck}
+	return true, nil
+}
+
+// connNack handles the I-Rcv-Conn-Nack event.
+func (t *peerTable) connNack(host datatype.DiameterIdentity) {
+	t.mu.Lock()
+	p, ok := t.peers[host]
+	if !ok {
+		t.mu.Unlock()
+		return
+	}
+	switch p.state {
+	case WaitConnAck:
+		delete(t.peers, host)
+		t.mu.Unlock()
+	case WaitConnAckElect:
+		// R-Snd-CEA
+		p.state = ROpen
+		t.mu.Unlock()
+		t.sm.acceptCER(p.rconn, p.rcer, p.rmeta)
+	default:
+		t.mu.Unlock()
+	}
+}
+
+// connAck handles the I-Rcv-Conn-Ack event. It returns ErrPeerConnected
+// if c had to be closed as the result of an election.
+func (t *peerTable) connAck(host datatype.DiameterIdentity, c diam.Conn) error {
+	t.mu.Lock()
+	p, ok := t.peers[host]
+	if !ok {
+		t.mu.Unlock()
+		return nil
+	}
+	switch p.state {
+	case WaitConnAck:
+		p.state, p.iconn = WaitICEA, c
+		t.mu.Unlock()
+		t.watch(c)
+	case WaitConnAckElect:
+		if t.elect(host) {
+			// I-Disc, R-Snd-CEA
+			p.state = ROpen
+			t.mu.Unlock()
+			c.Close()
+			t.sm.acceptCER(p.rconn, p.rcer, p.rmeta)
+			return ErrPeerConnected
+		}
+		p.state, p.iconn = WaitReturns, c
+		t.mu.Unlock()
+		t.watch(c)
+	default:
+		t.mu.Unlock()
+	}
+	return nil
+}
+
+// rConnCER handles the R-Conn-CER event for the connection c that
+// received the valid CER m. It reports whether the CEA must be sent
+// right away.
+func (t *peerTable) rConnCER(c diam.Conn, m *diam.Message, cer *smparser.CER) bool {
+	host := cer.OriginHost
+	t.mu.Lock()
+	p, ok := t.peers[host]
+	if !ok {
+		t.peers[host] = &peer{state: ROpen, rconn: c}
+		t.mu.Unlock()
+		t.watch(c)
+		return true
+	}
+	if p.rconn == c || p.iconn == c {
+		// Retransmission while the election is pending,
+		// or CER received on the initiator connection.
+		t.mu.Unlock()
+		return false
+	}
+	if !t.single() {
+		p.extra = append(p.extra, peerConn{Conn: c})
+		t.mu.Unlock()
+		t.watch(c)
+		return true
+	}
+	switch p.state {
+	case WaitConnAck:
+		p.state, p.rconn, p.rcer, p.rmeta = WaitConnAckElect, c, m, cer
+		t.mu.Unlock()
+		t.watch(c)
+		return false
+	case WaitICEA:
+		if t.elect(host) {
+			// I-Disc, R-Snd-CEA
+			iconn := p.iconn
+			p.state, p.iconn, p.rconn = ROpen, nil, c
+			t.mu.Unlock()
+			iconn.Close()
+			t.watch(c)
+			return true
+		}
+		p.state, p.rconn, p.rcer, p.rmeta = WaitReturns, c, m, cer
+		t.mu.Unlock()
+		t.watch(c)
+		return false
+	}
+	// R-Reject
+	t.mu.Unlock()
+	t.sm.Error(&diam.ErrorReport{Conn: c, Message: m, Error: ErrPeerConnected})
+	c.Close()
+	return false
+}
+
+// iRcvCEA handles the I-Rcv-CEA event for the connection c that
+// received a valid CEA from host.
+func (t *peerTable) iRcvCEA(c diam.Conn, host datatype.DiameterIdentity) error {
+	t.mu.Lock()
+	p, ok := t.find(c)
+	if !ok {
+		if p, ok = t.peers[host]; !ok {
+			t.peers[host] = &peer{state: IOpen, iconn: c}
+			t.mu.Unlock()
+			t.watch(c)
+			return nil
+		}
+		// Connected to a known peer without going through Start.
+		if t.single() {
+			t.mu.Unlock()
+			return ErrPeerConnected
+		}
+		p.extra = append(p.extra, peerConn{Conn: c, initiator: true})
+		t.mu.Unlock()
+		t.watch(c)
+		return nil
+	}
+	switch p.state {
+	case WaitICEA:
+		p.state = IOpen
+		t.mu.Unlock()
+	case WaitReturns:
+		// R-Disc
+		rconn := p.rconn
+		p.state, p.rconn, p.rcer, p.rmeta = IOpen, nil, nil, nil
+		t.mu.Unlock()
+		rconn.Close()
+	default:
+		t.mu.Unlock()
+	}
+	return nil
+}
+
+// peerDisc handles the I-Peer-Disc and R-Peer-Disc events.
+func (t *peerTable) peerDisc(c diam.Conn) {
+	t.mu.Lock()
+	delete(t.closing, c)
+	p, ok := t.find(c)
+	if !ok {
+		t.mu.Unlock()
+		return
+	}
+	for i, ec := range p.extra {
+		if ec.Conn == c {
+			p.extra = append(p.extra[:i], p.extra[i+1:]...)
+			t.mu.Unlock()
+			return
+		}
+	}
+	var host datatype.DiameterIdentity
+	for h, v := range t.peers {
+		if v == p {
+			host = h
+		}
+	}
+	switch {
+	case p.iconn == c && p.state == WaitReturns:
+		// R-Snd-CEA
+		p.state, p.iconn = ROpen, nil
+		t.mu.Unlock()
+		t.sm.acceptCER(p.rconn, p.rcer, p.rmeta)
+		return
+	case p.rconn == c && p.state == WaitConnAckElect:
+		p.state, p.rconn, p.rcer, p.rmeta = WaitConnAck, nil, nil, nil
+	case p.rconn == c && p.state == WaitReturns:
+		p.state, p.rconn, p.rcer, p.rmeta = WaitICEA, nil, nil, nil
+	case len(p.extra) > 0:
+		p.promote()
+	default:
+		delete(t.peers, host)
+	}
+	t.mu.Unlock()
+}
+
+// disconnect handles the disconnection of c with DPR/DPA, after which c
+// is no longer the connection of its peer. The peer is Closing if c was
+// its only open connection.
+func (t *peerTable) disconnect(c diam.Conn) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p, ok := t.find(c)
+	if !ok {
+		return
+	}
+	t.closing[c] = struct{}{}
+	for i, ec := range p.extra {
+		if ec.Conn == c {
+			p.extra = append(p.extra[:i], p.extra[i+1:]...)
+			return
+		}
+	}
+	if !p.state.Open() {
+		return
+	}
+	if len(p.extra) > 0 {
+		p.promote()
+	} else {
+		p.state = Closing
+	}
+}
+
+// disconnecting reports whether c is being disconnected with DPR/DPA.
+func (t *peerTable) disconnecting(c diam.Conn) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, ok := t.closing[c]
+	return ok
+}
+
+// conns returns the open connections of all the peers.
+func (t *peerTable) conns() []diam.Conn {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var conns []diam.Conn
+	for _, p := range t.peers {
+		switch p.state {
+		case ROpen:
+			conns = append(conns, p.rconn)
+		case IOpen:
+			conns = append(conns, p.iconn)
+		}
+		for _, ec := range p.extra {
+			conns = append(conns, ec.Conn)
+		}
+	}
+	return conns
+}
+
+// find returns the peer that owns the connection c.
+func (t *peerTable) find(c diam.Conn) (*peer, bool) {
+	for _, p := range t.peers {
+		if p.iconn == c || p.rconn == c {
+			return p, true
+		}
+		for _, ec := range p.extra {
+			if ec.Conn == c {
+				return p, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// elect reports whether the local peer wins the election against host.
+// See RFC 6733 section 5.6.4 for details.
+func (t *peerTable) elect(host datatype.DiameterIdentity) bool {
+	return bytes.Compare([]byte(t.sm.cfg.OriginHost), []byte(host)) > 0
+}
+
+// watch issues a Peer-Disc event when the connection c is closed.
+func (t *peerTable) watch(c diam.Conn) {
+	cn, ok := c.(diam.CloseNotifier)
+	if !ok {
+		return
+	}
+	// CloseNotify must be called before the connection's next read.
+	disconnect := cn.CloseNotify()
+	go func() {
+		<-disconnect
+		t.peerDisc(c)
+	}()
+}
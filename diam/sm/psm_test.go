@@ -0,0 +1,196 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+func newPeerClient(sm *StateMachine, peer datatype.DiameterIdentity) *Client {
+	return &Client{
+		Handler:      sm,
+		PeerIdentity: peer,
+		AcctApplicationID: []*diam.AVP{
+			diam.NewAVP(avp.AcctApplicationID, avp.Mbit, 0, datatype.Unsigned32(3)),
+		},
+	}
+}
+
+// waitPeerState waits for the peer to reach the given state.
+func waitPeerState(sm *StateMachine, host datatype.DiameterIdentity, state PeerState) error {
+	deadline := time.Now().Add(time.Second)
+	for sm.PeerState(host) != state {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("Unexpected state of peer %s. Want %s, have %s", host, state, sm.PeerState(host))
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return nil
+}
+
+func TestPeerState_Open(t *testing.T) {
+	srvSM := New(serverSettings)
+	srv := diamtest.NewServer(srvSM, dict.Default)
+	defer srv.Close()
+	cliSM := New(clientSettings)
+	c, err := newPeerClient(cliSM, serverSettings.OriginHost).Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := waitPeerState(cliSM, serverSettings.OriginHost, IOpen); err != nil {
+		t.Fatal(err)
+	}
+	if err := waitPeerState(srvSM, clientSettings.OriginHost, ROpen); err != nil {
+		t.Fatal(err)
+	}
+	if cliSM.PeerConn(serverSettings.OriginHost) != c {
+		t.Fatal("Unexpected peer connection")
+	}
+	c.Close()
+	if err := waitPeerState(cliSM, serverSettings.OriginHost, Closed); err != nil {
+		t.Fatal(err)
+	}
+	if err := waitPeerState(srvSM, clientSettings.OriginHost, Closed); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPeerState_SingleConnection(t *testing.T) {
+	settings := *serverSettings
+	settings.SingleConnection = true
+	srvSM := New(&settings)
+	srv := diamtest.NewServer(srvSM, dict.Default)
+	defer srv.Close()
+	c, err := newPeerClient(New(clientSettings), "").Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	// R-Reject
+	if _, err = newPeerClient(New(clientSettings), "").Dial(srv.Addr); err == nil {
+		t.Fatal("Second connection from the same peer was accepted")
+	}
+	select {
+	case err := <-srvSM.ErrorReports():
+		if err.Error != ErrPeerConnected {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for error")
+	}
+	if srvSM.PeerConn(clientSettings.OriginHost) == nil {
+		t.Fatal("First connection was not kept")
+	}
+}
+
+// simultaneousPeer runs a raw peer that connects back to the state
+// machine's server when it receives its CER, sending its own CER on the
+// new connection. If answer is set, the original CER is answered once
+// the state machine has lost the election.
+func simultaneousPeer(t *testing.T, sm *StateMachine, addr string, answer bool) (*diamtest.Server, chan net.Conn) {
+	rc := make(chan net.Conn, 1)
+	mux := diam.NewServeMux()
+	mux.HandleFunc("CER", func(c diam.Conn, m *diam.Message) {
+		r, err := net.Dial("tcp", addr)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		rc <- r
+		cer := diam.NewRequest(diam.CapabilitiesExchange, 0, dict.Default)
+		cer.NewAVP(avp.OriginHost, avp.Mbit, 0, serverSettings2.OriginHost)
+		cer.NewAVP(avp.OriginRealm, avp.Mbit, 0, serverSettings2.OriginRealm)
+		cer.NewAVP(avp.HostIPAddress, avp.Mbit, 0, localhostAddress)
+		cer.NewAVP(avp.VendorID, avp.Mbit, 0, serverSettings2.VendorID)
+		cer.NewAVP(avp.ProductName, 0, 0, serverSettings2.ProductName)
+		cer.NewAVP(avp.AcctApplicationID, avp.Mbit, 0, datatype.Unsigned32(3))
+		if _, err = cer.WriteTo(r); err != nil {
+			t.Error(err)
+			return
+		}
+		if !answer {
+			return
+		}
+		if err := waitPeerState(sm, serverSettings2.OriginHost, WaitReturns); err != nil {
+			t.Error(err)
+			return
+		}
+		a := m.Answer(diam.Success)
+		a.NewAVP(avp.OriginHost, avp.Mbit, 0, serverSettings2.OriginHost)
+		a.NewAVP(avp.OriginRealm, avp.Mbit, 0, serverSettings2.OriginRealm)
+		a.NewAVP(avp.AcctApplicationID, avp.Mbit, 0, datatype.Unsigned32(3))
+		a.WriteTo(c)
+	})
+	return diamtest.NewServer(mux, dict.Default), rc
+}
+
+func TestPeerState_ElectionLost(t *testing.T) {
+	settings := *serverSettings
+	settings.OriginHost = "a" // lower than srv2
+	settings.SingleConnection = true
+	sm := New(&settings)
+	srv := diamtest.NewServer(sm, dict.Default)
+	defer srv.Close()
+	peer, rc := simultaneousPeer(t, sm, srv.Addr, true)
+	defer peer.Close()
+
+	c, err := newPeerClient(sm, serverSettings2.OriginHost).Dial(peer.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if err := waitPeerState(sm, serverSettings2.OriginHost, IOpen); err != nil {
+		t.Fatal(err)
+	}
+	if sm.PeerConn(serverSettings2.OriginHost) != c {
+		t.Fatal("Unexpected peer connection")
+	}
+	// R-Disc
+	r := <-rc
+	defer r.Close()
+	r.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err = r.Read(make([]byte, 1)); err != io.EOF {
+		t.Fatalf("Responder connection was not closed: %v", err)
+	}
+}
+
+func TestPeerState_ElectionWon(t *testing.T) {
+	settings := *serverSettings
+	settings.OriginHost = "z" // higher than srv2
+	settings.SingleConnection = true
+	sm := New(&settings)
+	srv := diamtest.NewServer(sm, dict.Default)
+	defer srv.Close()
+	peer, rc := simultaneousPeer(t, sm, srv.Addr, false)
+	defer peer.Close()
+
+	_, err := newPeerClient(sm, serverSettings2.OriginHost).Dial(peer.Addr)
+	if err != ErrPeerConnected {
+		t.Fatalf("Unexpected error. Want %v, have %v", ErrPeerConnected, err)
+	}
+	r := <-rc
+	defer r.Close()
+	// R-Snd-CEA
+	r.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err = diam.ReadMessage(r, dict.Default); err != nil {
+		t.Fatal(err)
+	}
+	if err := waitPeerState(sm, serverSettings2.OriginHost, ROpen); err != nil {
+		t.Fatal(err)
+	}
+	if sm.PeerConn(serverSettings2.OriginHost) == nil {
+		t.Fatal("Responder connection was not kept")
+	}
+}
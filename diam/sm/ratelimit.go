@@ -0,0 +1,166 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/sm/smpeer"
+)
+
+// RateLimiter decides the admission of the requests received by the
+// state machine, which sheds load by answering DIAMETER_TOO_BUSY instead
+// of queuing requests unboundedly. See TokenBucket.
+type RateLimiter interface {
+	// Reserve is called for every request m received from the peer
+	// host after the capabilities exchange, other than the base
+	// protocol messages. It returns the delay after which the request
+	// is handled, or false if it is rejected with DIAMETER_TOO_BUSY.
+	Reserve(host datatype.DiameterIdentity, m *diam.Message) (time.Duration, bool)
+}
+
+// TokenBucket is a RateLimiter that admits requests at a sustained rate
+// with bursts, globally and per peer. Requests that would have to wait
+// more than MaxDelay for both limits are rejected.
+//
+// It must not be copied after first use. It is safe for concurrent use.
+type TokenBucket struct {
+	// Rate is the number of requests per second admitted from all the
+	// peers, and Burst the number of requests admitted at once. The
+	// global rate is unlimited when zero.
+	Rate  float64
+	Burst int
+
+	// PeerRate and PeerBurst limit the requests of each peer likewise.
+	// The rate of peers is unlimited when zero.
+	PeerRate  float64
+	PeerBurst int
+
+	// MaxDelay is the longest time requests are deferred for, while
+	// blocking the connection they were received on. Requests are
+	// rejected instead of deferred when zero.
+	MaxDelay time.Duration
+
+	mu     sync.Mutex // guards global and peers
+	global bucket
+	peers  map[datatype.DiameterIdentity]*bucket
+}
+
+// bucket holds the tokens of a limit. Tokens are negative when requests
+// were deferred until they are refilled.
+type bucket struct {
+	tokens float64
+	last   time.Time // last refill
+}
+
+// wait refills the bucket at rate up to burst tokens, and returns the
+// time to wait for a token.
+func (b *bucket) wait(now time.Time, rate float64, burst int) time.Duration {
+	if burst < 1 {
+		burst = 1
+	}
+	if b.last.IsZero() {
+		b.tokens = float64(burst)
+	} else {
+		b.tokens = math.Min(float64(burst), b.tokens+now.Sub(b.last).Seconds()*rate)
+	}
+	b.last = now
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / rate * float64(time.Second))
+}
+
+// Reserve implements the RateLimiter interface.
+func (tb *TokenBucket) Reserve(host datatype.DiameterIdentity, m *diam.Message) (time.Duration, bool) {
+	now := time.Now()
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	var delay time.Duration
+	if tb.Rate > 0 {
+		delay = tb.global.wait(now, tb.Rate, tb.Burst)
+	}
+	var peer *bucket
+	if tb.PeerRate > 0 {
+		if tb.peers == nil {
+			tb.peers = make(map[datatype.DiameterIdentity]*bucket)
+		}
+		if peer = tb.peers[host]; peer == nil {
+			peer = &bucket{}
+			tb.peers[host] = peer
+		}
+		if d := peer.wait(now, tb.PeerRate, tb.PeerBurst); d > delay {
+			delay = d
+		}
+	}
+	if delay > tb.MaxDelay {
+		return 0, false
+	}
+	if tb.Rate > 0 {
+		tb.global.tokens--
+	}
+	if peer != nil {
+		peer.tokens--
+	}
+	return delay, true
+}
+
+// admit applies the RateLimiter of the settings to the request m, and
+// returns false if it was rejected. Deferred requests are delayed here.
+func (sm *StateMachine) admit(c diam.Conn, m *diam.Message) bool {
+	rl := sm.cfg.RateLimiter
+	if rl == nil || baseCommand(m.Header.CommandCode) {
+		return true
+	}
+	meta, ok := smpeer.FromContext(c.Context())
+	if !ok {
+		return true
+	}
+	delay, ok := rl.Reserve(meta.OriginHost, m)
+	if !ok {
+		if mc := sm.cfg.Metrics; mc != nil {
+			mc.RequestDropped(c, m)
+		}
+		if err := sm.tooBusy(c, m); err != nil {
+			sm.Error(&diam.ErrorReport{Conn: c, Message: m, Error: err})
+		}
+		return false
+	}
+	if delay > 0 {
+		if mc := sm.cfg.Metrics; mc != nil {
+			mc.RequestDeferred(c, m)
+		}
+		time.Sleep(delay)
+	}
+	return true
+}
+
+// baseCommand returns whether code is a command of the base protocol
+// that manages the connection, which are never rate limited.
+func baseCommand(code uint32) bool {
+	switch code {
+	case diam.CapabilitiesExchange, diam.CapabilitiesUpdate, diam.DeviceWatchdog, diam.DisconnectPeer:
+		return true
+	}
+	return false
+}
+
+// tooBusy answers the request m with DIAMETER_TOO_BUSY.
+func (sm *StateMachine) tooBusy(c diam.Conn, m *diam.Message) error {
+	a := m.Answer(diam.TooBusy)
+	a.Header.CommandFlags |= diam.ErrorFlag
+	a.NewAVP(avp.OriginHost, avp.Mbit, 0, sm.cfg.OriginHost)
+	a.NewAVP(avp.OriginRealm, avp.Mbit, 0, sm.cfg.OriginRealm)
+	if _, err := a.WriteTo(c); err != nil {
+		return fmt.Errorf("Error TOO_BUSY answer send failure: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+	"github.com/omnicate/go-diameter/v4/diam/metrics"
+)
+
+func TestTokenBucket(t *testing.T) {
+	tb := &TokenBucket{
+		Rate:      100,
+		Burst:     3,
+		PeerRate:  10,
+		PeerBurst: 2,
+		MaxDelay:  50 * time.Millisecond,
+	}
+	for i := 0; i < 2; i++ {
+		if d, ok := tb.Reserve("a", nil); !ok || d != 0 {
+			t.Fatalf("Request %d of the burst was not admitted: %s, %t", i, d, ok)
+		}
+	}
+	if _, ok := tb.Reserve("a", nil); ok {
+		t.Fatal("Request above the peer rate was admitted")
+	}
+	if d, ok := tb.Reserve("b", nil); !ok || d != 0 {
+		t.Fatalf("Request of another peer was not admitted: %s, %t", d, ok)
+	}
+	// The global burst is exhausted.
+	if d, ok := tb.Reserve("c", nil); !ok || d <= 0 || d > 10*time.Millisecond {
+		t.Fatalf("Request above the global rate was not deferred: %s, %t", d, ok)
+	}
+}
+
+func TestStateMachine_RateLimiter(t *testing.T) {
+	cfg := *serverSettings
+	cfg.Metrics = metrics.NewCollector()
+	cfg.RateLimiter = &TokenBucket{PeerRate: 0.1, PeerBurst: 1}
+	srv := diamtest.NewServer(newACRServer(&cfg), dict.Default)
+	defer srv.Close()
+
+	cli := newPeerClient(New(clientSettings), "")
+	c, err := cli.Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	a, err := cli.Send(c, newACR(cli))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if busyAnswer(a) {
+		t.Fatalf("First request was rejected: %s", a)
+	}
+	if a, err = cli.Send(c, newACR(cli)); err != nil {
+		t.Fatal(err)
+	}
+	if !busyAnswer(a) || a.Header.CommandFlags&diam.ErrorFlag == 0 {
+		t.Fatalf("Unexpected answer. Want DIAMETER_TOO_BUSY, have %s", a)
+	}
+	var b bytes.Buffer
+	cfg.Metrics.WriteTo(&b)
+	want := `diameter_requests_dropped_total{application="3",command="ACR"} 1`
+	if !strings.Contains(b.String(), want) {
+		t.Fatalf("Missing metric %s:\n%s", want, b.String())
+	}
+}
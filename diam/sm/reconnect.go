@@ -0,0 +1,179 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/sm/smpeer"
+)
+
+// ConnState is the state of a connection of a Client, reported by
+// ConnEvents.
+type ConnState int
+
+// Connection states.
+const (
+	ConnLost            ConnState = iota // Connection dropped
+	ConnReconnected                      // New connection opened, after CER/CEA
+	ConnReconnectFailed                  // Reconnect attempt failed
+	ConnAbandoned                        // No more reconnect attempts
+)
+
+var connStates = [...]string{
+	"Lost",
+	"Reconnected",
+	"Reconnect-Failed",
+	"Abandoned",
+}
+
+func (s ConnState) String() string {
+	if s >= 0 && int(s) < len(connStates) {
+		return connStates[s]
+	}
+	return "ConnState(" + strconv.Itoa(int(s)) + ")"
+}
+
+// ConnEvent is a change of the state of a connection of a Client.
+type ConnEvent struct {
+	State   ConnState
+	Conn    diam.Conn // Connection lost, or new connection when reconnected
+	Attempt int       // Reconnect attempt, from 1
+	Err     error     // Error of the failed attempt
+}
+
+// ReconnectPolicy configures the automatic reconnection of the Client's
+// connections that drop, including those closed by the watchdog: the
+// connection is dialed again after the Tc timer, with the same
+// arguments, and the CER/CEA is exchanged again. See RFC 6733 section
+// 2.1 for details.
+//
+// Peers that disconnect with a DPR are reconnected after the reconnect
+// delay of the DisconnectPolicy, unless the cause is
+// DisconnectDoNotWantToTalkToYou. Connections are not reconnected after
+// Stop or Client.Close.
+//
+// The policy must not be copied after first use. It is safe for
+// concurrent use.
+type ReconnectPolicy struct {
+	// Tc is the time to wait before each reconnect attempt. Defaults
+	// to DefaultTc.
+	Tc time.Duration
+
+	// MaxTc makes Tc double after each failed attempt, up to MaxTc.
+	// Tc is constant when zero.
+	MaxTc time.Duration
+
+	// Jitter is the fraction of Tc, from 0 to 1, that is randomized
+	// so that peers do not reconnect at once.
+	Jitter float64
+
+	// MaxAttempts is the number of reconnect attempts after which the
+	// connection is abandoned. Attempts are unlimited when zero.
+	MaxAttempts int
+
+	// Events receives the changes of state of the connections, when
+	// set. Events are dropped when the channel is not ready.
+	Events chan<- ConnEvent
+
+	mu      sync.Mutex // guards stopped
+	stopped bool
+}
+
+// Stop stops the reconnection of the connections, including those
+// dialed afterwards.
+func (p *ReconnectPolicy) Stop() {
+	p.mu.Lock()
+	p.stopped = true
+	p.mu.Unlock()
+}
+
+func (p *ReconnectPolicy) isStopped() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.stopped
+}
+
+// interval returns the time to wait before the reconnect attempt n,
+// from 1.
+func (p *ReconnectPolicy) interval(n int) time.Duration {
+	d := p.Tc
+	if d <= 0 {
+		d = DefaultTc
+	}
+	for i := 1; i < n && d < p.MaxTc; i++ {
+		if d *= 2; d > p.MaxTc {
+			d = p.MaxTc
+		}
+	}
+	if p.Jitter > 0 {
+		d += time.Duration(p.Jitter * (2*rand.Float64() - 1) * float64(d))
+	}
+	return d
+}
+
+func (p *ReconnectPolicy) notify(ev ConnEvent) {
+	if p.Events == nil {
+		return
+	}
+	select {
+	case p.Events <- ev:
+	default:
+	}
+}
+
+// watchReconnect dials again with f when the connection c drops, as
+// configured by the Client's Reconnect policy.
+func (cli *Client) watchReconnect(f dialFunc, c diam.Conn) {
+	cn, ok := c.(diam.CloseNotifier)
+	if !ok {
+		return
+	}
+	disconnect := cn.CloseNotify()
+	go func() {
+		<-disconnect
+		cli.reconnect(f, c)
+	}()
+}
+
+func (cli *Client) reconnect(f dialFunc, c diam.Conn) {
+	p := cli.Reconnect
+	if p.isStopped() {
+		return
+	}
+	p.notify(ConnEvent{State: ConnLost, Conn: c})
+	var delay time.Duration
+	if dpr, ok := closeReason(c).(*DisconnectError); ok {
+		if dpr.Cause == DisconnectDoNotWantToTalkToYou {
+			p.notify(ConnEvent{State: ConnAbandoned, Conn: c})
+			return
+		}
+		if meta, ok := smpeer.FromContext(c.Context()); ok {
+			delay = cli.Handler.ReconnectDelay(meta.OriginHost)
+		}
+	}
+	for n := 1; p.MaxAttempts <= 0 || n <= p.MaxAttempts; n++ {
+		d := p.interval(n)
+		if n == 1 && delay > d {
+			d = delay
+		}
+		time.Sleep(d)
+		if p.isStopped() {
+			break
+		}
+		cli.Handler.emitConn(Reconnecting, c, nil, n)
+		nc, err := cli.dial(f)
+		if err == nil {
+			p.notify(ConnEvent{State: ConnReconnected, Conn: nc, Attempt: n})
+			return
+		}
+		p.notify(ConnEvent{State: ConnReconnectFailed, Conn: c, Attempt: n, Err: err})
+	}
+	p.notify(ConnEvent{State: ConnAbandoned, Conn: c})
+}
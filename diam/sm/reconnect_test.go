@@ -0,0 +1,130 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+// waitConnEvent returns the next event of events with the given state.
+func waitConnEvent(t *testing.T, events <-chan ConnEvent, state ConnState) ConnEvent {
+	t.Helper()
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case ev := <-events:
+			if ev.State == state {
+				return ev
+			}
+		case <-timeout:
+			t.Fatalf("No %s event", state)
+		}
+	}
+}
+
+func TestClient_Reconnect(t *testing.T) {
+	srvSM := newACRServer(serverSettings)
+	srv := diamtest.NewServer(srvSM, dict.Default)
+	defer srv.Close()
+
+	events := make(chan ConnEvent, 8)
+	cli := newPeerClient(New(clientSettings), "")
+	cli.Reconnect = &ReconnectPolicy{Tc: 10 * time.Millisecond, Events: events}
+	c, err := cli.Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = waitPeerState(srvSM, clientSettings.OriginHost, ROpen); err != nil {
+		t.Fatal(err)
+	}
+	srvSM.PeerConn(clientSettings.OriginHost).Close()
+	if ev := waitConnEvent(t, events, ConnLost); ev.Conn != c {
+		t.Fatal("Unexpected connection lost")
+	}
+	ev := waitConnEvent(t, events, ConnReconnected)
+	if ev.Conn == c || ev.Attempt != 1 {
+		t.Fatalf("Unexpected reconnect event: %+v", ev)
+	}
+	if _, err = cli.Send(ev.Conn, newACR(cli)); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err = cli.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case ev := <-events:
+		if ev.State != ConnLost {
+			t.Fatalf("Unexpected event after Close: %+v", ev)
+		}
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestClient_Reconnect_MaxAttempts(t *testing.T) {
+	srv := diamtest.NewServer(newACRServer(serverSettings), dict.Default)
+	events := make(chan ConnEvent, 8)
+	cli := newPeerClient(New(clientSettings), "")
+	cli.Reconnect = &ReconnectPolicy{Tc: 10 * time.Millisecond, MaxAttempts: 2, Events: events}
+	c, err := cli.Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.Close()
+	c.Close()
+	for n := 1; n <= 2; n++ {
+		if ev := waitConnEvent(t, events, ConnReconnectFailed); ev.Attempt != n || ev.Err == nil {
+			t.Fatalf("Unexpected failed attempt: %+v", ev)
+		}
+	}
+	waitConnEvent(t, events, ConnAbandoned)
+}
+
+func TestClient_Reconnect_DoNotWantToTalkToYou(t *testing.T) {
+	srvSM := newACRServer(serverSettings)
+	srv := diamtest.NewServer(srvSM, dict.Default)
+	defer srv.Close()
+
+	events := make(chan ConnEvent, 8)
+	cli := newPeerClient(New(clientSettings), "")
+	cli.Reconnect = &ReconnectPolicy{Tc: 10 * time.Millisecond, Events: events}
+	if _, err := cli.Dial(srv.Addr); err != nil {
+		t.Fatal(err)
+	}
+	if err := waitPeerState(srvSM, clientSettings.OriginHost, ROpen); err != nil {
+		t.Fatal(err)
+	}
+	sc := srvSM.PeerConn(clientSettings.OriginHost)
+	if err := srvSM.DisconnectPeer(context.Background(), sc, DisconnectDoNotWantToTalkToYou); err != nil {
+		t.Fatal(err)
+	}
+	sc.Close()
+	waitConnEvent(t, events, ConnLost)
+	if ev := <-events; ev.State != ConnAbandoned {
+		t.Fatalf("Unexpected event. Want %s, have %s", ConnAbandoned, ev.State)
+	}
+}
+
+func TestReconnectPolicy_Interval(t *testing.T) {
+	p := &ReconnectPolicy{Tc: time.Second, MaxTc: 3 * time.Second}
+	for n, want := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second} {
+		if d := p.interval(n + 1); d != want {
+			t.Fatalf("Unexpected interval of attempt %d. Want %s, have %s", n+1, want, d)
+		}
+	}
+	p = &ReconnectPolicy{Tc: time.Second, Jitter: 0.5}
+	for i := 0; i < 100; i++ {
+		if d := p.interval(1); d < time.Second/2 || d > 3*time.Second/2 {
+			t.Fatalf("Interval out of jitter range: %s", d)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// RedirectUsage is the value of the Redirect-Host-Usage AVP, which
+// tells how the redirect information may be cached.
+// See RFC 6733 section 6.13 for details.
+type RedirectUsage int32
+
+// Redirect-Host-Usage values.
+const (
+	RedirectDontCache           RedirectUsage = iota // Do not cache
+	RedirectAllSession                               // Requests of the same session
+	RedirectAllRealm                                 // Requests to the same realm
+	RedirectRealmAndApplication                      // Requests to the same realm and application
+	RedirectAllApplication                           // Requests of the same application
+	RedirectAllHost                                  // Requests to the same host
+	RedirectAllUser                                  // Requests of the same user
+)
+
+// Redirect is the information sent by a redirect agent in answers with
+// the DIAMETER_REDIRECT_INDICATION result code.
+type Redirect struct {
+	Hosts        []datatype.DiameterURI // Redirect-Host
+	Usage        RedirectUsage          // Redirect-Host-Usage
+	MaxCacheTime time.Duration          // Redirect-Max-Cache-Time
+}
+
+// RedirectFunc returns where the request m should be sent to, or nil
+// if the destination of the request is unknown.
+type RedirectFunc func(m *diam.Message) *Redirect
+
+// RedirectHandler returns a handler that answers requests with the
+// redirect information returned by f, or DIAMETER_UNABLE_TO_DELIVER
+// if f returns nil. Redirect agents register it as the catch-all:
+//
+//	sm.Handle("ALL", sm.RedirectHandler(f))
+//
+// See RFC 6733 section 6.1.8 for details.
+func (sm *StateMachine) RedirectHandler(f RedirectFunc) diam.Handler {
+	return diam.HandlerFunc(func(c diam.Conn, m *diam.Message) {
+		if m.Header.CommandFlags&diam.RequestFlag == 0 {
+			return
+		}
+		r := f(m)
+		var a *diam.Message
+		if r == nil || len(r.Hosts) == 0 {
+			a = sm.errorAnswer(m, diam.UnableToDeliver)
+		} else {
+			a = sm.errorAnswer(m, diam.RedirectIndication)
+			for _, host := range r.Hosts {
+				a.NewAVP(avp.RedirectHost, avp.Mbit, 0, host)
+			}
+			if r.Usage != RedirectDontCache {
+				a.NewAVP(avp.RedirectHostUsage, avp.Mbit, 0, datatype.Enumerated(r.Usage))
+				a.NewAVP(avp.RedirectMaxCacheTime, avp.Mbit, 0, datatype.Unsigned32(r.MaxCacheTime/time.Second))
+			}
+		}
+		if _, err := a.WriteTo(c); err != nil {
+			sm.Error(&diam.ErrorReport{Conn: c, Message: m, Error: err})
+		}
+	})
+}
+
+// errorAnswer returns an answer to m with the E bit and the given
+// Result-Code.
+func (sm *StateMachine) errorAnswer(m *diam.Message, code uint32) *diam.Message {
+	a := m.Answer(0)
+	a.Header.CommandFlags |= diam.ErrorFlag
+	if sid, err := m.FindAVP(avp.SessionID, 0); err == nil {
+		a.AddAVP(sid)
+	}
+	a.NewAVP(avp.ResultCode, avp.Mbit, 0, datatype.Unsigned32(code))
+	a.NewAVP(avp.OriginHost, avp.Mbit, 0, sm.cfg.OriginHost)
+	a.NewAVP(avp.OriginRealm, avp.Mbit, 0, sm.cfg.OriginRealm)
+	return a
+}
+
+// parseRedirect returns the redirect information of the answer a, or
+// nil if it's not a redirect indication.
+func parseRedirect(a *diam.Message) *Redirect {
+	if a.Header.CommandFlags&diam.ErrorFlag == 0 {
+		return nil
+	}
+	r := &Redirect{}
+	var redirect bool
+	for _, v := range a.AVP {
+		switch v.Code {
+		case avp.ResultCode:
+			redirect = v.Data == datatype.Unsigned32(diam.RedirectIndication)
+		case avp.RedirectHost:
+			if host, ok := v.Data.(datatype.DiameterURI); ok {
+				r.Hosts = append(r.Hosts, host)
+			}
+		case avp.RedirectHostUsage:
+			if usage, ok := v.Data.(datatype.Enumerated); ok {
+				r.Usage = RedirectUsage(usage)
+			}
+		case avp.RedirectMaxCacheTime:
+			if t, ok := v.Data.(datatype.Unsigned32); ok {
+				r.MaxCacheTime = time.Duration(t) * time.Second
+			}
+		}
+	}
+	if !redirect || len(r.Hosts) == 0 {
+		return nil
+	}
+	return r
+}
+
+// redirectKey returns the key of the request m in the redirect cache
+// for the given usage, or an empty string if m has no such key.
+func redirectKey(m *diam.Message, usage RedirectUsage) string {
+	var code uint32
+	switch usage {
+	case RedirectAllSession:
+		code = avp.SessionID
+	case RedirectAllRealm, RedirectRealmAndApplication:
+		code = avp.DestinationRealm
+	case RedirectAllApplication:
+		return strconv.FormatUint(uint64(m.Header.ApplicationID), 10)
+	case RedirectAllHost:
+		code = avp.DestinationHost
+	case RedirectAllUser:
+		code = avp.UserName
+	default:
+		return ""
+	}
+	for _, a := range m.AVP {
+		if a.Code != code {
+			continue
+		}
+		key := fmt.Sprint(a.Data)
+		if usage == RedirectRealmAndApplication {
+			key += "/" + strconv.FormatUint(uint64(m.Header.ApplicationID), 10)
+		}
+		return key
+	}
+	return ""
+}
+
+// Cache lookups go from the most to the least specific usage.
+var redirectLookupOrder = []RedirectUsage{
+	RedirectAllSession,
+	RedirectAllUser,
+	RedirectAllHost,
+	RedirectRealmAndApplication,
+	RedirectAllRealm,
+	RedirectAllApplication,
+}
+
+type redirectCacheKey struct {
+	usage RedirectUsage
+	key   string
+}
+
+type redirectCacheEntry struct {
+	hosts   []datatype.DiameterURI
+	expires time.Time
+}
+
+// redirectCache holds the redirect information received by clients,
+// keyed per Redirect-Host-Usage.
+type redirectCache struct {
+	mu sync.Mutex // guards m
+	m  map[redirectCacheKey]redirectCacheEntry
+}
+
+// add caches the redirect r received in the answer to the request m.
+func (rc *redirectCache) add(m *diam.Message, r *Redirect) {
+	key := redirectKey(m, r.Usage)
+	if len(key) == 0 || r.MaxCacheTime <= 0 {
+		return
+	}
+	rc.mu.Lock()
+	if rc.m == nil {
+		rc.m = make(map[redirectCacheKey]redirectCacheEntry)
+	}
+	rc.m[redirectCacheKey{r.Usage, key}] = redirectCacheEntry{
+		hosts:   r.Hosts,
+		expires: time.Now().Add(r.MaxCacheTime),
+	}
+	rc.mu.Unlock()
+}
+
+// lookup returns the cached redirect hosts for the request m.
+func (rc *redirectCache) lookup(m *diam.Message) []datatype.DiameterURI {
+	now := time.Now()
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	for _, usage := range redirectLookupOrder {
+		key := redirectKey(m, usage)
+		if len(key) == 0 {
+			continue
+		}
+		k := redirectCacheKey{usage, key}
+		e, ok := rc.m[k]
+		if !ok {
+			continue
+		}
+		if now.After(e.expires) {
+			delete(rc.m, k)
+			continue
+		}
+		return e.hosts
+	}
+	return nil
+}
+
+// parseDiameterURI returns the host, network and address of the
+// DiameterURI u, as in "aaa://host:port;transport=tcp".
+// See RFC 6733 section 4.3.1 for details.
+func parseDiameterURI(u datatype.DiameterURI) (host datatype.DiameterIdentity, network, addr string, err error) {
+	s := string(u)
+	port := "3868"
+	switch {
+	case strings.HasPrefix(s, "aaa://"):
+		s = s[len("aaa://"):]
+	case strings.HasPrefix(s, "aaas://"):
+		s, port = s[len("aaas://"):], "5658"
+	default:
+		return "", "", "", fmt.Errorf("invalid DiameterURI: %q", string(u))
+	}
+	params := strings.Split(s, ";")
+	network = "tcp"
+	for _, p := range params[1:] {
+		if strings.HasPrefix(p, "transport=") {
+			network = p[len("transport="):]
+		}
+	}
+	hostport := params[0]
+	if h, p, err := net.SplitHostPort(hostport); err == nil {
+		hostport, port = h, p
+	}
+	if len(hostport) == 0 {
+		return "", "", "", fmt.Errorf("invalid DiameterURI: %q", string(u))
+	}
+	return datatype.DiameterIdentity(hostport), network, net.JoinHostPort(hostport, port), nil
+}
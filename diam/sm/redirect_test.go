@@ -0,0 +1,85 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+func TestClient_Send_Redirect(t *testing.T) {
+	srv := diamtest.NewServer(newACRServer(serverSettings2), dict.Default)
+	defer srv.Close()
+	_, port, _ := net.SplitHostPort(srv.Addr)
+
+	redirects := make(chan *diam.Message, 2)
+	agentSM := New(serverSettings)
+	agentSM.Handle("ALL", agentSM.RedirectHandler(func(m *diam.Message) *Redirect {
+		redirects <- m
+		return &Redirect{
+			Hosts:        []datatype.DiameterURI{datatype.DiameterURI("aaa://127.0.0.1:" + port + ";transport=tcp")},
+			Usage:        RedirectAllRealm,
+			MaxCacheTime: time.Minute,
+		}
+	}))
+	agent := diamtest.NewServer(agentSM, dict.Default)
+	defer agent.Close()
+
+	cli := newPeerClient(New(clientSettings), "")
+	cli.FollowRedirects = true
+	cli.RequestTimeout = time.Second
+	c, err := cli.Dial(agent.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	for i := 0; i < 2; i++ {
+		a, err := cli.Send(c, newACR(cli))
+		if err != nil {
+			t.Fatal(err)
+		}
+		oh, err := a.FindAVP(avp.OriginHost, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if oh.Data != serverSettings2.OriginHost {
+			t.Fatalf("Unexpected Origin-Host. Want %s, have %s", serverSettings2.OriginHost, oh.Data)
+		}
+	}
+	if len(redirects) != 1 {
+		t.Fatalf("Unexpected number of redirected requests. Want 1, have %d", len(redirects))
+	}
+}
+
+func TestParseDiameterURI(t *testing.T) {
+	for _, tc := range []struct {
+		uri           datatype.DiameterURI
+		host          datatype.DiameterIdentity
+		network, addr string
+	}{
+		{"aaa://host.example.com", "host.example.com", "tcp", "host.example.com:3868"},
+		{"aaa://host.example.com:6666;transport=sctp", "host.example.com", "sctp", "host.example.com:6666"},
+		{"aaas://host.example.com;transport=tcp;protocol=diameter", "host.example.com", "tcp", "host.example.com:5658"},
+	} {
+		host, network, addr, err := parseDiameterURI(tc.uri)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if host != tc.host || network != tc.network || addr != tc.addr {
+			t.Fatalf("Unexpected result for %s: %s %s %s", tc.uri, host, network, addr)
+		}
+	}
+	if _, _, _, err := parseDiameterURI("http://host.example.com"); err == nil {
+		t.Fatal("Invalid DiameterURI was parsed")
+	}
+}
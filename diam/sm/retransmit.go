@@ -0,0 +1,314 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/sm/smpeer"
+	"github.com/omnicate/go-diameter/v4/diam/tracing"
+)
+
+// RetransmissionFunc is called by the StateMachine for every request
+// received with the T (retransmitted) flag set, before the request is
+// dispatched to its handler.
+//
+// It returns false to drop the request, for example after answering it
+// from a cache of previously sent answers.
+type RetransmissionFunc func(c diam.Conn, m *diam.Message) bool
+
+// pendingRequests holds the requests sent by Client.Send that are
+// waiting for an answer, indexed by End-to-End identifier.
+type pendingRequests struct {
+	mu sync.Mutex // guards m
+	m  map[uint32]chan *diam.Message
+}
+
+func (p *pendingRequests) add(id uint32) chan *diam.Message {
+	c := make(chan *diam.Message, 1)
+	p.mu.Lock()
+	if p.m == nil {
+		p.m = make(map[uint32]chan *diam.Message)
+	}
+	p.m[id] = c
+	p.mu.Unlock()
+	return c
+}
+
+func (p *pendingRequests) remove(id uint32) {
+	p.mu.Lock()
+	delete(p.m, id)
+	p.mu.Unlock()
+}
+
+// deliver hands the answer m to the pending request it belongs to,
+// and reports whether there was one.
+func (p *pendingRequests) deliver(m *diam.Message) bool {
+	p.mu.Lock()
+	c, ok := p.m[m.Header.EndToEndID]
+	delete(p.m, m.Header.EndToEndID)
+	p.mu.Unlock()
+	if ok {
+		c <- m
+	}
+	return ok
+}
+
+// Send writes the request m to c and waits for its answer.
+//
+// If no answer is received within RequestTimeout, the request is sent
+// again with the T flag set and the same End-to-End identifier, up to
+// RequestRetransmits times. The timeout is multiplied by
+// RetransmitBackoff after each retransmission. ErrRequestTimeout is
+// returned if no answer is received after the last one.
+//
+// The answer is returned to the caller instead of being dispatched to
+// the handlers registered in the state machine. Requests are throttled
+// by OverloadControl, when set.
+//
+// If c is nil, the request is sent to its Destination-Host, if
+// connected, or else to the next hop selected by Routes.
+//
+// If the connection fails before the answer is received, the request is
+// sent again with the T flag set on the connection returned by Failover.
+// ErrPeerDisconnected is returned if there is none.
+//
+// Requests of applications the peer of c did not advertise are not
+// sent, and return ErrApplicationUnsupportedByPeer, unless the Client
+// or the connection allow unsupported applications, see
+// AllowUnsupportedApps.
+//
+// If FollowRedirects is set, requests answered with a redirect
+// indication are sent again to one of the redirect hosts, and the
+// redirect information is cached as allowed by its Redirect-Host-Usage.
+func (cli *Client) Send(c diam.Conn, m *diam.Message) (*diam.Message, error) {
+	return cli.SendContext(context.Background(), c, m)
+}
+
+// SendContext is like Send, but stops waiting for the answer when ctx is
+// done, and returns ctx.Err().
+//
+// When Settings.Tracer is set, the request is traced with a client span
+// whose parent is the span carried by ctx, if any.
+func (cli *Client) SendContext(ctx context.Context, c diam.Conn, m *diam.Message) (a *diam.Message, err error) {
+	if cli.Handler == nil {
+		return nil, ErrMissingStateMachine
+	}
+	if t := cli.Handler.cfg.Tracer; t != nil {
+		var span tracing.Span
+		ctx, span = t.Start(ctx, tracing.Client, c, m)
+		defer func() { span.End(a, err) }()
+	}
+	if c == nil {
+		if c, err = cli.nextHop(m); err != nil {
+			return nil, err
+		}
+	}
+	if !cli.FollowRedirects {
+		return cli.send(ctx, c, m)
+	}
+	if hosts := cli.Handler.redirects.lookup(m); hosts != nil {
+		if rc, err := cli.redirectConn(hosts); err == nil {
+			c = rc
+		}
+	}
+	if a, err = cli.send(ctx, c, m); err != nil {
+		return nil, err
+	}
+	r := parseRedirect(a)
+	if r == nil {
+		return a, nil
+	}
+	cli.Handler.redirects.add(m, r)
+	if c, err = cli.redirectConn(r.Hosts); err != nil {
+		return nil, err
+	}
+	m.Header.CommandFlags &^= diam.RetransmittedFlag
+	return cli.send(ctx, c, m)
+}
+
+func (cli *Client) send(ctx context.Context, c diam.Conn, m *diam.Message) (*diam.Message, error) {
+	if !cli.supports(c, m.Header.ApplicationID) {
+		return nil, ErrApplicationUnsupportedByPeer
+	}
+	if cli.Backpressure != nil {
+		return cli.sendBackpressure(ctx, c, m)
+	}
+	return cli.transmit(ctx, c, m)
+}
+
+// unsupportedAppsKey marks the connections of AllowUnsupportedApps, in
+// their context.
+type unsupportedAppsKey struct{}
+
+// AllowUnsupportedApps allows Client.Send to send requests of any
+// application on the connection c, such as to relay agents that do not
+// advertise the Relay application, whatever the applications advertised
+// by its peer.
+func AllowUnsupportedApps(c diam.Conn) {
+	c.SetContext(context.WithValue(c.Context(), unsupportedAppsKey{}, true))
+}
+
+// supports returns whether requests of the application appID can be
+// sent on the connection c: its peer advertised appID in its CER or CEA,
+// its handshake is not complete, or unsupported applications are
+// allowed.
+func (cli *Client) supports(c diam.Conn, appID uint32) bool {
+	if cli.AllowUnsupportedApps {
+		return true
+	}
+	meta, ok := smpeer.FromConn(c)
+	if !ok || meta.Supports(appID) {
+		return true
+	}
+	allowed, _ := c.Context().Value(unsupportedAppsKey{}).(bool)
+	return allowed
+}
+
+// transmit sends the request m on c, retransmits and fails it over as
+// configured, and returns its answer.
+func (cli *Client) transmit(ctx context.Context, c diam.Conn, m *diam.Message) (*diam.Message, error) {
+	if cli.OverloadControl != nil {
+		if err := cli.OverloadControl.Prepare(m); err != nil {
+			return nil, err
+		}
+	}
+	id := m.Header.EndToEndID
+	answerc := cli.Handler.pending.add(id)
+	defer cli.Handler.pending.remove(id)
+	cluster := cli.Handler.cfg.Cluster != nil
+	unregister := func() {}
+	if cluster {
+		unregister = cli.Handler.register(ctx, c, m)
+	}
+	defer func() { unregister() }()
+	timeout := cli.requestTimeout()
+	disconnect := closeNotify(c)
+	var tried []diam.Conn // failed connections
+	for i := uint(0); ; {
+		_, err := m.WriteTo(c)
+		if err == nil {
+			select {
+			case a := <-answerc:
+				return a, nil
+			case <-disconnect:
+				err = ErrPeerDisconnected
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(timeout):
+			}
+		}
+		if err != nil {
+			// The connection failed, fail over to another one.
+			tried = append(tried, c)
+			if c = cli.failover(c, m, tried); c == nil {
+				return nil, err
+			}
+			disconnect = closeNotify(c)
+			m.Header.CommandFlags |= diam.RetransmittedFlag
+			m.Header.HopByHopID = rand.Uint32()
+			if cluster {
+				unregister()
+				unregister = cli.Handler.register(ctx, c, m)
+			}
+			continue
+		}
+		if i == cli.RequestRetransmits {
+			return nil, ErrRequestTimeout
+		}
+		i++
+		m.Header.CommandFlags |= diam.RetransmittedFlag
+		if cli.RetransmitBackoff > 1 {
+			timeout = time.Duration(float64(timeout) * cli.RetransmitBackoff)
+		}
+	}
+}
+
+// nextHop returns the connection to the peer the request m is routed
+// to, other than the connections exclude. Routes are only selected if
+// their peer supports the application of m, its watchdog is in the
+// WatchdogOkay state, and its circuit is not open when the Client has a
+// Backpressure policy.
+func (cli *Client) nextHop(m *diam.Message, exclude ...diam.Conn) (diam.Conn, error) {
+	available := func(h datatype.DiameterIdentity) diam.Conn {
+		c := cli.Handler.PeerConn(h)
+		if c == nil {
+			return nil
+		}
+		for _, e := range exclude {
+			if c == e {
+				return nil
+			}
+		}
+		if cli.Backpressure != nil && cli.Backpressure.CircuitOpen(h) {
+			return nil
+		}
+		if !watchdogOK(c) {
+			return nil
+		}
+		return c
+	}
+	host, realm := Destination(m)
+	if len(host) > 0 {
+		if c := available(host); c != nil {
+			return c, nil
+		}
+	}
+	if cli.Routes == nil {
+		return nil, ErrNoRoute
+	}
+	host, err := cli.Routes.NextHop(realm, m.Header.ApplicationID, func(h datatype.DiameterIdentity) bool {
+		c := available(h)
+		return c != nil && supports(c, m.Header.ApplicationID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if c := available(host); c != nil {
+		return c, nil
+	}
+	return nil, ErrNoAvailablePeer
+}
+
+// redirectConn returns a connection to the first of hosts that is
+// connected or can be dialed.
+func (cli *Client) redirectConn(hosts []datatype.DiameterURI) (diam.Conn, error) {
+	var err error
+	for _, uri := range hosts {
+		var (
+			host          datatype.DiameterIdentity
+			network, addr string
+		)
+		if host, network, addr, err = parseDiameterURI(uri); err != nil {
+			continue
+		}
+		if c := cli.Handler.PeerConn(host); c != nil {
+			return c, nil
+		}
+		rcli := *cli
+		rcli.PeerIdentity = host
+		var c diam.Conn
+		if c, err = rcli.DialNetwork(network, addr); err == nil {
+			return c, nil
+		}
+	}
+	return nil, err
+}
+
+func (cli *Client) requestTimeout() time.Duration {
+	switch {
+	case cli.RequestTimeout > 0:
+		return cli.RequestTimeout
+	case cli.WatchdogInterval > 0:
+		return cli.WatchdogInterval
+	}
+	return 5 * time.Second
+}
@@ -0,0 +1,113 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+func newACR(cli *Client) *diam.Message {
+	m := diam.NewRequest(diam.Accounting, 3, dict.Default)
+	m.NewAVP(avp.SessionID, avp.Mbit, 0, datatype.UTF8String("sess"))
+	m.NewAVP(avp.OriginHost, avp.Mbit, 0, cli.Handler.cfg.OriginHost)
+	m.NewAVP(avp.OriginRealm, avp.Mbit, 0, cli.Handler.cfg.OriginRealm)
+	m.NewAVP(avp.DestinationRealm, avp.Mbit, 0, serverSettings.OriginRealm)
+	m.NewAVP(avp.AccountingRecordType, avp.Mbit, 0, datatype.Enumerated(1))
+	m.NewAVP(avp.AccountingRecordNumber, avp.Mbit, 0, datatype.Unsigned32(0))
+	return m
+}
+
+// newACRServer returns a state machine that answers ACRs with success.
+func newACRServer(settings *Settings) *StateMachine {
+	sm := New(settings)
+	sm.HandleFunc("ACR", func(c diam.Conn, m *diam.Message) {
+		a := m.Answer(diam.Success)
+		a.NewAVP(avp.OriginHost, avp.Mbit, 0, settings.OriginHost)
+		a.NewAVP(avp.OriginRealm, avp.Mbit, 0, settings.OriginRealm)
+		a.WriteTo(c)
+	})
+	return sm
+}
+
+func TestClient_Send_Retransmit(t *testing.T) {
+	retransmitted := make(chan *diam.Message, 1)
+	srvSM := New(serverSettings)
+	srvSM.HandleRetransmission(func(c diam.Conn, m *diam.Message) bool {
+		retransmitted <- m
+		return true
+	})
+	var received int
+	srvSM.HandleFunc("ACR", func(c diam.Conn, m *diam.Message) {
+		// Drop the first request.
+		if received++; received == 1 {
+			return
+		}
+		a := m.Answer(diam.Success)
+		a.NewAVP(avp.OriginHost, avp.Mbit, 0, serverSettings.OriginHost)
+		a.NewAVP(avp.OriginRealm, avp.Mbit, 0, serverSettings.OriginRealm)
+		a.WriteTo(c)
+	})
+	srv := diamtest.NewServer(srvSM, dict.Default)
+	defer srv.Close()
+
+	cli := newPeerClient(New(clientSettings), "")
+	cli.RequestRetransmits = 1
+	cli.RequestTimeout = 50 * time.Millisecond
+	c, err := cli.Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	req := newACR(cli)
+	a, err := cli.Send(c, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Header.EndToEndID != req.Header.EndToEndID {
+		t.Fatalf("Unexpected End-to-End ID. Want %d, have %d", req.Header.EndToEndID, a.Header.EndToEndID)
+	}
+	select {
+	case m := <-retransmitted:
+		if m.Header.EndToEndID != req.Header.EndToEndID {
+			t.Fatalf("Unexpected End-to-End ID. Want %d, have %d", req.Header.EndToEndID, m.Header.EndToEndID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Retransmission was not detected")
+	}
+}
+
+func TestClient_Send_Timeout(t *testing.T) {
+	srvSM := New(serverSettings)
+	srvSM.HandleFunc("ACR", func(c diam.Conn, m *diam.Message) {})
+	srv := diamtest.NewServer(srvSM, dict.Default)
+	defer srv.Close()
+
+	cli := newPeerClient(New(clientSettings), "")
+	cli.RequestRetransmits = 2
+	cli.RequestTimeout = 10 * time.Millisecond
+	cli.RetransmitBackoff = 2
+	c, err := cli.Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	start := time.Now()
+	if _, err = cli.Send(c, newACR(cli)); err != ErrRequestTimeout {
+		t.Fatalf("Unexpected error. Want %v, have %v", ErrRequestTimeout, err)
+	}
+	// 10ms + 20ms + 40ms
+	if d := time.Since(start); d < 70*time.Millisecond {
+		t.Fatalf("Retransmissions did not back off: %s", d)
+	}
+}
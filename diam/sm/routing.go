@@ -0,0 +1,209 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/load"
+)
+
+// AnyApplication is the application id of routes that apply to
+// requests of all applications.
+const AnyApplication = 0xffffffff
+
+var (
+	// ErrNoRoute is returned when the routing table has no route to
+	// the realm and application of a request.
+	ErrNoRoute = errors.New("no route to realm")
+
+	// ErrNoAvailablePeer is returned when none of the peers of the
+	// route to the realm of a request are available.
+	ErrNoAvailablePeer = errors.New("no available peer")
+)
+
+// Route is a next hop of the routing table.
+type Route struct {
+	Host     datatype.DiameterIdentity // Peer identity
+	Priority int                       // Lower values are preferred
+	Weight   int                       // Share of requests among routes of the same priority
+}
+
+type routeKey struct {
+	realm datatype.DiameterIdentity
+	appID uint32
+}
+
+// RoutingTable holds the peers that serve each realm and application,
+// as the realm-based routing table of RFC 6733 section 2.7.
+//
+// Realms may be wildcards: "*.example.com" matches all the realms under
+// example.com, and "*" matches all realms. Routes to the exact realm are
+// preferred, followed by the longest matching wildcard. Routes for a
+// specific application are preferred over those for AnyApplication.
+//
+// It is safe to add and remove routes while the table is in use.
+type RoutingTable struct {
+	mu     sync.RWMutex // guards routes and load
+	routes map[routeKey][]Route
+	load   *load.Table
+}
+
+// NewRoutingTable creates and initializes an empty RoutingTable.
+func NewRoutingTable() *RoutingTable {
+	return &RoutingTable{routes: make(map[routeKey][]Route)}
+}
+
+// Add adds routes to the realm for the application appID.
+// A route to a host that already exists is replaced.
+func (rt *RoutingTable) Add(realm datatype.DiameterIdentity, appID uint32, routes ...Route) {
+	k := routeKey{realm, appID}
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	for _, r := range routes {
+		rt.routes[k] = append(removeRoute(rt.routes[k], r.Host), r)
+	}
+	sort.SliceStable(rt.routes[k], func(i, j int) bool {
+		return rt.routes[k][i].Priority < rt.routes[k][j].Priority
+	})
+}
+
+// SetLoadTable sets the table of peer loads used by NextHop to scale
+// the weights of routes, so that less loaded peers receive a larger
+// share of requests. See the load sub-package for details.
+func (rt *RoutingTable) SetLoadTable(t *load.Table) {
+	rt.mu.Lock()
+	rt.load = t
+	rt.mu.Unlock()
+}
+
+// Remove removes the routes to the given hosts from the realm for the
+// application appID, or all of its routes if no host is given.
+func (rt *RoutingTable) Remove(realm datatype.DiameterIdentity, appID uint32, hosts ...datatype.DiameterIdentity) {
+	k := routeKey{realm, appID}
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	if len(hosts) == 0 {
+		delete(rt.routes, k)
+		return
+	}
+	for _, host := range hosts {
+		rt.routes[k] = removeRoute(rt.routes[k], host)
+	}
+	if len(rt.routes[k]) == 0 {
+		delete(rt.routes, k)
+	}
+}
+
+func removeRoute(routes []Route, host datatype.DiameterIdentity) []Route {
+	for i, r := range routes {
+		if r.Host == host {
+			return append(routes[:i:i], routes[i+1:]...)
+		}
+	}
+	return routes
+}
+
+// Routes returns the routes to the realm for the application appID,
+// sorted by priority.
+func (rt *RoutingTable) Routes(realm datatype.DiameterIdentity, appID uint32) []Route {
+	rt.mu.RLock()
+	defer rt.mu.RUnlock()
+	for _, name := range realmPatterns(realm) {
+		if routes, ok := rt.routes[routeKey{name, appID}]; ok {
+			return append([]Route(nil), routes...)
+		}
+		if routes, ok := rt.routes[routeKey{name, AnyApplication}]; ok {
+			return append([]Route(nil), routes...)
+		}
+	}
+	return nil
+}
+
+// realmPatterns returns the names that may match realm in the routing
+// table, from the most to the least specific.
+func realmPatterns(realm datatype.DiameterIdentity) []datatype.DiameterIdentity {
+	names := []datatype.DiameterIdentity{realm}
+	s := string(realm)
+	for i := strings.IndexByte(s, '.'); i >= 0; i = strings.IndexByte(s, '.') {
+		s = s[i+1:]
+		names = append(names, datatype.DiameterIdentity("*."+s))
+	}
+	return append(names, "*")
+}
+
+// NextHop returns the peer the requests to the realm for the application
+// appID must be sent to. The routes of the highest priority with an
+// available peer are selected, at random according to their weights,
+// scaled by the load of their peers if a load table is set.
+//
+// It returns ErrNoRoute if there is no route to the realm, or
+// ErrNoAvailablePeer if none of its peers are available.
+func (rt *RoutingTable) NextHop(realm datatype.DiameterIdentity, appID uint32, available func(host datatype.DiameterIdentity) bool) (datatype.DiameterIdentity, error) {
+	routes := rt.Routes(realm, appID)
+	if len(routes) == 0 {
+		return "", ErrNoRoute
+	}
+	var candidates []Route
+	for _, r := range routes {
+		if len(candidates) > 0 && r.Priority != candidates[0].Priority {
+			break
+		}
+		if available == nil || available(r.Host) {
+			candidates = append(candidates, r)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", ErrNoAvailablePeer
+	}
+	rt.mu.RLock()
+	loads := rt.load
+	rt.mu.RUnlock()
+	weights := make([]int, len(candidates))
+	var total int
+	for i, r := range candidates {
+		weights[i] = routeWeight(r, loads)
+		total += weights[i]
+	}
+	n := rand.Intn(total)
+	for i, r := range candidates {
+		if n -= weights[i]; n < 0 {
+			return r.Host, nil
+		}
+	}
+	return candidates[len(candidates)-1].Host, nil
+}
+
+func routeWeight(r Route, loads *load.Table) int {
+	w := r.Weight
+	if w <= 0 {
+		w = 1
+	}
+	if loads != nil {
+		w = loads.Weight(r.Host, w)
+	}
+	return w
+}
+
+// Destination returns the Destination-Host and Destination-Realm of the
+// request m, if present.
+func Destination(m *diam.Message) (host, realm datatype.DiameterIdentity) {
+	for _, a := range m.AVP {
+		switch a.Code {
+		case avp.DestinationHost:
+			host, _ = a.Data.(datatype.DiameterIdentity)
+		case avp.DestinationRealm:
+			realm, _ = a.Data.(datatype.DiameterIdentity)
+		}
+	}
+	return host, realm
+}
@@ -0,0 +1,153 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"testing"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+	"github.com/omnicate/go-diameter/v4/diam/load"
+)
+
+func TestRoutingTable_Wildcard(t *testing.T) {
+	rt := NewRoutingTable()
+	rt.Add("*", AnyApplication, Route{Host: "default"})
+	rt.Add("*.example.com", AnyApplication, Route{Host: "example"})
+	rt.Add("a.example.com", 4, Route{Host: "a4"})
+	rt.Add("a.example.com", AnyApplication, Route{Host: "a"})
+	for _, tc := range []struct {
+		realm datatype.DiameterIdentity
+		appID uint32
+		host  datatype.DiameterIdentity
+	}{
+		{"a.example.com", 4, "a4"},
+		{"a.example.com", 3, "a"},
+		{"b.example.com", 4, "example"},
+		{"c.b.example.com", 4, "example"},
+		{"example.org", 4, "default"},
+	} {
+		host, err := rt.NextHop(tc.realm, tc.appID, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if host != tc.host {
+			t.Fatalf("Unexpected next hop for %s/%d. Want %s, have %s", tc.realm, tc.appID, tc.host, host)
+		}
+	}
+	rt.Remove("*", AnyApplication)
+	if _, err := rt.NextHop("example.org", 4, nil); err != ErrNoRoute {
+		t.Fatalf("Unexpected error. Want %v, have %v", ErrNoRoute, err)
+	}
+}
+
+func TestRoutingTable_Priority(t *testing.T) {
+	rt := NewRoutingTable()
+	rt.Add("example.com", AnyApplication,
+		Route{Host: "backup", Priority: 2},
+		Route{Host: "a", Priority: 1, Weight: 3},
+		Route{Host: "b", Priority: 1, Weight: 1},
+	)
+	count := make(map[datatype.DiameterIdentity]int)
+	for i := 0; i < 4000; i++ {
+		host, err := rt.NextHop("example.com", 4, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		count[host]++
+	}
+	if count["backup"] > 0 {
+		t.Fatal("Lower priority route was selected")
+	}
+	if count["a"] < 2500 || count["a"] > 3500 {
+		t.Fatalf("Unexpected distribution of weighted routes: %v", count)
+	}
+	host, err := rt.NextHop("example.com", 4, func(h datatype.DiameterIdentity) bool {
+		return h == "backup"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if host != "backup" {
+		t.Fatalf("Unexpected next hop. Want backup, have %s", host)
+	}
+	rt.Remove("example.com", AnyApplication, "a", "b")
+	if _, err = rt.NextHop("example.com", 4, func(datatype.DiameterIdentity) bool { return false }); err != ErrNoAvailablePeer {
+		t.Fatalf("Unexpected error. Want %v, have %v", ErrNoAvailablePeer, err)
+	}
+}
+
+func TestClient_Send_Routed(t *testing.T) {
+	srv := diamtest.NewServer(newACRServer(serverSettings), dict.Default)
+	defer srv.Close()
+	cli := newPeerClient(New(clientSettings), "")
+	cli.Routes = NewRoutingTable()
+	if _, err := cli.Send(nil, newACR(cli)); err != ErrNoRoute {
+		t.Fatalf("Unexpected error. Want %v, have %v", ErrNoRoute, err)
+	}
+	cli.Routes.Add(serverSettings.OriginRealm, 3, Route{Host: serverSettings.OriginHost})
+	if _, err := cli.Send(nil, newACR(cli)); err != ErrNoAvailablePeer {
+		t.Fatalf("Unexpected error. Want %v, have %v", ErrNoAvailablePeer, err)
+	}
+	c, err := cli.Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if _, err := cli.Send(nil, newACR(cli)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRoutingTable_Load(t *testing.T) {
+	loads := load.NewTable()
+	rt := NewRoutingTable()
+	rt.SetLoadTable(loads)
+	rt.Add("example.com", AnyApplication, Route{Host: "a"}, Route{Host: "b"})
+	loads.Set("b", 3*(load.MaxValue+1)/4)
+	count := make(map[datatype.DiameterIdentity]int)
+	for i := 0; i < 4000; i++ {
+		host, err := rt.NextHop("example.com", 4, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		count[host]++
+	}
+	if count["a"] < 2800 || count["a"] > 3600 {
+		t.Fatalf("Unexpected distribution of loaded routes: %v", count)
+	}
+}
+
+func TestClient_Send_Load(t *testing.T) {
+	srvSM := New(serverSettings)
+	srvSM.ReportLoad(1000)
+	srvSM.HandleFunc("ACR", func(c diam.Conn, m *diam.Message) {
+		a := m.Answer(diam.Success)
+		a.NewAVP(avp.OriginHost, avp.Mbit, 0, serverSettings.OriginHost)
+		a.NewAVP(avp.OriginRealm, avp.Mbit, 0, serverSettings.OriginRealm)
+		srvSM.LoadReporter().Answer(a)
+		a.WriteTo(c)
+	})
+	srv := diamtest.NewServer(srvSM, dict.Default)
+	defer srv.Close()
+
+	cli := newPeerClient(New(clientSettings), "")
+	loads := load.NewTable()
+	cli.Handler.SetLoadTable(loads)
+	c, err := cli.Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if _, err = cli.Send(c, newACR(cli)); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := loads.Value(serverSettings.OriginHost); !ok || v != 1000 {
+		t.Fatalf("Unexpected load of the server. Want 1000, have %d", v)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/sm/smparser"
+)
+
+// ErrNoPeerCertificate is returned by VerifyPeerHostname when the peer
+// did not present a certificate.
+var ErrNoPeerCertificate = errors.New("peer has no certificate")
+
+// VerifyPeerHostname is a Settings.VerifyPeer function that verifies
+// that the certificate of the peer is valid for its Origin-Host.
+func VerifyPeerHostname(host datatype.DiameterIdentity, state *tls.ConnectionState) error {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return ErrNoPeerCertificate
+	}
+	return state.PeerCertificates[0].VerifyHostname(string(host))
+}
+
+// inbandSecurity returns the Inband-Security-Id values supported on c,
+// in order of preference.
+func (sm *StateMachine) inbandSecurity(c diam.Conn) []uint32 {
+	switch {
+	case sm.cfg.InbandTLS == nil || c.TLS() != nil:
+		return []uint32{smparser.NoInbandSecurity}
+	case sm.cfg.RequireTLS:
+		return []uint32{smparser.InbandSecurityTLS}
+	}
+	return []uint32{smparser.InbandSecurityTLS, smparser.NoInbandSecurity}
+}
+
+// upgradeTLS upgrades c to TLS when the Inband-Security-Id security was
+// negotiated, after calling before, and then verifies the peer host.
+// Without upgrade, it only calls before: the peer must be verified with
+// verifyPeer beforehand.
+func (sm *StateMachine) upgradeTLS(c diam.Conn, host datatype.DiameterIdentity, security uint32, client bool, before func() error) error {
+	if security != smparser.InbandSecurityTLS {
+		if before != nil {
+			return before()
+		}
+		return nil
+	}
+	u, ok := c.(diam.TLSUpgrader)
+	if !ok {
+		return diam.ErrTLSUnsupported
+	}
+	if err := u.UpgradeTLS(sm.cfg.InbandTLS, client, before); err != nil {
+		return fmt.Errorf("TLS upgrade failed: %v", err)
+	}
+	return sm.verifyPeer(c, host)
+}
+
+// verifyPeer verifies the peer host with the TLS state of c, if
+// VerifyPeer is set.
+func (sm *StateMachine) verifyPeer(c diam.Conn, host datatype.DiameterIdentity) error {
+	if sm.cfg.VerifyPeer == nil {
+		return nil
+	}
+	if err := sm.cfg.VerifyPeer(host, c.TLS()); err != nil {
+		return fmt.Errorf("peer %s verification failed: %v", host, err)
+	}
+	return nil
+}
+
+// checkSecurity returns ErrNoCommonSecurity when the Inband-Security-Id
+// of a CEA received on c was not offered in the CER.
+func (sm *StateMachine) checkSecurity(c diam.Conn, security uint32) error {
+	for _, id := range sm.inbandSecurity(c) {
+		if id == security {
+			return nil
+		}
+	}
+	return smparser.ErrNoCommonSecurity
+}
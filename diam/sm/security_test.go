@@ -0,0 +1,155 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+	"github.com/omnicate/go-diameter/v4/diam/sm/smparser"
+)
+
+// newTLSConfigs returns the TLS configurations of a server and a client
+// that authenticate each other with a self-signed certificate valid for
+// the Origin-Host of serverSettings and clientSettings.
+func newTLSConfigs(t *testing.T) (srv, cli *tls.Config) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "go-diameter"},
+		DNSNames:              []string{string(serverSettings.OriginHost), string(clientSettings.OriginHost)},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+	cert := tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+	srv = &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+	}
+	cli = &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+		ServerName:   string(serverSettings.OriginHost),
+	}
+	return srv, cli
+}
+
+func TestClient_InbandTLS(t *testing.T) {
+	srvTLS, cliTLS := newTLSConfigs(t)
+	srvCfg := *serverSettings
+	srvCfg.InbandTLS = srvTLS
+	srvCfg.VerifyPeer = VerifyPeerHostname
+	srvSM := New(&srvCfg)
+	handled := make(chan *tls.ConnectionState, 1)
+	srvSM.HandleFunc("ACR", func(c diam.Conn, m *diam.Message) {
+		handled <- c.TLS()
+		a := m.Answer(diam.Success)
+		a.NewAVP(avp.OriginHost, avp.Mbit, 0, srvCfg.OriginHost)
+		a.NewAVP(avp.OriginRealm, avp.Mbit, 0, srvCfg.OriginRealm)
+		a.WriteTo(c)
+	})
+	srv := diamtest.NewServer(srvSM, dict.Default)
+	defer srv.Close()
+
+	cliCfg := *clientSettings
+	cliCfg.InbandTLS = cliTLS
+	cliCfg.RequireTLS = true
+	cliCfg.VerifyPeer = VerifyPeerHostname
+	cli := newPeerClient(New(&cliCfg), "")
+	c, err := cli.Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if c.TLS() == nil {
+		t.Fatal("Client connection was not upgraded to TLS")
+	}
+	if _, err = cli.Send(c, newACR(cli)); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case state := <-handled:
+		if state == nil || len(state.PeerCertificates) == 0 {
+			t.Fatalf("Unexpected server TLS state: %+v", state)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for ACR")
+	}
+}
+
+func TestClient_InbandTLS_Unsupported(t *testing.T) {
+	srv := diamtest.NewServer(New(serverSettings), dict.Default)
+	defer srv.Close()
+
+	_, cliTLS := newTLSConfigs(t)
+	cliCfg := *clientSettings
+	cliCfg.InbandTLS = cliTLS
+	cli := newPeerClient(New(&cliCfg), "")
+	c, err := cli.Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if c.TLS() != nil {
+		t.Fatal("Client connection was upgraded to TLS")
+	}
+
+	cliCfg.RequireTLS = true
+	cli = newPeerClient(New(&cliCfg), "")
+	c, err = cli.Dial(srv.Addr)
+	if err == nil {
+		c.Close()
+		t.Fatal("Handshake without TLS succeeded")
+	}
+	if fe, ok := err.(*smparser.ErrFailedResultCode); !ok || fe.ResultCode != diam.NoCommonSecurity {
+		t.Fatal("Unexpected error:", err)
+	}
+}
+
+func TestServer_VerifyPeer_ErrorCEA(t *testing.T) {
+	srvCfg := *serverSettings
+	srvCfg.VerifyPeer = VerifyPeerHostname
+	srv := diamtest.NewServer(New(&srvCfg), dict.Default)
+	defer srv.Close()
+
+	cli := newPeerClient(New(clientSettings), "")
+	_, err := cli.Dial(srv.Addr)
+	e, ok := err.(*smparser.ErrFailedResultCode)
+	if !ok {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if e.ResultCode != diam.UnableToComply {
+		t.Fatalf("Unexpected Result-Code. Want %d, have %d", diam.UnableToComply, e.ResultCode)
+	}
+}
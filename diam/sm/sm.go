@@ -5,12 +5,19 @@
 package sm
 
 import (
+	"crypto/tls"
 	"fmt"
+	"sync/atomic"
+	"time"
 
 	"github.com/omnicate/go-diameter/v4/diam"
 	"github.com/omnicate/go-diameter/v4/diam/datatype"
 	"github.com/omnicate/go-diameter/v4/diam/dict"
+	"github.com/omnicate/go-diameter/v4/diam/doic"
+	"github.com/omnicate/go-diameter/v4/diam/load"
+	"github.com/omnicate/go-diameter/v4/diam/metrics"
 	"github.com/omnicate/go-diameter/v4/diam/sm/smpeer"
+	"github.com/omnicate/go-diameter/v4/diam/tracing"
 )
 
 // SupportedApp holds properties of each locally supported App
@@ -24,7 +31,7 @@ type SupportedApp struct {
 func PrepareSupportedApps(d *dict.Parser) []*SupportedApp {
 	locallySupportedApps := []*SupportedApp{}
 	for _, app := range d.Apps() {
-		if app.ID == 0 {
+		if app.ID == 0 || app.ID == diam.CAPABILITIES_UPDATE_APP_ID {
 			continue
 		}
 		addApp := new(SupportedApp)
@@ -51,19 +58,28 @@ type Settings struct {
 	// to dict.Default.
 	Dict *dict.Parser
 
-	// OriginStateID is optional for clients, and not added if unset.
-	//
-	// On servers it has no effect because CEA will contain the
-	// same value from CER, if present.
-	//
-	// May be set to datatype.Unsigned32(time.Now().Unix()).
+	// OriginStateID is sent in CER, CEA, DWR and DWA so that peers
+	// detect restarts, and should increase each time the node starts.
+	// Will default to the time the process started.
 	OriginStateID datatype.Unsigned32
 
 	// FirmwareRevision is optional, and not added if unset.
 	FirmwareRevision datatype.Unsigned32
 
+	// SupportedVendorIDs are advertised in Supported-Vendor-Id AVPs of
+	// the CER and CEA, in addition to the vendors of the applications in
+	// Dict, for vendors whose AVPs are supported outside of their own
+	// applications.
+	SupportedVendorIDs []uint32
+
+	// CapabilitiesAVPs are added to the CER, CEA and CUR sent by the
+	// state machine, for example vendor-specific AVPs required by peers.
+	// They must not duplicate the AVPs added from the other settings.
+	CapabilitiesAVPs []*diam.AVP
+
 	// HostIPAddress is optional for both clients and servers, when not set local
-	// host IP address is used.
+	// host IP address is used: all the local addresses of the association
+	// for SCTP.
 	//
 	// This property may be set when the IP address of the host sending/receiving
 	// the request is different from the configured allowed IPs in the other end,
@@ -73,6 +89,123 @@ type Settings struct {
 	//
 	// Deprecated: HostIPAddress is depreciated, use HostIPAddresses instead
 	HostIPAddress datatype.Address
+
+	// SingleConnection enforces a single connection per peer identity,
+	// as required by RFC 6733 section 5.6. Connections from peers that
+	// are already connected are rejected, and simultaneous connections
+	// are resolved by the election process of section 5.6.4.
+	//
+	// When unset, peers may open several connections, and the peer
+	// state reflects the first one that is still open.
+	SingleConnection bool
+
+	// Relay advertises the Relay application (0xffffffff) in CEAs
+	// instead of the applications in Dict, as required for relay agents.
+	// See RFC 6733 section 2.8.1 for details.
+	Relay bool
+
+	// CapabilitiesUpdate advertises the Capabilities Update application
+	// (10) in CERs and CEAs, and handles the Capabilities-Update-Request
+	// messages of peers that advertise it too, which update the
+	// applications of their smpeer.Metadata. See RFC 6737 and
+	// Client.UpdateCapabilities for details.
+	CapabilitiesUpdate bool
+
+	// Metrics collects metrics of the connections and messages of the
+	// state machine when set. See the metrics sub-package for details.
+	Metrics *metrics.Collector
+
+	// RateLimiter controls the admission of the requests received from
+	// peers when set: requests are handled, deferred, or rejected with
+	// DIAMETER_TOO_BUSY, and counted by Metrics. See TokenBucket.
+	RateLimiter RateLimiter
+
+	// DisconnectPolicy configures the Disconnect-Peer-Requests sent and
+	// received by the state machine: the cause of those sent when the
+	// server shuts down, and the delay before reconnecting to the peers
+	// that disconnect. See DisconnectPolicy.
+	DisconnectPolicy DisconnectPolicy
+
+	// Tracer traces the requests sent by Client.Send and the requests
+	// handled by the state machine when set. See the tracing sub-package
+	// for details.
+	Tracer tracing.Tracer
+
+	// Logger logs the connections of the state machine, instead of
+	// diam.DefaultLogger, when set. Messages sent and received are
+	// logged at diam.LevelDebug.
+	Logger diam.Logger
+
+	// InbandTLS enables the negotiation of TLS with the Inband-Security-Id
+	// AVP of CER/CEA, after which the connection is upgraded to TLS with
+	// this configuration: clients are TLS clients and servers are TLS
+	// servers. Servers authenticate clients when ClientAuth is set to
+	// tls.RequireAndVerifyClientCert. See RFC 6733 sections 2.2 and 6.10.
+	//
+	// It has no effect on connections that already use TLS, as those
+	// of DialTLS or ListenAndServeTLS.
+	InbandTLS *tls.Config
+
+	// RequireTLS rejects the peers that do not negotiate TLS when
+	// InbandTLS is set, instead of continuing without it.
+	RequireTLS bool
+
+	// Peers overrides the settings, such as the Host-IP-Address and
+	// the applications advertised to peers, for the peers with the
+	// Origin-Host they are keyed by. See PeerConfig.
+	Peers map[datatype.DiameterIdentity]*PeerConfig
+
+	// DuplicateCache answers the retransmissions of requests already
+	// answered with their cached answer when set, instead of handling
+	// them again. See DuplicateCache.
+	DuplicateCache *DuplicateCache
+
+	// ValidateRequests validates the requests of the handlers
+	// registered with HandleAnswer against the dictionary, and answers
+	// those that fail with a protocol error answer.
+	ValidateRequests bool
+
+	// RejectUnsupportedAVPs answers the requests received with AVPs
+	// that have the M bit set and are not in the dictionary with
+	// DIAMETER_AVP_UNSUPPORTED, as required by RFC 6733, instead of
+	// passing them to the handlers. See diam.Message.CheckUnsupportedAVPs.
+	RejectUnsupportedAVPs bool
+
+	// RecoverPanics recovers the panics of the handlers of the state
+	// machine, answering their requests with DIAMETER_UNABLE_TO_COMPLY
+	// and keeping their connections open. See diam.Recover.
+	RecoverPanics bool
+
+	// CERValidation enables additional checks of the CERs received,
+	// such as allowlists of peers, when set. See CERValidation.
+	CERValidation *CERValidation
+
+	// VerifyPeer is called during the capabilities exchange with the
+	// Origin-Host of the peer and the TLS state of the connection, nil
+	// when not using TLS. Servers call it before sending the CEA, and
+	// answer CERs with an error CEA when it returns an error. When the
+	// connection is upgraded to TLS after the CEA, as negotiated with
+	// Inband-Security-Id, it is called after the upgrade instead, and
+	// the connection is closed when it returns an error, as it is by
+	// clients. See VerifyPeerHostname.
+	VerifyPeer func(host datatype.DiameterIdentity, state *tls.ConnectionState) error
+
+	// Cluster shares the requests sent by Client.Send with the other
+	// instances of a cluster when set, so that answers received by
+	// another instance are forwarded to the one that sent their
+	// request. See Cluster.
+	Cluster *Cluster
+}
+
+// NewRequestBuilder returns a diam.RequestBuilder of the command cmd of
+// the application appID in the dictionaries of the settings, with their
+// Origin-Host and Origin-Realm.
+func (s *Settings) NewRequestBuilder(cmd, appID uint32) *diam.RequestBuilder {
+	d := s.Dict
+	if d == nil {
+		d = dict.Default
+	}
+	return diam.NewRequestBuilder(cmd, appID, d).Origin(s.OriginHost, s.OriginRealm)
 }
 
 var (
@@ -91,6 +224,20 @@ type StateMachine struct {
 	mux           *diam.ServeMux
 	hsNotifyc     chan diam.Conn // handshake notifier
 	supportedApps []*SupportedApp
+	overloadCtl   atomic.Value    // *doic.Controller of reacting nodes
+	overloadRep   *doic.Reporter  // overload state of reporting nodes
+	loadRep       *load.Reporter  // load reported in answers
+	loadTable     atomic.Value    // *load.Table of peers
+	peers         *peerTable      // peer state machines
+	pending       pendingRequests // requests sent by Client.Send
+	retransmitFn  atomic.Value    // RetransmissionFunc
+	admissionFn   atomic.Value    // CERAdmissionFunc
+	redirects     redirectCache   // redirects received by Client.Send
+	spans         serverSpans     // spans of requests being handled
+	disconnects   disconnects     // DPRs received
+	events        eventHub        // connection event subscriptions
+	watchdogs     watchdogs       // peers that failed the watchdog
+	originStates  originStates    // Origin-State-Id of peers
 }
 
 // New creates and initializes a new StateMachine for clients or servers.
@@ -98,6 +245,9 @@ func New(settings *Settings) *StateMachine {
 	if settings.Dict == nil {
 		settings.Dict = dict.Default
 	}
+	if settings.OriginStateID == 0 {
+		settings.OriginStateID = bootStateID
+	}
 	if len(settings.HostIPAddresses) == 0 && len(settings.HostIPAddress) > 0 {
 		settings.HostIPAddresses = []datatype.Address{settings.HostIPAddress}
 	}
@@ -106,11 +256,30 @@ func New(settings *Settings) *StateMachine {
 		mux:           diam.NewServeMux(),
 		hsNotifyc:     make(chan diam.Conn),
 		supportedApps: PrepareSupportedApps(settings.Dict),
+		overloadRep:   doic.NewReporter(),
+		loadRep:       load.NewReporter(settings.OriginHost),
+	}
+	if settings.Relay {
+		sm.supportedApps = []*SupportedApp{{ID: 0xffffffff, AppType: "auth"}}
+	}
+	if settings.CapabilitiesUpdate {
+		sm.supportedApps = append(sm.supportedApps, &SupportedApp{
+			ID:      diam.CAPABILITIES_UPDATE_APP_ID,
+			AppType: "auth",
+		})
+	}
+	sm.peers = newPeerTable(sm)
+	if settings.RecoverPanics {
+		sm.mux.Use(diam.Recover(settings.OriginHost, settings.OriginRealm, settings.Logger))
 	}
 	sm.mux.Handle("CER", handleCER(sm))
 	sm.mux.Handle("DWR", handshakeOK(handleDWR(sm)))
+	sm.mux.Handle("DPR", handshakeOK(handleDPR(sm)))
 	sm.mux.HandleIdx(baseCERIdx, handleCER(sm))
 	sm.mux.HandleIdx(baseDWRIdx, handleDWR(sm))
+	if settings.CapabilitiesUpdate {
+		sm.mux.Handle("CUR", handshakeOK(handleCUR(sm)))
+	}
 	return sm
 }
 
@@ -121,9 +290,126 @@ func (sm *StateMachine) Settings() *Settings {
 
 // ServeDIAM implements the diam.Handler interface.
 func (sm *StateMachine) ServeDIAM(c diam.Conn, m *diam.Message) {
+	if ctl := sm.OverloadController(); ctl != nil {
+		if err := ctl.Process(m); err != nil {
+			sm.Error(&diam.ErrorReport{Conn: c, Message: m, Error: err})
+		}
+	}
+	if t := sm.LoadTable(); t != nil {
+		if err := t.Update(m); err != nil {
+			sm.Error(&diam.ErrorReport{Conn: c, Message: m, Error: err})
+		}
+	}
+	if w := connWatchdog(c); w != nil && (m.Header.CommandCode != diam.DeviceWatchdog || m.Header.CommandFlags&diam.RequestFlag != 0) {
+		w.received()
+	}
+	if m.Header.CommandFlags&diam.RequestFlag == 0 {
+		if sm.pending.deliver(m) {
+			return
+		}
+		if sm.cfg.Cluster != nil && sm.forward(c, m) {
+			return
+		}
+	} else if sm.answerDuplicate(c, m) {
+		return
+	} else if m.Header.CommandFlags&diam.RetransmittedFlag != 0 {
+		if f, _ := sm.retransmitFn.Load().(RetransmissionFunc); f != nil && !f(c, m) {
+			return
+		}
+	}
+	if m.Header.CommandFlags&diam.RequestFlag != 0 && (sm.rejectDisconnecting(c, m) || sm.rejectUnsupported(c, m) || !sm.admit(c, m)) {
+		return
+	}
+	if t := sm.cfg.Tracer; t != nil && m.Header.CommandFlags&diam.RequestFlag != 0 {
+		sm.spans.start(t, c, m)
+	}
 	sm.mux.ServeDIAM(c, m)
 }
 
+// HandleRetransmission registers the function called for requests
+// received with the T flag set, which allows detecting duplicates.
+func (sm *StateMachine) HandleRetransmission(f RetransmissionFunc) {
+	sm.retransmitFn.Store(f)
+}
+
+// SetOverloadController sets the DOIC controller that processes overload
+// reports received in answers. See the doic sub-package for details.
+func (sm *StateMachine) SetOverloadController(ctl *doic.Controller) {
+	sm.overloadCtl.Store(ctl)
+}
+
+// OverloadController returns the DOIC controller of the state machine,
+// or nil if overload control is disabled.
+func (sm *StateMachine) OverloadController() *doic.Controller {
+	ctl, _ := sm.overloadCtl.Load().(*doic.Controller)
+	return ctl
+}
+
+// OverloadReporter returns the DOIC reporter used to add overload
+// reports to answers sent by this node.
+func (sm *StateMachine) OverloadReporter() *doic.Reporter {
+	return sm.overloadRep
+}
+
+// ReportOverload publishes an overload report asking peers to reduce
+// their traffic towards this node by reduction percent during validity.
+//
+// Reports are added to answers by OverloadReporter().Answer.
+func (sm *StateMachine) ReportOverload(typ doic.ReportType, reduction uint32, validity time.Duration) {
+	sm.overloadRep.SetReport(typ, reduction, validity)
+}
+
+// ClearOverload ends the overload condition published by ReportOverload.
+func (sm *StateMachine) ClearOverload() {
+	sm.overloadRep.Clear()
+}
+
+// SetLoadTable sets the table of peer loads that is updated with the
+// Load AVPs of the answers received. See the load sub-package for
+// details.
+func (sm *StateMachine) SetLoadTable(t *load.Table) {
+	sm.loadTable.Store(t)
+}
+
+// LoadTable returns the table of peer loads of the state machine, or nil
+// if unset.
+func (sm *StateMachine) LoadTable() *load.Table {
+	t, _ := sm.loadTable.Load().(*load.Table)
+	return t
+}
+
+// LoadReporter returns the reporter used to add the load of this node
+// to the answers it sends.
+func (sm *StateMachine) LoadReporter() *load.Reporter {
+	return sm.loadRep
+}
+
+// ReportLoad sets the load of this node, from 0 to load.MaxValue, added
+// to answers by LoadReporter().Answer.
+func (sm *StateMachine) ReportLoad(value uint64) {
+	sm.loadRep.SetValue(value)
+}
+
+// PeerState returns the state of the peer state machine of the given
+// peer identity. See RFC 6733 section 5.6 for details.
+func (sm *StateMachine) PeerState(host datatype.DiameterIdentity) PeerState {
+	state, _ := sm.peers.state(host)
+	return state
+}
+
+// PeerConn returns the connection to the given peer identity, or nil
+// if the peer is not open.
+func (sm *StateMachine) PeerConn(host datatype.DiameterIdentity) diam.Conn {
+	_, c := sm.peers.state(host)
+	return c
+}
+
+// Use wraps the handlers of the state machine, including those of the
+// base protocol, with the middlewares mws. See diam.ServeMux.Use.
+func (sm *StateMachine) Use(mws ...diam.Middleware) {
+	sm.mux.Use(mws...)
+}
+
 // Handle implements the diam.Handler interface.
 func (sm *StateMachine) Handle(cmd string, handler diam.Handler) {
 	sm.HandleFunc(cmd, handler.ServeDIAM)
@@ -140,6 +426,13 @@ func (sm *StateMachine) HandleIdx(cmd diam.CommandIndex, handler diam.Handler) {
 	}
 }
 
+// HandleApp registers the fallback handler of the messages of the
+// application appID received after the handshake that have no handler
+// registered for their command. See diam.ServeMux.HandleApp.
+func (sm *StateMachine) HandleApp(appID uint32, handler diam.Handler) {
+	sm.mux.HandleApp(appID, handshakeOK(handler.ServeDIAM))
+}
+
 // HandleFunc implements the diam.Handler interface.
 func (sm *StateMachine) HandleFunc(cmd string, handler diam.HandlerFunc) {
 	switch cmd {
@@ -162,6 +455,77 @@ func (sm *StateMachine) ErrorReports() <-chan *diam.ErrorReport {
 	return sm.mux.ErrorReports()
 }
 
+// Logger implements the diam.LoggerProvider interface.
+func (sm *StateMachine) Logger() diam.Logger {
+	return sm.cfg.Logger
+}
+
+// ConnOpened implements the diam.Observer interface.
+func (sm *StateMachine) ConnOpened(c diam.Conn) {
+	if mc := sm.cfg.Metrics; mc != nil {
+		mc.ConnOpened(c)
+	}
+}
+
+// ConnClosed implements the diam.Observer interface.
+func (sm *StateMachine) ConnClosed(c diam.Conn) {
+	if mc := sm.cfg.Metrics; mc != nil {
+		mc.ConnClosed(c)
+	}
+	sm.spans.closeConn(c)
+	if _, ok := smpeer.FromContext(c.Context()); ok {
+		sm.emitConn(PeerDown, c, closeReason(c), 0)
+	}
+}
+
+// MessageReceived implements the diam.Observer interface.
+func (sm *StateMachine) MessageReceived(c diam.Conn, m *diam.Message) {
+	if mc := sm.cfg.Metrics; mc != nil {
+		mc.MessageReceived(c, m)
+	}
+}
+
+// MessageSent implements the diam.Observer interface.
+func (sm *StateMachine) MessageSent(c diam.Conn, m *diam.Message) {
+	if mc := sm.cfg.Metrics; mc != nil {
+		mc.MessageSent(c, m)
+	}
+	if m.Header.CommandFlags&diam.RequestFlag == 0 {
+		sm.spans.end(c, m)
+		if dc := sm.cfg.DuplicateCache; dc != nil {
+			dc.answered(c, m)
+		}
+	}
+}
+
+// PrepareMessage implements the diam.Preparer interface. It prepares the
+// requests of applications written to connections of the state machine
+// with its overload controller, if any, which adds OC-Supported-Features
+// to them and throttles those sent to overloaded peers. Base protocol
+// requests, such as CER and DWR, are not subject to overload control.
+func (sm *StateMachine) PrepareMessage(c diam.Conn, m *diam.Message) error {
+	if m.Header.ApplicationID == 0 {
+		return nil
+	}
+	if ctl := sm.OverloadController(); ctl != nil {
+		return ctl.Prepare(m)
+	}
+	return nil
+}
+
+// notifyHandshake notifies about the peer host passing the handshake on
+// the connection c.
+func (sm *StateMachine) notifyHandshake(c diam.Conn, host datatype.DiameterIdentity) {
+	if mc := sm.cfg.Metrics; mc != nil {
+		mc.PeerConnected(host)
+	}
+	sm.emitConn(PeerUp, c, nil, 0)
+	select {
+	case sm.hsNotifyc <- c:
+	default:
+	}
+}
+
 // HandshakeNotify implements the HandshakeNotifier interface.
 func (sm *StateMachine) HandshakeNotify() <-chan diam.Conn {
 	return sm.hsNotifyc
@@ -149,3 +149,13 @@ func testStateMachine(t *testing.T, network string) {
 		t.Fatal("No DWR message received")
 	}
 }
+
+func TestSettingsNewRequestBuilder(t *testing.T) {
+	m, err := serverSettings.NewRequestBuilder(diam.DeviceWatchdog, 0).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a, err := m.FindAVP(avp.OriginHost, 0); err != nil || a.Data != serverSettings.OriginHost {
+		t.Fatalf("Unexpected Origin-Host: %v, %v", a, err)
+	}
+}
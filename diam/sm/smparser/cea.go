@@ -17,10 +17,16 @@ type CEA struct {
 	ResultCode                  uint32                    `avp:"Result-Code"`
 	OriginHost                  datatype.DiameterIdentity `avp:"Origin-Host"`
 	OriginRealm                 datatype.DiameterIdentity `avp:"Origin-Realm"`
+	HostIPAddress               []*diam.AVP               `avp:"Host-IP-Address"`
+	VendorID                    uint32                    `avp:"Vendor-Id"`
+	ProductName                 datatype.UTF8String       `avp:"Product-Name"`
 	OriginStateID               uint32                    `avp:"Origin-State-Id"`
+	SupportedVendorID           []*diam.AVP               `avp:"Supported-Vendor-Id"`
+	InbandSecurityID            uint32                    `avp:"Inband-Security-Id"`
 	AcctApplicationID           []*diam.AVP               `avp:"Acct-Application-Id"`
 	AuthApplicationID           []*diam.AVP               `avp:"Auth-Application-Id"`
 	VendorSpecificApplicationID []*diam.AVP               `avp:"Vendor-Specific-Application-Id"`
+	FirmwareRevision            uint32                    `avp:"Firmware-Revision"`
 	FailedAVP                   []*diam.AVP               `avp:"Failed-AVP"`
 	ErrorMessage                string                    `avp:"Error-Message"`
 	appID                       []uint32                  // List of supported application IDs.
@@ -78,3 +84,13 @@ func (cea *CEA) sanityCheck() error {
 func (cea *CEA) Applications() []uint32 {
 	return cea.appID
 }
+
+// SupportedVendors returns the values of the Supported-Vendor-Id AVPs.
+func (cea *CEA) SupportedVendors() []uint32 {
+	return unsigned32s(cea.SupportedVendorID)
+}
+
+// HostIPAddresses returns the values of the Host-IP-Address AVPs.
+func (cea *CEA) HostIPAddresses() []datatype.Address {
+	return addresses(cea.HostIPAddress)
+}
@@ -14,14 +14,26 @@ import (
 type CER struct {
 	OriginHost                  datatype.DiameterIdentity `avp:"Origin-Host"`
 	OriginRealm                 datatype.DiameterIdentity `avp:"Origin-Realm"`
+	HostIPAddress               []*diam.AVP               `avp:"Host-IP-Address"`
+	VendorID                    uint32                    `avp:"Vendor-Id"`
+	ProductName                 datatype.UTF8String       `avp:"Product-Name"`
 	OriginStateID               *diam.AVP                 `avp:"Origin-State-Id"`
-	InbandSecurityID            *diam.AVP                 `avp:"Inband-Security-Id"`
+	SupportedVendorID           []*diam.AVP               `avp:"Supported-Vendor-Id"`
+	InbandSecurityID            []*diam.AVP               `avp:"Inband-Security-Id"`
 	AcctApplicationID           []*diam.AVP               `avp:"Acct-Application-Id"`
 	AuthApplicationID           []*diam.AVP               `avp:"Auth-Application-Id"`
 	VendorSpecificApplicationID []*diam.AVP               `avp:"Vendor-Specific-Application-Id"`
+	FirmwareRevision            uint32                    `avp:"Firmware-Revision"`
 	appID                       []uint32                  // List of supported application IDs.
+	security                    uint32                    // Selected Inband-Security-Id.
 }
 
+// Values of the Inband-Security-Id AVP. See RFC 6733 section 6.10.
+const (
+	NoInbandSecurity  = 0
+	InbandSecurityTLS = 1
+)
+
 // Parse parses and validates the given message, and returns nil when
 // all AVPs are ok, and all accounting or authentication applications
 // in the CER match the applications in our dictionary. If one or more
@@ -29,18 +41,24 @@ type CER struct {
 // error. If all mandatory AVPs are present but no common application
 // is found, then it returns the failedAVP (with the application that
 // we don't support in our dictionary) and an error. Another cause
-// for error is the presence of Inband Security, we don't support that.
+// for error is a CER that requires Inband Security, see ParseSecurity.
 func (cer *CER) Parse(m *diam.Message, localRole Role) (failedAVP *diam.AVP, err error) {
+	return cer.ParseSecurity(m, localRole, NoInbandSecurity)
+}
+
+// ParseSecurity is like Parse, but also selects the first of the given
+// Inband-Security-Id values that is offered by the CER. A CER without
+// Inband-Security-Id offers NoInbandSecurity. If none of the values is
+// offered, it returns ErrNoCommonSecurity.
+func (cer *CER) ParseSecurity(m *diam.Message, localRole Role, security ...uint32) (failedAVP *diam.AVP, err error) {
 	if err = m.Unmarshal(cer); err != nil {
 		return nil, err
 	}
 	if err = cer.sanityCheck(); err != nil {
 		return nil, err
 	}
-	if cer.InbandSecurityID != nil {
-		if v := cer.InbandSecurityID.Data.(datatype.Unsigned32); v != 0 {
-			return nil, ErrNoCommonSecurity
-		}
+	if cer.security, err = cer.selectSecurity(security); err != nil {
+		return nil, err
 	}
 	app := &Application{
 		AcctApplicationID:           cer.AcctApplicationID,
@@ -54,6 +72,28 @@ func (cer *CER) Parse(m *diam.Message, localRole Role) (failedAVP *diam.AVP, err
 	return nil, nil
 }
 
+// selectSecurity returns the first of the local Inband-Security-Id
+// values offered by the CER.
+func (cer *CER) selectSecurity(local []uint32) (uint32, error) {
+	offered := []uint32{NoInbandSecurity}
+	if len(cer.InbandSecurityID) > 0 {
+		offered = offered[:0]
+		for _, a := range cer.InbandSecurityID {
+			if v, ok := a.Data.(datatype.Unsigned32); ok {
+				offered = append(offered, uint32(v))
+			}
+		}
+	}
+	for _, l := range local {
+		for _, o := range offered {
+			if l == o {
+				return l, nil
+			}
+		}
+	}
+	return 0, ErrNoCommonSecurity
+}
+
 // sanityCheck ensures mandatory AVPs are present.
 func (cer *CER) sanityCheck() error {
 	if len(cer.OriginHost) == 0 {
@@ -69,3 +109,45 @@ func (cer *CER) sanityCheck() error {
 func (cer *CER) Applications() []uint32 {
 	return cer.appID
 }
+
+// Security returns the Inband-Security-Id selected by ParseSecurity.
+func (cer *CER) Security() uint32 {
+	return cer.security
+}
+
+// StateID returns the value of the Origin-State-Id AVP, 0 if absent.
+func (cer *CER) StateID() uint32 {
+	return stateID(cer.OriginStateID)
+}
+
+// SupportedVendors returns the values of the Supported-Vendor-Id AVPs.
+func (cer *CER) SupportedVendors() []uint32 {
+	return unsigned32s(cer.SupportedVendorID)
+}
+
+// HostIPAddresses returns the values of the Host-IP-Address AVPs.
+func (cer *CER) HostIPAddresses() []datatype.Address {
+	return addresses(cer.HostIPAddress)
+}
+
+// unsigned32s returns the values of the Unsigned32 AVPs avps.
+func unsigned32s(avps []*diam.AVP) []uint32 {
+	var v []uint32
+	for _, a := range avps {
+		if id, ok := a.Data.(datatype.Unsigned32); ok {
+			v = append(v, uint32(id))
+		}
+	}
+	return v
+}
+
+// addresses returns the values of the Address AVPs avps.
+func addresses(avps []*diam.AVP) []datatype.Address {
+	var v []datatype.Address
+	for _, a := range avps {
+		if addr, ok := a.Data.(datatype.Address); ok {
+			v = append(v, addr)
+		}
+	}
+	return v
+}
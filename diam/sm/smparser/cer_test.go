@@ -88,6 +88,27 @@ func TestCER_NoCommonSecurity(t *testing.T) {
 	}
 }
 
+func TestCER_ParseSecurity(t *testing.T) {
+	m := diam.NewRequest(diam.CapabilitiesExchange, 0, dict.Default)
+	m.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("foobar"))
+	m.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity("test"))
+	m.NewAVP(avp.InbandSecurityID, avp.Mbit, 0, datatype.Unsigned32(NoInbandSecurity))
+	m.NewAVP(avp.InbandSecurityID, avp.Mbit, 0, datatype.Unsigned32(InbandSecurityTLS))
+	m.NewAVP(avp.AcctApplicationID, avp.Mbit, 0, datatype.Unsigned32(1001))
+	cer := new(CER)
+	_, err := cer.ParseSecurity(m, Server, InbandSecurityTLS, NoInbandSecurity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := cer.Security(); v != InbandSecurityTLS {
+		t.Fatalf("Unexpected Inband-Security-Id. Want %d, have %d", InbandSecurityTLS, v)
+	}
+	_, err = new(CER).ParseSecurity(m, Server, 2)
+	if err != ErrNoCommonSecurity {
+		t.Fatal("Unexpected error:", err)
+	}
+}
+
 func TestCER_AcctAppID(t *testing.T) {
 	m := diam.NewRequest(diam.CapabilitiesExchange, 0, dict.Default)
 	m.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("foobar"))
@@ -0,0 +1,99 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package smparser
+
+import (
+	"fmt"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// CUR is a Capabilities-Update-Request message.
+// See RFC 6737 section 3.1 for details.
+type CUR struct {
+	OriginHost                  datatype.DiameterIdentity `avp:"Origin-Host"`
+	OriginRealm                 datatype.DiameterIdentity `avp:"Origin-Realm"`
+	AcctApplicationID           []*diam.AVP               `avp:"Acct-Application-Id"`
+	AuthApplicationID           []*diam.AVP               `avp:"Auth-Application-Id"`
+	VendorSpecificApplicationID []*diam.AVP               `avp:"Vendor-Specific-Application-Id"`
+	appID                       []uint32                  // List of supported application IDs.
+}
+
+// Parse parses and validates the given message, like CER.Parse. The
+// applications of the CUR replace all those advertised by the peer
+// before, and the Capabilities Update application alone is not an
+// application in common.
+func (cur *CUR) Parse(m *diam.Message, localRole Role) (failedAVP *diam.AVP, err error) {
+	if err = m.Unmarshal(cur); err != nil {
+		return nil, err
+	}
+	if len(cur.OriginHost) == 0 {
+		return nil, ErrMissingOriginHost
+	}
+	if len(cur.OriginRealm) == 0 {
+		return nil, ErrMissingOriginRealm
+	}
+	app := &Application{
+		AcctApplicationID:           cur.AcctApplicationID,
+		AuthApplicationID:           cur.AuthApplicationID,
+		VendorSpecificApplicationID: cur.VendorSpecificApplicationID,
+	}
+	if failedAVP, err = app.Parse(m.Dictionary(), localRole); err != nil {
+		return failedAVP, err
+	}
+	cur.appID = app.ID()
+	for _, id := range cur.appID {
+		if id != diam.CAPABILITIES_UPDATE_APP_ID {
+			return nil, nil
+		}
+	}
+	return nil, ErrNoCommonApplication
+}
+
+// Applications return a list of supported Application IDs.
+func (cur *CUR) Applications() []uint32 {
+	return cur.appID
+}
+
+// CUA is a Capabilities-Update-Answer message.
+// See RFC 6737 section 3.2 for details.
+type CUA struct {
+	ResultCode   uint32                    `avp:"Result-Code"`
+	OriginHost   datatype.DiameterIdentity `avp:"Origin-Host"`
+	OriginRealm  datatype.DiameterIdentity `avp:"Origin-Realm"`
+	ErrorMessage string                    `avp:"Error-Message"`
+}
+
+// ErrFailedUpdate is returned when the Capabilities-Update-Answer (CUA)
+// contains a Result-Code AVP that is not success (2001).
+type ErrFailedUpdate struct {
+	*CUA
+}
+
+// Error implements the error interface.
+func (e ErrFailedUpdate) Error() string {
+	return fmt.Sprintf("failed Result-Code AVP: %d", e.CUA.ResultCode)
+}
+
+// Parse parses and validates the given message.
+func (cua *CUA) Parse(m *diam.Message) error {
+	if err := m.Unmarshal(cua); err != nil {
+		return err
+	}
+	if cua.ResultCode == 0 {
+		return ErrMissingResultCode
+	}
+	if len(cua.OriginHost) == 0 {
+		return ErrMissingOriginHost
+	}
+	if len(cua.OriginRealm) == 0 {
+		return ErrMissingOriginRealm
+	}
+	if cua.ResultCode != diam.Success {
+		return &ErrFailedUpdate{CUA: cua}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package smparser
+
+import (
+	"testing"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+func TestCUR(t *testing.T) {
+	m := diam.NewRequest(diam.CapabilitiesUpdate, diam.CAPABILITIES_UPDATE_APP_ID, dict.Default)
+	m.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("foobar"))
+	m.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity("test"))
+	m.NewAVP(avp.AuthApplicationID, avp.Mbit, 0, datatype.Unsigned32(diam.CAPABILITIES_UPDATE_APP_ID))
+	m.NewAVP(avp.AcctApplicationID, avp.Mbit, 0, datatype.Unsigned32(1001))
+	cur := new(CUR)
+	if _, err := cur.Parse(m, Server); err != nil {
+		t.Fatal(err)
+	}
+	app := cur.Applications()
+	if len(app) != 2 || app[0] != 1001 || app[1] != diam.CAPABILITIES_UPDATE_APP_ID {
+		t.Fatalf("Unexpected app IDs: %v", app)
+	}
+}
+
+func TestCUR_NoCommonApplication(t *testing.T) {
+	m := diam.NewRequest(diam.CapabilitiesUpdate, diam.CAPABILITIES_UPDATE_APP_ID, dict.Default)
+	m.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("foobar"))
+	m.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity("test"))
+	m.NewAVP(avp.AuthApplicationID, avp.Mbit, 0, datatype.Unsigned32(diam.CAPABILITIES_UPDATE_APP_ID))
+	m.NewAVP(avp.AcctApplicationID, avp.Mbit, 0, datatype.Unsigned32(2))
+	if _, err := new(CUR).Parse(m, Server); err != ErrNoCommonApplication {
+		t.Fatal("Unexpected error:", err)
+	}
+}
+
+func TestCUA_FailedResultCode(t *testing.T) {
+	m := diam.NewMessage(diam.CapabilitiesUpdate, 0, diam.CAPABILITIES_UPDATE_APP_ID, 0, 0, dict.Default)
+	m.NewAVP(avp.ResultCode, avp.Mbit, 0, datatype.Unsigned32(diam.NoCommonApplication))
+	m.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("foobar"))
+	m.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity("test"))
+	err := new(CUA).Parse(m)
+	if fe, ok := err.(*ErrFailedUpdate); !ok || fe.ResultCode != diam.NoCommonApplication {
+		t.Fatal("Unexpected error:", err)
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package smparser
+
+import (
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// DPR is a Disconnect-Peer-Request message.
+// See RFC 6733 section 5.4.1 for details.
+type DPR struct {
+	OriginHost      datatype.DiameterIdentity `avp:"Origin-Host"`
+	OriginRealm     datatype.DiameterIdentity `avp:"Origin-Realm"`
+	DisconnectCause *int32                    `avp:"Disconnect-Cause"`
+}
+
+// Parse parses and validates the given message, and returns nil when
+// all AVPs are ok.
+func (dpr *DPR) Parse(m *diam.Message) error {
+	if err := m.Unmarshal(dpr); err != nil {
+		return err
+	}
+	return dpr.sanityCheck()
+}
+
+// sanityCheck ensures all mandatory AVPs are present.
+func (dpr *DPR) sanityCheck() error {
+	if len(dpr.OriginHost) == 0 {
+		return ErrMissingOriginHost
+	}
+	if len(dpr.OriginRealm) == 0 {
+		return ErrMissingOriginRealm
+	}
+	if dpr.DisconnectCause == nil {
+		return ErrMissingDisconnectCause
+	}
+	return nil
+}
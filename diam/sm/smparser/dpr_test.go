@@ -0,0 +1,38 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package smparser
+
+import (
+	"testing"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+func TestDPR_MissingDisconnectCause(t *testing.T) {
+	m := diam.NewRequest(diam.DisconnectPeer, 0, dict.Default)
+	m.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("foobar"))
+	m.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity("test"))
+	dpr := new(DPR)
+	if err := dpr.Parse(m); err != ErrMissingDisconnectCause {
+		t.Fatalf("Unexpected error. Want %v, have %v", ErrMissingDisconnectCause, err)
+	}
+}
+
+func TestDPR_OK(t *testing.T) {
+	m := diam.NewRequest(diam.DisconnectPeer, 0, dict.Default)
+	m.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("foobar"))
+	m.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity("test"))
+	m.NewAVP(avp.DisconnectCause, avp.Mbit, 0, datatype.Enumerated(2))
+	dpr := new(DPR)
+	if err := dpr.Parse(m); err != nil {
+		t.Fatal(err)
+	}
+	if dpr.OriginHost != "foobar" || dpr.DisconnectCause == nil || *dpr.DisconnectCause != 2 {
+		t.Fatalf("Unexpected DPR: %+v", dpr)
+	}
+}
@@ -40,3 +40,17 @@ func (dwr *DWR) sanityCheck() error {
 	}
 	return nil
 }
+
+// StateID returns the value of the Origin-State-Id AVP, 0 if absent.
+func (dwr *DWR) StateID() uint32 {
+	return stateID(dwr.OriginStateID)
+}
+
+// stateID returns the value of the Origin-State-Id AVP a, 0 if nil.
+func stateID(a *diam.AVP) uint32 {
+	if a == nil {
+		return 0
+	}
+	v, _ := a.Data.(datatype.Unsigned32)
+	return uint32(v)
+}
@@ -24,6 +24,10 @@ var (
 	// the message does not contain an Origin-Realm AVP.
 	ErrMissingOriginRealm = errors.New("missing Origin-Realm")
 
+	// ErrMissingDisconnectCause is returned by Parse when
+	// the DPR does not contain a Disconnect-Cause AVP.
+	ErrMissingDisconnectCause = errors.New("missing Disconnect-Cause")
+
 	// ErrMissingApplication is returned by Parse when
 	// the CER does not contain any Acct-Application-Id or
 	// Auth-Application-Id, or their embedded versions in
@@ -31,8 +35,8 @@ var (
 	ErrMissingApplication = errors.New("missing application")
 
 	// ErrNoCommonSecurity is returned by Parse when
-	// the CER contains the Inband-Security-Id.
-	// We currently don't support that.
+	// the CER requires an Inband-Security-Id we don't
+	// support.
 	ErrNoCommonSecurity = errors.New("no common security")
 
 	// ErrNoCommonApplication is returned by Parse when the
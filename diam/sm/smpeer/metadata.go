@@ -7,6 +7,7 @@ package smpeer
 import (
 	"golang.org/x/net/context"
 
+	"github.com/omnicate/go-diameter/v4/diam"
 	"github.com/omnicate/go-diameter/v4/diam/datatype"
 	"github.com/omnicate/go-diameter/v4/diam/sm/smparser"
 )
@@ -16,36 +17,79 @@ type key int
 const metadataKey key = 0
 
 // Metadata contains information about a diameter peer, acquired
-// during the CER/CEA handshake.
+// during the CER/CEA handshake. The applications of the peer are updated
+// by the Capabilities-Update-Request messages it sends, see RFC 6737.
 type Metadata struct {
-	OriginHost   datatype.DiameterIdentity
-	OriginRealm  datatype.DiameterIdentity
-	Applications []uint32 // Acct or Auth IDs supported by the peer.
+	OriginHost         datatype.DiameterIdentity
+	OriginRealm        datatype.DiameterIdentity
+	HostIPAddresses    []datatype.Address
+	VendorID           uint32
+	ProductName        datatype.UTF8String
+	FirmwareRevision   uint32   // Firmware-Revision of the peer, 0 if not sent.
+	OriginStateID      uint32   // Origin-State-Id of the peer, 0 if not sent.
+	SupportedVendorIDs []uint32 // Supported-Vendor-Id advertised by the peer.
+	Applications       []uint32 // Acct or Auth IDs supported by the peer.
+	InbandSecurityID   uint32   // Negotiated Inband-Security-Id, 0 (NO_INBAND_SECURITY) or 1 (TLS).
 }
 
 // FromCER creates a Metadata object from data in the CER.
 func FromCER(cer *smparser.CER) *Metadata {
 	return &Metadata{
-		OriginHost:   cer.OriginHost,
-		OriginRealm:  cer.OriginRealm,
-		Applications: cer.Applications(),
+		OriginHost:         cer.OriginHost,
+		OriginRealm:        cer.OriginRealm,
+		HostIPAddresses:    cer.HostIPAddresses(),
+		VendorID:           cer.VendorID,
+		ProductName:        cer.ProductName,
+		FirmwareRevision:   cer.FirmwareRevision,
+		OriginStateID:      cer.StateID(),
+		SupportedVendorIDs: cer.SupportedVendors(),
+		Applications:       cer.Applications(),
+		InbandSecurityID:   cer.Security(),
 	}
 }
 
 // FromCEA creates a Metadata object from data in the CEA.
 func FromCEA(cea *smparser.CEA) *Metadata {
 	return &Metadata{
-		OriginHost:   cea.OriginHost,
-		OriginRealm:  cea.OriginRealm,
-		Applications: cea.Applications(),
+		OriginHost:         cea.OriginHost,
+		OriginRealm:        cea.OriginRealm,
+		HostIPAddresses:    cea.HostIPAddresses(),
+		VendorID:           cea.VendorID,
+		ProductName:        cea.ProductName,
+		FirmwareRevision:   cea.FirmwareRevision,
+		OriginStateID:      cea.OriginStateID,
+		SupportedVendorIDs: cea.SupportedVendors(),
+		Applications:       cea.Applications(),
+		InbandSecurityID:   cea.InbandSecurityID,
 	}
 }
 
+// Supports returns whether the peer supports the application appID: the
+// base protocol, the applications advertised by the peer, or any
+// application if the peer advertised the Relay application.
+func (m *Metadata) Supports(appID uint32) bool {
+	if appID == 0 {
+		return true
+	}
+	for _, id := range m.Applications {
+		if id == appID || id == 0xffffffff {
+			return true
+		}
+	}
+	return false
+}
+
 // NewContext returns a new Context that carries a Metadata object.
 func NewContext(ctx context.Context, metadata *Metadata) context.Context {
 	return context.WithValue(ctx, metadataKey, metadata)
 }
 
+// FromConn extracts the Metadata of the peer of the connection c, once
+// its CER/CEA handshake has completed.
+func FromConn(c diam.Conn) (*Metadata, bool) {
+	return FromContext(c.Context())
+}
+
 // FromContext extracts a Metadata object from the context.
 func FromContext(ctx context.Context) (*Metadata, bool) {
 	meta, ok := ctx.Value(metadataKey).(*Metadata)
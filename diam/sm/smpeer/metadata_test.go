@@ -5,10 +5,13 @@
 package smpeer
 
 import (
+	"net"
 	"testing"
 
 	"golang.org/x/net/context"
 
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
 	"github.com/omnicate/go-diameter/v4/diam/datatype"
 	"github.com/omnicate/go-diameter/v4/diam/sm/smparser"
 )
@@ -60,3 +63,39 @@ func TestFromCEA(t *testing.T) {
 		t.Fatalf("Unexpected Metadata. Want %#v, have %#v", meta, data)
 	}
 }
+
+func TestMetadata_Supports(t *testing.T) {
+	meta := &Metadata{Applications: []uint32{4}}
+	if !meta.Supports(0) || !meta.Supports(4) {
+		t.Fatal("Advertised application is not supported")
+	}
+	if meta.Supports(16777251) {
+		t.Fatal("Unadvertised application is supported")
+	}
+	meta.Applications = append(meta.Applications, 0xffffffff)
+	if !meta.Supports(16777251) {
+		t.Fatal("Relay peer does not support all applications")
+	}
+}
+
+func TestFromCER_Capabilities(t *testing.T) {
+	cer := &smparser.CER{
+		OriginHost:        datatype.DiameterIdentity("foobar"),
+		OriginRealm:       datatype.DiameterIdentity("test"),
+		HostIPAddress:     []*diam.AVP{diam.NewAVP(avp.HostIPAddress, avp.Mbit, 0, datatype.Address(net.ParseIP("10.0.0.1")))},
+		VendorID:          13,
+		ProductName:       "go-diameter",
+		FirmwareRevision:  7,
+		SupportedVendorID: []*diam.AVP{diam.NewAVP(avp.SupportedVendorID, avp.Mbit, 0, datatype.Unsigned32(10415))},
+	}
+	meta := FromCER(cer)
+	if len(meta.HostIPAddresses) != 1 || !net.IP(meta.HostIPAddresses[0]).Equal(net.ParseIP("10.0.0.1")) {
+		t.Fatalf("Unexpected HostIPAddresses: %v", meta.HostIPAddresses)
+	}
+	if meta.VendorID != 13 || meta.ProductName != "go-diameter" || meta.FirmwareRevision != 7 {
+		t.Fatalf("Unexpected Metadata: %#v", meta)
+	}
+	if len(meta.SupportedVendorIDs) != 1 || meta.SupportedVendorIDs[0] != 10415 {
+		t.Fatalf("Unexpected SupportedVendorIDs: %v", meta.SupportedVendorIDs)
+	}
+}
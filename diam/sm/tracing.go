@@ -0,0 +1,97 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"sync"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/tracing"
+)
+
+// spanKey identifies a request being handled.
+type spanKey struct {
+	conn diam.Conn
+	hbh  uint32
+}
+
+type serverSpan struct {
+	ctx  context.Context
+	span tracing.Span
+}
+
+// serverSpans holds the spans of the requests being handled by the
+// state machine, which end when their answer is sent.
+type serverSpans struct {
+	mu sync.Mutex // guards m
+	m  map[spanKey]*serverSpan
+}
+
+// start starts the span of the request m received on c, unless it is a
+// retransmission of a request that is still being handled.
+func (s *serverSpans) start(t tracing.Tracer, c diam.Conn, m *diam.Message) {
+	k := spanKey{c, m.Header.HopByHopID}
+	s.mu.Lock()
+	_, exists := s.m[k]
+	s.mu.Unlock()
+	if exists {
+		return
+	}
+	ctx, span := t.Start(c.Context(), tracing.Server, c, m)
+	s.mu.Lock()
+	if s.m == nil {
+		s.m = make(map[spanKey]*serverSpan)
+	}
+	s.m[k] = &serverSpan{ctx: ctx, span: span}
+	s.mu.Unlock()
+}
+
+// end ends the span of the request answered by a on c, if any.
+func (s *serverSpans) end(c diam.Conn, a *diam.Message) {
+	k := spanKey{c, a.Header.HopByHopID}
+	s.mu.Lock()
+	ss, ok := s.m[k]
+	delete(s.m, k)
+	s.mu.Unlock()
+	if ok {
+		ss.span.End(a, nil)
+	}
+}
+
+// closeConn ends the spans of the requests received on c, which is
+// closed before they are answered.
+func (s *serverSpans) closeConn(c diam.Conn) {
+	var spans []*serverSpan
+	s.mu.Lock()
+	for k, ss := range s.m {
+		if k.conn == c {
+			spans = append(spans, ss)
+			delete(s.m, k)
+		}
+	}
+	s.mu.Unlock()
+	for _, ss := range spans {
+		ss.span.End(nil, ErrPeerDisconnected)
+	}
+}
+
+func (s *serverSpans) context(c diam.Conn, m *diam.Message) context.Context {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ss, ok := s.m[spanKey{c, m.Header.HopByHopID}]; ok {
+		return ss.ctx
+	}
+	return context.Background()
+}
+
+// RequestContext returns the context of the span of the request m being
+// handled on c, when Settings.Tracer is set, or else the background
+// context. Handlers pass it to Client.SendContext so the requests they
+// send, for example when relaying m, belong to the same trace.
+func (sm *StateMachine) RequestContext(c diam.Conn, m *diam.Message) context.Context {
+	return sm.spans.context(c, m)
+}
@@ -0,0 +1,81 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+	"github.com/omnicate/go-diameter/v4/diam/tracing"
+)
+
+// newSpanRecorder returns a Recorder that sends the ACR spans it ends
+// to spans.
+func newSpanRecorder(spans chan *tracing.SpanData) *tracing.Recorder {
+	return &tracing.Recorder{
+		Propagate: true,
+		OnEnd: func(s *tracing.SpanData) {
+			if s.Name == "ACR" {
+				spans <- s
+			}
+		},
+	}
+}
+
+func TestClient_Send_Tracing(t *testing.T) {
+	srvSpans := make(chan *tracing.SpanData, 1)
+	srvCfg := *serverSettings
+	srvCfg.Tracer = newSpanRecorder(srvSpans)
+	srvSM := New(&srvCfg)
+	handled := make(chan tracing.SpanContext, 1)
+	srvSM.HandleFunc("ACR", func(c diam.Conn, m *diam.Message) {
+		handled <- tracing.SpanContextFromContext(srvSM.RequestContext(c, m))
+		a := m.Answer(diam.Success)
+		a.NewAVP(avp.OriginHost, avp.Mbit, 0, srvCfg.OriginHost)
+		a.NewAVP(avp.OriginRealm, avp.Mbit, 0, srvCfg.OriginRealm)
+		a.WriteTo(c)
+	})
+	srv := diamtest.NewServer(srvSM, dict.Default)
+	defer srv.Close()
+
+	cliSpans := make(chan *tracing.SpanData, 1)
+	cliCfg := *clientSettings
+	cliCfg.Tracer = newSpanRecorder(cliSpans)
+	cli := newPeerClient(New(&cliCfg), "")
+	c, err := cli.Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if _, err = cli.Send(c, newACR(cli)); err != nil {
+		t.Fatal(err)
+	}
+	var cs, ss *tracing.SpanData
+	for cs == nil || ss == nil {
+		select {
+		case cs = <-cliSpans:
+		case ss = <-srvSpans:
+		case <-time.After(time.Second):
+			t.Fatal("Timeout waiting for spans")
+		}
+	}
+	if cs.Kind != tracing.Client || cs.Answer == nil || cs.Err != nil {
+		t.Fatalf("Unexpected client span: %+v", cs)
+	}
+	if ss.Kind != tracing.Server || ss.Answer == nil || ss.Err != nil {
+		t.Fatalf("Unexpected server span: %+v", ss)
+	}
+	if ss.Parent != cs.SpanContext {
+		t.Fatalf("Unexpected parent of server span. Want %s, have %s", cs.SpanContext, ss.Parent)
+	}
+	if sc := <-handled; sc != ss.SpanContext {
+		t.Fatalf("Unexpected request context. Want %s, have %s", ss.SpanContext, sc)
+	}
+}
@@ -0,0 +1,287 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/sm/smpeer"
+)
+
+// WatchdogState is the state of the watchdog of a connection. See RFC
+// 3539 section 3.4.1 for details.
+type WatchdogState int
+
+// Watchdog states.
+const (
+	WatchdogOkay    WatchdogState = iota // Peer answers, the connection is used
+	WatchdogSuspect                      // DWR not answered, requests fail over
+	WatchdogDown                         // Peer did not answer, the connection is closed
+	WatchdogReopen                       // Previously failed peer must answer DWRs first
+)
+
+var watchdogStates = [...]string{
+	"OKAY",
+	"SUSPECT",
+	"DOWN",
+	"REOPEN",
+}
+
+func (s WatchdogState) String() string {
+	if s >= 0 && int(s) < len(watchdogStates) {
+		return watchdogStates[s]
+	}
+	return "WatchdogState(" + strconv.Itoa(int(s)) + ")"
+}
+
+// watchdogReopenDWAs is the number of DWAs a previously failed peer must
+// answer before its connection is used again.
+const watchdogReopenDWAs = 3
+
+// watchdog is the state of the watchdog of a connection.
+type watchdog struct {
+	mu      sync.Mutex // guards state, pending and numDWA
+	state   WatchdogState
+	pending bool // DWR sent and not answered yet
+	numDWA  int  // DWAs received in WatchdogReopen
+	reset   chan struct{}
+}
+
+func newWatchdog() *watchdog {
+	return &watchdog{reset: make(chan struct{}, 1)}
+}
+
+// setWatchdog restarts the timer of the watchdog.
+func (w *watchdog) setWatchdog() {
+	select {
+	case w.reset <- struct{}{}:
+	default:
+	}
+}
+
+// received handles the messages received from the peer other than DWAs.
+func (w *watchdog) received() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	switch w.state {
+	case WatchdogOkay:
+		w.setWatchdog()
+	case WatchdogSuspect:
+		w.state = WatchdogOkay
+		w.setWatchdog()
+	}
+}
+
+// dwa handles the successful DWAs received from the peer, and returns
+// whether the peer is up again after failing.
+func (w *watchdog) dwa() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.pending = false
+	switch w.state {
+	case WatchdogOkay:
+		w.setWatchdog()
+	case WatchdogSuspect:
+		w.state = WatchdogOkay
+		w.setWatchdog()
+	case WatchdogReopen:
+		if w.numDWA++; w.numDWA >= watchdogReopenDWAs {
+			w.state = WatchdogOkay
+			return true
+		}
+	}
+	return false
+}
+
+// expire handles the expiration of the timer of the watchdog, and
+// returns whether a DWR must be sent.
+func (w *watchdog) expire() (dwr bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	switch w.state {
+	case WatchdogOkay:
+		if w.pending {
+			w.state = WatchdogSuspect
+			return false
+		}
+	case WatchdogSuspect:
+		w.state = WatchdogDown
+		return false
+	case WatchdogReopen:
+		if w.pending {
+			if w.numDWA < 0 {
+				w.state = WatchdogDown
+			} else {
+				w.numDWA = -1
+			}
+			return false
+		}
+	case WatchdogDown:
+		return false
+	}
+	w.pending = true
+	return true
+}
+
+// reopen puts the watchdog of the connection to a previously failed
+// peer in the WatchdogReopen state.
+func (w *watchdog) reopen() {
+	w.mu.Lock()
+	w.state = WatchdogReopen
+	w.numDWA = 0
+	w.pending = true
+	w.mu.Unlock()
+}
+
+// current returns the state of the watchdog.
+func (w *watchdog) current() WatchdogState {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.state
+}
+
+// watchdogKey is the key of the watchdog of a connection, in its context.
+type watchdogKey struct{}
+
+// connWatchdog returns the watchdog of the connection c, if any.
+func connWatchdog(c diam.Conn) *watchdog {
+	w, _ := c.Context().Value(watchdogKey{}).(*watchdog)
+	return w
+}
+
+// watchdogOK returns whether the connection c can be used for requests:
+// its watchdog, if any, is in the WatchdogOkay state.
+func watchdogOK(c diam.Conn) bool {
+	w := connWatchdog(c)
+	return w == nil || w.current() == WatchdogOkay
+}
+
+// WatchdogState returns the state of the watchdog of the connection c,
+// or false if the watchdog is not enabled on c.
+func (cli *Client) WatchdogState(c diam.Conn) (WatchdogState, bool) {
+	w := connWatchdog(c)
+	if w == nil {
+		return WatchdogOkay, false
+	}
+	return w.current(), true
+}
+
+// watchdogs holds the peers whose connection was closed by the watchdog,
+// which are in the WatchdogReopen state when connected again.
+type watchdogs struct {
+	mu     sync.Mutex // guards failed
+	failed map[datatype.DiameterIdentity]struct{}
+}
+
+func (ws *watchdogs) fail(host datatype.DiameterIdentity) {
+	ws.mu.Lock()
+	if ws.failed == nil {
+		ws.failed = make(map[datatype.DiameterIdentity]struct{})
+	}
+	ws.failed[host] = struct{}{}
+	ws.mu.Unlock()
+}
+
+func (ws *watchdogs) failback(host datatype.DiameterIdentity) {
+	ws.mu.Lock()
+	delete(ws.failed, host)
+	ws.mu.Unlock()
+}
+
+func (ws *watchdogs) hasFailed(host datatype.DiameterIdentity) bool {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	_, ok := ws.failed[host]
+	return ok
+}
+
+// tw returns the watchdog timer Tw of the connection c: its watchdog
+// interval with a random jitter of up to WatchdogJitter.
+func (cli *Client) tw(c diam.Conn) time.Duration {
+	interval := cli.watchdogInterval(c)
+	d := interval
+	if cli.WatchdogJitter > 0 {
+		d += time.Duration((2*rand.Float64() - 1) * float64(cli.WatchdogJitter))
+	}
+	if d <= 0 {
+		d = interval
+	}
+	return d
+}
+
+// watchdog runs the watchdog algorithm of RFC 3539 section 3.4.1 on the
+// connection c, until it is closed.
+func (cli *Client) watchdog(c diam.Conn, w *watchdog) {
+	disconnect := closeNotify(c)
+	osid := uint32(cli.Handler.cfg.OriginStateID)
+	if w.current() == WatchdogReopen {
+		cli.sendDWR(c, osid)
+	}
+	timer := time.NewTimer(cli.tw(c))
+	defer timer.Stop()
+	for {
+		select {
+		case <-disconnect:
+			return
+		case <-w.reset:
+			if !timer.Stop() {
+				<-timer.C
+			}
+		case <-timer.C:
+			if w.expire() {
+				cli.sendDWR(c, osid)
+			} else if w.current() == WatchdogDown {
+				cli.watchdogFailure(c)
+				return
+			}
+		}
+		timer.Reset(cli.tw(c))
+	}
+}
+
+func (cli *Client) sendDWR(c diam.Conn, osid uint32) {
+	m := cli.makeDWR(osid)
+	if _, err := m.WriteToStream(c, cli.WatchdogStream); err != nil {
+		cli.Handler.Error(&diam.ErrorReport{Conn: c, Message: m, Error: err})
+	}
+}
+
+// watchdogFailure closes the connection c, whose peer did not answer
+// DWRs.
+func (cli *Client) watchdogFailure(c diam.Conn) {
+	if mc := cli.Handler.cfg.Metrics; mc != nil {
+		mc.WatchdogFailure(c)
+	}
+	if meta, ok := smpeer.FromContext(c.Context()); ok {
+		cli.Handler.watchdogs.fail(meta.OriginHost)
+	}
+	setCloseReason(c, ErrWatchdogTimeout)
+	cli.Handler.emitConn(WatchdogTimeout, c, ErrWatchdogTimeout, 0)
+	c.Close()
+}
+
+// startWatchdog starts the watchdog of the connection c, after the
+// capabilities exchange. Connections to peers whose previous connection
+// failed are in the WatchdogReopen state until they answer DWRs.
+func (cli *Client) startWatchdog(c diam.Conn, w *watchdog) {
+	if meta, ok := smpeer.FromContext(c.Context()); ok && cli.Handler.watchdogs.hasFailed(meta.OriginHost) {
+		w.reopen()
+	}
+	go cli.watchdog(c, w)
+}
+
+// newConnWatchdog saves a new watchdog in the context of c.
+func newConnWatchdog(c diam.Conn) *watchdog {
+	w := newWatchdog()
+	c.SetContext(context.WithValue(c.Context(), watchdogKey{}, w))
+	return w
+}
@@ -0,0 +1,125 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sm
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/diamtest"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+func TestWatchdog_States(t *testing.T) {
+	w := newWatchdog()
+	if !w.expire() || !w.pending {
+		t.Fatal("DWR not sent in OKAY")
+	}
+	if w.expire() || w.current() != WatchdogSuspect {
+		t.Fatalf("Unexpected state. Want SUSPECT, have %s", w.current())
+	}
+	w.received()
+	if w.current() != WatchdogOkay {
+		t.Fatalf("No failback. Want OKAY, have %s", w.current())
+	}
+	w.expire()
+	if w.dwa() || w.pending {
+		t.Fatal("DWA not handled in OKAY")
+	}
+	w.expire()
+	w.expire()
+	if w.expire(); w.current() != WatchdogDown {
+		t.Fatalf("Unexpected state. Want DOWN, have %s", w.current())
+	}
+
+	w = newWatchdog()
+	w.reopen()
+	w.received()
+	if w.current() != WatchdogReopen {
+		t.Fatalf("Unexpected failback. Want REOPEN, have %s", w.current())
+	}
+	for i := 1; i < watchdogReopenDWAs; i++ {
+		if w.dwa() {
+			t.Fatalf("Failback after %d DWAs", i)
+		}
+		if !w.expire() {
+			t.Fatal("DWR not sent in REOPEN")
+		}
+	}
+	if !w.dwa() || w.current() != WatchdogOkay {
+		t.Fatalf("No failback after %d DWAs", watchdogReopenDWAs)
+	}
+
+	w = newWatchdog()
+	w.reopen()
+	w.expire()
+	if w.expire(); w.current() != WatchdogDown {
+		t.Fatalf("Unexpected state. Want DOWN, have %s", w.current())
+	}
+}
+
+func TestClient_Watchdog_Reopen(t *testing.T) {
+	var mute int32
+	srvSM := newACRServer(serverSettings)
+	dwr := handleDWR(srvSM)
+	srvSM.mux.HandleIdx(baseDWRIdx, handshakeOK(func(c diam.Conn, m *diam.Message) {
+		if atomic.LoadInt32(&mute) == 0 {
+			dwr(c, m)
+		}
+	}))
+	srv := diamtest.NewServer(srvSM, dict.Default)
+	defer srv.Close()
+
+	events := make(chan ConnEvent, 8)
+	cli := newPeerClient(New(clientSettings), "")
+	cli.EnableWatchdog = true
+	cli.WatchdogInterval = 20 * time.Millisecond
+	cli.WatchdogJitter = 5 * time.Millisecond
+	cli.Reconnect = &ReconnectPolicy{Tc: 10 * time.Millisecond, Events: events}
+	defer cli.Reconnect.Stop()
+	c, err := cli.Dial(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state, ok := cli.WatchdogState(c); !ok || state != WatchdogOkay {
+		t.Fatalf("Unexpected watchdog state: %s", state)
+	}
+	atomic.StoreInt32(&mute, 1)
+	if ev := waitConnEvent(t, events, ConnLost); ev.Conn != c {
+		t.Fatal("Unexpected connection lost")
+	}
+	if state, _ := cli.WatchdogState(c); state != WatchdogDown {
+		t.Fatalf("Unexpected watchdog state. Want DOWN, have %s", state)
+	}
+	atomic.StoreInt32(&mute, 0)
+	nc := waitConnEvent(t, events, ConnReconnected).Conn
+	if state, _ := cli.WatchdogState(nc); state != WatchdogReopen {
+		t.Fatalf("Unexpected watchdog state. Want REOPEN, have %s", state)
+	}
+	acr := newACR(cli)
+	acr.NewAVP(avp.DestinationHost, avp.Mbit, 0, serverSettings.OriginHost)
+	if _, err = cli.nextHop(acr); err == nil {
+		t.Fatal("Connection in REOPEN was routed to")
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		if state, _ := cli.WatchdogState(nc); state == WatchdogOkay {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Watchdog did not reopen the connection")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if hop, err := cli.nextHop(acr); err != nil || hop != nc {
+		t.Fatalf("Connection not routed to after failback: %v", err)
+	}
+	if cli.Handler.watchdogs.hasFailed(serverSettings.OriginHost) {
+		t.Fatal("Peer still failed after failback")
+	}
+}
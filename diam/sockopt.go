@@ -0,0 +1,173 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+import (
+	"net"
+	"time"
+
+	"github.com/ishidawataru/sctp"
+)
+
+// TransportConfig holds the socket options of the connections of servers
+// and clients, so that they are tuned without wrapping net.Conn. Options
+// left unset keep the defaults of Go and of the system. Options that do
+// not apply to the transport of a connection, such as the SCTP options
+// of TCP connections, are ignored.
+//
+// See Server.TransportConfig, ConfigureListener and DialConfig.
+type TransportConfig struct {
+	// KeepAlive is the period of the TCP keep-alive probes, enabled by
+	// Go every 15 seconds by default. Keep-alives are disabled when
+	// negative.
+	KeepAlive time.Duration
+
+	// Nagle enables Nagle's algorithm on TCP connections, by clearing
+	// the TCP_NODELAY option set by Go by default.
+	Nagle bool
+
+	// ReadBuffer and WriteBuffer are the sizes of the receive and send
+	// buffers of the socket (SO_RCVBUF and SO_SNDBUF) of TCP, SCTP and
+	// Unix domain socket connections.
+	ReadBuffer  int
+	WriteBuffer int
+
+	// SCTPRTOInitial, SCTPRTOMin and SCTPRTOMax are the initial, minimum
+	// and maximum retransmission timeouts of SCTP associations
+	// (SCTP_RTOINFO), in milliseconds precision.
+	SCTPRTOInitial time.Duration
+	SCTPRTOMin     time.Duration
+	SCTPRTOMax     time.Duration
+
+	// SCTPHeartbeatInterval is the interval of the heartbeats of the
+	// paths of SCTP associations (SCTP_PEER_ADDR_PARAMS). Heartbeats
+	// are disabled when negative.
+	SCTPHeartbeatInterval time.Duration
+}
+
+// Apply sets the socket options of cfg on the connection c, which must
+// not be wrapped, by TLS for example. It does nothing if cfg is nil.
+func (cfg *TransportConfig) Apply(c net.Conn) error {
+	if cfg == nil {
+		return nil
+	}
+	if err := cfg.applyBuffers(c); err != nil {
+		return err
+	}
+	switch c := c.(type) {
+	case *net.TCPConn:
+		return cfg.applyTCP(c)
+	case *SCTPConn:
+		return cfg.applySCTP(c.SCTPConn)
+	case *sctp.SCTPConn:
+		return cfg.applySCTP(c)
+	}
+	return nil
+}
+
+// bufferedConn is a connection with socket buffers, such as
+// *net.TCPConn, *net.UnixConn and *sctp.SCTPConn.
+type bufferedConn interface {
+	SetReadBuffer(bytes int) error
+	SetWriteBuffer(bytes int) error
+}
+
+func (cfg *TransportConfig) applyBuffers(c net.Conn) error {
+	bc, ok := c.(bufferedConn)
+	if !ok {
+		return nil
+	}
+	if cfg.ReadBuffer > 0 {
+		if err := bc.SetReadBuffer(cfg.ReadBuffer); err != nil {
+			return err
+		}
+	}
+	if cfg.WriteBuffer > 0 {
+		if err := bc.SetWriteBuffer(cfg.WriteBuffer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (cfg *TransportConfig) applyTCP(c *net.TCPConn) error {
+	if cfg.KeepAlive < 0 {
+		if err := c.SetKeepAlive(false); err != nil {
+			return err
+		}
+	} else if cfg.KeepAlive > 0 {
+		if err := c.SetKeepAlive(true); err != nil {
+			return err
+		}
+		if err := c.SetKeepAlivePeriod(cfg.KeepAlive); err != nil {
+			return err
+		}
+	}
+	if cfg.Nagle {
+		return c.SetNoDelay(false)
+	}
+	return nil
+}
+
+func (cfg *TransportConfig) applySCTP(c *sctp.SCTPConn) error {
+	fd := sctpConnFd(c)
+	if cfg.SCTPRTOInitial > 0 || cfg.SCTPRTOMin > 0 || cfg.SCTPRTOMax > 0 {
+		err := setSCTPRTOInfo(fd, cfg.SCTPRTOInitial, cfg.SCTPRTOMin, cfg.SCTPRTOMax)
+		if err != nil {
+			return err
+		}
+	}
+	if cfg.SCTPHeartbeatInterval != 0 {
+		return setSCTPHeartbeat(fd, cfg.SCTPHeartbeatInterval)
+	}
+	return nil
+}
+
+// configListener is the listener of ConfigureListener.
+type configListener struct {
+	net.Listener
+	cfg *TransportConfig
+}
+
+// ConfigureListener returns a listener that sets the socket options of
+// cfg on the connections accepted by l. The options are set on a best
+// effort basis: connections are accepted, and the failure logged, when
+// they cannot be set. It returns l if cfg is nil.
+//
+// The listeners of Server.ListenAndServe are configured with the
+// Server.TransportConfig; the listeners passed to Server.Serve are
+// configured with ConfigureListener.
+func ConfigureListener(l net.Listener, cfg *TransportConfig) net.Listener {
+	if cfg == nil {
+		return l
+	}
+	return &configListener{Listener: l, cfg: cfg}
+}
+
+// Accept implements the net.Listener interface.
+func (l *configListener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	if err = l.cfg.Apply(c); err != nil {
+		DefaultLogger.Log(LevelWarn, "setting socket options failed",
+			"network", l.Addr().Network(), "addr", l.Addr(), "err", err)
+	}
+	return c, nil
+}
+
+// configured returns the listeners ls configured with the
+// srv.TransportConfig, or ls if it is nil.
+func (srv *Server) configured(ls []net.Listener) []net.Listener {
+	if srv.TransportConfig == nil {
+		return ls
+	}
+	cls := make([]net.Listener, len(ls))
+	for i, l := range ls {
+		cls[i] = ConfigureListener(l, srv.TransportConfig)
+	}
+	return cls
+}
@@ -0,0 +1,94 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+// sockopt returns the integer socket option opt of c.
+func sockopt(t *testing.T, c net.Conn, level, opt int) int {
+	rc, err := c.(*net.TCPConn).SyscallConn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v int
+	rc.Control(func(fd uintptr) {
+		v, err = unix.GetsockoptInt(int(fd), level, opt)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestConfigureListener(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &TransportConfig{KeepAlive: -1, Nagle: true, ReadBuffer: 1 << 16, WriteBuffer: 1 << 16}
+	cl := ConfigureListener(l, cfg)
+	defer cl.Close()
+	c, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	sc, err := cl.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sc.Close()
+	if v := sockopt(t, sc, unix.SOL_SOCKET, unix.SO_KEEPALIVE); v != 0 {
+		t.Fatalf("Unexpected SO_KEEPALIVE. Want 0, have %d", v)
+	}
+	if v := sockopt(t, sc, unix.IPPROTO_TCP, unix.TCP_NODELAY); v != 0 {
+		t.Fatalf("Unexpected TCP_NODELAY. Want 0, have %d", v)
+	}
+	// Linux doubles the buffer sizes to account for its overhead.
+	if v := sockopt(t, sc, unix.SOL_SOCKET, unix.SO_RCVBUF); v < cfg.ReadBuffer {
+		t.Fatalf("Unexpected SO_RCVBUF. Want at least %d, have %d", cfg.ReadBuffer, v)
+	}
+	if v := sockopt(t, sc, unix.SOL_SOCKET, unix.SO_SNDBUF); v < cfg.WriteBuffer {
+		t.Fatalf("Unexpected SO_SNDBUF. Want at least %d, have %d", cfg.WriteBuffer, v)
+	}
+	if ConfigureListener(l, nil) != l {
+		t.Fatal("Listener without config was wrapped")
+	}
+}
+
+func TestDialConfig(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go func() {
+		if c, err := l.Accept(); err == nil {
+			defer c.Close()
+			time.Sleep(time.Second)
+		}
+	}()
+	cfg := &TransportConfig{KeepAlive: 30 * time.Second}
+	c, err := DialConfig("tcp", l.Addr().String(), nil, dict.Default, time.Second, nil, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	rw := c.Connection()
+	if v := sockopt(t, rw, unix.IPPROTO_TCP, unix.TCP_KEEPIDLE); v != 30 {
+		t.Fatalf("Unexpected TCP_KEEPIDLE. Want 30, have %d", v)
+	}
+	if v := sockopt(t, rw, unix.IPPROTO_TCP, unix.TCP_NODELAY); v != 1 {
+		t.Fatalf("Unexpected TCP_NODELAY. Want 1, have %d", v)
+	}
+}
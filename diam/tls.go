@@ -0,0 +1,167 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"net"
+	"time"
+)
+
+// DefaultTLSHandshakeTimeout is the time UpgradeTLS waits for the TLS
+// handshake to complete, when the Server has no ReadTimeout.
+const DefaultTLSHandshakeTimeout = 10 * time.Second
+
+var (
+	// ErrTLSUnsupported is returned by UpgradeTLS on connections that
+	// cannot be upgraded, such as SCTP associations.
+	ErrTLSUnsupported = errors.New("connection cannot be upgraded to TLS")
+
+	// ErrTLSActive is returned by UpgradeTLS on connections that already
+	// use TLS, or are being upgraded.
+	ErrTLSActive = errors.New("connection already uses TLS")
+)
+
+// The TLSUpgrader interface is implemented by Conns that can be upgraded
+// to TLS after the capabilities exchange, when it is negotiated with the
+// Inband-Security-Id AVP. See RFC 6733 sections 2.2 and 6.10.
+type TLSUpgrader interface {
+	// UpgradeTLS stops reading the connection, calls before, if not
+	// nil, and performs the TLS handshake with config, as the client if
+	// client is true or else as the server. Messages are then read and
+	// written over TLS.
+	//
+	// Responders write the answer after which the peer starts the
+	// handshake from before, so no data of the handshake is read as a
+	// message. Initiators call UpgradeTLS from the handler of that
+	// answer.
+	//
+	// The connection is closed if before or the handshake fail.
+	UpgradeTLS(config *tls.Config, client bool, before func() error) error
+}
+
+// readerFunc is an io.Reader calling a function.
+type readerFunc func(p []byte) (int, error)
+
+func (f readerFunc) Read(p []byte) (int, error) {
+	return f(p)
+}
+
+// readRaw reads from the connection, or from its TLS connection after
+// UpgradeTLS. Reads interrupted by UpgradeTLS are resumed once it is
+// done.
+func (c *conn) readRaw(p []byte) (int, error) {
+	for {
+		c.umu.Lock()
+		for c.upgrading {
+			c.ucond.Wait()
+		}
+		var r net.Conn = c.rwc
+		if c.tlsConn != nil {
+			r = c.tlsConn
+		}
+		upgrades := c.upgrades
+		c.readers++
+		c.umu.Unlock()
+
+		n, err := r.Read(p)
+
+		c.umu.Lock()
+		c.readers--
+		c.ucond.Broadcast()
+		interrupted := c.upgrading || c.upgrades != upgrades
+		c.umu.Unlock()
+		if n > 0 || err == nil || !interrupted {
+			return n, err
+		}
+	}
+}
+
+// UpgradeTLS implements the TLSUpgrader interface.
+func (w *response) UpgradeTLS(config *tls.Config, client bool, before func() error) error {
+	c := w.conn
+	if c.ucond == nil {
+		return ErrTLSUnsupported
+	}
+	c.umu.Lock()
+	if c.upgrading || c.tlsState != nil {
+		c.umu.Unlock()
+		return ErrTLSActive
+	}
+	c.upgrading = true
+	// Interrupt the goroutines reading the connection, and wait for
+	// them to stop.
+	c.rwc.SetReadDeadline(time.Now())
+	for c.readers > 0 {
+		c.ucond.Wait()
+	}
+	c.umu.Unlock()
+
+	var err error
+	if before != nil {
+		err = before()
+	}
+	var tc *tls.Conn
+	if err == nil {
+		tc, err = w.handshakeTLS(config, client)
+	}
+
+	c.umu.Lock()
+	if err == nil {
+		state := tc.ConnectionState()
+		c.tlsConn, c.tlsState = tc, &state
+	}
+	c.upgrading = false
+	c.upgrades++
+	c.ucond.Broadcast()
+	c.umu.Unlock()
+	if err != nil {
+		c.rwc.Close()
+	}
+	return err
+}
+
+// handshakeTLS performs the TLS handshake on the connection, and writes
+// messages to the TLS connection after it.
+func (w *response) handshakeTLS(config *tls.Config, client bool) (*tls.Conn, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	c := w.conn
+	if c.batch != nil {
+		// Write the messages sent before the handshake, such as the CEA.
+		if err := c.batch.Flush(); err != nil {
+			return nil, err
+		}
+	}
+	var tc *tls.Conn
+	if client {
+		tc = tls.Client(c.rwc, config)
+	} else {
+		tc = tls.Server(c.rwc, config)
+	}
+	timeout := c.server.ReadTimeout
+	if timeout == 0 {
+		timeout = DefaultTLSHandshakeTimeout
+	}
+	c.rwc.SetDeadline(time.Now().Add(timeout))
+	err := tc.Handshake()
+	c.rwc.SetDeadline(time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	if err = c.buf.Writer.Flush(); err != nil {
+		return nil, err
+	}
+	c.buf.Writer = bufio.NewWriter(tc)
+	c.wc = tc
+	if c.batch != nil {
+		if err = c.batch.setConn(tc); err != nil {
+			return nil, err
+		}
+	}
+	return tc, nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package tracing
+
+import (
+	"strings"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+// Defaults of the vendor-specific AVP trace context is carried in.
+//
+// The default vendor is the enterprise number reserved for documentation
+// by RFC 5612. Deployments should use an AVP of their own vendor, which
+// all their nodes are configured with.
+const (
+	DefaultVendorID = 32473
+	DefaultCode     = 1
+)
+
+// Carrier carries the trace context entries of a message, such as the
+// traceparent and tracestate of W3C Trace Context, in vendor-specific
+// AVPs. Each entry is an OctetString AVP with the value key=value and
+// the M bit cleared, so peers that do not trace ignore it.
+//
+// It implements the TextMapCarrier interface of OpenTelemetry, and can
+// be used with its propagators.
+type Carrier struct {
+	Message  *diam.Message
+	VendorID uint32
+	Code     uint32
+}
+
+// NewCarrier returns a Carrier of the trace context of m in the AVP
+// with DefaultCode and DefaultVendorID.
+func NewCarrier(m *diam.Message) *Carrier {
+	return &Carrier{Message: m, VendorID: DefaultVendorID, Code: DefaultCode}
+}
+
+// entry returns the key and value of the trace context entry a, if it
+// is one.
+func (c *Carrier) entry(a *diam.AVP) (key, value string, ok bool) {
+	if a.Code != c.Code || a.VendorID != c.VendorID || a.Data == nil {
+		return "", "", false
+	}
+	// AVPs that are not in the dictionary are decoded as Unknown.
+	var s string
+	switch v := a.Data.(type) {
+	case datatype.OctetString:
+		s = string(v)
+	case datatype.Unknown:
+		s = string(v)
+	default:
+		return "", "", false
+	}
+	i := strings.IndexByte(s, '=')
+	if i < 0 {
+		return "", "", false
+	}
+	return s[:i], s[i+1:], true
+}
+
+// Get returns the value of the entry key, or an empty string.
+func (c *Carrier) Get(key string) string {
+	for _, a := range c.Message.AVP {
+		if k, v, ok := c.entry(a); ok && k == key {
+			return v
+		}
+	}
+	return ""
+}
+
+// Set sets the entry key to value, replacing its previous value.
+func (c *Carrier) Set(key, value string) {
+	m := c.Message
+	avps := m.AVP[:0]
+	for _, a := range m.AVP {
+		if k, _, ok := c.entry(a); ok && k == key {
+			m.Header.MessageLength -= uint32(a.Len())
+			continue
+		}
+		avps = append(avps, a)
+	}
+	m.AVP = avps
+	m.NewAVP(c.Code, avp.Vbit, c.VendorID, datatype.OctetString(key+"="+value))
+}
+
+// Keys returns the keys of the entries.
+func (c *Carrier) Keys() []string {
+	var keys []string
+	for _, a := range c.Message.AVP {
+		if k, _, ok := c.entry(a); ok {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,60 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package tracing traces Diameter requests, and propagates their trace
+// context across Diameter hops.
+//
+// A Tracer set in sm.Settings starts a client span for each request sent
+// by sm.Client.Send, which ends when its answer is received, and a
+// server span for each request handled by the state machine, which ends
+// when its answer is sent. Handlers get the context of the span of the
+// request they handle from StateMachine.RequestContext, and pass it to
+// Client.SendContext so the requests they send are its children.
+//
+// Trace context is carried in vendor-specific AVPs of requests with a
+// Carrier. Recorder is a Tracer of W3C Trace Context spans that does
+// not depend on a tracing library:
+//
+//	settings := &sm.Settings{
+//		OriginHost:  "client",
+//		OriginRealm: "go-diameter",
+//		Tracer: &tracing.Recorder{
+//			Propagate: true,
+//			OnEnd: func(s *tracing.SpanData) {
+//				log.Printf("%s %s %s %s", s.Name, s.SpanContext, s.Parent, s.End.Sub(s.Start))
+//			},
+//		},
+//	}
+//
+// OpenTelemetry is used with an adapter such as:
+//
+//	type otelTracer struct {
+//		tracer     trace.Tracer
+//		propagator propagation.TextMapPropagator
+//	}
+//
+//	func (t *otelTracer) Start(ctx context.Context, kind tracing.SpanKind, c diam.Conn, m *diam.Message) (context.Context, tracing.Span) {
+//		carrier := tracing.NewCarrier(m)
+//		spanKind := trace.SpanKindClient
+//		if kind == tracing.Server {
+//			ctx = t.propagator.Extract(ctx, carrier)
+//			spanKind = trace.SpanKindServer
+//		}
+//		ctx, span := t.tracer.Start(ctx, tracing.SpanName(m), trace.WithSpanKind(spanKind))
+//		if kind == tracing.Client {
+//			t.propagator.Inject(ctx, carrier)
+//		}
+//		return ctx, otelSpan{span}
+//	}
+//
+//	type otelSpan struct{ trace.Span }
+//
+//	func (s otelSpan) End(answer *diam.Message, err error) {
+//		if err != nil {
+//			s.RecordError(err)
+//			s.SetStatus(codes.Error, err.Error())
+//		}
+//		s.Span.End()
+//	}
+package tracing
@@ -0,0 +1,105 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package tracing
+
+import (
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+)
+
+// SpanData is a span ended by a Recorder.
+type SpanData struct {
+	Name        string // see SpanName
+	Kind        SpanKind
+	SpanContext SpanContext
+	Parent      SpanContext // zero for the root span of a trace
+	Start       time.Time
+	End         time.Time
+	Request     *diam.Message
+	Answer      *diam.Message // nil if Err is set
+	Err         error
+}
+
+// Recorder is a Tracer of W3C Trace Context spans, which are passed to
+// OnEnd when they end, for example to log or export them.
+//
+// The parent of client spans is the span context carried by their
+// context, as returned by SpanContextFromContext. Spans without parent
+// start a new trace.
+type Recorder struct {
+	// Propagate carries the span context of client spans in the
+	// traceparent of their requests, and sets the parent of server
+	// spans to the traceparent of their requests, in the AVP with
+	// VendorID and Code.
+	Propagate bool
+
+	// VendorID and Code are the vendor-specific AVP trace context is
+	// carried in. They default to DefaultVendorID and DefaultCode.
+	VendorID uint32
+	Code     uint32
+
+	// OnEnd is called with each span that ends, if set. It must not
+	// modify the messages of the span.
+	OnEnd func(s *SpanData)
+}
+
+type recordedSpan struct {
+	r    *Recorder
+	data SpanData
+}
+
+func (r *Recorder) carrier(m *diam.Message) *Carrier {
+	c := NewCarrier(m)
+	if r.VendorID != 0 {
+		c.VendorID = r.VendorID
+	}
+	if r.Code != 0 {
+		c.Code = r.Code
+	}
+	return c
+}
+
+// Start implements the Tracer interface.
+func (r *Recorder) Start(ctx context.Context, kind SpanKind, c diam.Conn, m *diam.Message) (context.Context, Span) {
+	s := &recordedSpan{r: r, data: SpanData{
+		Name:    SpanName(m),
+		Kind:    kind,
+		Start:   time.Now(),
+		Request: m,
+	}}
+	parent := SpanContextFromContext(ctx)
+	if kind == Server && r.Propagate {
+		if sc, err := ParseTraceparent(r.carrier(m).Get("traceparent")); err == nil {
+			parent = sc
+		}
+	}
+	s.data.Parent = parent
+	s.data.SpanContext = SpanContext{
+		TraceID: parent.TraceID,
+		SpanID:  newSpanID(),
+		Flags:   parent.Flags,
+	}
+	if !parent.IsValid() {
+		s.data.SpanContext.TraceID = newTraceID()
+		s.data.SpanContext.Flags = FlagSampled
+	}
+	if kind == Client && r.Propagate {
+		r.carrier(m).Set("traceparent", s.data.SpanContext.String())
+	}
+	return ContextWithSpanContext(ctx, s.data.SpanContext), s
+}
+
+// End implements the Span interface.
+func (s *recordedSpan) End(answer *diam.Message, err error) {
+	s.data.End = time.Now()
+	s.data.Answer = answer
+	s.data.Err = err
+	if s.r.OnEnd != nil {
+		s.r.OnEnd(&s.data)
+	}
+}
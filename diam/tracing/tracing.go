@@ -0,0 +1,70 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package tracing
+
+import (
+	"strconv"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+)
+
+// SpanKind is the side of a request/answer exchange a span traces.
+type SpanKind int
+
+// Kinds of spans.
+const (
+	// Client spans trace requests sent, until their answer is received.
+	Client SpanKind = iota
+
+	// Server spans trace requests received, until their answer is sent.
+	Server
+)
+
+// String returns the name of the kind.
+func (k SpanKind) String() string {
+	switch k {
+	case Client:
+		return "client"
+	case Server:
+		return "server"
+	}
+	return "kind-" + strconv.Itoa(int(k))
+}
+
+// Tracer creates the spans of Diameter requests. It is implemented by
+// Recorder, and by adapters of tracing libraries such as OpenTelemetry.
+//
+// Tracers that propagate trace context inject it in client requests and
+// extract it from server requests in Start, usually with a Carrier.
+type Tracer interface {
+	// Start starts the span of the request m. Client spans are started
+	// before m is sent, and ctx carries their parent span, if any. c is
+	// nil when the connection is not known yet. Server spans are started
+	// when m is received on c, before it is handled.
+	//
+	// It returns a context carrying the new span, which may be used as
+	// the parent of other spans.
+	Start(ctx context.Context, kind SpanKind, c diam.Conn, m *diam.Message) (context.Context, Span)
+}
+
+// Span is the trace of a request/answer exchange.
+type Span interface {
+	// End ends the span with the answer to its request, or with the
+	// error the exchange failed with when no answer was received.
+	End(answer *diam.Message, err error)
+}
+
+// SpanName returns the name of the span of the request m: the short
+// name of its command followed by R, such as CCR, or its code followed
+// by R if the command is not in the dictionary of m.
+func SpanName(m *diam.Message) string {
+	cmd, err := m.Dictionary().FindCommand(m.Header.ApplicationID, m.Header.CommandCode)
+	if err != nil {
+		return strconv.FormatUint(uint64(m.Header.CommandCode), 10) + "R"
+	}
+	return cmd.Short + "R"
+}
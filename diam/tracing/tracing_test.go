@@ -0,0 +1,122 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package tracing
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+func newRequest() *diam.Message {
+	m := diam.NewRequest(diam.CreditControl, 4, dict.Default)
+	m.NewAVP(avp.SessionID, avp.Mbit, 0, datatype.UTF8String("sess"))
+	return m
+}
+
+func TestCarrier(t *testing.T) {
+	m := newRequest()
+	c := NewCarrier(m)
+	c.Set("traceparent", "a")
+	c.Set("tracestate", "k=v")
+	c.Set("traceparent", "b")
+	if len(m.AVP) != 3 {
+		t.Fatalf("Unexpected number of AVPs. Want 3, have %d", len(m.AVP))
+	}
+	if have := c.Keys(); !reflect.DeepEqual(have, []string{"tracestate", "traceparent"}) {
+		t.Fatalf("Unexpected keys: %q", have)
+	}
+
+	// Decode the message, where the trace context AVPs are unknown.
+	b, err := m.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int(m.Header.MessageLength) != len(b) {
+		t.Fatalf("Unexpected message length. Want %d, have %d", len(b), m.Header.MessageLength)
+	}
+	m, err = diam.ReadMessage(bytes.NewReader(b), dict.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c = NewCarrier(m)
+	if v := c.Get("traceparent"); v != "b" {
+		t.Fatalf("Unexpected traceparent. Want b, have %q", v)
+	}
+	if v := c.Get("tracestate"); v != "k=v" {
+		t.Fatalf("Unexpected tracestate. Want k=v, have %q", v)
+	}
+	if v := c.Get("baggage"); v != "" {
+		t.Fatalf("Unexpected baggage: %q", v)
+	}
+}
+
+func TestParseTraceparent(t *testing.T) {
+	const s = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	sc, err := ParseTraceparent(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sc.Flags != FlagSampled || sc.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("Unexpected span context: %+v", sc)
+	}
+	if sc.String() != s {
+		t.Fatalf("Unexpected traceparent. Want %s, have %s", s, sc)
+	}
+	for _, s := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e473x-00f067aa0ba902b7-01",
+	} {
+		if _, err := ParseTraceparent(s); err != ErrInvalidTraceparent {
+			t.Fatalf("Unexpected error parsing %q: %v", s, err)
+		}
+	}
+	if _, err := ParseTraceparent("01" + s[2:] + "-future"); err != nil {
+		t.Fatalf("Unexpected error parsing a future version: %v", err)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	var spans []*SpanData
+	r := &Recorder{Propagate: true, OnEnd: func(s *SpanData) { spans = append(spans, s) }}
+
+	m := newRequest()
+	ctx, cs := r.Start(context.Background(), Client, nil, m)
+	sc := SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		t.Fatal("Client span has no span context")
+	}
+	if v := NewCarrier(m).Get("traceparent"); v != sc.String() {
+		t.Fatalf("Unexpected traceparent. Want %s, have %q", sc, v)
+	}
+	_, ss := r.Start(context.Background(), Server, nil, m)
+	ss.End(m.Answer(diam.Success), nil)
+	cs.End(nil, context.Canceled)
+
+	if len(spans) != 2 {
+		t.Fatalf("Unexpected number of spans. Want 2, have %d", len(spans))
+	}
+	srv, cli := spans[0], spans[1]
+	if srv.Name != "CCR" || srv.Kind != Server || srv.Answer == nil {
+		t.Fatalf("Unexpected server span: %+v", srv)
+	}
+	if srv.Parent != sc || srv.SpanContext.TraceID != sc.TraceID {
+		t.Fatalf("Server span is not a child of the client span: %+v", srv)
+	}
+	if cli.Parent.IsValid() || cli.Err != context.Canceled {
+		t.Fatalf("Unexpected client span: %+v", cli)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+import (
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A Transport connects peers over a custom network, such as Unix domain
+// sockets or memory, selected by the scheme of the address passed to
+// Dial and Listen, as in "unix:///run/diameter.sock". See
+// RegisterTransport.
+type Transport interface {
+	// Dial connects to the address, without its scheme, within timeout
+	// if not zero.
+	Dial(addr string, timeout time.Duration) (net.Conn, error)
+
+	// Listen announces on the address, without its scheme.
+	Listen(addr string) (net.Listener, error)
+}
+
+var transports = struct {
+	sync.RWMutex
+	m map[string]Transport
+}{m: map[string]Transport{
+	"unix": unixTransport{},
+	"mem":  memTransport{},
+}}
+
+// RegisterTransport registers the Transport t for the addresses with the
+// given scheme, such as "unix" for "unix://" addresses. The "unix" and
+// "mem" schemes are registered by default:
+//
+//	unix://path	Unix domain socket at path
+//	mem://name	in-process transport named name, see ListenMem
+func RegisterTransport(scheme string, t Transport) {
+	transports.Lock()
+	transports.m[scheme] = t
+	transports.Unlock()
+}
+
+// transportOf returns the Transport registered for the scheme of addr,
+// and addr without its scheme, or nil if addr has no registered scheme.
+func transportOf(addr string) (Transport, string) {
+	i := strings.Index(addr, "://")
+	if i < 0 {
+		return nil, addr
+	}
+	transports.RLock()
+	t := transports.m[addr[:i]]
+	transports.RUnlock()
+	if t == nil {
+		return nil, addr
+	}
+	return t, addr[i+3:]
+}
+
+// transportDialer is the Dialer of a Transport, which ignores the
+// network passed to Dial.
+type transportDialer struct {
+	t       Transport
+	timeout time.Duration
+}
+
+func (d transportDialer) Dial(network, addr string) (net.Conn, error) {
+	return d.t.Dial(addr, d.timeout)
+}
+
+// unixTransport is the Transport of Unix domain sockets.
+type unixTransport struct{}
+
+func (unixTransport) Dial(addr string, timeout time.Duration) (net.Conn, error) {
+	return net.DialTimeout("unix", addr, timeout)
+}
+
+func (unixTransport) Listen(addr string) (net.Listener, error) {
+	return net.Listen("unix", addr)
+}
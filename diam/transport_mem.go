@@ -0,0 +1,291 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+import (
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// memQueueLen is the number of writes buffered by the in-process
+// transport before writers block, like the socket buffers of TCP.
+const memQueueLen = 256
+
+// errMemRefused is returned when dialing an in-process address without
+// listener.
+var errMemRefused = errors.New("connection refused")
+
+// memListeners are the listeners of the in-process transport, by name.
+var memListeners = struct {
+	sync.Mutex
+	m map[string]*memListener
+}{m: make(map[string]*memListener)}
+
+// memTransport is the in-process Transport of "mem://" addresses, which
+// connects the clients and servers of a process without sockets, for
+// tests and embedded peers.
+type memTransport struct{}
+
+func (memTransport) Dial(addr string, timeout time.Duration) (net.Conn, error) {
+	return dialMem(addr, timeout)
+}
+
+func (memTransport) Listen(addr string) (net.Listener, error) {
+	return ListenMem(addr)
+}
+
+// DialMem connects to the in-process address name of ListenMem. The
+// connections of Dial to "mem://name" addresses are created by DialMem.
+func DialMem(name string) (net.Conn, error) {
+	return dialMem(name, 0)
+}
+
+func dialMem(addr string, timeout time.Duration) (net.Conn, error) {
+	memListeners.Lock()
+	l := memListeners.m[addr]
+	memListeners.Unlock()
+	if l == nil {
+		return nil, &net.OpError{Op: "dial", Net: "mem", Addr: memAddr(addr), Err: errMemRefused}
+	}
+	client, server := newMemPipe(addr)
+	var expired <-chan time.Time
+	if timeout > 0 {
+		t := time.NewTimer(timeout)
+		defer t.Stop()
+		expired = t.C
+	}
+	select {
+	case l.conns <- server:
+		return client, nil
+	case <-l.done:
+		return nil, &net.OpError{Op: "dial", Net: "mem", Addr: memAddr(addr), Err: errMemRefused}
+	case <-expired:
+		return nil, &net.OpError{Op: "dial", Net: "mem", Addr: memAddr(addr), Err: timeoutError{}}
+	}
+}
+
+// ListenMem announces on the in-process address name, which clients of
+// the same process dial as "mem://name". Listeners of "mem://name"
+// addresses are created by ListenMem.
+func ListenMem(name string) (net.Listener, error) {
+	memListeners.Lock()
+	defer memListeners.Unlock()
+	if _, ok := memListeners.m[name]; ok {
+		return nil, &net.OpError{Op: "listen", Net: "mem", Addr: memAddr(name), Err: errors.New("address already in use")}
+	}
+	l := &memListener{name: name, conns: make(chan net.Conn), done: make(chan struct{})}
+	memListeners.m[name] = l
+	return l, nil
+}
+
+// memAddr is the address of the in-process transport.
+type memAddr string
+
+func (a memAddr) Network() string { return "mem" }
+func (a memAddr) String() string  { return string(a) }
+
+// memListener is a listener of the in-process transport.
+type memListener struct {
+	name  string
+	conns chan net.Conn
+	once  sync.Once
+	done  chan struct{}
+}
+
+func (l *memListener) Accept() (net.Conn, error) {
+	select {
+	case c := <-l.conns:
+		return c, nil
+	case <-l.done:
+		return nil, &net.OpError{Op: "accept", Net: "mem", Addr: l.Addr(), Err: errors.New("use of closed network connection")}
+	}
+}
+
+func (l *memListener) Close() error {
+	l.once.Do(func() {
+		memListeners.Lock()
+		delete(memListeners.m, l.name)
+		memListeners.Unlock()
+		close(l.done)
+	})
+	return nil
+}
+
+func (l *memListener) Addr() net.Addr {
+	return memAddr(l.name)
+}
+
+// timeoutError is the net.Error of the deadlines of the in-process
+// transport.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+// memDeadline is a deadline of a memConn, whose wait channel is closed
+// when it expires.
+type memDeadline struct {
+	mu      sync.Mutex // guards timer and expired
+	timer   *time.Timer
+	expired chan struct{}
+}
+
+func newMemDeadline() *memDeadline {
+	return &memDeadline{expired: make(chan struct{})}
+}
+
+// set sets the deadline to t, or disables it if t is zero.
+func (d *memDeadline) set(t time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.timer != nil && !d.timer.Stop() {
+		<-d.expired // Wait for the timer callback to close it.
+	}
+	d.timer = nil
+	closed := false
+	select {
+	case <-d.expired:
+		closed = true
+	default:
+	}
+	if t.IsZero() {
+		if closed {
+			d.expired = make(chan struct{})
+		}
+		return
+	}
+	if dur := time.Until(t); dur > 0 {
+		if closed {
+			d.expired = make(chan struct{})
+		}
+		expired := d.expired
+		d.timer = time.AfterFunc(dur, func() { close(expired) })
+		return
+	}
+	if !closed {
+		close(d.expired)
+	}
+}
+
+// wait returns the channel closed when the deadline expires.
+func (d *memDeadline) wait() chan struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.expired
+}
+
+// memConn is a connection of the in-process transport. Writes are
+// buffered up to memQueueLen, so that peers writing to each other at
+// the same time do not block.
+type memConn struct {
+	rx, tx   chan []byte
+	pending  []byte // rest of the last write read
+	done     chan struct{}
+	peerDone chan struct{}
+	once     sync.Once
+	local    net.Addr
+	remote   net.Addr
+
+	readDeadline  *memDeadline
+	writeDeadline *memDeadline
+}
+
+// newMemPipe returns the client and server ends of a connection to the
+// in-process address name.
+func newMemPipe(name string) (client, server *memConn) {
+	c2s := make(chan []byte, memQueueLen)
+	s2c := make(chan []byte, memQueueLen)
+	cdone := make(chan struct{})
+	sdone := make(chan struct{})
+	client = &memConn{
+		rx: s2c, tx: c2s, done: cdone, peerDone: sdone,
+		local: memAddr(""), remote: memAddr(name),
+		readDeadline: newMemDeadline(), writeDeadline: newMemDeadline(),
+	}
+	server = &memConn{
+		rx: c2s, tx: s2c, done: sdone, peerDone: cdone,
+		local: memAddr(name), remote: memAddr(""),
+		readDeadline: newMemDeadline(), writeDeadline: newMemDeadline(),
+	}
+	return client, server
+}
+
+func (c *memConn) Read(b []byte) (int, error) {
+	if len(c.pending) == 0 {
+		select {
+		case p := <-c.rx:
+			c.pending = p
+		default:
+			select {
+			case p := <-c.rx:
+				c.pending = p
+			case <-c.done:
+				return 0, io.ErrClosedPipe
+			case <-c.peerDone:
+				select {
+				case p := <-c.rx:
+					c.pending = p
+				default:
+					return 0, io.EOF
+				}
+			case <-c.readDeadline.wait():
+				return 0, timeoutError{}
+			}
+		}
+	}
+	n := copy(b, c.pending)
+	c.pending = c.pending[n:]
+	return n, nil
+}
+
+func (c *memConn) Write(b []byte) (int, error) {
+	select {
+	case <-c.done:
+		return 0, io.ErrClosedPipe
+	case <-c.peerDone:
+		return 0, io.ErrClosedPipe
+	default:
+	}
+	p := make([]byte, len(b))
+	copy(p, b)
+	select {
+	case c.tx <- p:
+		return len(b), nil
+	case <-c.done:
+		return 0, io.ErrClosedPipe
+	case <-c.peerDone:
+		return 0, io.ErrClosedPipe
+	case <-c.writeDeadline.wait():
+		return 0, timeoutError{}
+	}
+}
+
+func (c *memConn) Close() error {
+	c.once.Do(func() { close(c.done) })
+	return nil
+}
+
+func (c *memConn) LocalAddr() net.Addr  { return c.local }
+func (c *memConn) RemoteAddr() net.Addr { return c.remote }
+
+func (c *memConn) SetDeadline(t time.Time) error {
+	c.readDeadline.set(t)
+	c.writeDeadline.set(t)
+	return nil
+}
+
+func (c *memConn) SetReadDeadline(t time.Time) error {
+	c.readDeadline.set(t)
+	return nil
+}
+
+func (c *memConn) SetWriteDeadline(t time.Time) error {
+	c.writeDeadline.set(t)
+	return nil
+}
@@ -0,0 +1,131 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam_test
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/omnicate/go-diameter/v4/diam"
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+// testTransport serves ACRs on the address addr, and sends one from a
+// client connected to it.
+func testTransport(t *testing.T, addr string) {
+	smux := diam.NewServeMux()
+	smux.HandleFunc("ACR", func(c diam.Conn, m *diam.Message) {
+		a := m.Answer(diam.Success)
+		a.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("srv"))
+		a.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity("test"))
+		a.WriteTo(c)
+	})
+	l, err := diam.Listen("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &diam.Server{Handler: smux, Dict: dict.Default}
+	go srv.Serve(l)
+	defer srv.Shutdown(context.Background())
+
+	cli, err := diam.Dial(addr, nil, dict.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cli.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for i := 0; i < 10; i++ {
+		m := diam.NewRequest(diam.Accounting, 3, dict.Default)
+		m.NewAVP(avp.SessionID, avp.Mbit, 0, datatype.UTF8String("sess"))
+		a, err := diam.SendRequest(ctx, cli, m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.Header.EndToEndID != m.Header.EndToEndID {
+			t.Fatalf("Unexpected End-to-End ID. Want %d, have %d", m.Header.EndToEndID, a.Header.EndToEndID)
+		}
+	}
+}
+
+func TestTransport_Mem(t *testing.T) {
+	testTransport(t, "mem://test-transport")
+}
+
+func TestTransport_Unix(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix domain sockets not supported")
+	}
+	dir, err := ioutil.TempDir("", "diam")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	testTransport(t, "unix://"+filepath.Join(dir, "diameter.sock"))
+}
+
+func TestTransport_MemRefused(t *testing.T) {
+	if _, err := diam.Dial("mem://nobody", nil, dict.Default); err == nil {
+		t.Fatal("Dial succeeded without listener")
+	}
+	l, err := diam.ListenMem("dup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	if _, err = diam.ListenMem("dup"); err == nil {
+		t.Fatal("Address in use was not detected")
+	}
+}
+
+func TestTransport_MemDeadline(t *testing.T) {
+	l, err := diam.ListenMem("deadline")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go func() {
+		if c, err := l.Accept(); err == nil {
+			defer c.Close()
+			time.Sleep(time.Second)
+		}
+	}()
+	c, err := diam.DialMem("deadline")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	c.SetReadDeadline(time.Now().Add(20 * time.Millisecond))
+	_, err = c.Read(make([]byte, 1))
+	if ne, ok := err.(net.Error); !ok || !ne.Timeout() {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+// RegisterTransport is exercised by registering a scheme that aliases
+// the in-process transport.
+func TestRegisterTransport(t *testing.T) {
+	diam.RegisterTransport("alias", aliasTransport{})
+	testTransport(t, "alias://test-alias")
+}
+
+type aliasTransport struct{}
+
+func (aliasTransport) Dial(addr string, timeout time.Duration) (net.Conn, error) {
+	return diam.DialMem(addr)
+}
+
+func (aliasTransport) Listen(addr string) (net.Listener, error) {
+	return diam.ListenMem(addr)
+}
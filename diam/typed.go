@@ -0,0 +1,87 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+)
+
+var (
+	connType  = reflect.TypeOf((*Conn)(nil)).Elem()
+	errorType = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// TypedAnswer returns the function that answers requests with the typed
+// function f, which takes a pointer to a struct with avp tags, such as
+// gx.CCR, and returns a pointer to a struct with avp tags, such as
+// gx.CCA, and an error:
+//
+//	func(c diam.Conn, ccr *gx.CCR) (*gx.CCA, error)
+//
+// The requests are decoded into a new struct with Message.Unmarshal,
+// and the answers of f are encoded with Message.Marshal into the answer
+// created by Message.Answer, without Result-Code. The errors of the
+// decoding and encoding, and those of f, are returned. No answer is
+// returned when f returns neither answer nor error.
+//
+// TypedAnswer panics if f does not have the signature above.
+func TypedAnswer(f interface{}) func(c Conn, m *Message) (*Message, error) {
+	fv := reflect.ValueOf(f)
+	ft := fv.Type()
+	if ft.Kind() != reflect.Func || ft.NumIn() != 2 || ft.NumOut() != 2 ||
+		ft.In(0) != connType || !isStructPtr(ft.In(1)) ||
+		!isStructPtr(ft.Out(0)) || ft.Out(1) != errorType {
+		panic(fmt.Sprintf("DIAM: typed handler %s is not a func(diam.Conn, *Request) (*Answer, error)", ft))
+	}
+	reqType := ft.In(1).Elem()
+	return func(c Conn, m *Message) (*Message, error) {
+		req := reflect.New(reqType)
+		if err := m.Unmarshal(req.Interface()); err != nil {
+			return nil, err
+		}
+		out := fv.Call([]reflect.Value{reflect.ValueOf(&c).Elem(), req})
+		if err, _ := out[1].Interface().(error); err != nil {
+			return nil, err
+		}
+		if out[0].IsNil() {
+			return nil, nil
+		}
+		a := m.Answer(0)
+		if err := a.Marshal(out[0].Interface()); err != nil {
+			return nil, err
+		}
+		return a, nil
+	}
+}
+
+// TypedHandler returns a Handler that answers the requests with the
+// typed function f, see TypedAnswer, for example:
+//
+//	mux.Handle("CCR", diam.TypedHandler("pcrf.example.com", "example.com",
+//		func(c diam.Conn, ccr *gx.CCR) (*gx.CCA, error) {
+//			...
+//		}))
+//
+// Errors are answered by the node originHost of originRealm with
+// Message.ErrorAnswer.
+func TypedHandler(originHost, originRealm datatype.DiameterIdentity, f interface{}) Handler {
+	answer := TypedAnswer(f)
+	return HandlerFunc(func(c Conn, m *Message) {
+		a, err := answer(c, m)
+		if err != nil {
+			a = m.ErrorAnswer(err, originHost, originRealm)
+		}
+		if a != nil {
+			a.WriteTo(c)
+		}
+	})
+}
+
+func isStructPtr(t reflect.Type) bool {
+	return t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct
+}
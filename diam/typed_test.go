@@ -0,0 +1,93 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+type typedACR struct {
+	SessionID    datatype.UTF8String       `avp:"Session-Id"`
+	OriginHost   datatype.DiameterIdentity `avp:"Origin-Host"`
+	RecordNumber uint32                    `avp:"Accounting-Record-Number"`
+}
+
+type typedACA struct {
+	SessionID    datatype.UTF8String       `avp:"Session-Id"`
+	ResultCode   uint32                    `avp:"Result-Code"`
+	OriginHost   datatype.DiameterIdentity `avp:"Origin-Host"`
+	RecordNumber uint32                    `avp:"Accounting-Record-Number"`
+}
+
+func TestTypedAnswer(t *testing.T) {
+	answer := TypedAnswer(func(c Conn, acr *typedACR) (*typedACA, error) {
+		switch acr.RecordNumber {
+		case 1:
+			return nil, errors.New("failure")
+		case 2:
+			return nil, nil
+		}
+		return &typedACA{
+			SessionID:    acr.SessionID,
+			ResultCode:   Success,
+			OriginHost:   "srv",
+			RecordNumber: acr.RecordNumber,
+		}, nil
+	})
+	newACR := func(n uint32) *Message {
+		m := NewRequest(Accounting, 0, dict.Default)
+		m.NewAVP(avp.SessionID, avp.Mbit, 0, datatype.UTF8String("sess"))
+		m.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("cli"))
+		m.NewAVP(avp.AccountingRecordNumber, avp.Mbit, 0, datatype.Unsigned32(n))
+		return m
+	}
+
+	m := newACR(3)
+	a, err := answer(nil, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Header.CommandFlags&RequestFlag != 0 || a.Header.HopByHopID != m.Header.HopByHopID {
+		t.Fatalf("Unexpected answer header: %s", a.Header)
+	}
+	var aca typedACA
+	if err = a.Unmarshal(&aca); err != nil {
+		t.Fatal(err)
+	}
+	want := typedACA{SessionID: "sess", ResultCode: Success, OriginHost: "srv", RecordNumber: 3}
+	if aca != want {
+		t.Fatalf("Unexpected answer. Want %+v, have %+v", want, aca)
+	}
+
+	if _, err = answer(nil, newACR(1)); err == nil || err.Error() != "failure" {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if a, err = answer(nil, newACR(2)); a != nil || err != nil {
+		t.Fatalf("Unexpected answer %v, error %v", a, err)
+	}
+}
+
+func TestTypedAnswer_Signature(t *testing.T) {
+	for _, f := range []interface{}{
+		func(c Conn, m *Message) {},
+		func(c Conn, acr typedACR) (*typedACA, error) { return nil, nil },
+		func(acr *typedACR) (*typedACA, error) { return nil, nil },
+		func(c Conn, acr *typedACR) *typedACA { return nil },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("No panic for %T", f)
+				}
+			}()
+			TypedAnswer(f)
+		}()
+	}
+}
@@ -0,0 +1,284 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+// The ValidationError interface is implemented by the errors of
+// Validate, and by ResultError, which carry the Result-Code and the
+// Failed-AVP of the answer to the invalid message. See ErrorAnswer.
+type ValidationError interface {
+	error
+	ResultCode() uint32
+	FailedAVP() *AVP
+}
+
+// RuleError is returned by Validate when the number of instances of an
+// AVP does not satisfy the rule of the command in the dictionary.
+type RuleError struct {
+	Command string
+	Rule    *dict.Rule
+	Count   int  // Number of instances of the AVP in the message
+	AVP     *AVP // First instance in excess, or an example of the missing AVP
+}
+
+// Error implements the error interface.
+func (e *RuleError) Error() string {
+	if e.Count == 0 {
+		return fmt.Sprintf("%s: missing AVP %s", e.Command, e.Rule.AVP)
+	}
+	return fmt.Sprintf("%s: %d instances of AVP %s do not satisfy min=%d max=%d",
+		e.Command, e.Count, e.Rule.AVP, e.Rule.Min, e.Rule.Max)
+}
+
+// ResultCode implements the ValidationError interface. It returns
+// DIAMETER_MISSING_AVP or DIAMETER_AVP_OCCURS_TOO_MANY_TIMES.
+func (e *RuleError) ResultCode() uint32 {
+	if e.Rule.Max > 0 && e.Count > e.Rule.Max {
+		return AVPOccursTooManyTimes
+	}
+	return MissingAVP
+}
+
+// FailedAVP implements the ValidationError interface.
+func (e *RuleError) FailedAVP() *AVP {
+	return e.AVP
+}
+
+// AVPError is returned by Validate for an AVP that is not valid in the
+// command of the message.
+type AVPError struct {
+	Command string
+	AVP     *AVP
+	Result  uint32 // Result-Code of the answer
+	Reason  string
+}
+
+// Error implements the error interface.
+func (e *AVPError) Error() string {
+	return fmt.Sprintf("%s: AVP %d: %s", e.Command, e.AVP.Code, e.Reason)
+}
+
+// ResultCode implements the ValidationError interface.
+func (e *AVPError) ResultCode() uint32 {
+	return e.Result
+}
+
+// FailedAVP implements the ValidationError interface.
+func (e *AVPError) FailedAVP() *AVP {
+	return e.AVP
+}
+
+// ResultError is an error that handlers return to answer a request with
+// the Result-Code Code, and the Failed-AVP AVP when set. See ErrorAnswer.
+type ResultError struct {
+	Code uint32
+	AVP  *AVP
+}
+
+// Error implements the error interface.
+func (e *ResultError) Error() string {
+	if e.AVP == nil {
+		return fmt.Sprintf("Result-Code %d", e.Code)
+	}
+	return fmt.Sprintf("Result-Code %d: AVP %d", e.Code, e.AVP.Code)
+}
+
+// ResultCode implements the ValidationError interface.
+func (e *ResultError) ResultCode() uint32 {
+	return e.Code
+}
+
+// FailedAVP implements the ValidationError interface.
+func (e *ResultError) FailedAVP() *AVP {
+	return e.AVP
+}
+
+// Validate checks the message against the rules of its command in its
+// dictionary. See ValidateWith.
+func (m *Message) Validate() error {
+	return m.ValidateWith(m.Dictionary())
+}
+
+// ValidateWith checks the AVPs of the message against the rules of its
+// command in the dictionary d, for requests or answers, and returns a
+// ValidationError for the first that fails:
+//
+// The fixed AVPs must be first, in order. The required AVPs must be
+// present, at least min times, and AVPs must not appear more than max
+// times (*RuleError). The M bit of AVPs must be set as the dictionary
+// requires (*AVPError with DIAMETER_INVALID_AVP_BITS), and the AVPs with
+// the M bit that are unknown or not in the rules of the command are not
+// allowed, unless the command allows any AVP (*AVPError with
+// DIAMETER_AVP_UNSUPPORTED).
+//
+// The required AVPs of the answers with the E flag set are not checked,
+// as answers to errors only carry the AVPs of RFC 6733 section 7.2.
+// The AVPs embedded in grouped AVPs are not checked.
+func (m *Message) ValidateWith(d *dict.Parser) error {
+	request := m.Header.CommandFlags&RequestFlag != 0
+	cmd, err := d.FindCommand(m.Header.ApplicationID, m.Header.CommandCode)
+	if err != nil {
+		return err
+	}
+	rules, name := cmd.Answer.Rule, cmd.Short+"A"
+	if request {
+		rules, name = cmd.Request.Rule, cmd.Short+"R"
+	}
+	allowed := make(map[string]bool, len(rules))
+	for _, r := range rules {
+		allowed[r.AVP] = true
+	}
+	names := make([]string, len(m.AVP))
+	count := make(map[string]int, len(m.AVP))
+	first := make(map[string]*AVP)
+	for i, a := range m.AVP {
+		da, err := d.FindAVPWithVendor(m.Header.ApplicationID, a.Code, a.VendorID)
+		if err != nil {
+			if a.Flags&avp.Mbit != 0 {
+				return &AVPError{Command: name, AVP: a, Result: AVPUnsupported, Reason: "unknown AVP"}
+			}
+			continue
+		}
+		mbit := a.Flags&avp.Mbit != 0
+		if (strings.Contains(da.Must, "M") && !mbit) || (strings.Contains(da.MustNot, "M") && mbit) {
+			return &AVPError{Command: name, AVP: a, Result: InvalidAVPBits, Reason: "invalid M bit"}
+		}
+		if mbit && !allowed[da.Name] && !allowed[dict.AnyAVP] {
+			return &AVPError{Command: name, AVP: a, Result: AVPUnsupported, Reason: "AVP not allowed in command"}
+		}
+		names[i] = da.Name
+		count[da.Name]++
+		if r := ruleOf(rules, da.Name); r != nil && r.Max > 0 && count[da.Name] == r.Max+1 {
+			first[da.Name] = a
+		}
+	}
+	errorAnswer := m.Header.CommandFlags&(RequestFlag|ErrorFlag) == ErrorFlag
+	pos := 0
+	for _, r := range rules {
+		n := count[r.AVP]
+		if r.Fixed && n > 0 {
+			if pos >= len(names) || names[pos] != r.AVP {
+				a := m.AVP[0]
+				if pos < len(m.AVP) {
+					a = m.AVP[pos]
+				}
+				return &AVPError{Command: name, AVP: a, Result: UnableToComply, Reason: "fixed AVP " + r.AVP + " out of place"}
+			}
+			pos++
+		}
+		min := r.Min
+		if errorAnswer {
+			min = 0
+		} else if r.Required && min < 1 {
+			min = 1
+		}
+		if n < min {
+			return &RuleError{Command: name, Rule: r, Count: n, AVP: exampleAVP(d, m.Header.ApplicationID, r.AVP)}
+		}
+		if r.Max > 0 && n > r.Max {
+			return &RuleError{Command: name, Rule: r, Count: n, AVP: first[r.AVP]}
+		}
+	}
+	return nil
+}
+
+// CheckUnsupportedAVPs returns an *AVPError with DIAMETER_AVP_UNSUPPORTED
+// for the first AVP of the message with the M bit set that is not in its
+// dictionary, including the AVPs embedded in grouped AVPs, as required
+// by RFC 6733 section 4.1.
+func (m *Message) CheckUnsupportedAVPs() error {
+	if err := m.Decode(); err != nil {
+		return err
+	}
+	d := m.Dictionary()
+	name := fmt.Sprintf("command %d", m.Header.CommandCode)
+	if cmd, err := d.FindCommand(m.Header.ApplicationID, m.Header.CommandCode); err == nil {
+		name = cmd.Short + "A"
+		if m.Header.CommandFlags&RequestFlag != 0 {
+			name = cmd.Short + "R"
+		}
+	}
+	if a := unsupportedAVP(d, m.Header.ApplicationID, m.AVP); a != nil {
+		return &AVPError{Command: name, AVP: a, Result: AVPUnsupported, Reason: "unknown AVP"}
+	}
+	return nil
+}
+
+func unsupportedAVP(d *dict.Parser, appid uint32, avps []*AVP) *AVP {
+	for _, a := range avps {
+		if a.Flags&avp.Mbit == 0 {
+			continue
+		}
+		if _, err := d.FindAVPWithVendor(appid, a.Code, a.VendorID); err != nil {
+			return a
+		}
+		if g, ok := a.Data.(*GroupedAVP); ok {
+			if f := unsupportedAVP(d, appid, g.AVP); f != nil {
+				return f
+			}
+		}
+	}
+	return nil
+}
+
+func ruleOf(rules []*dict.Rule, name string) *dict.Rule {
+	for _, r := range rules {
+		if r.AVP == name {
+			return r
+		}
+	}
+	return nil
+}
+
+// exampleAVP returns an example of the AVP with the given name, with
+// a value of the minimum length filled with zeroes, as required in the
+// Failed-AVP of DIAMETER_MISSING_AVP answers by RFC 6733 section 7.5.
+func exampleAVP(d *dict.Parser, appid uint32, name string) *AVP {
+	da, err := d.FindAVP(appid, name)
+	if err != nil {
+		return nil
+	}
+	var n int
+	switch da.Data.Type {
+	case datatype.Integer32Type, datatype.Unsigned32Type, datatype.EnumeratedType,
+		datatype.Float32Type, datatype.TimeType, datatype.IPv4Type:
+		n = 4
+	case datatype.Integer64Type, datatype.Unsigned64Type, datatype.Float64Type:
+		n = 8
+	case datatype.AddressType:
+		n = 6
+	case datatype.IPv6Type:
+		n = 16
+	}
+	var flags uint8
+	if strings.Contains(da.Must, "M") {
+		flags |= avp.Mbit
+	}
+	return NewAVP(da.Code, flags, da.VendorID, datatype.OctetString(make([]byte, n)))
+}
+
+// ErrorAnswer creates the answer to the request m that failed the
+// validation with err, sent by the node originHost of originRealm, with
+// the Result-Code and Failed-AVP of err when it is a ValidationError, or
+// DIAMETER_UNABLE_TO_COMPLY.
+func (m *Message) ErrorAnswer(err error, originHost, originRealm datatype.DiameterIdentity) *Message {
+	ve, ok := err.(ValidationError)
+	if !ok {
+		return m.AnswerFrom(UnableToComply, originHost, originRealm)
+	}
+	a := m.AnswerFrom(ve.ResultCode(), originHost, originRealm)
+	if f := ve.FailedAVP(); f != nil {
+		a.NewAVP(avp.FailedAVP, avp.Mbit, 0, &GroupedAVP{AVP: []*AVP{f}})
+	}
+	return a
+}
@@ -0,0 +1,190 @@
+// Copyright 2013-2020 go-diameter authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diam
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/omnicate/go-diameter/v4/diam/avp"
+	"github.com/omnicate/go-diameter/v4/diam/datatype"
+	"github.com/omnicate/go-diameter/v4/diam/dict"
+)
+
+func TestMessage_Validate(t *testing.T) {
+	a := NewMessage(ReAuth, 0, 0, 1, 1, dict.Default)
+	a.NewAVP(avp.SessionID, avp.Mbit, 0, datatype.UTF8String("sess"))
+	a.NewAVP(avp.ResultCode, avp.Mbit, 0, datatype.Unsigned32(Success))
+	a.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("srv"))
+	err, ok := a.Validate().(*RuleError)
+	if !ok || err.Rule.AVP != "Origin-Realm" || err.Count != 0 {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	a.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity("test"))
+	a.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity("test"))
+	err, ok = a.Validate().(*RuleError)
+	if !ok || err.Rule.AVP != "Origin-Realm" || err.Count != 2 {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Protocol errors do not require the AVPs of the command.
+	e := NewMessage(ReAuth, ErrorFlag, 0, 1, 1, dict.Default)
+	e.NewAVP(avp.ResultCode, avp.Mbit, 0, datatype.Unsigned32(UnableToDeliver))
+	if err := e.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMessage_ValidateWith(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(m *Message)
+		result uint32
+		failed uint32
+	}{
+		{"MissingAVP", func(m *Message) { m.AVP = m.AVP[:3] }, MissingAVP, avp.DestinationRealm},
+		{"TooManyTimes", func(m *Message) {
+			m.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity("again"))
+		}, AVPOccursTooManyTimes, avp.OriginRealm},
+		{"InvalidMBit", func(m *Message) { m.AVP[1].Flags = 0 }, InvalidAVPBits, avp.OriginHost},
+		{"UnknownAVP", func(m *Message) {
+			m.NewAVP(9999, avp.Mbit, 0, datatype.Unsigned32(1))
+		}, AVPUnsupported, 9999},
+		{"NotAllowed", func(m *Message) {
+			m.NewAVP(avp.ResultCode, avp.Mbit, 0, datatype.Unsigned32(Success))
+		}, AVPUnsupported, avp.ResultCode},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newTestRAR()
+			tc.modify(m)
+			err := m.ValidateWith(dict.Default)
+			ve, ok := err.(ValidationError)
+			if !ok {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if ve.ResultCode() != tc.result {
+				t.Fatalf("Unexpected Result-Code. Want %d, have %d", tc.result, ve.ResultCode())
+			}
+			if f := ve.FailedAVP(); f == nil || f.Code != tc.failed {
+				t.Fatalf("Unexpected Failed-AVP: %v", f)
+			}
+			a := m.ErrorAnswer(err, "srv", "test")
+			if rc, err := a.FindAVP(avp.ResultCode, 0); err != nil || rc.Data != datatype.Unsigned32(tc.result) {
+				t.Fatalf("Unexpected Result-Code in answer: %v", rc)
+			}
+			if _, err := a.FindAVP(avp.FailedAVP, 0); err != nil {
+				t.Fatal("No Failed-AVP in answer")
+			}
+			if _, err := a.Serialize(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestMessage_ValidateWith_Fixed(t *testing.T) {
+	const fixedDict = `<?xml version="1.0" encoding="UTF-8"?>
+<diameter>
+	<application id="0">
+		<command code="258" short="RA" name="Re-Auth">
+			<request>
+				<rule avp="Session-Id" required="true" fixed="true" max="1"/>
+				<rule avp="Origin-Host" required="true" max="1"/>
+			</request>
+			<answer>
+				<rule avp="Session-Id" required="true" fixed="true" max="1"/>
+			</answer>
+		</command>
+		<avp name="Session-Id" code="263" must="M" may="P" must-not="V" may-encrypt="Y">
+			<data type="UTF8String"/>
+		</avp>
+		<avp name="Origin-Host" code="264" must="M" may="P" must-not="V" may-encrypt="-">
+			<data type="DiameterIdentity"/>
+		</avp>
+	</application>
+</diameter>`
+	d, err := dict.NewParser()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = d.Load(strings.NewReader(fixedDict)); err != nil {
+		t.Fatal(err)
+	}
+	m := NewRequest(ReAuth, 0, d)
+	m.NewAVP(avp.SessionID, avp.Mbit, 0, datatype.UTF8String("sess"))
+	m.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("cli"))
+	if err = m.ValidateWith(d); err != nil {
+		t.Fatal(err)
+	}
+	m.AVP[0], m.AVP[1] = m.AVP[1], m.AVP[0]
+	e, ok := m.ValidateWith(d).(*AVPError)
+	if !ok || e.Result != UnableToComply || e.AVP.Code != avp.OriginHost {
+		t.Fatalf("Unexpected error: %v", e)
+	}
+}
+
+func TestMessage_ErrorAnswer_ResultError(t *testing.T) {
+	m := newTestRAR()
+	a := m.ErrorAnswer(&ResultError{Code: UnableToDeliver}, "srv", "test")
+	if a.Header.CommandFlags&ErrorFlag == 0 {
+		t.Fatal("E bit is not set in protocol error answer")
+	}
+	if _, err := a.FindAVP(avp.FailedAVP, 0); err == nil {
+		t.Fatal("Unexpected Failed-AVP in answer")
+	}
+	a = m.ErrorAnswer(&ResultError{Code: InvalidAVPValue, AVP: m.AVP[0]}, "srv", "test")
+	if a.Header.CommandFlags&ErrorFlag != 0 {
+		t.Fatal("E bit is set in permanent failure answer")
+	}
+	if rc, err := a.FindAVP(avp.ResultCode, 0); err != nil || rc.Data != datatype.Unsigned32(InvalidAVPValue) {
+		t.Fatalf("Unexpected Result-Code in answer: %v", rc)
+	}
+	if _, err := a.FindAVP(avp.FailedAVP, 0); err != nil {
+		t.Fatal("No Failed-AVP in answer")
+	}
+}
+
+func TestMessage_CheckUnsupportedAVPs(t *testing.T) {
+	m := newTestRAR()
+	m.NewAVP(99999, 0, 0, datatype.OctetString("optional"))
+	b, err := m.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m, err = ReadMessage(bytes.NewReader(b), dict.Default); err != nil {
+		t.Fatal(err)
+	}
+	if err = m.CheckUnsupportedAVPs(); err != nil {
+		t.Fatalf("Unexpected error for unknown AVP without M bit: %v", err)
+	}
+
+	unknown := NewAVP(99999, avp.Mbit, 0, datatype.OctetString("mandatory"))
+	m = newTestRAR()
+	m.NewAVP(avp.ProxyInfo, avp.Mbit, 0, &GroupedAVP{
+		AVP: []*AVP{
+			NewAVP(avp.ProxyHost, avp.Mbit, 0, datatype.DiameterIdentity("proxy")),
+			unknown,
+		},
+	})
+	if b, err = m.Serialize(); err != nil {
+		t.Fatal(err)
+	}
+	if m, err = ReadMessage(bytes.NewReader(b), dict.Default); err != nil {
+		t.Fatal(err)
+	}
+	err = m.CheckUnsupportedAVPs()
+	ve, ok := err.(ValidationError)
+	if !ok {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if ve.ResultCode() != AVPUnsupported {
+		t.Fatalf("Unexpected Result-Code. Want %d, have %d", AVPUnsupported, ve.ResultCode())
+	}
+	if f := ve.FailedAVP(); f == nil || f.Code != unknown.Code {
+		t.Fatalf("Unexpected Failed-AVP: %v", f)
+	}
+}
@@ -7,5 +7,6 @@ require (
 	github.com/golang/protobuf v1.3.2
 	github.com/ishidawataru/sctp v0.0.0-20190922091402-408ec287e38c
 	golang.org/x/net v0.0.0-20191007182048-72f939374954
+	golang.org/x/sys v0.0.0-20190215142949-d0b11bdaac8a
 	google.golang.org/grpc v1.24.0
 )